@@ -0,0 +1,147 @@
+// codecbench - 게이트웨이 응답 보관 형식(JSON vs Protobuf)의 메모리/CPU 비교
+//
+//	go run ./cmd/codecbench [-pods 2000]
+//
+// 큰 PodList를 만들어 보관 크기와 클라이언트 형식별 응답 변환 비용을 측정합니다.
+// "json" 행은 JSON 원본을 보관하던 이전 방식, "protobuf" 행은 codec.Object 방식입니다.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"api-proxy/pkg/codec"
+	"api-proxy/pkg/printers"
+)
+
+const (
+	acceptJSON     = codec.MediaTypeJSON
+	acceptProtobuf = codec.MediaTypeProtobuf
+	acceptTable    = "application/json;as=Table;v=v1;g=meta.k8s.io"
+)
+
+// podList - 벤치마크용 PodList JSON
+func podList(count int) []byte {
+	list := corev1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}}
+	for i := 0; i < count; i++ {
+		list.Items = append(list.Items, corev1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("web-%05d", i),
+				Namespace:         "default",
+				UID:               "3f1c9a52-7d1e-4d7a-9a51-0c5b0e6a1f00",
+				ResourceVersion:   fmt.Sprintf("%d", 100000+i),
+				CreationTimestamp: metav1.NewTime(time.Unix(1700000000, 0)),
+				Labels:            map[string]string{"app": "web", "tier": "frontend"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: fmt.Sprintf("worker-%02d", i%16),
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Image: "nginx:1.25",
+					Ports: []corev1.ContainerPort{{ContainerPort: 80, Protocol: corev1.ProtocolTCP}},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("100m"),
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{
+				Phase:  corev1.PodRunning,
+				PodIP:  fmt.Sprintf("10.42.%d.%d", i/250, i%250),
+				HostIP: fmt.Sprintf("192.168.0.%d", i%16),
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "web", Ready: true, Image: "nginx:1.25",
+				}},
+			},
+		})
+	}
+
+	data, err := json.Marshal(&list)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	return data
+}
+
+func report(name string, result testing.BenchmarkResult) {
+	fmt.Printf("%-30s %12.2f ms/op %12d B/op %8d allocs/op\n",
+		name, float64(result.NsPerOp())/1e6, result.AllocedBytesPerOp(), result.AllocsPerOp())
+}
+
+func main() {
+	pods := flag.Int("pods", 2000, "number of pods in the benchmark PodList")
+	flag.Parse()
+
+	body := podList(*pods)
+	object := codec.NewJSONObject(body)
+	if object.MediaType() != codec.MediaTypeProtobuf {
+		fmt.Fprintf(os.Stderr, "❌ PodList was not converted to protobuf\n")
+		os.Exit(1)
+	}
+
+	fmt.Printf("PodList with %d pods\n", *pods)
+	fmt.Printf("%-30s %12d bytes\n", "stored json", len(body))
+	fmt.Printf("%-30s %12d bytes (%.0f%%)\n\n", "stored protobuf", object.Size(), 100*float64(object.Size())/float64(len(body)))
+
+	report("store json", testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = append([]byte(nil), body...)
+		}
+	}))
+	// 마스터 응답은 이미 Protobuf이므로 보관 시 변환이 없음 (변환은 게이트웨이가 만든 JSON 본문만 해당)
+	encoded, _ := object.Encode(acceptProtobuf)
+	report("store protobuf (master)", testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			codec.NewObject(codec.MediaTypeProtobuf, append([]byte(nil), encoded...))
+		}
+	}))
+	report("store protobuf (from json)", testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			codec.NewJSONObject(body)
+		}
+	}))
+
+	for _, accept := range []string{acceptProtobuf, acceptJSON, acceptTable} {
+		accept := accept
+		label := map[string]string{acceptProtobuf: "protobuf", acceptJSON: "json", acceptTable: "table"}[accept]
+
+		report("serve "+label+" from json", testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if accept == acceptTable {
+					table, _ := printers.ToTable(body, time.Now())
+					codec.Encode(accept, table)
+					continue
+				}
+				codec.Encode(accept, body)
+			}
+		}))
+		report("serve "+label+" from protobuf", testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if accept == acceptTable {
+					data, _ := object.JSON()
+					table, _ := printers.ToTable(data, time.Now())
+					codec.Encode(accept, table)
+					continue
+				}
+				object.Encode(accept)
+			}
+		}))
+	}
+}
@@ -0,0 +1,126 @@
+// daasctl abandoned - 포기로 표시된 노드 조회와 비상 인출(emergency_unstake)용 미참여 증명 발급
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+daasctl abandoned list [--owner ADDRESS]
+daasctl abandoned attest [--stake-proof ID] [--gas-budget N] <node-id>
+
+마스터에서 NodeAbandonment 기능 게이트가 켜져 있어야 합니다 (--feature-gates=NodeAbandonment=true).
+attest는 마스터가 서명한 증명을 받아 노드 소유자 지갑으로 실행할 sui client call 명령을 출력합니다.
+증명은 만료 전에, 노드가 다시 하트비트를 보내기 전에 사용해야 합니다.
+*/
+
+// abandonedNode - 마스터 /api/v1/nodes/abandoned 항목
+type abandonedNode struct {
+	NodeID      string    `json:"node_id"`
+	Owner       string    `json:"owner"`
+	Role        string    `json:"role"`
+	StakeAmount uint64    `json:"stake_amount"`
+	LastSeen    time.Time `json:"last_seen"`
+	AbandonedAt time.Time `json:"abandoned_at"`
+}
+
+// abandonmentAttestation - 마스터 /api/v1/nodes/abandoned/attestation 응답
+type abandonmentAttestation struct {
+	NodeID           string `json:"node_id"`
+	Owner            string `json:"owner"`
+	PackageID        string `json:"package_id"`
+	RegistryID       string `json:"registry_id"`
+	MasterRegistryID string `json:"master_registry_id"`
+	Region           string `json:"region"`
+	LastSeenMs       uint64 `json:"last_seen_ms"`
+	AbandonedAtMs    uint64 `json:"abandoned_at_ms"`
+	ExpiresAtMs      uint64 `json:"expires_at_ms"`
+	Signature        string `json:"signature"`
+	PublicKey        string `json:"public_key"`
+}
+
+// runAbandoned - abandoned 서브커맨드 처리
+func runAbandoned(args []string) error {
+	if len(args) == 0 {
+		usage()
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("abandoned "+action, flag.ExitOnError)
+	master := flags.String("master", os.Getenv("NAUTILUS_MASTER_URL"), "master URL (default $NAUTILUS_MASTER_URL)")
+	owner := flags.String("owner", "", "only nodes owned by this wallet address (list)")
+	stakeProof := flags.String("stake-proof", "<STAKE_PROOF_ID>", "StakeProof object ID of the node (attest)")
+	gasBudget := flags.Uint64("gas-budget", 100000000, "gas budget for the printed sui client call (attest)")
+	flags.Parse(args[1:])
+
+	if *master == "" {
+		return errors.New("master URL required (--master or NAUTILUS_MASTER_URL)")
+	}
+	client := &masterClient{master: *master}
+
+	switch action {
+	case "list":
+		query := url.Values{}
+		if *owner != "" {
+			query.Set("owner", *owner)
+		}
+		var nodes []abandonedNode
+		if err := client.do(http.MethodGet, "/api/v1/nodes/abandoned", query, &nodes); err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			fmt.Println("No abandoned nodes")
+			return nil
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "NODE\tOWNER\tSTAKE\tLAST SEEN\tABANDONED")
+		for _, node := range nodes {
+			fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", node.NodeID, node.Owner, node.StakeAmount,
+				node.LastSeen.Local().Format(time.RFC3339), node.AbandonedAt.Local().Format(time.RFC3339))
+		}
+		return table.Flush()
+
+	case "attest":
+		if flags.NArg() != 1 {
+			usage()
+		}
+		var attestation abandonmentAttestation
+		query := url.Values{"node_id": {flags.Arg(0)}}
+		if err := client.do(http.MethodGet, "/api/v1/nodes/abandoned/attestation", query, &attestation); err != nil {
+			return err
+		}
+		signature, err := hex.DecodeString(attestation.Signature)
+		if err != nil {
+			return fmt.Errorf("invalid attestation signature: %v", err)
+		}
+		bytes := make([]string, len(signature))
+		for i, b := range signature {
+			bytes[i] = fmt.Sprint(b)
+		}
+
+		fmt.Printf("Node:       %s (owner %s)\n", attestation.NodeID, attestation.Owner)
+		fmt.Printf("Last seen:  %s\n", time.UnixMilli(int64(attestation.LastSeenMs)).Local().Format(time.RFC3339))
+		fmt.Printf("Expires:    %s\n", time.UnixMilli(int64(attestation.ExpiresAtMs)).Local().Format(time.RFC3339))
+		fmt.Printf("Signed by:  %s master (%s)\n\n", attestation.Region, attestation.PublicKey)
+		fmt.Println("Run from the owner wallet to unregister the node and return its stake:")
+		fmt.Printf("  sui client call --package %s --module worker_registry --function emergency_unstake \\\n", attestation.PackageID)
+		fmt.Printf("    --args %s %s %s %s %d %d %d \"[%s]\" \\\n", attestation.RegistryID, *stakeProof,
+			attestation.MasterRegistryID, attestation.Region, attestation.LastSeenMs, attestation.AbandonedAtMs,
+			attestation.ExpiresAtMs, strings.Join(bytes, ","))
+		fmt.Printf("    --gas-budget %d\n", *gasBudget)
+		return nil
+
+	default:
+		usage()
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+// daasctl dev - 로컬 개발 환경 (Gateway + 마스터 + 워커 + mock 체인) docker compose 생성/실행
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+/*
+daasctl dev up [--dir DIR] [--workers N] [--stake MIST] [--repo PATH] [--no-start]
+daasctl dev down [--dir DIR] [--purge]
+
+up은 DIR(기본 .daas-dev)에 다음을 생성한 뒤 docker compose로 실행합니다.
+  - docker-compose.yml: 마스터(mock 체인 호스팅), Gateway, 워커 N개
+  - workers/<node>.json: 워커별 스테이커 설정 (mock 체인, 마스터 엔드포인트)
+  - samples/: 마스터 부트스트랩 디렉토리로 마운트되는 예제 워크로드
+  - dev-env.json: 생성된 키/토큰/지갑 (다시 up 해도 유지되어 볼륨의 상태와 어긋나지 않음)
+
+Gateway 서명 키와 마스터 응답 서명 키는 서로의 공개키로 설정되고,
+마스터가 올라오면 워커 지갑마다 mock 체인에 스테이크를 기록합니다 (관리 토큰 사용).
+*/
+
+const (
+	devProject     = "daas-dev"
+	devPackageSeed = "k3s-daas-dev"
+	devGatewayPort = 8080
+	devMasterPort  = 8081
+)
+
+// devEnv - dev-env.json (up을 반복해도 같은 키와 지갑 사용)
+type devEnv struct {
+	AdminToken    string      `json:"admin_token"`
+	GatewaySeed   string      `json:"gateway_signing_seed"`
+	MasterSeed    string      `json:"master_signing_seed"`
+	PackageID     string      `json:"package_id"`
+	StakeAmount   uint64      `json:"stake_amount"`
+	Workers       []devWorker `json:"workers"`
+	GatewayPubKey string      `json:"-"`
+	MasterPubKey  string      `json:"-"`
+	Repo          string      `json:"-"`
+}
+
+// devWorker - 워커 노드와 mock 지갑
+type devWorker struct {
+	NodeID string `json:"node_id"`
+	Wallet string `json:"wallet"`
+}
+
+func devUsage() {
+	fmt.Fprintf(os.Stderr, "usage:\n")
+	fmt.Fprintf(os.Stderr, "  daasctl dev up [--dir DIR] [--workers N] [--stake MIST] [--repo PATH] [--no-start]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl dev down [--dir DIR] [--purge]\n")
+	os.Exit(2)
+}
+
+// runDev - dev 서브커맨드 처리
+func runDev(args []string) error {
+	if len(args) == 0 {
+		devUsage()
+	}
+	switch args[0] {
+	case "up":
+		return devUp(args[1:])
+	case "down":
+		return devDown(args[1:])
+	}
+	devUsage()
+	return nil
+}
+
+func devUp(args []string) error {
+	flags := flag.NewFlagSet("dev up", flag.ExitOnError)
+	dir := flags.String("dir", ".daas-dev", "directory for the generated environment")
+	workers := flags.Int("workers", 2, "number of worker nodes")
+	stake := flags.Uint64("stake", 1000000000, "mock stake recorded for each worker wallet (MIST)")
+	repo := flags.String("repo", ".", "repository root used as the docker build context")
+	noStart := flags.Bool("no-start", false, "only generate the files")
+	wait := flags.Duration("wait", 5*time.Minute, "how long to wait for the master")
+	flags.Parse(args)
+
+	if *workers < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
+	repoRoot, err := filepath.Abs(*repo)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(repoRoot, "nautilus-release", "Dockerfile")); err != nil {
+		return fmt.Errorf("--repo %s is not the K3s-DaaS repository root (nautilus-release/Dockerfile not found)", repoRoot)
+	}
+
+	env, err := loadDevEnv(*dir, *workers, *stake)
+	if err != nil {
+		return err
+	}
+	env.Repo = repoRoot
+	if err := writeDevFiles(*dir, env); err != nil {
+		return err
+	}
+	fmt.Printf("📝 Generated %s (%d workers)\n", *dir, len(env.Workers))
+	if *noStart {
+		fmt.Printf("▶️  Start with: docker compose -p %s -f %s up -d --build\n", devProject, filepath.Join(*dir, "docker-compose.yml"))
+		return nil
+	}
+
+	if err := compose(*dir, "up", "-d", "--build"); err != nil {
+		return err
+	}
+
+	masterURL := fmt.Sprintf("http://localhost:%d", devMasterPort)
+	fmt.Printf("⏳ Waiting for the master at %s...\n", masterURL)
+	if err := fundWorkers(masterURL, env, *wait); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✅ Development environment is up\n")
+	fmt.Printf("  Gateway:      http://localhost:%d\n", devGatewayPort)
+	fmt.Printf("  Master API:   %s (admin token: %s)\n", masterURL, env.AdminToken)
+	fmt.Printf("  Mock chain:   %s/api/v1/mockchain/ (package %s)\n", masterURL, env.PackageID)
+	for _, worker := range env.Workers {
+		fmt.Printf("  Worker:       %s (wallet %s, stake %d)\n", worker.NodeID, worker.Wallet, env.StakeAmount)
+	}
+	fmt.Printf("  Samples:      applied from %s\n", filepath.Join(*dir, "samples"))
+	fmt.Printf("  Tear down:    daasctl dev down --dir %s\n", *dir)
+	return nil
+}
+
+func devDown(args []string) error {
+	flags := flag.NewFlagSet("dev down", flag.ExitOnError)
+	dir := flags.String("dir", ".daas-dev", "directory of the generated environment")
+	purge := flags.Bool("purge", false, "also delete the generated directory (keys, wallets)")
+	flags.Parse(args)
+
+	if _, err := os.Stat(filepath.Join(*dir, "docker-compose.yml")); err != nil {
+		return fmt.Errorf("no development environment in %s", *dir)
+	}
+	// 볼륨도 삭제 (다음 up은 빈 클러스터와 빈 mock 체인에서 시작)
+	if err := compose(*dir, "down", "-v", "--remove-orphans"); err != nil {
+		return err
+	}
+	if *purge {
+		if err := os.RemoveAll(*dir); err != nil {
+			return err
+		}
+		fmt.Printf("🧹 Removed %s\n", *dir)
+	}
+	fmt.Printf("✅ Development environment is down\n")
+	return nil
+}
+
+// loadDevEnv - 저장된 키/지갑 로드, 없으면 생성 (워커 수가 늘면 지갑만 추가)
+func loadDevEnv(dir string, workers int, stake uint64) (*devEnv, error) {
+	env := &devEnv{}
+	path := filepath.Join(dir, "dev-env.json")
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, env); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if env.AdminToken == "" {
+		env.AdminToken = randomHex(16)
+	}
+	if env.GatewaySeed == "" {
+		env.GatewaySeed = randomHex(ed25519.SeedSize)
+	}
+	if env.MasterSeed == "" {
+		env.MasterSeed = randomHex(ed25519.SeedSize)
+	}
+	if env.PackageID == "" {
+		digest := sha256.Sum256([]byte(devPackageSeed))
+		env.PackageID = "0x" + hex.EncodeToString(digest[:])
+	}
+	env.StakeAmount = stake
+	for i := len(env.Workers); i < workers; i++ {
+		env.Workers = append(env.Workers, devWorker{
+			NodeID: fmt.Sprintf("dev-worker-%d", i+1),
+			Wallet: "0x" + randomHex(32),
+		})
+	}
+	env.Workers = env.Workers[:workers]
+
+	for _, key := range []struct {
+		seed string
+		out  *string
+	}{{env.GatewaySeed, &env.GatewayPubKey}, {env.MasterSeed, &env.MasterPubKey}} {
+		seed, err := hex.DecodeString(key.seed)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%s: invalid signing seed", path)
+		}
+		*key.out = hex.EncodeToString(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey))
+	}
+	return env, nil
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// writeDevFiles - compose 파일, 워커 설정, 예제 워크로드 생성
+func writeDevFiles(dir string, env *devEnv) error {
+	if err := os.MkdirAll(filepath.Join(dir, "workers"), 0o755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "samples"), 0o755); err != nil {
+		return err
+	}
+
+	state, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 관리 토큰과 서명 시드가 들어 있으므로 소유자만 읽기
+	if err := os.WriteFile(filepath.Join(dir, "dev-env.json"), state, 0o600); err != nil {
+		return err
+	}
+
+	var composeFile bytes.Buffer
+	if err := devComposeTemplate.Execute(&composeFile, env); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docker-compose.yml"), composeFile.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	for _, worker := range env.Workers {
+		config, err := json.MarshalIndent(map[string]interface{}{
+			"node_id":            worker.NodeID,
+			"sui_wallet_address": worker.Wallet,
+			"stake_amount":       env.StakeAmount,
+			"contract_address":   env.PackageID,
+			"nautilus_endpoint":  "http://nautilus-control:8080",
+			"container_runtime":  "containerd",
+			"chain_backend":      "mock",
+			"region":             "dev",
+			"zone":               "dev-a",
+			"preflight_ignore":   []string{"all"},
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "workers", worker.NodeID+".json"), config, 0o644); err != nil {
+			return err
+		}
+	}
+
+	// 사용자가 수정한 예제는 덮어쓰지 않음
+	for name, content := range devSamples {
+		path := filepath.Join(dir, "samples", name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compose - docker compose (없으면 docker-compose) 실행
+func compose(dir string, args ...string) error {
+	file := filepath.Join(dir, "docker-compose.yml")
+	base := []string{"-p", devProject, "-f", file}
+
+	var cmd *exec.Cmd
+	if exec.Command("docker", "compose", "version").Run() == nil {
+		cmd = exec.Command("docker", append(append([]string{"compose"}, base...), args...)...)
+	} else if path, err := exec.LookPath("docker-compose"); err == nil {
+		cmd = exec.Command(path, append(base, args...)...)
+	} else {
+		return fmt.Errorf("docker compose is not available (install Docker with the compose plugin or docker-compose)")
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %v", strings.Join(cmd.Args, " "), err)
+	}
+	return nil
+}
+
+// fundWorkers - 마스터의 mock 체인이 응답하면 워커 지갑마다 스테이크 기록
+func fundWorkers(masterURL string, env *devEnv, wait time.Duration) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(wait)
+	for {
+		resp, err := client.Get(masterURL + "/api/v1/mockchain/events?after=-1")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("master did not come up within %s (docker compose -p %s logs nautilus-control)", wait, devProject)
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	for _, worker := range env.Workers {
+		body, _ := json.Marshal(map[string]interface{}{
+			"node_id": worker.NodeID,
+			"amount":  env.StakeAmount,
+			"status":  "active",
+		})
+		req, err := http.NewRequest(http.MethodPost, masterURL+"/api/v1/mockchain/stake", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+env.AdminToken)
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("funding %s: %v", worker.NodeID, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("funding %s: mock chain answered HTTP %d", worker.NodeID, resp.StatusCode)
+		}
+		fmt.Printf("💰 Staked %d MIST for %s\n", env.StakeAmount, worker.NodeID)
+	}
+	return nil
+}
+
+var devComposeTemplate = template.Must(template.New("compose").Parse(`# Generated by daasctl dev up - regenerate instead of editing (keys live in dev-env.json)
+services:
+  nautilus-control:
+    build:
+      context: {{.Repo}}
+      dockerfile: nautilus-release/Dockerfile
+    ports:
+      - "6444:6443"
+      - "` + fmt.Sprint(devMasterPort) + `:8080"
+    environment:
+      NAUTILUS_CHAIN_BACKEND: mock
+      NAUTILUS_CHAIN_DEFAULT_STAKE: "{{.StakeAmount}}"
+      NAUTILUS_ADMIN_TOKEN: {{.AdminToken}}
+      NAUTILUS_SIGNING_KEY: {{.MasterSeed}}
+      NAUTILUS_STATE_DIR: /var/lib/nautilus
+      NAUTILUS_BOOTSTRAP_DIR: /etc/nautilus/bootstrap
+      NAUTILUS_GATEWAY_URL: http://localhost:` + fmt.Sprint(devGatewayPort) + `
+      GATEWAY_PUBLIC_KEY: {{.GatewayPubKey}}
+      CONTRACT_PACKAGE_ID: {{.PackageID}}
+    privileged: true
+    volumes:
+      - nautilus-state:/var/lib/nautilus
+      - nautilus-k3s:/var/lib/rancher/k3s
+      - ./samples:/etc/nautilus/bootstrap:ro
+
+  gateway:
+    build:
+      context: {{.Repo}}
+      dockerfile: api-proxy/Dockerfile.gateway
+    ports:
+      - "` + fmt.Sprint(devGatewayPort) + `:8080"
+    environment:
+      NAUTILUS_MASTER_URL: http://nautilus-control:8080
+      NAUTILUS_MASTER_REGION: dev
+      NAUTILUS_MASTER_PUBLIC_KEY: {{.MasterPubKey}}
+      GATEWAY_SIGNING_KEY: {{.GatewaySeed}}
+    depends_on:
+      - nautilus-control
+{{range .Workers}}
+  {{.NodeID}}:
+    build:
+      context: {{$.Repo}}
+      dockerfile: worker-release/Dockerfile
+    hostname: {{.NodeID}}
+    environment:
+      STAKER_CONFIG_PATH: /etc/k3s-daas/staker-config.json
+      K3S_DAAS_CHAIN_BACKEND: mock
+    privileged: true
+    volumes:
+      - ./workers/{{.NodeID}}.json:/etc/k3s-daas/staker-config.json:ro
+      - {{.NodeID}}-state:/var/lib/k3s-daas-agent
+    depends_on:
+      - nautilus-control
+{{end}}
+volumes:
+  nautilus-state:
+  nautilus-k3s:
+{{- range .Workers}}
+  {{.NodeID}}-state:
+{{- end}}
+`))
+
+// devSamples - 부트스트랩으로 적용되는 예제 워크로드 (파일 이름 순서로 적용)
+var devSamples = map[string]string{
+	"00-namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: daas-samples
+`,
+	"10-nginx.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: daas-samples
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.25-alpine
+          ports:
+            - containerPort: 80
+          resources:
+            requests:
+              cpu: 50m
+              memory: 32Mi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: daas-samples
+spec:
+  selector:
+    app: nginx
+  ports:
+    - port: 80
+`,
+	"20-hello-job.yaml": `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hello
+  namespace: daas-samples
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: hello
+          image: busybox:1.36
+          command: ["sh", "-c", "echo hello from K3s-DaaS"]
+`,
+}
@@ -0,0 +1,205 @@
+// daasctl escrow - 봉인 루트 키 에스크로 보관자 도구 (키 생성, 번들 조회, 복구 시 조각 제출)
+package main
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	escrow "github.com/k3s-io/daas-escrow"
+)
+
+/*
+daasctl escrow keygen --output FILE
+daasctl escrow status
+daasctl escrow submit --guardian NAME --key FILE
+
+keygen은 보관자 X25519 개인키를 FILE(0600)에 쓰고, 마스터 NAUTILUS_ESCROW_GUARDIANS 파일에 넣을 항목을 출력합니다.
+개인키는 보관자가 오프라인으로 보관하며 마스터에는 공개키만 등록합니다.
+submit은 복구 대기 중인 마스터에서 자신의 조각을 받아 로컬에서 열고, 마스터가 출력한 일회용 복구 키로 다시 봉인해 제출합니다.
+제출 전에 복구 키 ID가 마스터 로그의 값과 같은지 확인하세요.
+*/
+
+// escrowShare - 마스터 번들의 보관자 조각
+type escrowShare struct {
+	Guardian string           `json:"guardian"`
+	KeyID    string           `json:"key_id"`
+	Index    byte             `json:"index"`
+	Envelope *escrow.Envelope `json:"envelope"`
+}
+
+// escrowBundle - 마스터 /api/v1/admin/escrow 응답
+type escrowBundle struct {
+	RootID    string        `json:"root_id"`
+	Provider  string        `json:"provider"`
+	Threshold int           `json:"threshold"`
+	Shares    []escrowShare `json:"shares"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// escrowRecoveryStatus - 복구 대기 중인 마스터 /api/v1/admin/escrow/recovery 응답
+type escrowRecoveryStatus struct {
+	RootID        string        `json:"root_id"`
+	Threshold     int           `json:"threshold"`
+	RecoveryKey   string        `json:"recovery_key"`
+	RecoveryKeyID string        `json:"recovery_key_id"`
+	Shares        []escrowShare `json:"shares"`
+	Received      []string      `json:"received"`
+	Recovered     bool          `json:"recovered"`
+}
+
+// runEscrow - escrow 서브커맨드 처리
+func runEscrow(args []string) error {
+	if len(args) == 0 {
+		usage()
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("escrow "+action, flag.ExitOnError)
+	master := flags.String("master", os.Getenv("NAUTILUS_MASTER_URL"), "master URL (default $NAUTILUS_MASTER_URL)")
+	adminToken := flags.String("admin-token", os.Getenv("NAUTILUS_ADMIN_TOKEN"), "master admin token")
+	output := flags.String("output", "", "file to write the guardian private key to (keygen)")
+	guardian := flags.String("guardian", "", "guardian name as listed in NAUTILUS_ESCROW_GUARDIANS (submit)")
+	keyFile := flags.String("key", "", "guardian private key file written by keygen (submit)")
+	flags.Parse(args[1:])
+
+	if action == "keygen" {
+		return escrowKeygen(*output)
+	}
+	if *master == "" {
+		return errors.New("master URL required (--master or NAUTILUS_MASTER_URL)")
+	}
+	if *adminToken == "" {
+		return errors.New("admin token required (--admin-token or NAUTILUS_ADMIN_TOKEN)")
+	}
+	client := &masterClient{master: *master, adminToken: *adminToken}
+
+	switch action {
+	case "status":
+		var bundle escrowBundle
+		if err := client.do(http.MethodGet, "/api/v1/admin/escrow", nil, &bundle); err != nil {
+			return err
+		}
+		fmt.Printf("Root:       %s (%s)\n", bundle.RootID, bundle.Provider)
+		fmt.Printf("Threshold:  %d of %d guardians\n", bundle.Threshold, len(bundle.Shares))
+		fmt.Printf("Created:    %s\n\n", bundle.CreatedAt.Local().Format(time.RFC3339))
+		table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "GUARDIAN\tKEY ID\tSHARE")
+		for _, share := range bundle.Shares {
+			fmt.Fprintf(table, "%s\t%s\t%d\n", share.Guardian, share.KeyID, share.Index)
+		}
+		return table.Flush()
+
+	case "submit":
+		if *guardian == "" || *keyFile == "" {
+			return errors.New("--guardian and --key are required")
+		}
+		key, err := readGuardianKey(*keyFile)
+		if err != nil {
+			return err
+		}
+		var status escrowRecoveryStatus
+		if err := client.do(http.MethodGet, "/api/v1/admin/escrow/recovery", nil, &status); err != nil {
+			return err
+		}
+		if status.Recovered {
+			fmt.Println("Recovery already completed")
+			return nil
+		}
+		var share *escrowShare
+		for i := range status.Shares {
+			if status.Shares[i].Guardian == *guardian {
+				share = &status.Shares[i]
+			}
+		}
+		if share == nil {
+			return fmt.Errorf("root %s has no share for guardian %s", status.RootID, *guardian)
+		}
+
+		// 조각은 이 프로세스 안에서만 평문으로 존재
+		plaintext, err := escrow.Open(key, share.Envelope, escrow.ShareAAD(status.RootID, *guardian))
+		if err != nil {
+			return err
+		}
+		recoveryKey, err := escrow.ParsePublicKey(status.RecoveryKey)
+		if err != nil {
+			return fmt.Errorf("invalid recovery key: %v", err)
+		}
+		if escrow.KeyID(recoveryKey) != status.RecoveryKeyID {
+			return fmt.Errorf("recovery key does not match its ID %s", status.RecoveryKeyID)
+		}
+		envelope, err := escrow.Seal(recoveryKey, plaintext, escrow.RecoveryAAD(status.RootID, *guardian))
+		if err != nil {
+			return err
+		}
+		submission := map[string]interface{}{"guardian": *guardian, "envelope": envelope}
+		if err := client.send(http.MethodPost, "/api/v1/admin/escrow/recovery", nil, submission, &status); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Share %d for root %s submitted to recovery key %s\n", share.Index, status.RootID, status.RecoveryKeyID)
+		if status.Recovered {
+			fmt.Println("Threshold reached: the master is re-sealing its keys and continuing startup")
+		} else {
+			fmt.Printf("Received %d of %d shares (%s)\n", len(status.Received), status.Threshold, strings.Join(status.Received, ", "))
+		}
+		return nil
+
+	default:
+		usage()
+	}
+	return nil
+}
+
+// escrowKeygen - 보관자 키 생성 (개인키는 파일에만, 공개키는 마스터 설정용으로 출력)
+func escrowKeygen(output string) error {
+	if output == "" {
+		return errors.New("--output is required")
+	}
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, base64.StdEncoding.EncodeToString(key.Bytes())); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	entry, _ := json.Marshal(map[string]string{"name": "<guardian name>", "public_key": base64.StdEncoding.EncodeToString(key.PublicKey().Bytes())})
+	fmt.Printf("🔑 Guardian key written to %s (key ID %s); keep it offline\n", output, escrow.KeyID(key.PublicKey()))
+	fmt.Printf("Add to the master's NAUTILUS_ESCROW_GUARDIANS file:\n  %s\n", entry)
+	return nil
+}
+
+// readGuardianKey - keygen이 쓴 base64 X25519 개인키
+func readGuardianKey(path string) (*ecdh.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a guardian key: %v", path, err)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a guardian key: %v", path, err)
+	}
+	return key, nil
+}
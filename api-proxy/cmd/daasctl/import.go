@@ -0,0 +1,573 @@
+// daasctl import - 기존 K3s 클러스터의 리소스를 읽어 DaaS 쓰기 경로(게이트웨이 admission → 온체인)로 옮기고 이관 보고서 작성
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"api-proxy/pkg/admission"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+/*
+daasctl import [--kubeconfig PATH] [--context NAME] [--namespaces A,B] [--gateway URL] [--seal-token TOKEN]
+               [--include-secrets] [--dry-run] [--report FILE]
+
+원본 클러스터(kubeconfig)에서 게이트웨이 admission이 지원하는 리소스를 읽어 의존 순서대로
+(네임스페이스 → LimitRange → ConfigMap/Secret/PVC → Service → 워크로드 → 단독 Pod) 게이트웨이에 POST합니다.
+kubectl 쓰기와 같은 경로이므로 각 객체는 타입 변환/기본값/검증을 거쳐 컨트랙트로 제출됩니다.
+
+  - 서버가 채우는 필드(status, uid, resourceVersion, managedFields, clusterIP, nodeName 등)는 제거
+  - 컨트롤러가 만든 객체(ownerReferences가 있는 ReplicaSet/Pod/Job)와 시스템 네임스페이스/객체는 건너뜀
+  - 지원하지 않는 리소스(ResourceQuota, NetworkPolicy, Ingress, RBAC 등)는 수동 조치 항목으로 보고
+  - Secret은 온체인 요청으로 제출되므로 --include-secrets를 지정해야 옮김 (EncryptedPayloads 게이트 권장)
+  - PVC는 객체만 만들고 볼륨 데이터는 옮기지 않음
+
+--dry-run은 서버 측 dryRun=All로 제출해 저장/온체인 기록 없이 검증만 합니다.
+이미 있는 객체(409)는 덮어쓰지 않고 exists로 보고하므로 중단된 이관을 다시 실행해도 됩니다.
+*/
+
+// importStep - 이관 대상 리소스 (importOrder 순서대로 제출)
+type importStep struct {
+	gvr  schema.GroupVersionResource
+	kind string
+}
+
+// importOrder - 의존 순서 (참조되는 객체를 먼저 만들어야 워크로드가 바로 뜸)
+var importOrder = []importStep{
+	{schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "Namespace"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}, "LimitRange"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, "ConfigMap"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, "Secret"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, "PersistentVolumeClaim"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "services"}, "Service"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, "Deployment"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, "StatefulSet"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, "DaemonSet"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, "ReplicaSet"},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, "CronJob"},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, "Job"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "pods"}, "Pod"},
+}
+
+// manualResources - DaaS 쓰기 경로가 받지 않는 리소스와 대신 할 일 (객체가 있으면 manual로 보고)
+var manualResources = []struct {
+	gvr    schema.GroupVersionResource
+	action string
+}{
+	{schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}, "quota comes from the master's namespace template; ask the operator to adjust it"},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}, "tenant isolation policy is applied by the master; request extra rules from the operator"},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, "not supported by the gateway; expose the service with a LoadBalancer or NodePort"},
+	{schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, "service accounts are not created through the gateway; pods run as the namespace default"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, "grant access with on-chain delegation instead of Kubernetes RBAC"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, "grant access with on-chain delegation instead of Kubernetes RBAC"},
+	{schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}, "not supported by the gateway; set replicas on the workload"},
+	{schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}, "not supported by the gateway"},
+}
+
+// 이관 결과 분류
+const (
+	importImported = "imported"
+	importExists   = "exists"
+	importSkipped  = "skipped"
+	importManual   = "manual"
+	importFailed   = "failed"
+)
+
+// importItem - 보고서 항목 하나
+type importItem struct {
+	Result    string   `json:"result"`
+	Resource  string   `json:"resource"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Detail    string   `json:"detail,omitempty"`
+	Changes   []string `json:"changes,omitempty"` // 제거하거나 바꾼 필드
+}
+
+// importReport - --report로 저장하는 이관 보고서
+type importReport struct {
+	Source     string         `json:"source"`
+	Gateway    string         `json:"gateway"`
+	DryRun     bool           `json:"dry_run"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Summary    map[string]int `json:"summary"`
+	Items      []importItem   `json:"items"`
+}
+
+// importer - 원본 클러스터 읽기와 게이트웨이 제출
+type importer struct {
+	source         dynamic.Interface
+	gateway        string
+	sealToken      string
+	namespaces     map[string]bool // 비어 있으면 시스템 네임스페이스를 뺀 전부
+	includeSecrets bool
+	dryRun         bool
+	http           *http.Client
+
+	report       importReport
+	failedSpaces map[string]bool // 만들지 못한 네임스페이스 (안의 객체는 제출하지 않음)
+}
+
+// runImport - import 서브커맨드 처리
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	kubeconfig := flags.String("kubeconfig", "", "kubeconfig of the source cluster (default $KUBECONFIG or ~/.kube/config)")
+	kubeContext := flags.String("context", "", "kubeconfig context of the source cluster (default current context)")
+	namespaceList := flags.String("namespaces", "", "comma-separated namespaces to import (default all but kube-*)")
+	gateway := flags.String("gateway", envOr("DAAS_GATEWAY_URL", "http://localhost:8080"), "DaaS gateway URL (default $DAAS_GATEWAY_URL)")
+	sealToken := flags.String("seal-token", os.Getenv("DAAS_SEAL_TOKEN"), "Seal token of one of your workers (default $DAAS_SEAL_TOKEN)")
+	includeSecrets := flags.Bool("include-secrets", false, "import Secrets too (their data is submitted on chain)")
+	dryRun := flags.Bool("dry-run", false, "validate every object with server-side dry-run without storing anything")
+	reportFile := flags.String("report", "", "also write the migration report as JSON to this file")
+	flags.Parse(args)
+
+	if *sealToken == "" {
+		return errors.New("seal token required (--seal-token or DAAS_SEAL_TOKEN)")
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if *kubeconfig != "" {
+		rules.ExplicitPath = *kubeconfig
+	}
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: *kubeContext})
+	config, err := loader.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("load source kubeconfig: %v", err)
+	}
+	source, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	imp := &importer{
+		source:         source,
+		gateway:        strings.TrimRight(*gateway, "/"),
+		sealToken:      *sealToken,
+		namespaces:     make(map[string]bool),
+		includeSecrets: *includeSecrets,
+		dryRun:         *dryRun,
+		http:           &http.Client{Timeout: 2 * time.Minute},
+		failedSpaces:   make(map[string]bool),
+	}
+	for _, namespace := range strings.Split(*namespaceList, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			imp.namespaces[namespace] = true
+		}
+	}
+	imp.report = importReport{
+		Source:    config.Host,
+		Gateway:   imp.gateway,
+		DryRun:    imp.dryRun,
+		StartedAt: time.Now().UTC(),
+		Summary:   make(map[string]int),
+	}
+
+	if err := imp.run(context.Background()); err != nil {
+		return err
+	}
+	imp.report.FinishedAt = time.Now().UTC()
+
+	printImportReport(imp.report)
+	if *reportFile != "" {
+		data, err := json.MarshalIndent(imp.report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*reportFile, append(data, '\n'), 0600); err != nil {
+			return err
+		}
+		fmt.Printf("Report written to %s\n", *reportFile)
+	}
+	if failed := imp.report.Summary[importFailed]; failed > 0 {
+		return fmt.Errorf("%d objects failed to import (fix them and run the import again; existing objects are kept)", failed)
+	}
+	return nil
+}
+
+// envOr - 환경 변수 또는 기본값
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// run - 리소스 종류별로 읽어 순서대로 제출한 뒤 수동 조치 대상을 수집
+func (imp *importer) run(ctx context.Context) error {
+	for _, step := range importOrder {
+		list, err := imp.source.Resource(step.gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("list %s from source cluster: %v", step.gvr.Resource, err)
+		}
+		objects := list.Items
+		sort.Slice(objects, func(i, j int) bool {
+			if objects[i].GetNamespace() != objects[j].GetNamespace() {
+				return objects[i].GetNamespace() < objects[j].GetNamespace()
+			}
+			return objects[i].GetName() < objects[j].GetName()
+		})
+		for i := range objects {
+			imp.importObject(ctx, step, &objects[i])
+		}
+	}
+
+	for _, manual := range manualResources {
+		list, err := imp.source.Resource(manual.gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			continue // 원본 클러스터에 없는 API이거나 읽을 권한 없음
+		}
+		if err != nil {
+			return fmt.Errorf("list %s from source cluster: %v", manual.gvr.Resource, err)
+		}
+		for _, object := range list.Items {
+			if !imp.selected(object.GetNamespace()) || isGeneratedManualObject(manual.gvr.Resource, &object) {
+				continue
+			}
+			imp.add(importItem{Result: importManual, Resource: manual.gvr.Resource,
+				Namespace: object.GetNamespace(), Name: object.GetName(), Detail: manual.action})
+		}
+	}
+	return nil
+}
+
+// selected - 이관 대상 네임스페이스인지
+func (imp *importer) selected(namespace string) bool {
+	if len(imp.namespaces) > 0 {
+		return imp.namespaces[namespace]
+	}
+	return !strings.HasPrefix(namespace, "kube-") // kube-system, kube-public, kube-node-lease
+}
+
+// add - 보고서에 항목 추가
+func (imp *importer) add(item importItem) {
+	imp.report.Items = append(imp.report.Items, item)
+	imp.report.Summary[item.Result]++
+}
+
+// importObject - 객체 하나를 걸러내고 변환해 제출
+func (imp *importer) importObject(ctx context.Context, step importStep, object *unstructured.Unstructured) {
+	resource := step.gvr.Resource
+	namespace := object.GetNamespace()
+	if resource == "namespaces" {
+		namespace = object.GetName()
+	}
+	if !imp.selected(namespace) {
+		return
+	}
+	item := importItem{Resource: resource, Namespace: object.GetNamespace(), Name: object.GetName()}
+
+	switch reason := skipReason(resource, object, imp.includeSecrets); {
+	case reason == "-":
+		return
+	case strings.HasPrefix(reason, "manual: "):
+		item.Result, item.Detail = importManual, strings.TrimPrefix(reason, "manual: ")
+		imp.add(item)
+		return
+	case reason != "":
+		item.Result, item.Detail = importSkipped, reason
+		imp.add(item)
+		return
+	}
+	if resource != "namespaces" && imp.failedSpaces[namespace] {
+		item.Result, item.Detail = importSkipped, "namespace "+namespace+" was not imported"
+		imp.add(item)
+		return
+	}
+
+	item.Changes = sanitizeForImport(resource, object)
+	object.SetAPIVersion(step.gvr.GroupVersion().String())
+	object.SetKind(step.kind)
+	body, err := object.MarshalJSON()
+	if err != nil {
+		item.Result, item.Detail = importFailed, err.Error()
+		imp.add(item)
+		return
+	}
+
+	// 게이트웨이와 같은 admission을 먼저 적용해 잘못된 객체는 제출하지 않음
+	if _, statusErr := admission.Normalize(resource, object.GetNamespace(), body); statusErr != nil {
+		item.Result, item.Detail = importFailed, "admission: "+statusErr.Error()
+		imp.add(item)
+		if resource == "namespaces" {
+			imp.failedSpaces[namespace] = true
+		}
+		return
+	}
+
+	code, message, err := imp.submit(ctx, step.gvr, object.GetNamespace(), body)
+	switch {
+	case err != nil:
+		item.Result, item.Detail = importFailed, err.Error()
+	case code == http.StatusConflict:
+		item.Result, item.Detail = importExists, "already exists on DaaS, left unchanged"
+	case code >= 300:
+		item.Result, item.Detail = importFailed, fmt.Sprintf("gateway returned %d: %s", code, message)
+	default:
+		item.Result = importImported
+		if imp.dryRun {
+			item.Detail = "dry-run passed"
+		}
+		if note := importNote(resource, object); note != "" {
+			item.Detail = strings.TrimPrefix(item.Detail+"; "+note, "; ")
+		}
+	}
+	if resource == "namespaces" && item.Result == importFailed {
+		imp.failedSpaces[namespace] = true
+	}
+	imp.add(item)
+}
+
+// submit - kubectl create와 같은 요청을 게이트웨이로 보냄 (상태 코드와 Status 메시지 반환)
+func (imp *importer) submit(ctx context.Context, gvr schema.GroupVersionResource, namespace string, body []byte) (int, string, error) {
+	path := "/api/" + gvr.Version
+	if gvr.Group != "" {
+		path = "/apis/" + gvr.Group + "/" + gvr.Version
+	}
+	if namespace != "" {
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+	path += "/" + gvr.Resource
+	if imp.dryRun {
+		path += "?dryRun=All"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, imp.gateway+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+imp.sealToken)
+	req.Header.Set("User-Agent", "daasctl-import")
+	resp, err := imp.http.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 300 {
+		return resp.StatusCode, "", nil
+	}
+
+	var status metav1.Status
+	if err := json.Unmarshal(data, &status); err == nil && status.Message != "" {
+		return resp.StatusCode, status.Message, nil
+	}
+	return resp.StatusCode, strings.TrimSpace(string(data)), nil
+}
+
+/*
+skipReason - 옮기지 않는 객체와 이유 (빈 문자열이면 옮김)
+"manual: "로 시작하면 수동 조치 항목, "-"이면 보고하지 않고 건너뜀 (클러스터가 자동으로 만드는 객체)
+*/
+func skipReason(resource string, object *unstructured.Unstructured, includeSecrets bool) string {
+	name := object.GetName()
+	if owners := object.GetOwnerReferences(); len(owners) > 0 && resource != "namespaces" {
+		for _, owner := range owners {
+			if owner.Controller != nil && *owner.Controller {
+				return "-" // 컨트롤러가 다시 만듦 (Deployment의 ReplicaSet/Pod, CronJob의 Job 등)
+			}
+		}
+	}
+
+	switch resource {
+	case "namespaces":
+		// default와 kube-*는 대상 클러스터에 이미 있음 (default 안의 객체는 옮김)
+		if name == metav1.NamespaceDefault || strings.HasPrefix(name, "kube-") {
+			return "-"
+		}
+	case "configmaps":
+		if name == "kube-root-ca.crt" {
+			return "-"
+		}
+	case "services":
+		if object.GetNamespace() == metav1.NamespaceDefault && name == "kubernetes" {
+			return "-"
+		}
+	case "secrets":
+		secretType, _, _ := unstructured.NestedString(object.Object, "type")
+		switch {
+		case secretType == "kubernetes.io/service-account-token":
+			return "-"
+		case secretType == "helm.sh/release.v1":
+			return "manual: helm release history; reinstall the chart through the gateway helm API"
+		case !includeSecrets:
+			return "manual: secret data would be submitted on chain; rerun with --include-secrets or create it separately"
+		}
+	case "jobs":
+		if completion, _, _ := unstructured.NestedString(object.Object, "status", "completionTime"); completion != "" {
+			return "job already completed (importing would run it again)"
+		}
+	case "pods":
+		switch phase, _, _ := unstructured.NestedString(object.Object, "status", "phase"); phase {
+		case "Succeeded", "Failed":
+			return "pod already finished (" + strings.ToLower(phase) + ")"
+		}
+		if _, mirror := object.GetAnnotations()["kubernetes.io/config.mirror"]; mirror {
+			return "manual: static pod of a source node; run it as a workload instead"
+		}
+	}
+	return ""
+}
+
+// serverSetAnnotations - 원본 클러스터의 서버/컨트롤러가 붙인 어노테이션 (접두사 포함)
+var serverSetAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+	"pv.kubernetes.io/",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/",
+	"control-plane.alpha.kubernetes.io/leader",
+	"batch.kubernetes.io/job-tracking",
+}
+
+/*
+sanitizeForImport - 서버가 채우는 필드와 원본 클러스터에 묶인 값을 제거하고 바꾼 필드 목록 반환
+admission은 알 수 없는 필드를 거부(Strict)하므로 status 같은 최상위 필드도 모두 지움
+*/
+func sanitizeForImport(resource string, object *unstructured.Unstructured) []string {
+	var changes []string
+	remove := func(fields ...string) {
+		if _, found, _ := unstructured.NestedFieldNoCopy(object.Object, fields...); found {
+			unstructured.RemoveNestedField(object.Object, fields...)
+			changes = append(changes, strings.Join(fields, "."))
+		}
+	}
+
+	remove("status")
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp",
+		"deletionGracePeriodSeconds", "managedFields", "selfLink", "ownerReferences", "finalizers"} {
+		remove("metadata", field)
+	}
+	if annotations := object.GetAnnotations(); len(annotations) > 0 {
+		for key := range annotations {
+			for _, prefix := range serverSetAnnotations {
+				if strings.HasPrefix(key, prefix) {
+					delete(annotations, key)
+					changes = append(changes, "metadata.annotations["+key+"]")
+					break
+				}
+			}
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		object.SetAnnotations(annotations)
+	}
+
+	switch resource {
+	case "namespaces":
+		remove("spec", "finalizers")
+	case "services":
+		// 헤드리스 서비스(clusterIP: None)만 유지하고 할당된 IP/포트는 대상 클러스터가 다시 할당
+		if clusterIP, _, _ := unstructured.NestedString(object.Object, "spec", "clusterIP"); clusterIP != "None" {
+			remove("spec", "clusterIP")
+			remove("spec", "clusterIPs")
+		}
+		remove("spec", "healthCheckNodePort")
+		if ports, found, _ := unstructured.NestedSlice(object.Object, "spec", "ports"); found {
+			for i := range ports {
+				if port, ok := ports[i].(map[string]interface{}); ok {
+					if _, assigned := port["nodePort"]; assigned {
+						delete(port, "nodePort")
+						changes = append(changes, fmt.Sprintf("spec.ports[%d].nodePort", i))
+					}
+				}
+			}
+			unstructured.SetNestedSlice(object.Object, ports, "spec", "ports")
+		}
+	case "persistentvolumeclaims":
+		remove("spec", "volumeName") // 원본 노드의 PV에 묶여 있음
+	case "pods":
+		remove("spec", "nodeName")
+	case "jobs":
+		// 자동 생성된 selector와 controller-uid 라벨은 새 Job에서 다시 생성
+		if manual, _, _ := unstructured.NestedBool(object.Object, "spec", "manualSelector"); !manual {
+			remove("spec", "selector")
+			for _, label := range []string{"controller-uid", "batch.kubernetes.io/controller-uid", "job-name", "batch.kubernetes.io/job-name"} {
+				remove("spec", "template", "metadata", "labels", label)
+			}
+		}
+	}
+	return changes
+}
+
+// importNote - 옮겼지만 추가로 확인할 점
+func importNote(resource string, object *unstructured.Unstructured) string {
+	switch resource {
+	case "persistentvolumeclaims":
+		return "volume data is not copied"
+	case "services":
+		if serviceType, _, _ := unstructured.NestedString(object.Object, "spec", "type"); serviceType == "LoadBalancer" || serviceType == "NodePort" {
+			return "external address changes; update DNS and clients"
+		}
+	case "statefulsets":
+		return "new PVCs from volumeClaimTemplates start empty"
+	}
+	return ""
+}
+
+// isGeneratedManualObject - 수동 조치 대상 리소스 중 클러스터가 자동으로 만든 객체
+func isGeneratedManualObject(resource string, object *unstructured.Unstructured) bool {
+	switch resource {
+	case "serviceaccounts":
+		return object.GetName() == "default"
+	case "resourcequotas", "networkpolicies":
+		// 마스터 네임스페이스 템플릿이 만든 객체 (DaaS에서 이관해 온 클러스터를 다시 읽는 경우)
+		return object.GetName() == "tenant-default" || object.GetName() == "tenant-isolation"
+	}
+	return len(object.GetOwnerReferences()) > 0
+}
+
+// printImportReport - 결과별 표와 요약
+func printImportReport(report importReport) {
+	mode := ""
+	if report.DryRun {
+		mode = " (dry-run, nothing was stored)"
+	}
+	fmt.Printf("Source:   %s\n", report.Source)
+	fmt.Printf("Gateway:  %s%s\n", report.Gateway, mode)
+	if len(report.Items) == 0 {
+		fmt.Println("Nothing to import")
+		return
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "RESULT\tRESOURCE\tNAMESPACE\tNAME\tDETAIL")
+	for _, result := range []string{importFailed, importManual, importImported, importExists, importSkipped} {
+		for _, item := range report.Items {
+			if item.Result != result {
+				continue
+			}
+			namespace := item.Namespace
+			if namespace == "" {
+				namespace = "-"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", item.Result, item.Resource, namespace, item.Name, item.Detail)
+		}
+	}
+	table.Flush()
+
+	fmt.Printf("\n%d imported, %d already existed, %d skipped, %d need manual action, %d failed\n",
+		report.Summary[importImported], report.Summary[importExists], report.Summary[importSkipped],
+		report.Summary[importManual], report.Summary[importFailed])
+}
@@ -0,0 +1,210 @@
+// daasctl - K3s-DaaS 구성요소를 systemd 유닛 / Windows 서비스로 설치하고 관리하는 CLI
+//
+//	daasctl service install <component> [--binary PATH] [--env KEY=VALUE]... [--user NAME] [-- ARGS...]
+//	daasctl service uninstall|start|stop|restart|status <component>
+//	daasctl service status            (모든 구성요소 상태)
+//	daasctl dev up|down [--dir DIR]   (로컬 개발 환경, dev.go 참고)
+//	daasctl support-bundle [--master URL] [--output FILE]   (문제 보고용 진단 아카이브, support.go 참고)
+//	daasctl trash list|restore|purge [--master URL] [ID]    (소프트 삭제 휴지통과 복원, trash.go 참고)
+//	daasctl abandoned list|attest [--master URL] [NODE_ID]  (포기된 노드의 비상 인출, abandoned.go 참고)
+//	daasctl escrow keygen|status|submit [--master URL]      (봉인 루트 키 에스크로 보관자 도구, escrow.go 참고)
+//	daasctl usage [--master URL] [--seal-token TOKEN]       (테넌트 quota 사용량, 추이와 소진 예측, usage.go 참고)
+//	daasctl import [--kubeconfig PATH] [--dry-run] [--report FILE]  (기존 K3s 클러스터 리소스 이관과 보고서, import.go 참고)
+//
+// 구성요소: master, worker, staking, runtime, gateway, listener
+// staking/runtime은 worker 바이너리를 K3S_DAAS_MODE=staking|runtime으로 분리 실행합니다.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	service "github.com/k3s-io/daas-service"
+)
+
+// component - 설치 가능한 구성요소
+type component struct {
+	binary string // 기본 실행 파일 이름 (PATH에서 검색)
+	spec   service.Spec
+}
+
+var components = map[string]component{
+	"master": {binary: "nautilus-control", spec: service.Spec{
+		Name:        "nautilus-control",
+		DisplayName: "K3s-DaaS Nautilus Control",
+		Description: "K3s-DaaS master node (Nautilus TEE control plane)",
+	}},
+	"worker": {binary: "worker-release", spec: service.Spec{
+		Name:        "k3s-daas-worker",
+		DisplayName: "K3s-DaaS combined",
+		Description: "K3s-DaaS worker (staking, heartbeat and K3s agent)",
+	}},
+	"staking": {binary: "worker-release", spec: service.Spec{
+		Name:        "k3s-daas-staking",
+		DisplayName: "K3s-DaaS staking",
+		Description: "K3s-DaaS staking daemon (staking, Nautilus registration, heartbeat)",
+		Env:         map[string]string{"K3S_DAAS_MODE": "staking"},
+	}},
+	"runtime": {binary: "worker-release", spec: service.Spec{
+		Name:        "k3s-daas-runtime",
+		DisplayName: "K3s-DaaS runtime",
+		Description: "K3s-DaaS runtime agent (K3s agent and container runtime)",
+		Env:         map[string]string{"K3S_DAAS_MODE": "runtime"},
+	}},
+	"gateway": {binary: "gateway", spec: service.Spec{
+		Name:        "k3s-daas-gateway",
+		DisplayName: "K3s-DaaS API Gateway",
+		Description: "K3s-DaaS kubectl API gateway (Sui contract / master forwarding)",
+	}},
+	"listener": {binary: "listener", spec: service.Spec{
+		Name:        "k3s-daas-listener",
+		DisplayName: "K3s-DaaS Event Listener",
+		Description: "K3s-DaaS Nautilus event listener (Sui contract events to K8s)",
+	}},
+}
+
+func usage() {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "usage:\n")
+	fmt.Fprintf(os.Stderr, "  daasctl service install <component> [--binary PATH] [--env KEY=VALUE]... [--user NAME] [-- ARGS...]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl service uninstall|start|stop|restart|status <component>\n")
+	fmt.Fprintf(os.Stderr, "  daasctl service status\n")
+	fmt.Fprintf(os.Stderr, "  daasctl dev up|down [--dir DIR] (local gateway + master + workers on a mock chain)\n")
+	fmt.Fprintf(os.Stderr, "  daasctl support-bundle [--master URL] [--admin-token TOKEN] [--output FILE] [--config PATH]... [--log-lines N]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl trash list [--master URL] [--admin-token TOKEN] [--namespace NS] [--resource RESOURCE]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl trash restore|purge [--master URL] [--admin-token TOKEN] <id>\n")
+	fmt.Fprintf(os.Stderr, "  daasctl abandoned list [--master URL] [--owner ADDRESS]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl abandoned attest [--master URL] [--stake-proof ID] [--gas-budget N] <node-id>\n")
+	fmt.Fprintf(os.Stderr, "  daasctl escrow keygen --output FILE\n")
+	fmt.Fprintf(os.Stderr, "  daasctl escrow status|submit [--master URL] [--admin-token TOKEN] [--guardian NAME --key FILE]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl usage [--master URL] [--seal-token TOKEN | --admin-token TOKEN [--tenant ADDRESS]]\n")
+	fmt.Fprintf(os.Stderr, "  daasctl import [--kubeconfig PATH] [--context NAME] [--namespaces A,B] [--gateway URL] [--seal-token TOKEN] [--include-secrets] [--dry-run] [--report FILE]\n")
+	fmt.Fprintf(os.Stderr, "components: %v\n", names)
+	os.Exit(2)
+}
+
+// resolveBinary - PATH에서 실행 파일 검색 (없으면 플랫폼 기본 설치 경로)
+func resolveBinary(name string) string {
+	if path, err := exec.LookPath(name); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramFiles"), "k3s-daas", name+".exe")
+	}
+	return filepath.Join("/usr/local/bin", name)
+}
+
+// statusAll - 모든 구성요소의 서비스 상태 출력
+func statusAll() error {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		status, err := service.Status(components[name].spec.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-9s %-20s %s\n", name, components[name].spec.Name, status)
+	}
+	return nil
+}
+
+func main() {
+	if len(os.Args) >= 2 && os.Args[1] == "dev" {
+		if err := runDev(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "support-bundle" {
+		if err := runSupportBundle(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "trash" {
+		if err := runTrash(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "abandoned" {
+		if err := runAbandoned(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "escrow" {
+		if err := runEscrow(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "usage" {
+		if err := runUsage(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) < 3 || os.Args[1] != "service" {
+		usage()
+	}
+	action := os.Args[2]
+
+	if action == "status" && len(os.Args) == 3 {
+		if err := statusAll(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) < 4 {
+		usage()
+	}
+
+	target, ok := components[os.Args[3]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "❌ unknown component %q\n", os.Args[3])
+		usage()
+	}
+
+	spec := target.spec
+	if action == "install" {
+		spec.Executable = resolveBinary(target.binary)
+	}
+
+	// 구성요소 이름을 제외한 나머지 인자는 바이너리의 service 서브커맨드와 동일
+	args := append([]string{action}, os.Args[4:]...)
+	if err := service.Command(spec, args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
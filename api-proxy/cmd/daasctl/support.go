@@ -0,0 +1,319 @@
+// daasctl support-bundle - 마스터 지원 번들과 로컬 구성요소 정보(서비스 상태, 로그, 설정, 버전)를 하나의 tar.gz로 수집
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	service "github.com/k3s-io/daas-service"
+	version "github.com/k3s-io/daas-version"
+)
+
+/*
+daasctl support-bundle [--master URL] [--admin-token TOKEN] [--output FILE] [--config PATH]... [--log-lines N]
+
+번들 구성 (이슈에 첨부하기 전에 내용을 확인할 수 있도록 평문 파일만 사용)
+  - master/...                 마스터 /debug/support-bundle 내용 그대로 (--master와 관리 토큰이 있을 때)
+  - local/services.txt         설치된 구성요소의 서비스 상태
+  - local/logs/<unit>.log      최근 journald 로그 (Linux, 토큰/키 형태 값 숨김)
+  - local/config/<file>        JSON 설정 파일 (이름에 KEY/TOKEN/SECRET/PASSWORD/CREDENTIAL이 들어간 필드 값 숨김)
+  - local/versions.txt         구성요소 바이너리의 version 출력
+  - local/system.json          OS/아키텍처/호스트 이름
+  - manifest.json              파일 목록과 수집 실패 항목
+
+설정 파일을 지정하지 않으면 STAKER_CONFIG_PATH 또는 ./staker-config.json이 있을 때 포함합니다.
+*/
+
+const supportRedacted = "[REDACTED]"
+
+var (
+	supportSecretMarkers = []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "CREDENTIAL"}
+
+	supportSecretValue = regexp.MustCompile(`(?i)\b([a-z_]*(?:token|secret|password|private_?key|seed)[a-z_]*)(["']?\s*[=:]\s*["']?)[^\s"',}]+`)
+	supportBearerValue = regexp.MustCompile(`(?i)\bbearer\s+[^\s"',]+`)
+	supportSuiKey      = regexp.MustCompile(`suiprivkey1[0-9a-z]+`)
+	supportSealToken   = regexp.MustCompile(`(^|[^0-9a-fA-Fx])[0-9a-f]{64}\b`)
+)
+
+// configPaths - 반복 가능한 --config 플래그
+type configPaths []string
+
+func (c *configPaths) String() string     { return strings.Join(*c, ",") }
+func (c *configPaths) Set(v string) error { *c = append(*c, v); return nil }
+
+// supportBundle - 작성 중인 아카이브와 수집 결과
+type supportBundle struct {
+	archive *tar.Writer
+	now     time.Time
+	files   []string
+	errors  map[string]string
+}
+
+func (b *supportBundle) add(name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: b.now}
+	if err := b.archive.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := b.archive.Write(data); err != nil {
+		return err
+	}
+	b.files = append(b.files, name)
+	return nil
+}
+
+// fail - 수집 실패 기록 (번들 생성은 계속)
+func (b *supportBundle) fail(name string, err error) {
+	b.errors[name] = err.Error()
+	fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", name, err)
+}
+
+// redactSupportLine - 로그/명령 출력의 비밀 값 제거
+func redactSupportLine(text string) string {
+	text = supportSecretValue.ReplaceAllString(text, "${1}${2}"+supportRedacted)
+	text = supportBearerValue.ReplaceAllString(text, "Bearer "+supportRedacted)
+	text = supportSuiKey.ReplaceAllString(text, supportRedacted)
+	return supportSealToken.ReplaceAllString(text, "${1}"+supportRedacted)
+}
+
+// redactSupportValue - 민감한 이름의 JSON 필드 값 숨김
+func redactSupportValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			upper := strings.ToUpper(key)
+			sensitive := false
+			for _, marker := range supportSecretMarkers {
+				sensitive = sensitive || strings.Contains(upper, marker)
+			}
+			if sensitive && field != nil && field != "" {
+				typed[key] = supportRedacted
+			} else if !sensitive {
+				typed[key] = redactSupportValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactSupportValue(item)
+		}
+	case string:
+		return redactSupportLine(typed)
+	}
+	return value
+}
+
+// runSupportBundle - support-bundle 서브커맨드 처리
+func runSupportBundle(args []string) error {
+	flags := flag.NewFlagSet("support-bundle", flag.ExitOnError)
+	master := flags.String("master", os.Getenv("NAUTILUS_MASTER_URL"), "master URL (default $NAUTILUS_MASTER_URL)")
+	adminToken := flags.String("admin-token", os.Getenv("NAUTILUS_ADMIN_TOKEN"), "master admin token")
+	output := flags.String("output", "", "archive path (default daas-support-<time>.tar.gz)")
+	logLines := flags.Int("log-lines", 2000, "journald lines per component")
+	var configs configPaths
+	flags.Var(&configs, "config", "JSON config file to include, redacted (repeatable)")
+	flags.Parse(args)
+
+	if len(configs) == 0 {
+		path := os.Getenv("STAKER_CONFIG_PATH")
+		if path == "" {
+			path = "./staker-config.json"
+		}
+		if _, err := os.Stat(path); err == nil {
+			configs = append(configs, path)
+		}
+	}
+
+	now := time.Now()
+	if *output == "" {
+		*output = fmt.Sprintf("daas-support-%s.tar.gz", now.UTC().Format("20060102-150405"))
+	}
+	file, err := os.OpenFile(*output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	bundle := &supportBundle{archive: tar.NewWriter(gz), now: now, errors: make(map[string]string)}
+
+	if *master != "" {
+		if err := bundle.addMaster(*master, *adminToken); err != nil {
+			bundle.fail("master", err)
+		}
+	}
+	if err := bundle.addLocal(configs, *logLines); err != nil {
+		return err
+	}
+
+	manifest, _ := json.MarshalIndent(map[string]interface{}{
+		"generated_at": now.UTC(),
+		"daasctl":      version.Get("daasctl").String(),
+		"files":        bundle.files,
+		"errors":       bundle.errors,
+	}, "", "  ")
+	if err := bundle.add("manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := bundle.archive.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Support bundle written to %s (%d files, %d collection errors)\n", *output, len(bundle.files), len(bundle.errors))
+	fmt.Printf("   Review the contents before attaching it to an issue: tar -tzf %s\n", *output)
+	return nil
+}
+
+// addMaster - 마스터 지원 번들을 받아 master/ 아래에 그대로 추가
+func (b *supportBundle) addMaster(masterURL, adminToken string) error {
+	if adminToken == "" {
+		return errors.New("admin token required (--admin-token or NAUTILUS_ADMIN_TOKEN)")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(masterURL, "/")+"/debug/support-bundle", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := (&http.Client{Timeout: 2 * time.Minute}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("master returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	remote := tar.NewReader(gz)
+	for {
+		header, err := remote.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(remote)
+		if err != nil {
+			return err
+		}
+		if err := b.add("master/"+header.Name, data); err != nil {
+			return err
+		}
+	}
+}
+
+// addLocal - 이 호스트의 서비스 상태, 로그, 설정, 버전, 시스템 정보
+func (b *supportBundle) addLocal(configs []string, logLines int) error {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var services strings.Builder
+	installed := make(map[string]bool)
+	for _, name := range names {
+		unit := components[name].spec.Name
+		status, err := service.Status(unit)
+		if err != nil {
+			status = "error: " + err.Error()
+		}
+		installed[unit] = err == nil && !strings.Contains(status, "not installed")
+		fmt.Fprintf(&services, "%-9s %-20s %s\n", name, unit, status)
+	}
+	if err := b.add("local/services.txt", []byte(services.String())); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "linux" {
+		units := make([]string, 0, len(installed))
+		for unit, ok := range installed {
+			if ok {
+				units = append(units, unit)
+			}
+		}
+		sort.Strings(units)
+		for _, unit := range units {
+			name := "local/logs/" + unit + ".log"
+			out, err := exec.Command("journalctl", "-u", unit, "-n", strconv.Itoa(logLines), "--no-pager", "-o", "short-iso").CombinedOutput()
+			if err != nil {
+				b.fail(name, fmt.Errorf("journalctl: %v", err))
+				continue
+			}
+			if err := b.add(name, []byte(redactSupportLine(string(out)))); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, path := range configs {
+		name := "local/config/" + filepath.Base(path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.fail(name, err)
+			continue
+		}
+		var parsed interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			// JSON이 아니면 값 구조를 알 수 없어 줄 단위로만 가림
+			if err := b.add(name, []byte(redactSupportLine(string(data)))); err != nil {
+				return err
+			}
+			continue
+		}
+		redactedConfig, _ := json.MarshalIndent(redactSupportValue(parsed), "", "  ")
+		if err := b.add(name, redactedConfig); err != nil {
+			return err
+		}
+	}
+
+	var versions strings.Builder
+	seen := make(map[string]bool)
+	for _, name := range names {
+		binary := components[name].binary
+		if seen[binary] {
+			continue
+		}
+		seen[binary] = true
+		out, err := exec.Command(resolveBinary(binary), "version").CombinedOutput()
+		if err != nil {
+			fmt.Fprintf(&versions, "%-16s unavailable (%v)\n", binary, err)
+			continue
+		}
+		fmt.Fprintf(&versions, "%-16s %s\n", binary, strings.TrimSpace(string(out)))
+	}
+	fmt.Fprintf(&versions, "%-16s %s\n", "daasctl", version.Get("daasctl"))
+	if err := b.add("local/versions.txt", []byte(versions.String())); err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	system, _ := json.MarshalIndent(map[string]interface{}{
+		"hostname": hostname,
+		"os":       runtime.GOOS,
+		"arch":     runtime.GOARCH,
+		"cpus":     runtime.NumCPU(),
+	}, "", "  ")
+	return b.add("local/system.json", system)
+}
@@ -0,0 +1,185 @@
+// daasctl trash - 마스터 소프트 삭제 휴지통 조회, 삭제한 객체 복원(undelete), 즉시 삭제
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+daasctl trash list [--namespace NS] [--resource RESOURCE]
+daasctl trash restore [flags] <id>
+daasctl trash purge [flags] <id>
+
+마스터에서 SoftDelete 기능 게이트가 켜져 있어야 합니다 (--feature-gates=SoftDelete=true).
+--master(기본 $NAUTILUS_MASTER_URL)와 --admin-token(기본 $NAUTILUS_ADMIN_TOKEN)은 모든 하위 명령에 공통입니다.
+*/
+
+// trashEntry - 마스터 /api/v1/admin/trash 항목
+type trashEntry struct {
+	ID        string    `json:"id"`
+	Resource  string    `json:"resource"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	DeletedBy string    `json:"deleted_by"`
+	Source    string    `json:"source"`
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// masterClient - 마스터 API 호출 (관리자 토큰이 있으면 Bearer 인증, Seal 토큰이 있으면 X-Seal-Token)
+type masterClient struct {
+	master     string
+	adminToken string
+	sealToken  string
+}
+
+func (c *masterClient) do(method, path string, query url.Values, out interface{}) error {
+	return c.send(method, path, query, nil, out)
+}
+
+// send - body가 있으면 JSON으로 보내는 do
+func (c *masterClient) send(method, path string, query url.Values, body, out interface{}) error {
+	endpoint := strings.TrimRight(c.master, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+	if c.sealToken != "" {
+		req.Header.Set("X-Seal-Token", c.sealToken)
+	}
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && path == "/api/v1/admin/trash" && query.Get("id") == "" {
+		return errors.New("master has no trash API (enable the SoftDelete feature gate)")
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("master returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("invalid master response: %v", err)
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// runTrash - trash 서브커맨드 처리
+func runTrash(args []string) error {
+	if len(args) == 0 {
+		usage()
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("trash "+action, flag.ExitOnError)
+	master := flags.String("master", os.Getenv("NAUTILUS_MASTER_URL"), "master URL (default $NAUTILUS_MASTER_URL)")
+	adminToken := flags.String("admin-token", os.Getenv("NAUTILUS_ADMIN_TOKEN"), "master admin token")
+	namespace := flags.String("namespace", "", "only entries deleted from this namespace (list)")
+	resource := flags.String("resource", "", "only entries of this plural resource, e.g. deployments (list)")
+	flags.Parse(args[1:])
+
+	if *master == "" {
+		return errors.New("master URL required (--master or NAUTILUS_MASTER_URL)")
+	}
+	if *adminToken == "" {
+		return errors.New("admin token required (--admin-token or NAUTILUS_ADMIN_TOKEN)")
+	}
+	client := &masterClient{master: *master, adminToken: *adminToken}
+
+	switch action {
+	case "list":
+		query := url.Values{}
+		if *namespace != "" {
+			query.Set("namespace", *namespace)
+		}
+		if *resource != "" {
+			query.Set("resource", *resource)
+		}
+		var entries []trashEntry
+		if err := client.do(http.MethodGet, "/api/v1/admin/trash", query, &entries); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tRESOURCE\tNAME\tDELETED\tEXPIRES\tDELETED BY")
+		for _, entry := range entries {
+			name := entry.Name
+			if entry.Namespace != "" {
+				name = entry.Namespace + "/" + entry.Name
+			}
+			deletedBy := entry.DeletedBy
+			if deletedBy == "" {
+				deletedBy = "-"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s (%s)\n", entry.ID, entry.Resource, name,
+				entry.DeletedAt.Local().Format(time.RFC3339), entry.ExpiresAt.Local().Format(time.RFC3339), deletedBy, entry.Source)
+		}
+		return table.Flush()
+
+	case "restore", "purge":
+		if flags.NArg() != 1 {
+			usage()
+		}
+		query := url.Values{"id": {flags.Arg(0)}}
+		if action == "purge" {
+			var purged map[string]string
+			if err := client.do(http.MethodDelete, "/api/v1/admin/trash", query, &purged); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Trash entry %s purged\n", flags.Arg(0))
+			return nil
+		}
+		var restored struct {
+			Entry trashEntry `json:"entry"`
+		}
+		if err := client.do(http.MethodPost, "/api/v1/admin/trash/restore", query, &restored); err != nil {
+			return err
+		}
+		name := restored.Entry.Name
+		if restored.Entry.Namespace != "" {
+			name = restored.Entry.Namespace + "/" + restored.Entry.Name
+		}
+		fmt.Printf("✅ Restored %s %s\n", restored.Entry.Resource, name)
+		return nil
+
+	default:
+		usage()
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// daasctl usage - 테넌트 네임스페이스의 quota 사용량, 7일 추이, 소진 예측
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+daasctl usage [--seal-token TOKEN]
+daasctl usage --admin-token TOKEN [--tenant ADDRESS]
+
+마스터에서 TenantUsageForecasts 기능 게이트가 켜져 있어야 합니다 (--feature-gates=TenantUsageForecasts=true).
+Seal 토큰(기본 $DAAS_SEAL_TOKEN)으로는 토큰 소유 지갑의 네임스페이스만, 관리자 토큰으로는 전체 테넌트를
+소진 예측이 빠른 순으로 조회합니다.
+*/
+
+// quotaUsagePoint - 하루 단위 사용량
+type quotaUsagePoint struct {
+	Time time.Time `json:"time"`
+	Used float64   `json:"used"`
+}
+
+// quotaResourceUsage - 네임스페이스의 리소스별 사용량과 예측
+type quotaResourceUsage struct {
+	Resource     string            `json:"resource"`
+	Used         string            `json:"used"`
+	Hard         string            `json:"hard"`
+	Ratio        float64           `json:"ratio"`
+	Trend        []quotaUsagePoint `json:"trend"`
+	GrowthPerDay float64           `json:"growth_per_day"`
+	ExhaustsAt   *time.Time        `json:"exhausts_at"`
+}
+
+// tenantQuotaReport - 마스터 /api/v1/tenant/usage 응답
+type tenantQuotaReport struct {
+	Tenant   string `json:"tenant"`
+	Requests struct {
+		QPS       float64 `json:"qps"`
+		Allowed   uint64  `json:"allowed"`
+		Throttled uint64  `json:"throttled"`
+	} `json:"requests"`
+	Namespaces []struct {
+		Namespace string               `json:"namespace"`
+		Resources []quotaResourceUsage `json:"resources"`
+	} `json:"namespaces"`
+	SampledAt time.Time `json:"sampled_at"`
+}
+
+// runUsage - usage 서브커맨드 처리
+func runUsage(args []string) error {
+	flags := flag.NewFlagSet("usage", flag.ExitOnError)
+	master := flags.String("master", os.Getenv("NAUTILUS_MASTER_URL"), "master URL (default $NAUTILUS_MASTER_URL)")
+	sealToken := flags.String("seal-token", os.Getenv("DAAS_SEAL_TOKEN"), "Seal token of one of your workers (default $DAAS_SEAL_TOKEN)")
+	adminToken := flags.String("admin-token", "", "master admin token, lists every tenant instead")
+	tenant := flags.String("tenant", "", "only this tenant wallet (with --admin-token)")
+	flags.Parse(args)
+
+	if *master == "" {
+		return errors.New("master URL required (--master or NAUTILUS_MASTER_URL)")
+	}
+
+	var reports []tenantQuotaReport
+	switch {
+	case *adminToken != "":
+		client := &masterClient{master: *master, adminToken: *adminToken}
+		query := url.Values{}
+		if *tenant != "" {
+			query.Set("tenant", *tenant)
+		}
+		if err := client.do(http.MethodGet, "/api/v1/admin/tenant-usage", query, &reports); err != nil {
+			return err
+		}
+	case *sealToken != "":
+		client := &masterClient{master: *master, sealToken: *sealToken}
+		var report tenantQuotaReport
+		if err := client.do(http.MethodGet, "/api/v1/tenant/usage", nil, &report); err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	default:
+		return errors.New("seal token (--seal-token or DAAS_SEAL_TOKEN) or --admin-token required")
+	}
+
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		printUsageReport(report)
+	}
+	return nil
+}
+
+// printUsageReport - 테넌트 하나의 사용량 표
+func printUsageReport(report tenantQuotaReport) {
+	fmt.Printf("Tenant:    %s\n", report.Tenant)
+	fmt.Printf("API:       %.1f qps, %d allowed, %d throttled\n", report.Requests.QPS, report.Requests.Allowed, report.Requests.Throttled)
+	if !report.SampledAt.IsZero() {
+		fmt.Printf("Sampled:   %s\n", report.SampledAt.Local().Format(time.RFC3339))
+	}
+	if len(report.Namespaces) == 0 {
+		fmt.Println("No namespaces labeled k3s-daas.io/tenant for this wallet")
+		return
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAMESPACE\tRESOURCE\tUSED\tHARD\tUSE\t7D TREND\tGROWTH/DAY\tEXHAUSTS")
+	for _, namespace := range report.Namespaces {
+		for _, resource := range namespace.Resources {
+			exhausts := "-"
+			if resource.ExhaustsAt != nil {
+				exhausts = resource.ExhaustsAt.Local().Format("2006-01-02 15:04")
+				if resource.Ratio >= 1 {
+					exhausts = "exhausted"
+				}
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%.0f%%\t%s\t%s\t%s\n", namespace.Namespace, resource.Resource,
+				resource.Used, resource.Hard, resource.Ratio*100, sparkline(resource.Trend),
+				formatGrowth(resource.Resource, resource.GrowthPerDay), exhausts)
+		}
+	}
+	table.Flush()
+}
+
+// sparkline - 하루 단위 사용량을 막대 문자로 (최솟값~최댓값 기준)
+func sparkline(points []quotaUsagePoint) string {
+	if len(points) == 0 {
+		return "-"
+	}
+	bars := []rune("▁▂▃▄▅▆▇█")
+	low, high := points[0].Used, points[0].Used
+	for _, point := range points {
+		low, high = min(low, point.Used), max(high, point.Used)
+	}
+	var line strings.Builder
+	for _, point := range points {
+		index := 0
+		if high > low {
+			index = int((point.Used - low) / (high - low) * float64(len(bars)-1))
+		}
+		line.WriteRune(bars[index])
+	}
+	return line.String()
+}
+
+// formatGrowth - 하루 증가량 (cpu는 millicore, 메모리/스토리지는 바이트로 옴)
+func formatGrowth(resource string, growth float64) string {
+	if growth == 0 {
+		return "-"
+	}
+	sign := "+"
+	if growth < 0 {
+		sign, growth = "-", -growth
+	}
+	switch {
+	case resource == "cpu" || strings.HasSuffix(resource, ".cpu"):
+		return fmt.Sprintf("%s%.0fm", sign, growth)
+	case strings.Contains(resource, "memory") || strings.Contains(resource, "storage"):
+		units := []string{"", "Ki", "Mi", "Gi", "Ti"}
+		unit := 0
+		for growth >= 1024 && unit < len(units)-1 {
+			growth /= 1024
+			unit++
+		}
+		return fmt.Sprintf("%s%.1f%s", sign, growth, units[unit])
+	default:
+		return fmt.Sprintf("%s%.1f", sign, growth)
+	}
+}
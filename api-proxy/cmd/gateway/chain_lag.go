@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// chainLagHeader - 컨트랙트 경로(온체인 제출 → 이벤트 → 실행자 콜백)를 거친 kubectl 응답의 체인 지연
+const chainLagHeader = "X-DaaS-Chain-Lag"
+
+/*
+setChainLag - 체인 지연을 응답 헤더로 기록 (time.ParseDuration 형식, 밀리초 단위)
+
+실행자가 보고한 값(요청 이벤트의 체크포인트 시각부터 실행자가 받기까지)을 쓰고,
+보고가 없는 응답(모의 응답, 이전 버전 실행자)은 제출부터 결과 도착까지의 왕복을 상한으로 씁니다.
+체인 쪽 원인(체크포인트 지연, RPC 지연, 가스 가격)은 마스터의 /api/v1/chain/health에서 확인합니다.
+*/
+func setChainLag(w http.ResponseWriter, response *K8sResponse, submittedAt time.Time) {
+	lag := response.ChainLag
+	if lag <= 0 {
+		arrivedAt := response.ProcessedAt
+		if arrivedAt.IsZero() {
+			arrivedAt = time.Now()
+		}
+		lag = arrivedAt.Sub(submittedAt)
+	}
+	if lag < 0 {
+		lag = 0
+	}
+	w.Header().Set(chainLagHeader, lag.Round(time.Millisecond).String())
+}
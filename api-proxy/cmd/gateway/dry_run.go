@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+kubectl --dry-run=server 쓰기 처리
+
+생성/수정/패치는 dryRun을 쿼리로, 삭제는 DeleteOptions 본문으로도 보냅니다 (K8s와 같이 All만 허용).
+dryRun 요청은 Gateway 어드미션(기본값 적용, 검증)까지 실제 쓰기와 같게 거친 뒤
+컨트랙트 대신 홈 리전 마스터로 서명 전달됩니다. 마스터는 이벤트 경로와 같은 어드미션을 적용한 객체를
+K3s dryRun으로 검증하고 저장될 객체를 돌려주므로, 온체인 요청/응답 기록과 응답 대기 저장소를 모두 거치지 않습니다.
+마스터가 구성되지 않은 Gateway는 dryRun을 실행할 곳이 없어 503으로 거부합니다 (온체인 제출은 실제 쓰기).
+*/
+
+// dryRunRequested - 쿼리 또는 DELETE 본문(DeleteOptions)의 dryRun
+func dryRunRequested(r *http.Request, kubectlReq *KubectlRequest) (bool, error) {
+	values := r.URL.Query()["dryRun"]
+	if kubectlReq.Method == http.MethodDelete && len(kubectlReq.Payload) > 0 {
+		var options struct {
+			DryRun []string `json:"dryRun"`
+		}
+		if err := json.Unmarshal(kubectlReq.Payload, &options); err == nil {
+			values = append(values, options.DryRun...)
+		}
+	}
+	for _, value := range values {
+		if value != "All" {
+			return false, fmt.Errorf("unsupported dryRun value %q (only All is supported)", value)
+		}
+	}
+	return len(values) > 0, nil
+}
+
+// forwardDryRun - dryRun 쓰기를 마스터로 서명 전달 (본문에만 있던 dryRun도 쿼리에 명시해 마스터 미들웨어가 알 수 있게 함)
+func (g *ContractAPIGateway) forwardDryRun(ctx context.Context, w http.ResponseWriter, r *http.Request, kubectlReq *KubectlRequest, requestID string, startTime time.Time) {
+	if g.master == nil {
+		g.returnK8sError(w, "ServiceUnavailable", "server-side dry-run requires a master connection (NAUTILUS_MASTER_URL)", http.StatusServiceUnavailable)
+		return
+	}
+	query := r.URL.Query()
+	query.Set("dryRun", "All")
+
+	g.logger.WithFields(logrus.Fields{
+		"request_id": requestID,
+		"method":     kubectlReq.Method,
+		"path":       kubectlReq.Path,
+		"owner":      kubectlReq.Owner,
+	}).Info("🧪 Forwarding server-side dry run to the master (not submitted on chain)")
+	g.forwardToMaster(ctx, w, r, kubectlReq, query.Encode(), requestID, startTime)
+}
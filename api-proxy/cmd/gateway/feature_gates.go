@@ -0,0 +1,38 @@
+// Feature Gates - 게이트웨이 기능의 단계적 출시 스위치
+package main
+
+import (
+	featuregate "github.com/k3s-io/daas-featuregate"
+)
+
+// 게이트웨이 기능 게이트 이름 (GATEWAY_FEATURE_GATES 또는 --feature-gates=Name=true,...)
+const (
+	featureRegionFailover      = "RegionFailover"
+	featureHelmReleases        = "HelmReleases"
+	featureResponseCompression = "ResponseCompression"
+	featureEncryptedPayloads   = "EncryptedPayloads"
+	featureRequestMirroring    = "RequestMirroring"
+)
+
+var features = featuregate.New(map[string]featuregate.Spec{
+	featureRegionFailover: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Retry other regional masters when the home region fails",
+	},
+	featureHelmReleases: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Serve /daas/v1/helm/releases (server-side chart rendering)",
+	},
+	featureResponseCompression: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "gzip responses when the client sends Accept-Encoding",
+	},
+	featureEncryptedPayloads: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Encrypt write payloads to the home master's attested enclave key so only ciphertext and a hash go on chain",
+	},
+	featureRequestMirroring: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Answer every request through the signed master forward and shadow it through the contract path to report divergence",
+	},
+})
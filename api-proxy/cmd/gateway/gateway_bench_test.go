@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api-proxy/pkg/codec"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/k3s-io/daas-signing"
+)
+
+// 게이트웨이 kubectl 경로 벤치마크
+// 예산은 api-proxy/perf/bench-budget.txt, 실행은 scripts/perf-check.sh 참고
+
+const benchToken = "seal_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// newBenchGateway - 로그를 버리는 게이트웨이와 운영과 같은 미들웨어 체인
+func newBenchGateway(master *MasterForwarder) (*ContractAPIGateway, http.Handler) {
+	g := NewContractAPIGateway("http://127.0.0.1:0", "0x0", "")
+	g.logger.SetOutput(io.Discard)
+	if master != nil {
+		g.master = newRegionRouter(g.logger, master.signingKey)
+		g.master.add("default", master, true)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", g.handleKubectlRequest)
+	handler := httpserver.Chain(mux,
+		httpserver.RequestID,
+		httpserver.Recovery(g.logger.Errorf),
+		httpserver.Compress(httpserver.DefaultCompressMinSize),
+		httpserver.Logging(g.logger.Debugf, "/healthz", "/readyz"),
+	)
+	return g, handler
+}
+
+func benchRequest(b *testing.B, handler http.Handler, method, path, accept string, body []byte) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+benchToken)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("Accept-Encoding", "gzip")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code >= 300 {
+			b.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func BenchmarkKubectlGet(b *testing.B) {
+	_, handler := newBenchGateway(nil)
+	benchRequest(b, handler, http.MethodGet, "/api/v1/namespaces/default/pods", "application/json", nil)
+}
+
+func BenchmarkKubectlGetTable(b *testing.B) {
+	_, handler := newBenchGateway(nil)
+	benchRequest(b, handler, http.MethodGet, "/api/v1/namespaces/default/pods",
+		"application/json;as=Table;v=v1;g=meta.k8s.io,application/json", nil)
+}
+
+func BenchmarkKubectlPost(b *testing.B) {
+	_, handler := newBenchGateway(nil)
+	pod := []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default"},` +
+		`"spec":{"containers":[{"name":"web","image":"nginx:1.25"}]}}`)
+	benchRequest(b, handler, http.MethodPost, "/api/v1/namespaces/default/pods", "application/json", pod)
+}
+
+// benchPodList - 마스터가 돌려주는 PodList (JSON)
+func benchPodList(count int) []byte {
+	var items []string
+	for i := 0; i < count; i++ {
+		items = append(items, fmt.Sprintf(`{"metadata":{"name":"web-%05d","namespace":"default",`+
+			`"creationTimestamp":"2024-01-01T00:00:00Z"},"spec":{"nodeName":"worker-%02d",`+
+			`"containers":[{"name":"web","image":"nginx:1.25"}]},"status":{"phase":"Running",`+
+			`"podIP":"10.42.0.%d","containerStatuses":[{"name":"web","ready":true,"restartCount":0,"image":"nginx:1.25","imageID":"","containerID":""}]}}`,
+			i, i%16, i%250))
+	}
+	return []byte(`{"apiVersion":"v1","kind":"PodList","metadata":{"resourceVersion":"1"},"items":[` + strings.Join(items, ",") + `]}`)
+}
+
+// BenchmarkKubectlGetSignedForward - 서명 전달 경로 (요청 서명, 마스터 왕복, 응답 서명 검증, 보관/변환)
+func BenchmarkKubectlGetSignedForward(b *testing.B) {
+	_, gatewayKey, _ := ed25519.GenerateKey(nil)
+	masterPub, masterKey, _ := ed25519.GenerateKey(nil)
+	verifier := signing.NewVerifier(gatewayKey.Public().(ed25519.PublicKey))
+	list := benchPodList(100)
+
+	master := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifier.VerifyRequest(r, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		// K3s API 서버처럼 Accept에 따라 Protobuf로 응답
+		body, contentType := codec.Encode(r.Header.Get("Accept"), list)
+		w.Header().Set("Content-Type", contentType)
+		signing.SignResponse(masterKey, w.Header(), http.StatusOK, r.Header.Get(signing.HeaderNonce), body)
+		w.Write(body)
+	}))
+	defer master.Close()
+
+	_, handler := newBenchGateway(&MasterForwarder{
+		masterURL:  master.URL,
+		signingKey: gatewayKey,
+		masterKey:  masterPub,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	})
+	benchRequest(b, handler, http.MethodGet, "/api/v1/namespaces/default/pods",
+		"application/json;as=Table;v=v1;g=meta.k8s.io,application/json", nil)
+}
@@ -3,15 +3,29 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/go-resty/resty/v2"
+	"api-proxy/pkg/admission"
+	"api-proxy/pkg/codec"
+	"api-proxy/pkg/printers"
+
+	apirequest "github.com/k3s-io/daas-apirequest"
+	httpserver "github.com/k3s-io/daas-httpserver"
+	service "github.com/k3s-io/daas-service"
+	sui "github.com/k3s-io/daas-sui"
+	version "github.com/k3s-io/daas-version"
 	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // ContractAPIGateway - kubectl과 Move Contract 간의 브릿지
@@ -20,8 +34,11 @@ type ContractAPIGateway struct {
 	contractAddress string
 	privateKeyHex   string
 	logger          *logrus.Logger
-	client          *resty.Client
-	responseCache   map[string]*PendingResponse
+	chain           *sui.SuiClient // 공용 Sui 클라이언트 (조회 전용)
+	responses       ResponseStore  // 요청-응답 대응 상태 (GATEWAY_RESPONSE_STORE로 복제본 간 공유)
+	callbackToken   string         // 실행자 결과 콜백 인증 (GATEWAY_CALLBACK_TOKEN, 비어 있으면 모의 응답)
+	master          *RegionRouter  // 읽기 요청을 홈 리전 마스터로 서명 전달 (마스터 구성 시)
+	mirror          *RequestMirror // 모든 요청을 직접 경로로 응답하고 컨트랙트 경로로 그림자 비교 (RequestMirroring 게이트)
 }
 
 // PendingResponse - 비동기 응답 대기 중인 요청
@@ -40,8 +57,9 @@ type PendingResponse struct {
 type K8sResponse struct {
 	StatusCode  int               `json:"status_code"`
 	Headers     map[string]string `json:"headers"`
-	Body        json.RawMessage   `json:"body"`
+	Body        *codec.Object     `json:"body"` // 가능하면 Protobuf로 보관, 응답 시 Accept 형식으로 변환
 	ProcessedAt time.Time         `json:"processed_at"`
+	ChainLag    time.Duration     `json:"chain_lag,omitempty"` // 실행자가 보고한 이벤트 수신 지연 (X-DaaS-Chain-Lag)
 }
 
 // KubectlRequest - kubectl 요청 구조체
@@ -52,6 +70,8 @@ type KubectlRequest struct {
 	ResourceType string            `json:"resource_type"`
 	Payload      []byte            `json:"payload"`
 	SealToken    string            `json:"seal_token"`
+	Owner        string            `json:"owner,omitempty"` // 위임 요청 대상 클러스터 소유자 (kubectl --as)
+	DeadlineMs   uint64            `json:"deadline_ms"`     // 클라이언트 마감 시각 (Unix ms, 마스터는 지난 요청을 실행하지 않음)
 	Headers      map[string]string `json:"headers"`
 	UserAgent    string            `json:"user_agent"`
 }
@@ -80,42 +100,82 @@ func NewContractAPIGateway(suiRPCURL, contractAddr, privateKey string) *Contract
 		contractAddress: contractAddr,
 		privateKeyHex:   privateKey,
 		logger:          logrus.New(),
-		client:          resty.New().SetTimeout(30 * time.Second),
-		responseCache:   make(map[string]*PendingResponse),
+		chain:           sui.NewReadOnlyClient(suiRPCURL, contractAddr),
+		responses:       newMemoryResponseStore(),
 	}
 }
 
-func (g *ContractAPIGateway) Start() {
-	g.logger.Info("🚀 Contract-First API Gateway starting...")
+// Start - 게이트웨이 실행 (ctx 취소 시 진행 중 요청 완료 후 종료)
+func (g *ContractAPIGateway) Start(ctx context.Context) {
+	g.logger.Infof("🚀 Contract-First API Gateway starting... (%s)", gatewayVersion)
+	features.LogSummary(func(format string, args ...interface{}) { g.logger.Infof("🚩 "+format, args...) })
+
+	// HTTP 핸들러 등록 (전용 mux)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", g.handleKubectlRequest)
+	mux.HandleFunc("/healthz", g.handleHealth)
+	mux.HandleFunc("/readyz", g.handleReady)
+	mux.HandleFunc("/api", g.handleAPIGroups)
+	mux.HandleFunc("/apis", g.handleAPIGroups)
+	mux.HandleFunc("/api/v1", g.handleAPIResources)
+	mux.HandleFunc("/apis/apps/v1", g.handleAPIResources)
+	// 서버 측 Helm 차트 렌더링 (출처 온체인 기록 후 일반 쓰기 경로로 제출)
+	if features.Enabled(featureHelmReleases) {
+		mux.HandleFunc("/daas/v1/helm/releases", g.handleHelmRelease)
+	}
+	// 실행 결과 콜백 (로드 밸런서가 어느 복제본으로 보내든 공유 저장소에서 대기 중인 요청을 완료)
+	if g.callbackToken != "" {
+		mux.HandleFunc("/daas/v1/responses/", g.handleResponseCallback)
+	}
+	// 직접 경로/컨트랙트 경로 불일치 통계
+	if g.mirror != nil {
+		mux.HandleFunc("/daas/v1/mirror", g.mirror.handleStats)
+	}
 
-	// HTTP 핸들러 등록
-	http.HandleFunc("/", g.handleKubectlRequest)
-	http.HandleFunc("/healthz", g.handleHealth)
-	http.HandleFunc("/readyz", g.handleReady)
-	http.HandleFunc("/api", g.handleAPIGroups)
-	http.HandleFunc("/apis", g.handleAPIGroups)
-	http.HandleFunc("/api/v1", g.handleAPIResources)
-	http.HandleFunc("/apis/apps/v1", g.handleAPIResources)
+	// 미들웨어 체인: 요청 ID → panic 복구 (Status 500) → gzip 압축 (Accept-Encoding 협상) → 접근 로그 (헬스체크 제외)
+	// 인증은 Seal 토큰 형식이 경로마다 달라 handleKubectlRequest에서 처리
+	middleware := []httpserver.Middleware{httpserver.RequestID, httpserver.Recovery(g.logger.Errorf)}
+	if features.Enabled(featureResponseCompression) {
+		middleware = append(middleware, httpserver.Compress(httpserver.DefaultCompressMinSize))
+	}
+	middleware = append(middleware, httpserver.Logging(g.logger.Debugf, "/healthz", "/readyz"))
+	handler := httpserver.Chain(mux, middleware...)
 
 	// 응답 정리 고루틴 시작
+	g.logger.Infof("📮 Response store: %s", g.responses.Description())
 	go g.cleanupExpiredResponses()
 
-	port := ":8080"
-	g.logger.Infof("🎯 API Gateway listening on %s", port)
+	// 리전 마스터 목록/상태 갱신
+	if g.master != nil {
+		go g.master.Start(ctx)
+	}
+
+	// GATEWAY_LISTEN_ADDR / GATEWAY_TLS_* / GATEWAY_HTTP_REDIRECT_ADDR / GATEWAY_HSTS_MAX_AGE / GATEWAY_HTTP2
+	listen := httpserver.ConfigFromEnv("GATEWAY", ":8080")
+	server, err := httpserver.New(listen, handler)
+	if err != nil {
+		g.logger.Fatalf("❌ Invalid listener config: %v", err)
+	}
+
+	g.logger.Infof("🎯 API Gateway listening on %s", server.Description())
 	g.logger.Info("📝 kubectl 설정:")
-	g.logger.Info("   kubectl config set-cluster k3s-daas --server=http://localhost:8080")
+	g.logger.Infof("   kubectl config set-cluster k3s-daas --server=%s://localhost%s", listen.Scheme(), listen.Addr)
 	g.logger.Info("   kubectl config set-credentials user --token=seal_YOUR_WALLET_SIGNATURE")
 	g.logger.Info("   kubectl config use-context k3s-daas")
 
-	if err := http.ListenAndServe(port, nil); err != nil {
+	if err := server.Run(ctx, 10*time.Second); err != nil {
 		g.logger.Fatalf("❌ Failed to start API Gateway: %v", err)
 	}
+	g.logger.Info("🛑 API Gateway stopped")
 }
 
 // handleKubectlRequest - kubectl 요청의 메인 진입점
 func (g *ContractAPIGateway) handleKubectlRequest(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
-	requestID := g.generateRequestID()
+	requestID := httpserver.RequestIDFrom(r.Context())
+	if requestID == "" {
+		requestID = g.generateRequestID()
+	}
 
 	g.logger.WithFields(logrus.Fields{
 		"request_id": requestID,
@@ -139,30 +199,231 @@ func (g *ContractAPIGateway) handleKubectlRequest(w http.ResponseWriter, r *http
 		return
 	}
 
-	// 3. Move Contract 호출 시뮬레이션 (실제 계약 없이 테스트용)
+	// watch는 응답을 모으지 않고 프레임 서명을 검증하며 흘려보냄 (요청 마감 시각 없이 kubectl 연결 동안 유지)
+	if g.master != nil && kubectlReq.Method == http.MethodGet && isWatch(r) {
+		g.streamFromMaster(w, r, kubectlReq, requestID, startTime)
+		return
+	}
+
+	// kubectl이 포기한 뒤 실행되지 않도록 마감 시각을 온체인 요청과 마스터 전달 모두에 적용
+	deadline := requestDeadline(r)
+	kubectlReq.DeadlineMs = uint64(deadline.UnixMilli())
+	ctx, cancel := context.WithDeadline(r.Context(), deadline)
+	defer cancel()
+
+	// 3. 읽기 요청은 서명하여 마스터로 직접 전달 (쓰기는 항상 온체인 경로)
+	if g.master != nil && kubectlReq.Method == http.MethodGet {
+		response := g.forwardToMaster(ctx, w, r, kubectlReq, r.URL.RawQuery, requestID, startTime)
+		if g.mirror != nil && response != nil {
+			g.mirror.Shadow(kubectlReq, requestID, response, time.Since(startTime))
+		}
+		return
+	}
+
+	// 4. 생성/수정 본문은 타입 객체로 변환하여 기본값 적용 및 검증 후 정규화된 객체를 제출
+	if kubectlReq.Method == http.MethodPost || kubectlReq.Method == http.MethodPut {
+		normalized, statusErr := admission.Normalize(kubectlReq.ResourceType, kubectlReq.Namespace, kubectlReq.Payload)
+		if statusErr != nil {
+			g.logger.WithFields(logrus.Fields{
+				"request_id": requestID,
+				"resource":   kubectlReq.ResourceType,
+				"code":       statusErr.Status().Code,
+			}).Warnf("🚫 Rejected invalid manifest: %s", statusErr.Error())
+			g.returnK8sStatus(w, statusErr)
+			return
+		}
+		kubectlReq.Payload = normalized
+	}
+
+	// 서버 측 dryRun은 컨트랙트에 제출하지 않고 마스터에서 어드미션과 검증만 (저장/온체인 기록 없음)
+	dryRun, err := dryRunRequested(r, kubectlReq)
+	if err != nil {
+		g.returnK8sError(w, "BadRequest", err.Error(), http.StatusBadRequest)
+		return
+	}
+	if dryRun {
+		g.forwardDryRun(ctx, w, r, kubectlReq, requestID, startTime)
+		return
+	}
+
+	// 미러링 중에는 쓰기도 직접 경로가 권위 응답 (컨트랙트 경로는 다시 실행하지 않고 수락 여부만 비교)
+	if g.mirror != nil {
+		response := g.forwardToMaster(ctx, w, r, kubectlReq, r.URL.RawQuery, requestID, startTime)
+		if response != nil {
+			g.mirror.Shadow(kubectlReq, requestID, response, time.Since(startTime))
+		}
+		return
+	}
+
+	// 온체인 제출 형식(공용 스키마)으로 변환 후 컨트랙트와 같은 검사 (체인에서 abort될 요청은 미리 거부)
+	submission := kubectlReq.apiRequest(requestID)
+	if err := submission.Validate(); err != nil {
+		g.logger.WithError(err).WithField("request_id", requestID).Warn("🚫 Request does not match the contract schema")
+		g.returnK8sError(w, "BadRequest", err.Error(), http.StatusBadRequest)
+		return
+	}
+	// 매니페스트는 검증 후 마스터의 증명된 엔클레이브 키로 암호화 (온체인에는 암호문과 평문 해시만)
+	if features.Enabled(featureEncryptedPayloads) && submission.Payload != "" {
+		if err := g.encryptPayload(ctx, submission); err != nil {
+			g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Payload encryption unavailable")
+			g.returnK8sError(w, "ServiceUnavailable", "payload encryption unavailable: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	// 5. 온체인 제출 후 실행 결과 대기
+	submittedAt := time.Now()
+	response, err := g.submitOnChain(ctx, kubectlReq, submission, startTime)
+	switch {
+	case err == errRequestInFlight:
+		g.returnK8sError(w, "Conflict", err.Error(), http.StatusConflict)
+		return
+	case err != nil && ctx.Err() == context.DeadlineExceeded:
+		g.returnK8sError(w, "Timeout", "request did not complete before the client deadline", http.StatusGatewayTimeout)
+		return
+	case err != nil:
+		g.returnK8sError(w, "ServiceUnavailable", err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	// 6. kubectl에 응답 (Accept 헤더 형식으로, 컨트랙트 경로의 체인 지연 포함)
+	setChainLag(w, response, submittedAt)
+	g.writeKubectlResponse(w, r, response)
+
+	duration := time.Since(startTime)
+	g.logger.WithFields(logrus.Fields{
+		"request_id": requestID,
+		"duration":   duration,
+		"status":     response.StatusCode,
+	}).Info("✅ Request completed")
+}
+
+// submitOnChain - 온체인 제출 후 실행자 결과 대기 (미러링의 그림자 요청도 같은 경로)
+func (g *ContractAPIGateway) submitOnChain(ctx context.Context, kubectlReq *KubectlRequest, submission *apirequest.Request, startTime time.Time) (*K8sResponse, error) {
+	requestID := submission.RequestID
+
+	// Move Contract 호출 시뮬레이션 (실제 계약 없이 테스트용)
+	// 소유자가 지정된 위임 요청은 submit_delegated_k8s_request로 제출되고 마스터 RBAC가 권한을 확인
+	function := apirequest.SubmitFunction
+	if kubectlReq.Owner != "" {
+		function = apirequest.DelegatedSubmitFunction
+	}
 	g.logger.WithFields(logrus.Fields{
 		"request_id": requestID,
 		"method":     kubectlReq.Method,
 		"path":       kubectlReq.Path,
+		"function":   function,
+		"resource":   submission.Resource,
+		"name":       submission.Name,
+		"owner":      kubectlReq.Owner,
+		"deadline":   time.UnixMilli(int64(submission.DeadlineMs)).UTC().Format(time.RFC3339Nano),
+		"encrypted":  submission.PayloadEncrypted(),
 	}).Info("🔗 Simulating contract call for testing")
 
-	// 4. 모의 응답 생성 (테스트용)
+	// 응답 대기 등록 - 실행 결과는 콜백으로 어느 복제본에 도착해도 저장소를 통해 이 요청으로 전달됨
+	if err := g.responses.Register(ctx, &PendingResponse{
+		RequestID: requestID,
+		StartTime: startTime,
+		Method:    kubectlReq.Method,
+		Path:      kubectlReq.Path,
+		Requester: kubectlReq.Owner,
+	}); err != nil {
+		if err != errRequestInFlight {
+			g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Response store unavailable")
+		}
+		return nil, err
+	}
+	defer g.responses.Release(context.Background(), requestID)
+
+	// 콜백이 구성되지 않았으면 모의 응답으로 완료 (테스트용) - 쓰기 요청은 정규화된 객체를 그대로 돌려줌
+	if g.callbackToken == "" {
+		g.responses.Complete(ctx, requestID, g.mockResponse(kubectlReq))
+	}
+	response, err := g.responses.Wait(ctx, requestID)
+	if err != nil && ctx.Err() != context.DeadlineExceeded {
+		g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Waiting for response failed")
+	}
+	return response, err
+}
+
+// forwardToMaster - 서명하여 마스터로 전달하고 서명 검증된 응답을 kubectl에 전송 (읽기, 서버 측 dryRun, 미러링)
+// 전송한 응답을 돌려줌 (실패로 오류를 응답했으면 nil)
+func (g *ContractAPIGateway) forwardToMaster(ctx context.Context, w http.ResponseWriter, r *http.Request, kubectlReq *KubectlRequest, rawQuery, requestID string, startTime time.Time) *K8sResponse {
+	response, err := g.master.Forward(ctx, kubectlReq, rawQuery)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		g.returnK8sError(w, "Timeout", "request did not complete before the client deadline", http.StatusGatewayTimeout)
+		return nil
+	}
+	if err != nil {
+		g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Signed master forward failed")
+		g.returnK8sError(w, "ServiceUnavailable", err.Error(), 503)
+		return nil
+	}
+	g.writeKubectlResponse(w, r, response)
+	g.logger.WithFields(logrus.Fields{
+		"request_id": requestID,
+		"method":     kubectlReq.Method,
+		"duration":   time.Since(startTime),
+		"status":     response.StatusCode,
+	}).Info("✅ Request completed via signed master forward")
+	return response
+}
+
+// streamFromMaster - watch를 마스터로 서명 전달하고 프레임마다 서명 검증된 이벤트를 kubectl에 전송
+func (g *ContractAPIGateway) streamFromMaster(w http.ResponseWriter, r *http.Request, kubectlReq *KubectlRequest, requestID string, startTime time.Time) {
+	status, err := g.master.Stream(r.Context(), w, kubectlReq, r.URL.RawQuery, r.Header.Get("Accept"))
+	if err != nil && status == 0 {
+		g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Signed master watch failed")
+		g.returnK8sError(w, "ServiceUnavailable", err.Error(), 503)
+		return
+	}
+
+	fields := logrus.Fields{
+		"request_id": requestID,
+		"path":       kubectlReq.Path,
+		"duration":   time.Since(startTime),
+		"status":     status,
+	}
+	if err != nil && r.Context().Err() == nil {
+		g.logger.WithError(err).WithFields(fields).Warn("⚠️ Watch stream from master aborted")
+		return
+	}
+	g.logger.WithFields(fields).Info("✅ Watch completed via signed master forward")
+}
+
+// mockResponse - 실행자 없이 쓰는 모의 응답 (쓰기 요청은 정규화된 객체를 그대로 돌려줌)
+func (g *ContractAPIGateway) mockResponse(kubectlReq *KubectlRequest) *K8sResponse {
 	response := &K8sResponse{
 		StatusCode: 200,
 		Headers:    map[string]string{"Content-Type": "application/json"},
-		Body:       json.RawMessage(`{"apiVersion": "v1", "kind": "PodList", "items": []}`),
+		Body:       codec.NewJSONObject([]byte(`{"apiVersion": "v1", "kind": "PodList", "items": []}`)),
 		ProcessedAt: time.Now(),
 	}
+	if admission.Supported(kubectlReq.ResourceType) && (kubectlReq.Method == http.MethodPost || kubectlReq.Method == http.MethodPut) {
+		response.Body = codec.NewJSONObject(kubectlReq.Payload)
+		if kubectlReq.Method == http.MethodPost {
+			response.StatusCode = http.StatusCreated
+		}
+	}
+	return response
+}
 
-	// 5. kubectl에 응답
-	g.writeKubectlResponse(w, response)
+// isWatch - watch=true|1 쿼리 여부 (끝나지 않는 스트리밍 응답)
+func isWatch(r *http.Request) bool {
+	watch := r.URL.Query().Get("watch")
+	return watch == "true" || watch == "1"
+}
 
-	duration := time.Since(startTime)
-	g.logger.WithFields(logrus.Fields{
-		"request_id": requestID,
-		"duration":   duration,
-		"status":     response.StatusCode,
-	}).Info("✅ Request completed")
+// requestDeadline - kubectl --request-timeout(?timeout=)과 GATEWAY_REQUEST_TIMEOUT(기본값이자 상한, 초) 중 짧은 쪽
+func requestDeadline(r *http.Request) time.Time {
+	limit := 60 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("GATEWAY_REQUEST_TIMEOUT")); err == nil && seconds > 0 {
+		limit = time.Duration(seconds) * time.Second
+	}
+	if timeout, err := time.ParseDuration(r.URL.Query().Get("timeout")); err == nil && timeout > 0 && timeout < limit {
+		limit = timeout
+	}
+	return time.Now().Add(limit)
 }
 
 // parseKubectlRequest - kubectl 요청을 Contract 호출 형태로 변환
@@ -173,6 +434,16 @@ func (g *ContractAPIGateway) parseKubectlRequest(r *http.Request, sealToken stri
 	}
 	defer r.Body.Close()
 
+	// YAML/Protobuf 본문은 내부 JSON 표현으로 변환하여 제출
+	payload, err := codec.ToJSON(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, err
+	}
+	headers := g.extractHeaders(r)
+	if mediaType, _ := codec.MediaType(r.Header.Get("Content-Type")); mediaType == codec.MediaTypeYAML || mediaType == codec.MediaTypeProtobuf {
+		headers["Content-Type"] = codec.MediaTypeJSON
+	}
+
 	// URL 경로에서 namespace와 resource type 추출
 	namespace, resourceType := g.parseK8sPath(r.URL.Path)
 
@@ -181,13 +452,43 @@ func (g *ContractAPIGateway) parseKubectlRequest(r *http.Request, sealToken stri
 		Path:         r.URL.Path,
 		Namespace:    namespace,
 		ResourceType: resourceType,
-		Payload:      body,
+		Payload:      payload,
 		SealToken:    sealToken,
-		Headers:      g.extractHeaders(r),
+		Owner:        r.Header.Get("Impersonate-User"),
+		Headers:      headers,
 		UserAgent:    r.UserAgent(),
 	}, nil
 }
 
+// defaultRequestPriority - 온체인 요청 우선순위 (컨트랙트 허용 범위 1-10의 중간)
+const defaultRequestPriority = 5
+
+// apiRequest - 온체인 제출 형식으로 변환 (Requester와 AssignedWorker는 컨트랙트가 채움)
+func (k *KubectlRequest) apiRequest(requestID string) *apirequest.Request {
+	return &apirequest.Request{
+		RequestID:  requestID,
+		Method:     k.Method,
+		Resource:   k.ResourceType,
+		Namespace:  k.Namespace,
+		Name:       resourceName(k.Path, k.ResourceType),
+		Payload:    string(k.Payload),
+		SealToken:  k.SealToken,
+		Priority:   defaultRequestPriority,
+		DeadlineMs: k.DeadlineMs,
+	}
+}
+
+// resourceName - 경로에서 리소스 다음 세그먼트 (/api/v1/namespaces/default/pods/web → web, 목록이면 "")
+func resourceName(path, resource string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == resource {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
 // 유틸리티 함수들
 func (g *ContractAPIGateway) extractSealToken(r *http.Request) string {
 	auth := r.Header.Get("Authorization")
@@ -205,7 +506,8 @@ func (g *ContractAPIGateway) parseK8sPath(path string) (namespace, resourceType
 		if part == "namespaces" && i+1 < len(parts) {
 			namespace = parts[i+1]
 		}
-		if part == "pods" || part == "services" || part == "deployments" {
+		// 네임스페이스 자체(/api/v1/namespaces[/이름])는 "namespaces" 리소스
+		if part == "namespaces" && i+2 >= len(parts) || part != "namespaces" && admission.Supported(part) {
 			resourceType = part
 		}
 	}
@@ -228,16 +530,59 @@ func (g *ContractAPIGateway) extractHeaders(r *http.Request) map[string]string {
 }
 
 func (g *ContractAPIGateway) generateRequestID() string {
-	return fmt.Sprintf("req_%d", time.Now().UnixNano())
+	// 복제본끼리 같은 저장소를 쓰므로 시각만으로는 충돌할 수 있음
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("req_%d_%s", time.Now().UnixNano(), hex.EncodeToString(suffix))
 }
 
-func (g *ContractAPIGateway) writeKubectlResponse(w http.ResponseWriter, response *K8sResponse) {
+// writeKubectlResponse - 보관된 응답을 클라이언트 Accept 형식으로 변환하여 전송
+// kubectl get의 as=Table 요청은 서버 측에서 컬럼을 구성한 Table로 응답
+func (g *ContractAPIGateway) writeKubectlResponse(w http.ResponseWriter, r *http.Request, response *K8sResponse) {
 	for key, value := range response.Headers {
 		w.Header().Set(key, value)
 	}
 
+	// 보관 형식(Protobuf/JSON)에서 Accept 형식으로 변환 - Protobuf 클라이언트는 변환 없이 전달
+	accept := r.Header.Get("Accept")
+	body, contentType := response.Body.Encode(accept)
+	if response.StatusCode < 300 && response.Body.IsKubernetes() && printers.WantsTable(accept) {
+		if object, err := response.Body.JSON(); err == nil {
+			if table, err := printers.ToTable(object, time.Now()); err == nil {
+				body, contentType = codec.Encode(accept, table)
+			} else {
+				g.logger.WithError(err).Debug("Table output not available, returning object")
+			}
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+
 	w.WriteHeader(response.StatusCode)
-	w.Write(response.Body)
+	writeChunked(w, body)
+}
+
+// responseChunkSize - 큰 목록 응답을 나눠 보내는 단위
+const responseChunkSize = 64 * 1024
+
+// writeChunked - 큰 본문은 청크 단위로 flush하여 전송 (Content-Length 없이 chunked 인코딩)
+// 느린 링크에서도 kubectl이 전체 본문을 기다리지 않고 수신/디코딩을 시작할 수 있음
+func writeChunked(w http.ResponseWriter, body []byte) {
+	flusher, ok := w.(http.Flusher)
+	if !ok || len(body) <= responseChunkSize {
+		w.Write(body)
+		return
+	}
+	for len(body) > 0 {
+		n := responseChunkSize
+		if n > len(body) {
+			n = len(body)
+		}
+		if _, err := w.Write(body[:n]); err != nil {
+			return
+		}
+		flusher.Flush()
+		body = body[n:]
+	}
 }
 
 func (g *ContractAPIGateway) returnK8sError(w http.ResponseWriter, reason, message string, code int) {
@@ -256,12 +601,51 @@ func (g *ContractAPIGateway) returnK8sError(w http.ResponseWriter, reason, messa
 	json.NewEncoder(w).Encode(errorResponse)
 }
 
+// returnK8sStatus - 검증 실패 등 apimachinery StatusError를 그대로 Status로 응답 (422는 details.causes에 필드 경로)
+func (g *ContractAPIGateway) returnK8sStatus(w http.ResponseWriter, statusErr *apierrors.StatusError) {
+	status := admission.StatusOf(statusErr)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	json.NewEncoder(w).Encode(status)
+}
+
 func (g *ContractAPIGateway) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(version.Header, gatewayVersion.HeaderValue())
 	w.WriteHeader(200)
+	if _, verbose := r.URL.Query()["verbose"]; verbose {
+		fmt.Fprintf(w, "[+]version %s (skew policy %s, max %d minor)\n", gatewayVersion, versionPolicy.Mode, versionPolicy.MaxMinorSkew)
+		if g.master != nil {
+			g.master.writeVersions(w)
+		}
+		if g.mirror != nil {
+			g.mirror.writeSummary(w)
+		}
+		features.WriteVerbose(w)
+		fmt.Fprintf(w, "healthz check passed\n")
+		return
+	}
 	fmt.Fprintf(w, "OK")
 }
 
+// handleReady - Sui RPC에 도달할 수 있어야 준비 완료
 func (g *ContractAPIGateway) handleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, err := g.chain.ChainTime(ctx); err != nil {
+		g.logger.Warnf("⚠️ Sui RPC not reachable: %v", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "Sui RPC unavailable")
+		return
+	}
+	// 공유 저장소에 닿지 않는 복제본은 로드 밸런서에서 빠지도록
+	if err := g.responses.Ping(ctx); err != nil {
+		g.logger.Warnf("⚠️ Response store not reachable: %v", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "Response store unavailable")
+		return
+	}
+
 	w.WriteHeader(200)
 	fmt.Fprintf(w, "Ready")
 }
@@ -281,7 +665,7 @@ func (g *ContractAPIGateway) handleAPIGroups(w http.ResponseWriter, r *http.Requ
 			"apiVersion": "v1",
 			"versions":   []string{"v1"},
 			"serverAddressByClientCIDRs": []map[string]string{
-				{"clientCIDR": "0.0.0.0/0", "serverAddress": "localhost:8080"},
+				{"clientCIDR": "0.0.0.0/0", "serverAddress": r.Host},
 			},
 		}
 		json.NewEncoder(w).Encode(apiGroupResponse)
@@ -335,6 +719,22 @@ func (g *ContractAPIGateway) handleAPIResources(w http.ResponseWriter, r *http.R
 					"kind":         "Service",
 					"verbs":        []string{"create", "delete", "get", "list", "patch", "update", "watch"},
 				},
+				{
+					"name":         "persistentvolumeclaims",
+					"singularName": "persistentvolumeclaim",
+					"namespaced":   true,
+					"kind":         "PersistentVolumeClaim",
+					"shortNames":   []string{"pvc"},
+					"verbs":        []string{"create", "delete", "get", "list"},
+				},
+				{
+					"name":         "limitranges",
+					"singularName": "limitrange",
+					"namespaced":   true,
+					"kind":         "LimitRange",
+					"shortNames":   []string{"limits"},
+					"verbs":        []string{"create", "delete", "get", "list", "patch", "update"},
+				},
 				{
 					"name":         "nodes",
 					"singularName": "node",
@@ -359,6 +759,14 @@ func (g *ContractAPIGateway) handleAPIResources(w http.ResponseWriter, r *http.R
 					"kind":         "Deployment",
 					"verbs":        []string{"create", "delete", "get", "list", "patch", "update", "watch"},
 				},
+				{
+					"name":         "statefulsets",
+					"singularName": "statefulset",
+					"namespaced":   true,
+					"kind":         "StatefulSet",
+					"shortNames":   []string{"sts"},
+					"verbs":        []string{"create", "delete", "get", "list", "patch", "update"},
+				},
 			},
 		}
 		json.NewEncoder(w).Encode(appsAPIResources)
@@ -370,22 +778,81 @@ func (g *ContractAPIGateway) cleanupExpiredResponses() {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		now := time.Now()
-		for id, pending := range g.responseCache {
-			if now.Sub(pending.StartTime) > 5*time.Minute {
-				delete(g.responseCache, id)
-			}
-		}
+		g.responses.Expire(responseTTL)
 	}
 }
 
 // main 함수
+// serviceName - systemd 유닛 / Windows 서비스 이름
+const serviceName = "k3s-daas-gateway"
+
 func main() {
+	// 기능 게이트: GATEWAY_FEATURE_GATES < --feature-gates
+	if err := features.LoadEnv("GATEWAY_FEATURE_GATES"); err != nil {
+		logrus.WithError(err).Fatal("Invalid feature gates")
+	}
+	args, err := features.ParseArgs(os.Args[1:])
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid feature gates")
+	}
+
+	// 빌드 버전: gateway version
+	if len(args) > 0 && args[0] == "version" {
+		fmt.Println(gatewayVersion)
+		return
+	}
+	// 마스터와의 버전 차이 정책: GATEWAY_VERSION_SKEW_POLICY, GATEWAY_VERSION_MAX_MINOR_SKEW
+	if versionPolicy, err = version.PolicyFromEnv("GATEWAY"); err != nil {
+		logrus.WithError(err).Fatal("Invalid version skew policy")
+	}
+
+	// 서비스 관리: gateway service install|uninstall|start|stop|restart|status
+	if len(args) > 0 && args[0] == "service" {
+		spec := service.Spec{
+			Name:        serviceName,
+			DisplayName: "K3s-DaaS API Gateway",
+			Description: "K3s-DaaS kubectl API gateway (Sui contract / master forwarding)",
+		}
+		if err := service.Command(spec, args[1:], os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Service management failed")
+		}
+		return
+	}
+
 	gateway := NewContractAPIGateway(
 		"https://fullnode.testnet.sui.io:443",
 		"0x0", // Contract address - 실제 배포 후 설정
 		"",    // Private key - 환경변수에서 로드
 	)
 
-	gateway.Start()
+	master, err := NewRegionRouterFromEnv(gateway.logger, gateway.chain)
+	if err != nil {
+		gateway.logger.Fatalf("❌ Invalid master forwarding config: %v", err)
+	}
+	gateway.master = master
+
+	// 여러 복제본 운영: GATEWAY_RESPONSE_STORE=redis://... 와 GATEWAY_CALLBACK_TOKEN (실행자가 결과를 POST)
+	responses, err := NewResponseStoreFromEnv()
+	if err != nil {
+		gateway.logger.Fatalf("❌ Invalid response store config: %v", err)
+	}
+	gateway.responses = responses
+	gateway.callbackToken = os.Getenv("GATEWAY_CALLBACK_TOKEN")
+
+	// 컨트랙트 모드 전환 전 검증: 직접 경로로 응답하고 컨트랙트 경로 결과와 비교 (마스터 전달 필요)
+	if features.Enabled(featureRequestMirroring) {
+		if master == nil {
+			gateway.logger.Fatalf("❌ %s requires a master connection (NAUTILUS_MASTER_URL or NAUTILUS_MASTERS)", featureRequestMirroring)
+		}
+		if gateway.mirror, err = NewRequestMirror(gateway); err != nil {
+			gateway.logger.Fatalf("❌ Invalid request mirroring config: %v", err)
+		}
+	}
+
+	// Windows 서비스로 실행 시 EventLog, 그 외 stderr (systemd에서는 journald로 수집)
+	gateway.logger.SetOutput(service.LogOutput(serviceName))
+
+	ctx, stop := service.NotifyContext(context.Background(), serviceName)
+	defer stop()
+	gateway.Start(ctx)
 }
\ No newline at end of file
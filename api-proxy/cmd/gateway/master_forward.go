@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"api-proxy/pkg/codec"
+
+	"github.com/k3s-io/daas-signing"
+	version "github.com/k3s-io/daas-version"
+)
+
+// MasterForwarder - 읽기 요청을 Nautilus 마스터로 서명 전달하고 응답 서명 검증
+type MasterForwarder struct {
+	masterURL  string
+	signingKey ed25519.PrivateKey
+	masterKey  ed25519.PublicKey
+	httpClient *http.Client
+	// watch 스트림용 (끝나지 않는 응답이므로 전체 타임아웃 없음, 종료는 요청 context로)
+	streamClient *http.Client
+
+	mutex         sync.Mutex
+	masterVersion string          // 마스터 응답의 X-Daas-Version
+	payloadKey    *ecdh.PublicKey // 증명 문서로 확인한 본문 암호화 키 (EncryptedPayloads)
+	payloadKeyAt  time.Time
+}
+
+// newMasterForwarder - 마스터 하나로의 서명 전달기 (리전 구성은 RegionRouter가 관리)
+func newMasterForwarder(masterURL string, signingKey ed25519.PrivateKey, masterKey ed25519.PublicKey) *MasterForwarder {
+	return &MasterForwarder{
+		masterURL:    strings.TrimSuffix(masterURL, "/"),
+		signingKey:   signingKey,
+		masterKey:    masterKey,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		streamClient: &http.Client{},
+	}
+}
+
+// Forward - 서명된 요청 전달 후 서명 검증된 응답 반환
+func (f *MasterForwarder) Forward(ctx context.Context, kubectlReq *KubectlRequest, rawQuery string) (*K8sResponse, error) {
+	// 내장 타입은 Protobuf로 받아 그대로 보관 (클라이언트 형식 변환은 Gateway가 응답 시 담당)
+	req, nonce, err := f.signedRequest(ctx, kubectlReq, rawQuery, codec.AcceptStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("master request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master response: %v", err)
+	}
+
+	if err := signing.VerifyResponse(f.masterKey, resp, nonce, body); err != nil {
+		return nil, fmt.Errorf("master response rejected: %v", err)
+	}
+	if err := f.observeVersion(resp.Header.Get(version.Header)); err != nil {
+		return nil, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = codec.MediaTypeJSON
+	}
+	headers := map[string]string{}
+	// 테넌트 요청 제한(429) 시 kubectl이 재시도 간격을 알 수 있도록 전달
+	for _, key := range []string{"Retry-After", "X-Kubernetes-Pf-Flowschema-Uid", "X-Kubernetes-Pf-Prioritylevel-Uid"} {
+		if value := resp.Header.Get(key); value != "" {
+			headers[key] = value
+		}
+	}
+	return &K8sResponse{
+		StatusCode:  resp.StatusCode,
+		Headers:     headers,
+		Body:        codec.NewObject(contentType, body),
+		ProcessedAt: time.Now(),
+	}, nil
+}
+
+/*
+Stream - watch 요청을 서명 전달하고, 마스터가 프레임마다 서명한 응답을 검증하며 kubectl로 흘려보냄
+
+본문을 모으지 않고 전체 타임아웃도 두지 않습니다 (종료는 kubectl 연결 또는 timeoutSeconds에 따라 K3s가 결정).
+응답 형식 변환 없이 kubectl의 Accept를 그대로 마스터에 전달합니다.
+반환하는 상태 코드가 0이면 kubectl에 아직 아무것도 보내지 않았으므로 호출자가 오류 응답을 쓸 수 있습니다.
+*/
+func (f *MasterForwarder) Stream(ctx context.Context, w http.ResponseWriter, kubectlReq *KubectlRequest, rawQuery, accept string) (int, error) {
+	req, nonce, err := f.signedRequest(ctx, kubectlReq, rawQuery, accept)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := f.streamClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("master request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := signing.VerifyStream(f.masterKey, resp, nonce)
+	if err != nil {
+		return 0, fmt.Errorf("master response rejected: %v", err)
+	}
+	if err := f.observeVersion(resp.Header.Get(version.Header)); err != nil {
+		return 0, err
+	}
+
+	for _, key := range []string{"Content-Type", "Retry-After"} {
+		if value := resp.Header.Get(key); value != "" {
+			w.Header().Set(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	// 검증된 프레임만 전달 - 서명이 맞지 않으면 중단하여 kubectl이 watch를 다시 시작하게 함
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return resp.StatusCode, werr
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return resp.StatusCode, nil
+		}
+		if err != nil {
+			return resp.StatusCode, fmt.Errorf("master stream rejected: %v", err)
+		}
+	}
+}
+
+// signedRequest - 마스터로 보낼 요청 생성 및 서명 (응답 검증에 쓸 nonce 반환)
+func (f *MasterForwarder) signedRequest(ctx context.Context, kubectlReq *KubectlRequest, rawQuery, accept string) (*http.Request, string, error) {
+	target := f.masterURL + kubectlReq.Path
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, kubectlReq.Method, target, bytes.NewReader(kubectlReq.Payload))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build master request: %v", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if contentType := kubectlReq.Headers["Content-Type"]; contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if kubectlReq.Owner != "" {
+		req.Header.Set("Impersonate-User", kubectlReq.Owner)
+	}
+	// 범위 제한 토큰이면 마스터가 네임스페이스/리소스/동사를 확인하도록 함께 전달 (서명된 요청으로만 받음)
+	if kubectlReq.SealToken != "" {
+		req.Header.Set("X-Seal-Token", kubectlReq.SealToken)
+	}
+	req.Header.Set(version.Header, gatewayVersion.HeaderValue())
+
+	nonce, err := signing.SignRequest(f.signingKey, req, kubectlReq.Payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return req, nonce, nil
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+요청 미러링 (RequestMirroring 게이트, 직접 모드에서 컨트랙트 모드로 전환하기 전 검증용)
+
+모든 kubectl 요청은 서명된 마스터 전달(직접 경로)로 처리되고 그 응답만 클라이언트에 돌아갑니다.
+응답 후 같은 요청을 그림자로 컨트랙트 경로에 보내 결과를 비교하고 불일치 통계만 남깁니다.
+  - 읽기: 그림자 요청 ID(<id>-shadow)로 온체인 제출 후 실행자 결과를 받아 상태 코드와 객체 집합(이름/UID) 비교
+  - 쓰기: 직접 경로에서 이미 실행했으므로 다시 실행하지 않고, 컨트랙트가 같은 요청을 받아들이는지(공용 스키마 검사)만 확인
+    (직접 경로는 성공했는데 컨트랙트가 abort할 요청은 전환 후 실패하는 요청)
+그림자 요청은 클라이언트 응답을 기다리게 하지 않으며, 가스 비용은 GATEWAY_MIRROR_SAMPLE_PERCENT로 줄입니다.
+
+	GET /daas/v1/mirror   결과별 건수, 경로별 평균 지연, 최근 불일치 (GATEWAY_ADMIN_TOKEN Bearer)
+*/
+
+// 미러링 결과
+const (
+	mirrorMatch            = "match"
+	mirrorStatusMismatch   = "status_mismatch"
+	mirrorBodyMismatch     = "body_mismatch"
+	mirrorShadowError      = "shadow_error"
+	mirrorContractRejected = "contract_rejected"
+	mirrorWriteAccepted    = "write_accepted"
+	mirrorSkipped          = "skipped"
+)
+
+var mirrorOutcomes = []string{mirrorMatch, mirrorStatusMismatch, mirrorBodyMismatch, mirrorShadowError, mirrorContractRejected, mirrorWriteAccepted, mirrorSkipped}
+
+// mirrorRecentLimit - 보관하는 최근 불일치 수
+const mirrorRecentLimit = 50
+
+// MirrorDivergence - 직접 경로와 컨트랙트 경로가 다르게 끝난 요청
+type MirrorDivergence struct {
+	RequestID    string    `json:"request_id"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Outcome      string    `json:"outcome"`
+	DirectStatus int       `json:"direct_status"`
+	ShadowStatus int       `json:"shadow_status,omitempty"`
+	Detail       string    `json:"detail"`
+	At           time.Time `json:"at"`
+}
+
+// MirrorStats - 미러링 통계
+type MirrorStats struct {
+	SamplePercent   int                `json:"sample_percent"`
+	Outcomes        map[string]uint64  `json:"outcomes"`
+	DirectLatencyMs float64            `json:"direct_latency_avg_ms"`
+	ShadowLatencyMs float64            `json:"shadow_latency_avg_ms"`
+	Recent          []MirrorDivergence `json:"recent_divergences"`
+}
+
+// RequestMirror - 그림자 요청 실행과 비교
+type RequestMirror struct {
+	gateway       *ContractAPIGateway
+	logger        *logrus.Logger
+	samplePercent int
+	timeout       time.Duration
+	adminToken    string
+
+	mutex    sync.Mutex
+	outcomes map[string]uint64
+	direct   latencyTotal
+	shadow   latencyTotal
+	recent   []MirrorDivergence
+}
+
+type latencyTotal struct {
+	sum   time.Duration
+	count uint64
+}
+
+func (l latencyTotal) averageMs() float64 {
+	if l.count == 0 {
+		return 0
+	}
+	return float64(l.sum.Milliseconds()) / float64(l.count)
+}
+
+// NewRequestMirror - GATEWAY_MIRROR_SAMPLE_PERCENT(기본 100), GATEWAY_MIRROR_TIMEOUT(초, 기본 60)
+func NewRequestMirror(gateway *ContractAPIGateway) (*RequestMirror, error) {
+	percent := 100
+	if value := os.Getenv("GATEWAY_MIRROR_SAMPLE_PERCENT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 100 {
+			return nil, fmt.Errorf("GATEWAY_MIRROR_SAMPLE_PERCENT must be between 1 and 100")
+		}
+		percent = parsed
+	}
+	timeout := 60 * time.Second
+	if value := os.Getenv("GATEWAY_MIRROR_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("GATEWAY_MIRROR_TIMEOUT must be a positive number of seconds")
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	return &RequestMirror{
+		gateway:       gateway,
+		logger:        gateway.logger,
+		samplePercent: percent,
+		timeout:       timeout,
+		adminToken:    os.Getenv("GATEWAY_ADMIN_TOKEN"),
+		outcomes:      make(map[string]uint64),
+	}, nil
+}
+
+// Shadow - 직접 경로 응답을 받은 요청을 컨트랙트 경로로 비교 (클라이언트 응답 후 백그라운드)
+func (m *RequestMirror) Shadow(kubectlReq *KubectlRequest, requestID string, direct *K8sResponse, directLatency time.Duration) {
+	if m.samplePercent < 100 && rand.Intn(100) >= m.samplePercent {
+		m.record(kubectlReq, requestID, mirrorSkipped, direct.StatusCode, 0, "")
+		return
+	}
+	m.mutex.Lock()
+	m.direct.sum += directLatency
+	m.direct.count++
+	m.mutex.Unlock()
+
+	shadowReq := *kubectlReq
+	go m.run(&shadowReq, requestID, direct)
+}
+
+func (m *RequestMirror) run(kubectlReq *KubectlRequest, requestID string, direct *K8sResponse) {
+	// 클라이언트 마감과 무관하게 그림자 요청 자체의 마감을 둠 (지난 마감은 실행자가 거부)
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	kubectlReq.DeadlineMs = uint64(time.Now().Add(m.timeout).UnixMilli())
+
+	submission := kubectlReq.apiRequest(requestID + "-shadow")
+	if err := submission.Validate(); err != nil {
+		outcome := mirrorContractRejected
+		if direct.StatusCode >= 300 {
+			outcome = mirrorMatch // 직접 경로도 실패한 요청은 양쪽 모두 실패
+		}
+		m.record(kubectlReq, requestID, outcome, direct.StatusCode, 0, err.Error())
+		return
+	}
+	if kubectlReq.Method != http.MethodGet {
+		m.record(kubectlReq, requestID, mirrorWriteAccepted, direct.StatusCode, 0, "")
+		return
+	}
+
+	started := time.Now()
+	shadow, err := m.gateway.submitOnChain(ctx, kubectlReq, submission, started)
+	if err != nil {
+		m.record(kubectlReq, requestID, mirrorShadowError, direct.StatusCode, 0, err.Error())
+		return
+	}
+	m.mutex.Lock()
+	m.shadow.sum += time.Since(started)
+	m.shadow.count++
+	m.mutex.Unlock()
+
+	outcome, detail := compareMirrored(direct, shadow)
+	m.record(kubectlReq, requestID, outcome, direct.StatusCode, shadow.StatusCode, detail)
+}
+
+// compareMirrored - 상태 코드와 객체 지문 비교 (resourceVersion/시각처럼 실행 시점마다 다른 값은 제외)
+func compareMirrored(direct, shadow *K8sResponse) (string, string) {
+	if direct.StatusCode != shadow.StatusCode {
+		return mirrorStatusMismatch, fmt.Sprintf("direct %d, contract %d", direct.StatusCode, shadow.StatusCode)
+	}
+	directPrint, shadowPrint := responseFingerprint(direct), responseFingerprint(shadow)
+	if directPrint != shadowPrint {
+		return mirrorBodyMismatch, fmt.Sprintf("direct %s, contract %s", directPrint, shadowPrint)
+	}
+	return mirrorMatch, ""
+}
+
+/*
+responseFingerprint - 응답 객체의 비교용 요약
+
+	목록     PodList[default/web@<uid>,default/db@<uid>]
+	객체     Pod[default/web@<uid>]
+	Status   Status[NotFound]
+*/
+func responseFingerprint(response *K8sResponse) string {
+	if response.Body == nil {
+		return "<empty>"
+	}
+	body, err := response.Body.JSON()
+	if err != nil {
+		return "<undecodable>"
+	}
+	type metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		UID       string `json:"uid"`
+	}
+	var object struct {
+		Kind     string   `json:"kind"`
+		Reason   string   `json:"reason"`
+		Metadata metadata `json:"metadata"`
+		Items    []struct {
+			Metadata metadata `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return "<not an object>"
+	}
+	identity := func(m metadata) string {
+		name := m.Name
+		if m.Namespace != "" {
+			name = m.Namespace + "/" + name
+		}
+		return name + "@" + m.UID
+	}
+
+	switch {
+	case object.Kind == "Status":
+		return "Status[" + object.Reason + "]"
+	case strings.HasSuffix(object.Kind, "List"):
+		items := make([]string, len(object.Items))
+		for i, item := range object.Items {
+			items[i] = identity(item.Metadata)
+		}
+		sort.Strings(items)
+		return object.Kind + "[" + strings.Join(items, ",") + "]"
+	default:
+		return object.Kind + "[" + identity(object.Metadata) + "]"
+	}
+}
+
+func (m *RequestMirror) record(kubectlReq *KubectlRequest, requestID, outcome string, directStatus, shadowStatus int, detail string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.outcomes[outcome]++
+
+	switch outcome {
+	case mirrorMatch, mirrorWriteAccepted, mirrorSkipped:
+		return
+	}
+	m.logger.WithFields(logrus.Fields{
+		"request_id":    requestID,
+		"method":        kubectlReq.Method,
+		"path":          kubectlReq.Path,
+		"outcome":       outcome,
+		"direct_status": directStatus,
+		"shadow_status": shadowStatus,
+	}).Warnf("🪞 Contract path diverged from direct path: %s", detail)
+
+	m.recent = append(m.recent, MirrorDivergence{
+		RequestID:    requestID,
+		Method:       kubectlReq.Method,
+		Path:         kubectlReq.Path,
+		Outcome:      outcome,
+		DirectStatus: directStatus,
+		ShadowStatus: shadowStatus,
+		Detail:       detail,
+		At:           time.Now().UTC(),
+	})
+	if len(m.recent) > mirrorRecentLimit {
+		m.recent = m.recent[len(m.recent)-mirrorRecentLimit:]
+	}
+}
+
+// Stats - 현재 미러링 통계
+func (m *RequestMirror) Stats() MirrorStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	outcomes := make(map[string]uint64, len(mirrorOutcomes))
+	for _, outcome := range mirrorOutcomes {
+		outcomes[outcome] = m.outcomes[outcome]
+	}
+	recent := make([]MirrorDivergence, len(m.recent))
+	copy(recent, m.recent)
+	return MirrorStats{
+		SamplePercent:   m.samplePercent,
+		Outcomes:        outcomes,
+		DirectLatencyMs: m.direct.averageMs(),
+		ShadowLatencyMs: m.shadow.averageMs(),
+		Recent:          recent,
+	}
+}
+
+// handleStats - GET /daas/v1/mirror (GATEWAY_ADMIN_TOKEN)
+func (m *RequestMirror) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		m.gateway.returnK8sError(w, "MethodNotAllowed", "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	if m.adminToken == "" {
+		m.gateway.returnK8sError(w, "Forbidden", "mirror statistics disabled (GATEWAY_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+		m.gateway.returnK8sError(w, "Unauthorized", "invalid admin token", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.Stats())
+}
+
+// writeSummary - /healthz?verbose 한 줄 요약
+func (m *RequestMirror) writeSummary(w io.Writer) {
+	stats := m.Stats()
+	var compared, diverged uint64
+	for outcome, count := range stats.Outcomes {
+		switch outcome {
+		case mirrorSkipped:
+		case mirrorMatch, mirrorWriteAccepted:
+			compared += count
+		default:
+			compared += count
+			diverged += count
+		}
+	}
+	fmt.Fprintf(w, "[+]mirror %d compared, %d diverged (sample %d%%)\n", compared, diverged, stats.SamplePercent)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	apirequest "github.com/k3s-io/daas-apirequest"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// attestationDomain - 마스터 증명 문서 서명 도메인 (nautilus-release/attestation.go와 동일한 형식)
+	attestationDomain = "daas-attestation-v1"
+	// payloadKeyTTL - 확인한 암호화 키 재사용 기간 (마스터 키 교체 후 이 시간 안에 새 키로 바뀜)
+	payloadKeyTTL = 10 * time.Minute
+	// attestationMaxAge - 증명 문서 발급 시각 허용 오차
+	attestationMaxAge = 2 * time.Minute
+)
+
+// attestationDocument - 마스터의 서명된 증명 문서 (/api/v1/attestation)
+type attestationDocument struct {
+	Subject       string `json:"subject"`
+	Measurement   string `json:"measurement"`
+	TEEDevice     string `json:"tee_device"`
+	Nonce         string `json:"nonce"`
+	IssuedAt      int64  `json:"issued_at"`
+	PublicKey     string `json:"public_key"`
+	EncryptionKey string `json:"encryption_key,omitempty"`
+	Signature     string `json:"signature"`
+}
+
+func attestationDigest(doc *attestationDocument) []byte {
+	payload := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%d",
+		attestationDomain, doc.Subject, doc.Measurement, doc.TEEDevice, doc.Nonce, doc.IssuedAt)
+	if doc.EncryptionKey != "" {
+		payload += "\n" + doc.EncryptionKey
+	}
+	digest := sha256.Sum256([]byte(payload))
+	return digest[:]
+}
+
+// trustedMeasurements - GATEWAY_TRUSTED_MEASUREMENTS (쉼표 구분, 비어 있으면 마스터 서명 키만 확인)
+func trustedMeasurements() []string {
+	return splitEntries(os.Getenv("GATEWAY_TRUSTED_MEASUREMENTS"))
+}
+
+/*
+EncryptionKey - 마스터의 본문 암호화 키 (증명 문서로 확인, payloadKeyTTL 동안 재사용)
+
+새 nonce로 /api/v1/attestation을 받아 nonce, 발급 시각, 고정된 마스터 응답 서명 키의 서명,
+허용 측정값을 확인한 뒤 문서에 서명으로 묶인 encryption_key만 사용합니다.
+*/
+func (f *MasterForwarder) EncryptionKey(ctx context.Context, measurements []string) (*ecdh.PublicKey, error) {
+	f.mutex.Lock()
+	if f.payloadKey != nil && time.Since(f.payloadKeyAt) < payloadKeyTTL {
+		key := f.payloadKey
+		f.mutex.Unlock()
+		return key, nil
+	}
+	f.mutex.Unlock()
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, err
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.masterURL+"/api/v1/attestation?nonce="+url.QueryEscape(nonce), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("attestation request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("attestation request failed: HTTP %d", resp.StatusCode)
+	}
+	var body struct {
+		Data attestationDocument `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid attestation document: %v", err)
+	}
+	doc := &body.Data
+
+	switch age := time.Since(time.Unix(doc.IssuedAt, 0)); {
+	case doc.Nonce != nonce:
+		return nil, fmt.Errorf("attestation nonce mismatch")
+	case age > attestationMaxAge || age < -attestationMaxAge:
+		return nil, fmt.Errorf("attestation issued %s away from now", age.Round(time.Second))
+	}
+	signature, err := hex.DecodeString(doc.Signature)
+	if err != nil || !ed25519.Verify(f.masterKey, attestationDigest(doc), signature) {
+		return nil, fmt.Errorf("attestation signature does not match the master key")
+	}
+	if len(measurements) > 0 && !containsString(measurements, doc.Measurement) {
+		return nil, fmt.Errorf("master measurement %s is not trusted", doc.Measurement)
+	}
+	if doc.EncryptionKey == "" {
+		return nil, fmt.Errorf("master does not advertise a payload encryption key (EncryptedPayloads feature gate)")
+	}
+	raw, err := base64.StdEncoding.DecodeString(doc.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %v", err)
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %v", err)
+	}
+
+	f.mutex.Lock()
+	f.payloadKey, f.payloadKeyAt = key, time.Now()
+	f.mutex.Unlock()
+	return key, nil
+}
+
+// EncryptionKey - 테넌트 홈 리전 마스터의 암호화 키와 리전 (쓰기 요청을 실행할 마스터)
+func (r *RegionRouter) EncryptionKey(ctx context.Context, sealToken string) (*ecdh.PublicKey, string, error) {
+	candidates := r.route(sealToken)
+	if len(candidates) == 0 {
+		return nil, "", fmt.Errorf("no regional master available")
+	}
+	master := candidates[0]
+	key, err := master.forwarder.EncryptionKey(ctx, trustedMeasurements())
+	if err != nil {
+		return nil, master.region, fmt.Errorf("region %s: %v", master.region, err)
+	}
+	return key, master.region, nil
+}
+
+// encryptPayload - 제출할 본문을 마스터 엔클레이브 키로 암호화 (키를 확인할 수 없으면 평문으로 보내지 않고 실패)
+func (g *ContractAPIGateway) encryptPayload(ctx context.Context, submission *apirequest.Request) error {
+	if g.master == nil {
+		return fmt.Errorf("encrypted payloads need a master (NAUTILUS_MASTER_URL, NAUTILUS_MASTERS or GATEWAY_MASTER_REGISTRY)")
+	}
+	key, region, err := g.master.EncryptionKey(ctx, submission.SealToken)
+	if err != nil {
+		return err
+	}
+	if err := submission.EncryptPayload(key); err != nil {
+		return err
+	}
+	g.logger.WithFields(logrus.Fields{
+		"request_id": submission.RequestID,
+		"region":     region,
+		"key_id":     apirequest.PayloadKeyID(key),
+	}).Debug("🔒 Payload encrypted to master enclave key")
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// completeScript - 대기 중인 요청이 있을 때만 응답을 넣고 대기 표시 삭제 (두 복제본이 같은 콜백을 받아도 한 번만 기록)
+const completeScript = `if redis.call('DEL', KEYS[1]) == 1 then
+  redis.call('RPUSH', KEYS[2], ARGV[1])
+  redis.call('PEXPIRE', KEYS[2], ARGV[2])
+  return 1
+end
+return 0`
+
+// pendingRecord - Redis에 보관하는 대기 요청 정보 (응답 대기 채널은 복제본 로컬)
+type pendingRecord struct {
+	RequestID string    `json:"request_id"`
+	StartTime time.Time `json:"start_time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Requester string    `json:"requester,omitempty"`
+}
+
+/*
+redisResponseStore - 여러 게이트웨이 복제본이 공유하는 Redis 저장소
+
+<prefix>pending:<id>는 Register가 SET NX PX로 만들고, 콜백을 받은 복제본은 스크립트로 이를 지우며
+<prefix>response:<id> 리스트에 응답을 넣습니다. kubectl 연결을 가진 복제본은 BLPOP으로 기다리므로 sticky 세션이 필요 없습니다.
+GATEWAY_RESPONSE_STORE_PREFIX(기본 k3s-daas:gateway:)로 같은 Redis를 쓰는 배포를 구분합니다.
+*/
+type redisResponseStore struct {
+	address  string
+	useTLS   bool
+	username string
+	password string
+	database int
+	prefix   string
+
+	mutex sync.Mutex
+	idle  []*redisConn
+}
+
+func newRedisResponseStore(target string) (*redisResponseStore, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GATEWAY_RESPONSE_STORE: %v", err)
+	}
+	store := &redisResponseStore{
+		address: parsed.Host,
+		useTLS:  parsed.Scheme == "rediss",
+		prefix:  getEnvOrDefault("GATEWAY_RESPONSE_STORE_PREFIX", "k3s-daas:gateway:"),
+	}
+	if parsed.Port() == "" {
+		store.address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		store.username = parsed.User.Username()
+		store.password, _ = parsed.User.Password()
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if store.database, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return store, nil
+}
+
+func getEnvOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (s *redisResponseStore) Description() string {
+	return fmt.Sprintf("redis %s/%d (shared)", s.address, s.database)
+}
+
+func (s *redisResponseStore) Register(ctx context.Context, pending *PendingResponse) error {
+	record, err := json.Marshal(pendingRecord{
+		RequestID: pending.RequestID,
+		StartTime: pending.StartTime,
+		Method:    pending.Method,
+		Path:      pending.Path,
+		Requester: pending.Requester,
+	})
+	if err != nil {
+		return err
+	}
+	reply, err := s.do(ctx, "SET", s.prefix+"pending:"+pending.RequestID, string(record),
+		"NX", "PX", strconv.FormatInt(responseTTL.Milliseconds(), 10))
+	if err != nil {
+		return err
+	}
+	if reply == nil {
+		return errRequestInFlight
+	}
+	return nil
+}
+
+func (s *redisResponseStore) Complete(ctx context.Context, requestID string, response *K8sResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	reply, err := s.do(ctx, "EVAL", completeScript, "2",
+		s.prefix+"pending:"+requestID, s.prefix+"response:"+requestID,
+		string(data), strconv.FormatInt(responseTTL.Milliseconds(), 10))
+	if err != nil {
+		return err
+	}
+	if stored, _ := reply.(int64); stored != 1 {
+		return errUnknownRequest
+	}
+	return nil
+}
+
+// Wait - BLPOP으로 응답 대기 (연결을 점유하므로 ctx 마감까지로 제한)
+func (s *redisResponseStore) Wait(ctx context.Context, requestID string) (*K8sResponse, error) {
+	timeout := responseTTL
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	// BLPOP 타임아웃은 초 단위 소수 (0은 무한 대기라 최소값 보장)
+	seconds := strconv.FormatFloat(max(timeout.Seconds(), 0.01), 'f', 3, 64)
+	reply, err := s.do(ctx, "BLPOP", s.prefix+"response:"+requestID, seconds)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if reply == nil {
+		return nil, context.DeadlineExceeded
+	}
+	pair, ok := reply.([]interface{})
+	if !ok || len(pair) != 2 {
+		return nil, fmt.Errorf("unexpected BLPOP reply %T", reply)
+	}
+	data, _ := pair[1].([]byte)
+	var response K8sResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid stored response: %v", err)
+	}
+	return &response, nil
+}
+
+func (s *redisResponseStore) Release(ctx context.Context, requestID string) {
+	// 요청 ctx가 이미 끝났을 수 있으므로 별도 시간 제한으로 정리
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+	s.do(ctx, "DEL", s.prefix+"pending:"+requestID, s.prefix+"response:"+requestID)
+}
+
+// Expire - 키마다 PX/PEXPIRE가 걸려 있어 Redis가 만료 처리
+func (s *redisResponseStore) Expire(maxAge time.Duration) {}
+
+func (s *redisResponseStore) Ping(ctx context.Context) error {
+	_, err := s.do(ctx, "PING")
+	return err
+}
+
+// do - 풀에서 연결을 꺼내 명령 실행 (오류가 난 연결은 버림)
+func (s *redisResponseStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		// BLPOP이 서버 타임아웃으로 끝날 여유
+		conn.SetDeadline(deadline.Add(time.Second))
+	} else {
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+	}
+	reply, err := conn.command(args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		conn.Close()
+		return nil, err
+	}
+	s.put(conn)
+	return reply, err
+}
+
+func (s *redisResponseStore) get(ctx context.Context) (*redisConn, error) {
+	s.mutex.Lock()
+	if n := len(s.idle); n > 0 {
+		conn := s.idle[n-1]
+		s.idle = s.idle[:n-1]
+		s.mutex.Unlock()
+		return conn, nil
+	}
+	s.mutex.Unlock()
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var netConn net.Conn
+	var err error
+	if s.useTLS {
+		host, _, _ := net.SplitHostPort(s.address)
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", s.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis dial %s: %v", s.address, err)
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if s.password != "" {
+		args := []string{"AUTH", s.password}
+		if s.username != "" {
+			args = []string{"AUTH", s.username, s.password}
+		}
+		if _, err := conn.command(args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis auth: %v", err)
+		}
+	}
+	if s.database != 0 {
+		if _, err := conn.command("SELECT", strconv.Itoa(s.database)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis select: %v", err)
+		}
+	}
+	return conn, nil
+}
+
+func (s *redisResponseStore) put(conn *redisConn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.idle) >= 16 {
+		conn.Close()
+		return
+	}
+	s.idle = append(s.idle, conn)
+}
+
+// redisError - 서버가 돌려준 -ERR 응답 (연결은 계속 사용 가능)
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisConn - RESP2 연결 (명령은 bulk string 배열로 전송)
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.Write([]byte(buf.String())); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply - 단순 문자열은 string, 정수는 int64, bulk는 []byte, 배열은 []interface{}, nil은 nil
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty RESP reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected RESP reply %q", line)
+	}
+}
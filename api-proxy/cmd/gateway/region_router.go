@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k3s-io/daas-signing"
+	sui "github.com/k3s-io/daas-sui"
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+RegionRouter - 리전별 Nautilus 마스터 중 테넌트의 홈 리전으로 읽기 요청을 전달 (장애 시 다른 리전으로 전환)
+
+마스터 목록은 온체인 MasterRegistry(GATEWAY_MASTER_REGISTRY)와 정적 설정에서 가져오고,
+각 마스터의 서명된 /api/v1/federation/state로 상태와 테넌트(Seal 토큰 SHA-256)→홈 리전 매핑을 갱신합니다.
+홈 리전을 모르는 테넌트는 rendezvous 해시로 리전을 고정하므로 마스터 구성이 바뀌지 않는 한 같은 리전으로 갑니다.
+
+	NAUTILUS_MASTERS               정적 마스터 "region=url@pubkey,..."
+	NAUTILUS_MASTER_URL            단일 마스터 주소 (NAUTILUS_MASTER_PUBLIC_KEY, 리전은 NAUTILUS_MASTER_REGION, 기본 default)
+	GATEWAY_MASTER_REGISTRY        MasterRegistry 공유 객체 ID
+	GATEWAY_REGION_SYNC_INTERVAL   상태 갱신 주기 (초, 기본 30)
+*/
+type RegionRouter struct {
+	logger     *logrus.Logger
+	signingKey ed25519.PrivateKey
+	chain      *sui.SuiClient
+	registryID string
+	interval   time.Duration
+	httpClient *http.Client
+
+	mutex   sync.RWMutex
+	masters map[string]*regionMaster
+	tenants map[string]string // sha256(Seal 토큰) → 홈 리전
+}
+
+// regionMaster - 리전 마스터 전달기와 상태
+type regionMaster struct {
+	region    string
+	forwarder *MasterForwarder
+	static    bool
+	healthy   bool
+	failures  int
+	lastError string
+	version   string // 마지막으로 기록한 마스터 버전
+}
+
+// failoverStatus - 다른 리전으로 재시도할 마스터 응답 코드
+func failoverStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// NewRegionRouterFromEnv - 환경변수 기반 생성 (마스터 구성이 없으면 nil, 서명 키 없이 전달하는 구성은 허용하지 않음)
+func NewRegionRouterFromEnv(logger *logrus.Logger, chain *sui.SuiClient) (*RegionRouter, error) {
+	static := splitEntries(os.Getenv("NAUTILUS_MASTERS"))
+	if masterURL := os.Getenv("NAUTILUS_MASTER_URL"); masterURL != "" {
+		region := os.Getenv("NAUTILUS_MASTER_REGION")
+		if region == "" {
+			region = "default"
+		}
+		static = append(static, region+"="+masterURL+"@"+os.Getenv("NAUTILUS_MASTER_PUBLIC_KEY"))
+	}
+	registryID := os.Getenv("GATEWAY_MASTER_REGISTRY")
+	if len(static) == 0 && registryID == "" {
+		return nil, nil
+	}
+
+	signingKey, err := signing.ParsePrivateKey(os.Getenv("GATEWAY_SIGNING_KEY"))
+	if err != nil {
+		return nil, fmt.Errorf("GATEWAY_SIGNING_KEY: %v", err)
+	}
+
+	interval := 30 * time.Second
+	if value := os.Getenv("GATEWAY_REGION_SYNC_INTERVAL"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("GATEWAY_REGION_SYNC_INTERVAL: invalid value %q", value)
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	router := newRegionRouter(logger, signingKey)
+	router.chain = chain
+	router.registryID = registryID
+	router.interval = interval
+	for _, entry := range static {
+		region, rest, ok := strings.Cut(entry, "=")
+		at := strings.LastIndex(rest, "@")
+		if !ok || region == "" || at <= 0 {
+			return nil, fmt.Errorf("invalid master %q (expected region=url@pubkey)", entry)
+		}
+		masterKey, err := signing.ParsePublicKey(rest[at+1:])
+		if err != nil {
+			return nil, fmt.Errorf("master %s public key: %v", region, err)
+		}
+		router.add(region, newMasterForwarder(rest[:at], signingKey, masterKey), true)
+	}
+	return router, nil
+}
+
+// newRegionRouter - 빈 라우터 (모든 리전은 상태 확인 전까지 정상으로 간주)
+func newRegionRouter(logger *logrus.Logger, signingKey ed25519.PrivateKey) *RegionRouter {
+	return &RegionRouter{
+		logger:     logger,
+		signingKey: signingKey,
+		interval:   30 * time.Second,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		masters:    make(map[string]*regionMaster),
+		tenants:    make(map[string]string),
+	}
+}
+
+func (r *RegionRouter) add(region string, forwarder *MasterForwarder, static bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.masters[region] = &regionMaster{region: region, forwarder: forwarder, static: static, healthy: true}
+}
+
+func splitEntries(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// tenantDigest - 마스터가 공개하는 테넌트 식별자 형식 (Seal 토큰 SHA-256)
+func tenantDigest(sealToken string) string {
+	digest := sha256.Sum256([]byte(sealToken))
+	return hex.EncodeToString(digest[:])
+}
+
+// route - 시도 순서: 홈 리전 → 나머지 정상 리전 → 비정상 리전 (같은 그룹 안에서는 rendezvous 해시 순)
+func (r *RegionRouter) route(sealToken string) []*regionMaster {
+	digest := tenantDigest(sealToken)
+
+	r.mutex.RLock()
+	home := r.tenants[digest]
+	candidates := make([]*regionMaster, 0, len(r.masters))
+	for _, master := range r.masters {
+		candidates = append(candidates, master)
+	}
+	rank := func(master *regionMaster) int {
+		switch {
+		case master.healthy && master.region == home:
+			return 0
+		case master.healthy:
+			return 1
+		default:
+			return 2
+		}
+	}
+	ranks := make(map[*regionMaster]int, len(candidates))
+	for _, master := range candidates {
+		ranks[master] = rank(master)
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if ranks[candidates[i]] != ranks[candidates[j]] {
+			return ranks[candidates[i]] < ranks[candidates[j]]
+		}
+		return rendezvousScore(digest, candidates[i].region) > rendezvousScore(digest, candidates[j].region)
+	})
+	return candidates
+}
+
+func rendezvousScore(digest, region string) string {
+	score := sha256.Sum256([]byte(digest + "/" + region))
+	return hex.EncodeToString(score[:])
+}
+
+// Forward - 홈 리전 마스터로 전달, 연결/서명 실패나 502/503/504면 다음 리전으로 재시도 (RegionFailover 게이트)
+func (r *RegionRouter) Forward(ctx context.Context, kubectlReq *KubectlRequest, rawQuery string) (*K8sResponse, error) {
+	candidates := r.route(kubectlReq.SealToken)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no regional master available")
+	}
+	if !features.Enabled(featureRegionFailover) {
+		candidates = candidates[:1]
+	}
+
+	var lastResponse *K8sResponse
+	var lastErr error
+	for i, master := range candidates {
+		response, err := master.forwarder.Forward(ctx, kubectlReq, rawQuery)
+		if err != nil && ctx.Err() != nil {
+			// 클라이언트 마감 초과는 리전 장애가 아니므로 상태를 바꾸지 않고 중단
+			return nil, ctx.Err()
+		}
+		r.noteVersion(master)
+		if err == nil && !failoverStatus(response.StatusCode) {
+			r.markResult(master, nil)
+			response.Headers["X-Daas-Region"] = master.region
+			if i > 0 {
+				r.logger.WithFields(logrus.Fields{"region": master.region, "skipped": i}).Warn("🌍 Request served by failover region")
+			}
+			return response, nil
+		}
+
+		if err == nil {
+			lastResponse = response
+			err = fmt.Errorf("HTTP %d", response.StatusCode)
+		}
+		lastErr = fmt.Errorf("region %s: %v", master.region, err)
+		r.markResult(master, lastErr)
+	}
+
+	// 모든 리전이 실패하면 마지막 마스터 응답(예: 503 Status)을 그대로 전달
+	if lastResponse != nil {
+		return lastResponse, nil
+	}
+	return nil, lastErr
+}
+
+// Stream - watch는 홈 리전 마스터로만 전달 (응답 헤더를 보낸 뒤에는 다른 리전으로 넘길 수 없음)
+func (r *RegionRouter) Stream(ctx context.Context, w http.ResponseWriter, kubectlReq *KubectlRequest, rawQuery, accept string) (int, error) {
+	candidates := r.route(kubectlReq.SealToken)
+	if len(candidates) == 0 {
+		return 0, fmt.Errorf("no regional master available")
+	}
+	master := candidates[0]
+	w.Header().Set("X-Daas-Region", master.region)
+
+	status, err := master.forwarder.Stream(ctx, w, kubectlReq, rawQuery, accept)
+	r.noteVersion(master)
+	switch {
+	case ctx.Err() != nil:
+		// kubectl이 watch를 끝낸 것은 리전 장애가 아님
+	case status == 0:
+		r.markResult(master, fmt.Errorf("region %s: %v", master.region, err))
+	case err == nil:
+		r.markResult(master, nil)
+	}
+	return status, err
+}
+
+func (r *RegionRouter) markResult(master *regionMaster, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err == nil {
+		if !master.healthy {
+			r.logger.WithField("region", master.region).Info("🌍 Region master recovered")
+		}
+		master.healthy, master.failures, master.lastError = true, 0, ""
+		return
+	}
+	if master.healthy {
+		r.logger.WithError(err).WithField("region", master.region).Warn("🌍 Region master marked unhealthy")
+	}
+	master.healthy = false
+	master.failures++
+	master.lastError = err.Error()
+}
+
+// Start - 레지스트리와 마스터 상태를 주기적으로 갱신
+func (r *RegionRouter) Start(ctx context.Context) {
+	r.refresh(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// refresh - 레지스트리 마스터 반영 후 각 마스터의 서명 상태로 정상 여부와 테넌트 홈 리전 갱신
+func (r *RegionRouter) refresh(ctx context.Context) {
+	if r.registryID != "" && r.chain != nil {
+		if err := r.refreshRegistry(ctx); err != nil {
+			r.logger.WithError(err).Warn("⚠️ Failed to read master registry")
+		}
+	}
+
+	r.mutex.RLock()
+	masters := make([]*regionMaster, 0, len(r.masters))
+	for _, master := range r.masters {
+		masters = append(masters, master)
+	}
+	r.mutex.RUnlock()
+
+	tenants := make(map[string]string)
+	for _, master := range masters {
+		digests, err := r.fetchTenants(ctx, master)
+		r.noteVersion(master)
+		r.markResult(master, err)
+		for _, digest := range digests {
+			tenants[digest] = master.region
+		}
+	}
+
+	// 상태를 가져오지 못한 리전의 기존 매핑은 유지 (홈 리전이 복구되면 다시 그쪽으로)
+	r.mutex.Lock()
+	for digest, region := range r.tenants {
+		if master, ok := r.masters[region]; ok && !master.healthy {
+			if _, remapped := tenants[digest]; !remapped {
+				tenants[digest] = region
+			}
+		}
+	}
+	r.tenants = tenants
+	r.mutex.Unlock()
+}
+
+// refreshRegistry - 활성 마스터 추가/교체, 레지스트리에서 빠진 마스터 제거 (정적 구성은 유지)
+func (r *RegionRouter) refreshRegistry(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	masters, err := r.chain.RegionalMasters(ctx, r.registryID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	active := make(map[string]sui.RegionalMaster)
+	for _, master := range masters {
+		if master.Active(now) {
+			active[master.Region] = master
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for region, master := range r.masters {
+		if _, ok := active[region]; !ok && !master.static {
+			delete(r.masters, region)
+		}
+	}
+	for region, entry := range active {
+		masterKey, err := signing.ParsePublicKey(entry.PublicKey)
+		if err != nil {
+			r.logger.WithError(err).WithField("region", region).Warn("⚠️ Ignoring regional master")
+			continue
+		}
+		if existing, ok := r.masters[region]; ok &&
+			existing.forwarder.masterURL == strings.TrimSuffix(entry.Endpoint, "/") && existing.forwarder.masterKey.Equal(masterKey) {
+			continue
+		}
+		r.masters[region] = &regionMaster{
+			region:    region,
+			forwarder: newMasterForwarder(entry.Endpoint, r.signingKey, masterKey),
+			healthy:   true,
+		}
+	}
+	return nil
+}
+
+// fetchTenants - 마스터의 서명된 연합 상태에서 테넌트 목록 조회
+func (r *RegionRouter) fetchTenants(ctx context.Context, master *regionMaster) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, master.forwarder.masterURL+"/api/v1/federation/state", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(version.Header, gatewayVersion.HeaderValue())
+	nonce, err := signing.SignRequest(r.signingKey, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("federation state HTTP %d", resp.StatusCode)
+	}
+	if err := signing.VerifyResponse(master.forwarder.masterKey, resp, nonce, body); err != nil {
+		return nil, fmt.Errorf("federation state rejected: %v", err)
+	}
+	if err := master.forwarder.observeVersion(resp.Header.Get(version.Header)); err != nil {
+		return nil, err
+	}
+
+	var state struct {
+		Region  string   `json:"region"`
+		Tenants []string `json:"tenants"`
+	}
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, fmt.Errorf("invalid federation state: %v", err)
+	}
+	if state.Region != master.region {
+		return nil, fmt.Errorf("master reported region %q", state.Region)
+	}
+	return state.Tenants, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"api-proxy/pkg/codec"
+
+	"github.com/sirupsen/logrus"
+)
+
+// responseTTL - 응답을 기다리는 요청의 최대 보관 시간 (클라이언트 마감보다 길게)
+const responseTTL = 5 * time.Minute
+
+var (
+	errUnknownRequest  = errors.New("no pending request with this ID (expired or already completed)")
+	errRequestInFlight = errors.New("a request with this ID is already pending")
+)
+
+/*
+ResponseStore - 온체인에 제출한 요청과 실행 결과의 대응 상태
+
+kubectl 연결을 가진 복제본이 Register 후 Wait로 결과를 기다리고, 실행자의 콜백(POST /daas/v1/responses/<id>)은
+로드 밸런서가 어느 복제본으로 보내든 Complete로 같은 저장소에 기록됩니다.
+GATEWAY_RESPONSE_STORE가 비어 있으면 프로세스 메모리(복제본 하나), redis:// 또는 rediss://이면 Redis를 공유합니다.
+*/
+type ResponseStore interface {
+	Register(ctx context.Context, pending *PendingResponse) error
+	Complete(ctx context.Context, requestID string, response *K8sResponse) error
+	Wait(ctx context.Context, requestID string) (*K8sResponse, error)
+	Release(ctx context.Context, requestID string)
+	Expire(maxAge time.Duration)
+	Ping(ctx context.Context) error
+	Description() string
+}
+
+// NewResponseStoreFromEnv - GATEWAY_RESPONSE_STORE로 저장소 선택
+func NewResponseStoreFromEnv() (ResponseStore, error) {
+	target := os.Getenv("GATEWAY_RESPONSE_STORE")
+	switch {
+	case target == "" || target == "memory":
+		return newMemoryResponseStore(), nil
+	case strings.HasPrefix(target, "redis://"), strings.HasPrefix(target, "rediss://"):
+		return newRedisResponseStore(target)
+	default:
+		return nil, fmt.Errorf("GATEWAY_RESPONSE_STORE must be memory, redis:// or rediss://")
+	}
+}
+
+// memoryResponseStore - 프로세스 메모리 저장소 (콜백이 요청을 받은 복제본으로 와야 함)
+type memoryResponseStore struct {
+	mutex   sync.Mutex
+	pending map[string]*PendingResponse
+}
+
+func newMemoryResponseStore() *memoryResponseStore {
+	return &memoryResponseStore{pending: make(map[string]*PendingResponse)}
+}
+
+func (m *memoryResponseStore) Register(ctx context.Context, pending *PendingResponse) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, exists := m.pending[pending.RequestID]; exists {
+		return errRequestInFlight
+	}
+	pending.WaitChannel = make(chan *K8sResponse, 1)
+	m.pending[pending.RequestID] = pending
+	return nil
+}
+
+func (m *memoryResponseStore) Complete(ctx context.Context, requestID string, response *K8sResponse) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	pending, ok := m.pending[requestID]
+	if !ok || pending.Completed {
+		return errUnknownRequest
+	}
+	pending.Completed = true
+	pending.Response = response
+	pending.WaitChannel <- response
+	return nil
+}
+
+func (m *memoryResponseStore) Wait(ctx context.Context, requestID string) (*K8sResponse, error) {
+	m.mutex.Lock()
+	pending, ok := m.pending[requestID]
+	m.mutex.Unlock()
+	if !ok {
+		return nil, errUnknownRequest
+	}
+	select {
+	case response := <-pending.WaitChannel:
+		return response, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *memoryResponseStore) Release(ctx context.Context, requestID string) {
+	m.mutex.Lock()
+	delete(m.pending, requestID)
+	m.mutex.Unlock()
+}
+
+func (m *memoryResponseStore) Expire(maxAge time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, pending := range m.pending {
+		if time.Since(pending.StartTime) > maxAge {
+			delete(m.pending, id)
+		}
+	}
+}
+
+func (m *memoryResponseStore) Ping(ctx context.Context) error { return nil }
+
+func (m *memoryResponseStore) Description() string { return "memory (single replica)" }
+
+// handleResponseCallback - POST /daas/v1/responses/<request_id> (실행자 결과 수신, GATEWAY_CALLBACK_TOKEN 인증)
+// 본문은 리스너의 실행 결과 형식 {"status_code", "headers", "body", "chain_lag_ms"}
+func (g *ContractAPIGateway) handleResponseCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		g.returnK8sError(w, "MethodNotAllowed", "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(g.callbackToken)) != 1 {
+		g.returnK8sError(w, "Unauthorized", "invalid callback token", http.StatusUnauthorized)
+		return
+	}
+	requestID := strings.TrimPrefix(r.URL.Path, "/daas/v1/responses/")
+	if requestID == "" || strings.Contains(requestID, "/") {
+		g.returnK8sError(w, "NotFound", "request ID required", http.StatusNotFound)
+		return
+	}
+
+	var result struct {
+		StatusCode int               `json:"status_code"`
+		Headers    map[string]string `json:"headers"`
+		Body       json.RawMessage   `json:"body"`
+		ChainLagMs int64             `json:"chain_lag_ms"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 16<<20)).Decode(&result); err != nil || result.StatusCode == 0 {
+		g.returnK8sError(w, "BadRequest", "invalid callback body", http.StatusBadRequest)
+		return
+	}
+	response := &K8sResponse{
+		StatusCode:  result.StatusCode,
+		Headers:     result.Headers,
+		Body:        codec.NewJSONObject(result.Body),
+		ProcessedAt: time.Now(),
+		ChainLag:    time.Duration(result.ChainLagMs) * time.Millisecond,
+	}
+
+	err := g.responses.Complete(r.Context(), requestID, response)
+	switch {
+	case errors.Is(err, errUnknownRequest):
+		g.returnK8sError(w, "NotFound", err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		g.logger.WithError(err).WithField("request_id", requestID).Error("❌ Failed to record callback response")
+		g.returnK8sError(w, "ServiceUnavailable", err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	g.logger.WithFields(logrus.Fields{
+		"request_id": requestID,
+		"status":     result.StatusCode,
+	}).Info("📬 Response callback recorded")
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,84 @@
+// Version Skew - 마스터와 빌드 버전 교환, 지원 범위를 벗어난 마스터 경고 또는 전달 중단
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+// Gateway는 마스터의 클라이언트: 마스터보다 GATEWAY_VERSION_MAX_MINOR_SKEW(기본 2) 마이너 버전까지 낮을 수 있고 높을 수는 없음
+// GATEWAY_VERSION_SKEW_POLICY=warn(기본)|refuse|off, refuse면 해당 마스터로 전달하지 않고 다른 리전으로 재시도
+var (
+	gatewayVersion = version.Get("gateway")
+	versionPolicy  = version.Policy{Mode: version.ModeWarn, MaxMinorSkew: version.DefaultMaxMinorSkew}
+)
+
+// observeVersion - 마스터 응답의 X-Daas-Version 기록 (refuse 정책에서 지원 범위를 벗어나면 오류)
+func (f *MasterForwarder) observeVersion(header string) error {
+	_, masterVersion, ok := version.ParseHeader(header)
+	if !ok {
+		return nil
+	}
+	f.mutex.Lock()
+	f.masterVersion = masterVersion
+	f.mutex.Unlock()
+
+	if skew := versionPolicy.Check(masterVersion, gatewayVersion.Version); versionPolicy.Refuses(skew) {
+		return fmt.Errorf("master version refused: %v", skew)
+	}
+	return nil
+}
+
+// MasterVersion - 마지막으로 확인한 마스터 버전 (아직 없으면 빈 문자열)
+func (f *MasterForwarder) MasterVersion() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.masterVersion
+}
+
+// noteVersion - 리전 마스터 버전이 바뀌면 한 번 로그
+func (r *RegionRouter) noteVersion(master *regionMaster) {
+	masterVersion := master.forwarder.MasterVersion()
+
+	r.mutex.Lock()
+	changed := masterVersion != "" && masterVersion != master.version
+	master.version = masterVersion
+	r.mutex.Unlock()
+	if !changed {
+		return
+	}
+
+	fields := logrus.Fields{"region": master.region, "master_version": masterVersion, "gateway_version": gatewayVersion.Version}
+	if skew := versionPolicy.Check(masterVersion, gatewayVersion.Version); skew != nil {
+		r.logger.WithFields(fields).Warnf("⚠️ Regional master is outside the supported version skew (policy %s): %v", versionPolicy.Mode, skew)
+		return
+	}
+	r.logger.WithFields(fields).Info("🔖 Regional master version")
+}
+
+// writeVersions - /healthz?verbose 출력 (리전별 마스터 버전)
+func (r *RegionRouter) writeVersions(w io.Writer) {
+	r.mutex.RLock()
+	lines := make([]string, 0, len(r.masters))
+	for _, master := range r.masters {
+		masterVersion := master.version
+		if masterVersion == "" {
+			masterVersion = "unknown"
+		}
+		mark := "+"
+		if versionPolicy.Check(masterVersion, gatewayVersion.Version) != nil {
+			mark = "-"
+		}
+		lines = append(lines, fmt.Sprintf("[%s]master %s %s\n", mark, master.region, masterVersion))
+	}
+	r.mutex.RUnlock()
+
+	sort.Strings(lines)
+	for _, line := range lines {
+		io.WriteString(w, line)
+	}
+}
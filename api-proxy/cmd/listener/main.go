@@ -3,13 +3,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
-	"github.com/go-resty/resty/v2"
+	"api-proxy/pkg/admission"
+
 	"github.com/gorilla/websocket"
+	apirequest "github.com/k3s-io/daas-apirequest"
+	httpserver "github.com/k3s-io/daas-httpserver"
+	service "github.com/k3s-io/daas-service"
+	sui "github.com/k3s-io/daas-sui"
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -21,11 +31,14 @@ type NautilusEventListener struct {
 	contractAddress string
 	privateKeyHex   string
 	k8sClient       kubernetes.Interface
-	restClient      *resty.Client
+	chain           *sui.SuiClient // 공용 Sui 클라이언트 (조회 전용)
 	logger          *logrus.Logger
 	wsConn          *websocket.Conn
 	eventChannel    chan ContractEvent
 	stopChannel     chan bool
+	callbackURL     string // Gateway 결과 콜백 주소 (GATEWAY_CALLBACK_URL, 로드 밸런서 뒤 어느 복제본이든 가능)
+	callbackToken   string
+	callbackClient  *http.Client
 }
 
 // ContractEvent - Move Contract에서 발생하는 이벤트
@@ -39,19 +52,8 @@ type ContractEvent struct {
 	Timestamp time.Time `json:"timestampMs"`
 }
 
-// EventData - K8s API 요청 이벤트 데이터
-type EventData struct {
-	RequestID    string `json:"request_id"`
-	Method       string `json:"method"`
-	Path         string `json:"path"`
-	Namespace    string `json:"namespace"`
-	ResourceType string `json:"resource_type"`
-	Payload      []int  `json:"payload"` // vector<u8> from Move
-	SealToken    string `json:"seal_token"`
-	Requester    string `json:"requester"`
-	Priority     int    `json:"priority"`
-	Timestamp    uint64 `json:"timestamp"`
-}
+// EventData - K8s API 요청 이벤트 데이터 (Gateway/마스터와 공용 스키마, Move 이벤트 필드 그대로)
+type EventData = apirequest.Request
 
 // K8sExecutionResult - K8s 실행 결과
 type K8sExecutionResult struct {
@@ -60,6 +62,7 @@ type K8sExecutionResult struct {
 	Body       json.RawMessage   `json:"body"`
 	Success    bool              `json:"success"`
 	Error      string            `json:"error,omitempty"`
+	ChainLagMs int64             `json:"chain_lag_ms,omitempty"` // 이벤트 체크포인트 시각부터 수신까지 (Gateway의 X-DaaS-Chain-Lag)
 }
 
 func NewNautilusEventListener(suiRPCURL, contractAddr, privateKey string) *NautilusEventListener {
@@ -79,18 +82,22 @@ func NewNautilusEventListener(suiRPCURL, contractAddr, privateKey string) *Nauti
 		contractAddress: contractAddr,
 		privateKeyHex:   privateKey,
 		k8sClient:       k8sClient,
-		restClient:      resty.New().SetTimeout(30 * time.Second),
+		chain:           sui.NewReadOnlyClient(suiRPCURL, contractAddr),
 		logger:          logrus.New(),
 		eventChannel:    make(chan ContractEvent, 100),
 		stopChannel:     make(chan bool),
+		callbackURL:     strings.TrimSuffix(os.Getenv("GATEWAY_CALLBACK_URL"), "/"),
+		callbackToken:   os.Getenv("GATEWAY_CALLBACK_TOKEN"),
+		callbackClient:  &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
-func (n *NautilusEventListener) Start() error {
+// Start - 리스너 실행 (ctx 취소 또는 stopChannel 수신 시 종료)
+func (n *NautilusEventListener) Start(ctx context.Context) error {
 	n.logger.Info("🌊 Nautilus Event Listener starting...")
 
 	// 1. 헬스체크 서버 시작
-	go n.startHealthServer()
+	go n.startHealthServer(ctx)
 
 	// 2. Mock 이벤트 처리 모드
 	go n.startMockEventProcessor()
@@ -99,10 +106,11 @@ func (n *NautilusEventListener) Start() error {
 
 	// 메인 루프
 	select {
+	case <-ctx.Done():
 	case <-n.stopChannel:
-		n.logger.Info("🛑 Nautilus Event Listener stopping...")
-		return nil
 	}
+	n.logger.Info("🛑 Nautilus Event Listener stopping...")
+	return nil
 }
 
 // startMockEventProcessor - 테스트용 Mock 이벤트 처리기
@@ -120,16 +128,15 @@ func (n *NautilusEventListener) startMockEventProcessor() {
 			Module:    "k8s_gateway",
 			Sender:    "0xtest",
 			EventData: EventData{
-				RequestID:    fmt.Sprintf("mock_%d", time.Now().Unix()),
-				Method:       "GET",
-				Path:         "/api/v1/pods",
-				Namespace:    "default",
-				ResourceType: "pods",
-				Payload:      []int{},
-				SealToken:    "mock_token",
-				Requester:    "test_user",
-				Priority:     1,
-				Timestamp:    uint64(time.Now().Unix()),
+				RequestID:      fmt.Sprintf("mock_%d", time.Now().Unix()),
+				Method:         "GET",
+				Resource:       "pods",
+				Namespace:      "default",
+				SealToken:      "mock_seal_token_for_listener_testing",
+				Requester:      "test_user",
+				Priority:       1,
+				AssignedWorker: "mock-worker",
+				Timestamp:      uint64(time.Now().UnixMilli()),
 			},
 			TxDigest:  "mock_digest",
 			Timestamp: time.Now(),
@@ -142,12 +149,13 @@ func (n *NautilusEventListener) startMockEventProcessor() {
 // handleK8sAPIRequest - K8s API 요청 이벤트 처리
 func (n *NautilusEventListener) handleK8sAPIRequest(event ContractEvent) {
 	requestID := event.EventData.RequestID
+	receivedAt := time.Now()
 
 	n.logger.WithFields(logrus.Fields{
-		"request_id":    requestID,
-		"method":        event.EventData.Method,
-		"path":          event.EventData.Path,
-		"resource_type": event.EventData.ResourceType,
+		"request_id": requestID,
+		"method":     event.EventData.Method,
+		"resource":   event.EventData.Resource,
+		"namespace":  event.EventData.Namespace,
 	}).Info("🔧 Processing K8s API request")
 
 	// 1. 이벤트 검증
@@ -156,35 +164,72 @@ func (n *NautilusEventListener) handleK8sAPIRequest(event ContractEvent) {
 		return
 	}
 
-	// 2. K8s API 실행 (Mock 모드)
+	// 마스터 엔클레이브 키로 암호화된 본문은 엔클레이브 밖의 이 실행자가 열 수 없음
+	if event.EventData.PayloadEncrypted() {
+		n.storeErrorResponse(requestID, "payload is encrypted to the master enclave and cannot be executed by this listener", 501)
+		return
+	}
+
+	// 클라이언트가 이미 포기한 요청은 실행하지 않고 만료 응답만 기록
+	if deadline := event.EventData.DeadlineMs; deadline > 0 && time.Now().UnixMilli() >= int64(deadline) {
+		n.storeErrorResponse(requestID, "request deadline exceeded before execution", 504)
+		return
+	}
+
+	// 2. K8s API 실행 (Mock 모드, 본문은 Gateway가 JSON으로 정규화해 제출)
 	result := n.executeK8sOperation(event.EventData)
+	if !event.Timestamp.IsZero() && receivedAt.After(event.Timestamp) {
+		result.ChainLagMs = receivedAt.Sub(event.Timestamp).Milliseconds()
+	}
 
-	// 3. 결과 로깅
+	// 3. 결과 로깅 및 Gateway로 전달
 	n.logger.WithFields(logrus.Fields{
 		"request_id":  requestID,
 		"status_code": result.StatusCode,
 		"success":     result.Success,
 	}).Info("✅ K8s operation completed")
+	n.deliverResult(requestID, result)
 }
 
-// validateEvent - 이벤트 검증
-func (n *NautilusEventListener) validateEvent(event ContractEvent) bool {
-	data := event.EventData
-
-	if data.RequestID == "" || data.Method == "" || data.Path == "" {
-		n.logger.Error("Invalid event: missing required fields")
-		return false
+// deliverResult - 실행 결과를 Gateway 콜백으로 POST (대기 중인 kubectl 요청을 완료, 미구성 시 생략)
+func (n *NautilusEventListener) deliverResult(requestID string, result *K8sExecutionResult) {
+	if n.callbackURL == "" {
+		return
 	}
-
-	allowedMethods := []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
-	for _, method := range allowedMethods {
-		if data.Method == method {
-			return true
-		}
+	body, err := json.Marshal(result)
+	if err != nil {
+		n.logger.WithError(err).WithField("request_id", requestID).Error("Failed to encode result")
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, n.callbackURL+"/daas/v1/responses/"+url.PathEscape(requestID), bytes.NewReader(body))
+	if err != nil {
+		n.logger.WithError(err).Error("Invalid GATEWAY_CALLBACK_URL")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.callbackToken)
+	resp, err := n.callbackClient.Do(req)
+	if err != nil {
+		n.logger.WithError(err).WithField("request_id", requestID).Warn("⚠️ Result callback failed")
+		return
+	}
+	resp.Body.Close()
+	// 404는 Gateway가 이미 마감으로 포기했거나 다른 실행자가 먼저 완료한 요청
+	if resp.StatusCode != http.StatusAccepted {
+		n.logger.WithFields(logrus.Fields{
+			"request_id": requestID,
+			"status":     resp.StatusCode,
+		}).Warn("⚠️ Gateway did not accept result")
 	}
+}
 
-	n.logger.WithField("method", data.Method).Error("Unsupported HTTP method")
-	return false
+// validateEvent - 이벤트 검증 (컨트랙트와 같은 공용 스키마 검사)
+func (n *NautilusEventListener) validateEvent(event ContractEvent) bool {
+	if err := event.EventData.Validate(); err != nil {
+		n.logger.WithError(err).Error("Invalid event")
+		return false
+	}
+	return true
 }
 
 // executeK8sOperation - K8s API 실제 실행 (Mock 버전)
@@ -211,7 +256,7 @@ func (n *NautilusEventListener) executeK8sOperation(data EventData) *K8sExecutio
 
 // handleGetRequest - GET 요청 처리 (Mock)
 func (n *NautilusEventListener) handleGetRequest(data EventData) *K8sExecutionResult {
-	switch data.ResourceType {
+	switch data.Resource {
 	case "pods":
 		return n.getMockPods(data.Namespace)
 	case "services":
@@ -301,19 +346,35 @@ func (n *NautilusEventListener) getMockNodes() *K8sExecutionResult {
 }
 
 func (n *NautilusEventListener) handlePostRequest(data EventData) *K8sExecutionResult {
-	return &K8sExecutionResult{
-		StatusCode: 201,
-		Headers:    map[string]string{"Content-Type": "application/json"},
-		Body:       json.RawMessage(`{"message": "Resource created"}`),
-		Success:    true,
-	}
+	return n.admitWrite(data, http.StatusCreated, "Resource created")
 }
 
 func (n *NautilusEventListener) handlePutRequest(data EventData) *K8sExecutionResult {
+	return n.admitWrite(data, http.StatusOK, "Resource updated")
+}
+
+// admitWrite - 온체인 본문을 타입 객체로 변환/기본값/검증 후 정규화된 객체를 결과로 사용
+// 게이트웨이를 거치지 않고 컨트랙트에 직접 제출된 잘못된 매니페스트도 여기서 422로 거부
+func (n *NautilusEventListener) admitWrite(data EventData, successCode int, message string) *K8sExecutionResult {
+	normalized, statusErr := admission.Normalize(data.Resource, data.Namespace, []byte(data.Payload))
+	if statusErr != nil {
+		status := admission.StatusOf(statusErr)
+		body, _ := json.Marshal(status)
+		return &K8sExecutionResult{
+			StatusCode: int(status.Code),
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       body,
+			Success:    false,
+			Error:      status.Message,
+		}
+	}
+	if !admission.Supported(data.Resource) {
+		normalized = []byte(fmt.Sprintf(`{"message": %q}`, message))
+	}
 	return &K8sExecutionResult{
-		StatusCode: 200,
+		StatusCode: successCode,
 		Headers:    map[string]string{"Content-Type": "application/json"},
-		Body:       json.RawMessage(`{"message": "Resource updated"}`),
+		Body:       normalized,
 		Success:    true,
 	}
 }
@@ -342,30 +403,89 @@ func (n *NautilusEventListener) storeErrorResponse(requestID, errorMsg string, s
 		"error":       errorMsg,
 		"status_code": statusCode,
 	}).Error("❌ K8s operation failed")
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"message":    errorMsg,
+		"code":       statusCode,
+	})
+	n.deliverResult(requestID, &K8sExecutionResult{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+		Error:      errorMsg,
+	})
 }
 
-// startHealthServer - 헬스체크 서버
-func (n *NautilusEventListener) startHealthServer() {
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+// startHealthServer - 헬스체크 서버 (전용 mux)
+func (n *NautilusEventListener) startHealthServer(ctx context.Context) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 		fmt.Fprintf(w, `{"status": "healthy", "service": "nautilus-event-listener"}`)
 	})
 
-	n.logger.Info("🏥 Health server starting on :10250")
-	if err := http.ListenAndServe(":10250", nil); err != nil {
+	// 준비 상태 - Sui RPC 도달 가능 여부
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if _, err := n.chain.ChainTime(ctx); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, `{"status": "not_ready", "error": %q}`, err.Error())
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"status": "ready"}`)
+	})
+
+	// LISTENER_LISTEN_ADDR / LISTENER_TLS_* / LISTENER_HTTP_REDIRECT_ADDR / LISTENER_HSTS_MAX_AGE
+	handler := httpserver.Chain(mux, httpserver.RequestID, httpserver.Recovery(n.logger.Errorf))
+	server, err := httpserver.New(httpserver.ConfigFromEnv("LISTENER", ":10250"), handler)
+	if err != nil {
+		n.logger.WithError(err).Error("Invalid health server listener config")
+		return
+	}
+
+	n.logger.Infof("🏥 Health server starting on %s", server.Description())
+	if err := server.Run(ctx, 5*time.Second); err != nil {
 		n.logger.WithError(err).Error("Health server failed")
 	}
 }
 
 // main 함수
+// serviceName - systemd 유닛 / Windows 서비스 이름
+const serviceName = "k3s-daas-listener"
+
 func main() {
+	// 서비스 관리: listener service install|uninstall|start|stop|restart|status
+	if len(os.Args) > 1 && os.Args[1] == "service" {
+		spec := service.Spec{
+			Name:        serviceName,
+			DisplayName: "K3s-DaaS Event Listener",
+			Description: "K3s-DaaS Nautilus event listener (Sui contract events to K8s)",
+		}
+		if err := service.Command(spec, os.Args[2:], os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Service management failed")
+		}
+		return
+	}
+
 	listener := NewNautilusEventListener(
 		"https://fullnode.testnet.sui.io:443",
 		"0x0", // Contract address
 		"",    // Private key
 	)
 
-	if err := listener.Start(); err != nil {
+	// Windows 서비스로 실행 시 EventLog, 그 외 stderr (systemd에서는 journald로 수집)
+	listener.logger.SetOutput(service.LogOutput(serviceName))
+
+	ctx, stop := service.NotifyContext(context.Background(), serviceName)
+	defer stop()
+
+	if err := listener.Start(ctx); err != nil {
 		logrus.WithError(err).Fatal("Nautilus Event Listener failed to start")
 	}
 }
\ No newline at end of file
@@ -0,0 +1,320 @@
+// loadtest - Gateway kubectl 경로 부하 테스트 (고정 요청률, 개방형 모델)
+//
+//	go run ./cmd/loadtest -target http://localhost:8080 -token seal_... -scenario get -rate 200 -duration 30s
+//	go run ./cmd/loadtest ... -budget perf/budget.json -baseline perf/baseline-get.json -out result.json
+//
+// 응답을 기다리지 않고 정해진 간격으로 요청을 보내므로 서버가 밀리면 지연 시간에 그대로 드러납니다.
+// 예산(-budget)을 넘거나 기준 결과(-baseline)보다 나빠지면 종료 코드 1로 끝나 CI에서 사용할 수 있습니다.
+// 같은 시나리오는 scripts/loadtest의 k6/vegeta 파일로도 실행할 수 있습니다.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// scenario - 요청 생성기
+type scenario struct {
+	method string
+	accept string
+	path   func(namespace string) string
+	body   func(namespace string, seq int64) []byte
+}
+
+var scenarios = map[string]scenario{
+	"get": {
+		method: http.MethodGet,
+		accept: "application/json",
+		path:   func(ns string) string { return "/api/v1/namespaces/" + ns + "/pods" },
+	},
+	"table": {
+		method: http.MethodGet,
+		accept: "application/json;as=Table;v=v1;g=meta.k8s.io,application/json",
+		path:   func(ns string) string { return "/api/v1/namespaces/" + ns + "/pods" },
+	},
+	"post": {
+		method: http.MethodPost,
+		accept: "application/json",
+		path:   func(ns string) string { return "/api/v1/namespaces/" + ns + "/configmaps" },
+		body: func(ns string, seq int64) []byte {
+			return []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"loadtest-%d-%d","namespace":%q},"data":{"k":"v"}}`,
+				time.Now().Unix(), seq, ns))
+		},
+	},
+}
+
+// Result - 한 번의 실행 결과 (-out, -baseline 파일 형식)
+type Result struct {
+	Scenario   string  `json:"scenario"`
+	Rate       int     `json:"rate"`
+	DurationS  float64 `json:"duration_s"`
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Dropped    int64   `json:"dropped"`
+	QPS        float64 `json:"qps"`
+	ErrorRate  float64 `json:"error_rate"`
+	P50Ms      float64 `json:"p50_ms"`
+	P95Ms      float64 `json:"p95_ms"`
+	P99Ms      float64 `json:"p99_ms"`
+	MaxMs      float64 `json:"max_ms"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+// Budget - 시나리오별 성능 목표 (perf/budget.json)
+type Budget struct {
+	P50Ms        float64 `json:"p50_ms"`
+	P95Ms        float64 `json:"p95_ms"`
+	P99Ms        float64 `json:"p99_ms"`
+	MinQPSRatio  float64 `json:"min_qps_ratio"` // 목표 요청률 대비 달성 비율
+	MaxErrorRate float64 `json:"max_error_rate"`
+}
+
+type sample struct {
+	latency time.Duration
+	failed  bool
+}
+
+func main() {
+	target := flag.String("target", "http://localhost:8080", "gateway base URL")
+	token := flag.String("token", os.Getenv("DAAS_SEAL_TOKEN"), "Seal token (defaults to $DAAS_SEAL_TOKEN)")
+	name := flag.String("scenario", "get", "scenario: get, table, post")
+	namespace := flag.String("namespace", "default", "namespace used by the scenario")
+	rate := flag.Int("rate", 100, "requests per second")
+	duration := flag.Duration("duration", 30*time.Second, "test duration")
+	maxInFlight := flag.Int("max-inflight", 1000, "requests beyond this many in flight are counted as dropped")
+	budgetPath := flag.String("budget", "", "budget file (perf/budget.json)")
+	baselinePath := flag.String("baseline", "", "previous result to detect regressions against")
+	tolerance := flag.Float64("tolerance", 0.15, "allowed regression against the baseline (0.15 = 15%)")
+	out := flag.String("out", "", "write the result as JSON to this file")
+	flag.Parse()
+
+	sc, ok := scenarios[*name]
+	if !ok || *rate <= 0 {
+		fmt.Fprintf(os.Stderr, "❌ unknown scenario %q or invalid rate\n", *name)
+		os.Exit(2)
+	}
+
+	result := run(*target, *token, *name, sc, *namespace, *rate, *duration, *maxInFlight)
+	printResult(result)
+
+	if *out != "" {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		if err := os.WriteFile(*out, append(data, '\n'), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ failed to write %s: %v\n", *out, err)
+			os.Exit(2)
+		}
+	}
+
+	var violations []string
+	if *budgetPath != "" {
+		budgets := map[string]Budget{}
+		if err := readJSON(*budgetPath, &budgets); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(2)
+		}
+		if budget, ok := budgets[*name]; ok {
+			violations = append(violations, checkBudget(result, budget)...)
+		}
+	}
+	if *baselinePath != "" {
+		var baseline Result
+		if err := readJSON(*baselinePath, &baseline); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(2)
+		}
+		violations = append(violations, checkRegression(result, baseline, *tolerance)...)
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Printf("❌ %s\n", v)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("✅ within budget")
+}
+
+// run - 고정 간격으로 요청을 보내고 지연 시간 수집
+func run(target, token, name string, sc scenario, namespace string, rate int, duration time.Duration, maxInFlight int) Result {
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: maxInFlight, ForceAttemptHTTP2: true},
+	}
+	url := target + sc.path(namespace)
+
+	var (
+		mutex    sync.Mutex
+		samples  []sample
+		wg       sync.WaitGroup
+		inFlight int64
+		dropped  int64
+		seq      int64
+	)
+
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+	deadline := time.After(duration)
+	started := time.Now()
+
+loop:
+	for {
+		select {
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			if atomic.LoadInt64(&inFlight) >= int64(maxInFlight) {
+				atomic.AddInt64(&dropped, 1)
+				continue
+			}
+			atomic.AddInt64(&inFlight, 1)
+			wg.Add(1)
+			go func(n int64) {
+				defer wg.Done()
+				defer atomic.AddInt64(&inFlight, -1)
+
+				var body []byte
+				if sc.body != nil {
+					body = sc.body(namespace, n)
+				}
+				s := send(client, sc.method, url, sc.accept, token, body)
+				mutex.Lock()
+				samples = append(samples, s)
+				mutex.Unlock()
+			}(atomic.AddInt64(&seq, 1))
+		}
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	return summarize(name, rate, elapsed, samples, dropped)
+}
+
+// send - 요청 하나를 보내고 본문을 끝까지 읽은 시간까지 측정
+func send(client *http.Client, method, url, accept, token string, body []byte) sample {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return sample{failed: true}
+	}
+	req.Header.Set("Accept", accept)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return sample{latency: time.Since(start), failed: true}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return sample{latency: time.Since(start), failed: resp.StatusCode >= 400}
+}
+
+func summarize(name string, rate int, elapsed time.Duration, samples []sample, dropped int64) Result {
+	result := Result{
+		Scenario:   name,
+		Rate:       rate,
+		DurationS:  elapsed.Seconds(),
+		Requests:   len(samples),
+		Dropped:    dropped,
+		FinishedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(samples) == 0 {
+		return result
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		if s.failed {
+			result.Errors++
+		}
+		latencies = append(latencies, s.latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	percentile := func(p float64) float64 {
+		index := int(p * float64(len(latencies)-1))
+		return float64(latencies[index]) / float64(time.Millisecond)
+	}
+	result.QPS = float64(len(samples)-result.Errors) / elapsed.Seconds()
+	result.ErrorRate = float64(result.Errors+int(dropped)) / float64(len(samples)+int(dropped))
+	result.P50Ms = percentile(0.50)
+	result.P95Ms = percentile(0.95)
+	result.P99Ms = percentile(0.99)
+	result.MaxMs = percentile(1)
+	return result
+}
+
+func printResult(r Result) {
+	fmt.Printf("scenario %s @ %d req/s for %.1fs\n", r.Scenario, r.Rate, r.DurationS)
+	fmt.Printf("  requests  %d (errors %d, dropped %d, error rate %.2f%%)\n", r.Requests, r.Errors, r.Dropped, 100*r.ErrorRate)
+	fmt.Printf("  throughput %.1f req/s\n", r.QPS)
+	fmt.Printf("  latency   p50 %.2fms  p95 %.2fms  p99 %.2fms  max %.2fms\n", r.P50Ms, r.P95Ms, r.P99Ms, r.MaxMs)
+}
+
+// checkBudget - 예산 위반 항목
+func checkBudget(r Result, b Budget) []string {
+	var violations []string
+	check := func(label string, got, limit float64) {
+		if limit > 0 && got > limit {
+			violations = append(violations, fmt.Sprintf("%s %s %.2fms exceeds budget %.2fms", r.Scenario, label, got, limit))
+		}
+	}
+	check("p50", r.P50Ms, b.P50Ms)
+	check("p95", r.P95Ms, b.P95Ms)
+	check("p99", r.P99Ms, b.P99Ms)
+
+	if b.MinQPSRatio > 0 && r.QPS < b.MinQPSRatio*float64(r.Rate) {
+		violations = append(violations, fmt.Sprintf("%s throughput %.1f req/s below %.0f%% of target %d req/s",
+			r.Scenario, r.QPS, 100*b.MinQPSRatio, r.Rate))
+	}
+	if r.ErrorRate > b.MaxErrorRate {
+		violations = append(violations, fmt.Sprintf("%s error rate %.2f%% exceeds budget %.2f%%",
+			r.Scenario, 100*r.ErrorRate, 100*b.MaxErrorRate))
+	}
+	return violations
+}
+
+// checkRegression - 같은 요청률의 기준 결과 대비 악화 항목
+func checkRegression(r, base Result, tolerance float64) []string {
+	if base.Scenario != r.Scenario || base.Rate != r.Rate {
+		return []string{fmt.Sprintf("baseline is for %s @ %d req/s, not %s @ %d req/s", base.Scenario, base.Rate, r.Scenario, r.Rate)}
+	}
+
+	var violations []string
+	check := func(label string, got, was float64) {
+		if was > 0 && got > was*(1+tolerance) {
+			violations = append(violations, fmt.Sprintf("%s %s regressed %.2fms -> %.2fms (+%.0f%%)",
+				r.Scenario, label, was, got, 100*(got/was-1)))
+		}
+	}
+	check("p50", r.P50Ms, base.P50Ms)
+	check("p99", r.P99Ms, base.P99Ms)
+	if base.QPS > 0 && r.QPS < base.QPS*(1-tolerance) {
+		violations = append(violations, fmt.Sprintf("%s throughput regressed %.1f -> %.1f req/s", r.Scenario, base.QPS, r.QPS))
+	}
+	return violations
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return nil
+}
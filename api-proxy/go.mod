@@ -5,15 +5,26 @@ go 1.21
 require (
 	github.com/go-resty/resty/v2 v2.7.0
 	github.com/gorilla/websocket v1.5.0
+	github.com/k3s-io/daas-apirequest v0.0.0
+	github.com/k3s-io/daas-chain v0.0.0 // indirect
+	github.com/k3s-io/daas-escrow v0.0.0
+	github.com/k3s-io/daas-featuregate v0.0.0
+	github.com/k3s-io/daas-httpserver v0.0.0
+	github.com/k3s-io/daas-service v0.0.0
+	github.com/k3s-io/daas-signing v0.0.0
+	github.com/k3s-io/daas-sui v0.0.0
+	github.com/k3s-io/daas-version v0.0.0
 	github.com/sirupsen/logrus v1.9.3
 	k8s.io/api v0.28.0
 	k8s.io/apimachinery v0.28.0
 	k8s.io/client-go v0.28.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/emicklei/go-restful/v3 v3.9.0 // indirect
+	github.com/evanphx/json-patch v5.6.0+incompatible // indirect
 	github.com/go-logr/logr v1.2.4 // indirect
 	github.com/go-openapi/jsonpointer v0.19.6 // indirect
 	github.com/go-openapi/jsonreference v0.20.2 // indirect
@@ -24,12 +35,15 @@ require (
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/google/gofuzz v1.2.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
+	github.com/imdario/mergo v0.3.6 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/net v0.13.0 // indirect
 	golang.org/x/oauth2 v0.8.0 // indirect
 	golang.org/x/sys v0.10.0 // indirect
@@ -46,5 +60,31 @@ require (
 	k8s.io/utils v0.0.0-20230726121419-3b25d923346b // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.3.0 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
+
+// 공용 Sui 클라이언트
+replace github.com/k3s-io/daas-sui => ../pkg/sui
+
+// 공용 체인 백엔드 인터페이스 (Sui/mock)
+replace github.com/k3s-io/daas-chain => ../pkg/chain
+
+// 공용 K8s API 요청 스키마 (Gateway/Move 이벤트/마스터)
+replace github.com/k3s-io/daas-apirequest => ../pkg/apirequest
+
+// 공용 기능 게이트 (--feature-gates)
+replace github.com/k3s-io/daas-featuregate => ../pkg/featuregate
+
+// 공용 HTTP 리스너 (TLS/리다이렉트/HSTS)
+replace github.com/k3s-io/daas-httpserver => ../pkg/httpserver
+
+// 공용 서비스 관리 (systemd 유닛 / Windows 서비스)
+replace github.com/k3s-io/daas-service => ../pkg/service
+
+// 공용 빌드 버전 / 구성요소 간 버전 차이 정책
+replace github.com/k3s-io/daas-version => ../pkg/version
+
+// 봉인 루트 키 에스크로 (Shamir 분할, 보관자 조각 봉투)
+replace github.com/k3s-io/daas-escrow => ../pkg/escrow
+
+// Gateway ↔ 마스터 요청/응답 서명 (watch 프레임 서명, nonce 재사용 차단)
+replace github.com/k3s-io/daas-signing => ../pkg/signing
@@ -0,0 +1,53 @@
+// k6 부하 테스트 - cmd/loadtest와 같은 시나리오와 예산 (budget.json)
+//
+//   k6 run -e TARGET=http://localhost:8080 -e TOKEN=seal_... -e SCENARIO=get -e RATE=200 api-proxy/perf/kubectl.k6.js
+import http from 'k6/http';
+import { check } from 'k6';
+
+const target = __ENV.TARGET || 'http://localhost:8080';
+const token = __ENV.TOKEN || '';
+const scenario = __ENV.SCENARIO || 'get';
+const namespace = __ENV.NAMESPACE || 'default';
+const rate = parseInt(__ENV.RATE || '100', 10);
+const budget = JSON.parse(open('./budget.json'))[scenario];
+
+export const options = {
+  scenarios: {
+    kubectl: {
+      executor: 'constant-arrival-rate',
+      rate: rate,
+      timeUnit: '1s',
+      duration: __ENV.DURATION || '30s',
+      preAllocatedVUs: Math.max(10, rate),
+      maxVUs: 1000,
+    },
+  },
+  thresholds: {
+    http_req_duration: [`med<${budget.p50_ms}`, `p(95)<${budget.p95_ms}`, `p(99)<${budget.p99_ms}`],
+    http_req_failed: [`rate<=${budget.max_error_rate}`],
+  },
+};
+
+const accept = {
+  get: 'application/json',
+  table: 'application/json;as=Table;v=v1;g=meta.k8s.io,application/json',
+  post: 'application/json',
+};
+
+export default function () {
+  const headers = { Accept: accept[scenario], Authorization: `Bearer ${token}` };
+  let res;
+  if (scenario === 'post') {
+    const body = JSON.stringify({
+      apiVersion: 'v1',
+      kind: 'ConfigMap',
+      metadata: { name: `loadtest-${__VU}-${__ITER}-${Date.now()}`, namespace: namespace },
+      data: { k: 'v' },
+    });
+    headers['Content-Type'] = 'application/json';
+    res = http.post(`${target}/api/v1/namespaces/${namespace}/configmaps`, body, { headers });
+  } else {
+    res = http.get(`${target}/api/v1/namespaces/${namespace}/pods`, { headers });
+  }
+  check(res, { 'status < 400': (r) => r.status < 400 });
+}
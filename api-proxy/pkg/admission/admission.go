@@ -0,0 +1,147 @@
+// Package admission - 쓰기 요청 본문을 K8s 타입 객체로 변환, 기본값 적용, 검증
+//
+// 게이트웨이와 리스너는 받은 바이트를 그대로 제출/실행하지 않고 Normalize를 거쳐
+// k8s.io/api(core/apps/batch) 구조체로 디코딩한 뒤 API 서버와 같은 기본값을 채우고,
+// 잘못된 매니페스트는 필드 경로가 담긴 422 Invalid Status로 즉시 거부합니다.
+// 테이블에 없는 리소스(CRD 등)는 받은 본문을 그대로 통과시킵니다.
+package admission
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// resourceKind - 리소스별 타입과 이름 규칙
+type resourceKind struct {
+	gvk        schema.GroupVersionKind
+	namespaced bool
+	nameFn     apivalidation.ValidateNameFunc
+}
+
+// resourceKinds - 타입 변환/검증 대상 리소스 (URL 경로의 리소스 이름 기준)
+var resourceKinds = map[string]resourceKind{
+	"pods":                   {corev1.SchemeGroupVersion.WithKind("Pod"), true, apivalidation.NameIsDNSSubdomain},
+	"services":               {corev1.SchemeGroupVersion.WithKind("Service"), true, apivalidation.NameIsDNS1035Label},
+	"configmaps":             {corev1.SchemeGroupVersion.WithKind("ConfigMap"), true, apivalidation.NameIsDNSSubdomain},
+	"secrets":                {corev1.SchemeGroupVersion.WithKind("Secret"), true, apivalidation.NameIsDNSSubdomain},
+	"persistentvolumeclaims": {corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"), true, apivalidation.NameIsDNSSubdomain},
+	"namespaces":             {corev1.SchemeGroupVersion.WithKind("Namespace"), false, apivalidation.NameIsDNSLabel},
+	"limitranges":            {corev1.SchemeGroupVersion.WithKind("LimitRange"), true, apivalidation.NameIsDNSSubdomain},
+	"deployments":            {appsv1.SchemeGroupVersion.WithKind("Deployment"), true, apivalidation.NameIsDNSSubdomain},
+	"statefulsets":           {appsv1.SchemeGroupVersion.WithKind("StatefulSet"), true, apivalidation.NameIsDNSSubdomain},
+	"daemonsets":             {appsv1.SchemeGroupVersion.WithKind("DaemonSet"), true, apivalidation.NameIsDNSSubdomain},
+	"replicasets":            {appsv1.SchemeGroupVersion.WithKind("ReplicaSet"), true, apivalidation.NameIsDNSSubdomain},
+	"jobs":                   {batchv1.SchemeGroupVersion.WithKind("Job"), true, apivalidation.NameIsDNSSubdomain},
+	"cronjobs":               {batchv1.SchemeGroupVersion.WithKind("CronJob"), true, apivalidation.NameIsDNSSubdomain},
+}
+
+var (
+	// strictSerializer - 알 수 없는 필드와 중복 필드를 오류로 처리 (fieldValidation=Strict와 동일)
+	strictSerializer = json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{Strict: true})
+	jsonSerializer   = json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{})
+)
+
+// Supported - 타입 변환/검증 대상 리소스인지 여부
+func Supported(resource string) bool {
+	_, ok := resourceKinds[resource]
+	return ok
+}
+
+// ResourceFor - apiVersion/kind에 해당하는 리소스 이름과 네임스페이스 범위 여부 (렌더링된 매니페스트 분류용)
+func ResourceFor(apiVersion, kind string) (resource string, namespaced bool, ok bool) {
+	for name, candidate := range resourceKinds {
+		if candidate.gvk.GroupVersion().String() == apiVersion && candidate.gvk.Kind == kind {
+			return name, candidate.namespaced, true
+		}
+	}
+	return "", false, false
+}
+
+/*
+Normalize - JSON 본문을 타입 객체로 디코딩 → 기본값 → 검증 후 정규화된 JSON 반환
+
+  - 본문의 apiVersion/kind가 비어 있으면 경로의 리소스 타입으로 간주, 다르면 400
+  - 네임스페이스 리소스는 metadata.namespace가 비어 있으면 요청 네임스페이스로 채우고, 다르면 400
+  - 검증 실패는 422 Invalid (details.causes에 필드 경로)
+*/
+func Normalize(resource, namespace string, body []byte) ([]byte, *apierrors.StatusError) {
+	kind, ok := resourceKinds[resource]
+	if !ok {
+		return body, nil
+	}
+	if len(body) == 0 {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("request body is required to create or update %s", resource))
+	}
+
+	obj, gvk, err := strictSerializer.Decode(body, &kind.gvk, nil)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if *gvk != kind.gvk {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("%s is not a valid object for %s (expected %s)",
+			gvk.GroupVersion().WithKind(gvk.Kind), resource, kind.gvk))
+	}
+	obj.GetObjectKind().SetGroupVersionKind(kind.gvk)
+
+	meta, err := metaOf(obj)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if kind.namespaced {
+		switch {
+		case meta.Namespace == "":
+			meta.Namespace = namespace
+		case namespace != "" && meta.Namespace != namespace:
+			return nil, apierrors.NewBadRequest(fmt.Sprintf(
+				"the namespace of the provided object (%s) does not match the namespace sent on the request (%s)", meta.Namespace, namespace))
+		}
+	}
+
+	setDefaults(obj)
+
+	errs := apivalidation.ValidateObjectMeta(meta, kind.namespaced, kind.nameFn, field.NewPath("metadata"))
+	errs = append(errs, validate(obj)...)
+	if len(errs) > 0 {
+		name := meta.Name
+		if name == "" {
+			name = meta.GenerateName
+		}
+		return nil, apierrors.NewInvalid(kind.gvk.GroupKind(), name, errs)
+	}
+
+	normalized, err := runtime.Encode(jsonSerializer, obj)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return normalized, nil
+}
+
+// StatusOf - kubectl에 돌려줄 Status 객체 (apiVersion/kind 포함)
+func StatusOf(err *apierrors.StatusError) metav1.Status {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+	return status
+}
+
+func metaOf(obj runtime.Object) (*metav1.ObjectMeta, error) {
+	accessor, ok := obj.(interface{ GetObjectMeta() metav1.Object })
+	if !ok {
+		return nil, fmt.Errorf("%T has no object metadata", obj)
+	}
+	meta, ok := accessor.GetObjectMeta().(*metav1.ObjectMeta)
+	if !ok {
+		return nil, fmt.Errorf("%T has unexpected metadata type", obj)
+	}
+	return meta, nil
+}
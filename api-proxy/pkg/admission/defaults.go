@@ -0,0 +1,266 @@
+package admission
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// setDefaults - API 서버의 SetDefaults_*와 같은 값으로 생략된 필드 채우기
+func setDefaults(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		defaultPodSpec(&o.Spec)
+	case *corev1.Service:
+		defaultService(&o.Spec)
+	case *corev1.Secret:
+		defaultSecret(o)
+	case *corev1.PersistentVolumeClaim:
+		if o.Spec.VolumeMode == nil {
+			mode := corev1.PersistentVolumeFilesystem
+			o.Spec.VolumeMode = &mode
+		}
+	case *corev1.LimitRange:
+		for i := range o.Spec.Limits {
+			defaultLimitRangeItem(&o.Spec.Limits[i])
+		}
+	case *appsv1.Deployment:
+		defaultDeployment(&o.Spec)
+	case *appsv1.StatefulSet:
+		defaultStatefulSet(&o.Spec)
+	case *appsv1.DaemonSet:
+		defaultDaemonSet(&o.Spec)
+	case *appsv1.ReplicaSet:
+		defaultInt32(&o.Spec.Replicas, 1)
+		defaultPodSpec(&o.Spec.Template.Spec)
+	case *batchv1.Job:
+		defaultJob(&o.Spec)
+	case *batchv1.CronJob:
+		defaultCronJob(&o.Spec)
+	}
+}
+
+func defaultPodSpec(spec *corev1.PodSpec) {
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = corev1.DNSClusterFirst
+	}
+	if spec.SchedulerName == "" {
+		spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	if spec.TerminationGracePeriodSeconds == nil {
+		grace := int64(corev1.DefaultTerminationGracePeriodSeconds)
+		spec.TerminationGracePeriodSeconds = &grace
+	}
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if spec.EnableServiceLinks == nil {
+		enable := corev1.DefaultEnableServiceLinks
+		spec.EnableServiceLinks = &enable
+	}
+	for i := range spec.InitContainers {
+		defaultContainer(&spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		defaultContainer(&spec.Containers[i])
+	}
+}
+
+func defaultContainer(container *corev1.Container) {
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = defaultPullPolicy(container.Image)
+	}
+	if container.TerminationMessagePath == "" {
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	for i := range container.Ports {
+		if container.Ports[i].Protocol == "" {
+			container.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+}
+
+// defaultPullPolicy - 태그가 없거나 latest면 Always, 그 외(다이제스트 포함) IfNotPresent
+func defaultPullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i < 0 || name[i+1:] == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+func defaultService(spec *corev1.ServiceSpec) {
+	if spec.Type == "" {
+		spec.Type = corev1.ServiceTypeClusterIP
+	}
+	if spec.SessionAffinity == "" {
+		spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+	if spec.Type != corev1.ServiceTypeExternalName && spec.InternalTrafficPolicy == nil {
+		policy := corev1.ServiceInternalTrafficPolicyCluster
+		spec.InternalTrafficPolicy = &policy
+	}
+	if (spec.Type == corev1.ServiceTypeNodePort || spec.Type == corev1.ServiceTypeLoadBalancer) && spec.ExternalTrafficPolicy == "" {
+		spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	}
+	for i := range spec.Ports {
+		port := &spec.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort == intstr.FromInt(0) || port.TargetPort == intstr.FromString("") {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+	}
+}
+
+// defaultSecret - stringData를 data로 합치고 기본 타입 지정 (API 서버와 같이 stringData는 저장하지 않음)
+func defaultSecret(secret *corev1.Secret) {
+	if secret.Type == "" {
+		secret.Type = corev1.SecretTypeOpaque
+	}
+	if len(secret.StringData) == 0 {
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(secret.StringData))
+	}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+}
+
+func defaultDeployment(spec *appsv1.DeploymentSpec) {
+	defaultInt32(&spec.Replicas, 1)
+	defaultInt32(&spec.RevisionHistoryLimit, 10)
+	defaultInt32(&spec.ProgressDeadlineSeconds, 600)
+	if spec.Strategy.Type == "" {
+		spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	}
+	if spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType {
+		if spec.Strategy.RollingUpdate == nil {
+			spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		}
+		defaultIntOrString(&spec.Strategy.RollingUpdate.MaxUnavailable, intstr.FromString("25%"))
+		defaultIntOrString(&spec.Strategy.RollingUpdate.MaxSurge, intstr.FromString("25%"))
+	}
+	defaultPodSpec(&spec.Template.Spec)
+}
+
+func defaultStatefulSet(spec *appsv1.StatefulSetSpec) {
+	defaultInt32(&spec.Replicas, 1)
+	defaultInt32(&spec.RevisionHistoryLimit, 10)
+	if spec.PodManagementPolicy == "" {
+		spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	}
+	if spec.UpdateStrategy.Type == "" {
+		spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	}
+	if spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		if spec.UpdateStrategy.RollingUpdate == nil {
+			spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+		}
+		defaultInt32(&spec.UpdateStrategy.RollingUpdate.Partition, 0)
+	}
+	defaultPodSpec(&spec.Template.Spec)
+}
+
+func defaultDaemonSet(spec *appsv1.DaemonSetSpec) {
+	defaultInt32(&spec.RevisionHistoryLimit, 10)
+	if spec.UpdateStrategy.Type == "" {
+		spec.UpdateStrategy.Type = appsv1.RollingUpdateDaemonSetStrategyType
+	}
+	if spec.UpdateStrategy.Type == appsv1.RollingUpdateDaemonSetStrategyType {
+		if spec.UpdateStrategy.RollingUpdate == nil {
+			spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{}
+		}
+		defaultIntOrString(&spec.UpdateStrategy.RollingUpdate.MaxUnavailable, intstr.FromInt(1))
+		defaultIntOrString(&spec.UpdateStrategy.RollingUpdate.MaxSurge, intstr.FromInt(0))
+	}
+	defaultPodSpec(&spec.Template.Spec)
+}
+
+func defaultJob(spec *batchv1.JobSpec) {
+	// completions와 parallelism이 모두 없으면 한 번 실행하는 Job
+	if spec.Completions == nil && spec.Parallelism == nil {
+		defaultInt32(&spec.Completions, 1)
+	}
+	defaultInt32(&spec.Parallelism, 1)
+	defaultInt32(&spec.BackoffLimit, 6)
+	if spec.CompletionMode == nil {
+		mode := batchv1.NonIndexedCompletion
+		spec.CompletionMode = &mode
+	}
+	if spec.Suspend == nil {
+		suspend := false
+		spec.Suspend = &suspend
+	}
+	// Job은 재시작 정책을 직접 지정해야 하므로 비어 있으면 그대로 두어 검증에서 Required로 보고
+	restartPolicy := spec.Template.Spec.RestartPolicy
+	defaultPodSpec(&spec.Template.Spec)
+	spec.Template.Spec.RestartPolicy = restartPolicy
+}
+
+func defaultCronJob(spec *batchv1.CronJobSpec) {
+	if spec.ConcurrencyPolicy == "" {
+		spec.ConcurrencyPolicy = batchv1.AllowConcurrent
+	}
+	if spec.Suspend == nil {
+		suspend := false
+		spec.Suspend = &suspend
+	}
+	defaultInt32(&spec.SuccessfulJobsHistoryLimit, 3)
+	defaultInt32(&spec.FailedJobsHistoryLimit, 1)
+	defaultJob(&spec.JobTemplate.Spec)
+}
+
+func defaultInt32(value **int32, fallback int32) {
+	if *value == nil {
+		*value = &fallback
+	}
+}
+
+func defaultIntOrString(value **intstr.IntOrString, fallback intstr.IntOrString) {
+	if *value == nil {
+		*value = &fallback
+	}
+}
+
+// defaultLimitRangeItem - Container 항목의 기본 limit은 max, 기본 request는 기본 limit 또는 min
+func defaultLimitRangeItem(item *corev1.LimitRangeItem) {
+	if item.Type != corev1.LimitTypeContainer {
+		return
+	}
+	if item.Default == nil {
+		item.Default = corev1.ResourceList{}
+	}
+	if item.DefaultRequest == nil {
+		item.DefaultRequest = corev1.ResourceList{}
+	}
+	for name, value := range item.Max {
+		if _, ok := item.Default[name]; !ok {
+			item.Default[name] = value.DeepCopy()
+		}
+	}
+	for _, source := range []corev1.ResourceList{item.Default, item.Min} {
+		for name, value := range source {
+			if _, ok := item.DefaultRequest[name]; !ok {
+				item.DefaultRequest[name] = value.DeepCopy()
+			}
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	supportedProtocols       = []string{string(corev1.ProtocolTCP), string(corev1.ProtocolUDP), string(corev1.ProtocolSCTP)}
+	supportedPullPolicies    = []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}
+	supportedDNSPolicies     = []string{string(corev1.DNSClusterFirst), string(corev1.DNSClusterFirstWithHostNet), string(corev1.DNSDefault), string(corev1.DNSNone)}
+	supportedServiceTypes    = []string{string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer), string(corev1.ServiceTypeExternalName)}
+	supportedAffinities      = []string{string(corev1.ServiceAffinityNone), string(corev1.ServiceAffinityClientIP)}
+	supportedAccessModes     = []string{string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod)}
+	workloadRestartPolicy    = []string{string(corev1.RestartPolicyAlways)}
+	jobRestartPolicies       = []string{string(corev1.RestartPolicyOnFailure), string(corev1.RestartPolicyNever)}
+	podRestartPolicies       = []string{string(corev1.RestartPolicyAlways), string(corev1.RestartPolicyOnFailure), string(corev1.RestartPolicyNever)}
+	concurrencyPolicies      = []string{string(batchv1.AllowConcurrent), string(batchv1.ForbidConcurrent), string(batchv1.ReplaceConcurrent)}
+	podManagementPolicies    = []string{string(appsv1.OrderedReadyPodManagement), string(appsv1.ParallelPodManagement)}
+	deploymentStrategies     = []string{string(appsv1.RecreateDeploymentStrategyType), string(appsv1.RollingUpdateDeploymentStrategyType)}
+	limitTypes               = []string{string(corev1.LimitTypePod), string(corev1.LimitTypeContainer), string(corev1.LimitTypePersistentVolumeClaim)}
+	cronScheduleMacros       = sets.NewString("@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly")
+	nodePortMin, nodePortMax = 30000, 32767
+)
+
+// validate - 타입별 spec 검증 (metadata는 Normalize에서 공통 검증)
+func validate(obj runtime.Object) field.ErrorList {
+	spec := field.NewPath("spec")
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return validatePodSpec(&o.Spec, spec, podRestartPolicies)
+	case *corev1.Service:
+		return validateService(&o.Spec, spec)
+	case *corev1.ConfigMap:
+		return validateConfigMap(o)
+	case *corev1.Secret:
+		return validateDataKeys(o.Data, nil, field.NewPath("data"))
+	case *corev1.PersistentVolumeClaim:
+		return validatePVC(&o.Spec, spec)
+	case *corev1.Namespace:
+		return nil
+	case *corev1.LimitRange:
+		return validateLimitRange(&o.Spec, spec.Child("limits"))
+	case *appsv1.Deployment:
+		errs := validateReplicas(o.Spec.Replicas, spec.Child("replicas"))
+		errs = append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template, spec, workloadRestartPolicy)...)
+		if o.Spec.Strategy.Type != "" && !contains(deploymentStrategies, string(o.Spec.Strategy.Type)) {
+			errs = append(errs, field.NotSupported(spec.Child("strategy", "type"), o.Spec.Strategy.Type, deploymentStrategies))
+		}
+		if o.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType && o.Spec.Strategy.RollingUpdate != nil {
+			errs = append(errs, field.Forbidden(spec.Child("strategy", "rollingUpdate"), "may not be specified when strategy `type` is 'Recreate'"))
+		}
+		return errs
+	case *appsv1.StatefulSet:
+		errs := validateReplicas(o.Spec.Replicas, spec.Child("replicas"))
+		errs = append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template, spec, workloadRestartPolicy)...)
+		if !contains(podManagementPolicies, string(o.Spec.PodManagementPolicy)) {
+			errs = append(errs, field.NotSupported(spec.Child("podManagementPolicy"), o.Spec.PodManagementPolicy, podManagementPolicies))
+		}
+		return errs
+	case *appsv1.DaemonSet:
+		return validateWorkload(o.Spec.Selector, &o.Spec.Template, spec, workloadRestartPolicy)
+	case *appsv1.ReplicaSet:
+		errs := validateReplicas(o.Spec.Replicas, spec.Child("replicas"))
+		return append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template, spec, workloadRestartPolicy)...)
+	case *batchv1.Job:
+		return validateJobSpec(&o.Spec, spec)
+	case *batchv1.CronJob:
+		return validateCronJob(&o.Spec, spec)
+	}
+	return nil
+}
+
+// validateWorkload - 셀렉터와 Pod 템플릿 (셀렉터는 템플릿 라벨과 일치해야 함)
+func validateWorkload(selector *metav1.LabelSelector, template *corev1.PodTemplateSpec, spec *field.Path, restartPolicies []string) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateSelector(selector, template, spec)...)
+	errs = append(errs, metavalidation.ValidateLabels(template.Labels, spec.Child("template", "metadata", "labels"))...)
+	errs = append(errs, apivalidation.ValidateAnnotations(template.Annotations, spec.Child("template", "metadata", "annotations"))...)
+	errs = append(errs, validatePodSpec(&template.Spec, spec.Child("template", "spec"), restartPolicies)...)
+	return errs
+}
+
+func validateSelector(selector *metav1.LabelSelector, template *corev1.PodTemplateSpec, spec *field.Path) field.ErrorList {
+	path := spec.Child("selector")
+	if selector == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	errs := metavalidation.ValidateLabelSelector(selector, metavalidation.LabelSelectorValidationOptions{}, path)
+	if len(selector.MatchLabels)+len(selector.MatchExpressions) == 0 {
+		errs = append(errs, field.Invalid(path, selector, "empty selector is not allowed"))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err == nil && !parsed.Matches(labels.Set(template.Labels)) {
+		errs = append(errs, field.Invalid(spec.Child("template", "metadata", "labels"), template.Labels, "`selector` does not match template `labels`"))
+	}
+	return errs
+}
+
+func validateReplicas(replicas *int32, path *field.Path) field.ErrorList {
+	if replicas == nil {
+		return nil
+	}
+	return apivalidation.ValidateNonnegativeField(int64(*replicas), path)
+}
+
+func validatePodSpec(spec *corev1.PodSpec, path *field.Path, restartPolicies []string) field.ErrorList {
+	var errs field.ErrorList
+
+	volumes := sets.NewString()
+	for i, volume := range spec.Volumes {
+		volumePath := path.Child("volumes").Index(i)
+		errs = append(errs, validateDNSLabel(volume.Name, volumePath.Child("name"))...)
+		if volumes.Has(volume.Name) {
+			errs = append(errs, field.Duplicate(volumePath.Child("name"), volume.Name))
+		}
+		volumes.Insert(volume.Name)
+	}
+
+	if len(spec.Containers) == 0 {
+		errs = append(errs, field.Required(path.Child("containers"), ""))
+	}
+	names := sets.NewString()
+	errs = append(errs, validateContainers(spec.InitContainers, path.Child("initContainers"), names, volumes)...)
+	errs = append(errs, validateContainers(spec.Containers, path.Child("containers"), names, volumes)...)
+
+	switch {
+	case spec.RestartPolicy == "":
+		errs = append(errs, field.Required(path.Child("restartPolicy"), "valid values: "+quoteJoin(restartPolicies)))
+	case !contains(restartPolicies, string(spec.RestartPolicy)):
+		errs = append(errs, field.NotSupported(path.Child("restartPolicy"), spec.RestartPolicy, restartPolicies))
+	}
+	if !contains(supportedDNSPolicies, string(spec.DNSPolicy)) {
+		errs = append(errs, field.NotSupported(path.Child("dnsPolicy"), spec.DNSPolicy, supportedDNSPolicies))
+	}
+	if spec.TerminationGracePeriodSeconds != nil {
+		errs = append(errs, apivalidation.ValidateNonnegativeField(*spec.TerminationGracePeriodSeconds, path.Child("terminationGracePeriodSeconds"))...)
+	}
+	errs = append(errs, metavalidation.ValidateLabels(spec.NodeSelector, path.Child("nodeSelector"))...)
+	return errs
+}
+
+// validateContainers - 이름은 init 컨테이너를 포함해 Pod 안에서 유일해야 함
+func validateContainers(containers []corev1.Container, path *field.Path, names, volumes sets.String) field.ErrorList {
+	var errs field.ErrorList
+	for i, container := range containers {
+		containerPath := path.Index(i)
+		errs = append(errs, validateDNSLabel(container.Name, containerPath.Child("name"))...)
+		if names.Has(container.Name) {
+			errs = append(errs, field.Duplicate(containerPath.Child("name"), container.Name))
+		}
+		names.Insert(container.Name)
+
+		if strings.TrimSpace(container.Image) == "" {
+			errs = append(errs, field.Required(containerPath.Child("image"), ""))
+		}
+		if !contains(supportedPullPolicies, string(container.ImagePullPolicy)) {
+			errs = append(errs, field.NotSupported(containerPath.Child("imagePullPolicy"), container.ImagePullPolicy, supportedPullPolicies))
+		}
+
+		ports := sets.NewString()
+		for j, port := range container.Ports {
+			portPath := containerPath.Child("ports").Index(j)
+			for _, msg := range validation.IsValidPortNum(int(port.ContainerPort)) {
+				errs = append(errs, field.Invalid(portPath.Child("containerPort"), port.ContainerPort, msg))
+			}
+			if port.HostPort != 0 {
+				for _, msg := range validation.IsValidPortNum(int(port.HostPort)) {
+					errs = append(errs, field.Invalid(portPath.Child("hostPort"), port.HostPort, msg))
+				}
+			}
+			if !contains(supportedProtocols, string(port.Protocol)) {
+				errs = append(errs, field.NotSupported(portPath.Child("protocol"), port.Protocol, supportedProtocols))
+			}
+			if port.Name != "" {
+				for _, msg := range validation.IsValidPortName(port.Name) {
+					errs = append(errs, field.Invalid(portPath.Child("name"), port.Name, msg))
+				}
+				if ports.Has(port.Name) {
+					errs = append(errs, field.Duplicate(portPath.Child("name"), port.Name))
+				}
+				ports.Insert(port.Name)
+			}
+		}
+
+		for j, env := range container.Env {
+			for _, msg := range validation.IsEnvVarName(env.Name) {
+				errs = append(errs, field.Invalid(containerPath.Child("env").Index(j).Child("name"), env.Name, msg))
+			}
+		}
+		for j, mount := range container.VolumeMounts {
+			mountPath := containerPath.Child("volumeMounts").Index(j)
+			if !volumes.Has(mount.Name) {
+				errs = append(errs, field.NotFound(mountPath.Child("name"), mount.Name))
+			}
+			if mount.MountPath == "" {
+				errs = append(errs, field.Required(mountPath.Child("mountPath"), ""))
+			}
+		}
+
+		// 요청량은 제한량을 넘을 수 없음
+		for name, limit := range container.Resources.Limits {
+			if request, ok := container.Resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+				errs = append(errs, field.Invalid(containerPath.Child("resources", "requests").Key(string(name)), request.String(),
+					fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+			}
+		}
+	}
+	return errs
+}
+
+func validateService(spec *corev1.ServiceSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if !contains(supportedServiceTypes, string(spec.Type)) {
+		errs = append(errs, field.NotSupported(path.Child("type"), spec.Type, supportedServiceTypes))
+	}
+	if !contains(supportedAffinities, string(spec.SessionAffinity)) {
+		errs = append(errs, field.NotSupported(path.Child("sessionAffinity"), spec.SessionAffinity, supportedAffinities))
+	}
+	errs = append(errs, metavalidation.ValidateLabels(spec.Selector, path.Child("selector"))...)
+
+	if spec.Type == corev1.ServiceTypeExternalName {
+		if spec.ExternalName == "" {
+			errs = append(errs, field.Required(path.Child("externalName"), ""))
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(spec.ExternalName) {
+			errs = append(errs, field.Invalid(path.Child("externalName"), spec.ExternalName, msg))
+		}
+	} else if len(spec.Ports) == 0 && spec.ClusterIP != corev1.ClusterIPNone {
+		errs = append(errs, field.Required(path.Child("ports"), ""))
+	}
+
+	names := sets.NewString()
+	for i, port := range spec.Ports {
+		portPath := path.Child("ports").Index(i)
+		if len(spec.Ports) > 1 && port.Name == "" {
+			errs = append(errs, field.Required(portPath.Child("name"), "must be specified when there is more than one port"))
+		}
+		if port.Name != "" {
+			errs = append(errs, validateDNSLabel(port.Name, portPath.Child("name"))...)
+			if names.Has(port.Name) {
+				errs = append(errs, field.Duplicate(portPath.Child("name"), port.Name))
+			}
+			names.Insert(port.Name)
+		}
+		for _, msg := range validation.IsValidPortNum(int(port.Port)) {
+			errs = append(errs, field.Invalid(portPath.Child("port"), port.Port, msg))
+		}
+		if !contains(supportedProtocols, string(port.Protocol)) {
+			errs = append(errs, field.NotSupported(portPath.Child("protocol"), port.Protocol, supportedProtocols))
+		}
+		errs = append(errs, validateTargetPort(port.TargetPort, portPath.Child("targetPort"))...)
+
+		if port.NodePort != 0 {
+			if spec.Type != corev1.ServiceTypeNodePort && spec.Type != corev1.ServiceTypeLoadBalancer {
+				errs = append(errs, field.Forbidden(portPath.Child("nodePort"), "may not be used when `type` is '"+string(spec.Type)+"'"))
+			} else if port.NodePort < int32(nodePortMin) || port.NodePort > int32(nodePortMax) {
+				errs = append(errs, field.Invalid(portPath.Child("nodePort"), port.NodePort,
+					fmt.Sprintf("provided port is not in the valid range. The range of valid ports is %d-%d", nodePortMin, nodePortMax)))
+			}
+		}
+	}
+	return errs
+}
+
+func validateTargetPort(port intstr.IntOrString, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if port.Type == intstr.String {
+		for _, msg := range validation.IsValidPortName(port.StrVal) {
+			errs = append(errs, field.Invalid(path, port.StrVal, msg))
+		}
+		return errs
+	}
+	for _, msg := range validation.IsValidPortNum(port.IntValue()) {
+		errs = append(errs, field.Invalid(path, port.IntVal, msg))
+	}
+	return errs
+}
+
+func validateConfigMap(configMap *corev1.ConfigMap) field.ErrorList {
+	errs := validateDataKeys(nil, configMap.BinaryData, field.NewPath("binaryData"))
+	for key := range configMap.Data {
+		path := field.NewPath("data").Key(key)
+		for _, msg := range validation.IsConfigMapKey(key) {
+			errs = append(errs, field.Invalid(path, key, msg))
+		}
+		if _, exists := configMap.BinaryData[key]; exists {
+			errs = append(errs, field.Invalid(path, key, "duplicate of key present in binaryData"))
+		}
+	}
+	return errs
+}
+
+func validateDataKeys(data map[string][]byte, binaryData map[string][]byte, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, values := range []map[string][]byte{data, binaryData} {
+		for key := range values {
+			for _, msg := range validation.IsConfigMapKey(key) {
+				errs = append(errs, field.Invalid(path.Key(key), key, msg))
+			}
+		}
+	}
+	return errs
+}
+
+func validatePVC(spec *corev1.PersistentVolumeClaimSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(spec.AccessModes) == 0 {
+		errs = append(errs, field.Required(path.Child("accessModes"), "at least 1 access mode is required"))
+	}
+	for i, mode := range spec.AccessModes {
+		if !contains(supportedAccessModes, string(mode)) {
+			errs = append(errs, field.NotSupported(path.Child("accessModes").Index(i), mode, supportedAccessModes))
+		}
+	}
+	storage, ok := spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		errs = append(errs, field.Required(path.Child("resources").Key(string(corev1.ResourceStorage)), ""))
+	} else if storage.Sign() <= 0 {
+		errs = append(errs, field.Invalid(path.Child("resources").Key(string(corev1.ResourceStorage)), storage.String(), "must be greater than zero"))
+	}
+	return errs
+}
+
+// validateLimitRange - 항목별로 min <= defaultRequest <= default <= max, maxLimitRequestRatio >= 1
+func validateLimitRange(spec *corev1.LimitRangeSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(spec.Limits) == 0 {
+		return field.ErrorList{field.Required(path, "at least 1 limit is required")}
+	}
+	for i, item := range spec.Limits {
+		itemPath := path.Index(i)
+		if !contains(limitTypes, string(item.Type)) {
+			errs = append(errs, field.NotSupported(itemPath.Child("type"), item.Type, limitTypes))
+		}
+		if item.Type != corev1.LimitTypeContainer {
+			if len(item.Default) > 0 {
+				errs = append(errs, field.Forbidden(itemPath.Child("default"), "may only be set for type Container"))
+			}
+			if len(item.DefaultRequest) > 0 {
+				errs = append(errs, field.Forbidden(itemPath.Child("defaultRequest"), "may only be set for type Container"))
+			}
+		}
+		if item.Type == corev1.LimitTypePersistentVolumeClaim {
+			_, minStorage := item.Min[corev1.ResourceStorage]
+			_, maxStorage := item.Max[corev1.ResourceStorage]
+			if !minStorage && !maxStorage {
+				errs = append(errs, field.Required(itemPath.Child("limits"), "either minimum or maximum storage value is required, but neither was provided"))
+			}
+		}
+
+		lists := []struct {
+			name  string
+			value corev1.ResourceList
+		}{
+			{"min", item.Min}, {"defaultRequest", item.DefaultRequest}, {"default", item.Default}, {"max", item.Max},
+		}
+		for _, list := range lists {
+			for name, value := range list.value {
+				if value.Sign() < 0 {
+					errs = append(errs, field.Invalid(itemPath.Child(list.name).Key(string(name)), value.String(), "must be greater than or equal to 0"))
+				}
+			}
+		}
+		// 앞의 값이 뒤의 값보다 클 수 없음 (min <= defaultRequest <= default <= max)
+		for lower := 0; lower < len(lists); lower++ {
+			for upper := lower + 1; upper < len(lists); upper++ {
+				for name, low := range lists[lower].value {
+					high, ok := lists[upper].value[name]
+					if ok && low.Cmp(high) > 0 {
+						errs = append(errs, field.Invalid(itemPath.Child(lists[lower].name).Key(string(name)), low.String(),
+							fmt.Sprintf("%s value %s must be less than or equal to %s value %s", lists[lower].name, low.String(), lists[upper].name, high.String())))
+					}
+				}
+			}
+		}
+		for name, ratio := range item.MaxLimitRequestRatio {
+			ratioPath := itemPath.Child("maxLimitRequestRatio").Key(string(name))
+			if ratio.Cmp(resource.MustParse("1")) < 0 {
+				errs = append(errs, field.Invalid(ratioPath, ratio.String(), "ratio must be greater than or equal to 1"))
+			}
+			minimum, hasMin := item.Min[name]
+			maximum, hasMax := item.Max[name]
+			if hasMin && hasMax && minimum.Sign() > 0 && ratio.AsApproximateFloat64() > maximum.AsApproximateFloat64()/minimum.AsApproximateFloat64() {
+				errs = append(errs, field.Invalid(ratioPath, ratio.String(), fmt.Sprintf("ratio %s is greater than max/min = %f", ratio.String(), maximum.AsApproximateFloat64()/minimum.AsApproximateFloat64())))
+			}
+		}
+	}
+	return errs
+}
+
+func validateJobSpec(spec *batchv1.JobSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for name, value := range map[string]*int32{"parallelism": spec.Parallelism, "completions": spec.Completions, "backoffLimit": spec.BackoffLimit} {
+		if value != nil {
+			errs = append(errs, apivalidation.ValidateNonnegativeField(int64(*value), path.Child(name))...)
+		}
+	}
+	if spec.ActiveDeadlineSeconds != nil && *spec.ActiveDeadlineSeconds <= 0 {
+		errs = append(errs, field.Invalid(path.Child("activeDeadlineSeconds"), *spec.ActiveDeadlineSeconds, "must be greater than 0"))
+	}
+	// 셀렉터는 Job 컨트롤러가 생성하므로 직접 지정한 경우에만 템플릿과 비교
+	if spec.Selector != nil {
+		errs = append(errs, validateSelector(spec.Selector, &spec.Template, path)...)
+	}
+	errs = append(errs, metavalidation.ValidateLabels(spec.Template.Labels, path.Child("template", "metadata", "labels"))...)
+	errs = append(errs, validatePodSpec(&spec.Template.Spec, path.Child("template", "spec"), jobRestartPolicies)...)
+	return errs
+}
+
+func validateCronJob(spec *batchv1.CronJobSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	schedule := strings.TrimSpace(spec.Schedule)
+	switch {
+	case schedule == "":
+		errs = append(errs, field.Required(path.Child("schedule"), ""))
+	case strings.HasPrefix(schedule, "@"):
+		if !cronScheduleMacros.Has(schedule) && !strings.HasPrefix(schedule, "@every ") {
+			errs = append(errs, field.Invalid(path.Child("schedule"), spec.Schedule, "unrecognized descriptor"))
+		}
+	case len(strings.Fields(schedule)) != 5:
+		errs = append(errs, field.Invalid(path.Child("schedule"), spec.Schedule, "expected exactly 5 fields (minute hour day-of-month month day-of-week)"))
+	}
+	if !contains(concurrencyPolicies, string(spec.ConcurrencyPolicy)) {
+		errs = append(errs, field.NotSupported(path.Child("concurrencyPolicy"), spec.ConcurrencyPolicy, concurrencyPolicies))
+	}
+	errs = append(errs, validateJobSpec(&spec.JobTemplate.Spec, path.Child("jobTemplate", "spec"))...)
+	return errs
+}
+
+func validateDNSLabel(name string, path *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(name) {
+		errs = append(errs, field.Invalid(path, name, msg))
+	}
+	return errs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
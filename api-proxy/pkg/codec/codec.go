@@ -0,0 +1,167 @@
+// Package codec - kubectl 요청/응답의 Content-Type 협상과 변환
+//
+// 컨트랙트 경로의 요청 표현은 항상 JSON입니다. kubectl이 보내는 YAML/Protobuf 본문은
+// JSON으로 변환하여 제출하고, 응답은 Object(가능하면 Protobuf)로 보관했다가
+// 클라이언트의 Accept 헤더에 맞춰 내보낼 때 변환합니다.
+package codec
+
+import (
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// 지원하는 미디어 타입
+const (
+	MediaTypeJSON     = "application/json"
+	MediaTypeYAML     = "application/yaml"
+	MediaTypeProtobuf = "application/vnd.kubernetes.protobuf"
+)
+
+var (
+	jsonSerializer     = json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{})
+	protobufSerializer = protobuf.NewSerializer(scheme.Scheme, scheme.Scheme)
+)
+
+// MediaType - Content-Type 헤더에서 지원 미디어 타입 추출 (빈 값은 JSON)
+func MediaType(contentType string) (string, error) {
+	if contentType == "" {
+		return MediaTypeJSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q: %v", contentType, err)
+	}
+	switch mediaType {
+	case MediaTypeJSON, MediaTypeYAML, MediaTypeProtobuf:
+		return mediaType, nil
+	case "application/x-yaml", "text/yaml":
+		return MediaTypeYAML, nil
+	case "application/merge-patch+json", "application/strategic-merge-patch+json", "application/json-patch+json", "application/apply-patch+yaml":
+		// 패치 본문은 그대로 전달 (apply-patch는 YAML이지만 서버가 해석)
+		return mediaType, nil
+	}
+	return "", fmt.Errorf("unsupported content type %q", mediaType)
+}
+
+// ToJSON - 요청 본문을 내부 JSON 표현으로 변환
+func ToJSON(contentType string, body []byte) ([]byte, error) {
+	mediaType, err := MediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return body, nil
+	}
+
+	switch mediaType {
+	case MediaTypeYAML:
+		converted, err := yaml.YAMLToJSON(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML body: %v", err)
+		}
+		return converted, nil
+	case MediaTypeProtobuf:
+		obj, _, err := protobufSerializer.Decode(body, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protobuf body: %v", err)
+		}
+		return runtime.Encode(jsonSerializer, obj)
+	default:
+		return body, nil
+	}
+}
+
+// Negotiate - Accept 헤더에서 선호 순으로 응답 미디어 타입 목록 반환 (항상 JSON 포함)
+func Negotiate(accept string) []string {
+	type candidate struct {
+		mediaType string
+		quality   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		switch mediaType {
+		case "application/x-yaml", "text/yaml":
+			mediaType = MediaTypeYAML
+		case "*/*", "application/*":
+			mediaType = MediaTypeJSON
+		}
+		if quality <= 0 || (mediaType != MediaTypeJSON && mediaType != MediaTypeYAML && mediaType != MediaTypeProtobuf) {
+			continue
+		}
+
+		// 같은 q 값은 헤더 순서 유지 (안정 삽입)
+		i := len(candidates)
+		for i > 0 && candidates[i-1].quality < quality {
+			i--
+		}
+		candidates = append(candidates, candidate{})
+		copy(candidates[i+1:], candidates[i:])
+		candidates[i] = candidate{mediaType, quality}
+	}
+
+	var mediaTypes []string
+	for _, c := range candidates {
+		if !containsMediaType(mediaTypes, c.mediaType) {
+			mediaTypes = append(mediaTypes, c.mediaType)
+		}
+	}
+	if !containsMediaType(mediaTypes, MediaTypeJSON) {
+		mediaTypes = append(mediaTypes, MediaTypeJSON)
+	}
+	return mediaTypes
+}
+
+// Encode - 내부 JSON 응답을 Accept 헤더에 맞게 인코딩하고 실제 Content-Type 반환
+// Protobuf는 스킴에 등록된 타입만 가능하므로 실패 시 다음 후보로 넘어감
+func Encode(accept string, body []byte) ([]byte, string) {
+	for _, mediaType := range Negotiate(accept) {
+		switch mediaType {
+		case MediaTypeYAML:
+			converted, err := yaml.JSONToYAML(body)
+			if err == nil {
+				return converted, MediaTypeYAML
+			}
+		case MediaTypeProtobuf:
+			obj, _, err := jsonSerializer.Decode(body, nil, nil)
+			if err != nil {
+				continue
+			}
+			converted, err := runtime.Encode(protobufSerializer, obj)
+			if err == nil {
+				return converted, MediaTypeProtobuf
+			}
+		default:
+			return body, MediaTypeJSON
+		}
+	}
+	return body, MediaTypeJSON
+}
+
+func containsMediaType(mediaTypes []string, target string) bool {
+	for _, mediaType := range mediaTypes {
+		if mediaType == target {
+			return true
+		}
+	}
+	return false
+}
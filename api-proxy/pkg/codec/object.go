@@ -0,0 +1,129 @@
+package codec
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// AcceptStorage - 마스터에 요청할 때의 Accept 헤더
+// 내장 타입은 Protobuf, CRD처럼 Protobuf가 없는 타입은 API 서버가 JSON으로 응답
+const AcceptStorage = MediaTypeProtobuf + ", " + MediaTypeJSON
+
+/*
+Object - 게이트웨이가 보관하는 K8s 응답 본문
+
+스킴에 등록된 타입은 Protobuf 인코딩으로 보관하여 JSON보다 작은 메모리를 쓰고,
+Protobuf를 요청하는 클라이언트(client-go 기반 컨트롤러 등)에는 변환 없이 그대로 전달합니다.
+JSON/YAML 변환은 응답을 내보내는 시점에 필요한 경우에만 수행합니다.
+스킴에 없는 타입(CRD)이나 K8s 객체가 아닌 본문은 받은 그대로 보관합니다.
+*/
+type Object struct {
+	mediaType string // MediaTypeProtobuf, MediaTypeJSON 또는 원본 Content-Type
+	data      []byte
+}
+
+// NewObject - 응답 본문을 보관 형식으로 변환 (JSON 본문은 가능하면 Protobuf로)
+func NewObject(contentType string, body []byte) *Object {
+	mediaType, err := MediaType(contentType)
+	if err != nil || (mediaType != MediaTypeJSON && mediaType != MediaTypeProtobuf) {
+		return &Object{mediaType: contentType, data: body}
+	}
+	if mediaType == MediaTypeProtobuf || len(body) == 0 {
+		return &Object{mediaType: mediaType, data: body}
+	}
+
+	obj, _, err := jsonSerializer.Decode(body, nil, nil)
+	if err != nil {
+		return &Object{mediaType: MediaTypeJSON, data: body}
+	}
+	encoded, err := runtime.Encode(protobufSerializer, obj)
+	if err != nil {
+		return &Object{mediaType: MediaTypeJSON, data: body}
+	}
+	return &Object{mediaType: MediaTypeProtobuf, data: encoded}
+}
+
+// NewJSONObject - 내부에서 만든 JSON 본문 보관
+func NewJSONObject(body []byte) *Object {
+	return NewObject(MediaTypeJSON, body)
+}
+
+// MediaType - 보관 형식
+func (o *Object) MediaType() string {
+	return o.mediaType
+}
+
+// Size - 보관 중인 바이트 수
+func (o *Object) Size() int {
+	return len(o.data)
+}
+
+// IsKubernetes - JSON 또는 Protobuf로 표현된 K8s 객체인지 여부
+func (o *Object) IsKubernetes() bool {
+	return o.mediaType == MediaTypeJSON || o.mediaType == MediaTypeProtobuf
+}
+
+// JSON - JSON 표현 (Protobuf 보관 시 이 시점에 변환)
+func (o *Object) JSON() ([]byte, error) {
+	if o.mediaType != MediaTypeProtobuf || len(o.data) == 0 {
+		return o.data, nil
+	}
+	obj, _, err := protobufSerializer.Decode(o.data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.Encode(jsonSerializer, obj)
+}
+
+/*
+Encode - Accept 헤더에 맞춰 인코딩하고 실제 Content-Type 반환
+Protobuf로 보관된 객체를 Protobuf 클라이언트에 보낼 때는 변환하지 않습니다.
+K8s 객체가 아닌 본문은 원본 그대로 반환합니다.
+*/
+func (o *Object) Encode(accept string) ([]byte, string) {
+	if !o.IsKubernetes() {
+		return o.data, o.mediaType
+	}
+	if o.mediaType == MediaTypeJSON {
+		return Encode(accept, o.data)
+	}
+
+	for _, mediaType := range Negotiate(accept) {
+		if mediaType == MediaTypeProtobuf {
+			return o.data, MediaTypeProtobuf
+		}
+
+		body, err := o.JSON()
+		if err != nil {
+			continue
+		}
+		if mediaType == MediaTypeYAML {
+			if converted, err := yaml.JSONToYAML(body); err == nil {
+				return converted, MediaTypeYAML
+			}
+			continue
+		}
+		return body, MediaTypeJSON
+	}
+	return o.data, MediaTypeProtobuf
+}
+
+// MarshalJSON - 응답을 JSON으로 직렬화할 때 (로그, 캐시 덤프) JSON 표현 사용
+func (o *Object) MarshalJSON() ([]byte, error) {
+	if !o.IsKubernetes() {
+		return json.Marshal(string(o.data))
+	}
+	body, err := o.JSON()
+	if err != nil || len(body) == 0 {
+		return []byte("null"), err
+	}
+	return body, nil
+}
+
+// UnmarshalJSON - JSON으로 전달된 본문 보관 (Protobuf 변환 포함)
+func (o *Object) UnmarshalJSON(data []byte) error {
+	*o = *NewJSONObject(append([]byte(nil), data...))
+	return nil
+}
@@ -0,0 +1,374 @@
+// Package printers - kubectl get 서버 측 출력 (meta.k8s.io/v1 Table)
+//
+// kubectl은 `Accept: application/json;as=Table;v=v1;g=meta.k8s.io`로 요청하고, 서버가 리소스별
+// 컬럼 정의와 행을 내려주면 그대로 출력합니다. 컬럼 구성은 kube-apiserver 기본 출력을 따릅니다.
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// printer - 리소스 종류별 컬럼 정의와 행 생성
+type printer struct {
+	columns []metav1.TableColumnDefinition
+	cells   func(raw []byte, now time.Time) ([]interface{}, error)
+}
+
+var (
+	nameColumn = metav1.TableColumnDefinition{Name: "Name", Type: "string", Format: "name", Description: "Name must be unique within a namespace."}
+	ageColumn  = metav1.TableColumnDefinition{Name: "Age", Type: "string", Description: "Time since the object was created."}
+)
+
+// printers - kind별 출력 정의 (미등록 kind는 NAME/AGE 기본 출력)
+var printers = map[string]printer{
+	"Pod": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Ready", Type: "string", Description: "Ready containers / total containers."},
+			{Name: "Status", Type: "string", Description: "Aggregate status of the containers in this pod."},
+			{Name: "Restarts", Type: "integer", Description: "Number of container restarts."},
+			ageColumn,
+		},
+		cells: podCells,
+	},
+	"Service": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Type", Type: "string", Description: "Type of the service."},
+			{Name: "Cluster-IP", Type: "string", Description: "Internal cluster IP of the service."},
+			{Name: "External-IP", Type: "string", Description: "External IPs of the service."},
+			{Name: "Port(s)", Type: "string", Description: "Ports exposed by the service."},
+			ageColumn,
+		},
+		cells: serviceCells,
+	},
+	"Deployment": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Ready", Type: "string", Description: "Ready replicas / desired replicas."},
+			{Name: "Up-to-date", Type: "integer", Description: "Replicas updated to the latest template."},
+			{Name: "Available", Type: "integer", Description: "Replicas available to serve traffic."},
+			ageColumn,
+		},
+		cells: deploymentCells,
+	},
+	"StatefulSet": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Ready", Type: "string", Description: "Ready replicas / desired replicas."},
+			ageColumn,
+		},
+		cells: statefulSetCells,
+	},
+	"PersistentVolumeClaim": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Status", Type: "string", Description: "Phase of the claim."},
+			{Name: "Volume", Type: "string", Description: "Bound persistent volume."},
+			{Name: "Capacity", Type: "string", Description: "Actual capacity of the bound volume."},
+			{Name: "Access Modes", Type: "string", Description: "Access modes of the bound volume."},
+			{Name: "StorageClass", Type: "string", Description: "Storage class of the claim."},
+			ageColumn,
+		},
+		cells: pvcCells,
+	},
+	"Node": {
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Status", Type: "string", Description: "Readiness and scheduling status of the node."},
+			{Name: "Roles", Type: "string", Description: "Roles from node-role.kubernetes.io labels."},
+			ageColumn,
+			{Name: "Version", Type: "string", Description: "Kubelet version."},
+		},
+		cells: nodeCells,
+	},
+}
+
+// defaultPrinter - 알 수 없는 kind의 NAME/AGE 출력
+var defaultPrinter = printer{
+	columns: []metav1.TableColumnDefinition{nameColumn, ageColumn},
+	cells: func(raw []byte, now time.Time) ([]interface{}, error) {
+		var obj metav1.PartialObjectMetadata
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		return []interface{}{obj.Name, age(obj.CreationTimestamp, now)}, nil
+	},
+}
+
+// WantsTable - Accept 헤더가 meta.k8s.io Table 형식을 요청하는지 확인
+func WantsTable(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if params["as"] == "Table" && (params["g"] == "" || params["g"] == "meta.k8s.io") {
+			return true
+		}
+	}
+	return false
+}
+
+// ToTable - 단일 객체 또는 *List JSON을 Table JSON으로 변환
+func ToTable(body []byte, now time.Time) ([]byte, error) {
+	var envelope struct {
+		Kind     string            `json:"kind"`
+		Metadata metav1.ListMeta   `json:"metadata"`
+		Items    []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("response is not a Kubernetes object: %v", err)
+	}
+	if envelope.Kind == "" || envelope.Kind == "Status" || envelope.Kind == "Table" {
+		return nil, fmt.Errorf("kind %q cannot be printed as a table", envelope.Kind)
+	}
+
+	kind := envelope.Kind
+	items := []json.RawMessage{body}
+	if strings.HasSuffix(kind, "List") {
+		kind = strings.TrimSuffix(kind, "List")
+		items = envelope.Items
+	}
+
+	p, ok := printers[kind]
+	if !ok {
+		p = defaultPrinter
+	}
+
+	table := metav1.Table{
+		TypeMeta:          metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+		ColumnDefinitions: p.columns,
+		Rows:              make([]metav1.TableRow, 0, len(items)),
+	}
+	if strings.HasSuffix(envelope.Kind, "List") {
+		table.ResourceVersion = envelope.Metadata.ResourceVersion
+		table.Continue = envelope.Metadata.Continue
+		table.RemainingItemCount = envelope.Metadata.RemainingItemCount
+	}
+
+	for _, raw := range items {
+		cells, err := p.cells(raw, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to print %s: %v", kind, err)
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Raw: raw},
+		})
+	}
+	return json.Marshal(&table)
+}
+
+// age - 생성 시각 기준 경과 시간 (kubectl 표기)
+func age(created metav1.Time, now time.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(created.Time))
+}
+
+func podCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var pod corev1.Pod
+	if err := json.Unmarshal(raw, &pod); err != nil {
+		return nil, err
+	}
+
+	total := len(pod.Spec.Containers)
+	ready := 0
+	restarts := int32(0)
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			reason = status.State.Waiting.Reason
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			reason = status.State.Terminated.Reason
+		case status.Ready && status.State.Running != nil:
+			ready++
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		reason = "Terminating"
+	}
+	if reason == "" {
+		reason = "Pending"
+	}
+
+	return []interface{}{pod.Name, fmt.Sprintf("%d/%d", ready, total), reason, int64(restarts), age(pod.CreationTimestamp, now)}, nil
+}
+
+func serviceCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var svc corev1.Service
+	if err := json.Unmarshal(raw, &svc); err != nil {
+		return nil, err
+	}
+
+	svcType := svc.Spec.Type
+	if svcType == "" {
+		svcType = corev1.ServiceTypeClusterIP
+	}
+	clusterIP := svc.Spec.ClusterIP
+	if clusterIP == "" {
+		clusterIP = "<none>"
+	}
+
+	var external []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			external = append(external, ingress.IP)
+		} else if ingress.Hostname != "" {
+			external = append(external, ingress.Hostname)
+		}
+	}
+	external = append(external, svc.Spec.ExternalIPs...)
+	externalIP := strings.Join(external, ",")
+	switch {
+	case externalIP != "":
+	case svcType == corev1.ServiceTypeLoadBalancer:
+		externalIP = "<pending>"
+	case svcType == corev1.ServiceTypeExternalName:
+		externalIP = svc.Spec.ExternalName
+	default:
+		externalIP = "<none>"
+	}
+
+	var ports []string
+	for _, port := range svc.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		if port.NodePort != 0 {
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, protocol))
+		}
+	}
+	portList := strings.Join(ports, ",")
+	if portList == "" {
+		portList = "<none>"
+	}
+
+	return []interface{}{svc.Name, string(svcType), clusterIP, externalIP, portList, age(svc.CreationTimestamp, now)}, nil
+}
+
+func deploymentCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var deployment appsv1.Deployment
+	if err := json.Unmarshal(raw, &deployment); err != nil {
+		return nil, err
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return []interface{}{
+		deployment.Name,
+		fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, desired),
+		int64(deployment.Status.UpdatedReplicas),
+		int64(deployment.Status.AvailableReplicas),
+		age(deployment.CreationTimestamp, now),
+	}, nil
+}
+
+func statefulSetCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var sts appsv1.StatefulSet
+	if err := json.Unmarshal(raw, &sts); err != nil {
+		return nil, err
+	}
+
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	return []interface{}{sts.Name, fmt.Sprintf("%d/%d", sts.Status.ReadyReplicas, desired), age(sts.CreationTimestamp, now)}, nil
+}
+
+func pvcCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var pvc corev1.PersistentVolumeClaim
+	if err := json.Unmarshal(raw, &pvc); err != nil {
+		return nil, err
+	}
+
+	phase := string(pvc.Status.Phase)
+	if pvc.DeletionTimestamp != nil {
+		phase = "Terminating"
+	}
+
+	capacity := ""
+	if storage, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		capacity = storage.String()
+	}
+
+	var modes []string
+	for _, mode := range pvc.Status.AccessModes {
+		switch mode {
+		case corev1.ReadWriteOnce:
+			modes = append(modes, "RWO")
+		case corev1.ReadOnlyMany:
+			modes = append(modes, "ROX")
+		case corev1.ReadWriteMany:
+			modes = append(modes, "RWX")
+		case corev1.ReadWriteOncePod:
+			modes = append(modes, "RWOP")
+		}
+	}
+
+	storageClass := "<unset>"
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+
+	return []interface{}{pvc.Name, phase, pvc.Spec.VolumeName, capacity, strings.Join(modes, ","), storageClass, age(pvc.CreationTimestamp, now)}, nil
+}
+
+func nodeCells(raw []byte, now time.Time) ([]interface{}, error) {
+	var node corev1.Node
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+
+	status := "Unknown"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			if condition.Status == corev1.ConditionTrue {
+				status = "Ready"
+			} else {
+				status = "NotReady"
+			}
+		}
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+
+	var roles []string
+	for label := range node.Labels {
+		if role := strings.TrimPrefix(label, "node-role.kubernetes.io/"); role != label && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	roleList := strings.Join(roles, ",")
+	if roleList == "" {
+		roleList = "<none>"
+	}
+
+	return []interface{}{node.Name, status, roleList, age(node.CreationTimestamp, now), node.Status.NodeInfo.KubeletVersion}, nil
+}
@@ -3,6 +3,8 @@ package types
 import (
 	"encoding/json"
 	"time"
+
+	apirequest "github.com/k3s-io/daas-apirequest"
 )
 
 // SuiTransactionResult - 통일된 Sui 트랜잭션 결과
@@ -46,19 +48,8 @@ type ContractEvent struct {
 	Timestamp time.Time `json:"timestampMs"`
 }
 
-// EventData - K8s API 요청 이벤트 데이터
-type EventData struct {
-	RequestID    string `json:"request_id"`
-	Method       string `json:"method"`
-	Path         string `json:"path"`
-	Namespace    string `json:"namespace"`
-	ResourceType string `json:"resource_type"`
-	Payload      []int  `json:"payload"` // vector<u8> from Move
-	SealToken    string `json:"seal_token"`
-	Requester    string `json:"requester"`
-	Priority     int    `json:"priority"`
-	Timestamp    uint64 `json:"timestamp"`
-}
+// EventData - K8s API 요청 이벤트 데이터 (Gateway/마스터와 공용 스키마, Move 이벤트 필드 그대로)
+type EventData = apirequest.Request
 
 // K8sExecutionResult - K8s 실행 결과
 type K8sExecutionResult struct {
@@ -0,0 +1,138 @@
+// K8s-DaaS Audit Ledger - 마스터의 감사 로그 앵커(해시 체인)와 테넌트 사용량 기록을 순번대로 온체인에 남김
+module k8s_daas::audit_ledger {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::transfer;
+    use sui::event;
+    use std::string::String;
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const ESequenceRecorded: u64 = 2;   // 이미 기록된 순번 (재전송)
+    const ESequenceGap: u64 = 3;        // 앞 순번이 아직 기록되지 않음
+
+    // ==================== Structs ====================
+
+    /// 감사/사용량 원장 - 마스터가 로컬 버퍼의 순번과 next_sequence를 맞춰 순서대로 제출
+    public struct AuditLedger has key {
+        id: UID,
+        next_sequence: u64,
+        last_root: String,      // 마지막 감사 앵커의 해시 체인 루트
+        anchors: u64,
+        usage_records: u64,
+        admin: address,
+    }
+
+    /// 감사 로그 앵커 이벤트 - root는 이전 루트와 이번 구간 이벤트를 이어 해시한 값
+    public struct AuditAnchoredEvent has copy, drop {
+        sequence: u64,
+        root: String,
+        event_count: u64,
+        first_event_ms: u64,
+        last_event_ms: u64,
+        timestamp: u64,
+    }
+
+    /// 테넌트 사용량 기록 이벤트 (과금 기준)
+    public struct UsageRecordedEvent has copy, drop {
+        sequence: u64,
+        tenant: String,
+        period_start_ms: u64,
+        period_end_ms: u64,
+        requests: u64,
+        throttled: u64,
+        egress_bytes: u64,      // 테넌트 Pod 송신 바이트 (워커 Pod 네트워크 카운터 집계)
+        ingress_bytes: u64,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 원장 오브젝트 초기화
+    fun init(ctx: &mut TxContext) {
+        let ledger = AuditLedger {
+            id: object::new(ctx),
+            next_sequence: 0,
+            last_root: std::string::utf8(b""),
+            anchors: 0,
+            usage_records: 0,
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(ledger);
+    }
+
+    /// 순번 확인 후 다음 순번으로 진행 (관리자만, 빠짐없이 순서대로)
+    fun advance(ledger: &mut AuditLedger, sequence: u64, ctx: &TxContext) {
+        assert!(tx_context::sender(ctx) == ledger.admin, EUnauthorized);
+        assert!(sequence >= ledger.next_sequence, ESequenceRecorded);
+        assert!(sequence == ledger.next_sequence, ESequenceGap);
+        ledger.next_sequence = sequence + 1;
+    }
+
+    /// 감사 로그 구간 앵커 기록 (마스터 노드에서 호출)
+    public entry fun anchor_audit_batch(
+        ledger: &mut AuditLedger,
+        sequence: u64,
+        root: String,
+        event_count: u64,
+        first_event_ms: u64,
+        last_event_ms: u64,
+        ctx: &mut TxContext
+    ) {
+        advance(ledger, sequence, ctx);
+        ledger.last_root = root;
+        ledger.anchors = ledger.anchors + 1;
+
+        event::emit(AuditAnchoredEvent {
+            sequence,
+            root,
+            event_count,
+            first_event_ms,
+            last_event_ms,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 테넌트 사용량 기록 (마스터 노드에서 호출)
+    public entry fun record_usage(
+        ledger: &mut AuditLedger,
+        sequence: u64,
+        tenant: String,
+        period_start_ms: u64,
+        period_end_ms: u64,
+        requests: u64,
+        throttled: u64,
+        egress_bytes: u64,
+        ingress_bytes: u64,
+        ctx: &mut TxContext
+    ) {
+        advance(ledger, sequence, ctx);
+        ledger.usage_records = ledger.usage_records + 1;
+
+        event::emit(UsageRecordedEvent {
+            sequence,
+            tenant,
+            period_start_ms,
+            period_end_ms,
+            requests,
+            throttled,
+            egress_bytes,
+            ingress_bytes,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 다음에 기록할 순번
+    public fun next_sequence(ledger: &AuditLedger): u64 {
+        ledger.next_sequence
+    }
+
+    /// 마지막 감사 앵커 루트
+    public fun last_root(ledger: &AuditLedger): String {
+        ledger.last_root
+    }
+}
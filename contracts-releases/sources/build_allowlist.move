@@ -0,0 +1,127 @@
+// K8s-DaaS Build Allowlist - 스테이커가 신뢰하는 릴리스 빌드 목록 (워커가 마스터 빌드 출처를 대조)
+module k8s_daas::build_allowlist {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::table::{Self, Table};
+    use sui::transfer;
+    use sui::event;
+    use std::string::{Self, String};
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EInvalidDigest: u64 = 2;
+    const EBuildExists: u64 = 3;
+    const EBuildNotFound: u64 = 4;
+    const EEmptyCommit: u64 = 5;
+
+    // ==================== Constants ====================
+
+    const DIGEST_LENGTH: u64 = 71;              // "sha256:" + 64 hex
+
+    // ==================== Structs ====================
+
+    /// 허용된 빌드 - 바이너리에 기록된 출처(커밋, 빌더, SBOM 다이제스트)
+    public struct AllowedBuild has store, drop {
+        component: String,        // master, worker, gateway, listener
+        version: String,          // 릴리스 버전 (예: v1.4.0)
+        commit: String,           // 소스 커밋
+        builder: String,          // 빌드 주체 (CI 실행 ID 등)
+        sbom_digest: String,      // 모듈 목록(SBOM) sha256
+        allowed_at: u64,
+    }
+
+    /// 빌드 허용 목록
+    public struct BuildAllowlist has key {
+        id: UID,
+        builds: Table<String, AllowedBuild>,  // sbom_digest -> build
+        admin: address,
+    }
+
+    /// 빌드 허용 이벤트
+    public struct BuildAllowedEvent has copy, drop {
+        component: String,
+        version: String,
+        commit: String,
+        builder: String,
+        sbom_digest: String,
+        timestamp: u64,
+    }
+
+    /// 빌드 취소 이벤트 - 워커는 다음 확인 때 해당 빌드의 마스터를 거부
+    public struct BuildRevokedEvent has copy, drop {
+        sbom_digest: String,
+        reason: String,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 허용 목록 초기화 (배포자가 관리자)
+    fun init(ctx: &mut TxContext) {
+        let allowlist = BuildAllowlist {
+            id: object::new(ctx),
+            builds: table::new(ctx),
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(allowlist);
+    }
+
+    /// 릴리스 빌드 허용 (관리자만)
+    public entry fun allow_build(
+        allowlist: &mut BuildAllowlist,
+        component: String,
+        version: String,
+        commit: String,
+        builder: String,
+        sbom_digest: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == allowlist.admin, EUnauthorized);
+        assert!(string::length(&sbom_digest) == DIGEST_LENGTH, EInvalidDigest);
+        assert!(!string::is_empty(&commit), EEmptyCommit);
+        assert!(!table::contains(&allowlist.builds, sbom_digest), EBuildExists);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        table::add(&mut allowlist.builds, sbom_digest, AllowedBuild {
+            component,
+            version,
+            commit,
+            builder,
+            sbom_digest,
+            allowed_at: now,
+        });
+
+        event::emit(BuildAllowedEvent { component, version, commit, builder, sbom_digest, timestamp: now });
+    }
+
+    /// 빌드 허용 취소 (관리자만, 취약점이 발견된 릴리스 등)
+    public entry fun revoke_build(
+        allowlist: &mut BuildAllowlist,
+        sbom_digest: String,
+        reason: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == allowlist.admin, EUnauthorized);
+        assert!(table::contains(&allowlist.builds, sbom_digest), EBuildNotFound);
+
+        table::remove(&mut allowlist.builds, sbom_digest);
+
+        event::emit(BuildRevokedEvent { sbom_digest, reason, timestamp: tx_context::epoch_timestamp_ms(ctx) });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 빌드 허용 여부
+    public fun is_allowed(allowlist: &BuildAllowlist, sbom_digest: String): bool {
+        table::contains(&allowlist.builds, sbom_digest)
+    }
+
+    /// 허용된 빌드의 커밋과 빌더 조회
+    public fun get_build(allowlist: &BuildAllowlist, sbom_digest: String): (String, String, String) {
+        assert!(table::contains(&allowlist.builds, sbom_digest), EBuildNotFound);
+        let build = table::borrow(&allowlist.builds, sbom_digest);
+        (build.component, build.commit, build.builder)
+    }
+}
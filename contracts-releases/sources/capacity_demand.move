@@ -0,0 +1,106 @@
+// K8s-DaaS Capacity Demand - 스케줄 불가 Pod 수요를 온체인에 공시하여 스테이커 모집
+module k8s_daas::capacity_demand {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::transfer;
+    use sui::event;
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EInvalidMultiplier: u64 = 2;
+
+    // ==================== Constants ====================
+
+    const BASE_MULTIPLIER_BPS: u64 = 10000;     // 1.0x 보상
+    const MAX_MULTIPLIER_BPS: u64 = 50000;      // 최대 5.0x 보상
+
+    // ==================== Structs ====================
+
+    /// 용량 수요 공시 오브젝트 - 예비 스테이커가 구독
+    public struct CapacityDemand has key {
+        id: UID,
+        pending_pods: u64,
+        pending_cpu_millis: u64,
+        pending_memory_mb: u64,
+        active_workers: u64,
+        reward_multiplier_bps: u64,
+        updated_at: u64,
+        admin: address,
+    }
+
+    /// 용량 수요 변경 이벤트
+    public struct CapacityDemandUpdatedEvent has copy, drop {
+        pending_pods: u64,
+        pending_cpu_millis: u64,
+        pending_memory_mb: u64,
+        active_workers: u64,
+        reward_multiplier_bps: u64,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 수요 오브젝트 초기화
+    fun init(ctx: &mut TxContext) {
+        let demand = CapacityDemand {
+            id: object::new(ctx),
+            pending_pods: 0,
+            pending_cpu_millis: 0,
+            pending_memory_mb: 0,
+            active_workers: 0,
+            reward_multiplier_bps: BASE_MULTIPLIER_BPS,
+            updated_at: 0,
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(demand);
+    }
+
+    /// 용량 수요 공시 (마스터 노드에서 호출)
+    public fun publish_capacity_demand(
+        demand: &mut CapacityDemand,
+        pending_pods: u64,
+        pending_cpu_millis: u64,
+        pending_memory_mb: u64,
+        active_workers: u64,
+        reward_multiplier_bps: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == demand.admin, EUnauthorized);
+        assert!(
+            reward_multiplier_bps >= BASE_MULTIPLIER_BPS && reward_multiplier_bps <= MAX_MULTIPLIER_BPS,
+            EInvalidMultiplier
+        );
+
+        let timestamp = tx_context::epoch_timestamp_ms(ctx);
+
+        demand.pending_pods = pending_pods;
+        demand.pending_cpu_millis = pending_cpu_millis;
+        demand.pending_memory_mb = pending_memory_mb;
+        demand.active_workers = active_workers;
+        demand.reward_multiplier_bps = reward_multiplier_bps;
+        demand.updated_at = timestamp;
+
+        event::emit(CapacityDemandUpdatedEvent {
+            pending_pods,
+            pending_cpu_millis,
+            pending_memory_mb,
+            active_workers,
+            reward_multiplier_bps,
+            timestamp,
+        });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 현재 수요 조회 (pending_pods, cpu, memory, multiplier)
+    public fun get_demand(demand: &CapacityDemand): (u64, u64, u64, u64) {
+        (demand.pending_pods, demand.pending_cpu_millis, demand.pending_memory_mb, demand.reward_multiplier_bps)
+    }
+
+    /// 추가 용량이 필요한지 확인
+    public fun is_capacity_wanted(demand: &CapacityDemand): bool {
+        demand.pending_pods > 0
+    }
+}
@@ -0,0 +1,316 @@
+// K8s-DaaS Capacity Reservations - 테넌트가 기간 한정 CPU/메모리 예약을 구매하고 마스터가 확정하면 스케줄러가 용량을 보류
+module k8s_daas::capacity_reservations {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::table::{Self, Table};
+    use sui::coin::{Self, Coin};
+    use sui::balance::{Self, Balance};
+    use sui::sui::SUI;
+    use sui::transfer;
+    use sui::event;
+    use std::string::String;
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EInvalidWindow: u64 = 2;
+    const EWindowTooLong: u64 = 3;
+    const EEmptyReservation: u64 = 4;
+    const EInsufficientPayment: u64 = 5;
+    const ENoReservation: u64 = 6;
+    const ENotPending: u64 = 7;
+    const EInsufficientEarnings: u64 = 8;
+
+    // ==================== Constants ====================
+
+    const MIN_WINDOW_MS: u64 = 3600000;         // 최소 1시간
+    const MAX_WINDOW_MS: u64 = 7776000000;      // 최대 90일
+    const HOUR_MS: u128 = 3600000;
+
+    // 예약 상태 (pending → confirmed | rejected | cancelled)
+    const STATUS_PENDING: u8 = 0;
+    const STATUS_CONFIRMED: u8 = 1;
+    const STATUS_REJECTED: u8 = 2;
+    const STATUS_CANCELLED: u8 = 3;
+
+    // ==================== Structs ====================
+
+    /// 용량 예약
+    public struct Reservation has store {
+        tenant: address,
+        namespace: String,
+        cpu_millis: u64,
+        memory_mb: u64,
+        start_ms: u64,
+        end_ms: u64,
+        lendable: bool,             // 쓰지 않는 예약 용량을 best-effort Pod에 빌려줄지
+        paid: u64,
+        status: u8,
+    }
+
+    /// 예약 장부 - 단가와 대금 보관 (확정 전 대금은 escrow, 확정 후 earnings)
+    public struct ReservationBook has key {
+        id: UID,
+        reservations: Table<u64, Reservation>,
+        next_id: u64,
+        cpu_core_hour_mist: u64,    // CPU 1코어(1000m) 시간당
+        memory_gib_hour_mist: u64,  // 메모리 1GiB 시간당
+        escrow: Balance<SUI>,
+        earnings: Balance<SUI>,
+        admin: address,
+    }
+
+    /// 예약 구매 이벤트 - 마스터가 구독하여 네임스페이스 소유와 여유 용량 확인 후 확정/거절
+    public struct ReservationPurchasedEvent has copy, drop {
+        reservation_id: u64,
+        tenant: address,
+        namespace: String,
+        cpu_millis: u64,
+        memory_mb: u64,
+        start_ms: u64,
+        end_ms: u64,
+        lendable: bool,
+        paid: u64,
+        timestamp: u64,
+    }
+
+    /// 예약 확정 이벤트
+    public struct ReservationConfirmedEvent has copy, drop {
+        reservation_id: u64,
+        namespace: String,
+        timestamp: u64,
+    }
+
+    /// 예약 거절 이벤트 (대금 환불)
+    public struct ReservationRejectedEvent has copy, drop {
+        reservation_id: u64,
+        tenant: address,
+        reason: String,
+        refunded: u64,
+        timestamp: u64,
+    }
+
+    /// 예약 취소 이벤트 (확정 전 테넌트 취소, 대금 환불)
+    public struct ReservationCancelledEvent has copy, drop {
+        reservation_id: u64,
+        tenant: address,
+        refunded: u64,
+        timestamp: u64,
+    }
+
+    /// 단가 변경 이벤트
+    public struct ReservationPricingUpdatedEvent has copy, drop {
+        cpu_core_hour_mist: u64,
+        memory_gib_hour_mist: u64,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 예약 장부 초기화 (단가 0, 배포자가 관리자 - 마스터 주소로 넘겨야 확정/거절 가능)
+    fun init(ctx: &mut TxContext) {
+        let book = ReservationBook {
+            id: object::new(ctx),
+            reservations: table::new(ctx),
+            next_id: 1,
+            cpu_core_hour_mist: 0,
+            memory_gib_hour_mist: 0,
+            escrow: balance::zero(),
+            earnings: balance::zero(),
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(book);
+    }
+
+    /// 단가 공시 (관리자만)
+    public entry fun update_reservation_pricing(
+        book: &mut ReservationBook,
+        cpu_core_hour_mist: u64,
+        memory_gib_hour_mist: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == book.admin, EUnauthorized);
+
+        book.cpu_core_hour_mist = cpu_core_hour_mist;
+        book.memory_gib_hour_mist = memory_gib_hour_mist;
+
+        event::emit(ReservationPricingUpdatedEvent {
+            cpu_core_hour_mist,
+            memory_gib_hour_mist,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 관리자 변경 (마스터 키 교체 시)
+    public entry fun transfer_admin(book: &mut ReservationBook, new_admin: address, ctx: &mut TxContext) {
+        assert!(tx_context::sender(ctx) == book.admin, EUnauthorized);
+        book.admin = new_admin;
+    }
+
+    /// 예약 구매 - 대금은 확정/거절까지 escrow에 보관, 남는 금액은 돌려줌
+    public entry fun purchase_reservation(
+        book: &mut ReservationBook,
+        namespace: String,
+        cpu_millis: u64,
+        memory_mb: u64,
+        start_ms: u64,
+        duration_ms: u64,
+        lendable: bool,
+        mut payment: Coin<SUI>,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        assert!(cpu_millis > 0 || memory_mb > 0, EEmptyReservation);
+        assert!(duration_ms >= MIN_WINDOW_MS, EInvalidWindow);
+        assert!(duration_ms <= MAX_WINDOW_MS, EWindowTooLong);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        assert!(start_ms + duration_ms > now, EInvalidWindow);
+
+        let price = quote_reservation(book, cpu_millis, memory_mb, duration_ms);
+        assert!(coin::value(&payment) >= price, EInsufficientPayment);
+        if (coin::value(&payment) > price) {
+            let change = coin::split(&mut payment, coin::value(&payment) - price, ctx);
+            transfer::public_transfer(change, sender);
+        };
+        balance::join(&mut book.escrow, coin::into_balance(payment));
+
+        let reservation_id = book.next_id;
+        book.next_id = reservation_id + 1;
+        let end_ms = start_ms + duration_ms;
+        table::add(&mut book.reservations, reservation_id, Reservation {
+            tenant: sender,
+            namespace,
+            cpu_millis,
+            memory_mb,
+            start_ms,
+            end_ms,
+            lendable,
+            paid: price,
+            status: STATUS_PENDING,
+        });
+
+        event::emit(ReservationPurchasedEvent {
+            reservation_id,
+            tenant: sender,
+            namespace,
+            cpu_millis,
+            memory_mb,
+            start_ms,
+            end_ms,
+            lendable,
+            paid: price,
+            timestamp: now,
+        });
+    }
+
+    /// 예약 확정 (관리자만) - 대금을 earnings로 옮김
+    public entry fun confirm_reservation(book: &mut ReservationBook, reservation_id: u64, ctx: &mut TxContext) {
+        assert!(tx_context::sender(ctx) == book.admin, EUnauthorized);
+        assert!(table::contains(&book.reservations, reservation_id), ENoReservation);
+
+        let reservation = table::borrow_mut(&mut book.reservations, reservation_id);
+        assert!(reservation.status == STATUS_PENDING, ENotPending);
+        reservation.status = STATUS_CONFIRMED;
+        let paid = balance::split(&mut book.escrow, reservation.paid);
+        balance::join(&mut book.earnings, paid);
+
+        event::emit(ReservationConfirmedEvent {
+            reservation_id,
+            namespace: reservation.namespace,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 예약 거절 (관리자만) - 대금 환불
+    public entry fun reject_reservation(
+        book: &mut ReservationBook,
+        reservation_id: u64,
+        reason: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == book.admin, EUnauthorized);
+        let (tenant, refunded) = close_pending(book, reservation_id, STATUS_REJECTED, ctx);
+
+        event::emit(ReservationRejectedEvent {
+            reservation_id,
+            tenant,
+            reason,
+            refunded,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 예약 취소 (구매한 테넌트만, 확정 전에만) - 대금 환불
+    public entry fun cancel_reservation(book: &mut ReservationBook, reservation_id: u64, ctx: &mut TxContext) {
+        assert!(table::contains(&book.reservations, reservation_id), ENoReservation);
+        assert!(table::borrow(&book.reservations, reservation_id).tenant == tx_context::sender(ctx), EUnauthorized);
+        let (tenant, refunded) = close_pending(book, reservation_id, STATUS_CANCELLED, ctx);
+
+        event::emit(ReservationCancelledEvent {
+            reservation_id,
+            tenant,
+            refunded,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 확정된 예약 대금 인출 (관리자만)
+    public entry fun withdraw_earnings(book: &mut ReservationBook, amount: u64, ctx: &mut TxContext) {
+        let sender = tx_context::sender(ctx);
+        assert!(sender == book.admin, EUnauthorized);
+        assert!(balance::value(&book.earnings) >= amount, EInsufficientEarnings);
+
+        transfer::public_transfer(coin::from_balance(balance::split(&mut book.earnings, amount), ctx), sender);
+    }
+
+    // ==================== Internal Functions ====================
+
+    /// 대기 중인 예약을 닫고 escrow에서 환불 (tenant, 환불액)
+    fun close_pending(book: &mut ReservationBook, reservation_id: u64, status: u8, ctx: &mut TxContext): (address, u64) {
+        assert!(table::contains(&book.reservations, reservation_id), ENoReservation);
+
+        let reservation = table::borrow_mut(&mut book.reservations, reservation_id);
+        assert!(reservation.status == STATUS_PENDING, ENotPending);
+        reservation.status = status;
+        let refunded = reservation.paid;
+        let tenant = reservation.tenant;
+        if (refunded > 0) {
+            transfer::public_transfer(coin::from_balance(balance::split(&mut book.escrow, refunded), ctx), tenant);
+        };
+        (tenant, refunded)
+    }
+
+    // ==================== View Functions ====================
+
+    /// 예약 가격 (MIST) - (코어 수 × 코어 단가 + GiB × GiB 단가) × 시간, 올림
+    public fun quote_reservation(book: &ReservationBook, cpu_millis: u64, memory_mb: u64, duration_ms: u64): u64 {
+        let per_hour_milli = (cpu_millis as u128) * (book.cpu_core_hour_mist as u128) * 1024
+            + (memory_mb as u128) * (book.memory_gib_hour_mist as u128) * 1000;
+        let numerator = per_hour_milli * (duration_ms as u128);
+        let denominator = HOUR_MS * 1000 * 1024;
+        (((numerator + denominator - 1) / denominator) as u64)
+    }
+
+    /// 예약 조회 (tenant, namespace, cpu_millis, memory_mb, start_ms, end_ms, lendable, status)
+    public fun get_reservation(book: &ReservationBook, reservation_id: u64): (address, String, u64, u64, u64, u64, bool, u8) {
+        assert!(table::contains(&book.reservations, reservation_id), ENoReservation);
+        let r = table::borrow(&book.reservations, reservation_id);
+        (r.tenant, r.namespace, r.cpu_millis, r.memory_mb, r.start_ms, r.end_ms, r.lendable, r.status)
+    }
+
+    /// 주어진 시각에 확정된 예약이 유효한지 확인
+    public fun is_active(book: &ReservationBook, reservation_id: u64, now_ms: u64): bool {
+        if (!table::contains(&book.reservations, reservation_id)) {
+            return false
+        };
+        let r = table::borrow(&book.reservations, reservation_id);
+        r.status == STATUS_CONFIRMED && now_ms >= r.start_ms && now_ms < r.end_ms
+    }
+
+    /// 현재 단가 (cpu_core_hour_mist, memory_gib_hour_mist)
+    public fun get_pricing(book: &ReservationBook): (u64, u64) {
+        (book.cpu_core_hour_mist, book.memory_gib_hour_mist)
+    }
+}
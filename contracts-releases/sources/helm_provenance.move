@@ -0,0 +1,64 @@
+// K8s-DaaS Helm Provenance - 렌더링된 차트 배포의 출처(차트 다이제스트, values 해시)를 온체인에 기록
+module k8s_daas::helm_provenance {
+    use sui::tx_context::{Self, TxContext};
+    use sui::event;
+    use std::string::{Self, String};
+
+    // ==================== Error Constants ====================
+
+    const EInvalidDigest: u64 = 1;
+    const EEmptyRelease: u64 = 2;
+
+    // ==================== Constants ====================
+
+    const DIGEST_LENGTH: u64 = 71;              // "sha256:" + 64 hex
+
+    // ==================== Structs ====================
+
+    /// 차트 릴리스 기록 이벤트 - 배포된 매니페스트가 어떤 차트와 values에서 나왔는지 추적
+    public struct HelmReleaseRecordedEvent has copy, drop {
+        release: String,           // 릴리스 이름
+        namespace: String,         // 대상 네임스페이스
+        chart_name: String,        // Chart.yaml name
+        chart_version: String,     // Chart.yaml version
+        chart_digest: String,      // 차트 아카이브 sha256
+        values_hash: String,       // 정규화된 values sha256
+        manifest_digest: String,   // 검증/정규화된 매니페스트 sha256
+        object_count: u64,         // 적용 대상 객체 수
+        requester: address,        // 요청자 주소
+        timestamp: u64,            // 타임스탬프
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 차트 릴리스 출처 기록 (Gateway가 렌더링/검증 후 제출)
+    public entry fun record_helm_release(
+        release: String,
+        namespace: String,
+        chart_name: String,
+        chart_version: String,
+        chart_digest: String,
+        values_hash: String,
+        manifest_digest: String,
+        object_count: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(!string::is_empty(&release), EEmptyRelease);
+        assert!(string::length(&chart_digest) == DIGEST_LENGTH, EInvalidDigest);
+        assert!(string::length(&values_hash) == DIGEST_LENGTH, EInvalidDigest);
+        assert!(string::length(&manifest_digest) == DIGEST_LENGTH, EInvalidDigest);
+
+        event::emit(HelmReleaseRecordedEvent {
+            release,
+            namespace,
+            chart_name,
+            chart_version,
+            chart_digest,
+            values_hash,
+            manifest_digest,
+            object_count,
+            requester: tx_context::sender(ctx),
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+}
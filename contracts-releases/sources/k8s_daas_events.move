@@ -169,6 +169,8 @@ module k8s_daas::events {
         resource == &std::string::utf8(b"pods") ||
         resource == &std::string::utf8(b"services") ||
         resource == &std::string::utf8(b"deployments") ||
+        resource == &std::string::utf8(b"statefulsets") ||
+        resource == &std::string::utf8(b"persistentvolumeclaims") ||
         resource == &std::string::utf8(b"configmaps") ||
         resource == &std::string::utf8(b"secrets") ||
         resource == &std::string::utf8(b"namespaces") ||
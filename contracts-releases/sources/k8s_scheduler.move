@@ -16,6 +16,7 @@ module k8s_daas::k8s_scheduler {
     const EWorkerNotActive: u64 = 3;
     const EUnauthorizedRequest: u64 = 4;
     const EInvalidSealToken: u64 = 5;
+    const ERequestExpired: u64 = 6;
 
     // ==================== Structs ====================
 
@@ -35,6 +36,7 @@ module k8s_daas::k8s_scheduler {
         created_at: u64,
         assigned_at: u64,
         completed_at: u64,
+        deadline_ms: u64,        // 클라이언트 마감 시각 (0이면 없음, 마스터는 지난 요청을 실행하지 않음)
     }
 
     /// 스케줄러 상태
@@ -60,6 +62,7 @@ module k8s_daas::k8s_scheduler {
         priority: u8,
         assigned_worker: String,
         timestamp: u64,
+        deadline_ms: u64,
     }
 
     /// K8s API 실행 결과 이벤트
@@ -97,7 +100,7 @@ module k8s_daas::k8s_scheduler {
         transfer::share_object(scheduler);
     }
 
-    /// K8s API 요청 제출 및 워커 할당
+    /// K8s API 요청 제출 및 워커 할당 (deadline_ms: 클라이언트 마감 시각, 0이면 없음)
     public fun submit_k8s_request(
         scheduler: &mut K8sScheduler,
         registry: &WorkerRegistry,
@@ -109,11 +112,152 @@ module k8s_daas::k8s_scheduler {
         payload: String,
         seal_token: String,
         priority: u8,
+        deadline_ms: u64,
         ctx: &mut TxContext
     ) {
         let sender = tx_context::sender(ctx);
+        schedule_request(
+            scheduler, registry, sender, sender,
+            request_id, method, resource, namespace, name, payload, seal_token, priority, deadline_ms, ctx
+        );
+    }
+
+    /// 위임 요청 제출 - owner의 워커에서 실행, 권한 검증은 마스터 RBAC가 requester 기준으로 수행
+    public fun submit_delegated_k8s_request(
+        scheduler: &mut K8sScheduler,
+        registry: &WorkerRegistry,
+        owner: address,
+        request_id: String,
+        method: String,
+        resource: String,
+        namespace: String,
+        name: String,
+        payload: String,
+        seal_token: String,
+        priority: u8,
+        deadline_ms: u64,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        schedule_request(
+            scheduler, registry, owner, sender,
+            request_id, method, resource, namespace, name, payload, seal_token, priority, deadline_ms, ctx
+        );
+    }
+
+    /// API 실행 결과 기록 (마스터 노드에서 호출)
+    public fun record_api_result(
+        scheduler: &mut K8sScheduler,
+        registry: &mut WorkerRegistry,
+        request_id: String,
+        success: bool,
+        output: String,
+        error: String,
+        execution_time_ms: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(table::contains(&scheduler.active_requests, request_id), EInvalidRequest);
+
+        let request = table::remove(&mut scheduler.active_requests, request_id);
+        let worker_id = request.assigned_worker;
+        let timestamp = tx_context::epoch_timestamp_ms(ctx);
+
+        // 요청 상태 업데이트
+        let mut completed_request = request;
+        completed_request.status = if (success) {
+            string::utf8(b"completed")
+        } else {
+            string::utf8(b"failed")
+        };
+        completed_request.completed_at = timestamp;
+
+        // 완료된 요청 목록으로 이동
+        table::add(&mut scheduler.completed_requests, request_id, completed_request);
+
+        // 워커 워크로드 감소
+        if (table::contains(&scheduler.worker_workloads, worker_id)) {
+            let workload = table::borrow_mut(&mut scheduler.worker_workloads, worker_id);
+            if (*workload > 0) {
+                *workload = *workload - 1;
+            };
+        };
+
+        // 워커 통계 업데이트 (워커 레지스트리에)
+        worker_registry::record_pod_service(registry, worker_id, success, ctx);
+
+        // 결과 이벤트 발생
+        event::emit(K8sAPIResultEvent {
+            request_id,
+            assigned_worker: worker_id,
+            success,
+            output,
+            error,
+            execution_time_ms,
+            timestamp,
+        });
+    }
+
+    /// 여러 API 실행 결과를 한 트랜잭션으로 기록 (마스터 노드의 응답 배치 제출)
+    /// 이미 기록되었거나 만료된 요청은 건너뛰어 배치 전체가 중단되지 않도록 함
+    public fun record_api_results(
+        scheduler: &mut K8sScheduler,
+        registry: &mut WorkerRegistry,
+        request_ids: vector<String>,
+        successes: vector<bool>,
+        outputs: vector<String>,
+        errors: vector<String>,
+        execution_times_ms: vector<u64>,
+        ctx: &mut TxContext
+    ) {
+        let count = vector::length(&request_ids);
+        assert!(vector::length(&successes) == count, EInvalidRequest);
+        assert!(vector::length(&outputs) == count, EInvalidRequest);
+        assert!(vector::length(&errors) == count, EInvalidRequest);
+        assert!(vector::length(&execution_times_ms) == count, EInvalidRequest);
+
+        let mut i = 0;
+        while (i < count) {
+            let request_id = *vector::borrow(&request_ids, i);
+            if (table::contains(&scheduler.active_requests, request_id)) {
+                record_api_result(
+                    scheduler,
+                    registry,
+                    request_id,
+                    *vector::borrow(&successes, i),
+                    *vector::borrow(&outputs, i),
+                    *vector::borrow(&errors, i),
+                    *vector::borrow(&execution_times_ms, i),
+                    ctx
+                );
+            };
+            i = i + 1;
+        };
+    }
+
+    // ==================== Internal Functions ====================
+
+    /// 요청 검증, owner 소유 워커 할당 및 이벤트 발생
+    fun schedule_request(
+        scheduler: &mut K8sScheduler,
+        registry: &WorkerRegistry,
+        owner: address,
+        requester: address,
+        request_id: String,
+        method: String,
+        resource: String,
+        namespace: String,
+        name: String,
+        payload: String,
+        seal_token: String,
+        priority: u8,
+        deadline_ms: u64,
+        ctx: &mut TxContext
+    ) {
         let timestamp = tx_context::epoch_timestamp_ms(ctx);
 
+        // 마감 시각이 이미 지난 요청은 제출 단계에서 거부
+        assert!(deadline_ms == 0 || deadline_ms > timestamp, ERequestExpired);
+
         // Seal Token 유효성 검사
         assert!(string::length(&seal_token) >= 32, EInvalidSealToken);
 
@@ -122,13 +266,13 @@ module k8s_daas::k8s_scheduler {
         assert!(is_valid_resource(&resource), EInvalidRequest);
         assert!(priority >= 1 && priority <= 10, EInvalidRequest);
 
-        // 요청자가 소유한 활성 워커 선택
-        let assigned_worker = select_owner_worker(scheduler, registry, sender, priority);
+        // 소유자의 활성 워커 선택
+        let assigned_worker = select_owner_worker(scheduler, registry, owner, priority);
         assert!(assigned_worker != string::utf8(b""), ENoAvailableWorkers);
 
-        // 워커가 실제로 활성 상태이고 요청자 소유인지 확인
+        // 워커가 실제로 활성 상태이고 소유자 워커인지 확인
         assert!(worker_registry::is_worker_active(registry, assigned_worker), EWorkerNotActive);
-        assert!(worker_registry::is_worker_owner(registry, assigned_worker, sender), EUnauthorizedRequest);
+        assert!(worker_registry::is_worker_owner(registry, assigned_worker, owner), EUnauthorizedRequest);
 
         // 요청 객체 생성
         let request = K8sAPIRequest {
@@ -139,13 +283,14 @@ module k8s_daas::k8s_scheduler {
             name,
             payload,
             seal_token,
-            requester: sender,
+            requester,
             priority,
             assigned_worker,
             status: string::utf8(b"assigned"),
             created_at: timestamp,
             assigned_at: timestamp,
             completed_at: 0,
+            deadline_ms,
         };
 
         // 활성 요청 목록에 추가
@@ -167,10 +312,11 @@ module k8s_daas::k8s_scheduler {
             name,
             payload,
             seal_token,
-            requester: sender,
+            requester,
             priority,
             assigned_worker,
             timestamp,
+            deadline_ms,
         });
 
         event::emit(WorkerAssignedEvent {
@@ -181,60 +327,6 @@ module k8s_daas::k8s_scheduler {
         });
     }
 
-    /// API 실행 결과 기록 (마스터 노드에서 호출)
-    public fun record_api_result(
-        scheduler: &mut K8sScheduler,
-        registry: &mut WorkerRegistry,
-        request_id: String,
-        success: bool,
-        output: String,
-        error: String,
-        execution_time_ms: u64,
-        ctx: &mut TxContext
-    ) {
-        assert!(table::contains(&scheduler.active_requests, request_id), EInvalidRequest);
-
-        let request = table::remove(&mut scheduler.active_requests, request_id);
-        let worker_id = request.assigned_worker;
-        let timestamp = tx_context::epoch_timestamp_ms(ctx);
-
-        // 요청 상태 업데이트
-        let mut completed_request = request;
-        completed_request.status = if (success) {
-            string::utf8(b"completed")
-        } else {
-            string::utf8(b"failed")
-        };
-        completed_request.completed_at = timestamp;
-
-        // 완료된 요청 목록으로 이동
-        table::add(&mut scheduler.completed_requests, request_id, completed_request);
-
-        // 워커 워크로드 감소
-        if (table::contains(&scheduler.worker_workloads, worker_id)) {
-            let workload = table::borrow_mut(&mut scheduler.worker_workloads, worker_id);
-            if (*workload > 0) {
-                *workload = *workload - 1;
-            };
-        };
-
-        // 워커 통계 업데이트 (워커 레지스트리에)
-        worker_registry::record_pod_service(registry, worker_id, success, ctx);
-
-        // 결과 이벤트 발생
-        event::emit(K8sAPIResultEvent {
-            request_id,
-            assigned_worker: worker_id,
-            success,
-            output,
-            error,
-            execution_time_ms,
-            timestamp,
-        });
-    }
-
-    // ==================== Internal Functions ====================
-
     /// 요청자 소유 워커 중 최적 워커 선택 (보안 강화)
     fun select_owner_worker(
         scheduler: &K8sScheduler,
@@ -310,6 +402,8 @@ module k8s_daas::k8s_scheduler {
         resource == &string::utf8(b"pods") ||
         resource == &string::utf8(b"services") ||
         resource == &string::utf8(b"deployments") ||
+        resource == &string::utf8(b"statefulsets") ||
+        resource == &string::utf8(b"persistentvolumeclaims") ||
         resource == &string::utf8(b"configmaps") ||
         resource == &string::utf8(b"secrets") ||
         resource == &string::utf8(b"namespaces") ||
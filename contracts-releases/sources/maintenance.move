@@ -0,0 +1,145 @@
+// K8s-DaaS Maintenance Windows - 스테이커가 예정된 정비 시간을 온체인에 등록하여 정비 중 패널티 면제
+module k8s_daas::maintenance {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::table::{Self, Table};
+    use sui::transfer;
+    use sui::event;
+    use std::string::String;
+    use k8s_daas::worker_registry::{Self, WorkerRegistry};
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EInvalidWindow: u64 = 2;
+    const EWindowTooLong: u64 = 3;
+    const ENoWindow: u64 = 4;
+
+    // ==================== Constants ====================
+
+    const MAX_WINDOW_MS: u64 = 86400000;        // 한 번에 최대 24시간
+    const MIN_WINDOW_MS: u64 = 300000;          // 최소 5분
+
+    // ==================== Structs ====================
+
+    /// 노드별 정비 시간
+    public struct MaintenanceWindow has store, drop {
+        owner: address,
+        start_ms: u64,
+        end_ms: u64,
+        reason: String,
+    }
+
+    /// 정비 일정 - 노드당 하나의 예정/진행 중 정비 시간
+    public struct MaintenanceSchedule has key {
+        id: UID,
+        windows: Table<String, MaintenanceWindow>,  // node_id -> window
+    }
+
+    /// 정비 예약 이벤트 - 마스터가 구독하여 시작 시각에 cordon/drain
+    public struct MaintenanceScheduledEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        start_ms: u64,
+        end_ms: u64,
+        reason: String,
+        timestamp: u64,
+    }
+
+    /// 정비 취소 이벤트 - 진행 중이면 마스터가 즉시 uncordon
+    public struct MaintenanceCancelledEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 정비 일정 오브젝트 초기화
+    fun init(ctx: &mut TxContext) {
+        let schedule = MaintenanceSchedule {
+            id: object::new(ctx),
+            windows: table::new(ctx),
+        };
+
+        transfer::share_object(schedule);
+    }
+
+    /// 정비 시간 예약 (워커 소유자만, 기존 예약은 교체)
+    public entry fun request_maintenance(
+        schedule: &mut MaintenanceSchedule,
+        registry: &WorkerRegistry,
+        node_id: String,
+        start_ms: u64,
+        duration_ms: u64,
+        reason: String,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        assert!(worker_registry::is_worker_owner(registry, node_id, sender), EUnauthorized);
+        assert!(duration_ms >= MIN_WINDOW_MS, EInvalidWindow);
+        assert!(duration_ms <= MAX_WINDOW_MS, EWindowTooLong);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        let end_ms = start_ms + duration_ms;
+        assert!(end_ms > now, EInvalidWindow);
+
+        if (table::contains(&schedule.windows, node_id)) {
+            table::remove(&mut schedule.windows, node_id);
+        };
+        table::add(&mut schedule.windows, node_id, MaintenanceWindow {
+            owner: sender,
+            start_ms,
+            end_ms,
+            reason,
+        });
+
+        event::emit(MaintenanceScheduledEvent {
+            node_id,
+            owner: sender,
+            start_ms,
+            end_ms,
+            reason,
+            timestamp: now,
+        });
+    }
+
+    /// 정비 취소 (워커 소유자만)
+    public entry fun cancel_maintenance(
+        schedule: &mut MaintenanceSchedule,
+        registry: &WorkerRegistry,
+        node_id: String,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        assert!(worker_registry::is_worker_owner(registry, node_id, sender), EUnauthorized);
+        assert!(table::contains(&schedule.windows, node_id), ENoWindow);
+
+        table::remove(&mut schedule.windows, node_id);
+
+        event::emit(MaintenanceCancelledEvent {
+            node_id,
+            owner: sender,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 주어진 시각에 정비 중인지 확인 (슬래싱 판단 시 참조)
+    public fun is_in_maintenance(schedule: &MaintenanceSchedule, node_id: String, now_ms: u64): bool {
+        if (!table::contains(&schedule.windows, node_id)) {
+            return false
+        };
+
+        let window = table::borrow(&schedule.windows, node_id);
+        now_ms >= window.start_ms && now_ms < window.end_ms
+    }
+
+    /// 예약된 정비 시간 조회 (start_ms, end_ms)
+    public fun get_window(schedule: &MaintenanceSchedule, node_id: String): (u64, u64) {
+        assert!(table::contains(&schedule.windows, node_id), ENoWindow);
+        let window = table::borrow(&schedule.windows, node_id);
+        (window.start_ms, window.end_ms)
+    }
+}
@@ -0,0 +1,178 @@
+// K8s-DaaS Master Registry - 리전별 Nautilus 마스터 등록 (Gateway 라우팅 및 마스터 간 상태 동기화 기준)
+module k8s_daas::master_registry {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::table::{Self, Table};
+    use sui::transfer;
+    use sui::event;
+    use std::string::String;
+    use std::vector;
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const ERegionExists: u64 = 2;
+    const ERegionNotFound: u64 = 3;
+    const EInvalidPublicKey: u64 = 4;
+
+    // ==================== Constants ====================
+
+    const MASTER_HEARTBEAT_TIMEOUT_MS: u64 = 900000; // 15분 동안 하트비트가 없으면 비활성으로 간주
+    const ED25519_PUBLIC_KEY_HEX_LENGTH: u64 = 64;
+
+    // ==================== Structs ====================
+
+    /// 리전 마스터 정보 - 관리자가 리전과 운영자 주소를 승인하고, 마스터가 엔드포인트/서명 키를 공지
+    public struct RegionalMaster has store, drop {
+        region: String,
+        operator: address,        // 공지/하트비트를 보낼 수 있는 마스터 지갑
+        endpoint: String,         // 마스터 API 주소 (공지 전에는 빈 문자열)
+        public_key: String,       // 응답 서명 ed25519 공개키 (hex)
+        registered_at: u64,
+        last_heartbeat: u64,
+    }
+
+    /// 마스터 레지스트리
+    public struct MasterRegistry has key {
+        id: UID,
+        masters: Table<String, RegionalMaster>,  // region -> master
+        regions: vector<String>,
+        admin: address,
+    }
+
+    /// 리전 승인 이벤트
+    public struct MasterRegisteredEvent has copy, drop {
+        region: String,
+        operator: address,
+        timestamp: u64,
+    }
+
+    /// 마스터 엔드포인트/키 공지 이벤트 - Gateway와 다른 리전 마스터가 구독
+    public struct MasterAnnouncedEvent has copy, drop {
+        region: String,
+        endpoint: String,
+        public_key: String,
+        timestamp: u64,
+    }
+
+    /// 리전 제거 이벤트
+    public struct MasterDeregisteredEvent has copy, drop {
+        region: String,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 마스터 레지스트리 초기화 (배포자가 관리자)
+    fun init(ctx: &mut TxContext) {
+        let registry = MasterRegistry {
+            id: object::new(ctx),
+            masters: table::new(ctx),
+            regions: vector::empty(),
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(registry);
+    }
+
+    /// 리전 승인 (관리자만) - 운영자가 announce_master로 엔드포인트를 공지해야 라우팅 대상이 됨
+    public entry fun register_master(
+        registry: &mut MasterRegistry,
+        region: String,
+        operator: address,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == registry.admin, EUnauthorized);
+        assert!(!table::contains(&registry.masters, region), ERegionExists);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        table::add(&mut registry.masters, region, RegionalMaster {
+            region,
+            operator,
+            endpoint: std::string::utf8(b""),
+            public_key: std::string::utf8(b""),
+            registered_at: now,
+            last_heartbeat: 0,
+        });
+        vector::push_back(&mut registry.regions, region);
+
+        event::emit(MasterRegisteredEvent { region, operator, timestamp: now });
+    }
+
+    /// 엔드포인트와 응답 서명 키 공지 (리전 운영자만, 마스터 시작 시 호출)
+    public entry fun announce_master(
+        registry: &mut MasterRegistry,
+        region: String,
+        endpoint: String,
+        public_key: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(table::contains(&registry.masters, region), ERegionNotFound);
+        assert!(std::string::length(&public_key) == ED25519_PUBLIC_KEY_HEX_LENGTH, EInvalidPublicKey);
+
+        let master = table::borrow_mut(&mut registry.masters, region);
+        assert!(tx_context::sender(ctx) == master.operator, EUnauthorized);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        master.endpoint = endpoint;
+        master.public_key = public_key;
+        master.last_heartbeat = now;
+
+        event::emit(MasterAnnouncedEvent { region, endpoint, public_key, timestamp: now });
+    }
+
+    /// 마스터 하트비트 (리전 운영자만)
+    public entry fun master_heartbeat(
+        registry: &mut MasterRegistry,
+        region: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(table::contains(&registry.masters, region), ERegionNotFound);
+        let master = table::borrow_mut(&mut registry.masters, region);
+        assert!(tx_context::sender(ctx) == master.operator, EUnauthorized);
+
+        master.last_heartbeat = tx_context::epoch_timestamp_ms(ctx);
+    }
+
+    /// 리전 제거 (관리자만)
+    public entry fun deregister_master(
+        registry: &mut MasterRegistry,
+        region: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == registry.admin, EUnauthorized);
+        assert!(table::contains(&registry.masters, region), ERegionNotFound);
+
+        table::remove(&mut registry.masters, region);
+        let (found, index) = vector::index_of(&registry.regions, &region);
+        if (found) {
+            vector::remove(&mut registry.regions, index);
+        };
+
+        event::emit(MasterDeregisteredEvent { region, timestamp: tx_context::epoch_timestamp_ms(ctx) });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 등록된 리전 목록
+    public fun regions(registry: &MasterRegistry): vector<String> {
+        registry.regions
+    }
+
+    /// 하트비트 제한 시간 안에 있는 마스터인지 확인
+    public fun is_master_active(registry: &MasterRegistry, region: String, now_ms: u64): bool {
+        if (!table::contains(&registry.masters, region)) {
+            return false
+        };
+
+        let master = table::borrow(&registry.masters, region);
+        master.last_heartbeat > 0 && now_ms <= master.last_heartbeat + MASTER_HEARTBEAT_TIMEOUT_MS
+    }
+
+    /// 리전 마스터 엔드포인트와 공개키 조회
+    public fun get_master(registry: &MasterRegistry, region: String): (String, String, address) {
+        assert!(table::contains(&registry.masters, region), ERegionNotFound);
+        let master = table::borrow(&registry.masters, region);
+        (master.endpoint, master.public_key, master.operator)
+    }
+}
@@ -0,0 +1,90 @@
+// K8s-DaaS Pricing - 테넌트가 내는 리소스 단가(에포크당 MIST)를 온체인에 공시하여 배포 비용 추정 기준 제공
+module k8s_daas::pricing {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID};
+    use sui::transfer;
+    use sui::event;
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+
+    // ==================== Structs ====================
+
+    /// 리소스 단가표 - 마스터 비용 추정 API가 조회
+    public struct PricingTable has key {
+        id: UID,
+        cpu_core_epoch_mist: u64,       // CPU 1코어(1000m) 에포크당
+        memory_gib_epoch_mist: u64,     // 메모리 1GiB 에포크당
+        storage_gib_epoch_mist: u64,    // 영구 볼륨 1GiB 에포크당
+        pod_epoch_mist: u64,            // Pod 하나당 고정 비용
+        version: u64,
+        updated_at: u64,
+        admin: address,
+    }
+
+    /// 단가 변경 이벤트
+    public struct PricingUpdatedEvent has copy, drop {
+        cpu_core_epoch_mist: u64,
+        memory_gib_epoch_mist: u64,
+        storage_gib_epoch_mist: u64,
+        pod_epoch_mist: u64,
+        version: u64,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 단가표 초기화 (단가 0, 관리자가 공시 전까지는 가스 비용만 추정됨)
+    fun init(ctx: &mut TxContext) {
+        let table = PricingTable {
+            id: object::new(ctx),
+            cpu_core_epoch_mist: 0,
+            memory_gib_epoch_mist: 0,
+            storage_gib_epoch_mist: 0,
+            pod_epoch_mist: 0,
+            version: 0,
+            updated_at: 0,
+            admin: tx_context::sender(ctx),
+        };
+
+        transfer::share_object(table);
+    }
+
+    /// 단가 공시 (관리자만)
+    public entry fun update_pricing(
+        table: &mut PricingTable,
+        cpu_core_epoch_mist: u64,
+        memory_gib_epoch_mist: u64,
+        storage_gib_epoch_mist: u64,
+        pod_epoch_mist: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(tx_context::sender(ctx) == table.admin, EUnauthorized);
+
+        let timestamp = tx_context::epoch_timestamp_ms(ctx);
+
+        table.cpu_core_epoch_mist = cpu_core_epoch_mist;
+        table.memory_gib_epoch_mist = memory_gib_epoch_mist;
+        table.storage_gib_epoch_mist = storage_gib_epoch_mist;
+        table.pod_epoch_mist = pod_epoch_mist;
+        table.version = table.version + 1;
+        table.updated_at = timestamp;
+
+        event::emit(PricingUpdatedEvent {
+            cpu_core_epoch_mist,
+            memory_gib_epoch_mist,
+            storage_gib_epoch_mist,
+            pod_epoch_mist,
+            version: table.version,
+            timestamp,
+        });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 현재 단가 조회 (cpu, memory, storage, pod)
+    public fun get_pricing(table: &PricingTable): (u64, u64, u64, u64) {
+        (table.cpu_core_epoch_mist, table.memory_gib_epoch_mist, table.storage_gib_epoch_mist, table.pod_epoch_mist)
+    }
+}
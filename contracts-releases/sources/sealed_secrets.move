@@ -0,0 +1,156 @@
+// K8s-DaaS Sealed Secrets - 마스터 TEE 키로 암호화한 테넌트 Secret (본문 또는 외부 blob 참조)을 온체인에 보관
+module k8s_daas::sealed_secrets {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID, ID};
+    use sui::transfer;
+    use sui::event;
+    use std::string::{Self, String};
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EEmptyName: u64 = 2;
+    const EInvalidDigest: u64 = 3;
+    const ENoPayload: u64 = 4;
+
+    // ==================== Constants ====================
+
+    const DIGEST_LENGTH: u64 = 71;              // "sha256:" + 64 hex
+
+    // ==================== Structs ====================
+
+    /// 암호화된 Secret - 마스터는 워커가 Pod 마운트 시 요청하면 소유자와 네임스페이스를 확인한 뒤 복호화
+    /// ciphertext가 비어 있으면 blob_url의 내용을 내려받아 blob_sha256과 비교 후 사용
+    public struct SealedSecret has key, store {
+        id: UID,
+        owner: address,              // 테넌트 지갑 (네임스페이스의 k3s-daas.io/tenant 라벨과 일치해야 함)
+        namespace: String,           // 마운트를 허용할 네임스페이스
+        name: String,
+        ciphertext: String,          // base64 봉투 (마스터 /api/v1/secrets/recipient 공개키로 암호화)
+        blob_url: String,            // 큰 Secret은 외부 저장소(Walrus 등)에 두고 주소만 기록
+        blob_sha256: String,         // blob 무결성 "sha256:<hex>"
+        key_id: String,              // 암호화에 사용한 마스터 키 ID
+        updated_at: u64,
+    }
+
+    /// 생성/변경 이벤트 (본문 없이 메타데이터만)
+    public struct SealedSecretUpdatedEvent has copy, drop {
+        secret_id: ID,
+        owner: address,
+        namespace: String,
+        name: String,
+        key_id: String,
+        timestamp: u64,
+    }
+
+    /// 삭제 이벤트
+    public struct SealedSecretDeletedEvent has copy, drop {
+        secret_id: ID,
+        owner: address,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// Secret 생성 (소유자 지갑으로 보관)
+    public entry fun create_sealed_secret(
+        namespace: String,
+        name: String,
+        ciphertext: String,
+        blob_url: String,
+        blob_sha256: String,
+        key_id: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(!string::is_empty(&name) && !string::is_empty(&namespace), EEmptyName);
+        check_payload(&ciphertext, &blob_url, &blob_sha256);
+
+        let sender = tx_context::sender(ctx);
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        let secret = SealedSecret {
+            id: object::new(ctx),
+            owner: sender,
+            namespace,
+            name,
+            ciphertext,
+            blob_url,
+            blob_sha256,
+            key_id,
+            updated_at: now,
+        };
+
+        event::emit(SealedSecretUpdatedEvent {
+            secret_id: object::id(&secret),
+            owner: sender,
+            namespace: secret.namespace,
+            name: secret.name,
+            key_id: secret.key_id,
+            timestamp: now,
+        });
+
+        transfer::transfer(secret, sender);
+    }
+
+    /// 본문 교체 (키 교체, 값 변경) - 이후 새로 마운트되는 Pod부터 적용
+    public entry fun update_sealed_secret(
+        secret: &mut SealedSecret,
+        ciphertext: String,
+        blob_url: String,
+        blob_sha256: String,
+        key_id: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(secret.owner == tx_context::sender(ctx), EUnauthorized);
+        check_payload(&ciphertext, &blob_url, &blob_sha256);
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        secret.ciphertext = ciphertext;
+        secret.blob_url = blob_url;
+        secret.blob_sha256 = blob_sha256;
+        secret.key_id = key_id;
+        secret.updated_at = now;
+
+        event::emit(SealedSecretUpdatedEvent {
+            secret_id: object::id(secret),
+            owner: secret.owner,
+            namespace: secret.namespace,
+            name: secret.name,
+            key_id,
+            timestamp: now,
+        });
+    }
+
+    /// 삭제 (이후 마운트 실패)
+    public entry fun delete_sealed_secret(secret: SealedSecret, ctx: &mut TxContext) {
+        assert!(secret.owner == tx_context::sender(ctx), EUnauthorized);
+        let secret_id = object::id(&secret);
+        let SealedSecret { id, owner, namespace: _, name: _, ciphertext: _, blob_url: _, blob_sha256: _, key_id: _, updated_at: _ } = secret;
+        object::delete(id);
+
+        event::emit(SealedSecretDeletedEvent {
+            secret_id,
+            owner,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    // ==================== Internal Functions ====================
+
+    /// 본문 또는 blob 참조 중 하나는 있어야 하고, blob에는 다이제스트가 필요
+    fun check_payload(ciphertext: &String, blob_url: &String, blob_sha256: &String) {
+        assert!(!string::is_empty(ciphertext) || !string::is_empty(blob_url), ENoPayload);
+        assert!(string::is_empty(blob_url) || string::length(blob_sha256) == DIGEST_LENGTH, EInvalidDigest);
+    }
+
+    // ==================== View Functions ====================
+
+    /// 마운트 허용 네임스페이스
+    public fun get_namespace(secret: &SealedSecret): String {
+        secret.namespace
+    }
+
+    /// 소유자
+    public fun get_owner(secret: &SealedSecret): address {
+        secret.owner
+    }
+}
@@ -0,0 +1,145 @@
+// K8s-DaaS Slash Appeals - 슬래싱된 스테이커의 이의 신청과 거버넌스 심사 기록
+module k8s_daas::slash_appeals {
+    use sui::tx_context::{Self, TxContext};
+    use sui::object::{Self, UID, ID};
+    use sui::transfer;
+    use sui::event;
+    use std::string::{Self, String};
+    use k8s_daas::worker_registry::{Self, WorkerRegistry};
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EInvalidDigest: u64 = 2;
+    const EAlreadyDecided: u64 = 3;
+    const EEmptyNodeId: u64 = 4;
+
+    // ==================== Constants ====================
+
+    const DIGEST_LENGTH: u64 = 71;              // "sha256:" + 64 hex
+
+    const STATUS_PENDING: u8 = 0;
+    const STATUS_UPHELD: u8 = 1;                // 이의 인정 (슬래싱 철회 대상)
+    const STATUS_REJECTED: u8 = 2;              // 이의 기각
+
+    // ==================== Structs ====================
+
+    /// 이의 신청 - 공유 오브젝트로 생성되어 거버넌스가 심사
+    public struct SlashAppeal has key {
+        id: UID,
+        node_id: String,
+        appellant: address,          // 제출자 (심사 시 워커 소유자인지 확인)
+        stake_object_id: String,     // 슬래싱된 StakeRecord
+        evidence_digest: String,     // 증거 번들 sha256 (하트비트 로그, 증명, 배정 기록)
+        summary: String,             // 스테이커가 작성한 요약
+        status: u8,
+        decision_note: String,
+        submitted_at: u64,
+        decided_at: u64,
+    }
+
+    /// 이의 신청 이벤트 - 마스터가 구독하여 상태 API로 노출
+    public struct AppealSubmittedEvent has copy, drop {
+        appeal_id: ID,
+        node_id: String,
+        appellant: address,
+        stake_object_id: String,
+        evidence_digest: String,
+        summary: String,
+        timestamp: u64,
+    }
+
+    /// 심사 결과 이벤트
+    public struct AppealDecidedEvent has copy, drop {
+        appeal_id: ID,
+        node_id: String,
+        upheld: bool,
+        note: String,
+        reviewer: address,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 이의 신청 제출 (슬래싱된 노드의 스테이커가 자신의 지갑으로 제출)
+    public entry fun submit_appeal(
+        node_id: String,
+        stake_object_id: String,
+        evidence_digest: String,
+        summary: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(!string::is_empty(&node_id), EEmptyNodeId);
+        assert!(string::length(&evidence_digest) == DIGEST_LENGTH, EInvalidDigest);
+
+        let sender = tx_context::sender(ctx);
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        let appeal = SlashAppeal {
+            id: object::new(ctx),
+            node_id,
+            appellant: sender,
+            stake_object_id,
+            evidence_digest,
+            summary,
+            status: STATUS_PENDING,
+            decision_note: string::utf8(b""),
+            submitted_at: now,
+            decided_at: 0,
+        };
+
+        event::emit(AppealSubmittedEvent {
+            appeal_id: object::id(&appeal),
+            node_id,
+            appellant: sender,
+            stake_object_id,
+            evidence_digest,
+            summary,
+            timestamp: now,
+        });
+
+        transfer::share_object(appeal);
+    }
+
+    /// 이의 신청 심사 (레지스트리 관리자만, 워커 소유자가 아닌 제출은 인정 불가)
+    public entry fun decide_appeal(
+        appeal: &mut SlashAppeal,
+        registry: &WorkerRegistry,
+        upheld: bool,
+        note: String,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        assert!(sender == worker_registry::get_admin(registry), EUnauthorized);
+        assert!(appeal.status == STATUS_PENDING, EAlreadyDecided);
+        assert!(
+            !upheld || worker_registry::is_worker_owner(registry, appeal.node_id, appeal.appellant),
+            EUnauthorized
+        );
+
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        appeal.status = if (upheld) { STATUS_UPHELD } else { STATUS_REJECTED };
+        appeal.decision_note = note;
+        appeal.decided_at = now;
+
+        event::emit(AppealDecidedEvent {
+            appeal_id: object::id(appeal),
+            node_id: appeal.node_id,
+            upheld,
+            note,
+            reviewer: sender,
+            timestamp: now,
+        });
+    }
+
+    // ==================== View Functions ====================
+
+    /// 심사 상태 조회 (0: pending, 1: upheld, 2: rejected)
+    public fun get_status(appeal: &SlashAppeal): u8 {
+        appeal.status
+    }
+
+    /// 증거 번들 다이제스트 조회
+    public fun get_evidence_digest(appeal: &SlashAppeal): String {
+        appeal.evidence_digest
+    }
+}
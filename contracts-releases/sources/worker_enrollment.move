@@ -0,0 +1,48 @@
+// K8s-DaaS Worker Enrollment - 승인 모드 마스터의 신규 워커 등록 심사 결과를 거버넌스가 온체인에 기록
+module k8s_daas::worker_enrollment {
+    use sui::tx_context::{Self, TxContext};
+    use sui::event;
+    use std::string::{Self, String};
+    use k8s_daas::worker_registry::{Self, WorkerRegistry};
+
+    // ==================== Error Constants ====================
+
+    const EUnauthorized: u64 = 1;
+    const EEmptyNodeId: u64 = 2;
+
+    // ==================== Events ====================
+
+    /// 등록 심사 이벤트 - 마스터가 구독하여 승인 대기열의 워커를 풀에 추가하거나 거절
+    public struct EnrollmentDecidedEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        approved: bool,
+        note: String,
+        reviewer: address,
+        timestamp: u64,
+    }
+
+    // ==================== Public Functions ====================
+
+    /// 등록 심사 (레지스트리 관리자만, 워커가 레지스트리에 없으면 abort)
+    public entry fun decide_enrollment(
+        registry: &WorkerRegistry,
+        node_id: String,
+        approved: bool,
+        note: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(!string::is_empty(&node_id), EEmptyNodeId);
+        let sender = tx_context::sender(ctx);
+        assert!(sender == worker_registry::get_admin(registry), EUnauthorized);
+
+        event::emit(EnrollmentDecidedEvent {
+            node_id,
+            owner: worker_registry::get_worker_owner(registry, node_id),
+            approved,
+            note,
+            reviewer: sender,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+}
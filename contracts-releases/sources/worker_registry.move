@@ -1,14 +1,20 @@
 // K8s-DaaS Worker Registry - 실제 워커 풀과 스테이킹 관리
 module k8s_daas::worker_registry {
     use sui::coin::{Self, Coin};
+    use sui::balance::{Self, Balance};
+    use sui::dynamic_field as df;
     use sui::sui::SUI;
     use sui::table::{Self, Table};
     use sui::tx_context::{Self, TxContext};
     use sui::object::{Self, UID};
     use sui::transfer;
     use sui::event;
+    use sui::bcs;
+    use sui::ed25519;
+    use sui::hex;
     use std::string::{Self, String};
     use std::vector;
+    use k8s_daas::master_registry::{Self, MasterRegistry};
 
     // ==================== Error Constants ====================
 
@@ -19,6 +25,14 @@ module k8s_daas::worker_registry {
     const EWorkerNotActive: u64 = 5;
     const EUnauthorized: u64 = 6;
     const EInvalidOperation: u64 = 7;
+    const EInvalidRole: u64 = 8;
+    const EInvalidVerdict: u64 = 9;
+    const EStakeLocked: u64 = 10;
+    const ENoVaultBalance: u64 = 11;
+    const EInvalidAttestation: u64 = 12;
+    const EAttestationExpired: u64 = 13;
+    const ENotAbandoned: u64 = 14;
+    const EInvalidTokenScope: u64 = 15;
 
     // ==================== Constants ====================
 
@@ -26,6 +40,14 @@ module k8s_daas::worker_registry {
     const MAX_WORKERS_PER_ADDRESS: u64 = 10;
     const HEARTBEAT_TIMEOUT_MS: u64 = 300000; // 5 minutes
 
+    // 노드 역할별 최소 스테이킹 배수 (MIN_STAKE_AMOUNT 기준)
+    const EDGE_STAKE_MULTIPLIER: u64 = 2;
+    const STORAGE_STAKE_MULTIPLIER: u64 = 10;
+
+    // 비상 인출: 마스터 설정과 관계없이 체인이 강제하는 최소 부재 기간과 서명 도메인
+    const ABANDONMENT_MIN_ABSENCE_MS: u64 = 604800000; // 7일
+    const ABANDONMENT_DOMAIN: vector<u8> = b"daas-node-abandonment-v1";
+
     // ==================== Structs ====================
 
     /// 워커 노드 정보
@@ -33,6 +55,7 @@ module k8s_daas::worker_registry {
         node_id: String,
         owner: address,
         stake_amount: u64,
+        role: String,             // "worker", "edge", "storage"
         status: String,           // "pending", "active", "busy", "offline", "slashed"
         seal_token: String,
         join_token: String,       // K3s 클러스터 참여용 조인 토큰
@@ -62,11 +85,17 @@ module k8s_daas::worker_registry {
         owner: address,
     }
 
+    /// 워커별 스테이킹 자금 보관소 키 (레지스트리의 dynamic field, 값은 Balance<SUI>)
+    public struct StakeVaultKey has copy, drop, store {
+        node_id: String,
+    }
+
     /// 워커 등록 이벤트
     public struct WorkerRegisteredEvent has copy, drop {
         node_id: String,
         owner: address,
         stake_amount: u64,
+        role: String,
         seal_token: String,
         timestamp: u64,
     }
@@ -79,6 +108,17 @@ module k8s_daas::worker_registry {
         timestamp: u64,
     }
 
+    /// TEE 교차 검증 결과 이벤트 (워커가 마스터/피어의 증명 문서를 검증)
+    public struct AttestationVerifiedEvent has copy, drop {
+        verifier_node_id: String,
+        verifier: address,
+        target: String,
+        measurement: String,
+        verdict: String,
+        epoch: u64,
+        timestamp: u64,
+    }
+
     /// 스테이킹 이벤트
     public struct StakeDepositedEvent has copy, drop {
         node_id: String,
@@ -87,6 +127,37 @@ module k8s_daas::worker_registry {
         timestamp: u64,
     }
 
+    /// 스테이킹 양 변경 이벤트 (추가 예치/부분 인출, 재등록 없이 티어 갱신)
+    public struct StakeAmountChangedEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        old_amount: u64,
+        new_amount: u64,
+        role: String,
+        timestamp: u64,
+    }
+
+    /// 노드 미참여 증명 - 마스터가 BCS 직렬화 결과에 ed25519로 서명 (nautilus node_abandonment.go와 필드 순서 동일)
+    public struct AbandonmentStatement has copy, drop {
+        domain: vector<u8>,
+        registry: address,        // 다른 배포의 증명 재사용 방지
+        node_id: String,
+        owner: address,
+        last_seen_ms: u64,        // 마스터가 마지막으로 하트비트를 받은 시각
+        abandoned_at_ms: u64,
+        expires_at_ms: u64,
+    }
+
+    /// 비상 인출 이벤트 - 포기된 노드의 등록 해제와 스테이킹 반환
+    public struct EmergencyUnstakeEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        amount: u64,
+        master_region: String,
+        last_seen_ms: u64,
+        timestamp: u64,
+    }
+
     /// 조인 토큰 설정 이벤트
     public struct JoinTokenSetEvent has copy, drop {
         node_id: String,
@@ -94,6 +165,25 @@ module k8s_daas::worker_registry {
         timestamp: u64,
     }
 
+    /// 범위 제한 토큰 발급 이벤트 (토큰 자체가 아닌 sha256 해시만 기록, 마스터가 해시로 범위 조회)
+    public struct ScopedTokenIssuedEvent has copy, drop {
+        node_id: String,
+        owner: address,
+        token_hash: String,          // 토큰의 sha256 hex (64자)
+        namespaces: vector<String>,  // 비어 있으면 전체
+        resources: vector<String>,   // 비어 있으면 전체
+        verbs: vector<String>,       // get, list, watch, create, update, patch, delete (비어 있으면 전체)
+        expires_at_ms: u64,
+        timestamp: u64,
+    }
+
+    /// 범위 제한 토큰 폐기 이벤트
+    public struct ScopedTokenRevokedEvent has copy, drop {
+        node_id: String,
+        token_hash: String,
+        timestamp: u64,
+    }
+
     // ==================== Public Functions ====================
 
     /// 워커 레지스트리 초기화 (한 번만 실행)
@@ -111,19 +201,68 @@ module k8s_daas::worker_registry {
         transfer::share_object(registry);
     }
 
-    /// 워커 노드 등록 및 스테이킹
+    /// 워커 노드 등록 및 스테이킹 (기본 worker 역할)
     public fun stake_and_register_worker(
         registry: &mut WorkerRegistry,
         payment: Coin<SUI>,
         node_id: String,
         seal_token: String,
         ctx: &mut TxContext
+    ) {
+        register_worker(registry, payment, node_id, seal_token, string::utf8(b"worker"), ctx)
+    }
+
+    /// 역할을 지정한 워커 노드 등록 - 역할별 스테이킹 티어 검증
+    public fun stake_and_register_worker_with_role(
+        registry: &mut WorkerRegistry,
+        payment: Coin<SUI>,
+        node_id: String,
+        seal_token: String,
+        role: String,
+        ctx: &mut TxContext
+    ) {
+        register_worker(registry, payment, node_id, seal_token, role, ctx)
+    }
+
+    /// 여러 코인을 합쳐 워커 등록 (한 코인으로 스테이킹 양을 채우지 못하는 경우)
+    public fun stake_and_register_worker_with_coins(
+        registry: &mut WorkerRegistry,
+        payments: vector<Coin<SUI>>,
+        node_id: String,
+        seal_token: String,
+        role: String,
+        ctx: &mut TxContext
+    ) {
+        register_worker(registry, join_coins(payments), node_id, seal_token, role, ctx)
+    }
+
+    /// 역할별 최소 스테이킹 양 (알 수 없는 역할은 abort)
+    public fun required_stake_for_role(role: &String): u64 {
+        if (*role == string::utf8(b"worker")) {
+            MIN_STAKE_AMOUNT
+        } else if (*role == string::utf8(b"edge")) {
+            MIN_STAKE_AMOUNT * EDGE_STAKE_MULTIPLIER
+        } else if (*role == string::utf8(b"storage")) {
+            MIN_STAKE_AMOUNT * STORAGE_STAKE_MULTIPLIER
+        } else {
+            abort EInvalidRole
+        }
+    }
+
+    /// 워커 등록 내부 구현
+    fun register_worker(
+        registry: &mut WorkerRegistry,
+        payment: Coin<SUI>,
+        node_id: String,
+        seal_token: String,
+        role: String,
+        ctx: &mut TxContext
     ) {
         let sender = tx_context::sender(ctx);
         let stake_amount = coin::value(&payment);
 
-        // 최소 스테이킹 요구사항 확인
-        assert!(stake_amount >= MIN_STAKE_AMOUNT, EInsufficientStake);
+        // 역할별 최소 스테이킹 요구사항 확인
+        assert!(stake_amount >= required_stake_for_role(&role), EInsufficientStake);
 
         // 워커 ID 중복 확인
         assert!(!table::contains(&registry.workers, node_id), EWorkerAlreadyExists);
@@ -144,6 +283,7 @@ module k8s_daas::worker_registry {
             node_id,
             owner: sender,
             stake_amount,
+            role,
             status: string::utf8(b"pending"),
             seal_token,
             join_token: string::utf8(b""), // 초기에는 빈 토큰
@@ -167,8 +307,8 @@ module k8s_daas::worker_registry {
         registry.total_stake = registry.total_stake + stake_amount;
         registry.total_workers = registry.total_workers + 1;
 
-        // 스테이킹 자금을 컨트랙트에 보관 (실제로는 Treasury 등으로)
-        transfer::public_transfer(payment, @k8s_daas);
+        // 스테이킹 자금을 레지스트리 보관소에 보관 (부분 인출 시 여기서 지급)
+        df::add(&mut registry.id, StakeVaultKey { node_id }, coin::into_balance(payment));
 
         // 스테이킹 증명서 발행
         let stake_proof = StakeProof {
@@ -184,6 +324,7 @@ module k8s_daas::worker_registry {
             node_id,
             owner: sender,
             stake_amount,
+            role,
             seal_token,
             timestamp,
         });
@@ -202,6 +343,194 @@ module k8s_daas::worker_registry {
         ()
     }
 
+    /// 기존 스테이킹에 코인 추가 예치 (여러 코인 가능, 노드 재등록 없음)
+    public fun top_up_stake(
+        registry: &mut WorkerRegistry,
+        proof: &mut StakeProof,
+        payments: vector<Coin<SUI>>,
+        ctx: &mut TxContext
+    ) {
+        let payment = join_coins(payments);
+        let amount = coin::value(&payment);
+        assert!(amount > 0, EInsufficientStake);
+
+        let node_id = proof.node_id;
+        let sender = tx_context::sender(ctx);
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+
+        let worker = table::borrow_mut(&mut registry.workers, node_id);
+        assert!(worker.owner == sender, EUnauthorized);
+        assert!(worker.status != string::utf8(b"slashed"), EStakeLocked);
+
+        let old_amount = worker.stake_amount;
+        worker.stake_amount = old_amount + amount;
+        proof.stake_amount = worker.stake_amount;
+        let new_amount = worker.stake_amount;
+        let role = worker.role;
+
+        let key = StakeVaultKey { node_id };
+        if (df::exists_(&registry.id, key)) {
+            balance::join(df::borrow_mut<StakeVaultKey, Balance<SUI>>(&mut registry.id, key), coin::into_balance(payment));
+        } else {
+            df::add(&mut registry.id, key, coin::into_balance(payment));
+        };
+        registry.total_stake = registry.total_stake + amount;
+
+        let timestamp = tx_context::epoch_timestamp_ms(ctx);
+        event::emit(StakeDepositedEvent {
+            node_id,
+            owner: sender,
+            amount,
+            timestamp,
+        });
+        event::emit(StakeAmountChangedEvent {
+            node_id,
+            owner: sender,
+            old_amount,
+            new_amount,
+            role,
+            timestamp,
+        });
+    }
+
+    /// 스테이킹 부분 인출 - 남는 양이 역할 최소치 이상이어야 함 (전부 인출은 등록 해제로)
+    public fun withdraw_stake(
+        registry: &mut WorkerRegistry,
+        proof: &mut StakeProof,
+        amount: u64,
+        ctx: &mut TxContext
+    ) {
+        assert!(amount > 0, EInvalidOperation);
+
+        let node_id = proof.node_id;
+        let sender = tx_context::sender(ctx);
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+
+        // 보관소 도입 전에 등록된 워커는 자금이 컨트랙트 주소로 이전되어 인출 불가
+        assert!(get_vault_balance(registry, node_id) >= amount, ENoVaultBalance);
+
+        let worker = table::borrow_mut(&mut registry.workers, node_id);
+        assert!(worker.owner == sender, EUnauthorized);
+        assert!(worker.status != string::utf8(b"slashed"), EStakeLocked);
+        assert!(worker.stake_amount >= amount, EInsufficientStake);
+        assert!(worker.stake_amount - amount >= required_stake_for_role(&worker.role), EInsufficientStake);
+
+        let old_amount = worker.stake_amount;
+        worker.stake_amount = old_amount - amount;
+        proof.stake_amount = worker.stake_amount;
+        let new_amount = worker.stake_amount;
+        let role = worker.role;
+        registry.total_stake = registry.total_stake - amount;
+
+        let vault = df::borrow_mut<StakeVaultKey, Balance<SUI>>(&mut registry.id, StakeVaultKey { node_id });
+        transfer::public_transfer(coin::from_balance(balance::split(vault, amount), ctx), sender);
+
+        event::emit(StakeAmountChangedEvent {
+            node_id,
+            owner: sender,
+            old_amount,
+            new_amount,
+            role,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
+    /// 포기된 노드의 비상 인출 - 마스터가 서명한 미참여 증명으로 등록을 해제하고 보관소 전액 반환
+    /// 체인 하트비트가 증명의 마지막 확인 시각보다 최근이거나 최소 부재 기간이 지나지 않았으면 abort
+    public fun emergency_unstake(
+        registry: &mut WorkerRegistry,
+        proof: StakeProof,
+        masters: &MasterRegistry,
+        region: String,
+        last_seen_ms: u64,
+        abandoned_at_ms: u64,
+        expires_at_ms: u64,
+        signature: vector<u8>,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        let now = tx_context::epoch_timestamp_ms(ctx);
+        let StakeProof { id, node_id, stake_amount: _, staked_at: _, owner } = proof;
+        object::delete(id);
+
+        assert!(owner == sender, EUnauthorized);
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+        assert!(now <= expires_at_ms, EAttestationExpired);
+        assert!(abandoned_at_ms >= last_seen_ms + ABANDONMENT_MIN_ABSENCE_MS, ENotAbandoned);
+        assert!(now >= last_seen_ms + ABANDONMENT_MIN_ABSENCE_MS, ENotAbandoned);
+        {
+            let worker = table::borrow(&registry.workers, node_id);
+            assert!(worker.owner == sender, EUnauthorized);
+            assert!(worker.status != string::utf8(b"slashed"), EStakeLocked);
+            assert!(worker.last_heartbeat <= last_seen_ms, ENotAbandoned);
+        };
+
+        // 하트비트 중인 리전 마스터가 공지한 공개키로 서명 확인
+        assert!(master_registry::is_master_active(masters, region, now), EInvalidAttestation);
+        let (_, public_key_hex, _) = master_registry::get_master(masters, region);
+        let public_key = hex::decode(*string::as_bytes(&public_key_hex));
+        let statement = AbandonmentStatement {
+            domain: ABANDONMENT_DOMAIN,
+            registry: object::uid_to_address(&registry.id),
+            node_id,
+            owner: sender,
+            last_seen_ms,
+            abandoned_at_ms,
+            expires_at_ms,
+        };
+        assert!(ed25519::ed25519_verify(&signature, &public_key, &bcs::to_bytes(&statement)), EInvalidAttestation);
+
+        let worker = table::remove(&mut registry.workers, node_id);
+        registry.total_stake = registry.total_stake - worker.stake_amount;
+        registry.total_workers = registry.total_workers - 1;
+        let (active, index) = vector::index_of(&registry.active_workers, &node_id);
+        if (active) {
+            vector::remove(&mut registry.active_workers, index);
+        };
+        if (table::contains(&registry.owner_workers, sender)) {
+            let owner_list = table::borrow_mut(&mut registry.owner_workers, sender);
+            let (owned, owned_index) = vector::index_of(owner_list, &node_id);
+            if (owned) {
+                vector::remove(owner_list, owned_index);
+            };
+        };
+
+        // 보관소 도입 전에 등록된 워커는 반환할 자금 없이 등록만 해제
+        let key = StakeVaultKey { node_id };
+        let mut amount = 0;
+        if (df::exists_(&registry.id, key)) {
+            let vault = df::remove<StakeVaultKey, Balance<SUI>>(&mut registry.id, key);
+            amount = balance::value(&vault);
+            transfer::public_transfer(coin::from_balance(vault, ctx), sender);
+        };
+
+        event::emit(WorkerStatusChangedEvent {
+            node_id,
+            old_status: worker.status,
+            new_status: string::utf8(b"abandoned"),
+            timestamp: now,
+        });
+        event::emit(EmergencyUnstakeEvent {
+            node_id,
+            owner: sender,
+            amount,
+            master_region: region,
+            last_seen_ms,
+            timestamp: now,
+        });
+    }
+
+    /// 코인 목록을 하나로 합침 (빈 목록은 abort)
+    fun join_coins(mut payments: vector<Coin<SUI>>): Coin<SUI> {
+        assert!(!vector::is_empty(&payments), EInsufficientStake);
+        let mut merged = vector::pop_back(&mut payments);
+        while (!vector::is_empty(&payments)) {
+            coin::join(&mut merged, vector::pop_back(&mut payments));
+        };
+        vector::destroy_empty(payments);
+        merged
+    }
+
     /// 워커 활성화 (관리자 또는 자동화 시스템)
     public fun activate_worker(
         registry: &mut WorkerRegistry,
@@ -252,6 +581,36 @@ module k8s_daas::worker_registry {
         worker.last_heartbeat = tx_context::epoch_timestamp_ms(ctx);
     }
 
+    /// TEE 교차 검증 결과 기록 (검증한 워커의 소유자만 보고 가능)
+    public fun report_attestation(
+        registry: &WorkerRegistry,
+        node_id: String,
+        target: String,
+        measurement: String,
+        verdict: String,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+        let worker = table::borrow(&registry.workers, node_id);
+        assert!(worker.owner == sender, EUnauthorized);
+        assert!(
+            verdict == string::utf8(b"valid") || verdict == string::utf8(b"invalid"),
+            EInvalidVerdict
+        );
+
+        event::emit(AttestationVerifiedEvent {
+            verifier_node_id: node_id,
+            verifier: sender,
+            target,
+            measurement,
+            verdict,
+            epoch: tx_context::epoch(ctx),
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
     /// 워커 상태 변경
     public fun change_worker_status(
         registry: &mut WorkerRegistry,
@@ -333,6 +692,58 @@ module k8s_daas::worker_registry {
         });
     }
 
+    /// 범위 제한 토큰 발급 (워커 소유자만, 네임스페이스/리소스/동사/만료로 제한된 파생 토큰)
+    /// 토큰은 소유자가 오프체인에서 생성하고 해시만 제출하므로 체인에 노출되지 않음
+    public fun issue_scoped_token(
+        registry: &WorkerRegistry,
+        node_id: String,
+        token_hash: String,
+        namespaces: vector<String>,
+        resources: vector<String>,
+        verbs: vector<String>,
+        expires_at_ms: u64,
+        ctx: &mut TxContext
+    ) {
+        let sender = tx_context::sender(ctx);
+        let now = tx_context::epoch_timestamp_ms(ctx);
+
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+        let worker = table::borrow(&registry.workers, node_id);
+        assert!(worker.owner == sender, EUnauthorized);
+        assert!(worker.status != string::utf8(b"slashed"), EWorkerNotActive);
+        assert!(string::length(&token_hash) == 64, EInvalidTokenScope);
+        assert!(expires_at_ms > now, EInvalidTokenScope);
+
+        event::emit(ScopedTokenIssuedEvent {
+            node_id,
+            owner: sender,
+            token_hash,
+            namespaces,
+            resources,
+            verbs,
+            expires_at_ms,
+            timestamp: now,
+        });
+    }
+
+    /// 범위 제한 토큰 폐기 (워커 소유자만)
+    public fun revoke_scoped_token(
+        registry: &WorkerRegistry,
+        node_id: String,
+        token_hash: String,
+        ctx: &mut TxContext
+    ) {
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+        let worker = table::borrow(&registry.workers, node_id);
+        assert!(worker.owner == tx_context::sender(ctx), EUnauthorized);
+
+        event::emit(ScopedTokenRevokedEvent {
+            node_id,
+            token_hash,
+            timestamp: tx_context::epoch_timestamp_ms(ctx),
+        });
+    }
+
     // ==================== View Functions ====================
 
     /// 워커 정보 조회
@@ -371,6 +782,22 @@ module k8s_daas::worker_registry {
         worker.stake_amount
     }
 
+    /// 워커 보관소에 남은 스테이킹 자금 (보관소 도입 전 등록 워커는 0)
+    public fun get_vault_balance(registry: &WorkerRegistry, node_id: String): u64 {
+        let key = StakeVaultKey { node_id };
+        if (!df::exists_(&registry.id, key)) {
+            return 0
+        };
+        balance::value(df::borrow<StakeVaultKey, Balance<SUI>>(&registry.id, key))
+    }
+
+    /// 워커 역할 조회
+    public fun get_worker_role(registry: &WorkerRegistry, node_id: String): String {
+        assert!(table::contains(&registry.workers, node_id), EWorkerNotFound);
+        let worker = table::borrow(&registry.workers, node_id);
+        worker.role
+    }
+
     /// 워커 소유자 확인
     public fun is_worker_owner(registry: &WorkerRegistry, node_id: String, owner: address): bool {
         if (!table::contains(&registry.workers, node_id)) {
@@ -381,6 +808,11 @@ module k8s_daas::worker_registry {
         worker.owner == owner
     }
 
+    /// 레지스트리 관리자 주소 조회 (거버넌스 권한 확인용)
+    public fun get_admin(registry: &WorkerRegistry): address {
+        registry.admin
+    }
+
     /// 워커 소유자 주소 조회
     public fun get_worker_owner(registry: &WorkerRegistry, node_id: String): address {
         let worker = table::borrow(&registry.workers, node_id);
@@ -182,9 +182,9 @@ cat > staker-config.json << EOF
 }
 EOF
 
-# 워커 노드 시작 (Mock 모드)
+# 워커 노드 시작 (마스터가 호스팅하는 mock 체인 사용 - PRIVATE_KEY 없는 마스터는 mock 체인으로 시작)
 echo "🔧 워커 노드 시작 중..."
-MOCK_MODE=true go run main.go > worker.log 2>&1 &
+K3S_DAAS_CHAIN_BACKEND=mock go run main.go > worker.log 2>&1 &
 WORKER_PID=$!
 echo $WORKER_PID > worker.pid
 
@@ -0,0 +1,464 @@
+// Access Review - authentication.k8s.io TokenReview / authorization.k8s.io SubjectAccessReview (웹훅 호환)
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+Seal 세션 토큰과 서비스 계정 JWT를 K8s 인증/인가 API로 노출해, 웹훅 인증기(--authentication-token-webhook)나
+kubectl auth can-i / whoami 같은 표준 도구가 권한을 조회할 수 있게 합니다.
+
+	POST /apis/authentication.k8s.io/v1/tokenreviews              토큰 검증 (관리자 토큰)
+	POST /apis/authentication.k8s.io/v1/selfsubjectreviews        호출자 자신 (kubectl auth whoami)
+	POST /apis/authorization.k8s.io/v1/subjectaccessreviews       임의 사용자 인가 (관리자 토큰)
+	POST /apis/authorization.k8s.io/v1/selfsubjectaccessreviews   호출자 자신 (kubectl auth can-i)
+
+인가 기준 (K8s API 프록시와 같은 규칙):
+  - 대상 클러스터 소유자 지갑(extra daas.k3s.io/owner, 없으면 사용자 자신)이 slashed가 아닌 워커로 최소 티어 이상 스테이킹
+  - 소유자 본인은 전체 허용, 다른 지갑은 RBAC 위임 권한(view/edit)으로 판단
+  - 서비스 계정은 소유자 지갑으로 평가하되 cluster_scope가 아니면 자기 네임스페이스만
+*/
+
+const (
+	tokenReviewPrefix   = "/apis/authentication.k8s.io/"
+	accessReviewPrefix  = "/apis/authorization.k8s.io/"
+	tokenReviewVersion  = "authentication.k8s.io/v1"
+	accessReviewVersion = "authorization.k8s.io/v1"
+	reviewMaxBodyBytes  = 64 * 1024
+
+	reviewOwnerExtra = "daas.k3s.io/owner"
+	reviewNodeExtra  = "daas.k3s.io/node-id"
+	reviewStakeExtra = "daas.k3s.io/stake"
+	reviewTierGroup  = "daas:stake-tier:" // Seal 토큰 사용자의 스테이킹 티어 그룹
+)
+
+// reviewUser - TokenReview status.user / SubjectAccessReview spec의 사용자 정보
+type reviewUser struct {
+	Username string              `json:"username,omitempty"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+}
+
+type reviewMeta struct {
+	Name              string  `json:"name,omitempty"`
+	CreationTimestamp *string `json:"creationTimestamp"`
+}
+
+// tokenReview - authentication.k8s.io/v1 TokenReview
+type tokenReview struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   reviewMeta `json:"metadata"`
+	Spec       struct {
+		Token     string   `json:"token"`
+		Audiences []string `json:"audiences,omitempty"`
+	} `json:"spec"`
+	Status tokenReviewStatus `json:"status"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool        `json:"authenticated"`
+	User          *reviewUser `json:"user,omitempty"`
+	Audiences     []string    `json:"audiences,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
+
+type resourceAttributes struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Verb        string `json:"verb,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type nonResourceAttributes struct {
+	Path string `json:"path,omitempty"`
+	Verb string `json:"verb,omitempty"`
+}
+
+// accessReview - authorization.k8s.io/v1 SubjectAccessReview / SelfSubjectAccessReview
+type accessReview struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   reviewMeta `json:"metadata"`
+	Spec       struct {
+		ResourceAttributes    *resourceAttributes    `json:"resourceAttributes,omitempty"`
+		NonResourceAttributes *nonResourceAttributes `json:"nonResourceAttributes,omitempty"`
+		User                  string                 `json:"user,omitempty"`
+		UID                   string                 `json:"uid,omitempty"`
+		Groups                []string               `json:"groups,omitempty"`
+		Extra                 map[string][]string    `json:"extra,omitempty"`
+	} `json:"spec"`
+	Status accessReviewStatus `json:"status"`
+}
+
+type accessReviewStatus struct {
+	Allowed         bool   `json:"allowed"`
+	Denied          bool   `json:"denied,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+}
+
+// AccessReviewer - 인증/인가 조회 API
+type AccessReviewer struct {
+	logger          *logrus.Logger
+	workerPool      *WorkerPool
+	rbac            *RBACAuthorizer
+	serviceAccounts *ServiceAccountIssuer
+	adminToken      string
+
+	mutex  sync.Mutex
+	counts map[string]int // <kind>/<결과>
+}
+
+// NewAccessReviewer - 인증/인가 조회 API 생성 (serviceAccounts가 nil이면 Seal 토큰만 인증)
+func NewAccessReviewer(logger *logrus.Logger, workerPool *WorkerPool, rbac *RBACAuthorizer, serviceAccounts *ServiceAccountIssuer) *AccessReviewer {
+	return &AccessReviewer{
+		logger:          logger,
+		workerPool:      workerPool,
+		rbac:            rbac,
+		serviceAccounts: serviceAccounts,
+		adminToken:      os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		counts:          make(map[string]int),
+	}
+}
+
+// ServeHTTP - 두 API 그룹 라우팅 (탐색 문서는 인증 없이)
+func (a *AccessReviewer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix, group, version, resources := tokenReviewPrefix, "authentication.k8s.io", tokenReviewVersion, []string{"tokenreviews", "selfsubjectreviews"}
+	if strings.HasPrefix(r.URL.Path, accessReviewPrefix) {
+		prefix, group, version, resources = accessReviewPrefix, "authorization.k8s.io", accessReviewVersion, []string{"subjectaccessreviews", "selfsubjectaccessreviews"}
+	}
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	if rest == "" || rest == "v1" {
+		if r.Method != http.MethodGet {
+			writeK8sStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+			return
+		}
+		writeReviewDiscovery(w, rest, group, version, resources)
+		return
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[0] != "v1" || !containsString(resources, parts[1]) {
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeK8sStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method "+r.Method+" is not supported on "+parts[1])
+		return
+	}
+
+	// self 리뷰는 호출자 토큰이 곧 대상, 나머지는 웹훅 구성의 관리자 토큰 필요
+	var caller *reviewUser
+	if strings.HasPrefix(parts[1], "self") {
+		user, err := a.authenticateToken(requestSealToken(r), "")
+		if err != nil {
+			writeK8sStatus(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+			return
+		}
+		caller = user
+	} else if !a.isAdmin(requestSealToken(r)) {
+		writeK8sStatus(w, http.StatusUnauthorized, "Unauthorized", "an admin token is required to create "+parts[1])
+		return
+	}
+
+	switch parts[1] {
+	case "tokenreviews":
+		a.handleTokenReview(w, r)
+	case "selfsubjectreviews":
+		a.count("SelfSubjectReview", "served")
+		writeK8sObject(w, http.StatusCreated, map[string]interface{}{
+			"kind": "SelfSubjectReview", "apiVersion": tokenReviewVersion,
+			"metadata": reviewMeta{}, "status": map[string]interface{}{"userInfo": caller},
+		})
+	default:
+		a.handleAccessReview(w, r, caller)
+	}
+}
+
+func (a *AccessReviewer) isAdmin(token string) bool {
+	return a.adminToken != "" && token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) == 1
+}
+
+// writeReviewDiscovery - APIGroup / APIResourceList (리뷰 리소스는 create만 지원)
+func writeReviewDiscovery(w http.ResponseWriter, rest, group, groupVersion string, resources []string) {
+	if rest == "" {
+		version := map[string]string{"groupVersion": groupVersion, "version": "v1"}
+		writeK8sObject(w, http.StatusOK, map[string]interface{}{
+			"kind": "APIGroup", "apiVersion": "v1", "name": group,
+			"versions": []map[string]string{version}, "preferredVersion": version,
+		})
+		return
+	}
+	list := make([]map[string]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		kind := map[string]string{
+			"tokenreviews":             "TokenReview",
+			"selfsubjectreviews":       "SelfSubjectReview",
+			"subjectaccessreviews":     "SubjectAccessReview",
+			"selfsubjectaccessreviews": "SelfSubjectAccessReview",
+		}[resource]
+		list = append(list, map[string]interface{}{
+			"name": resource, "singularName": strings.ToLower(kind), "namespaced": false,
+			"kind": kind, "verbs": []string{"create"},
+		})
+	}
+	writeK8sObject(w, http.StatusOK, map[string]interface{}{
+		"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": groupVersion, "resources": list,
+	})
+}
+
+// authenticateToken - Seal 세션 토큰 또는 서비스 계정 JWT를 K8s 사용자 정보로 변환
+// audience가 비어 있으면 K8s API 기본 audience로 검사합니다 (Seal 토큰은 그 audience에만 유효).
+func (a *AccessReviewer) authenticateToken(token, audience string) (*reviewUser, error) {
+	if token == "" {
+		return nil, fmt.Errorf("a seal token or service account token is required")
+	}
+
+	if a.serviceAccounts != nil && strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ") {
+		if audience == "" {
+			audience = a.serviceAccounts.audience
+		}
+		account, err := a.serviceAccounts.Authenticate(token, audience)
+		if err != nil {
+			return nil, err
+		}
+		return &reviewUser{
+			Username: account.username(),
+			UID:      account.UID,
+			Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:" + account.Namespace, "system:authenticated"},
+			Extra:    map[string][]string{reviewOwnerExtra: {account.Owner}},
+		}, nil
+	}
+
+	if audience != "" && a.serviceAccounts != nil && audience != a.serviceAccounts.audience {
+		return nil, fmt.Errorf("seal tokens are only valid for audience %s", a.serviceAccounts.audience)
+	}
+	worker, ok := a.workerPool.WorkerBySealToken(token)
+	if !ok || worker.WorkerAddress == "" {
+		return nil, fmt.Errorf("invalid seal token")
+	}
+	if worker.Status == "slashed" || worker.Status == "unstaked" {
+		return nil, fmt.Errorf("seal token of %s worker %s is no longer accepted", worker.Status, worker.NodeID)
+	}
+
+	groups := []string{"daas:tenants", "system:authenticated"}
+	if tier := stakeTierFor(worker.StakeAmount); tier != "" {
+		groups = append(groups, reviewTierGroup+tier)
+	}
+	return &reviewUser{
+		Username: worker.WorkerAddress,
+		UID:      "seal:" + worker.NodeID,
+		Groups:   groups,
+		Extra: map[string][]string{
+			reviewOwnerExtra: {worker.WorkerAddress},
+			reviewNodeExtra:  {worker.NodeID},
+			reviewStakeExtra: {strconv.FormatUint(worker.StakeAmount, 10)},
+		},
+	}, nil
+}
+
+// handleTokenReview - 토큰 검증 결과는 항상 201 (실패도 status.authenticated=false로 응답)
+func (a *AccessReviewer) handleTokenReview(w http.ResponseWriter, r *http.Request) {
+	var review tokenReview
+	if err := json.NewDecoder(io.LimitReader(r.Body, reviewMaxBodyBytes)).Decode(&review); err != nil {
+		writeK8sStatus(w, http.StatusBadRequest, "BadRequest", "invalid TokenReview: "+err.Error())
+		return
+	}
+	if review.Spec.Token == "" {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", "spec.token: Required value")
+		return
+	}
+
+	review.APIVersion, review.Kind = tokenReviewVersion, "TokenReview"
+	review.Status = tokenReviewStatus{}
+	// audiences 중 하나라도 맞으면 인증 (K8s와 같이 맞은 audience만 돌려줌)
+	audiences := review.Spec.Audiences
+	if len(audiences) == 0 {
+		audiences = []string{""}
+	}
+	var lastErr error
+	for _, audience := range audiences {
+		user, err := a.authenticateToken(review.Spec.Token, audience)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		review.Status.Authenticated = true
+		review.Status.User = user
+		if audience != "" {
+			review.Status.Audiences = []string{audience}
+		} else if a.serviceAccounts != nil {
+			review.Status.Audiences = []string{a.serviceAccounts.audience}
+		}
+		break
+	}
+	if !review.Status.Authenticated {
+		review.Status.Error = lastErr.Error()
+		a.count("TokenReview", "rejected")
+		a.logger.Debugf("🔎 TokenReview rejected: %v", lastErr)
+	} else {
+		a.count("TokenReview", "authenticated")
+	}
+	review.Spec.Token = "" // 토큰은 응답에 되돌려 주지 않음
+	writeK8sObject(w, http.StatusCreated, review)
+}
+
+// handleAccessReview - caller가 nil이 아니면 SelfSubjectAccessReview (spec의 사용자 정보는 무시)
+func (a *AccessReviewer) handleAccessReview(w http.ResponseWriter, r *http.Request, caller *reviewUser) {
+	var review accessReview
+	if err := json.NewDecoder(io.LimitReader(r.Body, reviewMaxBodyBytes)).Decode(&review); err != nil {
+		writeK8sStatus(w, http.StatusBadRequest, "BadRequest", "invalid SubjectAccessReview: "+err.Error())
+		return
+	}
+	if (review.Spec.ResourceAttributes == nil) == (review.Spec.NonResourceAttributes == nil) {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid",
+			"exactly one of spec.resourceAttributes, spec.nonResourceAttributes must be specified")
+		return
+	}
+
+	kind := "SubjectAccessReview"
+	user := reviewUser{Username: review.Spec.User, UID: review.Spec.UID, Groups: review.Spec.Groups, Extra: review.Spec.Extra}
+	if caller != nil {
+		kind = "SelfSubjectAccessReview"
+		user = *caller
+	} else if user.Username == "" {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", "spec.user: Required value")
+		return
+	}
+
+	review.APIVersion, review.Kind = accessReviewVersion, kind
+	review.Status = a.evaluate(user, review.Spec.ResourceAttributes, review.Spec.NonResourceAttributes)
+	if review.Status.Allowed {
+		a.count(kind, "allowed")
+	} else {
+		a.count(kind, "denied")
+	}
+	writeK8sObject(w, http.StatusCreated, review)
+}
+
+// evaluate - 스테이킹과 RBAC 위임 권한으로 인가 판단
+func (a *AccessReviewer) evaluate(user reviewUser, resource *resourceAttributes, nonResource *nonResourceAttributes) accessReviewStatus {
+	requester := user.Username
+	owner := requester
+	if owners := user.Extra[reviewOwnerExtra]; len(owners) > 0 && owners[0] != "" {
+		owner = owners[0]
+	}
+
+	// 서비스 계정은 소유자 지갑으로 평가 (네임스페이스 범위는 계정 설정)
+	if strings.HasPrefix(requester, serviceAccountUserPrefix) {
+		if a.serviceAccounts == nil {
+			return accessReviewStatus{Denied: true, Reason: "service accounts are not enabled"}
+		}
+		var account *ServiceAccount
+		ref := strings.SplitN(strings.TrimPrefix(requester, serviceAccountUserPrefix), ":", 2)
+		if len(ref) == 2 {
+			account, _ = a.serviceAccounts.get(ref[0], ref[1])
+		}
+		if account == nil {
+			return accessReviewStatus{Denied: true, Reason: "service account " + requester + " does not exist"}
+		}
+		if resource != nil && !account.ClusterScope && resource.Namespace != account.Namespace {
+			return accessReviewStatus{Denied: true, Reason: fmt.Sprintf("%s may only access namespace %s", requester, account.Namespace)}
+		}
+		if nonResource != nil && !account.ClusterScope {
+			return accessReviewStatus{Denied: true, Reason: requester + " is limited to namespace " + account.Namespace}
+		}
+		requester, owner = account.Owner, account.Owner
+	}
+
+	stake := a.ownerStake(owner)
+	if stakeTierFor(stake) == "" {
+		return accessReviewStatus{Reason: fmt.Sprintf("cluster owner %s has no active stake meeting the minimum tier", owner)}
+	}
+
+	if nonResource != nil {
+		// 탐색/상태 경로 읽기만 허용 (쓰기 가능한 비리소스 경로는 없음)
+		if verbMethod(nonResource.Verb) != http.MethodGet {
+			return accessReviewStatus{Reason: "only get is allowed on non-resource paths"}
+		}
+		return accessReviewStatus{Allowed: true, Reason: "staked tenant may read " + nonResource.Path}
+	}
+
+	method := verbMethod(resource.Verb)
+	name := resource.Resource
+	if a.rbac == nil {
+		if strings.EqualFold(owner, requester) {
+			return accessReviewStatus{Allowed: true, Reason: "cluster owner"}
+		}
+		return accessReviewStatus{Reason: "access delegation is not enabled"}
+	}
+	if err := a.rbac.Authorize(owner, requester, method, name, resource.Namespace); err != nil {
+		return accessReviewStatus{Reason: err.Error()}
+	}
+	if strings.EqualFold(owner, requester) {
+		return accessReviewStatus{Allowed: true, Reason: fmt.Sprintf("cluster owner (stake tier %s)", stakeTierFor(stake))}
+	}
+	return accessReviewStatus{Allowed: true, Reason: "access grant from " + owner}
+}
+
+// ownerStake - slashed/unstaked가 아닌 워커의 스테이킹 합계
+func (a *AccessReviewer) ownerStake(owner string) uint64 {
+	var total uint64
+	for _, worker := range a.workerPool.ListWorkers() {
+		if strings.EqualFold(worker.WorkerAddress, owner) && worker.Status != "slashed" && worker.Status != "unstaked" {
+			total += worker.StakeAmount
+		}
+	}
+	return total
+}
+
+// verbMethod - K8s 동사를 RBAC 위임 권한이 쓰는 HTTP 메서드로 변환
+func verbMethod(verb string) string {
+	switch strings.ToLower(verb) {
+	case "get", "list", "watch":
+		return http.MethodGet
+	case "create":
+		return http.MethodPost
+	case "update":
+		return http.MethodPut
+	case "patch":
+		return http.MethodPatch
+	case "delete", "deletecollection":
+		return http.MethodDelete
+	default:
+		return strings.ToUpper(verb)
+	}
+}
+
+func (a *AccessReviewer) count(kind, result string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.counts[kind+"/"+result]++
+}
+
+// writeMetrics - 리뷰 종류별 결과 수
+func (a *AccessReviewer) writeMetrics(w io.Writer) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_access_reviews_total", "counter", "TokenReview and SubjectAccessReview requests by kind and result")
+	for _, key := range []string{
+		"TokenReview/authenticated", "TokenReview/rejected", "SelfSubjectReview/served",
+		"SubjectAccessReview/allowed", "SubjectAccessReview/denied",
+		"SelfSubjectAccessReview/allowed", "SelfSubjectAccessReview/denied",
+	} {
+		parts := strings.SplitN(key, "/", 2)
+		writeMetric(w, "nautilus_access_reviews_total", map[string]string{"kind": parts[0], "result": parts[1]}, float64(a.counts[key]))
+	}
+}
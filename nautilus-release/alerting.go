@@ -0,0 +1,882 @@
+// Alerting - 메트릭/이벤트 기반 선언적 알림 규칙과 웹훅/Slack/PagerDuty 알림 (중복 제거, 사일런스)
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	alertQueueSize        = 256
+	alertMaxBodyBytes     = 64 * 1024
+	pagerDutyEventsURL    = "https://events.pagerduty.com/v2/enqueue"
+	alertSeverityInfo     = "info"
+	alertSeverityWarning  = "warning"
+	alertSeverityCritical = "critical"
+)
+
+var alertSeverityRank = map[string]int{alertSeverityInfo: 0, alertSeverityWarning: 1, alertSeverityCritical: 2}
+
+/*
+AlertRule - 알림 규칙 하나 (metric 또는 event 중 하나)
+
+metric 규칙은 /metrics 출력의 시계열마다 따로 판정합니다. labels가 있으면 라벨 값이 모두 같은 시계열만 보고,
+increase_seconds가 있으면 그 기간의 증가량(카운터 리셋은 현재 값)을 threshold와 비교합니다.
+조건이 for_seconds 동안 이어지면 발생(firing), 조건이 풀리거나 시계열이 사라지면 해소(resolved) 알림을 보냅니다.
+
+event 규칙은 이벤트 스트림의 타입(범주 또는 정확한 타입, 예: slashing, node.left)과 match의 데이터 필드가
+일치하면 바로 발생하며, 같은 노드/종류는 반복 주기 동안 한 번만 보냅니다.
+
+summary의 {node}, {value}, {라벨 또는 데이터 필드}는 값으로 바뀝니다.
+*/
+type AlertRule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"` // info, warning, critical
+	Summary  string `json:"summary"`
+	Disabled bool   `json:"disabled,omitempty"` // 같은 이름의 기본 규칙 끄기
+
+	Metric          string            `json:"metric,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Op              string            `json:"op,omitempty"` // >, >=, <, <=, ==, !=
+	Threshold       float64           `json:"threshold"`
+	IncreaseSeconds int               `json:"increase_seconds,omitempty"`
+	ForSeconds      int               `json:"for_seconds,omitempty"`
+
+	Event string            `json:"event,omitempty"`
+	Match map[string]string `json:"match,omitempty"`
+}
+
+// AlertSink - 알림 대상 (webhook: 알림 JSON, slack: incoming webhook, pagerduty: Events API v2)
+type AlertSink struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	URL         string `json:"url,omitempty"`
+	RoutingKey  string `json:"routing_key,omitempty"`  // pagerduty
+	MinSeverity string `json:"min_severity,omitempty"` // 이보다 낮은 심각도는 보내지 않음
+}
+
+// alertConfig - NAUTILUS_ALERT_RULES_FILE 형식
+type alertConfig struct {
+	Rules []AlertRule `json:"rules"`
+	Sinks []AlertSink `json:"sinks"`
+}
+
+// Alert - 발생 중이거나 대기 중인 알림
+type Alert struct {
+	Fingerprint  string            `json:"fingerprint"`
+	Rule         string            `json:"rule"`
+	Severity     string            `json:"severity"`
+	Summary      string            `json:"summary"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Value        float64           `json:"value"`
+	State        string            `json:"state"` // pending, firing, resolved
+	StartsAt     time.Time         `json:"starts_at"`
+	EndsAt       time.Time         `json:"ends_at,omitempty"`
+	LastNotified time.Time         `json:"last_notified,omitempty"`
+	SilencedBy   string            `json:"silenced_by,omitempty"`
+}
+
+// AlertSilence - 일치하는 알림을 기간 동안 보내지 않음 (rule, severity 또는 라벨 이름으로 매칭)
+type AlertSilence struct {
+	ID        string            `json:"id"`
+	Matchers  map[string]string `json:"matchers"`
+	StartsAt  time.Time         `json:"starts_at"`
+	EndsAt    time.Time         `json:"ends_at"`
+	Comment   string            `json:"comment,omitempty"`
+	CreatedBy string            `json:"created_by,omitempty"`
+}
+
+func (s *AlertSilence) matches(alert *Alert, now time.Time) bool {
+	if now.Before(s.StartsAt) || !now.Before(s.EndsAt) {
+		return false
+	}
+	for key, value := range s.Matchers {
+		actual := alert.Labels[key]
+		switch key {
+		case "rule":
+			actual = alert.Rule
+		case "severity":
+			actual = alert.Severity
+		}
+		if actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+type alertNotification struct {
+	alert  Alert
+	status string // firing, resolved
+}
+
+type timedValue struct {
+	at    time.Time
+	value float64
+}
+
+/*
+AlertManager - 규칙 판정, 중복 제거, 사일런스, 알림 전송
+
+	NAUTILUS_ALERT_RULES_FILE             규칙/대상 JSON ({"rules": [...], "sinks": [...]}, 같은 이름은 기본 규칙 대체)
+	NAUTILUS_ALERT_WEBHOOK_URL            알림 JSON을 받을 웹훅
+	NAUTILUS_ALERT_SLACK_WEBHOOK          Slack incoming webhook
+	NAUTILUS_ALERT_PAGERDUTY_ROUTING_KEY  PagerDuty Events API v2 routing key (critical만 전송)
+	NAUTILUS_ALERT_EVAL_INTERVAL_SECONDS  metric 규칙 판정 주기 (기본 30)
+	NAUTILUS_ALERT_REPEAT_INTERVAL_SECONDS 계속 발생 중인 알림을 다시 보내는 주기 (기본 14400)
+
+대상이 없어도 발생한 알림은 로그와 /api/v1/admin/alerts로 확인할 수 있습니다.
+*/
+type AlertManager struct {
+	logger     *logrus.Logger
+	metrics    *MetricsRegistry
+	adminToken string
+	rules      []AlertRule
+	sinks      []AlertSink
+	interval   time.Duration
+	repeat     time.Duration
+	stateFile  string
+	client     *http.Client
+	queue      chan alertNotification
+
+	mutex      sync.Mutex
+	alerts     map[string]*Alert
+	history    map[string][]timedValue // increase 규칙의 시계열별 과거 값
+	silences   []*AlertSilence
+	delivered  map[string]int // <대상>/<sent|failed>
+	suppressed map[string]int // <silenced|deduplicated>/<규칙>
+	dropped    int
+}
+
+// NewAlertManager - 기본 규칙과 설정 파일/환경변수의 대상으로 알림 관리자 생성 (잘못된 규칙은 오류)
+func NewAlertManager(logger *logrus.Logger, metrics *MetricsRegistry) (*AlertManager, error) {
+	config := alertConfig{Rules: defaultAlertRules()}
+	if path := os.Getenv("NAUTILUS_ALERT_RULES_FILE"); path != "" {
+		var file alertConfig
+		found, err := loadJSONState(path, &file)
+		if err != nil || !found {
+			return nil, fmt.Errorf("failed to load alert rules %s: %v", path, err)
+		}
+		config.Rules = mergeAlertRules(config.Rules, file.Rules)
+		config.Sinks = file.Sinks
+	}
+	if url := os.Getenv("NAUTILUS_ALERT_WEBHOOK_URL"); url != "" {
+		config.Sinks = append(config.Sinks, AlertSink{Name: "webhook", Type: "webhook", URL: url})
+	}
+	if url := os.Getenv("NAUTILUS_ALERT_SLACK_WEBHOOK"); url != "" {
+		config.Sinks = append(config.Sinks, AlertSink{Name: "slack", Type: "slack", URL: url})
+	}
+	if key := os.Getenv("NAUTILUS_ALERT_PAGERDUTY_ROUTING_KEY"); key != "" {
+		config.Sinks = append(config.Sinks, AlertSink{Name: "pagerduty", Type: "pagerduty", RoutingKey: key, MinSeverity: alertSeverityCritical})
+	}
+	for _, rule := range config.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for i := range config.Sinks {
+		if err := config.Sinks[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &AlertManager{
+		logger:     logger,
+		metrics:    metrics,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		rules:      config.Rules,
+		sinks:      config.Sinks,
+		interval:   envSeconds("NAUTILUS_ALERT_EVAL_INTERVAL_SECONDS", 30),
+		repeat:     envSeconds("NAUTILUS_ALERT_REPEAT_INTERVAL_SECONDS", 4*3600),
+		stateFile:  statePath("alert-silences.json"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan alertNotification, alertQueueSize),
+		alerts:     make(map[string]*Alert),
+		history:    make(map[string][]timedValue),
+		delivered:  make(map[string]int),
+		suppressed: make(map[string]int),
+	}
+	if _, err := loadJSONState(m.stateFile, &m.silences); err != nil {
+		logger.Warnf("⚠️ Failed to load alert silences: %v", err)
+	}
+	return m, nil
+}
+
+// defaultAlertRules - 설정 없이도 켜지는 규칙 (노드 슬래싱, 리전 마스터 장애, DLQ 증가, 이벤트 루프 멈춤, 준비 상태, 지갑 잔액)
+func defaultAlertRules() []AlertRule {
+	return []AlertRule{
+		{Name: "NodeSlashed", Severity: alertSeverityCritical, Event: streamSlashing + ".status",
+			Match: map[string]string{"status": "slashed"}, Summary: "Node {node} was slashed"},
+		{Name: "FederationPeerDown", Severity: alertSeverityWarning, Metric: "nautilus_federation_peer_healthy",
+			Op: "==", Threshold: 0, ForSeconds: 300, Summary: "Region {region} master unreachable for 5 minutes"},
+		{Name: "DeadLetterQueueGrowing", Severity: alertSeverityWarning, Metric: "nautilus_dead_letters_total",
+			Labels: map[string]string{"outcome": "added"}, Op: ">=", Threshold: 5, IncreaseSeconds: 900,
+			Summary: "{value} contract events dead-lettered in the last 15 minutes"},
+		{Name: "EventLoopStalled", Severity: alertSeverityCritical, Metric: "nautilus_event_loop_stalled",
+			Op: "==", Threshold: 1, Summary: "Event {loop} loop stalled"},
+		{Name: "MasterNotReady", Severity: alertSeverityCritical, Metric: "nautilus_ready",
+			Op: "==", Threshold: 0, ForSeconds: 600, Summary: "Master has not been ready for 10 minutes"},
+		{Name: "WalletBalanceLow", Severity: alertSeverityWarning, Metric: "nautilus_wallet_below_threshold",
+			Labels: map[string]string{"threshold": "low"}, Op: "==", Threshold: 1, Summary: "Master wallet {address} is running low on gas"},
+		{Name: "WalletBalanceCritical", Severity: alertSeverityCritical, Metric: "nautilus_wallet_below_threshold",
+			Labels: map[string]string{"threshold": "critical"}, Op: "==", Threshold: 1, Summary: "Master wallet {address} is almost out of gas"},
+	}
+}
+
+// mergeAlertRules - 같은 이름의 기본 규칙은 설정으로 대체, disabled면 제거
+func mergeAlertRules(defaults, configured []AlertRule) []AlertRule {
+	byName := make(map[string]int)
+	merged := append([]AlertRule(nil), defaults...)
+	for i, rule := range merged {
+		byName[rule.Name] = i
+	}
+	for _, rule := range configured {
+		if i, ok := byName[rule.Name]; ok {
+			merged[i] = rule
+			continue
+		}
+		byName[rule.Name] = len(merged)
+		merged = append(merged, rule)
+	}
+	enabled := merged[:0]
+	for _, rule := range merged {
+		if !rule.Disabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+func (r AlertRule) validate() error {
+	if r.Disabled {
+		return nil
+	}
+	switch {
+	case r.Name == "":
+		return fmt.Errorf("alert rule without name")
+	case (r.Metric == "") == (r.Event == ""):
+		return fmt.Errorf("alert rule %s: exactly one of metric or event is required", r.Name)
+	}
+	if _, ok := alertSeverityRank[r.Severity]; !ok {
+		return fmt.Errorf("alert rule %s: severity must be info, warning or critical", r.Name)
+	}
+	if r.Metric != "" {
+		if _, ok := compareAlertValue(r.Op, 0, 0); !ok {
+			return fmt.Errorf("alert rule %s: invalid op %q", r.Name, r.Op)
+		}
+		if r.IncreaseSeconds < 0 || r.ForSeconds < 0 {
+			return fmt.Errorf("alert rule %s: negative duration", r.Name)
+		}
+	}
+	return nil
+}
+
+func (s *AlertSink) validate() error {
+	if s.Name == "" {
+		s.Name = s.Type
+	}
+	if s.MinSeverity != "" {
+		if _, ok := alertSeverityRank[s.MinSeverity]; !ok {
+			return fmt.Errorf("alert sink %s: min_severity must be info, warning or critical", s.Name)
+		}
+	}
+	switch s.Type {
+	case "webhook", "slack":
+		if s.URL == "" {
+			return fmt.Errorf("alert sink %s: url is required", s.Name)
+		}
+	case "pagerduty":
+		if s.RoutingKey == "" {
+			return fmt.Errorf("alert sink %s: routing_key is required", s.Name)
+		}
+		if s.URL == "" {
+			s.URL = pagerDutyEventsURL
+		}
+	default:
+		return fmt.Errorf("alert sink %s: type must be webhook, slack or pagerduty", s.Name)
+	}
+	return nil
+}
+
+// compareAlertValue - 비교 연산 (알 수 없는 연산이면 false, false)
+func compareAlertValue(op string, value, threshold float64) (bool, bool) {
+	switch op {
+	case ">":
+		return value > threshold, true
+	case ">=":
+		return value >= threshold, true
+	case "<":
+		return value < threshold, true
+	case "<=":
+		return value <= threshold, true
+	case "==":
+		return value == threshold, true
+	case "!=":
+		return value != threshold, true
+	}
+	return false, false
+}
+
+// Start - metric 규칙 주기 판정과 알림 전송
+func (m *AlertManager) Start(ctx context.Context) {
+	m.logger.Infof("🔔 Starting alert manager (%d rules, %d sinks)...", len(m.rules), len(m.sinks))
+	go m.deliver(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evaluate(time.Now())
+		}
+	}
+}
+
+// metricSample - /metrics 출력의 샘플 한 줄
+type metricSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parseMetricSamples - Prometheus 텍스트 형식 파싱 (주석과 해석할 수 없는 줄은 건너뜀)
+func parseMetricSamples(text string) []metricSample {
+	var samples []metricSample
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sample := metricSample{labels: map[string]string{}}
+		rest := line
+		if i := strings.IndexAny(line, "{ "); i < 0 {
+			continue
+		} else {
+			sample.name, rest = line[:i], line[i:]
+		}
+		if strings.HasPrefix(rest, "{") {
+			rest = rest[1:]
+			for !strings.HasPrefix(rest, "}") {
+				key, after, ok := strings.Cut(rest, "=")
+				if !ok {
+					break
+				}
+				quoted, err := strconv.QuotedPrefix(after)
+				if err != nil {
+					break
+				}
+				value, _ := strconv.Unquote(quoted)
+				sample.labels[key] = value
+				rest = strings.TrimPrefix(after[len(quoted):], ",")
+			}
+			if !strings.HasPrefix(rest, "}") {
+				continue
+			}
+			rest = rest[1:]
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+		if err != nil {
+			continue
+		}
+		sample.value = value
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// evaluate - metric 규칙 판정 (수집기 출력은 잠금 밖에서 만듦, 알림 관리자도 수집기이므로)
+func (m *AlertManager) evaluate(now time.Time) {
+	var buf bytes.Buffer
+	m.metrics.writeAll(&buf)
+	samples := parseMetricSamples(buf.String())
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	seen := make(map[string]bool)
+	for _, rule := range m.rules {
+		if rule.Metric == "" {
+			continue
+		}
+		for _, sample := range samples {
+			if sample.name != rule.Metric || !labelsMatch(sample.labels, rule.Labels) {
+				continue
+			}
+			fingerprint := alertFingerprint(rule.Name, sample.labels)
+			seen[fingerprint] = true
+			value := sample.value
+			if rule.IncreaseSeconds > 0 {
+				value = m.increaseLocked(fingerprint, now, sample.value, time.Duration(rule.IncreaseSeconds)*time.Second)
+			}
+			active, _ := compareAlertValue(rule.Op, value, rule.Threshold)
+			m.updateLocked(rule, fingerprint, sample.labels, value, active, now)
+		}
+	}
+
+	// 시계열이 사라진 metric 알림은 해소, 반복 주기가 지난 event 알림은 정리
+	for fingerprint, alert := range m.alerts {
+		if rule := m.rule(alert.Rule); rule != nil && rule.Metric != "" && !seen[fingerprint] {
+			m.updateLocked(*rule, fingerprint, alert.Labels, alert.Value, false, now)
+		} else if rule == nil || (rule.Event != "" && now.Sub(alert.StartsAt) > m.repeat) {
+			delete(m.alerts, fingerprint)
+		}
+	}
+	for fingerprint := range m.history {
+		if !seen[fingerprint] {
+			delete(m.history, fingerprint)
+		}
+	}
+	m.pruneSilencesLocked(now)
+}
+
+func (m *AlertManager) rule(name string) *AlertRule {
+	for i := range m.rules {
+		if m.rules[i].Name == name {
+			return &m.rules[i]
+		}
+	}
+	return nil
+}
+
+// increaseLocked - window 동안의 증가량 (window보다 오래 본 값 중 가장 최근 값 기준, 리셋이면 현재 값)
+func (m *AlertManager) increaseLocked(fingerprint string, now time.Time, value float64, window time.Duration) float64 {
+	history := append(m.history[fingerprint], timedValue{at: now, value: value})
+	base := history[0]
+	keep := 0
+	for i, point := range history {
+		if now.Sub(point.at) >= window {
+			base = point
+			keep = i
+		}
+	}
+	m.history[fingerprint] = history[keep:]
+	if value < base.value {
+		return value
+	}
+	return value - base.value
+}
+
+// updateLocked - 시계열 하나의 조건 반영 (pending → firing → resolved)
+func (m *AlertManager) updateLocked(rule AlertRule, fingerprint string, labels map[string]string, value float64, active bool, now time.Time) {
+	alert := m.alerts[fingerprint]
+	if !active {
+		if alert != nil {
+			delete(m.alerts, fingerprint)
+			if alert.State == "firing" && !alert.LastNotified.IsZero() {
+				alert.State = "resolved"
+				alert.EndsAt = now
+				m.notifyLocked(alert, now)
+			}
+		}
+		return
+	}
+
+	if alert == nil {
+		alert = &Alert{
+			Fingerprint: fingerprint,
+			Rule:        rule.Name,
+			Severity:    rule.Severity,
+			Labels:      labels,
+			State:       "pending",
+			StartsAt:    now,
+		}
+		m.alerts[fingerprint] = alert
+	}
+	alert.Value = value
+	alert.Summary = expandAlertSummary(rule, labels, value)
+
+	switch {
+	case alert.State == "pending" && now.Sub(alert.StartsAt) >= time.Duration(rule.ForSeconds)*time.Second:
+		alert.State = "firing"
+		m.notifyLocked(alert, now)
+	case alert.State == "firing" && (alert.SilencedBy != "" || now.Sub(alert.LastNotified) >= m.repeat):
+		// 사일런스 중인 알림은 매 판정마다 다시 확인 (사일런스가 끝나면 바로 전송)
+		m.notifyLocked(alert, now)
+	}
+}
+
+// ObserveEvent - 이벤트 스트림 발행 시 event 규칙 판정 (구독자가 없어도 호출됨, nil이면 무시)
+func (m *AlertManager) ObserveEvent(eventType, nodeID string, data interface{}) {
+	if m == nil {
+		return
+	}
+	fields, _ := data.(map[string]interface{})
+	now := time.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, rule := range m.rules {
+		if rule.Event == "" || (eventType != rule.Event && !strings.HasPrefix(eventType, rule.Event+".")) {
+			continue
+		}
+		labels := map[string]string{"type": eventType}
+		if nodeID != "" {
+			labels["node"] = nodeID
+		}
+		for key, value := range fields {
+			if text, ok := value.(string); ok && text != "" {
+				labels[key] = text
+			}
+		}
+		if !labelsMatch(labels, rule.Match) {
+			continue
+		}
+		// detail 같은 자유 형식 필드는 중복 판정에서 제외 (같은 노드/종류면 같은 알림)
+		identity := map[string]string{"type": eventType, "node": nodeID, "kind": labels["kind"]}
+		fingerprint := alertFingerprint(rule.Name, identity)
+		if existing := m.alerts[fingerprint]; existing != nil && now.Sub(existing.StartsAt) < m.repeat {
+			m.suppressed["deduplicated/"+rule.Name]++
+			continue
+		}
+		alert := &Alert{
+			Fingerprint: fingerprint,
+			Rule:        rule.Name,
+			Severity:    rule.Severity,
+			Summary:     expandAlertSummary(rule, labels, 1),
+			Labels:      labels,
+			Value:       1,
+			State:       "firing",
+			StartsAt:    now,
+		}
+		m.alerts[fingerprint] = alert
+		m.notifyLocked(alert, now)
+	}
+}
+
+// notifyLocked - 사일런스 확인 후 전송 대기열에 추가
+func (m *AlertManager) notifyLocked(alert *Alert, now time.Time) {
+	for _, silence := range m.silences {
+		if silence.matches(alert, now) {
+			if alert.SilencedBy == "" {
+				m.suppressed["silenced/"+alert.Rule]++
+			}
+			alert.SilencedBy = silence.ID
+			return
+		}
+	}
+	alert.SilencedBy = ""
+	alert.LastNotified = now
+
+	if alert.State == "resolved" {
+		m.logger.Infof("✅ Alert %s resolved: %s", alert.Rule, alert.Summary)
+	} else {
+		m.logger.Warnf("🔔 Alert %s (%s): %s", alert.Rule, alert.Severity, alert.Summary)
+	}
+	if len(m.sinks) == 0 {
+		return
+	}
+	status := "firing"
+	if alert.State == "resolved" {
+		status = "resolved"
+	}
+	select {
+	case m.queue <- alertNotification{alert: *alert, status: status}:
+	default:
+		m.dropped++
+	}
+}
+
+// deliver - 대상별 전송 (심각도 필터, 실패는 기록만 하고 재시도하지 않음 - 계속 발생 중이면 반복 주기에 다시 보냄)
+func (m *AlertManager) deliver(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-m.queue:
+			for _, sink := range m.sinks {
+				if alertSeverityRank[notification.alert.Severity] < alertSeverityRank[sink.MinSeverity] {
+					continue
+				}
+				outcome := "sent"
+				if err := m.send(sink, notification); err != nil {
+					outcome = "failed"
+					m.logger.Warnf("⚠️ Alert sink %s failed for %s: %v", sink.Name, notification.alert.Rule, err)
+				}
+				m.mutex.Lock()
+				m.delivered[sink.Name+"/"+outcome]++
+				m.mutex.Unlock()
+			}
+		}
+	}
+}
+
+// send - 대상 형식에 맞춘 요청 본문
+func (m *AlertManager) send(sink AlertSink, notification alertNotification) error {
+	alert := notification.alert
+	var body interface{}
+	switch sink.Type {
+	case "slack":
+		icon := map[string]string{alertSeverityInfo: "ℹ️", alertSeverityWarning: "⚠️", alertSeverityCritical: "🚨"}[alert.Severity]
+		if notification.status == "resolved" {
+			icon = "✅"
+		}
+		body = map[string]string{"text": fmt.Sprintf("%s [%s] %s: %s", icon, strings.ToUpper(notification.status), alert.Rule, alert.Summary)}
+	case "pagerduty":
+		action := "trigger"
+		if notification.status == "resolved" {
+			action = "resolve"
+		}
+		body = map[string]interface{}{
+			"routing_key":  sink.RoutingKey,
+			"event_action": action,
+			"dedup_key":    alert.Fingerprint,
+			"payload": map[string]interface{}{
+				"summary":        alert.Rule + ": " + alert.Summary,
+				"source":         "nautilus-" + getEnvOrDefault("NAUTILUS_REGION", "default"),
+				"severity":       alert.Severity, // info/warning/critical은 PagerDuty 심각도와 같은 이름
+				"timestamp":      alert.StartsAt.UTC().Format(time.RFC3339),
+				"custom_details": alert.Labels,
+			},
+		}
+	default:
+		body = map[string]interface{}{"status": notification.status, "alert": alert}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := m.client.Post(sink.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// alertFingerprint - 규칙 이름과 정렬한 라벨 (중복 제거 키, PagerDuty dedup_key)
+func alertFingerprint(rule string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key, value := range labels {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + labels[key]
+	}
+	return rule + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// expandAlertSummary - {node}, {value}, {라벨} 치환 (summary가 없으면 규칙 이름)
+func expandAlertSummary(rule AlertRule, labels map[string]string, value float64) string {
+	summary := rule.Summary
+	if summary == "" {
+		summary = rule.Name
+	}
+	pairs := []string{"{value}", strconv.FormatFloat(value, 'g', -1, 64)}
+	for key, label := range labels {
+		pairs = append(pairs, "{"+key+"}", label)
+	}
+	return strings.NewReplacer(pairs...).Replace(summary)
+}
+
+func (m *AlertManager) pruneSilencesLocked(now time.Time) {
+	kept := m.silences[:0]
+	for _, silence := range m.silences {
+		if now.Before(silence.EndsAt) {
+			kept = append(kept, silence)
+		}
+	}
+	if len(kept) != len(m.silences) {
+		m.silences = kept
+		m.saveSilencesLocked()
+	}
+}
+
+func (m *AlertManager) saveSilencesLocked() {
+	if err := saveJSONState(m.stateFile, m.silences); err != nil {
+		m.logger.Warnf("⚠️ Failed to save alert silences: %v", err)
+	}
+}
+
+// Alerts - 대기/발생 중인 알림 (규칙, fingerprint 순)
+func (m *AlertManager) Alerts() []Alert {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	alerts := make([]Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Fingerprint < alerts[j].Fingerprint })
+	return alerts
+}
+
+// authorize - 관리자 토큰 확인 (미설정 403, 불일치 401)
+func (m *AlertManager) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if m.adminToken == "" {
+		http.Error(w, "Alerting admin API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+		m.logger.Warnf("🚫 Unauthorized alerting access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleAlerts - 규칙, 대상, 현재 알림 (/api/v1/admin/alerts)
+func (m *AlertManager) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if !m.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sinks := make([]AlertSink, len(m.sinks))
+	for i, sink := range m.sinks {
+		sinks[i] = AlertSink{Name: sink.Name, Type: sink.Type, MinSeverity: sink.MinSeverity} // URL/키는 비밀이므로 제외
+	}
+	writeClientJSON(w, map[string]interface{}{"rules": m.rules, "sinks": sinks, "alerts": m.Alerts()})
+}
+
+// handleSilences - 사일런스 조회/생성/삭제 (/api/v1/admin/alerts/silences)
+func (m *AlertManager) handleSilences(w http.ResponseWriter, r *http.Request) {
+	if !m.authorize(w, r) {
+		return
+	}
+	now := time.Now()
+
+	switch r.Method {
+	case http.MethodGet:
+		m.mutex.Lock()
+		m.pruneSilencesLocked(now)
+		silences := make([]AlertSilence, len(m.silences))
+		for i, silence := range m.silences {
+			silences[i] = *silence
+		}
+		m.mutex.Unlock()
+		writeClientJSON(w, silences)
+
+	case http.MethodPost:
+		var body struct {
+			Matchers        map[string]string `json:"matchers"`
+			DurationSeconds int               `json:"duration_seconds"`
+			EndsAt          time.Time         `json:"ends_at"`
+			Comment         string            `json:"comment"`
+			CreatedBy       string            `json:"created_by"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, alertMaxBodyBytes)).Decode(&body); err != nil {
+			http.Error(w, "Invalid silence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body.Matchers) == 0 {
+			http.Error(w, "Invalid silence: at least one matcher is required", http.StatusBadRequest)
+			return
+		}
+		if body.EndsAt.IsZero() {
+			body.EndsAt = now.Add(time.Duration(body.DurationSeconds) * time.Second)
+		}
+		if !body.EndsAt.After(now) {
+			http.Error(w, "Invalid silence: ends_at or duration_seconds must be in the future", http.StatusBadRequest)
+			return
+		}
+		silence := &AlertSilence{
+			ID:        randomToken(12),
+			Matchers:  body.Matchers,
+			StartsAt:  now,
+			EndsAt:    body.EndsAt,
+			Comment:   body.Comment,
+			CreatedBy: body.CreatedBy,
+		}
+		m.mutex.Lock()
+		m.silences = append(m.silences, silence)
+		m.saveSilencesLocked()
+		m.mutex.Unlock()
+		m.logger.Infof("🔕 Alert silence %s created until %s: %v", silence.ID, silence.EndsAt.Format(time.RFC3339), silence.Matchers)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeClientJSON(w, silence)
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		m.mutex.Lock()
+		found := false
+		for i, silence := range m.silences {
+			if silence.ID == id {
+				m.silences = append(m.silences[:i], m.silences[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if found {
+			m.saveSilencesLocked()
+		}
+		m.mutex.Unlock()
+		if !found {
+			http.Error(w, "Silence not found", http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, map[string]string{"id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 상태별 알림 수, 대상별 전송 결과, 사일런스/중복 제거
+func (m *AlertManager) writeMetrics(w io.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	states := map[string]int{"pending": 0, "firing": 0}
+	for _, alert := range m.alerts {
+		if alert.SilencedBy == "" {
+			states[alert.State]++
+		}
+	}
+	writeMetricHeader(w, "nautilus_alerts", "gauge", "Alerts by state (silenced alerts excluded)")
+	for _, state := range []string{"pending", "firing"} {
+		writeMetric(w, "nautilus_alerts", map[string]string{"state": state}, float64(states[state]))
+	}
+	writeMetricHeader(w, "nautilus_alert_silences", "gauge", "Active and scheduled alert silences")
+	writeMetric(w, "nautilus_alert_silences", nil, float64(len(m.silences)))
+
+	writeMetricHeader(w, "nautilus_alert_notifications_total", "counter", "Alert notifications by sink and outcome")
+	for _, key := range sortedCountKeys(m.delivered) {
+		sink, outcome, _ := strings.Cut(key, "/")
+		writeMetric(w, "nautilus_alert_notifications_total", map[string]string{"sink": sink, "outcome": outcome}, float64(m.delivered[key]))
+	}
+	writeMetricHeader(w, "nautilus_alert_notifications_dropped_total", "counter", "Alert notifications dropped because the delivery queue was full")
+	writeMetric(w, "nautilus_alert_notifications_dropped_total", nil, float64(m.dropped))
+	writeMetricHeader(w, "nautilus_alerts_suppressed_total", "counter", "Alert notifications suppressed by silences or deduplication")
+	for _, key := range sortedCountKeys(m.suppressed) {
+		reason, rule, _ := strings.Cut(key, "/")
+		writeMetric(w, "nautilus_alerts_suppressed_total", map[string]string{"reason": reason, "rule": rule}, float64(m.suppressed[key]))
+	}
+}
+
+func sortedCountKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
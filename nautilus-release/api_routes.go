@@ -0,0 +1,1189 @@
+// API Routes - 마스터 HTTP 경로 등록과 OpenAPI 명세 (/openapi-daas.json)
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	version "github.com/k3s-io/daas-version"
+)
+
+// openAPIPath - 등록된 경로에서 생성한 관리 API 명세
+const openAPIPath = "/openapi-daas.json"
+
+// 명세용 응답 예시 (값이 아니라 타입과 키가 스키마가 됨)
+var okResponse = map[string]interface{}{"status": "success"}
+
+func dataResponse(data interface{}) map[string]interface{} {
+	return map[string]interface{}{"status": "success", "data": data}
+}
+
+type operation = httpserver.Operation
+type param = httpserver.Param
+
+/*
+routes - 모든 마스터 경로 등록
+
+관리 API는 메서드별 Operation을 함께 등록하므로 명세가 실제 라우팅과 어긋나지 않습니다.
+K8s API 프록시, 레지스트리 v2, mock 체인, pprof는 명세 없이 등록됩니다 (Undocumented).
+*/
+func (a *APIServer) routes() *httpserver.Router {
+	router := httpserver.NewRouter("K3s-DaaS Nautilus master API", "v1")
+	router.Handle(openAPIPath, router.SpecHandler(), operation{
+		Summary: "OpenAPI document for the management API", Tags: []string{"meta"}, Response: map[string]interface{}{},
+	})
+
+	// 헬스체크 엔드포인트
+	router.HandleFunc("/healthz", a.handleHealth, operation{
+		Summary: "Liveness", Tags: []string{"health"}, Response: "OK",
+		Query: []param{{Name: "verbose", Type: "boolean", Description: "also list the build version, worker/gateway versions, feature gate states and master wallet balances"}},
+	})
+	router.HandleFunc("/readyz", a.handleReady, operation{
+		Summary: "Readiness (chain synced, K3s running, not draining)", Tags: []string{"health"},
+		Response: "Ready", Errors: []int{http.StatusServiceUnavailable},
+	})
+
+	// 공개 상태 페이지 (인증 없음, 요청 제한)
+	if a.status != nil {
+		router.HandleFunc("/status", a.status.handleStatus, operation{
+			Summary: "Public status page (HTML, or JSON with ?format=json)", Tags: []string{"health"},
+			Query:    []param{{Name: "format", Description: "json for a JSON response"}},
+			Response: dataResponse(PublicStatus{}), Errors: []int{http.StatusTooManyRequests},
+		})
+	}
+	if a.metrics != nil {
+		router.Handle("/metrics", a.metrics, operation{
+			Summary: "Prometheus metrics", Tags: []string{"health"}, Response: "", ContentType: "text/plain; version=0.0.4",
+		})
+	}
+	// 내장 Grafana 대시보드 (위 지표 기준, 관리자 토큰)
+	if a.observability != nil {
+		router.HandleFunc("/api/v1/observability/dashboards", a.observability.handleDashboards, operation{
+			Summary: "Bundled Grafana dashboards", Tags: []string{"health", "admin"},
+			Auth: httpserver.AuthAdminToken, Response: dataResponse([]DashboardInfo{}),
+			Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/observability/dashboards/", a.observability.handleDashboards,
+			operation{
+				Path: "/api/v1/observability/dashboards/{uid}", Summary: "Grafana dashboard JSON for manual import", Tags: []string{"health", "admin"},
+				Auth: httpserver.AuthAdminToken, Response: map[string]interface{}{},
+				Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+			},
+			operation{
+				Path: "/api/v1/observability/dashboards/push", Method: http.MethodPost, Summary: "Create or overwrite the bundled dashboards in GRAFANA_URL", Tags: []string{"health", "admin"},
+				Description: "Calls Grafana's /api/dashboards/db with GRAFANA_API_TOKEN for every bundled dashboard, into GRAFANA_FOLDER_UID if set. " +
+					"Answers 502 with the per-dashboard results if any push failed.",
+				Auth: httpserver.AuthAdminToken, Response: dataResponse([]DashboardPushResult{}),
+				Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusBadGateway, http.StatusServiceUnavailable},
+			})
+	}
+	if a.slo != nil {
+		router.HandleFunc("/api/v1/slo", a.slo.handleSLO, operation{
+			Summary: "Control plane SLO compliance, remaining error budget and burn rates", Tags: []string{"health"},
+			Response: dataResponse([]SLOStatus{}),
+		})
+	}
+
+	// 노드 관리 API
+	register := operation{
+		Method: http.MethodPost, Summary: "Register a worker and receive a single-use K3s join token bound to its node", Tags: []string{"nodes"},
+		Auth:    httpserver.AuthSealToken,
+		Request: map[string]interface{}{"node_id": "", "region": "", "zone": ""},
+		Response: map[string]interface{}{
+			"status": "success", "join_token": "", "token_id": "", "node_id": "", "expires_at": time.Time{}, "server_url": "",
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+	}
+	// 승인 모드에서 심사 중인 워커는 202 + Retry-After (본문은 안내 문구)
+	if a.enrollment != nil {
+		register.Errors = append(register.Errors, http.StatusAccepted)
+	}
+	// 재시도된 등록은 첫 응답(같은 조인 토큰)을 재생
+	var registerHandler http.Handler = http.HandlerFunc(a.handleNodeRegister)
+	if a.idempotency != nil {
+		register.Errors = append(register.Errors, http.StatusConflict, http.StatusUnprocessableEntity)
+		replayed := a.idempotency.Middleware(deriveRegistrationKey)(registerHandler)
+		registerHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				a.handleNodeRegister(w, r)
+				return
+			}
+			replayed.ServeHTTP(w, r)
+		})
+	}
+	router.Handle("/api/v1/nodes/register", registerHandler, register)
+	// 워커 에이전트가 사용하는 이전 경로
+	legacyRegister := register
+	legacyRegister.Summary = "Register a worker (alias of /api/v1/nodes/register)"
+	legacyRegister.Deprecated = true
+	router.Handle("/api/v1/register-worker", registerHandler, legacyRegister)
+	router.HandleFunc("/api/v1/nodes/token", a.handleGetJoinToken, operation{
+		Summary: "Mint a fresh single-use join token for the caller's node (revokes the previous one)", Tags: []string{"nodes"},
+		Auth:     httpserver.AuthSealToken,
+		Query:    []param{{Name: "node_id", Description: "node the Seal token is bound to (default: the token's node)"}},
+		Response: &JoinTokenGrant{},
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+	})
+	router.HandleFunc("/api/v1/nodes/heartbeat", a.handleNodeHeartbeat, operation{
+		Method: http.MethodPost, Summary: "Worker heartbeat (topology, usage, conditions, collector sections)", Tags: []string{"nodes"},
+		Auth: httpserver.AuthSealToken,
+		Request: map[string]interface{}{
+			"node_id": "", "region": "", "zone": "", "os": "", "arch": "", "latency_ms": int64(0), "running_pods": 0,
+			"probe_result": &ProbeResult{}, "stake_status": "", "stake_amount": uint64(0),
+			"resource_usage":        map[string]interface{}{"cpu_percent": 0.0, "memory_percent": 0.0, "disk_percent": 0.0},
+			"node_conditions":       []NodeCondition{},
+			"log_throttling":        []LogThrottle{},
+			"pod_network":           []PodNetworkUsage{},
+			"pod_resources":         []PodResourceUsage{},
+			"collectors":            map[string]json.RawMessage{},
+			"collector_errors":      map[string]string{},
+			"config_version":        int64(0),
+			"config_error":          &WorkerConfigFailure{},
+			"network":               &NetworkReport{},
+			"probe_url":             "",
+			"probe_token":           "",
+			"status_access_version": "",
+			"challenge_answer":      &ChallengeAnswer{},
+		},
+		Response: map[string]interface{}{
+			"status": "success", "probe": &AuditProbe{}, "config": &WorkerConfigUpdate{}, "probe_peers": []ProbePeer{},
+			"status_access": &StatusAccessPolicy{}, "challenge": &HeartbeatChallenge{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+	})
+	if a.network != nil {
+		probeQuery := []param{
+			{Name: "node_id", Required: true, Description: "worker the Seal token is bound to"},
+			{Name: "bytes", Type: "integer", Description: "payload size for GET (default 2MiB, at most 32MiB)"},
+		}
+		router.HandleFunc("/api/v1/nodes/bandwidth-probe", a.network.handleBandwidthProbe, operation{
+			Method: http.MethodGet, Summary: "Download probe payload to measure master to worker bandwidth", Tags: []string{"nodes"},
+			Auth: httpserver.AuthSealToken, Query: probeQuery, Response: "", ContentType: "application/octet-stream",
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		}, operation{
+			Method: http.MethodPost, Summary: "Upload probe payload to measure worker to master bandwidth", Tags: []string{"nodes"},
+			Auth: httpserver.AuthSealToken, Query: probeQuery[:1],
+			Response: map[string]interface{}{"bytes": int64(0), "duration_ms": int64(0)},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		})
+	}
+	router.HandleFunc("/api/v1/nodes/reconcile", a.handleNodeReconcile, operation{
+		Method: http.MethodPost, Summary: "Report pods adopted or stopped after a worker restart", Tags: []string{"nodes"},
+		Auth: httpserver.AuthSealToken,
+		Request: map[string]interface{}{
+			"node_id": "", "adopted": []reconciledPod{}, "stopped": []reconciledPod{}, "kept": []reconciledPod{},
+		},
+		Response: okResponse, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+	})
+	router.HandleFunc("/api/v1/nodes/desired-state", a.handleNodeDesiredState, operation{
+		Summary:     "Full desired state of a worker for resync after a restart or reconnect",
+		Description: "Pods assigned to the node, the current worker config bundle and cordon/maintenance status. The worker stops local pods that are absent or terminating here before resuming heartbeats.",
+		Tags:        []string{"nodes"}, Auth: httpserver.AuthSealToken,
+		Query:    []param{{Name: "node_id", Required: true, Description: "worker the Seal token is bound to"}},
+		Response: dataResponse(&NodeDesiredState{}),
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusServiceUnavailable},
+	})
+	if a.health != nil {
+		router.HandleFunc("/api/v1/nodes/health", a.health.handleNodeHealth, operation{
+			Summary: "Node health scores and probation state", Tags: []string{"nodes"},
+			Response: dataResponse([]NodeHealth{}),
+		})
+	}
+	if a.claims != nil {
+		router.HandleFunc("/api/v1/claims", a.claims.handleClaims, operation{
+			Summary: "Heartbeat claim verification records", Tags: []string{"nodes"},
+			Query:    []param{{Name: "flagged", Description: "true to list only nodes flagged for slashing review", Type: "boolean"}},
+			Response: dataResponse([]ClaimRecord{}),
+		})
+	}
+	if a.challenges != nil {
+		router.HandleFunc("/api/v1/challenges", a.challenges.handleChallenges, operation{
+			Summary: "Heartbeat challenge results per node", Tags: []string{"nodes"},
+			Query:    []param{{Name: "flagged", Description: "true to list only nodes flagged for slashing review", Type: "boolean"}},
+			Response: dataResponse([]ChallengeRecord{}),
+		})
+	}
+	if a.maintenance != nil {
+		router.HandleFunc("/api/v1/nodes/maintenance", a.maintenance.handleMaintenance,
+			operation{
+				Summary: "Scheduled and active maintenance windows", Tags: []string{"nodes"},
+				Response: map[string]interface{}{"status": "success", "windows": []MaintenanceWindow{}},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Schedule maintenance for a worker", Tags: []string{"nodes"},
+				Auth:     httpserver.AuthSealToken,
+				Request:  map[string]interface{}{"node_id": "", "start": time.Time{}, "duration_seconds": int64(0), "reason": ""},
+				Status:   http.StatusCreated,
+				Response: map[string]interface{}{"status": "success", "window": MaintenanceWindow{}},
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Cancel a worker's maintenance", Tags: []string{"nodes"},
+				Auth:     httpserver.AuthSealToken,
+				Query:    []param{{Name: "node_id", Required: true}},
+				Response: okResponse, Errors: []int{http.StatusUnauthorized, http.StatusNotFound},
+			})
+	}
+	// 워커 설정 게시/롤백은 모든 워커의 설정을 바꾸므로 읽기 전용 모드 중 거부 (GET 조회는 허용)
+	if a.workerConfig != nil {
+		router.Handle("/api/v1/worker-config", a.readOnly.Guard("admin", http.HandlerFunc(a.workerConfig.handleWorkerConfig)),
+			operation{
+				Summary: "Current worker config bundle, publish history and per-worker applied versions", Tags: []string{"admin"},
+				Auth: httpserver.AuthAdminToken,
+				Response: map[string]interface{}{
+					"status": "success", "current": &WorkerConfigBundle{}, "history": []WorkerConfigBundle{}, "workers": []WorkerConfigStatus{},
+				},
+				Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Publish a new worker config bundle version", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"settings": WorkerSettings{}, "comment": ""},
+				Status:   http.StatusCreated,
+				Response: map[string]interface{}{"status": "success", "bundle": WorkerConfigBundle{}},
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+			})
+		router.Handle("/api/v1/worker-config/rollback", a.readOnly.Guard("admin", http.HandlerFunc(a.workerConfig.handleRollback)), operation{
+			Method: http.MethodPost, Summary: "Republish the settings of an earlier version as a new version", Tags: []string{"admin"},
+			Auth:     httpserver.AuthAdminToken,
+			Query:    []param{{Name: "version", Required: true, Type: "integer"}, {Name: "comment"}},
+			Status:   http.StatusCreated,
+			Response: map[string]interface{}{"status": "success", "bundle": WorkerConfigBundle{}},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable},
+		})
+	}
+	if a.podLogs != nil {
+		router.HandleFunc("/api/v1/logs", a.podLogs.handleLogs, operation{
+			Summary: "Archived pod logs shipped by workers, by pod, container and time range", Tags: []string{"nodes"},
+			Auth: httpserver.AuthAdminToken,
+			Query: []param{
+				{Name: "pod", Required: true},
+				{Name: "namespace", Description: "default if omitted"},
+				{Name: "container", Description: "all containers of the pod if omitted"},
+				{Name: "node_id", Description: "all nodes if omitted"},
+				{Name: "since", Description: "RFC3339 time or a duration ago such as 1h"},
+				{Name: "until", Description: "RFC3339 time or a duration ago"},
+				{Name: "limit", Type: "integer", Description: "most recent entries to return (default 1000, max 10000)"},
+			},
+			Response: map[string]interface{}{"status": "success", "entries": []PodLogLine{}, "truncated": false},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/logs/segments", a.podLogs.handleSegments, operation{
+			Method: http.MethodPost, Summary: "Upload a sealed gzip pod log segment from a worker", Tags: []string{"nodes"},
+			Auth: httpserver.AuthSealToken,
+			Query: []param{
+				{Name: "node_id", Required: true},
+				{Name: "namespace", Required: true},
+				{Name: "pod", Required: true},
+				{Name: "container", Required: true},
+				{Name: "from", Required: true, Type: "integer", Description: "first entry time (unix nanoseconds)"},
+				{Name: "to", Required: true, Type: "integer", Description: "last entry time (unix nanoseconds)"},
+			},
+			Request:  map[string]interface{}{},
+			Status:   http.StatusCreated,
+			Response: map[string]interface{}{"status": "success"},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		})
+	}
+	if a.history != nil {
+		router.HandleFunc("/api/v1/nodes/", a.history.handleTimeline, operation{
+			Path: "/api/v1/nodes/{node_id}/timeline", Summary: "Heartbeat history and events of a node", Tags: []string{"nodes"},
+			Query: []param{
+				{Name: "since", Description: "duration (6h) or RFC3339 time, default 24h"},
+				{Name: "limit", Description: "maximum heartbeat samples", Type: "integer"},
+			},
+			Response: dataResponse(map[string]interface{}{
+				"node_id": "", "since": time.Time{}, "heartbeats": []HeartbeatSample{}, "events": []NodeEvent{},
+				"summaries": []HeartbeatSummary{},
+			}),
+			Errors: []int{http.StatusBadRequest},
+		})
+	}
+	router.HandleFunc("/api/nodes", a.handleNodes, operation{
+		Summary: "Node overview (placeholder data)", Tags: []string{"nodes"}, Deprecated: true,
+		Response: dataResponse(map[string]interface{}{
+			"master_node":  map[string]interface{}{"name": "", "status": "", "role": ""},
+			"worker_nodes": []map[string]interface{}{{"name": "", "status": "", "role": ""}},
+		}),
+	})
+
+	// 외부 연동 API (토큰 없는 워커 조회, seal 토큰 조회, kubeconfig 발급)
+	if a.clientAPI != nil {
+		listWorkers := operation{
+			Summary: "List workers", Tags: []string{"workers"},
+			Query:    []param{{Name: "status"}, {Name: "role"}},
+			Response: dataResponse([]WorkerView{}),
+		}
+		router.HandleFunc("/api/v1/workers", a.clientAPI.handleWorkers, listWorkers)
+		router.HandleFunc("/api/v1/workers/", a.clientAPI.handleWorkers, operation{
+			Path: "/api/v1/workers/{node_id}", Summary: "Get a worker, including stake amount and tier", Tags: []string{"workers"},
+			Response: dataResponse(WorkerView{}), Errors: []int{http.StatusNotFound},
+		})
+		router.HandleFunc("/api/v1/tokens/introspect", a.clientAPI.handleIntrospect, operation{
+			Summary: "Describe the worker owning the presented seal token, and its scope if it is a scoped token", Tags: []string{"tokens"},
+			Auth: httpserver.AuthSealToken, Response: dataResponse(TokenIntrospection{Scope: &TokenScope{}}),
+		})
+		// 세션 교환 (워커 Seal 토큰 → 네임스페이스/리소스/동사/만료로 제한된 토큰)
+		if a.scopes != nil {
+			router.HandleFunc("/api/v1/tokens/exchange", a.scopes.handleExchange,
+				operation{
+					Method: http.MethodPost, Summary: "Exchange a worker seal token for a token limited to namespaces, resources, verbs and a lifetime", Tags: []string{"tokens"},
+					Description: "Empty lists and \"*\" mean unrestricted. Subresources are separate resources (pods/log, pods/exec). " +
+						"The token is returned only in this response; scoped tokens cannot be exchanged again.",
+					Auth:     httpserver.AuthSealToken,
+					Request:  map[string]interface{}{"namespaces": []string{}, "resources": []string{}, "verbs": []string{}, "ttl_seconds": 0},
+					Response: dataResponse(ScopedTokenGrant{Scope: &TokenScope{}}),
+					Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+				},
+				operation{
+					Method: http.MethodDelete, Summary: "Revoke the presented scoped token", Tags: []string{"tokens"},
+					Auth:     httpserver.AuthSealToken,
+					Response: dataResponse(map[string]bool{"revoked": true}),
+					Errors:   []int{http.StatusNotFound},
+				})
+		}
+		router.HandleFunc("/kubectl/config", a.clientAPI.handleKubectlConfig, operation{
+			Summary: "kubeconfig pointing at the API gateway", Tags: []string{"tokens"},
+			Auth: httpserver.AuthSealToken, Response: "", ContentType: "application/yaml",
+			Errors: []int{http.StatusUnauthorized},
+		})
+	}
+
+	// 멀티 리전 연합 (다른 리전 마스터와 Gateway가 읽는 서명 상태, 전역 조회)
+	if a.federation != nil {
+		router.HandleFunc("/api/v1/federation/state", a.federation.handleState, operation{
+			Summary: "Read-only state of this region, signed for the X-Daas-Signature-Nonce request header",
+			Tags:    []string{"federation"}, Response: &FederationState{},
+		})
+		router.HandleFunc("/api/v1/federation/regions", a.federation.handleRegions, operation{
+			Summary: "Regional masters in the federation with their last sync result", Tags: []string{"federation"},
+			Response: dataResponse([]RegionStatus{}),
+		})
+		router.HandleFunc("/api/v1/federation/workers", a.federation.handleWorkers, operation{
+			Summary: "Workers of every region (peer regions as of their last sync)", Tags: []string{"federation"},
+			Query:    []param{{Name: "region", Description: "only workers managed by this master region"}},
+			Response: dataResponse([]FederatedWorker{}),
+		})
+	}
+
+	// 대시보드 이벤트 스트림 (WebSocket)
+	if a.stream != nil {
+		router.HandleFunc("/api/v1/stream", a.stream.handleStream, operation{
+			Summary: "WebSocket stream of node, pod, slashing and capacity events",
+			Description: "Upgrades to a WebSocket and sends one JSON event per message, starting with a snapshot. " +
+				"Send {\"action\":\"subscribe\",\"types\":[],\"nodes\":[],\"namespaces\":[]} to change the filter. " +
+				"Seal tokens only receive events for nodes owned by the same wallet.",
+			Tags: []string{"stream"}, Auth: httpserver.AuthSealToken,
+			Query: []param{
+				{Name: "types", Description: "comma-separated categories (node, pod, slashing, capacity) or event types"},
+				{Name: "node_id", Description: "comma-separated node IDs"},
+				{Name: "namespace", Description: "comma-separated namespaces for pod events"},
+				{Name: "token", Description: "seal or admin token for browsers that cannot set headers"},
+			},
+			Response: StreamEvent{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusServiceUnavailable},
+		})
+	}
+
+	// 상태 확인 API
+	router.HandleFunc("/api/contract/call", a.handleContractCall, operation{
+		Method: http.MethodPost, Summary: "Pool statistics (placeholder data)", Tags: []string{"chain"}, Deprecated: true,
+		Response: dataResponse(map[string]interface{}{
+			"total_staked": "", "total_workers": 0, "active_workers": 0, "pending_tasks": 0,
+		}),
+	})
+	router.HandleFunc("/api/transactions/history", a.handleTransactionHistory, operation{
+		Summary: "Transaction history (placeholder data)", Tags: []string{"chain"}, Deprecated: true,
+		Response: dataResponse([]map[string]string{}),
+	})
+
+	// 위임 접근 권한 API
+	if a.rbac != nil {
+		router.HandleFunc("/api/v1/rbac/grants", a.rbac.handleGrants,
+			operation{
+				Summary: "Access grants issued by the caller's wallet", Tags: []string{"rbac"},
+				Auth: httpserver.AuthSealToken, Response: dataResponse([]*AccessGrant{}),
+				Errors: []int{http.StatusUnauthorized},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Grant another wallet access to namespaces", Tags: []string{"rbac"},
+				Auth:     httpserver.AuthSealToken,
+				Request:  map[string]interface{}{"grantee": "", "role": "", "namespaces": []string{}, "ttl_hours": 0},
+				Status:   http.StatusCreated,
+				Response: dataResponse(&AccessGrant{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Revoke an access grant", Tags: []string{"rbac"},
+				Auth:     httpserver.AuthSealToken,
+				Query:    []param{{Name: "id", Required: true}},
+				Response: okResponse, Errors: []int{http.StatusUnauthorized, http.StatusNotFound},
+			})
+	}
+
+	// 클러스터 내부 컨트롤러용 서비스 계정 (토큰은 /api/, /apis/ 프록시가 직접 검증)
+	if a.serviceAccounts != nil {
+		router.HandleFunc("/api/v1/serviceaccounts", a.serviceAccounts.handleServiceAccounts,
+			operation{
+				Summary: "Service accounts owned by the caller's wallet", Tags: []string{"rbac"},
+				Auth: httpserver.AuthSealToken, Response: dataResponse([]*ServiceAccount{}),
+				Errors: []int{http.StatusUnauthorized},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Create a service account (existing accounts of the same owner are returned as-is)", Tags: []string{"rbac"},
+				Auth:     httpserver.AuthSealToken,
+				Request:  map[string]interface{}{"namespace": "", "name": "", "cluster_scope": false},
+				Status:   http.StatusCreated,
+				Response: dataResponse(&ServiceAccount{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Delete a service account and revoke its tokens", Tags: []string{"rbac"},
+				Auth:     httpserver.AuthSealToken,
+				Query:    []param{{Name: "namespace", Required: true}, {Name: "name", Required: true}},
+				Response: okResponse, Errors: []int{http.StatusUnauthorized, http.StatusNotFound},
+			})
+		router.HandleFunc("/api/v1/serviceaccounts/token", a.serviceAccounts.handleToken, operation{
+			Method: http.MethodPost, Summary: "Issue a signed service account token (owner seal token, or the account's own token to renew)", Tags: []string{"rbac"},
+			Auth:     httpserver.AuthSealToken,
+			Request:  map[string]interface{}{"namespace": "", "name": "", "audiences": []string{}, "expiration_seconds": int64(0)},
+			Status:   http.StatusCreated,
+			Response: dataResponse(&ServiceAccountToken{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+		})
+		router.HandleFunc("/api/v1/serviceaccounts/jwks", a.serviceAccounts.handleJWKS, operation{
+			Summary: "Public keys for verifying service account tokens", Tags: []string{"rbac"},
+			Response: map[string]interface{}{"issuer": "", "keys": []serviceAccountJWK{}},
+		})
+		// OIDC 발급자 (클라우드 STS가 서비스 계정 토큰을 검증할 때 조회, 디스커버리 경로는 발급자 URL의 경로를 따름)
+		router.HandleFunc(a.serviceAccounts.discoveryPath(), a.serviceAccounts.handleOIDCDiscovery, operation{
+			Summary: "OpenID Connect discovery document of the service account token issuer", Tags: []string{"rbac"},
+			Response: OIDCConfiguration{},
+		})
+		router.HandleFunc(oidcJWKSPath, a.serviceAccounts.handleOIDCJWKS, operation{
+			Summary: "RS256 keys that sign service account tokens, for OIDC federation", Tags: []string{"rbac"},
+			Response: map[string]interface{}{"keys": []serviceAccountJWK{}}, ContentType: "application/jwk-set+json",
+		})
+	}
+
+	// 외부 Secret (워커 secrets 볼륨이 Pod 마운트 시 호출, 본문의 node_id에 묶인 Seal 토큰)
+	if a.secrets != nil {
+		podRequest := map[string]interface{}{"node_id": "", "namespace": "", "pod": "", "pod_uid": ""}
+		withField := func(name string) map[string]interface{} {
+			request := map[string]interface{}{name: ""}
+			for key, value := range podRequest {
+				request[key] = value
+			}
+			return request
+		}
+		router.HandleFunc("/api/v1/secrets/recipient", a.secrets.handleRecipient, operation{
+			Summary: "Master public key for encrypting sealed secrets", Tags: []string{"secrets"},
+			Response: dataResponse(SecretRecipient{}),
+		})
+		router.HandleFunc("/api/v1/secrets/token", a.secrets.handleToken, operation{
+			Method: http.MethodPost, Summary: "Issue a pod service account JWT for external secret stores", Tags: []string{"secrets"},
+			Auth: httpserver.AuthSealToken, Request: withField("audience"), Response: dataResponse(ServiceAccountToken{}),
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/secrets/unseal", a.secrets.handleUnseal, operation{
+			Method: http.MethodPost, Summary: "Decrypt an on-chain sealed secret for a pod on the calling worker", Tags: []string{"secrets"},
+			Auth: httpserver.AuthSealToken, Request: withField("object_id"),
+			Response: dataResponse(map[string]interface{}{"object_id": "", "version": uint64(0), "data": map[string][]byte{}}),
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusBadGateway},
+		})
+	}
+
+	// 데이터 보관 정책과 테넌트 데이터 삭제 (관리자 토큰, 삭제 결과는 TEE 키로 서명한 증명)
+	if a.retention != nil {
+		router.HandleFunc("/api/v1/retention", a.retention.handleRetention,
+			operation{
+				Summary: "Retention period in hours per data class", Tags: []string{"admin"},
+				Auth: httpserver.AuthAdminToken, Response: dataResponse(map[string]int{}),
+				Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPut, Summary: "Change retention of some data classes and prune immediately", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]int{RetentionAuditLogs: 0, RetentionHeartbeatHistory: 0, RetentionUsageRecords: 0},
+				Response: dataResponse(map[string]int{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			})
+		// 테넌트 데이터 삭제는 네임스페이스를 지우므로 읽기 전용 모드 중 거부 (GET 조회는 허용)
+		router.Handle("/api/v1/tenants/purge", a.readOnly.Guard("admin", http.HandlerFunc(a.retention.handlePurge)),
+			operation{
+				Summary: "Signed deletion attestations issued so far", Tags: []string{"admin"},
+				Auth: httpserver.AuthAdminToken, Query: []param{{Name: "tenant"}},
+				Response: dataResponse([]DeletionAttestation{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Delete a tenant's namespaces, secrets, logs and off-chain records", Tags: []string{"admin"},
+				Description: "confirm must repeat the tenant address. Returns a deletion attestation signed with the master's TEE key; " +
+					"data already delivered to external audit sinks and on-chain records are listed as skipped.",
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"tenant": "", "namespaces": []string{}, "confirm": ""},
+				Response: dataResponse(DeletionAttestation{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+			})
+	}
+
+	// 이벤트 확정성 상태 (확정 대기 이벤트, 폐기/되돌림 기록)
+	if a.finality != nil {
+		router.HandleFunc("/api/v1/chain/finality", a.finality.handleFinality, operation{
+			Summary: "Events waiting for checkpoint finality and recent incidents", Tags: []string{"chain"},
+			Response: map[string]interface{}{
+				"status": "success", "latest_checkpoint": uint64(0), "depth": uint64(0),
+				"pending": []pendingEvent{}, "incidents": []finalityIncident{},
+			},
+		})
+	}
+
+	// 온체인 상태 정합성 검사 드리프트 보고서 (관리자 토큰)
+	if a.poolSync != nil {
+		router.HandleFunc("/api/v1/admin/chain-drift", a.poolSync.handleDrift,
+			operation{
+				Summary: "Last chain reconciliation drift report", Tags: []string{"admin", "chain"},
+				Description: "Compares the on-chain worker registry with the worker pool and the cluster. " +
+					"Registry fields and node labels are healed automatically; orphaned pod assignments and unregistered nodes are only reported.",
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse(&DriftReport{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Reconcile with the chain now and return the new drift report", Tags: []string{"admin", "chain"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse(&DriftReport{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusBadGateway},
+			})
+	}
+
+	// 처리할 수 없는 컨트랙트 이벤트 관리 (관리자 토큰)
+	if a.deadLetters != nil {
+		deadLetterID := param{Name: "id", Required: true, Description: "dead letter ID (all to requeue every entry)"}
+		router.HandleFunc("/api/v1/admin/dead-letters", a.deadLetters.handleDeadLetters,
+			operation{
+				Summary: "Contract events that could not be processed", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "id"}, {Name: "type", Description: "event type substring"}},
+				Response: map[string]interface{}{"status": "success", "alert_threshold": 0, "dead_letters": []DeadLetter{}},
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Requeue dead letters", Tags: []string{"admin"},
+				Auth:  httpserver.AuthAdminToken,
+				Query: []param{deadLetterID, {Name: "action", Required: true, Description: "requeue"}},
+				Response: map[string]interface{}{"status": "success", "results": []map[string]interface{}{
+					{"id": "", "processed": false, "error": "", "retries": 0},
+				}},
+				Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Discard a dead letter", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "id", Required: true}},
+				Response: okResponse, Errors: []int{http.StatusUnauthorized, http.StatusNotFound},
+			})
+	}
+
+	// 알림 규칙/현재 알림과 사일런스 (관리자 토큰)
+	if a.alerts != nil {
+		router.HandleFunc("/api/v1/admin/alerts", a.alerts.handleAlerts, operation{
+			Summary: "Alert rules, notification sinks and pending or firing alerts", Tags: []string{"admin"},
+			Auth:     httpserver.AuthAdminToken,
+			Response: dataResponse(map[string]interface{}{"rules": []AlertRule{}, "sinks": []AlertSink{}, "alerts": []Alert{}}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/admin/alerts/silences", a.alerts.handleSilences,
+			operation{
+				Summary: "Active and scheduled alert silences", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse([]AlertSilence{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Silence alerts matching rule, severity or label values", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"matchers": map[string]string{"rule": "", "node": ""}, "duration_seconds": 3600, "ends_at": time.Time{}, "comment": "", "created_by": ""},
+				Response: dataResponse(AlertSilence{}), Status: http.StatusCreated,
+				Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Remove an alert silence", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "id", Required: true, Description: "silence ID"}},
+				Response: dataResponse(map[string]string{"id": ""}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusNotFound},
+			})
+	}
+
+	// 소프트 삭제 휴지통 조회/복원/즉시 삭제 (관리자 토큰)
+	if a.trash != nil {
+		trashID := param{Name: "id", Required: true, Description: "trash entry ID"}
+		router.HandleFunc("/api/v1/admin/trash", a.trash.handleTrash,
+			operation{
+				Summary: "Deleted objects held in the soft-delete trash", Tags: []string{"admin"},
+				Auth: httpserver.AuthAdminToken,
+				Query: []param{
+					{Name: "id", Description: "return one entry including the deleted object"},
+					{Name: "namespace"}, {Name: "resource", Description: "plural resource, e.g. deployments"},
+				},
+				Response: dataResponse([]TrashEntry{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Purge a trash entry before it expires", Tags: []string{"admin"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{trashID},
+				Response: dataResponse(map[string]string{"id": ""}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+			})
+		// 복원은 객체를 다시 만들므로 읽기 전용 모드 중 거부
+		router.Handle("/api/v1/admin/trash/restore", a.readOnly.Guard("admin", http.HandlerFunc(a.trash.handleRestore)), operation{
+			Method: http.MethodPost, Summary: "Recreate a deleted object from the trash", Tags: []string{"admin"},
+			Auth:     httpserver.AuthAdminToken,
+			Query:    []param{trashID},
+			Response: dataResponse(map[string]interface{}{"entry": TrashEntry{}, "object": map[string]interface{}{}}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusBadGateway, http.StatusServiceUnavailable},
+		})
+	}
+
+	// 체인 장애 동안 디스크에 쌓인 감사 앵커/사용량 기록 (관리자 토큰)
+	if a.outbox != nil {
+		router.HandleFunc("/api/v1/admin/chain-outbox", a.outbox.handleOutbox, operation{
+			Summary: "Audit anchors and usage records buffered on disk until the chain accepts them", Tags: []string{"admin"},
+			Auth: httpserver.AuthAdminToken, Response: dataResponse(ChainOutboxStatus{Pending: []ChainRecord{}}),
+			Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+		})
+	}
+
+	// 하트비트 압축 아카이브 (관리자 토큰, 콜드 스토리지의 원본을 기간별로 복원)
+	if a.historyArchive != nil {
+		router.HandleFunc("/api/v1/admin/heartbeat-archive", a.historyArchive.handleArchive,
+			operation{
+				Summary: "Archived heartbeat segments in cold storage", Tags: []string{"admin", "nodes"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "node_id"}},
+				Response: dataResponse(map[string]interface{}{"backend": "", "compact_after_hours": 0, "last_compaction": time.Time{}, "segments": []ArchiveSegment{}}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Compact and archive old heartbeats now", Tags: []string{"admin", "nodes"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse(map[string]interface{}{"backend": "", "compact_after_hours": 0, "last_compaction": time.Time{}, "segments": []ArchiveSegment{}}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			})
+		router.HandleFunc("/api/v1/admin/heartbeat-archive/rehydrate", a.historyArchive.handleRehydrate, operation{
+			Method: http.MethodPost, Summary: "Load raw heartbeats of a node for a time range from cold storage", Tags: []string{"admin", "nodes"},
+			Description: "to defaults to now and from to 24 hours before to. Segments are verified against their recorded SHA-256; " +
+				"truncated is set when NAUTILUS_HEARTBEAT_REHYDRATE_MAX_SAMPLES is reached.",
+			Auth:     httpserver.AuthAdminToken,
+			Request:  map[string]interface{}{"node_id": "", "from": time.Time{}, "to": time.Time{}},
+			Response: dataResponse(&RehydratedHistory{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusBadGateway},
+		})
+	}
+
+	// 인라인 한도를 넘어 오프체인에 저장된 실행 결과 원문 (온체인 output의 blob:sha256= 참조)
+	if a.responses != nil {
+		router.HandleFunc("/api/v1/responses/", a.responses.handleBlob, operation{
+			Path: "/api/v1/responses/{sha256}", Summary: "Full K8s API result body stored off-chain", Tags: []string{"chain"},
+			Response: "", ContentType: "application/octet-stream",
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		})
+	}
+
+	// 인메모리 mock 체인 (워커/API 프록시가 같은 체인을 공유, 관리용 경로는 관리자 토큰)
+	if a.mockChain != nil {
+		router.Handle("/api/v1/mockchain/", http.StripPrefix("/api/v1/mockchain", a.mockChain))
+	}
+
+	// 워커 등록 승인 대기열 (관리자 토큰, NAUTILUS_ENROLLMENT_MODE=approval일 때 심사)
+	if a.enrollment != nil {
+		enrollmentOverview := dataResponse(map[string]interface{}{"mode": "", "rules": EnrollmentRules{}, "enrollments": []Enrollment{}})
+		router.HandleFunc("/api/v1/admin/enrollments", a.enrollment.handleEnrollments,
+			operation{
+				Summary: "Worker enrollment mode, auto-approval rules and review records", Tags: []string{"admin", "nodes"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "status", Description: "pending, approved or rejected"}},
+				Response: enrollmentOverview,
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Approve or reject a pending worker enrollment", Tags: []string{"admin", "nodes"},
+				Description: "Approving adds the worker to the pool so it can fetch a join token. Rejected workers can still be approved later; " +
+					"approved workers cannot be rejected here.",
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"node_id": "", "action": "approve", "note": ""},
+				Response: dataResponse(&Enrollment{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+			},
+			operation{
+				Method: http.MethodPut, Summary: "Replace the enrollment auto-approval rules and re-evaluate pending workers", Tags: []string{"admin", "nodes"},
+				Auth:     httpserver.AuthAdminToken,
+				Request:  EnrollmentRules{},
+				Response: enrollmentOverview,
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			})
+	}
+
+	// 슬래싱 이의 신청 상태 및 증거 API
+	if a.appeals != nil {
+		router.HandleFunc("/api/v1/appeals", a.appeals.handleAppeals, operation{
+			Summary: "Slash appeals and their review state", Tags: []string{"staking"},
+			Query:    []param{{Name: "node_id"}, {Name: "status", Description: "pending, upheld or rejected"}},
+			Response: map[string]interface{}{"status": "success", "appeals": []SlashAppeal{}},
+			Errors:   []int{http.StatusBadRequest},
+		})
+		router.HandleFunc("/api/v1/appeals/evidence", a.appeals.handleEvidence,
+			operation{
+				Summary: "Download an evidence bundle for governance review", Tags: []string{"staking"},
+				Query:    []param{{Name: "digest", Required: true, Description: "sha256:<hex>"}},
+				Response: map[string]interface{}{},
+				Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Upload an evidence bundle for a slash appeal", Tags: []string{"staking"},
+				Auth:     httpserver.AuthSealToken,
+				Query:    []param{{Name: "node_id", Required: true}},
+				Request:  map[string]interface{}{},
+				Status:   http.StatusCreated,
+				Response: map[string]interface{}{"status": "success", "evidence_digest": ""},
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+			})
+	}
+
+	// 포기된 노드와 비상 인출용 미참여 증명 API
+	if a.abandonment != nil {
+		router.HandleFunc("/api/v1/nodes/abandoned", a.abandonment.handleAbandoned, operation{
+			Summary: "Nodes marked abandoned after a prolonged heartbeat absence", Tags: []string{"staking"},
+			Query:    []param{{Name: "owner", Description: "only nodes owned by this wallet"}},
+			Response: dataResponse([]AbandonedNode{}),
+		})
+		router.HandleFunc("/api/v1/nodes/abandoned/attestation", a.abandonment.handleAttestation, operation{
+			Summary: "Signed non-participation attestation for worker_registry::emergency_unstake", Tags: []string{"staking"},
+			Query:    []param{{Name: "node_id", Required: true}},
+			Response: dataResponse(AbandonmentAttestation{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+		})
+	}
+
+	// 용량 예약 조회 API (구매는 capacity_reservations::purchase_reservation 온체인 호출)
+	if a.reservations != nil {
+		router.HandleFunc("/api/v1/reservations", a.reservations.handleReservations, operation{
+			Summary: "Capacity reservations purchased on chain and their usage", Tags: []string{"tenants"},
+			Query: []param{
+				{Name: "namespace", Description: "only reservations for this namespace"},
+				{Name: "tenant", Description: "only reservations purchased by this wallet"},
+			},
+			Response: dataResponse([]CapacityReservation{}),
+		})
+	}
+
+	// 호스트 컴플라이언스 보고서와 정책 API
+	if a.compliance != nil {
+		router.HandleFunc("/api/v1/compliance", a.compliance.handleCompliance, operation{
+			Summary: "Host OS/kernel/runtime compliance of each node against the active policy", Tags: []string{"nodes"},
+			Query:    []param{{Name: "status", Description: "only nodes with this status (compliant, noncompliant, unknown)"}},
+			Response: dataResponse(ComplianceReport{}),
+			Errors:   []int{http.StatusBadRequest},
+		})
+		router.HandleFunc("/api/v1/admin/compliance-policy", a.compliance.handlePolicy,
+			operation{
+				Summary: "Active host compliance policy", Tags: []string{"admin", "nodes"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse(CompliancePolicy{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPut, Summary: "Replace the host compliance policy", Tags: []string{"admin", "nodes"},
+				Description: "Empty fields are not checked. The version is incremented and every node is re-evaluated against its last fingerprint.",
+				Auth:        httpserver.AuthAdminToken,
+				Request:     CompliancePolicy{},
+				Response:    dataResponse(CompliancePolicy{}),
+				Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			})
+	}
+
+	// 봉인 루트 키 에스크로 번들 (복구 엔드포인트는 복구 대기 중에만 별도 리스너로 열림)
+	if a.escrow != nil {
+		router.HandleFunc("/api/v1/admin/escrow", a.escrow.handleEscrow, operation{
+			Summary: "Sealing root escrow bundle with each guardian's sealed share", Tags: []string{"admin"},
+			Description: "Shares are sealed to the guardians' X25519 keys. If the sealing root is lost, the master waits for " +
+				"threshold shares on /api/v1/admin/escrow/recovery before starting (daasctl escrow submit).",
+			Auth:     httpserver.AuthAdminToken,
+			Response: dataResponse(&EscrowBundle{}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusServiceUnavailable},
+		})
+	}
+
+	// 가스 스폰서 API (워커 트랜잭션 가스 대납)
+	if a.sponsor != nil {
+		router.HandleFunc("/api/v1/gas/sponsor", a.sponsor.handleSponsor, operation{
+			Method: http.MethodPost, Summary: "Sponsor gas for an allowed worker transaction", Tags: []string{"chain"},
+			Auth: httpserver.AuthSealToken, Request: SponsorRequest{},
+			Response: dataResponse(&SponsoredTransaction{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+		})
+	}
+
+	// 용량 공급/수요 API
+	if a.capacity != nil {
+		router.HandleFunc("/api/v1/capacity", a.capacity.handleCapacity, operation{
+			Summary: "Cluster capacity supply and unschedulable demand", Tags: []string{"cluster"},
+			Query:    []param{{Name: "refresh", Type: "boolean"}},
+			Response: map[string]interface{}{"status": "success", "data": &CapacitySnapshot{}, "demand_object": ""},
+		})
+	}
+	if a.simulator != nil {
+		router.HandleFunc("/api/v1/schedule/simulate", a.simulator.handleSimulate, operation{
+			Method: http.MethodPost, Summary: "Simulate placement of pods against current capacity without creating them", Tags: []string{"cluster"},
+			Description: "Accepts Pod, Deployment, StatefulSet, ReplicaSet and Job manifests and reports the node each replica would land on, " +
+				"kube-scheduler style reasons for the rest, and the extra nodes and stake needed to fit them.",
+			Request:  map[string]interface{}{"pods": []map[string]interface{}{}},
+			Response: dataResponse(ScheduleSimulation{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusBadGateway, http.StatusServiceUnavailable},
+		})
+	}
+	if a.costs != nil {
+		router.HandleFunc("/api/v1/cost/estimate", a.costs.handleEstimate, operation{
+			Method: http.MethodPost, Summary: "Project resource consumption and SUI cost of manifests from on-chain pricing", Tags: []string{"cluster"},
+			Description: "Sums cpu, memory and storage requests per manifest, prices them per epoch with the on-chain pricing table, " +
+				"and adds the gas of submitting each manifest and recording its result on-chain.",
+			Request:  map[string]interface{}{"manifests": []map[string]interface{}{}, "epochs": 1},
+			Response: dataResponse(CostEstimate{}),
+			Errors:   []int{http.StatusBadRequest, http.StatusBadGateway, http.StatusServiceUnavailable},
+		})
+	}
+	// 스케줄러 익스텐더 (kube-scheduler가 루프백으로 호출, 테넌트 웹훅은 관리자 토큰으로 등록)
+	if a.extender != nil {
+		extenderArgsExample := map[string]interface{}{"Pod": map[string]interface{}{}, "NodeNames": []string{}}
+		router.HandleFunc("/scheduler/extender/filter", a.extender.handleFilter, operation{
+			Method: http.MethodPost, Summary: "kube-scheduler extender filter call (loopback only)", Tags: []string{"cluster"},
+			Request:  extenderArgsExample,
+			Response: extenderFilterResult{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+		})
+		router.HandleFunc("/scheduler/extender/prioritize", a.extender.handlePrioritize, operation{
+			Method: http.MethodPost, Summary: "kube-scheduler extender prioritize call (loopback only)", Tags: []string{"cluster"},
+			Request:  extenderArgsExample,
+			Response: []extenderHostPriority{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden},
+		})
+		router.HandleFunc("/scheduler/extender/bind", a.extender.handleBind, operation{
+			Method: http.MethodPost, Summary: "kube-scheduler extender bind call (loopback only)", Tags: []string{"cluster"},
+			Description: "Only registered with kube-scheduler when a bind plugin other than DefaultBinder is enabled at startup.",
+			Request:     extenderBindingArgs{},
+			Response:    extenderBindingResult{},
+			Errors:      []int{http.StatusBadRequest, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/admin/scheduler-plugins", a.extender.handlePlugins,
+			operation{
+				Summary: "Registered scheduler plugins with their phases, weights and args", Tags: []string{"admin", "cluster"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse([]SchedulerPluginStatus{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPut, Summary: "Replace the scheduler plugin pipeline", Tags: []string{"admin", "cluster"},
+				Description: "List order is execution order and plugins left out are disabled. Scores (0-10) are averaged by weight; " +
+					"weight 0 keeps a plugin out of the score phase. Bind plugin changes apply after a master restart.",
+				Auth:     httpserver.AuthAdminToken,
+				Request:  []SchedulerPluginConfig{},
+				Response: dataResponse([]SchedulerPluginStatus{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			})
+		router.HandleFunc("/api/v1/admin/scheduler-extenders", a.extender.handleWebhooks,
+			operation{
+				Summary: "Scheduler extender webhooks (tokens redacted)", Tags: []string{"admin", "cluster"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: dataResponse([]ExtenderWebhook{}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Add or replace a scheduler extender webhook by name", Tags: []string{"admin", "cluster"},
+				Description: "failure_policy is ignore (fail-open, default) or fail (fail-closed: a failing filter webhook rejects every candidate node). " +
+					"timeout_ms defaults to 1000 and is capped by NAUTILUS_SCHEDULER_EXTENDER_MAX_TIMEOUT.",
+				Auth:     httpserver.AuthAdminToken,
+				Request:  ExtenderWebhook{},
+				Response: dataResponse(ExtenderWebhook{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodDelete, Summary: "Remove a scheduler extender webhook", Tags: []string{"admin", "cluster"},
+				Auth:     httpserver.AuthAdminToken,
+				Query:    []param{{Name: "name", Required: true}},
+				Response: dataResponse(map[string]interface{}{"removed": ""}),
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+			})
+	}
+	// 카나리 가중치 변경은 EndpointSlice를 바꾸므로 읽기 전용 모드 중 거부 (GET 조회는 허용)
+	if a.canaries != nil {
+		router.Handle("/api/v1/canaries", a.readOnly.Guard("admin", http.HandlerFunc(a.canaries.handleCanaries)),
+			operation{
+				Summary: "Canary deployments, requested and effective traffic weights", Tags: []string{"cluster"},
+				Auth: httpserver.AuthAdminToken, Response: dataResponse([]CanaryState{}),
+				Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Shift traffic weight of a service's canary deployment", Tags: []string{"cluster"},
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"namespace": "", "service": "", "weight": 0},
+				Response: dataResponse(CanaryState{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable},
+			})
+	}
+	if a.routing != nil {
+		router.HandleFunc("/api/v1/topology-routes", a.routing.handleTopologyRoutes, operation{
+			Summary: "Topology-aware routing per service, healthy endpoints per zone and fallbacks", Tags: []string{"cluster"},
+			Auth: httpserver.AuthAdminToken, Response: dataResponse([]TopologyRouteState{}),
+			Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+		})
+	}
+
+	// 무중단 업그레이드 API (대기 마스터 인계, watch 북마크)
+	if a.drain != nil {
+		router.HandleFunc("/api/v1/upgrade/handoff", a.drain.handleHandoff, operation{
+			Method: http.MethodPost, Summary: "Receive worker pool and watch bookmarks from a draining master", Tags: []string{"admin"},
+			Auth: httpserver.AuthAdminToken, Request: HandoffState{}, Response: okResponse,
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		})
+		router.HandleFunc("/api/v1/upgrade/bookmarks", a.drain.handleBookmarks, operation{
+			Summary: "Last resourceVersion per watch, for resuming after an upgrade", Tags: []string{"cluster"},
+			Response: dataResponse(map[string]string{}),
+		})
+	}
+
+	// 디버그 API (관리자 토큰 필요)
+	if a.debug != nil {
+		a.debug.Register(router)
+	}
+
+	// 운영자 대시보드 (정적 파일, 데이터는 브라우저가 위 API에서 조회)
+	if a.dashboard != nil {
+		router.HandleFunc("/dashboard/", a.dashboard.handleDashboard, operation{
+			Summary: "Embedded operator dashboard (HTML, redirects to /dashboard/login without a session)", Tags: []string{"dashboard"},
+			Auth: httpserver.AuthAdminToken, Response: "", ContentType: "text/html",
+			Errors: []int{http.StatusSeeOther, http.StatusForbidden},
+		})
+		router.HandleFunc("/dashboard/login", a.dashboard.handleLogin,
+			operation{
+				Summary: "Dashboard sign-in page", Tags: []string{"dashboard"},
+				Response: "", ContentType: "text/html", Errors: []int{http.StatusForbidden},
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Exchange the admin token for a dashboard session cookie", Tags: []string{"dashboard"},
+				Auth:     httpserver.AuthAdminToken,
+				Response: map[string]interface{}{"status": "success", "expires_at": time.Time{}},
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+			})
+		router.HandleFunc("/dashboard/logout", a.dashboard.handleLogout, operation{
+			Method: http.MethodPost, Summary: "End the dashboard session", Tags: []string{"dashboard"},
+			Response: okResponse,
+		})
+	}
+
+	// 워커 이미지 pull 미러 안내 및 내장 pull-through 캐시 (레지스트리 v2 API)
+	if a.registry != nil {
+		router.HandleFunc("/api/v1/registry/mirror", a.registry.handleMirror, operation{
+			Summary: "Registry mirrors for the worker's registries.yaml", Tags: []string{"cluster"},
+			Auth:  httpserver.AuthSealToken,
+			Query: []param{{Name: "node_id", Required: true}},
+			Response: map[string]interface{}{
+				"status": "success", "registry": "", "mirrors": []registryMirror{}, "allowlist": []string{},
+			},
+			Errors: []int{http.StatusUnauthorized, http.StatusNotFound},
+		})
+		router.Handle("/v2/", a.registry)
+	}
+
+	// 부트스트랩 매니페스트 적용 상태
+	if a.bootstrap != nil {
+		router.HandleFunc("/api/v1/bootstrap", a.bootstrap.handleBootstrap, operation{
+			Summary: "Bootstrap manifest apply status", Tags: []string{"cluster"},
+			Response: map[string]interface{}{
+				"status": "success", "directory": "", "complete": false, "pending": 0, "files": []BootstrapFile{},
+			},
+		})
+	}
+
+	// 교차 검증용 마스터 증명 문서 (워커가 epoch마다 검증 후 온체인 기록)
+	if a.signer != nil {
+		router.HandleFunc("/api/v1/attestation", a.signer.handleAttestation, operation{
+			Summary: "Signed attestation document for the given nonce", Tags: []string{"chain"},
+			Query:    []param{{Name: "nonce", Required: true, Description: "16-128 characters"}},
+			Response: dataResponse(&AttestationDocument{}),
+			Errors:   []int{http.StatusBadRequest},
+		})
+		// 빌드 출처 (워커가 서명 검증 후 온체인 build_allowlist와 대조)
+		router.HandleFunc("/api/v1/version", a.signer.handleVersion, operation{
+			Summary: "Build provenance (commit, builder, SBOM digest) signed with the master's enclave key", Tags: []string{"chain"},
+			Query:    []param{{Name: "nonce", Description: "16-128 characters; without it the signed document can be replayed"}},
+			Response: dataResponse(&version.Provenance{}),
+			Errors:   []int{http.StatusBadRequest},
+		})
+	}
+	router.HandleFunc("/api/v1/version/sbom", handleSBOM, operation{
+		Summary: "Module list the binary was built from; its sha256 is the signed sbom_digest", Tags: []string{"chain"},
+		Response: "", ContentType: "text/plain",
+		Errors: []int{http.StatusNotFound},
+	})
+
+	// 사고 대응 읽기 전용 모드 (조회는 공개, 전환은 관리자 토큰)
+	if a.readOnly != nil {
+		router.HandleFunc("/api/v1/admin/read-only", a.readOnly.handleReadOnly,
+			operation{
+				Summary: "Whether the cluster is in emergency read-only mode and why", Tags: []string{"admin", "health"},
+				Response: dataResponse(ReadOnlyState{}),
+			},
+			operation{
+				Method: http.MethodPut, Summary: "Enable or lift emergency read-only mode", Tags: []string{"admin"},
+				Description: "While enabled, mutating kubectl requests get a 503 Status and contract write requests are recorded as failed without running. " +
+					"Reads, watches, heartbeats, metrics and staking events keep working. The state survives restarts.",
+				Auth:     httpserver.AuthAdminToken,
+				Request:  map[string]interface{}{"enabled": true, "reason": ""},
+				Response: dataResponse(ReadOnlyState{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			})
+	}
+
+	// 컨트랙트 패키지 전환 (조회는 워커/Gateway가 활성 패키지 확인용, 제어는 관리자 토큰)
+	if a.migration != nil {
+		router.HandleFunc("/api/v1/contract/migration", a.migration.handleMigration,
+			operation{
+				Summary: "Contract package migration phase, accepted packages and progress", Tags: []string{"chain"},
+				Response: dataResponse(MigrationProgress{}),
+			},
+			operation{
+				Method: http.MethodPost, Summary: "Begin, complete or abort a contract package migration", Tags: []string{"chain"},
+				Auth: httpserver.AuthAdminToken,
+				Request: map[string]interface{}{
+					"action": "begin", "package_id": "", "registry_id": "", "scheduler_id": "", "window_hours": 0, "reason": "",
+				},
+				Response: dataResponse(MigrationProgress{}),
+				Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict},
+			})
+	}
+
+	// K8s API 프록시 (포트 6443으로 포워딩, Gateway 서명 검증 후 테넌트별 요청 제한)
+	k8sProxy := a.createK8sProxy()
+	// 서버 측 dryRun 쓰기는 이벤트 경로와 같은 어드미션을 거친 본문으로 K3s에 dryRun 전달
+	if a.dryRun != nil {
+		k8sProxy = a.dryRun.Middleware(k8sProxy)
+	}
+	// 소프트 삭제: 이름 지정 DELETE가 성공하면 삭제 직전 객체를 휴지통에 보관
+	if a.trash != nil {
+		k8sProxy = a.trash.Middleware(k8sProxy)
+	}
+	if a.quota != nil {
+		k8sProxy = a.quota.Middleware(k8sProxy)
+	}
+	// 저장소/이벤트 구독/증명이 준비되기 전에는 503 (응답 서명 안쪽이라 Gateway가 검증 가능)
+	if a.ready != nil {
+		k8sProxy = a.ready.Middleware(k8sProxy)
+	}
+	// 준비 전 503과 K3s 오류(502 포함)는 kubectl 가용성 SLI에서 나쁜 요청
+	if a.slo != nil {
+		k8sProxy = a.slo.Middleware(k8sProxy)
+	}
+	// 같은 Pod 생성 재시도는 K3s에 다시 보내지 않고 첫 응답 재생 (Idempotency-Key 또는 본문 해시)
+	if a.idempotency != nil {
+		k8sProxy = a.idempotency.Middleware(derivePodCreateKey)(k8sProxy)
+	}
+	// kubectl 버전에 맞춘 Warning 헤더, 제거된 쿼리 파라미터 정리, 폐지 API 버전 매핑 (응답 서명 안쪽)
+	if a.kubectlCompat != nil {
+		k8sProxy = a.kubectlCompat.Middleware(k8sProxy)
+	}
+	// 읽기 전용 모드 중 쓰기 거부 (Gateway 경로와 클러스터 내부 서비스 계정 경로 모두)
+	if a.readOnly != nil {
+		k8sProxy = a.readOnly.Middleware(k8sProxy)
+	}
+	if a.scopes != nil {
+		k8sProxy = a.scopes.Middleware(k8sProxy)
+	}
+	// 서비스 계정 토큰 요청은 Gateway를 거치지 않으므로 서명 검증 없이 토큰으로 인증
+	inCluster := k8sProxy
+	if a.signer != nil {
+		k8sProxy = a.signer.Wrap(k8sProxy)
+	}
+	if a.serviceAccounts != nil {
+		k8sProxy = a.serviceAccounts.Middleware(inCluster, k8sProxy)
+	}
+
+	// 테넌트 사용량 API (워커 Seal 토큰은 직접, Impersonate-User는 Gateway 서명 필요)
+	if a.quota != nil {
+		router.Handle("/api/v1/tenants/usage", a.tenantAuth(a.quota.handleUsage), operation{
+			Summary: "API quota and pod network usage of the caller's wallet", Tags: []string{"tenants"},
+			Auth: httpserver.AuthSealToken, Response: dataResponse(TenantUsage{}),
+			Errors: []int{http.StatusUnauthorized},
+		})
+	}
+	// 테넌트 quota 사용량과 소진 예측 (워커 Seal 토큰은 직접, Impersonate-User는 Gateway 서명 필요)
+	if a.tenantUsage != nil {
+		router.Handle("/api/v1/tenant/usage", a.tenantAuth(a.tenantUsage.handleTenantUsage), operation{
+			Summary: "Per-namespace ResourceQuota usage, 7-day trend and quota exhaustion forecast of the caller's wallet", Tags: []string{"tenants"},
+			Auth: httpserver.AuthSealToken, Response: dataResponse(TenantQuotaReport{}),
+			Errors: []int{http.StatusUnauthorized},
+		})
+		router.HandleFunc("/api/v1/admin/tenant-usage", a.tenantUsage.handleAdminUsage, operation{
+			Summary: "Quota usage and exhaustion forecasts of all tenants, soonest exhaustion first", Tags: []string{"tenants", "admin"},
+			Auth:     httpserver.AuthAdminToken,
+			Query:    []param{{Name: "tenant", Description: "only this tenant wallet"}},
+			Response: dataResponse([]TenantQuotaReport{}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+		})
+	}
+	// 체인 상태 API (kubectl 쓰기 응답 지연의 체인 쪽 원인을 테넌트가 확인)
+	if a.chainHealth != nil {
+		router.Handle("/api/v1/chain/health", a.tenantAuth(a.chainHealth.handleHealth), operation{
+			Summary: "Sui RPC latency, checkpoint lag, event delay and reference gas price seen by the master", Tags: []string{"tenants"},
+			Auth: httpserver.AuthSealToken, Response: dataResponse(ChainHealthReport{}),
+			Errors: []int{http.StatusUnauthorized},
+		})
+	}
+	// kubelet TLS 부트스트랩 CSR은 K3s로 보내지 않고 TEE CA로 직접 발급 (seal 토큰 인증이라 Gateway 서명 불필요)
+	if a.csr != nil {
+		router.Handle(csrAPIPrefix, a.csr)
+	}
+	// TokenReview/SubjectAccessReview는 K3s가 아니라 마스터가 Seal 토큰·스테이킹 기준으로 답함
+	if a.reviews != nil {
+		router.Handle(tokenReviewPrefix, a.reviews)
+		router.Handle(accessReviewPrefix, a.reviews)
+	}
+	router.Handle("/api/", k8sProxy)
+	router.Handle("/apis/", k8sProxy)
+
+	return router
+}
+
+// tenantHandler - 인증된 테넌트 지갑을 받는 핸들러
+type tenantHandler func(w http.ResponseWriter, r *http.Request, tenant string)
+
+/*
+tenantAuth - 테넌트 본인 조회 API 인증
+
+X-Seal-Token이 워커 소유자로 확인될 때만 Gateway 서명 없이 그 소유자 지갑으로 처리합니다 (Impersonate-User는 보지 않음).
+토큰이 없거나 확인되지 않으면 서명 검증을 거친 요청의 Impersonate-User만 신뢰합니다.
+서명 검증이 꺼져 있으면(GATEWAY_PUBLIC_KEY 없음) Impersonate-User는 누구나 보낼 수 있으므로 거부합니다.
+*/
+func (a *APIServer) tenantAuth(handler tenantHandler) http.Handler {
+	var impersonated http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("Impersonate-User")
+		if tenant == "" || !a.signer.verifying() {
+			http.Error(w, "Invalid or missing seal token", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r, tenant)
+	})
+	if a.signer != nil {
+		impersonated = a.signer.Wrap(impersonated)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if owner, ok := a.k3sMgr.workerPool.OwnerBySealToken(r.Header.Get("X-Seal-Token")); ok {
+			handler(w, r, owner)
+			return
+		}
+		impersonated.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/k3s-io/daas-signing"
+	sui "github.com/k3s-io/daas-sui"
+)
+
+// 관리 API 명세/핸들러 일치 테스트
+// /openapi-daas.json의 모든 경로·메서드를 실제 라우터에 요청해
+//   - 명세에 있는 메서드는 405나 미등록 404가 아니고
+//   - 명세에 없는 메서드는 405이며
+//   - 성공한 JSON 응답의 최상위 키는 모두 명세 스키마에 선언되어 있는지 확인합니다.
+// 명세 없이 등록할 수 있는 경로는 undocumentedRoutes뿐입니다.
+
+var undocumentedRoutes = map[string]bool{
+	"/api/":                        true, // K8s API 프록시
+	"/apis/":                       true,
+	"/apis/certificates.k8s.io/":   true, // K8s CSR API (kubelet TLS 부트스트랩)
+	"/apis/authentication.k8s.io/": true, // TokenReview
+	"/apis/authorization.k8s.io/":  true, // SubjectAccessReview
+	"/v2/":                         true, // 레지스트리 pull-through 캐시
+	"/api/v1/mockchain/":           true,
+	"/debug/pprof/":                true,
+	"/debug/pprof/cmdline":         true,
+	"/debug/pprof/profile":         true,
+	"/debug/pprof/symbol":          true,
+	"/debug/pprof/trace":           true,
+}
+
+const (
+	conformanceNode  = "node-conformance"
+	conformanceSeal  = "seal-conformance"
+	conformanceAdmin = "admin-conformance"
+)
+
+// conformanceServer - 모든 관리 API 컴포넌트를 켠 API 서버 (외부 호출 없이 동작하는 구성)
+func conformanceServer(t *testing.T) *APIServer {
+	t.Setenv("NAUTILUS_STATE_DIR", t.TempDir())
+	t.Setenv("NAUTILUS_BOOTSTRAP_DIR", t.TempDir())
+	t.Setenv("NAUTILUS_ADMIN_TOKEN", conformanceAdmin)
+	t.Setenv("NAUTILUS_SIGNING_KEY", "")
+	t.Setenv("GATEWAY_PUBLIC_KEY", "")
+
+	logger := benchLogger()
+	k3sMgr := NewK3sManager(logger)
+	k3sMgr.dataDir = t.TempDir()
+	if err := k3sMgr.workerPool.AddWorker(&WorkerNode{
+		NodeID: conformanceNode, SealToken: conformanceSeal, Status: "active",
+		StakeAmount: 1000000000, WorkerAddress: "0xowner", Role: "worker",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	chainClient := sui.NewReadOnlyClient("", "0xconformance")
+	suiIntegration := &SuiIntegration{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		workerPool:   k3sMgr.workerPool,
+		sealTokenMgr: k3sMgr.sealTokenManager,
+		backend:      sui.NewBackend(chainClient, "", ""),
+		chain:        chainClient,
+		contractAddr: "0xconformance",
+		eventChan:    make(chan *SuiContractEvent, 1),
+		inFlight:     make(map[string]*K8sAPIRequest),
+	}
+
+	keyring, err := NewEnclaveKeyring(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewRequestSigner(logger, keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewAPIServer(logger, k3sMgr)
+	a.history = NewHeartbeatHistory(logger)
+	a.historyArchive, err = NewHeartbeatArchive(logger, a.history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.history.archive = a.historyArchive
+	a.health = NewNodeHealthScorer(logger, k3sMgr)
+	a.claims = NewClaimVerifier(logger, k3sMgr)
+	a.maintenance = NewMaintenanceScheduler(logger, k3sMgr)
+	a.maintenance.history = a.history
+	a.appeals = NewAppealTracker(logger, k3sMgr.workerPool)
+	a.quota = NewTenantThrottler(logger, k3sMgr.workerPool)
+	a.rbac = NewRBACAuthorizer(logger, k3sMgr.workerPool)
+	a.clock = NewClockGuard(logger, suiIntegration)
+	a.deadLetters = NewDeadLetterQueue(logger, suiIntegration)
+	a.outbox = NewChainOutbox(logger, suiIntegration)
+	a.poolSync = NewPoolSync(logger, suiIntegration)
+	suiIntegration.poolSync = a.poolSync
+	a.enrollment, err = NewEnrollmentQueue(logger, suiIntegration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.enrollment.history = a.history
+	suiIntegration.enrollment = a.enrollment
+	a.responses, err = NewResponseStore(logger, suiIntegration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.registry = NewRegistryCache(logger, k3sMgr.workerPool)
+	a.bootstrap = NewBootstrapManager(logger, k3sMgr)
+	a.sponsor = NewGasSponsor(logger, suiIntegration)
+	a.drain = NewDrainer(logger, k3sMgr.workerPool)
+	a.debug = NewDebugServer(logger, k3sMgr, suiIntegration)
+	a.clientAPI = NewClientAPI(logger, k3sMgr.workerPool)
+	a.stream = NewEventStream(logger, k3sMgr)
+	a.dashboard = NewDashboard(logger)
+	a.simulator = NewScheduleSimulator(logger, k3sMgr)
+	a.extender, err = NewSchedulerExtender(logger, k3sMgr, a.simulator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.costs = NewCostEstimator(logger, suiIntegration, k3sMgr.workerPool)
+	a.canaries = NewCanaryController(logger, k3sMgr)
+	a.routing = NewTopologyRouter(logger, k3sMgr, a.canaries)
+	kubeletCA, err := NewKubeletCA(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.csr = NewCSRAPI(logger, k3sMgr.workerPool, kubeletCA)
+	a.statusAccess, err = NewStatusAccess(logger, k3sMgr.workerPool, kubeletCA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.joinTokens = NewJoinTokenIssuer(logger, k3sMgr)
+	a.federation, err = NewFederation(logger, k3sMgr.workerPool, suiIntegration, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.workerConfig = NewWorkerConfigSync(logger)
+	a.workerConfig.history = a.history
+	a.network = NewNetworkProbe(logger, k3sMgr.workerPool)
+	a.podLogs = NewPodLogArchive(logger, k3sMgr.workerPool)
+	a.logThrottle = NewLogThrottleTracker(logger, k3sMgr)
+	a.podLimits = NewPodLimitTracker(logger, k3sMgr)
+	a.netMeter = NewNetworkMeter(logger, k3sMgr, a.quota)
+	a.problems, _ = NewNodeProblemTainter(logger, k3sMgr)
+	a.trash = NewTrashBin(logger, k3sMgr)
+	a.metrics = NewMetricsRegistry()
+	a.alerts, err = NewAlertManager(logger, a.metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.signer = signer
+	a.debug.metrics = a.metrics
+	a.debug.deadLetters = a.deadLetters
+	a.debug.poolSync = a.poolSync
+	a.debug.signer = signer
+	a.debug.logs = newLogRing()
+	a.debug.logControl, err = NewLogControl(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.serviceAccounts, err = NewServiceAccountIssuer(logger, k3sMgr, signer, keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.secrets, err = NewSecretBroker(logger, k3sMgr, suiIntegration, keyring, a.serviceAccounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.reviews = NewAccessReviewer(logger, k3sMgr.workerPool, a.rbac, a.serviceAccounts)
+	a.retention = NewDataRetention(logger, k3sMgr, signer)
+	a.slo = NewSLOTracker(logger)
+	a.idempotency = newIdempotencyCache()
+	a.versions, err = NewVersionSkew(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.kubectlCompat = NewKubectlCompat(logger, k3sMgr)
+	a.abandonment, err = NewAbandonmentTracker(logger, k3sMgr.workerPool, suiIntegration, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.reservations, err = NewCapacityReservations(logger, k3sMgr, suiIntegration, a.simulator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.compliance, err = NewNodeComplianceTracker(logger, k3sMgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.dryRun = NewDryRunAdmission(logger, NewControllerManager(logger, k3sMgr))
+	a.chainHealth = NewChainHealth(logger, suiIntegration)
+	a.observability = NewObservabilityBundle(logger, suiIntegration, k3sMgr.workerPool)
+	a.tenantUsage = NewTenantUsageForecaster(logger, k3sMgr, a.quota)
+	a.readOnly = NewReadOnlyMode(logger)
+	a.scopes = NewTokenScopes(logger, k3sMgr.workerPool)
+	a.clientAPI.scopes = a.scopes
+	a.challenges, err = NewHeartbeatChallenger(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guardian, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guardians := filepath.Join(t.TempDir(), "guardians.json")
+	entry := `[{"name": "conformance", "public_key": "` + base64.StdEncoding.EncodeToString(guardian.PublicKey().Bytes()) + `"}]`
+	if err := os.WriteFile(guardians, []byte(entry), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NAUTILUS_ESCROW_GUARDIANS", guardians)
+	a.escrow, err = NewSealingEscrow(logger, keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.escrow.Ensure(); err != nil {
+		t.Fatal(err)
+	}
+	a.migration, err = NewContractMigration(logger, suiIntegration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suiIntegration.migration = a.migration
+	return a
+}
+
+// conformanceRequest - 명세의 경로 템플릿/필수 파라미터/인증을 채운 요청
+func conformanceRequest(method, path string, op operation) *http.Request {
+	path = strings.ReplaceAll(path, "{node_id}", conformanceNode)
+	query := make([]string, 0, len(op.Query))
+	for _, p := range op.Query {
+		if !p.Required {
+			continue
+		}
+		value := map[string]string{
+			"node_id": conformanceNode,
+			"nonce":   "conformance-nonce-0001",
+			"digest":  "sha256:" + strings.Repeat("0", 64),
+			"action":  "requeue",
+		}[p.Name]
+		if value == "" {
+			value = "missing"
+		}
+		query = append(query, p.Name+"="+value)
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	var body *strings.Reader
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		body = strings.NewReader(`{"node_id":"` + conformanceNode + `"}`)
+	} else {
+		body = strings.NewReader("")
+	}
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Accept", "application/json")
+	switch op.Auth {
+	case "sealToken":
+		req.Header.Set("X-Seal-Token", conformanceSeal)
+	case "adminToken":
+		req.Header.Set("Authorization", "Bearer "+conformanceAdmin)
+	}
+	return req
+}
+
+func TestOpenAPIConformance(t *testing.T) {
+	router := conformanceServer(t).routes()
+
+	for _, pattern := range router.Undocumented() {
+		if !undocumentedRoutes[pattern] {
+			t.Errorf("route %s is registered without an OpenAPI operation", pattern)
+		}
+	}
+
+	// 명세 문서 자체가 유효한 JSON으로 제공되는지
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	var doc struct {
+		OpenAPI string                                       `json:"openapi"`
+		Paths   map[string]map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: HTTP %d, %v", openAPIPath, rec.Code, err)
+	}
+	if doc.OpenAPI == "" || len(doc.Paths) == 0 {
+		t.Fatalf("empty OpenAPI document")
+	}
+
+	byPath := map[string]map[string]operation{}
+	for _, op := range router.Operations() {
+		if byPath[op.Path] == nil {
+			byPath[op.Path] = map[string]operation{}
+		}
+		byPath[op.Path][op.Method] = op
+		if _, ok := doc.Paths[op.Path][strings.ToLower(op.Method)]; !ok {
+			t.Errorf("%s %s missing from served document", op.Method, op.Path)
+		}
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		ops := byPath[path]
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			op, declared := ops[method]
+			if !declared {
+				for _, other := range ops {
+					op = other
+					break
+				}
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, conformanceRequest(method, path, op))
+
+			if !declared {
+				if rec.Code != http.StatusMethodNotAllowed {
+					t.Errorf("%s %s: undeclared method answered HTTP %d, want 405", method, path, rec.Code)
+				}
+				continue
+			}
+			if rec.Code == http.StatusMethodNotAllowed || rec.Body.String() == "404 page not found\n" {
+				t.Errorf("%s %s: declared operation answered HTTP %d: %s", method, path, rec.Code, rec.Body.String())
+				continue
+			}
+			if rec.Code >= 300 || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+				continue
+			}
+			checkResponseKeys(t, method+" "+path, rec.Body.Bytes(), doc.Paths[path][strings.ToLower(method)])
+		}
+	}
+}
+
+// checkResponseKeys - 응답 객체의 최상위 키가 명세 스키마 properties에 모두 있는지
+func checkResponseKeys(t *testing.T, name string, body []byte, operationDoc map[string]interface{}) {
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return // 배열/원본 문서 응답
+	}
+
+	var properties map[string]interface{}
+	for _, value := range operationDoc["responses"].(map[string]interface{}) {
+		content, _ := value.(map[string]interface{})["content"].(map[string]interface{})
+		media, _ := content["application/json"].(map[string]interface{})
+		schema, _ := media["schema"].(map[string]interface{})
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			properties = props
+		}
+	}
+	if properties == nil {
+		return // 자유 형식 객체
+	}
+	for key := range response {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("%s: response field %q is not in the OpenAPI schema", name, key)
+		}
+	}
+}
+
+// signedRequest - Gateway처럼 서명한 요청
+func signedRequest(t *testing.T, key ed25519.PrivateKey, method, path string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if _, err := signing.SignRequest(key, req, nil); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// 확인되지 않은 Seal 토큰과 위조한 Impersonate-User로 다른 테넌트의 사용량을 읽을 수 없어야 함
+func TestTenantUsageRejectsForgedImpersonation(t *testing.T) {
+	a := conformanceServer(t)
+	gatewayPub, gatewayKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		signing bool
+		request func() *http.Request
+		want    int
+		tenant  string
+	}{
+		{"bogus token, signing off", false, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant/usage", nil)
+			req.Header.Set("X-Seal-Token", "x")
+			req.Header.Set("Impersonate-User", "0xvictim")
+			return req
+		}, http.StatusUnauthorized, ""},
+		{"impersonation only, signing off", false, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant/usage", nil)
+			req.Header.Set("Impersonate-User", "0xvictim")
+			return req
+		}, http.StatusUnauthorized, ""},
+		{"bogus token, signing on", true, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant/usage", nil)
+			req.Header.Set("X-Seal-Token", "x")
+			req.Header.Set("Impersonate-User", "0xvictim")
+			return req
+		}, http.StatusUnauthorized, ""},
+		{"valid token ignores impersonation", true, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant/usage", nil)
+			req.Header.Set("X-Seal-Token", conformanceSeal)
+			req.Header.Set("Impersonate-User", "0xvictim")
+			return req
+		}, http.StatusOK, "0xowner"},
+		{"signed impersonation", true, func() *http.Request {
+			req := signedRequest(t, gatewayKey, http.MethodGet, "/api/v1/tenant/usage")
+			req.Header.Set("Impersonate-User", "0xtenant")
+			return req
+		}, http.StatusOK, "0xtenant"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a.signer.verifier = nil
+			if tc.signing {
+				a.signer.verifier = signing.NewVerifier(gatewayPub)
+			}
+			rec := httptest.NewRecorder()
+			a.routes().ServeHTTP(rec, tc.request())
+			if rec.Code != tc.want {
+				t.Fatalf("HTTP %d, want %d: %s", rec.Code, tc.want, rec.Body.String())
+			}
+			if tc.tenant == "" {
+				return
+			}
+			var response struct {
+				Data TenantQuotaReport `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.Tenant != tc.tenant {
+				t.Fatalf("report for %q, want %q", response.Data.Tenant, tc.tenant)
+			}
+		})
+	}
+}
+
+// 체인 상태와 API quota 사용량도 확인된 Seal 토큰이나 서명된 Impersonate-User만 허용
+func TestTenantEndpointsRequireTenantAuth(t *testing.T) {
+	a := conformanceServer(t)
+	gatewayPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, signed := range []bool{false, true} {
+		a.signer.verifier = nil
+		if signed {
+			a.signer.verifier = signing.NewVerifier(gatewayPub)
+		}
+		for _, path := range []string{"/api/v1/chain/health", "/api/v1/tenants/usage"} {
+			for token, want := range map[string]int{"": http.StatusUnauthorized, "x": http.StatusUnauthorized, conformanceSeal: http.StatusOK} {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set("X-Seal-Token", token)
+				req.Header.Set("Impersonate-User", "0xanyone")
+				rec := httptest.NewRecorder()
+				a.routes().ServeHTTP(rec, req)
+				if rec.Code != want {
+					t.Errorf("%s signed=%v token=%q: HTTP %d, want %d", path, signed, token, rec.Code, want)
+				}
+			}
+		}
+	}
+}
+
+// 읽기 전용 모드 중에는 kubectl 경로 밖에서 클러스터를 바꾸는 관리 API도 거부
+func TestReadOnlyBlocksTrashRestore(t *testing.T) {
+	a := conformanceServer(t)
+	router := a.routes()
+	restore := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/trash/restore?id=missing", nil)
+		req.Header.Set("Authorization", "Bearer "+conformanceAdmin)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := restore(); code != http.StatusNotFound {
+		t.Fatalf("restore before read-only: HTTP %d, want 404", code)
+	}
+	if _, err := a.readOnly.Set(true, "incident"); err != nil {
+		t.Fatal(err)
+	}
+	if code := restore(); code != http.StatusServiceUnavailable {
+		t.Fatalf("restore in read-only mode: HTTP %d, want 503", code)
+	}
+	if rejected := a.readOnly.rejected["admin"]; rejected != 1 {
+		t.Fatalf("admin rejections = %d, want 1", rejected)
+	}
+}
+
+func TestReadOnlyBlocksTenantPurge(t *testing.T) {
+	a := conformanceServer(t)
+	router := a.routes()
+	if _, err := a.readOnly.Set(true, "incident"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/purge",
+		strings.NewReader(`{"tenant": "0xowner", "namespaces": ["team-a"], "confirm": "0xowner"}`))
+	req.Header.Set("Authorization", "Bearer "+conformanceAdmin)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("purge in read-only mode: HTTP %d, want 503: %s", rec.Code, rec.Body.String())
+	}
+	if len(a.retention.attestations) != 0 {
+		t.Fatalf("purge ran in read-only mode: %+v", a.retention.attestations)
+	}
+
+	// 발급한 삭제 증명 조회는 계속 가능
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tenants/purge", nil)
+	req.Header.Set("Authorization", "Bearer "+conformanceAdmin)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("attestation list in read-only mode: HTTP %d, want 200", rec.Code)
+	}
+}
+
+// 카나리 가중치 변경과 워커 설정 게시/롤백도 읽기 전용 모드 중 거부 (조회는 허용)
+func TestReadOnlyBlocksCanaryAndWorkerConfig(t *testing.T) {
+	a := conformanceServer(t)
+	router := a.routes()
+	if _, err := a.readOnly.Set(true, "incident"); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+conformanceAdmin)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	writes := []struct{ path, body string }{
+		{"/api/v1/canaries", `{"namespace": "default", "service": "web", "weight": 50}`},
+		{"/api/v1/worker-config", `{"settings": {}, "comment": "during incident"}`},
+		{"/api/v1/worker-config/rollback?version=1", ""},
+	}
+	for _, write := range writes {
+		if code := send(http.MethodPost, write.path, write.body); code != http.StatusServiceUnavailable {
+			t.Errorf("POST %s in read-only mode: HTTP %d, want 503", write.path, code)
+		}
+	}
+	if rejected := a.readOnly.rejected["admin"]; rejected != uint64(len(writes)) {
+		t.Errorf("admin rejections = %d, want %d", rejected, len(writes))
+	}
+	for _, path := range []string{"/api/v1/canaries", "/api/v1/worker-config"} {
+		if code := send(http.MethodGet, path, ""); code != http.StatusOK {
+			t.Errorf("GET %s in read-only mode: HTTP %d, want 200", path, code)
+		}
+	}
+}
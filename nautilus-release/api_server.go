@@ -4,19 +4,95 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	chain "github.com/k3s-io/daas-chain"
+	httpserver "github.com/k3s-io/daas-httpserver"
 	"github.com/sirupsen/logrus"
 )
 
 // APIServer - HTTP API 서버
 type APIServer struct {
-	logger    *logrus.Logger
-	k3sMgr    *K3sManager
-	server    *http.Server
+	logger          *logrus.Logger
+	k3sMgr          *K3sManager
+	server          *httpserver.Server
+	capacity        *CapacityPublisher
+	topology        *TopologyScheduler
+	simulator       *ScheduleSimulator
+	costs           *CostEstimator
+	canaries        *CanaryController
+	routing         *TopologyRouter
+	health          *NodeHealthScorer
+	metrics         *MetricsRegistry
+	rbac            *RBACAuthorizer
+	debug           *DebugServer
+	clock           *ClockGuard
+	signer          *RequestSigner
+	status          *StatusPage
+	history         *HeartbeatHistory
+	historyArchive  *HeartbeatArchive
+	enrollment      *EnrollmentQueue
+	extender        *SchedulerExtender
+	sponsor         *GasSponsor
+	claims          *ClaimVerifier
+	drain           *Drainer
+	quota           *TenantThrottler
+	registry        *RegistryCache
+	ready           *ReadinessGate
+	bootstrap       *BootstrapManager
+	maintenance     *MaintenanceScheduler
+	appeals         *AppealTracker
+	finality        *FinalityGate
+	deadLetters     *DeadLetterQueue
+	outbox          *ChainOutbox
+	responses       *ResponseStore
+	poolSync        *PoolSync
+	mockChain       *chain.MockServer
+	clientAPI       *ClientAPI
+	stream          *EventStream
+	dashboard       *Dashboard
+	csr             *CSRAPI
+	joinTokens      *JoinTokenIssuer
+	federation      *Federation
+	workerConfig    *WorkerConfigSync
+	network         *NetworkProbe
+	statusAccess    *StatusAccess
+	podLogs         *PodLogArchive
+	logThrottle     *LogThrottleTracker
+	podLimits       *PodLimitTracker
+	netMeter        *NetworkMeter
+	problems        *NodeProblemTainter
+	alerts          *AlertManager
+	trash           *TrashBin
+	wallets         *WalletMonitor
+	serviceAccounts *ServiceAccountIssuer
+	secrets         *SecretBroker
+	reviews         *AccessReviewer
+	retention       *DataRetention
+	slo             *SLOTracker
+	migration       *ContractMigration
+	idempotency     *httpserver.IdempotencyCache
+	versions        *VersionSkew
+	kubectlCompat   *KubectlCompat
+	abandonment     *AbandonmentTracker
+	reservations    *CapacityReservations
+	compliance      *NodeComplianceTracker
+	escrow          *SealingEscrow
+	dryRun          *DryRunAdmission
+	chainHealth     *ChainHealth
+	observability   *ObservabilityBundle
+	challenges      *HeartbeatChallenger
+	tenantUsage     *TenantUsageForecaster
+	readOnly        *ReadOnlyMode
+	scopes          *TokenScopes
 }
 
 // NewAPIServer - 새 API 서버 생성
@@ -31,55 +107,89 @@ func NewAPIServer(logger *logrus.Logger, k3sMgr *K3sManager) *APIServer {
 func (a *APIServer) Start(ctx context.Context) {
 	a.logger.Info("🌐 Starting API Server...")
 
-	mux := http.NewServeMux()
+	// 경로 등록 (관리 API 명세는 /openapi-daas.json)
+	mux := a.routes()
 
-	// 헬스체크 엔드포인트
-	mux.HandleFunc("/healthz", a.handleHealth)
-	mux.HandleFunc("/readyz", a.handleReady)
-
-	// 노드 관리 API
-	mux.HandleFunc("/api/v1/nodes/register", a.handleNodeRegister)
-	mux.HandleFunc("/api/v1/nodes/token", a.handleGetJoinToken)
-	mux.HandleFunc("/api/nodes", a.handleNodes)
-
-	// 상태 확인 API
-	mux.HandleFunc("/api/contract/call", a.handleContractCall)
-	mux.HandleFunc("/api/transactions/history", a.handleTransactionHistory)
-
-	// K8s API 프록시 (포트 6443으로 포워딩)
-	mux.Handle("/api/", a.createK8sProxy())
-	mux.Handle("/apis/", a.createK8sProxy())
+	// 드레인 중 신규 요청 거부 및 진행 중 요청 추적
+	var inner http.Handler = mux
+	if a.drain != nil {
+		inner = a.drain.Middleware(mux)
+	}
+	// 버전 핸드셰이크 (드레인 중 거부 응답에도 마스터 버전 포함)
+	if a.versions != nil {
+		inner = a.versions.Middleware(inner)
+	}
 
-	a.server = &http.Server{
-		Addr:    ":8080",
-		Handler: mux,
+	// 공통 미들웨어: 요청 ID → panic 복구 (Status 500) → gzip 압축 (Accept-Encoding 협상) → 접근 로그 (헬스체크 제외)
+	// 압축은 응답 서명 바깥에서 수행되므로 서명은 항상 비압축 본문 기준
+	handler := httpserver.Chain(inner,
+		httpserver.RequestID,
+		httpserver.Recovery(a.logger.Errorf),
+		httpserver.Compress(httpserver.DefaultCompressMinSize),
+		httpserver.Logging(a.logger.Debugf, "/healthz", "/readyz"),
+	)
+
+	// NAUTILUS_LISTEN_ADDR / NAUTILUS_TLS_* / NAUTILUS_HTTP_REDIRECT_ADDR / NAUTILUS_HSTS_MAX_AGE / NAUTILUS_HTTP2
+	server, err := httpserver.New(httpserver.ConfigFromEnv("NAUTILUS", ":8080"), handler)
+	if err != nil {
+		a.logger.Errorf("❌ Invalid API Server listener config: %v", err)
+		return
 	}
+	a.server = server
 
+	// Context 종료 시 진행 중인 요청을 최대 10초 기다린 뒤 종료
 	go func() {
-		a.logger.Info("🎯 API Server listening on :8080")
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		a.logger.Infof("🎯 API Server listening on %s", a.server.Description())
+		if err := a.server.Run(ctx, 10*time.Second); err != nil {
 			a.logger.Errorf("❌ API Server failed: %v", err)
+			return
 		}
-	}()
-
-	// Context 종료 시 서버 정리
-	go func() {
-		<-ctx.Done()
-		a.logger.Info("🛑 Shutting down API Server...")
-		a.server.Shutdown(context.Background())
+		a.logger.Info("🛑 API Server stopped")
 	}()
 
 	a.logger.Info("✅ API Server started successfully")
 }
 
-// handleHealth - 헬스체크
+// handleHealth - 헬스체크 (?verbose면 빌드 버전, 연결된 구성요소 버전, 기능 게이트 상태, 지갑 잔액도 함께 출력)
 func (a *APIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
+	if _, verbose := r.URL.Query()["verbose"]; verbose {
+		if a.versions != nil {
+			a.versions.writeVerbose(w)
+		}
+		features.WriteVerbose(w)
+		if a.wallets != nil {
+			a.wallets.writeVerbose(w)
+		}
+		fmt.Fprintf(w, "healthz check passed\n")
+		return
+	}
 	fmt.Fprintf(w, "OK")
 }
 
 // handleReady - 준비 상태 확인
 func (a *APIServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if a.drain != nil && !a.drain.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "Draining")
+		return
+	}
+	if a.ready != nil {
+		if ready, pending := a.ready.Ready(); !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "Not Ready: %s", pending)
+			return
+		}
+	}
 	if a.k3sMgr.IsRunning() {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "Ready")
@@ -96,53 +206,330 @@ func (a *APIServer) handleNodeRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Seal 토큰 검증
-	sealToken := r.Header.Get("Authorization")
+	if err := a.clock.Check(); err != nil {
+		a.logger.Errorf("🛑 Refusing worker registration: %v", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	// 등록 본문의 node_id는 선택 (없으면 Seal 토큰에 연결된 노드), 리전/존은 등록 승인 규칙에 사용
+	var registration struct {
+		NodeID string `json:"node_id"`
+		Region string `json:"region"`
+		Zone   string `json:"zone"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&registration); err != nil {
+			http.Error(w, "Invalid registration body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	a.logger.Infof("📝 Worker node registration request from: %s", r.RemoteAddr)
+
+	// 승인 모드에서 심사 중인 워커는 조인 토큰 없이 돌려보냄 (워커는 Retry-After 후 재시도)
+	if enrollment := a.enrollment.ObserveRegistration(requestSealToken(r), registration.NodeID, registration.Region, registration.Zone); enrollment != nil {
+		switch enrollment.Status {
+		case enrollmentRejected:
+			http.Error(w, "Enrollment rejected: "+enrollment.Note, http.StatusForbidden)
+			return
+		case enrollmentPending:
+			w.Header().Set("Retry-After", strconv.Itoa(enrollmentRetryAfter))
+			http.Error(w, "Enrollment pending operator approval", http.StatusAccepted)
+			return
+		}
+	}
+
+	grant, ok := a.mintJoinToken(w, r, registration.NodeID)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "success",
+		"join_token": grant.Token,
+		"token_id":   grant.TokenID,
+		"node_id":    grant.NodeID,
+		"expires_at": grant.ExpiresAt,
+		"server_url": "https://nautilus-control:6443",
+	})
+
+	a.logger.Infof("✅ Worker node %s registration successful", grant.NodeID)
+}
+
+// mintJoinToken - Seal 토큰 재검증 후 노드 전용 조인 토큰 발급 (실패 시 응답까지 작성)
+func (a *APIServer) mintJoinToken(w http.ResponseWriter, r *http.Request, nodeID string) (*JoinTokenGrant, bool) {
+	sealToken := requestSealToken(r)
 	if sealToken == "" {
 		http.Error(w, "Missing authorization", http.StatusUnauthorized)
+		return nil, false
+	}
+	if a.joinTokens == nil {
+		http.Error(w, "Join tokens are not available", http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	grant, err := a.joinTokens.Mint(sealToken, nodeID)
+	switch {
+	case err == nil:
+		return grant, true
+	case errors.Is(err, errJoinTokenUnauthorized):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, errJoinTokenForbidden):
+		a.logger.Warnf("🚫 Join token refused: %v", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		a.logger.Errorf("❌ Failed to mint join token: %v", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	}
+	return nil, false
+}
+
+// nodeHeartbeat - 워커 하트비트 본문
+type nodeHeartbeat struct {
+	NodeID        string       `json:"node_id"`
+	Region        string       `json:"region"`
+	Zone          string       `json:"zone"`
+	OS            string       `json:"os"`
+	Arch          string       `json:"arch"`
+	LatencyMs     int64        `json:"latency_ms"`
+	RunningPods   int          `json:"running_pods"`
+	ProbeResult   *ProbeResult `json:"probe_result,omitempty"`
+	StakeStatus   string       `json:"stake_status"`
+	StakeAmount   uint64       `json:"stake_amount"`
+	ResourceUsage struct {
+		CPUPercent    float64 `json:"cpu_percent"`
+		MemoryPercent float64 `json:"memory_percent"`
+		DiskPercent   float64 `json:"disk_percent"`
+	} `json:"resource_usage"`
+	Conditions      []NodeCondition            `json:"node_conditions,omitempty"`
+	LogThrottling   *[]LogThrottle             `json:"log_throttling,omitempty"` // 없으면 알 수 없음 (빈 목록은 전체 해제)
+	PodNetwork      *[]PodNetworkUsage         `json:"pod_network,omitempty"`    // Pod별 누적 송수신 바이트
+	PodResources    *[]PodResourceUsage        `json:"pod_resources,omitempty"`  // Pod별 제한 강제 여부와 스로틀링/OOM 카운터
+	HostFingerprint *HostFingerprint           `json:"host_fingerprint,omitempty"`
+	Collectors      map[string]json.RawMessage `json:"collectors,omitempty"`
+	CollectorErrors map[string]string          `json:"collector_errors,omitempty"`
+	ConfigVersion   int64                      `json:"config_version"`
+	ConfigError     *WorkerConfigFailure       `json:"config_error,omitempty"`
+	Network         *NetworkReport             `json:"network,omitempty"`
+	ProbeURL        string                     `json:"probe_url,omitempty"`
+	ProbeToken      string                     `json:"probe_token,omitempty"`
+	StatusAccess    string                     `json:"status_access_version"`
+	ChallengeAnswer *ChallengeAnswer           `json:"challenge_answer,omitempty"`
+}
+
+// heartbeatResponse - 하트비트 응답 (워커마다 주기적으로 보내므로 map 대신 구조체, 필요할 때만 감사 프로브/설정 번들/측정 대상/상태 접근 정책 포함)
+type heartbeatResponse struct {
+	Status       string              `json:"status"`
+	Probe        *AuditProbe         `json:"probe,omitempty"`
+	Config       *WorkerConfigUpdate `json:"config,omitempty"`
+	ProbePeers   []ProbePeer         `json:"probe_peers,omitempty"`
+	StatusAccess *StatusAccessPolicy `json:"status_access,omitempty"`
+	Challenge    *HeartbeatChallenge `json:"challenge,omitempty"`
+}
+
+// handleNodeHeartbeat - 워커 하트비트 (위치 정보 갱신 포함)
+func (a *APIServer) handleNodeHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	a.logger.Infof("📝 Worker node registration request from: %s", r.RemoteAddr)
+	var heartbeat nodeHeartbeat
+	if err := json.NewDecoder(r.Body).Decode(&heartbeat); err != nil {
+		http.Error(w, "Invalid heartbeat payload", http.StatusBadRequest)
+		return
+	}
 
-	// Join token 생성
-	token, err := a.k3sMgr.GetJoinToken()
+	workerPool := a.k3sMgr.workerPool
+	worker, exists := workerPool.GetWorker(heartbeat.NodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if worker.SealToken != "" && r.Header.Get("X-Seal-Token") != worker.SealToken {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	changed, err := workerPool.UpdateWorkerTopology(heartbeat.NodeID, heartbeat.Region, heartbeat.Zone, heartbeat.LatencyMs)
 	if err != nil {
-		a.logger.Errorf("❌ Failed to get join token: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	// OS/아키텍처는 멀티 아키텍처 이미지 플랫폼 선택에 사용 (라벨로도 반영)
+	if workerPool.UpdateWorkerPlatform(heartbeat.NodeID, heartbeat.OS, heartbeat.Arch) {
+		changed = true
+	}
 
-	_ = map[string]interface{}{
-		"status": "success",
-		"join_token": token,
-		"server_url": "https://nautilus-control:6443",
-		"message": "Worker node registered successfully",
+	if a.abandonment != nil {
+		a.abandonment.Seen(heartbeat.NodeID)
+	}
+	if a.history != nil {
+		a.history.Record(heartbeat.NodeID, HeartbeatSample{
+			Timestamp:     time.Now(),
+			RunningPods:   heartbeat.RunningPods,
+			CPUPercent:    heartbeat.ResourceUsage.CPUPercent,
+			MemoryPercent: heartbeat.ResourceUsage.MemoryPercent,
+			DiskPercent:   heartbeat.ResourceUsage.DiskPercent,
+			LatencyMs:     heartbeat.LatencyMs,
+			StakeStatus:   heartbeat.StakeStatus,
+			StakeAmount:   heartbeat.StakeAmount,
+		})
+	}
+
+	// 디스크/메모리 압박과 노드 문제 조건은 바뀔 때만 타임라인에 기록 (eviction은 kubelet이 수행)
+	for _, condition := range workerPool.UpdateWorkerConditions(heartbeat.NodeID, heartbeat.Conditions) {
+		kind := "pressure_cleared"
+		if condition.Status {
+			kind = "pressure_detected"
+			a.logger.Warnf("⚠️ Worker %s reports %s: %s", heartbeat.NodeID, condition.Type, condition.Message)
+		}
+		a.history.RecordEvent(heartbeat.NodeID, kind, condition.Type+" "+condition.Message)
+	}
+	// 노드 문제 조건이 켜진 노드는 taint로 새 Pod 배치를 막음 (해소되면 제거)
+	if a.problems != nil {
+		a.problems.Observe(heartbeat.NodeID, heartbeat.Conditions)
+	}
+
+	// 로그 제한 중인 Pod는 Pod 상태 조건으로 표시
+	if heartbeat.LogThrottling != nil && a.logThrottle != nil {
+		a.logThrottle.Observe(heartbeat.NodeID, *heartbeat.LogThrottling)
+	}
+
+	// CPU 스로틀링, OOM kill, 강제되지 않는 제한도 Pod 상태 조건으로 표시
+	if heartbeat.PodResources != nil && a.podLimits != nil {
+		a.podLimits.Observe(heartbeat.NodeID, *heartbeat.PodResources)
 	}
 
+	// 호스트 지문은 컴플라이언스 정책과 비교 (지문이 바뀌면 드리프트로 기록)
+	if a.compliance != nil {
+		a.compliance.Observe(heartbeat.NodeID, heartbeat.HostFingerprint)
+	}
+
+	// Pod 네트워크 카운터 증가분은 테넌트별 송수신 사용량으로 집계
+	if heartbeat.PodNetwork != nil && a.netMeter != nil {
+		a.netMeter.Observe(heartbeat.NodeID, *heartbeat.PodNetwork)
+	}
+
+	// 워커 수집기 섹션은 해석하지 않고 최신 값만 보관 (워커 조회 API로 노출)
+	workerPool.UpdateWorkerTelemetry(heartbeat.NodeID, heartbeat.Collectors, heartbeat.CollectorErrors)
+	// kubelet serving 인증서의 IP SAN은 이 관측 주소에만 자동 승인
+	workerPool.UpdateWorkerAddress(heartbeat.NodeID, r.RemoteAddr)
+
+	// 새 대역폭 측정 결과는 노드 라벨/어노테이션으로 반영
+	var probePeers []ProbePeer
+	if a.network != nil {
+		peerURL := probeURL(heartbeat.ProbeURL, r.RemoteAddr)
+		if workerPool.UpdateWorkerNetwork(heartbeat.NodeID, peerURL, heartbeat.ProbeToken, heartbeat.Network) {
+			changed = true
+		}
+		if peerURL != "" {
+			probePeers = a.network.PeersFor(heartbeat.NodeID)
+		}
+	}
+
+	// 위치 변경 또는 아직 라벨이 없는 노드 (조인 직후) 라벨 동기화
+	if (changed || !worker.LabelsSynced) && a.topology != nil && a.k3sMgr.IsRunning() {
+		if err := a.topology.SyncNodeLabels(worker); err != nil {
+			a.logger.Warnf("⚠️ Failed to sync topology labels: %v", err)
+		}
+	}
+
+	// 보고된 Pod 수/프로브 응답 교차 검증, 필요 시 다음 감사 프로브를 응답에 포함
+	var probe *AuditProbe
+	if a.claims != nil {
+		probe = a.claims.VerifyHeartbeat(heartbeat.NodeID, heartbeat.RunningPods, heartbeat.ProbeResult)
+	}
+
+	// 워커가 적용한 설정 버전이 최신이 아니면 설정 번들을 함께 전달 (차등 동기화)
+	config := a.workerConfig.Observe(heartbeat.NodeID, heartbeat.ConfigVersion, heartbeat.ConfigError)
+
+	response := heartbeatResponse{Status: "success", Probe: probe, Config: config, ProbePeers: probePeers}
+	if a.statusAccess != nil {
+		response.StatusAccess = a.statusAccess.Policy(heartbeat.NodeID, heartbeat.StatusAccess)
+	}
+	// 지난 챌린지 응답 확인, 새 라운드면 다음 챌린지 전달
+	if a.challenges != nil {
+		response.Challenge = a.challenges.Observe(heartbeat.NodeID, worker.SealToken, heartbeat.ChallengeAnswer)
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, `{"status":"success","join_token":"%s","server_url":"https://nautilus-control:6443"}`, token)
+	json.NewEncoder(w).Encode(response)
+}
 
-	a.logger.Info("✅ Worker node registration successful")
+// reconciledPod - 워커가 재시작 후 정리한 Pod
+type reconciledPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
 }
 
-// handleGetJoinToken - Join token 조회
+// handleNodeReconcile - 워커 재시작 시 이전 실행 Pod 채택/중단 결과 수신 (노드 타임라인에 기록)
+func (a *APIServer) handleNodeReconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var report struct {
+		NodeID  string          `json:"node_id"`
+		Adopted []reconciledPod `json:"adopted"`
+		Stopped []reconciledPod `json:"stopped"`
+		Kept    []reconciledPod `json:"kept"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		http.Error(w, "Invalid reconcile report", http.StatusBadRequest)
+		return
+	}
+
+	worker, exists := a.k3sMgr.workerPool.GetWorker(report.NodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if worker.SealToken != "" && r.Header.Get("X-Seal-Token") != worker.SealToken {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	a.logger.Infof("♻️ Worker %s reconciled previous run: %d adopted, %d stopped, %d kept",
+		report.NodeID, len(report.Adopted), len(report.Stopped), len(report.Kept))
+	for kind, pods := range map[string][]reconciledPod{
+		"pods_adopted":    report.Adopted,
+		"orphans_stopped": report.Stopped,
+		"orphans_kept":    report.Kept,
+	} {
+		if len(pods) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, pod.Namespace+"/"+pod.Name)
+		}
+		a.history.RecordEvent(report.NodeID, kind, strings.Join(names, ", "))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"status":"success"}`)
+}
+
+// handleGetJoinToken - 조인 토큰 재발급 (이전 토큰은 회수, ?node_id= 선택)
 func (a *APIServer) handleGetJoinToken(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	token, err := a.k3sMgr.GetJoinToken()
-	if err != nil {
-		a.logger.Errorf("❌ Failed to get join token: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	grant, ok := a.mintJoinToken(w, r, r.URL.Query().Get("node_id"))
+	if !ok {
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, `{"join_token":"%s"}`, token)
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(grant)
 }
 
 // createK8sProxy - K8s API 프록시 생성
@@ -153,13 +540,45 @@ func (a *APIServer) createK8sProxy() http.Handler {
 	proxy := httputil.NewSingleHostReverseProxy(target)
 
 	// TLS 검증 비활성화 (개발용)
+	// 사용자 TLS 설정이 있으면 HTTP/2가 자동으로 켜지지 않으므로 명시적으로 활성화
 	proxy.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}
+
+	// JSON watch는 K3s에 북마크를 요청하여 재개 지점을 최신으로 유지 (WatchBookmarks 게이트)
+	// LIST는 페이지 크기를 제한하고 continue 토큰으로 나눠 받도록 함 (ListPaging 게이트)
+	maxPage := maxListPage()
+	watchBookmarks := features.Enabled(featureWatchBookmarks)
+	listPaging := features.Enabled(featureListPaging)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		if watchBookmarks {
+			requestWatchBookmarks(req)
+		}
+		if listPaging && enforceListLimit(req, maxPage) {
+			a.logger.Debugf("📄 LIST %s limited to %d items per page", req.URL.Path, maxPage)
+		}
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		a.logger.Debugf("🔄 Proxying K8s API request: %s %s", r.Method, r.URL.Path)
-		proxy.ServeHTTP(w, r)
+		if !watchBookmarks || !isJSONWatch(r) {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+
+		stream := newWatchStream(w, r, a.drain)
+		defer stream.finish()
+		// 드레인으로 스트림이 취소되면 ReverseProxy가 ErrAbortHandler로 중단하므로
+		// 연결을 끊지 않고 마지막 북마크를 보낸 뒤 정상 종료
+		defer func() {
+			if rec := recover(); rec != nil && rec != http.ErrAbortHandler {
+				panic(rec)
+			}
+		}()
+		proxy.ServeHTTP(stream, r)
 	})
 }
 
@@ -239,19 +658,19 @@ func (a *APIServer) handleTransactionHistory(w http.ResponseWriter, r *http.Requ
 		"status": "success",
 		"data": []map[string]interface{}{
 			{
-				"tx_hash":    "8Gk3vLEuhp8SU1nENpVFkhpW9M6VAGZjgXoLJVvgLH1M",
-				"type":       "pod_deployment",
-				"status":     "completed",
-				"timestamp":  "2025-09-20T20:17:00Z",
-				"pod_name":   "demo-nginx-pod",
-				"worker":     "hackathon-worker-001",
+				"tx_hash":   "8Gk3vLEuhp8SU1nENpVFkhpW9M6VAGZjgXoLJVvgLH1M",
+				"type":      "pod_deployment",
+				"status":    "completed",
+				"timestamp": "2025-09-20T20:17:00Z",
+				"pod_name":  "demo-nginx-pod",
+				"worker":    "hackathon-worker-001",
 			},
 			{
-				"tx_hash":    "Dr7ZPeNxqJb6Rt1A7yB6c2JTxGD15RqGqjPsjRbLR9sv",
-				"type":       "worker_activation",
-				"status":     "completed",
-				"timestamp":  "2025-09-20T20:16:00Z",
-				"worker":     "hackathon-worker-001",
+				"tx_hash":   "Dr7ZPeNxqJb6Rt1A7yB6c2JTxGD15RqGqjPsjRbLR9sv",
+				"type":      "worker_activation",
+				"status":    "completed",
+				"timestamp": "2025-09-20T20:16:00Z",
+				"worker":    "hackathon-worker-001",
 			},
 		},
 	}
@@ -261,4 +680,4 @@ func (a *APIServer) handleTransactionHistory(w http.ResponseWriter, r *http.Requ
 	fmt.Fprintf(w, `{"status":"success","data":[{"tx_hash":"8Gk3vLEuhp8SU1nENpVFkhpW9M6VAGZjgXoLJVvgLH1M","type":"pod_deployment","status":"completed","timestamp":"2025-09-20T20:17:00Z","pod_name":"demo-nginx-pod","worker":"hackathon-worker-001"},{"tx_hash":"Dr7ZPeNxqJb6Rt1A7yB6c2JTxGD15RqGqjPsjRbLR9sv","type":"worker_activation","status":"completed","timestamp":"2025-09-20T20:16:00Z","worker":"hackathon-worker-001"}]}`)
 
 	a.logger.Info("✅ Transaction history returned successfully")
-}
\ No newline at end of file
+}
@@ -0,0 +1,294 @@
+// Slash Appeals - 슬래싱 이의 신청 상태 추적 및 거버넌스 심사용 증거 보관
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 이의 신청 심사 상태 (slash_appeals 모듈의 status와 대응)
+const (
+	appealPending  = "pending"
+	appealUpheld   = "upheld"
+	appealRejected = "rejected"
+)
+
+// maxAppealEvidenceSize - 증거 번들 최대 크기
+const maxAppealEvidenceSize = 8 << 20
+
+// SlashAppeal - 온체인 이의 신청 (AppealSubmittedEvent/AppealDecidedEvent로 갱신)
+type SlashAppeal struct {
+	AppealID          string    `json:"appeal_id"`
+	NodeID            string    `json:"node_id"`
+	Appellant         string    `json:"appellant"`
+	StakeObjectID     string    `json:"stake_object_id"`
+	EvidenceDigest    string    `json:"evidence_digest"`
+	Summary           string    `json:"summary"`
+	Status            string    `json:"status"`
+	DecisionNote      string    `json:"decision_note,omitempty"`
+	Reviewer          string    `json:"reviewer,omitempty"`
+	SubmittedAt       time.Time `json:"submitted_at"`
+	DecidedAt         time.Time `json:"decided_at,omitempty"`
+	EvidenceAvailable bool      `json:"evidence_available"`
+}
+
+/*
+AppealTracker - 슬래싱된 스테이커의 이의 신청을 체인 이벤트로 추적
+
+  - 워커는 슬래싱 확정 시 증거 번들을 POST /api/v1/appeals/evidence로 업로드 (Seal 토큰 인증)
+  - 마스터는 본문의 sha256으로 저장하므로 온체인 evidence_digest와 바로 대조 가능
+  - 거버넌스는 GET /api/v1/appeals로 심사 대기 목록과 증거 보유 여부를 확인하고
+    GET /api/v1/appeals/evidence?digest=...로 원본을 내려받아 decide_appeal을 호출
+*/
+type AppealTracker struct {
+	logger      *logrus.Logger
+	workerPool  *WorkerPool
+	history     *HeartbeatHistory
+	stateFile   string
+	evidenceDir string
+	appeals     map[string]*SlashAppeal // appeal_id 기준
+	mutex       sync.RWMutex
+}
+
+// NewAppealTracker - 새 Appeal Tracker 생성 (저장된 이의 신청 복원)
+func NewAppealTracker(logger *logrus.Logger, workerPool *WorkerPool) *AppealTracker {
+	t := &AppealTracker{
+		logger:      logger,
+		workerPool:  workerPool,
+		stateFile:   statePath("slash-appeals.json"),
+		evidenceDir: statePath("appeal-evidence"),
+		appeals:     make(map[string]*SlashAppeal),
+	}
+	if _, err := loadJSONState(t.stateFile, &t.appeals); err != nil {
+		logger.Warnf("⚠️ Failed to load slash appeals: %v", err)
+	}
+	return t
+}
+
+// handleChainEvent - slash_appeals 모듈 이벤트 반영
+func (t *AppealTracker) handleChainEvent(event *SuiContractEvent) {
+	if t == nil {
+		return
+	}
+	appealID, _ := event.EventData["appeal_id"].(string)
+	nodeID, _ := event.EventData["node_id"].(string)
+	if appealID == "" {
+		t.logger.Warnf("⚠️ Appeal event without appeal_id: %s", event.Type)
+		return
+	}
+	timestamp := time.UnixMilli(event.Timestamp)
+	if ms, err := eventUint(event.EventData["timestamp"]); err == nil {
+		timestamp = time.UnixMilli(int64(ms))
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if strings.Contains(event.Type, "AppealSubmittedEvent") {
+		appeal := &SlashAppeal{
+			AppealID:    appealID,
+			NodeID:      nodeID,
+			Status:      appealPending,
+			SubmittedAt: timestamp,
+		}
+		appeal.Appellant, _ = event.EventData["appellant"].(string)
+		appeal.StakeObjectID, _ = event.EventData["stake_object_id"].(string)
+		appeal.EvidenceDigest, _ = event.EventData["evidence_digest"].(string)
+		appeal.Summary, _ = event.EventData["summary"].(string)
+		t.appeals[appealID] = appeal
+		t.saveLocked()
+
+		t.logger.Warnf("⚖️ Slash appeal %s submitted for %s by %s (evidence %s)", appealID, nodeID, appeal.Appellant, appeal.EvidenceDigest)
+		t.history.RecordEvent(nodeID, "appeal_submitted", fmt.Sprintf("appeal %s, evidence %s", appealID, appeal.EvidenceDigest))
+		return
+	}
+
+	appeal := t.appeals[appealID]
+	if appeal == nil {
+		// 마스터가 제출 이벤트를 놓친 경우에도 심사 결과는 남김
+		appeal = &SlashAppeal{AppealID: appealID, NodeID: nodeID}
+		t.appeals[appealID] = appeal
+	}
+	appeal.Status = appealRejected
+	if upheld, _ := event.EventData["upheld"].(bool); upheld {
+		appeal.Status = appealUpheld
+	}
+	appeal.DecisionNote, _ = event.EventData["note"].(string)
+	appeal.Reviewer, _ = event.EventData["reviewer"].(string)
+	appeal.DecidedAt = timestamp
+	t.saveLocked()
+
+	t.logger.Infof("⚖️ Slash appeal %s for %s %s by %s", appealID, nodeID, appeal.Status, appeal.Reviewer)
+	t.history.RecordEvent(nodeID, "appeal_decided", fmt.Sprintf("appeal %s %s: %s", appealID, appeal.Status, appeal.DecisionNote))
+}
+
+func (t *AppealTracker) saveLocked() {
+	if err := saveJSONState(t.stateFile, t.appeals); err != nil {
+		t.logger.Warnf("⚠️ Failed to persist slash appeals: %v", err)
+	}
+}
+
+// evidencePath - 다이제스트("sha256:<hex>")에 해당하는 증거 파일 경로
+func (t *AppealTracker) evidencePath(digest string) (string, bool) {
+	sum := strings.TrimPrefix(digest, "sha256:")
+	if len(sum) != sha256.Size*2 || sum == digest {
+		return "", false
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", false
+	}
+	return filepath.Join(t.evidenceDir, sum+".json"), true
+}
+
+// StoreEvidence - 증거 번들 저장 후 다이제스트 반환 (번들의 node_id는 업로드한 워커와 같아야 함)
+func (t *AppealTracker) StoreEvidence(nodeID string, data []byte) (string, error) {
+	var bundle struct {
+		NodeID string `json:"node_id"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return "", fmt.Errorf("evidence must be a JSON object: %v", err)
+	}
+	if bundle.NodeID != nodeID {
+		return "", fmt.Errorf("evidence node_id %q does not match %q", bundle.NodeID, nodeID)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	path, _ := t.evidencePath(digest)
+	if err := os.MkdirAll(t.evidenceDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create evidence directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to store evidence: %v", err)
+	}
+
+	t.logger.Infof("🗂️ Stored slash evidence from %s (%s, %d bytes)", nodeID, digest, len(data))
+	t.history.RecordEvent(nodeID, "appeal_evidence", digest)
+	return digest, nil
+}
+
+// Appeals - 제출 시각 순 이의 신청 목록 (nodeID/status가 비어 있으면 전체)
+func (t *AppealTracker) Appeals(nodeID, status string) []SlashAppeal {
+	t.mutex.RLock()
+	appeals := make([]SlashAppeal, 0, len(t.appeals))
+	for _, appeal := range t.appeals {
+		if (nodeID == "" || appeal.NodeID == nodeID) && (status == "" || appeal.Status == status) {
+			appeals = append(appeals, *appeal)
+		}
+	}
+	t.mutex.RUnlock()
+
+	for i := range appeals {
+		if path, ok := t.evidencePath(appeals[i].EvidenceDigest); ok {
+			_, err := os.Stat(path)
+			appeals[i].EvidenceAvailable = err == nil
+		}
+	}
+	sort.Slice(appeals, func(i, j int) bool { return appeals[i].SubmittedAt.Before(appeals[j].SubmittedAt) })
+	return appeals
+}
+
+// handleAppeals - 이의 신청 상태 조회 (?node_id=, ?status=pending|upheld|rejected)
+func (t *AppealTracker) handleAppeals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != appealPending && status != appealUpheld && status != appealRejected {
+		http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"appeals": t.Appeals(r.URL.Query().Get("node_id"), status),
+	})
+}
+
+/*
+handleEvidence - 증거 번들 업로드/조회 (/api/v1/appeals/evidence)
+
+	POST ?node_id=...   워커 업로드 (X-Seal-Token, 슬래싱된 워커도 허용)
+	GET  ?digest=sha256:...   거버넌스 심사용 원본
+*/
+func (t *AppealTracker) handleEvidence(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		nodeID := r.URL.Query().Get("node_id")
+		worker, exists := t.workerPool.GetWorker(nodeID)
+		if !exists {
+			http.Error(w, "Unknown worker", http.StatusNotFound)
+			return
+		}
+		if worker.SealToken == "" || r.Header.Get("X-Seal-Token") != worker.SealToken {
+			http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxAppealEvidenceSize+1))
+		if err != nil {
+			http.Error(w, "Failed to read evidence", http.StatusBadRequest)
+			return
+		}
+		if len(data) > maxAppealEvidenceSize {
+			http.Error(w, "Evidence bundle too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		digest, err := t.StoreEvidence(nodeID, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "evidence_digest": digest})
+
+	case http.MethodGet:
+		path, ok := t.evidencePath(r.URL.Query().Get("digest"))
+		if !ok {
+			http.Error(w, "Invalid digest parameter", http.StatusBadRequest)
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			http.Error(w, "Evidence not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 심사 상태별 이의 신청 수 메트릭 출력
+func (t *AppealTracker) writeMetrics(w io.Writer) {
+	counts := map[string]int{appealPending: 0, appealUpheld: 0, appealRejected: 0}
+	t.mutex.RLock()
+	for _, appeal := range t.appeals {
+		counts[appeal.Status]++
+	}
+	t.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_slash_appeals", "gauge", "Slash appeals by review status")
+	for _, status := range []string{appealPending, appealUpheld, appealRejected} {
+		writeMetric(w, "nautilus_slash_appeals", map[string]string{"status": status}, float64(counts[status]))
+	}
+}
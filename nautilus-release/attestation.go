@@ -0,0 +1,97 @@
+// Attestation - 워커의 교차 검증(peer review)용 마스터 TEE 증명 문서 발급
+//
+// 워커가 보낸 nonce에 측정값, TEE 디바이스, 발급 시각을 묶어 응답 서명 키(ed25519)로 서명합니다.
+// 워커는 공개키와 허용 측정값 목록으로 검증한 결과를 worker_registry::report_attestation으로
+// 온체인에 기록하며, 마스터는 그 이벤트를 구독해 실패 보고를 타임라인에 남깁니다.
+// 문서 형식은 worker-release/peer_attestation.go와 동일합니다.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// attestationDomain - 서명 도메인 구분자 (요청/응답 서명과 혼용 방지)
+const attestationDomain = "daas-attestation-v1"
+
+// AttestationDocument - 서명된 TEE 증명 문서
+type AttestationDocument struct {
+	Subject     string `json:"subject"`
+	Measurement string `json:"measurement"`
+	TEEDevice   string `json:"tee_device"`
+	Nonce       string `json:"nonce"`
+	IssuedAt    int64  `json:"issued_at"`
+	PublicKey   string `json:"public_key"`
+	// EncryptionKey - 요청 본문 암호화 수신자 키 (base64 X25519, EncryptedPayloads 게이트가 켜진 경우만)
+	EncryptionKey string `json:"encryption_key,omitempty"`
+	Signature     string `json:"signature"`
+}
+
+// attestationDigest - 서명 대상 다이제스트 (암호화 키는 있을 때만 덧붙여 이전 문서와 호환)
+func attestationDigest(doc *AttestationDocument) []byte {
+	payload := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%d",
+		attestationDomain, doc.Subject, doc.Measurement, doc.TEEDevice, doc.Nonce, doc.IssuedAt)
+	if doc.EncryptionKey != "" {
+		payload += "\n" + doc.EncryptionKey
+	}
+	digest := sha256.Sum256([]byte(payload))
+	return digest[:]
+}
+
+// Attest - nonce에 대한 마스터 증명 문서 생성
+func (s *RequestSigner) Attest(nonce string) *AttestationDocument {
+	doc := &AttestationDocument{
+		Subject:     "master",
+		Measurement: attestationMeasurement(),
+		TEEDevice:   teeDevice(),
+		Nonce:       nonce,
+		IssuedAt:    time.Now().Unix(),
+		PublicKey:   hex.EncodeToString(s.publicKey()),
+	}
+	if s.payloads != nil {
+		doc.EncryptionKey = s.payloads.PublicKey()
+	}
+	doc.Signature = hex.EncodeToString(s.signMessage(attestationDigest(doc)))
+	return doc
+}
+
+// handleAttestation - GET /api/v1/attestation?nonce= (인증 없음, 워커/누구나 검증 가능)
+func (s *RequestSigner) handleAttestation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nonce := r.URL.Query().Get("nonce")
+	if len(nonce) < 16 || len(nonce) > 128 {
+		http.Error(w, "nonce must be 16-128 characters", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   s.Attest(nonce),
+	})
+}
+
+// handleAttestationEvent - 워커의 교차 검증 결과 이벤트 (실패 보고는 타임라인에 기록)
+func (s *SuiIntegration) handleAttestationEvent(event *SuiContractEvent) {
+	verifier, _ := event.EventData["verifier_node_id"].(string)
+	target, _ := event.EventData["target"].(string)
+	verdict, _ := event.EventData["verdict"].(string)
+	measurement, _ := event.EventData["measurement"].(string)
+
+	if verdict == "valid" {
+		s.logger.Infof("🛡️ Worker %s verified attestation of %s (%s)", verifier, target, measurement)
+		return
+	}
+
+	s.logger.Warnf("🚨 Worker %s reported failed attestation of %s (measurement %s)", verifier, target, measurement)
+	s.history.RecordEvent(target, "attestation_failed",
+		fmt.Sprintf("reported by %s, measurement %s", verifier, measurement))
+}
@@ -0,0 +1,501 @@
+// Audit - K8s 감사 로그 포맷 이벤트 기록 및 외부 싱크(webhook/file/Kafka) 전송
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 감사 레벨 (K8s audit policy와 동일)
+const (
+	AuditLevelNone            = "None"
+	AuditLevelMetadata        = "Metadata"
+	AuditLevelRequest         = "Request"
+	AuditLevelRequestResponse = "RequestResponse"
+)
+
+const (
+	auditBatchSize     = 100
+	auditFlushInterval = 5 * time.Second
+	auditQueueSize     = 1000
+)
+
+// AuditEvent - audit.k8s.io/v1 Event
+type AuditEvent struct {
+	Kind                     string            `json:"kind"`
+	APIVersion               string            `json:"apiVersion"`
+	Level                    string            `json:"level"`
+	AuditID                  string            `json:"auditID"`
+	Stage                    string            `json:"stage"`
+	RequestURI               string            `json:"requestURI"`
+	Verb                     string            `json:"verb"`
+	User                     AuditUser         `json:"user"`
+	ObjectRef                *AuditObjectRef   `json:"objectRef,omitempty"`
+	ResponseStatus           *AuditStatus      `json:"responseStatus,omitempty"`
+	RequestObject            json.RawMessage   `json:"requestObject,omitempty"`
+	ResponseObject           json.RawMessage   `json:"responseObject,omitempty"`
+	Annotations              map[string]string `json:"annotations,omitempty"`
+	RequestReceivedTimestamp time.Time         `json:"requestReceivedTimestamp"`
+	StageTimestamp           time.Time         `json:"stageTimestamp"`
+}
+
+// AuditUser - 요청자 정보
+type AuditUser struct {
+	Username string `json:"username"`
+}
+
+// AuditObjectRef - 대상 리소스
+type AuditObjectRef struct {
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// AuditStatus - 응답 상태
+type AuditStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// AuditPolicyRule - 첫 번째로 일치하는 규칙의 레벨 적용 (빈 목록은 전체 일치)
+type AuditPolicyRule struct {
+	Level      string   `json:"level"`
+	Verbs      []string `json:"verbs,omitempty"`
+	Resources  []string `json:"resources,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// defaultAuditPolicy - secrets는 메타데이터만, 조회는 메타데이터, 변경은 요청 본문까지 기록
+var defaultAuditPolicy = []AuditPolicyRule{
+	{Level: AuditLevelMetadata, Resources: []string{"secrets"}},
+	{Level: AuditLevelMetadata, Verbs: []string{"get", "list", "watch"}},
+	{Level: AuditLevelRequest},
+}
+
+// AuditSink - 감사 이벤트 전송 대상
+type AuditSink interface {
+	Name() string
+	Write(events []*AuditEvent) error
+}
+
+// AuditLogger - 정책 기반 감사 이벤트 수집 및 배치 전송
+type AuditLogger struct {
+	logger *logrus.Logger
+	policy []AuditPolicyRule
+	sinks  []AuditSink
+	queue  chan *AuditEvent
+	mutex  sync.Mutex
+	outbox *ChainOutbox // 온체인 해시 체인 앵커 (선택)
+}
+
+// NewAuditLogger - 환경변수 설정으로 Audit Logger 생성
+func NewAuditLogger(logger *logrus.Logger) *AuditLogger {
+	a := &AuditLogger{
+		logger: logger,
+		policy: defaultAuditPolicy,
+		queue:  make(chan *AuditEvent, auditQueueSize),
+	}
+
+	if path := os.Getenv("AUDIT_POLICY_FILE"); path != "" {
+		var rules []AuditPolicyRule
+		if found, err := loadJSONState(path, &rules); err != nil || !found {
+			logger.Warnf("⚠️ Failed to load audit policy %s, using default: %v", path, err)
+		} else {
+			a.policy = rules
+		}
+	}
+
+	for _, name := range splitList(getEnvOrDefault("AUDIT_SINKS", "file")) {
+		switch name {
+		case "file":
+			a.sinks = append(a.sinks, &FileAuditSink{path: getEnvOrDefault("AUDIT_LOG_PATH", statePath("audit.log"))})
+		case "webhook":
+			if url := os.Getenv("AUDIT_WEBHOOK_URL"); url != "" {
+				a.sinks = append(a.sinks, newWebhookAuditSink(url))
+			} else {
+				logger.Warn("⚠️ AUDIT_WEBHOOK_URL not set, webhook audit sink disabled")
+			}
+		case "kafka":
+			if url := os.Getenv("AUDIT_KAFKA_REST_URL"); url != "" {
+				a.sinks = append(a.sinks, newKafkaAuditSink(url, getEnvOrDefault("AUDIT_KAFKA_TOPIC", "k3s-daas-audit")))
+			} else {
+				logger.Warn("⚠️ AUDIT_KAFKA_REST_URL not set, kafka audit sink disabled")
+			}
+		default:
+			logger.Warnf("⚠️ Unknown audit sink: %s", name)
+		}
+	}
+
+	return a
+}
+
+// levelFor - 정책에서 요청에 해당하는 감사 레벨 결정
+func (a *AuditLogger) levelFor(verb, resource, namespace string) string {
+	for _, rule := range a.policy {
+		if matchesRule(rule.Verbs, verb) && matchesRule(rule.Resources, resource) && matchesRule(rule.Namespaces, namespace) {
+			return rule.Level
+		}
+	}
+	return AuditLevelNone
+}
+
+// matchesRule - 빈 목록 또는 "*"는 전체 일치
+func matchesRule(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// auditVerb - HTTP 메서드를 K8s verb로 변환
+func auditVerb(method, name string) string {
+	switch strings.ToUpper(method) {
+	case "GET":
+		if name == "" {
+			return "list"
+		}
+		return "get"
+	case "POST":
+		return "create"
+	case "PUT":
+		return "update"
+	case "PATCH":
+		return "patch"
+	case "DELETE":
+		return "delete"
+	default:
+		return strings.ToLower(method)
+	}
+}
+
+// RecordK8sRequest - Contract 경로 K8s 요청 결과 기록
+func (a *AuditLogger) RecordK8sRequest(request *K8sAPIRequest, result *K8sAPIResult, receivedAt time.Time) {
+	verb := auditVerb(request.Method, request.Name)
+	level := a.levelFor(verb, request.Resource, request.Namespace)
+	if level == AuditLevelNone {
+		return
+	}
+
+	event := &AuditEvent{
+		Kind:       "Event",
+		APIVersion: "audit.k8s.io/v1",
+		Level:      level,
+		AuditID:    request.RequestID,
+		Stage:      "ResponseComplete",
+		RequestURI: k8sRequestURI(request),
+		Verb:       verb,
+		User:       AuditUser{Username: request.Requester},
+		ObjectRef: &AuditObjectRef{
+			Resource:  request.Resource,
+			Namespace: request.Namespace,
+			Name:      request.Name,
+		},
+		ResponseStatus:           &AuditStatus{Code: http.StatusOK},
+		RequestReceivedTimestamp: receivedAt,
+		StageTimestamp:           time.Now(),
+	}
+	if !result.Success {
+		event.ResponseStatus = &AuditStatus{Code: http.StatusInternalServerError, Message: result.Error}
+	}
+	if (level == AuditLevelRequest || level == AuditLevelRequestResponse) && json.Valid([]byte(request.Payload)) {
+		event.RequestObject = json.RawMessage(request.Payload)
+	}
+	if level == AuditLevelRequestResponse && json.Valid([]byte(result.Output)) {
+		event.ResponseObject = json.RawMessage(result.Output)
+	}
+
+	a.enqueue(event)
+}
+
+/*
+RecordNamespaceProvisioning - 요청을 계기로 마스터가 네임스페이스를 자동 생성한 기록
+테넌트 요청이 아닌 시스템 동작이라 정책과 관계없이 Metadata 레벨로 남기고,
+어떤 요청이 계기였는지와 템플릿으로 만든 오브젝트를 annotation에 적습니다.
+*/
+func (a *AuditLogger) RecordNamespaceProvisioning(request *K8sAPIRequest, tenant string, objects []string, provisionErr error, startedAt time.Time) {
+	if a == nil {
+		return
+	}
+	event := &AuditEvent{
+		Kind:       "Event",
+		APIVersion: "audit.k8s.io/v1",
+		Level:      AuditLevelMetadata,
+		AuditID:    request.RequestID + "-provision",
+		Stage:      "ResponseComplete",
+		RequestURI: "/api/v1/namespaces/" + request.Namespace,
+		Verb:       "create",
+		User:       AuditUser{Username: tenant},
+		ObjectRef: &AuditObjectRef{
+			Resource: "namespaces",
+			Name:     request.Namespace,
+		},
+		ResponseStatus: &AuditStatus{Code: http.StatusCreated},
+		Annotations: map[string]string{
+			"k3s-daas.io/provisioning":        "auto",
+			"k3s-daas.io/triggered-by":        request.RequestID,
+			"k3s-daas.io/trigger-verb":        auditVerb(request.Method, request.Name) + " " + request.Resource,
+			"k3s-daas.io/provisioned-objects": strings.Join(objects, ","),
+		},
+		RequestReceivedTimestamp: startedAt,
+		StageTimestamp:           time.Now(),
+	}
+	if provisionErr != nil {
+		event.ResponseStatus = &AuditStatus{Code: http.StatusInternalServerError, Message: provisionErr.Error()}
+	}
+	a.enqueue(event)
+}
+
+// enqueue - 배치 전송 대기열에 추가 (가득 차면 버림)
+func (a *AuditLogger) enqueue(event *AuditEvent) {
+	select {
+	case a.queue <- event:
+	default:
+		a.logger.Warnf("⚠️ Audit queue full, dropping event %s", event.AuditID)
+	}
+}
+
+// k8sRequestURI - 요청을 K8s REST 경로로 표현
+func k8sRequestURI(request *K8sAPIRequest) string {
+	prefix := "/api/v1"
+	switch request.Resource {
+	case "deployments", "statefulsets", "replicasets", "daemonsets":
+		prefix = "/apis/apps/v1"
+	}
+
+	uri := prefix
+	if request.Namespace != "" && !clusterScopedResources[request.Resource] {
+		uri += "/namespaces/" + request.Namespace
+	}
+	uri += "/" + request.Resource
+	if request.Name != "" {
+		uri += "/" + request.Name
+	}
+	return uri
+}
+
+// Start - 배치 전송 루프 (종료 시 남은 이벤트 flush)
+func (a *AuditLogger) Start(ctx context.Context) {
+	a.logger.Infof("📜 Starting Audit Logger with %d sinks...", len(a.sinks))
+
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	var batch []*AuditEvent
+	for {
+		select {
+		case <-ctx.Done():
+			a.flush(batch)
+			return
+		case event := <-a.queue:
+			batch = append(batch, event)
+			if len(batch) >= auditBatchSize {
+				a.flush(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				a.flush(batch)
+				batch = nil
+			}
+		}
+	}
+}
+
+// flush - 모든 싱크에 배치 전송
+func (a *AuditLogger) flush(batch []*AuditEvent) {
+	if len(batch) == 0 {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, sink := range a.sinks {
+		if err := sink.Write(batch); err != nil {
+			a.logger.Errorf("❌ Audit sink %s failed (%d events lost): %v", sink.Name(), len(batch), err)
+		}
+	}
+	a.outbox.AddAuditBatch(batch)
+}
+
+// FileAuditSink - K8s 감사 로그 포맷(JSON lines) 파일 싱크
+type FileAuditSink struct {
+	path string
+}
+
+// Name - 싱크 이름
+func (f *FileAuditSink) Name() string { return "file" }
+
+// Write - 이벤트를 한 줄씩 추가
+func (f *FileAuditSink) Write(events []*AuditEvent) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %v", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to write audit event: %v", err)
+		}
+	}
+	return nil
+}
+
+// Rewrite - keep이 false인 이벤트를 지우고 파일을 교체 (보관 정책/테넌트 삭제), 삭제한 이벤트 수 반환
+func (f *FileAuditSink) Rewrite(keep func(event *AuditEvent) bool) (int, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to read audit log: %v", err)
+	}
+
+	var kept bytes.Buffer
+	removed := 0
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event AuditEvent
+		if err := json.Unmarshal(line, &event); err == nil && !keep(&event) {
+			removed++
+			continue
+		}
+		kept.Write(line)
+		kept.WriteByte('\n')
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0600); err != nil {
+		return 0, fmt.Errorf("failed to write audit log: %v", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return 0, fmt.Errorf("failed to replace audit log: %v", err)
+	}
+	return removed, nil
+}
+
+// Prune - 파일 싱크의 이벤트 중 keep이 false인 것 삭제 (외부 싱크로 이미 보낸 이벤트는 지울 수 없으므로 external 반환)
+func (a *AuditLogger) Prune(keep func(event *AuditEvent) bool) (removed int, external []string, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, sink := range a.sinks {
+		file, ok := sink.(*FileAuditSink)
+		if !ok {
+			external = append(external, sink.Name())
+			continue
+		}
+		count, err := file.Rewrite(keep)
+		removed += count
+		if err != nil {
+			return removed, external, err
+		}
+	}
+	return removed, external, nil
+}
+
+// httpAuditSink - 재시도하는 HTTP POST 공통 구현
+type httpAuditSink struct {
+	name        string
+	url         string
+	contentType string
+	encode      func(events []*AuditEvent) ([]byte, error)
+	client      *http.Client
+	maxRetries  int
+}
+
+// newWebhookAuditSink - audit.k8s.io/v1 EventList를 POST하는 웹훅 싱크 (Splunk HEC, Logstash 등)
+func newWebhookAuditSink(url string) *httpAuditSink {
+	return &httpAuditSink{
+		name:        "webhook",
+		url:         url,
+		contentType: "application/json",
+		encode: func(events []*AuditEvent) ([]byte, error) {
+			return json.Marshal(map[string]interface{}{
+				"kind":       "EventList",
+				"apiVersion": "audit.k8s.io/v1",
+				"items":      events,
+			})
+		},
+		client:     &http.Client{Timeout: 10 * time.Second},
+		maxRetries: 3,
+	}
+}
+
+// newKafkaAuditSink - Kafka REST Proxy를 통해 토픽에 기록하는 싱크
+func newKafkaAuditSink(restURL, topic string) *httpAuditSink {
+	return &httpAuditSink{
+		name:        "kafka",
+		url:         strings.TrimRight(restURL, "/") + "/topics/" + topic,
+		contentType: "application/vnd.kafka.json.v2+json",
+		encode: func(events []*AuditEvent) ([]byte, error) {
+			records := make([]map[string]interface{}, len(events))
+			for i, event := range events {
+				records[i] = map[string]interface{}{"key": event.AuditID, "value": event}
+			}
+			return json.Marshal(map[string]interface{}{"records": records})
+		},
+		client:     &http.Client{Timeout: 10 * time.Second},
+		maxRetries: 3,
+	}
+}
+
+// Name - 싱크 이름
+func (h *httpAuditSink) Name() string { return h.name }
+
+// Write - 지수 백오프로 재시도하며 전송
+func (h *httpAuditSink) Write(events []*AuditEvent) error {
+	body, err := h.encode(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit batch: %v", err)
+	}
+
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err = h.post(body)
+		if err == nil || attempt >= h.maxRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post - 단일 전송 시도
+func (h *httpAuditSink) post(body []byte) error {
+	resp, err := h.client.Post(h.url, h.contentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned HTTP %d", h.url, resp.StatusCode)
+	}
+	return nil
+}
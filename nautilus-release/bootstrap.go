@@ -0,0 +1,281 @@
+// Bootstrap Manifests - 마스터 시작 시 기본 클러스터 객체 자동 적용
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BootstrapObject - 적용된 객체 하나와 kubectl 결과 (created, configured, unchanged)
+type BootstrapObject struct {
+	Object string `json:"object"`
+	Result string `json:"result"`
+}
+
+// BootstrapFile - 매니페스트 파일별 적용 상태
+type BootstrapFile struct {
+	File      string            `json:"file"`
+	Hash      string            `json:"hash"`
+	Applied   bool              `json:"applied"`
+	AppliedAt time.Time         `json:"applied_at,omitempty"`
+	Attempts  int               `json:"attempts"`
+	Objects   []BootstrapObject `json:"objects,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+/*
+BootstrapManager - 부트스트랩 디렉토리의 매니페스트를 K3s에 적용 (k3s auto-deploying manifests와 같은 방식)
+
+  - NAUTILUS_BOOTSTRAP_DIR의 *.yaml, *.yml, *.json을 파일 이름 순서로 kubectl apply (네임스페이스는 00-namespaces.yaml처럼 앞에 두기)
+  - <파일>.skip 파일이 있으면 해당 매니페스트는 적용하지 않음
+  - apply는 멱등이므로 재시작마다 다시 적용해도 안전, 이후에는 내용이 바뀌었거나 실패한 파일만 재적용
+  - 객체별 결과는 /api/v1/bootstrap과 메트릭으로 확인
+*/
+type BootstrapManager struct {
+	logger   *logrus.Logger
+	k3sMgr   *K3sManager
+	dir      string
+	interval time.Duration
+	files    map[string]*BootstrapFile
+	mutex    sync.RWMutex
+}
+
+// NewBootstrapManager - 새 Bootstrap Manager 생성
+func NewBootstrapManager(logger *logrus.Logger, k3sMgr *K3sManager) *BootstrapManager {
+	return &BootstrapManager{
+		logger:   logger,
+		k3sMgr:   k3sMgr,
+		dir:      getEnvOrDefault("NAUTILUS_BOOTSTRAP_DIR", "/etc/nautilus/bootstrap"),
+		interval: envSeconds("NAUTILUS_BOOTSTRAP_INTERVAL_SECONDS", 30),
+		files:    make(map[string]*BootstrapFile),
+	}
+}
+
+// Start - K3s API 서버가 뜨면 적용하고, 이후 주기적으로 변경/실패 파일 재적용
+func (b *BootstrapManager) Start(ctx context.Context) {
+	if _, err := os.Stat(b.dir); os.IsNotExist(err) {
+		b.logger.Debugf("Bootstrap directory %s not found, skipping bootstrap manifests", b.dir)
+		return
+	}
+	b.logger.Infof("📜 Starting bootstrap manifests from %s", b.dir)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		if b.k3sMgr.IsRunning() {
+			b.applyAll()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyAll - 디렉토리를 다시 읽어 새로 생기거나 바뀌었거나 실패한 파일 적용
+func (b *BootstrapManager) applyAll() {
+	manifests, err := b.listManifests()
+	if err != nil {
+		b.logger.Errorf("❌ Failed to read bootstrap directory: %v", err)
+		return
+	}
+
+	present := make(map[string]bool, len(manifests))
+	for _, name := range manifests {
+		present[name] = true
+		data, err := os.ReadFile(filepath.Join(b.dir, name))
+		if err != nil {
+			b.record(name, "", nil, err)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		b.mutex.RLock()
+		previous := b.files[name]
+		b.mutex.RUnlock()
+		if previous != nil && previous.Applied && previous.Hash == hash {
+			continue
+		}
+
+		output, err := b.k3sMgr.RunKubectl(data, "apply", "-f", "-")
+		objects := parseApplyOutput(output)
+		b.record(name, hash, objects, err)
+	}
+
+	// 삭제되었거나 .skip 처리된 파일은 상태에서 제외 (이미 만든 객체는 지우지 않음)
+	b.mutex.Lock()
+	for name := range b.files {
+		if !present[name] {
+			delete(b.files, name)
+		}
+	}
+	b.mutex.Unlock()
+}
+
+// listManifests - 적용 대상 파일 이름 (정렬, .skip 제외)
+func (b *BootstrapManager) listManifests() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make(map[string]bool)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".skip") {
+			skipped[strings.TrimSuffix(entry.Name(), ".skip")] = true
+		}
+	}
+
+	var manifests []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || skipped[name] {
+			continue
+		}
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml", ".json":
+			manifests = append(manifests, name)
+		}
+	}
+	sort.Strings(manifests)
+	return manifests, nil
+}
+
+// record - 파일 적용 결과 저장 및 로그
+func (b *BootstrapManager) record(name, hash string, objects []BootstrapObject, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	file := b.files[name]
+	if file == nil || file.Hash != hash {
+		file = &BootstrapFile{File: name}
+		b.files[name] = file
+	}
+	file.Hash = hash
+	file.Attempts++
+	file.Objects = objects
+	if err != nil {
+		file.Applied = false
+		file.Error = err.Error()
+		// 같은 오류를 매 주기 반복 기록하지 않도록 첫 실패만 Error
+		if file.Attempts == 1 {
+			b.logger.Errorf("❌ Bootstrap manifest %s failed: %v", name, err)
+		} else {
+			b.logger.Debugf("Bootstrap manifest %s still failing (attempt %d): %v", name, file.Attempts, err)
+		}
+		return
+	}
+
+	file.Applied = true
+	file.AppliedAt = time.Now()
+	file.Error = ""
+	changed := 0
+	for _, object := range objects {
+		if object.Result != "unchanged" {
+			changed++
+		}
+	}
+	b.logger.Infof("📜 Applied bootstrap manifest %s (%d objects, %d changed)", name, len(objects), changed)
+}
+
+// parseApplyOutput - "namespace/monitoring created" 형식의 kubectl apply 출력 파싱
+func parseApplyOutput(output []byte) []BootstrapObject {
+	var objects []BootstrapObject
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.Contains(fields[0], "/") {
+			continue
+		}
+		objects = append(objects, BootstrapObject{
+			Object: fields[0],
+			Result: strings.Join(fields[1:], " "),
+		})
+	}
+	return objects
+}
+
+// Files - 파일 이름 순서의 적용 상태 복사본
+func (b *BootstrapManager) Files() []BootstrapFile {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	files := make([]BootstrapFile, 0, len(b.files))
+	for _, file := range b.files {
+		files = append(files, *file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].File < files[j].File })
+	return files
+}
+
+// handleBootstrap - 부트스트랩 매니페스트 적용 상태 조회 (GET /api/v1/bootstrap)
+func (b *BootstrapManager) handleBootstrap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	files := b.Files()
+	pending := 0
+	for _, file := range files {
+		if !file.Applied {
+			pending++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "success",
+		"directory": b.dir,
+		"complete":  pending == 0,
+		"pending":   pending,
+		"files":     files,
+	})
+}
+
+// writeMetrics - 부트스트랩 적용 메트릭 출력
+func (b *BootstrapManager) writeMetrics(w io.Writer) {
+	files := b.Files()
+	if len(files) == 0 {
+		return
+	}
+
+	writeMetricHeader(w, "nautilus_bootstrap_manifest_applied", "gauge", "Whether each bootstrap manifest is applied at its current content")
+	for _, file := range files {
+		applied := 0.0
+		if file.Applied {
+			applied = 1
+		}
+		writeMetric(w, "nautilus_bootstrap_manifest_applied", map[string]string{"file": file.File}, applied)
+	}
+
+	results := make(map[string]int)
+	for _, file := range files {
+		for _, object := range file.Objects {
+			results[object.Result]++
+		}
+	}
+	writeMetricHeader(w, "nautilus_bootstrap_objects", "gauge", "Bootstrap objects by result of the last apply")
+	names := make([]string, 0, len(results))
+	for result := range results {
+		names = append(names, result)
+	}
+	sort.Strings(names)
+	for _, result := range names {
+		writeMetric(w, "nautilus_bootstrap_objects", map[string]string{"result": result}, float64(results[result]))
+	}
+}
@@ -0,0 +1,70 @@
+// Build Provenance - 마스터 빌드 출처(커밋, 빌더, SBOM 다이제스트) 서명 조회
+//
+// 워커와 스테이커가 TEE 안팎에서 실행 중인 마스터가 허용된 릴리스 빌드인지 확인할 수 있도록
+// 바이너리에 기록된 출처를 호출자 nonce와 묶어 응답 서명 키(ed25519)로 서명합니다.
+// 워커는 고정 공개키로 서명을 검증한 뒤 SBOM 다이제스트를 온체인 build_allowlist와 대조합니다.
+// 서명 형식은 pkg/version Provenance가 마스터와 워커에 공통으로 정의합니다.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	version "github.com/k3s-io/daas-version"
+)
+
+// defaultSBOMPath - 이미지에 함께 배포되는 SBOM (Dockerfile의 go list -m all 결과)
+const defaultSBOMPath = "/usr/share/k3s-daas/sbom.txt"
+
+// Provenance - nonce에 대한 마스터 빌드 출처 서명
+func (s *RequestSigner) Provenance(nonce string) *version.Provenance {
+	provenance := &version.Provenance{
+		Info:     version.Get("master"),
+		Nonce:    nonce,
+		IssuedAt: time.Now().Unix(),
+	}
+	provenance.Sign(s.publicKey(), s.signMessage)
+	return provenance
+}
+
+// handleVersion - GET /api/v1/version?nonce= (인증 없음, nonce 없이 받은 서명은 재전송될 수 있음)
+func (s *RequestSigner) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nonce := r.URL.Query().Get("nonce")
+	if nonce != "" && (len(nonce) < 16 || len(nonce) > 128) {
+		http.Error(w, "nonce must be 16-128 characters", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   s.Provenance(nonce),
+	})
+}
+
+// handleSBOM - GET /api/v1/version/sbom (sha256이 서명된 sbom_digest와 같아야 함)
+func handleSBOM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := os.ReadFile(getEnvOrDefault("NAUTILUS_SBOM_PATH", defaultSBOMPath))
+	if err != nil {
+		http.Error(w, "SBOM not available in this build", http.StatusNotFound)
+		return
+	}
+	digest := sha256.Sum256(data)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Daas-SBOM-Digest", "sha256:"+hex.EncodeToString(digest[:]))
+	w.Write(data)
+}
@@ -0,0 +1,711 @@
+// Canary Controller - 서비스 메시 없이 가중치 어노테이션으로 canary Deployment에 트래픽 분배
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+kube-proxy는 엔드포인트마다 같은 확률로 트래픽을 보내므로, 엔드포인트 구성 비율로 가중치를 만듭니다.
+
+	Deployment  k3s-daas.io/canary-for: <service>          canary Deployment 표시 (같은 네임스페이스의 Service)
+	Deployment  k3s-daas.io/canary-weight: "20"            canary로 보낼 비율(%) (없으면 Service의 같은 어노테이션, 둘 다 없으면 0)
+	Deployment  k3s-daas.io/canary-max-failure-rate: "50"  canary Pod 실패 비율(%) 상한 (기본 50)
+
+canary가 붙은 Service는 컨트롤러가 selector를 넘겨받아(k3s-daas.io/canary-selector에 보관) 직접 EndpointSlice를
+관리하고, 준비된 stable/canary Pod 중 비율이 가중치에 가장 가까운 조합만 엔드포인트로 넣습니다.
+canary Pod가 준비 상태를 잃거나 재시작(프로브 실패)하는 비율이 상한을 canaryFailureChecks번 연속 넘으면
+가중치를 0으로 돌리고 canary Deployment를 0으로 줄인 뒤 k3s-daas.io/canary-rolled-back에 사유를 남깁니다.
+canary Deployment가 없어지면 selector를 되돌리고 EndpointSlice를 지워 K8s 기본 동작으로 돌아갑니다.
+*/
+
+const (
+	canaryForAnnot         = "k3s-daas.io/canary-for"
+	canaryWeightAnnot      = "k3s-daas.io/canary-weight"
+	canaryMaxFailureAnnot  = "k3s-daas.io/canary-max-failure-rate"
+	canaryRolledBackAnnot  = "k3s-daas.io/canary-rolled-back"
+	canarySelectorAnnot    = "k3s-daas.io/canary-selector"
+	canaryManagedBy        = "nautilus.k3s-daas.io/canary"
+	canaryDefaultMaxFail   = 50
+	canaryFailureChecks    = 3   // 연속으로 상한을 넘어야 롤백 (일시적인 재시작 무시)
+	canaryMaxEndpointCount = 200 // 가중치 조합 탐색 범위 (역할별 Pod 수 상한)
+)
+
+// CanaryState - Service별 canary 상태 (API 응답)
+type CanaryState struct {
+	Namespace       string    `json:"namespace"`
+	Service         string    `json:"service"`
+	Deployment      string    `json:"deployment"`
+	Weight          int       `json:"weight"`           // 요청한 가중치 (%)
+	EffectiveWeight float64   `json:"effective_weight"` // 엔드포인트 비율로 실제 적용된 가중치 (%)
+	StableReady     int       `json:"stable_ready"`
+	CanaryReady     int       `json:"canary_ready"`
+	StableEndpoints int       `json:"stable_endpoints"`
+	CanaryEndpoints int       `json:"canary_endpoints"`
+	FailureRate     float64   `json:"failure_rate"` // 마지막 확인 때 canary Pod 실패 비율 (%)
+	MaxFailureRate  int       `json:"max_failure_rate"`
+	FailingChecks   int       `json:"failing_checks"`
+	RolledBack      string    `json:"rolled_back,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	restarts map[string]int // Pod → 마지막으로 본 재시작 횟수
+	applied  string         // 마지막으로 적용한 EndpointSlice (변경 시에만 apply)
+}
+
+// canaryObject - Deployment/Service 목록에서 쓰는 필드
+type canaryObject struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Selector            json.RawMessage `json:"selector"` // Service: map, Deployment: LabelSelector
+		TrafficDistribution string          `json:"trafficDistribution"`
+		Ports               []struct {
+			Name       string          `json:"name"`
+			Protocol   string          `json:"protocol"`
+			TargetPort json.RawMessage `json:"targetPort"`
+			Port       int             `json:"port"`
+		} `json:"ports"`
+	} `json:"spec"`
+}
+
+// canaryPod - 엔드포인트 후보 Pod
+type canaryPod struct {
+	Metadata struct {
+		Name              string            `json:"name"`
+		Labels            map[string]string `json:"labels"`
+		DeletionTimestamp *time.Time        `json:"deletionTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		NodeName   string `json:"nodeName"`
+		Containers []struct {
+			Ports []struct {
+				Name          string `json:"name"`
+				ContainerPort int    `json:"containerPort"`
+			} `json:"ports"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		PodIP      string `json:"podIP"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		ContainerStatuses []struct {
+			RestartCount int `json:"restartCount"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+func (p *canaryPod) ready() bool {
+	if p.Metadata.DeletionTimestamp != nil || p.Status.PodIP == "" {
+		return false
+	}
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == "Ready" {
+			return condition.Status == "True"
+		}
+	}
+	return false
+}
+
+func (p *canaryPod) restartCount() int {
+	total := 0
+	for _, status := range p.Status.ContainerStatuses {
+		total += status.RestartCount
+	}
+	return total
+}
+
+// CanaryController - canary 가중치 reconcile 및 자동 롤백
+type CanaryController struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	adminToken string
+
+	mutex     sync.Mutex
+	states    map[string]*CanaryState // <namespace>/<service>
+	rollbacks int
+	failures  int
+}
+
+// NewCanaryController - 새 canary 컨트롤러 생성
+func NewCanaryController(logger *logrus.Logger, k3sMgr *K3sManager) *CanaryController {
+	return &CanaryController{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		states:     make(map[string]*CanaryState),
+	}
+}
+
+// ReconcileAll - canary Deployment가 가리키는 Service를 모두 reconcile하고, 끝난 canary는 Service를 원래대로 되돌림
+func (c *CanaryController) ReconcileAll() {
+	deployments, err := c.listObjects("deployments")
+	if err != nil {
+		c.logger.Warnf("⚠️ Canary: failed to list deployments: %v", err)
+		return
+	}
+	services, err := c.listObjects("services")
+	if err != nil {
+		c.logger.Warnf("⚠️ Canary: failed to list services: %v", err)
+		return
+	}
+
+	canaries := make(map[string]*canaryObject)
+	for i := range deployments {
+		deployment := &deployments[i]
+		target := deployment.Metadata.Annotations[canaryForAnnot]
+		if target == "" {
+			continue
+		}
+		key := deployment.Metadata.Namespace + "/" + target
+		if existing, ok := canaries[key]; ok {
+			// Service 하나에 canary는 하나만 (이름 순으로 첫 번째)
+			if existing.Metadata.Name < deployment.Metadata.Name {
+				continue
+			}
+		}
+		canaries[key] = deployment
+	}
+
+	seen := make(map[string]bool)
+	for i := range services {
+		service := &services[i]
+		key := service.Metadata.Namespace + "/" + service.Metadata.Name
+		deployment, ok := canaries[key]
+		switch {
+		case ok:
+			seen[key] = true
+			c.reconcile(service, deployment)
+		case service.Metadata.Annotations[canarySelectorAnnot] != "":
+			c.release(service)
+		}
+	}
+
+	c.mutex.Lock()
+	for key := range c.states {
+		if !seen[key] {
+			delete(c.states, key)
+		}
+	}
+	c.mutex.Unlock()
+}
+
+func (c *CanaryController) listObjects(resource string) ([]canaryObject, error) {
+	output, err := c.k3sMgr.RunKubectl(nil, "get", resource, "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []canaryObject `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", resource, err)
+	}
+	return list.Items, nil
+}
+
+// stateLocked - Service 상태 (없으면 생성, 호출자가 mutex 보유)
+func (c *CanaryController) stateLocked(namespace, service string) *CanaryState {
+	key := namespace + "/" + service
+	state, ok := c.states[key]
+	if !ok {
+		state = &CanaryState{Namespace: namespace, Service: service, restarts: make(map[string]int)}
+		c.states[key] = state
+	}
+	return state
+}
+
+// reconcile - selector 인수, 실패율 확인, 가중치에 맞는 EndpointSlice 적용
+func (c *CanaryController) reconcile(service, deployment *canaryObject) {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+
+	c.mutex.Lock()
+	state := c.stateLocked(namespace, name)
+	c.mutex.Unlock()
+
+	err := c.sync(state, service, deployment)
+
+	c.mutex.Lock()
+	state.UpdatedAt = time.Now()
+	state.Error = ""
+	if err != nil {
+		state.Error = err.Error()
+		c.failures++
+	}
+	c.mutex.Unlock()
+	if err != nil {
+		c.logger.Warnf("⚠️ Canary %s/%s: %v", namespace, name, err)
+	}
+}
+
+func (c *CanaryController) sync(state *CanaryState, service, deployment *canaryObject) error {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+
+	// 1. Service selector 인수 (원래 selector는 어노테이션에 보관)
+	selector := map[string]string{}
+	if saved := service.Metadata.Annotations[canarySelectorAnnot]; saved != "" {
+		if err := json.Unmarshal([]byte(saved), &selector); err != nil {
+			return fmt.Errorf("invalid %s annotation: %v", canarySelectorAnnot, err)
+		}
+	} else {
+		if err := json.Unmarshal(service.Spec.Selector, &selector); err != nil || len(selector) == 0 {
+			return fmt.Errorf("service has no selector to split between stable and canary pods")
+		}
+		saved, _ := json.Marshal(selector)
+		patch, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{canarySelectorAnnot: string(saved)}},
+			"spec":     map[string]interface{}{"selector": nil},
+		})
+		if _, err := c.k3sMgr.RunKubectl(nil, "patch", "service", name, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+			return fmt.Errorf("failed to take over selector: %v", err)
+		}
+		c.logger.Infof("🐤 Canary %s/%s: endpoints now managed for deployment %s", namespace, name, deployment.Metadata.Name)
+	}
+
+	var canarySelector struct {
+		MatchLabels map[string]string `json:"matchLabels"`
+	}
+	if err := json.Unmarshal(deployment.Spec.Selector, &canarySelector); err != nil || len(canarySelector.MatchLabels) == 0 {
+		return fmt.Errorf("canary deployment %s has no matchLabels selector", deployment.Metadata.Name)
+	}
+
+	// 2. 후보 Pod 분류
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "pods", "-n", namespace, "-l", labelSelectorString(selector), "-o", "json")
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	var podList struct {
+		Items []canaryPod `json:"items"`
+	}
+	if err := json.Unmarshal(output, &podList); err != nil {
+		return fmt.Errorf("failed to parse pods: %v", err)
+	}
+	sort.Slice(podList.Items, func(i, j int) bool { return podList.Items[i].Metadata.Name < podList.Items[j].Metadata.Name })
+
+	var stable, canary []*canaryPod
+	canaryTotal, canaryFailing := 0, 0
+	restarts := make(map[string]int)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		isCanary := labelsMatch(pod.Metadata.Labels, canarySelector.MatchLabels)
+		if isCanary && pod.Metadata.DeletionTimestamp == nil {
+			canaryTotal++
+			count := pod.restartCount()
+			restarts[pod.Metadata.Name] = count
+			c.mutex.Lock()
+			previous, known := state.restarts[pod.Metadata.Name]
+			c.mutex.Unlock()
+			if !pod.ready() || (known && count > previous) {
+				canaryFailing++
+			}
+		}
+		if !pod.ready() {
+			continue
+		}
+		if isCanary {
+			canary = append(canary, pod)
+		} else {
+			stable = append(stable, pod)
+		}
+	}
+
+	// 3. 가중치와 실패율
+	annotations := deployment.Metadata.Annotations
+	weight, err := canaryPercent(annotations[canaryWeightAnnot], service.Metadata.Annotations[canaryWeightAnnot], 0)
+	if err != nil {
+		return err
+	}
+	maxFailure, err := canaryPercent(annotations[canaryMaxFailureAnnot], "", canaryDefaultMaxFail)
+	if err != nil {
+		return err
+	}
+	rolledBack := annotations[canaryRolledBackAnnot]
+	if rolledBack != "" {
+		weight = 0
+	}
+
+	failureRate := 0.0
+	if canaryTotal > 0 {
+		failureRate = float64(canaryFailing) * 100 / float64(canaryTotal)
+	}
+	c.mutex.Lock()
+	state.Deployment = deployment.Metadata.Name
+	state.restarts = restarts
+	state.FailureRate = failureRate
+	state.MaxFailureRate = maxFailure
+	if rolledBack == "" && weight > 0 && failureRate > float64(maxFailure) {
+		state.FailingChecks++
+	} else {
+		state.FailingChecks = 0
+	}
+	failingChecks := state.FailingChecks
+	c.mutex.Unlock()
+
+	if failingChecks >= canaryFailureChecks {
+		reason := fmt.Sprintf("%.0f%% of canary pods failing probes (limit %d%%) at %s", failureRate, maxFailure, time.Now().UTC().Format(time.RFC3339))
+		if err := c.rollback(namespace, deployment.Metadata.Name, reason); err != nil {
+			return err
+		}
+		rolledBack, weight = reason, 0
+	}
+
+	// 4. 가중치에 맞는 엔드포인트 조합
+	stableCount, canaryCount := weightedCounts(len(stable), len(canary), weight)
+	endpoints := append(append([]*canaryPod{}, stable[:stableCount]...), canary[:canaryCount]...)
+
+	c.mutex.Lock()
+	state.Weight = weight
+	state.RolledBack = rolledBack
+	state.StableReady, state.CanaryReady = len(stable), len(canary)
+	state.StableEndpoints, state.CanaryEndpoints = stableCount, canaryCount
+	state.EffectiveWeight = 0
+	if stableCount+canaryCount > 0 {
+		state.EffectiveWeight = math.Round(float64(canaryCount)*1000/float64(stableCount+canaryCount)) / 10
+	}
+	applied := state.applied
+	c.mutex.Unlock()
+
+	slice, err := canaryEndpointSlice(service, endpoints)
+	if err != nil {
+		return err
+	}
+	if slice == applied {
+		return nil
+	}
+	if _, err := c.k3sMgr.RunKubectl([]byte(slice), "apply", "-f", "-"); err != nil {
+		return fmt.Errorf("failed to apply endpoint slice: %v", err)
+	}
+	c.mutex.Lock()
+	state.applied = slice
+	c.mutex.Unlock()
+	c.logger.Infof("🐤 Canary %s/%s: %d stable + %d canary endpoints (weight %d%%)", namespace, name, stableCount, canaryCount, weight)
+	return nil
+}
+
+// rollback - 가중치 0, 사유 기록, canary Deployment를 0으로 축소
+func (c *CanaryController) rollback(namespace, deployment, reason string) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{canaryWeightAnnot: "0", canaryRolledBackAnnot: reason}},
+	})
+	if _, err := c.k3sMgr.RunKubectl(nil, "patch", "deployment", deployment, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+		return fmt.Errorf("failed to roll back canary: %v", err)
+	}
+	if _, err := c.k3sMgr.RunKubectl(nil, "scale", "deployment", deployment, "-n", namespace, "--replicas=0"); err != nil {
+		c.logger.Warnf("⚠️ Canary %s/%s rolled back but scale down failed: %v", namespace, deployment, err)
+	}
+
+	c.mutex.Lock()
+	c.rollbacks++
+	c.mutex.Unlock()
+	c.logger.Warnf("⏪ Canary %s/%s rolled back: %s", namespace, deployment, reason)
+	return nil
+}
+
+// release - canary가 끝난 Service의 selector 복원 및 관리 EndpointSlice 삭제
+func (c *CanaryController) release(service *canaryObject) {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+	selector := map[string]string{}
+	if err := json.Unmarshal([]byte(service.Metadata.Annotations[canarySelectorAnnot]), &selector); err != nil {
+		c.logger.Warnf("⚠️ Canary %s/%s: cannot restore selector: %v", namespace, name, err)
+		return
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{canarySelectorAnnot: nil}},
+		"spec":     map[string]interface{}{"selector": selector},
+	})
+	if _, err := c.k3sMgr.RunKubectl(nil, "patch", "service", name, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+		c.logger.Warnf("⚠️ Canary %s/%s: failed to restore selector: %v", namespace, name, err)
+		return
+	}
+	if _, err := c.k3sMgr.RunKubectl(nil, "delete", "endpointslice", name+"-canary", "-n", namespace, "--ignore-not-found"); err != nil {
+		c.logger.Warnf("⚠️ Canary %s/%s: failed to delete endpoint slice: %v", namespace, name, err)
+	}
+	c.logger.Infof("🐤 Canary %s/%s finished, selector restored", namespace, name)
+}
+
+// canaryEndpointSlice - canary 컨트롤러가 관리하는 <service>-canary EndpointSlice
+func canaryEndpointSlice(service *canaryObject, pods []*canaryPod) (string, error) {
+	return endpointSliceManifest(service, pods, service.Metadata.Name+"-canary", canaryManagedBy, nil)
+}
+
+// endpointSliceManifest - Service 포트를 Pod 포트로 풀어낸 EndpointSlice 매니페스트 (decorate로 엔드포인트에 필드 추가)
+func endpointSliceManifest(service *canaryObject, pods []*canaryPod, sliceName, managedBy string, decorate func(pod *canaryPod, endpoint map[string]interface{})) (string, error) {
+	ports := make([]map[string]interface{}, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		target := port.Port
+		var named string
+		if len(port.TargetPort) > 0 {
+			if json.Unmarshal(port.TargetPort, &target) != nil {
+				json.Unmarshal(port.TargetPort, &named)
+				target = 0
+			}
+		}
+		// 이름 있는 targetPort는 첫 Pod의 컨테이너 포트로 해석 (같은 템플릿 가정)
+		if named != "" && len(pods) > 0 {
+			for _, container := range pods[0].Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == named {
+						target = containerPort.ContainerPort
+					}
+				}
+			}
+		}
+		if target == 0 {
+			if len(pods) > 0 {
+				return "", fmt.Errorf("cannot resolve target port %q", named)
+			}
+			continue
+		}
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		ports = append(ports, map[string]interface{}{"name": port.Name, "protocol": protocol, "port": target})
+	}
+
+	endpoints := make([]map[string]interface{}, 0, len(pods))
+	for _, pod := range pods {
+		endpoint := map[string]interface{}{
+			"addresses":  []string{pod.Status.PodIP},
+			"conditions": map[string]bool{"ready": true},
+			"targetRef":  map[string]string{"kind": "Pod", "name": pod.Metadata.Name, "namespace": service.Metadata.Namespace},
+		}
+		if pod.Spec.NodeName != "" {
+			endpoint["nodeName"] = pod.Spec.NodeName
+		}
+		if decorate != nil {
+			decorate(pod, endpoint)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	addressType := "IPv4"
+	if len(pods) > 0 && strings.Contains(pods[0].Status.PodIP, ":") {
+		addressType = "IPv6"
+	}
+	slice, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "discovery.k8s.io/v1",
+		"kind":       "EndpointSlice",
+		"metadata": map[string]interface{}{
+			"name":      sliceName,
+			"namespace": service.Metadata.Namespace,
+			"labels": map[string]string{
+				"kubernetes.io/service-name":             service.Metadata.Name,
+				"endpointslice.kubernetes.io/managed-by": managedBy,
+			},
+		},
+		"addressType": addressType,
+		"ports":       ports,
+		"endpoints":   endpoints,
+	})
+	return string(slice), err
+}
+
+/*
+weightedCounts - canary 비율이 weight(%)에 가장 가까운 (stable, canary) 엔드포인트 수
+0 < weight < 100이면 양쪽에서 최소 하나씩 넣고, 같은 오차라면 엔드포인트가 많은 조합을 고릅니다.
+한쪽에 준비된 Pod가 없으면 가용성을 위해 다른 쪽을 모두 씁니다.
+*/
+func weightedCounts(stable, canary, weight int) (int, int) {
+	switch {
+	case canary == 0 || (weight <= 0 && stable > 0):
+		return stable, 0
+	case stable == 0 || weight >= 100:
+		return 0, canary
+	}
+
+	target := float64(weight) / 100
+	bestS, bestC, bestErr := stable, 0, math.Inf(1)
+	for s := 1; s <= stable && s <= canaryMaxEndpointCount; s++ {
+		for n := 1; n <= canary && n <= canaryMaxEndpointCount; n++ {
+			diff := math.Abs(float64(n)/float64(s+n) - target)
+			if diff < bestErr-1e-9 || (math.Abs(diff-bestErr) <= 1e-9 && s+n > bestS+bestC) {
+				bestS, bestC, bestErr = s, n, diff
+			}
+		}
+	}
+	return bestS, bestC
+}
+
+// canaryPercent - 0~100 정수 어노테이션 (primary가 비어 있으면 fallback, 둘 다 비면 def)
+func canaryPercent(primary, fallback string, def int) (int, error) {
+	value := primary
+	if value == "" {
+		value = fallback
+	}
+	if value == "" {
+		return def, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("invalid percentage %q (expected 0-100)", value)
+	}
+	return percent, nil
+}
+
+func labelsMatch(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// labelSelectorString - kubectl -l 형식 (정렬해 항상 같은 문자열)
+func labelSelectorString(selector map[string]string) string {
+	parts := make([]string, 0, len(selector))
+	for key, value := range selector {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// Manages - Service에 canary가 붙어 있는지 (마지막 reconcile 기준)
+func (c *CanaryController) Manages(namespace, service string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, ok := c.states[namespace+"/"+service]
+	return ok
+}
+
+// States - canary 상태 목록
+func (c *CanaryController) States() []CanaryState {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	states := make([]CanaryState, 0, len(c.states))
+	for _, state := range c.states {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Namespace+"/"+states[i].Service < states[j].Namespace+"/"+states[j].Service
+	})
+	return states
+}
+
+// SetWeight - canary Deployment 가중치 변경 (가중치를 올리면 롤백 표시도 지움, 바로 reconcile)
+func (c *CanaryController) SetWeight(namespace, service string, weight int) (*CanaryState, error) {
+	c.mutex.Lock()
+	state, ok := c.states[namespace+"/"+service]
+	deployment := ""
+	if ok {
+		deployment = state.Deployment
+	}
+	c.mutex.Unlock()
+	if deployment == "" {
+		return nil, fmt.Errorf("no canary deployment targets service %s/%s", namespace, service)
+	}
+
+	annotations := map[string]interface{}{canaryWeightAnnot: strconv.Itoa(weight)}
+	if weight > 0 {
+		annotations[canaryRolledBackAnnot] = nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if _, err := c.k3sMgr.RunKubectl(nil, "patch", "deployment", deployment, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+		return nil, err
+	}
+	c.logger.Infof("🐤 Canary %s/%s weight set to %d%%", namespace, service, weight)
+
+	c.ReconcileAll()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if state, ok := c.states[namespace+"/"+service]; ok {
+		copied := *state
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("canary for %s/%s disappeared during reconcile", namespace, service)
+}
+
+/*
+handleCanaries - canary 상태 조회와 가중치 변경 (/api/v1/canaries, 관리자 토큰 필요)
+
+	GET                                                      Service별 상태
+	POST {"namespace": "", "service": "", "weight": 0-100}   가중치 변경 (롤백 후 다시 올리려면 canary를 다시 scale up)
+*/
+func (c *CanaryController) handleCanaries(w http.ResponseWriter, r *http.Request) {
+	if c.adminToken == "" {
+		http.Error(w, "Canary API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, c.States())
+
+	case http.MethodPost:
+		var body struct {
+			Namespace string `json:"namespace"`
+			Service   string `json:"service"`
+			Weight    *int   `json:"weight"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Weight == nil {
+			http.Error(w, "Invalid weight request", http.StatusBadRequest)
+			return
+		}
+		if *body.Weight < 0 || *body.Weight > 100 {
+			http.Error(w, "weight must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+		if body.Namespace == "" {
+			body.Namespace = "default"
+		}
+		if !c.k3sMgr.IsRunning() {
+			http.Error(w, "K3s control plane is not running", http.StatusServiceUnavailable)
+			return
+		}
+		state, err := c.SetWeight(body.Namespace, body.Service, *body.Weight)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, state)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - Service별 가중치와 롤백 수
+func (c *CanaryController) writeMetrics(w io.Writer) {
+	states := c.States()
+	writeMetricHeader(w, "nautilus_canary_weight_percent", "gauge", "Requested canary traffic weight per service")
+	for _, state := range states {
+		writeMetric(w, "nautilus_canary_weight_percent", map[string]string{"namespace": state.Namespace, "service": state.Service}, float64(state.Weight))
+	}
+	writeMetricHeader(w, "nautilus_canary_effective_weight_percent", "gauge", "Canary share of service endpoints currently published")
+	for _, state := range states {
+		writeMetric(w, "nautilus_canary_effective_weight_percent", map[string]string{"namespace": state.Namespace, "service": state.Service}, state.EffectiveWeight)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	writeMetricHeader(w, "nautilus_canary_rollbacks_total", "counter", "Canaries rolled back automatically for probe failures")
+	writeMetric(w, "nautilus_canary_rollbacks_total", nil, float64(c.rollbacks))
+	writeMetricHeader(w, "nautilus_canary_sync_errors_total", "counter", "Canary reconcile errors")
+	writeMetric(w, "nautilus_canary_sync_errors_total", nil, float64(c.failures))
+}
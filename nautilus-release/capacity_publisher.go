@@ -0,0 +1,303 @@
+// Capacity Publisher - 스케줄 불가 Pod 수요를 온체인에 공시하여 스테이커 모집
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	capacityPublishInterval = 60 * time.Second
+	capacityRepublishPeriod = 10 * time.Minute
+	maxRewardMultiplier     = 5.0
+)
+
+// CapacitySupply - 현재 클러스터 공급 용량
+type CapacitySupply struct {
+	TotalWorkers         int   `json:"total_workers"`
+	ActiveWorkers        int   `json:"active_workers"`
+	AllocatableCPUMillis int64 `json:"allocatable_cpu_millis"`
+	AllocatableMemoryMB  int64 `json:"allocatable_memory_mb"`
+}
+
+// CapacityDemand - 스케줄되지 못한 Pod의 요구 용량
+type CapacityDemand struct {
+	PendingPods      int   `json:"pending_pods"`
+	PendingCPUMillis int64 `json:"pending_cpu_millis"`
+	PendingMemoryMB  int64 `json:"pending_memory_mb"`
+}
+
+// CapacitySnapshot - 공급/수요 스냅샷
+type CapacitySnapshot struct {
+	Supply              CapacitySupply `json:"supply"`
+	Demand              CapacityDemand `json:"demand"`
+	RewardMultiplierBps uint64         `json:"reward_multiplier_bps"`
+	CapacityWanted      bool           `json:"capacity_wanted"`
+	LastPublishedAt     time.Time      `json:"last_published_at,omitempty"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// CapacityPublisher - 수요 계산 및 온체인 공시
+type CapacityPublisher struct {
+	logger        *logrus.Logger
+	k3sMgr        *K3sManager
+	workerPool    *WorkerPool
+	sui           *SuiIntegration
+	demandObject  string
+	snapshot      *CapacitySnapshot
+	lastPublished *CapacitySnapshot
+	mutex         sync.RWMutex
+	stream        *EventStream // 대시보드 스트림 (선택)
+}
+
+// NewCapacityPublisher - 새 Capacity Publisher 생성
+func NewCapacityPublisher(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration) *CapacityPublisher {
+	return &CapacityPublisher{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		workerPool:   k3sMgr.workerPool,
+		sui:          sui,
+		demandObject: getEnvOrDefault("CAPACITY_DEMAND_ID", ""),
+	}
+}
+
+// Start - 주기적으로 수요 계산 및 공시
+func (c *CapacityPublisher) Start(ctx context.Context) {
+	c.logger.Info("📣 Starting Capacity Publisher...")
+
+	ticker := time.NewTicker(capacityPublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !c.k3sMgr.IsRunning() {
+				continue
+			}
+			snapshot := c.Refresh()
+			c.publish(snapshot)
+		}
+	}
+}
+
+// Refresh - 공급/수요 재계산
+func (c *CapacityPublisher) Refresh() *CapacitySnapshot {
+	snapshot := &CapacitySnapshot{UpdatedAt: time.Now()}
+
+	stats := c.workerPool.GetWorkerStats()
+	snapshot.Supply.TotalWorkers = stats["total"]
+	snapshot.Supply.ActiveWorkers = stats["active"]
+
+	if cpu, memory, err := c.collectAllocatable(); err != nil {
+		c.logger.Warnf("⚠️ Failed to collect node allocatable: %v", err)
+	} else {
+		snapshot.Supply.AllocatableCPUMillis = cpu
+		snapshot.Supply.AllocatableMemoryMB = memory / (1 << 20)
+	}
+
+	if demand, err := c.collectPendingDemand(); err != nil {
+		c.logger.Warnf("⚠️ Failed to collect pending pods: %v", err)
+	} else {
+		snapshot.Demand = *demand
+	}
+
+	snapshot.CapacityWanted = snapshot.Demand.PendingPods > 0
+	snapshot.RewardMultiplierBps = rewardMultiplierBps(snapshot.Supply, snapshot.Demand)
+
+	c.mutex.Lock()
+	if c.lastPublished != nil {
+		snapshot.LastPublishedAt = c.lastPublished.UpdatedAt
+	}
+	previous := c.snapshot
+	c.snapshot = snapshot
+	c.mutex.Unlock()
+
+	// 공급/수요가 바뀐 경우에만 대시보드에 알림
+	if previous == nil || previous.Supply != snapshot.Supply || previous.Demand != snapshot.Demand ||
+		previous.CapacityWanted != snapshot.CapacityWanted || previous.RewardMultiplierBps != snapshot.RewardMultiplierBps {
+		c.stream.Publish(streamCapacity+".changed", "", "", snapshot)
+	}
+	return snapshot
+}
+
+// Snapshot - 마지막으로 계산된 스냅샷
+func (c *CapacityPublisher) Snapshot() *CapacitySnapshot {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.snapshot
+}
+
+// rewardMultiplierBps - 수요/공급 비율로 보상 배수 계산 (10000 = 1.0x)
+func rewardMultiplierBps(supply CapacitySupply, demand CapacityDemand) uint64 {
+	if demand.PendingPods == 0 {
+		return 10000
+	}
+
+	allocatable := math.Max(float64(supply.AllocatableCPUMillis), 1000)
+	multiplier := 1.0 + float64(demand.PendingCPUMillis)/allocatable
+
+	// 활성 워커가 하나도 없으면 최소 2배 보상
+	if supply.ActiveWorkers == 0 {
+		multiplier = math.Max(multiplier, 2.0)
+	}
+	multiplier = math.Min(multiplier, maxRewardMultiplier)
+
+	return uint64(math.Round(multiplier * 10000))
+}
+
+// publish - 수요가 변했거나 재공시 주기가 지났으면 온체인에 기록
+func (c *CapacityPublisher) publish(snapshot *CapacitySnapshot) {
+	if c.demandObject == "" || !c.sui.canSubmit() {
+		c.logger.Debugf("📝 Capacity demand not published (CAPACITY_DEMAND_ID or key not configured)")
+		return
+	}
+
+	c.mutex.RLock()
+	last := c.lastPublished
+	c.mutex.RUnlock()
+
+	if last != nil &&
+		last.Demand == snapshot.Demand &&
+		last.RewardMultiplierBps == snapshot.RewardMultiplierBps &&
+		time.Since(last.UpdatedAt) < capacityRepublishPeriod {
+		return
+	}
+
+	err := c.sui.callContract("capacity_demand", "publish_capacity_demand",
+		c.demandObject,
+		strconv.Itoa(snapshot.Demand.PendingPods),
+		strconv.FormatInt(snapshot.Demand.PendingCPUMillis, 10),
+		strconv.FormatInt(snapshot.Demand.PendingMemoryMB, 10),
+		strconv.Itoa(snapshot.Supply.ActiveWorkers),
+		strconv.FormatUint(snapshot.RewardMultiplierBps, 10),
+	)
+	if err != nil {
+		c.logger.Errorf("❌ Failed to publish capacity demand: %v", err)
+		return
+	}
+
+	c.mutex.Lock()
+	c.lastPublished = snapshot
+	c.mutex.Unlock()
+
+	c.logger.Infof("📣 Capacity demand published: %d pending pods, %dm CPU, %dMB memory, reward x%.2f",
+		snapshot.Demand.PendingPods, snapshot.Demand.PendingCPUMillis, snapshot.Demand.PendingMemoryMB,
+		float64(snapshot.RewardMultiplierBps)/10000)
+}
+
+// collectPendingDemand - 스케줄되지 못한 Pod의 요청 리소스 합산
+func (c *CapacityPublisher) collectPendingDemand() (*CapacityDemand, error) {
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces",
+		"--field-selector=status.phase=Pending", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []struct {
+			Spec struct {
+				NodeName   string `json:"nodeName"`
+				Containers []struct {
+					Resources struct {
+						Requests map[string]string `json:"requests"`
+					} `json:"resources"`
+				} `json:"containers"`
+			} `json:"spec"`
+			Status struct {
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %v", err)
+	}
+
+	demand := &CapacityDemand{}
+	var memoryBytes int64
+	for _, pod := range list.Items {
+		if pod.Spec.NodeName != "" {
+			continue
+		}
+		unschedulable := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == "PodScheduled" && condition.Status == "False" {
+				unschedulable = true
+			}
+		}
+		if !unschedulable {
+			continue
+		}
+
+		demand.PendingPods++
+		for _, container := range pod.Spec.Containers {
+			cpu, _ := parseCPUMillis(container.Resources.Requests["cpu"])
+			memory, _ := parseMemoryBytes(container.Resources.Requests["memory"])
+			demand.PendingCPUMillis += cpu
+			memoryBytes += memory
+		}
+	}
+	demand.PendingMemoryMB = memoryBytes / (1 << 20)
+
+	return demand, nil
+}
+
+// collectAllocatable - 전체 노드 할당 가능 리소스 합산
+func (c *CapacityPublisher) collectAllocatable() (int64, int64, error) {
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "nodes", "-o", "json")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var list struct {
+		Items []struct {
+			Status struct {
+				Allocatable map[string]string `json:"allocatable"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse node list: %v", err)
+	}
+
+	var cpuMillis, memoryBytes int64
+	for _, node := range list.Items {
+		cpu, _ := parseCPUMillis(node.Status.Allocatable["cpu"])
+		memory, _ := parseMemoryBytes(node.Status.Allocatable["memory"])
+		cpuMillis += cpu
+		memoryBytes += memory
+	}
+	return cpuMillis, memoryBytes, nil
+}
+
+// handleCapacity - 현재 공급/수요 조회 API
+func (c *CapacityPublisher) handleCapacity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshot := c.Snapshot()
+	if snapshot == nil || r.URL.Query().Get("refresh") == "true" {
+		snapshot = c.Refresh()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":        "success",
+		"data":          snapshot,
+		"demand_object": c.demandObject,
+	})
+}
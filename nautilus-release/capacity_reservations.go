@@ -0,0 +1,570 @@
+// Capacity Reservations - 테넌트가 온체인에서 구매한 기간 한정 CPU/메모리 예약을 확정하고 스케줄러에서 용량을 보류
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 예약 상태 (pending → confirmed → expired, 또는 rejected/cancelled)
+const (
+	reservationPending   = "pending"
+	reservationConfirmed = "confirmed"
+	reservationRejected  = "rejected"
+	reservationCancelled = "cancelled"
+	reservationExpired   = "expired"
+)
+
+// reservationRetention - 끝난 예약을 조회 목록에 남겨두는 기간
+const reservationRetention = 7 * 24 * time.Hour
+
+// CapacityReservation - 네임스페이스 하나의 예약 (사용량은 활성 예약만 채움)
+type CapacityReservation struct {
+	ID         string    `json:"id"`
+	Tenant     string    `json:"tenant"`
+	Namespace  string    `json:"namespace"`
+	CPUMillis  int64     `json:"cpu_millis"`
+	MemoryMB   int64     `json:"memory_mb"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Lendable   bool      `json:"lendable"`
+	PaidMist   uint64    `json:"paid_mist"`
+	State      string    `json:"state"`
+	Reason     string    `json:"reason,omitempty"`
+	Submitted  bool      `json:"submitted"` // 확정/거절 트랜잭션을 제출했는지 (실패 시 다음 주기에 재시도)
+	UsedCPU    int64     `json:"used_cpu_millis,omitempty"`
+	UsedMemory int64     `json:"used_memory_bytes,omitempty"`
+}
+
+func (r *CapacityReservation) activeAt(now time.Time) bool {
+	return r.State == reservationConfirmed && !now.Before(r.Start) && now.Before(r.End)
+}
+
+/*
+CapacityReservations - capacity_reservations 모듈의 구매 이벤트를 확정/거절하고 활성 예약의 미사용 용량을 보류
+
+  - 구매 이벤트를 받으면 네임스페이스의 k3s-daas.io/tenant 라벨이 구매자와 같은지, 예약 기간에 겹치는
+    확정 예약을 더해도 할당 가능량의 NAUTILUS_RESERVATION_MAX_PERCENT(기본 80) 이하인지 확인
+  - 결정은 RESERVATION_BOOK_ID 장부에 confirm_reservation / reject_reservation으로 제출 (거절은 컨트랙트가 환불)
+  - 활성 예약마다 네임스페이스 Pod 요청량을 NAUTILUS_RESERVATION_REFRESH_SECONDS마다 합산해 미사용량 계산
+  - CapacityReservations 스케줄러 플러그인이 다른 네임스페이스 Pod가 미사용 예약 용량을 쓰지 못하게 막음
+    (lendable 예약은 best-effort Pod - 요청량이 없거나 우선순위가 음수 - 에게 빌려줌, 예약 Pod가 오면 선점으로 회수)
+*/
+type CapacityReservations struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	sui        *SuiIntegration
+	simulator  *ScheduleSimulator
+	bookID     string
+	stateFile  string
+	interval   time.Duration
+	maxPercent int
+
+	mutex        sync.RWMutex
+	reservations map[string]*CapacityReservation
+	filtered     map[string]uint64 // 플러그인 결과별 Pod 수 (held, borrowed)
+}
+
+// NewCapacityReservations - 저장된 예약 복원
+func NewCapacityReservations(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration, simulator *ScheduleSimulator) (*CapacityReservations, error) {
+	c := &CapacityReservations{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		sui:          sui,
+		simulator:    simulator,
+		bookID:       os.Getenv("RESERVATION_BOOK_ID"),
+		stateFile:    statePath("capacity-reservations.json"),
+		interval:     envSeconds("NAUTILUS_RESERVATION_REFRESH_SECONDS", 30),
+		maxPercent:   envCount("NAUTILUS_RESERVATION_MAX_PERCENT", 80),
+		reservations: make(map[string]*CapacityReservation),
+		filtered:     make(map[string]uint64),
+	}
+	if c.maxPercent <= 0 || c.maxPercent > 100 {
+		return nil, fmt.Errorf("NAUTILUS_RESERVATION_MAX_PERCENT must be between 1 and 100")
+	}
+	if _, err := loadJSONState(c.stateFile, &c.reservations); err != nil {
+		logger.Warnf("⚠️ Failed to load capacity reservations: %v", err)
+	}
+	if c.reservations == nil {
+		c.reservations = make(map[string]*CapacityReservation)
+	}
+	return c, nil
+}
+
+// Start - 주기적으로 미제출 결정 재시도, 사용량 갱신, 끝난 예약 정리
+func (c *CapacityReservations) Start(ctx context.Context) {
+	c.logger.Info("📅 Starting Capacity Reservations...")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if c.k3sMgr.IsRunning() {
+			c.reconcile(time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *CapacityReservations) reconcile(now time.Time) {
+	c.mutex.RLock()
+	var undecided []string
+	for id, reservation := range c.reservations {
+		if reservation.State == reservationPending && !reservation.Submitted {
+			undecided = append(undecided, id)
+		}
+	}
+	c.mutex.RUnlock()
+	sort.Strings(undecided)
+	for _, id := range undecided {
+		c.decide(id)
+	}
+
+	usage, err := c.namespaceUsage()
+	if err != nil {
+		c.logger.Warnf("⚠️ Failed to refresh reserved namespace usage: %v", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	changed := false
+	for id, reservation := range c.reservations {
+		switch {
+		case reservation.State == reservationConfirmed && !now.Before(reservation.End):
+			reservation.State = reservationExpired
+			reservation.UsedCPU, reservation.UsedMemory = 0, 0
+			changed = true
+			c.logger.Infof("📅 Reservation %s for %s expired", id, reservation.Namespace)
+		case reservation.State != reservationPending && reservation.State != reservationConfirmed &&
+			now.Sub(reservation.End) > reservationRetention:
+			delete(c.reservations, id)
+			changed = true
+		case reservation.activeAt(now) && usage != nil:
+			used := usage[reservation.Namespace]
+			reservation.UsedCPU, reservation.UsedMemory = used[0], used[1]
+		}
+	}
+	if changed {
+		c.saveLocked()
+	}
+}
+
+// namespaceUsage - 예약 네임스페이스별 배치된 Pod 요청량 합 (cpu, memory)
+func (c *CapacityReservations) namespaceUsage() (map[string][2]int64, error) {
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces",
+		"--field-selector=status.phase!=Succeeded,status.phase!=Failed", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var podList struct {
+		Items []struct {
+			Metadata struct {
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+				simPodSpec
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &podList); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %v", err)
+	}
+	usage := make(map[string][2]int64)
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		cpu, memory := podRequests(pod.Spec.simPodSpec)
+		total := usage[pod.Metadata.Namespace]
+		usage[pod.Metadata.Namespace] = [2]int64{total[0] + cpu, total[1] + memory}
+	}
+	return usage, nil
+}
+
+// decide - 대기 중인 예약 확인 후 확정/거절 제출 (제출 실패 시 pending으로 남겨 재시도)
+func (c *CapacityReservations) decide(id string) {
+	c.mutex.RLock()
+	reservation := c.reservations[id]
+	if reservation == nil || reservation.State != reservationPending {
+		c.mutex.RUnlock()
+		return
+	}
+	candidate := *reservation
+	c.mutex.RUnlock()
+
+	reason, err := c.admit(&candidate)
+	if err != nil {
+		c.logger.Warnf("⚠️ Reservation %s not decided yet: %v", id, err)
+		return
+	}
+	if c.bookID == "" || !c.sui.canSubmit() {
+		c.logger.Warnf("⚠️ Reservation %s not decided on chain (RESERVATION_BOOK_ID or key not configured)", id)
+		return
+	}
+	if reason == "" {
+		err = c.sui.callContract("capacity_reservations", "confirm_reservation", c.bookID, id)
+	} else {
+		err = c.sui.callContract("capacity_reservations", "reject_reservation", c.bookID, id, reason)
+	}
+	if err != nil {
+		c.logger.Errorf("❌ Failed to submit decision for reservation %s: %v", id, err)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if reservation := c.reservations[id]; reservation != nil && reservation.State == reservationPending {
+		reservation.Submitted = true
+		if reason == "" {
+			reservation.State = reservationConfirmed
+			c.logger.Infof("📅 Reservation %s confirmed: %s gets %dm CPU / %dMB memory %s ~ %s", id, reservation.Namespace,
+				reservation.CPUMillis, reservation.MemoryMB, reservation.Start.Format(time.RFC3339), reservation.End.Format(time.RFC3339))
+		} else {
+			reservation.State = reservationRejected
+			reservation.Reason = reason
+			c.logger.Infof("📅 Reservation %s rejected: %s", id, reason)
+		}
+		c.saveLocked()
+	}
+}
+
+// admit - 거절 사유 (확정 가능하면 빈 문자열, 용량을 확인할 수 없으면 오류로 다음 주기에 재시도)
+func (c *CapacityReservations) admit(candidate *CapacityReservation) (string, error) {
+	if !candidate.End.After(time.Now()) {
+		return "reservation window already ended", nil
+	}
+	if !c.k3sMgr.IsRunning() {
+		return "", fmt.Errorf("k3s is not running")
+	}
+	tenant, err := c.k3sMgr.RunKubectl(nil, "get", "namespace", candidate.Namespace,
+		"-o", `jsonpath={.metadata.labels.k3s-daas\.io/tenant}`)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "NotFound"):
+		return fmt.Sprintf("namespace %s not found", candidate.Namespace), nil
+	case err != nil:
+		return "", err
+	}
+	if owner := strings.TrimSpace(string(tenant)); !strings.EqualFold(owner, candidate.Tenant) {
+		return fmt.Sprintf("namespace %s does not belong to %s", candidate.Namespace, candidate.Tenant), nil
+	}
+
+	nodes, _, err := c.simulator.snapshot()
+	if err != nil {
+		return "", fmt.Errorf("node capacity unavailable: %v", err)
+	}
+	var allocCPU, allocMemory int64
+	for _, node := range nodes {
+		if node.blocked == "" {
+			allocCPU += node.allocCPU
+			allocMemory += node.allocMemory
+		}
+	}
+	committedCPU, committedMemory := c.committed(candidate.Start, candidate.End)
+	limitCPU := allocCPU * int64(c.maxPercent) / 100
+	limitMemory := allocMemory * int64(c.maxPercent) / 100
+	switch {
+	case committedCPU+candidate.CPUMillis > limitCPU:
+		return fmt.Sprintf("only %dm CPU reservable in this window", maxInt64(limitCPU-committedCPU, 0)), nil
+	case committedMemory+candidate.MemoryMB<<20 > limitMemory:
+		return fmt.Sprintf("only %dMB memory reservable in this window", maxInt64(limitMemory-committedMemory, 0)>>20), nil
+	}
+	return "", nil
+}
+
+// committed - 기간이 겹치는 확정 예약 합 (cpu millis, memory bytes)
+func (c *CapacityReservations) committed(start, end time.Time) (int64, int64) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var cpu, memory int64
+	for _, reservation := range c.reservations {
+		if reservation.State == reservationConfirmed && reservation.Start.Before(end) && start.Before(reservation.End) {
+			cpu += reservation.CPUMillis
+			memory += reservation.MemoryMB << 20
+		}
+	}
+	return cpu, memory
+}
+
+// HeldFor - 이 Pod가 쓸 수 없는 미사용 예약 용량 (자기 네임스페이스 예약 제외, best-effort면 lendable 예약 제외)
+func (c *CapacityReservations) HeldFor(namespace string, bestEffort bool) (int64, int64, []string) {
+	if c == nil {
+		return 0, 0, nil
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	now := time.Now()
+	var cpu, memory int64
+	var holders []string
+	for _, reservation := range c.reservations {
+		if !reservation.activeAt(now) || reservation.Namespace == namespace || (bestEffort && reservation.Lendable) {
+			continue
+		}
+		unusedCPU := maxInt64(reservation.CPUMillis-reservation.UsedCPU, 0)
+		unusedMemory := maxInt64(reservation.MemoryMB<<20-reservation.UsedMemory, 0)
+		if unusedCPU == 0 && unusedMemory == 0 {
+			continue
+		}
+		cpu += unusedCPU
+		memory += unusedMemory
+		holders = append(holders, reservation.Namespace)
+	}
+	sort.Strings(holders)
+	return cpu, memory, holders
+}
+
+func (c *CapacityReservations) countFilter(outcome string) {
+	c.mutex.Lock()
+	c.filtered[outcome]++
+	c.mutex.Unlock()
+}
+
+// Reservations - ID 순서의 예약 복사본 (namespace/tenant가 비어 있지 않으면 해당 예약만)
+func (c *CapacityReservations) Reservations(namespace, tenant string) []CapacityReservation {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	reservations := make([]CapacityReservation, 0, len(c.reservations))
+	for _, reservation := range c.reservations {
+		if (namespace != "" && reservation.Namespace != namespace) || (tenant != "" && !strings.EqualFold(reservation.Tenant, tenant)) {
+			continue
+		}
+		reservations = append(reservations, *reservation)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		a, _ := strconv.ParseUint(reservations[i].ID, 10, 64)
+		b, _ := strconv.ParseUint(reservations[j].ID, 10, 64)
+		return a < b
+	})
+	return reservations
+}
+
+func (c *CapacityReservations) saveLocked() {
+	if err := saveJSONState(c.stateFile, c.reservations); err != nil {
+		c.logger.Warnf("⚠️ Failed to persist capacity reservations: %v", err)
+	}
+}
+
+// handleChainEvent - capacity_reservations 모듈 이벤트 반영 (구매는 확인 후 결정 제출, 나머지는 상태만 갱신)
+func (c *CapacityReservations) handleChainEvent(event *SuiContractEvent) {
+	if c == nil {
+		return
+	}
+	reservationID, err := eventUint(event.EventData["reservation_id"])
+	if err != nil {
+		c.logger.Warnf("⚠️ Reservation event without reservation_id: %s", event.Type)
+		return
+	}
+	id := strconv.FormatUint(reservationID, 10)
+
+	if strings.Contains(event.Type, "ReservationPurchasedEvent") {
+		c.purchased(id, event)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	reservation := c.reservations[id]
+	if reservation == nil {
+		c.logger.Debugf("Reservation event %s for unknown reservation %s ignored", event.Type, id)
+		return
+	}
+	reservation.Submitted = true
+	switch {
+	case strings.Contains(event.Type, "ReservationConfirmedEvent"):
+		reservation.State = reservationConfirmed
+	case strings.Contains(event.Type, "ReservationRejectedEvent"):
+		reservation.State = reservationRejected
+		reservation.Reason, _ = event.EventData["reason"].(string)
+	case strings.Contains(event.Type, "ReservationCancelledEvent"):
+		reservation.State = reservationCancelled
+	}
+	c.saveLocked()
+}
+
+func (c *CapacityReservations) purchased(id string, event *SuiContractEvent) {
+	cpu, cpuErr := eventUint(event.EventData["cpu_millis"])
+	memory, memoryErr := eventUint(event.EventData["memory_mb"])
+	startMs, startErr := eventUint(event.EventData["start_ms"])
+	endMs, endErr := eventUint(event.EventData["end_ms"])
+	if cpuErr != nil || memoryErr != nil || startErr != nil || endErr != nil {
+		c.logger.Warnf("⚠️ Invalid reservation %s: %v", id, event.EventData)
+		return
+	}
+	paid, _ := eventUint(event.EventData["paid"])
+	tenant, _ := event.EventData["tenant"].(string)
+	namespace, _ := event.EventData["namespace"].(string)
+	lendable, _ := event.EventData["lendable"].(bool)
+
+	c.mutex.Lock()
+	if _, exists := c.reservations[id]; exists {
+		c.mutex.Unlock()
+		return
+	}
+	c.reservations[id] = &CapacityReservation{
+		ID:        id,
+		Tenant:    tenant,
+		Namespace: namespace,
+		CPUMillis: int64(cpu),
+		MemoryMB:  int64(memory),
+		Start:     time.UnixMilli(int64(startMs)),
+		End:       time.UnixMilli(int64(endMs)),
+		Lendable:  lendable,
+		PaidMist:  paid,
+		State:     reservationPending,
+	}
+	c.saveLocked()
+	c.mutex.Unlock()
+
+	c.logger.Infof("📅 Reservation %s purchased by %s for %s: %dm CPU / %dMB memory", id, tenant, namespace, cpu, memory)
+	c.decide(id)
+}
+
+// handleReservations - GET /api/v1/reservations?namespace=&tenant=
+func (c *CapacityReservations) handleReservations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	writeClientJSON(w, c.Reservations(query.Get("namespace"), query.Get("tenant")))
+}
+
+// writeMetrics - 상태별 예약 수, 활성 예약의 예약량/사용량, 플러그인 결과
+func (c *CapacityReservations) writeMetrics(w io.Writer) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	now := time.Now()
+	states := make(map[string]int)
+	reserved := make(map[string][4]int64) // namespace → cpu, memory, used cpu, used memory
+	for _, reservation := range c.reservations {
+		states[reservation.State]++
+		if reservation.activeAt(now) {
+			total := reserved[reservation.Namespace]
+			reserved[reservation.Namespace] = [4]int64{
+				total[0] + reservation.CPUMillis, total[1] + reservation.MemoryMB<<20,
+				total[2] + reservation.UsedCPU, total[3] + reservation.UsedMemory,
+			}
+		}
+	}
+
+	writeMetricHeader(w, "nautilus_capacity_reservations", "gauge", "Capacity reservations by state")
+	for _, state := range []string{reservationPending, reservationConfirmed, reservationRejected, reservationCancelled, reservationExpired} {
+		writeMetric(w, "nautilus_capacity_reservations", map[string]string{"state": state}, float64(states[state]))
+	}
+
+	namespaces := make([]string, 0, len(reserved))
+	for namespace := range reserved {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	writeMetricHeader(w, "nautilus_reserved_cpu_millis", "gauge", "CPU reserved by active reservations")
+	for _, namespace := range namespaces {
+		writeMetric(w, "nautilus_reserved_cpu_millis", map[string]string{"namespace": namespace}, float64(reserved[namespace][0]))
+	}
+	writeMetricHeader(w, "nautilus_reserved_memory_bytes", "gauge", "Memory reserved by active reservations")
+	for _, namespace := range namespaces {
+		writeMetric(w, "nautilus_reserved_memory_bytes", map[string]string{"namespace": namespace}, float64(reserved[namespace][1]))
+	}
+	writeMetricHeader(w, "nautilus_reserved_cpu_used_millis", "gauge", "CPU requested by pods in namespaces with an active reservation")
+	for _, namespace := range namespaces {
+		writeMetric(w, "nautilus_reserved_cpu_used_millis", map[string]string{"namespace": namespace}, float64(reserved[namespace][2]))
+	}
+	writeMetricHeader(w, "nautilus_reserved_memory_used_bytes", "gauge", "Memory requested by pods in namespaces with an active reservation")
+	for _, namespace := range namespaces {
+		writeMetric(w, "nautilus_reserved_memory_used_bytes", map[string]string{"namespace": namespace}, float64(reserved[namespace][3]))
+	}
+
+	writeMetricHeader(w, "nautilus_reservation_filter_total", "counter", "Pods checked against reserved capacity by outcome")
+	for _, outcome := range []string{"held", "borrowed"} {
+		writeMetric(w, "nautilus_reservation_filter_total", map[string]string{"outcome": outcome}, float64(c.filtered[outcome]))
+	}
+}
+
+// ==================== 스케줄러 플러그인 ====================
+
+func init() {
+	RegisterSchedulerPlugin("CapacityReservations", true, func(handle *SchedulerPluginHandle, _ json.RawMessage) (SchedulerPlugin, error) {
+		if handle.Simulator == nil {
+			return nil, fmt.Errorf("node capacity snapshot unavailable")
+		}
+		return &reservationPlugin{handle: handle}, nil
+	})
+}
+
+// reservationPlugin - Pod를 배치해도 클러스터 여유 용량이 다른 네임스페이스의 미사용 예약량 이상 남을 때만 통과
+// (예약은 특정 노드가 아닌 클러스터 전체 용량에 대한 것이라 통과/탈락이 모든 후보 노드에 같음)
+type reservationPlugin struct {
+	handle *SchedulerPluginHandle
+}
+
+func (p *reservationPlugin) Name() string { return "CapacityReservations" }
+
+func (p *reservationPlugin) Filter(state *SchedulingState) (map[string]string, error) {
+	reservations := p.handle.Reservations
+	if reservations == nil {
+		return nil, nil
+	}
+	if state.Pod.Spec.Priority != nil && *state.Pod.Spec.Priority >= systemCriticalPriority {
+		return nil, nil
+	}
+	var pod struct {
+		Spec simPodSpec `json:"spec"`
+	}
+	if err := json.Unmarshal(state.RawPod, &pod); err != nil {
+		return nil, fmt.Errorf("invalid pod: %v", err)
+	}
+	cpu, memory := podRequests(pod.Spec)
+	bestEffort := (cpu == 0 && memory == 0) || (state.Pod.Spec.Priority != nil && *state.Pod.Spec.Priority < 0)
+
+	heldCPU, heldMemory, holders := reservations.HeldFor(state.Pod.Metadata.Namespace, bestEffort)
+	strictCPU, strictMemory, _ := reservations.HeldFor(state.Pod.Metadata.Namespace, false)
+	if strictCPU == 0 && strictMemory == 0 {
+		return nil, nil
+	}
+	nodes, _, err := p.handle.Simulator.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	var freeCPU, freeMemory int64
+	for _, node := range nodes {
+		if node.blocked == "" {
+			freeCPU += maxInt64(node.freeCPU, 0)
+			freeMemory += maxInt64(node.freeMemory, 0)
+		}
+	}
+	fits := func(cpuHeld, memoryHeld int64) bool {
+		return freeCPU-cpu >= cpuHeld && freeMemory-memory >= memoryHeld
+	}
+	if fits(heldCPU, heldMemory) {
+		if !fits(strictCPU, strictMemory) {
+			reservations.countFilter("borrowed")
+		}
+		return nil, nil
+	}
+
+	reservations.countFilter("held")
+	reason := fmt.Sprintf("capacity reserved for namespace(s) %s", strings.Join(holders, ","))
+	rejected := make(map[string]string, len(state.Nodes))
+	for _, node := range state.Nodes {
+		rejected[node.Name] = reason
+	}
+	return rejected, nil
+}
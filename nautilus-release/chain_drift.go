@@ -0,0 +1,277 @@
+// Chain Drift - 주기적 정합성 검사 결과(온체인 레지스트리 vs 워커 풀 vs 클러스터)를 드리프트 보고서로 기록
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+드리프트 보고서
+
+PoolSync.Reconcile이 실행될 때마다 차이를 항목으로 남기고, 안전한 차이만 자동으로 고칩니다.
+  - 자동 수정 (healed): 온체인이 기준인 레지스트리 필드 (누락 워커, 스테이크, 상태, 소유자, 역할, Seal 토큰),
+    체인에 없는 워커 제거, 온체인 값과 다른 노드 라벨(다음 하트비트에서 다시 적용)
+  - 보고만 (reported): 풀에 없거나 슬래싱된 워커에 배정된 Pod(orphaned_assignment),
+    레지스트리에 없는 K8s 노드(unregistered_node) - 삭제/격리는 운영자가 판단
+
+reported 항목 수는 nautilus_chain_drift_unresolved 게이지로 내보내 알림에 사용합니다.
+*/
+const (
+	driftHealed   = "healed"
+	driftReported = "reported"
+
+	controlPlaneLabel = "node-role.kubernetes.io/control-plane"
+	masterRoleLabel   = "node-role.kubernetes.io/master"
+)
+
+// driftKinds - 풀 필드 → 보고서 항목 종류 (메트릭 라벨 순서)
+var driftKinds = []struct{ field, kind string }{
+	{"missing", "missing_worker"},
+	{"extra", "extra_worker"},
+	{"stake_amount", "stale_stake"},
+	{"status", "status"},
+	{"owner", "owner"},
+	{"role", "role"},
+	{"seal_token", "seal_token"},
+	{"", "stale_labels"},
+	{"", "orphaned_assignment"},
+	{"", "unregistered_node"},
+}
+
+func driftKind(field string) string {
+	for _, kind := range driftKinds {
+		if kind.field == field {
+			return kind.kind
+		}
+	}
+	return field
+}
+
+// DriftEntry - 차이 하나
+type DriftEntry struct {
+	Kind   string `json:"kind"`
+	NodeID string `json:"node_id"`
+	Object string `json:"object,omitempty"` // orphaned_assignment의 namespace/pod
+	Local  string `json:"local,omitempty"`
+	Chain  string `json:"chain,omitempty"`
+	Action string `json:"action"` // healed | reported
+}
+
+// DriftReport - 한 번의 정합성 검사 결과
+type DriftReport struct {
+	GeneratedAt    time.Time    `json:"generated_at"`
+	OnChainWorkers int          `json:"onchain_workers"`
+	PoolWorkers    int          `json:"pool_workers"`
+	ClusterChecked bool         `json:"cluster_checked"`
+	ClusterError   string       `json:"cluster_error,omitempty"`
+	Healed         int          `json:"healed"`
+	Reported       int          `json:"reported"`
+	Entries        []DriftEntry `json:"entries"`
+}
+
+func (r *DriftReport) add(entry DriftEntry) {
+	r.Entries = append(r.Entries, entry)
+	if entry.Action == driftHealed {
+		r.Healed++
+	} else {
+		r.Reported++
+	}
+}
+
+// countByKind - 종류별 항목 수
+func (r *DriftReport) countByKind() map[string]int {
+	counts := make(map[string]int)
+	for _, entry := range r.Entries {
+		counts[entry.Kind]++
+	}
+	return counts
+}
+
+// clusterNode - kubectl get nodes 중 필요한 필드
+type clusterNode struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+}
+
+func (n clusterNode) controlPlane() bool {
+	_, controlPlane := n.Metadata.Labels[controlPlaneLabel]
+	_, master := n.Metadata.Labels[masterRoleLabel]
+	return controlPlane || master
+}
+
+/*
+checkCluster - K8s 노드/Pod과 풀 비교
+노드 라벨 차이는 워커 라벨을 다시 적용하도록 표시하고, 배정 이상은 보고만 합니다.
+*/
+func (p *PoolSync) checkCluster(report *DriftReport) error {
+	k3sMgr := p.sui.k3sMgr
+	if k3sMgr == nil || !k3sMgr.IsRunning() {
+		return fmt.Errorf("k3s is not running")
+	}
+
+	output, err := k3sMgr.RunKubectl(nil, "get", "nodes", "-o", "json")
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+	var nodes struct {
+		Items []clusterNode `json:"items"`
+	}
+	if err := json.Unmarshal(output, &nodes); err != nil {
+		return fmt.Errorf("failed to parse nodes: %v", err)
+	}
+
+	controlPlane := make(map[string]bool)
+	for _, node := range nodes.Items {
+		name := node.Metadata.Name
+		if node.controlPlane() {
+			controlPlane[name] = true
+			continue
+		}
+		worker, ok := p.workerPool.GetWorker(name)
+		if !ok {
+			report.add(DriftEntry{Kind: "unregistered_node", NodeID: name, Action: driftReported})
+			continue
+		}
+
+		expected := map[string]string{nodeRoleLabel: worker.Role, stakeTierLabel: stakeTierFor(worker.StakeAmount)}
+		for label, want := range expected {
+			if got := node.Metadata.Labels[label]; want != "" && got != want {
+				p.workerPool.MarkLabelsStale(name)
+				report.add(DriftEntry{Kind: "stale_labels", NodeID: name, Local: label + "=" + got, Chain: label + "=" + want, Action: driftHealed})
+			}
+		}
+	}
+
+	output, err = k3sMgr.RunKubectl(nil, "get", "pods", "-A", "-o", "json",
+		"--field-selector=status.phase!=Succeeded,status.phase!=Failed")
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	var pods struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return fmt.Errorf("failed to parse pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" || controlPlane[nodeName] {
+			continue
+		}
+		worker, ok := p.workerPool.GetWorker(nodeName)
+		if ok && worker.Status != "slashed" {
+			continue
+		}
+		local := "not in pool"
+		if ok {
+			local = worker.Status
+		}
+		report.add(DriftEntry{
+			Kind: "orphaned_assignment", NodeID: nodeName, Object: pod.Metadata.Namespace + "/" + pod.Metadata.Name,
+			Local: local, Action: driftReported,
+		})
+	}
+	return nil
+}
+
+// recordReport - 보고서 저장 및 요약 로그 (p.mutex 보유 상태에서 호출)
+func (p *PoolSync) recordReport(report *DriftReport) {
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Action != report.Entries[j].Action {
+			return report.Entries[i].Action == driftReported
+		}
+		return report.Entries[i].NodeID < report.Entries[j].NodeID
+	})
+	p.lastReport = report
+
+	if len(report.Entries) == 0 {
+		return
+	}
+	var kinds []string
+	for kind, count := range report.countByKind() {
+		kinds = append(kinds, fmt.Sprintf("%s=%d", kind, count))
+	}
+	sort.Strings(kinds)
+	p.logger.Warnf("🧭 Chain drift: %d healed, %d need attention (%s)", report.Healed, report.Reported, strings.Join(kinds, ", "))
+}
+
+// Report - 마지막 드리프트 보고서 (아직 검사 전이면 nil)
+func (p *PoolSync) Report() *DriftReport {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.lastReport
+}
+
+/*
+handleDrift - 드리프트 보고서 (/api/v1/admin/chain-drift, 관리자 토큰 필요)
+
+	GET    마지막 보고서 (검사 전이면 data는 null)
+	POST   즉시 정합성 검사 후 새 보고서
+*/
+func (p *PoolSync) handleDrift(w http.ResponseWriter, r *http.Request) {
+	if p.adminToken == "" {
+		http.Error(w, "Chain drift API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(p.adminToken)) != 1 {
+		p.logger.Warnf("🚫 Unauthorized chain drift access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !p.sui.onSui() {
+			http.Error(w, "Chain reconciliation requires the Sui backend", http.StatusConflict)
+			return
+		}
+		if err := p.Reconcile(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, p.Report())
+}
+
+// writeDriftMetrics - 마지막 보고서의 종류별 항목 수와 미해결 수 (알림용)
+func (p *PoolSync) writeDriftMetrics(w io.Writer) {
+	report := p.Report()
+	if report == nil {
+		return
+	}
+	counts := report.countByKind()
+	writeMetricHeader(w, "nautilus_chain_drift", "gauge", "Differences found by the last chain reconciliation, by kind")
+	for _, kind := range driftKinds {
+		writeMetric(w, "nautilus_chain_drift", map[string]string{"kind": kind.kind}, float64(counts[kind.kind]))
+	}
+	writeMetricHeader(w, "nautilus_chain_drift_unresolved", "gauge", "Differences the last reconciliation reported but did not heal")
+	writeMetric(w, "nautilus_chain_drift_unresolved", nil, float64(report.Reported))
+	cluster := 0.0
+	if report.ClusterChecked {
+		cluster = 1
+	}
+	writeMetricHeader(w, "nautilus_chain_drift_cluster_checked", "gauge", "Whether the last reconciliation could compare pods and nodes")
+	writeMetric(w, "nautilus_chain_drift_cluster_checked", nil, cluster)
+}
@@ -0,0 +1,205 @@
+// Chain Health - Sui RPC 지연, 체크포인트 지연, 이벤트 처리 지연, 가스 가격 측정 (테넌트 공개)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chainHealthSamples - 이벤트 처리 지연 백분위에 쓰는 최근 요청 이벤트 수
+const chainHealthSamples = 256
+
+/*
+ChainHealthReport - 테넌트가 보는 체인 상태
+
+kubectl 쓰기는 컨트랙트 이벤트로 마스터에 도달하므로 응답 시간의 대부분이 체인 쪽에서 생길 수 있습니다.
+  - rpc_latency_ms: 최신 체크포인트 조회 왕복 시간
+  - checkpoint_lag: 최신 체크포인트와 이벤트 조회가 따라잡은 체크포인트의 차이
+  - event_delay_*: 요청 이벤트의 체크포인트 시각부터 마스터가 받기까지 (최근 256건)
+  - reference_gas_price: 현재 기준 가스 가격 (MIST)
+*/
+type ChainHealthReport struct {
+	Healthy           bool      `json:"healthy"`
+	RPCLatencyMs      int64     `json:"rpc_latency_ms"`
+	LatestCheckpoint  uint64    `json:"latest_checkpoint"`
+	EventCursor       uint64    `json:"event_cursor"`
+	CheckpointLag     uint64    `json:"checkpoint_lag"`
+	EventDelayP50Ms   int64     `json:"event_delay_p50_ms"`
+	EventDelayP95Ms   int64     `json:"event_delay_p95_ms"`
+	EventDelaySamples int       `json:"event_delay_samples"`
+	ReferenceGasPrice uint64    `json:"reference_gas_price"`
+	Error             string    `json:"error,omitempty"`
+	SampledAt         time.Time `json:"sampled_at"`
+}
+
+// ChainHealth - 주기적 체인 상태 측정
+type ChainHealth struct {
+	logger   *logrus.Logger
+	sui      *SuiIntegration
+	interval time.Duration
+	maxLag   uint64
+
+	mutex    sync.RWMutex
+	report   ChainHealthReport
+	delays   []time.Duration // 링 버퍼
+	next     int
+	failures uint64
+}
+
+// NewChainHealth - 새 Chain Health 생성 (CHAIN_HEALTH_INTERVAL_SECONDS, READINESS_MAX_CHECKPOINT_LAG와 같은 지연 한도)
+func NewChainHealth(logger *logrus.Logger, sui *SuiIntegration) *ChainHealth {
+	maxLag, err := strconv.ParseUint(getEnvOrDefault("READINESS_MAX_CHECKPOINT_LAG", "100"), 10, 64)
+	if err != nil {
+		maxLag = 100
+	}
+	return &ChainHealth{
+		logger:   logger,
+		sui:      sui,
+		interval: envSeconds("CHAIN_HEALTH_INTERVAL_SECONDS", 15),
+		maxLag:   maxLag,
+		delays:   make([]time.Duration, 0, chainHealthSamples),
+	}
+}
+
+// Start - 주기적 측정
+func (c *ChainHealth) Start(ctx context.Context) {
+	c.logger.Info("⛓️ Starting Chain Health sampler...")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.sample()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample - RPC 왕복 시간과 체크포인트 지연, 가스 가격 측정 (실패해도 이벤트 지연 통계는 유지)
+func (c *ChainHealth) sample() {
+	report := ChainHealthReport{SampledAt: time.Now()}
+
+	started := time.Now()
+	latest, err := c.sui.latestCheckpoint()
+	report.RPCLatencyMs = time.Since(started).Milliseconds()
+	if err == nil {
+		report.LatestCheckpoint = latest
+		report.EventCursor = c.sui.EventCursor()
+		if latest > report.EventCursor {
+			report.CheckpointLag = latest - report.EventCursor
+		}
+		var gasPrice string
+		if err = c.sui.rpcCall("suix_getReferenceGasPrice", []interface{}{}, &gasPrice); err == nil {
+			report.ReferenceGasPrice, err = strconv.ParseUint(gasPrice, 10, 64)
+		}
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	// 이벤트 조회를 한 번도 성공하지 못했으면 커서가 0이라 지연을 판단할 수 없음
+	report.Healthy = err == nil && report.EventCursor > 0 && report.CheckpointLag <= c.maxLag
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err != nil {
+		c.failures++
+		c.logger.Debugf("⛓️ Chain health sample failed: %v", err)
+	}
+	report.EventDelayP50Ms, report.EventDelayP95Ms = c.delayPercentiles()
+	report.EventDelaySamples = len(c.delays)
+	c.report = report
+}
+
+// ObserveEventDelay - 요청 이벤트가 체크포인트에 포함된 시각부터 마스터가 받기까지 (handleK8sAPIRequest에서 기록)
+func (c *ChainHealth) ObserveEventDelay(timestampMs int64, receivedAt time.Time) {
+	if timestampMs <= 0 {
+		return
+	}
+	delay := receivedAt.Sub(time.UnixMilli(timestampMs))
+	if delay < 0 {
+		delay = 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.delays) < chainHealthSamples {
+		c.delays = append(c.delays, delay)
+	} else {
+		c.delays[c.next] = delay
+	}
+	c.next = (c.next + 1) % chainHealthSamples
+}
+
+// delayPercentiles - 최근 이벤트 처리 지연의 50/95 백분위 (호출자가 mutex 보유)
+func (c *ChainHealth) delayPercentiles() (p50, p95 int64) {
+	if len(c.delays) == 0 {
+		return 0, 0
+	}
+	sorted := append([]time.Duration(nil), c.delays...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(q float64) int64 {
+		return sorted[int(q*float64(len(sorted)-1))].Milliseconds()
+	}
+	return at(0.50), at(0.95)
+}
+
+// Report - 마지막 측정 결과 (이벤트 지연은 측정 이후 기록분까지 반영)
+func (c *ChainHealth) Report() ChainHealthReport {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	report := c.report
+	report.EventDelayP50Ms, report.EventDelayP95Ms = c.delayPercentiles()
+	report.EventDelaySamples = len(c.delays)
+	return report
+}
+
+// handleHealth - GET /api/v1/chain/health (tenantAuth로 인증한 테넌트, 내용은 테넌트와 무관)
+func (c *ChainHealth) handleHealth(w http.ResponseWriter, r *http.Request, _ string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   c.Report(),
+	})
+}
+
+// writeMetrics - 체인 상태 지표
+func (c *ChainHealth) writeMetrics(w io.Writer) {
+	report := c.Report()
+	c.mutex.RLock()
+	failures := c.failures
+	c.mutex.RUnlock()
+
+	healthy := 0.0
+	if report.Healthy {
+		healthy = 1
+	}
+	writeMetricHeader(w, "nautilus_chain_healthy", "gauge", "Whether the chain RPC answers and event polling is within the checkpoint lag limit")
+	writeMetric(w, "nautilus_chain_healthy", nil, healthy)
+	writeMetricHeader(w, "nautilus_chain_rpc_latency_seconds", "gauge", "Round trip of the last latest-checkpoint RPC call")
+	writeMetric(w, "nautilus_chain_rpc_latency_seconds", nil, float64(report.RPCLatencyMs)/1000)
+	writeMetricHeader(w, "nautilus_chain_checkpoint_lag", "gauge", "Checkpoints between the chain head and the event polling cursor")
+	writeMetric(w, "nautilus_chain_checkpoint_lag", nil, float64(report.CheckpointLag))
+	writeMetricHeader(w, "nautilus_chain_event_delay_seconds", "gauge", "Delay from a request event's checkpoint to its receipt by the master over recent events")
+	writeMetric(w, "nautilus_chain_event_delay_seconds", map[string]string{"quantile": "0.5"}, float64(report.EventDelayP50Ms)/1000)
+	writeMetric(w, "nautilus_chain_event_delay_seconds", map[string]string{"quantile": "0.95"}, float64(report.EventDelayP95Ms)/1000)
+	writeMetricHeader(w, "nautilus_chain_reference_gas_price", "gauge", "Reference gas price in MIST")
+	writeMetric(w, "nautilus_chain_reference_gas_price", nil, float64(report.ReferenceGasPrice))
+	writeMetricHeader(w, "nautilus_chain_health_sample_failures_total", "counter", "Chain health samples that failed to reach the RPC")
+	writeMetric(w, "nautilus_chain_health_sample_failures_total", nil, float64(failures))
+}
@@ -0,0 +1,473 @@
+// Chain Outbox - 감사 로그 앵커와 테넌트 사용량 기록을 디스크에 순번과 함께 보관했다가 체인이 돌아오면 순서대로 제출
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+체인에 닿지 않는 동안 만들어진 기록을 잃지 않도록 audit_ledger::AuditLedger(AUDIT_LEDGER_ID)에 쓰기 전에
+상태 파일(chain-outbox.json)에 먼저 저장합니다.
+
+  - 감사 로그: 배치마다 이벤트 해시를 이전 루트에 이어 붙인 해시 체인을 만들고,
+    NAUTILUS_AUDIT_ANCHOR_INTERVAL_SECONDS(기본 300)마다 구간 루트 하나를 anchor_audit_batch로 기록
+  - 사용량: NAUTILUS_USAGE_RECORD_INTERVAL_SECONDS(기본 3600)마다 테넌트별 증가분(요청 수, Pod 송수신 바이트)을 record_usage로 기록
+  - 기록마다 순번을 붙여 맨 앞부터 하나씩 제출하고, 실패하면 멈췄다가 다음 주기에 같은 순번부터 다시 시도
+    (재시도 전 원장의 next_sequence를 읽어 이미 기록된 순번은 건너뜀)
+  - 대기 기록이 NAUTILUS_CHAIN_OUTBOX_MAX(기본 10000)에 이르면 새 기록을 만들지 않고 원본에 누적
+    (감사 구간은 다음 앵커에 합쳐지고, 사용량은 테넌트 카운터에 남음)
+  - 가장 오래된 대기 기록이 NAUTILUS_CHAIN_OUTAGE_ALERT_SECONDS(기본 900)보다 오래되면 오류 로그/메트릭으로 알리고
+    NAUTILUS_CHAIN_OUTAGE_ALERT_WEBHOOK이 있으면 웹훅 전송 (밀린 기록을 모두 제출하면 해제)
+*/
+
+const chainOutboxStatusLimit = 100 // 상태 API에 보여주는 대기 기록 수
+
+// ChainRecord - 체인 제출 대기 기록 (Args는 원장과 순번 뒤에 오는 인자)
+type ChainRecord struct {
+	Sequence  uint64    `json:"sequence"`
+	Function  string    `json:"function"`
+	Args      []string  `json:"args"`
+	CreatedAt time.Time `json:"created_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// pendingAuditAnchor - 아직 앵커로 봉인하지 않은 감사 구간
+type pendingAuditAnchor struct {
+	Root    string `json:"root"`
+	Count   uint64 `json:"count"`
+	FirstMs int64  `json:"first_ms"`
+	LastMs  int64  `json:"last_ms"`
+}
+
+// chainOutboxState - 상태 파일 내용
+type chainOutboxState struct {
+	NextSequence uint64              `json:"next_sequence"`
+	AuditRoot    string              `json:"audit_root"` // 마지막으로 봉인한 앵커 루트
+	PendingAudit *pendingAuditAnchor `json:"pending_audit,omitempty"`
+	UsageSince   time.Time           `json:"usage_since"`
+	Records      []ChainRecord       `json:"records"`
+}
+
+// ChainOutboxStatus - 상태 API 응답
+type ChainOutboxStatus struct {
+	Enabled      bool          `json:"enabled"`
+	LedgerID     string        `json:"ledger_id"`
+	NextSequence uint64        `json:"next_sequence"`
+	Backlog      int           `json:"backlog"`
+	OldestAge    float64       `json:"oldest_age_seconds"`
+	AlertAfter   float64       `json:"alert_after_seconds"`
+	Alerting     bool          `json:"alerting"`
+	LastError    string        `json:"last_error,omitempty"`
+	LastFlushed  time.Time     `json:"last_flushed"`
+	Pending      []ChainRecord `json:"pending"`
+}
+
+// ChainOutbox - 순번이 붙은 디스크 버퍼와 순차 제출기
+type ChainOutbox struct {
+	logger         *logrus.Logger
+	sui            *SuiIntegration
+	quota          *TenantThrottler
+	ledgerID       string
+	adminToken     string
+	stateFile      string
+	maxRecords     int
+	flushInterval  time.Duration
+	anchorInterval time.Duration
+	usageInterval  time.Duration
+	alertAfter     time.Duration
+	alertWebhook   string
+	client         *http.Client
+
+	mutex       sync.Mutex
+	state       chainOutboxState
+	synced      bool // 마지막 실패 이후 원장 순번을 다시 맞췄는지
+	alerting    bool
+	lastError   string
+	lastFlushed time.Time
+	flushed     map[string]uint64 // function → 제출 성공 수
+	failures    uint64
+}
+
+// NewChainOutbox - 새 Chain Outbox 생성 (저장된 대기 기록 복원, AUDIT_LEDGER_ID가 없으면 비활성)
+func NewChainOutbox(logger *logrus.Logger, sui *SuiIntegration) *ChainOutbox {
+	o := &ChainOutbox{
+		logger:         logger,
+		sui:            sui,
+		ledgerID:       os.Getenv("AUDIT_LEDGER_ID"),
+		adminToken:     os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		stateFile:      statePath("chain-outbox.json"),
+		maxRecords:     envCount("NAUTILUS_CHAIN_OUTBOX_MAX", 10000),
+		flushInterval:  envSeconds("NAUTILUS_CHAIN_OUTBOX_FLUSH_SECONDS", 10),
+		anchorInterval: envSeconds("NAUTILUS_AUDIT_ANCHOR_INTERVAL_SECONDS", 300),
+		usageInterval:  envSeconds("NAUTILUS_USAGE_RECORD_INTERVAL_SECONDS", 3600),
+		alertAfter:     envSeconds("NAUTILUS_CHAIN_OUTAGE_ALERT_SECONDS", 900),
+		alertWebhook:   os.Getenv("NAUTILUS_CHAIN_OUTAGE_ALERT_WEBHOOK"),
+		client:         &http.Client{Timeout: 10 * time.Second},
+		flushed:        make(map[string]uint64),
+	}
+	if ok, err := loadJSONState(o.stateFile, &o.state); err != nil {
+		logger.Warnf("⚠️ Failed to load chain outbox: %v", err)
+	} else if ok && len(o.state.Records) > 0 {
+		logger.Warnf("📦 Loaded %d chain records waiting for submission (from sequence %d)", len(o.state.Records), o.state.Records[0].Sequence)
+	}
+	if o.state.UsageSince.IsZero() {
+		o.state.UsageSince = time.Now()
+	}
+	return o
+}
+
+func (o *ChainOutbox) enabled() bool {
+	return o != nil && o.ledgerID != ""
+}
+
+func (o *ChainOutbox) saveLocked() {
+	if err := saveJSONState(o.stateFile, o.state); err != nil {
+		o.logger.Errorf("❌ Failed to persist chain outbox: %v", err)
+	}
+}
+
+// enqueueLocked - 다음 순번으로 기록 추가 (호출자가 mutex 보유, 저장은 호출자가)
+func (o *ChainOutbox) enqueueLocked(function string, args ...string) {
+	o.state.Records = append(o.state.Records, ChainRecord{
+		Sequence:  o.state.NextSequence,
+		Function:  function,
+		Args:      args,
+		CreatedAt: time.Now(),
+	})
+	o.state.NextSequence++
+}
+
+// AddAuditBatch - 싱크에 쓴 감사 배치를 현재 구간 해시 체인에 추가 (비활성이면 무시)
+func (o *ChainOutbox) AddAuditBatch(events []*AuditEvent) {
+	if !o.enabled() || len(events) == 0 {
+		return
+	}
+	digest := sha256.New()
+	for _, event := range events {
+		line, _ := json.Marshal(event)
+		digest.Write(append(line, '\n'))
+	}
+	first, last := events[0].StageTimestamp, events[len(events)-1].StageTimestamp
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	pending := o.state.PendingAudit
+	if pending == nil {
+		pending = &pendingAuditAnchor{Root: o.state.AuditRoot, FirstMs: first.UnixMilli()}
+		o.state.PendingAudit = pending
+	}
+	root, _ := hex.DecodeString(pending.Root)
+	chained := sha256.Sum256(append(root, digest.Sum(nil)...))
+	pending.Root = hex.EncodeToString(chained[:])
+	pending.Count += uint64(len(events))
+	pending.LastMs = last.UnixMilli()
+	o.saveLocked()
+}
+
+// sealAuditAnchor - 누적된 감사 구간을 앵커 기록으로 봉인 (버퍼가 가득 차면 다음 주기로 미룸)
+func (o *ChainOutbox) sealAuditAnchor() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	pending := o.state.PendingAudit
+	if pending == nil || len(o.state.Records) >= o.maxRecords {
+		return
+	}
+	o.enqueueLocked("anchor_audit_batch", pending.Root, strconv.FormatUint(pending.Count, 10),
+		strconv.FormatInt(pending.FirstMs, 10), strconv.FormatInt(pending.LastMs, 10))
+	o.state.AuditRoot, o.state.PendingAudit = pending.Root, nil
+	o.saveLocked()
+}
+
+// recordUsage - 테넌트별 사용량 증가분을 기록 (버퍼가 가득 차면 카운터에 남겨 다음 주기에 합산)
+func (o *ChainOutbox) recordUsage() {
+	if o.quota == nil {
+		return
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.state.Records) >= o.maxRecords {
+		o.logger.Warnf("⚠️ Chain outbox full (%d records), usage stays accumulated until the backlog drains", len(o.state.Records))
+		return
+	}
+	now := time.Now()
+	start, end := strconv.FormatInt(o.state.UsageSince.UnixMilli(), 10), strconv.FormatInt(now.UnixMilli(), 10)
+	for _, delta := range o.quota.UsageDeltas() {
+		o.enqueueLocked("record_usage", delta.Tenant, start, end,
+			strconv.FormatUint(delta.Allowed, 10), strconv.FormatUint(delta.Throttled, 10),
+			strconv.FormatUint(delta.Egress, 10), strconv.FormatUint(delta.Ingress, 10))
+	}
+	o.state.UsageSince = now
+	o.saveLocked()
+}
+
+// syncCursor - 원장의 next_sequence에 맞춰 이미 기록된 순번은 버리고 남은 기록의 순번을 다시 붙임
+func (o *ChainOutbox) syncCursor() error {
+	var object struct {
+		Data struct {
+			Content struct {
+				Fields struct {
+					NextSequence string `json:"next_sequence"`
+				} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	options := map[string]bool{"showContent": true}
+	if err := o.sui.rpcCall("sui_getObject", []interface{}{o.ledgerID, options}, &object); err != nil {
+		return err
+	}
+	next, err := strconv.ParseUint(object.Data.Content.Fields.NextSequence, 10, 64)
+	if err != nil {
+		return fmt.Errorf("audit ledger %s has no next_sequence", o.ledgerID)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	recorded := 0
+	for recorded < len(o.state.Records) && o.state.Records[recorded].Sequence < next {
+		o.flushed[o.state.Records[recorded].Function]++
+		recorded++
+	}
+	o.state.Records = o.state.Records[recorded:]
+	if recorded > 0 {
+		o.logger.Infof("📦 %d chain records were already on the ledger, skipped", recorded)
+	}
+	// 상태 파일을 잃었거나 다른 마스터가 원장을 썼다면 남은 기록을 원장 순번에 이어 붙임
+	if len(o.state.Records) > 0 && o.state.Records[0].Sequence != next {
+		o.logger.Warnf("⚠️ Chain outbox sequence %d does not follow ledger sequence %d, renumbering", o.state.Records[0].Sequence, next)
+	}
+	for i := range o.state.Records {
+		o.state.Records[i].Sequence = next + uint64(i)
+	}
+	o.state.NextSequence = next + uint64(len(o.state.Records))
+	o.synced = true
+	o.saveLocked()
+	return nil
+}
+
+// Flush - 대기 기록을 순번대로 제출 (실패하면 그 순번에서 멈춤)
+func (o *ChainOutbox) Flush() {
+	if !o.enabled() {
+		return
+	}
+	o.mutex.Lock()
+	synced := o.synced
+	o.mutex.Unlock()
+	if !synced {
+		if err := o.syncCursor(); err != nil {
+			o.logger.Debugf("📦 Could not read audit ledger sequence: %v", err)
+		}
+	}
+
+	for {
+		o.mutex.Lock()
+		if len(o.state.Records) == 0 {
+			o.checkAlertLocked()
+			o.mutex.Unlock()
+			return
+		}
+		record := o.state.Records[0]
+		o.mutex.Unlock()
+
+		args := append([]string{o.ledgerID, strconv.FormatUint(record.Sequence, 10)}, record.Args...)
+		err := o.sui.callContract("audit_ledger", record.Function, args...)
+
+		o.mutex.Lock()
+		if err != nil {
+			head := &o.state.Records[0]
+			head.Attempts++
+			head.LastError = err.Error()
+			o.lastError = err.Error()
+			o.failures++
+			o.synced = false
+			o.saveLocked()
+			o.checkAlertLocked()
+			backlog := len(o.state.Records)
+			o.mutex.Unlock()
+			o.logger.Warnf("⚠️ Chain unreachable, %d records buffered (sequence %d): %v", backlog, record.Sequence, err)
+			return
+		}
+		o.state.Records = o.state.Records[1:]
+		o.flushed[record.Function]++
+		o.lastError = ""
+		o.lastFlushed = time.Now()
+		o.saveLocked()
+		o.mutex.Unlock()
+		o.logger.Debugf("📦 Chain record %d (%s) submitted", record.Sequence, record.Function)
+	}
+}
+
+// oldestAgeLocked - 가장 오래 기다린 기록의 대기 시간
+func (o *ChainOutbox) oldestAgeLocked() time.Duration {
+	if len(o.state.Records) == 0 {
+		return 0
+	}
+	return time.Since(o.state.Records[0].CreatedAt)
+}
+
+// checkAlertLocked - 오래된 대기 기록이 임계값을 넘으면 한 번 알림, 모두 제출되면 해제
+func (o *ChainOutbox) checkAlertLocked() {
+	age := o.oldestAgeLocked()
+	if age < o.alertAfter {
+		if o.alerting && len(o.state.Records) == 0 {
+			o.logger.Infof("✅ Chain outbox drained, outage alert cleared")
+		}
+		o.alerting = o.alerting && len(o.state.Records) > 0
+		return
+	}
+	if o.alerting {
+		return
+	}
+	o.alerting = true
+	o.logger.Errorf("🚨 Chain writes failing for %s: %d audit/usage records buffered on disk (alert after %s)",
+		age.Round(time.Second), len(o.state.Records), o.alertAfter)
+
+	if o.alertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"alert":          "nautilus_chain_outage",
+		"backlog":        len(o.state.Records),
+		"oldest_seconds": int(age.Seconds()),
+		"last_error":     o.lastError,
+		"at":             time.Now().UTC().Format(time.RFC3339),
+	})
+	go func() {
+		resp, err := o.client.Post(o.alertWebhook, "application/json", bytes.NewReader(body))
+		if err != nil {
+			o.logger.Warnf("⚠️ Chain outage alert webhook failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			o.logger.Warnf("⚠️ Chain outage alert webhook returned HTTP %d", resp.StatusCode)
+		}
+	}()
+}
+
+// Start - 앵커 봉인, 사용량 기록, 순차 제출 루프 (종료 시 남은 감사 구간을 봉인해 디스크에 남김)
+func (o *ChainOutbox) Start(ctx context.Context) {
+	if !o.enabled() {
+		o.logger.Info("📦 Chain outbox disabled (AUDIT_LEDGER_ID not set)")
+		return
+	}
+	o.logger.Infof("📦 Starting Chain Outbox (ledger %s, anchor every %s, usage every %s)", o.ledgerID, o.anchorInterval, o.usageInterval)
+
+	flush := time.NewTicker(o.flushInterval)
+	defer flush.Stop()
+	anchor := time.NewTicker(o.anchorInterval)
+	defer anchor.Stop()
+	usage := time.NewTicker(o.usageInterval)
+	defer usage.Stop()
+
+	o.Flush()
+	for {
+		select {
+		case <-ctx.Done():
+			o.sealAuditAnchor()
+			o.recordUsage()
+			return
+		case <-anchor.C:
+			o.sealAuditAnchor()
+		case <-usage.C:
+			o.recordUsage()
+		case <-flush.C:
+			o.Flush()
+		}
+	}
+}
+
+// Status - 대기 기록 수, 가장 오래된 기록, 알림 상태
+func (o *ChainOutbox) Status() ChainOutboxStatus {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	pending := o.state.Records
+	if len(pending) > chainOutboxStatusLimit {
+		pending = pending[:chainOutboxStatusLimit]
+	}
+	return ChainOutboxStatus{
+		Enabled:      o.enabled(),
+		LedgerID:     o.ledgerID,
+		NextSequence: o.state.NextSequence,
+		Backlog:      len(o.state.Records),
+		OldestAge:    o.oldestAgeLocked().Seconds(),
+		AlertAfter:   o.alertAfter.Seconds(),
+		Alerting:     o.alerting,
+		LastError:    o.lastError,
+		LastFlushed:  o.lastFlushed,
+		Pending:      append([]ChainRecord{}, pending...),
+	}
+}
+
+// handleOutbox - 체인 기록 버퍼 상태 (/api/v1/admin/chain-outbox, 관리자 토큰 필요)
+func (o *ChainOutbox) handleOutbox(w http.ResponseWriter, r *http.Request) {
+	if o.adminToken == "" {
+		http.Error(w, "Chain outbox API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(o.adminToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, o.Status())
+}
+
+// writeMetrics - 버퍼 깊이, 가장 오래된 기록 대기 시간, 장애 알림, 제출 결과
+func (o *ChainOutbox) writeMetrics(w io.Writer) {
+	o.mutex.Lock()
+	backlog := make(map[string]int)
+	for _, record := range o.state.Records {
+		backlog[record.Function]++
+	}
+	oldest, alerting, failures := o.oldestAgeLocked().Seconds(), o.alerting, o.failures
+	flushed := make(map[string]uint64, len(o.flushed))
+	for function, count := range o.flushed {
+		flushed[function] = count
+	}
+	o.mutex.Unlock()
+
+	alert := 0.0
+	if alerting {
+		alert = 1
+	}
+	functions := []string{"anchor_audit_batch", "record_usage"}
+	writeMetricHeader(w, "nautilus_chain_outbox_backlog", "gauge", "Audit anchors and usage records buffered on disk waiting for the chain")
+	for _, function := range functions {
+		writeMetric(w, "nautilus_chain_outbox_backlog", map[string]string{"function": function}, float64(backlog[function]))
+	}
+	writeMetricHeader(w, "nautilus_chain_outbox_oldest_seconds", "gauge", "Age of the oldest buffered chain record")
+	writeMetric(w, "nautilus_chain_outbox_oldest_seconds", nil, oldest)
+	writeMetricHeader(w, "nautilus_chain_outage_alert", "gauge", "Whether buffered chain records are older than the outage alert threshold")
+	writeMetric(w, "nautilus_chain_outage_alert", nil, alert)
+	writeMetricHeader(w, "nautilus_chain_outbox_flushed_total", "counter", "Buffered chain records submitted in order")
+	for _, function := range functions {
+		writeMetric(w, "nautilus_chain_outbox_flushed_total", map[string]string{"function": function}, float64(flushed[function]))
+	}
+	writeMetricHeader(w, "nautilus_chain_outbox_failures_total", "counter", "Failed chain record submissions (retried from the same sequence)")
+	writeMetric(w, "nautilus_chain_outbox_failures_total", nil, float64(failures))
+}
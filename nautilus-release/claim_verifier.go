@@ -0,0 +1,351 @@
+// Claim Verifier - 워커 하트비트 자가 보고를 마스터 배정 기록과 교차 검증하고 감사 프로브 발급
+//
+// 워커가 보고한 running_pods를 K8s에 기록된 해당 노드의 Running Pod 수와 비교하고,
+// 가끔 하트비트 응답에 임의 Pod 상태 질의(프로브)를 실어 보냅니다. 워커는 다음 하트비트에
+// 결과를 담아 답하며, 불일치가 지속되는 노드는 슬래싱 검토 대상으로 표시됩니다.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	claimWindowSize        = 20               // 노드별 최근 검증 결과 보관 수
+	claimMinSamples        = 10               // 판정에 필요한 최소 검증 횟수
+	claimFlagRatio         = 0.6              // 검토 대상 표시 불일치 비율
+	claimClearRatio        = 0.2              // 표시 해제 불일치 비율
+	claimAssignmentRefresh = 60 * time.Second // 배정 기록 갱신 주기
+	claimProbeTimeout      = 5 * time.Minute  // 프로브 응답 대기 시간
+)
+
+// AuditProbe - 하트비트 응답에 실리는 Pod 상태 질의
+type AuditProbe struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	IssuedAt  time.Time `json:"issued_at"`
+	expected  bool      // 발급 시점 배정 기록상 이 노드에서 Running 여부
+}
+
+// ProbeResult - 워커의 프로브 응답 (다음 하트비트에 포함)
+type ProbeResult struct {
+	ID      string `json:"id"`
+	Running bool   `json:"running"`
+}
+
+// podAssignment - 노드에 배정된 Pod
+type podAssignment struct {
+	namespace string
+	name      string
+	running   bool
+}
+
+// ClaimRecord - 노드별 검증 기록
+type ClaimRecord struct {
+	NodeID          string    `json:"node_id"`
+	Checks          uint64    `json:"checks"`
+	Divergences     uint64    `json:"divergences"`
+	ProbesPassed    uint64    `json:"probes_passed"`
+	ProbesFailed    uint64    `json:"probes_failed"`
+	LastReported    int       `json:"last_reported_pods"`
+	LastAssigned    int       `json:"last_assigned_pods"`
+	DivergenceRatio float64   `json:"divergence_ratio"`
+	Flagged         bool      `json:"flagged"`
+	FlaggedAt       time.Time `json:"flagged_at,omitempty"`
+	window          []bool
+	pending         *AuditProbe
+}
+
+// ClaimVerifier - 하트비트 주장 검증기
+type ClaimVerifier struct {
+	logger      *logrus.Logger
+	k3sMgr      *K3sManager
+	history     *HeartbeatHistory
+	maintenance *MaintenanceScheduler
+	assignments map[string][]podAssignment
+	refreshedAt time.Time
+	records     map[string]*ClaimRecord
+	tolerance   int
+	probeRate   float64
+	mutex       sync.Mutex
+}
+
+// NewClaimVerifier - 새 Claim Verifier 생성 (CLAIM_POD_TOLERANCE, CLAIM_PROBE_RATE로 조정)
+func NewClaimVerifier(logger *logrus.Logger, k3sMgr *K3sManager) *ClaimVerifier {
+	tolerance, err := strconv.Atoi(getEnvOrDefault("CLAIM_POD_TOLERANCE", "2"))
+	if err != nil || tolerance < 0 {
+		tolerance = 2
+	}
+	probeRate, err := strconv.ParseFloat(getEnvOrDefault("CLAIM_PROBE_RATE", "0.1"), 64)
+	if err != nil || probeRate < 0 || probeRate > 1 {
+		probeRate = 0.1
+	}
+
+	return &ClaimVerifier{
+		logger:      logger,
+		k3sMgr:      k3sMgr,
+		assignments: make(map[string][]podAssignment),
+		records:     make(map[string]*ClaimRecord),
+		tolerance:   tolerance,
+		probeRate:   probeRate,
+	}
+}
+
+// VerifyHeartbeat - 보고된 Pod 수와 프로브 응답을 검증하고, 필요 시 새 프로브 반환
+func (c *ClaimVerifier) VerifyHeartbeat(nodeID string, reportedPods int, result *ProbeResult) *AuditProbe {
+	if !c.k3sMgr.IsRunning() || c.maintenance.InMaintenance(nodeID) {
+		return nil
+	}
+	if err := c.refreshAssignments(); err != nil {
+		c.logger.Warnf("⚠️ Failed to refresh pod assignments for claim verification: %v", err)
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, exists := c.records[nodeID]
+	if !exists {
+		record = &ClaimRecord{NodeID: nodeID}
+		c.records[nodeID] = record
+	}
+
+	assigned := 0
+	for _, pod := range c.assignments[nodeID] {
+		if pod.running {
+			assigned++
+		}
+	}
+	diff := reportedPods - assigned
+	if diff < 0 {
+		diff = -diff
+	}
+	record.LastReported = reportedPods
+	record.LastAssigned = assigned
+	c.observe(record, diff > c.tolerance)
+
+	// 프로브 응답 확인 (응답 없이 만료된 프로브도 실패로 간주)
+	if record.pending != nil {
+		switch {
+		case result != nil && result.ID == record.pending.ID:
+			if result.Running == record.pending.expected {
+				record.ProbesPassed++
+			} else {
+				record.ProbesFailed++
+				c.observe(record, true)
+				c.logger.Warnf("🔍 Audit probe mismatch on %s: %s/%s reported running=%v", nodeID, record.pending.Namespace, record.pending.Pod, result.Running)
+			}
+			record.pending = nil
+		case time.Since(record.pending.IssuedAt) > claimProbeTimeout:
+			record.ProbesFailed++
+			c.observe(record, true)
+			record.pending = nil
+		}
+	}
+
+	c.evaluate(record)
+
+	if record.pending == nil && rand.Float64() < c.probeRate {
+		record.pending = c.newProbe(nodeID)
+	}
+	return record.pending
+}
+
+// observe - 검증 결과를 최근 창에 기록
+func (c *ClaimVerifier) observe(record *ClaimRecord, diverged bool) {
+	record.Checks++
+	if diverged {
+		record.Divergences++
+	}
+	record.window = append(record.window, diverged)
+	if len(record.window) > claimWindowSize {
+		record.window = record.window[len(record.window)-claimWindowSize:]
+	}
+
+	count := 0
+	for _, d := range record.window {
+		if d {
+			count++
+		}
+	}
+	record.DivergenceRatio = float64(count) / float64(len(record.window))
+}
+
+// evaluate - 불일치가 지속되면 슬래싱 검토 대상으로 표시, 회복 시 해제
+func (c *ClaimVerifier) evaluate(record *ClaimRecord) {
+	if len(record.window) < claimMinSamples {
+		return
+	}
+
+	switch {
+	case !record.Flagged && record.DivergenceRatio >= claimFlagRatio:
+		record.Flagged = true
+		record.FlaggedAt = time.Now()
+		detail := fmt.Sprintf("heartbeat claims diverge in %.0f%% of recent checks (reported %d, assigned %d)",
+			record.DivergenceRatio*100, record.LastReported, record.LastAssigned)
+		c.logger.Warnf("🚩 Node %s flagged for slashing review: %s", record.NodeID, detail)
+		c.history.RecordEvent(record.NodeID, "slashing_review", detail)
+	case record.Flagged && record.DivergenceRatio <= claimClearRatio:
+		record.Flagged = false
+		record.FlaggedAt = time.Time{}
+		c.logger.Infof("✅ Node %s heartbeat claims consistent again", record.NodeID)
+		c.history.RecordEvent(record.NodeID, "claims_consistent", fmt.Sprintf("divergence %.0f%%", record.DivergenceRatio*100))
+	}
+}
+
+// newProbe - 이 노드의 Pod 또는 (절반 확률로) 다른 노드의 Pod 상태를 질의
+// 다른 노드의 Pod를 섞어 항상 "실행 중"이라고 답하는 노드를 잡아냄
+func (c *ClaimVerifier) newProbe(nodeID string) *AuditProbe {
+	var candidates []podAssignment
+	expected := true
+	if rand.Intn(2) == 0 {
+		for node, pods := range c.assignments {
+			if node != nodeID {
+				candidates = append(candidates, pods...)
+			}
+		}
+		expected = false
+	}
+	if len(candidates) == 0 {
+		candidates = c.assignments[nodeID]
+		expected = true
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	pod := candidates[rand.Intn(len(candidates))]
+	return &AuditProbe{
+		ID:        fmt.Sprintf("probe_%d", time.Now().UnixNano()),
+		Namespace: pod.namespace,
+		Pod:       pod.name,
+		IssuedAt:  time.Now(),
+		expected:  expected && pod.running,
+	}
+}
+
+// refreshAssignments - 노드별 Pod 배정 기록 갱신 (주기 내에는 캐시 사용)
+func (c *ClaimVerifier) refreshAssignments() error {
+	c.mutex.Lock()
+	fresh := time.Since(c.refreshedAt) < claimAssignmentRefresh
+	c.mutex.Unlock()
+	if fresh {
+		return nil
+	}
+
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return err
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("failed to parse pod list: %v", err)
+	}
+
+	assignments := make(map[string][]podAssignment)
+	for _, pod := range list.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		assignments[pod.Spec.NodeName] = append(assignments[pod.Spec.NodeName], podAssignment{
+			namespace: pod.Metadata.Namespace,
+			name:      pod.Metadata.Name,
+			running:   pod.Status.Phase == "Running",
+		})
+	}
+
+	c.mutex.Lock()
+	c.assignments = assignments
+	c.refreshedAt = time.Now()
+	c.mutex.Unlock()
+	return nil
+}
+
+// ListRecords - 노드 ID 순으로 검증 기록 반환
+func (c *ClaimVerifier) ListRecords() []ClaimRecord {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]ClaimRecord, 0, len(c.records))
+	for _, record := range c.records {
+		copied := *record
+		copied.window = nil
+		copied.pending = nil
+		result = append(result, copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].NodeID < result[j].NodeID })
+	return result
+}
+
+// handleClaims - 하트비트 검증 기록 및 슬래싱 검토 대상 조회 API (?flagged=true)
+func (c *ClaimVerifier) handleClaims(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records := c.ListRecords()
+	if r.URL.Query().Get("flagged") == "true" {
+		flagged := records[:0]
+		for _, record := range records {
+			if record.Flagged {
+				flagged = append(flagged, record)
+			}
+		}
+		records = flagged
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   records,
+	})
+}
+
+// writeMetrics - 하트비트 검증 메트릭 출력
+func (c *ClaimVerifier) writeMetrics(w io.Writer) {
+	records := c.ListRecords()
+
+	writeMetricHeader(w, "nautilus_claim_divergence_ratio", "gauge", "Share of recent heartbeat checks where reported pods diverged from assignments")
+	for _, record := range records {
+		writeMetric(w, "nautilus_claim_divergence_ratio", map[string]string{"node": record.NodeID}, record.DivergenceRatio)
+	}
+
+	writeMetricHeader(w, "nautilus_claim_probe_failures_total", "counter", "Audit probes answered incorrectly or not at all")
+	for _, record := range records {
+		writeMetric(w, "nautilus_claim_probe_failures_total", map[string]string{"node": record.NodeID}, float64(record.ProbesFailed))
+	}
+
+	writeMetricHeader(w, "nautilus_claim_flagged", "gauge", "Whether the node is flagged for slashing review")
+	for _, record := range records {
+		flagged := 0.0
+		if record.Flagged {
+			flagged = 1
+		}
+		writeMetric(w, "nautilus_claim_flagged", map[string]string{"node": record.NodeID}, flagged)
+	}
+}
@@ -0,0 +1,190 @@
+// Client API - 외부 연동용 워커 조회, 토큰 조회, kubeconfig 발급
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed kubeconfig-template.yaml
+var kubeconfigTemplate string
+
+// ClientAPI - 서드파티 연동(pkg/client SDK)이 사용하는 읽기 위주 API
+type ClientAPI struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	gatewayURL string
+	oidcIssuer string       // 서비스 계정 토큰 발급자 (kubeconfig 클러스터 확장에 안내)
+	scopes     *TokenScopes // 범위 제한 토큰 조회 (introspection에 범위 포함)
+}
+
+// WorkerView - 외부에 공개하는 워커 정보 (seal/join 토큰 제외)
+type WorkerView struct {
+	NodeID        string          `json:"node_id"`
+	Status        string          `json:"status"`
+	Role          string          `json:"role,omitempty"`
+	StakeAmount   uint64          `json:"stake_amount"`
+	StakeTier     string          `json:"stake_tier"`
+	WorkerAddress string          `json:"worker_address"`
+	Region        string          `json:"region,omitempty"`
+	Zone          string          `json:"zone,omitempty"`
+	LatencyMs     int64           `json:"latency_ms,omitempty"`
+	RegisteredAt  time.Time       `json:"registered_at"`
+	LastHeartbeat time.Time       `json:"last_heartbeat"`
+	Conditions    []NodeCondition `json:"conditions,omitempty"`
+}
+
+// TokenIntrospection - seal 토큰 조회 결과 (알 수 없는 토큰은 active=false, 범위 제한 토큰은 scope 포함)
+type TokenIntrospection struct {
+	Active      bool        `json:"active"`
+	NodeID      string      `json:"node_id,omitempty"`
+	Owner       string      `json:"owner,omitempty"`
+	Role        string      `json:"role,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	StakeAmount uint64      `json:"stake_amount,omitempty"`
+	StakeTier   string      `json:"stake_tier,omitempty"`
+	Scope       *TokenScope `json:"scope,omitempty"`
+}
+
+// NewClientAPI - Client API 생성 (NAUTILUS_GATEWAY_URL은 발급하는 kubeconfig의 서버 주소)
+func NewClientAPI(logger *logrus.Logger, workerPool *WorkerPool) *ClientAPI {
+	return &ClientAPI{
+		logger:     logger,
+		workerPool: workerPool,
+		gatewayURL: getEnvOrDefault("NAUTILUS_GATEWAY_URL", "http://localhost:8080"),
+		oidcIssuer: serviceAccountIssuerURL(),
+	}
+}
+
+func newWorkerView(worker *WorkerNode) WorkerView {
+	return WorkerView{
+		NodeID:        worker.NodeID,
+		Status:        worker.Status,
+		Role:          worker.Role,
+		StakeAmount:   worker.StakeAmount,
+		StakeTier:     stakeTierFor(worker.StakeAmount),
+		WorkerAddress: worker.WorkerAddress,
+		Region:        worker.Region,
+		Zone:          worker.Zone,
+		LatencyMs:     worker.LatencyMs,
+		RegisteredAt:  worker.RegisteredAt,
+		LastHeartbeat: worker.LastHeartbeat,
+		Conditions:    worker.Conditions,
+	}
+}
+
+// requestSealToken - X-Seal-Token 헤더 또는 Authorization: Bearer
+func requestSealToken(r *http.Request) string {
+	if token := r.Header.Get("X-Seal-Token"); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+/*
+handleWorkers - 워커 목록/단일 조회
+
+	GET /api/v1/workers              전체 목록 (?status=, ?role= 필터)
+	GET /api/v1/workers/{node_id}    단일 워커
+*/
+func (c *ClientAPI) handleWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if nodeID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/workers"), "/"); nodeID != "" {
+		worker, exists := c.workerPool.GetWorker(nodeID)
+		if !exists {
+			http.Error(w, "Unknown worker", http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, newWorkerView(worker))
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	role := r.URL.Query().Get("role")
+	views := []WorkerView{}
+	for _, worker := range c.workerPool.ListWorkers() {
+		if (status == "" || worker.Status == status) && (role == "" || worker.Role == role) {
+			views = append(views, newWorkerView(worker))
+		}
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].NodeID < views[j].NodeID })
+	writeClientJSON(w, views)
+}
+
+// handleIntrospect - seal 토큰 조회 (GET /api/v1/tokens/introspect, 토큰 자체는 응답에 포함하지 않음)
+func (c *ClientAPI) handleIntrospect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := requestSealToken(r)
+	result := TokenIntrospection{}
+	worker, ok := c.workerPool.WorkerBySealToken(token)
+	scope, scoped := c.scopes.Lookup(token)
+	if scoped {
+		// 범위 제한 토큰은 발급한 워커 기준으로 설명하고 만료되면 비활성
+		worker, ok = c.workerPool.GetWorker(scope.NodeID)
+	}
+	if ok {
+		result = TokenIntrospection{
+			Active:      worker.Status != "offline",
+			NodeID:      worker.NodeID,
+			Owner:       worker.WorkerAddress,
+			Role:        worker.Role,
+			Status:      worker.Status,
+			StakeAmount: worker.StakeAmount,
+			StakeTier:   stakeTierFor(worker.StakeAmount),
+		}
+		if scoped {
+			result.Active = result.Active && time.Now().Before(scope.ExpiresAt)
+			result.Scope = scope
+		}
+	}
+	writeClientJSON(w, result)
+}
+
+// handleKubectlConfig - seal 토큰 소유자에게 Gateway 주소로 접속하는 kubeconfig 발급 (GET /kubectl/config)
+// 클러스터의 oidc.k3s-daas.io 확장에 서비스 계정 토큰 발급자를 적어 클라우드 IAM 연동 설정에 쓰도록 함
+func (c *ClientAPI) handleKubectlConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := requestSealToken(r)
+	worker, ok := c.workerPool.WorkerBySealToken(token)
+	if !ok {
+		c.logger.Warnf("🚫 kubeconfig request with unknown seal token from %s", r.RemoteAddr)
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	config := strings.Replace(kubeconfigTemplate, "http://localhost:8080", c.gatewayURL, 1)
+	config = strings.Replace(config, "SEAL_TOKEN_PLACEHOLDER", token, 1)
+	config = strings.Replace(config, "OIDC_ISSUER_PLACEHOLDER", c.oidcIssuer, 1)
+	config = strings.Replace(config, "OIDC_DISCOVERY_PLACEHOLDER", c.oidcIssuer+oidcDiscoveryPath, 1)
+	c.logger.Infof("📄 Issued kubeconfig for worker %s", worker.NodeID)
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte(config))
+}
+
+func writeClientJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   data,
+	})
+}
@@ -0,0 +1,140 @@
+// Clock Guard - Sui 체크포인트 시각 대비 로컬 시계 오차 감시 (재전송 방지/토큰 만료 보호)
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ClockGuard - 시계 오차 측정 및 보안 민감 작업 차단
+type ClockGuard struct {
+	logger        *logrus.Logger
+	sui           *SuiIntegration
+	warnThreshold time.Duration
+	maxSkew       time.Duration
+	interval      time.Duration
+	skew          time.Duration
+	lastChecked   time.Time
+	lastErr       error
+	mutex         sync.RWMutex
+}
+
+// NewClockGuard - 새 Clock Guard 생성
+func NewClockGuard(logger *logrus.Logger, sui *SuiIntegration) *ClockGuard {
+	return &ClockGuard{
+		logger:        logger,
+		sui:           sui,
+		warnThreshold: envSeconds("CLOCK_SKEW_WARN_SECONDS", 5),
+		maxSkew:       envSeconds("MAX_CLOCK_SKEW_SECONDS", 30),
+		interval:      envSeconds("CLOCK_SKEW_CHECK_INTERVAL_SECONDS", 300),
+	}
+}
+
+// envSeconds - 초 단위 환경변수를 Duration으로 변환 (잘못된 값은 기본값)
+func envSeconds(key string, defaultSeconds int) time.Duration {
+	seconds, err := strconv.Atoi(getEnvOrDefault(key, strconv.Itoa(defaultSeconds)))
+	if err != nil || seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Start - 주기적 오차 측정 시작
+func (c *ClockGuard) Start(ctx context.Context) {
+	// 체인 시각은 Sui 체크포인트에서만 얻을 수 있음
+	if !c.sui.onSui() {
+		c.logger.Warnf("⚠️ Clock guard disabled on %s chain backend", c.sui.backend.Name())
+		return
+	}
+
+	c.logger.Info("⏱️ Starting clock guard...")
+	c.measure()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.measure()
+		}
+	}
+}
+
+// measure - 체인 시각과 로컬 시각 비교
+func (c *ClockGuard) measure() {
+	chainNow, err := c.sui.chainTime()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err != nil {
+		c.lastErr = err
+		c.logger.Warnf("⚠️ Failed to measure clock skew: %v", err)
+		return
+	}
+
+	c.skew = time.Since(chainNow)
+	c.lastChecked = time.Now()
+	c.lastErr = nil
+
+	abs := c.skew
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs > c.maxSkew:
+		c.logger.Errorf("🛑 Clock skew %s exceeds %s, refusing security-sensitive operations", c.skew.Round(time.Millisecond), c.maxSkew)
+	case abs > c.warnThreshold:
+		c.logger.Warnf("⚠️ Clock skew %s exceeds warning threshold %s", c.skew.Round(time.Millisecond), c.warnThreshold)
+	default:
+		c.logger.Debugf("⏱️ Clock skew %s", c.skew.Round(time.Millisecond))
+	}
+}
+
+// Check - 보안 민감 작업 허용 여부 (오차가 한도를 넘으면 오류)
+func (c *ClockGuard) Check() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	abs := c.skew
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs > c.maxSkew {
+		return NewUserFriendlyError(ErrCodeClockSkew,
+			fmt.Sprintf("Local clock differs from chain by %s (max %s)", c.skew.Round(time.Second), c.maxSkew),
+			"NTP 동기화(timedatectl set-ntp true 또는 chronyd)를 활성화하세요", nil)
+	}
+	return nil
+}
+
+// Skew - 마지막 측정 오차와 측정 시각
+func (c *ClockGuard) Skew() (time.Duration, time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.skew, c.lastChecked
+}
+
+// writeMetrics - 시계 오차 메트릭 출력
+func (c *ClockGuard) writeMetrics(w io.Writer) {
+	skew, checkedAt := c.Skew()
+	writeMetricHeader(w, "nautilus_clock_skew_seconds", "gauge", "Local clock minus latest Sui checkpoint timestamp")
+	writeMetric(w, "nautilus_clock_skew_seconds", nil, skew.Seconds())
+	if !checkedAt.IsZero() {
+		writeMetricHeader(w, "nautilus_clock_skew_last_check_timestamp", "gauge", "Unix time of the last successful skew measurement")
+		writeMetric(w, "nautilus_clock_skew_last_check_timestamp", nil, float64(checkedAt.Unix()))
+	}
+}
@@ -0,0 +1,318 @@
+// Cold Storage - 오래된 기록을 옮겨 두는 저비용 저장소 (로컬 디렉토리, S3 호환 오브젝트 스토리지, Walrus)
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+NAUTILUS_ARCHIVE_BACKEND로 선택 (기본 local)
+
+	local   상태 디렉토리의 archive/ (NAUTILUS_ARCHIVE_DIR로 변경)
+	s3      NAUTILUS_ARCHIVE_S3_BUCKET, NAUTILUS_ARCHIVE_S3_REGION(기본 us-east-1),
+	        NAUTILUS_ARCHIVE_S3_ENDPOINT(기본 AWS, MinIO 등은 path-style URL),
+	        AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY / AWS_SESSION_TOKEN
+	walrus  NAUTILUS_WALRUS_PUBLISHER_URL, NAUTILUS_WALRUS_AGGREGATOR_URL,
+	        NAUTILUS_ARCHIVE_WALRUS_EPOCHS(기본 53) - 삭제할 수 없고 저장 기간이 지나면 만료
+
+Put이 돌려준 location을 색인에 남겨 두었다가 Get/Delete에 그대로 넘깁니다.
+*/
+
+// errColdStoreImmutable - 삭제할 수 없는 저장소 (Walrus)
+var errColdStoreImmutable = errors.New("cold storage backend does not support deletion")
+
+// ColdStore - 아카이브 저장소
+type ColdStore interface {
+	Name() string
+	Put(key string, data []byte) (string, error)
+	Get(location string) ([]byte, error)
+	Delete(location string) error
+}
+
+// NewColdStore - 환경변수 설정으로 저장소 생성
+func NewColdStore() (ColdStore, error) {
+	client := &http.Client{Timeout: 2 * time.Minute}
+
+	switch backend := strings.ToLower(getEnvOrDefault("NAUTILUS_ARCHIVE_BACKEND", "local")); backend {
+	case "local":
+		return &localColdStore{dir: getEnvOrDefault("NAUTILUS_ARCHIVE_DIR", statePath("archive"))}, nil
+
+	case "s3":
+		store := &s3ColdStore{
+			client:       client,
+			bucket:       os.Getenv("NAUTILUS_ARCHIVE_S3_BUCKET"),
+			region:       getEnvOrDefault("NAUTILUS_ARCHIVE_S3_REGION", "us-east-1"),
+			endpoint:     strings.TrimRight(os.Getenv("NAUTILUS_ARCHIVE_S3_ENDPOINT"), "/"),
+			accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if store.bucket == "" || store.accessKey == "" || store.secretKey == "" {
+			return nil, fmt.Errorf("s3 archive requires NAUTILUS_ARCHIVE_S3_BUCKET, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		if store.endpoint == "" {
+			store.endpoint = "https://s3." + store.region + ".amazonaws.com"
+		}
+		return store, nil
+
+	case "walrus":
+		store := &walrusColdStore{
+			client:        client,
+			publisherURL:  strings.TrimRight(os.Getenv("NAUTILUS_WALRUS_PUBLISHER_URL"), "/"),
+			aggregatorURL: strings.TrimRight(os.Getenv("NAUTILUS_WALRUS_AGGREGATOR_URL"), "/"),
+			epochs:        envCount("NAUTILUS_ARCHIVE_WALRUS_EPOCHS", 53),
+		}
+		if store.publisherURL == "" || store.aggregatorURL == "" {
+			return nil, fmt.Errorf("walrus archive requires NAUTILUS_WALRUS_PUBLISHER_URL and NAUTILUS_WALRUS_AGGREGATOR_URL")
+		}
+		return store, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported NAUTILUS_ARCHIVE_BACKEND %q (local, s3, walrus)", backend)
+	}
+}
+
+// localColdStore - 로컬 디렉토리 (location은 dir 기준 상대 경로)
+type localColdStore struct {
+	dir string
+}
+
+func (l *localColdStore) Name() string { return "local" }
+
+func (l *localColdStore) path(location string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(location))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive location %q", location)
+	}
+	return filepath.Join(l.dir, clean), nil
+}
+
+func (l *localColdStore) Put(key string, data []byte) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (l *localColdStore) Get(location string) ([]byte, error) {
+	path, err := l.path(location)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+func (l *localColdStore) Delete(location string) error {
+	path, err := l.path(location)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// s3ColdStore - S3 호환 오브젝트 스토리지 (SigV4 서명, path-style, location은 오브젝트 키)
+type s3ColdStore struct {
+	client       *http.Client
+	bucket       string
+	region       string
+	endpoint     string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+func (s *s3ColdStore) Name() string { return "s3" }
+
+func (s *s3ColdStore) Put(key string, data []byte) (string, error) {
+	resp, err := s.do(http.MethodPut, key, data)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return key, nil
+}
+
+func (s *s3ColdStore) Get(location string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (s *s3ColdStore) Delete(location string) error {
+	resp, err := s.do(http.MethodDelete, location, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+/*
+do - SigV4로 서명한 요청 (2xx가 아니면 오류)
+키는 호출하는 쪽에서 [A-Za-z0-9._/-]만 쓰므로 경로 인코딩을 따로 하지 않습니다.
+*/
+func (s *s3ColdStore) do(method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.endpoint+"/"+s.bucket+"/"+key, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if s.sessionToken != "" {
+		headers["x-amz-security-token"] = s.sessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+		if name != "host" {
+			req.Header.Set(name, headers[name])
+		}
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{day, s.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(signingKey, stringToSign))))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("s3 %s %s returned %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// walrusColdStore - Walrus (location은 blob ID)
+type walrusColdStore struct {
+	client        *http.Client
+	publisherURL  string
+	aggregatorURL string
+	epochs        int
+}
+
+func (w *walrusColdStore) Name() string { return "walrus" }
+
+func (w *walrusColdStore) Put(key string, data []byte) (string, error) {
+	return uploadWalrusBlob(w.client, w.publisherURL, w.epochs, data)
+}
+
+func (w *walrusColdStore) Get(location string) ([]byte, error) {
+	resp, err := w.client.Get(w.aggregatorURL + "/v1/blobs/" + location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("aggregator returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (w *walrusColdStore) Delete(location string) error {
+	return errColdStoreImmutable
+}
+
+// uploadWalrusBlob - Walrus 퍼블리셔에 blob 저장 후 blob ID 반환
+func uploadWalrusBlob(client *http.Client, publisherURL string, epochs int, data []byte) (string, error) {
+	url := fmt.Sprintf("%s/v1/blobs?epochs=%d", publisherURL, epochs)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("publisher returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var stored struct {
+		NewlyCreated *struct {
+			BlobObject struct {
+				BlobID string `json:"blobId"`
+			} `json:"blobObject"`
+		} `json:"newlyCreated"`
+		AlreadyCertified *struct {
+			BlobID string `json:"blobId"`
+		} `json:"alreadyCertified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stored); err != nil {
+		return "", fmt.Errorf("invalid publisher response: %v", err)
+	}
+	switch {
+	case stored.NewlyCreated != nil && stored.NewlyCreated.BlobObject.BlobID != "":
+		return stored.NewlyCreated.BlobObject.BlobID, nil
+	case stored.AlreadyCertified != nil && stored.AlreadyCertified.BlobID != "":
+		return stored.AlreadyCertified.BlobID, nil
+	}
+	return "", fmt.Errorf("publisher response has no blob id")
+}
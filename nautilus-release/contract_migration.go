@@ -0,0 +1,523 @@
+// Contract Migration - Move 패키지 업그레이드/재배포 시 이전·새 패키지를 함께 받는 전환 기간 관리
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+패키지를 업그레이드하거나 새로 배포하면 마스터, 워커, Gateway의 CONTRACT_PACKAGE_ID를 동시에 바꿔야 했습니다.
+전환 기간 동안 마스터는 두 패키지를 모두 받아들여 설정을 순서대로 바꿀 수 있게 합니다.
+
+	transition  이전/새 패키지 이벤트를 모두 처리, 워커 풀은 두 레지스트리의 합집합
+	            (어느 쪽에 등록된 워커의 Seal 토큰/스테이크도 유효), 컨트랙트 호출은 새 패키지로
+	completed   새 패키지만 처리 (전환 기간이 지나면 자동, 또는 API로 조기 완료)
+	aborted     이전 패키지로 되돌림
+
+시작: NAUTILUS_MIGRATION_PACKAGE_ID (+ _REGISTRY_ID, _SCHEDULER_ID, 재배포로 공유 객체가 바뀐 경우),
+NAUTILUS_MIGRATION_WINDOW_HOURS (기본 72) 또는 POST /api/v1/contract/migration.
+상태는 contract-migration.json에 저장되어 재시작해도 이어지며, CONTRACT_PACKAGE_ID를 새 패키지로 바꾸면 정리됩니다.
+*/
+
+const (
+	MigrationIdle       = "idle"
+	MigrationTransition = "transition"
+	MigrationCompleted  = "completed"
+	MigrationAborted    = "aborted"
+)
+
+var suiObjectIDPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{1,64}$`)
+
+// ContractRefs - 패키지와 공유 객체 ID
+type ContractRefs struct {
+	Package   string `json:"package_id"`
+	Registry  string `json:"registry_id"`
+	Scheduler string `json:"scheduler_id"`
+}
+
+// MigrationState - 저장되는 전환 상태
+type MigrationState struct {
+	Phase      string       `json:"phase"`
+	From       ContractRefs `json:"from"`
+	To         ContractRefs `json:"to"`
+	StartedAt  time.Time    `json:"started_at,omitempty"`
+	Deadline   time.Time    `json:"deadline,omitempty"`
+	FinishedAt time.Time    `json:"finished_at,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+}
+
+// MigrationProgress - API 응답 (전환 상태와 진행률)
+type MigrationProgress struct {
+	MigrationState
+	Active           ContractRefs         `json:"active"`
+	AcceptedPackages []string             `json:"accepted_packages"`
+	Events           map[string]uint64    `json:"events"`
+	LastEventAt      map[string]time.Time `json:"last_event_at,omitempty"`
+	WorkersTotal     int                  `json:"workers_total"`
+	WorkersMigrated  int                  `json:"workers_migrated"`
+	PendingWorkers   []string             `json:"pending_workers,omitempty"`
+	Percent          float64              `json:"percent"`
+	RemainingSeconds int64                `json:"remaining_seconds,omitempty"`
+}
+
+// ContractMigration - 패키지 전환 관리자
+type ContractMigration struct {
+	logger     *logrus.Logger
+	sui        *SuiIntegration
+	base       ContractRefs // 환경변수로 설정된 패키지
+	stateFile  string
+	adminToken string
+	window     time.Duration
+
+	mutex       sync.Mutex
+	state       MigrationState
+	events      map[string]uint64
+	lastEvent   map[string]time.Time
+	nodePackage map[string]string   // 노드별 마지막 이벤트의 패키지 (레지스트리가 같을 때 진행률 기준)
+	registered  map[string][]string // 레지스트리별 마지막 동기화 때의 노드
+	ctx         context.Context
+	cancel      context.CancelFunc // 새 패키지 이벤트 구독 중단
+}
+
+// NewContractMigration - 저장된 상태와 NAUTILUS_MIGRATION_* 환경변수로 생성
+func NewContractMigration(logger *logrus.Logger, sui *SuiIntegration) (*ContractMigration, error) {
+	hours, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_MIGRATION_WINDOW_HOURS", "72"))
+	if err != nil || hours <= 0 {
+		return nil, fmt.Errorf("invalid NAUTILUS_MIGRATION_WINDOW_HOURS")
+	}
+	m := &ContractMigration{
+		logger:      logger,
+		sui:         sui,
+		base:        ContractRefs{Package: sui.contractAddr, Registry: sui.registryAddr, Scheduler: sui.schedulerAddr},
+		stateFile:   statePath("contract-migration.json"),
+		adminToken:  os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		window:      time.Duration(hours) * time.Hour,
+		state:       MigrationState{Phase: MigrationIdle},
+		events:      make(map[string]uint64),
+		lastEvent:   make(map[string]time.Time),
+		nodePackage: make(map[string]string),
+		registered:  make(map[string][]string),
+	}
+
+	var saved MigrationState
+	found, err := loadJSONState(m.stateFile, &saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract migration state: %v", err)
+	}
+	switch {
+	case !found || saved.Phase == MigrationIdle:
+	case saved.From.Package == m.base.Package:
+		m.state = saved
+	case saved.To.Package == m.base.Package:
+		logger.Infof("📦 CONTRACT_PACKAGE_ID now points to %s, clearing finished migration state", m.base.Package)
+		os.Remove(m.stateFile)
+	default:
+		logger.Warnf("⚠️ Ignoring saved migration from %s (current package is %s)", saved.From.Package, m.base.Package)
+	}
+
+	if target := os.Getenv("NAUTILUS_MIGRATION_PACKAGE_ID"); target != "" && target != m.base.Package && target != m.state.To.Package {
+		to := ContractRefs{
+			Package:   target,
+			Registry:  os.Getenv("NAUTILUS_MIGRATION_REGISTRY_ID"),
+			Scheduler: os.Getenv("NAUTILUS_MIGRATION_SCHEDULER_ID"),
+		}
+		if err := m.Begin(to, m.window); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Start - 새 패키지 이벤트 구독과 전환 기간 만료 처리
+func (m *ContractMigration) Start(ctx context.Context) {
+	m.mutex.Lock()
+	m.ctx = ctx
+	if m.state.Phase == MigrationTransition || m.state.Phase == MigrationCompleted {
+		m.followLocked()
+	}
+	m.mutex.Unlock()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mutex.Lock()
+			expired := m.state.Phase == MigrationTransition && time.Now().After(m.state.Deadline)
+			m.mutex.Unlock()
+			if expired {
+				if err := m.Complete("transition window elapsed"); err != nil {
+					m.logger.Errorf("❌ Failed to complete contract migration: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// followLocked - 새 패키지 이벤트 구독 시작 (Start 전이면 Start에서 시작)
+func (m *ContractMigration) followLocked() {
+	if m.ctx == nil || m.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancel = cancel
+	go m.sui.subscribePackage(ctx, m.state.To.Package)
+}
+
+// Begin - 전환 시작 (레지스트리/스케줄러를 비우면 업그레이드로 보고 기존 객체 유지)
+func (m *ContractMigration) Begin(to ContractRefs, window time.Duration) error {
+	if to.Registry == "" {
+		to.Registry = m.base.Registry
+	}
+	if to.Scheduler == "" {
+		to.Scheduler = m.base.Scheduler
+	}
+	for _, id := range []string{to.Package, to.Registry, to.Scheduler} {
+		if !suiObjectIDPattern.MatchString(id) {
+			return fmt.Errorf("invalid object ID %q", id)
+		}
+	}
+	if to.Package == m.base.Package {
+		return fmt.Errorf("package %s is already the configured package", to.Package)
+	}
+	if window <= 0 {
+		window = m.window
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch m.state.Phase {
+	case MigrationTransition:
+		return fmt.Errorf("migration to %s already in progress", m.state.To.Package)
+	case MigrationCompleted:
+		return fmt.Errorf("migration to %s completed; set CONTRACT_PACKAGE_ID to it and restart before starting another", m.state.To.Package)
+	}
+	now := time.Now()
+	m.state = MigrationState{
+		Phase:     MigrationTransition,
+		From:      m.base,
+		To:        to,
+		StartedAt: now,
+		Deadline:  now.Add(window),
+	}
+	m.events = make(map[string]uint64)
+	m.lastEvent = make(map[string]time.Time)
+	m.nodePackage = make(map[string]string)
+	if err := saveJSONState(m.stateFile, m.state); err != nil {
+		return err
+	}
+	m.followLocked()
+	m.logger.Infof("📦 Contract migration started: %s → %s (accepting both until %s)", m.base.Package, to.Package, m.state.Deadline.Format(time.RFC3339))
+	return nil
+}
+
+// Complete - 새 패키지만 처리
+func (m *ContractMigration) Complete(reason string) error {
+	return m.finish(MigrationCompleted, reason)
+}
+
+// Abort - 이전 패키지로 되돌림 (새 패키지 구독 중단)
+func (m *ContractMigration) Abort(reason string) error {
+	return m.finish(MigrationAborted, reason)
+}
+
+func (m *ContractMigration) finish(phase, reason string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.state.Phase != MigrationTransition {
+		return fmt.Errorf("no migration in progress (phase %s)", m.state.Phase)
+	}
+	m.state.Phase = phase
+	m.state.FinishedAt = time.Now()
+	m.state.Reason = reason
+	if phase == MigrationAborted && m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	if err := saveJSONState(m.stateFile, m.state); err != nil {
+		return err
+	}
+	if phase == MigrationCompleted {
+		m.logger.Infof("✅ Contract migration to %s completed (%s); update CONTRACT_PACKAGE_ID on all components", m.state.To.Package, reason)
+	} else {
+		m.logger.Warnf("↩️ Contract migration to %s aborted (%s)", m.state.To.Package, reason)
+	}
+	return nil
+}
+
+// Target - 컨트랙트 호출에 쓸 객체 (전환 중/완료 후에는 새 패키지)
+func (m *ContractMigration) Target() (ContractRefs, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.state.Phase == MigrationTransition || m.state.Phase == MigrationCompleted {
+		return m.state.To, true
+	}
+	return m.base, false
+}
+
+// Accepts - 이벤트를 처리할 패키지인지
+func (m *ContractMigration) Accepts(packageID string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch m.state.Phase {
+	case MigrationTransition:
+		return packageID == m.state.From.Package || packageID == m.state.To.Package
+	case MigrationCompleted:
+		return packageID == m.state.To.Package
+	default:
+		return packageID == m.base.Package
+	}
+}
+
+// Registries - 워커 풀을 구성할 레지스트리 (새 레지스트리가 먼저)
+func (m *ContractMigration) Registries() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch m.state.Phase {
+	case MigrationTransition:
+		if m.state.To.Registry != m.state.From.Registry {
+			return []string{m.state.To.Registry, m.state.From.Registry}
+		}
+		return []string{m.state.To.Registry}
+	case MigrationCompleted:
+		return []string{m.state.To.Registry}
+	default:
+		return []string{m.base.Registry}
+	}
+}
+
+// ObserveEvent - 전환 중 패키지별 이벤트 집계 (진행률 계산용)
+func (m *ContractMigration) ObserveEvent(event *SuiContractEvent) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.state.Phase != MigrationTransition {
+		return
+	}
+	m.events[event.PackageID]++
+	m.lastEvent[event.PackageID] = time.Now()
+	if nodeID, ok := event.EventData["node_id"].(string); ok && nodeID != "" {
+		m.nodePackage[nodeID] = event.PackageID
+	}
+}
+
+// ObserveRegistries - 풀 동기화 때 레지스트리별 등록 노드 기록
+func (m *ContractMigration) ObserveRegistries(registered map[string][]string) {
+	m.mutex.Lock()
+	m.registered = registered
+	m.mutex.Unlock()
+}
+
+// Progress - 전환 상태와 진행률
+func (m *ContractMigration) Progress() MigrationProgress {
+	var poolNodes []string
+	for _, worker := range m.sui.workerPool.ListWorkers() {
+		poolNodes = append(poolNodes, worker.NodeID)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	progress := MigrationProgress{
+		MigrationState: m.state,
+		Active:         m.base,
+		Events:         make(map[string]uint64),
+		LastEventAt:    make(map[string]time.Time),
+	}
+	for pkg, count := range m.events {
+		progress.Events[pkg] = count
+	}
+	for pkg, at := range m.lastEvent {
+		progress.LastEventAt[pkg] = at
+	}
+
+	switch m.state.Phase {
+	case MigrationTransition:
+		progress.Active = m.state.To
+		progress.AcceptedPackages = []string{m.state.From.Package, m.state.To.Package}
+		if remaining := time.Until(m.state.Deadline); remaining > 0 {
+			progress.RemainingSeconds = int64(remaining.Seconds())
+		}
+	case MigrationCompleted:
+		progress.Active = m.state.To
+		progress.AcceptedPackages = []string{m.state.To.Package}
+	default:
+		progress.AcceptedPackages = []string{m.base.Package}
+	}
+
+	// 재배포는 새 레지스트리 등록 여부, 업그레이드는 새 패키지로 이벤트를 낸 노드 기준
+	migrated := make(map[string]bool)
+	if m.state.To.Registry != m.state.From.Registry {
+		all := make(map[string]bool)
+		for registry, nodes := range m.registered {
+			for _, node := range nodes {
+				all[node] = true
+				if registry == m.state.To.Registry {
+					migrated[node] = true
+				}
+			}
+		}
+		poolNodes = poolNodes[:0]
+		for node := range all {
+			poolNodes = append(poolNodes, node)
+		}
+	} else {
+		for node, pkg := range m.nodePackage {
+			if pkg == m.state.To.Package {
+				migrated[node] = true
+			}
+		}
+	}
+
+	progress.WorkersTotal = len(poolNodes)
+	for _, node := range poolNodes {
+		if migrated[node] {
+			progress.WorkersMigrated++
+		} else if m.state.Phase == MigrationTransition {
+			progress.PendingWorkers = append(progress.PendingWorkers, node)
+		}
+	}
+	sort.Strings(progress.PendingWorkers)
+	switch {
+	case m.state.Phase == MigrationCompleted:
+		progress.Percent = 100
+	case m.state.Phase == MigrationTransition && progress.WorkersTotal > 0:
+		progress.Percent = float64(progress.WorkersMigrated) * 100 / float64(progress.WorkersTotal)
+	}
+	return progress
+}
+
+/*
+handleMigration - 패키지 전환 상태 조회/제어 (/api/v1/contract/migration)
+
+	GET                                                                      상태와 진행률 (인증 없음, 워커/Gateway가 활성 패키지 확인)
+	POST {"action": "begin", "package_id", "registry_id", "scheduler_id", "window_hours"}   관리자 토큰
+	POST {"action": "complete" | "abort", "reason"}                                          관리자 토큰
+*/
+func (m *ContractMigration) handleMigration(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, m.Progress())
+	case http.MethodPost:
+		if m.adminToken == "" {
+			http.Error(w, "Contract migration API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+			m.logger.Warnf("🚫 Unauthorized contract migration request from %s", r.RemoteAddr)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Action      string `json:"action"`
+			PackageID   string `json:"package_id"`
+			RegistryID  string `json:"registry_id"`
+			SchedulerID string `json:"scheduler_id"`
+			WindowHours int    `json:"window_hours"`
+			Reason      string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid migration request", http.StatusBadRequest)
+			return
+		}
+		if body.Reason == "" {
+			body.Reason = "requested by operator"
+		}
+
+		var err error
+		switch body.Action {
+		case "begin":
+			to := ContractRefs{Package: body.PackageID, Registry: body.RegistryID, Scheduler: body.SchedulerID}
+			for _, id := range []string{to.Package, to.Registry, to.Scheduler} {
+				if id != "" && !suiObjectIDPattern.MatchString(id) || to.Package == "" {
+					http.Error(w, fmt.Sprintf("invalid object ID %q", id), http.StatusBadRequest)
+					return
+				}
+			}
+			err = m.Begin(to, time.Duration(body.WindowHours)*time.Hour)
+		case "complete":
+			err = m.Complete(body.Reason)
+		case "abort":
+			err = m.Abort(body.Reason)
+		default:
+			http.Error(w, "action must be begin, complete or abort", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeClientJSON(w, m.Progress())
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 전환 단계, 패키지별 이벤트 수, 진행률
+func (m *ContractMigration) writeMetrics(w io.Writer) {
+	progress := m.Progress()
+
+	writeMetricHeader(w, "nautilus_contract_migration_phase", "gauge", "Current contract package migration phase")
+	for _, phase := range []string{MigrationIdle, MigrationTransition, MigrationCompleted, MigrationAborted} {
+		value := 0.0
+		if progress.Phase == phase {
+			value = 1
+		}
+		writeMetric(w, "nautilus_contract_migration_phase", map[string]string{"phase": phase}, value)
+	}
+	packages := make([]string, 0, len(progress.Events))
+	for pkg := range progress.Events {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	writeMetricHeader(w, "nautilus_contract_migration_events_total", "counter", "Contract events accepted per package during the transition")
+	for _, pkg := range packages {
+		writeMetric(w, "nautilus_contract_migration_events_total", map[string]string{"package": pkg}, float64(progress.Events[pkg]))
+	}
+	writeMetricHeader(w, "nautilus_contract_migration_workers_migrated", "gauge", "Workers already on the new package or registry")
+	writeMetric(w, "nautilus_contract_migration_workers_migrated", nil, float64(progress.WorkersMigrated))
+	writeMetricHeader(w, "nautilus_contract_migration_workers_total", "gauge", "Workers considered for migration progress")
+	writeMetric(w, "nautilus_contract_migration_workers_total", nil, float64(progress.WorkersTotal))
+}
+
+// activeContract - 컨트랙트 호출과 공개 정보에 쓸 패키지/객체
+func (s *SuiIntegration) activeContract() ContractRefs {
+	if s.migration != nil {
+		refs, _ := s.migration.Target()
+		return refs
+	}
+	return ContractRefs{Package: s.contractAddr, Registry: s.registryAddr, Scheduler: s.schedulerAddr}
+}
+
+// acceptsPackage - 처리할 이벤트의 패키지인지 (전환 중에는 이전/새 패키지 모두)
+func (s *SuiIntegration) acceptsPackage(packageID string) bool {
+	if s.migration != nil {
+		return s.migration.Accepts(packageID)
+	}
+	return packageID == s.contractAddr
+}
+
+// workerRegistries - 워커 풀을 구성할 레지스트리 객체
+func (s *SuiIntegration) workerRegistries() []string {
+	if s.migration != nil {
+		return s.migration.Registries()
+	}
+	return []string{s.registryAddr}
+}
@@ -0,0 +1,124 @@
+// Controller Manager - Contract 경로 요청의 어드미션과 마스터 측 컨트롤러 (StatefulSet 등 워크로드 컨트롤러는 K3s 내장)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ControllerManager - 마스터 측 리소스 컨트롤러 관리
+type ControllerManager struct {
+	logger       *logrus.Logger
+	k3sMgr       *K3sManager
+	topology     *TopologyScheduler
+	priorities   *PriorityGuard
+	platforms    *ImagePlatformResolver
+	limits       *LimitRangeAdmission
+	canaries     *CanaryController
+	routing      *TopologyRouter
+	resyncPeriod time.Duration
+}
+
+// NewControllerManager - 새 Controller Manager 생성
+func NewControllerManager(logger *logrus.Logger, k3sMgr *K3sManager) *ControllerManager {
+	canaries := NewCanaryController(logger, k3sMgr)
+	return &ControllerManager{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		topology:     NewTopologyScheduler(logger, k3sMgr),
+		priorities:   NewPriorityGuard(logger, k3sMgr),
+		platforms:    NewImagePlatformResolver(logger, k3sMgr.workerPool),
+		limits:       NewLimitRangeAdmission(logger, k3sMgr),
+		canaries:     canaries,
+		routing:      NewTopologyRouter(logger, k3sMgr, canaries),
+		resyncPeriod: 10 * time.Second,
+	}
+}
+
+// Start - 주기적 reconcile 루프 시작
+func (cm *ControllerManager) Start(ctx context.Context) {
+	cm.logger.Info("🎛️ Starting Controller Manager...")
+
+	ticker := time.NewTicker(cm.resyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cm.logger.Info("🛑 Controller Manager stopping...")
+			return
+		case <-ticker.C:
+			if !cm.k3sMgr.IsRunning() {
+				continue
+			}
+			cm.priorities.Reconcile()
+			cm.canaries.ReconcileAll()
+			cm.routing.ReconcileAll()
+		}
+	}
+}
+
+/*
+admissionPayload - 어드미션 단계가 공유하는 요청 본문
+
+단계마다 본문을 map으로 다시 파싱하고 바꾼 뒤 다시 인코딩하면 쓰기 요청 하나에 파싱 네 번,
+인코딩 세 번이 생깁니다. 첫 단계가 필요할 때 한 번만 파싱해 공유하고,
+어느 단계든 바꿨으면 모든 단계를 통과한 뒤 한 번만 인코딩합니다.
+*/
+type admissionPayload struct {
+	raw     string
+	object  map[string]interface{}
+	parsed  bool
+	changed bool
+}
+
+// Object - 파싱된 본문 (비어 있거나 JSON 객체가 아니면 nil, YAML 등은 그대로 전달)
+func (p *admissionPayload) Object() map[string]interface{} {
+	if !p.parsed {
+		p.parsed = true
+		if p.raw != "" && json.Unmarshal([]byte(p.raw), &p.object) != nil {
+			p.object = nil
+		}
+	}
+	return p.object
+}
+
+// MarkChanged - Object를 고친 단계가 호출
+func (p *admissionPayload) MarkChanged() {
+	p.changed = true
+}
+
+// commit - 바뀐 본문을 요청에 반영
+func (p *admissionPayload) commit(request *K8sAPIRequest) error {
+	if !p.changed {
+		return nil
+	}
+	encoded, err := json.Marshal(p.object)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %v", err)
+	}
+	request.Payload = string(encoded)
+	return nil
+}
+
+// Admit - 실행 전 요청 검증(critical 보호)과 payload 변환 (LimitRange 기본값, 위치 제약, 이미지 플랫폼 등)
+func (cm *ControllerManager) Admit(request *K8sAPIRequest) error {
+	payload := &admissionPayload{raw: request.Payload}
+	if err := cm.priorities.Admit(request, payload); err != nil {
+		return err
+	}
+	if err := cm.limits.Admit(request, payload); err != nil {
+		return err
+	}
+	if err := cm.topology.Admit(request, payload); err != nil {
+		return err
+	}
+	if err := cm.platforms.Admit(request, payload); err != nil {
+		return err
+	}
+	return payload.commit(request)
+}
@@ -0,0 +1,335 @@
+// Cost Estimator - 매니페스트의 리소스 요청량을 온체인 단가표로 환산해 에포크당 SUI 비용과 컨트랙트 가스 예측
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+테넌트가 배포 전에 비용을 가늠할 수 있도록 pricing::PricingTable(PRICING_TABLE_ID)의 단가로 계산합니다.
+
+	POST /api/v1/cost/estimate   {"manifests": [...], "epochs": 1}
+
+  - 리소스: Pod 템플릿이 있는 워크로드는 복제본 × 유효 요청량(cpu/memory), DaemonSet은 활성 워커 수만큼,
+    PersistentVolumeClaim과 StatefulSet volumeClaimTemplates는 storage 요청량
+  - 에포크당 비용: cpu 코어 + 메모리 GiB + 스토리지 GiB + Pod 수 × 각 단가
+  - 가스: 매니페스트마다 submit_k8s_request와 record_api_result 한 번씩 (기준 가스 가격 × 예상 가스 단위)
+    + 요청 payload가 온체인에 남는 저장 비용. 응답이 배치로 기록되면 실제 가스는 더 적습니다.
+*/
+
+const (
+	costPricingTTL         = 5 * time.Minute // 단가표/기준 가스 가격 캐시
+	costMaxBodyBytes       = 1 << 20
+	costMaxEpochs          = 10000
+	costRequestOverhead    = 512 // payload 외에 요청마다 저장되는 필드 (바이트, 대략치)
+	costDefaultSubmitGas   = 2000
+	costDefaultResultGas   = 2000
+	costDefaultStorageMist = 7600 // 바이트당 저장 비용 (storage price 76 × 100 단위)
+	mistPerSUI             = 1e9
+)
+
+// ChainPricing - 온체인 단가표 (에포크당 MIST)
+type ChainPricing struct {
+	ObjectID            string `json:"object_id"`
+	CPUCoreEpochMist    uint64 `json:"cpu_core_epoch_mist"`
+	MemoryGiBEpochMist  uint64 `json:"memory_gib_epoch_mist"`
+	StorageGiBEpochMist uint64 `json:"storage_gib_epoch_mist"`
+	PodEpochMist        uint64 `json:"pod_epoch_mist"`
+	Version             uint64 `json:"version"`
+	ReferenceGasPrice   uint64 `json:"reference_gas_price"`
+}
+
+// CostLineItem - 매니페스트 하나의 예상 리소스와 비용
+type CostLineItem struct {
+	Kind             string `json:"kind"`
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	Pods             int    `json:"pods"`
+	CPUMillis        int64  `json:"cpu_millis"`
+	MemoryMB         int64  `json:"memory_mb"`
+	StorageMB        int64  `json:"storage_mb"`
+	CostPerEpochMist uint64 `json:"cost_per_epoch_mist"`
+	GasMist          uint64 `json:"gas_mist"`
+}
+
+// CostGas - 컨트랙트 왕복 가스 내역
+type CostGas struct {
+	RoundTrips      int    `json:"round_trips"`
+	ComputationMist uint64 `json:"computation_mist"`
+	StorageMist     uint64 `json:"storage_mist"`
+	TotalMist       uint64 `json:"total_mist"`
+}
+
+// CostEstimate - 비용 추정 결과
+type CostEstimate struct {
+	Items            []CostLineItem `json:"items"`
+	Pods             int            `json:"pods"`
+	CPUMillis        int64          `json:"cpu_millis"`
+	MemoryMB         int64          `json:"memory_mb"`
+	StorageMB        int64          `json:"storage_mb"`
+	CostPerEpochMist uint64         `json:"cost_per_epoch_mist"`
+	Epochs           int            `json:"epochs"`
+	Gas              CostGas        `json:"gas"`
+	TotalMist        uint64         `json:"total_mist"` // 에포크당 비용 × epochs + 가스
+	TotalSUI         float64        `json:"total_sui"`
+	Pricing          ChainPricing   `json:"pricing"`
+	PricingAge       float64        `json:"pricing_age_seconds"`
+	EstimatedAt      time.Time      `json:"estimated_at"`
+}
+
+// CostEstimator - 온체인 단가 조회와 비용 계산
+type CostEstimator struct {
+	logger      *logrus.Logger
+	sui         *SuiIntegration
+	workerPool  *WorkerPool
+	pricingID   string
+	submitGas   uint64
+	resultGas   uint64
+	storageMist uint64
+
+	mutex     sync.Mutex
+	pricing   *ChainPricing
+	fetchedAt time.Time
+}
+
+// NewCostEstimator - 새 비용 추정기 생성 (PRICING_TABLE_ID가 없으면 API가 503)
+func NewCostEstimator(logger *logrus.Logger, sui *SuiIntegration, workerPool *WorkerPool) *CostEstimator {
+	return &CostEstimator{
+		logger:      logger,
+		sui:         sui,
+		workerPool:  workerPool,
+		pricingID:   getEnvOrDefault("PRICING_TABLE_ID", ""),
+		submitGas:   uint64(envCount("NAUTILUS_COST_SUBMIT_GAS_UNITS", costDefaultSubmitGas)),
+		resultGas:   uint64(envCount("NAUTILUS_COST_RESULT_GAS_UNITS", costDefaultResultGas)),
+		storageMist: uint64(envCount("NAUTILUS_COST_STORAGE_MIST_PER_BYTE", costDefaultStorageMist)),
+	}
+}
+
+// currentPricing - 캐시된 단가표와 기준 가스 가격 (TTL이 지나면 다시 조회)
+func (c *CostEstimator) currentPricing() (ChainPricing, time.Time, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pricing != nil && time.Since(c.fetchedAt) < costPricingTTL {
+		return *c.pricing, c.fetchedAt, nil
+	}
+
+	var object struct {
+		Data struct {
+			Content struct {
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	options := map[string]bool{"showContent": true}
+	if err := c.sui.rpcCall("sui_getObject", []interface{}{c.pricingID, options}, &object); err != nil {
+		return ChainPricing{}, time.Time{}, fmt.Errorf("failed to fetch pricing table: %v", err)
+	}
+	fields := object.Data.Content.Fields
+	if fields == nil {
+		return ChainPricing{}, time.Time{}, fmt.Errorf("pricing table %s not found", c.pricingID)
+	}
+
+	pricing := ChainPricing{ObjectID: c.pricingID}
+	for _, field := range []struct {
+		name  string
+		value *uint64
+	}{
+		{"cpu_core_epoch_mist", &pricing.CPUCoreEpochMist},
+		{"memory_gib_epoch_mist", &pricing.MemoryGiBEpochMist},
+		{"storage_gib_epoch_mist", &pricing.StorageGiBEpochMist},
+		{"pod_epoch_mist", &pricing.PodEpochMist},
+		{"version", &pricing.Version},
+	} {
+		// Sui JSON-RPC는 u64를 문자열로 반환
+		value, err := strconv.ParseUint(fmt.Sprint(fields[field.name]), 10, 64)
+		if err != nil {
+			return ChainPricing{}, time.Time{}, fmt.Errorf("pricing table has invalid %s: %v", field.name, err)
+		}
+		*field.value = value
+	}
+
+	var gasPrice string
+	if err := c.sui.rpcCall("suix_getReferenceGasPrice", []interface{}{}, &gasPrice); err != nil {
+		return ChainPricing{}, time.Time{}, fmt.Errorf("failed to fetch reference gas price: %v", err)
+	}
+	price, err := strconv.ParseUint(gasPrice, 10, 64)
+	if err != nil {
+		return ChainPricing{}, time.Time{}, fmt.Errorf("invalid reference gas price %q", gasPrice)
+	}
+	pricing.ReferenceGasPrice = price
+
+	c.pricing, c.fetchedAt = &pricing, time.Now()
+	return pricing, c.fetchedAt, nil
+}
+
+// measureManifest - 매니페스트 하나의 Pod 수와 총 cpu/memory/storage 요청량
+func (c *CostEstimator) measureManifest(obj map[string]interface{}) (CostLineItem, error) {
+	kind, _ := obj["kind"].(string)
+	if kind == "" {
+		return CostLineItem{}, fmt.Errorf("kind is required")
+	}
+	meta := objectMeta(obj)
+	item := CostLineItem{Kind: kind, Namespace: "default"}
+	item.Name, _ = meta["name"].(string)
+	if namespace, _ := meta["namespace"].(string); namespace != "" {
+		item.Namespace = namespace
+	}
+	spec, _ := obj["spec"].(map[string]interface{})
+
+	if podSpec, _ := podTemplateOf(obj); podSpec != nil {
+		pods, err := expandPods([]map[string]interface{}{obj})
+		if err != nil {
+			return CostLineItem{}, fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "pods[0]: "))
+		}
+		copies := 1
+		if kind == "DaemonSet" {
+			// DaemonSet은 노드마다 하나 (워커 수 기준)
+			copies = c.workerPool.GetWorkerStats()["active"]
+		}
+		for _, pod := range pods {
+			item.Pods += copies
+			item.CPUMillis += pod.cpu * int64(copies)
+			item.MemoryMB += pod.memory * int64(copies) / (1 << 20)
+		}
+	}
+
+	var claims []interface{}
+	switch kind {
+	case "PersistentVolumeClaim":
+		claims = []interface{}{obj}
+	case "StatefulSet":
+		claims, _ = spec["volumeClaimTemplates"].([]interface{})
+	}
+	replicas := int64(1)
+	if kind == "StatefulSet" {
+		if value, ok := spec["replicas"].(float64); ok {
+			replicas = int64(value)
+		}
+	}
+	for _, claim := range claims {
+		claimObj, _ := claim.(map[string]interface{})
+		claimSpec, _ := claimObj["spec"].(map[string]interface{})
+		resources, _ := claimSpec["resources"].(map[string]interface{})
+		requests, _ := resources["requests"].(map[string]interface{})
+		storage, _ := requests["storage"].(string)
+		bytes, err := parseMemoryBytes(storage)
+		if err != nil {
+			return CostLineItem{}, fmt.Errorf("invalid storage request: %v", err)
+		}
+		item.StorageMB += bytes * replicas / (1 << 20)
+	}
+	return item, nil
+}
+
+// measureManifests - 요청 매니페스트 검증과 리소스 측정 (단가 조회 전에 입력 오류를 먼저 돌려줌)
+func (c *CostEstimator) measureManifests(manifests []map[string]interface{}) ([]CostLineItem, error) {
+	items := make([]CostLineItem, 0, len(manifests))
+	pods := 0
+	for i, obj := range manifests {
+		item, err := c.measureManifest(obj)
+		if err != nil {
+			return nil, fmt.Errorf("manifests[%d]: %v", i, err)
+		}
+		if pods += item.Pods; pods > scheduleSimMaxPods {
+			return nil, fmt.Errorf("at most %d pods can be estimated per request", scheduleSimMaxPods)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Estimate - 측정한 매니페스트의 에포크당 비용과 배포 가스 계산
+func (c *CostEstimator) Estimate(manifests []map[string]interface{}, items []CostLineItem, epochs int) (*CostEstimate, error) {
+	pricing, fetchedAt, err := c.currentPricing()
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &CostEstimate{Items: []CostLineItem{}, Epochs: epochs, Pricing: pricing, EstimatedAt: time.Now()}
+	estimate.PricingAge = time.Since(fetchedAt).Seconds()
+	for i, item := range items {
+		// 에포크당 비용 (부분 코어/GiB는 비례, MIST 단위 올림)
+		perEpoch := float64(item.CPUMillis)/1000*float64(pricing.CPUCoreEpochMist) +
+			float64(item.MemoryMB)/1024*float64(pricing.MemoryGiBEpochMist) +
+			float64(item.StorageMB)/1024*float64(pricing.StorageGiBEpochMist) +
+			float64(item.Pods)*float64(pricing.PodEpochMist)
+		item.CostPerEpochMist = uint64(math.Ceil(perEpoch))
+
+		// 컨트랙트 왕복: 요청 제출 + 결과 기록, payload는 완료 후에도 온체인에 남음
+		payload, _ := json.Marshal(manifests[i])
+		computation := (c.submitGas + c.resultGas) * pricing.ReferenceGasPrice
+		storage := uint64(len(payload)+costRequestOverhead) * c.storageMist
+		item.GasMist = computation + storage
+
+		estimate.Items = append(estimate.Items, item)
+		estimate.Pods += item.Pods
+		estimate.CPUMillis += item.CPUMillis
+		estimate.MemoryMB += item.MemoryMB
+		estimate.StorageMB += item.StorageMB
+		estimate.CostPerEpochMist += item.CostPerEpochMist
+		estimate.Gas.RoundTrips++
+		estimate.Gas.ComputationMist += computation
+		estimate.Gas.StorageMist += storage
+		estimate.Gas.TotalMist += item.GasMist
+	}
+
+	estimate.TotalMist = estimate.CostPerEpochMist*uint64(epochs) + estimate.Gas.TotalMist
+	estimate.TotalSUI = float64(estimate.TotalMist) / mistPerSUI
+	return estimate, nil
+}
+
+// handleEstimate - POST /api/v1/cost/estimate
+func (c *CostEstimator) handleEstimate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Manifests []map[string]interface{} `json:"manifests"`
+		Epochs    int                      `json:"epochs"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, costMaxBodyBytes)).Decode(&body); err != nil {
+		http.Error(w, "Invalid cost estimate request", http.StatusBadRequest)
+		return
+	}
+	if len(body.Manifests) == 0 {
+		http.Error(w, "manifests is required", http.StatusBadRequest)
+		return
+	}
+	if body.Epochs == 0 {
+		body.Epochs = 1
+	}
+	if body.Epochs < 0 || body.Epochs > costMaxEpochs {
+		http.Error(w, fmt.Sprintf("epochs must be between 1 and %d", costMaxEpochs), http.StatusBadRequest)
+		return
+	}
+	items, err := c.measureManifests(body.Manifests)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if c.pricingID == "" {
+		http.Error(w, "Cost estimation unavailable (PRICING_TABLE_ID not set)", http.StatusServiceUnavailable)
+		return
+	}
+
+	estimate, err := c.Estimate(body.Manifests, items, body.Epochs)
+	if err != nil {
+		c.logger.Warnf("⚠️ Cost estimate failed: %v", err)
+		http.Error(w, "Failed to read on-chain pricing: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	c.logger.Debugf("💰 Estimated %d manifests: %d MIST/epoch, %d MIST gas", len(estimate.Items), estimate.CostPerEpochMist, estimate.Gas.TotalMist)
+	writeClientJSON(w, estimate)
+}
@@ -0,0 +1,721 @@
+// CSR API - certificates.k8s.io/v1 CertificateSigningRequest 처리 및 kubelet 인증서 자동 승인
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+실제 k3s agent의 kubelet이 TLS 부트스트랩으로 보내는 CSR을 마스터가 직접 받아
+TEE CA(KubeletCA)로 서명합니다. kubelet은 seal 토큰을 bootstrap 토큰으로 사용합니다.
+
+	GET    /apis/certificates.k8s.io/v1/certificatesigningrequests          목록 (?watch=true, ?fieldSelector=metadata.name=X)
+	POST   /apis/certificates.k8s.io/v1/certificatesigningrequests          생성 (seal 토큰)
+	GET    /apis/certificates.k8s.io/v1/certificatesigningrequests/{name}   조회 (/status 동일)
+	DELETE /apis/certificates.k8s.io/v1/certificatesigningrequests/{name}   삭제
+	PUT    /apis/certificates.k8s.io/v1/certificatesigningrequests/{name}/approval   수동 승인/거절 (관리자 토큰)
+
+자동 승인 정책 (kube-controller-manager의 csrapproving과 같은 기준에 seal 토큰 검증 추가):
+  - 유효한 seal 토큰의 워커이며 slashed 상태가 아님
+  - Subject가 CN=system:node:<토큰 워커의 node ID>, O=system:nodes
+  - kubernetes.io/kube-apiserver-client-kubelet: client auth 용도, SAN 없음
+  - kubernetes.io/kubelet-serving: server auth 용도, DNS SAN은 node ID만, IP SAN 1개 이상 허용, 이메일/URI SAN 없음
+정책에 맞지 않는 CSR은 Pending으로 남고 관리자가 /approval로 처리합니다.
+*/
+
+const (
+	csrAPIPrefix           = "/apis/certificates.k8s.io/"
+	csrAPIVersion          = "certificates.k8s.io/v1"
+	signerKubeletClient    = "kubernetes.io/kube-apiserver-client-kubelet"
+	signerKubeletServing   = "kubernetes.io/kubelet-serving"
+	csrIssuedRetention     = time.Hour      // 발급/거절된 CSR 보관 기간
+	csrPendingRetention    = 24 * time.Hour // 처리되지 않은 CSR 보관 기간
+	csrDefaultWatchTimeout = 30 * time.Minute
+	csrMaxBodyBytes        = 64 * 1024
+	csrMaxTombstones       = 100
+)
+
+// certificateSigningRequest - certificates.k8s.io/v1 CertificateSigningRequest (JSON 형식만 지원)
+type certificateSigningRequest struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Metadata   csrMeta   `json:"metadata"`
+	Spec       csrSpec   `json:"spec"`
+	Status     csrStatus `json:"status"`
+}
+
+type csrMeta struct {
+	Name              string    `json:"name,omitempty"`
+	GenerateName      string    `json:"generateName,omitempty"`
+	UID               string    `json:"uid,omitempty"`
+	ResourceVersion   string    `json:"resourceVersion,omitempty"`
+	CreationTimestamp time.Time `json:"creationTimestamp,omitempty"`
+}
+
+type csrSpec struct {
+	Request           []byte              `json:"request"`
+	SignerName        string              `json:"signerName"`
+	ExpirationSeconds *int32              `json:"expirationSeconds,omitempty"`
+	Usages            []string            `json:"usages,omitempty"`
+	Username          string              `json:"username,omitempty"`
+	UID               string              `json:"uid,omitempty"`
+	Groups            []string            `json:"groups,omitempty"`
+	Extra             map[string][]string `json:"extra,omitempty"`
+}
+
+type csrStatus struct {
+	Conditions  []csrCondition `json:"conditions,omitempty"`
+	Certificate []byte         `json:"certificate,omitempty"`
+}
+
+type csrCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastUpdateTime     time.Time `json:"lastUpdateTime"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// csrRecord - 저장된 CSR과 변경 시점
+type csrRecord struct {
+	csr       certificateSigningRequest
+	nodeID    string // 요청한 워커 (관리자 생성이면 빈 값)
+	createdRV uint64
+	rv        uint64
+	deleted   bool
+}
+
+// csrViewer - 요청자 (관리자는 전체, 워커는 자기 CSR만)
+type csrViewer struct {
+	admin  bool
+	worker *WorkerNode
+}
+
+func (v csrViewer) canSee(record *csrRecord) bool {
+	return v.admin || (v.worker != nil && record.nodeID == v.worker.NodeID)
+}
+
+// CSRAPI - CSR 저장소, 자동 승인기, 서명기
+type CSRAPI struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	ca         *KubeletCA
+	history    *HeartbeatHistory
+	adminToken string
+
+	mutex      sync.Mutex
+	records    map[string]*csrRecord
+	tombstones []*csrRecord
+	version    uint64
+	changed    chan struct{} // 변경 시 닫고 새로 만듦 (watch 알림)
+	counts     map[string]uint64
+}
+
+// NewCSRAPI - 새 CSR API 생성
+func NewCSRAPI(logger *logrus.Logger, workerPool *WorkerPool, ca *KubeletCA) *CSRAPI {
+	return &CSRAPI{
+		logger:     logger,
+		workerPool: workerPool,
+		ca:         ca,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		records:    make(map[string]*csrRecord),
+		changed:    make(chan struct{}),
+		counts:     make(map[string]uint64),
+	}
+}
+
+// writeK8sStatus - Kubernetes Status 오류 응답
+func writeK8sStatus(w http.ResponseWriter, code int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]interface{}{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
+
+func writeK8sObject(w http.ResponseWriter, code int, object interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(object)
+}
+
+// authenticate - 관리자 토큰 또는 seal 토큰
+func (c *CSRAPI) authenticate(r *http.Request) (csrViewer, bool) {
+	token := requestSealToken(r)
+	if token == "" {
+		return csrViewer{}, false
+	}
+	if c.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) == 1 {
+		return csrViewer{admin: true}, true
+	}
+	if worker, ok := c.workerPool.WorkerBySealToken(token); ok {
+		return csrViewer{worker: worker}, true
+	}
+	return csrViewer{}, false
+}
+
+// ServeHTTP - /apis/certificates.k8s.io/ 라우팅
+func (c *CSRAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, csrAPIPrefix), "/")
+	parts := strings.Split(rest, "/")
+
+	// API 탐색 (kubectl get csr)
+	if rest == "" || rest == "v1" {
+		if r.Method != http.MethodGet {
+			writeK8sStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+			return
+		}
+		c.writeDiscovery(w, rest)
+		return
+	}
+
+	viewer, ok := c.authenticate(r)
+	if !ok {
+		writeK8sStatus(w, http.StatusUnauthorized, "Unauthorized", "a valid seal token or admin token is required")
+		return
+	}
+	if parts[0] != "v1" || len(parts) < 2 || parts[1] != "certificatesigningrequests" || len(parts) > 4 {
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		c.handleList(w, r, viewer)
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		c.handleCreate(w, r, viewer)
+	case len(parts) == 3 && r.Method == http.MethodGet, len(parts) == 4 && parts[3] == "status" && r.Method == http.MethodGet:
+		c.handleGet(w, parts[2], viewer)
+	case len(parts) == 3 && r.Method == http.MethodDelete:
+		c.handleDelete(w, parts[2], viewer)
+	case len(parts) == 4 && parts[3] == "approval" && r.Method == http.MethodPut:
+		c.handleApproval(w, r, parts[2], viewer)
+	case len(parts) == 4 && parts[3] != "approval" && parts[3] != "status":
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "unknown subresource "+parts[3])
+	default:
+		writeK8sStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method "+r.Method+" is not supported on this resource")
+	}
+}
+
+// writeDiscovery - APIGroup / APIResourceList
+func (c *CSRAPI) writeDiscovery(w http.ResponseWriter, rest string) {
+	if rest == "" {
+		version := map[string]string{"groupVersion": csrAPIVersion, "version": "v1"}
+		writeK8sObject(w, http.StatusOK, map[string]interface{}{
+			"kind": "APIGroup", "apiVersion": "v1", "name": "certificates.k8s.io",
+			"versions": []map[string]string{version}, "preferredVersion": version,
+		})
+		return
+	}
+	writeK8sObject(w, http.StatusOK, map[string]interface{}{
+		"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": csrAPIVersion,
+		"resources": []map[string]interface{}{
+			{"name": "certificatesigningrequests", "singularName": "certificatesigningrequest", "namespaced": false,
+				"kind": "CertificateSigningRequest", "shortNames": []string{"csr"},
+				"verbs": []string{"create", "delete", "get", "list", "watch"}},
+			{"name": "certificatesigningrequests/approval", "singularName": "", "namespaced": false,
+				"kind": "CertificateSigningRequest", "verbs": []string{"update"}},
+			{"name": "certificatesigningrequests/status", "singularName": "", "namespaced": false,
+				"kind": "CertificateSigningRequest", "verbs": []string{"get"}},
+		},
+	})
+}
+
+// bumpLocked - resourceVersion 증가 및 watch 알림
+func (c *CSRAPI) bumpLocked(record *csrRecord) {
+	c.version++
+	record.rv = c.version
+	record.csr.Metadata.ResourceVersion = strconv.FormatUint(c.version, 10)
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// handleCreate - CSR 생성 후 자동 승인 정책 적용
+func (c *CSRAPI) handleCreate(w http.ResponseWriter, r *http.Request, viewer csrViewer) {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		writeK8sStatus(w, http.StatusUnsupportedMediaType, "UnsupportedMediaType",
+			"only application/json is supported, run the kubelet with --kube-api-content-type=application/json")
+		return
+	}
+
+	var csr certificateSigningRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, csrMaxBodyBytes)).Decode(&csr); err != nil {
+		writeK8sStatus(w, http.StatusBadRequest, "BadRequest", "invalid CertificateSigningRequest: "+err.Error())
+		return
+	}
+	request, err := parseCSRRequest(csr.Spec.Request)
+	if err != nil {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", err.Error())
+		return
+	}
+	if csr.Spec.SignerName != signerKubeletClient && csr.Spec.SignerName != signerKubeletServing {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid",
+			fmt.Sprintf("spec.signerName %q is not issued by this master (supported: %s, %s)", csr.Spec.SignerName, signerKubeletClient, signerKubeletServing))
+		return
+	}
+	if csr.Metadata.Name == "" {
+		if csr.Metadata.GenerateName == "" {
+			writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", "metadata.name or metadata.generateName is required")
+			return
+		}
+		csr.Metadata.Name = csr.Metadata.GenerateName + randomSuffix(5)
+	}
+
+	// 요청자 정보는 인증 결과로 덮어씀
+	csr.APIVersion = csrAPIVersion
+	csr.Kind = "CertificateSigningRequest"
+	csr.Metadata.UID = randomSuffix(16)
+	csr.Metadata.CreationTimestamp = time.Now().UTC().Truncate(time.Second)
+	csr.Status = csrStatus{}
+	csr.Spec.Groups = []string{"system:authenticated"}
+	csr.Spec.Extra = nil
+	record := &csrRecord{}
+	if viewer.admin {
+		csr.Spec.Username = "nautilus:admin"
+		csr.Spec.UID = ""
+	} else {
+		record.nodeID = viewer.worker.NodeID
+		csr.Spec.Username = "system:bootstrap:" + viewer.worker.NodeID
+		csr.Spec.Groups = append(csr.Spec.Groups, "system:bootstrappers")
+		csr.Spec.UID = viewer.worker.NodeID
+	}
+	record.csr = csr
+
+	c.mutex.Lock()
+	c.pruneLocked()
+	if existing, exists := c.records[csr.Metadata.Name]; exists && !existing.deleted {
+		c.mutex.Unlock()
+		writeK8sStatus(w, http.StatusConflict, "AlreadyExists", "certificatesigningrequests \""+csr.Metadata.Name+"\" already exists")
+		return
+	}
+	c.records[csr.Metadata.Name] = record
+	c.bumpLocked(record)
+	record.createdRV = record.rv
+
+	if viewer.worker != nil {
+		observedIP := c.workerPool.ObservedIP(viewer.worker.NodeID)
+		if reason := kubeletCSRPolicy(&record.csr, request, viewer.worker, observedIP); reason != "" {
+			c.counts["pending"]++
+			c.logger.Warnf("⏸️ CSR %s from %s left pending for manual approval: %s", csr.Metadata.Name, viewer.worker.NodeID, reason)
+		} else {
+			c.counts["auto_approved"]++
+			c.approveLocked(record, request, "AutoApproved", "Auto approving kubelet certificate after seal token validation")
+		}
+	}
+	response := record.csr
+	c.mutex.Unlock()
+
+	writeK8sObject(w, http.StatusCreated, response)
+}
+
+// approveLocked - Approved 조건 추가 후 TEE CA로 서명
+func (c *CSRAPI) approveLocked(record *csrRecord, request *x509.CertificateRequest, reason, message string) {
+	now := time.Now().UTC().Truncate(time.Second)
+	record.csr.Status.Conditions = append(record.csr.Status.Conditions, csrCondition{
+		Type: "Approved", Status: "True", Reason: reason, Message: message, LastUpdateTime: now, LastTransitionTime: now,
+	})
+
+	extUsages, keyUsage := csrKeyUsages(record.csr.Spec.Usages)
+	certificate, err := c.ca.Sign(request, extUsages, keyUsage, record.csr.Spec.ExpirationSeconds)
+	if err != nil {
+		c.counts["failed"]++
+		record.csr.Status.Conditions = append(record.csr.Status.Conditions, csrCondition{
+			Type: "Failed", Status: "True", Reason: "SigningError", Message: err.Error(), LastUpdateTime: now, LastTransitionTime: now,
+		})
+		c.logger.Errorf("❌ Failed to sign CSR %s: %v", record.csr.Metadata.Name, err)
+	} else {
+		c.counts["issued"]++
+		record.csr.Status.Certificate = certificate
+		c.logger.Infof("📜 Issued %s certificate for %s (CSR %s)", record.csr.Spec.SignerName, request.Subject.CommonName, record.csr.Metadata.Name)
+		if record.nodeID != "" {
+			c.history.RecordEvent(record.nodeID, "kubelet_cert", record.csr.Spec.SignerName+" issued ("+record.csr.Metadata.Name+")")
+		}
+	}
+	c.bumpLocked(record)
+}
+
+// handleApproval - PUT .../{name}/approval (관리자가 status.conditions에 Approved 또는 Denied 추가)
+func (c *CSRAPI) handleApproval(w http.ResponseWriter, r *http.Request, name string, viewer csrViewer) {
+	if !viewer.admin {
+		writeK8sStatus(w, http.StatusForbidden, "Forbidden", "approving certificate signing requests requires the admin token")
+		return
+	}
+
+	var update certificateSigningRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, csrMaxBodyBytes)).Decode(&update); err != nil {
+		writeK8sStatus(w, http.StatusBadRequest, "BadRequest", "invalid CertificateSigningRequest: "+err.Error())
+		return
+	}
+	var decision *csrCondition
+	for i := range update.Status.Conditions {
+		condition := update.Status.Conditions[i]
+		if (condition.Type == "Approved" || condition.Type == "Denied") && condition.Status != "False" {
+			decision = &condition
+		}
+	}
+	if decision == nil {
+		writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", "status.conditions must contain an Approved or Denied condition")
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, exists := c.records[name]
+	if !exists || record.deleted {
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "certificatesigningrequests \""+name+"\" not found")
+		return
+	}
+	if csrDecided(&record.csr) {
+		writeK8sStatus(w, http.StatusConflict, "Conflict", "certificatesigningrequests \""+name+"\" is already approved or denied")
+		return
+	}
+
+	reason := decision.Reason
+	if reason == "" {
+		reason = "AdminApproval"
+	}
+	if decision.Type == "Denied" {
+		now := time.Now().UTC().Truncate(time.Second)
+		record.csr.Status.Conditions = append(record.csr.Status.Conditions, csrCondition{
+			Type: "Denied", Status: "True", Reason: reason, Message: decision.Message, LastUpdateTime: now, LastTransitionTime: now,
+		})
+		c.counts["denied"]++
+		c.bumpLocked(record)
+		c.logger.Infof("🚫 CSR %s denied by admin", name)
+	} else {
+		request, err := parseCSRRequest(record.csr.Spec.Request)
+		if err != nil {
+			writeK8sStatus(w, http.StatusUnprocessableEntity, "Invalid", err.Error())
+			return
+		}
+		c.counts["approved"]++
+		c.approveLocked(record, request, reason, decision.Message)
+	}
+	writeK8sObject(w, http.StatusOK, record.csr)
+}
+
+// handleGet - 단일 CSR 조회
+func (c *CSRAPI) handleGet(w http.ResponseWriter, name string, viewer csrViewer) {
+	c.mutex.Lock()
+	record, exists := c.records[name]
+	visible := exists && !record.deleted && viewer.canSee(record)
+	var csr certificateSigningRequest
+	if visible {
+		csr = record.csr
+	}
+	c.mutex.Unlock()
+
+	if !visible {
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "certificatesigningrequests \""+name+"\" not found")
+		return
+	}
+	writeK8sObject(w, http.StatusOK, csr)
+}
+
+// handleDelete - CSR 삭제 (관리자 또는 요청한 워커)
+func (c *CSRAPI) handleDelete(w http.ResponseWriter, name string, viewer csrViewer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, exists := c.records[name]
+	if !exists || record.deleted || !viewer.canSee(record) {
+		writeK8sStatus(w, http.StatusNotFound, "NotFound", "certificatesigningrequests \""+name+"\" not found")
+		return
+	}
+	c.deleteLocked(record)
+	writeK8sObject(w, http.StatusOK, map[string]interface{}{
+		"kind": "Status", "apiVersion": "v1", "metadata": map[string]interface{}{}, "status": "Success",
+		"details": map[string]string{"name": name, "group": "certificates.k8s.io", "kind": "certificatesigningrequests"},
+	})
+}
+
+// deleteLocked - 삭제 후 watch가 DELETED를 보낼 수 있도록 tombstone 보관
+func (c *CSRAPI) deleteLocked(record *csrRecord) {
+	delete(c.records, record.csr.Metadata.Name)
+	record.deleted = true
+	c.bumpLocked(record)
+	c.tombstones = append(c.tombstones, record)
+	if len(c.tombstones) > csrMaxTombstones {
+		c.tombstones = c.tombstones[len(c.tombstones)-csrMaxTombstones:]
+	}
+}
+
+// pruneLocked - 오래된 CSR 정리 (kube-controller-manager의 csrcleaner와 같은 보관 기간)
+func (c *CSRAPI) pruneLocked() {
+	now := time.Now()
+	for _, record := range c.records {
+		age := now.Sub(record.csr.Metadata.CreationTimestamp)
+		if (csrDecided(&record.csr) && age > csrIssuedRetention) || age > csrPendingRetention {
+			c.deleteLocked(record)
+		}
+	}
+}
+
+// csrSelector - ?fieldSelector=metadata.name=X
+func csrSelector(r *http.Request) (string, error) {
+	selector := r.URL.Query().Get("fieldSelector")
+	if selector == "" {
+		return "", nil
+	}
+	for _, prefix := range []string{"metadata.name=", "metadata.name=="} {
+		if name := strings.TrimPrefix(selector, prefix); name != selector && !strings.ContainsAny(name, ",=!") {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported fieldSelector %q (only metadata.name is supported)", selector)
+}
+
+// handleList - 목록 또는 watch
+func (c *CSRAPI) handleList(w http.ResponseWriter, r *http.Request, viewer csrViewer) {
+	name, err := csrSelector(r)
+	if err != nil {
+		writeK8sStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	matches := func(record *csrRecord) bool {
+		return viewer.canSee(record) && (name == "" || record.csr.Metadata.Name == name)
+	}
+
+	if watch := r.URL.Query().Get("watch"); watch == "true" || watch == "1" {
+		since, _ := strconv.ParseUint(r.URL.Query().Get("resourceVersion"), 10, 64)
+		c.watch(w, r, matches, since)
+		return
+	}
+
+	c.mutex.Lock()
+	items := []certificateSigningRequest{}
+	for _, record := range c.records {
+		if matches(record) {
+			items = append(items, record.csr)
+		}
+	}
+	version := c.version
+	c.mutex.Unlock()
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Metadata.Name < items[j].Metadata.Name })
+	writeK8sObject(w, http.StatusOK, map[string]interface{}{
+		"kind":       "CertificateSigningRequestList",
+		"apiVersion": csrAPIVersion,
+		"metadata":   map[string]string{"resourceVersion": strconv.FormatUint(version, 10)},
+		"items":      items,
+	})
+}
+
+/*
+watch - resourceVersion 이후 변경을 JSON watch 이벤트로 전송
+
+resourceVersion이 없거나 0이면 현재 객체를 ADDED로 먼저 보냅니다 (Kubernetes와 동일).
+*/
+func (c *CSRAPI) watch(w http.ResponseWriter, r *http.Request, matches func(*csrRecord) bool, since uint64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeK8sStatus(w, http.StatusInternalServerError, "InternalError", "streaming is not supported")
+		return
+	}
+
+	timeout := csrDefaultWatchTimeout
+	if seconds, err := strconv.Atoi(r.URL.Query().Get("timeoutSeconds")); err == nil && seconds > 0 && time.Duration(seconds)*time.Second < timeout {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+
+	for {
+		c.mutex.Lock()
+		type watchEvent struct {
+			Type   string                    `json:"type"`
+			Object certificateSigningRequest `json:"object"`
+			rv     uint64
+		}
+		var events []watchEvent
+		for _, record := range c.records {
+			if record.rv > since && matches(record) {
+				eventType := "MODIFIED"
+				if record.createdRV > since {
+					eventType = "ADDED"
+				}
+				events = append(events, watchEvent{Type: eventType, Object: record.csr, rv: record.rv})
+			}
+		}
+		for _, record := range c.tombstones {
+			if record.rv > since && record.createdRV <= since && matches(record) {
+				events = append(events, watchEvent{Type: "DELETED", Object: record.csr, rv: record.rv})
+			}
+		}
+		since = c.version
+		changed := c.changed
+		c.mutex.Unlock()
+
+		sort.Slice(events, func(i, j int) bool { return events[i].rv < events[j].rv })
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			return
+		case <-changed:
+		}
+	}
+}
+
+// parseCSRRequest - spec.request PEM 파싱 및 서명 검증
+func parseCSRRequest(data []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("spec.request must be a PEM-encoded CERTIFICATE REQUEST")
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("spec.request is not a valid certificate request: %v", err)
+	}
+	if err := request.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("spec.request signature is invalid: %v", err)
+	}
+	return request, nil
+}
+
+// kubeletCSRPolicy - 자동 승인 조건 확인 (통과하면 빈 문자열, 아니면 보류 사유)
+// serving 인증서의 IP SAN은 하트비트에서 관측한 워커 IP만 허용 (관측 전이면 수동 승인 대기)
+func kubeletCSRPolicy(csr *certificateSigningRequest, request *x509.CertificateRequest, worker *WorkerNode, observedIP string) string {
+	if worker.Status == "slashed" {
+		return "worker is slashed"
+	}
+	if request.Subject.CommonName != "system:node:"+worker.NodeID {
+		return fmt.Sprintf("subject CN %q does not match the seal token's node %q", request.Subject.CommonName, worker.NodeID)
+	}
+	if len(request.Subject.Organization) != 1 || request.Subject.Organization[0] != "system:nodes" {
+		return "subject organization must be exactly system:nodes"
+	}
+	if len(request.EmailAddresses) > 0 || len(request.URIs) > 0 {
+		return "email and URI subject alternative names are not allowed"
+	}
+
+	required := "client auth"
+	if csr.Spec.SignerName == signerKubeletServing {
+		required = "server auth"
+	}
+	allowed := map[string]bool{"digital signature": true, "key encipherment": true, required: true}
+	for _, usage := range csr.Spec.Usages {
+		if !allowed[usage] {
+			return fmt.Sprintf("usage %q is not allowed for %s", usage, csr.Spec.SignerName)
+		}
+	}
+	if !containsString(csr.Spec.Usages, required) {
+		return fmt.Sprintf("%s requires the %q usage", csr.Spec.SignerName, required)
+	}
+
+	if csr.Spec.SignerName == signerKubeletClient {
+		if len(request.DNSNames) > 0 || len(request.IPAddresses) > 0 {
+			return "kubelet client certificates must not contain subject alternative names"
+		}
+		return ""
+	}
+	if len(request.DNSNames) == 0 && len(request.IPAddresses) == 0 {
+		return "kubelet serving certificates need at least one DNS or IP subject alternative name"
+	}
+	for _, name := range request.DNSNames {
+		if name != worker.NodeID {
+			return fmt.Sprintf("DNS name %q does not match node %q", name, worker.NodeID)
+		}
+	}
+	for _, ip := range request.IPAddresses {
+		if observedIP == "" {
+			return fmt.Sprintf("IP address %s cannot be checked before the worker's first heartbeat", ip)
+		}
+		if !ip.Equal(net.ParseIP(observedIP)) {
+			return fmt.Sprintf("IP address %s does not match the worker's observed address %s", ip, observedIP)
+		}
+	}
+	return ""
+}
+
+// csrKeyUsages - spec.usages를 x509 용도로 변환
+func csrKeyUsages(usages []string) ([]x509.ExtKeyUsage, x509.KeyUsage) {
+	var extUsages []x509.ExtKeyUsage
+	var keyUsage x509.KeyUsage
+	for _, usage := range usages {
+		switch usage {
+		case "client auth":
+			extUsages = append(extUsages, x509.ExtKeyUsageClientAuth)
+		case "server auth":
+			extUsages = append(extUsages, x509.ExtKeyUsageServerAuth)
+		case "digital signature":
+			keyUsage |= x509.KeyUsageDigitalSignature
+		case "key encipherment":
+			keyUsage |= x509.KeyUsageKeyEncipherment
+		}
+	}
+	return extUsages, keyUsage
+}
+
+// csrDecided - Approved 또는 Denied 조건이 있는지
+func csrDecided(csr *certificateSigningRequest) bool {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == "Approved" || condition.Type == "Denied" {
+			return true
+		}
+	}
+	return false
+}
+
+// randomSuffix - generateName 접미사 (Kubernetes와 같은 모음 없는 문자 집합)
+func randomSuffix(length int) string {
+	const alphabet = "bcdfghjklmnpqrstvwxz2456789"
+	suffix := make([]byte, length)
+	for i := range suffix {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			n = big.NewInt(int64(time.Now().UnixNano() % int64(len(alphabet))))
+		}
+		suffix[i] = alphabet[n.Int64()]
+	}
+	return string(suffix)
+}
+
+// writeMetrics - CSR 처리 메트릭 출력
+func (c *CSRAPI) writeMetrics(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_kubelet_csr_total", "counter", "Kubelet certificate signing requests by outcome")
+	for _, result := range []string{"auto_approved", "pending", "approved", "denied", "issued", "failed"} {
+		writeMetric(w, "nautilus_kubelet_csr_total", map[string]string{"result": result}, float64(c.counts[result]))
+	}
+	writeMetricHeader(w, "nautilus_kubelet_csr_stored", "gauge", "Certificate signing requests currently stored")
+	writeMetric(w, "nautilus_kubelet_csr_stored", nil, float64(len(c.records)))
+}
@@ -0,0 +1,198 @@
+// Dashboard - 운영자용 내장 웹 대시보드 (노드, 스테이크, Pod, 이벤트, 증명)
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+대시보드는 정적 파일(dashboard/)만 제공하고, 화면의 데이터는 브라우저가 기존 API
+(/api/v1/workers, /api/v1/nodes/health, /api/v1/claims, /api/v1/capacity,
+/api/v1/attestation, /debug/state, /api/v1/stream)를 관리자 토큰으로 직접 호출해 가져옵니다.
+
+	GET  /dashboard/login    로그인 화면
+	POST /dashboard/login    Authorization: Bearer <NAUTILUS_ADMIN_TOKEN> → 세션 쿠키 발급
+	POST /dashboard/logout   세션 종료
+	GET  /dashboard/...      세션 쿠키 또는 Bearer 관리자 토큰 필요
+*/
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+const (
+	dashboardCookie     = "daas_dashboard"
+	dashboardSessionTTL = 8 * time.Hour
+)
+
+// dashboardPublicAssets - 로그인 화면에 필요한 파일 (데이터 없음)
+var dashboardPublicAssets = map[string]bool{
+	"/dashboard/login.js":  true,
+	"/dashboard/style.css": true,
+}
+
+// Dashboard - 대시보드 정적 파일 및 세션 관리
+type Dashboard struct {
+	logger     *logrus.Logger
+	adminToken string
+	static     http.Handler
+	sessions   map[string]time.Time // 세션 ID → 만료 시각
+	mutex      sync.Mutex
+}
+
+// NewDashboard - 새 Dashboard 생성 (NAUTILUS_ADMIN_TOKEN 미설정 시 비활성)
+func NewDashboard(logger *logrus.Logger) *Dashboard {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // embed 경로는 컴파일 시 고정
+	}
+	return &Dashboard{
+		logger:     logger,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		static:     http.StripPrefix("/dashboard/", http.FileServer(http.FS(files))),
+		sessions:   make(map[string]time.Time),
+	}
+}
+
+// setSecurityHeaders - 외부 스크립트/프레임 차단 (데이터는 같은 출처 API와 WebSocket만)
+func setSecurityHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Security-Policy",
+		"default-src 'self'; connect-src 'self' ws: wss:; frame-ancestors 'none'; base-uri 'none'; form-action 'self'")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+// validAdminToken - Bearer 관리자 토큰 확인
+func (d *Dashboard) validAdminToken(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(d.adminToken)) == 1
+}
+
+// validSession - 세션 쿠키 확인 (만료된 세션은 정리)
+func (d *Dashboard) validSession(r *http.Request) bool {
+	cookie, err := r.Cookie(dashboardCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	expiresAt, exists := d.sessions[cookie.Value]
+	if !exists {
+		return false
+	}
+	if time.Now().After(expiresAt) {
+		delete(d.sessions, cookie.Value)
+		return false
+	}
+	return true
+}
+
+// handleDashboard - GET /dashboard/ (세션이 없으면 로그인 화면으로)
+func (d *Dashboard) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if d.adminToken == "" {
+		http.Error(w, "Dashboard disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+
+	setSecurityHeaders(w)
+	if !dashboardPublicAssets[r.URL.Path] && !d.validSession(r) && !d.validAdminToken(r) {
+		http.Redirect(w, r, "/dashboard/login", http.StatusSeeOther)
+		return
+	}
+	d.static.ServeHTTP(w, r)
+}
+
+// handleLogin - GET 로그인 화면, POST 관리자 토큰 확인 후 세션 쿠키 발급
+func (d *Dashboard) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if d.adminToken == "" {
+		http.Error(w, "Dashboard disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	setSecurityHeaders(w)
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		page, err := dashboardFiles.ReadFile("dashboard/login.html")
+		if err != nil {
+			http.Error(w, "Login page missing", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	case http.MethodPost:
+		if !d.validAdminToken(r) {
+			d.logger.Warnf("🚫 Failed dashboard login from %s", r.RemoteAddr)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		sessionBytes := make([]byte, 32)
+		if _, err := rand.Read(sessionBytes); err != nil {
+			http.Error(w, "Failed to create session", http.StatusInternalServerError)
+			return
+		}
+		sessionID := hex.EncodeToString(sessionBytes)
+		expiresAt := time.Now().Add(dashboardSessionTTL)
+
+		d.mutex.Lock()
+		for id, expiry := range d.sessions {
+			if time.Now().After(expiry) {
+				delete(d.sessions, id)
+			}
+		}
+		d.sessions[sessionID] = expiresAt
+		d.mutex.Unlock()
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     dashboardCookie,
+			Value:    sessionID,
+			Path:     "/dashboard/",
+			Expires:  expiresAt,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+		d.logger.Infof("🖥️ Dashboard login from %s", r.RemoteAddr)
+		writeDashboardJSON(w, map[string]interface{}{"status": "success", "expires_at": expiresAt})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleLogout - POST /dashboard/logout
+func (d *Dashboard) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if cookie, err := r.Cookie(dashboardCookie); err == nil {
+		d.mutex.Lock()
+		delete(d.sessions, cookie.Value)
+		d.mutex.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{Name: dashboardCookie, Value: "", Path: "/dashboard/", MaxAge: -1, HttpOnly: true})
+	writeDashboardJSON(w, map[string]interface{}{"status": "success"})
+}
+
+func writeDashboardJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
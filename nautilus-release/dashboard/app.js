@@ -0,0 +1,285 @@
+// K3s-DaaS 대시보드 - 모든 데이터는 기존 마스터 API와 /api/v1/stream에서 가져옴
+"use strict";
+
+const token = sessionStorage.getItem("daasAdminToken");
+if (!token) {
+  location.href = "/dashboard/login";
+}
+
+const REFRESH_MS = 15000;
+const MAX_EVENTS = 200;
+let selectedNode = "";
+let reloadTimer = null;
+
+// el - 텍스트는 항상 textContent로 넣어 API 값이 HTML로 해석되지 않게 함
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined && text !== null) node.textContent = String(text);
+  if (className) node.className = className;
+  return node;
+}
+
+function row(cells) {
+  const tr = el("tr");
+  for (const cell of cells) {
+    const td = el("td");
+    if (cell instanceof Node) td.appendChild(cell);
+    else td.textContent = cell === undefined || cell === null ? "" : String(cell);
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function badge(status) {
+  return el("span", status || "unknown", "badge " + (status || ""));
+}
+
+function fillTable(id, rows) {
+  const body = document.querySelector("#" + id + " tbody");
+  body.replaceChildren(...rows);
+}
+
+function showError(message) {
+  const error = document.getElementById("error");
+  error.textContent = message;
+  error.hidden = !message;
+}
+
+function formatSui(mist) {
+  return (Number(mist || 0) / 1e9).toLocaleString(undefined, { maximumFractionDigits: 3 }) + " SUI";
+}
+
+function formatTime(value) {
+  if (!value) return "";
+  const date = typeof value === "number" ? new Date(value * 1000) : new Date(value);
+  return isNaN(date) || date.getFullYear() < 2000 ? "" : date.toLocaleString();
+}
+
+// api - 관리자 토큰으로 기존 API 호출 (비활성 컴포넌트의 404는 null)
+async function api(path) {
+  const response = await fetch(path, { headers: { Authorization: "Bearer " + token, Accept: "application/json" } });
+  if (response.status === 401) {
+    sessionStorage.removeItem("daasAdminToken");
+    location.href = "/dashboard/login";
+    throw new Error("unauthorized");
+  }
+  if (response.status === 404 || response.status === 403) return null;
+  if (!response.ok) throw new Error(path + ": HTTP " + response.status);
+  const body = await response.json();
+  return body.data !== undefined ? body.data : body;
+}
+
+function randomNonce() {
+  const bytes = new Uint8Array(16);
+  crypto.getRandomValues(bytes);
+  return Array.from(bytes, (b) => b.toString(16).padStart(2, "0")).join("");
+}
+
+async function refresh() {
+  try {
+    const [workers, health, claims, capacity, state, attestation, tenantUsage] = await Promise.all([
+      api("/api/v1/workers"),
+      api("/api/v1/nodes/health"),
+      api("/api/v1/claims"),
+      api("/api/v1/capacity"),
+      api("/debug/state"),
+      api("/api/v1/attestation?nonce=" + randomNonce()),
+      api("/api/v1/admin/tenant-usage"),
+    ]);
+    renderSummary(workers || [], capacity, state);
+    renderNodes(workers || [], health || []);
+    renderStakes(workers || []);
+    renderPods(workers || [], health || [], claims || []);
+    renderAttestation(attestation);
+    renderTenantUsage(tenantUsage);
+    document.getElementById("updated").textContent = "updated " + new Date().toLocaleTimeString();
+    showError("");
+  } catch (err) {
+    if (err.message !== "unauthorized") showError("Refresh failed: " + err.message);
+  }
+}
+
+function card(label, value) {
+  const div = el("div", null, "card");
+  div.append(el("div", value, "value"), el("div", label, "label"));
+  return div;
+}
+
+function renderSummary(workers, capacity, state) {
+  const active = workers.filter((w) => w.status === "active").length;
+  const totalStake = workers.reduce((sum, w) => sum + Number(w.stake_amount || 0), 0);
+  const cards = [card("Active / registered nodes", active + " / " + workers.length), card("Total stake", formatSui(totalStake))];
+  if (state) {
+    const pods = state.storage && state.storage.objects ? state.storage.objects.pods : undefined;
+    cards.push(card("Pods in cluster", pods === undefined ? "n/a" : pods));
+    cards.push(card("K3s", state.k3s_running ? "running" : "stopped"));
+    cards.push(card("Event queue", state.event_queue.depth + " / " + state.event_queue.capacity));
+  }
+  if (capacity) {
+    cards.push(card("Pending pods", capacity.demand.pending_pods));
+    cards.push(card("Reward multiplier", (capacity.reward_multiplier_bps / 10000).toFixed(2) + "x"));
+  }
+  document.getElementById("summary").replaceChildren(...cards);
+}
+
+function renderNodes(workers, health) {
+  const scores = new Map(health.map((h) => [h.node_name, h]));
+  const rows = workers
+    .slice()
+    .sort((a, b) => a.node_id.localeCompare(b.node_id))
+    .map((w) => {
+      const h = scores.get(w.node_id);
+      const healthCell = h ? el("span", h.score.toFixed(1), h.probation ? "badge probation" : "") : "";
+      const tr = row([
+        el("span", w.node_id, "mono"), badge(w.status), w.role, formatSui(w.stake_amount), w.stake_tier,
+        el("span", w.worker_address, "mono"), [w.region, w.zone].filter(Boolean).join("/"), healthCell,
+        formatTime(w.last_heartbeat),
+      ]);
+      if (w.node_id === selectedNode) tr.classList.add("selected");
+      tr.addEventListener("click", () => selectNode(w.node_id === selectedNode ? "" : w.node_id));
+      return tr;
+    });
+  fillTable("nodes", rows);
+}
+
+function renderStakes(workers) {
+  const tiers = new Map();
+  for (const w of workers) {
+    const tier = tiers.get(w.stake_tier) || { count: 0, stake: 0 };
+    tier.count++;
+    tier.stake += Number(w.stake_amount || 0);
+    tiers.set(w.stake_tier, tier);
+  }
+  fillTable("stakes", Array.from(tiers, ([tier, t]) => row([tier || "none", t.count, formatSui(t.stake)])));
+}
+
+function renderPods(workers, health, claims) {
+  const scores = new Map(health.map((h) => [h.node_name, h]));
+  const records = new Map(claims.map((c) => [c.node_id, c]));
+  fillTable("pods", workers.map((w) => {
+    const h = scores.get(w.node_id) || {};
+    const c = records.get(w.node_id);
+    let check = "";
+    if (c) check = c.flagged ? el("span", "flagged", "badge slashed") : el("span", (c.divergence_ratio * 100).toFixed(0) + "% divergent");
+    return row([el("span", w.node_id, "mono"), c ? c.last_reported_pods : "", c ? c.last_assigned_pods : "",
+      h.pods_observed, h.pods_failed, check]);
+  }));
+}
+
+function renderAttestation(doc) {
+  if (!doc) {
+    fillTable("attestation", [row(["", "Attestation unavailable"])]);
+    return;
+  }
+  fillTable("attestation", [
+    row(["Measurement", el("code", doc.measurement)]),
+    row(["TEE device", doc.tee_device]),
+    row(["Subject", doc.subject]),
+    row(["Public key", el("code", doc.public_key)]),
+    row(["Signature", el("code", doc.signature)]),
+    row(["Issued at", formatTime(doc.issued_at)]),
+  ]);
+}
+
+// renderTenantUsage - quota가 있는 리소스를 테넌트별로 (TenantUsageForecasts가 꺼져 있으면 숨김)
+function renderTenantUsage(reports) {
+  document.getElementById("tenant-usage-section").hidden = !reports;
+  if (!reports) return;
+  const rows = [];
+  for (const report of reports) {
+    for (const ns of report.namespaces) {
+      for (const r of ns.resources) {
+        const use = el("span", (r.ratio * 100).toFixed(0) + "%", r.ratio >= 0.9 ? "badge probation" : "");
+        const growth = r.growth_per_day ? (r.growth_per_day > 0 ? "+" : "") + r.growth_per_day.toFixed(1) : "";
+        rows.push(row([el("span", report.tenant, "mono"), ns.namespace, r.resource, r.used + " / " + r.hard, use,
+          growth, r.ratio >= 1 ? "exhausted" : formatTime(r.exhausts_at)]));
+      }
+    }
+  }
+  fillTable("tenant-usage", rows);
+}
+
+function addEvent(listId, event) {
+  const list = document.getElementById(listId);
+  const li = el("li");
+  li.append(el("span", formatTime(event.timestamp) + " ", "muted"), el("span", event.type, "type"));
+  if (event.node_id) li.append(el("span", event.node_id + " ", "mono"));
+  li.append(el("span", describe(event)));
+  list.prepend(li);
+  while (list.children.length > MAX_EVENTS) list.lastChild.remove();
+}
+
+function describe(event) {
+  const d = event.data || {};
+  if (event.type.startsWith("pod.")) {
+    return d.namespace + "/" + d.name + " " + (d.old_phase ? d.old_phase + " → " : "") + d.phase;
+  }
+  if (d.old_status !== undefined) return d.old_status + " → " + d.status;
+  if (d.kind) return d.kind + (d.detail ? ": " + d.detail : "");
+  if (event.type === "capacity.changed") return d.demand ? d.demand.pending_pods + " pending pods" : "";
+  return d.status || "";
+}
+
+// selectNode - 노드를 고르면 해당 노드 타임라인 이벤트로 목록을 채우고 스트림도 그 노드만 표시
+async function selectNode(nodeID) {
+  selectedNode = nodeID;
+  document.getElementById("event-filter").textContent = nodeID ? "(" + nodeID + ")" : "";
+  document.getElementById("events").replaceChildren();
+  document.querySelectorAll("#nodes tbody tr").forEach((tr) => {
+    tr.classList.toggle("selected", tr.firstChild.textContent === nodeID);
+  });
+  if (!nodeID) return;
+
+  try {
+    const timeline = await api("/api/v1/nodes/" + encodeURIComponent(nodeID) + "/timeline?since=24h");
+    for (const event of (timeline && timeline.events) || []) {
+      addEvent("events", { type: "node.event", node_id: nodeID, timestamp: event.timestamp, data: event });
+    }
+  } catch (err) {
+    showError("Timeline failed: " + err.message);
+  }
+}
+
+function scheduleRefresh() {
+  clearTimeout(reloadTimer);
+  reloadTimer = setTimeout(refresh, 1000);
+}
+
+// connectStream - 이벤트 스트림 (브라우저 WebSocket은 헤더를 못 넣으므로 token 쿼리 사용)
+function connectStream() {
+  const scheme = location.protocol === "https:" ? "wss://" : "ws://";
+  const socket = new WebSocket(scheme + location.host + "/api/v1/stream?token=" + encodeURIComponent(token));
+  const state = document.getElementById("stream-state");
+
+  socket.onopen = () => {
+    state.textContent = "live";
+    state.className = "badge up";
+  };
+  socket.onmessage = (message) => {
+    const event = JSON.parse(message.data);
+    if (event.type === "snapshot") return;
+    if (event.type.startsWith("pod.")) {
+      addEvent("pod-events", event);
+      return;
+    }
+    if (!selectedNode || event.node_id === selectedNode || !event.node_id) addEvent("events", event);
+    if (!event.type.startsWith("node.event")) scheduleRefresh();
+  };
+  socket.onclose = () => {
+    state.textContent = "stream offline";
+    state.className = "badge down";
+    setTimeout(connectStream, 5000);
+  };
+}
+
+document.getElementById("logout").addEventListener("click", async () => {
+  await fetch("/dashboard/logout", { method: "POST", credentials: "same-origin" });
+  sessionStorage.removeItem("daasAdminToken");
+  location.href = "/dashboard/login";
+});
+
+if (token) {
+  refresh();
+  connectStream();
+  setInterval(refresh, REFRESH_MS);
+}
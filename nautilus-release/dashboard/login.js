@@ -0,0 +1,22 @@
+// 관리자 토큰으로 세션 쿠키를 받고, API 호출용 토큰은 탭을 닫으면 사라지는 sessionStorage에 보관
+"use strict";
+
+document.getElementById("login").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const token = document.getElementById("token").value.trim();
+  const error = document.getElementById("error");
+  error.hidden = true;
+
+  const response = await fetch("/dashboard/login", {
+    method: "POST",
+    headers: { Authorization: "Bearer " + token },
+    credentials: "same-origin",
+  });
+  if (!response.ok) {
+    error.textContent = response.status === 401 ? "Invalid admin token" : "Sign-in failed (HTTP " + response.status + ")";
+    error.hidden = false;
+    return;
+  }
+  sessionStorage.setItem("daasAdminToken", token);
+  location.href = "/dashboard/";
+});
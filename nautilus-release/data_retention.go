@@ -0,0 +1,579 @@
+// Data Retention - 데이터 종류별 보관 기간 적용과 테넌트 데이터 삭제(서명된 삭제 증명 발급)
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+개인정보 삭제 요청에 대응하기 위한 보관 정책과 테넌트(지갑) 데이터 삭제.
+
+보관 정책 (시간 단위, PUT /api/v1/retention으로 변경하면 retention-policy.json에 저장되어 환경변수보다 우선):
+
+	audit_logs         NAUTILUS_RETENTION_AUDIT_LOGS_HOURS         파일 감사 로그 (기본 2160 = 90일)
+	heartbeat_history  NAUTILUS_RETENTION_HEARTBEAT_HISTORY_HOURS  노드 하트비트 샘플/이벤트 (기본 168)
+	usage_records      NAUTILUS_RETENTION_USAGE_RECORDS_HOURS      테넌트 API 사용량 (기본 720)
+
+삭제 (POST /api/v1/tenants/purge, 관리자 토큰, confirm에 지갑 주소를 다시 적어야 실행):
+  - 네임스페이스: k3s-daas.io/tenant=<지갑> 라벨이 붙은 것과 요청에 적은 것 (시스템 네임스페이스 제외, 안의 Secret 포함)
+  - 다른 네임스페이스의 k3s-daas.io/tenant=<지갑> Secret
+  - 해당 네임스페이스의 보관 Pod 로그와 휴지통 항목, 지갑이 요청했거나 해당 네임스페이스를 대상으로 한 파일 감사 로그
+  - 지갑 소유 워커의 하트비트 기록(콜드 스토리지 아카이브와 요약 포함), API 사용량 기록
+  - 마스터에 저장된 오프체인 기록: 서비스 계정, 위임 권한(부여/수신 모두)
+결과는 TEE 서명 키(RequestSigner)로 서명한 삭제 증명으로 돌려주고 deletion-attestations.json에 남깁니다.
+웹훅/Kafka 감사 싱크처럼 이미 외부로 보낸 데이터와 온체인 기록은 지울 수 없으므로 증명의 skipped에 적습니다.
+*/
+
+const (
+	RetentionAuditLogs        = "audit_logs"
+	RetentionHeartbeatHistory = "heartbeat_history"
+	RetentionUsageRecords     = "usage_records"
+
+	tenantLabel            = "k3s-daas.io/tenant"
+	retentionInterval      = time.Hour
+	maxDeletionAttestation = 1000
+)
+
+// retentionDefaults - 데이터 종류별 기본 보관 시간
+var retentionDefaults = map[string]int{
+	RetentionAuditLogs:        90 * 24,
+	RetentionHeartbeatHistory: 7 * 24,
+	RetentionUsageRecords:     30 * 24,
+}
+
+// protectedNamespaces - 삭제 요청으로 지울 수 없는 네임스페이스
+var protectedNamespaces = map[string]bool{
+	"default": true, "kube-system": true, "kube-public": true, "kube-node-lease": true,
+}
+
+// DeletionItem - 삭제한 데이터 한 종류
+type DeletionItem struct {
+	Class   string   `json:"class"`
+	Count   int      `json:"count"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+// DeletionAttestation - 서명된 삭제 증명 (signature는 signature 필드를 비운 JSON에 대한 Ed25519 서명)
+type DeletionAttestation struct {
+	ID          string         `json:"id"`
+	Tenant      string         `json:"tenant"`
+	Namespaces  []string       `json:"namespaces"`
+	Deleted     []DeletionItem `json:"deleted"`
+	Skipped     []string       `json:"skipped,omitempty"`
+	Errors      []string       `json:"errors,omitempty"`
+	Complete    bool           `json:"complete"`
+	RequestedAt time.Time      `json:"requested_at"`
+	CompletedAt time.Time      `json:"completed_at"`
+	PublicKey   string         `json:"public_key"`
+	Signature   string         `json:"signature,omitempty"`
+}
+
+// DataRetention - 보관 정책 적용기와 테넌트 삭제 처리기
+type DataRetention struct {
+	logger          *logrus.Logger
+	k3sMgr          *K3sManager
+	workerPool      *WorkerPool
+	signer          *RequestSigner
+	adminToken      string
+	policyFile      string
+	attestationFile string
+
+	// 선택 구성요소 (main에서 연결, nil이면 해당 데이터 없음)
+	audit           *AuditLogger
+	history         *HeartbeatHistory
+	historyArchive  *HeartbeatArchive
+	quota           *TenantThrottler
+	podLogs         *PodLogArchive
+	rbac            *RBACAuthorizer
+	serviceAccounts *ServiceAccountIssuer
+	slo             *SLOTracker
+	trash           *TrashBin
+
+	mutex        sync.Mutex
+	policy       map[string]int
+	attestations []DeletionAttestation
+	pruned       map[string]int
+	purges       int
+}
+
+// NewDataRetention - 환경변수와 저장된 정책으로 생성 (서명 키는 RequestSigner의 TEE 키)
+func NewDataRetention(logger *logrus.Logger, k3sMgr *K3sManager, signer *RequestSigner) *DataRetention {
+	d := &DataRetention{
+		logger:          logger,
+		k3sMgr:          k3sMgr,
+		workerPool:      k3sMgr.workerPool,
+		signer:          signer,
+		adminToken:      os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		policyFile:      statePath("retention-policy.json"),
+		attestationFile: statePath("deletion-attestations.json"),
+		policy:          make(map[string]int),
+		pruned:          make(map[string]int),
+	}
+
+	for class, hours := range retentionDefaults {
+		d.policy[class] = hours
+		env := "NAUTILUS_RETENTION_" + strings.ToUpper(class) + "_HOURS"
+		if value, err := strconv.Atoi(os.Getenv(env)); err == nil && value > 0 {
+			d.policy[class] = value
+		}
+	}
+	var saved map[string]int
+	if found, err := loadJSONState(d.policyFile, &saved); err != nil {
+		logger.Warnf("⚠️ Failed to load retention policy: %v", err)
+	} else if found {
+		for class, hours := range saved {
+			if _, ok := retentionDefaults[class]; ok && hours > 0 {
+				d.policy[class] = hours
+			}
+		}
+	}
+	if _, err := loadJSONState(d.attestationFile, &d.attestations); err != nil {
+		logger.Warnf("⚠️ Failed to load deletion attestations: %v", err)
+	}
+	return d
+}
+
+// Start - 주기적으로 보관 기간이 지난 데이터 삭제
+func (d *DataRetention) Start(ctx context.Context) {
+	d.Enforce()
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if d.slo != nil && d.slo.Defer("retention_enforcement") {
+				continue
+			}
+			d.Enforce()
+		}
+	}
+}
+
+// Policy - 현재 보관 정책 (시간)
+func (d *DataRetention) Policy() map[string]int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	policy := make(map[string]int, len(d.policy))
+	for class, hours := range d.policy {
+		policy[class] = hours
+	}
+	return policy
+}
+
+// SetPolicy - 일부 종류만 바꿔도 됨 (알 수 없는 종류나 0 이하는 거부)
+func (d *DataRetention) SetPolicy(changes map[string]int) (map[string]int, error) {
+	for class, hours := range changes {
+		if _, ok := retentionDefaults[class]; !ok {
+			return nil, fmt.Errorf("unknown data class %q", class)
+		}
+		if hours <= 0 {
+			return nil, fmt.Errorf("retention for %s must be at least 1 hour", class)
+		}
+	}
+
+	d.mutex.Lock()
+	for class, hours := range changes {
+		d.policy[class] = hours
+	}
+	err := saveJSONState(d.policyFile, d.policy)
+	d.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	d.logger.Infof("🗄️ Retention policy updated: %v", changes)
+	d.Enforce()
+	return d.Policy(), nil
+}
+
+// Enforce - 종류별 보관 기간이 지난 데이터 삭제
+func (d *DataRetention) Enforce() {
+	policy := d.Policy()
+	cutoff := func(class string) time.Time {
+		return time.Now().Add(-time.Duration(policy[class]) * time.Hour)
+	}
+
+	removed := make(map[string]int)
+	if d.audit != nil {
+		limit := cutoff(RetentionAuditLogs)
+		count, _, err := d.audit.Prune(func(event *AuditEvent) bool { return event.StageTimestamp.After(limit) })
+		if err != nil {
+			d.logger.Warnf("⚠️ Failed to apply audit log retention: %v", err)
+		}
+		removed[RetentionAuditLogs] = count
+	}
+	if d.history != nil {
+		removed[RetentionHeartbeatHistory] = d.history.Prune(cutoff(RetentionHeartbeatHistory))
+	}
+	if d.quota != nil {
+		removed[RetentionUsageRecords] = d.quota.Prune(cutoff(RetentionUsageRecords))
+	}
+
+	d.mutex.Lock()
+	for class, count := range removed {
+		d.pruned[class] += count
+	}
+	d.mutex.Unlock()
+	for class, count := range removed {
+		if count > 0 {
+			d.logger.Infof("🧹 Retention removed %d %s records older than %dh", count, class, policy[class])
+		}
+	}
+}
+
+// tenantNamespaces - 테넌트 라벨 네임스페이스와 요청한 네임스페이스 (보호 네임스페이스는 오류)
+func (d *DataRetention) tenantNamespaces(tenant string, requested []string) ([]string, error) {
+	set := make(map[string]bool)
+	for _, namespace := range requested {
+		if protectedNamespaces[namespace] {
+			return nil, fmt.Errorf("namespace %s cannot be purged", namespace)
+		}
+		if !podLogNamePattern.MatchString(namespace) {
+			return nil, fmt.Errorf("invalid namespace %q", namespace)
+		}
+		set[namespace] = true
+	}
+
+	if d.k3sMgr.IsRunning() {
+		output, err := d.k3sMgr.RunKubectl(nil, "get", "namespaces", "-l", tenantLabel+"="+tenant, "-o", "jsonpath={.items[*].metadata.name}")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tenant namespaces: %v", err)
+		}
+		for _, namespace := range strings.Fields(string(output)) {
+			if !protectedNamespaces[namespace] {
+				set[namespace] = true
+			}
+		}
+	}
+
+	namespaces := make([]string, 0, len(set))
+	for namespace := range set {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// Purge - 테넌트 데이터 삭제 후 서명된 증명 반환 (일부 실패해도 나머지는 계속 삭제하고 complete=false)
+func (d *DataRetention) Purge(tenant string, requested []string) (*DeletionAttestation, error) {
+	namespaces, err := d.tenantNamespaces(tenant, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &DeletionAttestation{
+		ID:          newAttestationID(),
+		Tenant:      tenant,
+		Namespaces:  namespaces,
+		Deleted:     []DeletionItem{},
+		RequestedAt: time.Now().UTC(),
+		PublicKey:   hex.EncodeToString(d.signer.publicKey()),
+	}
+	record := func(class string, count int, targets []string, err error) {
+		if err != nil {
+			attestation.Errors = append(attestation.Errors, class+": "+err.Error())
+		}
+		attestation.Deleted = append(attestation.Deleted, DeletionItem{Class: class, Count: count, Targets: targets})
+	}
+
+	// 1. K8s 네임스페이스와 테넌트 Secret
+	if d.k3sMgr.IsRunning() {
+		var deleted []string
+		var nsErr error
+		for _, namespace := range namespaces {
+			if _, err := d.k3sMgr.RunKubectl(nil, "delete", "namespace", namespace, "--ignore-not-found", "--wait=false"); err != nil {
+				nsErr = err
+				continue
+			}
+			deleted = append(deleted, namespace)
+		}
+		record("namespaces", len(deleted), deleted, nsErr)
+
+		output, err := d.k3sMgr.RunKubectl(nil, "delete", "secrets", "--all-namespaces", "-l", tenantLabel+"="+tenant, "-o", "name")
+		record("secrets", len(strings.Fields(string(output))), nil, err)
+	} else {
+		attestation.Errors = append(attestation.Errors, "kubernetes objects: K3s control plane not running")
+	}
+
+	// 2. 로그와 휴지통에 보관한 삭제 객체
+	if d.trash != nil {
+		record("trash", d.trash.PurgeNamespaces(namespaces), nil, nil)
+	}
+	if d.podLogs != nil {
+		count, err := d.podLogs.PurgeNamespaces(namespaces)
+		record("pod_logs", count, nil, err)
+	}
+	if d.audit != nil {
+		inNamespaces := toSet(namespaces)
+		count, external, err := d.audit.Prune(func(event *AuditEvent) bool {
+			if strings.EqualFold(event.User.Username, tenant) {
+				return false
+			}
+			return event.ObjectRef == nil || !inNamespaces[event.ObjectRef.Namespace]
+		})
+		record(RetentionAuditLogs, count, nil, err)
+		for _, sink := range external {
+			attestation.Skipped = append(attestation.Skipped, "audit events already delivered to the "+sink+" sink")
+		}
+	}
+
+	// 3. 하트비트/사용량 기록
+	if d.history != nil {
+		count := 0
+		var nodes []string
+		for _, worker := range d.workerPool.ListWorkers() {
+			if strings.EqualFold(worker.WorkerAddress, tenant) {
+				count += d.history.Forget(worker.NodeID)
+				nodes = append(nodes, worker.NodeID)
+			}
+		}
+		record(RetentionHeartbeatHistory, count, nodes, nil)
+
+		if d.historyArchive != nil {
+			deleted, immutable := 0, 0
+			var archiveErr error
+			for _, nodeID := range nodes {
+				removed, kept, err := d.historyArchive.Forget(nodeID)
+				deleted += removed
+				immutable += kept
+				if err != nil {
+					archiveErr = err
+				}
+			}
+			record("heartbeat_archive", deleted, nil, archiveErr)
+			if immutable > 0 {
+				attestation.Skipped = append(attestation.Skipped,
+					fmt.Sprintf("%d archived heartbeat segments on Walrus (unreadable once removed from the index, expire with their storage epochs)", immutable))
+			}
+		}
+	}
+	if d.quota != nil {
+		count := 0
+		if d.quota.Forget(tenant) {
+			count = 1
+		}
+		record(RetentionUsageRecords, count, nil, nil)
+	}
+
+	// 4. 마스터에 저장된 오프체인 기록
+	if d.serviceAccounts != nil {
+		var deleted []string
+		var saErr error
+		for _, account := range d.serviceAccounts.List(tenant) {
+			if err := d.serviceAccounts.Delete(tenant, account.Namespace, account.Name); err != nil {
+				saErr = err
+				continue
+			}
+			deleted = append(deleted, account.Namespace+"/"+account.Name)
+		}
+		record("service_accounts", len(deleted), deleted, saErr)
+	}
+	if d.rbac != nil {
+		count := 0
+		var grantErr error
+		for _, grant := range d.rbac.ListGrants(tenant) {
+			if err := d.rbac.Revoke(grant.Owner, grant.ID); err != nil {
+				grantErr = err
+				continue
+			}
+			count++
+		}
+		record("access_grants", count, nil, grantErr)
+	}
+	attestation.Skipped = append(attestation.Skipped, "on-chain records (immutable)")
+
+	attestation.Complete = len(attestation.Errors) == 0
+	attestation.CompletedAt = time.Now().UTC()
+	if err := d.sign(attestation); err != nil {
+		return nil, err
+	}
+
+	d.mutex.Lock()
+	d.purges++
+	d.attestations = append(d.attestations, *attestation)
+	if len(d.attestations) > maxDeletionAttestation {
+		d.attestations = d.attestations[len(d.attestations)-maxDeletionAttestation:]
+	}
+	err = saveJSONState(d.attestationFile, d.attestations)
+	d.mutex.Unlock()
+	if err != nil {
+		d.logger.Warnf("⚠️ Failed to persist deletion attestation %s: %v", attestation.ID, err)
+	}
+
+	d.logger.Infof("🗑️ Tenant %s purged (%d namespaces, complete=%v), attestation %s", tenant, len(namespaces), attestation.Complete, attestation.ID)
+	return attestation, nil
+}
+
+// sign - signature를 비운 JSON에 서명
+func (d *DataRetention) sign(attestation *DeletionAttestation) error {
+	attestation.Signature = ""
+	payload, err := json.Marshal(attestation)
+	if err != nil {
+		return err
+	}
+	attestation.Signature = hex.EncodeToString(d.signer.signMessage(payload))
+	return nil
+}
+
+// VerifyDeletionAttestation - 증명 서명 검증 (증명에 적힌 공개키 기준, 호출자는 공개키가 마스터 키인지 따로 확인)
+func VerifyDeletionAttestation(attestation DeletionAttestation) error {
+	key, err := hex.DecodeString(attestation.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key")
+	}
+	signature, err := hex.DecodeString(attestation.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding")
+	}
+	attestation.Signature = ""
+	payload, err := json.Marshal(attestation)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), payload, signature) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func newAttestationID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return "del-" + hex.EncodeToString(id)
+}
+
+func (d *DataRetention) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if d.adminToken == "" {
+		http.Error(w, "Retention API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(d.adminToken)) != 1 {
+		d.logger.Warnf("🚫 Unauthorized retention API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handleRetention - 보관 정책 조회/변경 (/api/v1/retention, 관리자 토큰 필요)
+
+	GET                                    종류별 보관 시간
+	PUT {"audit_logs": 720, ...}           일부 종류 변경 (바로 적용)
+*/
+func (d *DataRetention) handleRetention(w http.ResponseWriter, r *http.Request) {
+	if !d.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, d.Policy())
+	case http.MethodPut:
+		var changes map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			http.Error(w, "Invalid retention policy", http.StatusBadRequest)
+			return
+		}
+		policy, err := d.SetPolicy(changes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, policy)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+/*
+handlePurge - 테넌트 데이터 삭제 (/api/v1/tenants/purge, 관리자 토큰 필요)
+
+	GET  ?tenant=                                                  발급한 삭제 증명
+	POST {"tenant": "0x..", "namespaces": [], "confirm": "0x.."}   삭제 실행 (confirm은 tenant와 같아야 함)
+*/
+func (d *DataRetention) handlePurge(w http.ResponseWriter, r *http.Request) {
+	if !d.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		tenant := r.URL.Query().Get("tenant")
+		d.mutex.Lock()
+		attestations := []DeletionAttestation{}
+		for _, attestation := range d.attestations {
+			if tenant == "" || strings.EqualFold(attestation.Tenant, tenant) {
+				attestations = append(attestations, attestation)
+			}
+		}
+		d.mutex.Unlock()
+		writeClientJSON(w, attestations)
+
+	case http.MethodPost:
+		var body struct {
+			Tenant     string   `json:"tenant"`
+			Namespaces []string `json:"namespaces"`
+			Confirm    string   `json:"confirm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Tenant == "" {
+			http.Error(w, "tenant is required", http.StatusBadRequest)
+			return
+		}
+		if body.Confirm != body.Tenant {
+			http.Error(w, "confirm must repeat the tenant address", http.StatusBadRequest)
+			return
+		}
+		attestation, err := d.Purge(body.Tenant, body.Namespaces)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, attestation)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 보관 정책 삭제 수와 테넌트 삭제 수
+func (d *DataRetention) writeMetrics(w io.Writer) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	classes := make([]string, 0, len(d.policy))
+	for class := range d.policy {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	writeMetricHeader(w, "nautilus_retention_hours", "gauge", "Configured retention per data class")
+	for _, class := range classes {
+		writeMetric(w, "nautilus_retention_hours", map[string]string{"class": class}, float64(d.policy[class]))
+	}
+	writeMetricHeader(w, "nautilus_retention_pruned_total", "counter", "Records removed by retention policies")
+	for _, class := range classes {
+		writeMetric(w, "nautilus_retention_pruned_total", map[string]string{"class": class}, float64(d.pruned[class]))
+	}
+	writeMetricHeader(w, "nautilus_tenant_purges_total", "counter", "Tenant data deletion requests executed")
+	writeMetric(w, "nautilus_tenant_purges_total", nil, float64(d.purges))
+}
@@ -0,0 +1,340 @@
+// Dead Letter Queue - 처리할 수 없는 컨트랙트 이벤트 보관, 관리자 재처리/폐기, 증가 시 알림
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeadLetter - 처리 실패 이벤트 (원본 이벤트, 오류, 재처리 횟수)
+type DeadLetter struct {
+	ID          string            `json:"id"`
+	EventType   string            `json:"event_type"`
+	TxDigest    string            `json:"tx_digest"`
+	Event       *SuiContractEvent `json:"event"`
+	Error       string            `json:"error"`
+	Retries     int               `json:"retries"`
+	FirstSeen   time.Time         `json:"first_seen"`
+	LastAttempt time.Time         `json:"last_attempt"`
+}
+
+/*
+DeadLetterQueue - 형식 오류/미지원 이벤트를 버리지 않고 보관
+
+  - 상태 파일(dead-letters.json)에 저장되어 재시작 후에도 유지, 최대 NAUTILUS_DLQ_MAX_ENTRIES개 (오래된 것부터 제거)
+  - /api/v1/admin/dead-letters: 조회, 재처리(requeue), 폐기 (NAUTILUS_ADMIN_TOKEN Bearer 인증)
+  - 항목 수가 NAUTILUS_DLQ_ALERT_THRESHOLD 이상이 되면 오류 로그와 메트릭으로 알리고,
+    NAUTILUS_DLQ_ALERT_WEBHOOK이 설정되어 있으면 웹훅으로 전송 (임계값 아래로 내려가면 해제)
+*/
+type DeadLetterQueue struct {
+	logger         *logrus.Logger
+	sui            *SuiIntegration
+	adminToken     string
+	stateFile      string
+	maxEntries     int
+	alertThreshold int
+	alertWebhook   string
+	client         *http.Client
+
+	mutex     sync.Mutex
+	entries   map[string]*DeadLetter
+	alerting  bool
+	added     uint64
+	requeued  uint64
+	discarded uint64
+}
+
+// NewDeadLetterQueue - 새 Dead Letter Queue 생성 (저장된 항목 복원)
+func NewDeadLetterQueue(logger *logrus.Logger, sui *SuiIntegration) *DeadLetterQueue {
+	maxEntries, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_DLQ_MAX_ENTRIES", "1000"))
+	if err != nil || maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	threshold, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_DLQ_ALERT_THRESHOLD", "10"))
+	if err != nil || threshold <= 0 {
+		threshold = 10
+	}
+
+	q := &DeadLetterQueue{
+		logger:         logger,
+		sui:            sui,
+		adminToken:     os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		stateFile:      statePath("dead-letters.json"),
+		maxEntries:     maxEntries,
+		alertThreshold: threshold,
+		alertWebhook:   os.Getenv("NAUTILUS_DLQ_ALERT_WEBHOOK"),
+		client:         &http.Client{Timeout: 10 * time.Second},
+		entries:        make(map[string]*DeadLetter),
+	}
+	if ok, err := loadJSONState(q.stateFile, &q.entries); err != nil {
+		logger.Warnf("⚠️ Failed to load dead letters: %v", err)
+	} else if ok && len(q.entries) > 0 {
+		logger.Warnf("📮 Loaded %d dead-lettered events", len(q.entries))
+	}
+	q.alerting = len(q.entries) >= q.alertThreshold
+	return q
+}
+
+// Add - 처리 실패 이벤트 보관 (같은 이벤트가 다시 실패하면 오류와 시각만 갱신, nil이면 무시)
+func (q *DeadLetterQueue) Add(event *SuiContractEvent, cause error) {
+	if q == nil {
+		return
+	}
+	id := eventKey(event)
+	now := time.Now()
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if entry := q.entries[id]; entry != nil {
+		entry.Error = cause.Error()
+		entry.LastAttempt = now
+		q.saveLocked()
+		return
+	}
+
+	if len(q.entries) >= q.maxEntries {
+		q.evictOldestLocked()
+	}
+	q.entries[id] = &DeadLetter{
+		ID:          id,
+		EventType:   event.Type,
+		TxDigest:    event.TxDigest,
+		Event:       event,
+		Error:       cause.Error(),
+		FirstSeen:   now,
+		LastAttempt: now,
+	}
+	q.added++
+	q.saveLocked()
+	q.checkAlertLocked()
+}
+
+func (q *DeadLetterQueue) evictOldestLocked() {
+	var oldest *DeadLetter
+	for _, entry := range q.entries {
+		if oldest == nil || entry.FirstSeen.Before(oldest.FirstSeen) {
+			oldest = entry
+		}
+	}
+	if oldest != nil {
+		delete(q.entries, oldest.ID)
+		q.logger.Warnf("⚠️ Dead-letter queue full (%d), evicted %s", q.maxEntries, oldest.ID)
+	}
+}
+
+// checkAlertLocked - 임계값을 넘으면 한 번 알림, 내려가면 알림 상태 해제
+func (q *DeadLetterQueue) checkAlertLocked() {
+	size := len(q.entries)
+	if size < q.alertThreshold {
+		q.alerting = false
+		return
+	}
+	if q.alerting {
+		return
+	}
+	q.alerting = true
+	q.logger.Errorf("🚨 Dead-letter queue has %d unprocessable events (threshold %d), inspect /api/v1/admin/dead-letters", size, q.alertThreshold)
+
+	if q.alertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"alert":     "nautilus_dead_letter_queue",
+		"entries":   size,
+		"threshold": q.alertThreshold,
+		"at":        time.Now().UTC().Format(time.RFC3339),
+	})
+	go func() {
+		resp, err := q.client.Post(q.alertWebhook, "application/json", bytes.NewReader(body))
+		if err != nil {
+			q.logger.Warnf("⚠️ Dead-letter alert webhook failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			q.logger.Warnf("⚠️ Dead-letter alert webhook returned HTTP %d", resp.StatusCode)
+		}
+	}()
+}
+
+func (q *DeadLetterQueue) saveLocked() {
+	if err := saveJSONState(q.stateFile, q.entries); err != nil {
+		q.logger.Warnf("⚠️ Failed to persist dead letters: %v", err)
+	}
+}
+
+// Requeue - 이벤트 재처리 (성공하면 제거, 실패하면 재처리 횟수 증가)
+func (q *DeadLetterQueue) Requeue(ctx context.Context, id string) (*DeadLetter, error) {
+	q.mutex.Lock()
+	entry := q.entries[id]
+	q.mutex.Unlock()
+	if entry == nil {
+		return nil, fmt.Errorf("dead letter %s not found", id)
+	}
+
+	// 핸들러는 락 밖에서 실행 (kubectl 등 오래 걸리는 작업 중에도 다른 이벤트 보관 가능)
+	err := q.sui.dispatchEvent(ctx, entry.Event)
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	current := q.entries[id]
+	if current == nil {
+		return entry, err
+	}
+	if err == nil {
+		delete(q.entries, id)
+		q.requeued++
+		q.logger.Infof("📮 Dead letter %s (%s) reprocessed after %d retries", id, entry.EventType, current.Retries)
+	} else {
+		current.Retries++
+		current.Error = err.Error()
+		current.LastAttempt = time.Now()
+	}
+	q.saveLocked()
+	q.checkAlertLocked()
+	return current, err
+}
+
+// Discard - 항목 폐기
+func (q *DeadLetterQueue) Discard(id string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entry := q.entries[id]
+	if entry == nil {
+		return false
+	}
+	delete(q.entries, id)
+	q.discarded++
+	q.saveLocked()
+	q.checkAlertLocked()
+	q.logger.Infof("🗑️ Discarded dead letter %s (%s)", id, entry.EventType)
+	return true
+}
+
+// Entries - 처음 실패 시각 순 항목 복사본
+func (q *DeadLetterQueue) Entries() []DeadLetter {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entries := make([]DeadLetter, 0, len(q.entries))
+	for _, entry := range q.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FirstSeen.Before(entries[j].FirstSeen) })
+	return entries
+}
+
+/*
+handleDeadLetters - dead-letter 관리 API (/api/v1/admin/dead-letters, 관리자 토큰 필요)
+
+	GET                         전체 목록 (?type=으로 이벤트 타입 필터)
+	POST   ?id=...&action=requeue   재처리 (id=all이면 전체)
+	DELETE ?id=...              폐기
+*/
+func (q *DeadLetterQueue) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if q.adminToken == "" {
+		http.Error(w, "Dead-letter admin API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(q.adminToken)) != 1 {
+		q.logger.Warnf("🚫 Unauthorized dead-letter access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	switch r.Method {
+	case http.MethodGet:
+		eventType := r.URL.Query().Get("type")
+		entries := []DeadLetter{}
+		for _, entry := range q.Entries() {
+			if (id == "" || entry.ID == id) && (eventType == "" || strings.Contains(entry.EventType, eventType)) {
+				entries = append(entries, entry)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":          "success",
+			"alert_threshold": q.alertThreshold,
+			"dead_letters":    entries,
+		})
+
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "requeue" || id == "" {
+			http.Error(w, "Expected ?id=...&action=requeue", http.StatusBadRequest)
+			return
+		}
+		ids := []string{id}
+		if id == "all" {
+			ids = ids[:0]
+			for _, entry := range q.Entries() {
+				ids = append(ids, entry.ID)
+			}
+		}
+
+		results := make([]map[string]interface{}, 0, len(ids))
+		for _, target := range ids {
+			entry, err := q.Requeue(r.Context(), target)
+			if entry == nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			result := map[string]interface{}{"id": target, "processed": err == nil}
+			if err != nil {
+				result["error"] = err.Error()
+				result["retries"] = entry.Retries
+			}
+			results = append(results, result)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "results": results})
+
+	case http.MethodDelete:
+		if !q.Discard(id) {
+			http.Error(w, "Dead letter not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - dead-letter 메트릭 출력
+func (q *DeadLetterQueue) writeMetrics(w io.Writer) {
+	q.mutex.Lock()
+	size, alerting, added, requeued, discarded := len(q.entries), q.alerting, q.added, q.requeued, q.discarded
+	q.mutex.Unlock()
+
+	alert := 0.0
+	if alerting {
+		alert = 1
+	}
+	writeMetricHeader(w, "nautilus_dead_letters", "gauge", "Contract events waiting in the dead-letter queue")
+	writeMetric(w, "nautilus_dead_letters", nil, float64(size))
+	writeMetricHeader(w, "nautilus_dead_letter_alert", "gauge", "Whether the dead-letter queue is above its alert threshold")
+	writeMetric(w, "nautilus_dead_letter_alert", nil, alert)
+	writeMetricHeader(w, "nautilus_dead_letters_total", "counter", "Dead-letter queue transitions by outcome")
+	writeMetric(w, "nautilus_dead_letters_total", map[string]string{"outcome": "added"}, float64(added))
+	writeMetric(w, "nautilus_dead_letters_total", map[string]string{"outcome": "requeued"}, float64(requeued))
+	writeMetric(w, "nautilus_dead_letters_total", map[string]string{"outcome": "discarded"}, float64(discarded))
+}
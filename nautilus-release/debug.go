@@ -0,0 +1,290 @@
+// Debug - 관리자 인증 기반 마스터 내부 상태 조회 (pprof, 큐 깊이, 진행 중 요청, 저장소 통계, 상태 덤프)
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveEnvMarkers - 값을 숨길 환경변수 이름 패턴
+var sensitiveEnvMarkers = []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "CREDENTIAL"}
+
+// DebugServer - /debug 엔드포인트 제공
+type DebugServer struct {
+	logger   *logrus.Logger
+	k3sMgr   *K3sManager
+	sui      *SuiIntegration
+	rbac     *RBACAuthorizer
+	capacity *CapacityPublisher
+	health   *NodeHealthScorer
+
+	// 지원 번들 수집 대상 (main에서 설정, 없으면 해당 항목 생략)
+	metrics     *MetricsRegistry
+	readiness   *ReadinessGate
+	deadLetters *DeadLetterQueue
+	poolSync    *PoolSync
+	signer      *RequestSigner
+	logs        *logRing
+	logControl  *LogControl
+
+	adminToken string
+	startedAt  time.Time
+}
+
+// NewDebugServer - 새 Debug Server 생성 (NAUTILUS_ADMIN_TOKEN 미설정 시 비활성)
+func NewDebugServer(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration) *DebugServer {
+	return &DebugServer{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		sui:        sui,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		startedAt:  time.Now(),
+	}
+}
+
+// Register - 라우터에 디버그 핸들러 등록 (pprof는 명세 제외)
+func (d *DebugServer) Register(router *httpserver.Router) {
+	router.Handle("/debug/pprof/", d.requireAdmin(http.HandlerFunc(pprof.Index)))
+	router.Handle("/debug/pprof/cmdline", d.requireAdmin(http.HandlerFunc(pprof.Cmdline)))
+	router.Handle("/debug/pprof/profile", d.requireAdmin(http.HandlerFunc(pprof.Profile)))
+	router.Handle("/debug/pprof/symbol", d.requireAdmin(http.HandlerFunc(pprof.Symbol)))
+	router.Handle("/debug/pprof/trace", d.requireAdmin(http.HandlerFunc(pprof.Trace)))
+	router.Handle("/debug/state", d.requireAdmin(http.HandlerFunc(d.handleState)), httpserver.Operation{
+		Summary: "Runtime state summary", Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+		Response: dataResponse(map[string]interface{}{
+			"uptime_seconds": int64(0), "goroutines": 0, "heap_alloc": uint64(0), "heap_objects": uint64(0),
+			"gc_cycles": uint32(0), "event_queue": map[string]int{}, "in_flight_requests": 0,
+			"k3s_running": false, "storage": map[string]interface{}{},
+		}),
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+	})
+	router.Handle("/debug/dump", d.requireAdmin(http.HandlerFunc(d.handleDump)), httpserver.Operation{
+		Summary: "Full state dump with secrets redacted", Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+		Response: dataResponse(map[string]interface{}{
+			"generated_at": time.Time{}, "workers": []WorkerNode{}, "environment": map[string]string{},
+			"access_grants": []*AccessGrant{}, "capacity": &CapacitySnapshot{}, "node_health": []NodeHealth{},
+		}),
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+	})
+	router.Handle("/debug/support-bundle", d.requireAdmin(http.HandlerFunc(d.handleSupportBundle)), httpserver.Operation{
+		Summary:     "Support bundle archive",
+		Description: "tar.gz of sanitized configuration, recent logs, health and readiness snapshots, version info, event-queue stats and a fresh attestation document, with secrets redacted.",
+		Tags:        []string{"admin"}, Auth: httpserver.AuthAdminToken,
+		Response: "", ContentType: "application/gzip",
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden},
+	})
+	if d.logControl == nil {
+		return
+	}
+	router.Handle("/debug/log-levels", d.requireAdmin(http.HandlerFunc(d.logControl.handleLogLevels)),
+		httpserver.Operation{
+			Summary: "Base and per-subsystem log levels", Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+			Response: dataResponse(map[string]interface{}{"base": "", "effective": "", "subsystems": map[string]interface{}{}}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+		},
+		httpserver.Operation{
+			Method: http.MethodPut, Summary: "Change a log level at runtime",
+			Description: "Subsystems are source file names without .go (e.g. pool_sync); an empty subsystem changes the base level. Level \"reset\" removes a subsystem override; duration_seconds makes the override expire.",
+			Tags:        []string{"admin"}, Auth: httpserver.AuthAdminToken,
+			Request:  map[string]interface{}{"subsystem": "", "level": "debug", "duration_seconds": 0},
+			Response: dataResponse(map[string]interface{}{"base": "", "effective": "", "subsystems": map[string]interface{}{}}),
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		})
+	router.Handle("/debug/traces", d.requireAdmin(http.HandlerFunc(d.logControl.handleTraces)),
+		httpserver.Operation{
+			Summary: "Active targeted log traces", Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+			Response: dataResponse([]LogTrace{}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden},
+		},
+		httpserver.Operation{
+			Method: http.MethodPost, Summary: "Log everything about a node, request ID or message text for a bounded time",
+			Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+			Request:  map[string]interface{}{"node_id": "", "request_id": "", "match": "", "duration_seconds": 600},
+			Response: dataResponse(LogTrace{}), Status: http.StatusCreated,
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		},
+		httpserver.Operation{
+			Method: http.MethodDelete, Summary: "Stop a log trace", Tags: []string{"admin"}, Auth: httpserver.AuthAdminToken,
+			Query:    []httpserver.Param{{Name: "id", Required: true, Description: "trace ID"}},
+			Response: dataResponse(map[string]string{"id": ""}),
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+		})
+}
+
+// requireAdmin - Bearer 관리자 토큰 검증
+func (d *DebugServer) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.adminToken == "" {
+			http.Error(w, "Debug endpoints disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(d.adminToken)) != 1 {
+			d.logger.Warnf("🚫 Unauthorized debug access from %s: %s", r.RemoteAddr, r.URL.Path)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleState - 런타임 상태 요약
+func (d *DebugServer) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   d.runtimeState(),
+	})
+}
+
+// runtimeState - 런타임/이벤트 큐/저장소 요약 (상태 조회와 지원 번들 공용)
+func (d *DebugServer) runtimeState() map[string]interface{} {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	depth, capacity := d.sui.EventQueueDepth()
+	storageObjects, err := d.storageObjectCounts()
+	storage := map[string]interface{}{"objects": storageObjects}
+	if err != nil {
+		storage["error"] = err.Error()
+	}
+
+	return map[string]interface{}{
+		"uptime_seconds": int64(time.Since(d.startedAt).Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"heap_alloc":     memStats.HeapAlloc,
+		"heap_objects":   memStats.HeapObjects,
+		"gc_cycles":      memStats.NumGC,
+		"event_queue": map[string]int{
+			"depth":    depth,
+			"capacity": capacity,
+		},
+		"in_flight_requests": d.sui.InFlightRequests(),
+		"k3s_running":        d.k3sMgr.IsRunning(),
+		"storage":            storage,
+	}
+}
+
+// storageObjectCounts - API 서버 메트릭에서 리소스별 저장 객체 수 추출
+func (d *DebugServer) storageObjectCounts() (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if !d.k3sMgr.IsRunning() {
+		return counts, nil
+	}
+
+	output, err := d.k3sMgr.RunKubectl(nil, "get", "--raw", "/metrics")
+	if err != nil {
+		return counts, err
+	}
+
+	// apiserver_storage_objects{resource="pods"} 12
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "apiserver_storage_objects{") {
+			continue
+		}
+		start := strings.Index(line, `resource="`)
+		end := strings.LastIndex(line, "}")
+		if start < 0 || end < 0 {
+			continue
+		}
+		resource := strings.SplitN(line[start+len(`resource="`):], `"`, 2)[0]
+		value, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64)
+		if err == nil {
+			counts[resource] = int64(value)
+		}
+	}
+	return counts, scanner.Err()
+}
+
+// handleDump - 비밀 정보를 제거한 전체 상태 덤프
+func (d *DebugServer) handleDump(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	d.logger.Infof("🧾 State dump requested from %s", r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   d.stateDump(),
+	})
+}
+
+// stateDump - 비밀 정보를 가린 워커/권한/용량/노드 상태
+func (d *DebugServer) stateDump() map[string]interface{} {
+	workers := d.k3sMgr.workerPool.ListWorkers()
+	sanitized := make([]WorkerNode, 0, len(workers))
+	for _, worker := range workers {
+		copied := *worker
+		if copied.SealToken != "" {
+			copied.SealToken = redacted
+		}
+		if copied.JoinToken != "" {
+			copied.JoinToken = redacted
+		}
+		sanitized = append(sanitized, copied)
+	}
+
+	dump := map[string]interface{}{
+		"generated_at": time.Now(),
+		"workers":      sanitized,
+		"environment":  sanitizedEnvironment(),
+	}
+	if d.rbac != nil {
+		dump["access_grants"] = d.rbac.AllGrants()
+	}
+	if d.capacity != nil {
+		dump["capacity"] = d.capacity.Snapshot()
+	}
+	if d.health != nil {
+		dump["node_health"] = d.health.ListHealth()
+	}
+	return dump
+}
+
+// sanitizedEnvironment - 민감한 값을 가린 환경변수 목록
+func sanitizedEnvironment() map[string]string {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, value := parts[0], parts[1]
+		upper := strings.ToUpper(name)
+		for _, marker := range sensitiveEnvMarkers {
+			if strings.Contains(upper, marker) {
+				value = redacted
+				break
+			}
+		}
+		env[name] = value
+	}
+	return env
+}
@@ -0,0 +1,154 @@
+// Delete Options - 온체인 DELETE 요청의 DeleteOptions(유예 시간, 전파 정책, 전제 조건) 처리와 업스트림 형식 응답
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+온체인 K8sAPIRequest의 DELETE는 payload에 업스트림 DeleteOptions를 담을 수 있습니다.
+
+	{"gracePeriodSeconds": 30, "propagationPolicy": "Foreground", "preconditions": {"uid": "..."}, "dryRun": ["All"]}
+
+kubectl delete는 기본적으로 객체가 사라질 때까지 기다리므로, 유예 시간이 긴 Pod이나 finalizer가 남은 객체는
+요청 처리 루프를 막습니다. 업스트림 API 서버처럼 --wait=false로 삭제를 시작만 하고,
+객체가 아직 남아 있으면 deletionTimestamp/deletionGracePeriodSeconds/finalizers가 채워진 객체를,
+이미 사라졌으면 Status(Success)를 결과로 돌려줍니다. Pod의 실제 종료(preStop, SIGTERM→SIGKILL)는 워커 kubelet이 수행합니다.
+*/
+
+// K8sDeleteOptions - meta/v1 DeleteOptions 중 지원하는 필드
+type K8sDeleteOptions struct {
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	PropagationPolicy  string `json:"propagationPolicy,omitempty"` // Foreground, Background, Orphan
+	OrphanDependents   *bool  `json:"orphanDependents,omitempty"`  // 폐기 예정 (propagationPolicy 우선)
+	Preconditions      *struct {
+		UID             string `json:"uid,omitempty"`
+		ResourceVersion string `json:"resourceVersion,omitempty"`
+	} `json:"preconditions,omitempty"`
+	DryRun []string `json:"dryRun,omitempty"`
+}
+
+// parseDeleteOptions - DELETE payload 해석 (비어 있으면 기본 옵션)
+func parseDeleteOptions(payload string) (*K8sDeleteOptions, error) {
+	options := &K8sDeleteOptions{}
+	if strings.TrimSpace(payload) == "" {
+		return options, nil
+	}
+	if err := json.Unmarshal([]byte(payload), options); err != nil {
+		return nil, fmt.Errorf("invalid DeleteOptions: %v", err)
+	}
+	if options.GracePeriodSeconds != nil && *options.GracePeriodSeconds < 0 {
+		return nil, fmt.Errorf("gracePeriodSeconds must be >= 0")
+	}
+	for _, value := range options.DryRun {
+		if value != "All" {
+			return nil, fmt.Errorf("unsupported dryRun value %q", value)
+		}
+	}
+	return options, nil
+}
+
+// kubectlFlags - kubectl delete 플래그 (항상 --wait=false)
+func (o *K8sDeleteOptions) kubectlFlags() ([]string, error) {
+	flags := []string{"--wait=false"}
+	if o.GracePeriodSeconds != nil {
+		// 업스트림의 gracePeriodSeconds=0(즉시 삭제)은 kubectl에서 --force와 함께여야 허용됨
+		grace := *o.GracePeriodSeconds
+		if grace == 0 {
+			flags = append(flags, "--grace-period=0", "--force")
+		} else {
+			flags = append(flags, "--grace-period="+strconv.FormatInt(grace, 10))
+		}
+	}
+
+	policy := o.PropagationPolicy
+	if policy == "" && o.OrphanDependents != nil && *o.OrphanDependents {
+		policy = "Orphan"
+	}
+	switch policy {
+	case "":
+	case "Foreground", "Background", "Orphan":
+		flags = append(flags, "--cascade="+strings.ToLower(policy))
+	default:
+		return nil, fmt.Errorf("unsupported propagationPolicy %q", policy)
+	}
+
+	if len(o.DryRun) > 0 {
+		flags = append(flags, "--dry-run=server")
+	}
+	return flags, nil
+}
+
+// objectRef - 네임스페이스를 포함한 kubectl 대상 인자
+func objectRef(request *K8sAPIRequest) []string {
+	args := []string{request.Resource, request.Name}
+	if request.Namespace != "" {
+		args = append(args, "-n", request.Namespace)
+	}
+	return args
+}
+
+// checkDeletePreconditions - uid/resourceVersion 전제 조건 확인 (업스트림 409 Conflict 메시지 형식)
+func (s *SuiIntegration) checkDeletePreconditions(ctx context.Context, request *K8sAPIRequest, options *K8sDeleteOptions) error {
+	if options.Preconditions == nil || (options.Preconditions.UID == "" && options.Preconditions.ResourceVersion == "") {
+		return nil
+	}
+	if request.Name == "" {
+		return fmt.Errorf("preconditions require a resource name")
+	}
+	stdout, stderr, err := s.runCommand(ctx, "kubectl", nil, append(append([]string{"get"}, objectRef(request)...), "-o", "json")...)
+	if err != nil {
+		return fmt.Errorf("Command failed: %v, stderr: %s", err, stderr)
+	}
+	var object struct {
+		Metadata struct {
+			UID             string `json:"uid"`
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(stdout, &object); err != nil {
+		return fmt.Errorf("failed to parse %s/%s: %v", request.Resource, request.Name, err)
+	}
+	if uid := options.Preconditions.UID; uid != "" && uid != object.Metadata.UID {
+		return fmt.Errorf("Precondition failed: UID in precondition: %s, UID in object meta: %s", uid, object.Metadata.UID)
+	}
+	if rv := options.Preconditions.ResourceVersion; rv != "" && rv != object.Metadata.ResourceVersion {
+		return fmt.Errorf("Precondition failed: ResourceVersion in precondition: %s, ResourceVersion in object meta: %s", rv, object.Metadata.ResourceVersion)
+	}
+	return nil
+}
+
+/*
+deletionResult - 삭제 요청 후 결과 본문
+종료 중인 객체(유예 시간, finalizer)는 그대로 반환하고, 이미 제거됐으면 업스트림과 같은 Status를 반환합니다.
+이름 없는 삭제와 dryRun은 kubectl 출력을 그대로 사용합니다.
+*/
+func (s *SuiIntegration) deletionResult(ctx context.Context, request *K8sAPIRequest) string {
+	stdout, _, err := s.runCommand(ctx, "kubectl", nil, append(append([]string{"get"}, objectRef(request)...), "-o", "json", "--ignore-not-found")...)
+	if err == nil && len(strings.TrimSpace(string(stdout))) > 0 {
+		var object struct {
+			Metadata struct {
+				DeletionTimestamp string   `json:"deletionTimestamp"`
+				Finalizers        []string `json:"finalizers"`
+			} `json:"metadata"`
+		}
+		if json.Unmarshal(stdout, &object) == nil && object.Metadata.DeletionTimestamp != "" {
+			s.logger.Infof("⏳ %s/%s terminating since %s (finalizers: %v)",
+				request.Resource, request.Name, object.Metadata.DeletionTimestamp, object.Metadata.Finalizers)
+		}
+		return string(stdout)
+	}
+
+	status, _ := json.Marshal(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]interface{}{},
+		"status":     "Success",
+		"details":    map[string]interface{}{"name": request.Name, "kind": request.Resource},
+	})
+	return string(status)
+}
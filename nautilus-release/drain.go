@@ -0,0 +1,319 @@
+// Drainer - 종료 시 연결 드레인 및 대기(standby) 마스터로 상태 인계
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandoffState - 대기 마스터로 넘기는 상태
+type HandoffState struct {
+	Workers   []*WorkerNode     `json:"workers"`
+	Bookmarks map[string]string `json:"bookmarks"`
+	SentAt    time.Time         `json:"sent_at"`
+}
+
+// Drainer - 진행 중 요청 추적, 드레인 중 신규 요청 거부, watch 북마크 보존
+type Drainer struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	standbyURL string
+	adminToken string
+	client     *http.Client
+
+	mutex     sync.Mutex
+	draining  bool
+	inFlight  int
+	idle      chan struct{}
+	watches   map[int]context.CancelFunc
+	nextWatch int
+	bookmarks map[string]string
+
+	standby   bool
+	handedOff chan struct{}
+	handoffMu sync.Once
+}
+
+// NewDrainer - 새 Drainer 생성 (NAUTILUS_STANDBY=true면 인계를 받을 때까지 not-ready)
+func NewDrainer(logger *logrus.Logger, workerPool *WorkerPool) *Drainer {
+	d := &Drainer{
+		logger:     logger,
+		workerPool: workerPool,
+		standbyURL: strings.TrimSuffix(os.Getenv("NAUTILUS_STANDBY_URL"), "/"),
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		watches:    make(map[int]context.CancelFunc),
+		bookmarks:  make(map[string]string),
+		standby:    getEnvOrDefault("NAUTILUS_STANDBY", "false") == "true",
+		handedOff:  make(chan struct{}),
+	}
+
+	if ok, err := loadJSONState(statePath("watch-bookmarks.json"), &d.bookmarks); err != nil {
+		logger.Warnf("⚠️ Failed to load watch bookmarks: %v", err)
+	} else if ok {
+		logger.Infof("📑 Loaded %d watch bookmarks", len(d.bookmarks))
+	}
+	if !d.standby {
+		d.handoffMu.Do(func() { close(d.handedOff) })
+	}
+	return d
+}
+
+// Ready - 드레인 중이 아니고, 대기 모드라면 인계를 받은 상태
+func (d *Drainer) Ready() bool {
+	d.mutex.Lock()
+	draining := d.draining
+	d.mutex.Unlock()
+	if draining {
+		return false
+	}
+
+	select {
+	case <-d.handedOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForHandoff - 대기 모드에서 인계를 받을 때까지 블록
+func (d *Drainer) WaitForHandoff(ctx context.Context) error {
+	if d.standby {
+		d.logger.Info("⏸️ Standby mode: waiting for handoff from primary master...")
+	}
+	select {
+	case <-d.handedOff:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware - 요청 수를 추적하고 드레인 중에는 503 + Retry-After로 거부
+func (d *Drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 헬스체크는 드레인 여부와 무관하게 응답해야 LB가 트래픽을 뺄 수 있음
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.mutex.Lock()
+		if d.draining {
+			d.mutex.Unlock()
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "Master is draining for restart", http.StatusServiceUnavailable)
+			return
+		}
+		d.inFlight++
+		d.mutex.Unlock()
+		defer d.done()
+
+		if !isWatchRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// watch 스트림은 드레인 시 취소할 수 있도록 별도 context로 실행
+		ctx, cancel := context.WithCancel(r.Context())
+		d.mutex.Lock()
+		id := d.nextWatch
+		d.nextWatch++
+		d.watches[id] = cancel
+		d.mutex.Unlock()
+		defer func() {
+			d.mutex.Lock()
+			delete(d.watches, id)
+			d.mutex.Unlock()
+			cancel()
+		}()
+
+		// resourceVersion 기록은 서명 범위 안쪽의 K8s 프록시(watchStream)에서 수행
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Draining - 드레인 진행 여부
+func (d *Drainer) Draining() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.draining
+}
+
+// done - 진행 중 요청 하나 완료
+func (d *Drainer) done() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.inFlight--
+	if d.inFlight == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+}
+
+// recordBookmark - watch 경로별 마지막 resourceVersion 기록
+func (d *Drainer) recordBookmark(key, resourceVersion string) {
+	d.mutex.Lock()
+	d.bookmarks[key] = resourceVersion
+	d.mutex.Unlock()
+}
+
+// Drain - 신규 요청 거부 → watch 취소 → 진행 중 요청 완료 대기 → 북마크 저장
+func (d *Drainer) Drain(timeout time.Duration) error {
+	d.mutex.Lock()
+	d.draining = true
+	for _, cancel := range d.watches {
+		cancel()
+	}
+	watches := len(d.watches)
+	var idle chan struct{}
+	if d.inFlight > 0 {
+		d.idle = make(chan struct{})
+		idle = d.idle
+	}
+	inFlight := d.inFlight
+	d.mutex.Unlock()
+
+	d.logger.Infof("🚰 Draining: %d in-flight requests, %d watch streams closed", inFlight, watches)
+
+	var err error
+	if idle != nil {
+		select {
+		case <-idle:
+		case <-time.After(timeout):
+			d.mutex.Lock()
+			err = fmt.Errorf("drain deadline exceeded with %d requests still in flight", d.inFlight)
+			d.mutex.Unlock()
+		}
+	}
+
+	if saveErr := saveJSONState(statePath("watch-bookmarks.json"), d.Bookmarks()); saveErr != nil {
+		d.logger.Errorf("❌ Failed to persist watch bookmarks: %v", saveErr)
+	}
+	return err
+}
+
+// Bookmarks - 북마크 복사본
+func (d *Drainer) Bookmarks() map[string]string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	bookmarks := make(map[string]string, len(d.bookmarks))
+	for key, rv := range d.bookmarks {
+		bookmarks[key] = rv
+	}
+	return bookmarks
+}
+
+// Handoff - NAUTILUS_STANDBY_URL로 워커 풀과 북마크 전송 (미설정 시 생략)
+func (d *Drainer) Handoff() error {
+	if d.standbyURL == "" {
+		return nil
+	}
+
+	state := HandoffState{
+		Workers:   d.workerPool.ListWorkers(),
+		Bookmarks: d.Bookmarks(),
+		SentAt:    time.Now(),
+	}
+	body, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode handoff state: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.standbyURL+"/api/v1/upgrade/handoff", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create handoff request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+d.adminToken)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("handoff to %s failed: %v", d.standbyURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("standby rejected handoff: HTTP %d", resp.StatusCode)
+	}
+
+	d.logger.Infof("🤝 Handed off %d workers and %d bookmarks to %s", len(state.Workers), len(state.Bookmarks), d.standbyURL)
+	return nil
+}
+
+// handleHandoff - 대기 마스터가 주 마스터의 상태를 수신 (관리자 토큰 필요)
+func (d *Drainer) handleHandoff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if d.adminToken == "" {
+		http.Error(w, "Handoff disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(d.adminToken)) != 1 {
+		d.logger.Warnf("🚫 Unauthorized handoff attempt from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var state HandoffState
+	if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+		http.Error(w, "Invalid handoff state", http.StatusBadRequest)
+		return
+	}
+
+	for _, worker := range state.Workers {
+		if err := d.workerPool.AddWorker(worker); err != nil {
+			d.logger.Debugf("Handoff worker %s skipped: %v", worker.NodeID, err)
+		}
+	}
+	d.mutex.Lock()
+	for key, rv := range state.Bookmarks {
+		d.bookmarks[bookmarkKey(key)] = rv // 이전 버전 마스터는 요청 경로 그대로 보냄
+	}
+	d.mutex.Unlock()
+
+	d.handoffMu.Do(func() { close(d.handedOff) })
+	d.logger.Infof("🤝 Received handoff: %d workers, %d bookmarks", len(state.Workers), len(state.Bookmarks))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+	})
+}
+
+// handleBookmarks - watch 재개용 마지막 resourceVersion 조회
+func (d *Drainer) handleBookmarks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   d.Bookmarks(),
+	})
+}
+
+// Shutdown - SIGTERM 경로: 드레인 후 대기 마스터로 인계
+func (d *Drainer) Shutdown() {
+	timeout := envSeconds("NAUTILUS_DRAIN_TIMEOUT", 30)
+	if err := d.Drain(timeout); err != nil {
+		d.logger.Warnf("⚠️ %v", err)
+	}
+	if err := d.Handoff(); err != nil {
+		d.logger.Errorf("❌ %v", err)
+	}
+}
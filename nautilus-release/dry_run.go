@@ -0,0 +1,144 @@
+// Dry Run Admission - 서버 측 dryRun 쓰기에 이벤트 경로와 같은 어드미션을 적용한 뒤 K3s dryRun으로 전달
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+kubectl --dry-run=server 쓰기는 Gateway가 컨트랙트에 제출하지 않고 서명된 HTTP로 보냅니다 (쿼리 dryRun=All).
+K3s로 그대로 보내면 이벤트 경로의 ControllerManager.Admit(critical 보호, 위치 제약, 이미지 플랫폼)이 빠져
+실제 생성과 다른 객체가 돌아오므로, 같은 요청 형태로 Admit을 실행해
+  - 거부는 K8s Status(403)로 응답하고
+  - 변환된 본문은 K3s에 dryRun으로 전달해 저장될 객체를 그대로 돌려줍니다.
+K3s는 dryRun 요청을 저장하지 않고, 휴지통과 재생 캐시도 dryRun 요청은 건너뜁니다.
+*/
+
+// DryRunAdmission - dryRun 쓰기 요청 어드미션
+type DryRunAdmission struct {
+	logger        *logrus.Logger
+	controllerMgr *ControllerManager
+
+	mutex    sync.Mutex
+	outcomes map[string]uint64 // admitted, rejected
+}
+
+// NewDryRunAdmission - 새 Dry Run Admission 생성
+func NewDryRunAdmission(logger *logrus.Logger, controllerMgr *ControllerManager) *DryRunAdmission {
+	return &DryRunAdmission{
+		logger:        logger,
+		controllerMgr: controllerMgr,
+		outcomes:      make(map[string]uint64),
+	}
+}
+
+// isDryRun - 쓰기 요청의 dryRun 쿼리 (값 검증은 K3s가 담당)
+func isDryRun(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return len(r.URL.Query()["dryRun"]) > 0
+	}
+	return false
+}
+
+/*
+dryRunTarget - 경로의 네임스페이스, 리소스, 이름 (하위 리소스는 false)
+
+	/api/v1/namespaces/default/pods          → default, pods, ""
+	/apis/apps/v1/namespaces/default/deployments/web → default, deployments, web
+	/api/v1/namespaces/team-a                → "", namespaces, team-a
+	/api/v1/namespaces/default/pods/web/status → false
+*/
+func dryRunTarget(path string) (namespace, resource, name string, ok bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "", "", "", false
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		namespace, segments = segments[1], segments[2:]
+	}
+	switch {
+	case len(segments) == 1 && segments[0] != "":
+		return namespace, segments[0], "", true
+	case len(segments) == 2 && segments[0] != "" && segments[1] != "":
+		return namespace, segments[0], segments[1], true
+	}
+	return "", "", "", false
+}
+
+// Middleware - dryRun 쓰기만 Admit 후 변환된 본문으로 전달 (그 외 요청은 그대로)
+func (d *DryRunAdmission) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isDryRun(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		namespace, resource, name, ok := dryRunTarget(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				httpserver.WriteStatus(w, http.StatusBadRequest, "BadRequest", "failed to read request body")
+				return
+			}
+		}
+		request := &K8sAPIRequest{
+			Method:    r.Method,
+			Resource:  resource,
+			Namespace: namespace,
+			Name:      name,
+			Payload:   string(body),
+			Requester: r.Header.Get("Impersonate-User"),
+		}
+		if err := d.controllerMgr.Admit(request); err != nil {
+			d.count("rejected")
+			d.logger.Infof("🧪 Dry run %s %s rejected by admission: %v", r.Method, r.URL.Path, err)
+			httpserver.WriteStatus(w, http.StatusForbidden, "Forbidden", err.Error())
+			return
+		}
+		d.count("admitted")
+		d.logger.Debugf("🧪 Dry run %s %s admitted, forwarding to K3s", r.Method, r.URL.Path)
+
+		r.Body = io.NopCloser(bytes.NewReader([]byte(request.Payload)))
+		r.ContentLength = int64(len(request.Payload))
+		r.Header.Set("Content-Length", strconv.Itoa(len(request.Payload)))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d *DryRunAdmission) count(outcome string) {
+	d.mutex.Lock()
+	d.outcomes[outcome]++
+	d.mutex.Unlock()
+}
+
+// writeMetrics - dryRun 요청 어드미션 결과
+func (d *DryRunAdmission) writeMetrics(w io.Writer) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_dry_run_requests_total", "counter", "Server-side dry-run write requests by admission outcome")
+	for _, outcome := range []string{"admitted", "rejected"} {
+		writeMetric(w, "nautilus_dry_run_requests_total", map[string]string{"outcome": outcome}, float64(d.outcomes[outcome]))
+	}
+}
@@ -0,0 +1,606 @@
+// Enclave Keys - 마스터 자격 증명을 TEE 안에서 생성/가져오고 봉인된 blob으로만 저장, 서명 연산만 노출
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+PRIVATE_KEY, NAUTILUS_SIGNING_KEY 같은 환경 변수는 /proc/<pid>/environ과 크래시 덤프로 새어 나가고,
+상태 디렉토리의 평문 키 파일은 호스트가 그대로 읽을 수 있습니다. 마스터 자격 증명은 TEE 안에서 생성하거나
+한 번만 가져온 뒤 봉인 키(AES-256-GCM)로 암호화한 <state>/<name>.sealed 만 디스크에 남기고,
+다른 구성요소에는 원본 키 대신 EnclaveSigner(서명)와 EnclaveAgreement(키 합의)만 넘깁니다.
+
+	NAUTILUS_SEALING_PROVIDER          nitro-kms | local (기본: NAUTILUS_SEALING_KMS_KEY_ID가 있으면 nitro-kms)
+	NAUTILUS_SEALING_KMS_KEY_ID        봉인 루트 키를 감싸는 KMS 키 (키 정책에 kms:RecipientAttestation PCR 조건 필요)
+	NAUTILUS_SEALING_KMS_REGION        KMS 리전 (기본 AWS_REGION)
+	NAUTILUS_SEALING_KMS_PROXY_PORT    부모 인스턴스 vsock-proxy 포트 (기본 8000)
+	NAUTILUS_KEY_IMPORT_DIR            한 번만 가져올 키 파일 디렉토리 (<name>.key, 봉인 후 삭제)
+
+봉인 루트 키:
+	nitro-kms  kmstool_enclave_cli genkey로 만든 데이터 키. KMS 암호문만 저장하고, 복호화는 엔클레이브 증명
+	           문서가 키 정책의 PCR과 맞을 때만 성공하므로 호스트나 다른 이미지로는 blob을 열 수 없음
+	local      상태 디렉토리의 루트 키 파일 (0600). 하드웨어 보호가 없으므로 개발/테스트 전용
+
+가져오기 순서: NAUTILUS_KEY_IMPORT_DIR/<name>.key → 키별 파일 변수 → 이전 환경 변수 → 봉인 blob → 이전 평문
+상태 파일 → 생성. 가져온 키가 봉인된 키와 다르면 다시 봉인합니다(교체). 환경 변수는 읽은 즉시 unset하여
+sui CLI/K3s 같은 자식 프로세스로 전달되지 않게 하지만, 커널이 보관하는 최초 환경은 지워지지 않으므로
+가져온 뒤에는 유닛 파일/컨테이너 정의에서 변수를 제거해야 합니다.
+*/
+
+const (
+	sealingProviderLocal    = "local"
+	sealingProviderNitroKMS = "nitro-kms"
+	sealedKeyVersion        = 1
+	sealedKeyLabel          = "nautilus-sealed-key/v1"
+)
+
+// EnclaveSigner - 봉인 키로 할 수 있는 서명 연산 (원본 키 바이트는 키링 밖으로 나가지 않음)
+type EnclaveSigner interface {
+	Public() crypto.PublicKey
+	// Sign - Ed25519는 메시지 그대로, RSA는 SHA-256 PKCS#1 v1.5
+	Sign(message []byte) ([]byte, error)
+}
+
+// EnclaveAgreement - 봉인 키에서 파생한 X25519 키 합의 (*ecdh.PrivateKey가 구현)
+type EnclaveAgreement interface {
+	PublicKey() *ecdh.PublicKey
+	ECDH(remote *ecdh.PublicKey) ([]byte, error)
+}
+
+// enclaveKey - 키링이 보관하는 키의 서명 전용 핸들
+type enclaveKey struct {
+	key crypto.Signer
+}
+
+// Public - 공개키
+func (e *enclaveKey) Public() crypto.PublicKey {
+	return e.key.Public()
+}
+
+// Sign - 키 종류별 서명
+func (e *enclaveKey) Sign(message []byte) ([]byte, error) {
+	switch key := e.key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, message), nil
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+	return nil, fmt.Errorf("unsupported enclave key type %T", e.key)
+}
+
+// enclaveKeySpec - 키별 가져오기 경로와 형식
+type enclaveKeySpec struct {
+	name       string                                   // 봉인 blob 이름 (<state>/<name>.sealed)
+	envVar     string                                   // 이전 방식 환경 변수 (읽은 뒤 unset)
+	importFile string                                   // 운영자가 지정한 키 파일 (리전 간 공유 키 등, 삭제하지 않음)
+	legacyFile string                                   // 이전 평문 상태 파일 (봉인 후 삭제)
+	parse      func(data []byte) (crypto.Signer, error) // 가져온 키 형식 파싱
+	generate   func() (crypto.Signer, error)            // nil이면 가져오기 전용 (없으면 키 없음)
+}
+
+// sealedKeyBlob - 디스크에 남는 유일한 형태
+type sealedKeyBlob struct {
+	Version  int       `json:"version"`
+	Provider string    `json:"provider"`
+	RootID   string    `json:"root_id"`
+	Nonce    []byte    `json:"nonce"`
+	Sealed   []byte    `json:"sealed"`
+	SealedAt time.Time `json:"sealed_at"`
+}
+
+// sealedKeyInfo - 키 목록/메트릭용 (공개 정보만)
+type sealedKeyInfo struct {
+	source   string // generated | imported | sealed | migrated
+	sealedAt time.Time
+}
+
+// EnclaveKeyring - 봉인 루트 키와 로드된 마스터 키
+type EnclaveKeyring struct {
+	logger   *logrus.Logger
+	provider string
+	rootID   string
+	root     []byte // 에스크로 분할용 (디스크에는 KMS 암호문 또는 local 루트 파일로만 존재)
+	aead     cipher.AEAD
+
+	mutex sync.Mutex
+	keys  map[string]crypto.Signer
+	info  map[string]sealedKeyInfo
+}
+
+// NewEnclaveKeyring - 봉인 루트 키를 열거나 만들어 키링 생성
+func NewEnclaveKeyring(logger *logrus.Logger) (*EnclaveKeyring, error) {
+	provider := os.Getenv("NAUTILUS_SEALING_PROVIDER")
+	if provider == "" {
+		provider = sealingProviderLocal
+		if os.Getenv("NAUTILUS_SEALING_KMS_KEY_ID") != "" {
+			provider = sealingProviderNitroKMS
+		}
+	}
+
+	var root []byte
+	var err error
+	switch provider {
+	case sealingProviderNitroKMS:
+		root, err = loadNitroSealingRoot()
+	case sealingProviderLocal:
+		root, err = loadLocalSealingRoot()
+		if device := teeDevice(); device != "" {
+			logger.Warnf("⚠️ TEE device %s present but master keys are sealed with a host-readable root key (set NAUTILUS_SEALING_KMS_KEY_ID)", device)
+		} else {
+			logger.Warn("⚠️ No TEE sealing available, master keys are sealed with a local root key (development only)")
+		}
+	default:
+		return nil, fmt.Errorf("unknown NAUTILUS_SEALING_PROVIDER %q (expected %s or %s)", provider, sealingProviderNitroKMS, sealingProviderLocal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aead, rootID, err := sealingAEAD(root)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("🔐 Enclave keyring ready (provider=%s, root=%s)", provider, rootID)
+	return &EnclaveKeyring{
+		logger:   logger,
+		provider: provider,
+		rootID:   rootID,
+		root:     root,
+		aead:     aead,
+		keys:     make(map[string]crypto.Signer),
+		info:     make(map[string]sealedKeyInfo),
+	}, nil
+}
+
+// sealingAEAD - 루트 키에서 봉인 키와 루트 ID 파생 (에스크로 복구 시 이전 루트에도 사용)
+func sealingAEAD(root []byte) (cipher.AEAD, string, error) {
+	sealingKey := sha256.Sum256(append([]byte(sealedKeyLabel), root...))
+	block, err := aes.NewCipher(sealingKey[:])
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, "", err
+	}
+	rootID := sha256.Sum256(append([]byte(sealedKeyLabel+" id"), root...))
+	return aead, hex.EncodeToString(rootID[:8]), nil
+}
+
+// loadLocalSealingRoot - 상태 디렉토리의 루트 키 (없으면 생성)
+func loadLocalSealingRoot() ([]byte, error) {
+	var stored string
+	path := statePath("sealing-root.key")
+	found, err := loadJSONState(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		root, err := hex.DecodeString(stored)
+		if err != nil || len(root) != 32 {
+			return nil, fmt.Errorf("invalid sealing root key in %s", path)
+		}
+		return root, nil
+	}
+
+	root := make([]byte, 32)
+	if _, err := rand.Read(root); err != nil {
+		return nil, fmt.Errorf("failed to generate sealing root key: %v", err)
+	}
+	if err := saveJSONState(path, hex.EncodeToString(root)); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// nitroSealingRoot - KMS로 감싼 루트 키 (평문은 저장하지 않음)
+type nitroSealingRoot struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// loadNitroSealingRoot - 저장된 KMS 암호문을 증명 문서와 함께 복호화 (없으면 genkey)
+func loadNitroSealingRoot() ([]byte, error) {
+	keyID := os.Getenv("NAUTILUS_SEALING_KMS_KEY_ID")
+	if keyID == "" {
+		return nil, fmt.Errorf("NAUTILUS_SEALING_KMS_KEY_ID is required for the %s sealing provider", sealingProviderNitroKMS)
+	}
+
+	var stored nitroSealingRoot
+	path := statePath("sealing-root.kms")
+	found, err := loadJSONState(path, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		fields, err := runKMSTool("decrypt", "--ciphertext", stored.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unseal root key (enclave measurement may not match the KMS key policy): %v", err)
+		}
+		return decodeKMSPlaintext(fields)
+	}
+
+	fields, err := runKMSTool("genkey", "--key-id", keyID, "--key-spec", "AES-256")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sealing root key: %v", err)
+	}
+	if fields["CIPHERTEXT"] == "" {
+		return nil, fmt.Errorf("kmstool_enclave_cli returned no ciphertext")
+	}
+	root, err := decodeKMSPlaintext(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveJSONState(path, nitroSealingRoot{KeyID: keyID, Ciphertext: fields["CIPHERTEXT"]}); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// runKMSTool - kmstool_enclave_cli 실행 (vsock-proxy 경유, 요청에 NSM 증명 문서 첨부)
+// AWS 자격 증명은 키 정책의 PCR 조건 없이는 복호화에 쓸 수 없음
+func runKMSTool(command string, args ...string) (map[string]string, error) {
+	region := getEnvOrDefault("NAUTILUS_SEALING_KMS_REGION", os.Getenv("AWS_REGION"))
+	cmdArgs := []string{command, "--region", region, "--proxy-port", getEnvOrDefault("NAUTILUS_SEALING_KMS_PROXY_PORT", "8000")}
+	for flag, env := range map[string]string{
+		"--aws-access-key-id":     "AWS_ACCESS_KEY_ID",
+		"--aws-secret-access-key": "AWS_SECRET_ACCESS_KEY",
+		"--aws-session-token":     "AWS_SESSION_TOKEN",
+	} {
+		if value := os.Getenv(env); value != "" {
+			cmdArgs = append(cmdArgs, flag, value)
+		}
+	}
+	cmdArgs = append(cmdArgs, args...)
+
+	output, err := exec.Command("kmstool_enclave_cli", cmdArgs...).Output()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if label, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			fields[strings.TrimSpace(label)] = strings.TrimSpace(value)
+		}
+	}
+	return fields, nil
+}
+
+// decodeKMSPlaintext - PLAINTEXT: <base64> 필드의 32바이트 데이터 키
+func decodeKMSPlaintext(fields map[string]string) ([]byte, error) {
+	root, err := base64.StdEncoding.DecodeString(fields["PLAINTEXT"])
+	if err != nil || len(root) != 32 {
+		return nil, fmt.Errorf("kmstool_enclave_cli returned an invalid data key")
+	}
+	return root, nil
+}
+
+// Load - 스펙에 따라 키를 가져오거나 봉인 blob에서 열거나 생성 (가져오기 전용 키가 없으면 nil)
+func (k *EnclaveKeyring) Load(spec enclaveKeySpec) (EnclaveSigner, error) {
+	imported, origin, err := k.readImport(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sealedPath := statePath(spec.name + ".sealed")
+	var blob sealedKeyBlob
+	found, err := loadJSONState(sealedPath, &blob)
+	if err != nil {
+		return nil, err
+	}
+	var sealed crypto.Signer
+	if found {
+		if sealed, err = k.unseal(spec.name, &blob); err != nil {
+			return nil, err
+		}
+	}
+
+	key, source := sealed, "sealed"
+	switch {
+	case imported != nil && sealed != nil && samePublicKey(imported, sealed):
+		k.logger.Infof("🔐 Key from %s matches the sealed %s key", origin, spec.name)
+	case imported != nil:
+		if sealed != nil {
+			k.logger.Warnf("🔁 Replacing sealed %s key with the one from %s", spec.name, origin)
+		}
+		key, source = imported, "imported"
+	case sealed == nil && spec.legacyFile != "":
+		if key, err = k.readLegacyFile(spec); err != nil {
+			return nil, err
+		}
+		source = "migrated"
+	}
+	if key == nil && spec.generate != nil {
+		if key, err = spec.generate(); err != nil {
+			return nil, fmt.Errorf("failed to generate %s key: %v", spec.name, err)
+		}
+		source = "generated"
+	}
+	if key == nil {
+		return nil, nil
+	}
+
+	if source != "sealed" {
+		if blob, err = k.seal(spec.name, key); err != nil {
+			return nil, err
+		}
+		if err := saveJSONState(sealedPath, blob); err != nil {
+			return nil, err
+		}
+		k.logger.Infof("🔐 Sealed %s key (%s)", spec.name, source)
+	}
+	if source == "migrated" {
+		if err := os.Remove(spec.legacyFile); err != nil {
+			k.logger.Warnf("⚠️ Failed to remove plaintext %s key %s: %v", spec.name, spec.legacyFile, err)
+		}
+	}
+
+	k.mutex.Lock()
+	k.keys[spec.name] = key
+	k.info[spec.name] = sealedKeyInfo{source: source, sealedAt: blob.SealedAt}
+	k.mutex.Unlock()
+	return &enclaveKey{key: key}, nil
+}
+
+// readImport - 가져오기 디렉토리, 키 파일, 이전 환경 변수 순으로 확인 (환경 변수는 읽은 뒤 unset)
+func (k *EnclaveKeyring) readImport(spec enclaveKeySpec) (crypto.Signer, string, error) {
+	if dir := os.Getenv("NAUTILUS_KEY_IMPORT_DIR"); dir != "" {
+		path := filepath.Join(dir, spec.name+".key")
+		if data, err := os.ReadFile(path); err == nil {
+			key, err := spec.parse(data)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid %s: %v", path, err)
+			}
+			if err := os.Remove(path); err != nil {
+				k.logger.Warnf("⚠️ Failed to remove imported key file %s: %v", path, err)
+			}
+			return key, path, nil
+		}
+	}
+
+	if spec.importFile != "" {
+		if path := os.Getenv(spec.importFile); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read %s: %v", spec.importFile, err)
+			}
+			key, err := spec.parse(data)
+			return key, spec.importFile, err
+		}
+	}
+
+	if spec.envVar != "" {
+		value := os.Getenv(spec.envVar)
+		os.Unsetenv(spec.envVar)
+		if value != "" {
+			k.logger.Warnf("⚠️ %s is set in the environment; it has been sealed, remove it from the service definition", spec.envVar)
+			key, err := spec.parse([]byte(value))
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid %s: %v", spec.envVar, err)
+			}
+			return key, spec.envVar, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// readLegacyFile - 이전 버전이 JSON 문자열로 저장한 평문 키
+func (k *EnclaveKeyring) readLegacyFile(spec enclaveKeySpec) (crypto.Signer, error) {
+	var stored string
+	found, err := loadJSONState(spec.legacyFile, &stored)
+	if err != nil || !found {
+		return nil, err
+	}
+	key, err := spec.parse([]byte(stored))
+	if err != nil {
+		return nil, fmt.Errorf("invalid plaintext %s key %s: %v", spec.name, spec.legacyFile, err)
+	}
+	return key, nil
+}
+
+// seal - PKCS#8로 직렬화 후 이름을 AAD로 묶어 암호화 (다른 이름의 blob으로 바꿔치기 불가)
+func (k *EnclaveKeyring) seal(name string, key crypto.Signer) (sealedKeyBlob, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return sealedKeyBlob{}, fmt.Errorf("failed to encode %s key: %v", name, err)
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return sealedKeyBlob{}, err
+	}
+	return sealedKeyBlob{
+		Version:  sealedKeyVersion,
+		Provider: k.provider,
+		RootID:   k.rootID,
+		Nonce:    nonce,
+		Sealed:   k.aead.Seal(nil, nonce, der, []byte(sealedKeyLabel+"/"+name)),
+		SealedAt: time.Now().UTC(),
+	}, nil
+}
+
+// unseal - 같은 루트 키로 봉인된 blob만 복호화
+func (k *EnclaveKeyring) unseal(name string, blob *sealedKeyBlob) (crypto.Signer, error) {
+	if blob.Version != sealedKeyVersion {
+		return nil, fmt.Errorf("sealed %s key has unsupported version %d", name, blob.Version)
+	}
+	if blob.RootID != k.rootID {
+		return nil, fmt.Errorf("sealed %s key belongs to sealing root %s (%s), this enclave has %s (%s)",
+			name, blob.RootID, blob.Provider, k.rootID, k.provider)
+	}
+	der, err := k.aead.Open(nil, blob.Nonce, blob.Sealed, []byte(sealedKeyLabel+"/"+name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal %s key: %v", name, err)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sealed %s key: %v", name, err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("sealed %s key is not a signing key", name)
+	}
+	return key, nil
+}
+
+// DeriveX25519 - Ed25519 키 시드에서 X25519 합의 키 파생 (기존 Sealed Secret 수신자 키와 같은 값)
+func (k *EnclaveKeyring) DeriveX25519(name, label string) (EnclaveAgreement, error) {
+	k.mutex.Lock()
+	key, ok := k.keys[name].(ed25519.PrivateKey)
+	k.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no Ed25519 key %q in the enclave keyring", name)
+	}
+	seed := sha256.Sum256(append([]byte(label), key.Seed()...))
+	return ecdh.X25519().NewPrivateKey(seed[:])
+}
+
+// samePublicKey - 가져온 키와 봉인된 키 비교
+func samePublicKey(a, b crypto.Signer) bool {
+	public, ok := a.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(b.Public())
+}
+
+// writeMetrics - 봉인 제공자와 키별 출처
+func (k *EnclaveKeyring) writeMetrics(w io.Writer) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	hardware := 0.0
+	if k.provider != sealingProviderLocal {
+		hardware = 1
+	}
+	writeMetricHeader(w, "nautilus_sealing_hardware_backed", "gauge", "Whether master keys are sealed by a TEE-bound root key")
+	writeMetric(w, "nautilus_sealing_hardware_backed", map[string]string{"provider": k.provider}, hardware)
+
+	names := make([]string, 0, len(k.info))
+	for name := range k.info {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeMetricHeader(w, "nautilus_sealed_key_sealed_timestamp_seconds", "gauge", "When each master key was last sealed, by how it entered the enclave")
+	for _, name := range names {
+		info := k.info[name]
+		writeMetric(w, "nautilus_sealed_key_sealed_timestamp_seconds", map[string]string{"key": name, "source": info.source}, float64(info.sealedAt.Unix()))
+	}
+}
+
+// parseEd25519Seed - 32바이트 hex seed (0x 접두사 허용)
+func parseEd25519Seed(data []byte) (crypto.Signer, error) {
+	seed, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("expected 32-byte hex seed")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// parseSuiPrivateKey - suiprivkey1… (Bech32), base64(flag||seed) 또는 hex seed 형식의 Ed25519 키
+func parseSuiPrivateKey(data []byte) (crypto.Signer, error) {
+	value := strings.TrimSpace(string(data))
+	var raw []byte
+	switch {
+	case strings.HasPrefix(value, "suiprivkey1"):
+		decoded, err := decodeBech32("suiprivkey", value)
+		if err != nil {
+			return nil, err
+		}
+		raw = decoded
+	case len(value) == 64 || strings.HasPrefix(value, "0x"):
+		return parseEd25519Seed([]byte(value))
+	default:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("unrecognized Sui private key format")
+		}
+		raw = decoded
+	}
+	if len(raw) == ed25519.SeedSize+1 {
+		if raw[0] != 0x00 {
+			return nil, fmt.Errorf("only Ed25519 Sui keys are supported (scheme flag %d)", raw[0])
+		}
+		raw = raw[1:]
+	}
+	if len(raw) != ed25519.SeedSize {
+		return nil, fmt.Errorf("Sui private key must be a 32-byte Ed25519 seed")
+	}
+	return ed25519.NewKeyFromSeed(raw), nil
+}
+
+// decodeBech32 - BIP-173 Bech32 디코딩 (체크섬 확인, 5비트 → 8비트 변환)
+func decodeBech32(hrp, value string) ([]byte, error) {
+	const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	value = strings.ToLower(value)
+	if !strings.HasPrefix(value, hrp+"1") || len(value) < len(hrp)+8 {
+		return nil, fmt.Errorf("not a %s Bech32 string", hrp)
+	}
+	var data []byte
+	for _, c := range value[len(hrp)+1:] {
+		index := strings.IndexRune(charset, c)
+		if index < 0 {
+			return nil, fmt.Errorf("invalid Bech32 character %q", c)
+		}
+		data = append(data, byte(index))
+	}
+
+	var expanded []byte
+	for _, c := range []byte(hrp) {
+		expanded = append(expanded, c>>5)
+	}
+	expanded = append(expanded, 0)
+	for _, c := range []byte(hrp) {
+		expanded = append(expanded, c&31)
+	}
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, v := range append(expanded, data...) {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+	if checksum != 1 {
+		return nil, fmt.Errorf("invalid Bech32 checksum")
+	}
+
+	var out []byte
+	acc, bits := 0, 0
+	for _, v := range data[:len(data)-6] {
+		acc = acc<<5 | int(v)
+		bits += 5
+		for bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>uint(bits)))
+		}
+		acc &= (1 << uint(bits)) - 1
+	}
+	return out, nil
+}
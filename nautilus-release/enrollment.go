@@ -0,0 +1,507 @@
+// Worker Enrollment - 승인 모드에서 신규 워커 등록을 대기열에 두고 운영자/거버넌스 심사 또는 자동 승인 규칙으로 풀에 추가
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+NAUTILUS_ENROLLMENT_MODE
+  - auto (기본)  스테이킹한 워커는 바로 풀에 추가 (기존 동작)
+  - approval     새 워커는 대기열에 두고, 승인된 워커만 풀에 추가 (조인 토큰/스케줄링 대상)
+
+자동 승인 규칙 (설정한 조건을 모두 만족하면 승인, 조건이 하나도 없으면 전부 수동 심사):
+
+	min_stake  NAUTILUS_ENROLLMENT_AUTO_APPROVE_MIN_STAKE  스테이크(MIST) 하한
+	regions    NAUTILUS_ENROLLMENT_AUTO_APPROVE_REGIONS    워커가 등록 요청에 보낸 리전 (쉼표 구분)
+
+PUT /api/v1/admin/enrollments로 바꾼 규칙은 enrollment.json에 저장되어 환경변수보다 우선합니다.
+리전은 워커가 /api/v1/nodes/register를 호출할 때 알 수 있으므로, 그 전까지 리전 규칙은 맞지 않습니다.
+대기 중인 워커의 등록 요청에는 202와 Retry-After를 돌려주고, 거절된 워커에는 403을 돌려줍니다.
+
+심사: 관리자 토큰으로 POST /api/v1/admin/enrollments, 또는 레지스트리 관리자(거버넌스)가
+worker_enrollment::decide_enrollment를 호출하면 EnrollmentDecidedEvent로 반영합니다.
+승인 모드를 처음 켠 시각 이전에 온체인에 등록된 워커는 기존 워커로 보고 자동 승인합니다.
+*/
+
+const (
+	enrollmentModeAuto     = "auto"
+	enrollmentModeApproval = "approval"
+
+	enrollmentPending  = "pending"
+	enrollmentApproved = "approved"
+	enrollmentRejected = "rejected"
+
+	enrollmentRetryAfter = 30 // 대기 중 등록 요청 재시도 간격 (초)
+)
+
+var (
+	errEnrollmentNotFound = errors.New("no enrollment for node")
+	errEnrollmentConflict = errors.New("enrollment cannot change")
+)
+
+// EnrollmentRules - 자동 승인 규칙
+type EnrollmentRules struct {
+	MinStake uint64   `json:"min_stake"`
+	Regions  []string `json:"regions"`
+}
+
+// match - 규칙을 모두 만족하는지와 근거
+func (r EnrollmentRules) match(e *Enrollment) (bool, string) {
+	if r.MinStake == 0 && len(r.Regions) == 0 {
+		return false, ""
+	}
+	var reasons []string
+	if r.MinStake > 0 {
+		if e.StakeAmount < r.MinStake {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("stake %d >= %d", e.StakeAmount, r.MinStake))
+	}
+	if len(r.Regions) > 0 {
+		allowed := false
+		for _, region := range r.Regions {
+			allowed = allowed || (e.Region != "" && strings.EqualFold(region, e.Region))
+		}
+		if !allowed {
+			return false, ""
+		}
+		reasons = append(reasons, "region "+e.Region)
+	}
+	return true, strings.Join(reasons, ", ")
+}
+
+// Enrollment - 워커 한 대의 등록 심사 기록
+type Enrollment struct {
+	NodeID      string    `json:"node_id"`
+	Owner       string    `json:"owner"`
+	Role        string    `json:"role"`
+	StakeAmount uint64    `json:"stake_amount"`
+	Region      string    `json:"region,omitempty"`
+	Zone        string    `json:"zone,omitempty"`
+	Status      string    `json:"status"`
+	DecidedBy   string    `json:"decided_by,omitempty"` // admin | rule | chain:<reviewer> | existing
+	Note        string    `json:"note,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+	DecidedAt   time.Time `json:"decided_at,omitempty"`
+}
+
+// enrollmentState - enrollment.json
+type enrollmentState struct {
+	EnabledAt   time.Time              `json:"enabled_at,omitempty"`
+	Rules       *EnrollmentRules       `json:"rules,omitempty"`
+	Enrollments map[string]*Enrollment `json:"enrollments"`
+}
+
+// EnrollmentQueue - 등록 승인 대기열
+type EnrollmentQueue struct {
+	logger     *logrus.Logger
+	sui        *SuiIntegration
+	history    *HeartbeatHistory
+	adminToken string
+	mode       string
+	stateFile  string
+
+	mutex      sync.Mutex
+	state      enrollmentState
+	rules      EnrollmentRules
+	candidates map[string]*WorkerNode // 심사 중/거절된 워커의 온체인 정보 (승인 시 풀에 추가)
+	decisions  map[string]uint64      // 결정 주체별 수
+}
+
+// NewEnrollmentQueue - 환경변수와 저장된 규칙/심사 기록으로 생성
+func NewEnrollmentQueue(logger *logrus.Logger, sui *SuiIntegration) (*EnrollmentQueue, error) {
+	q := &EnrollmentQueue{
+		logger:     logger,
+		sui:        sui,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		mode:       strings.ToLower(getEnvOrDefault("NAUTILUS_ENROLLMENT_MODE", enrollmentModeAuto)),
+		stateFile:  statePath("enrollment.json"),
+		candidates: make(map[string]*WorkerNode),
+		decisions:  make(map[string]uint64),
+	}
+	if q.mode != enrollmentModeAuto && q.mode != enrollmentModeApproval {
+		return nil, fmt.Errorf("unsupported NAUTILUS_ENROLLMENT_MODE %q (auto, approval)", q.mode)
+	}
+
+	if value := os.Getenv("NAUTILUS_ENROLLMENT_AUTO_APPROVE_MIN_STAKE"); value != "" {
+		minStake, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NAUTILUS_ENROLLMENT_AUTO_APPROVE_MIN_STAKE %q", value)
+		}
+		q.rules.MinStake = minStake
+	}
+	q.rules.Regions = splitList(os.Getenv("NAUTILUS_ENROLLMENT_AUTO_APPROVE_REGIONS"))
+
+	if _, err := loadJSONState(q.stateFile, &q.state); err != nil {
+		logger.Warnf("⚠️ Failed to load enrollment state: %v", err)
+	}
+	if q.state.Enrollments == nil {
+		q.state.Enrollments = make(map[string]*Enrollment)
+	}
+	if q.state.Rules != nil {
+		q.rules = *q.state.Rules
+	}
+	if q.mode == enrollmentModeApproval && q.state.EnabledAt.IsZero() {
+		q.state.EnabledAt = time.Now().UTC()
+		q.saveLocked()
+	}
+	if q.mode == enrollmentModeApproval {
+		logger.Infof("📋 Worker enrollment requires approval (auto-approve: min_stake=%d regions=%v)", q.rules.MinStake, q.rules.Regions)
+	}
+	return q, nil
+}
+
+func (q *EnrollmentQueue) saveLocked() {
+	if err := saveJSONState(q.stateFile, q.state); err != nil {
+		q.logger.Warnf("⚠️ Failed to persist enrollment state: %v", err)
+	}
+}
+
+// decideLocked - 심사 결과 기록
+func (q *EnrollmentQueue) decideLocked(e *Enrollment, status, by, note string) {
+	e.Status = status
+	e.DecidedBy = by
+	e.Note = note
+	e.DecidedAt = time.Now().UTC()
+	source := by
+	if strings.HasPrefix(by, "chain:") {
+		source = "chain"
+	}
+	q.decisions[status+"/"+source]++
+	q.saveLocked()
+}
+
+/*
+Admit - 풀에 없는 워커를 추가해도 되는지 (auto 모드는 항상 true)
+처음 보는 워커는 규칙에 맞으면 승인, 아니면 대기열에 넣고 false를 반환합니다.
+*/
+func (q *EnrollmentQueue) Admit(worker *WorkerNode) bool {
+	if q == nil || q.mode != enrollmentModeApproval {
+		return true
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	e, exists := q.state.Enrollments[worker.NodeID]
+	if exists && e.Status == enrollmentApproved {
+		return true
+	}
+	q.candidates[worker.NodeID] = worker
+	if exists && e.Status == enrollmentRejected {
+		return false
+	}
+
+	if !exists {
+		e = &Enrollment{NodeID: worker.NodeID, Status: enrollmentPending, RequestedAt: time.Now().UTC()}
+		q.state.Enrollments[worker.NodeID] = e
+		if !worker.RegisteredAt.IsZero() && worker.RegisteredAt.Before(q.state.EnabledAt) {
+			e.Owner, e.Role, e.StakeAmount = worker.WorkerAddress, worker.Role, worker.StakeAmount
+			delete(q.candidates, worker.NodeID)
+			q.decideLocked(e, enrollmentApproved, "existing", "registered before approval mode was enabled")
+			return true
+		}
+	}
+	// 스테이크 추가 등으로 규칙에 새로 맞을 수 있으므로 온체인 값으로 갱신 후 다시 평가
+	e.Owner, e.Role, e.StakeAmount = worker.WorkerAddress, worker.Role, worker.StakeAmount
+	if ok, reason := q.rules.match(e); ok {
+		delete(q.candidates, worker.NodeID)
+		q.decideLocked(e, enrollmentApproved, "rule", reason)
+		q.logger.Infof("✅ Worker %s enrollment auto-approved (%s)", worker.NodeID, reason)
+		return true
+	}
+
+	if !exists {
+		q.saveLocked()
+		q.logger.Warnf("📋 Worker %s (owner %s, stake %d) is waiting for enrollment approval", worker.NodeID, worker.WorkerAddress, worker.StakeAmount)
+		q.history.RecordEvent(worker.NodeID, "enrollment_pending", "waiting for operator approval")
+	}
+	return false
+}
+
+/*
+ObserveRegistration - 대기 중/거절된 워커의 등록 요청 (Seal 토큰으로 식별)
+리전/존을 기록하고 규칙을 다시 평가하여 승인되면 풀에 추가합니다.
+심사 대상이 아니면 nil (조인 토큰 발급 경로에서 판단).
+*/
+func (q *EnrollmentQueue) ObserveRegistration(sealToken, nodeID, region, zone string) *Enrollment {
+	if q == nil || q.mode != enrollmentModeApproval || sealToken == "" {
+		return nil
+	}
+
+	q.mutex.Lock()
+	var candidate *WorkerNode
+	for _, worker := range q.candidates {
+		if subtle.ConstantTimeCompare([]byte(worker.SealToken), []byte(sealToken)) == 1 && (nodeID == "" || worker.NodeID == nodeID) {
+			candidate = worker
+			break
+		}
+	}
+	if candidate == nil {
+		q.mutex.Unlock()
+		return nil
+	}
+	e := q.state.Enrollments[candidate.NodeID]
+	if e.Status == enrollmentPending && (e.Region != region || e.Zone != zone) {
+		e.Region, e.Zone = region, zone
+		if ok, reason := q.rules.match(e); ok {
+			delete(q.candidates, candidate.NodeID)
+			q.decideLocked(e, enrollmentApproved, "rule", reason)
+			q.logger.Infof("✅ Worker %s enrollment auto-approved (%s)", candidate.NodeID, reason)
+		} else {
+			q.saveLocked()
+		}
+	}
+	result := *e
+	q.mutex.Unlock()
+
+	if result.Status == enrollmentApproved {
+		q.sui.addRegisteredWorker(candidate)
+	}
+	return &result
+}
+
+// Decide - 운영자/거버넌스 심사 (승인은 대기 중이거나 거절된 워커, 거절은 대기 중인 워커만)
+func (q *EnrollmentQueue) Decide(nodeID string, approve bool, by, note string) (*Enrollment, error) {
+	if q.mode != enrollmentModeApproval {
+		return nil, fmt.Errorf("%w: enrollment approval is disabled (NAUTILUS_ENROLLMENT_MODE=%s)", errEnrollmentConflict, q.mode)
+	}
+
+	q.mutex.Lock()
+	e, exists := q.state.Enrollments[nodeID]
+	if !exists {
+		if !strings.HasPrefix(by, "chain:") {
+			q.mutex.Unlock()
+			return nil, fmt.Errorf("%w %s", errEnrollmentNotFound, nodeID)
+		}
+		// 마스터가 등록을 보기 전에 거버넌스가 먼저 결정한 경우에도 결과는 남김
+		e = &Enrollment{NodeID: nodeID, Status: enrollmentPending, RequestedAt: time.Now().UTC()}
+		q.state.Enrollments[nodeID] = e
+	}
+
+	status := enrollmentRejected
+	if approve {
+		status = enrollmentApproved
+	}
+	switch {
+	case e.Status == status:
+		result := *e
+		q.mutex.Unlock()
+		return &result, nil
+	case e.Status == enrollmentApproved:
+		q.mutex.Unlock()
+		return nil, fmt.Errorf("%w: %s is already approved (remove it from the registry instead)", errEnrollmentConflict, nodeID)
+	}
+	q.decideLocked(e, status, by, note)
+	candidate := q.candidates[nodeID]
+	if approve {
+		delete(q.candidates, nodeID)
+	}
+	result := *e
+	q.mutex.Unlock()
+
+	q.logger.Infof("📋 Worker %s enrollment %s by %s", nodeID, status, by)
+	q.history.RecordEvent(nodeID, "enrollment_"+status, strings.TrimSpace(by+" "+note))
+	if approve {
+		if candidate != nil {
+			q.sui.addRegisteredWorker(candidate)
+		} else if err := q.sui.poolSync.SyncWorker(nodeID); err != nil {
+			// 다음 정합성 검사에서 다시 추가
+			q.logger.Warnf("⚠️ Approved worker %s could not be loaded from chain yet: %v", nodeID, err)
+		}
+	}
+	return &result, nil
+}
+
+// handleChainEvent - worker_enrollment::EnrollmentDecidedEvent 반영
+func (q *EnrollmentQueue) handleChainEvent(event *SuiContractEvent) {
+	if q == nil {
+		return
+	}
+	nodeID, _ := event.EventData["node_id"].(string)
+	approved, _ := event.EventData["approved"].(bool)
+	note, _ := event.EventData["note"].(string)
+	reviewer, _ := event.EventData["reviewer"].(string)
+
+	if _, err := q.Decide(nodeID, approved, "chain:"+reviewer, note); err != nil {
+		q.logger.Warnf("⚠️ Ignoring on-chain enrollment decision for %s: %v", nodeID, err)
+	}
+}
+
+// SetRules - 자동 승인 규칙 변경 후 대기 중인 워커 재평가, 새로 승인된 노드 목록 반환
+func (q *EnrollmentQueue) SetRules(rules EnrollmentRules) []string {
+	q.mutex.Lock()
+	q.rules = rules
+	q.state.Rules = &rules
+	var approved []*WorkerNode
+	for nodeID, e := range q.state.Enrollments {
+		if e.Status != enrollmentPending {
+			continue
+		}
+		if ok, reason := rules.match(e); ok {
+			q.decideLocked(e, enrollmentApproved, "rule", reason)
+			if candidate := q.candidates[nodeID]; candidate != nil {
+				approved = append(approved, candidate)
+				delete(q.candidates, nodeID)
+			}
+		}
+	}
+	q.saveLocked()
+	q.mutex.Unlock()
+
+	q.logger.Infof("📋 Enrollment auto-approve rules updated: min_stake=%d regions=%v", rules.MinStake, rules.Regions)
+	nodes := make([]string, 0, len(approved))
+	for _, worker := range approved {
+		q.sui.addRegisteredWorker(worker)
+		nodes = append(nodes, worker.NodeID)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Enrollments - 요청 시각 순 심사 기록 (status가 비어 있으면 전체)
+func (q *EnrollmentQueue) Enrollments(status string) []Enrollment {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	result := make([]Enrollment, 0, len(q.state.Enrollments))
+	for _, e := range q.state.Enrollments {
+		if status == "" || e.Status == status {
+			result = append(result, *e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RequestedAt.Before(result[j].RequestedAt) })
+	return result
+}
+
+func (q *EnrollmentQueue) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if q.adminToken == "" {
+		http.Error(w, "Enrollment API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(q.adminToken)) != 1 {
+		q.logger.Warnf("🚫 Unauthorized enrollment API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func (q *EnrollmentQueue) overview(status string) map[string]interface{} {
+	q.mutex.Lock()
+	rules := q.rules
+	q.mutex.Unlock()
+	return map[string]interface{}{"mode": q.mode, "rules": rules, "enrollments": q.Enrollments(status)}
+}
+
+/*
+handleEnrollments - 등록 승인 대기열 (/api/v1/admin/enrollments, 관리자 토큰 필요)
+
+	GET  ?status=pending|approved|rejected                                   모드, 규칙, 심사 기록
+	POST {"node_id": "...", "action": "approve|reject", "note": "..."}      심사
+	PUT  {"min_stake": 0, "regions": []}                                     자동 승인 규칙 변경 (대기 중인 워커 재평가)
+*/
+func (q *EnrollmentQueue) handleEnrollments(w http.ResponseWriter, r *http.Request) {
+	if !q.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		status := r.URL.Query().Get("status")
+		if status != "" && status != enrollmentPending && status != enrollmentApproved && status != enrollmentRejected {
+			http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, q.overview(status))
+
+	case http.MethodPost:
+		var body struct {
+			NodeID string `json:"node_id"`
+			Action string `json:"action"`
+			Note   string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.NodeID == "" {
+			http.Error(w, "node_id is required", http.StatusBadRequest)
+			return
+		}
+		if body.Action != "approve" && body.Action != "reject" {
+			http.Error(w, "action must be approve or reject", http.StatusBadRequest)
+			return
+		}
+		enrollment, err := q.Decide(body.NodeID, body.Action == "approve", "admin", body.Note)
+		switch {
+		case errors.Is(err, errEnrollmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, errEnrollmentConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			writeClientJSON(w, enrollment)
+		}
+
+	case http.MethodPut:
+		var rules EnrollmentRules
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&rules); err != nil {
+			http.Error(w, "Invalid enrollment rules: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		approved := q.SetRules(rules)
+		overview := q.overview("")
+		overview["approved"] = approved
+		writeClientJSON(w, overview)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 심사 상태별 워커 수와 결정 주체별 결정 수
+func (q *EnrollmentQueue) writeMetrics(w io.Writer) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	counts := map[string]int{enrollmentPending: 0, enrollmentApproved: 0, enrollmentRejected: 0}
+	for _, e := range q.state.Enrollments {
+		counts[e.Status]++
+	}
+	approval := 0.0
+	if q.mode == enrollmentModeApproval {
+		approval = 1
+	}
+	writeMetricHeader(w, "nautilus_enrollment_approval_required", "gauge", "Whether new workers need enrollment approval")
+	writeMetric(w, "nautilus_enrollment_approval_required", nil, approval)
+	writeMetricHeader(w, "nautilus_enrollments", "gauge", "Worker enrollments by review status")
+	for _, status := range []string{enrollmentPending, enrollmentApproved, enrollmentRejected} {
+		writeMetric(w, "nautilus_enrollments", map[string]string{"status": status}, float64(counts[status]))
+	}
+
+	keys := make([]string, 0, len(q.decisions))
+	for key := range q.decisions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeMetricHeader(w, "nautilus_enrollment_decisions_total", "counter", "Enrollment decisions since start, by outcome and source")
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 2)
+		writeMetric(w, "nautilus_enrollment_decisions_total", map[string]string{"status": parts[0], "source": parts[1]}, float64(q.decisions[key]))
+	}
+}
@@ -0,0 +1,46 @@
+// Errors - 운영자가 바로 조치할 수 있는 오류 코드와 해결 방법
+package main
+
+import "fmt"
+
+// 오류 코드
+const (
+	ErrCodeRPCUnreachable    = "E_RPC_UNREACHABLE"
+	ErrCodeContractNotFound  = "E_CONTRACT_NOT_FOUND"
+	ErrCodeContractVersion   = "E_CONTRACT_VERSION_MISMATCH"
+	ErrCodeObjectUnreadable  = "E_OBJECT_UNREADABLE"
+	ErrCodeAttestationFailed = "E_TEE_ATTESTATION_FAILED"
+	ErrCodeClockSkew         = "E_CLOCK_SKEW"
+	ErrCodeContractPinned    = "E_CONTRACT_PACKAGE_MISMATCH"
+)
+
+// UserFriendlyError - 코드, 설명, 해결 방법을 포함한 오류
+type UserFriendlyError struct {
+	Code     string
+	Message  string
+	Solution string
+	Cause    error
+}
+
+// NewUserFriendlyError - 새 UserFriendlyError 생성
+func NewUserFriendlyError(code, message, solution string, cause error) *UserFriendlyError {
+	return &UserFriendlyError{
+		Code:     code,
+		Message:  message,
+		Solution: solution,
+		Cause:    cause,
+	}
+}
+
+// Error - error 인터페이스 구현
+func (e *UserFriendlyError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+// Unwrap - 원인 오류 반환
+func (e *UserFriendlyError) Unwrap() error {
+	return e.Cause
+}
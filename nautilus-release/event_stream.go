@@ -0,0 +1,523 @@
+// Event Stream - 대시보드용 WebSocket 실시간 이벤트 (노드 참여/이탈, Pod 상태, 슬래싱, 용량)
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	streamBufferSize   = 256              // 구독자별 대기 이벤트 상한 (넘으면 느린 구독자로 보고 연결 종료)
+	streamPingInterval = 30 * time.Second // 연결 유지 ping 주기
+	streamPongWait     = 75 * time.Second // pong 없이 기다리는 최대 시간
+	streamWriteTimeout = 10 * time.Second
+	streamMaxMessage   = 4096 // 클라이언트 구독 변경 메시지 크기 상한
+)
+
+// 이벤트 범주 (구독 필터의 types에 범주 또는 정확한 타입 사용)
+const (
+	streamNode     = "node"     // node.joined, node.left, node.status, node.event
+	streamPod      = "pod"      // pod.phase, pod.deleted
+	streamSlashing = "slashing" // slashing.status, slashing.review, slashing.appeal
+	streamCapacity = "capacity" // capacity.changed
+)
+
+// StreamEvent - 구독자에게 보내는 이벤트
+type StreamEvent struct {
+	ID        uint64      `json:"id"`
+	Type      string      `json:"type"`
+	NodeID    string      `json:"node_id,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+
+	owner string // 노드 소유 지갑 (테넌트 구독자 권한 확인용)
+}
+
+/*
+streamFilter - 구독 필터 (연결 시 쿼리 또는 연결 후 subscribe 메시지로 지정)
+
+	types       범주(node, pod, slashing, capacity) 또는 정확한 타입(pod.phase), 비어 있으면 전체
+	nodes       노드 ID (노드에 속한 이벤트에만 적용)
+	namespaces  Pod 이벤트의 네임스페이스
+*/
+type streamFilter struct {
+	Types      []string `json:"types"`
+	Nodes      []string `json:"nodes"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// matches - 이벤트가 필터를 통과하는지
+func (f streamFilter) matches(event *StreamEvent) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, t := range f.Types {
+			if event.Type == t || strings.HasPrefix(event.Type, t+".") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Nodes) > 0 && event.NodeID != "" && !containsString(f.Nodes, event.NodeID) {
+		return false
+	}
+	if len(f.Namespaces) > 0 && strings.HasPrefix(event.Type, streamPod+".") {
+		data, _ := event.Data.(map[string]interface{})
+		namespace, _ := data["namespace"].(string)
+		if !containsString(f.Namespaces, namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// streamSubscriber - WebSocket 연결 하나
+type streamSubscriber struct {
+	admin  bool
+	wallet string
+	events chan *StreamEvent
+	closed chan struct{} // 느린 구독자로 끊길 때 닫힘
+	once   sync.Once
+	mutex  sync.Mutex
+	filter streamFilter
+}
+
+// allowed - 관리자는 전체, 테넌트는 자기 노드의 이벤트와 클러스터 용량만
+func (s *streamSubscriber) allowed(event *StreamEvent) bool {
+	if s.admin || event.Type == streamCapacity+".changed" {
+		return true
+	}
+	return event.owner != "" && event.owner == s.wallet
+}
+
+func (s *streamSubscriber) setFilter(filter streamFilter) {
+	s.mutex.Lock()
+	s.filter = filter
+	s.mutex.Unlock()
+}
+
+func (s *streamSubscriber) wants(event *StreamEvent) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.allowed(event) && s.filter.matches(event)
+}
+
+func (s *streamSubscriber) drop() {
+	s.once.Do(func() { close(s.closed) })
+}
+
+// EventStream - 이벤트 발행 및 구독자 관리
+type EventStream struct {
+	logger      *logrus.Logger
+	k3sMgr      *K3sManager
+	workerPool  *WorkerPool
+	capacity    *CapacityPublisher
+	alerts      *AlertManager // 발행한 이벤트로 event 알림 규칙 판정
+	adminToken  string
+	maxClients  int
+	podInterval time.Duration
+	upgrader    websocket.Upgrader
+
+	mutex       sync.Mutex
+	subscribers map[*streamSubscriber]bool
+	pods        map[string]podState // 마지막 Pod 조회 결과 (구독자가 없으면 비움)
+
+	nextID    atomic.Uint64
+	published atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// podState - Pod 상태 변화 비교용
+type podState struct {
+	Namespace string
+	Name      string
+	NodeName  string
+	Phase     string
+}
+
+/*
+NewEventStream - Event Stream 생성
+
+	NAUTILUS_STREAM_MAX_CLIENTS       동시 연결 상한 (기본 100)
+	NAUTILUS_STREAM_POD_INTERVAL      Pod 상태 조회 주기(초, 기본 5, 구독자가 있을 때만 조회)
+	NAUTILUS_STREAM_ALLOWED_ORIGINS   브라우저 Origin 허용 목록 (쉼표 구분, *는 전체, 기본 같은 호스트만)
+*/
+func NewEventStream(logger *logrus.Logger, k3sMgr *K3sManager) *EventStream {
+	maxClients, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_STREAM_MAX_CLIENTS", "100"))
+	if err != nil || maxClients <= 0 {
+		maxClients = 100
+	}
+
+	s := &EventStream{
+		logger:      logger,
+		k3sMgr:      k3sMgr,
+		workerPool:  k3sMgr.workerPool,
+		adminToken:  os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		maxClients:  maxClients,
+		podInterval: envSeconds("NAUTILUS_STREAM_POD_INTERVAL", 5),
+		subscribers: make(map[*streamSubscriber]bool),
+	}
+	allowedOrigins := splitList(os.Getenv("NAUTILUS_STREAM_ALLOWED_ORIGINS"))
+	s.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin:     func(r *http.Request) bool { return originAllowed(r, allowedOrigins) },
+	}
+	return s
+}
+
+// originAllowed - Origin 헤더가 없으면(비브라우저 클라이언트) 허용, 있으면 같은 호스트 또는 허용 목록
+func originAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if containsString(allowed, "*") || containsString(allowed, origin) {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// Publish - 이벤트 발행 (구독자 채널이 가득 차면 해당 구독자만 끊고 다른 구독자는 계속 수신)
+func (s *EventStream) Publish(eventType, nodeID, owner string, data interface{}) {
+	if s == nil {
+		return
+	}
+	s.alerts.ObserveEvent(eventType, nodeID, data)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.subscribers) == 0 {
+		return
+	}
+
+	event := &StreamEvent{
+		ID:        s.nextID.Add(1),
+		Type:      eventType,
+		NodeID:    nodeID,
+		Timestamp: time.Now(),
+		Data:      data,
+		owner:     owner,
+	}
+	s.published.Add(1)
+
+	for sub := range s.subscribers {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.dropped.Add(1)
+			sub.drop()
+		}
+	}
+}
+
+// PublishWorker - 워커 풀 변경 (워커 풀 잠금 안에서 호출되므로 풀을 다시 조회하지 않음)
+func (s *EventStream) PublishWorker(eventType string, worker *WorkerNode, data map[string]interface{}) {
+	if s == nil || worker == nil {
+		return
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["status"] = worker.Status
+	data["role"] = worker.Role
+	data["stake_amount"] = worker.StakeAmount
+	s.Publish(eventType, worker.NodeID, worker.WorkerAddress, data)
+}
+
+// PublishNodeEvent - 노드 타임라인 이벤트 (슬래싱 검토/이의 신청은 slashing 범주)
+func (s *EventStream) PublishNodeEvent(nodeID, kind, detail string) {
+	if s == nil {
+		return
+	}
+
+	eventType := streamNode + ".event"
+	switch {
+	case kind == "status":
+		return // 워커 풀의 node.status로 발행됨
+	case kind == "slashing_review":
+		eventType = streamSlashing + ".review"
+	case strings.HasPrefix(kind, "appeal_"):
+		eventType = streamSlashing + ".appeal"
+	}
+
+	owner := ""
+	if worker, exists := s.workerPool.GetWorker(nodeID); exists {
+		owner = worker.WorkerAddress
+	}
+	s.Publish(eventType, nodeID, owner, map[string]interface{}{"kind": kind, "detail": detail})
+}
+
+// subscriberCount - 현재 연결 수
+func (s *EventStream) subscriberCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.subscribers)
+}
+
+// Start - 구독자가 있는 동안 Pod 상태 변화 감시
+func (s *EventStream) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.podInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.subscriberCount() == 0 {
+				s.mutex.Lock()
+				s.pods = nil
+				s.mutex.Unlock()
+				continue
+			}
+			if !s.k3sMgr.IsRunning() {
+				continue
+			}
+			if err := s.pollPods(); err != nil {
+				s.logger.Debugf("Event stream pod poll failed: %v", err)
+			}
+		}
+	}
+}
+
+// pollPods - Pod 목록을 이전 조회와 비교해 phase 변화/삭제 발행 (첫 조회는 기준선만 저장)
+func (s *EventStream) pollPods() error {
+	output, err := s.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return err
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Namespace string `json:"namespace"`
+				Name      string `json:"name"`
+				UID       string `json:"uid"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("failed to parse pod list: %v", err)
+	}
+
+	current := make(map[string]podState, len(list.Items))
+	for _, item := range list.Items {
+		current[item.Metadata.UID] = podState{
+			Namespace: item.Metadata.Namespace,
+			Name:      item.Metadata.Name,
+			NodeName:  item.Spec.NodeName,
+			Phase:     item.Status.Phase,
+		}
+	}
+
+	s.mutex.Lock()
+	previous := s.pods
+	s.pods = current
+	s.mutex.Unlock()
+	if previous == nil {
+		return nil
+	}
+
+	for uid, pod := range current {
+		old, existed := previous[uid]
+		if existed && old.Phase == pod.Phase && old.NodeName == pod.NodeName {
+			continue
+		}
+		data := map[string]interface{}{"namespace": pod.Namespace, "name": pod.Name, "uid": uid, "phase": pod.Phase}
+		if existed {
+			data["old_phase"] = old.Phase
+		}
+		s.Publish(streamPod+".phase", pod.NodeName, s.ownerOf(pod.NodeName), data)
+	}
+	for uid, pod := range previous {
+		if _, exists := current[uid]; !exists {
+			s.Publish(streamPod+".deleted", pod.NodeName, s.ownerOf(pod.NodeName),
+				map[string]interface{}{"namespace": pod.Namespace, "name": pod.Name, "uid": uid, "phase": pod.Phase})
+		}
+	}
+	return nil
+}
+
+func (s *EventStream) ownerOf(nodeID string) string {
+	if worker, exists := s.workerPool.GetWorker(nodeID); exists {
+		return worker.WorkerAddress
+	}
+	return ""
+}
+
+// authenticate - 관리자 토큰 또는 seal 토큰 (브라우저 WebSocket은 헤더를 못 넣으므로 ?token= 허용)
+func (s *EventStream) authenticate(r *http.Request) (admin bool, wallet string, ok bool) {
+	token := requestSealToken(r)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		return false, "", false
+	}
+	if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+		return true, "", true
+	}
+	wallet, ok = s.workerPool.OwnerBySealToken(token)
+	return false, wallet, ok
+}
+
+/*
+handleStream - GET /api/v1/stream (WebSocket)
+
+	?types=node,pod&node_id=a,b&namespace=default   초기 구독 필터
+	→ {"action":"subscribe","types":[...],"nodes":[...],"namespaces":[...]}   연결 중 필터 변경
+
+연결 직후 현재 노드 목록(snapshot)을 보내고 이후 이벤트를 JSON으로 하나씩 보냅니다.
+*/
+func (s *EventStream) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	admin, wallet, ok := s.authenticate(r)
+	if !ok {
+		http.Error(w, "Invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+	if s.subscriberCount() >= s.maxClients {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "Too many stream clients", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	sub := &streamSubscriber{
+		admin:  admin,
+		wallet: wallet,
+		events: make(chan *StreamEvent, streamBufferSize),
+		closed: make(chan struct{}),
+		filter: streamFilter{
+			Types:      splitList(query.Get("types")),
+			Nodes:      splitList(query.Get("node_id")),
+			Namespaces: splitList(query.Get("namespace")),
+		},
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade가 오류 응답을 보냄
+	}
+	defer conn.Close()
+
+	s.mutex.Lock()
+	s.subscribers[sub] = true
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.subscribers, sub)
+		s.mutex.Unlock()
+	}()
+	s.logger.Infof("📡 Event stream client connected from %s (admin=%v)", r.RemoteAddr, admin)
+
+	// 읽기: 구독 변경 메시지와 pong 처리, 연결이 끊기면 done 닫힘
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadLimit(streamMaxMessage)
+		conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		})
+		for {
+			var message struct {
+				Action string `json:"action"`
+				streamFilter
+			}
+			if err := conn.ReadJSON(&message); err != nil {
+				if _, isJSON := err.(*json.SyntaxError); isJSON || err == io.ErrUnexpectedEOF {
+					continue
+				}
+				return
+			}
+			if message.Action == "subscribe" {
+				sub.setFilter(message.streamFilter)
+			}
+		}
+	}()
+
+	if err := s.writeSnapshot(conn, sub); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-sub.closed:
+			s.logger.Warnf("🐢 Event stream client %s too slow, disconnecting", r.RemoteAddr)
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "slow consumer"), time.Now().Add(time.Second))
+			return
+		case event := <-sub.events:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeSnapshot - 구독자가 볼 수 있는 현재 노드 목록과 용량
+func (s *EventStream) writeSnapshot(conn *websocket.Conn, sub *streamSubscriber) error {
+	nodes := []WorkerView{}
+	for _, worker := range s.workerPool.ListWorkers() {
+		if sub.admin || worker.WorkerAddress == sub.wallet {
+			nodes = append(nodes, newWorkerView(worker))
+		}
+	}
+	data := map[string]interface{}{"nodes": nodes}
+	if s.capacity != nil {
+		data["capacity"] = s.capacity.Snapshot()
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return conn.WriteJSON(&StreamEvent{ID: s.nextID.Load(), Type: "snapshot", Timestamp: time.Now(), Data: data})
+}
+
+// writeMetrics - 스트림 메트릭 출력
+func (s *EventStream) writeMetrics(w io.Writer) {
+	writeMetricHeader(w, "nautilus_stream_clients", "gauge", "Connected event stream clients")
+	writeMetric(w, "nautilus_stream_clients", nil, float64(s.subscriberCount()))
+	writeMetricHeader(w, "nautilus_stream_events_total", "counter", "Events published to stream clients")
+	writeMetric(w, "nautilus_stream_events_total", nil, float64(s.published.Load()))
+	writeMetricHeader(w, "nautilus_stream_dropped_clients_total", "counter", "Stream clients disconnected for falling behind")
+	writeMetric(w, "nautilus_stream_dropped_clients_total", nil, float64(s.dropped.Load()))
+}
@@ -0,0 +1,245 @@
+// Event Watchdog - 이벤트 조회/처리 루프가 멈췄는지 감시 (준비 상태 해제, 알림, 선택적 재시작)
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// watchdogCheckInterval - 진행 여부 판정 주기
+const watchdogCheckInterval = 10 * time.Second
+
+/*
+EventWatchdog - Sui 이벤트 루프의 진행 시각을 기록하고 멈춤을 감지
+
+조회 루프나 처리 고루틴이 교착되면 /healthz는 정상이지만 이벤트는 하나도 처리되지 않습니다.
+  - checkpoint: 이벤트 조회가 마지막으로 새 체크포인트까지 따라잡은 시각 (Sui 체인만, 체크포인트는 유휴 상태에서도 진행)
+  - processing: 처리 중인 이벤트가 끝나지 않거나, 대기 중인 이벤트가 있는데 마지막 처리 이후 진행이 없는 시간
+
+NAUTILUS_WATCHDOG_CHECKPOINT_STALL_SECONDS(기본 300), NAUTILUS_WATCHDOG_PROCESSING_STALL_SECONDS(기본 300)를
+넘으면 준비 조건 event_loop를 끄고 한 번 알림(NAUTILUS_WATCHDOG_ALERT_WEBHOOK)을 보냅니다.
+NAUTILUS_WATCHDOG_RESTART_SECONDS가 0보다 크면 그만큼 더 멈춰 있을 때 프로세스를 종료해
+서비스 관리자(systemd Restart=on-failure)가 다시 시작하게 합니다.
+*/
+type EventWatchdog struct {
+	logger          *logrus.Logger
+	sui             *SuiIntegration
+	checkpointStall time.Duration
+	processingStall time.Duration
+	restartAfter    time.Duration
+	alertWebhook    string
+	client          *http.Client
+	exit            func(code int)
+
+	mutex            sync.Mutex
+	cursor           uint64
+	cursorAdvancedAt time.Time
+	processedAt      time.Time
+	processingSince  time.Time // 처리 중인 이벤트의 시작 시각 (처리 중이 아니면 zero)
+	processed        uint64
+	stalled          map[string]string    // 멈춘 루프 → 설명
+	stalledSince     map[string]time.Time // 멈춘 루프 → 판정 시각
+	stalls           map[string]int
+}
+
+// NewEventWatchdog - 새 이벤트 루프 감시기 생성
+func NewEventWatchdog(logger *logrus.Logger, sui *SuiIntegration) *EventWatchdog {
+	now := time.Now()
+	return &EventWatchdog{
+		logger:           logger,
+		sui:              sui,
+		checkpointStall:  envSeconds("NAUTILUS_WATCHDOG_CHECKPOINT_STALL_SECONDS", 300),
+		processingStall:  envSeconds("NAUTILUS_WATCHDOG_PROCESSING_STALL_SECONDS", 300),
+		restartAfter:     envSeconds("NAUTILUS_WATCHDOG_RESTART_SECONDS", 0),
+		alertWebhook:     os.Getenv("NAUTILUS_WATCHDOG_ALERT_WEBHOOK"),
+		client:           &http.Client{Timeout: 10 * time.Second},
+		exit:             os.Exit,
+		cursorAdvancedAt: now,
+		processedAt:      now,
+		stalled:          make(map[string]string),
+		stalledSince:     make(map[string]time.Time),
+		stalls:           make(map[string]int),
+	}
+}
+
+// CursorAdvanced - 이벤트 조회가 따라잡은 체크포인트 기록 (nil이면 무시)
+func (w *EventWatchdog) CursorAdvanced(height uint64) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if height > w.cursor {
+		w.cursor = height
+		w.cursorAdvancedAt = time.Now()
+	}
+}
+
+// BeginEvent - 이벤트 처리 시작 (반환한 함수로 종료 기록, nil이면 아무것도 하지 않음)
+func (w *EventWatchdog) BeginEvent() func() {
+	if w == nil {
+		return func() {}
+	}
+	w.mutex.Lock()
+	w.processingSince = time.Now()
+	w.mutex.Unlock()
+
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		w.processingSince = time.Time{}
+		w.processedAt = time.Now()
+		w.processed++
+	}
+}
+
+// Start - 주기적 진행 여부 판정
+func (w *EventWatchdog) Start(ctx context.Context) {
+	w.logger.Infof("🐕 Starting event loop watchdog (checkpoint stall %s, processing stall %s)...", w.checkpointStall, w.processingStall)
+
+	ticker := time.NewTicker(watchdogCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(time.Now())
+		}
+	}
+}
+
+// check - 루프별 멈춤 판정, 상태 전이 시 로그/알림, 재시작 기한 확인
+func (w *EventWatchdog) check(now time.Time) {
+	queued := len(w.sui.eventChan)
+
+	w.mutex.Lock()
+	current := make(map[string]string)
+	if w.sui.onSui() && now.Sub(w.cursorAdvancedAt) > w.checkpointStall {
+		current["checkpoint"] = fmt.Sprintf("event cursor stuck at checkpoint %d for %s", w.cursor, now.Sub(w.cursorAdvancedAt).Round(time.Second))
+	}
+	switch {
+	case !w.processingSince.IsZero() && now.Sub(w.processingSince) > w.processingStall:
+		current["processing"] = fmt.Sprintf("event handler running for %s", now.Sub(w.processingSince).Round(time.Second))
+	case queued > 0 && now.Sub(w.processedAt) > w.processingStall:
+		current["processing"] = fmt.Sprintf("%d events queued, none processed for %s", queued, now.Sub(w.processedAt).Round(time.Second))
+	}
+
+	var started, recovered []string
+	for loop, message := range current {
+		if _, ok := w.stalled[loop]; !ok {
+			w.stalledSince[loop] = now
+			w.stalls[loop]++
+			started = append(started, loop+": "+message)
+		}
+	}
+	for loop := range w.stalled {
+		if _, ok := current[loop]; !ok {
+			delete(w.stalledSince, loop)
+			recovered = append(recovered, loop)
+		}
+	}
+	w.stalled = current
+
+	var restart string
+	for loop, since := range w.stalledSince {
+		if w.restartAfter > 0 && now.Sub(since) > w.restartAfter {
+			restart = fmt.Sprintf("%s (%s)", loop, current[loop])
+		}
+	}
+	w.mutex.Unlock()
+
+	for _, loop := range recovered {
+		w.logger.Infof("✅ Event loop %s is making progress again", loop)
+	}
+	if len(started) > 0 {
+		summary := joinSorted(toSet(started), "; ")
+		w.logger.Errorf("🚨 Event loop stalled, master marked not ready: %s", summary)
+		w.alert(summary)
+	}
+	if restart != "" {
+		w.logger.Errorf("🛑 Event loop stalled longer than %s, exiting for restart: %s", w.restartAfter, restart)
+		w.exit(1)
+	}
+}
+
+// alert - 멈춤 알림 웹훅 전송 (설정된 경우)
+func (w *EventWatchdog) alert(summary string) {
+	if w.alertWebhook == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"alert":   "nautilus_event_loop_stalled",
+		"details": summary,
+		"at":      time.Now().UTC().Format(time.RFC3339),
+	})
+	go func() {
+		resp, err := w.client.Post(w.alertWebhook, "application/json", bytes.NewReader(body))
+		if err != nil {
+			w.logger.Warnf("⚠️ Watchdog alert webhook failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			w.logger.Warnf("⚠️ Watchdog alert webhook returned HTTP %d", resp.StatusCode)
+		}
+	}()
+}
+
+// Condition - 준비 조건 event_loop (nil이면 항상 충족)
+func (w *EventWatchdog) Condition() ReadinessCondition {
+	condition := ReadinessCondition{Name: "event_loop", Ready: true, Message: "event loop making progress"}
+	if w == nil {
+		return condition
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.stalled) == 0 {
+		return condition
+	}
+	var stalled []string
+	for loop, message := range w.stalled {
+		stalled = append(stalled, loop+": "+message)
+	}
+	condition.Ready = false
+	condition.Message = joinSorted(toSet(stalled), "; ")
+	return condition
+}
+
+// writeMetrics - 마지막 진행 시각과 멈춤 상태
+func (w *EventWatchdog) writeMetrics(out io.Writer) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	writeMetricHeader(out, "nautilus_event_loop_last_processed_timestamp", "gauge", "Unix time the last contract event finished processing (watchdog start if none)")
+	writeMetric(out, "nautilus_event_loop_last_processed_timestamp", nil, float64(w.processedAt.Unix()))
+	writeMetricHeader(out, "nautilus_event_loop_last_checkpoint_advance_timestamp", "gauge", "Unix time the event cursor last advanced to a new checkpoint")
+	writeMetric(out, "nautilus_event_loop_last_checkpoint_advance_timestamp", nil, float64(w.cursorAdvancedAt.Unix()))
+	writeMetricHeader(out, "nautilus_event_loop_processed_total", "counter", "Contract events processed since start")
+	writeMetric(out, "nautilus_event_loop_processed_total", nil, float64(w.processed))
+
+	loops := []string{"checkpoint", "processing"}
+	writeMetricHeader(out, "nautilus_event_loop_stalled", "gauge", "Whether the watchdog considers an event loop stalled")
+	for _, loop := range loops {
+		var stalled float64
+		if _, ok := w.stalled[loop]; ok {
+			stalled = 1
+		}
+		writeMetric(out, "nautilus_event_loop_stalled", map[string]string{"loop": loop}, stalled)
+	}
+	writeMetricHeader(out, "nautilus_event_loop_stalls_total", "counter", "Event loop stalls detected by the watchdog")
+	for _, loop := range loops {
+		writeMetric(out, "nautilus_event_loop_stalls_total", map[string]string{"loop": loop}, float64(w.stalls[loop]))
+	}
+}
@@ -0,0 +1,104 @@
+// Feature Gates - 위험도가 있는 기능의 단계적 출시 스위치
+package main
+
+import (
+	featuregate "github.com/k3s-io/daas-featuregate"
+)
+
+// 마스터 기능 게이트 이름 (NAUTILUS_FEATURE_GATES 또는 --feature-gates=Name=true,...)
+const (
+	featureWatchBookmarks       = "WatchBookmarks"
+	featureListPaging           = "ListPaging"
+	featureFederation           = "Federation"
+	featureWorkerConfigSync     = "WorkerConfigSync"
+	featureSchedulerExtenders   = "SchedulerExtenders"
+	featureSoftDelete           = "SoftDelete"
+	featureRestartCheckpoint    = "RestartCheckpoint"
+	featureKubectlCompat        = "KubectlCompat"
+	featureNodeAbandonment      = "NodeAbandonment"
+	featureEncryptedPayloads    = "EncryptedPayloads"
+	featureCapacityReservations = "CapacityReservations"
+	featureHostCompliance       = "HostCompliance"
+	featureDryRunAdmission      = "DryRunAdmission"
+	featureChainHealth          = "ChainHealth"
+	featureObservabilityBundle  = "ObservabilityBundle"
+	featureHeartbeatChallenges  = "HeartbeatChallenges"
+	featureTenantUsage          = "TenantUsageForecasts"
+	featureNamespaceProvision   = "NamespaceAutoProvisioning"
+)
+
+// features - 마스터가 아는 기능 게이트 (main에서 환경 변수와 플래그로 덮어씀)
+var features = featuregate.New(map[string]featuregate.Spec{
+	featureWatchBookmarks: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Request bookmarks on proxied JSON watches so streams resume after a drain",
+	},
+	featureListPaging: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Cap proxied LIST page size (NAUTILUS_MAX_LIST_PAGE) and page with continue tokens",
+	},
+	featureFederation: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Announce this region and sync worker state with peer masters",
+	},
+	featureWorkerConfigSync: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Push versioned worker config bundles in heartbeat responses",
+	},
+	featureSchedulerExtenders: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Register the master as a kube-scheduler extender that runs scheduler plugins and tenant filter/score webhooks",
+	},
+	featureSoftDelete: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Keep deleted objects in a trash bin for NAUTILUS_TRASH_RETENTION_HOURS so they can be restored",
+	},
+	featureRestartCheckpoint: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Write a state checkpoint on graceful shutdown and resume from it at start instead of rebuilding from chain",
+	},
+	featureKubectlCompat: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Warn skewed kubectl versions, drop removed query parameters and serve removed API versions through their replacements",
+	},
+	featureNodeAbandonment: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Mark nodes abandoned after a prolonged heartbeat absence and sign non-participation attestations for emergency_unstake",
+	},
+	featureEncryptedPayloads: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Advertise a payload encryption key in the attestation document and decrypt encrypted request payloads before admission",
+	},
+	featureCapacityReservations: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Confirm tenant capacity reservations purchased on chain and hold unused reserved capacity in the scheduler extender",
+	},
+	featureHostCompliance: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Compare worker host fingerprints against a compliance policy, mark drifting nodes with a condition and lower their scheduling score",
+	},
+	featureDryRunAdmission: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Run the chain path's admission on server-side dry-run writes and send the admitted object to K3s as a dry run",
+	},
+	featureChainHealth: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Sample chain RPC latency, checkpoint lag, event delay and gas price and publish them to tenants at /api/v1/chain/health",
+	},
+	featureObservabilityBundle: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Export worker, stake and queue state as Prometheus series and serve or push the bundled Grafana dashboards",
+	},
+	featureHeartbeatChallenges: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Send workers signed-nonce and memory-hard work challenges in heartbeat responses and lower the health score of nodes that fail them",
+	},
+	featureTenantUsage: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Record tenant namespace ResourceQuota usage for 7 days and serve usage, trend and exhaustion forecasts at /api/v1/tenant/usage",
+	},
+	featureNamespaceProvision: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Create a missing namespace from a template (quota, limit range, network policy) when its tenant first deploys to it, and audit the creation",
+	},
+})
@@ -0,0 +1,497 @@
+// Federation - 멀티 리전 마스터 연합 (온체인 등록, 다른 리전 상태의 읽기 전용 동기화)
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k3s-io/daas-signing"
+	sui "github.com/k3s-io/daas-sui"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+각 리전 마스터는 master_registry 컨트랙트에 엔드포인트와 응답 서명 공개키를 공지하고,
+다른 리전 마스터의 /api/v1/federation/state를 주기적으로 가져와 전역 조회(리전 목록, 전체 워커)를 제공합니다.
+가져온 상태는 공지된 공개키로 서명을 검증한 뒤 조회에만 사용하며, 로컬 워커 풀에는 반영하지 않습니다.
+
+	NAUTILUS_REGION                  이 마스터의 리전 (기본 default)
+	NAUTILUS_PUBLIC_URL              다른 리전과 Gateway가 접근할 이 마스터의 주소 (공지용)
+	NAUTILUS_MASTER_REGISTRY         MasterRegistry 공유 객체 ID (미설정 시 온체인 공지/조회 생략)
+	NAUTILUS_FEDERATION_PEERS        정적 피어 목록 "region=url@pubkey,..." (레지스트리 없이 구성할 때)
+	NAUTILUS_FEDERATION_INTERVAL     피어 상태 동기화 주기 (초, 기본 30)
+	NAUTILUS_FEDERATION_HEARTBEAT    온체인 하트비트 주기 (초, 기본 300, 컨트랙트 제한 시간 15분)
+*/
+
+// peerStaleAfter - 이 주기 수만큼 동기화에 실패하면 피어 상태를 전역 조회에서 stale로 표시
+const peerStaleAfter = 3
+
+// FederationState - 리전 마스터가 공개하는 읽기 전용 상태 (응답 서명 포함)
+type FederationState struct {
+	Region      string       `json:"region"`
+	Endpoint    string       `json:"endpoint"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Workers     []WorkerView `json:"workers"`
+	// Tenants - 이 리전에 등록된 Seal 토큰의 SHA-256 (Gateway가 홈 리전을 찾는 데 사용, 토큰 자체는 공개하지 않음)
+	Tenants []string `json:"tenants"`
+}
+
+// RegionStatus - 전역 리전 목록 항목
+type RegionStatus struct {
+	Region    string    `json:"region"`
+	Endpoint  string    `json:"endpoint"`
+	Local     bool      `json:"local"`
+	Healthy   bool      `json:"healthy"`
+	Stale     bool      `json:"stale,omitempty"`
+	Workers   int       `json:"workers"`
+	Tenants   int       `json:"tenants"`
+	SyncedAt  time.Time `json:"synced_at,omitempty"`
+	LatencyMs int64     `json:"latency_ms,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// FederatedWorker - 전역 워커 조회 항목 (소속 마스터 리전 포함)
+type FederatedWorker struct {
+	WorkerView
+	MasterRegion string `json:"master_region"`
+}
+
+// federationPeer - 다른 리전 마스터와 마지막 동기화 결과
+type federationPeer struct {
+	region    string
+	endpoint  string
+	publicKey ed25519.PublicKey
+	static    bool
+
+	state     *FederationState
+	syncedAt  time.Time
+	latency   time.Duration
+	failures  int
+	lastError string
+}
+
+// Federation - 리전 공지 및 피어 상태 동기화
+type Federation struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	sui        *SuiIntegration
+	signer     *RequestSigner
+
+	region            string
+	endpoint          string
+	registryID        string
+	interval          time.Duration
+	heartbeatInterval time.Duration
+	client            *http.Client
+
+	mutex     sync.RWMutex
+	peers     map[string]*federationPeer
+	announced bool
+	counts    map[string]uint64
+}
+
+// NewFederation - 새 Federation 생성 (정적 피어 형식 오류는 시작 실패)
+func NewFederation(logger *logrus.Logger, workerPool *WorkerPool, suiIntegration *SuiIntegration, signer *RequestSigner) (*Federation, error) {
+	f := &Federation{
+		logger:            logger,
+		workerPool:        workerPool,
+		sui:               suiIntegration,
+		signer:            signer,
+		region:            getEnvOrDefault("NAUTILUS_REGION", "default"),
+		endpoint:          strings.TrimSuffix(os.Getenv("NAUTILUS_PUBLIC_URL"), "/"),
+		registryID:        os.Getenv("NAUTILUS_MASTER_REGISTRY"),
+		interval:          envSeconds("NAUTILUS_FEDERATION_INTERVAL", 30),
+		heartbeatInterval: envSeconds("NAUTILUS_FEDERATION_HEARTBEAT", 300),
+		client:            &http.Client{Timeout: 10 * time.Second},
+		peers:             make(map[string]*federationPeer),
+		counts:            make(map[string]uint64),
+	}
+
+	for _, entry := range splitList(os.Getenv("NAUTILUS_FEDERATION_PEERS")) {
+		peer, err := parseFederationPeer(entry)
+		if err != nil {
+			return nil, fmt.Errorf("NAUTILUS_FEDERATION_PEERS: %v", err)
+		}
+		if peer.region != f.region {
+			f.peers[peer.region] = peer
+		}
+	}
+	return f, nil
+}
+
+// parseFederationPeer - "region=url@pubkey" 파싱
+func parseFederationPeer(entry string) (*federationPeer, error) {
+	region, rest, ok := strings.Cut(entry, "=")
+	at := strings.LastIndex(rest, "@")
+	if !ok || region == "" || at <= 0 {
+		return nil, fmt.Errorf("invalid peer %q (expected region=url@pubkey)", entry)
+	}
+	key, err := parseFederationKey(rest[at+1:])
+	if err != nil {
+		return nil, fmt.Errorf("peer %s: %v", region, err)
+	}
+	return &federationPeer{
+		region:    region,
+		endpoint:  strings.TrimSuffix(rest[:at], "/"),
+		publicKey: key,
+		static:    true,
+	}, nil
+}
+
+func parseFederationKey(hexKey string) (ed25519.PublicKey, error) {
+	raw, err := hex.DecodeString(hexKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// Region - 이 마스터의 리전
+func (f *Federation) Region() string {
+	return f.region
+}
+
+// Start - 온체인 공지/하트비트 및 피어 동기화
+func (f *Federation) Start(ctx context.Context) {
+	f.logger.Infof("🌍 Federation started: region %s, %d static peer(s)", f.region, len(f.peers))
+
+	f.announce()
+	f.Sync()
+
+	syncTicker := time.NewTicker(f.interval)
+	defer syncTicker.Stop()
+	heartbeatTicker := time.NewTicker(f.heartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncTicker.C:
+			f.Sync()
+		case <-heartbeatTicker.C:
+			f.heartbeat()
+		}
+	}
+}
+
+// onChain - 레지스트리 공지/조회가 가능한 구성인지
+func (f *Federation) onChain() bool {
+	return f.registryID != "" && f.sui != nil && f.sui.chain != nil
+}
+
+// announce - 엔드포인트와 응답 서명 공개키를 레지스트리에 공지 (리전은 관리자가 register_master로 미리 승인)
+func (f *Federation) announce() {
+	if !f.onChain() || f.endpoint == "" || f.signer == nil {
+		return
+	}
+
+	publicKey := hex.EncodeToString(f.signer.publicKey())
+	if err := f.sui.callContract("master_registry", "announce_master", f.registryID, f.region, f.endpoint, publicKey); err != nil {
+		f.logger.Errorf("❌ Failed to announce region %s master: %v", f.region, err)
+		return
+	}
+
+	f.mutex.Lock()
+	f.announced = true
+	f.mutex.Unlock()
+	f.logger.Infof("📣 Announced region %s master at %s", f.region, f.endpoint)
+}
+
+// heartbeat - 온체인 하트비트 (공지 실패 상태면 공지 재시도)
+func (f *Federation) heartbeat() {
+	f.mutex.RLock()
+	announced := f.announced
+	f.mutex.RUnlock()
+	if !announced {
+		f.announce()
+		return
+	}
+
+	if err := f.sui.callContract("master_registry", "master_heartbeat", f.registryID, f.region); err != nil {
+		f.logger.Warnf("⚠️ Region %s master heartbeat failed: %v", f.region, err)
+	}
+}
+
+// Sync - 레지스트리에서 피어 목록 갱신 후 각 피어 상태를 가져옴
+func (f *Federation) Sync() {
+	if f.onChain() {
+		if err := f.refreshPeers(); err != nil {
+			f.logger.Warnf("⚠️ Failed to read master registry: %v", err)
+		}
+	}
+
+	f.mutex.RLock()
+	peers := make([]*federationPeer, 0, len(f.peers))
+	for _, peer := range f.peers {
+		peers = append(peers, peer)
+	}
+	f.mutex.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(peer *federationPeer) {
+			defer wg.Done()
+			f.syncPeer(peer)
+		}(peer)
+	}
+	wg.Wait()
+}
+
+// refreshPeers - 레지스트리의 활성 마스터로 피어 목록 교체 (정적 피어는 유지)
+func (f *Federation) refreshPeers() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	masters, err := f.sui.chain.RegionalMasters(ctx, f.registryID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	registered := make(map[string]sui.RegionalMaster)
+	for _, master := range masters {
+		if master.Region != f.region && master.Active(now) {
+			registered[master.Region] = master
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for region, peer := range f.peers {
+		if _, ok := registered[region]; !ok && !peer.static {
+			f.logger.Infof("🌍 Region %s left the federation", region)
+			delete(f.peers, region)
+		}
+	}
+	for region, master := range registered {
+		key, err := parseFederationKey(master.PublicKey)
+		if err != nil {
+			f.logger.Warnf("⚠️ Ignoring region %s master: %v", region, err)
+			continue
+		}
+		endpoint := strings.TrimSuffix(master.Endpoint, "/")
+		if peer, ok := f.peers[region]; ok && peer.endpoint == endpoint && peer.publicKey.Equal(key) {
+			continue
+		}
+		f.logger.Infof("🌍 Region %s master at %s joined the federation", region, endpoint)
+		f.peers[region] = &federationPeer{region: region, endpoint: endpoint, publicKey: key}
+	}
+	return nil
+}
+
+// syncPeer - 피어 상태 조회 및 서명 검증
+func (f *Federation) syncPeer(peer *federationPeer) {
+	start := time.Now()
+	state, err := f.fetchState(peer)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err != nil {
+		peer.failures++
+		peer.lastError = err.Error()
+		f.counts["failure"]++
+		if peer.failures == peerStaleAfter {
+			f.logger.Warnf("⚠️ Region %s state is stale: %v", peer.region, err)
+		}
+		return
+	}
+	peer.state = state
+	peer.syncedAt = time.Now()
+	peer.latency = time.Since(start)
+	peer.failures = 0
+	peer.lastError = ""
+	f.counts["success"]++
+}
+
+func (f *Federation) fetchState(peer *federationPeer) (*FederationState, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, err
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+
+	req, err := http.NewRequest(http.MethodGet, peer.endpoint+"/api/v1/federation/state", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(signatureNonceHeader, nonce)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if err := signing.VerifyResponseHeader(peer.publicKey, resp.Header, resp.StatusCode, nonce, body); err != nil {
+		return nil, err
+	}
+
+	var state FederationState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, fmt.Errorf("invalid state: %v", err)
+	}
+	if state.Region != peer.region {
+		return nil, fmt.Errorf("peer reported region %q", state.Region)
+	}
+	return &state, nil
+}
+
+// LocalState - 이 리전의 읽기 전용 상태
+func (f *Federation) LocalState() *FederationState {
+	state := &FederationState{
+		Region:      f.region,
+		Endpoint:    f.endpoint,
+		GeneratedAt: time.Now().UTC(),
+		Workers:     []WorkerView{},
+		Tenants:     []string{},
+	}
+	for _, worker := range f.workerPool.ListWorkers() {
+		state.Workers = append(state.Workers, newWorkerView(worker))
+		if worker.SealToken != "" {
+			digest := sha256.Sum256([]byte(worker.SealToken))
+			state.Tenants = append(state.Tenants, hex.EncodeToString(digest[:]))
+		}
+	}
+	sort.Slice(state.Workers, func(i, j int) bool { return state.Workers[i].NodeID < state.Workers[j].NodeID })
+	sort.Strings(state.Tenants)
+	return state
+}
+
+// Regions - 이 리전과 피어 리전 상태
+func (f *Federation) Regions() []RegionStatus {
+	local := f.LocalState()
+	regions := []RegionStatus{{
+		Region: f.region, Endpoint: f.endpoint, Local: true, Healthy: true,
+		Workers: len(local.Workers), Tenants: len(local.Tenants), SyncedAt: local.GeneratedAt,
+	}}
+
+	f.mutex.RLock()
+	for _, peer := range f.peers {
+		status := RegionStatus{
+			Region:    peer.region,
+			Endpoint:  peer.endpoint,
+			Healthy:   peer.state != nil && peer.failures == 0,
+			Stale:     peer.failures >= peerStaleAfter,
+			SyncedAt:  peer.syncedAt,
+			LatencyMs: peer.latency.Milliseconds(),
+			Error:     peer.lastError,
+		}
+		if peer.state != nil {
+			status.Workers = len(peer.state.Workers)
+			status.Tenants = len(peer.state.Tenants)
+		}
+		regions = append(regions, status)
+	}
+	f.mutex.RUnlock()
+
+	sort.Slice(regions[1:], func(i, j int) bool { return regions[i+1].Region < regions[j+1].Region })
+	return regions
+}
+
+// Workers - 모든 리전의 워커 (피어는 마지막으로 동기화한 상태 기준)
+func (f *Federation) Workers(region string) []FederatedWorker {
+	workers := []FederatedWorker{}
+	if region == "" || region == f.region {
+		for _, view := range f.LocalState().Workers {
+			workers = append(workers, FederatedWorker{WorkerView: view, MasterRegion: f.region})
+		}
+	}
+
+	f.mutex.RLock()
+	for _, peer := range f.peers {
+		if peer.state == nil || (region != "" && region != peer.region) {
+			continue
+		}
+		for _, view := range peer.state.Workers {
+			workers = append(workers, FederatedWorker{WorkerView: view, MasterRegion: peer.region})
+		}
+	}
+	f.mutex.RUnlock()
+
+	sort.Slice(workers, func(i, j int) bool {
+		if workers[i].MasterRegion != workers[j].MasterRegion {
+			return workers[i].MasterRegion < workers[j].MasterRegion
+		}
+		return workers[i].NodeID < workers[j].NodeID
+	})
+	return workers
+}
+
+// handleState - 이 리전 상태 (요청의 X-Daas-Signature-Nonce에 묶어 서명)
+func (f *Federation) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.Marshal(f.LocalState())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if f.signer != nil {
+		f.signer.sign(w.Header(), http.StatusOK, r.Header.Get(signatureNonceHeader), body)
+	}
+	w.Write(body)
+}
+
+// handleRegions - 전역 리전 목록
+func (f *Federation) handleRegions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, f.Regions())
+}
+
+// handleWorkers - 전역 워커 조회 (?region= 필터)
+func (f *Federation) handleWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, f.Workers(r.URL.Query().Get("region")))
+}
+
+// writeMetrics - 연합 메트릭 출력
+func (f *Federation) writeMetrics(w io.Writer) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_federation_peer_healthy", "gauge", "Whether the last state sync with a peer region succeeded")
+	for region, peer := range f.peers {
+		healthy := 0.0
+		if peer.state != nil && peer.failures == 0 {
+			healthy = 1
+		}
+		writeMetric(w, "nautilus_federation_peer_healthy", map[string]string{"region": region}, healthy)
+	}
+	writeMetricHeader(w, "nautilus_federation_sync_total", "counter", "Peer region state syncs by result")
+	for _, result := range []string{"success", "failure"} {
+		writeMetric(w, "nautilus_federation_sync_total", map[string]string{"result": result}, float64(f.counts[result]))
+	}
+}
@@ -0,0 +1,478 @@
+// Finality Gate - 인증된 체크포인트에 포함된 이벤트만 처리하고, 사라진 트랜잭션은 보류/되돌림
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	finalityDedupTTL      = 24 * time.Hour // 폴링이 같은 이벤트를 다시 돌려줘도 한 번만 처리
+	finalityMaxRecent     = 50             // 상태 API에 남기는 최근 폐기/되돌림 기록 수
+	finalityAuditPerCycle = 20             // 주기당 재확인할 처리 완료 트랜잭션 수
+)
+
+// pendingEvent - 아직 인증된 체크포인트에 포함되지 않은 이벤트
+type pendingEvent struct {
+	Key        string    `json:"key"`
+	Type       string    `json:"type"`
+	TxDigest   string    `json:"tx_digest"`
+	Checkpoint uint64    `json:"checkpoint,omitempty"` // 0이면 아직 체크포인트 미포함
+	SeenAt     time.Time `json:"seen_at"`
+	Misses     int       `json:"misses"` // 트랜잭션 조회 실패(미존재) 연속 횟수
+	event      *SuiContractEvent
+}
+
+// finalizedTx - 처리한 트랜잭션 (감사 기간 동안 체인에 남아 있는지 재확인)
+type finalizedTx struct {
+	digest      string
+	checkpoint  uint64
+	processedAt time.Time
+	events      []*SuiContractEvent
+}
+
+// finalityIncident - 체크포인트 전에 사라졌거나 처리 후 사라진 트랜잭션 기록
+type finalityIncident struct {
+	TxDigest   string    `json:"tx_digest"`
+	EventType  string    `json:"event_type"`
+	Checkpoint uint64    `json:"checkpoint,omitempty"`
+	Action     string    `json:"action"` // dropped, reverted
+	Detail     string    `json:"detail,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+/*
+FinalityGate - 체크포인트 확정성을 고려한 이벤트 처리
+
+  - 이벤트 트랜잭션의 체크포인트가 최신 인증 체크포인트 - NAUTILUS_FINALITY_DEPTH 이하일 때만 처리
+  - 그보다 새로운 이벤트는 최대 NAUTILUS_FINALITY_MAX_PENDING개까지 보류 (넘치면 다음 폴링에서 다시 수신)
+  - 보류 중 트랜잭션이 NAUTILUS_FINALITY_MISSING_CHECKS회 연속 조회되지 않으면 폐기
+  - 처리한 트랜잭션이 NAUTILUS_FINALITY_AUDIT_SECONDS 안에 사라지면(RPC equivocation 등)
+    생성한 객체를 삭제하고 워커 풀/정비 일정을 다시 맞춰 유령 Pod을 막음
+*/
+type FinalityGate struct {
+	logger      *logrus.Logger
+	sui         *SuiIntegration
+	history     *HeartbeatHistory
+	depth       uint64
+	interval    time.Duration
+	maxPending  int
+	missLimit   int
+	auditWindow time.Duration
+
+	mutex      sync.Mutex
+	latest     uint64
+	pending    map[string]*pendingEvent
+	seen       map[string]time.Time // 처리 완료 이벤트 키
+	finalized  map[string]*finalizedTx
+	incidents  []finalityIncident
+	duplicates uint64
+	dropped    uint64
+	reverted   uint64
+	overflow   uint64
+}
+
+// NewFinalityGate - 새 Finality Gate 생성
+func NewFinalityGate(logger *logrus.Logger, sui *SuiIntegration) *FinalityGate {
+	depth, err := strconv.ParseUint(getEnvOrDefault("NAUTILUS_FINALITY_DEPTH", "0"), 10, 64)
+	if err != nil {
+		logger.Warnf("⚠️ Invalid NAUTILUS_FINALITY_DEPTH, using 0: %v", err)
+		depth = 0
+	}
+	maxPending, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_FINALITY_MAX_PENDING", "256"))
+	if err != nil || maxPending <= 0 {
+		maxPending = 256
+	}
+	missLimit, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_FINALITY_MISSING_CHECKS", "5"))
+	if err != nil || missLimit <= 0 {
+		missLimit = 5
+	}
+
+	return &FinalityGate{
+		logger:      logger,
+		sui:         sui,
+		depth:       depth,
+		interval:    envSeconds("NAUTILUS_FINALITY_CHECK_INTERVAL_SECONDS", 2),
+		maxPending:  maxPending,
+		missLimit:   missLimit,
+		auditWindow: envSeconds("NAUTILUS_FINALITY_AUDIT_SECONDS", 600),
+		pending:     make(map[string]*pendingEvent),
+		seen:        make(map[string]time.Time),
+		finalized:   make(map[string]*finalizedTx),
+	}
+}
+
+// eventKey - 트랜잭션 다이제스트와 이벤트 순번으로 이벤트 식별
+func eventKey(event *SuiContractEvent) string {
+	return event.TxDigest + ":" + event.EventSeq
+}
+
+// Admit - 수신한 이벤트를 보류 버퍼에 추가 (이미 처리했거나 보류 중이면 무시)
+func (f *FinalityGate) Admit(event *SuiContractEvent) {
+	key := eventKey(event)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, done := f.seen[key]; done {
+		f.duplicates++
+		return
+	}
+	if _, waiting := f.pending[key]; waiting {
+		return
+	}
+	if len(f.pending) >= f.maxPending {
+		f.overflow++
+		f.logger.Warnf("⚠️ Finality buffer full (%d), deferring %s until next poll", f.maxPending, event.Type)
+		return
+	}
+	f.pending[key] = &pendingEvent{
+		Key:      key,
+		Type:     event.Type,
+		TxDigest: event.TxDigest,
+		SeenAt:   time.Now(),
+		event:    event,
+	}
+}
+
+// Start - 주기적으로 보류 이벤트 확정 처리 및 처리 완료 트랜잭션 재확인
+func (f *FinalityGate) Start(ctx context.Context) {
+	f.logger.Infof("🧱 Starting Finality Gate (depth %d, max pending %d)", f.depth, f.maxPending)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.cycle(ctx)
+		}
+	}
+}
+
+// cycle - 최신 인증 체크포인트 조회 → 보류 이벤트 처리 → 감사
+func (f *FinalityGate) cycle(ctx context.Context) {
+	latest, err := f.sui.latestCheckpoint()
+	if err != nil {
+		f.logger.Debugf("Finality check skipped, latest checkpoint unavailable: %v", err)
+		return
+	}
+	f.mutex.Lock()
+	f.latest = latest
+	f.mutex.Unlock()
+
+	for _, event := range f.releasable(latest) {
+		f.sui.processEvent(ctx, event)
+	}
+	f.audit()
+	f.prune()
+}
+
+// releasable - 확정된 보류 이벤트를 체크포인트/시각 순으로 꺼냄 (같은 트랜잭션의 이벤트는 함께)
+func (f *FinalityGate) releasable(latest uint64) []*SuiContractEvent {
+	f.mutex.Lock()
+	digests := make(map[string][]*pendingEvent)
+	for _, pending := range f.pending {
+		digests[pending.TxDigest] = append(digests[pending.TxDigest], pending)
+	}
+	f.mutex.Unlock()
+
+	var ready []*pendingEvent
+	for digest, events := range digests {
+		checkpoint, found, err := f.txCheckpoint(digest)
+		if err != nil {
+			continue // RPC 장애는 판단 보류
+		}
+
+		f.mutex.Lock()
+		if !found {
+			for _, pending := range events {
+				pending.Misses++
+				if pending.Misses >= f.missLimit {
+					delete(f.pending, pending.Key)
+					f.dropped++
+					f.recordIncidentLocked(pending.TxDigest, pending.Type, 0, "dropped",
+						fmt.Sprintf("transaction not found after %d checks", pending.Misses))
+					f.logger.Warnf("🧱 Dropped %s from %s: transaction disappeared before finality", pending.Type, digest)
+				}
+			}
+			f.mutex.Unlock()
+			continue
+		}
+		for _, pending := range events {
+			pending.Misses = 0
+			pending.Checkpoint = checkpoint
+		}
+		if checkpoint > 0 && checkpoint+f.depth <= latest {
+			for _, pending := range events {
+				delete(f.pending, pending.Key)
+				f.seen[pending.Key] = time.Now()
+				ready = append(ready, pending)
+			}
+			tx := f.finalized[digest]
+			if tx == nil {
+				tx = &finalizedTx{digest: digest, checkpoint: checkpoint, processedAt: time.Now()}
+				f.finalized[digest] = tx
+			}
+			for _, pending := range events {
+				tx.events = append(tx.events, pending.event)
+			}
+		}
+		f.mutex.Unlock()
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].Checkpoint != ready[j].Checkpoint {
+			return ready[i].Checkpoint < ready[j].Checkpoint
+		}
+		if ready[i].event.Timestamp != ready[j].event.Timestamp {
+			return ready[i].event.Timestamp < ready[j].event.Timestamp
+		}
+		return ready[i].Key < ready[j].Key
+	})
+	events := make([]*SuiContractEvent, len(ready))
+	for i, pending := range ready {
+		events[i] = pending.event
+	}
+	return events
+}
+
+// txCheckpoint - 트랜잭션이 포함된 체크포인트 (found=false면 노드가 트랜잭션을 모름)
+func (f *FinalityGate) txCheckpoint(digest string) (uint64, bool, error) {
+	var tx struct {
+		Checkpoint string `json:"checkpoint"`
+	}
+	if err := f.sui.rpcCall("sui_getTransactionBlock", []interface{}{digest, map[string]bool{}}, &tx); err != nil {
+		if isTxNotFound(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	if tx.Checkpoint == "" {
+		return 0, true, nil
+	}
+	checkpoint, err := strconv.ParseUint(tx.Checkpoint, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid checkpoint %q: %v", tx.Checkpoint, err)
+	}
+	return checkpoint, true, nil
+}
+
+// isTxNotFound - RPC 오류가 트랜잭션 미존재를 뜻하는지 (전송 실패와 구분)
+func isTxNotFound(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "could not find") || strings.Contains(message, "not found")
+}
+
+// audit - 감사 기간 내 처리한 트랜잭션이 여전히 같은 체크포인트에 있는지 확인
+func (f *FinalityGate) audit() {
+	f.mutex.Lock()
+	var candidates []*finalizedTx
+	for _, tx := range f.finalized {
+		candidates = append(candidates, tx)
+	}
+	f.mutex.Unlock()
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].processedAt.After(candidates[j].processedAt) })
+	if len(candidates) > finalityAuditPerCycle {
+		candidates = candidates[:finalityAuditPerCycle]
+	}
+
+	for _, tx := range candidates {
+		checkpoint, found, err := f.txCheckpoint(tx.digest)
+		if err != nil || (found && checkpoint == tx.checkpoint) {
+			continue
+		}
+
+		detail := "transaction no longer found"
+		if found {
+			detail = fmt.Sprintf("transaction moved from checkpoint %d to %d", tx.checkpoint, checkpoint)
+		}
+		f.logger.Errorf("🚨 Finalized transaction %s changed: %s, reverting %d events", tx.digest, detail, len(tx.events))
+
+		f.mutex.Lock()
+		delete(f.finalized, tx.digest)
+		f.reverted += uint64(len(tx.events))
+		for _, event := range tx.events {
+			f.recordIncidentLocked(tx.digest, event.Type, tx.checkpoint, "reverted", detail)
+		}
+		f.mutex.Unlock()
+
+		for _, event := range tx.events {
+			f.revert(event)
+		}
+	}
+}
+
+/*
+revert - 처리했던 이벤트의 효과 되돌리기
+
+  - K8s 생성 요청: 생성된 객체 삭제 (유령 Pod 방지)
+  - 워커 등록/상태 변경: 온체인 레지스트리 기준으로 풀 재동기화
+  - 정비 예약: 일정 취소
+*/
+func (f *FinalityGate) revert(event *SuiContractEvent) {
+	switch {
+	case strings.Contains(event.Type, "K8sAPIRequestScheduledEvent"):
+		method, _ := event.EventData["method"].(string)
+		resource, _ := event.EventData["resource"].(string)
+		namespace, _ := event.EventData["namespace"].(string)
+		name, _ := event.EventData["name"].(string)
+		if name == "" {
+			payload, _ := event.EventData["payload"].(string)
+			name = manifestName(payload)
+		}
+		if method != "POST" || resource == "" || name == "" {
+			f.logger.Warnf("⚠️ Cannot automatically revert %s %s/%s from %s", method, resource, name, event.TxDigest)
+			return
+		}
+		args := []string{"delete", resource, name, "--ignore-not-found"}
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		if _, err := f.sui.k3sMgr.RunKubectl(nil, args...); err != nil {
+			f.logger.Errorf("❌ Failed to revert %s %s/%s: %v", resource, namespace, name, err)
+			return
+		}
+		f.logger.Warnf("🧹 Reverted %s %s/%s created by unfinalized transaction %s", resource, namespace, name, event.TxDigest)
+
+	case strings.Contains(event.Type, "WorkerRegisteredEvent"),
+		strings.Contains(event.Type, "WorkerStatusChangedEvent"):
+		if f.sui.poolSync == nil {
+			return
+		}
+		if err := f.sui.poolSync.Reconcile(); err != nil {
+			f.logger.Errorf("❌ Pool reconcile after reverted %s failed: %v", event.Type, err)
+		}
+
+	case strings.Contains(event.Type, "MaintenanceScheduledEvent"):
+		if nodeID, _ := event.EventData["node_id"].(string); nodeID != "" && f.sui.maintenance != nil {
+			f.sui.maintenance.Cancel(nodeID, "reverted")
+		}
+
+	default:
+		f.logger.Warnf("⚠️ No revert action for %s from %s", event.Type, event.TxDigest)
+	}
+
+	if nodeID, _ := event.EventData["node_id"].(string); nodeID != "" {
+		f.history.RecordEvent(nodeID, "chain_event_reverted", fmt.Sprintf("%s from %s", event.Type, event.TxDigest))
+	}
+}
+
+// manifestName - JSON/YAML 매니페스트의 metadata.name (찾지 못하면 빈 문자열)
+func manifestName(payload string) string {
+	var manifest struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if json.Unmarshal([]byte(payload), &manifest) == nil {
+		return manifest.Metadata.Name
+	}
+
+	inMetadata := false
+	for _, line := range strings.Split(payload, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case line == "metadata:" || strings.HasPrefix(line, "metadata:"):
+			inMetadata = true
+		case inMetadata && len(line) > 0 && line[0] != ' ' && line[0] != '\t':
+			inMetadata = false
+		case inMetadata && strings.HasPrefix(trimmed, "name:"):
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "name:")), `"'`)
+		}
+	}
+	return ""
+}
+
+func (f *FinalityGate) recordIncidentLocked(digest, eventType string, checkpoint uint64, action, detail string) {
+	f.incidents = append(f.incidents, finalityIncident{
+		TxDigest:   digest,
+		EventType:  eventType,
+		Checkpoint: checkpoint,
+		Action:     action,
+		Detail:     detail,
+		At:         time.Now(),
+	})
+	if len(f.incidents) > finalityMaxRecent {
+		f.incidents = f.incidents[len(f.incidents)-finalityMaxRecent:]
+	}
+}
+
+// prune - 감사 기간이 지난 트랜잭션과 오래된 중복 방지 키 정리
+func (f *FinalityGate) prune() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	for digest, tx := range f.finalized {
+		if now.Sub(tx.processedAt) > f.auditWindow {
+			delete(f.finalized, digest)
+		}
+	}
+	for key, at := range f.seen {
+		if now.Sub(at) > finalityDedupTTL {
+			delete(f.seen, key)
+		}
+	}
+}
+
+// PendingCount - 확정 대기 중인 이벤트 수
+func (f *FinalityGate) PendingCount() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.pending)
+}
+
+// handleFinality - 확정 대기 이벤트와 최근 폐기/되돌림 기록 (/api/v1/chain/finality)
+func (f *FinalityGate) handleFinality(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	f.mutex.Lock()
+	pending := make([]pendingEvent, 0, len(f.pending))
+	for _, event := range f.pending {
+		pending = append(pending, *event)
+	}
+	incidents := append([]finalityIncident(nil), f.incidents...)
+	latest := f.latest
+	f.mutex.Unlock()
+	sort.Slice(pending, func(i, j int) bool { return pending[i].SeenAt.Before(pending[j].SeenAt) })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":            "success",
+		"latest_checkpoint": latest,
+		"depth":             f.depth,
+		"pending":           pending,
+		"incidents":         incidents,
+	})
+}
+
+// writeMetrics - 확정성 메트릭 출력
+func (f *FinalityGate) writeMetrics(w io.Writer) {
+	f.mutex.Lock()
+	pending, duplicates, dropped, reverted, overflow := len(f.pending), f.duplicates, f.dropped, f.reverted, f.overflow
+	f.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_finality_pending_events", "gauge", "Contract events waiting for a certified checkpoint")
+	writeMetric(w, "nautilus_finality_pending_events", nil, float64(pending))
+	writeMetricHeader(w, "nautilus_finality_events_total", "counter", "Contract events not processed as received, by outcome")
+	writeMetric(w, "nautilus_finality_events_total", map[string]string{"outcome": "duplicate"}, float64(duplicates))
+	writeMetric(w, "nautilus_finality_events_total", map[string]string{"outcome": "dropped"}, float64(dropped))
+	writeMetric(w, "nautilus_finality_events_total", map[string]string{"outcome": "reverted"}, float64(reverted))
+	writeMetric(w, "nautilus_finality_events_total", map[string]string{"outcome": "deferred"}, float64(overflow))
+}
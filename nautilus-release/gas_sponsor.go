@@ -0,0 +1,268 @@
+// Gas Sponsor - 워커 트랜잭션의 가스를 마스터 지갑이 대신 지불 (Sui sponsored transaction)
+//
+// 핸드셰이크:
+//  1. 워커가 Seal 토큰으로 인증하여 허용된 Move 호출을 요청
+//  2. 마스터가 sender=워커, gas owner=스폰서로 TransactionData를 만들고 스폰서 서명 후 반환
+//  3. 워커가 트랜잭션 내용을 확인하고 자신의 서명을 더해 두 서명으로 실행
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sponsoredCallPolicy - 스폰서가 가스를 지불하는 Move 호출과 허용 인자
+type sponsoredCallPolicy struct {
+	module   string
+	function string
+	// allowedValues - node_id 다음 인자별 허용 값 (nil이면 추가 인자 없음, anyArgValue는 식별자 형식의 임의 값)
+	allowedValues [][]string
+}
+
+// anyArgValue - 형식만 제한하는 자유 인자 (대상 이름, 측정값 등)
+const anyArgValue = "*"
+
+// freeArgPattern - 자유 인자 허용 형식
+var freeArgPattern = regexp.MustCompile(`^[A-Za-z0-9:._-]{1,128}$`)
+
+// sponsoredCalls - 워커 자신의 노드에 대한 하트비트/상태 변경과 교차 검증 결과 보고만 허용
+var sponsoredCalls = map[string]sponsoredCallPolicy{
+	"worker_registry::update_heartbeat": {
+		module:   "worker_registry",
+		function: "update_heartbeat",
+	},
+	"worker_registry::change_worker_status": {
+		module:        "worker_registry",
+		function:      "change_worker_status",
+		allowedValues: [][]string{{"offline", "busy"}},
+	},
+	"worker_registry::report_attestation": {
+		module:        "worker_registry",
+		function:      "report_attestation",
+		allowedValues: [][]string{{anyArgValue}, {anyArgValue}, {"valid", "invalid"}},
+	},
+}
+
+// SponsorRequest - 워커의 스폰서 요청
+type SponsorRequest struct {
+	NodeID   string   `json:"node_id"`
+	Module   string   `json:"module"`
+	Function string   `json:"function"`
+	Args     []string `json:"args"` // node_id 이후 인자
+}
+
+// SponsoredTransaction - 스폰서 서명이 포함된 미서명(워커) 트랜잭션
+type SponsoredTransaction struct {
+	TxBytes          string    `json:"tx_bytes"`
+	Sponsor          string    `json:"sponsor"`
+	SponsorSignature string    `json:"sponsor_signature"`
+	GasBudget        uint64    `json:"gas_budget"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// GasSponsor - 스폰서 트랜잭션 발급
+type GasSponsor struct {
+	logger     *logrus.Logger
+	sui        *SuiIntegration
+	workerPool *WorkerPool
+	sponsor    string
+	gasBudget  uint64
+	maxPerHour int
+	issued     map[string][]time.Time
+	mutex      sync.Mutex
+}
+
+// NewGasSponsor - 새 Gas Sponsor 생성 (GAS_SPONSOR_ADDRESS 미설정 시 sui CLI 활성 주소)
+func NewGasSponsor(logger *logrus.Logger, sui *SuiIntegration) *GasSponsor {
+	gasBudget, err := strconv.ParseUint(getEnvOrDefault("GAS_SPONSOR_GAS_BUDGET", "5000000"), 10, 64)
+	if err != nil || gasBudget == 0 {
+		gasBudget = 5000000
+	}
+	maxPerHour, err := strconv.Atoi(getEnvOrDefault("GAS_SPONSOR_MAX_PER_HOUR", "30"))
+	if err != nil || maxPerHour <= 0 {
+		maxPerHour = 30
+	}
+
+	return &GasSponsor{
+		logger:     logger,
+		sui:        sui,
+		workerPool: sui.workerPool,
+		sponsor:    getEnvOrDefault("GAS_SPONSOR_ADDRESS", ""),
+		gasBudget:  gasBudget,
+		maxPerHour: maxPerHour,
+		issued:     make(map[string][]time.Time),
+	}
+}
+
+// sponsorAddress - 스폰서 지갑 주소
+func (g *GasSponsor) sponsorAddress() (string, error) {
+	if g.sponsor != "" {
+		return g.sponsor, nil
+	}
+
+	output, err := exec.Command("sui", "client", "active-address").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve sponsor address: %v", err)
+	}
+	g.sponsor = strings.TrimSpace(string(output))
+	return g.sponsor, nil
+}
+
+// Sponsor - 정책 확인 후 스폰서 트랜잭션 생성
+func (g *GasSponsor) Sponsor(sealToken string, request *SponsorRequest) (*SponsoredTransaction, error) {
+	worker, exists := g.workerPool.GetWorker(request.NodeID)
+	if !exists || worker.SealToken == "" || worker.SealToken != sealToken {
+		return nil, fmt.Errorf("unknown worker or invalid seal token")
+	}
+	if worker.Status == "slashed" {
+		return nil, fmt.Errorf("slashed workers are not sponsored")
+	}
+
+	policy, ok := sponsoredCalls[request.Module+"::"+request.Function]
+	if !ok {
+		return nil, fmt.Errorf("%s::%s is not eligible for sponsorship", request.Module, request.Function)
+	}
+	if len(request.Args) != len(policy.allowedValues) {
+		return nil, fmt.Errorf("%s::%s expects %d arguments after node_id", request.Module, request.Function, len(policy.allowedValues))
+	}
+	for i, arg := range request.Args {
+		if !allowsArg(policy.allowedValues[i], arg) {
+			return nil, fmt.Errorf("argument %q not allowed for %s::%s", arg, request.Module, request.Function)
+		}
+	}
+
+	if err := g.reserve(request.NodeID); err != nil {
+		return nil, err
+	}
+
+	sponsor, err := g.sponsorAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	// 레지스트리와 node_id는 마스터가 채움 (워커는 자기 노드만 대상으로 할 수 있음)
+	contract := g.sui.activeContract()
+	ptbArgs := []string{"client", "ptb",
+		"--move-call", fmt.Sprintf("%s::%s::%s", contract.Package, policy.module, policy.function),
+		"@" + contract.Registry, strconv.Quote(request.NodeID),
+	}
+	for _, arg := range request.Args {
+		ptbArgs = append(ptbArgs, strconv.Quote(arg))
+	}
+	ptbArgs = append(ptbArgs,
+		"--sender", "@"+worker.WorkerAddress,
+		"--gas-sponsor", "@"+sponsor,
+		"--gas-budget", strconv.FormatUint(g.gasBudget, 10),
+		"--serialize-unsigned-transaction",
+	)
+
+	output, err := exec.Command("sui", ptbArgs...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sponsored transaction: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	txBytes := strings.TrimSpace(string(output))
+
+	signature, err := signWithKeystore(sponsor, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	g.logger.Infof("⛽ Sponsored %s::%s for worker %s", policy.module, policy.function, request.NodeID)
+	return &SponsoredTransaction{
+		TxBytes:          txBytes,
+		Sponsor:          sponsor,
+		SponsorSignature: signature,
+		GasBudget:        g.gasBudget,
+		ExpiresAt:        time.Now().Add(2 * time.Minute),
+	}, nil
+}
+
+// reserve - 워커별 시간당 스폰서 횟수 제한
+func (g *GasSponsor) reserve(nodeID string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	cutoff := time.Now().Add(-time.Hour)
+	recent := g.issued[nodeID][:0]
+	for _, issuedAt := range g.issued[nodeID] {
+		if issuedAt.After(cutoff) {
+			recent = append(recent, issuedAt)
+		}
+	}
+	if len(recent) >= g.maxPerHour {
+		g.issued[nodeID] = recent
+		return fmt.Errorf("sponsorship limit of %d per hour reached", g.maxPerHour)
+	}
+	g.issued[nodeID] = append(recent, time.Now())
+	return nil
+}
+
+// signWithKeystore - sui keytool로 트랜잭션 바이트 서명
+func signWithKeystore(address, txBytes string) (string, error) {
+	output, err := exec.Command("sui", "keytool", "sign", "--address", address, "--data", txBytes, "--json").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign sponsored transaction: %v", err)
+	}
+
+	var signed struct {
+		SuiSignature string `json:"suiSignature"`
+	}
+	if err := json.Unmarshal(output, &signed); err != nil || signed.SuiSignature == "" {
+		return "", fmt.Errorf("unexpected keytool output")
+	}
+	return signed.SuiSignature, nil
+}
+
+// allowsArg - 허용 값 목록 또는 자유 인자 형식에 맞는지 확인
+func allowsArg(allowed []string, arg string) bool {
+	if containsString(allowed, anyArgValue) {
+		return freeArgPattern.MatchString(arg)
+	}
+	return containsString(allowed, arg)
+}
+
+// containsString - 슬라이스 포함 여부
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSponsor - POST /api/v1/gas/sponsor (X-Seal-Token 인증)
+func (g *GasSponsor) handleSponsor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request SponsorRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid sponsor request", http.StatusBadRequest)
+		return
+	}
+
+	sponsored, err := g.Sponsor(r.Header.Get("X-Seal-Token"), &request)
+	if err != nil {
+		g.logger.Warnf("⚠️ Sponsorship denied for %s: %v", request.NodeID, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   sponsored,
+	})
+}
@@ -1,9 +1,20 @@
 module github.com/k3s-io/nautilus-tee
 
-go 1.21
+go 1.22
 
 require (
 	github.com/gorilla/websocket v1.5.0
+	github.com/k3s-io/daas-apirequest v0.0.0
+	github.com/k3s-io/daas-chain v0.0.0
+	github.com/k3s-io/daas-escrow v0.0.0
+	github.com/k3s-io/daas-featuregate v0.0.0
+	github.com/k3s-io/daas-httpserver v0.0.0
+	github.com/k3s-io/daas-service v0.0.0
+	github.com/k3s-io/daas-signing v0.0.0
+	github.com/k3s-io/daas-sui v0.0.0
+	github.com/k3s-io/daas-version v0.0.0
+	github.com/k3s-io/daas-workproof v0.0.0
+	github.com/klauspost/compress v1.18.0
 	github.com/sirupsen/logrus v1.9.3
 	k8s.io/apiserver v0.28.0
 )
@@ -12,3 +23,33 @@ require golang.org/x/sys v0.10.0 // indirect
 
 // K3s-DaaS 로컬 패키지 참조
 replace github.com/k3s-io/k3s => ../k3s-daas/pkg-reference
+
+// 공용 Sui 클라이언트
+replace github.com/k3s-io/daas-sui => ../pkg/sui
+
+// 공용 체인 백엔드 인터페이스 (Sui/mock)
+replace github.com/k3s-io/daas-chain => ../pkg/chain
+
+// 공용 K8s API 요청 스키마 (Gateway/Move 이벤트/마스터)
+replace github.com/k3s-io/daas-apirequest => ../pkg/apirequest
+
+// 공용 기능 게이트 (--feature-gates)
+replace github.com/k3s-io/daas-featuregate => ../pkg/featuregate
+
+// 공용 HTTP 리스너 (TLS/리다이렉트/HSTS)
+replace github.com/k3s-io/daas-httpserver => ../pkg/httpserver
+
+// 공용 서비스 관리 (systemd 유닛 / Windows 서비스)
+replace github.com/k3s-io/daas-service => ../pkg/service
+
+// 공용 빌드 버전 / 구성요소 간 버전 차이 정책
+replace github.com/k3s-io/daas-version => ../pkg/version
+
+// 봉인 루트 키 에스크로 (Shamir 분할, 보관자 조각 봉투)
+replace github.com/k3s-io/daas-escrow => ../pkg/escrow
+
+// 하트비트 챌린지 (nonce 서명, 메모리 의존 작업 증명)
+replace github.com/k3s-io/daas-workproof => ../pkg/workproof
+
+// Gateway ↔ 마스터 요청/응답 서명 (watch 프레임 서명, nonce 재사용 차단)
+replace github.com/k3s-io/daas-signing => ../pkg/signing
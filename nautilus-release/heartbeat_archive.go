@@ -0,0 +1,663 @@
+// Heartbeat Archive - 오래된 하트비트를 시간 단위 요약으로 압축하고 원본은 콜드 스토리지로 아카이브 (요청 시 기간별 복원)
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+압축 작업 (NAUTILUS_HEARTBEAT_COMPACT_INTERVAL초마다, 기본 3600)
+
+ 1. NAUTILUS_HEARTBEAT_COMPACT_AFTER_HOURS(기본 12)보다 오래된 노드별 샘플을 gzip JSON 세그먼트로 콜드 스토리지에 저장
+ 2. 저장에 성공한 노드만 시간 단위 요약(heartbeat-summaries.json)에 합치고 메모리에서 삭제
+    (실패하면 샘플을 그대로 두고 다음 주기에 다시 시도)
+ 3. 요약은 NAUTILUS_HEARTBEAT_SUMMARY_RETENTION_DAYS(기본 90), 세그먼트는
+    NAUTILUS_HEARTBEAT_ARCHIVE_RETENTION_DAYS(기본 365)가 지나면 삭제
+
+세그먼트 색인(heartbeat-archive.json)에는 저장 위치와 내용 해시를 남겨 복원할 때 검증합니다.
+저장소 설정은 cold_storage.go 참고. 보관 정책의 heartbeat_history는 메모리의 원본 샘플에만 적용됩니다.
+*/
+
+const (
+	heartbeatSummaryWindow        = time.Hour
+	defaultRehydrateRange         = 24 * time.Hour
+	defaultRehydrateMaxSamples    = 100000
+	heartbeatArchiveSegmentPrefix = "heartbeats/"
+)
+
+var unsafeArchiveKey = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// HeartbeatSummary - 노드의 한 시간 구간 하트비트 요약
+type HeartbeatSummary struct {
+	Start         time.Time `json:"start"`
+	Samples       int       `json:"samples"`
+	AvgCPU        float64   `json:"avg_cpu_percent"`
+	MaxCPU        float64   `json:"max_cpu_percent"`
+	AvgMemory     float64   `json:"avg_memory_percent"`
+	MaxMemory     float64   `json:"max_memory_percent"`
+	AvgDisk       float64   `json:"avg_disk_percent"`
+	MaxDisk       float64   `json:"max_disk_percent"`
+	AvgLatencyMs  float64   `json:"avg_latency_ms"`
+	MaxLatencyMs  int64     `json:"max_latency_ms"`
+	MinPods       int       `json:"min_running_pods"`
+	MaxPods       int       `json:"max_running_pods"`
+	StakeStatus   string    `json:"stake_status,omitempty"` // 구간의 마지막 값
+	StakeAmount   uint64    `json:"stake_amount,omitempty"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// add - 샘플 하나를 요약에 반영 (평균은 샘플 수 가중)
+func (s *HeartbeatSummary) add(sample HeartbeatSample) {
+	n := float64(s.Samples)
+	average := func(current, value float64) float64 { return (current*n + value) / (n + 1) }
+	if s.Samples == 0 {
+		s.MinPods, s.MaxPods = sample.RunningPods, sample.RunningPods
+	}
+	s.AvgCPU = average(s.AvgCPU, sample.CPUPercent)
+	s.AvgMemory = average(s.AvgMemory, sample.MemoryPercent)
+	s.AvgDisk = average(s.AvgDisk, sample.DiskPercent)
+	s.AvgLatencyMs = average(s.AvgLatencyMs, float64(sample.LatencyMs))
+	if sample.CPUPercent > s.MaxCPU {
+		s.MaxCPU = sample.CPUPercent
+	}
+	if sample.MemoryPercent > s.MaxMemory {
+		s.MaxMemory = sample.MemoryPercent
+	}
+	if sample.DiskPercent > s.MaxDisk {
+		s.MaxDisk = sample.DiskPercent
+	}
+	if sample.LatencyMs > s.MaxLatencyMs {
+		s.MaxLatencyMs = sample.LatencyMs
+	}
+	if sample.RunningPods < s.MinPods {
+		s.MinPods = sample.RunningPods
+	}
+	if sample.RunningPods > s.MaxPods {
+		s.MaxPods = sample.RunningPods
+	}
+	if !sample.Timestamp.Before(s.LastHeartbeat) {
+		s.LastHeartbeat = sample.Timestamp
+		if sample.StakeStatus != "" {
+			s.StakeStatus = sample.StakeStatus
+			s.StakeAmount = sample.StakeAmount
+		}
+	}
+	s.Samples++
+}
+
+// ArchiveSegment - 콜드 스토리지에 저장한 노드의 원본 샘플 묶음
+type ArchiveSegment struct {
+	ID         string    `json:"id"`
+	NodeID     string    `json:"node_id"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Samples    int       `json:"samples"`
+	Bytes      int       `json:"bytes"`
+	SHA256     string    `json:"sha256"`
+	Backend    string    `json:"backend"`
+	Location   string    `json:"location"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// RehydratedHistory - 복원한 기간의 원본 샘플과 요약
+type RehydratedHistory struct {
+	NodeID     string             `json:"node_id"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Heartbeats []HeartbeatSample  `json:"heartbeats"`
+	Summaries  []HeartbeatSummary `json:"summaries"`
+	Segments   []string           `json:"segments"`
+	Truncated  bool               `json:"truncated"`
+}
+
+// archivedSamples - 세그먼트 내용 (gzip 전)
+type archivedSamples struct {
+	NodeID  string            `json:"node_id"`
+	Samples []HeartbeatSample `json:"samples"`
+}
+
+// HeartbeatArchive - 하트비트 압축/아카이브/복원
+type HeartbeatArchive struct {
+	logger           *logrus.Logger
+	history          *HeartbeatHistory
+	store            ColdStore
+	adminToken       string
+	compactAfter     time.Duration
+	interval         time.Duration
+	summaryRetention time.Duration
+	archiveRetention time.Duration
+	maxRehydrate     int
+	indexFile        string
+	summaryFile      string
+	slo              *SLOTracker
+
+	mutex          sync.Mutex
+	segments       []ArchiveSegment
+	summaries      map[string][]HeartbeatSummary
+	lastCompaction time.Time
+	compactions    uint64
+	archived       uint64 // 아카이브한 샘플 수
+	archivedBytes  uint64
+	failures       uint64
+	rehydrations   uint64
+}
+
+// NewHeartbeatArchive - 환경변수 설정과 저장된 색인/요약으로 생성
+func NewHeartbeatArchive(logger *logrus.Logger, history *HeartbeatHistory) (*HeartbeatArchive, error) {
+	store, err := NewColdStore()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &HeartbeatArchive{
+		logger:           logger,
+		history:          history,
+		store:            store,
+		adminToken:       os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		compactAfter:     time.Duration(envCount("NAUTILUS_HEARTBEAT_COMPACT_AFTER_HOURS", 12)) * time.Hour,
+		interval:         envSeconds("NAUTILUS_HEARTBEAT_COMPACT_INTERVAL", 3600),
+		summaryRetention: time.Duration(envCount("NAUTILUS_HEARTBEAT_SUMMARY_RETENTION_DAYS", 90)) * 24 * time.Hour,
+		archiveRetention: time.Duration(envCount("NAUTILUS_HEARTBEAT_ARCHIVE_RETENTION_DAYS", 365)) * 24 * time.Hour,
+		maxRehydrate:     envCount("NAUTILUS_HEARTBEAT_REHYDRATE_MAX_SAMPLES", defaultRehydrateMaxSamples),
+		indexFile:        statePath("heartbeat-archive.json"),
+		summaryFile:      statePath("heartbeat-summaries.json"),
+		summaries:        make(map[string][]HeartbeatSummary),
+	}
+	if _, err := loadJSONState(a.indexFile, &a.segments); err != nil {
+		logger.Warnf("⚠️ Failed to load heartbeat archive index: %v", err)
+	}
+	if _, err := loadJSONState(a.summaryFile, &a.summaries); err != nil {
+		logger.Warnf("⚠️ Failed to load heartbeat summaries: %v", err)
+	}
+	if a.summaries == nil {
+		a.summaries = make(map[string][]HeartbeatSummary)
+	}
+	return a, nil
+}
+
+// Start - 주기적 압축
+func (a *HeartbeatArchive) Start(ctx context.Context) {
+	a.logger.Infof("🧊 Heartbeat archive started (%s backend, compacting samples older than %s)", a.store.Name(), a.compactAfter)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.slo != nil && a.slo.Defer("heartbeat_compaction") {
+				continue
+			}
+			a.Compact()
+		}
+	}
+}
+
+// archiveKey - 노드/기간별 세그먼트 키 ([A-Za-z0-9._/-]만 사용, 바꾼 문자가 있으면 해시로 구분)
+func archiveKey(nodeID string, from, to time.Time) string {
+	safe := unsafeArchiveKey.ReplaceAllString(nodeID, "_")
+	if safe != nodeID || safe == "" || strings.Trim(safe, ".") == "" {
+		sum := sha256.Sum256([]byte(nodeID))
+		safe += "-" + hex.EncodeToString(sum[:4])
+	}
+	const layout = "20060102T150405Z"
+	return heartbeatArchiveSegmentPrefix + safe + "/" + from.UTC().Format(layout) + "-" + to.UTC().Format(layout) + ".json.gz"
+}
+
+// encodeSegment - gzip JSON 세그먼트
+func encodeSegment(nodeID string, samples []HeartbeatSample) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(archivedSamples{NodeID: nodeID, Samples: samples}); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Compact - 오래된 샘플을 아카이브하고 요약으로 대체, 아카이브한 샘플 수 반환
+func (a *HeartbeatArchive) Compact() int {
+	now := time.Now()
+	pending := a.history.SamplesBefore(now.Add(-a.compactAfter))
+
+	nodes := make([]string, 0, len(pending))
+	for nodeID := range pending {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+
+	total := 0
+	for _, nodeID := range nodes {
+		samples := pending[nodeID]
+		from, to := samples[0].Timestamp, samples[0].Timestamp
+		for _, sample := range samples {
+			if sample.Timestamp.Before(from) {
+				from = sample.Timestamp
+			}
+			if sample.Timestamp.After(to) {
+				to = sample.Timestamp
+			}
+		}
+
+		segment, err := a.archive(nodeID, samples, from, to)
+		if err != nil {
+			a.mutex.Lock()
+			a.failures++
+			a.mutex.Unlock()
+			a.logger.Warnf("⚠️ Failed to archive %d heartbeats of %s (kept in memory for the next run): %v", len(samples), nodeID, err)
+			continue
+		}
+
+		a.mutex.Lock()
+		a.segments = append(a.segments, *segment)
+		a.mergeSummariesLocked(nodeID, samples)
+		a.archived += uint64(len(samples))
+		a.archivedBytes += uint64(segment.Bytes)
+		a.mutex.Unlock()
+
+		a.history.DropSamples(nodeID, to)
+		total += len(samples)
+	}
+
+	a.expire(now)
+
+	a.mutex.Lock()
+	a.compactions++
+	a.lastCompaction = now
+	err := a.saveLocked()
+	a.mutex.Unlock()
+	if err != nil {
+		a.logger.Warnf("⚠️ Failed to persist heartbeat archive index: %v", err)
+	}
+	if total > 0 {
+		a.logger.Infof("🧊 Compacted %d heartbeats from %d nodes into hourly summaries (%s archive)", total, len(nodes), a.store.Name())
+	}
+	return total
+}
+
+// archive - 세그먼트 저장
+func (a *HeartbeatArchive) archive(nodeID string, samples []HeartbeatSample, from, to time.Time) (*ArchiveSegment, error) {
+	data, err := encodeSegment(nodeID, samples)
+	if err != nil {
+		return nil, err
+	}
+	key := archiveKey(nodeID, from, to)
+	location, err := a.store.Put(key, data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &ArchiveSegment{
+		ID:         hex.EncodeToString(sum[:8]),
+		NodeID:     nodeID,
+		From:       from.UTC(),
+		To:         to.UTC(),
+		Samples:    len(samples),
+		Bytes:      len(data),
+		SHA256:     hex.EncodeToString(sum[:]),
+		Backend:    a.store.Name(),
+		Location:   location,
+		ArchivedAt: time.Now().UTC(),
+	}, nil
+}
+
+// mergeSummariesLocked - 샘플을 시간 단위 요약에 합침 (같은 구간이 이미 있으면 이어서 집계)
+func (a *HeartbeatArchive) mergeSummariesLocked(nodeID string, samples []HeartbeatSample) {
+	summaries := a.summaries[nodeID]
+	index := make(map[int64]int, len(summaries))
+	for i, summary := range summaries {
+		index[summary.Start.Unix()] = i
+	}
+	for _, sample := range samples {
+		start := sample.Timestamp.UTC().Truncate(heartbeatSummaryWindow)
+		i, ok := index[start.Unix()]
+		if !ok {
+			summaries = append(summaries, HeartbeatSummary{Start: start})
+			i = len(summaries) - 1
+			index[start.Unix()] = i
+		}
+		summaries[i].add(sample)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Start.Before(summaries[j].Start) })
+	a.summaries[nodeID] = summaries
+}
+
+// expire - 보관 기간이 지난 요약과 세그먼트 삭제
+func (a *HeartbeatArchive) expire(now time.Time) {
+	summaryCutoff := now.Add(-a.summaryRetention)
+	archiveCutoff := now.Add(-a.archiveRetention)
+
+	a.mutex.Lock()
+	for nodeID, summaries := range a.summaries {
+		kept := summaries[:0]
+		for _, summary := range summaries {
+			if summary.Start.After(summaryCutoff) {
+				kept = append(kept, summary)
+			}
+		}
+		if len(kept) == 0 {
+			delete(a.summaries, nodeID)
+		} else {
+			a.summaries[nodeID] = kept
+		}
+	}
+	var expired []ArchiveSegment
+	kept := a.segments[:0]
+	for _, segment := range a.segments {
+		if segment.To.Before(archiveCutoff) {
+			expired = append(expired, segment)
+		} else {
+			kept = append(kept, segment)
+		}
+	}
+	a.segments = kept
+	a.mutex.Unlock()
+
+	for _, segment := range expired {
+		if err := a.deleteSegment(segment); err != nil {
+			a.logger.Warnf("⚠️ Failed to delete expired heartbeat segment %s: %v", segment.Location, err)
+		}
+	}
+}
+
+// deleteSegment - 저장소에서 세그먼트 삭제 (Walrus는 저장 기간이 지나면 만료되므로 색인만 정리)
+func (a *HeartbeatArchive) deleteSegment(segment ArchiveSegment) error {
+	if segment.Backend != a.store.Name() {
+		return fmt.Errorf("segment is stored on %s, current backend is %s", segment.Backend, a.store.Name())
+	}
+	err := a.store.Delete(segment.Location)
+	if errors.Is(err, errColdStoreImmutable) {
+		return nil
+	}
+	return err
+}
+
+// saveLocked - 색인과 요약을 함께 저장 (한쪽만 바뀐 상태로 남지 않도록 트랜잭션 사용)
+func (a *HeartbeatArchive) saveLocked() error {
+	txn := newStateTxn()
+	if err := txn.Put(a.indexFile, a.segments); err != nil {
+		return err
+	}
+	if err := txn.Put(a.summaryFile, a.summaries); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// Summaries - 노드의 [from, to) 구간 요약
+func (a *HeartbeatArchive) Summaries(nodeID string, from, to time.Time) []HeartbeatSummary {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	result := []HeartbeatSummary{}
+	for _, summary := range a.summaries[nodeID] {
+		if !summary.Start.Add(heartbeatSummaryWindow).After(from) || !summary.Start.Before(to) {
+			continue
+		}
+		result = append(result, summary)
+	}
+	return result
+}
+
+// Segments - 세그먼트 색인 (nodeID가 비어 있으면 전체)
+func (a *HeartbeatArchive) Segments(nodeID string) []ArchiveSegment {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	result := []ArchiveSegment{}
+	for _, segment := range a.segments {
+		if nodeID == "" || segment.NodeID == nodeID {
+			result = append(result, segment)
+		}
+	}
+	return result
+}
+
+// Rehydrate - 콜드 스토리지에서 노드의 [from, to] 구간 원본 샘플 복원 (해시 검증, 최대 maxRehydrate개)
+func (a *HeartbeatArchive) Rehydrate(nodeID string, from, to time.Time) (*RehydratedHistory, error) {
+	result := &RehydratedHistory{
+		NodeID:     nodeID,
+		From:       from.UTC(),
+		To:         to.UTC(),
+		Heartbeats: []HeartbeatSample{},
+		Summaries:  a.Summaries(nodeID, from, to),
+		Segments:   []string{},
+	}
+
+	var matching []ArchiveSegment
+	for _, segment := range a.Segments(nodeID) {
+		if !segment.To.Before(from) && !segment.From.After(to) {
+			matching = append(matching, segment)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].From.Before(matching[j].From) })
+
+	for _, segment := range matching {
+		samples, err := a.readSegment(segment)
+		if err != nil {
+			return nil, fmt.Errorf("segment %s: %v", segment.ID, err)
+		}
+		result.Segments = append(result.Segments, segment.ID)
+		for _, sample := range samples {
+			if sample.Timestamp.Before(from) || sample.Timestamp.After(to) {
+				continue
+			}
+			if len(result.Heartbeats) >= a.maxRehydrate {
+				result.Truncated = true
+				break
+			}
+			result.Heartbeats = append(result.Heartbeats, sample)
+		}
+		if result.Truncated {
+			break
+		}
+	}
+
+	a.mutex.Lock()
+	a.rehydrations++
+	a.mutex.Unlock()
+	a.logger.Infof("♨️ Rehydrated %d archived heartbeats of %s from %d segments", len(result.Heartbeats), nodeID, len(result.Segments))
+	return result, nil
+}
+
+// readSegment - 세그먼트를 읽어 해시 검증 후 디코딩
+func (a *HeartbeatArchive) readSegment(segment ArchiveSegment) ([]HeartbeatSample, error) {
+	if segment.Backend != a.store.Name() {
+		return nil, fmt.Errorf("stored on %s, current backend is %s", segment.Backend, a.store.Name())
+	}
+	data, err := a.store.Get(segment.Location)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != segment.SHA256 {
+		return nil, fmt.Errorf("content hash mismatch")
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	var decoded archivedSamples
+	if err := json.NewDecoder(gz).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return decoded.Samples, nil
+}
+
+// Forget - 노드의 요약과 세그먼트 삭제 (테넌트 데이터 삭제), 삭제한 세그먼트 수와 삭제할 수 없는(Walrus) 세그먼트 수 반환
+func (a *HeartbeatArchive) Forget(nodeID string) (int, int, error) {
+	a.mutex.Lock()
+	delete(a.summaries, nodeID)
+	var removed []ArchiveSegment
+	kept := a.segments[:0]
+	for _, segment := range a.segments {
+		if segment.NodeID == nodeID {
+			removed = append(removed, segment)
+		} else {
+			kept = append(kept, segment)
+		}
+	}
+	a.segments = kept
+	err := a.saveLocked()
+	a.mutex.Unlock()
+
+	deleted, immutable := 0, 0
+	for _, segment := range removed {
+		if segment.Backend == a.store.Name() {
+			deleteErr := a.store.Delete(segment.Location)
+			if errors.Is(deleteErr, errColdStoreImmutable) {
+				immutable++
+				continue
+			}
+			if deleteErr == nil {
+				deleted++
+				continue
+			}
+			err = deleteErr
+		} else {
+			err = fmt.Errorf("segment %s is stored on %s, current backend is %s", segment.ID, segment.Backend, a.store.Name())
+		}
+	}
+	return deleted, immutable, err
+}
+
+func (a *HeartbeatArchive) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if a.adminToken == "" {
+		http.Error(w, "Heartbeat archive API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) != 1 {
+		a.logger.Warnf("🚫 Unauthorized heartbeat archive access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handleArchive - 아카이브 상태 (/api/v1/admin/heartbeat-archive, 관리자 토큰 필요)
+
+	GET  ?node_id=   세그먼트 색인
+	POST             즉시 압축
+*/
+func (a *HeartbeatArchive) handleArchive(w http.ResponseWriter, r *http.Request) {
+	if !a.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		a.Compact()
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	segments := a.Segments(r.URL.Query().Get("node_id"))
+	a.mutex.Lock()
+	lastCompaction := a.lastCompaction
+	a.mutex.Unlock()
+	writeClientJSON(w, map[string]interface{}{
+		"backend":             a.store.Name(),
+		"compact_after_hours": int(a.compactAfter / time.Hour),
+		"last_compaction":     lastCompaction,
+		"segments":            segments,
+	})
+}
+
+/*
+handleRehydrate - 기간별 원본 복원 (/api/v1/admin/heartbeat-archive/rehydrate, 관리자 토큰 필요)
+
+	POST {"node_id": "...", "from": RFC3339, "to": RFC3339}   to 기본 현재, from 기본 to-24h
+*/
+func (a *HeartbeatArchive) handleRehydrate(w http.ResponseWriter, r *http.Request) {
+	if !a.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		NodeID string    `json:"node_id"`
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.NodeID == "" {
+		http.Error(w, "node_id is required (from/to as RFC3339)", http.StatusBadRequest)
+		return
+	}
+	if body.To.IsZero() {
+		body.To = time.Now()
+	}
+	if body.From.IsZero() {
+		body.From = body.To.Add(-defaultRehydrateRange)
+	}
+	if body.From.After(body.To) {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+
+	result, err := a.Rehydrate(body.NodeID, body.From, body.To)
+	if err != nil {
+		a.logger.Errorf("❌ Failed to rehydrate heartbeats of %s: %v", body.NodeID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeClientJSON(w, result)
+}
+
+// writeMetrics - 압축/아카이브/복원 통계
+func (a *HeartbeatArchive) writeMetrics(w io.Writer) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	summaries := 0
+	for _, nodeSummaries := range a.summaries {
+		summaries += len(nodeSummaries)
+	}
+	backend := map[string]string{"backend": a.store.Name()}
+	writeMetricHeader(w, "nautilus_heartbeat_compactions_total", "counter", "Heartbeat compaction runs")
+	writeMetric(w, "nautilus_heartbeat_compactions_total", nil, float64(a.compactions))
+	writeMetricHeader(w, "nautilus_heartbeat_archived_samples_total", "counter", "Raw heartbeat samples moved to cold storage")
+	writeMetric(w, "nautilus_heartbeat_archived_samples_total", backend, float64(a.archived))
+	writeMetricHeader(w, "nautilus_heartbeat_archived_bytes_total", "counter", "Compressed bytes written to cold storage")
+	writeMetric(w, "nautilus_heartbeat_archived_bytes_total", backend, float64(a.archivedBytes))
+	writeMetricHeader(w, "nautilus_heartbeat_archive_failures_total", "counter", "Node segments that could not be archived (retried on the next run)")
+	writeMetric(w, "nautilus_heartbeat_archive_failures_total", backend, float64(a.failures))
+	writeMetricHeader(w, "nautilus_heartbeat_archive_segments", "gauge", "Archived heartbeat segments in the index")
+	writeMetric(w, "nautilus_heartbeat_archive_segments", backend, float64(len(a.segments)))
+	writeMetricHeader(w, "nautilus_heartbeat_summaries", "gauge", "Hourly heartbeat summaries kept on the master")
+	writeMetric(w, "nautilus_heartbeat_summaries", nil, float64(summaries))
+	writeMetricHeader(w, "nautilus_heartbeat_rehydrations_total", "counter", "Archived time ranges rehydrated on demand")
+	writeMetric(w, "nautilus_heartbeat_rehydrations_total", nil, float64(a.rehydrations))
+	if !a.lastCompaction.IsZero() {
+		writeMetricHeader(w, "nautilus_heartbeat_last_compaction_timestamp_seconds", "gauge", "Unix time of the last compaction run")
+		writeMetric(w, "nautilus_heartbeat_last_compaction_timestamp_seconds", nil, float64(a.lastCompaction.Unix()))
+	}
+}
@@ -0,0 +1,362 @@
+// Heartbeat Challenge - 하트비트 응답에 서명 nonce와 메모리 의존 작업 증명을 실어 한 장비의 다중 워커 신원(Sybil) 억제
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	workproof "github.com/k3s-io/daas-workproof"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+한 장비가 적은 스테이크로 여러 워커 신원을 흉내 내면 하트비트와 자가 보고만으로는 구별할 수 없습니다.
+마스터는 라운드(HEARTBEAT_CHALLENGE_INTERVAL_SECONDS, 기본 600초)마다 모든 워커에 챌린지를 하나씩 발급하고,
+워커는 다음 하트비트 응답으로 받아 그다음 하트비트의 challenge_answer로 답합니다.
+
+  - signature: seal 토큰으로 서명한 id:nonce (그 신원의 비밀을 가진 프로세스가 살아 있음)
+  - solution: memory_mib MiB를 채운 뒤 값에 따라 위치가 정해지는 읽기를 반복하는 작업 증명 (pkg/workproof)
+  - 발급 후 HEARTBEAT_CHALLENGE_DEADLINE_SECONDS(기본 120초) 안에 답해야 함
+
+같은 라운드에 모든 신원이 같은 마감 안에 작업을 끝내야 하므로 한 장비에 몰린 신원은 메모리와 CPU를 나눠 쓰다
+마감을 넘기거나 답하지 못합니다. 작업 결과 확인은 마스터도 같은 계산이 필요해 HEARTBEAT_CHALLENGE_VERIFY_RATE
+(기본 0.25) 비율만 재계산하고 (어느 답이 재계산될지 워커는 모름), 재계산은 한 번에 하나씩 수행해 마스터 메모리를 제한합니다.
+
+실패(서명/결과 불일치, 무응답, 마감 초과) 비율은 노드 건강 점수를 최대 40점 깎고,
+높은 비율이 지속되면 ClaimVerifier와 같이 슬래싱 검토 대상으로 표시합니다. 정비 중인 노드에는 발급하지 않습니다.
+*/
+
+const (
+	challengeWindowSize = 12   // 노드별 최근 챌린지 결과 보관 수
+	challengeMinSamples = 3    // 점수 감점과 표시에 필요한 최소 결과 수
+	challengeFlagRatio  = 0.5  // 슬래싱 검토 대상 표시 실패 비율
+	challengeClearRatio = 0.1  // 표시 해제 실패 비율
+	challengeMaxPenalty = 40.0 // 실패 비율 1일 때 건강 점수 감점
+	challengeQueueSize  = 64   // 재계산 대기 응답 수 (넘치면 서명만 확인)
+)
+
+// HeartbeatChallenge - 하트비트 응답에 실리는 챌린지
+type HeartbeatChallenge struct {
+	ID        string    `json:"id"`
+	Nonce     string    `json:"nonce"`
+	MemoryMiB int       `json:"memory_mib"`
+	IssuedAt  time.Time `json:"issued_at"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// ChallengeAnswer - 워커의 챌린지 응답 (다음 하트비트에 포함)
+type ChallengeAnswer struct {
+	ID        string `json:"id"`
+	Signature string `json:"signature"`
+	Solution  string `json:"solution"`
+	ElapsedMs int64  `json:"elapsed_ms"` // 워커가 측정한 작업 시간 (참고용, 판정에는 마감만 사용)
+}
+
+// ChallengeRecord - 노드별 챌린지 기록
+type ChallengeRecord struct {
+	NodeID        string    `json:"node_id"`
+	Issued        uint64    `json:"issued"`
+	Passed        uint64    `json:"passed"`
+	Failed        uint64    `json:"failed"`
+	Unverified    uint64    `json:"unverified"` // 서명과 마감만 확인하고 작업 결과는 재계산하지 않은 응답
+	LastElapsedMs int64     `json:"last_elapsed_ms"`
+	LastFailure   string    `json:"last_failure,omitempty"`
+	FailureRatio  float64   `json:"failure_ratio"`
+	Flagged       bool      `json:"flagged"`
+	FlaggedAt     time.Time `json:"flagged_at,omitempty"`
+	window        []bool
+	pending       *HeartbeatChallenge
+	round         int64 // 마지막으로 발급한 라운드
+}
+
+// challengeCheck - 재계산 대기 중인 응답
+type challengeCheck struct {
+	nodeID    string
+	challenge *HeartbeatChallenge
+	solution  string
+}
+
+// HeartbeatChallenger - 하트비트 챌린지 발급과 검증
+type HeartbeatChallenger struct {
+	logger      *logrus.Logger
+	history     *HeartbeatHistory
+	maintenance *MaintenanceScheduler
+	interval    time.Duration
+	deadline    time.Duration
+	memoryMiB   int
+	verifyRate  float64
+	checks      chan challengeCheck
+
+	mutex   sync.Mutex
+	records map[string]*ChallengeRecord
+	dropped uint64
+}
+
+// NewHeartbeatChallenger - 새 Heartbeat Challenger 생성
+// (HEARTBEAT_CHALLENGE_INTERVAL_SECONDS, HEARTBEAT_CHALLENGE_DEADLINE_SECONDS, HEARTBEAT_CHALLENGE_MEMORY_MIB, HEARTBEAT_CHALLENGE_VERIFY_RATE)
+func NewHeartbeatChallenger(logger *logrus.Logger) (*HeartbeatChallenger, error) {
+	memoryMiB, err := strconv.Atoi(getEnvOrDefault("HEARTBEAT_CHALLENGE_MEMORY_MIB", "32"))
+	if err != nil || memoryMiB < 1 || memoryMiB > workproof.MaxMemoryMiB {
+		return nil, fmt.Errorf("HEARTBEAT_CHALLENGE_MEMORY_MIB must be 1-%d", workproof.MaxMemoryMiB)
+	}
+	verifyRate, err := strconv.ParseFloat(getEnvOrDefault("HEARTBEAT_CHALLENGE_VERIFY_RATE", "0.25"), 64)
+	if err != nil || verifyRate < 0 || verifyRate > 1 {
+		return nil, fmt.Errorf("HEARTBEAT_CHALLENGE_VERIFY_RATE must be between 0 and 1")
+	}
+	interval := envSeconds("HEARTBEAT_CHALLENGE_INTERVAL_SECONDS", 600)
+	deadline := envSeconds("HEARTBEAT_CHALLENGE_DEADLINE_SECONDS", 120)
+	if deadline >= interval {
+		return nil, fmt.Errorf("HEARTBEAT_CHALLENGE_DEADLINE_SECONDS must be shorter than the challenge interval")
+	}
+
+	return &HeartbeatChallenger{
+		logger:     logger,
+		interval:   interval,
+		deadline:   deadline,
+		memoryMiB:  memoryMiB,
+		verifyRate: verifyRate,
+		checks:     make(chan challengeCheck, challengeQueueSize),
+		records:    make(map[string]*ChallengeRecord),
+	}, nil
+}
+
+// Start - 작업 결과 재계산 (한 번에 하나)
+func (c *HeartbeatChallenger) Start(ctx context.Context) {
+	c.logger.Infof("🧩 Starting Heartbeat Challenger (every %s, %d MiB, deadline %s)", c.interval, c.memoryMiB, c.deadline)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case check := <-c.checks:
+			ok, err := workproof.Verify(check.challenge.Nonce, check.challenge.MemoryMiB, check.solution)
+			c.mutex.Lock()
+			if record, exists := c.records[check.nodeID]; exists {
+				switch {
+				case err != nil:
+					record.Unverified++
+				case ok:
+					record.Passed++
+				default:
+					c.fail(record, "work proof does not match the challenge")
+				}
+			}
+			c.mutex.Unlock()
+		}
+	}
+}
+
+// Observe - 하트비트의 챌린지 응답을 확인하고, 응답 대기 중이거나 새 라운드면 챌린지 반환
+// sealToken이 비어 있으면 (토큰 없이 조인한 노드) 서명 확인은 생략
+func (c *HeartbeatChallenger) Observe(nodeID, sealToken string, answer *ChallengeAnswer) *HeartbeatChallenge {
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, exists := c.records[nodeID]
+	if !exists {
+		record = &ChallengeRecord{NodeID: nodeID}
+		c.records[nodeID] = record
+	}
+
+	if pending := record.pending; pending != nil {
+		switch {
+		case answer != nil && answer.ID == pending.ID:
+			record.pending = nil
+			record.LastElapsedMs = answer.ElapsedMs
+			switch {
+			case now.After(pending.Deadline):
+				c.fail(record, fmt.Sprintf("answered %s after the deadline", now.Sub(pending.Deadline).Round(time.Second)))
+			case sealToken != "" && !workproof.VerifySignature(sealToken, pending.ID, pending.Nonce, answer.Signature):
+				c.fail(record, "nonce signature does not match the seal token")
+			default:
+				c.observe(record, false)
+				c.queueCheck(record, pending, answer.Solution)
+			}
+		case now.After(pending.Deadline):
+			record.pending = nil
+			c.fail(record, "no answer before the deadline")
+		}
+	}
+
+	round := now.UnixNano() / int64(c.interval)
+	if record.pending == nil && record.round < round && !c.maintenance.InMaintenance(nodeID) {
+		record.round = round
+		record.pending = c.newChallenge(now)
+		record.Issued++
+	}
+	return record.pending
+}
+
+// queueCheck - verifyRate 확률로 작업 결과 재계산 예약 (호출자가 mutex 보유)
+func (c *HeartbeatChallenger) queueCheck(record *ChallengeRecord, challenge *HeartbeatChallenge, solution string) {
+	if mathrand.Float64() >= c.verifyRate {
+		record.Unverified++
+		return
+	}
+	select {
+	case c.checks <- challengeCheck{nodeID: record.NodeID, challenge: challenge, solution: solution}:
+	default:
+		record.Unverified++
+		c.dropped++
+	}
+}
+
+func (c *HeartbeatChallenger) newChallenge(now time.Time) *HeartbeatChallenge {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	return &HeartbeatChallenge{
+		ID:        fmt.Sprintf("challenge_%d", now.UnixNano()),
+		Nonce:     hex.EncodeToString(nonce),
+		MemoryMiB: c.memoryMiB,
+		IssuedAt:  now,
+		Deadline:  now.Add(c.deadline),
+	}
+}
+
+// fail - 실패 기록 (호출자가 mutex 보유)
+func (c *HeartbeatChallenger) fail(record *ChallengeRecord, reason string) {
+	record.Failed++
+	record.LastFailure = reason
+	c.logger.Warnf("🧩 Heartbeat challenge failed on %s: %s", record.NodeID, reason)
+	c.observe(record, true)
+}
+
+// observe - 결과를 최근 창에 기록하고 슬래싱 검토 표시 갱신 (호출자가 mutex 보유)
+func (c *HeartbeatChallenger) observe(record *ChallengeRecord, failed bool) {
+	record.window = append(record.window, failed)
+	if len(record.window) > challengeWindowSize {
+		record.window = record.window[len(record.window)-challengeWindowSize:]
+	}
+	count := 0
+	for _, f := range record.window {
+		if f {
+			count++
+		}
+	}
+	record.FailureRatio = float64(count) / float64(len(record.window))
+
+	if len(record.window) < challengeMinSamples {
+		return
+	}
+	switch {
+	case !record.Flagged && record.FailureRatio >= challengeFlagRatio:
+		record.Flagged = true
+		record.FlaggedAt = time.Now()
+		detail := fmt.Sprintf("failed %.0f%% of recent heartbeat challenges (last: %s)", record.FailureRatio*100, record.LastFailure)
+		c.logger.Warnf("🚩 Node %s flagged for slashing review: %s", record.NodeID, detail)
+		c.history.RecordEvent(record.NodeID, "slashing_review", detail)
+	case record.Flagged && record.FailureRatio <= challengeClearRatio:
+		record.Flagged = false
+		record.FlaggedAt = time.Time{}
+		c.logger.Infof("✅ Node %s passes heartbeat challenges again", record.NodeID)
+		c.history.RecordEvent(record.NodeID, "challenges_passing", fmt.Sprintf("failure %.0f%%", record.FailureRatio*100))
+	}
+}
+
+// Penalty - 노드 건강 점수 감점 (결과가 적으면 0)
+func (c *HeartbeatChallenger) Penalty(nodeID string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, exists := c.records[nodeID]
+	if !exists || len(record.window) < challengeMinSamples {
+		return 0
+	}
+	return record.FailureRatio * challengeMaxPenalty
+}
+
+// PenalizedNodes - 감점이 있는 노드 (Pod가 없어 건강 점수 대상이 아닌 노드도 평가하도록)
+func (c *HeartbeatChallenger) PenalizedNodes() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var nodes []string
+	for nodeID, record := range c.records {
+		if len(record.window) >= challengeMinSamples && record.FailureRatio > 0 {
+			nodes = append(nodes, nodeID)
+		}
+	}
+	return nodes
+}
+
+// ListRecords - 노드 ID 순으로 챌린지 기록 반환
+func (c *HeartbeatChallenger) ListRecords() []ChallengeRecord {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]ChallengeRecord, 0, len(c.records))
+	for _, record := range c.records {
+		copied := *record
+		copied.window = nil
+		copied.pending = nil
+		result = append(result, copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].NodeID < result[j].NodeID })
+	return result
+}
+
+// handleChallenges - 챌린지 기록 및 슬래싱 검토 대상 조회 API (?flagged=true)
+func (c *HeartbeatChallenger) handleChallenges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records := c.ListRecords()
+	if r.URL.Query().Get("flagged") == "true" {
+		flagged := records[:0]
+		for _, record := range records {
+			if record.Flagged {
+				flagged = append(flagged, record)
+			}
+		}
+		records = flagged
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   records,
+	})
+}
+
+// writeMetrics - 챌린지 결과 지표
+func (c *HeartbeatChallenger) writeMetrics(w io.Writer) {
+	records := c.ListRecords()
+	c.mutex.Lock()
+	dropped := c.dropped
+	c.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_challenge_failure_ratio", "gauge", "Share of recent heartbeat challenges the node failed or missed")
+	for _, record := range records {
+		writeMetric(w, "nautilus_challenge_failure_ratio", map[string]string{"node": record.NodeID}, record.FailureRatio)
+	}
+	writeMetricHeader(w, "nautilus_challenge_results_total", "counter", "Heartbeat challenge answers by result")
+	for _, record := range records {
+		writeMetric(w, "nautilus_challenge_results_total", map[string]string{"node": record.NodeID, "result": "passed"}, float64(record.Passed))
+		writeMetric(w, "nautilus_challenge_results_total", map[string]string{"node": record.NodeID, "result": "failed"}, float64(record.Failed))
+		writeMetric(w, "nautilus_challenge_results_total", map[string]string{"node": record.NodeID, "result": "unverified"}, float64(record.Unverified))
+	}
+	writeMetricHeader(w, "nautilus_challenge_flagged", "gauge", "Whether the node is flagged for slashing review after failing heartbeat challenges")
+	for _, record := range records {
+		flagged := 0.0
+		if record.Flagged {
+			flagged = 1
+		}
+		writeMetric(w, "nautilus_challenge_flagged", map[string]string{"node": record.NodeID}, flagged)
+	}
+	writeMetricHeader(w, "nautilus_challenge_checks_dropped_total", "counter", "Work proofs left unverified because the verification queue was full")
+	writeMetric(w, "nautilus_challenge_checks_dropped_total", nil, float64(dropped))
+}
@@ -0,0 +1,309 @@
+// Heartbeat History - 노드별 하트비트 시계열(링 버퍼)과 상태 이벤트 타임라인
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHeartbeatHistorySize = 2880 // 30초 간격 기준 24시간
+	maxNodeEvents               = 200
+)
+
+// HeartbeatSample - 하트비트 한 건의 자원/상태 스냅샷
+type HeartbeatSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	RunningPods   int       `json:"running_pods"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	MemoryPercent float64   `json:"memory_percent"`
+	DiskPercent   float64   `json:"disk_percent"`
+	LatencyMs     int64     `json:"latency_ms"`
+	StakeStatus   string    `json:"stake_status,omitempty"`
+	StakeAmount   uint64    `json:"stake_amount,omitempty"`
+}
+
+// NodeEvent - 상태 변화 이벤트 (슬래싱, probation, 상태 변경 등)
+type NodeEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Detail    string    `json:"detail"`
+}
+
+// heartbeatRing - 고정 크기 순환 버퍼
+type heartbeatRing struct {
+	samples []HeartbeatSample
+	next    int
+	filled  bool
+}
+
+// ordered - 오래된 순서로 샘플 반환
+func (r *heartbeatRing) ordered() []HeartbeatSample {
+	if !r.filled {
+		return append([]HeartbeatSample(nil), r.samples[:r.next]...)
+	}
+	result := make([]HeartbeatSample, 0, len(r.samples))
+	result = append(result, r.samples[r.next:]...)
+	return append(result, r.samples[:r.next]...)
+}
+
+// HeartbeatHistory - 노드별 하트비트 기록 저장소
+type HeartbeatHistory struct {
+	logger *logrus.Logger
+	size   int
+	rings  map[string]*heartbeatRing
+	events map[string][]NodeEvent
+	mutex  sync.RWMutex
+	stream *EventStream // 대시보드 스트림 (선택)
+
+	archive *HeartbeatArchive // 압축된 구간의 시간 단위 요약 (선택)
+}
+
+// NewHeartbeatHistory - 새 Heartbeat History 생성 (HEARTBEAT_HISTORY_SIZE로 노드당 샘플 수 조정)
+func NewHeartbeatHistory(logger *logrus.Logger) *HeartbeatHistory {
+	size, err := strconv.Atoi(getEnvOrDefault("HEARTBEAT_HISTORY_SIZE", strconv.Itoa(defaultHeartbeatHistorySize)))
+	if err != nil || size <= 0 {
+		size = defaultHeartbeatHistorySize
+	}
+
+	return &HeartbeatHistory{
+		logger: logger,
+		size:   size,
+		rings:  make(map[string]*heartbeatRing),
+		events: make(map[string][]NodeEvent),
+	}
+}
+
+// Record - 하트비트 샘플 추가 (스테이킹 상태가 바뀌면 이벤트도 기록)
+func (h *HeartbeatHistory) Record(nodeID string, sample HeartbeatSample) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	ring, exists := h.rings[nodeID]
+	if !exists {
+		ring = &heartbeatRing{samples: make([]HeartbeatSample, h.size)}
+		h.rings[nodeID] = ring
+	}
+
+	if previous := h.lastLocked(ring); previous != nil && sample.StakeStatus != "" && previous.StakeStatus != sample.StakeStatus {
+		h.addEventLocked(nodeID, NodeEvent{
+			Timestamp: sample.Timestamp,
+			Kind:      "stake_status",
+			Detail:    previous.StakeStatus + " → " + sample.StakeStatus,
+		})
+	}
+
+	ring.samples[ring.next] = sample
+	ring.next = (ring.next + 1) % len(ring.samples)
+	if ring.next == 0 {
+		ring.filled = true
+	}
+}
+
+// lastLocked - 가장 최근 샘플
+func (h *HeartbeatHistory) lastLocked(ring *heartbeatRing) *HeartbeatSample {
+	if ring.next == 0 && !ring.filled {
+		return nil
+	}
+	index := (ring.next - 1 + len(ring.samples)) % len(ring.samples)
+	return &ring.samples[index]
+}
+
+// RecordEvent - 노드 이벤트 기록
+func (h *HeartbeatHistory) RecordEvent(nodeID, kind, detail string) {
+	if h == nil {
+		return
+	}
+	h.stream.PublishNodeEvent(nodeID, kind, detail)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.addEventLocked(nodeID, NodeEvent{Timestamp: time.Now(), Kind: kind, Detail: detail})
+}
+
+// addEventLocked - 이벤트 추가 (노드당 최근 maxNodeEvents개 유지)
+func (h *HeartbeatHistory) addEventLocked(nodeID string, event NodeEvent) {
+	events := append(h.events[nodeID], event)
+	if len(events) > maxNodeEvents {
+		events = events[len(events)-maxNodeEvents:]
+	}
+	h.events[nodeID] = events
+}
+
+// Timeline - since 이후의 샘플과 이벤트 (limit > 0이면 최근 limit개 샘플)
+func (h *HeartbeatHistory) Timeline(nodeID string, since time.Time, limit int) ([]HeartbeatSample, []NodeEvent) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	samples := []HeartbeatSample{}
+	if ring, exists := h.rings[nodeID]; exists {
+		for _, sample := range ring.ordered() {
+			if !sample.Timestamp.Before(since) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	if limit > 0 && len(samples) > limit {
+		samples = samples[len(samples)-limit:]
+	}
+
+	events := []NodeEvent{}
+	for _, event := range h.events[nodeID] {
+		if !event.Timestamp.Before(since) {
+			events = append(events, event)
+		}
+	}
+	return samples, events
+}
+
+// Prune - cutoff 이전 샘플과 이벤트 삭제 (보관 정책), 삭제한 항목 수 반환
+func (h *HeartbeatHistory) Prune(cutoff time.Time) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	removed := 0
+	for nodeID := range h.rings {
+		removed += h.keepSamplesLocked(nodeID, cutoff)
+	}
+	for nodeID, events := range h.events {
+		kept := events[:0]
+		for _, event := range events {
+			if event.Timestamp.After(cutoff) {
+				kept = append(kept, event)
+			}
+		}
+		removed += len(events) - len(kept)
+		if len(kept) == 0 {
+			delete(h.events, nodeID)
+		} else {
+			h.events[nodeID] = kept
+		}
+	}
+	return removed
+}
+
+// keepSamplesLocked - 노드의 cutoff 이전(cutoff 포함) 샘플 삭제, 삭제한 샘플 수 반환
+func (h *HeartbeatHistory) keepSamplesLocked(nodeID string, cutoff time.Time) int {
+	ring, ok := h.rings[nodeID]
+	if !ok {
+		return 0
+	}
+	ordered := ring.ordered()
+	kept := ordered[:0]
+	for _, sample := range ordered {
+		if sample.Timestamp.After(cutoff) {
+			kept = append(kept, sample)
+		}
+	}
+	if len(kept) == len(ordered) {
+		return 0
+	}
+	if len(kept) == 0 {
+		delete(h.rings, nodeID)
+		return len(ordered)
+	}
+	// 남은 샘플을 앞에서부터 다시 채움
+	rebuilt := &heartbeatRing{samples: make([]HeartbeatSample, h.size)}
+	rebuilt.next = copy(rebuilt.samples, kept) % h.size
+	rebuilt.filled = len(kept) == h.size
+	h.rings[nodeID] = rebuilt
+	return len(ordered) - len(kept)
+}
+
+// SamplesBefore - 노드별 cutoff 이전 샘플 사본 (오래된 순서, 압축/아카이브용)
+func (h *HeartbeatHistory) SamplesBefore(cutoff time.Time) map[string][]HeartbeatSample {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	result := make(map[string][]HeartbeatSample)
+	for nodeID, ring := range h.rings {
+		for _, sample := range ring.ordered() {
+			if sample.Timestamp.Before(cutoff) {
+				result[nodeID] = append(result[nodeID], sample)
+			}
+		}
+	}
+	return result
+}
+
+// DropSamples - 아카이브가 끝난 샘플(through 시각까지) 삭제, 삭제한 샘플 수 반환
+func (h *HeartbeatHistory) DropSamples(nodeID string, through time.Time) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.keepSamplesLocked(nodeID, through)
+}
+
+// Forget - 노드의 샘플과 이벤트 전부 삭제 (테넌트 데이터 삭제), 삭제한 항목 수 반환
+func (h *HeartbeatHistory) Forget(nodeID string) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	removed := len(h.events[nodeID])
+	if ring, ok := h.rings[nodeID]; ok {
+		removed += len(ring.ordered())
+	}
+	delete(h.rings, nodeID)
+	delete(h.events, nodeID)
+	return removed
+}
+
+// handleTimeline - GET /api/v1/nodes/{id}/timeline?since=6h&limit=100
+func (h *HeartbeatHistory) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nodeID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/"), "/timeline")
+	if nodeID == "" || strings.Contains(nodeID, "/") || !strings.HasSuffix(r.URL.Path, "/timeline") {
+		http.NotFound(w, r)
+		return
+	}
+
+	// since는 기간(6h) 또는 RFC3339 시각, 기본 24시간
+	since := time.Now().Add(-24 * time.Hour)
+	if value := r.URL.Query().Get("since"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, value); err == nil {
+			since = t
+		} else {
+			http.Error(w, "Invalid since parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	samples, events := h.Timeline(nodeID, since, limit)
+	data := map[string]interface{}{
+		"node_id":    nodeID,
+		"since":      since,
+		"heartbeats": samples,
+		"events":     events,
+	}
+	// 원본이 아카이브된 구간은 시간 단위 요약으로 (원본은 관리자 rehydrate API)
+	if h.archive != nil {
+		data["summaries"] = h.archive.Summaries(nodeID, since, time.Now())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   data,
+	})
+}
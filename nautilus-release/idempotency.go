@@ -0,0 +1,79 @@
+// Idempotency - 워커 등록과 Pod 생성 재시도가 두 번 실행되지 않도록 Idempotency-Key 응답 재생
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+)
+
+/*
+워커 등록(/api/v1/nodes/register)과 K8s API 프록시의 변경 요청이 하나의 재생 캐시를 공유합니다.
+Idempotency-Key 헤더가 있으면 NAUTILUS_IDEMPOTENCY_TTL_SECONDS(기본 600초) 동안 첫 응답을 그대로 돌려주고,
+헤더가 없으면 아래 요청만 내용으로 키를 만들어 네트워크 재시도 구간(30초) 동안만 재생합니다.
+  - 워커 등록: 같은 Seal 토큰, 같은 node_id (재시도마다 1회용 조인 토큰이 새로 발급되던 문제)
+  - Pod 생성: 이름을 지정한 같은 본문의 POST (generateName은 매번 새 Pod가 의도이므로 제외)
+키는 Seal 토큰/Bearer 토큰/Gateway가 전달한 사용자별로 분리됩니다.
+*/
+
+var podCollectionPath = regexp.MustCompile(`^/api/v1/namespaces/[^/]+/pods/?$`)
+
+// newIdempotencyCache - 마스터 공용 재생 캐시
+func newIdempotencyCache() *httpserver.IdempotencyCache {
+	return httpserver.NewIdempotencyCache(httpserver.IdempotencyConfig{
+		TTL:   envSeconds("NAUTILUS_IDEMPOTENCY_TTL_SECONDS", 600),
+		Scope: idempotencyScope,
+	})
+}
+
+// idempotencyScope - 호출자 식별 (Seal 토큰, Bearer 토큰, Gateway가 전달한 사용자)
+func idempotencyScope(r *http.Request) string {
+	return requestSealToken(r) + "\x00" + r.Header.Get("Authorization") + "\x00" + r.Header.Get("Impersonate-User")
+}
+
+// deriveRegistrationKey - 헤더 없는 등록 재시도는 node_id 기준 (본문의 timestamp는 재시도마다 바뀜)
+func deriveRegistrationKey(r *http.Request, body []byte) string {
+	var registration struct {
+		NodeID string `json:"node_id"`
+	}
+	json.Unmarshal(body, &registration)
+	return "register:" + registration.NodeID
+}
+
+// derivePodCreateKey - 이름을 지정한 Pod 생성만 본문 해시로 키 생성
+func derivePodCreateKey(r *http.Request, body []byte) string {
+	if r.Method != http.MethodPost || !podCollectionPath.MatchString(r.URL.Path) {
+		return ""
+	}
+	var pod struct {
+		Metadata struct {
+			Name         string `json:"name"`
+			GenerateName string `json:"generateName"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &pod); err != nil || pod.Metadata.Name == "" {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return "pod-create:" + hex.EncodeToString(sum[:])
+}
+
+// idempotencyCollector - 재생 캐시 메트릭
+func idempotencyCollector(cache *httpserver.IdempotencyCache) MetricsCollector {
+	return func(w io.Writer) {
+		stats := cache.Stats()
+
+		writeMetricHeader(w, "nautilus_idempotency_entries", "gauge", "Responses held in the idempotency replay cache")
+		writeMetric(w, "nautilus_idempotency_entries", nil, float64(stats.Entries))
+		writeMetricHeader(w, "nautilus_idempotency_requests_total", "counter", "Mutating requests with an idempotency key by outcome")
+		writeMetric(w, "nautilus_idempotency_requests_total", map[string]string{"outcome": "stored"}, float64(stats.Stored))
+		writeMetric(w, "nautilus_idempotency_requests_total", map[string]string{"outcome": "replayed"}, float64(stats.Replayed))
+		writeMetric(w, "nautilus_idempotency_requests_total", map[string]string{"outcome": "in_progress"}, float64(stats.InProgress))
+		writeMetric(w, "nautilus_idempotency_requests_total", map[string]string{"outcome": "mismatched"}, float64(stats.Mismatched))
+	}
+}
@@ -0,0 +1,467 @@
+// Image Platforms - 워커 OS/아키텍처에 맞는 멀티 아키텍처 이미지 플랫폼 선택 (호환 플랫폼이 없으면 스케줄링 거부)
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	nodeOSLabel         = "kubernetes.io/os"
+	nodeArchLabel       = "kubernetes.io/arch"
+	imagePlatformsAnnot = "k3s-daas.io/image-platforms"
+
+	imagePlatformRetry = time.Minute // 조회 실패한 이미지는 이 시간 동안 다시 묻지 않음
+)
+
+// manifestAccept - 멀티 아키텍처 index와 단일 manifest를 모두 받도록 요청
+var manifestAccept = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageRef - 컨테이너 이미지 참조를 레지스트리 API 경로로 분해한 값
+type imageRef struct {
+	registry string // 레지스트리 호스트 (Docker Hub는 registry-1.docker.io)
+	repo     string // 저장소 (Docker Hub 공식 이미지는 library/ 접두사)
+	ref      string // 태그 또는 다이제스트 (없으면 latest)
+}
+
+// parseImageRef - "nginx", "ghcr.io/org/app:v1", "repo@sha256:..." 형식 해석
+func parseImageRef(image string) (imageRef, error) {
+	if image == "" || strings.ContainsAny(image, " \t") {
+		return imageRef{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	name, digest, hasDigest := strings.Cut(image, "@")
+	tag := ""
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+
+	ref := imageRef{registry: "docker.io", repo: name}
+	if host, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.registry, ref.repo = host, rest
+	}
+	if ref.registry == "docker.io" || ref.registry == "index.docker.io" {
+		ref.registry = "registry-1.docker.io"
+		if !strings.Contains(ref.repo, "/") {
+			ref.repo = "library/" + ref.repo
+		}
+	}
+
+	switch {
+	case hasDigest:
+		ref.ref = digest
+	case tag != "":
+		ref.ref = tag
+	default:
+		ref.ref = "latest"
+	}
+	return ref, nil
+}
+
+type imagePlatformEntry struct {
+	platforms map[string]string // "os/arch" → 플랫폼별 manifest 다이제스트
+	index     bool              // 멀티 아키텍처 index 여부 (단일 manifest는 다이제스트로 고정하지 않음)
+	err       error
+	fetched   time.Time
+}
+
+/*
+ImagePlatformResolver - 워크로드 생성 요청의 이미지 플랫폼을 워커 플랫폼과 대조
+
+  - 각 컨테이너 이미지의 manifest(index)를 조회해 지원 플랫폼을 구하고, 모든 이미지가 공통으로 지원하는
+    플랫폼만 kubernetes.io/os, kubernetes.io/arch nodeAffinity로 허용
+  - 그중 실제 워커가 제공하는 플랫폼이 없으면 어떤 이미지가 무엇을 지원하는지 밝혀 요청을 거부
+  - 사용할 수 있는 플랫폼이 하나뿐이면 멀티 아키텍처 이미지를 해당 플랫폼 다이제스트로 고정
+    (둘 이상이면 태그를 유지하고 pull 시 containerd가 노드 플랫폼에 맞는 다이제스트를 선택)
+
+레지스트리에 닿지 않거나 manifest를 해석할 수 없으면 제약 없이 통과시킵니다 (레지스트리 장애로 배포가 막히지 않도록).
+플랫폼을 보고한 워커가 없을 때도 판단할 수 없으므로 통과시킵니다.
+*/
+type ImagePlatformResolver struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	client     *http.Client
+	ttl        time.Duration
+	insecure   []string // HTTP로 접근하는 레지스트리 (NAUTILUS_INSECURE_REGISTRIES)
+
+	mutex    sync.Mutex
+	cache    map[string]imagePlatformEntry
+	tokens   map[string]registryToken // "레지스트리/저장소" → 익명 pull 토큰
+	admitted map[string]uint64        // 결과별 (constrained, pinned, rejected, unresolved)
+}
+
+// NewImagePlatformResolver - 환경변수 설정으로 Image Platform Resolver 생성
+func NewImagePlatformResolver(logger *logrus.Logger, workerPool *WorkerPool) *ImagePlatformResolver {
+	return &ImagePlatformResolver{
+		logger:     logger,
+		workerPool: workerPool,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		ttl:        envSeconds("NAUTILUS_IMAGE_PLATFORM_TTL", 600),
+		insecure:   splitList(os.Getenv("NAUTILUS_INSECURE_REGISTRIES")),
+		cache:      make(map[string]imagePlatformEntry),
+		tokens:     make(map[string]registryToken),
+		admitted:   make(map[string]uint64),
+	}
+}
+
+// Admit - Pod 템플릿 이미지의 플랫폼을 nodeAffinity로 제한하고 호환 플랫폼이 없으면 거부
+func (p *ImagePlatformResolver) Admit(request *K8sAPIRequest, payload *admissionPayload) error {
+	method := strings.ToUpper(request.Method)
+	if (method != "POST" && method != "PUT") || request.Payload == "" {
+		return nil
+	}
+	workers := p.workerPool.WorkerPlatforms()
+	if len(workers) == 0 {
+		return nil
+	}
+
+	podSpec, podMeta := podTemplateOf(payload.Object())
+	if podSpec == nil {
+		return nil
+	}
+	containers := podContainers(podSpec)
+
+	// 모든 이미지가 공통으로 지원하는 플랫폼
+	resolved := make(map[string]imagePlatformEntry)
+	var images []string
+	var compatible map[string]bool
+	for _, container := range containers {
+		image, _ := container["image"].(string)
+		if _, done := resolved[image]; done || image == "" {
+			continue
+		}
+		entry := p.platformsFor(image)
+		if entry.err != nil {
+			p.count("unresolved")
+			p.logger.Warnf("⚠️ Cannot resolve platforms of image %s, leaving the choice to the runtime: %v", image, entry.err)
+			return nil
+		}
+		resolved[image] = entry
+		images = append(images, image)
+
+		next := make(map[string]bool)
+		for platform := range entry.platforms {
+			if compatible == nil || compatible[platform] {
+				next[platform] = true
+			}
+		}
+		compatible = next
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	// nodeSelector로 직접 고른 OS/아키텍처가 있으면 그 안에서만 선택
+	selector, _ := podSpec["nodeSelector"].(map[string]interface{})
+	candidates := make([]string, 0, len(compatible))
+	for platform := range compatible {
+		osName, arch, _ := strings.Cut(platform, "/")
+		if want, ok := selector[nodeOSLabel].(string); ok && want != osName {
+			continue
+		}
+		if want, ok := selector[nodeArchLabel].(string); ok && want != arch {
+			continue
+		}
+		candidates = append(candidates, platform)
+	}
+	sort.Strings(candidates)
+
+	var usable []string
+	for _, platform := range candidates {
+		if containsString(workers, platform) {
+			usable = append(usable, platform)
+		}
+	}
+	if len(usable) == 0 {
+		p.count("rejected")
+		supported := make([]string, 0, len(images))
+		for _, image := range images {
+			supported = append(supported, fmt.Sprintf("%s (%s)", image, strings.Join(sortedKeys(resolved[image].platforms), ", ")))
+		}
+		return fmt.Errorf("no compatible platform for %s %s/%s: images support %s; workers provide %s",
+			request.Resource, request.Namespace, request.Name, strings.Join(supported, ", "), strings.Join(workers, ", "))
+	}
+
+	targets, result := candidates, "constrained"
+	if len(usable) == 1 {
+		targets, result = usable, "pinned"
+		for _, container := range containers {
+			image, _ := container["image"].(string)
+			entry := resolved[image]
+			if digest := entry.platforms[usable[0]]; entry.index && digest != "" {
+				base, _, _ := strings.Cut(image, "@")
+				container["image"] = base + "@" + digest
+			}
+		}
+	}
+
+	var oses, arches []string
+	for _, platform := range targets {
+		osName, arch, _ := strings.Cut(platform, "/")
+		if !containsString(oses, osName) {
+			oses = append(oses, osName)
+		}
+		if !containsString(arches, arch) {
+			arches = append(arches, arch)
+		}
+	}
+	addRequiredNodeExpression(podSpec, map[string]interface{}{
+		"key": nodeOSLabel, "operator": "In", "values": toInterfaceSlice(oses),
+	})
+	addRequiredNodeExpression(podSpec, map[string]interface{}{
+		"key": nodeArchLabel, "operator": "In", "values": toInterfaceSlice(arches),
+	})
+	if podMeta != nil {
+		childMap(podMeta, "annotations")[imagePlatformsAnnot] = strings.Join(targets, ",")
+	}
+
+	payload.MarkChanged()
+	p.count(result)
+	p.logger.Infof("🧬 Image platforms for %s %s/%s: %s (%s)", request.Resource, request.Namespace, request.Name, strings.Join(targets, ", "), result)
+	return nil
+}
+
+// podContainers - init 컨테이너와 일반 컨테이너
+func podContainers(podSpec map[string]interface{}) []map[string]interface{} {
+	var containers []map[string]interface{}
+	for _, key := range []string{"initContainers", "containers"} {
+		list, _ := podSpec[key].([]interface{})
+		for _, item := range list {
+			if container, ok := item.(map[string]interface{}); ok {
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// platformsFor - 캐시된 조회 결과 (성공은 TTL, 실패는 imagePlatformRetry 동안 유지)
+func (p *ImagePlatformResolver) platformsFor(image string) imagePlatformEntry {
+	p.mutex.Lock()
+	entry, ok := p.cache[image]
+	p.mutex.Unlock()
+
+	ttl := p.ttl
+	if entry.err != nil {
+		ttl = imagePlatformRetry
+	}
+	if ok && time.Since(entry.fetched) < ttl {
+		return entry
+	}
+
+	entry = p.resolve(image)
+	entry.fetched = time.Now()
+	p.mutex.Lock()
+	p.cache[image] = entry
+	p.mutex.Unlock()
+	return entry
+}
+
+// resolve - manifest가 index면 항목별 플랫폼, 단일 manifest면 config blob의 os/architecture
+func (p *ImagePlatformResolver) resolve(image string) imagePlatformEntry {
+	ref, err := parseImageRef(image)
+	if err != nil {
+		return imagePlatformEntry{err: err}
+	}
+	body, digest, err := p.registryGet(ref, "/manifests/"+ref.ref, manifestAccept)
+	if err != nil {
+		return imagePlatformEntry{err: err}
+	}
+
+	var manifest struct {
+		Manifests []struct {
+			Digest   string `json:"digest"`
+			Platform *struct {
+				OS           string `json:"os"`
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+		Config *struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return imagePlatformEntry{err: fmt.Errorf("invalid manifest: %v", err)}
+	}
+
+	platforms := make(map[string]string)
+	if len(manifest.Manifests) > 0 {
+		for _, item := range manifest.Manifests {
+			// 빌드 증명(attestation) manifest는 os가 unknown
+			if item.Platform == nil || item.Platform.OS == "" || item.Platform.OS == "unknown" {
+				continue
+			}
+			platform := item.Platform.OS + "/" + item.Platform.Architecture
+			if _, ok := platforms[platform]; !ok {
+				platforms[platform] = item.Digest
+			}
+		}
+		if len(platforms) == 0 {
+			return imagePlatformEntry{err: fmt.Errorf("image index lists no platforms")}
+		}
+		return imagePlatformEntry{platforms: platforms, index: true}
+	}
+	if manifest.Config == nil || manifest.Config.Digest == "" {
+		return imagePlatformEntry{err: fmt.Errorf("unsupported manifest type")}
+	}
+
+	configBody, _, err := p.registryGet(ref, "/blobs/"+manifest.Config.Digest, nil)
+	if err != nil {
+		return imagePlatformEntry{err: err}
+	}
+	var config struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	}
+	if err := json.Unmarshal(configBody, &config); err != nil || config.OS == "" || config.Architecture == "" {
+		return imagePlatformEntry{err: fmt.Errorf("image config has no platform")}
+	}
+	platforms[config.OS+"/"+config.Architecture] = digest
+	return imagePlatformEntry{platforms: platforms}
+}
+
+// registryGet - 레지스트리 v2 GET (401이면 익명 Bearer 토큰을 받아 한 번 재시도), 본문과 다이제스트 반환
+func (p *ImagePlatformResolver) registryGet(ref imageRef, suffix string, accept []string) ([]byte, string, error) {
+	scheme := "https"
+	if containsString(p.insecure, ref.registry) {
+		scheme = "http"
+	}
+	tokenKey := ref.registry + "/" + ref.repo
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, scheme+"://"+ref.registry+"/v2/"+ref.repo+suffix, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, value := range accept {
+			req.Header.Add("Accept", value)
+		}
+		p.mutex.Lock()
+		token, ok := p.tokens[tokenKey]
+		p.mutex.Unlock()
+		if ok && time.Now().Before(token.expires) {
+			req.Header.Set("Authorization", "Bearer "+token.value)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.StatusCode == http.StatusOK {
+			defer resp.Body.Close()
+			body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+			if err != nil {
+				return nil, "", err
+			}
+			digest := resp.Header.Get("Docker-Content-Digest")
+			if digest == "" {
+				sum := sha256.Sum256(body)
+				digest = "sha256:" + hex.EncodeToString(sum[:])
+			}
+			return body, digest, nil
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, "", fmt.Errorf("%s returned HTTP %d for %s", ref.registry, resp.StatusCode, ref.repo)
+		}
+		if err := p.refreshToken(tokenKey, ref.repo, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("%s authorization failed", ref.registry)
+}
+
+// refreshToken - 공개 이미지 조회용 익명 pull 토큰 발급
+func (p *ImagePlatformResolver) refreshToken(key, repo, challenge string) error {
+	params, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return err
+	}
+	query := url.Values{"service": {params["service"]}, "scope": {"repository:" + repo + ":pull"}}
+	resp, err := p.client.Get(params["realm"] + "?" + query.Encode())
+	if err != nil {
+		return fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token request returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid token response: %v", err)
+	}
+	if result.Token == "" {
+		result.Token = result.AccessToken
+	}
+	if result.ExpiresIn <= 0 {
+		result.ExpiresIn = 60
+	}
+
+	p.mutex.Lock()
+	p.tokens[key] = registryToken{
+		value:   result.Token,
+		expires: time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - 10*time.Second),
+	}
+	p.mutex.Unlock()
+	return nil
+}
+
+func (p *ImagePlatformResolver) count(result string) {
+	p.mutex.Lock()
+	p.admitted[result]++
+	p.mutex.Unlock()
+}
+
+// writeMetrics - 플랫폼 선택 결과와 워커 플랫폼 분포
+func (p *ImagePlatformResolver) writeMetrics(w io.Writer) {
+	p.mutex.Lock()
+	writeMetricHeader(w, "nautilus_image_platform_admissions_total", "counter", "Workload admissions by image platform selection result")
+	for _, result := range []string{"constrained", "pinned", "rejected", "unresolved"} {
+		writeMetric(w, "nautilus_image_platform_admissions_total", map[string]string{"result": result}, float64(p.admitted[result]))
+	}
+	writeMetricHeader(w, "nautilus_image_platform_cache_entries", "gauge", "Images whose platform list is cached")
+	writeMetric(w, "nautilus_image_platform_cache_entries", nil, float64(len(p.cache)))
+	p.mutex.Unlock()
+
+	counts := make(map[string]int)
+	for _, worker := range p.workerPool.ListWorkers() {
+		if worker.Arch != "" {
+			counts[worker.OS+"/"+worker.Arch]++
+		}
+	}
+	writeMetricHeader(w, "nautilus_worker_platforms", "gauge", "Workers by reported os/arch")
+	for _, platform := range p.workerPool.WorkerPlatforms() {
+		writeMetric(w, "nautilus_worker_platforms", map[string]string{"platform": platform}, float64(counts[platform]))
+	}
+}
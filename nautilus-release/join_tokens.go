@@ -0,0 +1,343 @@
+// Join Tokens - Seal 검증 후 노드별 1회용 K3s 부트스트랩 토큰 발급/회수
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+K3s 서버 토큰(node-token)은 클러스터 전체에 영구히 유효하므로 워커에게 주지 않습니다.
+대신 등록 요청마다 Seal 토큰을 다시 검증하고 `k3s token create --ttl`로
+노드 전용 부트스트랩 토큰을 만들어 줍니다.
+
+  - 노드당 유효한 토큰은 하나 (새로 발급하면 이전 토큰 회수)
+  - 토큰 발급 이후 노드가 클러스터에 등록되면(첫 사용) 즉시 회수
+  - TTL이 지나면 만료 처리 후 회수
+  - 발급 기록(토큰 ID, 노드, 소유 지갑, 시각)은 NAUTILUS_STATE_DIR/join-tokens.json에 보관 (비밀 값은 저장하지 않음)
+
+	NAUTILUS_JOIN_TOKEN_TTL   토큰 유효 시간(초, 기본 900)
+*/
+
+const (
+	joinTokenCheckInterval = 30 * time.Second
+	joinTokenHistoryLimit  = 500 // 보관하는 종료된 발급 기록 수
+)
+
+// 발급 거부 사유 (API가 상태 코드로 변환)
+var (
+	errJoinTokenUnauthorized = errors.New("seal token is not bound to a registered worker")
+	errJoinTokenForbidden    = errors.New("worker is not allowed to join")
+	errJoinTokenUnavailable  = errors.New("join tokens are unavailable")
+)
+
+// JoinTokenRecord - 발급 기록
+type JoinTokenRecord struct {
+	TokenID   string    `json:"token_id"`
+	NodeID    string    `json:"node_id"`
+	Owner     string    `json:"owner"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	State     string    `json:"state"` // outstanding, used, expired, revoked
+	ClosedAt  time.Time `json:"closed_at,omitempty"`
+}
+
+// JoinTokenGrant - 워커에게 돌려주는 토큰
+type JoinTokenGrant struct {
+	Token     string    `json:"join_token"`
+	TokenID   string    `json:"token_id"`
+	NodeID    string    `json:"node_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// JoinTokenIssuer - 부트스트랩 토큰 발급기
+type JoinTokenIssuer struct {
+	logger       *logrus.Logger
+	k3sMgr       *K3sManager
+	workerPool   *WorkerPool
+	sealTokenMgr *SealTokenManager
+	poolSync     *PoolSync // 설정되면 발급 직전에 체인에서 워커 기록을 다시 읽음
+	history      *HeartbeatHistory
+	ttl          time.Duration
+
+	mutex   sync.Mutex
+	records []*JoinTokenRecord
+	counts  map[string]uint64
+}
+
+// NewJoinTokenIssuer - 새 Join Token Issuer 생성 (이전 발급 기록 복원)
+func NewJoinTokenIssuer(logger *logrus.Logger, k3sMgr *K3sManager) *JoinTokenIssuer {
+	issuer := &JoinTokenIssuer{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		workerPool:   k3sMgr.workerPool,
+		sealTokenMgr: k3sMgr.sealTokenManager,
+		ttl:          envSeconds("NAUTILUS_JOIN_TOKEN_TTL", 900),
+		counts:       make(map[string]uint64),
+	}
+	if _, err := loadJSONState(statePath("join-tokens.json"), &issuer.records); err != nil {
+		logger.Warnf("⚠️ Failed to load join token records: %v", err)
+	}
+	return issuer
+}
+
+// Mint - Seal 토큰 재검증 후 노드 전용 토큰 발급 (nodeID가 비어 있으면 토큰 소유 노드)
+func (j *JoinTokenIssuer) Mint(sealToken, nodeID string) (*JoinTokenGrant, error) {
+	worker, ok := j.workerPool.WorkerBySealToken(sealToken)
+	if !ok {
+		j.count("rejected")
+		return nil, errJoinTokenUnauthorized
+	}
+	if nodeID == "" {
+		nodeID = worker.NodeID
+	}
+	if worker.NodeID != nodeID {
+		j.count("rejected")
+		return nil, fmt.Errorf("%w: seal token belongs to %s, not %s", errJoinTokenForbidden, worker.NodeID, nodeID)
+	}
+
+	// 체인 기록 재확인 (스테이크 회수/슬래싱/토큰 교체가 로컬 풀에 아직 반영되지 않았을 수 있음)
+	if j.poolSync != nil {
+		if err := j.poolSync.SyncWorker(nodeID); err != nil {
+			j.count("rejected")
+			return nil, fmt.Errorf("%w: could not re-validate %s on chain: %v", errJoinTokenUnavailable, nodeID, err)
+		}
+		if worker, ok = j.workerPool.WorkerBySealToken(sealToken); !ok || worker.NodeID != nodeID {
+			j.count("rejected")
+			return nil, errJoinTokenUnauthorized
+		}
+	}
+	if worker.Status == "slashed" || worker.Status == "unstaked" {
+		j.count("rejected")
+		return nil, fmt.Errorf("%w: %s is %s", errJoinTokenForbidden, nodeID, worker.Status)
+	}
+	if !j.sealTokenMgr.ValidateSealToken(sealToken, nodeID) {
+		j.count("rejected")
+		return nil, errJoinTokenUnauthorized
+	}
+	if !j.k3sMgr.IsRunning() {
+		return nil, fmt.Errorf("%w: K3s is not running", errJoinTokenUnavailable)
+	}
+
+	// 이전 토큰은 새 토큰 발급 전에 회수 (노드당 하나만 유효)
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for _, record := range j.records {
+		if record.NodeID == nodeID && record.State == "outstanding" {
+			j.closeLocked(record, "revoked")
+		}
+	}
+
+	tokenID, secret := randomToken(6), randomToken(16)
+	output, err := j.k3sMgr.RunK3s("token", "create", tokenID+"."+secret,
+		"--ttl", j.ttl.String(),
+		"--description", "k3s-daas join token for "+nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errJoinTokenUnavailable, err)
+	}
+	// k3s는 CA 해시가 붙은 전체 토큰(K10<hash>::<id>.<secret>)을 출력
+	token := tokenID + "." + secret
+	if fields := strings.Fields(string(output)); len(fields) > 0 && strings.HasSuffix(fields[len(fields)-1], token) {
+		token = fields[len(fields)-1]
+	}
+
+	now := time.Now()
+	record := &JoinTokenRecord{
+		TokenID: tokenID, NodeID: nodeID, Owner: worker.WorkerAddress,
+		IssuedAt: now, ExpiresAt: now.Add(j.ttl), State: "outstanding",
+	}
+	j.records = append(j.records, record)
+	j.counts["minted"]++
+	j.saveLocked()
+
+	j.logger.Infof("🎟️ Join token %s minted for %s (expires %s)", tokenID, nodeID, record.ExpiresAt.Format(time.RFC3339))
+	j.history.RecordEvent(nodeID, "join_token", "minted "+tokenID)
+	return &JoinTokenGrant{Token: token, TokenID: tokenID, NodeID: nodeID, ExpiresAt: record.ExpiresAt}, nil
+}
+
+// Start - 사용/만료된 토큰 회수
+func (j *JoinTokenIssuer) Start(ctx context.Context) {
+	ticker := time.NewTicker(joinTokenCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Sweep()
+		}
+	}
+}
+
+/*
+Sweep - 토큰 발급 이후 클러스터에 등록된 노드의 토큰은 used, TTL이 지난 토큰은 expired로 회수
+
+재시작하거나 다시 조인하는 노드는 이전 Node 객체가 남아 있으므로 이름만으로는 사용 여부를 알 수 없습니다.
+Node의 creationTimestamp가 토큰 발급 시각 이후일 때만 사용된 것으로 보고, 나머지는 TTL 만료로 회수합니다.
+kubectl/k3s 실행 중에는 Mint와 기록 조회가 막히지 않도록 잠금을 잡지 않습니다.
+*/
+func (j *JoinTokenIssuer) Sweep() {
+	j.mutex.Lock()
+	var outstanding []JoinTokenRecord
+	for _, record := range j.records {
+		if record.State == "outstanding" {
+			outstanding = append(outstanding, *record)
+		}
+	}
+	j.mutex.Unlock()
+	if len(outstanding) == 0 || !j.k3sMgr.IsRunning() {
+		return
+	}
+
+	registered := map[string]time.Time{}
+	if output, err := j.k3sMgr.RunKubectl(nil, "get", "nodes", "-o", "json"); err != nil {
+		j.logger.Warnf("⚠️ Failed to list nodes for join token sweep: %v", err)
+	} else {
+		var list struct {
+			Items []struct {
+				Metadata struct {
+					Name              string    `json:"name"`
+					CreationTimestamp time.Time `json:"creationTimestamp"`
+				} `json:"metadata"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(output, &list); err != nil {
+			j.logger.Warnf("⚠️ Failed to parse nodes for join token sweep: %v", err)
+		}
+		for _, item := range list.Items {
+			registered[item.Metadata.Name] = item.Metadata.CreationTimestamp
+		}
+	}
+
+	now := time.Now()
+	closing := map[string]string{} // 토큰 ID → 종료 상태
+	for _, record := range outstanding {
+		// creationTimestamp는 초 단위이므로 발급 시각도 초 단위로 비교
+		createdAt, joined := registered[record.NodeID]
+		switch {
+		case joined && !createdAt.Before(record.IssuedAt.Truncate(time.Second)):
+			closing[record.TokenID] = "used"
+		case now.After(record.ExpiresAt):
+			closing[record.TokenID] = "expired"
+		}
+	}
+	if len(closing) == 0 {
+		return
+	}
+	for tokenID, state := range closing {
+		j.deleteToken(tokenID, state)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for _, record := range j.records {
+		// 그 사이 Mint가 회수한 토큰은 이미 종료됨
+		if state, ok := closing[record.TokenID]; ok && record.State == "outstanding" {
+			j.markClosedLocked(record, state)
+		}
+	}
+	j.saveLocked()
+}
+
+// closeLocked - K3s에서 토큰 삭제 후 기록 종료
+func (j *JoinTokenIssuer) closeLocked(record *JoinTokenRecord, state string) {
+	j.deleteToken(record.TokenID, state)
+	j.markClosedLocked(record, state)
+}
+
+// deleteToken - K3s에서 토큰 삭제 (삭제 실패는 TTL로 자연 만료되므로 경고만)
+func (j *JoinTokenIssuer) deleteToken(tokenID, state string) {
+	if _, err := j.k3sMgr.RunK3s("token", "delete", tokenID); err != nil && state != "expired" {
+		j.logger.Warnf("⚠️ Failed to delete join token %s: %v", tokenID, err)
+	}
+}
+
+// markClosedLocked - 기록 종료
+func (j *JoinTokenIssuer) markClosedLocked(record *JoinTokenRecord, state string) {
+	record.State = state
+	record.ClosedAt = time.Now()
+	j.counts[state]++
+	j.logger.Infof("🎟️ Join token %s for %s %s", record.TokenID, record.NodeID, state)
+	j.history.RecordEvent(record.NodeID, "join_token", state+" "+record.TokenID)
+}
+
+// saveLocked - 종료된 기록은 최근 joinTokenHistoryLimit개만 유지하고 저장
+func (j *JoinTokenIssuer) saveLocked() {
+	closed := 0
+	for i := len(j.records) - 1; i >= 0; i-- {
+		if j.records[i].State == "outstanding" {
+			continue
+		}
+		closed++
+		if closed > joinTokenHistoryLimit {
+			j.records = append(j.records[:i], j.records[i+1:]...)
+		}
+	}
+	if err := saveJSONState(statePath("join-tokens.json"), j.records); err != nil {
+		j.logger.Warnf("⚠️ Failed to save join token records: %v", err)
+	}
+}
+
+func (j *JoinTokenIssuer) count(result string) {
+	j.mutex.Lock()
+	j.counts[result]++
+	j.mutex.Unlock()
+}
+
+// Records - 발급 기록 (최근 순)
+func (j *JoinTokenIssuer) Records(nodeID string) []JoinTokenRecord {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	records := []JoinTokenRecord{}
+	for i := len(j.records) - 1; i >= 0; i-- {
+		if nodeID == "" || j.records[i].NodeID == nodeID {
+			records = append(records, *j.records[i])
+		}
+	}
+	return records
+}
+
+// randomToken - K3s 부트스트랩 토큰 문자 집합 [a-z0-9]
+func randomToken(length int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	token := make([]byte, length)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			panic(fmt.Sprintf("crypto/rand failed: %v", err))
+		}
+		token[i] = alphabet[n.Int64()]
+	}
+	return string(token)
+}
+
+// writeMetrics - 조인 토큰 메트릭 출력
+func (j *JoinTokenIssuer) writeMetrics(w io.Writer) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	outstanding := 0
+	for _, record := range j.records {
+		if record.State == "outstanding" {
+			outstanding++
+		}
+	}
+	writeMetricHeader(w, "nautilus_join_tokens_outstanding", "gauge", "Join tokens minted and not yet used, expired or revoked")
+	writeMetric(w, "nautilus_join_tokens_outstanding", nil, float64(outstanding))
+	writeMetricHeader(w, "nautilus_join_tokens_total", "counter", "Join token operations by result")
+	for _, result := range []string{"minted", "used", "expired", "revoked", "rejected"} {
+		writeMetric(w, "nautilus_join_tokens_total", map[string]string{"result": result}, float64(j.counts[result]))
+	}
+}
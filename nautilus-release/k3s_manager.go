@@ -2,11 +2,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -21,6 +22,8 @@ type K3sManager struct {
 	running          bool
 	workerPool       *WorkerPool
 	sealTokenManager *SealTokenManager
+	kubeletCA        *KubeletCA // 설정되면 K3s 최초 시작 전에 클라이언트/서버 CA로 배치
+	extender         *SchedulerExtender // 설정되면 kube-scheduler 익스텐더로 등록
 }
 
 // NewK3sManager - 새 K3s Manager 생성
@@ -51,6 +54,14 @@ func (k *K3sManager) Start(ctx context.Context) {
 		return
 	}
 
+	// TEE CA로 발급한 kubelet 인증서를 K3s가 신뢰하도록 CA 배치
+	if k.kubeletCA != nil {
+		if err := k.kubeletCA.InstallForK3s(k.dataDir); err != nil {
+			k.logger.Errorf("❌ Failed to install kubelet CA: %v", err)
+			return
+		}
+	}
+
 	// K3s 서버 시작
 	if err := k.startK3sServer(ctx); err != nil {
 		k.logger.Errorf("❌ Failed to start K3s server: %v", err)
@@ -123,6 +134,17 @@ func (k *K3sManager) startK3sServer(ctx context.Context) error {
 		"--write-kubeconfig-mode", "644",
 		"--node-name", "nautilus-master",
 		"--cluster-init", // 단일 노드 클러스터로 시작
+		// 저장 형식을 Protobuf로 고정 (JSON 대비 TEE 메모리/CPU 절약, CRD는 API 서버가 JSON으로 저장)
+		"--kube-apiserver-arg", "storage-media-type=application/vnd.kubernetes.protobuf",
+	}
+
+	// 테넌트 웹훅을 필터/점수 단계에 끼우기 위해 마스터를 스케줄러 익스텐더로 등록
+	if k.extender != nil {
+		schedulerConfig, err := k.extender.InstallForK3s(k.dataDir)
+		if err != nil {
+			return fmt.Errorf("failed to configure scheduler extender: %v", err)
+		}
+		args = append(args, "--kube-scheduler-arg", "config="+schedulerConfig)
 	}
 
 	k.process = exec.CommandContext(ctx, "/usr/local/bin/k3s", args...)
@@ -217,20 +239,34 @@ func (k *K3sManager) GetKubeconfig() string {
 	return k.configFile
 }
 
-// GetJoinToken - 워커 노드 join token 생성
-func (k *K3sManager) GetJoinToken() (string, error) {
-	if !k.running {
-		return "", fmt.Errorf("K3s is not running")
+// RunK3s - 마스터 데이터 디렉토리 기준으로 k3s 하위 명령 실행 (예: token create)
+func (k *K3sManager) RunK3s(args ...string) ([]byte, error) {
+	cmd := exec.Command("/usr/local/bin/k3s", append(args, "--data-dir", k.dataDir)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("k3s %s failed: %v, stderr: %s", strings.Join(args[:min(2, len(args))], " "), err, stderr.String())
 	}
+	return stdout.Bytes(), nil
+}
 
-	// Node token 파일에서 읽기
-	tokenFile := filepath.Join(k.dataDir, "server", "node-token")
-	tokenBytes, err := os.ReadFile(tokenFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read node token: %v", err)
+// RunKubectl - 마스터 kubeconfig로 kubectl 실행 (stdin 옵션)
+func (k *K3sManager) RunKubectl(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("kubectl", args...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+k.configFile)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
 	}
 
-	token := string(tokenBytes)
-	k.logger.Infof("🎟️ Generated join token: %s...", token[:20])
-	return token, nil
-}
\ No newline at end of file
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("kubectl %s failed: %v, stderr: %s", args[0], err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
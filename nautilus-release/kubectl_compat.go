@@ -0,0 +1,532 @@
+// Kubectl Compat - User-Agent의 kubectl 버전에 맞춘 K8s API 호환 처리와 Warning 헤더
+//
+// K3s보다 마이너 버전이 2 이상 차이 나는 kubectl에는 지원 범위 밖이라는 Warning을 붙이고,
+// 클러스터에서 제거된 쿼리 파라미터(export 등)는 빼고 전달합니다. K3s가 더 이상 제공하지 않는
+// 폐지 API 버전(batch/v1beta1 CronJob 등)은 스키마가 같은 대체 버전으로 요청을 바꿔 보내고,
+// 응답의 apiVersion을 요청한 버전으로 되돌리며 옛 버전에 없는 필드를 뺍니다.
+// 옛 매니페스트를 apply할 수 있도록 디스커버리(/apis)에도 폐지 버전을 함께 노출합니다.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+// kubectlUserAgent - kubectl/v1.28.3 (linux/amd64) kubernetes/a8a1abc
+var kubectlUserAgent = regexp.MustCompile(`^kubectl(?:\.exe)?/(v[0-9][^ ]*)`)
+
+// removedQueryParam - 클러스터에서 제거된 쿼리 파라미터 (RemovedIn 이상이면 빼고 전달)
+type removedQueryParam struct {
+	Name      string
+	RemovedIn int // 제거된 Kubernetes 마이너 버전
+}
+
+var removedQueryParams = []removedQueryParam{
+	{Name: "includeUninitialized", RemovedIn: 14},
+	{Name: "export", RemovedIn: 18},
+}
+
+// deprecatedAPI - 대체 버전과 스키마가 같은 폐지 API (RemovedIn 이상인 클러스터에서만 매핑)
+type deprecatedAPI struct {
+	Group       string
+	Version     string
+	Resource    string
+	Kind        string
+	Replacement string
+	RemovedIn   int
+	Omit        [][]string // 옛 버전에 없는 필드 경로 (응답에서 제거)
+}
+
+var deprecatedAPIs = []deprecatedAPI{
+	{Group: "batch", Version: "v1beta1", Resource: "cronjobs", Kind: "CronJob", Replacement: "v1", RemovedIn: 25,
+		Omit: [][]string{{"spec", "timeZone"}}},
+	{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Replacement: "v1", RemovedIn: 25,
+		Omit: [][]string{{"spec", "unhealthyPodEvictionPolicy"}}},
+	{Group: "discovery.k8s.io", Version: "v1beta1", Resource: "endpointslices", Kind: "EndpointSlice", Replacement: "v1", RemovedIn: 25},
+	{Group: "autoscaling", Version: "v2beta1", Resource: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Replacement: "v2", RemovedIn: 25},
+	{Group: "autoscaling", Version: "v2beta2", Resource: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Replacement: "v2", RemovedIn: 26},
+}
+
+func (d deprecatedAPI) groupVersion() string { return d.Group + "/" + d.Version }
+
+func (d deprecatedAPI) replacementGroupVersion() string { return d.Group + "/" + d.Replacement }
+
+// KubectlCompat - kubectl 버전 호환 처리
+type KubectlCompat struct {
+	logger *logrus.Logger
+	k3sMgr *K3sManager
+
+	mutex   sync.Mutex
+	server  version.Semver // K3s Kubernetes 버전 (확인 전이면 0)
+	clients map[string]time.Time
+	counts  map[string]int64
+}
+
+// NewKubectlCompat - 새 Kubectl Compat 생성 (NAUTILUS_KUBE_SERVER_VERSION이 있으면 K3s 조회 대신 사용)
+func NewKubectlCompat(logger *logrus.Logger, k3sMgr *K3sManager) *KubectlCompat {
+	c := &KubectlCompat{
+		logger:  logger,
+		k3sMgr:  k3sMgr,
+		clients: make(map[string]time.Time),
+		counts:  make(map[string]int64),
+	}
+	if configured, ok := version.Parse(os.Getenv("NAUTILUS_KUBE_SERVER_VERSION")); ok {
+		c.server = configured
+	}
+	return c
+}
+
+// Start - K3s 버전 확인 (확인될 때까지 30초, 이후 업그레이드 반영을 위해 1시간마다)
+func (c *KubectlCompat) Start(ctx context.Context) {
+	if os.Getenv("NAUTILUS_KUBE_SERVER_VERSION") != "" {
+		return
+	}
+	for {
+		interval := 30 * time.Second
+		if c.refreshServerVersion() {
+			interval = time.Hour
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// refreshServerVersion - K3s /version 조회
+func (c *KubectlCompat) refreshServerVersion() bool {
+	output, err := c.k3sMgr.RunKubectl(nil, "get", "--raw", "/version")
+	if err != nil {
+		return false
+	}
+	var info struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return false
+	}
+	server, ok := version.Parse(info.GitVersion)
+	if !ok {
+		return false
+	}
+
+	c.mutex.Lock()
+	changed := c.server != server
+	c.server = server
+	c.mutex.Unlock()
+	if changed {
+		c.logger.Infof("🧭 K3s serves Kubernetes %s; kubectl compatibility adjusted", info.GitVersion)
+	}
+	return true
+}
+
+func (c *KubectlCompat) serverVersion() version.Semver {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.server
+}
+
+func (c *KubectlCompat) count(action string) {
+	c.mutex.Lock()
+	c.counts[action]++
+	c.mutex.Unlock()
+}
+
+// Middleware - K8s API 프록시 앞에서 요청/응답 조정 (KubectlCompat 게이트가 꺼져 있으면 그대로 전달)
+func (c *KubectlCompat) Middleware(next http.Handler) http.Handler {
+	if !features.Enabled(featureKubectlCompat) {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server := c.serverVersion()
+		if server.Major == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if client, ok := c.clientVersion(r); ok {
+			c.warnSkew(w, client, server)
+		}
+		c.dropRemovedParams(w, r, server)
+
+		if api, ok := c.discoveryTarget(r.URL.Path, server); ok || (isDiscoveryRoot(r.URL.Path) && mappingActive(server)) {
+			c.serveDiscovery(w, r, next, api, server)
+			return
+		}
+		if api, ok := c.mappedAPI(r.URL.Path, server); ok {
+			c.serveMapped(w, r, next, api)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientVersion - User-Agent의 kubectl 버전 (다른 클라이언트는 false)
+func (c *KubectlCompat) clientVersion(r *http.Request) (string, bool) {
+	match := kubectlUserAgent.FindStringSubmatch(r.UserAgent())
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// warnSkew - kubectl은 클러스터와 마이너 버전 ±1까지만 지원
+func (c *KubectlCompat) warnSkew(w http.ResponseWriter, client string, server version.Semver) {
+	parsed, ok := version.Parse(client)
+	if !ok || parsed.Major != server.Major {
+		return
+	}
+	skew := parsed.Minor - server.Minor
+	if skew >= -1 && skew <= 1 {
+		return
+	}
+	addWarning(w, fmt.Sprintf("kubectl %s is outside the supported skew of the v%d.%d cluster; use kubectl v%d.%d to v%d.%d",
+		client, server.Major, server.Minor, server.Major, server.Minor-1, server.Major, server.Minor+1))
+	c.count("skew_warning")
+
+	c.mutex.Lock()
+	_, seen := c.clients[client]
+	c.clients[client] = time.Now()
+	c.mutex.Unlock()
+	if !seen {
+		c.logger.Warnf("⚠️ kubectl %s connected to a v%d.%d cluster (supported skew is one minor version)", client, server.Major, server.Minor)
+	}
+}
+
+// dropRemovedParams - 클러스터에서 제거된 쿼리 파라미터를 빼고 Warning
+func (c *KubectlCompat) dropRemovedParams(w http.ResponseWriter, r *http.Request, server version.Semver) {
+	query := r.URL.Query()
+	changed := false
+	for _, param := range removedQueryParams {
+		if server.Minor < param.RemovedIn || !query.Has(param.Name) {
+			continue
+		}
+		query.Del(param.Name)
+		changed = true
+		addWarning(w, fmt.Sprintf("the %s query parameter was removed in Kubernetes v1.%d and was ignored", param.Name, param.RemovedIn))
+		c.count("param_removed")
+	}
+	if changed {
+		r.URL.RawQuery = query.Encode()
+	}
+}
+
+// mappedAPI - 요청 경로가 클러스터에서 제거된 폐지 API의 리소스인지 확인
+func (c *KubectlCompat) mappedAPI(path string, server version.Semver) (deprecatedAPI, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 4 || segments[0] != "apis" {
+		return deprecatedAPI{}, false
+	}
+	resource := segments[3]
+	if resource == "namespaces" && len(segments) >= 6 {
+		resource = segments[5]
+	}
+	for _, api := range deprecatedAPIs {
+		if api.Group == segments[1] && api.Version == segments[2] && api.Resource == resource && server.Minor >= api.RemovedIn {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// discoveryTarget - /apis/<group>, /apis/<group>/<폐지 버전> 디스커버리 요청인지 확인
+func (c *KubectlCompat) discoveryTarget(path string, server version.Semver) (deprecatedAPI, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 || len(segments) > 3 || segments[0] != "apis" {
+		return deprecatedAPI{}, false
+	}
+	for _, api := range deprecatedAPIs {
+		if api.Group == segments[1] && server.Minor >= api.RemovedIn && (len(segments) == 2 || api.Version == segments[2]) {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+func isDiscoveryRoot(path string) bool {
+	return strings.TrimSuffix(path, "/") == "/apis"
+}
+
+// mappingActive - 이 클러스터에서 매핑하는 폐지 API가 있는지
+func mappingActive(server version.Semver) bool {
+	for _, api := range deprecatedAPIs {
+		if server.Minor >= api.RemovedIn {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+serveMapped - 폐지 버전 요청을 대체 버전으로 보내고 응답을 요청한 버전으로 되돌림
+
+	/apis/batch/v1beta1/namespaces/default/cronjobs → /apis/batch/v1/namespaces/default/cronjobs
+	본문 apiVersion: batch/v1beta1 → batch/v1, 응답(목록 항목, watch 이벤트 포함): batch/v1 → batch/v1beta1
+*/
+func (c *KubectlCompat) serveMapped(w http.ResponseWriter, r *http.Request, next http.Handler, api deprecatedAPI) {
+	prefix := "/apis/" + api.Group + "/"
+	r.URL.Path = prefix + api.Replacement + "/" + strings.TrimPrefix(r.URL.Path, prefix+api.Version+"/")
+	r.URL.RawPath = ""
+	if r.Body != nil && r.ContentLength != 0 {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		pattern := regexp.MustCompile(`"apiVersion"\s*:\s*"` + regexp.QuoteMeta(api.groupVersion()) + `"`)
+		body = pattern.ReplaceAll(body, []byte(`"apiVersion":"`+api.replacementGroupVersion()+`"`))
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	// 응답 본문을 고쳐야 하므로 압축과 protobuf 없이 JSON으로 받음
+	r.Header.Del("Accept-Encoding")
+	if strings.Contains(r.Header.Get("Accept"), "protobuf") {
+		r.Header.Set("Accept", "application/json")
+	}
+
+	addWarning(w, fmt.Sprintf("%s %s is unavailable in v1.%d+ and was served as %s; update manifests to %s",
+		api.groupVersion(), api.Kind, api.RemovedIn, api.replacementGroupVersion(), api.replacementGroupVersion()))
+	c.count("api_mapped")
+
+	if isJSONWatch(r) {
+		stream := &compatWatchWriter{ResponseWriter: w, api: api}
+		defer stream.finish()
+		next.ServeHTTP(stream, r)
+		return
+	}
+	recorder := httptest.NewRecorder()
+	next.ServeHTTP(recorder, r)
+	writeRecorded(w, recorder, func(body []byte) []byte { return downgradeObject(body, api) })
+}
+
+// serveDiscovery - 디스커버리 응답에 폐지 버전 추가 (aggregated 디스커버리는 고치지 않도록 기존 형식으로 받음)
+func (c *KubectlCompat) serveDiscovery(w http.ResponseWriter, r *http.Request, next http.Handler, api deprecatedAPI, server version.Semver) {
+	if r.Method != http.MethodGet {
+		next.ServeHTTP(w, r)
+		return
+	}
+	r.Header.Set("Accept", "application/json")
+	r.Header.Del("Accept-Encoding")
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) == 3 {
+		// 폐지 버전의 리소스 목록은 대체 버전 목록에서 매핑된 리소스만 골라 제공
+		r.URL.Path = "/apis/" + api.Group + "/" + api.Replacement
+		r.URL.RawPath = ""
+	}
+	recorder := httptest.NewRecorder()
+	next.ServeHTTP(recorder, r)
+	writeRecorded(w, recorder, func(body []byte) []byte {
+		switch len(segments) {
+		case 3:
+			return deprecatedResourceList(body, api, server)
+		default:
+			return withDeprecatedVersions(body, server)
+		}
+	})
+}
+
+// writeRecorded - 성공한 JSON 응답만 고쳐서 기록된 헤더와 함께 전달
+func writeRecorded(w http.ResponseWriter, recorder *httptest.ResponseRecorder, rewrite func([]byte) []byte) {
+	body := recorder.Body.Bytes()
+	if recorder.Code < 300 && strings.Contains(recorder.Header().Get("Content-Type"), "json") {
+		body = rewrite(body)
+	}
+	for key, values := range recorder.Header() {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(recorder.Code)
+	w.Write(body)
+}
+
+// downgradeObject - 객체나 목록의 apiVersion을 폐지 버전으로 되돌리고 옛 버전에 없는 필드 제거
+func downgradeObject(body []byte, api deprecatedAPI) []byte {
+	var object map[string]interface{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return body
+	}
+	downgrade := func(item map[string]interface{}) {
+		if item["apiVersion"] != api.replacementGroupVersion() {
+			return
+		}
+		item["apiVersion"] = api.groupVersion()
+		for _, path := range api.Omit {
+			removeField(item, path)
+		}
+	}
+	downgrade(object)
+	if items, ok := object["items"].([]interface{}); ok {
+		for _, item := range items {
+			if typed, ok := item.(map[string]interface{}); ok {
+				// 목록 항목은 apiVersion이 비어 있을 수 있음
+				if _, set := typed["apiVersion"]; !set {
+					typed["apiVersion"] = api.replacementGroupVersion()
+				}
+				downgrade(typed)
+			}
+		}
+	}
+	if event, ok := object["object"].(map[string]interface{}); ok {
+		downgrade(event)
+	}
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
+
+func removeField(object map[string]interface{}, path []string) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		object = child
+	}
+	delete(object, path[len(path)-1])
+}
+
+// withDeprecatedVersions - APIGroupList(/apis)나 APIGroup(/apis/<group>)의 versions에 매핑된 폐지 버전 추가
+func withDeprecatedVersions(body []byte, server version.Semver) []byte {
+	var document map[string]interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return body
+	}
+	groups := []interface{}{document}
+	if list, ok := document["groups"].([]interface{}); ok {
+		groups = list
+	}
+	for _, entry := range groups {
+		group, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		versions, _ := group["versions"].([]interface{})
+		for _, api := range deprecatedAPIs {
+			if api.Group != group["name"] || server.Minor < api.RemovedIn || hasGroupVersion(versions, api.groupVersion()) {
+				continue
+			}
+			// 선호 버전(preferredVersion)은 그대로 두고 목록 끝에 추가
+			versions = append(versions, map[string]interface{}{"groupVersion": api.groupVersion(), "version": api.Version})
+		}
+		group["versions"] = versions
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
+
+func hasGroupVersion(versions []interface{}, groupVersion string) bool {
+	for _, entry := range versions {
+		if typed, ok := entry.(map[string]interface{}); ok && typed["groupVersion"] == groupVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecatedResourceList - 대체 버전 APIResourceList에서 폐지 버전으로 매핑되는 리소스(하위 리소스 포함)만 남김
+func deprecatedResourceList(body []byte, requested deprecatedAPI, server version.Semver) []byte {
+	var list map[string]interface{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return body
+	}
+	resources, _ := list["resources"].([]interface{})
+	kept := make([]interface{}, 0, len(resources))
+	for _, entry := range resources {
+		resource, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := resource["name"].(string)
+		for _, api := range deprecatedAPIs {
+			if api.Group == requested.Group && api.Version == requested.Version && server.Minor >= api.RemovedIn &&
+				strings.SplitN(name, "/", 2)[0] == api.Resource {
+				kept = append(kept, resource)
+				break
+			}
+		}
+	}
+	list["groupVersion"] = requested.groupVersion()
+	list["resources"] = kept
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
+
+// compatWatchWriter - watch 이벤트를 줄 단위로 받아 객체 apiVersion을 폐지 버전으로 되돌림
+type compatWatchWriter struct {
+	http.ResponseWriter
+	api     deprecatedAPI
+	partial []byte
+}
+
+func (s *compatWatchWriter) Write(p []byte) (int, error) {
+	s.partial = append(s.partial, p...)
+	for {
+		idx := bytes.IndexByte(s.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		line := append(downgradeObject(s.partial[:idx], s.api), '\n')
+		s.partial = s.partial[idx+1:]
+		if _, err := s.ResponseWriter.Write(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (s *compatWatchWriter) finish() {
+	if len(s.partial) > 0 {
+		s.ResponseWriter.Write(s.partial)
+		s.partial = nil
+	}
+}
+
+func (s *compatWatchWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// addWarning - 표준 Warning 헤더 (299 - "<text>")
+func addWarning(w http.ResponseWriter, text string) {
+	w.Header().Add("Warning", "299 - "+strconv.Quote(text))
+}
+
+// writeMetrics - 호환 처리 횟수와 지원 범위 밖 kubectl 버전 수
+func (c *KubectlCompat) writeMetrics(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_kubectl_compat_total", "counter", "Requests adjusted for the kubectl client version by action")
+	for _, action := range []string{"skew_warning", "param_removed", "api_mapped"} {
+		writeMetric(w, "nautilus_kubectl_compat_total", map[string]string{"action": action}, float64(c.counts[action]))
+	}
+	writeMetricHeader(w, "nautilus_kubectl_skewed_clients", "gauge", "Distinct kubectl versions seen outside the supported skew")
+	writeMetric(w, "nautilus_kubectl_skewed_clients", nil, float64(len(c.clients)))
+}
@@ -0,0 +1,228 @@
+// Kubelet CA - TEE 안에서 생성/보관하는 kubelet 인증서 발급 CA
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	kubeletCAValidity    = 10 * 365 * 24 * time.Hour
+	kubeletCertBackdate  = 5 * time.Minute // 워커 시계가 약간 느려도 바로 쓸 수 있도록
+	minKubeletCertExpiry = 10 * time.Minute
+)
+
+// kubeletCAState - 상태 파일 형식 (PEM)
+type kubeletCAState struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// KubeletCA - kubelet 클라이언트/서빙 인증서 서명용 CA
+type KubeletCA struct {
+	logger  *logrus.Logger
+	cert    *x509.Certificate
+	certPEM []byte
+	keyPEM  []byte
+	key     *ecdsa.PrivateKey
+	maxTTL  time.Duration
+}
+
+/*
+NewKubeletCA - 저장된 CA 로드 또는 새로 생성 (NAUTILUS_STATE_DIR/kubelet-ca.json)
+
+	NAUTILUS_KUBELET_CERT_DAYS   발급 인증서 최대 유효 기간 (기본 365일, CSR의 expirationSeconds가 더 짧으면 그 값)
+*/
+func NewKubeletCA(logger *logrus.Logger) (*KubeletCA, error) {
+	days, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_KUBELET_CERT_DAYS", "365"))
+	if err != nil || days <= 0 {
+		days = 365
+	}
+	ca := &KubeletCA{logger: logger, maxTTL: time.Duration(days) * 24 * time.Hour}
+
+	var state kubeletCAState
+	path := statePath("kubelet-ca.json")
+	found, err := loadJSONState(path, &state)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		if state, err = generateKubeletCA(); err != nil {
+			return nil, err
+		}
+		if err := saveJSONState(path, state); err != nil {
+			return nil, err
+		}
+		logger.Info("🔐 Generated new kubelet CA inside the TEE")
+	}
+
+	if err := ca.load(state); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// generateKubeletCA - 자체 서명 ECDSA P-256 CA 생성
+func generateKubeletCA() (kubeletCAState, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return kubeletCAState{}, fmt.Errorf("failed to generate kubelet CA key: %v", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return kubeletCAState{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "k3s-daas-tee-ca", Organization: []string{"K3s-DaaS"}},
+		NotBefore:             now.Add(-kubeletCertBackdate),
+		NotAfter:              now.Add(kubeletCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return kubeletCAState{}, fmt.Errorf("failed to self-sign kubelet CA: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return kubeletCAState{}, fmt.Errorf("failed to encode kubelet CA key: %v", err)
+	}
+
+	return kubeletCAState{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}, nil
+}
+
+// load - PEM 상태 파싱
+func (ca *KubeletCA) load(state kubeletCAState) error {
+	certBlock, _ := pem.Decode([]byte(state.Certificate))
+	keyBlock, _ := pem.Decode([]byte(state.PrivateKey))
+	if certBlock == nil || keyBlock == nil {
+		return fmt.Errorf("kubelet CA state is not valid PEM")
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubelet CA certificate: %v", err)
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubelet CA key: %v", err)
+	}
+
+	ca.cert = cert
+	ca.key = key
+	ca.certPEM = []byte(state.Certificate)
+	ca.keyPEM = []byte(state.PrivateKey)
+	return nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial: %v", err)
+	}
+	return serial, nil
+}
+
+// CertificatePEM - CA 인증서 (워커가 마스터 인증서를 검증할 때 사용)
+func (ca *KubeletCA) CertificatePEM() []byte {
+	return ca.certPEM
+}
+
+/*
+InstallForK3s - K3s가 처음 시작되기 전에 CA를 클라이언트/서버 CA로 배치
+
+K3s는 server/tls에 CA가 이미 있으면 그대로 사용하므로, API 서버가 TEE CA로 발급한
+kubelet 클라이언트 인증서를 신뢰하고 kubelet 서빙 인증서를 검증할 수 있게 됩니다.
+이미 다른 CA로 초기화된 클러스터는 건드리지 않고 경고만 남깁니다.
+*/
+func (ca *KubeletCA) InstallForK3s(dataDir string) error {
+	tlsDir := filepath.Join(dataDir, "server", "tls")
+	if err := os.MkdirAll(tlsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create K3s TLS directory: %v", err)
+	}
+
+	for _, name := range []string{"client-ca", "server-ca"} {
+		certPath := filepath.Join(tlsDir, name+".crt")
+		existing, err := os.ReadFile(certPath)
+		if err == nil {
+			if !bytes.Equal(bytes.TrimSpace(existing), bytes.TrimSpace(ca.certPEM)) {
+				ca.logger.Warnf("⚠️ K3s %s was created by another CA, kubelet certificates from the TEE CA will not be trusted until it is rotated", name)
+			}
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %v", certPath, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(tlsDir, name+".key"), ca.keyPEM, 0600); err != nil {
+			return fmt.Errorf("failed to write %s key: %v", name, err)
+		}
+		if err := os.WriteFile(certPath, ca.certPEM, 0644); err != nil {
+			return fmt.Errorf("failed to write %s certificate: %v", name, err)
+		}
+		ca.logger.Infof("🔐 Installed TEE CA as K3s %s", name)
+	}
+	return nil
+}
+
+// Sign - 승인된 CSR로 인증서 발급 (유효 기간은 요청값과 최대값 중 짧은 쪽)
+func (ca *KubeletCA) Sign(csr *x509.CertificateRequest, usages []x509.ExtKeyUsage, keyUsage x509.KeyUsage, expirationSeconds *int32) ([]byte, error) {
+	ttl := ca.maxTTL
+	if expirationSeconds != nil {
+		requested := time.Duration(*expirationSeconds) * time.Second
+		if requested < minKubeletCertExpiry {
+			requested = minKubeletCertExpiry
+		}
+		if requested < ttl {
+			ttl = requested
+		}
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               csr.Subject,
+		DNSNames:              csr.DNSNames,
+		IPAddresses:           csr.IPAddresses,
+		NotBefore:             now.Add(-kubeletCertBackdate),
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           usages,
+		BasicConstraintsValid: true,
+		AuthorityKeyId:        ca.cert.SubjectKeyId,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
@@ -0,0 +1,339 @@
+// LimitRange Admission - 네임스페이스 LimitRange의 기본 requests/limits를 Pod 템플릿에 채우고 min/max/비율 위반 거부
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+K3s의 LimitRanger는 Pod가 만들어지는 순간에만 적용되므로, Deployment 같은 워크로드 템플릿은 requests 없이
+온체인에 기록되고 비용 추정/스케줄 시뮬레이션도 0으로 계산되며, 위반은 컨트롤러가 Pod를 만들 때 이벤트로만 드러납니다.
+컨트랙트 경로 요청의 Pod 템플릿(Pod 포함)에 같은 규칙을 미리 적용합니다.
+
+  - Container 항목: 지정된 limits만 있는 자원은 requests = limits (API 서버 기본값),
+    없는 limits는 default, 없는 requests는 defaultRequest
+    (K3s가 저장할 때 default는 max로, defaultRequest는 default 또는 min으로 채워 둠)
+  - min은 requests 하한, max는 limits 상한 (각각 값이 없으면 위반), maxLimitRequestRatio는 limits/requests 상한
+  - Pod 항목: 컨테이너 requests 합계에 min, limits 합계에 max 적용
+
+LimitRange는 네임스페이스별로 limitRangeCacheTTL 동안 캐시하고, LimitRange 쓰기 요청이 오면 해당 네임스페이스 캐시를 비웁니다.
+조회에 실패하면 그대로 통과시킵니다 (K3s LimitRanger가 Pod 생성 시 다시 적용).
+*/
+
+const limitRangeCacheTTL = 30 * time.Second
+
+// limitRangeItem - LimitRange spec.limits 항목 (수량은 문자열 그대로)
+type limitRangeItem struct {
+	Type                 string            `json:"type"`
+	Max                  map[string]string `json:"max,omitempty"`
+	Min                  map[string]string `json:"min,omitempty"`
+	Default              map[string]string `json:"default,omitempty"`
+	DefaultRequest       map[string]string `json:"defaultRequest,omitempty"`
+	MaxLimitRequestRatio map[string]string `json:"maxLimitRequestRatio,omitempty"`
+}
+
+type limitRangeEntry struct {
+	items     []limitRangeItem
+	fetchedAt time.Time
+}
+
+// LimitRangeAdmission - 네임스페이스 LimitRange 적용
+type LimitRangeAdmission struct {
+	logger *logrus.Logger
+	k3sMgr *K3sManager
+
+	mutex     sync.Mutex
+	cache     map[string]limitRangeEntry
+	defaulted uint64
+	rejected  uint64
+}
+
+// NewLimitRangeAdmission - 새 LimitRange Admission 생성
+func NewLimitRangeAdmission(logger *logrus.Logger, k3sMgr *K3sManager) *LimitRangeAdmission {
+	return &LimitRangeAdmission{
+		logger: logger,
+		k3sMgr: k3sMgr,
+		cache:  make(map[string]limitRangeEntry),
+	}
+}
+
+// limitsFor - 네임스페이스의 LimitRange 항목 (캐시)
+func (l *LimitRangeAdmission) limitsFor(namespace string) ([]limitRangeItem, error) {
+	l.mutex.Lock()
+	entry, ok := l.cache[namespace]
+	l.mutex.Unlock()
+	if ok && time.Since(entry.fetchedAt) < limitRangeCacheTTL {
+		return entry.items, nil
+	}
+
+	output, err := l.k3sMgr.RunKubectl(nil, "get", "limitranges", "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Spec struct {
+				Limits []limitRangeItem `json:"limits"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("invalid limitrange list: %v", err)
+	}
+	var items []limitRangeItem
+	for _, limitRange := range list.Items {
+		items = append(items, limitRange.Spec.Limits...)
+	}
+
+	l.mutex.Lock()
+	l.cache[namespace] = limitRangeEntry{items: items, fetchedAt: time.Now()}
+	l.mutex.Unlock()
+	return items, nil
+}
+
+// forget - 네임스페이스 캐시 비움 (LimitRange가 바뀌었거나 새로 만들어진 경우)
+func (l *LimitRangeAdmission) forget(namespace string) {
+	l.mutex.Lock()
+	delete(l.cache, namespace)
+	l.mutex.Unlock()
+}
+
+// Admit - 생성/수정 요청의 Pod 템플릿에 기본값 적용과 범위 검사
+func (l *LimitRangeAdmission) Admit(request *K8sAPIRequest, payload *admissionPayload) error {
+	method := strings.ToUpper(request.Method)
+	if request.Resource == "limitranges" && method != "GET" {
+		l.forget(request.Namespace)
+		return nil
+	}
+	if (method != "POST" && method != "PUT") || request.Payload == "" || request.Namespace == "" {
+		return nil
+	}
+
+	podSpec, _ := podTemplateOf(payload.Object())
+	if podSpec == nil {
+		return nil
+	}
+	items, err := l.limitsFor(request.Namespace)
+	if err != nil {
+		l.logger.Warnf("⚠️ LimitRanges in %s unavailable, deferring to K3s: %v", request.Namespace, err)
+		return nil
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	changed, err := applyLimitRanges(podSpec, items)
+	if err != nil {
+		l.mutex.Lock()
+		l.rejected++
+		l.mutex.Unlock()
+		return fmt.Errorf("Forbidden: %v", err)
+	}
+	if !changed {
+		return nil
+	}
+	payload.MarkChanged()
+
+	l.mutex.Lock()
+	l.defaulted++
+	l.mutex.Unlock()
+	l.logger.Infof("📏 Applied LimitRange defaults to %s %s/%s", request.Resource, request.Namespace, request.Name)
+	return nil
+}
+
+// applyLimitRanges - Container 항목 기본값 적용 후 Container/Pod 항목 검사 (컨테이너 순서대로 첫 위반 반환)
+func applyLimitRanges(podSpec map[string]interface{}, items []limitRangeItem) (bool, error) {
+	changed := false
+	containers := podContainers(podSpec)
+
+	for _, item := range items {
+		if item.Type != "Container" {
+			continue
+		}
+		for _, container := range containers {
+			resources, _ := container["resources"].(map[string]interface{})
+			if resources == nil {
+				resources = map[string]interface{}{}
+			}
+			limits := quantityMap(resources["limits"])
+			requests := quantityMap(resources["requests"])
+			updated := false
+			// API 서버 기본값(어드미션 전)과 같이 limits만 지정된 자원은 requests = limits
+			for name, value := range limits {
+				if _, ok := requests[name]; !ok {
+					requests[name] = value
+					updated = true
+				}
+			}
+			for _, name := range sortedKeys(item.Default) {
+				if _, ok := limits[name]; !ok {
+					limits[name] = item.Default[name]
+					updated = true
+				}
+			}
+			for _, name := range sortedKeys(item.DefaultRequest) {
+				if _, ok := requests[name]; !ok {
+					requests[name] = item.DefaultRequest[name]
+					updated = true
+				}
+			}
+			if updated {
+				if len(limits) > 0 {
+					resources["limits"] = limits
+				}
+				if len(requests) > 0 {
+					resources["requests"] = requests
+				}
+				container["resources"] = resources
+				changed = true
+			}
+		}
+	}
+
+	for _, item := range items {
+		switch item.Type {
+		case "Container":
+			for _, container := range containers {
+				name, _ := container["name"].(string)
+				resources, _ := container["resources"].(map[string]interface{})
+				if err := checkLimitRange(item, "Container", name, quantityMap(resources["requests"]), quantityMap(resources["limits"])); err != nil {
+					return changed, err
+				}
+			}
+		case "Pod":
+			requests, limits := map[string]interface{}{}, map[string]interface{}{}
+			for _, container := range podContainersOnly(podSpec) {
+				resources, _ := container["resources"].(map[string]interface{})
+				addQuantities(requests, quantityMap(resources["requests"]))
+				addQuantities(limits, quantityMap(resources["limits"]))
+			}
+			if err := checkLimitRange(item, "Pod", "", requests, limits); err != nil {
+				return changed, err
+			}
+		}
+	}
+	return changed, nil
+}
+
+// checkLimitRange - min/max/maxLimitRequestRatio 검사 (메시지는 K8s LimitRanger와 같은 형식)
+func checkLimitRange(item limitRangeItem, scope, container string, requests, limits map[string]interface{}) error {
+	where := scope
+	if container != "" {
+		where = fmt.Sprintf("Container (%s)", container)
+	}
+	for _, name := range sortedKeys(item.Min) {
+		minimum, _ := quantityValue(name, item.Min[name])
+		request, ok := requests[name]
+		if !ok {
+			return fmt.Errorf("minimum %s usage per %s is %s. No request is specified", name, where, item.Min[name])
+		}
+		if value, _ := quantityValue(name, request); value < minimum {
+			return fmt.Errorf("minimum %s usage per %s is %s, but request is %v", name, where, item.Min[name], request)
+		}
+	}
+	for _, name := range sortedKeys(item.Max) {
+		maximum, _ := quantityValue(name, item.Max[name])
+		limit, ok := limits[name]
+		if !ok {
+			return fmt.Errorf("maximum %s usage per %s is %s. No limit is specified", name, where, item.Max[name])
+		}
+		if value, _ := quantityValue(name, limit); value > maximum {
+			return fmt.Errorf("maximum %s usage per %s is %s, but limit is %v", name, where, item.Max[name], limit)
+		}
+	}
+	for _, name := range sortedKeys(item.MaxLimitRequestRatio) {
+		var ratio float64
+		fmt.Sscanf(item.MaxLimitRequestRatio[name], "%g", &ratio)
+		request, hasRequest := requests[name]
+		limit, hasLimit := limits[name]
+		if !hasRequest || !hasLimit {
+			return fmt.Errorf("%s max limit to request ratio per %s is %s, but no request or limit is specified", name, where, item.MaxLimitRequestRatio[name])
+		}
+		requestValue, _ := quantityValue(name, request)
+		limitValue, _ := quantityValue(name, limit)
+		if requestValue > 0 && float64(limitValue)/float64(requestValue) > ratio {
+			return fmt.Errorf("%s max limit to request ratio per %s is %s, but provided ratio is %.6f", name, where, item.MaxLimitRequestRatio[name], float64(limitValue)/float64(requestValue))
+		}
+	}
+	return nil
+}
+
+// quantityValue - cpu는 millicore, 그 외(memory, ephemeral-storage 등)는 바이트
+func quantityValue(name string, quantity interface{}) (int64, error) {
+	value := fmt.Sprint(quantity)
+	if name == "cpu" {
+		return parseCPUMillis(value)
+	}
+	return parseMemoryBytes(value)
+}
+
+// quantityMap - resources.requests/limits 맵 (없으면 새 맵)
+func quantityMap(value interface{}) map[string]interface{} {
+	if quantities, ok := value.(map[string]interface{}); ok {
+		return quantities
+	}
+	return map[string]interface{}{}
+}
+
+// addQuantities - Pod 합계에 컨테이너 수량 더하기 (합계는 cpu millicore "Nm", 그 외 바이트 또는 "NMi")
+func addQuantities(total, quantities map[string]interface{}) {
+	for name, quantity := range quantities {
+		value, err := quantityValue(name, quantity)
+		if err != nil {
+			continue
+		}
+		current := int64(0)
+		if existing, ok := total[name]; ok {
+			current, _ = quantityValue(name, existing)
+		}
+		switch sum := current + value; {
+		case name == "cpu":
+			total[name] = fmt.Sprintf("%dm", sum)
+		case sum%(1<<20) == 0:
+			total[name] = fmt.Sprintf("%dMi", sum>>20)
+		default:
+			total[name] = fmt.Sprintf("%d", sum)
+		}
+	}
+}
+
+// podContainersOnly - Pod 항목 합계 대상 (초기화 컨테이너는 순서대로 실행되므로 제외)
+func podContainersOnly(podSpec map[string]interface{}) []map[string]interface{} {
+	var containers []map[string]interface{}
+	list, _ := podSpec["containers"].([]interface{})
+	for _, item := range list {
+		if container, ok := item.(map[string]interface{}); ok {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
+
+// writeMetrics - LimitRange 적용 결과
+func (l *LimitRangeAdmission) writeMetrics(w io.Writer) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	namespaces := make([]string, 0, len(l.cache))
+	for namespace := range l.cache {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	writeMetricHeader(w, "nautilus_limitrange_defaulted_total", "counter", "Contract path requests whose pod template received LimitRange defaults")
+	writeMetric(w, "nautilus_limitrange_defaulted_total", nil, float64(l.defaulted))
+	writeMetricHeader(w, "nautilus_limitrange_rejected_total", "counter", "Contract path requests rejected for violating a LimitRange")
+	writeMetric(w, "nautilus_limitrange_rejected_total", nil, float64(l.rejected))
+	writeMetricHeader(w, "nautilus_limitrange_items", "gauge", "Cached LimitRange items per namespace")
+	for _, namespace := range namespaces {
+		writeMetric(w, "nautilus_limitrange_items", map[string]string{"namespace": namespace}, float64(len(l.cache[namespace].items)))
+	}
+}
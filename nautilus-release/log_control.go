@@ -0,0 +1,393 @@
+// Log Control - 재시작 없이 서브시스템별 로그 레벨 변경과 노드/요청 대상 추적 로그
+//
+// 기본 레벨은 NAUTILUS_LOG_LEVEL(기본 debug)이고, 서브시스템(로그를 남긴 소스 파일 이름, 예: pool_sync)별로
+// 레벨을 덮어쓸 수 있습니다. 추적 규칙은 노드 ID, 요청 ID 또는 메시지 문자열과 일치하는 로그를 레벨과
+// 관계없이 정해진 시간 동안 남깁니다. logrus 레벨은 가장 자세한 규칙에 맞춰 올리고, 나머지 거르기는
+// 포매터(출력)와 최근 로그 보관(logRing)이 Allow로 같은 판단을 합니다.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLogTraceDuration = 10 * time.Minute
+	maxLogTraces            = 20
+	logControlMaxBodyBytes  = 4 << 10
+)
+
+// LogTrace - 대상 추적 규칙 (조건 중 하나라도 일치하면 추적)
+type LogTrace struct {
+	ID        string    `json:"id"`
+	NodeID    string    `json:"node_id,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Match     string    `json:"match,omitempty"` // 메시지에 포함된 문자열
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// logLevelOverride - 서브시스템 레벨 (ExpiresAt이 0이면 해제하거나 재시작할 때까지)
+type logLevelOverride struct {
+	Level     logrus.Level
+	ExpiresAt time.Time
+}
+
+// LogControl - 런타임 로그 레벨/추적 관리
+type LogControl struct {
+	logger    *logrus.Logger
+	formatter logrus.Formatter // 원래 포매터 (거르지 않은 로그 출력)
+	maxTrace  time.Duration
+
+	mutex     sync.RWMutex
+	base      logrus.Level
+	overrides map[string]logLevelOverride
+	traces    []*LogTrace
+}
+
+// NewLogControl - 새 Log Control 생성 후 로거 포매터를 감싸서 설치
+func NewLogControl(logger *logrus.Logger) (*LogControl, error) {
+	base, err := logrus.ParseLevel(getEnvOrDefault("NAUTILUS_LOG_LEVEL", "debug"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAUTILUS_LOG_LEVEL: %v", err)
+	}
+	c := &LogControl{
+		logger:    logger,
+		formatter: logger.Formatter,
+		maxTrace:  envSeconds("NAUTILUS_LOG_TRACE_MAX_SECONDS", 3600),
+		base:      base,
+		overrides: make(map[string]logLevelOverride),
+	}
+	logger.SetFormatter(c)
+	logger.SetLevel(base)
+	return c, nil
+}
+
+// Start - 만료된 레벨/추적 규칙 정리
+func (c *LogControl) Start(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.expire(time.Now())
+		}
+	}
+}
+
+// Format - 걸러진 로그는 출력하지 않고, 추적된 로그에는 trace 필드 추가
+func (c *LogControl) Format(entry *logrus.Entry) ([]byte, error) {
+	allowed, trace := c.decide(entry)
+	if !allowed {
+		return nil, nil
+	}
+	if trace != "" {
+		entry.Data["trace"] = trace
+	}
+	return c.formatter.Format(entry)
+}
+
+// Allow - 현재 레벨/추적 규칙으로 로그를 남길지 판단 (logRing 공용)
+func (c *LogControl) Allow(entry *logrus.Entry) bool {
+	allowed, _ := c.decide(entry)
+	return allowed
+}
+
+// decide - 추적 규칙이 우선이고, 그 외에는 서브시스템 레벨 또는 기본 레벨과 비교
+func (c *LogControl) decide(entry *logrus.Entry) (bool, string) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	// 덮어쓴 레벨과 추적이 없으면 logrus 레벨 검사로 충분
+	if len(c.overrides) == 0 && len(c.traces) == 0 {
+		return true, ""
+	}
+
+	now := entry.Time
+	for _, trace := range c.traces {
+		if now.Before(trace.ExpiresAt) && trace.matches(entry) {
+			return true, trace.ID
+		}
+	}
+
+	level := c.base
+	if len(c.overrides) > 0 {
+		if override, ok := c.overrides[callerSubsystem()]; ok && (override.ExpiresAt.IsZero() || now.Before(override.ExpiresAt)) {
+			level = override.Level
+		}
+	}
+	return entry.Level <= level, ""
+}
+
+// matches - 필드 값이나 메시지에 대상이 있는지 확인 (로그 대부분이 노드 ID를 메시지에 포함)
+func (t *LogTrace) matches(entry *logrus.Entry) bool {
+	for _, target := range []struct{ value, field string }{
+		{t.NodeID, "node_id"}, {t.NodeID, "node"}, {t.RequestID, "request_id"},
+	} {
+		if target.value != "" && fmt.Sprint(entry.Data[target.field]) == target.value {
+			return true
+		}
+	}
+	for _, value := range []string{t.NodeID, t.RequestID, t.Match} {
+		if value != "" && strings.Contains(entry.Message, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// callerSubsystem - logrus 호출부를 지나 로그를 남긴 소스 파일 이름 (확장자 제외)
+func callerSubsystem() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	inLogrus := false
+	for {
+		frame, more := frames.Next()
+		if strings.Contains(frame.Function, "sirupsen/logrus") {
+			inLogrus = true
+		} else if inLogrus {
+			return strings.TrimSuffix(filepath.Base(frame.File), ".go")
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// SetLevel - 서브시스템 레벨 변경 (subsystem이 비어 있으면 기본 레벨, level "reset"은 덮어쓰기 해제)
+func (c *LogControl) SetLevel(subsystem, level string, duration time.Duration) error {
+	c.mutex.Lock()
+	switch {
+	case level == "reset" && subsystem == "":
+		c.mutex.Unlock()
+		return fmt.Errorf("the base level cannot be reset, set a level instead")
+	case level == "reset":
+		delete(c.overrides, subsystem)
+	default:
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			c.mutex.Unlock()
+			return err
+		}
+		if subsystem == "" {
+			c.base = parsed
+		} else {
+			override := logLevelOverride{Level: parsed}
+			if duration > 0 {
+				override.ExpiresAt = time.Now().Add(duration)
+			}
+			c.overrides[subsystem] = override
+		}
+	}
+	c.applyLevelLocked()
+	c.mutex.Unlock()
+
+	if subsystem == "" {
+		subsystem = "(base)"
+	}
+	c.logger.Warnf("🎚️ Log level for %s set to %s", subsystem, level)
+	return nil
+}
+
+// AddTrace - 대상 추적 시작 (기간은 NAUTILUS_LOG_TRACE_MAX_SECONDS 이내)
+func (c *LogControl) AddTrace(trace LogTrace, duration time.Duration) (*LogTrace, error) {
+	if trace.NodeID == "" && trace.RequestID == "" && trace.Match == "" {
+		return nil, fmt.Errorf("one of node_id, request_id or match is required")
+	}
+	if duration <= 0 {
+		duration = defaultLogTraceDuration
+	}
+	if duration > c.maxTrace {
+		return nil, fmt.Errorf("duration exceeds the %s maximum", c.maxTrace)
+	}
+
+	now := time.Now()
+	trace.ID = randomToken(12)
+	trace.CreatedAt, trace.ExpiresAt = now, now.Add(duration)
+
+	c.mutex.Lock()
+	if len(c.traces) >= maxLogTraces {
+		c.mutex.Unlock()
+		return nil, fmt.Errorf("at most %d traces can be active", maxLogTraces)
+	}
+	c.traces = append(c.traces, &trace)
+	c.applyLevelLocked()
+	c.mutex.Unlock()
+
+	c.logger.Warnf("🔎 Log trace %s started until %s (node=%q request=%q match=%q)",
+		trace.ID, trace.ExpiresAt.Format(time.RFC3339), trace.NodeID, trace.RequestID, trace.Match)
+	return &trace, nil
+}
+
+// RemoveTrace - 추적 중지
+func (c *LogControl) RemoveTrace(id string) bool {
+	c.mutex.Lock()
+	found := false
+	for i, trace := range c.traces {
+		if trace.ID == id {
+			c.traces = append(c.traces[:i], c.traces[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if found {
+		c.applyLevelLocked()
+	}
+	c.mutex.Unlock()
+
+	if found {
+		c.logger.Warnf("🔎 Log trace %s stopped", id)
+	}
+	return found
+}
+
+// expire - 만료된 규칙 제거 후 logrus 레벨 재계산
+func (c *LogControl) expire(now time.Time) {
+	c.mutex.Lock()
+	var expired []string
+	for subsystem, override := range c.overrides {
+		if !override.ExpiresAt.IsZero() && !now.Before(override.ExpiresAt) {
+			delete(c.overrides, subsystem)
+			expired = append(expired, subsystem)
+		}
+	}
+	active := c.traces[:0]
+	for _, trace := range c.traces {
+		if now.Before(trace.ExpiresAt) {
+			active = append(active, trace)
+		} else {
+			expired = append(expired, "trace "+trace.ID)
+		}
+	}
+	c.traces = active
+	if len(expired) > 0 {
+		c.applyLevelLocked()
+	}
+	c.mutex.Unlock()
+
+	if len(expired) > 0 {
+		sort.Strings(expired)
+		c.logger.Infof("⏱️ Log overrides expired: %s", strings.Join(expired, ", "))
+	}
+}
+
+// applyLevelLocked - logrus 레벨을 가장 자세한 규칙에 맞춤 (추적은 모든 레벨)
+func (c *LogControl) applyLevelLocked() {
+	level := c.base
+	for _, override := range c.overrides {
+		if override.Level > level {
+			level = override.Level
+		}
+	}
+	if len(c.traces) > 0 {
+		level = logrus.TraceLevel
+	}
+	c.logger.SetLevel(level)
+}
+
+// levels - 현재 기본/실제 logrus/서브시스템 레벨
+func (c *LogControl) levels() map[string]interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	subsystems := make(map[string]interface{}, len(c.overrides))
+	for subsystem, override := range c.overrides {
+		value := map[string]interface{}{"level": override.Level.String()}
+		if !override.ExpiresAt.IsZero() {
+			value["expires_at"] = override.ExpiresAt
+		}
+		subsystems[subsystem] = value
+	}
+	return map[string]interface{}{
+		"base":       c.base.String(),
+		"effective":  c.logger.GetLevel().String(),
+		"subsystems": subsystems,
+	}
+}
+
+func (c *LogControl) traceList() []LogTrace {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	traces := make([]LogTrace, len(c.traces))
+	for i, trace := range c.traces {
+		traces[i] = *trace
+	}
+	return traces
+}
+
+// handleLogLevels - GET/PUT /debug/log-levels
+func (c *LogControl) handleLogLevels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, c.levels())
+
+	case http.MethodPut:
+		var body struct {
+			Subsystem       string `json:"subsystem"`
+			Level           string `json:"level"`
+			DurationSeconds int    `json:"duration_seconds"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, logControlMaxBodyBytes)).Decode(&body); err != nil {
+			http.Error(w, "Invalid log level: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := c.SetLevel(body.Subsystem, strings.ToLower(body.Level), time.Duration(body.DurationSeconds)*time.Second); err != nil {
+			http.Error(w, "Invalid log level: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, c.levels())
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleTraces - GET/POST/DELETE /debug/traces
+func (c *LogControl) handleTraces(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, c.traceList())
+
+	case http.MethodPost:
+		var body struct {
+			NodeID          string `json:"node_id"`
+			RequestID       string `json:"request_id"`
+			Match           string `json:"match"`
+			DurationSeconds int    `json:"duration_seconds"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, logControlMaxBodyBytes)).Decode(&body); err != nil {
+			http.Error(w, "Invalid trace: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		trace, err := c.AddTrace(LogTrace{NodeID: body.NodeID, RequestID: body.RequestID, Match: body.Match},
+			time.Duration(body.DurationSeconds)*time.Second)
+		if err != nil {
+			http.Error(w, "Invalid trace: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeClientJSON(w, trace)
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if !c.RemoveTrace(id) {
+			http.Error(w, "Trace not found", http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, map[string]string{"id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,216 @@
+// Log Throttling - 워커가 로그 제한을 건 Pod를 Pod 상태 조건(k3s-daas.io/LogThrottled)으로 표시
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logThrottledCondition - 워커가 Pod 로그를 버리거나 잘라낼 때 켜지는 Pod 조건 (kubelet은 자신이 관리하지 않는 조건을 유지)
+const logThrottledCondition = "k3s-daas.io/LogThrottled"
+
+// LogThrottle - 워커 하트비트 log_throttling 항목 (컨테이너 하나, 제한 사유 하나)
+type LogThrottle struct {
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container"`
+	Reason       string    `json:"reason"` // RateLimited, SizeCapped
+	DroppedLines int64     `json:"dropped_lines"`
+	DroppedBytes int64     `json:"dropped_bytes"`
+	Since        time.Time `json:"since"`
+	LastAt       time.Time `json:"last_at"`
+}
+
+// podThrottleState - Pod 조건에 반영한 값 (바뀔 때만 다시 patch)
+type podThrottleState struct {
+	namespace string
+	pod       string
+	reason    string
+	message   string
+}
+
+/*
+LogThrottleTracker - 노드별 로그 제한 보고를 Pod 조건으로 반영
+하트비트마다 워커가 현재 제한 목록 전체를 보내므로, 목록에서 빠진 Pod는 조건을 False로 되돌립니다.
+kubectl patch는 하트비트 응답을 늦추지 않도록 별도 고루틴에서 순서대로 실행합니다.
+*/
+type LogThrottleTracker struct {
+	logger  *logrus.Logger
+	k3sMgr  *K3sManager
+	history *HeartbeatHistory
+
+	mutex   sync.Mutex
+	nodes   map[string]map[string]podThrottleState // 노드 → <ns>/<pod> → 반영한 상태
+	dropped map[string]int64                       // 노드 → 마지막 보고의 버린 바이트 합계
+	patches chan podThrottlePatch
+	patched int
+	failed  int
+}
+
+type podThrottlePatch struct {
+	state  podThrottleState
+	active bool
+}
+
+// NewLogThrottleTracker - 로그 제한 추적기 생성
+func NewLogThrottleTracker(logger *logrus.Logger, k3sMgr *K3sManager) *LogThrottleTracker {
+	t := &LogThrottleTracker{
+		logger:  logger,
+		k3sMgr:  k3sMgr,
+		nodes:   make(map[string]map[string]podThrottleState),
+		dropped: make(map[string]int64),
+		patches: make(chan podThrottlePatch, 256),
+	}
+	go t.run()
+	return t
+}
+
+// Observe - 하트비트의 제한 목록 반영 (새로 제한되거나 내용이 바뀐 Pod와 해제된 Pod만 patch)
+func (t *LogThrottleTracker) Observe(nodeID string, throttles []LogThrottle) {
+	current := make(map[string]podThrottleState)
+	reasons := make(map[string]map[string]bool)
+	containers := make(map[string][]string)
+	var dropped int64
+	for _, throttle := range throttles {
+		key := throttle.Namespace + "/" + throttle.Pod
+		if reasons[key] == nil {
+			reasons[key] = make(map[string]bool)
+		}
+		reasons[key][throttle.Reason] = true
+		containers[key] = append(containers[key], throttle.Container)
+		dropped += throttle.DroppedBytes
+		current[key] = podThrottleState{namespace: throttle.Namespace, pod: throttle.Pod}
+	}
+	for key, state := range current {
+		state.reason = joinSorted(reasons[key], "And") // 예: RateLimitedAndSizeCapped
+		state.message = fmt.Sprintf("logs throttled on node %s for containers %s", nodeID, joinSorted(toSet(containers[key]), ", "))
+		current[key] = state
+	}
+
+	t.mutex.Lock()
+	previous := t.nodes[nodeID]
+	t.nodes[nodeID] = current
+	t.dropped[nodeID] = dropped
+	if len(current) == 0 {
+		delete(t.nodes, nodeID)
+		delete(t.dropped, nodeID)
+	}
+	t.mutex.Unlock()
+
+	for key, state := range current {
+		if old, ok := previous[key]; ok && old == state {
+			continue
+		}
+		t.logger.Warnf("🚱 Worker %s throttles logs of pod %s: %s", nodeID, key, state.reason)
+		t.history.RecordEvent(nodeID, "log_throttled", key+" "+state.reason)
+		t.enqueue(podThrottlePatch{state: state, active: true})
+	}
+	for key, state := range previous {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		t.history.RecordEvent(nodeID, "log_throttle_cleared", key)
+		t.enqueue(podThrottlePatch{state: state, active: false})
+	}
+}
+
+func (t *LogThrottleTracker) enqueue(patch podThrottlePatch) {
+	select {
+	case t.patches <- patch:
+	default:
+		// 다음 변경 때 다시 반영되므로 대기열이 가득 차면 버림
+		t.logger.Warnf("⚠️ Log throttle patch queue full, skipping pod %s/%s", patch.state.namespace, patch.state.pod)
+	}
+}
+
+func (t *LogThrottleTracker) run() {
+	for patch := range t.patches {
+		if t.k3sMgr == nil || !t.k3sMgr.IsRunning() {
+			continue
+		}
+		err := t.patchPod(patch)
+		t.mutex.Lock()
+		if err != nil {
+			t.failed++
+		} else {
+			t.patched++
+		}
+		t.mutex.Unlock()
+		if err != nil {
+			t.logger.Warnf("⚠️ Failed to update %s condition on pod %s/%s: %v", logThrottledCondition, patch.state.namespace, patch.state.pod, err)
+		}
+	}
+}
+
+// patchPod - Pod status 조건 설정 (conditions는 type 기준 strategic merge라 다른 조건은 그대로)
+func (t *LogThrottleTracker) patchPod(patch podThrottlePatch) error {
+	condition := map[string]string{
+		"type":               logThrottledCondition,
+		"status":             "False",
+		"reason":             "LogsWithinLimits",
+		"message":            "",
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	}
+	if patch.active {
+		condition["status"] = "True"
+		condition["reason"] = patch.state.reason
+		condition["message"] = patch.state.message
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []map[string]string{condition}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.k3sMgr.RunKubectl(nil, "patch", "pod", patch.state.pod, "-n", patch.state.namespace,
+		"--subresource=status", "--type=strategic", "-p", string(body))
+	return err
+}
+
+// writeMetrics - 제한 중인 Pod 수와 patch 결과
+func (t *LogThrottleTracker) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	nodes := make([]string, 0, len(t.nodes))
+	for nodeID := range t.nodes {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+	writeMetricHeader(w, "nautilus_log_throttled_pods", "gauge", "Pods whose logs a worker is currently rate limiting or size capping")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_log_throttled_pods", map[string]string{"node": nodeID}, float64(len(t.nodes[nodeID])))
+	}
+	writeMetricHeader(w, "nautilus_log_throttled_dropped_bytes", "gauge", "Log bytes dropped by throttled containers as last reported by each worker")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_log_throttled_dropped_bytes", map[string]string{"node": nodeID}, float64(t.dropped[nodeID]))
+	}
+	writeMetricHeader(w, "nautilus_log_throttle_patches_total", "counter", "Pod condition updates for log throttling")
+	writeMetric(w, "nautilus_log_throttle_patches_total", map[string]string{"outcome": "applied"}, float64(t.patched))
+	writeMetric(w, "nautilus_log_throttle_patches_total", map[string]string{"outcome": "failed"}, float64(t.failed))
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// joinSorted - 집합을 정렬해 이어 붙임 (조건 내용이 보고 순서와 무관하게 같도록)
+func joinSorted(set map[string]bool, sep string) string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return strings.Join(values, sep)
+}
@@ -3,23 +3,96 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 
+	service "github.com/k3s-io/daas-service"
+	version "github.com/k3s-io/daas-version"
 	"github.com/sirupsen/logrus"
 )
 
+// serviceName - systemd 유닛 / Windows 서비스 이름
+const serviceName = "nautilus-control"
+
 func main() {
-	// 로거 초기화
+	// 기능 게이트: NAUTILUS_FEATURE_GATES < --feature-gates (플래그를 뺀 나머지 인자로 서브커맨드 판별)
+	if err := features.LoadEnv("NAUTILUS_FEATURE_GATES"); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+	args, err := features.ParseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	// 서비스 관리: nautilus-control service install|uninstall|start|stop|restart|status
+	if len(args) > 0 && args[0] == "service" {
+		spec := service.Spec{
+			Name:        serviceName,
+			DisplayName: "K3s-DaaS Nautilus Control",
+			Description: "K3s-DaaS master node (Nautilus TEE control plane)",
+		}
+		if err := service.Command(spec, args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 상태 저장소 마이그레이션: nautilus-control migrate [status] [--dry-run]
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrateCommand(args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 빌드 버전: nautilus-control version
+	if len(args) > 0 && args[0] == "version" {
+		fmt.Println(version.Get("master"))
+		return
+	}
+
+	// 로거 초기화 (Windows 서비스로 실행 시 EventLog, 그 외 stderr → journald)
 	logger := logrus.New()
-	logger.SetLevel(logrus.DebugLevel)
+	logger.SetOutput(service.LogOutput(serviceName))
+
+	// 런타임 로그 레벨/대상 추적 (NAUTILUS_LOG_LEVEL, 기본 debug)
+	logControl, err := NewLogControl(logger)
+	if err != nil {
+		logger.Fatalf("❌ %v", err)
+	}
+
+	// 지원 번들용 최근 로그 보관 (NAUTILUS_SUPPORT_LOG_LINES)
+	recentLogs := newLogRing()
+	recentLogs.allow = logControl.Allow
+	logger.AddHook(recentLogs)
 
 	// Context 생성
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	go logControl.Start(ctx)
+
+	logger.Infof("🚀 Nautilus Control starting... (%s)", version.Get("master"))
+	features.LogSummary(func(format string, args ...interface{}) { logger.Infof("🚩 "+format, args...) })
+
+	// 반영 도중 중단된 상태 트랜잭션을 마저 반영 (마이그레이션과 컴포넌트가 일부만 바뀐 상태를 읽지 않도록)
+	if replayed, err := recoverStateTxns(stateDir()); err != nil {
+		logger.Fatalf("🛑 State transaction recovery failed: %v", err)
+	} else if replayed > 0 {
+		logger.Infof("♻️ Replayed %d interrupted state transactions", replayed)
+	}
 
-	logger.Info("🚀 Nautilus Control starting...")
+	// 상태 파일을 읽는 컴포넌트 생성 전에 스키마 마이그레이션 (NAUTILUS_AUTO_MIGRATE=false면 수동 실행 요구)
+	if getEnvOrDefault("NAUTILUS_AUTO_MIGRATE", "true") == "true" {
+		if err := migrateState(logger, stateDir(), false); err != nil {
+			logger.Fatalf("🛑 State migration failed: %v", err)
+		}
+	} else if marker, _, err := readSchemaVersion(stateDir()); err != nil || marker.Version != latestSchemaVersion() {
+		logger.Fatalf("🛑 State schema is not v%d; run `%s migrate` first (%v)", latestSchemaVersion(), serviceName, err)
+	}
 
 	// K3s Manager 초기화
 	k3sMgr := NewK3sManager(logger)
@@ -27,23 +100,649 @@ func main() {
 	// API Server 초기화
 	apiServer := NewAPIServer(logger, k3sMgr)
 
+	// Version Skew 초기화 (워커/Gateway 버전 핸드셰이크, NAUTILUS_VERSION_SKEW_POLICY)
+	versionSkew, err := NewVersionSkew(logger)
+	if err != nil {
+		logger.Fatalf("❌ Invalid version skew policy: %v", err)
+	}
+	apiServer.versions = versionSkew
+
+	// Kubectl Compat 초기화 (User-Agent의 kubectl 버전 기준 호환 처리, KubectlCompat 게이트)
+	kubectlCompat := NewKubectlCompat(logger, k3sMgr)
+	apiServer.kubectlCompat = kubectlCompat
+
+	// Controller Manager 초기화 (어드미션, 카나리/토폴로지 라우팅 등 마스터 측 컨트롤러)
+	controllerMgr := NewControllerManager(logger, k3sMgr)
+
+	// Enclave Keyring 초기화 (마스터 자격 증명은 봉인된 blob으로만 저장, NAUTILUS_SEALING_PROVIDER)
+	keyring, err := NewEnclaveKeyring(logger)
+	if err != nil {
+		logger.Fatalf("🛑 Failed to open enclave keyring: %v", err)
+	}
+
+	// Sealing Escrow 초기화 (봉인 루트 키를 보관자들에게 분할 보관, 이전 루트의 blob만 있으면 여기서 조각을 받아 복구)
+	sealingEscrow, err := NewSealingEscrow(logger, keyring)
+	if err != nil {
+		logger.Fatalf("❌ Invalid escrow config: %v", err)
+	}
+	if sealingEscrow != nil {
+		if err := sealingEscrow.Recover(ctx); err != nil {
+			logger.Fatalf("🛑 Escrow recovery failed: %v", err)
+		}
+		if err := sealingEscrow.Ensure(); err != nil {
+			logger.Fatalf("🛑 Failed to escrow sealing root: %v", err)
+		}
+		apiServer.escrow = sealingEscrow
+	}
+
 	// Sui Integration 초기화
-	suiIntegration := NewSuiIntegration(logger, k3sMgr)
+	suiIntegration := NewSuiIntegration(logger, k3sMgr, controllerMgr, keyring)
+
+	// Contract Migration 초기화 (패키지 업그레이드/재배포 전환 기간, NAUTILUS_MIGRATION_PACKAGE_ID)
+	contractMigration, err := NewContractMigration(logger, suiIntegration)
+	if err != nil {
+		logger.Fatalf("❌ Invalid contract migration config: %v", err)
+	}
+	suiIntegration.migration = contractMigration
+	apiServer.migration = contractMigration
+
+	// RBAC 초기화 (지갑 간 위임 접근 권한)
+	rbac := NewRBACAuthorizer(logger, k3sMgr.workerPool)
+	suiIntegration.rbac = rbac
+	apiServer.rbac = rbac
+
+	// Audit Logger 초기화 (AUDIT_SINKS=file,webhook,kafka)
+	auditLogger := NewAuditLogger(logger)
+	suiIntegration.audit = auditLogger
+
+	// Capacity Publisher 초기화 (스케줄 불가 수요 온체인 공시)
+	capacityPublisher := NewCapacityPublisher(logger, k3sMgr, suiIntegration)
+	apiServer.capacity = capacityPublisher
+	apiServer.topology = controllerMgr.topology
+	apiServer.simulator = NewScheduleSimulator(logger, k3sMgr)
+	apiServer.costs = NewCostEstimator(logger, suiIntegration, k3sMgr.workerPool)
+	apiServer.canaries = controllerMgr.canaries
+	apiServer.routing = controllerMgr.routing
+
+	// Node Health Scorer 초기화 (느리거나 불안정한 워커 probation)
+	healthScorer := NewNodeHealthScorer(logger, k3sMgr)
+	apiServer.health = healthScorer
+
+	// Heartbeat History 초기화 (노드별 하트비트 시계열 및 이벤트 타임라인)
+	heartbeatHistory := NewHeartbeatHistory(logger)
+	apiServer.history = heartbeatHistory
+	suiIntegration.history = heartbeatHistory
+	healthScorer.history = heartbeatHistory
+
+	// Claim Verifier 초기화 (하트비트 자가 보고 교차 검증 및 감사 프로브)
+	claimVerifier := NewClaimVerifier(logger, k3sMgr)
+	claimVerifier.history = heartbeatHistory
+	apiServer.claims = claimVerifier
+
+	// Maintenance Scheduler 초기화 (예약된 정비 시간에 cordon/drain, 정비 중 liveness 패널티 유예)
+	maintenanceScheduler := NewMaintenanceScheduler(logger, k3sMgr)
+	maintenanceScheduler.history = heartbeatHistory
+	claimVerifier.maintenance = maintenanceScheduler
+	healthScorer.maintenance = maintenanceScheduler
+	suiIntegration.maintenance = maintenanceScheduler
+	apiServer.maintenance = maintenanceScheduler
+
+	// Appeal Tracker 초기화 (슬래싱 이의 신청 상태 및 거버넌스 심사용 증거 보관)
+	appealTracker := NewAppealTracker(logger, k3sMgr.workerPool)
+	appealTracker.history = heartbeatHistory
+	suiIntegration.appeals = appealTracker
+	apiServer.appeals = appealTracker
+
+	// Tenant Throttler 초기화 (요청자 지갑별 스테이킹 비례 QPS 제한)
+	tenantThrottler := NewTenantThrottler(logger, k3sMgr.workerPool)
+	apiServer.quota = tenantThrottler
+	suiIntegration.quota = tenantThrottler
+
+	// Chain Outbox 초기화 (감사 앵커/사용량 기록을 순번과 함께 디스크에 보관 후 순서대로 제출, AUDIT_LEDGER_ID)
+	chainOutbox := NewChainOutbox(logger, suiIntegration)
+	chainOutbox.quota = tenantThrottler
+	auditLogger.outbox = chainOutbox
+	apiServer.outbox = chainOutbox
+
+	// Clock Guard 초기화 (체인 시각 대비 오차가 크면 보안 민감 작업 거부)
+	clockGuard := NewClockGuard(logger, suiIntegration)
+	suiIntegration.clock = clockGuard
+	apiServer.clock = clockGuard
+
+	// Package Pin 초기화 (CONTRACT_PACKAGE_DIGEST/CONTRACT_MODULES_SHA256과 다른 패키지면 이벤트 처리 거부)
+	packagePin := NewPackagePin(logger, suiIntegration)
+	suiIntegration.pin = packagePin
+
+	// Pool Sync 초기화 (온체인 레지스트리 기준 워커 풀 재구성/정합성 검사)
+	poolSync := NewPoolSync(logger, suiIntegration)
+	suiIntegration.poolSync = poolSync
+	apiServer.poolSync = poolSync
+
+	// Restart Checkpoint 초기화 (정상 종료 시 풀/이벤트 위치/캐시 저장, 다음 시작은 변경분만 동기화)
+	restartCheckpoint := NewRestartCheckpointer(logger, suiIntegration, poolSync)
+
+	// Enrollment Queue 초기화 (NAUTILUS_ENROLLMENT_MODE=approval이면 신규 워커를 심사 후 풀에 추가)
+	enrollmentQueue, err := NewEnrollmentQueue(logger, suiIntegration)
+	if err != nil {
+		logger.Fatalf("❌ Invalid enrollment config: %v", err)
+	}
+	enrollmentQueue.history = heartbeatHistory
+	suiIntegration.enrollment = enrollmentQueue
+	apiServer.enrollment = enrollmentQueue
+
+	// Finality Gate 초기화 (인증된 체크포인트 이하의 이벤트만 처리, NAUTILUS_FINALITY_DEPTH)
+	// 체크포인트 조회가 Sui 전용이므로 다른 체인 백엔드에서는 사용하지 않음
+	var finalityGate *FinalityGate
+	if suiIntegration.onSui() {
+		finalityGate = NewFinalityGate(logger, suiIntegration)
+		finalityGate.history = heartbeatHistory
+		suiIntegration.finality = finalityGate
+		apiServer.finality = finalityGate
+	}
+
+	// Dead Letter Queue 초기화 (형식 오류/미지원 이벤트 보관, NAUTILUS_DLQ_ALERT_THRESHOLD 초과 시 알림)
+	deadLetters := NewDeadLetterQueue(logger, suiIntegration)
+	suiIntegration.deadLetters = deadLetters
+	apiServer.deadLetters = deadLetters
+
+	// Response Store 초기화 (실행 결과 zstd 압축, 인라인 한도 초과분 오프체인 저장, 작은 응답 배치 제출)
+	responseStore, err := NewResponseStore(logger, suiIntegration)
+	if err != nil {
+		logger.Fatalf("❌ Invalid response store config: %v", err)
+	}
+	suiIntegration.responses = responseStore
+	apiServer.responses = responseStore
+
+	// Client API 초기화 (pkg/client SDK용 워커/토큰 조회, NAUTILUS_GATEWAY_URL 기준 kubeconfig 발급)
+	apiServer.clientAPI = NewClientAPI(logger, k3sMgr.workerPool)
+
+	// Event Stream 초기화 (대시보드용 /api/v1/stream WebSocket)
+	eventStream := NewEventStream(logger, k3sMgr)
+	eventStream.capacity = capacityPublisher
+	k3sMgr.workerPool.stream = eventStream
+	heartbeatHistory.stream = eventStream
+	capacityPublisher.stream = eventStream
+	apiServer.stream = eventStream
+
+	// 오프라인 개발용 mock 체인 (마스터가 호스팅할 때만 워커에 노출)
+	apiServer.mockChain = suiIntegration.mockChain
+
+	// Registry Cache 초기화 (NAUTILUS_REGISTRY_CACHE=true일 때 Docker Hub pull-through 캐시)
+	registryCache := NewRegistryCache(logger, k3sMgr.workerPool)
+	apiServer.registry = registryCache
+
+	// Readiness Gate 초기화 (준비 전 kubectl 트래픽 503, READINESS_MAX_CHECKPOINT_LAG)
+	readinessGate := NewReadinessGate(logger, k3sMgr, suiIntegration, poolSync)
+	apiServer.ready = readinessGate
+
+	// Event Watchdog 초기화 (이벤트 조회/처리가 멈추면 준비 해제, 알림, NAUTILUS_WATCHDOG_*)
+	eventWatchdog := NewEventWatchdog(logger, suiIntegration)
+	suiIntegration.watchdog = eventWatchdog
+	readinessGate.watchdog = eventWatchdog
+
+	// SLO Tracker 초기화 (kubectl 경로/이벤트 파이프라인 오류 예산, NAUTILUS_SLO_*)
+	sloTracker := NewSLOTracker(logger)
+	apiServer.slo = sloTracker
+	suiIntegration.slo = sloTracker
+	registryCache.slo = sloTracker
+
+	// Idempotency Cache 초기화 (워커 등록/Pod 생성 재시도 응답 재생, NAUTILUS_IDEMPOTENCY_TTL_SECONDS)
+	idempotencyCache := newIdempotencyCache()
+	apiServer.idempotency = idempotencyCache
+
+	// Bootstrap Manager 초기화 (NAUTILUS_BOOTSTRAP_DIR의 기본 매니페스트를 K3s 기동 후 적용)
+	bootstrapMgr := NewBootstrapManager(logger, k3sMgr)
+	apiServer.bootstrap = bootstrapMgr
+
+	// 메트릭 수집기 등록
+	metrics := NewMetricsRegistry()
+	metrics.Register("node_health", healthScorer.writeMetrics)
+	metrics.Register("canaries", controllerMgr.canaries.writeMetrics)
+	metrics.Register("topology_routing", controllerMgr.routing.writeMetrics)
+	metrics.Register("priority_guard", controllerMgr.priorities.writeMetrics)
+	metrics.Register("image_platforms", controllerMgr.platforms.writeMetrics)
+	metrics.Register("limit_ranges", controllerMgr.limits.writeMetrics)
+	metrics.Register("clock_skew", clockGuard.writeMetrics)
+	metrics.Register("package_pin", packagePin.writeMetrics)
+	metrics.Register("enclave_keys", keyring.writeMetrics)
+	if sealingEscrow != nil {
+		metrics.Register("sealing_escrow", sealingEscrow.writeMetrics)
+	}
+	metrics.Register("pool_sync", poolSync.writeMetrics)
+	metrics.Register("restart_checkpoint", restartCheckpoint.writeMetrics)
+	metrics.Register("enrollment", enrollmentQueue.writeMetrics)
+	metrics.Register("chain_drift", poolSync.writeDriftMetrics)
+	metrics.Register("contract_migration", contractMigration.writeMetrics)
+	metrics.Register("dead_letters", deadLetters.writeMetrics)
+	metrics.Register("chain_outbox", chainOutbox.writeMetrics)
+	metrics.Register("responses", responseStore.writeMetrics)
+	metrics.Register("claims", claimVerifier.writeMetrics)
+	metrics.Register("maintenance", maintenanceScheduler.writeMetrics)
+	metrics.Register("slash_appeals", appealTracker.writeMetrics)
+	metrics.Register("tenant_quota", tenantThrottler.writeMetrics)
+	metrics.Register("sui_client", suiClientCollector(suiIntegration.chain))
+	metrics.Register("http_panics", httpPanicCollector)
+	metrics.Register("registry_cache", registryCache.writeMetrics)
+	metrics.Register("readiness", readinessGate.writeMetrics)
+	metrics.Register("event_watchdog", eventWatchdog.writeMetrics)
+	metrics.Register("slo", sloTracker.writeMetrics)
+	metrics.Register("idempotency", idempotencyCollector(idempotencyCache))
+	metrics.Register("versions", versionSkew.writeMetrics)
+	metrics.Register("kubectl_compat", kubectlCompat.writeMetrics)
+	metrics.Register("bootstrap", bootstrapMgr.writeMetrics)
+	metrics.Register("event_stream", eventStream.writeMetrics)
+	if finalityGate != nil {
+		metrics.Register("finality", finalityGate.writeMetrics)
+	}
+	apiServer.metrics = metrics
+
+	// Alert Manager 초기화 (메트릭/이벤트 알림 규칙, 웹훅/Slack/PagerDuty 전송, NAUTILUS_ALERT_*)
+	alertManager, err := NewAlertManager(logger, metrics)
+	if err != nil {
+		logger.Fatalf("❌ Invalid alerting configuration: %v", err)
+	}
+	eventStream.alerts = alertManager
+	apiServer.alerts = alertManager
+	metrics.Register("alerting", alertManager.writeMetrics)
+
+	// Wallet Monitor 초기화 (마스터 지갑 잔액 부족 경고, 테스트넷 faucet 자동 요청)
+	walletMonitor, err := NewWalletMonitor(logger, suiIntegration)
+	if err != nil {
+		logger.Fatalf("❌ Invalid wallet monitor configuration: %v", err)
+	}
+	apiServer.wallets = walletMonitor
+	metrics.Register("wallet_monitor", walletMonitor.writeMetrics)
+
+	// Gas Sponsor 초기화 (GAS_SPONSORSHIP=true일 때 워커 가스 대납)
+	if getEnvOrDefault("GAS_SPONSORSHIP", "false") == "true" {
+		apiServer.sponsor = NewGasSponsor(logger, suiIntegration)
+	}
+
+	// Status Page 초기화 (인증 없는 공개 상태)
+	statusPage := NewStatusPage(logger, k3sMgr, suiIntegration)
+	statusPage.capacity = capacityPublisher
+	apiServer.status = statusPage
+
+	// Request Signer 초기화 (Gateway ↔ 마스터 요청/응답 서명)
+	requestSigner, err := NewRequestSigner(logger, keyring)
+	if err != nil {
+		logger.Fatalf("❌ Failed to initialize request signing: %v", err)
+	}
+	apiServer.signer = requestSigner
+
+	// Service Account 초기화 (클러스터 내부 컨트롤러용 계정과 TEE 키 서명 토큰, OIDC 발급자 NAUTILUS_SA_ISSUER)
+	serviceAccounts, err := NewServiceAccountIssuer(logger, k3sMgr, requestSigner, keyring)
+	if err != nil {
+		logger.Fatalf("❌ Failed to initialize service account issuer: %v", err)
+	}
+	apiServer.serviceAccounts = serviceAccounts
+	metrics.Register("service_accounts", serviceAccounts.writeMetrics)
+
+	// Secret Broker 초기화 (워커 secrets 볼륨의 Vault JWT 발급, 온체인 Sealed Secret 복호화)
+	secretBroker, err := NewSecretBroker(logger, k3sMgr, suiIntegration, keyring, serviceAccounts)
+	if err != nil {
+		logger.Fatalf("❌ Failed to initialize secret broker: %v", err)
+	}
+	apiServer.secrets = secretBroker
+	metrics.Register("secret_broker", secretBroker.writeMetrics)
+
+	// Payload Encryption 초기화 (증명 문서로 수신자 키 공지, 온체인 암호문을 승인 전 복호화)
+	if features.Enabled(featureEncryptedPayloads) {
+		payloads, err := NewPayloadDecryptor(logger, keyring)
+		if err != nil {
+			logger.Fatalf("❌ Failed to initialize payload encryption: %v", err)
+		}
+		suiIntegration.payloads = payloads
+		requestSigner.payloads = payloads
+		metrics.Register("encrypted_payloads", payloads.writeMetrics)
+	}
+
+	// Access Review 초기화 (TokenReview/SubjectAccessReview로 Seal·서비스 계정 토큰과 위임 권한 조회)
+	reviews := NewAccessReviewer(logger, k3sMgr.workerPool, rbac, serviceAccounts)
+	apiServer.reviews = reviews
+	metrics.Register("access_reviews", reviews.writeMetrics)
+
+	// Federation 초기화 (NAUTILUS_REGION, NAUTILUS_MASTER_REGISTRY로 리전 공지 및 피어 상태 동기화)
+	var federation *Federation
+	if features.Enabled(featureFederation) {
+		federation, err = NewFederation(logger, k3sMgr.workerPool, suiIntegration, requestSigner)
+		if err != nil {
+			logger.Fatalf("❌ Invalid federation config: %v", err)
+		}
+		apiServer.federation = federation
+		metrics.Register("federation", federation.writeMetrics)
+	}
+
+	// Node Abandonment 초기화 (장기 부재 노드 표시 및 비상 인출용 미참여 증명 서명)
+	var abandonment *AbandonmentTracker
+	if features.Enabled(featureNodeAbandonment) {
+		abandonment, err = NewAbandonmentTracker(logger, k3sMgr.workerPool, suiIntegration, requestSigner)
+		if err != nil {
+			logger.Fatalf("❌ Invalid node abandonment config: %v", err)
+		}
+		abandonment.history = heartbeatHistory
+		suiIntegration.abandonment = abandonment
+		apiServer.abandonment = abandonment
+		metrics.Register("node_abandonment", abandonment.writeMetrics)
+	}
+
+	// Debug Server 초기화 (NAUTILUS_ADMIN_TOKEN 필요)
+	debugServer := NewDebugServer(logger, k3sMgr, suiIntegration)
+	debugServer.rbac = rbac
+	debugServer.capacity = capacityPublisher
+	debugServer.health = healthScorer
+	debugServer.metrics = metrics
+	debugServer.readiness = readinessGate
+	debugServer.deadLetters = deadLetters
+	debugServer.poolSync = poolSync
+	debugServer.signer = requestSigner
+	debugServer.logs = recentLogs
+	debugServer.logControl = logControl
+	apiServer.debug = debugServer
+
+	// Kubelet CA/CSR API 초기화 (k3s agent kubelet 인증서를 seal 토큰 검증 후 TEE CA로 발급)
+	kubeletCA, err := NewKubeletCA(logger)
+	if err != nil {
+		logger.Fatalf("❌ Failed to initialize kubelet CA: %v", err)
+	}
+	k3sMgr.kubeletCA = kubeletCA
+	csrAPI := NewCSRAPI(logger, k3sMgr.workerPool, kubeletCA)
+	csrAPI.history = heartbeatHistory
+	apiServer.csr = csrAPI
+	metrics.Register("kubelet_csr", csrAPI.writeMetrics)
+
+	// Status Access 초기화 (워커 상태 포트 허용 목록과 mTLS CA/신원, NAUTILUS_WORKER_STATUS_ALLOWLIST)
+	statusAccess, err := NewStatusAccess(logger, k3sMgr.workerPool, kubeletCA)
+	if err != nil {
+		logger.Fatalf("❌ Invalid worker status access config: %v", err)
+	}
+	apiServer.statusAccess = statusAccess
+
+	// Join Token Issuer 초기화 (등록 시 Seal 재검증 후 노드 전용 1회용 K3s 토큰 발급)
+	joinTokens := NewJoinTokenIssuer(logger, k3sMgr)
+	joinTokens.poolSync = poolSync
+	joinTokens.history = heartbeatHistory
+	apiServer.joinTokens = joinTokens
+	metrics.Register("join_tokens", joinTokens.writeMetrics)
+
+	// Worker Config Sync 초기화 (설정 번들을 하트비트 응답으로 차등 배포)
+	if features.Enabled(featureWorkerConfigSync) {
+		workerConfig := NewWorkerConfigSync(logger)
+		workerConfig.history = heartbeatHistory
+		apiServer.workerConfig = workerConfig
+		metrics.Register("worker_config", workerConfig.writeMetrics)
+	}
+
+	// Trash Bin 초기화 (삭제된 객체를 NAUTILUS_TRASH_RETENTION_HOURS 동안 보관, 복원 API)
+	var trashBin *TrashBin
+	if features.Enabled(featureSoftDelete) {
+		trashBin = NewTrashBin(logger, k3sMgr)
+		suiIntegration.trash = trashBin
+		apiServer.trash = trashBin
+		metrics.Register("trash", trashBin.writeMetrics)
+	}
+
+	// Scheduler Extender 초기화 (kube-scheduler 필터/점수/바인드 단계에서 플러그인 파이프라인 실행, 테넌트 웹훅 포함)
+	if features.Enabled(featureSchedulerExtenders) {
+		schedulerExtender, err := NewSchedulerExtender(logger, k3sMgr, apiServer.simulator)
+		if err != nil {
+			logger.Fatalf("❌ Failed to configure scheduler plugins: %v", err)
+		}
+		k3sMgr.extender = schedulerExtender
+		apiServer.extender = schedulerExtender
+		metrics.Register("scheduler_extender", schedulerExtender.writeMetrics)
+	}
+
+	// Capacity Reservations 초기화 (온체인 예약 구매 확정/거절, 스케줄러 플러그인이 미사용 예약 용량 보류)
+	var reservations *CapacityReservations
+	if features.Enabled(featureCapacityReservations) {
+		reservations, err = NewCapacityReservations(logger, k3sMgr, suiIntegration, apiServer.simulator)
+		if err != nil {
+			logger.Fatalf("❌ Invalid capacity reservation config: %v", err)
+		}
+		if apiServer.extender != nil {
+			apiServer.extender.handle.Reservations = reservations
+		} else {
+			logger.Warnf("⚠️ %s is enabled without %s: reservations are confirmed but not enforced by the scheduler", featureCapacityReservations, featureSchedulerExtenders)
+		}
+		suiIntegration.reservations = reservations
+		apiServer.reservations = reservations
+		metrics.Register("capacity_reservations", reservations.writeMetrics)
+	}
+
+	// Node Compliance 초기화 (워커 호스트 지문을 NAUTILUS_COMPLIANCE_POLICY와 비교, 위반 노드 조건 표시와 감점)
+	if features.Enabled(featureHostCompliance) {
+		compliance, err := NewNodeComplianceTracker(logger, k3sMgr)
+		if err != nil {
+			logger.Fatalf("❌ Invalid compliance policy: %v", err)
+		}
+		compliance.history = heartbeatHistory
+		if apiServer.extender != nil {
+			apiServer.extender.handle.Compliance = compliance
+		}
+		apiServer.compliance = compliance
+		metrics.Register("node_compliance", compliance.writeMetrics)
+	}
+
+	// Dry Run Admission 초기화 (kubectl --dry-run=server 쓰기에 Controller Manager 어드미션 적용)
+	if features.Enabled(featureDryRunAdmission) {
+		dryRun := NewDryRunAdmission(logger, controllerMgr)
+		apiServer.dryRun = dryRun
+		metrics.Register("dry_run", dryRun.writeMetrics)
+	}
+
+	// Chain Health 초기화 (RPC 지연, 체크포인트/이벤트 지연, 가스 가격을 테넌트에 공개)
+	var chainHealth *ChainHealth
+	if features.Enabled(featureChainHealth) {
+		chainHealth = NewChainHealth(logger, suiIntegration)
+		suiIntegration.chainHealth = chainHealth
+		apiServer.chainHealth = chainHealth
+		metrics.Register("chain_health", chainHealth.writeMetrics)
+	}
+
+	// Observability Bundle 초기화 (워커/스테이크/큐 상태 지표, 내장 Grafana 대시보드 제공과 GRAFANA_URL 배포)
+	if features.Enabled(featureObservabilityBundle) {
+		observability := NewObservabilityBundle(logger, suiIntegration, k3sMgr.workerPool)
+		apiServer.observability = observability
+		metrics.Register("observability", observability.writeMetrics)
+	}
+
+	// Heartbeat Challenger 초기화 (하트비트로 서명 nonce와 메모리 의존 작업 증명 요구, 실패 시 건강 점수 감점과 슬래싱 검토)
+	var challenger *HeartbeatChallenger
+	if features.Enabled(featureHeartbeatChallenges) {
+		challenger, err = NewHeartbeatChallenger(logger)
+		if err != nil {
+			logger.Fatalf("❌ Invalid heartbeat challenge config: %v", err)
+		}
+		challenger.history = heartbeatHistory
+		challenger.maintenance = maintenanceScheduler
+		healthScorer.challenges = challenger
+		apiServer.challenges = challenger
+		metrics.Register("heartbeat_challenges", challenger.writeMetrics)
+	}
+
+	// Read Only Mode 초기화 (사고 대응용 클러스터 쓰기 동결, NAUTILUS_READ_ONLY 또는 관리자 API)
+	readOnly := NewReadOnlyMode(logger)
+	readOnly.stream = eventStream
+	suiIntegration.readOnly = readOnly
+	apiServer.readOnly = readOnly
+	metrics.Register("read_only", readOnly.writeMetrics)
+
+	// Token Scopes 초기화 (체인 발급/세션 교환 범위 제한 토큰, TOKEN_EXCHANGE_MAX_TTL_SECONDS)
+	tokenScopes := NewTokenScopes(logger, k3sMgr.workerPool)
+	suiIntegration.scopes = tokenScopes
+	apiServer.scopes = tokenScopes
+	apiServer.clientAPI.scopes = tokenScopes
+	metrics.Register("token_scopes", tokenScopes.writeMetrics)
+
+	// Tenant Usage Forecaster 초기화 (테넌트 네임스페이스 ResourceQuota 사용량 7일 추이와 소진 예측, TENANT_USAGE_SAMPLE_SECONDS)
+	var tenantUsage *TenantUsageForecaster
+	if features.Enabled(featureTenantUsage) {
+		tenantUsage = NewTenantUsageForecaster(logger, k3sMgr, tenantThrottler)
+		apiServer.tenantUsage = tenantUsage
+		metrics.Register("tenant_usage", tenantUsage.writeMetrics)
+	}
+
+	// Namespace Provisioner 초기화 (테넌트 첫 배포 시 네임스페이스 템플릿 적용, NAMESPACE_TEMPLATE_FILE)
+	if features.Enabled(featureNamespaceProvision) {
+		provisioner, err := NewNamespaceProvisioner(logger, k3sMgr, k3sMgr.workerPool)
+		if err != nil {
+			logger.Fatalf("❌ Invalid namespace provisioning config: %v", err)
+		}
+		provisioner.audit = auditLogger
+		provisioner.limits = controllerMgr.limits
+		suiIntegration.provisioner = provisioner
+		metrics.Register("namespace_provisioning", provisioner.writeMetrics)
+	}
+
+	// Network Probe 초기화 (워커 대역폭 측정 엔드포인트, 피어 선택, k3s-daas.io/bandwidth-mbps 라벨)
+	networkProbe := NewNetworkProbe(logger, k3sMgr.workerPool)
+	apiServer.network = networkProbe
+	metrics.Register("network_probe", networkProbe.writeMetrics)
+
+	// Pod Log Archive 초기화 (워커가 배송한 Pod 로그 보관/조회, NAUTILUS_POD_LOG_RETENTION_HOURS)
+	podLogs := NewPodLogArchive(logger, k3sMgr.workerPool)
+	podLogs.slo = sloTracker
+	apiServer.podLogs = podLogs
+	metrics.Register("pod_logs", podLogs.writeMetrics)
+
+	// Log Throttle Tracker 초기화 (워커가 로그를 제한한 Pod에 k3s-daas.io/LogThrottled 조건 표시)
+	logThrottle := NewLogThrottleTracker(logger, k3sMgr)
+	logThrottle.history = heartbeatHistory
+	apiServer.logThrottle = logThrottle
+	metrics.Register("log_throttling", logThrottle.writeMetrics)
+
+	// Pod Limit Tracker 초기화 (워커가 보고한 CPU 스로틀링/OOM kill을 k3s-daas.io/ResourceLimited 조건으로 표시)
+	podLimits := NewPodLimitTracker(logger, k3sMgr)
+	podLimits.history = heartbeatHistory
+	apiServer.podLimits = podLimits
+	metrics.Register("pod_limits", podLimits.writeMetrics)
+
+	// Network Meter 초기화 (워커 Pod 네트워크 카운터를 테넌트별 송수신 바이트로 집계, 사용량 기록에 포함)
+	networkMeter := NewNetworkMeter(logger, k3sMgr, tenantThrottler)
+	apiServer.netMeter = networkMeter
+	metrics.Register("network_metering", networkMeter.writeMetrics)
+
+	// Node Problem Tainter 초기화 (워커가 보고한 KernelDeadlock/OOMKilling/DiskPressure 노드에 taint)
+	problemTainter, err := NewNodeProblemTainter(logger, k3sMgr)
+	if err != nil {
+		logger.Fatalf("❌ Invalid node problem taint configuration: %v", err)
+	}
+	problemTainter.history = heartbeatHistory
+	apiServer.problems = problemTainter
+	metrics.Register("node_problems", problemTainter.writeMetrics)
+
+	// Heartbeat Archive 초기화 (오래된 하트비트를 시간 단위 요약으로 압축, 원본은 NAUTILUS_ARCHIVE_BACKEND에 보관)
+	heartbeatArchive, err := NewHeartbeatArchive(logger, heartbeatHistory)
+	if err != nil {
+		logger.Fatalf("❌ Invalid heartbeat archive config: %v", err)
+	}
+	heartbeatArchive.slo = sloTracker
+	heartbeatHistory.archive = heartbeatArchive
+	apiServer.historyArchive = heartbeatArchive
+	metrics.Register("heartbeat_archive", heartbeatArchive.writeMetrics)
+
+	// Data Retention 초기화 (NAUTILUS_RETENTION_*_HOURS 보관 정책, 테넌트 데이터 삭제와 서명된 삭제 증명)
+	retention := NewDataRetention(logger, k3sMgr, requestSigner)
+	retention.audit = auditLogger
+	retention.history = heartbeatHistory
+	retention.historyArchive = heartbeatArchive
+	retention.quota = tenantThrottler
+	retention.podLogs = podLogs
+	retention.rbac = rbac
+	retention.serviceAccounts = serviceAccounts
+	retention.slo = sloTracker
+	retention.trash = trashBin
+	apiServer.retention = retention
+	metrics.Register("data_retention", retention.writeMetrics)
+
+	// Dashboard 초기화 (/dashboard/, NAUTILUS_ADMIN_TOKEN으로 로그인)
+	apiServer.dashboard = NewDashboard(logger)
+
+	// Drainer 초기화 (SIGTERM 시 연결 드레인, NAUTILUS_STANDBY_URL로 상태 인계)
+	drainer := NewDrainer(logger, k3sMgr.workerPool)
+	apiServer.drain = drainer
+
+	// 시작 검증 (SKIP_STARTUP_SELF_TEST=true로 생략 가능)
+	if getEnvOrDefault("SKIP_STARTUP_SELF_TEST", "false") != "true" {
+		if err := NewSelfTest(logger, suiIntegration).Run(); err != nil {
+			logger.Fatalf("🛑 Startup self-test failed: %v", err)
+		}
+	}
+
+	// 이벤트 처리 전에 재시작 체크포인트 복원, 없으면 온체인 상태로 워커 풀 재구성 (실패 시 이벤트와 주기 검사로 복구)
+	restartCheckpoint.Restore()
 
 	// 컴포넌트 시작
 	go k3sMgr.Start(ctx)
 	go apiServer.Start(ctx)
-	go suiIntegration.Start(ctx)
+	go controllerMgr.Start(ctx)
+	go kubectlCompat.Start(ctx)
+	// 대기 마스터는 인계를 받은 뒤에만 이벤트 처리 (이중 처리 방지)
+	go func() {
+		if err := drainer.WaitForHandoff(ctx); err == nil {
+			go contractMigration.Start(ctx)
+			suiIntegration.Start(ctx)
+		}
+	}()
+	go capacityPublisher.Start(ctx)
+	go healthScorer.Start(ctx)
+	go maintenanceScheduler.Start(ctx)
+	go auditLogger.Start(ctx)
+	go chainOutbox.Start(ctx)
+	go clockGuard.Start(ctx)
+	go packagePin.Start(ctx)
+	go statusPage.Start(ctx)
+	go poolSync.Start(ctx)
+	go responseStore.Start(ctx)
+	go registryCache.Start(ctx)
+	go readinessGate.Start(ctx)
+	go eventWatchdog.Start(ctx)
+	go bootstrapMgr.Start(ctx)
+	go eventStream.Start(ctx)
+	go alertManager.Start(ctx)
+	go walletMonitor.Start(ctx)
+	go joinTokens.Start(ctx)
+	go podLogs.Start(ctx)
+	go heartbeatArchive.Start(ctx)
+	go retention.Start(ctx)
+	if federation != nil {
+		go federation.Start(ctx)
+	}
+	if trashBin != nil {
+		go trashBin.Start(ctx)
+	}
+	if abandonment != nil {
+		go abandonment.Start(ctx)
+	}
+	if reservations != nil {
+		go reservations.Start(ctx)
+	}
+	if chainHealth != nil {
+		go chainHealth.Start(ctx)
+	}
+	if challenger != nil {
+		go challenger.Start(ctx)
+	}
+	if tenantUsage != nil {
+		go tenantUsage.Start(ctx)
+	}
 
 	logger.Info("✅ All components started")
 
-	// 우아한 종료 대기
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 우아한 종료 대기 (SIGINT/SIGTERM 또는 Windows 서비스 중지 요청)
+	stopCtx, stop := service.NotifyContext(context.Background(), serviceName)
+	defer stop()
 
-	sig := <-sigChan
-	logger.Infof("🛑 Received signal %v, shutting down...", sig)
+	<-stopCtx.Done()
+	logger.Info("🛑 Shutdown requested, shutting down...")
 
+	// 진행 중 요청 완료 및 watch 북마크 저장 후 종료
+	drainer.Shutdown()
 	cancel()
+	// 다음 시작이 체인 전체 재구성 대신 변경분만 동기화하도록 상태 기록 (RestartCheckpoint 게이트)
+	if err := restartCheckpoint.Save(); err != nil {
+		logger.Errorf("❌ %v", err)
+	}
 	logger.Info("✅ Nautilus Control stopped")
-}
\ No newline at end of file
+}
@@ -0,0 +1,405 @@
+// Maintenance Windows - 스테이커의 예정된 정비 시간 동안 cordon/drain 및 liveness 패널티 유예
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 정비 상태 (scheduled → active → completed, 유예 시간이 지나면 목록에서 제거)
+const (
+	maintenanceScheduled = "scheduled"
+	maintenanceActive    = "active"
+	maintenanceCompleted = "completed"
+)
+
+const (
+	maintenanceMinDuration = 5 * time.Minute
+	maintenanceMaxDuration = 24 * time.Hour
+)
+
+// MaintenanceWindow - 노드 하나의 정비 시간
+type MaintenanceWindow struct {
+	NodeID      string    `json:"node_id"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Reason      string    `json:"reason,omitempty"`
+	Source      string    `json:"source"` // chain, api
+	RequestedBy string    `json:"requested_by,omitempty"`
+	State       string    `json:"state"`
+	Error       string    `json:"error,omitempty"`
+}
+
+/*
+MaintenanceScheduler - 온체인(maintenance::request_maintenance) 또는 마스터 API로 예약된 정비 시간 실행
+
+  - 시작 시각에 노드를 cordon 후 drain (DaemonSet Pod 제외, MAINTENANCE_DRAIN_TIMEOUT_SECONDS)
+  - 정비 시간과 종료 후 MAINTENANCE_GRACE_SECONDS 동안은 하트비트 클레임 검증/probation 등 liveness 패널티 유예
+  - 종료 시각에 자동 uncordon
+  - 마스터 재시작 중 종료된 정비도 다시 시작하면 uncordon되도록 상태 파일에 보관
+*/
+type MaintenanceScheduler struct {
+	logger       *logrus.Logger
+	k3sMgr       *K3sManager
+	workerPool   *WorkerPool
+	history      *HeartbeatHistory
+	stateFile    string
+	interval     time.Duration
+	drainTimeout time.Duration
+	grace        time.Duration
+	windows      map[string]*MaintenanceWindow
+	mutex        sync.RWMutex
+}
+
+// NewMaintenanceScheduler - 새 Maintenance Scheduler 생성 (저장된 정비 일정 복원)
+func NewMaintenanceScheduler(logger *logrus.Logger, k3sMgr *K3sManager) *MaintenanceScheduler {
+	m := &MaintenanceScheduler{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		workerPool:   k3sMgr.workerPool,
+		stateFile:    statePath("maintenance-windows.json"),
+		interval:     envSeconds("MAINTENANCE_CHECK_INTERVAL_SECONDS", 15),
+		drainTimeout: envSeconds("MAINTENANCE_DRAIN_TIMEOUT_SECONDS", 300),
+		grace:        envSeconds("MAINTENANCE_GRACE_SECONDS", 300),
+		windows:      make(map[string]*MaintenanceWindow),
+	}
+	if _, err := loadJSONState(m.stateFile, &m.windows); err != nil {
+		logger.Warnf("⚠️ Failed to load maintenance windows: %v", err)
+	}
+	return m
+}
+
+// Start - 주기적으로 정비 시작/종료 처리
+func (m *MaintenanceScheduler) Start(ctx context.Context) {
+	m.logger.Info("🛠️ Starting Maintenance Scheduler...")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if m.k3sMgr.IsRunning() {
+			m.reconcile(time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Schedule - 정비 시간 등록 (노드당 하나, 진행 중인 정비는 종료 시각만 갱신)
+func (m *MaintenanceScheduler) Schedule(window MaintenanceWindow) error {
+	duration := window.End.Sub(window.Start)
+	if duration < maintenanceMinDuration || duration > maintenanceMaxDuration {
+		return fmt.Errorf("maintenance window must be between %s and %s", maintenanceMinDuration, maintenanceMaxDuration)
+	}
+	if !window.End.After(time.Now()) {
+		return fmt.Errorf("maintenance window already ended at %s", window.End.Format(time.RFC3339))
+	}
+	if _, exists := m.workerPool.GetWorker(window.NodeID); !exists {
+		return fmt.Errorf("worker %s not found", window.NodeID)
+	}
+
+	m.mutex.Lock()
+	window.State = maintenanceScheduled
+	if existing := m.windows[window.NodeID]; existing != nil && existing.State == maintenanceActive {
+		window.State = maintenanceActive
+	}
+	m.windows[window.NodeID] = &window
+	m.saveLocked()
+	m.mutex.Unlock()
+
+	m.logger.Infof("🛠️ Maintenance scheduled for %s: %s ~ %s (%s, %s)", window.NodeID,
+		window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339), window.Source, window.Reason)
+	m.history.RecordEvent(window.NodeID, "maintenance_scheduled",
+		fmt.Sprintf("%s ~ %s via %s", window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339), window.Source))
+	return nil
+}
+
+// Cancel - 정비 취소 (진행 중이면 즉시 uncordon)
+func (m *MaintenanceScheduler) Cancel(nodeID, source string) error {
+	m.mutex.Lock()
+	window := m.windows[nodeID]
+	if window == nil {
+		m.mutex.Unlock()
+		return fmt.Errorf("no maintenance window for %s", nodeID)
+	}
+	delete(m.windows, nodeID)
+	m.saveLocked()
+	m.mutex.Unlock()
+
+	if window.State == maintenanceActive {
+		m.uncordon(nodeID)
+	}
+	m.logger.Infof("🛠️ Maintenance for %s cancelled via %s", nodeID, source)
+	m.history.RecordEvent(nodeID, "maintenance_cancelled", "via "+source)
+	return nil
+}
+
+// InMaintenance - 정비 중(종료 후 유예 포함)이면 liveness 패널티를 적용하지 않음 (nil이면 항상 false)
+func (m *MaintenanceScheduler) InMaintenance(nodeID string) bool {
+	if m == nil {
+		return false
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	window := m.windows[nodeID]
+	if window == nil {
+		return false
+	}
+	now := time.Now()
+	return !now.Before(window.Start) && now.Before(window.End.Add(m.grace))
+}
+
+// reconcile - 시작 시각이 된 정비는 cordon/drain, 종료된 정비는 uncordon
+func (m *MaintenanceScheduler) reconcile(now time.Time) {
+	m.mutex.RLock()
+	var starting, ending, expired []string
+	for nodeID, window := range m.windows {
+		switch {
+		case window.State != maintenanceCompleted && !now.Before(window.End):
+			ending = append(ending, nodeID)
+		case window.State == maintenanceScheduled && !now.Before(window.Start):
+			starting = append(starting, nodeID)
+		case window.State == maintenanceCompleted && !now.Before(window.End.Add(m.grace)):
+			expired = append(expired, nodeID)
+		}
+	}
+	m.mutex.RUnlock()
+	sort.Strings(starting)
+	sort.Strings(ending)
+
+	for _, nodeID := range starting {
+		m.begin(nodeID)
+	}
+	for _, nodeID := range ending {
+		m.uncordon(nodeID)
+		m.setState(nodeID, maintenanceCompleted, "")
+		m.logger.Infof("✅ Maintenance for %s ended, node uncordoned", nodeID)
+		m.history.RecordEvent(nodeID, "maintenance_ended", "uncordoned")
+	}
+
+	if len(expired) > 0 {
+		m.mutex.Lock()
+		for _, nodeID := range expired {
+			delete(m.windows, nodeID)
+		}
+		m.saveLocked()
+		m.mutex.Unlock()
+	}
+}
+
+// begin - cordon 후 drain (drain 실패해도 cordon 상태로 정비 진행)
+func (m *MaintenanceScheduler) begin(nodeID string) {
+	m.logger.Infof("🛠️ Maintenance for %s starting: cordon and drain", nodeID)
+
+	if _, err := m.k3sMgr.RunKubectl(nil, "cordon", nodeID); err != nil {
+		m.logger.Errorf("❌ Failed to cordon %s: %v", nodeID, err)
+		m.setState(nodeID, maintenanceScheduled, err.Error())
+		return
+	}
+
+	var drainErr string
+	_, err := m.k3sMgr.RunKubectl(nil, "drain", nodeID,
+		"--ignore-daemonsets", "--delete-emptydir-data",
+		"--timeout", m.drainTimeout.String())
+	if err != nil {
+		drainErr = err.Error()
+		m.logger.Warnf("⚠️ Drain of %s incomplete, node stays cordoned: %v", nodeID, err)
+	}
+
+	m.setState(nodeID, maintenanceActive, drainErr)
+	m.history.RecordEvent(nodeID, "maintenance_started", "cordoned and drained")
+}
+
+func (m *MaintenanceScheduler) uncordon(nodeID string) {
+	if _, err := m.k3sMgr.RunKubectl(nil, "uncordon", nodeID); err != nil {
+		m.logger.Errorf("❌ Failed to uncordon %s: %v", nodeID, err)
+	}
+}
+
+func (m *MaintenanceScheduler) setState(nodeID, state, errMsg string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if window := m.windows[nodeID]; window != nil {
+		window.State = state
+		window.Error = errMsg
+		m.saveLocked()
+	}
+}
+
+func (m *MaintenanceScheduler) saveLocked() {
+	if err := saveJSONState(m.stateFile, m.windows); err != nil {
+		m.logger.Warnf("⚠️ Failed to persist maintenance windows: %v", err)
+	}
+}
+
+// Windows - 노드 ID 순서의 정비 시간 복사본
+func (m *MaintenanceScheduler) Windows() []MaintenanceWindow {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	windows := make([]MaintenanceWindow, 0, len(m.windows))
+	for _, window := range m.windows {
+		windows = append(windows, *window)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].NodeID < windows[j].NodeID })
+	return windows
+}
+
+// handleChainEvent - maintenance 모듈 이벤트 반영 (소유자 확인은 컨트랙트가 수행)
+func (m *MaintenanceScheduler) handleChainEvent(event *SuiContractEvent) {
+	if m == nil {
+		return
+	}
+	nodeID, _ := event.EventData["node_id"].(string)
+	if nodeID == "" {
+		m.logger.Warnf("⚠️ Maintenance event without node_id: %s", event.Type)
+		return
+	}
+	owner, _ := event.EventData["owner"].(string)
+
+	if strings.Contains(event.Type, "MaintenanceCancelledEvent") {
+		if err := m.Cancel(nodeID, "chain"); err != nil {
+			m.logger.Debugf("Maintenance cancel event for %s ignored: %v", nodeID, err)
+		}
+		return
+	}
+
+	startMs, startErr := eventUint(event.EventData["start_ms"])
+	endMs, endErr := eventUint(event.EventData["end_ms"])
+	if startErr != nil || endErr != nil {
+		m.logger.Warnf("⚠️ Invalid maintenance window for %s: start=%v end=%v", nodeID, event.EventData["start_ms"], event.EventData["end_ms"])
+		return
+	}
+	reason, _ := event.EventData["reason"].(string)
+
+	if err := m.Schedule(MaintenanceWindow{
+		NodeID:      nodeID,
+		Start:       time.UnixMilli(int64(startMs)),
+		End:         time.UnixMilli(int64(endMs)),
+		Reason:      reason,
+		Source:      "chain",
+		RequestedBy: owner,
+	}); err != nil {
+		m.logger.Warnf("⚠️ Maintenance request for %s rejected: %v", nodeID, err)
+	}
+}
+
+// eventUint - parsedJson의 u64 값 (문자열 또는 숫자)
+func eventUint(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	case float64:
+		return uint64(v), nil
+	}
+	return 0, fmt.Errorf("unexpected value %v", value)
+}
+
+/*
+handleMaintenance - 정비 시간 조회/예약/취소 (/api/v1/nodes/maintenance)
+
+	GET                                            전체 정비 일정
+	POST   {"node_id", "start", "duration_seconds", "reason"}   예약 (X-Seal-Token: 워커 Seal 토큰, start 생략 시 즉시)
+	DELETE ?node_id=...                            취소 (X-Seal-Token 필요)
+*/
+func (m *MaintenanceScheduler) handleMaintenance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"windows": m.Windows(),
+		})
+
+	case http.MethodPost:
+		var request struct {
+			NodeID          string    `json:"node_id"`
+			Start           time.Time `json:"start"`
+			DurationSeconds int64     `json:"duration_seconds"`
+			Reason          string    `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid maintenance request", http.StatusBadRequest)
+			return
+		}
+		worker, ok := m.authorize(w, r, request.NodeID)
+		if !ok {
+			return
+		}
+		if request.Start.IsZero() {
+			request.Start = time.Now()
+		}
+		window := MaintenanceWindow{
+			NodeID:      request.NodeID,
+			Start:       request.Start,
+			End:         request.Start.Add(time.Duration(request.DurationSeconds) * time.Second),
+			Reason:      request.Reason,
+			Source:      "api",
+			RequestedBy: worker.WorkerAddress,
+		}
+		if err := m.Schedule(window); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "window": window})
+
+	case http.MethodDelete:
+		nodeID := r.URL.Query().Get("node_id")
+		if _, ok := m.authorize(w, r, nodeID); !ok {
+			return
+		}
+		if err := m.Cancel(nodeID, "api"); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorize - 하트비트와 같이 워커 Seal 토큰으로 요청자 확인
+func (m *MaintenanceScheduler) authorize(w http.ResponseWriter, r *http.Request, nodeID string) (*WorkerNode, bool) {
+	worker, exists := m.workerPool.GetWorker(nodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return nil, false
+	}
+	if worker.SealToken != "" && r.Header.Get("X-Seal-Token") != worker.SealToken {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return nil, false
+	}
+	return worker, true
+}
+
+// writeMetrics - 정비 상태 메트릭 출력
+func (m *MaintenanceScheduler) writeMetrics(w io.Writer) {
+	windows := m.Windows()
+	if len(windows) == 0 {
+		return
+	}
+
+	writeMetricHeader(w, "nautilus_node_maintenance", "gauge", "Maintenance window state per node (1 for the current state)")
+	for _, window := range windows {
+		writeMetric(w, "nautilus_node_maintenance", map[string]string{"node": window.NodeID, "state": window.State}, 1)
+	}
+}
@@ -0,0 +1,121 @@
+// Metrics - Prometheus 텍스트 포맷 메트릭 노출
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	sui "github.com/k3s-io/daas-sui"
+)
+
+// MetricsCollector - 컴포넌트별 메트릭 출력 함수
+type MetricsCollector func(w io.Writer)
+
+// MetricsRegistry - 메트릭 수집기 등록소
+type MetricsRegistry struct {
+	collectors map[string]MetricsCollector
+	mutex      sync.RWMutex
+}
+
+// NewMetricsRegistry - 새 Metrics Registry 생성
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		collectors: make(map[string]MetricsCollector),
+	}
+}
+
+// Register - 수집기 등록 (같은 이름이면 교체)
+func (m *MetricsRegistry) Register(name string, collector MetricsCollector) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.collectors[name] = collector
+}
+
+// ServeHTTP - /metrics 핸들러
+func (m *MetricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.writeAll(w)
+}
+
+// writeAll - 모든 수집기 출력 (이름 순)
+func (m *MetricsRegistry) writeAll(w io.Writer) {
+	m.mutex.RLock()
+	names := make([]string, 0, len(m.collectors))
+	for name := range m.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]MetricsCollector, len(names))
+	for i, name := range names {
+		collectors[i] = m.collectors[name]
+	}
+	m.mutex.RUnlock()
+
+	for _, collect := range collectors {
+		collect(w)
+	}
+}
+
+// writeMetricHeader - HELP/TYPE 헤더 출력
+func writeMetricHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// writeMetric - 라벨이 있는 단일 샘플 출력
+func writeMetric(w io.Writer, name string, labels map[string]string, value float64) {
+	if len(labels) == 0 {
+		fmt.Fprintf(w, "%s %g\n", name, value)
+		return
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", key, labels[key])
+	}
+	fmt.Fprintf(w, "%s{%s} %g\n", name, strings.Join(pairs, ","), value)
+}
+
+// suiClientCollector - 공용 Sui 클라이언트 RPC/캐시 메트릭
+func suiClientCollector(client *sui.SuiClient) MetricsCollector {
+	return func(w io.Writer) {
+		metrics := client.GetMetrics()
+
+		writeMetricHeader(w, "nautilus_sui_rpc_requests_total", "counter", "Sui RPC requests by outcome")
+		writeMetric(w, "nautilus_sui_rpc_requests_total", map[string]string{"outcome": "success"}, float64(metrics.SuccessCount))
+		writeMetric(w, "nautilus_sui_rpc_requests_total", map[string]string{"outcome": "error"}, float64(metrics.ErrorCount))
+
+		writeMetricHeader(w, "nautilus_sui_cache_requests_total", "counter", "Sui object cache lookups by result")
+		types := make([]string, 0, len(metrics.Cache.ByType))
+		for objectType := range metrics.Cache.ByType {
+			types = append(types, objectType)
+		}
+		sort.Strings(types)
+		for _, objectType := range types {
+			stats := metrics.Cache.ByType[objectType]
+			writeMetric(w, "nautilus_sui_cache_requests_total", map[string]string{"type": objectType, "result": "hit"}, float64(stats.Hits))
+			writeMetric(w, "nautilus_sui_cache_requests_total", map[string]string{"type": objectType, "result": "miss"}, float64(stats.Misses))
+		}
+	}
+}
+
+// httpPanicCollector - HTTP 핸들러 panic 복구 횟수
+func httpPanicCollector(w io.Writer) {
+	writeMetricHeader(w, "nautilus_http_panics_total", "counter", "HTTP handler panics recovered and answered with Status 500")
+	writeMetric(w, "nautilus_http_panics_total", nil, float64(httpserver.PanicCount()))
+}
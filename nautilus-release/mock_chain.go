@@ -0,0 +1,78 @@
+// Mock Chain - 체인 백엔드 선택, 오프라인 개발용 인메모리 mock 체인 호스팅
+package main
+
+import (
+	"os"
+
+	chain "github.com/k3s-io/daas-chain"
+	sui "github.com/k3s-io/daas-sui"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+openChainBackend - NAUTILUS_CHAIN_BACKEND로 체인 백엔드 선택
+
+  - 미설정 시 봉인된 Sui 마스터 키가 있으면 sui, 없으면 mock (기존 MOCK_MODE 분기 대체)
+  - mock은 마스터 프로세스 안에서 실행되고 /api/v1/mockchain/ 으로 노출되어,
+    워커(K3S_DAAS_CHAIN_BACKEND=mock)도 같은 체인에 스테이킹/등록/이의 신청을 제출
+    → 워커 등록 이벤트가 즉시 마스터로 전달되어 실제와 같은 흐름으로 동작
+  - NAUTILUS_MOCK_CHAIN_URL 설정 시 다른 프로세스가 호스팅하는 mock 체인에 연결
+  - NAUTILUS_CHAIN_DEFAULT_STAKE: 스테이킹 기록이 없는 노드에 적용할 기본 스테이크
+
+mock 체인을 직접 호스팅할 때만 두 번째 반환값(MockServer)이 설정됩니다.
+*/
+func openChainBackend(logger *logrus.Logger, client *sui.SuiClient, contractAddr string, hasKey bool) (chain.Backend, *chain.MockServer) {
+	name := os.Getenv("NAUTILUS_CHAIN_BACKEND")
+	if name == "" {
+		name = sui.BackendName
+		if !hasKey {
+			logger.Warn("⚠️ No sealed Sui master key (import PRIVATE_KEY once), using in-memory mock chain (set NAUTILUS_CHAIN_BACKEND=sui to force Sui)")
+			name = chain.MockBackendName
+		}
+	}
+	if name == sui.BackendName {
+		// 트랜잭션 서명은 sui CLI가 담당하므로 공용 클라이언트는 조회 전용
+		return sui.NewBackend(client, "", ""), nil
+	}
+
+	backend, err := chain.Open(name, chain.Config{
+		Endpoint: os.Getenv("NAUTILUS_MOCK_CHAIN_URL"),
+		Package:  contractAddr,
+		Options: map[string]string{
+			"default_stake": os.Getenv("NAUTILUS_CHAIN_DEFAULT_STAKE"),
+			"min_stake":     getEnvOrDefault("MIN_STAKE_AMOUNT", "1000000"), // 부분 인출 시 티어 최소치 기준
+		},
+	})
+	if err != nil {
+		logger.Fatalf("❌ Failed to open chain backend: %v", err)
+	}
+	logger.Infof("⛓️ Using %s chain backend", backend.Name())
+
+	mock, ok := backend.(*chain.MockBackend)
+	if !ok {
+		return backend, nil
+	}
+	server := chain.NewMockServer(mock)
+	server.AdminToken = os.Getenv("NAUTILUS_ADMIN_TOKEN")
+	logger.Info("🧪 Hosting mock chain at /api/v1/mockchain/ (objects and events are kept in memory)")
+	return backend, server
+}
+
+// onSui - Sui 전용 기능(체크포인트 확정성, 레지스트리 재구성, 체인 시각) 사용 가능 여부
+func (s *SuiIntegration) onSui() bool {
+	return s.backend.Name() == sui.BackendName
+}
+
+// suiMasterKey - 마스터 Sui 지갑 키 (PRIVATE_KEY 또는 NAUTILUS_KEY_IMPORT_DIR/sui-master.key를 가져와 봉인, 생성하지 않음)
+func suiMasterKey() enclaveKeySpec {
+	return enclaveKeySpec{
+		name:   "sui-master",
+		envVar: "PRIVATE_KEY",
+		parse:  parseSuiPrivateKey,
+	}
+}
+
+// canSubmit - 컨트랙트 호출 가능 여부 (Sui는 서명 키 필요, mock은 항상 가능)
+func (s *SuiIntegration) canSubmit() bool {
+	return !s.onSui() || s.chainKey != nil
+}
@@ -0,0 +1,240 @@
+// Namespace Provisioning - 테넌트가 처음 배포하는 네임스페이스를 템플릿(quota, LimitRange, NetworkPolicy)으로 자동 생성
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// namespaceExistsTTL - 존재를 확인한 네임스페이스를 다시 조회하지 않는 시간
+const namespaceExistsTTL = 5 * time.Minute
+
+// namespaceNamePattern - K8s 네임스페이스 이름 (DNS-1123 label)
+var namespaceNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// defaultNamespaceTemplate - NAMESPACE_TEMPLATE_FILE이 없을 때의 기본 템플릿 ({{.Namespace}}, {{.Tenant}})
+const defaultNamespaceTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{.Namespace}}
+  labels:
+    k3s-daas.io/tenant: "{{.Tenant}}"
+    k3s-daas.io/provisioned: "auto"
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: tenant-default
+  namespace: {{.Namespace}}
+spec:
+  hard:
+    requests.cpu: "4"
+    requests.memory: 8Gi
+    limits.cpu: "8"
+    limits.memory: 16Gi
+    pods: "50"
+    services: "10"
+    persistentvolumeclaims: "10"
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: tenant-default
+  namespace: {{.Namespace}}
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 500m
+      memory: 512Mi
+    defaultRequest:
+      cpu: 100m
+      memory: 128Mi
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: tenant-isolation
+  namespace: {{.Namespace}}
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector: {}
+`
+
+/*
+NamespaceProvisioner - 테넌트가 없는 네임스페이스에 처음 쓰기 요청을 보내면 네임스페이스를 만들어 줌
+
+컨트랙트 경로의 요청자(트랜잭션 서명 지갑)가 배정된 워커의 소유자이고 요청이 어드미션을 통과했을 때만 동작합니다.
+위임 권한으로 들어온 요청자나 조회 요청, 클러스터 범위 리소스, 예약된 네임스페이스(default, kube-*)는 대상이 아닙니다.
+템플릿은 text/template YAML로, 기본값은 테넌트 라벨이 붙은 네임스페이스와 ResourceQuota, LimitRange,
+같은 네임스페이스에서만 들어오는 트래픽을 허용하는 NetworkPolicy입니다. 생성 결과는 감사 로그에 남깁니다.
+
+환경 변수:
+  - NAMESPACE_TEMPLATE_FILE: 템플릿 파일 (없으면 defaultNamespaceTemplate)
+  - NAMESPACE_PROVISIONING_MAX_PER_TENANT: 테넌트당 자동 생성 네임스페이스 상한 (기본 10, 0이면 무제한)
+*/
+type NamespaceProvisioner struct {
+	logger       *logrus.Logger
+	k3sMgr       *K3sManager
+	workerPool   *WorkerPool
+	audit        *AuditLogger
+	limits       *LimitRangeAdmission // 어드미션이 캐시한 "LimitRange 없음"을 생성 후 비움
+	template     *template.Template
+	templateName string
+	maxPerTenant int
+
+	mutex       sync.Mutex // 같은 네임스페이스를 동시에 두 번 만들지 않도록 생성은 한 번에 하나
+	known       map[string]time.Time
+	provisioned uint64
+	failed      uint64
+	refused     uint64
+}
+
+// NewNamespaceProvisioner - 새 Namespace Provisioner 생성 (템플릿 파싱 오류는 시작 시 반환)
+func NewNamespaceProvisioner(logger *logrus.Logger, k3sMgr *K3sManager, workerPool *WorkerPool) (*NamespaceProvisioner, error) {
+	source, name := defaultNamespaceTemplate, "default"
+	if path := os.Getenv("NAMESPACE_TEMPLATE_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("NAMESPACE_TEMPLATE_FILE: %v", err)
+		}
+		source, name = string(data), path
+	}
+	parsed, err := template.New("namespace").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace template %s: %v", name, err)
+	}
+	maxPerTenant, err := strconv.Atoi(getEnvOrDefault("NAMESPACE_PROVISIONING_MAX_PER_TENANT", "10"))
+	if err != nil || maxPerTenant < 0 {
+		return nil, fmt.Errorf("invalid NAMESPACE_PROVISIONING_MAX_PER_TENANT")
+	}
+
+	return &NamespaceProvisioner{
+		logger:       logger,
+		k3sMgr:       k3sMgr,
+		workerPool:   workerPool,
+		template:     parsed,
+		templateName: name,
+		maxPerTenant: maxPerTenant,
+		known:        make(map[string]time.Time),
+	}, nil
+}
+
+// provisionTarget - 자동 생성 대상 요청인지 (네임스페이스 범위 리소스의 생성/수정)
+func provisionTarget(request *K8sAPIRequest) bool {
+	switch strings.ToUpper(request.Method) {
+	case "POST", "PUT", "PATCH":
+	default:
+		return false
+	}
+	namespace := request.Namespace
+	if namespace == "" || request.Resource == "namespaces" || clusterScopedResources[request.Resource] {
+		return false
+	}
+	if protectedNamespaces[namespace] || strings.HasPrefix(namespace, "kube-") {
+		return false
+	}
+	return len(namespace) <= 63 && namespaceNamePattern.MatchString(namespace)
+}
+
+/*
+Ensure - 요청 대상 네임스페이스가 없으면 템플릿으로 생성
+대상이 아니거나 이미 있으면 nil, 상한 초과는 Forbidden, 생성 실패는 InternalError로 요청을 실패시킵니다.
+*/
+func (p *NamespaceProvisioner) Ensure(request *K8sAPIRequest, assignedWorker string) error {
+	if p == nil || request.Requester == "" || !provisionTarget(request) {
+		return nil
+	}
+	worker, exists := p.workerPool.GetWorker(assignedWorker)
+	if !exists || !strings.EqualFold(worker.WorkerAddress, request.Requester) {
+		return nil
+	}
+	tenant := worker.WorkerAddress
+	namespace := request.Namespace
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if checkedAt, ok := p.known[namespace]; ok && time.Since(checkedAt) < namespaceExistsTTL {
+		return nil
+	}
+	output, err := p.k3sMgr.RunKubectl(nil, "get", "namespace", namespace, "--ignore-not-found", "-o", "name")
+	if err != nil {
+		// 확인할 수 없으면 만들지 않고 실행 단계에 맡김 (없으면 K3s가 NotFound로 응답)
+		p.logger.Warnf("⚠️ Could not check namespace %s before provisioning: %v", namespace, err)
+		return nil
+	}
+	if strings.TrimSpace(string(output)) != "" {
+		p.known[namespace] = time.Now()
+		return nil
+	}
+
+	if p.maxPerTenant > 0 {
+		owned, err := p.k3sMgr.RunKubectl(nil, "get", "namespaces", "-l", tenantLabel+"="+tenant, "-o", "name")
+		if err != nil {
+			p.logger.Warnf("⚠️ Could not count namespaces of %s: %v", tenant, err)
+			return nil
+		}
+		if count := len(strings.Fields(string(owned))); count >= p.maxPerTenant {
+			p.refused++
+			return fmt.Errorf("Forbidden: namespace %s does not exist and tenant %s already has %d namespaces (automatic provisioning limit)",
+				namespace, tenant, count)
+		}
+	}
+
+	startedAt := time.Now()
+	objects, err := p.apply(namespace, tenant)
+	p.audit.RecordNamespaceProvisioning(request, tenant, objects, err, startedAt)
+	if err != nil {
+		p.failed++
+		p.logger.Errorf("❌ Failed to provision namespace %s for %s: %v", namespace, tenant, err)
+		return fmt.Errorf("InternalError: failed to provision namespace %s: %v", namespace, err)
+	}
+	p.provisioned++
+	p.known[namespace] = time.Now()
+	if p.limits != nil {
+		p.limits.forget(namespace)
+	}
+	p.logger.Infof("🏗️ Provisioned namespace %s for %s on first %s %s (%s)",
+		namespace, tenant, request.Method, request.Resource, strings.Join(objects, ", "))
+	return nil
+}
+
+// apply - 템플릿을 렌더링해 적용하고 만든 오브젝트 목록 반환 (kind/name)
+func (p *NamespaceProvisioner) apply(namespace, tenant string) ([]string, error) {
+	var manifest bytes.Buffer
+	if err := p.template.Execute(&manifest, struct{ Namespace, Tenant string }{namespace, tenant}); err != nil {
+		return nil, fmt.Errorf("render template %s: %v", p.templateName, err)
+	}
+	output, err := p.k3sMgr.RunKubectl(manifest.Bytes(), "apply", "-f", "-", "-o", "name")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// writeMetrics - 자동 생성, 실패, 상한 초과로 거부한 수
+func (p *NamespaceProvisioner) writeMetrics(w io.Writer) {
+	p.mutex.Lock()
+	provisioned, failed, refused := p.provisioned, p.failed, p.refused
+	p.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_namespace_provisioning_total", "counter", "Tenant namespaces created automatically on first deploy, by result")
+	writeMetric(w, "nautilus_namespace_provisioning_total", map[string]string{"result": "provisioned"}, float64(provisioned))
+	writeMetric(w, "nautilus_namespace_provisioning_total", map[string]string{"result": "failed"}, float64(failed))
+	writeMetric(w, "nautilus_namespace_provisioning_total", map[string]string{"result": "limit_exceeded"}, float64(refused))
+}
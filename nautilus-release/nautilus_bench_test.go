@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k3s-io/daas-signing"
+	"github.com/sirupsen/logrus"
+)
+
+// Nautilus 마스터 요청 경로 벤치마크 (Seal 검증, 컨트랙트 이벤트 수신, 서명 프록시)
+// 예산은 api-proxy/perf/bench-budget.txt, 실행은 scripts/perf-check.sh 참고
+
+func benchLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func BenchmarkSealTokenValidation(b *testing.B) {
+	stm := NewSealTokenManager(benchLogger())
+	token := strings.Repeat("0123456789abcdef", 4)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !stm.ValidateSealToken(token, "worker-01") {
+			b.Fatal("seal token rejected")
+		}
+	}
+}
+
+// BenchmarkEventIngestion - WebSocket 메시지 디코딩부터 이벤트 처리까지
+func BenchmarkEventIngestion(b *testing.B) {
+	s := &SuiIntegration{
+		logger:       benchLogger(),
+		contractAddr: "0xbench",
+		eventChan:    make(chan *SuiContractEvent, 1),
+	}
+
+	var message suiEventMessage
+	raw := `{"jsonrpc":"2.0","method":"suix_subscribeEvent","params":{"subscription":1,"result":{` +
+		`"type":"0xbench::worker_registry::AttestationVerifiedEvent","packageId":"0xbench",` +
+		`"sender":"0x1","transactionDigest":"9xDigest","timestampMs":"1700000000000",` +
+		`"parsedJson":{"verifier_node_id":"worker-01","target":"nautilus-master",` +
+		`"measurement":"pcr0:abcd","verdict":"valid","epoch":"42"}}}}`
+	if err := json.Unmarshal([]byte(raw), &message); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.handleWebSocketMessage(&message)
+		s.processEvent(context.Background(), <-s.eventChan)
+	}
+}
+
+// BenchmarkSignedProxy - Gateway 서명 검증, 응답 버퍼링 및 서명
+func BenchmarkSignedProxy(b *testing.B) {
+	gatewayPub, gatewayKey, _ := ed25519.GenerateKey(nil)
+	_, signingKey, _ := ed25519.GenerateKey(nil)
+	signer := &RequestSigner{
+		logger:     benchLogger(),
+		verifier:   signing.NewVerifier(gatewayPub),
+		signingKey: &enclaveKey{key: signingKey},
+	}
+
+	var items []string
+	for i := 0; i < 100; i++ {
+		items = append(items, fmt.Sprintf(`{"metadata":{"name":"web-%05d","namespace":"default"}}`, i))
+	}
+	list := []byte(`{"apiVersion":"v1","kind":"PodList","items":[` + strings.Join(items, ",") + `]}`)
+	handler := signer.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(list)
+	}))
+
+	digest := sha256.Sum256(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", bytes.NewReader(nil))
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := strconv.Itoa(i)
+		payload := strings.Join([]string{req.Method, req.URL.RequestURI(), timestamp, nonce, hex.EncodeToString(digest[:])}, "\n")
+		req.Header.Set(signatureTimestampHeader, timestamp)
+		req.Header.Set(signatureNonceHeader, nonce)
+		req.Header.Set(signatureHeader, hex.EncodeToString(ed25519.Sign(gatewayKey, []byte(payload))))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			b.Fatalf("unexpected status %d", rec.Code)
+		}
+	}
+}
+
+// BenchmarkEventDecode - WebSocket 요청 이벤트 디코딩 (map은 이전 방식)
+func BenchmarkEventDecode(b *testing.B) {
+	s := &SuiIntegration{logger: benchLogger(), contractAddr: "0xbench"}
+	raw := []byte(`{"jsonrpc":"2.0","method":"suix_subscribeEvent","params":{"subscription":1,"result":{` +
+		`"id":{"txDigest":"9xDigest","eventSeq":"0"},"packageId":"0xbench","transactionModule":"k8s_gateway",` +
+		`"sender":"0x1","type":"0xbench::k8s_gateway::K8sAPIRequestScheduledEvent","transactionDigest":"9xDigest",` +
+		`"timestampMs":"1700000000000","bcs":"","parsedJson":{"request_id":"req-00042","method":"GET",` +
+		`"resource":"pods","namespace":"default","name":"","payload":"","seal_token":"` + strings.Repeat("ab", 32) + `",` +
+		`"requester":"0x1","priority":5,"timestamp":"1700000000000","assigned_worker":"worker-01"}}}}`)
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var message map[string]interface{}
+			if err := json.Unmarshal(raw, &message); err != nil {
+				b.Fatal(err)
+			}
+			params, _ := message["params"].(map[string]interface{})
+			if result, _ := params["result"].(map[string]interface{}); result["type"] == nil {
+				b.Fatal("missing event")
+			}
+		}
+	})
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var message suiEventMessage
+			if err := json.Unmarshal(raw, &message); err != nil {
+				b.Fatal(err)
+			}
+			if s.parseEventFromAPI(message.Params.Result) == nil {
+				b.Fatal("event filtered")
+			}
+		}
+	})
+}
+
+// BenchmarkAdmit - Deployment 생성 요청의 어드미션 (LimitRange 기본값, 위치 제약, 이미지 플랫폼 고정이 모두 적용됨)
+func BenchmarkAdmit(b *testing.B) {
+	logger := benchLogger()
+	k3sMgr := NewK3sManager(logger)
+	k3sMgr.workerPool.AddWorker(&WorkerNode{NodeID: "worker-01", Status: "active", OS: "linux", Arch: "amd64"})
+	cm := NewControllerManager(logger, k3sMgr)
+	// 조회 결과는 캐시에 미리 채워 kubectl/레지스트리 호출 없이 측정
+	cm.limits.cache["default"] = limitRangeEntry{
+		items:     []limitRangeItem{{Type: "Container", Default: map[string]string{"cpu": "500m", "memory": "256Mi"}, DefaultRequest: map[string]string{"cpu": "100m", "memory": "128Mi"}}},
+		fetchedAt: time.Now().Add(time.Hour),
+	}
+	for _, image := range []string{"nginx:1.25", "busybox:1.36"} {
+		cm.platforms.cache[image] = imagePlatformEntry{
+			platforms: map[string]string{"linux/amd64": "sha256:" + strings.Repeat("a", 64), "linux/arm64": "sha256:" + strings.Repeat("b", 64)},
+			index:     true,
+			fetched:   time.Now().Add(time.Hour),
+		}
+	}
+	payload := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default",` +
+		`"labels":{"app":"web"},"annotations":{"k3s-daas.io/zone-affinity":"a"}},"spec":{"replicas":3,` +
+		`"selector":{"matchLabels":{"app":"web"}},"template":{"metadata":{"labels":{"app":"web"}},"spec":{"containers":[` +
+		`{"name":"web","image":"nginx:1.25","ports":[{"containerPort":80}]},` +
+		`{"name":"sidecar","image":"busybox:1.36","command":["sh","-c","sleep infinity"]}]}}}}`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		request := &K8sAPIRequest{Method: "POST", Resource: "deployments", Namespace: "default", Payload: payload}
+		if err := cm.Admit(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
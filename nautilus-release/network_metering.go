@@ -0,0 +1,166 @@
+// Network Metering - 워커가 보고한 Pod별 네트워크 카운터를 테넌트 지갑별 송수신 바이트로 집계
+package main
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// namespaceTenantRefresh - 네임스페이스 → 테넌트 매핑을 다시 읽는 주기
+const namespaceTenantRefresh = time.Minute
+
+// PodNetworkUsage - 워커 하트비트 pod_network 항목 (샌드박스 네트워크 네임스페이스의 누적 카운터)
+type PodNetworkUsage struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	UID       string `json:"uid"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	TxBytes   uint64 `json:"tx_bytes"`
+}
+
+// podNetworkCounter - Pod별 마지막으로 본 누적 카운터
+type podNetworkCounter struct {
+	rx uint64
+	tx uint64
+}
+
+/*
+NetworkMeter - Pod 네트워크 카운터의 증가분을 테넌트 사용량(TenantThrottler)에 누적
+워커는 누적값을 보내므로 Pod UID별 직전 값과의 차이만 더합니다. 값이 줄면 샌드박스가 다시 만들어진
+것으로 보고 현재 값을 증가분으로 씁니다. 마스터 재시작 후 처음 보는 노드의 Pod는 기준값만 잡아
+이미 집계된 바이트를 다시 더하지 않습니다.
+테넌트는 네임스페이스의 k3s-daas.io/tenant 라벨로 정하며, 라벨이 없는 네임스페이스는 미귀속으로 따로 셉니다.
+*/
+type NetworkMeter struct {
+	logger *logrus.Logger
+	k3sMgr *K3sManager
+	quota  *TenantThrottler
+
+	mutex     sync.Mutex
+	nodes     map[string]map[string]podNetworkCounter // 노드 → Pod UID → 직전 카운터
+	tenants   map[string]string                       // 네임스페이스 → 테넌트 지갑
+	tenantsAt time.Time
+
+	unattributedTx uint64
+	unattributedRx uint64
+	resets         int
+	lookupFailures int
+}
+
+// NewNetworkMeter - 네트워크 계량기 생성
+func NewNetworkMeter(logger *logrus.Logger, k3sMgr *K3sManager, quota *TenantThrottler) *NetworkMeter {
+	return &NetworkMeter{
+		logger:  logger,
+		k3sMgr:  k3sMgr,
+		quota:   quota,
+		nodes:   make(map[string]map[string]podNetworkCounter),
+		tenants: make(map[string]string),
+	}
+}
+
+// Observe - 하트비트의 Pod 카운터 반영 (목록에서 빠진 Pod는 잊음)
+func (m *NetworkMeter) Observe(nodeID string, usage []PodNetworkUsage) {
+	type traffic struct{ tx, rx uint64 }
+	byNamespace := make(map[string]traffic)
+
+	m.mutex.Lock()
+	previous, known := m.nodes[nodeID]
+	current := make(map[string]podNetworkCounter, len(usage))
+	for _, pod := range usage {
+		if pod.UID == "" {
+			continue
+		}
+		counter := podNetworkCounter{rx: pod.RxBytes, tx: pod.TxBytes}
+		current[pod.UID] = counter
+
+		last, seen := previous[pod.UID]
+		if !seen && !known {
+			continue // 재시작 후 첫 보고는 기준값만
+		}
+		if counter.rx < last.rx || counter.tx < last.tx {
+			m.resets++
+			last = podNetworkCounter{}
+		}
+		delta := byNamespace[pod.Namespace]
+		delta.tx += counter.tx - last.tx
+		delta.rx += counter.rx - last.rx
+		byNamespace[pod.Namespace] = delta
+	}
+	m.nodes[nodeID] = current
+	m.mutex.Unlock()
+
+	if len(byNamespace) == 0 {
+		return
+	}
+	tenants := m.namespaceTenants()
+	for namespace, delta := range byNamespace {
+		if delta.tx == 0 && delta.rx == 0 {
+			continue
+		}
+		tenant := tenants[namespace]
+		if tenant == "" || m.quota == nil {
+			m.mutex.Lock()
+			m.unattributedTx += delta.tx
+			m.unattributedRx += delta.rx
+			m.mutex.Unlock()
+			continue
+		}
+		m.quota.AddNetwork(tenant, delta.tx, delta.rx)
+	}
+}
+
+// namespaceTenants - 테넌트 라벨이 붙은 네임스페이스 매핑 (주기마다 갱신, 실패 시 이전 값 유지)
+func (m *NetworkMeter) namespaceTenants() map[string]string {
+	m.mutex.Lock()
+	if time.Since(m.tenantsAt) < namespaceTenantRefresh || m.k3sMgr == nil || !m.k3sMgr.IsRunning() {
+		tenants := m.tenants
+		m.mutex.Unlock()
+		return tenants
+	}
+	m.tenantsAt = time.Now()
+	m.mutex.Unlock()
+
+	output, err := m.k3sMgr.RunKubectl(nil, "get", "namespaces", "-l", tenantLabel,
+		"-o", `jsonpath={range .items[*]}{.metadata.name}{"="}{.metadata.labels.k3s-daas\.io/tenant}{"\n"}{end}`)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err != nil {
+		m.lookupFailures++
+		m.logger.Warnf("⚠️ Failed to list tenant namespaces for network metering: %v", err)
+		return m.tenants
+	}
+	tenants := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if namespace, tenant, ok := strings.Cut(line, "="); ok && tenant != "" {
+			tenants[namespace] = tenant
+		}
+	}
+	m.tenants = tenants
+	return tenants
+}
+
+// writeMetrics - 계량 중인 Pod 수, 테넌트에 귀속되지 않은 바이트, 카운터 리셋
+func (m *NetworkMeter) writeMetrics(w io.Writer) {
+	m.mutex.Lock()
+	pods := 0
+	for _, counters := range m.nodes {
+		pods += len(counters)
+	}
+	tx, rx, resets, failures := m.unattributedTx, m.unattributedRx, m.resets, m.lookupFailures
+	m.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_network_metered_pods", "gauge", "Pods whose network counters are being metered")
+	writeMetric(w, "nautilus_network_metered_pods", nil, float64(pods))
+	writeMetricHeader(w, "nautilus_network_unattributed_bytes_total", "counter", "Pod network bytes in namespaces without a tenant label")
+	writeMetric(w, "nautilus_network_unattributed_bytes_total", map[string]string{"direction": "egress"}, float64(tx))
+	writeMetric(w, "nautilus_network_unattributed_bytes_total", map[string]string{"direction": "ingress"}, float64(rx))
+	writeMetricHeader(w, "nautilus_network_counter_resets_total", "counter", "Pod network counters that went backwards (sandbox recreated)")
+	writeMetric(w, "nautilus_network_counter_resets_total", nil, float64(resets))
+	writeMetricHeader(w, "nautilus_network_tenant_lookup_failures_total", "counter", "Failed namespace to tenant lookups")
+	writeMetric(w, "nautilus_network_tenant_lookup_failures_total", nil, float64(failures))
+}
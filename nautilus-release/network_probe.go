@@ -0,0 +1,289 @@
+// Network Probe - 워커 대역폭/지연 측정 지원 (마스터 측 전송 엔드포인트, 피어 선택, 노드 라벨/어노테이션)
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+NetworkProbe - 네트워크를 많이 쓰는 워크로드가 약한 링크의 노드에 배치되지 않도록 워커 링크 품질 측정
+
+  - 워커는 주기적으로 /api/v1/nodes/bandwidth-probe에서 내려받고(GET) 올려서(POST) 마스터와의 대역폭 측정
+  - 하트비트 응답의 probe_peers로 받은 다른 워커(같은 리전 우선)에 지연/대역폭 측정
+  - 결과는 다음 하트비트의 network 섹션으로 보고 → 노드 라벨 k3s-daas.io/bandwidth-mbps, k3s-daas.io/upload-mbps
+    (10Mbps 단위 내림)와 상세 결과 어노테이션 k3s-daas.io/network-probe
+  - Pod 어노테이션 k3s-daas.io/min-bandwidth-mbps, k3s-daas.io/min-upload-mbps는 Topology Scheduler가 nodeAffinity로 변환
+*/
+
+const (
+	bandwidthLabel        = "k3s-daas.io/bandwidth-mbps"
+	uploadBandwidthLabel  = "k3s-daas.io/upload-mbps"
+	networkProbeAnnot     = "k3s-daas.io/network-probe"
+	minBandwidthAnnot     = "k3s-daas.io/min-bandwidth-mbps"
+	minUploadAnnot        = "k3s-daas.io/min-upload-mbps"
+	defaultProbeBytes     = 2 << 20  // 2MiB
+	maxProbeBytes         = 32 << 20 // 요청 하나가 마스터 대역폭을 오래 점유하지 않도록
+	probeChunkSize        = 64 << 10
+	bandwidthBucketMbps   = 10
+	defaultProbePeerCount = 3
+)
+
+// PeerProbe - 워커가 다른 워커에 측정한 결과
+type PeerProbe struct {
+	NodeID string  `json:"node_id"`
+	RTTMs  int64   `json:"rtt_ms"`
+	Mbps   float64 `json:"mbps"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// NetworkReport - 워커 하트비트 network 섹션 (마지막 측정 결과)
+type NetworkReport struct {
+	DownloadMbps float64     `json:"download_mbps"` // 마스터 → 워커
+	UploadMbps   float64     `json:"upload_mbps"`   // 워커 → 마스터
+	Peers        []PeerProbe `json:"peers,omitempty"`
+	MeasuredAt   time.Time   `json:"measured_at"`
+}
+
+// ProbePeer - 하트비트 응답으로 전달하는 측정 대상 워커
+type ProbePeer struct {
+	NodeID string `json:"node_id"`
+	URL    string `json:"url"`   // 워커 상태 서버 주소 (http(s)://host:port)
+	Token  string `json:"token"` // 대상 워커가 발급한 측정 토큰 (X-Probe-Token)
+}
+
+// bandwidthBucket - 라벨 값 (10Mbps 단위 내림)
+func bandwidthBucket(mbps float64) int {
+	if mbps <= 0 {
+		return 0
+	}
+	return int(mbps) / bandwidthBucketMbps * bandwidthBucketMbps
+}
+
+// NetworkProbe - 측정 엔드포인트와 피어 선택
+type NetworkProbe struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	peerCount  int
+	payload    []byte // 압축되지 않는 무작위 데이터 (반복 전송)
+
+	mutex    sync.Mutex
+	sent     int64 // 다운로드 측정으로 보낸 바이트
+	received int64 // 업로드 측정으로 받은 바이트
+	rotation int   // 하트비트마다 다른 피어를 고르기 위한 오프셋
+}
+
+// NewNetworkProbe - NAUTILUS_NETWORK_PROBE_PEERS(기본 3)개 피어를 고르는 측정기 생성
+func NewNetworkProbe(logger *logrus.Logger, workerPool *WorkerPool) *NetworkProbe {
+	payload := make([]byte, probeChunkSize)
+	rand.Read(payload)
+	return &NetworkProbe{
+		logger:     logger,
+		workerPool: workerPool,
+		peerCount:  int(envFloat("NAUTILUS_NETWORK_PROBE_PEERS", defaultProbePeerCount)),
+		payload:    payload,
+	}
+}
+
+// handleBandwidthProbe - GET은 bytes만큼 내려보내고, POST는 본문을 끝까지 읽어 받은 양과 시간 응답
+func (p *NetworkProbe) handleBandwidthProbe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nodeID := r.URL.Query().Get("node_id")
+	worker, exists := p.workerPool.GetWorker(nodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Seal-Token")), []byte(worker.SealToken)) != 1 {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		start := time.Now()
+		received, err := io.Copy(io.Discard, io.LimitReader(r.Body, maxProbeBytes))
+		if err != nil {
+			http.Error(w, "Failed to read probe payload", http.StatusBadRequest)
+			return
+		}
+		elapsed := time.Since(start)
+
+		p.mutex.Lock()
+		p.received += received
+		p.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"bytes":       received,
+			"duration_ms": elapsed.Milliseconds(),
+		})
+		return
+	}
+
+	size := int64(defaultProbeBytes)
+	if value := r.URL.Query().Get("bytes"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid bytes", http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+	if size > maxProbeBytes {
+		size = maxProbeBytes
+	}
+
+	// Compress 미들웨어가 건너뛰도록 identity로 표시 (gzip은 측정값을 왜곡)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Encoding", "identity")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	var written int64
+	for written < size {
+		chunk := p.payload
+		if remaining := size - written; remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+		n, err := w.Write(chunk)
+		written += int64(n)
+		if err != nil {
+			break
+		}
+	}
+
+	p.mutex.Lock()
+	p.sent += written
+	p.mutex.Unlock()
+}
+
+// PeersFor - 워커가 측정할 피어 (같은 리전 먼저, 하트비트마다 돌아가며 선택)
+func (p *NetworkProbe) PeersFor(nodeID string) []ProbePeer {
+	if p.peerCount <= 0 {
+		return nil
+	}
+	self, exists := p.workerPool.GetWorker(nodeID)
+	if !exists {
+		return nil
+	}
+
+	var local, remote []ProbePeer
+	for _, worker := range p.workerPool.ListWorkers() {
+		if worker.NodeID == nodeID || worker.Status != "active" || worker.ProbeURL == "" || worker.ProbeToken == "" {
+			continue
+		}
+		peer := ProbePeer{NodeID: worker.NodeID, URL: worker.ProbeURL, Token: worker.ProbeToken}
+		if self.Region != "" && worker.Region == self.Region {
+			local = append(local, peer)
+		} else {
+			remote = append(remote, peer)
+		}
+	}
+	byNode := func(peers []ProbePeer) {
+		sort.Slice(peers, func(i, j int) bool { return peers[i].NodeID < peers[j].NodeID })
+	}
+	byNode(local)
+	byNode(remote)
+
+	p.mutex.Lock()
+	p.rotation++
+	offset := p.rotation
+	p.mutex.Unlock()
+
+	// 같은 리전에서 최대 peerCount-1개, 나머지 한 자리는 다른 리전 (리전 간 링크도 측정)
+	var selected []ProbePeer
+	take := func(peers []ProbePeer, count int) {
+		for i := 0; i < len(peers) && count > 0; i++ {
+			selected = append(selected, peers[(offset+i)%len(peers)])
+			count--
+		}
+	}
+	localCount := p.peerCount
+	if len(remote) > 0 && localCount > 1 {
+		localCount--
+	}
+	take(local, localCount)
+	take(remote, p.peerCount-len(selected))
+	return selected
+}
+
+// probeURL - 워커가 보고한 상태 서버 주소, 호스트가 없으면 하트비트 요청의 원격 주소로 채움
+func probeURL(reported, remoteAddr string) string {
+	if reported == "" {
+		return ""
+	}
+	u, err := url.Parse(reported)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Port() == "" {
+		return ""
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		if host, _, err = net.SplitHostPort(remoteAddr); err != nil {
+			return ""
+		}
+	}
+	return u.Scheme + "://" + net.JoinHostPort(host, u.Port())
+}
+
+// networkAnnotation - 노드 어노테이션 값 (마지막 측정 결과 JSON)
+func networkAnnotation(report *NetworkReport) (string, error) {
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode network probe: %v", err)
+	}
+	return string(encoded), nil
+}
+
+// writeMetrics - 워커별 측정 대역폭/피어 지연, 측정 트래픽
+func (p *NetworkProbe) writeMetrics(w io.Writer) {
+	workers := p.workerPool.ListWorkers()
+	sort.Slice(workers, func(i, j int) bool { return workers[i].NodeID < workers[j].NodeID })
+
+	writeMetricHeader(w, "nautilus_worker_bandwidth_mbps", "gauge", "Last measured bandwidth between each worker and the master")
+	for _, worker := range workers {
+		if report := worker.Network; report != nil {
+			writeMetric(w, "nautilus_worker_bandwidth_mbps", map[string]string{"node": worker.NodeID, "direction": "download"}, report.DownloadMbps)
+			writeMetric(w, "nautilus_worker_bandwidth_mbps", map[string]string{"node": worker.NodeID, "direction": "upload"}, report.UploadMbps)
+		}
+	}
+	for _, metric := range []struct {
+		name, help string
+		value      func(PeerProbe) float64
+	}{
+		{"nautilus_worker_peer_rtt_ms", "Last measured round trip time between two workers", func(peer PeerProbe) float64 { return float64(peer.RTTMs) }},
+		{"nautilus_worker_peer_bandwidth_mbps", "Last measured bandwidth between two workers", func(peer PeerProbe) float64 { return peer.Mbps }},
+	} {
+		writeMetricHeader(w, metric.name, "gauge", metric.help)
+		for _, worker := range workers {
+			if worker.Network == nil {
+				continue
+			}
+			for _, peer := range worker.Network.Peers {
+				if peer.Error == "" {
+					writeMetric(w, metric.name, map[string]string{"node": worker.NodeID, "peer": peer.NodeID}, metric.value(peer))
+				}
+			}
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	writeMetricHeader(w, "nautilus_network_probe_bytes_total", "counter", "Bytes transferred for worker bandwidth probes")
+	writeMetric(w, "nautilus_network_probe_bytes_total", map[string]string{"direction": "download"}, float64(p.sent))
+	writeMetric(w, "nautilus_network_probe_bytes_total", map[string]string{"direction": "upload"}, float64(p.received))
+}
@@ -0,0 +1,375 @@
+// Node Abandonment - 오래 하트비트가 없는 노드를 포기(abandoned)로 표시하고 스테이커의 비상 인출용 미참여 증명 서명
+//
+// 노드가 NAUTILUS_ABANDON_AFTER_HOURS(기본 168시간, 컨트랙트 최소 부재 기간과 같음) 동안 하트비트를 보내지 않으면
+// 포기로 표시합니다. 소유자는 GET /api/v1/nodes/abandoned/attestation으로 마스터 서명 키가 서명한 미참여 증명을 받아
+// worker_registry::emergency_unstake를 호출해 등록을 해제하고 보관소의 스테이킹을 돌려받습니다.
+// 컨트랙트는 master_registry에 공지된 이 리전 마스터의 공개키로 서명을 확인하고, 체인 하트비트가 증명보다
+// 최근이면 거부합니다. 포기로 표시된 노드가 다시 하트비트를 보내면 표시를 해제합니다.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// abandonmentDomain - 컨트랙트 ABANDONMENT_DOMAIN과 같아야 함
+	abandonmentDomain = "daas-node-abandonment-v1"
+	// abandonmentMinAbsence - 컨트랙트 ABANDONMENT_MIN_ABSENCE_MS (이보다 짧은 설정은 체인에서 거부됨)
+	abandonmentMinAbsence = 7 * 24 * time.Hour
+	// abandonmentSaveInterval - 하트비트로 갱신한 마지막 확인 시각을 저장하는 최소 간격
+	abandonmentSaveInterval = 5 * time.Minute
+)
+
+// AbandonedNode - 포기로 표시된 노드
+type AbandonedNode struct {
+	NodeID      string    `json:"node_id"`
+	Owner       string    `json:"owner"`
+	Role        string    `json:"role,omitempty"`
+	StakeAmount uint64    `json:"stake_amount"`
+	LastSeen    time.Time `json:"last_seen"`
+	AbandonedAt time.Time `json:"abandoned_at"`
+}
+
+// AbandonmentAttestation - emergency_unstake 인자와 서명된 미참여 증명
+type AbandonmentAttestation struct {
+	NodeID           string `json:"node_id"`
+	Owner            string `json:"owner"`
+	PackageID        string `json:"package_id"`
+	RegistryID       string `json:"registry_id"`
+	MasterRegistryID string `json:"master_registry_id"`
+	Region           string `json:"region"`
+	LastSeenMs       uint64 `json:"last_seen_ms"`
+	AbandonedAtMs    uint64 `json:"abandoned_at_ms"`
+	ExpiresAtMs      uint64 `json:"expires_at_ms"`
+	Statement        string `json:"statement"` // 서명한 BCS 바이트 (hex)
+	Signature        string `json:"signature"` // ed25519 (hex)
+	PublicKey        string `json:"public_key"`
+}
+
+// abandonmentState - 저장되는 상태 (재시작 시 풀 복원 시각을 하트비트로 오인하지 않도록 마지막 확인 시각 보관)
+type abandonmentState struct {
+	LastSeen  map[string]time.Time      `json:"last_seen"`
+	Abandoned map[string]*AbandonedNode `json:"abandoned"`
+}
+
+// AbandonmentTracker - 노드 부재 추적과 미참여 증명 발급
+type AbandonmentTracker struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	sui        *SuiIntegration
+	signer     *RequestSigner
+	history    *HeartbeatHistory
+
+	stateFile      string
+	after          time.Duration
+	validity       time.Duration
+	region         string
+	masterRegistry string
+
+	mutex        sync.Mutex
+	state        abandonmentState
+	savedAt      time.Time
+	attestations int64
+}
+
+// NewAbandonmentTracker - NAUTILUS_ABANDON_AFTER_HOURS / NAUTILUS_ABANDON_ATTESTATION_HOURS로 생성
+func NewAbandonmentTracker(logger *logrus.Logger, workerPool *WorkerPool, sui *SuiIntegration, signer *RequestSigner) (*AbandonmentTracker, error) {
+	t := &AbandonmentTracker{
+		logger:         logger,
+		workerPool:     workerPool,
+		sui:            sui,
+		signer:         signer,
+		stateFile:      statePath("abandoned-nodes.json"),
+		after:          time.Duration(envCount("NAUTILUS_ABANDON_AFTER_HOURS", 168)) * time.Hour,
+		validity:       time.Duration(envCount("NAUTILUS_ABANDON_ATTESTATION_HOURS", 24)) * time.Hour,
+		region:         getEnvOrDefault("NAUTILUS_REGION", "default"),
+		masterRegistry: os.Getenv("NAUTILUS_MASTER_REGISTRY"),
+		state: abandonmentState{
+			LastSeen:  make(map[string]time.Time),
+			Abandoned: make(map[string]*AbandonedNode),
+		},
+	}
+	if t.after < abandonmentMinAbsence {
+		return nil, fmt.Errorf("NAUTILUS_ABANDON_AFTER_HOURS must be at least %d (the contract's minimum absence)", int(abandonmentMinAbsence.Hours()))
+	}
+	if t.validity <= 0 {
+		return nil, fmt.Errorf("NAUTILUS_ABANDON_ATTESTATION_HOURS must be positive")
+	}
+	if _, err := loadJSONState(t.stateFile, &t.state); err != nil {
+		logger.Warnf("⚠️ Failed to load abandoned nodes: %v", err)
+	}
+	if t.state.LastSeen == nil {
+		t.state.LastSeen = make(map[string]time.Time)
+	}
+	if t.state.Abandoned == nil {
+		t.state.Abandoned = make(map[string]*AbandonedNode)
+	}
+	return t, nil
+}
+
+// Start - 한 시간마다 부재 노드 검사
+func (t *AbandonmentTracker) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.scan(time.Now())
+		}
+	}
+}
+
+// Seen - 하트비트 수신 (포기로 표시된 노드가 돌아오면 해제)
+func (t *AbandonmentTracker) Seen(nodeID string) {
+	now := time.Now()
+	t.mutex.Lock()
+	t.state.LastSeen[nodeID] = now
+	returned, wasAbandoned := t.state.Abandoned[nodeID]
+	delete(t.state.Abandoned, nodeID)
+	if wasAbandoned || now.Sub(t.savedAt) >= abandonmentSaveInterval {
+		t.saveLocked(now)
+	}
+	t.mutex.Unlock()
+
+	if wasAbandoned {
+		t.logger.Infof("🔙 Abandoned node %s is heartbeating again (absent since %s)", nodeID, returned.LastSeen.Format(time.RFC3339))
+		t.history.RecordEvent(nodeID, "abandonment_cleared", "heartbeat received")
+	}
+}
+
+// scan - 부재 기간이 지난 노드를 포기로 표시 (처음 보는 노드는 지금부터 부재 계산)
+func (t *AbandonmentTracker) scan(now time.Time) {
+	workers := t.workerPool.ListWorkers()
+	present := make(map[string]bool, len(workers))
+	var marked []*AbandonedNode
+
+	t.mutex.Lock()
+	for _, worker := range workers {
+		present[worker.NodeID] = true
+		seen, known := t.state.LastSeen[worker.NodeID]
+		if !known {
+			t.state.LastSeen[worker.NodeID] = now
+			continue
+		}
+		if _, done := t.state.Abandoned[worker.NodeID]; done || now.Sub(seen) < t.after {
+			continue
+		}
+		node := &AbandonedNode{
+			NodeID:      worker.NodeID,
+			Owner:       worker.WorkerAddress,
+			Role:        worker.Role,
+			StakeAmount: worker.StakeAmount,
+			LastSeen:    seen,
+			AbandonedAt: now,
+		}
+		t.state.Abandoned[worker.NodeID] = node
+		marked = append(marked, node)
+	}
+	// 풀에서 빠진 노드(등록 해제, 비상 인출)는 잊음
+	for nodeID := range t.state.LastSeen {
+		if !present[nodeID] {
+			delete(t.state.LastSeen, nodeID)
+			delete(t.state.Abandoned, nodeID)
+		}
+	}
+	t.saveLocked(now)
+	t.mutex.Unlock()
+
+	for _, node := range marked {
+		t.logger.WithFields(logrus.Fields{"node_id": node.NodeID, "owner": node.Owner}).
+			Warnf("🪦 Node %s marked abandoned: no heartbeat since %s", node.NodeID, node.LastSeen.Format(time.RFC3339))
+		t.history.RecordEvent(node.NodeID, "abandoned", "no heartbeat since "+node.LastSeen.Format(time.RFC3339))
+	}
+}
+
+// forget - 비상 인출로 등록이 해제된 노드 제거
+func (t *AbandonmentTracker) forget(nodeID string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.state.LastSeen, nodeID)
+	delete(t.state.Abandoned, nodeID)
+	t.saveLocked(time.Now())
+}
+
+func (t *AbandonmentTracker) saveLocked(now time.Time) {
+	if err := saveJSONState(t.stateFile, t.state); err != nil {
+		t.logger.Warnf("⚠️ Failed to persist abandoned nodes: %v", err)
+		return
+	}
+	t.savedAt = now
+}
+
+// Abandoned - 포기로 표시된 노드 (owner가 있으면 해당 소유자만, 노드 ID 순)
+func (t *AbandonmentTracker) Abandoned(owner string) []AbandonedNode {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	nodes := make([]AbandonedNode, 0, len(t.state.Abandoned))
+	for _, node := range t.state.Abandoned {
+		if owner == "" || strings.EqualFold(node.Owner, owner) {
+			nodes = append(nodes, *node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeID < nodes[j].NodeID })
+	return nodes
+}
+
+// Attest - 포기된 노드의 미참여 증명 서명 (소유자만 체인에서 사용할 수 있으므로 인증 없이 발급)
+func (t *AbandonmentTracker) Attest(nodeID string) (*AbandonmentAttestation, error) {
+	if t.masterRegistry == "" {
+		return nil, fmt.Errorf("NAUTILUS_MASTER_REGISTRY is not set; the contract cannot look up this master's key")
+	}
+	t.mutex.Lock()
+	node, ok := t.state.Abandoned[nodeID]
+	var copied AbandonedNode
+	if ok {
+		copied = *node
+	}
+	t.mutex.Unlock()
+	if !ok {
+		return nil, errNodeNotAbandoned
+	}
+
+	refs := t.sui.activeContract()
+	now := time.Now()
+	attestation := &AbandonmentAttestation{
+		NodeID:           copied.NodeID,
+		Owner:            copied.Owner,
+		PackageID:        refs.Package,
+		RegistryID:       refs.Registry,
+		MasterRegistryID: t.masterRegistry,
+		Region:           t.region,
+		LastSeenMs:       uint64(copied.LastSeen.UnixMilli()),
+		AbandonedAtMs:    uint64(copied.AbandonedAt.UnixMilli()),
+		ExpiresAtMs:      uint64(now.Add(t.validity).UnixMilli()),
+		PublicKey:        hex.EncodeToString(t.signer.publicKey()),
+	}
+	statement, err := abandonmentStatement(attestation)
+	if err != nil {
+		return nil, err
+	}
+	attestation.Statement = hex.EncodeToString(statement)
+	attestation.Signature = hex.EncodeToString(t.signer.signMessage(statement))
+
+	t.mutex.Lock()
+	t.attestations++
+	t.mutex.Unlock()
+	t.logger.Infof("🖋️ Signed abandonment attestation for %s (owner %s, expires %s)",
+		nodeID, copied.Owner, time.UnixMilli(int64(attestation.ExpiresAtMs)).Format(time.RFC3339))
+	return attestation, nil
+}
+
+var errNodeNotAbandoned = errors.New("node is not marked abandoned")
+
+/*
+abandonmentStatement - worker_registry::AbandonmentStatement의 BCS 직렬화
+
+	domain: vector<u8>, registry: address, node_id: String, owner: address,
+	last_seen_ms: u64, abandoned_at_ms: u64, expires_at_ms: u64
+*/
+func abandonmentStatement(a *AbandonmentAttestation) ([]byte, error) {
+	registry, err := bcsAddress(a.RegistryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry ID: %v", err)
+	}
+	owner, err := bcsAddress(a.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %v", err)
+	}
+
+	var buf bytes.Buffer
+	writeBCSBytes(&buf, []byte(abandonmentDomain))
+	buf.Write(registry)
+	writeBCSBytes(&buf, []byte(a.NodeID))
+	buf.Write(owner)
+	for _, value := range []uint64{a.LastSeenMs, a.AbandonedAtMs, a.ExpiresAtMs} {
+		binary.Write(&buf, binary.LittleEndian, value)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBCSBytes - ULEB128 길이 + 바이트 (vector<u8>, String)
+func writeBCSBytes(buf *bytes.Buffer, data []byte) {
+	length := uint64(len(data))
+	for length >= 0x80 {
+		buf.WriteByte(byte(length) | 0x80)
+		length >>= 7
+	}
+	buf.WriteByte(byte(length))
+	buf.Write(data)
+}
+
+// bcsAddress - 0x 접두 hex 주소를 32바이트로 (짧은 주소는 앞을 0으로 채움)
+func bcsAddress(address string) ([]byte, error) {
+	trimmed := strings.TrimPrefix(strings.ToLower(address), "0x")
+	if trimmed == "" || len(trimmed) > 64 {
+		return nil, fmt.Errorf("%q is not a Sui address", address)
+	}
+	decoded, err := hex.DecodeString(strings.Repeat("0", 64-len(trimmed)) + trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a Sui address", address)
+	}
+	return decoded, nil
+}
+
+// handleAbandoned - GET /api/v1/nodes/abandoned?owner=
+func (t *AbandonmentTracker) handleAbandoned(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, t.Abandoned(r.URL.Query().Get("owner")))
+}
+
+// handleAttestation - GET /api/v1/nodes/abandoned/attestation?node_id=
+func (t *AbandonmentTracker) handleAttestation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	nodeID := r.URL.Query().Get("node_id")
+	if nodeID == "" {
+		http.Error(w, "node_id is required", http.StatusBadRequest)
+		return
+	}
+	attestation, err := t.Attest(nodeID)
+	switch {
+	case err == errNodeNotAbandoned:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeClientJSON(w, attestation)
+}
+
+// writeMetrics - 포기된 노드 수와 발급한 증명 수
+func (t *AbandonmentTracker) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	abandoned, attestations := len(t.state.Abandoned), t.attestations
+	t.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_abandoned_nodes", "gauge", "Nodes marked abandoned after a prolonged heartbeat absence")
+	writeMetric(w, "nautilus_abandoned_nodes", nil, float64(abandoned))
+	writeMetricHeader(w, "nautilus_abandonment_attestations_total", "counter", "Non-participation attestations signed for emergency unstaking")
+	writeMetric(w, "nautilus_abandonment_attestations_total", nil, float64(attestations))
+}
@@ -0,0 +1,590 @@
+// Node Compliance - 워커가 보고한 OS/커널/런타임 지문을 컴플라이언스 정책과 비교해 드리프트를 기록하고 위반 노드를 표시
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+// hostNonCompliantCondition - 컴플라이언스 정책 위반 여부를 알리는 노드 조건
+const hostNonCompliantCondition = "k3s-daas.io/HostNonCompliant"
+
+const (
+	complianceCompliant    = "compliant"
+	complianceNonCompliant = "noncompliant"
+	complianceUnknown      = "unknown" // 지문을 보내지 않는 워커 (require_fingerprint가 꺼져 있을 때)
+)
+
+// HostFingerprint - 워커 하트비트 host_fingerprint (Digest는 수집 시각을 뺀 지문의 sha256)
+type HostFingerprint struct {
+	OSID           string            `json:"os_id"`
+	OSVersion      string            `json:"os_version"`
+	OSName         string            `json:"os_name,omitempty"`
+	KernelRelease  string            `json:"kernel_release"`
+	CgroupVersion  int               `json:"cgroup_version,omitempty"`
+	K3sVersion     string            `json:"k3s_version,omitempty"`
+	RuntimeName    string            `json:"runtime_name,omitempty"`
+	RuntimeVersion string            `json:"runtime_version,omitempty"`
+	Sysctls        map[string]string `json:"sysctls,omitempty"`
+	Digest         string            `json:"digest"`
+	CollectedAt    time.Time         `json:"collected_at"`
+}
+
+/*
+CompliancePolicy - 노드가 지켜야 할 호스트 구성 (비어 있는 항목은 검사하지 않음)
+
+	allowed_os        "ubuntu" 또는 "ubuntu 22.04" (ID와 VERSION_ID 접두사)
+	min_kernel        "5.15" 미만 커널은 위반
+	blocked_kernels   알려진 취약 커널 릴리스 접두사 (예: "5.15.0-91")
+	allowed_runtimes  "containerd" 또는 "containerd v1.7" (RuntimeName과 RuntimeVersion 접두사)
+*/
+type CompliancePolicy struct {
+	AllowedOS          []string          `json:"allowed_os,omitempty"`
+	MinKernel          string            `json:"min_kernel,omitempty"`
+	BlockedKernels     []string          `json:"blocked_kernels,omitempty"`
+	MinK3sVersion      string            `json:"min_k3s_version,omitempty"`
+	AllowedRuntimes    []string          `json:"allowed_runtimes,omitempty"`
+	CgroupVersion      int               `json:"cgroup_version,omitempty"`
+	Sysctls            map[string]string `json:"sysctls,omitempty"`             // 필수 값 (보고되지 않은 키도 위반)
+	RequireFingerprint bool              `json:"require_fingerprint,omitempty"` // 지문을 보내지 않는 워커도 위반으로 처리
+	ScorePercent       int               `json:"score_percent"`                 // 위반 노드의 HostCompliance 점수 (만점 대비 %)
+	Version            int64             `json:"version"`
+	UpdatedAt          time.Time         `json:"updated_at,omitempty"`
+}
+
+const defaultComplianceScorePercent = 20
+
+// validate - 버전 형식과 점수 범위 확인 (ScorePercent 0은 기본값으로)
+func (p *CompliancePolicy) validate() error {
+	if p.MinKernel != "" {
+		if _, ok := version.Parse(p.MinKernel); !ok {
+			return fmt.Errorf("invalid min_kernel %q", p.MinKernel)
+		}
+	}
+	if p.MinK3sVersion != "" {
+		if _, ok := version.Parse(p.MinK3sVersion); !ok {
+			return fmt.Errorf("invalid min_k3s_version %q", p.MinK3sVersion)
+		}
+	}
+	if p.CgroupVersion != 0 && p.CgroupVersion != 1 && p.CgroupVersion != 2 {
+		return fmt.Errorf("cgroup_version must be 1 or 2")
+	}
+	if p.ScorePercent < 0 || p.ScorePercent > 100 {
+		return fmt.Errorf("score_percent must be between 0 and 100")
+	}
+	if p.ScorePercent == 0 {
+		p.ScorePercent = defaultComplianceScorePercent
+	}
+	return nil
+}
+
+// NodeCompliance - 노드별 최근 판정
+type NodeCompliance struct {
+	NodeID        string           `json:"node_id"`
+	Status        string           `json:"status"`
+	Violations    []string         `json:"violations,omitempty"`
+	Fingerprint   *HostFingerprint `json:"fingerprint,omitempty"`
+	PolicyVersion int64            `json:"policy_version"`
+	CheckedAt     time.Time        `json:"checked_at"`
+	Since         time.Time        `json:"since"`                // 현재 상태가 된 시각
+	DriftedAt     time.Time        `json:"drifted_at,omitempty"` // 지문이 마지막으로 바뀐 시각
+}
+
+// ComplianceReport - /api/v1/compliance 응답
+type ComplianceReport struct {
+	Policy  CompliancePolicy `json:"policy"`
+	Summary map[string]int   `json:"summary"` // 상태별 노드 수
+	Nodes   []NodeCompliance `json:"nodes"`
+}
+
+// complianceChange - 노드 조건 patch 요청
+type complianceChange struct {
+	nodeID  string
+	status  string // True (위반), False
+	reason  string
+	message string
+}
+
+/*
+NodeComplianceTracker - 하트비트의 호스트 지문으로 노드 컴플라이언스 판정
+  - 지문 Digest가 바뀌면 host_drift 이벤트 기록 (예: 커널 업그레이드, sysctl 변경)
+  - 위반 노드에 k3s-daas.io/HostNonCompliant=True 조건을 걸고 HostCompliance 스케줄러 플러그인이 점수를 낮춤
+  - 정책은 NAUTILUS_COMPLIANCE_POLICY(JSON 파일)로 시작하고 /api/v1/admin/compliance-policy(PUT)로 바꾸면 저장된 값이 우선
+*/
+type NodeComplianceTracker struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	history    *HeartbeatHistory
+	adminToken string
+	stateFile  string
+
+	mutex   sync.RWMutex
+	policy  CompliancePolicy
+	nodes   map[string]*NodeCompliance
+	patches chan complianceChange
+	patched int
+	failed  int
+}
+
+// NewNodeComplianceTracker - 저장된 정책 또는 NAUTILUS_COMPLIANCE_POLICY 파일로 추적기 생성
+func NewNodeComplianceTracker(logger *logrus.Logger, k3sMgr *K3sManager) (*NodeComplianceTracker, error) {
+	t := &NodeComplianceTracker{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		stateFile:  statePath("compliance-policy.json"),
+		nodes:      make(map[string]*NodeCompliance),
+		patches:    make(chan complianceChange, 128),
+	}
+	found, err := loadJSONState(t.stateFile, &t.policy)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to load compliance policy: %v", err)
+	}
+	if !found {
+		if path := os.Getenv("NAUTILUS_COMPLIANCE_POLICY"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("NAUTILUS_COMPLIANCE_POLICY: %v", err)
+			}
+			if err := json.Unmarshal(data, &t.policy); err != nil {
+				return nil, fmt.Errorf("NAUTILUS_COMPLIANCE_POLICY: %v", err)
+			}
+		}
+	}
+	if err := t.policy.validate(); err != nil {
+		return nil, fmt.Errorf("compliance policy: %v", err)
+	}
+	go t.run()
+	return t, nil
+}
+
+// Observe - 하트비트 지문 반영 (nil은 지문을 보내지 않은 워커)
+func (t *NodeComplianceTracker) Observe(nodeID string, fingerprint *HostFingerprint) {
+	now := time.Now()
+	t.mutex.Lock()
+	previous := t.nodes[nodeID]
+	current := t.evaluate(nodeID, fingerprint, now)
+	var drift string
+	if previous != nil {
+		current.DriftedAt = previous.DriftedAt
+		if previous.Status == current.Status {
+			current.Since = previous.Since
+		}
+		if previous.Fingerprint != nil && fingerprint != nil && previous.Fingerprint.Digest != fingerprint.Digest {
+			current.DriftedAt = now
+			drift = describeDrift(previous.Fingerprint, fingerprint)
+		}
+	}
+	t.nodes[nodeID] = current
+	t.mutex.Unlock()
+
+	if drift != "" {
+		t.logger.Infof("🧬 Host fingerprint of %s changed: %s", nodeID, drift)
+		t.history.RecordEvent(nodeID, "host_drift", drift)
+	}
+	if previous == nil || previous.Status != current.Status || strings.Join(previous.Violations, "\n") != strings.Join(current.Violations, "\n") {
+		t.statusChanged(previous, current)
+	}
+}
+
+// evaluate - 현재 정책으로 지문 판정 (mutex 보유 상태에서 호출)
+func (t *NodeComplianceTracker) evaluate(nodeID string, fingerprint *HostFingerprint, now time.Time) *NodeCompliance {
+	result := &NodeCompliance{
+		NodeID:        nodeID,
+		Status:        complianceCompliant,
+		Fingerprint:   fingerprint,
+		PolicyVersion: t.policy.Version,
+		CheckedAt:     now,
+		Since:         now,
+	}
+	if fingerprint == nil {
+		result.Status = complianceUnknown
+		if t.policy.RequireFingerprint {
+			result.Status = complianceNonCompliant
+			result.Violations = []string{"no host fingerprint reported"}
+		}
+		return result
+	}
+	result.Violations = t.policy.violations(fingerprint)
+	if len(result.Violations) > 0 {
+		result.Status = complianceNonCompliant
+	}
+	return result
+}
+
+// violations - 정책을 벗어난 항목 (정렬된 사람이 읽을 수 있는 문장)
+func (p *CompliancePolicy) violations(f *HostFingerprint) []string {
+	var violations []string
+	if len(p.AllowedOS) > 0 && !matchesAny(p.AllowedOS, f.OSID, f.OSVersion) {
+		violations = append(violations, fmt.Sprintf("os %s %s is not allowed", f.OSID, f.OSVersion))
+	}
+	if p.MinKernel != "" && !versionAtLeast(f.KernelRelease, p.MinKernel) {
+		violations = append(violations, fmt.Sprintf("kernel %s is older than %s", f.KernelRelease, p.MinKernel))
+	}
+	for _, blocked := range p.BlockedKernels {
+		if blocked != "" && strings.HasPrefix(f.KernelRelease, blocked) {
+			violations = append(violations, fmt.Sprintf("kernel %s is blocked", f.KernelRelease))
+			break
+		}
+	}
+	if p.MinK3sVersion != "" && !versionAtLeast(f.K3sVersion, p.MinK3sVersion) {
+		violations = append(violations, fmt.Sprintf("k3s %s is older than %s", valueOrUnknown(f.K3sVersion), p.MinK3sVersion))
+	}
+	if len(p.AllowedRuntimes) > 0 && !matchesAny(p.AllowedRuntimes, f.RuntimeName, f.RuntimeVersion) {
+		violations = append(violations, fmt.Sprintf("container runtime %s %s is not allowed", valueOrUnknown(f.RuntimeName), f.RuntimeVersion))
+	}
+	if p.CgroupVersion != 0 && f.CgroupVersion != p.CgroupVersion {
+		violations = append(violations, fmt.Sprintf("cgroup v%d instead of v%d", f.CgroupVersion, p.CgroupVersion))
+	}
+	for key, want := range p.Sysctls {
+		got, ok := f.Sysctls[key]
+		switch {
+		case !ok:
+			violations = append(violations, fmt.Sprintf("sysctl %s is not reported (want %s)", key, want))
+		case got != want:
+			violations = append(violations, fmt.Sprintf("sysctl %s=%s (want %s)", key, got, want))
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// matchesAny - "이름" 또는 "이름 버전접두사" 항목 중 하나와 일치
+func matchesAny(allowed []string, name, ver string) bool {
+	for _, entry := range allowed {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], name) {
+			continue
+		}
+		if len(fields) == 1 || strings.HasPrefix(strings.TrimPrefix(ver, "v"), strings.TrimPrefix(fields[1], "v")) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionAtLeast - 숫자 부분만 비교 (해석할 수 없는 버전은 미달로 판정)
+func versionAtLeast(actual, minimum string) bool {
+	a, ok := version.Parse(actual)
+	if !ok {
+		return false
+	}
+	m, _ := version.Parse(minimum)
+	if a.Major != m.Major {
+		return a.Major > m.Major
+	}
+	if a.Minor != m.Minor {
+		return a.Minor > m.Minor
+	}
+	return a.Patch >= m.Patch
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "(unknown)"
+	}
+	return value
+}
+
+// describeDrift - 바뀐 항목만 "kernel 5.15.0-91 → 5.15.0-94" 형태로
+func describeDrift(before, after *HostFingerprint) string {
+	var changes []string
+	field := func(name, old, new string) {
+		if old != new {
+			changes = append(changes, fmt.Sprintf("%s %s → %s", name, valueOrUnknown(old), valueOrUnknown(new)))
+		}
+	}
+	field("os", before.OSID+" "+before.OSVersion, after.OSID+" "+after.OSVersion)
+	field("kernel", before.KernelRelease, after.KernelRelease)
+	field("cgroup", fmt.Sprint(before.CgroupVersion), fmt.Sprint(after.CgroupVersion))
+	field("k3s", before.K3sVersion, after.K3sVersion)
+	field("runtime", before.RuntimeName+" "+before.RuntimeVersion, after.RuntimeName+" "+after.RuntimeVersion)
+	keys := make(map[string]string)
+	for key, value := range before.Sysctls {
+		keys[key] = value
+	}
+	for key, value := range after.Sysctls {
+		keys[key] = value
+	}
+	for _, key := range sortedKeys(keys) {
+		field(key, before.Sysctls[key], after.Sysctls[key])
+	}
+	if len(changes) == 0 {
+		return "fingerprint digest changed"
+	}
+	return strings.Join(changes, ", ")
+}
+
+// statusChanged - 상태 전환 기록과 노드 조건 patch 대기열 추가
+func (t *NodeComplianceTracker) statusChanged(previous, current *NodeCompliance) {
+	change := complianceChange{nodeID: current.NodeID, status: "False", reason: "Compliant",
+		message: fmt.Sprintf("host matches compliance policy v%d", current.PolicyVersion)}
+	switch current.Status {
+	case complianceNonCompliant:
+		change.status, change.reason = "True", "PolicyViolation"
+		change.message = strings.Join(current.Violations, "; ")
+		t.logger.Warnf("🚨 Node %s violates compliance policy v%d: %s", current.NodeID, current.PolicyVersion, change.message)
+		t.history.RecordEvent(current.NodeID, "compliance_violation", change.message)
+	case complianceUnknown:
+		change.status, change.reason = "Unknown", "NoFingerprint"
+		change.message = "worker does not report a host fingerprint"
+	default:
+		if previous != nil && previous.Status == complianceNonCompliant {
+			t.logger.Infof("✅ Node %s is compliant again", current.NodeID)
+			t.history.RecordEvent(current.NodeID, "compliance_restored", change.message)
+		}
+	}
+	if previous == nil && current.Status != complianceNonCompliant {
+		// 처음 본 정상 노드는 조건이 없어도 같은 의미라 patch하지 않음
+		return
+	}
+	select {
+	case t.patches <- change:
+	default:
+		t.logger.Warnf("⚠️ Compliance patch queue full, skipping node %s", current.NodeID)
+	}
+}
+
+func (t *NodeComplianceTracker) run() {
+	for change := range t.patches {
+		if t.k3sMgr == nil || !t.k3sMgr.IsRunning() {
+			continue
+		}
+		err := t.patchNode(change)
+		t.mutex.Lock()
+		if err != nil {
+			t.failed++
+		} else {
+			t.patched++
+		}
+		t.mutex.Unlock()
+		if err != nil {
+			t.logger.Warnf("⚠️ Failed to update %s condition on node %s: %v", hostNonCompliantCondition, change.nodeID, err)
+		}
+	}
+}
+
+// patchNode - 노드 status 조건 설정 (kubelet 조건과 type이 달라 strategic merge로 공존)
+func (t *NodeComplianceTracker) patchNode(change complianceChange) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	body, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []map[string]string{{
+			"type":               hostNonCompliantCondition,
+			"status":             change.status,
+			"reason":             change.reason,
+			"message":            change.message,
+			"lastHeartbeatTime":  now,
+			"lastTransitionTime": now,
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.k3sMgr.RunKubectl(nil, "patch", "node", change.nodeID,
+		"--subresource=status", "--type=strategic", "-p", string(body))
+	return err
+}
+
+// Status - 노드의 최근 판정 (보고가 없으면 false)
+func (t *NodeComplianceTracker) Status(nodeID string) (string, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	node, ok := t.nodes[nodeID]
+	if !ok {
+		return "", false
+	}
+	return node.Status, true
+}
+
+// Policy - 현재 정책 사본
+func (t *NodeComplianceTracker) Policy() CompliancePolicy {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.policy
+}
+
+// SetPolicy - 정책 교체 후 저장하고 마지막 지문으로 모든 노드 재판정
+func (t *NodeComplianceTracker) SetPolicy(policy CompliancePolicy) (CompliancePolicy, error) {
+	if err := policy.validate(); err != nil {
+		return CompliancePolicy{}, err
+	}
+	now := time.Now()
+	t.mutex.Lock()
+	policy.Version = t.policy.Version + 1
+	policy.UpdatedAt = now
+	if err := saveJSONState(t.stateFile, policy); err != nil {
+		t.mutex.Unlock()
+		return CompliancePolicy{}, fmt.Errorf("failed to save compliance policy: %v", err)
+	}
+	t.policy = policy
+	type transition struct{ previous, current *NodeCompliance }
+	var changed []transition
+	for nodeID, previous := range t.nodes {
+		current := t.evaluate(nodeID, previous.Fingerprint, now)
+		current.DriftedAt = previous.DriftedAt
+		if previous.Status == current.Status {
+			current.Since = previous.Since
+		}
+		t.nodes[nodeID] = current
+		if previous.Status != current.Status || strings.Join(previous.Violations, "\n") != strings.Join(current.Violations, "\n") {
+			changed = append(changed, transition{previous, current})
+		}
+	}
+	t.mutex.Unlock()
+
+	t.logger.Infof("📋 Compliance policy updated to v%d (%d nodes changed status)", policy.Version, len(changed))
+	for _, c := range changed {
+		t.statusChanged(c.previous, c.current)
+	}
+	return policy, nil
+}
+
+// Report - 정책, 상태별 노드 수, 노드별 판정 (status가 있으면 해당 상태만)
+func (t *NodeComplianceTracker) Report(status string) ComplianceReport {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	report := ComplianceReport{
+		Policy:  t.policy,
+		Summary: map[string]int{complianceCompliant: 0, complianceNonCompliant: 0, complianceUnknown: 0},
+		Nodes:   []NodeCompliance{},
+	}
+	for _, node := range t.nodes {
+		report.Summary[node.Status]++
+		if status == "" || node.Status == status {
+			report.Nodes = append(report.Nodes, *node)
+		}
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].NodeID < report.Nodes[j].NodeID })
+	return report
+}
+
+// handleCompliance - 컴플라이언스 보고서 (GET /api/v1/compliance?status=)
+func (t *NodeComplianceTracker) handleCompliance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", complianceCompliant, complianceNonCompliant, complianceUnknown:
+	default:
+		http.Error(w, "status must be compliant, noncompliant or unknown", http.StatusBadRequest)
+		return
+	}
+	writeClientJSON(w, t.Report(status))
+}
+
+func (t *NodeComplianceTracker) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if t.adminToken == "" {
+		http.Error(w, "Compliance policy API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(t.adminToken)) != 1 {
+		t.logger.Warnf("🚫 Unauthorized compliance policy access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handlePolicy - 컴플라이언스 정책 조회/교체 (/api/v1/admin/compliance-policy, 관리자 토큰 필요)
+
+	GET                                                현재 정책
+	PUT {"min_kernel": "5.15", "allowed_os": [...]}    전체 교체 (version은 자동 증가, 모든 노드 재판정)
+*/
+func (t *NodeComplianceTracker) handlePolicy(w http.ResponseWriter, r *http.Request) {
+	if !t.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, t.Policy())
+	case http.MethodPut:
+		var policy CompliancePolicy
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&policy); err != nil {
+			http.Error(w, "Invalid compliance policy", http.StatusBadRequest)
+			return
+		}
+		updated, err := t.SetPolicy(policy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, updated)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 상태별 노드 수, 노드별 위반 여부, patch 결과
+func (t *NodeComplianceTracker) writeMetrics(w io.Writer) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	counts := map[string]int{complianceCompliant: 0, complianceNonCompliant: 0, complianceUnknown: 0}
+	nodes := make([]string, 0, len(t.nodes))
+	for nodeID, node := range t.nodes {
+		counts[node.Status]++
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+
+	writeMetricHeader(w, "nautilus_compliance_policy_version", "gauge", "Version of the active host compliance policy")
+	writeMetric(w, "nautilus_compliance_policy_version", nil, float64(t.policy.Version))
+	writeMetricHeader(w, "nautilus_compliance_nodes", "gauge", "Nodes by host compliance status")
+	for _, status := range []string{complianceCompliant, complianceNonCompliant, complianceUnknown} {
+		writeMetric(w, "nautilus_compliance_nodes", map[string]string{"status": status}, float64(counts[status]))
+	}
+	writeMetricHeader(w, "nautilus_compliance_violations", "gauge", "Compliance policy violations per node")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_compliance_violations", map[string]string{"node": nodeID}, float64(len(t.nodes[nodeID].Violations)))
+	}
+	writeMetricHeader(w, "nautilus_compliance_patches_total", "counter", "Node condition updates for host compliance")
+	writeMetric(w, "nautilus_compliance_patches_total", map[string]string{"outcome": "applied"}, float64(t.patched))
+	writeMetric(w, "nautilus_compliance_patches_total", map[string]string{"outcome": "failed"}, float64(t.failed))
+}
+
+// hostCompliancePlugin - 정책 위반 노드는 score_percent만큼만, 정상/미보고 노드는 만점
+type hostCompliancePlugin struct {
+	handle *SchedulerPluginHandle
+}
+
+func (p *hostCompliancePlugin) Name() string { return "HostCompliance" }
+
+func (p *hostCompliancePlugin) Score(state *SchedulingState) (map[string]int64, error) {
+	compliance := p.handle.Compliance
+	if compliance == nil {
+		return nil, nil
+	}
+	reduced := int64(maxExtenderPriority * compliance.Policy().ScorePercent / 100)
+	scores := make(map[string]int64, len(state.Nodes))
+	for _, node := range state.Nodes {
+		scores[node.Name] = maxExtenderPriority
+		if status, ok := compliance.Status(node.Name); ok && status == complianceNonCompliant {
+			scores[node.Name] = reduced
+		}
+	}
+	return scores, nil
+}
+
+func init() {
+	RegisterSchedulerPlugin("HostCompliance", true, func(handle *SchedulerPluginHandle, _ json.RawMessage) (SchedulerPlugin, error) {
+		return &hostCompliancePlugin{handle: handle}, nil
+	})
+}
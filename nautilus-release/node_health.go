@@ -0,0 +1,341 @@
+// Node Health - Pod 시작 지연/실패율 기반 노드 건강 점수 및 probation 관리
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	probationTaintKey      = "k3s-daas.io/probation"
+	healthEvaluateInterval = 60 * time.Second
+	healthWindow           = 30 * time.Minute
+	probationThreshold     = 60.0
+	recoveryThreshold      = 75.0
+	podStartBaseline       = 5 * time.Second
+)
+
+// failureReasons - 노드 측 실패로 간주하는 컨테이너 대기 사유
+var failureReasons = map[string]bool{
+	"CrashLoopBackOff":     true,
+	"CreateContainerError": true,
+	"RunContainerError":    true,
+	"ErrImagePull":         true,
+	"ImagePullBackOff":     true,
+}
+
+// NodeHealth - 노드 건강 상태
+type NodeHealth struct {
+	NodeName         string    `json:"node_name"`
+	Score            float64   `json:"score"`
+	PodsObserved     int       `json:"pods_observed"`
+	PodsFailed       int       `json:"pods_failed"`
+	Restarts         int       `json:"restarts"`
+	AvgStartSeconds  float64   `json:"avg_start_seconds"`
+	FailureRate      float64   `json:"failure_rate"`
+	ChallengePenalty float64   `json:"challenge_penalty,omitempty"` // 하트비트 챌린지 실패 감점
+	Probation        bool      `json:"probation"`
+	ProbationSince   time.Time `json:"probation_since,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NodeHealthScorer - 노드 건강 점수 계산기
+type NodeHealthScorer struct {
+	logger      *logrus.Logger
+	k3sMgr      *K3sManager
+	nodes       map[string]*NodeHealth
+	history     *HeartbeatHistory
+	maintenance *MaintenanceScheduler
+	challenges  *HeartbeatChallenger
+	mutex       sync.RWMutex
+}
+
+// NewNodeHealthScorer - 새 Node Health Scorer 생성
+func NewNodeHealthScorer(logger *logrus.Logger, k3sMgr *K3sManager) *NodeHealthScorer {
+	return &NodeHealthScorer{
+		logger: logger,
+		k3sMgr: k3sMgr,
+		nodes:  make(map[string]*NodeHealth),
+	}
+}
+
+// Start - 주기적 점수 계산 시작
+func (h *NodeHealthScorer) Start(ctx context.Context) {
+	h.logger.Info("🩺 Starting Node Health Scorer...")
+
+	ticker := time.NewTicker(healthEvaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !h.k3sMgr.IsRunning() {
+				continue
+			}
+			if err := h.Evaluate(); err != nil {
+				h.logger.Warnf("⚠️ Node health evaluation failed: %v", err)
+			}
+		}
+	}
+}
+
+// podObservation - 점수 계산용 Pod 관측 결과
+type podObservation struct {
+	started      bool
+	startLatency time.Duration
+	failed       bool
+	restarts     int
+}
+
+// Evaluate - 최근 Pod 기록으로 노드별 점수 재계산 및 probation 반영
+func (h *NodeHealthScorer) Evaluate() error {
+	observations, err := h.observePods()
+	if err != nil {
+		return err
+	}
+
+	// 챌린지에 실패한 노드는 최근 Pod가 없어도 평가 (Pod를 받지 않는 위장 신원)
+	if h.challenges != nil {
+		for _, nodeName := range h.challenges.PenalizedNodes() {
+			if _, exists := observations[nodeName]; !exists {
+				observations[nodeName] = nil
+			}
+		}
+	}
+
+	now := time.Now()
+	for nodeName, pods := range observations {
+		health := scoreNode(nodeName, pods)
+		health.UpdatedAt = now
+		if h.challenges != nil {
+			health.ChallengePenalty = h.challenges.Penalty(nodeName)
+			health.Score = math.Max(0, health.Score-health.ChallengePenalty)
+		}
+
+		h.mutex.Lock()
+		previous := h.nodes[nodeName]
+		if previous != nil {
+			health.Probation = previous.Probation
+			health.ProbationSince = previous.ProbationSince
+		}
+		h.nodes[nodeName] = health
+		h.mutex.Unlock()
+
+		// 정비 중에는 drain으로 인한 실패/재시작이 점수에 반영되므로 probation 변경 보류
+		if h.maintenance.InMaintenance(nodeName) {
+			continue
+		}
+		switch {
+		case !health.Probation && health.Score < probationThreshold:
+			h.setProbation(health, true)
+		case health.Probation && health.Score >= recoveryThreshold:
+			h.setProbation(health, false)
+		}
+	}
+	return nil
+}
+
+// scoreNode - 시작 지연, 실패율, 재시작 횟수로 0~100 점수 계산
+func scoreNode(nodeName string, pods []podObservation) *NodeHealth {
+	health := &NodeHealth{NodeName: nodeName, PodsObserved: len(pods)}
+
+	var totalStart time.Duration
+	started := 0
+	for _, pod := range pods {
+		if pod.failed {
+			health.PodsFailed++
+		}
+		if pod.started {
+			totalStart += pod.startLatency
+			started++
+		}
+		health.Restarts += pod.restarts
+	}
+
+	if started > 0 {
+		health.AvgStartSeconds = (totalStart / time.Duration(started)).Seconds()
+	}
+	if len(pods) > 0 {
+		health.FailureRate = float64(health.PodsFailed) / float64(len(pods))
+	}
+
+	// 기준(5초)을 넘는 시작 지연 1초당 2점, 최대 40점 감점
+	latencyPenalty := math.Min(40, math.Max(0, health.AvgStartSeconds-podStartBaseline.Seconds())*2)
+	failurePenalty := health.FailureRate * 50
+	restartPenalty := math.Min(10, float64(health.Restarts))
+
+	health.Score = math.Max(0, 100-latencyPenalty-failurePenalty-restartPenalty)
+	return health
+}
+
+// observePods - 최근 생성된 Pod를 노드별로 관측
+func (h *NodeHealthScorer) observePods() (map[string][]podObservation, error) {
+	output, err := h.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				CreationTimestamp time.Time `json:"creationTimestamp"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+			Status struct {
+				Phase      string `json:"phase"`
+				Conditions []struct {
+					Type               string    `json:"type"`
+					Status             string    `json:"status"`
+					LastTransitionTime time.Time `json:"lastTransitionTime"`
+				} `json:"conditions"`
+				ContainerStatuses []struct {
+					RestartCount int `json:"restartCount"`
+					State        struct {
+						Waiting *struct {
+							Reason string `json:"reason"`
+						} `json:"waiting"`
+					} `json:"state"`
+				} `json:"containerStatuses"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %v", err)
+	}
+
+	cutoff := time.Now().Add(-healthWindow)
+	observations := make(map[string][]podObservation)
+	for _, pod := range list.Items {
+		if pod.Spec.NodeName == "" || pod.Metadata.CreationTimestamp.Before(cutoff) {
+			continue
+		}
+
+		observation := podObservation{failed: pod.Status.Phase == "Failed"}
+
+		var scheduledAt, readyAt time.Time
+		for _, condition := range pod.Status.Conditions {
+			if condition.Status != "True" {
+				continue
+			}
+			switch condition.Type {
+			case "PodScheduled":
+				scheduledAt = condition.LastTransitionTime
+			case "Ready":
+				readyAt = condition.LastTransitionTime
+			}
+		}
+		if !scheduledAt.IsZero() && !readyAt.IsZero() && readyAt.After(scheduledAt) {
+			observation.started = true
+			observation.startLatency = readyAt.Sub(scheduledAt)
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			observation.restarts += status.RestartCount
+			if status.State.Waiting != nil && failureReasons[status.State.Waiting.Reason] {
+				observation.failed = true
+			}
+		}
+
+		observations[pod.Spec.NodeName] = append(observations[pod.Spec.NodeName], observation)
+	}
+	return observations, nil
+}
+
+// setProbation - PreferNoSchedule taint로 스케줄링 가중치 축소/복구
+func (h *NodeHealthScorer) setProbation(health *NodeHealth, probation bool) {
+	taint := probationTaintKey + "=true:PreferNoSchedule"
+	if !probation {
+		taint = probationTaintKey + "-"
+	}
+
+	if _, err := h.k3sMgr.RunKubectl(nil, "taint", "nodes", health.NodeName, taint, "--overwrite"); err != nil {
+		h.logger.Errorf("❌ Failed to update probation taint on %s: %v", health.NodeName, err)
+		return
+	}
+
+	h.mutex.Lock()
+	health.Probation = probation
+	if probation {
+		health.ProbationSince = time.Now()
+	} else {
+		health.ProbationSince = time.Time{}
+	}
+	h.mutex.Unlock()
+
+	if probation {
+		h.logger.Warnf("🚧 Node %s placed on probation (score %.1f)", health.NodeName, health.Score)
+		h.history.RecordEvent(health.NodeName, "probation", fmt.Sprintf("score %.1f", health.Score))
+	} else {
+		h.logger.Infof("✅ Node %s recovered from probation (score %.1f)", health.NodeName, health.Score)
+		h.history.RecordEvent(health.NodeName, "recovered", fmt.Sprintf("score %.1f", health.Score))
+	}
+}
+
+// ListHealth - 노드 이름 순으로 건강 상태 반환
+func (h *NodeHealthScorer) ListHealth() []NodeHealth {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	result := make([]NodeHealth, 0, len(h.nodes))
+	for _, health := range h.nodes {
+		result = append(result, *health)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].NodeName < result[j].NodeName })
+	return result
+}
+
+// handleNodeHealth - 노드 건강 점수 조회 API
+func (h *NodeHealthScorer) handleNodeHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   h.ListHealth(),
+	})
+}
+
+// writeMetrics - 노드 건강 메트릭 출력
+func (h *NodeHealthScorer) writeMetrics(w io.Writer) {
+	nodes := h.ListHealth()
+
+	writeMetricHeader(w, "k3s_daas_node_health_score", "gauge", "Node health score (0-100)")
+	for _, node := range nodes {
+		writeMetric(w, "k3s_daas_node_health_score", map[string]string{"node": node.NodeName}, node.Score)
+	}
+
+	writeMetricHeader(w, "k3s_daas_node_pod_start_seconds", "gauge", "Average pod start latency on the node")
+	for _, node := range nodes {
+		writeMetric(w, "k3s_daas_node_pod_start_seconds", map[string]string{"node": node.NodeName}, node.AvgStartSeconds)
+	}
+
+	writeMetricHeader(w, "k3s_daas_node_pod_failure_ratio", "gauge", "Ratio of failed pods on the node")
+	for _, node := range nodes {
+		writeMetric(w, "k3s_daas_node_pod_failure_ratio", map[string]string{"node": node.NodeName}, node.FailureRate)
+	}
+
+	writeMetricHeader(w, "k3s_daas_node_probation", "gauge", "Whether the node is on probation")
+	for _, node := range nodes {
+		value := 0.0
+		if node.Probation {
+			value = 1
+		}
+		writeMetric(w, "k3s_daas_node_probation", map[string]string{"node": node.NodeName}, value)
+	}
+}
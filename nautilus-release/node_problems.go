@@ -0,0 +1,187 @@
+// Node Problems - 워커가 보고한 노드 문제 조건(KernelDeadlock, OOMKilling, DiskPressure)에 따라 노드 taint 자동 설정/해제
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// nodeProblemTaints - taint로 반영하는 노드 조건과 taint 키
+var nodeProblemTaints = map[string]string{
+	"KernelDeadlock": "k3s-daas.io/kernel-deadlock",
+	"OOMKilling":     "k3s-daas.io/oom-killing",
+	"DiskPressure":   "k3s-daas.io/disk-pressure",
+}
+
+type nodeTaintChange struct {
+	nodeID    string
+	condition string
+	active    bool
+}
+
+/*
+NodeProblemTainter - 노드 문제 조건이 켜진 워커에 taint를 걸고 해소되면 제거
+워커의 노드 문제 감지기(커널 로그)와 압박 감지기가 하트비트마다 조건 전체를 보내므로, 노드별로 반영한 상태와
+비교해 다른 조건만 kubectl taint를 실행합니다. 실패하면 반영하지 않은 것으로 두고 다음 하트비트에서 다시 시도합니다.
+효과는 NAUTILUS_NODE_PROBLEM_TAINT(NoSchedule 기본, PreferNoSchedule, NoExecute, off)로 정합니다.
+NoExecute는 toleration이 없는 Pod를 즉시 내보내므로 교착된 노드에서 워크로드를 옮기고 싶을 때만 쓰세요.
+*/
+type NodeProblemTainter struct {
+	logger  *logrus.Logger
+	k3sMgr  *K3sManager
+	history *HeartbeatHistory
+	effect  string
+
+	mutex   sync.Mutex
+	applied map[string]map[string]bool // 노드 → 조건 → 반영한 taint 여부
+	pending map[nodeTaintChange]bool
+	changes chan nodeTaintChange
+	taints  int
+	removed int
+	failed  int
+}
+
+// NewNodeProblemTainter - 노드 문제 taint 관리자 생성 (잘못된 효과는 오류)
+func NewNodeProblemTainter(logger *logrus.Logger, k3sMgr *K3sManager) (*NodeProblemTainter, error) {
+	effect := getEnvOrDefault("NAUTILUS_NODE_PROBLEM_TAINT", "NoSchedule")
+	switch effect {
+	case "NoSchedule", "PreferNoSchedule", "NoExecute", "off":
+	default:
+		return nil, fmt.Errorf("invalid NAUTILUS_NODE_PROBLEM_TAINT %q (NoSchedule, PreferNoSchedule, NoExecute or off)", effect)
+	}
+	t := &NodeProblemTainter{
+		logger:  logger,
+		k3sMgr:  k3sMgr,
+		effect:  effect,
+		applied: make(map[string]map[string]bool),
+		pending: make(map[nodeTaintChange]bool),
+		changes: make(chan nodeTaintChange, 256),
+	}
+	if effect != "off" {
+		go t.run()
+	}
+	return t, nil
+}
+
+// Observe - 하트비트의 노드 조건 반영 (taint 대상 조건 중 반영한 상태와 다른 것만 실행)
+func (t *NodeProblemTainter) Observe(nodeID string, conditions []NodeCondition) {
+	if t.effect == "off" {
+		return
+	}
+	desired := make(map[string]bool, len(nodeProblemTaints))
+	for _, condition := range conditions {
+		if _, ok := nodeProblemTaints[condition.Type]; ok {
+			desired[condition.Type] = desired[condition.Type] || condition.Status
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	applied := t.applied[nodeID]
+	for condition, active := range desired {
+		state, known := applied[condition]
+		// 마스터 재시작 후에는 이전 taint가 남아 있을 수 있으므로 꺼진 조건도 한 번은 제거
+		if known && state == active {
+			continue
+		}
+		change := nodeTaintChange{nodeID: nodeID, condition: condition, active: active}
+		if t.pending[change] {
+			continue
+		}
+		select {
+		case t.changes <- change:
+			t.pending[change] = true
+		default:
+			// 다음 하트비트에서 다시 비교하므로 대기열이 가득 차면 버림
+		}
+	}
+}
+
+func (t *NodeProblemTainter) run() {
+	for change := range t.changes {
+		if t.k3sMgr == nil || !t.k3sMgr.IsRunning() {
+			t.mutex.Lock()
+			delete(t.pending, change)
+			t.mutex.Unlock()
+			continue
+		}
+		err := t.apply(change)
+
+		t.mutex.Lock()
+		delete(t.pending, change)
+		if err != nil {
+			t.failed++
+		} else {
+			if t.applied[change.nodeID] == nil {
+				t.applied[change.nodeID] = make(map[string]bool)
+			}
+			previous, known := t.applied[change.nodeID][change.condition]
+			t.applied[change.nodeID][change.condition] = change.active
+			if change.active {
+				t.taints++
+			} else if known && previous {
+				t.removed++
+				t.logger.Infof("✅ Node %s untainted %s (%s cleared)", change.nodeID, nodeProblemTaints[change.condition], change.condition)
+				t.history.RecordEvent(change.nodeID, "problem_untainted", change.condition)
+			}
+		}
+		t.mutex.Unlock()
+
+		if err != nil {
+			t.logger.Warnf("⚠️ Failed to update %s taint on node %s: %v", nodeProblemTaints[change.condition], change.nodeID, err)
+		}
+	}
+}
+
+// apply - kubectl taint 실행 (없는 taint 제거는 성공으로 처리)
+func (t *NodeProblemTainter) apply(change nodeTaintChange) error {
+	key := nodeProblemTaints[change.condition]
+	if !change.active {
+		output, err := t.k3sMgr.RunKubectl(nil, "taint", "nodes", change.nodeID, key+"-")
+		if err != nil && !strings.Contains(string(output)+err.Error(), "not found") {
+			return err
+		}
+		return nil
+	}
+
+	if _, err := t.k3sMgr.RunKubectl(nil, "taint", "nodes", change.nodeID, key+"=true:"+t.effect, "--overwrite"); err != nil {
+		return err
+	}
+	t.logger.Warnf("🚧 Node %s tainted %s:%s (%s)", change.nodeID, key, t.effect, change.condition)
+	t.history.RecordEvent(change.nodeID, "problem_tainted", change.condition)
+	return nil
+}
+
+// writeMetrics - 노드 문제 taint 현황과 실행 결과
+func (t *NodeProblemTainter) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	counts := make(map[string]int, len(nodeProblemTaints))
+	for _, conditions := range t.applied {
+		for condition, active := range conditions {
+			if active {
+				counts[condition]++
+			}
+		}
+	}
+	conditions := make([]string, 0, len(nodeProblemTaints))
+	for condition := range nodeProblemTaints {
+		conditions = append(conditions, condition)
+	}
+	sort.Strings(conditions)
+
+	writeMetricHeader(w, "nautilus_node_problem_tainted_nodes", "gauge", "Nodes tainted because a worker reports a node problem condition")
+	for _, condition := range conditions {
+		writeMetric(w, "nautilus_node_problem_tainted_nodes", map[string]string{"condition": condition}, float64(counts[condition]))
+	}
+	writeMetricHeader(w, "nautilus_node_problem_taint_updates_total", "counter", "Node problem taint changes")
+	writeMetric(w, "nautilus_node_problem_taint_updates_total", map[string]string{"outcome": "tainted"}, float64(t.taints))
+	writeMetric(w, "nautilus_node_problem_taint_updates_total", map[string]string{"outcome": "removed"}, float64(t.removed))
+	writeMetric(w, "nautilus_node_problem_taint_updates_total", map[string]string{"outcome": "failed"}, float64(t.failed))
+}
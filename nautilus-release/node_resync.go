@@ -0,0 +1,150 @@
+// Node Resync - 재연결한 워커가 놓친 drain/Pod 삭제를 따라잡도록 노드의 전체 목표 상태 제공
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// DesiredPod - 워커에서 실행 중이어야 하는 Pod (Terminating이면 삭제가 요청된 Pod)
+type DesiredPod struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	UID         string `json:"uid"`
+	Phase       string `json:"phase"`
+	Terminating bool   `json:"terminating,omitempty"`
+}
+
+/*
+NodeDesiredState - 노드의 전체 목표 상태 (state-of-the-world)
+워커는 하트비트 응답의 차등 정보 대신 이 스냅샷과 로컬 실제 상태를 대조한 뒤 정상 동작을 재개합니다.
+*/
+type NodeDesiredState struct {
+	NodeID        string              `json:"node_id"`
+	Pods          []DesiredPod        `json:"pods"`
+	ConfigVersion int64               `json:"config_version"`
+	Config        *WorkerConfigBundle `json:"config,omitempty"`
+	Cordoned      bool                `json:"cordoned"`
+	Maintenance   bool                `json:"maintenance"`
+	WorkerStatus  string              `json:"worker_status"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+}
+
+// Current - 현재 게시된 설정 번들 복사본 (게시 전이거나 nil이면 nil)
+func (c *WorkerConfigSync) Current() *WorkerConfigBundle {
+	if c == nil {
+		return nil
+	}
+	current, _, _ := c.snapshot()
+	return current
+}
+
+/*
+desiredState - K3s에 기록된 노드 배정과 cordon 상태로 목표 상태 구성
+종료된(Succeeded/Failed) Pod은 제외하고, 삭제 중인 Pod은 Terminating으로 표시합니다.
+*/
+func (a *APIServer) desiredState(worker *WorkerNode) (*NodeDesiredState, error) {
+	state := &NodeDesiredState{
+		NodeID:       worker.NodeID,
+		Pods:         []DesiredPod{},
+		Maintenance:  a.maintenance.InMaintenance(worker.NodeID),
+		WorkerStatus: worker.Status,
+		GeneratedAt:  time.Now(),
+	}
+	if bundle := a.workerConfig.Current(); bundle != nil {
+		state.ConfigVersion, state.Config = bundle.Version, bundle
+	}
+
+	output, err := a.k3sMgr.RunKubectl(nil, "get", "node", worker.NodeID, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %v", err)
+	}
+	var node struct {
+		Spec struct {
+			Unschedulable bool `json:"unschedulable"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse node: %v", err)
+	}
+	state.Cordoned = node.Spec.Unschedulable
+
+	output, err = a.k3sMgr.RunKubectl(nil, "get", "pods", "-A", "-o", "json",
+		"--field-selector=spec.nodeName="+worker.NodeID+",status.phase!=Succeeded,status.phase!=Failed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	var pods struct {
+		Items []struct {
+			Metadata struct {
+				Name              string  `json:"name"`
+				Namespace         string  `json:"namespace"`
+				UID               string  `json:"uid"`
+				DeletionTimestamp *string `json:"deletionTimestamp"`
+			} `json:"metadata"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("failed to parse pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		state.Pods = append(state.Pods, DesiredPod{
+			Namespace:   pod.Metadata.Namespace,
+			Name:        pod.Metadata.Name,
+			UID:         pod.Metadata.UID,
+			Phase:       pod.Status.Phase,
+			Terminating: pod.Metadata.DeletionTimestamp != nil,
+		})
+	}
+	sort.Slice(state.Pods, func(i, j int) bool {
+		if state.Pods[i].Namespace != state.Pods[j].Namespace {
+			return state.Pods[i].Namespace < state.Pods[j].Namespace
+		}
+		return state.Pods[i].Name < state.Pods[j].Name
+	})
+	return state, nil
+}
+
+// handleNodeDesiredState - 재시작/재연결한 워커의 전체 동기화 요청 (Seal 토큰 인증, ?node_id=)
+func (a *APIServer) handleNodeDesiredState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nodeID := r.URL.Query().Get("node_id")
+	if nodeID == "" {
+		http.Error(w, "node_id is required", http.StatusBadRequest)
+		return
+	}
+	worker, exists := a.k3sMgr.workerPool.GetWorker(nodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if worker.SealToken != "" && r.Header.Get("X-Seal-Token") != worker.SealToken {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	state, err := a.desiredState(worker)
+	if err != nil {
+		a.logger.Errorf("❌ Failed to build desired state for %s: %v", nodeID, err)
+		http.Error(w, "Desired state unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	a.logger.Infof("🔁 Worker %s requested full resync: %d pods, config v%d, cordoned=%t",
+		nodeID, len(state.Pods), state.ConfigVersion, state.Cordoned)
+	a.history.RecordEvent(nodeID, "resync_requested", fmt.Sprintf("%d pods, config v%d", len(state.Pods), state.ConfigVersion))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(dataResponse(state))
+}
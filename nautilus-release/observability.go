@@ -0,0 +1,302 @@
+// Observability Bundle - 노드/스테이크/큐 상태 익스포터와 Grafana 대시보드 배포
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+/metrics의 컴포넌트별 지표는 각자의 동작(카운터, 실패)을 다루고, 이 익스포터는 운영자가 대시보드 첫 화면에
+두는 상태(상태별 워커 수, 워커별 스테이크와 하트비트 경과, 이벤트 큐, 처리 중 요청)를 같은 /metrics에 추가합니다.
+
+observability/의 대시보드 JSON은 이 지표와 기존 지표(readiness, chain_outbox, slo, chain_health 등)를 쓰며
+Prometheus 데이터 소스를 ${datasource} 변수로 고르므로 어느 Grafana에든 그대로 가져올 수 있습니다.
+
+	GET  /api/v1/observability/dashboards         내장 대시보드 목록
+	GET  /api/v1/observability/dashboards/<uid>   대시보드 JSON (수동 가져오기용)
+	POST /api/v1/observability/dashboards/push    GRAFANA_URL로 모든 대시보드 배포 (같은 uid는 덮어씀)
+
+배포는 Grafana 서비스 계정 토큰(GRAFANA_API_TOKEN)으로 /api/dashboards/db를 호출하고,
+GRAFANA_FOLDER_UID가 있으면 그 폴더에 둡니다. 체인 대시보드의 chain_health 패널은 ChainHealth 게이트가 필요합니다.
+*/
+
+//go:embed observability/*.json
+var observabilityDashboards embed.FS
+
+// DashboardInfo - 내장 대시보드 요약
+type DashboardInfo struct {
+	UID   string   `json:"uid"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+// DashboardPushResult - 대시보드 하나의 Grafana 배포 결과
+type DashboardPushResult struct {
+	UID     string `json:"uid"`
+	Status  string `json:"status"` // pushed, failed
+	URL     string `json:"url,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ObservabilityBundle - 상태 익스포터와 대시보드 배포
+type ObservabilityBundle struct {
+	logger       *logrus.Logger
+	sui          *SuiIntegration
+	workerPool   *WorkerPool
+	adminToken   string
+	grafanaURL   string
+	grafanaToken string
+	folderUID    string
+	client       *http.Client
+	dashboards   map[string]json.RawMessage // uid → 대시보드 JSON
+
+	mutex    sync.Mutex
+	pushes   map[string]uint64 // pushed, failed
+	lastPush time.Time
+}
+
+// NewObservabilityBundle - 새 Observability Bundle 생성 (내장 대시보드는 컴파일 시 고정이라 파싱 실패는 버그)
+func NewObservabilityBundle(logger *logrus.Logger, sui *SuiIntegration, workerPool *WorkerPool) *ObservabilityBundle {
+	o := &ObservabilityBundle{
+		logger:       logger,
+		sui:          sui,
+		workerPool:   workerPool,
+		adminToken:   os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		grafanaURL:   strings.TrimSuffix(os.Getenv("GRAFANA_URL"), "/"),
+		grafanaToken: os.Getenv("GRAFANA_API_TOKEN"),
+		folderUID:    os.Getenv("GRAFANA_FOLDER_UID"),
+		client:       &http.Client{Timeout: 15 * time.Second},
+		dashboards:   make(map[string]json.RawMessage),
+		pushes:       make(map[string]uint64),
+	}
+
+	files, err := observabilityDashboards.ReadDir("observability")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		data, err := observabilityDashboards.ReadFile(path.Join("observability", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var info DashboardInfo
+		if err := json.Unmarshal(data, &info); err != nil || info.UID == "" {
+			panic(fmt.Sprintf("invalid embedded dashboard %s: %v", file.Name(), err))
+		}
+		o.dashboards[info.UID] = data
+	}
+	return o
+}
+
+// Dashboards - 내장 대시보드 목록 (uid 순)
+func (o *ObservabilityBundle) Dashboards() []DashboardInfo {
+	uids := make([]string, 0, len(o.dashboards))
+	for uid := range o.dashboards {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	infos := make([]DashboardInfo, 0, len(uids))
+	for _, uid := range uids {
+		var info DashboardInfo
+		json.Unmarshal(o.dashboards[uid], &info)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Push - 모든 내장 대시보드를 Grafana에 배포 (하나가 실패해도 나머지는 계속)
+func (o *ObservabilityBundle) Push() ([]DashboardPushResult, error) {
+	if o.grafanaURL == "" {
+		return nil, fmt.Errorf("GRAFANA_URL is not set")
+	}
+
+	var results []DashboardPushResult
+	for _, info := range o.Dashboards() {
+		result := DashboardPushResult{UID: info.UID, Status: "pushed"}
+		if err := o.pushDashboard(info.UID, &result); err != nil {
+			result.Status, result.Error = "failed", err.Error()
+			o.logger.Warnf("⚠️ Failed to push Grafana dashboard %s: %v", info.UID, err)
+		} else {
+			o.logger.Infof("📊 Pushed Grafana dashboard %s (version %d)", info.UID, result.Version)
+		}
+		results = append(results, result)
+
+		o.mutex.Lock()
+		o.pushes[result.Status]++
+		o.mutex.Unlock()
+	}
+
+	o.mutex.Lock()
+	o.lastPush = time.Now()
+	o.mutex.Unlock()
+	return results, nil
+}
+
+// pushDashboard - Grafana POST /api/dashboards/db
+func (o *ObservabilityBundle) pushDashboard(uid string, result *DashboardPushResult) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"dashboard": o.dashboards[uid],
+		"folderUid": o.folderUID,
+		"overwrite": true,
+		"message":   "Provisioned by nautilus " + getEnvOrDefault("NAUTILUS_REGION", "default"),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, o.grafanaURL+"/api/dashboards/db", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.grafanaToken != "" {
+		req.Header.Set("Authorization", "Bearer "+o.grafanaToken)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("grafana returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var response struct {
+		URL     string `json:"url"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(data, &response); err == nil {
+		result.URL = o.grafanaURL + response.URL
+		result.Version = response.Version
+	}
+	return nil
+}
+
+func (o *ObservabilityBundle) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if o.adminToken == "" {
+		http.Error(w, "Observability API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(o.adminToken)) != 1 {
+		o.logger.Warnf("🚫 Unauthorized observability API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleDashboards - /api/v1/observability/dashboards[/<uid>|/push] (관리자 토큰 필요)
+func (o *ObservabilityBundle) handleDashboards(w http.ResponseWriter, r *http.Request) {
+	if !o.authorize(w, r) {
+		return
+	}
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/observability/dashboards"), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		writeClientJSON(w, o.Dashboards())
+
+	case name == "push" && r.Method == http.MethodPost:
+		results, err := o.Push()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		for _, result := range results {
+			if result.Status != "pushed" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadGateway)
+				json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "data": results})
+				return
+			}
+		}
+		writeClientJSON(w, results)
+
+	case name != "" && name != "push" && r.Method == http.MethodGet:
+		dashboard, ok := o.dashboards[name]
+		if !ok {
+			http.Error(w, "Dashboard not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".json"))
+		w.Write(dashboard)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 워커/스테이크/큐 상태 지표와 대시보드 배포 결과
+func (o *ObservabilityBundle) writeMetrics(w io.Writer) {
+	workers := o.workerPool.ListWorkers()
+	sort.Slice(workers, func(i, j int) bool { return workers[i].NodeID < workers[j].NodeID })
+	now := time.Now()
+
+	stats := o.workerPool.GetWorkerStats()
+	delete(stats, "total")
+	statuses := make([]string, 0, len(stats))
+	for status := range stats {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	writeMetricHeader(w, "nautilus_workers", "gauge", "Workers in the pool by status")
+	for _, status := range statuses {
+		writeMetric(w, "nautilus_workers", map[string]string{"status": status}, float64(stats[status]))
+	}
+	var staked uint64
+	for _, worker := range workers {
+		staked += worker.StakeAmount
+	}
+	writeMetricHeader(w, "nautilus_staked_mist", "gauge", "Stake of all workers in the pool in MIST")
+	writeMetric(w, "nautilus_staked_mist", nil, float64(staked))
+
+	writeMetricHeader(w, "nautilus_worker_stake_mist", "gauge", "Stake of each worker in MIST")
+	for _, worker := range workers {
+		writeMetric(w, "nautilus_worker_stake_mist", map[string]string{"node_id": worker.NodeID, "region": worker.Region}, float64(worker.StakeAmount))
+	}
+	writeMetricHeader(w, "nautilus_worker_heartbeat_age_seconds", "gauge", "Seconds since each worker's last heartbeat")
+	for _, worker := range workers {
+		if worker.LastHeartbeat.IsZero() {
+			continue
+		}
+		writeMetric(w, "nautilus_worker_heartbeat_age_seconds", map[string]string{"node_id": worker.NodeID}, now.Sub(worker.LastHeartbeat).Seconds())
+	}
+
+	depth, capacity := o.sui.EventQueueDepth()
+	writeMetricHeader(w, "nautilus_event_queue_depth", "gauge", "Chain events waiting to be processed")
+	writeMetric(w, "nautilus_event_queue_depth", nil, float64(depth))
+	writeMetricHeader(w, "nautilus_event_queue_capacity", "gauge", "Capacity of the chain event queue")
+	writeMetric(w, "nautilus_event_queue_capacity", nil, float64(capacity))
+	writeMetricHeader(w, "nautilus_requests_in_flight", "gauge", "K8s API requests from chain events currently executing")
+	writeMetric(w, "nautilus_requests_in_flight", nil, float64(len(o.sui.InFlightRequests())))
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	writeMetricHeader(w, "nautilus_grafana_dashboard_pushes_total", "counter", "Grafana dashboard pushes by outcome")
+	for _, status := range []string{"pushed", "failed"} {
+		writeMetric(w, "nautilus_grafana_dashboard_pushes_total", map[string]string{"status": status}, float64(o.pushes[status]))
+	}
+	if !o.lastPush.IsZero() {
+		writeMetricHeader(w, "nautilus_grafana_last_push_timestamp_seconds", "gauge", "Unix time of the last Grafana dashboard push")
+		writeMetric(w, "nautilus_grafana_last_push_timestamp_seconds", nil, float64(o.lastPush.Unix()))
+	}
+}
@@ -0,0 +1,175 @@
+// OIDC Discovery - 서비스 계정 토큰 발급자의 OpenID Connect 디스커버리 문서와 JWKS (클라우드 워크로드 ID 연동)
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+/*
+AWS IAM, GCP Workload Identity Federation, Azure 워크로드 ID는 토큰의 iss로 디스커버리 문서를 찾아
+JWKS로 서명을 검증하므로, 발급자 URL은 외부에서 HTTPS로 접근할 수 있어야 합니다.
+
+	<issuer>/.well-known/openid-configuration   디스커버리 문서
+	/openid/v1/jwks                              RS256 공개키 (K8s API 서버와 같은 경로)
+
+	NAUTILUS_SA_ISSUER                발급자 URL (기본 NAUTILUS_PUBLIC_URL, 둘 다 없으면 https://nautilus.k3s-daas.local)
+	NAUTILUS_SA_JWKS_URI              디스커버리 문서의 jwks_uri (기본 <issuer>/openid/v1/jwks, 별도 호스팅 시 변경)
+	NAUTILUS_SA_SIGNING_KEY_FILE      RSA 서명 키 PEM (리전 마스터들이 같은 발급자를 쓸 때 가져와 봉인, 없으면 엔클레이브에서 생성)
+*/
+
+const (
+	defaultServiceAccountIssuer = "https://nautilus.k3s-daas.local"
+	oidcDiscoveryPath           = "/.well-known/openid-configuration"
+	oidcJWKSPath                = "/openid/v1/jwks"
+	serviceAccountRSABits       = 2048
+)
+
+// serviceAccountIssuerURL - 토큰 iss이자 kubeconfig에 안내하는 발급자 URL
+func serviceAccountIssuerURL() string {
+	if issuer := os.Getenv("NAUTILUS_SA_ISSUER"); issuer != "" {
+		return strings.TrimSuffix(issuer, "/")
+	}
+	if public := os.Getenv("NAUTILUS_PUBLIC_URL"); public != "" {
+		return strings.TrimSuffix(public, "/")
+	}
+	return defaultServiceAccountIssuer
+}
+
+// checkIssuerURL - OIDC 발급자 요건 (https, 쿼리/프래그먼트 없음)
+func checkIssuerURL(issuer string) error {
+	parsed, err := url.Parse(issuer)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("issuer %q is not an absolute URL", issuer)
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("issuer %q must use https for OIDC federation", issuer)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("issuer %q must not contain a query or fragment", issuer)
+	}
+	return nil
+}
+
+// serviceAccountSigningKey - RS256 서명 키 (NAUTILUS_SA_SIGNING_KEY_FILE 또는 이전 평문 상태 파일을 가져와 봉인)
+func serviceAccountSigningKey() enclaveKeySpec {
+	return enclaveKeySpec{
+		name:       "service-account-signing",
+		importFile: "NAUTILUS_SA_SIGNING_KEY_FILE",
+		legacyFile: statePath("service-account-signing.key"),
+		parse: func(data []byte) (crypto.Signer, error) {
+			return parseRSAPrivateKey(data)
+		},
+		generate: func() (crypto.Signer, error) {
+			return rsa.GenerateKey(rand.Reader, serviceAccountRSABits)
+		},
+	}
+}
+
+// parseRSAPrivateKey - PKCS#1 또는 PKCS#8 PEM
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("service account signing key is not PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account signing key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("service account signing key must be RSA")
+	}
+	if key.N.BitLen() < serviceAccountRSABits {
+		return nil, fmt.Errorf("service account signing key must be at least %d bits", serviceAccountRSABits)
+	}
+	return key, nil
+}
+
+// rsaKeyID - 공개키(PKIX DER) 다이제스트 앞 8바이트
+func rsaKeyID(key *rsa.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(key)
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:8])
+}
+
+// rsaJWK - RS256 공개키 JWK
+func rsaJWK(key *rsa.PublicKey, keyID string) serviceAccountJWK {
+	return serviceAccountJWK{
+		KeyType: "RSA", Use: "sig", Algorithm: serviceAccountTokenAlgorithm, KeyID: keyID,
+		N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// OIDCConfiguration - 디스커버리 문서 (K8s API 서버가 제공하는 것과 같은 필드)
+type OIDCConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+// oidcConfiguration - 현재 발급자의 디스커버리 문서
+func (s *ServiceAccountIssuer) oidcConfiguration() OIDCConfiguration {
+	return OIDCConfiguration{
+		Issuer:                           s.issuer,
+		JWKSURI:                          getEnvOrDefault("NAUTILUS_SA_JWKS_URI", s.issuer+oidcJWKSPath),
+		ResponseTypesSupported:           []string{"id_token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{serviceAccountTokenAlgorithm},
+		ClaimsSupported:                  []string{"iss", "sub", "aud", "exp", "iat", "nbf", "jti"},
+	}
+}
+
+// discoveryPath - 발급자 URL의 경로 아래 디스커버리 문서 위치 (https://host/clusters/a → /clusters/a/.well-known/...)
+func (s *ServiceAccountIssuer) discoveryPath() string {
+	issuerPath := ""
+	if parsed, err := url.Parse(s.issuer); err == nil {
+		issuerPath = parsed.Path
+	}
+	return path.Join("/", issuerPath, oidcDiscoveryPath)
+}
+
+// handleOIDCDiscovery - OpenID Connect 디스커버리 문서 (인증 없음, 클라우드 STS가 주기적으로 조회)
+func (s *ServiceAccountIssuer) handleOIDCDiscovery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(s.oidcConfiguration())
+}
+
+// handleOIDCJWKS - 외부 검증자용 JWKS (RS256 키만, 이전 EdDSA 키는 /api/v1/serviceaccounts/jwks)
+func (s *ServiceAccountIssuer) handleOIDCJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []serviceAccountJWK{rsaJWK(s.rsaPublic, s.rsaKeyID)},
+	})
+}
@@ -0,0 +1,217 @@
+// Package Pin - 설정에 고정한 컨트랙트 패키지 다이제스트를 RPC 응답과 대조 (다른 코드를 내주는 RPC 노드 차단)
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+PackagePin - CONTRACT_PACKAGE_ID 패키지가 배포 때 확인한 바이트코드 그대로인지 검증
+
+Sui 패키지는 불변이고 업그레이드하면 새 패키지 ID가 생기므로, 같은 ID의 다이제스트가 달라졌다면
+RPC 노드가 다른 컨트랙트를 내주고 있는 것입니다. 다음 중 설정된 값을 시작 시와 주기마다 확인합니다.
+
+  - CONTRACT_PACKAGE_DIGEST: sui_getObject의 패키지 오브젝트 digest
+  - CONTRACT_MODULES_SHA256: sui_getNormalizedMoveModulesByPackage 응답(키 정렬 JSON)의 SHA-256
+
+검증 전이거나 불일치하면 해당 패키지의 이벤트를 처리하지 않고 dead-letter 큐로 보냅니다
+(원인을 해결한 뒤 재처리). 확인 주기는 NAUTILUS_PACKAGE_PIN_INTERVAL_SECONDS(기본 600)이며,
+패키지 전환 중 새 패키지는 고정 대상이 아닙니다.
+*/
+type PackagePin struct {
+	logger      *logrus.Logger
+	sui         *SuiIntegration
+	packageID   string
+	digest      string
+	modulesHash string
+	interval    time.Duration
+
+	mutex       sync.RWMutex
+	verified    bool
+	mismatch    string // 마지막 확인에서 다르게 나온 항목 (일치하면 비움)
+	lastChecked time.Time
+	lastErr     error
+	checks      int
+	failures    int
+}
+
+// NewPackagePin - 새 Package Pin 생성
+func NewPackagePin(logger *logrus.Logger, sui *SuiIntegration) *PackagePin {
+	return &PackagePin{
+		logger:      logger,
+		sui:         sui,
+		packageID:   sui.contractAddr,
+		digest:      getEnvOrDefault("CONTRACT_PACKAGE_DIGEST", ""),
+		modulesHash: strings.ToLower(getEnvOrDefault("CONTRACT_MODULES_SHA256", "")),
+		interval:    envSeconds("NAUTILUS_PACKAGE_PIN_INTERVAL_SECONDS", 600),
+	}
+}
+
+// enabled - 고정 값이 설정되어 있고 Sui 체인을 쓰는 경우만 검증
+func (p *PackagePin) enabled() bool {
+	return (p.digest != "" || p.modulesHash != "") && p.sui.onSui()
+}
+
+// Start - 시작 검증 후 주기적 재검증
+func (p *PackagePin) Start(ctx context.Context) {
+	if !p.enabled() {
+		p.logger.Warnf("⚠️ Contract package pin disabled (CONTRACT_PACKAGE_DIGEST / CONTRACT_MODULES_SHA256 not set or %s chain backend)", p.sui.backend.Name())
+		return
+	}
+
+	p.logger.Infof("📌 Starting contract package pin for %s...", p.packageID)
+	p.verify()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.verify()
+		}
+	}
+}
+
+// verify - RPC가 내주는 패키지와 고정 값 비교 (조회 실패 시 이전 판정 유지)
+func (p *PackagePin) verify() {
+	mismatch, err := p.compare()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.checks++
+	if err != nil {
+		p.failures++
+		p.lastErr = err
+		p.logger.Warnf("⚠️ Failed to verify contract package %s: %v", p.packageID, err)
+		return
+	}
+	p.lastErr = nil
+	p.lastChecked = time.Now()
+
+	switch {
+	case mismatch != "":
+		if p.mismatch != mismatch {
+			p.logger.Errorf("🛑 Contract package %s no longer matches its pin (%s), refusing contract events", p.packageID, mismatch)
+		}
+	case p.mismatch != "":
+		p.logger.Infof("✅ Contract package %s matches its pin again, resuming contract events", p.packageID)
+	case !p.verified:
+		p.logger.Infof("✅ Contract package %s matches its pin", p.packageID)
+	}
+	p.mismatch = mismatch
+	p.verified = mismatch == ""
+}
+
+// compare - 설정된 항목별로 비교해 다른 항목 설명 반환 (모두 같으면 "")
+func (p *PackagePin) compare() (string, error) {
+	var mismatches []string
+
+	if p.digest != "" {
+		var object struct {
+			Data *struct {
+				Digest string `json:"digest"`
+				Type   string `json:"type"`
+			} `json:"data"`
+		}
+		options := map[string]bool{"showType": true}
+		if err := p.sui.rpcCall("sui_getObject", []interface{}{p.packageID, options}, &object); err != nil {
+			return "", err
+		}
+		if object.Data == nil || object.Data.Type != "package" {
+			mismatches = append(mismatches, "package object not found")
+		} else if object.Data.Digest != p.digest {
+			mismatches = append(mismatches, fmt.Sprintf("digest %s, expected %s", object.Data.Digest, p.digest))
+		}
+	}
+
+	if p.modulesHash != "" {
+		var modules interface{}
+		if err := p.sui.rpcCall("sui_getNormalizedMoveModulesByPackage", []interface{}{p.packageID}, &modules); err != nil {
+			return "", err
+		}
+		hash, err := normalizedModulesHash(modules)
+		if err != nil {
+			return "", err
+		}
+		if hash != p.modulesHash {
+			mismatches = append(mismatches, fmt.Sprintf("modules sha256 %s, expected %s", hash, p.modulesHash))
+		}
+	}
+	return strings.Join(mismatches, "; "), nil
+}
+
+// normalizedModulesHash - 정규화된 모듈 정의의 SHA-256 (json.Marshal이 맵 키를 정렬하므로 응답 필드 순서와 무관)
+func normalizedModulesHash(modules interface{}) (string, error) {
+	canonical, err := json.Marshal(modules)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Check - 패키지 이벤트 처리 허용 여부 (고정 대상이 아닌 패키지는 통과)
+func (p *PackagePin) Check(packageID string) error {
+	if p == nil || !p.enabled() || packageID != p.packageID {
+		return nil
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	switch {
+	case p.mismatch != "":
+		return NewUserFriendlyError(ErrCodeContractPinned,
+			fmt.Sprintf("Contract package %s does not match its pin: %s", p.packageID, p.mismatch),
+			"SUI_RPC_URL이 신뢰할 수 있는 fullnode인지 확인하고, 패키지를 새로 배포했다면 CONTRACT_PACKAGE_ID와 고정 값을 함께 갱신하세요", nil)
+	case !p.verified:
+		return NewUserFriendlyError(ErrCodeContractPinned,
+			fmt.Sprintf("Contract package %s has not been verified against its pin yet", p.packageID),
+			"RPC 연결을 확인하세요. 검증이 끝나면 dead-letter 큐의 이벤트를 재처리할 수 있습니다", p.lastErr)
+	}
+	return nil
+}
+
+// writeMetrics - 패키지 고정 검증 상태
+func (p *PackagePin) writeMetrics(w io.Writer) {
+	if !p.enabled() {
+		return
+	}
+	p.mutex.RLock()
+	var verified, mismatched float64
+	if p.verified {
+		verified = 1
+	}
+	if p.mismatch != "" {
+		mismatched = 1
+	}
+	checked, checks, failures := p.lastChecked, p.checks, p.failures
+	p.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_contract_package_verified", "gauge", "Whether the contract package matches its configured pin")
+	writeMetric(w, "nautilus_contract_package_verified", nil, verified)
+	writeMetricHeader(w, "nautilus_contract_package_mismatch", "gauge", "Whether the RPC node serves a contract package different from the pin")
+	writeMetric(w, "nautilus_contract_package_mismatch", nil, mismatched)
+	writeMetricHeader(w, "nautilus_contract_package_checks_total", "counter", "Contract package pin checks")
+	writeMetric(w, "nautilus_contract_package_checks_total", nil, float64(checks))
+	writeMetricHeader(w, "nautilus_contract_package_check_failures_total", "counter", "Contract package pin checks that failed to query the RPC node")
+	writeMetric(w, "nautilus_contract_package_check_failures_total", nil, float64(failures))
+	if !checked.IsZero() {
+		writeMetricHeader(w, "nautilus_contract_package_last_check_timestamp", "gauge", "Unix time of the last completed contract package pin check")
+		writeMetric(w, "nautilus_contract_package_last_check_timestamp", nil, float64(checked.Unix()))
+	}
+}
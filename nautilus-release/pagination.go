@@ -0,0 +1,63 @@
+// List Pagination - K8s LIST 요청의 페이지 크기 상한 (limit/continue)
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxListPage = 500
+
+// maxListPage - NAUTILUS_MAX_LIST_PAGE (기본 500, kubectl/client-go 기본 청크 크기와 같음)
+func maxListPage() int {
+	limit, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_MAX_LIST_PAGE", strconv.Itoa(defaultMaxListPage)))
+	if err != nil || limit <= 0 {
+		return defaultMaxListPage
+	}
+	return limit
+}
+
+/*
+isListPath - 컬렉션 경로 여부
+
+	/api/v1/pods, /api/v1/namespaces, /api/v1/namespaces/default/pods,
+	/apis/apps/v1/namespaces/default/deployments → 컬렉션
+	/api/v1/namespaces/default, /api/v1/namespaces/default/pods/web → 단일 객체
+*/
+func isListPath(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return false
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	return len(segments) == 1 && segments[0] != ""
+}
+
+/*
+enforceListLimit - K3s로 보내는 LIST 요청의 limit을 상한으로 제한
+
+LIST 응답은 서명을 위해 통째로 버퍼링되므로 limit이 없거나 큰 요청은 enclave 메모리를 소진할 수 있습니다.
+limit을 줄이면 K3s가 metadata.continue 토큰을 돌려주고, client-go 페이저와 kubectl 청크 조회는
+그 토큰으로 다음 페이지를 이어서 요청합니다. continue 토큰은 K3s가 발급하므로 그대로 전달합니다.
+*/
+func enforceListLimit(req *http.Request, max int) bool {
+	if req.Method != http.MethodGet || isWatchRequest(req) || !isListPath(req.URL.Path) {
+		return false
+	}
+
+	query := req.URL.Query()
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= max {
+		return false
+	}
+	query.Set("limit", strconv.Itoa(max))
+	req.URL.RawQuery = query.Encode()
+	return true
+}
@@ -0,0 +1,98 @@
+// Payload Encryption - 온체인에는 암호문과 해시만 남는 요청 본문 E2E 암호화의 마스터 측 (수신자 키 공지, 승인 전 복호화)
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	apirequest "github.com/k3s-io/daas-apirequest"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+PayloadDecryptor - Gateway가 마스터 엔클레이브 키로 암호화해 제출한 매니페스트 복호화
+
+수신자 키는 봉인된 응답 서명 키에서 파생한 X25519라 재시작/대기 마스터에서도 같고, 서명된 증명 문서
+(/api/v1/attestation의 encryption_key)로 공지되므로 Gateway는 측정값을 확인한 키에만 암호화합니다.
+복호화는 요청 승인(RBAC, 어드미션) 전에 하며, 실패한 요청은 실행하지 않고 오류 응답만 기록합니다.
+봉투 형식은 pkg/apirequest/encryption.go에 있습니다.
+*/
+type PayloadDecryptor struct {
+	logger *logrus.Logger
+	key    EnclaveAgreement
+	keyID  string
+
+	mutex    sync.Mutex
+	outcomes map[string]int // decrypted, failed
+}
+
+// NewPayloadDecryptor - 응답 서명 키에서 수신자 키 파생
+func NewPayloadDecryptor(logger *logrus.Logger, keyring *EnclaveKeyring) (*PayloadDecryptor, error) {
+	key, err := keyring.DeriveX25519(responseSigningKey().name, apirequest.PayloadKDFLabel+" recipient")
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive payload encryption key: %v", err)
+	}
+	keyID := apirequest.PayloadKeyID(key.PublicKey())
+	logger.Infof("🔒 Encrypted request payloads accepted (key %s)", keyID)
+	return &PayloadDecryptor{
+		logger:   logger,
+		key:      key,
+		keyID:    keyID,
+		outcomes: make(map[string]int),
+	}, nil
+}
+
+// PublicKey - 증명 문서에 넣는 수신자 공개키 (base64 X25519)
+func (p *PayloadDecryptor) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(p.key.PublicKey().Bytes())
+}
+
+// Open - 암호화된 본문을 평문으로 바꿈 (평문 요청은 그대로)
+func (p *PayloadDecryptor) Open(request *K8sAPIRequest) error {
+	if !request.PayloadEncrypted() {
+		return nil
+	}
+	err := request.DecryptPayload(p.key)
+	outcome := "decrypted"
+	if err != nil {
+		outcome = "failed"
+		p.logger.Warnf("🔒 Failed to decrypt payload of request %s: %v", request.RequestID, err)
+	}
+	p.mutex.Lock()
+	p.outcomes[outcome]++
+	p.mutex.Unlock()
+	return err
+}
+
+// openPayload - 승인 전 본문 복호화 (게이트가 꺼진 마스터는 암호화된 요청을 실행할 수 없음)
+func (s *SuiIntegration) openPayload(request *K8sAPIRequest) error {
+	if !request.PayloadEncrypted() {
+		return nil
+	}
+	if s.payloads == nil {
+		return fmt.Errorf("payload is encrypted but this master does not accept encrypted payloads (%s feature gate)", featureEncryptedPayloads)
+	}
+	if err := s.payloads.Open(request); err != nil {
+		return fmt.Errorf("encrypted payload rejected: %v", err)
+	}
+	return nil
+}
+
+// writeMetrics - 복호화 결과별 요청 수
+func (p *PayloadDecryptor) writeMetrics(w io.Writer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	outcomes := make([]string, 0, len(p.outcomes))
+	for outcome := range p.outcomes {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	writeMetricHeader(w, "nautilus_encrypted_payloads_total", "counter", "Encrypted request payloads by decryption outcome")
+	for _, outcome := range outcomes {
+		writeMetric(w, "nautilus_encrypted_payloads_total", map[string]string{"outcome": outcome, "key_id": p.keyID}, float64(p.outcomes[outcome]))
+	}
+}
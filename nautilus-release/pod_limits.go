@@ -0,0 +1,250 @@
+// Pod Limits - 워커가 보고한 Pod별 CPU 스로틀링/OOM kill과 제한 강제 여부를 Pod 상태 조건(k3s-daas.io/ResourceLimited)으로 표시
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// podResourceLimitedCondition - CPU 스로틀링, OOM kill, 강제되지 않는 제한을 알리는 Pod 조건
+const podResourceLimitedCondition = "k3s-daas.io/ResourceLimited"
+
+// PodResourceUsage - 워커 하트비트 pod_resources 항목 (cgroup 누적 카운터, OOM kill은 Pod 수명 동안 누적)
+type PodResourceUsage struct {
+	Namespace           string  `json:"namespace"`
+	Pod                 string  `json:"pod"`
+	UID                 string  `json:"uid"`
+	CPULimitMillis      int64   `json:"cpu_limit_millis,omitempty"`
+	MemoryLimitBytes    int64   `json:"memory_limit_bytes,omitempty"`
+	Enforced            bool    `json:"enforced"`
+	CPUPeriods          uint64  `json:"cpu_periods"`
+	CPUThrottledPeriods uint64  `json:"cpu_throttled_periods"`
+	CPUThrottledSeconds float64 `json:"cpu_throttled_seconds"`
+	OOMKills            uint64  `json:"oom_kills"`
+}
+
+// podLimitState - Pod별 직전 카운터와 조건에 반영한 값 (status/reason/OOM 수가 바뀔 때만 다시 patch)
+type podLimitState struct {
+	namespace string
+	pod       string
+	periods   uint64
+	throttled uint64
+	oomKills  uint64
+	status    string // True, False, Unknown (제한이 강제되지 않음)
+	reason    string
+	message   string
+}
+
+/*
+PodLimitTracker - 노드별 Pod 자원 제한 보고를 Pod 조건으로 반영
+CPU 스로틀링은 직전 하트비트 이후 스로틀된 CFS 주기 비율이 NAUTILUS_CPU_THROTTLE_PERCENT(기본 25) 이상일 때,
+OOM kill은 Pod에서 한 번이라도 일어났으면 조건에 표시합니다. 제한이 있는데 워커가 cgroup에 걸지 못한 Pod는 Unknown입니다.
+*/
+type PodLimitTracker struct {
+	logger  *logrus.Logger
+	k3sMgr  *K3sManager
+	history *HeartbeatHistory
+
+	throttlePercent float64
+
+	mutex   sync.Mutex
+	nodes   map[string]map[string]podLimitState // 노드 → Pod UID → 상태
+	patches chan podLimitState
+	patched int
+	failed  int
+}
+
+// NewPodLimitTracker - Pod 자원 제한 추적기 생성
+func NewPodLimitTracker(logger *logrus.Logger, k3sMgr *K3sManager) *PodLimitTracker {
+	throttlePercent, err := strconv.ParseFloat(getEnvOrDefault("NAUTILUS_CPU_THROTTLE_PERCENT", "25"), 64)
+	if err != nil || throttlePercent <= 0 || throttlePercent > 100 {
+		logger.Warnf("⚠️ Invalid NAUTILUS_CPU_THROTTLE_PERCENT, using 25")
+		throttlePercent = 25
+	}
+	t := &PodLimitTracker{
+		logger:          logger,
+		k3sMgr:          k3sMgr,
+		throttlePercent: throttlePercent,
+		nodes:           make(map[string]map[string]podLimitState),
+		patches:         make(chan podLimitState, 256),
+	}
+	go t.run()
+	return t
+}
+
+// Observe - 하트비트의 Pod 목록 반영 (목록에서 빠진 Pod는 잊음)
+func (t *PodLimitTracker) Observe(nodeID string, usage []PodResourceUsage) {
+	t.mutex.Lock()
+	previous := t.nodes[nodeID]
+	current := make(map[string]podLimitState, len(usage))
+	var changed []podLimitState
+	var newOOM []string
+	for _, pod := range usage {
+		if pod.UID == "" {
+			continue
+		}
+		last, seen := previous[pod.UID]
+		state := t.evaluate(nodeID, pod, last, seen)
+		current[pod.UID] = state
+
+		if seen && pod.OOMKills > last.oomKills {
+			newOOM = append(newOOM, fmt.Sprintf("%s/%s (%d total)", pod.Namespace, pod.Pod, pod.OOMKills))
+		}
+		if seen && (state.status != last.status || state.reason != last.reason || state.oomKills != last.oomKills) ||
+			!seen && state.status != "False" {
+			changed = append(changed, state)
+		}
+	}
+	if len(current) == 0 {
+		delete(t.nodes, nodeID)
+	} else {
+		t.nodes[nodeID] = current
+	}
+	t.mutex.Unlock()
+
+	for _, pod := range newOOM {
+		t.logger.Warnf("💥 Worker %s OOM-killed a process in pod %s", nodeID, pod)
+		t.history.RecordEvent(nodeID, "oom_kill", pod)
+	}
+	for _, state := range changed {
+		t.logger.Infof("🧮 Pod %s/%s on %s: %s %s", state.namespace, state.pod, nodeID, podResourceLimitedCondition, state.reason)
+		select {
+		case t.patches <- state:
+		default:
+			// 다음 변경 때 다시 반영되므로 대기열이 가득 차면 버림
+			t.logger.Warnf("⚠️ Pod limit patch queue full, skipping pod %s/%s", state.namespace, state.pod)
+		}
+	}
+}
+
+// evaluate - 직전 카운터와 비교해 조건 값 결정
+func (t *PodLimitTracker) evaluate(nodeID string, pod PodResourceUsage, last podLimitState, seen bool) podLimitState {
+	state := podLimitState{
+		namespace: pod.Namespace,
+		pod:       pod.Pod,
+		periods:   pod.CPUPeriods,
+		throttled: pod.CPUThrottledPeriods,
+		oomKills:  pod.OOMKills,
+		status:    "False",
+		reason:    "WithinLimits",
+	}
+	if (pod.CPULimitMillis > 0 || pod.MemoryLimitBytes > 0) && !pod.Enforced {
+		state.status = "Unknown"
+		state.reason = "LimitsNotEnforced"
+		state.message = fmt.Sprintf("node %s cannot enforce the pod's CPU/memory limits with cgroups", nodeID)
+		return state
+	}
+
+	// 컨테이너 재시작으로 카운터가 줄면 이번 보고는 비율 계산에서 제외
+	var percent float64
+	if seen && pod.CPUPeriods > last.periods && pod.CPUThrottledPeriods >= last.throttled {
+		percent = 100 * float64(pod.CPUThrottledPeriods-last.throttled) / float64(pod.CPUPeriods-last.periods)
+	}
+	reasons := make(map[string]bool)
+	var details []string
+	if percent >= t.throttlePercent {
+		reasons["CPUThrottled"] = true
+		details = append(details, fmt.Sprintf("CPU throttled in %.0f%% of periods since the last heartbeat (%.1fs total)", percent, pod.CPUThrottledSeconds))
+	}
+	if pod.OOMKills > 0 {
+		reasons["OOMKilled"] = true
+		details = append(details, fmt.Sprintf("%d OOM kills", pod.OOMKills))
+	}
+	if len(reasons) > 0 {
+		state.status = "True"
+		state.reason = joinSorted(reasons, "And") // 예: CPUThrottledAndOOMKilled
+		state.message = fmt.Sprintf("on node %s: %s", nodeID, joinSorted(toSet(details), "; "))
+	}
+	return state
+}
+
+func (t *PodLimitTracker) run() {
+	for state := range t.patches {
+		if t.k3sMgr == nil || !t.k3sMgr.IsRunning() {
+			continue
+		}
+		err := t.patchPod(state)
+		t.mutex.Lock()
+		if err != nil {
+			t.failed++
+		} else {
+			t.patched++
+		}
+		t.mutex.Unlock()
+		if err != nil {
+			t.logger.Warnf("⚠️ Failed to update %s condition on pod %s/%s: %v", podResourceLimitedCondition, state.namespace, state.pod, err)
+		}
+	}
+}
+
+// patchPod - Pod status 조건 설정 (conditions는 type 기준 strategic merge라 다른 조건은 그대로)
+func (t *PodLimitTracker) patchPod(state podLimitState) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []map[string]string{{
+			"type":               podResourceLimitedCondition,
+			"status":             state.status,
+			"reason":             state.reason,
+			"message":            state.message,
+			"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.k3sMgr.RunKubectl(nil, "patch", "pod", state.pod, "-n", state.namespace,
+		"--subresource=status", "--type=strategic", "-p", string(body))
+	return err
+}
+
+// writeMetrics - 노드별 스로틀링/OOM/미강제 Pod 수와 patch 결과
+func (t *PodLimitTracker) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	nodes := make([]string, 0, len(t.nodes))
+	for nodeID := range t.nodes {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+	type nodeCounts struct {
+		throttled, unenforced int
+		oomKills              uint64
+	}
+	counts := make(map[string]nodeCounts, len(nodes))
+	for _, nodeID := range nodes {
+		var c nodeCounts
+		for _, state := range t.nodes[nodeID] {
+			switch {
+			case state.status == "Unknown":
+				c.unenforced++
+			case state.reason == "CPUThrottled" || state.reason == "CPUThrottledAndOOMKilled":
+				c.throttled++
+			}
+			c.oomKills += state.oomKills
+		}
+		counts[nodeID] = c
+	}
+
+	writeMetricHeader(w, "nautilus_pod_cpu_throttled_pods", "gauge", "Pods throttled above NAUTILUS_CPU_THROTTLE_PERCENT since their last heartbeat")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_pod_cpu_throttled_pods", map[string]string{"node": nodeID}, float64(counts[nodeID].throttled))
+	}
+	writeMetricHeader(w, "nautilus_pod_oom_kills", "gauge", "OOM kills in running pods as last reported by each worker")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_pod_oom_kills", map[string]string{"node": nodeID}, float64(counts[nodeID].oomKills))
+	}
+	writeMetricHeader(w, "nautilus_pod_limits_unenforced", "gauge", "Pods with CPU/memory limits that the worker could not enforce with cgroups")
+	for _, nodeID := range nodes {
+		writeMetric(w, "nautilus_pod_limits_unenforced", map[string]string{"node": nodeID}, float64(counts[nodeID].unenforced))
+	}
+	writeMetricHeader(w, "nautilus_pod_limit_patches_total", "counter", "Pod condition updates for resource limits")
+	writeMetric(w, "nautilus_pod_limit_patches_total", map[string]string{"outcome": "applied"}, float64(t.patched))
+	writeMetric(w, "nautilus_pod_limit_patches_total", map[string]string{"outcome": "failed"}, float64(t.failed))
+}
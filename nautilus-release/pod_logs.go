@@ -0,0 +1,426 @@
+// Pod Log Archive - 워커가 배송한 Pod 로그 세그먼트 보관 및 Pod/컨테이너/시간 범위 조회
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxPodLogSegmentSize    = 32 << 20
+	defaultPodLogQueryLimit = 1000
+	maxPodLogQueryLimit     = 10000
+	podLogSegmentExt        = ".log.gz"
+	podLogExpireInterval    = time.Hour
+)
+
+// 네임스페이스/Pod/컨테이너 이름 (경로 구성요소로 쓰므로 DNS 이름 규칙만 허용)
+var podLogNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// PodLogLine - 조회 결과 한 줄 (워커 CRI 로그 한 줄에 노드/Pod 정보를 붙인 것)
+type PodLogLine struct {
+	Time      time.Time `json:"time"`
+	NodeID    string    `json:"node_id"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Stream    string    `json:"stream"`
+	Partial   bool      `json:"partial,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// podLogSegment - 보관된 세그먼트 (<노드>/<ns>/<pod>/<container>/<from>-<to>.log.gz)
+type podLogSegment struct {
+	path      string
+	nodeID    string
+	namespace string
+	pod       string
+	container string
+	from      time.Time
+	to        time.Time
+	size      int64
+}
+
+/*
+PodLogArchive - 워커가 봉인해 보낸 gzip 로그 세그먼트 보관소
+워커가 재시작되거나 노드가 빠져도 보관 기간(NAUTILUS_POD_LOG_RETENTION_HOURS, 기본 168시간) 동안 조회할 수 있습니다.
+세그먼트 파일 이름이 시간 범위이므로 조회 시 범위 밖 세그먼트는 열지 않습니다.
+*/
+type PodLogArchive struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	dir        string
+	retention  time.Duration
+	adminToken string
+	slo        *SLOTracker // 오류 예산 소진 중 만료 보류 (선택)
+
+	mutex    sync.Mutex
+	received int
+	rejected int
+	expired  int
+}
+
+// NewPodLogArchive - Pod 로그 보관소 생성
+func NewPodLogArchive(logger *logrus.Logger, workerPool *WorkerPool) *PodLogArchive {
+	hours, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_POD_LOG_RETENTION_HOURS", "168"))
+	if err != nil || hours <= 0 {
+		hours = 168
+	}
+	return &PodLogArchive{
+		logger:     logger,
+		workerPool: workerPool,
+		dir:        statePath("pod-logs"),
+		retention:  time.Duration(hours) * time.Hour,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+	}
+}
+
+// Start - 보관 기간이 지난 세그먼트 주기적 삭제
+func (p *PodLogArchive) Start(ctx context.Context) {
+	p.Expire()
+
+	ticker := time.NewTicker(podLogExpireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if p.slo != nil && p.slo.Defer("pod_log_expiry") {
+				continue
+			}
+			p.Expire()
+		}
+	}
+}
+
+// Store - 세그먼트 저장 (같은 범위의 재전송은 덮어씀)
+func (p *PodLogArchive) Store(nodeID, namespace, pod, container string, from, to int64, data []byte) error {
+	if nodeID == "" || strings.ContainsAny(nodeID, `/\`) || nodeID == "." || nodeID == ".." {
+		return fmt.Errorf("invalid node id %q", nodeID)
+	}
+	for _, name := range []string{namespace, pod, container} {
+		if !podLogNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name %q", name)
+		}
+	}
+	if from <= 0 || to < from {
+		return fmt.Errorf("invalid time range %d-%d", from, to)
+	}
+	if _, err := gzip.NewReader(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("segment is not gzip: %v", err)
+	}
+
+	dir := filepath.Join(p.dir, nodeID, namespace, pod, container)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d%s", from, to, podLogSegmentExt))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to store segment: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to store segment: %v", err)
+	}
+	return nil
+}
+
+// segments - 조건에 맞는 세그먼트 (빈 값은 전체)
+func (p *PodLogArchive) segments(nodeID, namespace, pod, container string) []podLogSegment {
+	glob := func(value string) string {
+		if value == "" {
+			return "*"
+		}
+		return value
+	}
+	paths, _ := filepath.Glob(filepath.Join(p.dir, glob(nodeID), glob(namespace), glob(pod), glob(container), "*"+podLogSegmentExt))
+
+	var segments []podLogSegment
+	for _, path := range paths {
+		rel, err := filepath.Rel(p.dir, path)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 5 {
+			continue
+		}
+		from, to, ok := strings.Cut(strings.TrimSuffix(parts[4], podLogSegmentExt), "-")
+		fromNanos, err1 := strconv.ParseInt(from, 10, 64)
+		toNanos, err2 := strconv.ParseInt(to, 10, 64)
+		info, err3 := os.Stat(path)
+		if !ok || err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		segments = append(segments, podLogSegment{
+			path: path, nodeID: parts[0], namespace: parts[1], pod: parts[2], container: parts[3],
+			from: time.Unix(0, fromNanos), to: time.Unix(0, toNanos), size: info.Size(),
+		})
+	}
+	return segments
+}
+
+// Expire - 마지막 기록 시각이 보관 기간을 넘긴 세그먼트 삭제
+func (p *PodLogArchive) Expire() {
+	cutoff := time.Now().Add(-p.retention)
+	removed := 0
+	for _, segment := range p.segments("", "", "", "") {
+		if segment.to.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(segment.path); err != nil {
+			p.logger.Warnf("⚠️ Failed to remove expired pod log segment %s: %v", segment.path, err)
+			continue
+		}
+		removed++
+		os.Remove(filepath.Dir(segment.path))
+	}
+	if removed > 0 {
+		p.mutex.Lock()
+		p.expired += removed
+		p.mutex.Unlock()
+		p.logger.Infof("🧹 Removed %d expired pod log segments", removed)
+	}
+}
+
+// PurgeNamespaces - 네임스페이스의 세그먼트를 모든 노드에서 삭제 (테넌트 데이터 삭제), 삭제한 세그먼트 수 반환
+func (p *PodLogArchive) PurgeNamespaces(namespaces []string) (int, error) {
+	removed := 0
+	for _, namespace := range namespaces {
+		if !podLogNamePattern.MatchString(namespace) {
+			continue
+		}
+		removed += len(p.segments("", namespace, "", ""))
+		dirs, _ := filepath.Glob(filepath.Join(p.dir, "*", namespace))
+		for _, dir := range dirs {
+			if err := os.RemoveAll(dir); err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %v", dir, err)
+			}
+		}
+	}
+	return removed, nil
+}
+
+// Query - 시간 순 로그 (limit을 넘으면 가장 최근 항목만, truncated=true)
+func (p *PodLogArchive) Query(nodeID, namespace, pod, container string, since, until time.Time, limit int) ([]PodLogLine, bool) {
+	lines := []PodLogLine{}
+	for _, segment := range p.segments(nodeID, namespace, pod, container) {
+		if (!since.IsZero() && segment.to.Before(since)) || (!until.IsZero() && segment.from.After(until)) {
+			continue
+		}
+		file, err := os.Open(segment.path)
+		if err != nil {
+			continue
+		}
+		if reader, err := gzip.NewReader(file); err == nil {
+			scanner := bufio.NewScanner(reader)
+			scanner.Buffer(make([]byte, 64*1024), 1<<20)
+			for scanner.Scan() {
+				line, ok := parseCRILogLine(scanner.Text())
+				if !ok || (!since.IsZero() && line.Time.Before(since)) || (!until.IsZero() && line.Time.After(until)) {
+					continue
+				}
+				line.NodeID, line.Namespace, line.Pod, line.Container = segment.nodeID, segment.namespace, segment.pod, segment.container
+				lines = append(lines, line)
+			}
+		}
+		file.Close()
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	if len(lines) > limit {
+		return lines[len(lines)-limit:], true
+	}
+	return lines, false
+}
+
+// parseCRILogLine - "2024-01-02T03:04:05.123456789Z stdout F message"
+func parseCRILogLine(text string) (PodLogLine, bool) {
+	fields := strings.SplitN(text, " ", 4)
+	if len(fields) < 3 {
+		return PodLogLine{}, false
+	}
+	ts, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return PodLogLine{}, false
+	}
+	line := PodLogLine{Time: ts, Stream: fields[1], Partial: fields[2] == "P"}
+	if len(fields) == 4 {
+		line.Message = fields[3]
+	}
+	return line, true
+}
+
+/*
+handleSegments - 워커의 봉인 세그먼트 수신 (/api/v1/logs/segments, Seal 토큰 필요)
+
+	POST ?node_id=&namespace=&pod=&container=&from=&to=  (본문: gzip CRI 로그, from/to는 유닉스 나노초)
+*/
+func (p *PodLogArchive) handleSegments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	nodeID := query.Get("node_id")
+	worker, exists := p.workerPool.GetWorker(nodeID)
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if worker.SealToken == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Seal-Token")), []byte(worker.SealToken)) != 1 {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxPodLogSegmentSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read segment", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxPodLogSegmentSize {
+		http.Error(w, "Segment too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	from, _ := strconv.ParseInt(query.Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(query.Get("to"), 10, 64)
+	if err := p.Store(nodeID, query.Get("namespace"), query.Get("pod"), query.Get("container"), from, to, data); err != nil {
+		p.mutex.Lock()
+		p.rejected++
+		p.mutex.Unlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mutex.Lock()
+	p.received++
+	p.mutex.Unlock()
+	p.logger.Debugf("📜 Stored pod log segment from %s: %s/%s/%s (%d bytes)", nodeID, query.Get("namespace"), query.Get("pod"), query.Get("container"), len(data))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "success"})
+}
+
+/*
+handleLogs - 보관된 Pod 로그 조회 (/api/v1/logs, 관리자 토큰 필요)
+
+	GET ?pod=&namespace=&container=&node_id=&since=&until=&limit=
+	since/until은 RFC3339 시각 또는 1h 같은 상대 시간
+*/
+func (p *PodLogArchive) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if p.adminToken == "" {
+		http.Error(w, "Pod log API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(p.adminToken)) != 1 {
+		p.logger.Warnf("🚫 Unauthorized pod log access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	namespace := getQueryOrDefault(query, "namespace", "default")
+	pod, container, nodeID := query.Get("pod"), query.Get("container"), query.Get("node_id")
+	if pod == "" {
+		http.Error(w, "pod is required", http.StatusBadRequest)
+		return
+	}
+	for _, name := range []string{namespace, pod, container, nodeID} {
+		if strings.ContainsAny(name, `/\*?[`) || name == "." || name == ".." {
+			http.Error(w, fmt.Sprintf("invalid name %q", name), http.StatusBadRequest)
+			return
+		}
+	}
+	since, err := parsePodLogTime(query.Get("since"))
+	if err != nil {
+		http.Error(w, "Invalid since: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	until, err := parsePodLogTime(query.Get("until"))
+	if err != nil {
+		http.Error(w, "Invalid until: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := defaultPodLogQueryLimit
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit > maxPodLogQueryLimit {
+		limit = maxPodLogQueryLimit
+	}
+
+	lines, truncated := p.Query(nodeID, namespace, pod, container, since, until, limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "success",
+		"entries":   lines,
+		"truncated": truncated,
+	})
+}
+
+func getQueryOrDefault(query url.Values, key, defaultValue string) string {
+	if value := query.Get(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func parsePodLogTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-ago), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// writeMetrics - 보관 중인 세그먼트와 수신 결과 메트릭
+func (p *PodLogArchive) writeMetrics(w io.Writer) {
+	segments := p.segments("", "", "", "")
+	var size int64
+	for _, segment := range segments {
+		size += segment.size
+	}
+	p.mutex.Lock()
+	received, rejected, expired := p.received, p.rejected, p.expired
+	p.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_pod_log_segments", "gauge", "Pod log segments kept in the archive")
+	writeMetric(w, "nautilus_pod_log_segments", nil, float64(len(segments)))
+	writeMetricHeader(w, "nautilus_pod_log_bytes", "gauge", "Compressed size of archived pod log segments")
+	writeMetric(w, "nautilus_pod_log_bytes", nil, float64(size))
+	writeMetricHeader(w, "nautilus_pod_log_segments_total", "counter", "Pod log segments received from workers, rejected, and expired")
+	writeMetric(w, "nautilus_pod_log_segments_total", map[string]string{"outcome": "received"}, float64(received))
+	writeMetric(w, "nautilus_pod_log_segments_total", map[string]string{"outcome": "rejected"}, float64(rejected))
+	writeMetric(w, "nautilus_pod_log_segments_total", map[string]string{"outcome": "expired"}, float64(expired))
+}
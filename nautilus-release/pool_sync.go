@@ -0,0 +1,404 @@
+// Pool Sync - 온체인 WorkerRegistry 기준으로 워커 풀 재구성 및 주기적 정합성 검사
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chainWorkerFields - worker_registry::WorkerNode의 JSON 표현 (u64는 문자열)
+type chainWorkerFields struct {
+	NodeID        string `json:"node_id"`
+	Owner         string `json:"owner"`
+	StakeAmount   string `json:"stake_amount"`
+	Role          string `json:"role"`
+	Status        string `json:"status"`
+	SealToken     string `json:"seal_token"`
+	JoinToken     string `json:"join_token"`
+	RegisteredAt  string `json:"registered_at"`
+	LastHeartbeat string `json:"last_heartbeat"`
+}
+
+// PoolSync - 워커 풀과 컨트랙트 상태 동기화
+type PoolSync struct {
+	logger        *logrus.Logger
+	sui           *SuiIntegration
+	workerPool    *WorkerPool
+	interval      time.Duration
+	tableIDs      map[string]string // 레지스트리 객체 → workers 테이블 ID
+	divergence    map[string]uint64 // 필드별 누적 불일치 횟수
+	onChain       int
+	lastReconcile time.Time
+	lastErr       error
+	lastReport    *DriftReport
+	adminToken    string
+	mutex         sync.RWMutex
+}
+
+// NewPoolSync - 새 Pool Sync 생성 (POOL_RECONCILE_INTERVAL_SECONDS로 주기 조정)
+func NewPoolSync(logger *logrus.Logger, sui *SuiIntegration) *PoolSync {
+	return &PoolSync{
+		logger:     logger,
+		sui:        sui,
+		workerPool: sui.workerPool,
+		interval:   envSeconds("POOL_RECONCILE_INTERVAL_SECONDS", 600),
+		tableIDs:   make(map[string]string),
+		divergence: make(map[string]uint64),
+		adminToken: getEnvOrDefault("NAUTILUS_ADMIN_TOKEN", ""),
+	}
+}
+
+// Start - 주기적 전체 정합성 검사 (시작 시 재구성은 Rebuild로 별도 수행)
+func (p *PoolSync) Start(ctx context.Context) {
+	// 레지스트리 재구성은 Sui 레지스트리 객체를 직접 읽음 (mock은 등록 이벤트로 충분)
+	if !p.sui.onSui() {
+		p.logger.Warnf("⚠️ Worker pool reconciliation disabled on %s chain backend", p.sui.backend.Name())
+		return
+	}
+
+	p.logger.Info("🔁 Starting worker pool reconciliation...")
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Reconcile(); err != nil {
+				p.logger.Errorf("❌ Worker pool reconciliation failed: %v", err)
+			}
+		}
+	}
+}
+
+// Rebuild - 시작 시 온체인 워커 목록으로 풀 재구성
+func (p *PoolSync) Rebuild() error {
+	if !p.sui.onSui() {
+		return nil
+	}
+
+	if err := p.Reconcile(); err != nil {
+		return fmt.Errorf("failed to rebuild worker pool from chain: %v", err)
+	}
+
+	p.mutex.RLock()
+	count := p.onChain
+	p.mutex.RUnlock()
+	p.logger.Infof("✅ Worker pool rebuilt from chain: %d workers", count)
+	return nil
+}
+
+// restore - 재시작 체크포인트의 테이블 ID와 마지막 정합성 검사 시각 복원 (다음 주기 검사가 전체 비교)
+func (p *PoolSync) restore(tableIDs map[string]string, reconciledAt time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for registry, tableID := range tableIDs {
+		p.tableIDs[registry] = tableID
+	}
+	p.lastReconcile = reconciledAt
+}
+
+// checkpointState - 재시작 체크포인트에 넣을 테이블 ID와 마지막 정합성 검사 시각
+func (p *PoolSync) checkpointState() (map[string]string, time.Time) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	tableIDs := make(map[string]string, len(p.tableIDs))
+	for registry, tableID := range p.tableIDs {
+		tableIDs[registry] = tableID
+	}
+	return tableIDs, p.lastReconcile
+}
+
+// Loaded - 온체인 상태로 풀을 한 번 이상 재구성했는지 (Sui 외 백엔드는 항상 true)
+func (p *PoolSync) Loaded() bool {
+	if !p.sui.onSui() {
+		return true
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return !p.lastReconcile.IsZero()
+}
+
+// Reconcile - 온체인 전체 워커와 로컬 풀을 비교하여 반영하고 드리프트 보고서 기록
+// 패키지 전환 중에는 두 레지스트리의 합집합 (같은 노드는 새 레지스트리 기준)
+func (p *PoolSync) Reconcile() error {
+	var workers []*WorkerNode
+	var err error
+	registered := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, registry := range p.sui.workerRegistries() {
+		var fetched []*WorkerNode
+		fetched, err = p.fetchWorkers(registry)
+		if err != nil {
+			break
+		}
+		for _, worker := range fetched {
+			registered[registry] = append(registered[registry], worker.NodeID)
+			if !seen[worker.NodeID] {
+				seen[worker.NodeID] = true
+				workers = append(workers, worker)
+			}
+		}
+	}
+	if err == nil && p.sui.migration != nil {
+		p.sui.migration.ObserveRegistries(registered)
+	}
+
+	p.mutex.Lock()
+	p.lastErr = err
+	if err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+
+	report := &DriftReport{GeneratedAt: time.Now(), OnChainWorkers: len(workers)}
+	onChain := make(map[string]bool, len(workers))
+	for _, worker := range workers {
+		onChain[worker.NodeID] = true
+		if !p.admit(worker) {
+			continue
+		}
+		for _, drift := range p.workerPool.SyncFromChain(worker) {
+			p.divergence[drift.Field]++
+			report.add(DriftEntry{Kind: driftKind(drift.Field), NodeID: worker.NodeID, Local: drift.Local, Chain: drift.Chain, Action: driftHealed})
+		}
+	}
+	for _, nodeID := range p.workerPool.PruneMissing(onChain) {
+		p.divergence["extra"]++
+		p.sui.history.RecordEvent(nodeID, "pool_sync", "removed: not registered on chain")
+		report.add(DriftEntry{Kind: driftKind("extra"), NodeID: nodeID, Action: driftHealed})
+	}
+
+	p.onChain = len(workers)
+	p.lastReconcile = time.Now()
+	p.mutex.Unlock()
+
+	// 노드/Pod 비교는 kubectl 호출이 있어 잠금 밖에서
+	if clusterErr := p.checkCluster(report); clusterErr != nil {
+		report.ClusterError = clusterErr.Error()
+	} else {
+		report.ClusterChecked = true
+	}
+	report.PoolWorkers = len(p.workerPool.ListWorkers())
+
+	p.mutex.Lock()
+	p.recordReport(report)
+	p.mutex.Unlock()
+	return nil
+}
+
+// SyncWorker - 이벤트로 알게 된 미등록 워커를 컨트랙트에서 조회하여 추가
+func (p *PoolSync) SyncWorker(nodeID string) error {
+	if p == nil {
+		return fmt.Errorf("pool sync not configured")
+	}
+
+	// 전환 중에는 새 레지스트리부터 찾음
+	var worker *WorkerNode
+	var err error
+	for _, registry := range p.sui.workerRegistries() {
+		var tableID string
+		tableID, err = p.workersTableID(registry)
+		if err != nil {
+			return err
+		}
+
+		var object suiObjectResponse
+		name := map[string]interface{}{"type": "0x1::string::String", "value": nodeID}
+		if err = p.sui.rpcCall("suix_getDynamicFieldObject", []interface{}{tableID, name}, &object); err != nil {
+			err = fmt.Errorf("failed to fetch worker %s: %v", nodeID, err)
+			continue
+		}
+		if worker, err = object.worker(); err == nil {
+			break
+		}
+	}
+	if worker == nil {
+		return err
+	}
+
+	if !p.admit(worker) {
+		return nil
+	}
+	diverged := p.workerPool.SyncFromChain(worker)
+	p.mutex.Lock()
+	for _, drift := range diverged {
+		p.divergence[drift.Field]++
+	}
+	p.mutex.Unlock()
+	return nil
+}
+
+// admit - 풀에 없는 온체인 워커는 등록 승인을 받은 경우에만 추가 (승인 모드)
+func (p *PoolSync) admit(worker *WorkerNode) bool {
+	if _, exists := p.workerPool.GetWorker(worker.NodeID); exists {
+		return true
+	}
+	return p.sui.enrollment.Admit(worker)
+}
+
+// workersTableID - WorkerRegistry.workers 테이블 ID (레지스트리 객체에서 한 번 조회)
+func (p *PoolSync) workersTableID(registryID string) (string, error) {
+	p.mutex.RLock()
+	tableID := p.tableIDs[registryID]
+	p.mutex.RUnlock()
+	if tableID != "" {
+		return tableID, nil
+	}
+
+	var registry struct {
+		Data struct {
+			Content struct {
+				Fields struct {
+					Workers struct {
+						Fields struct {
+							ID struct {
+								ID string `json:"id"`
+							} `json:"id"`
+						} `json:"fields"`
+					} `json:"workers"`
+				} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	options := map[string]bool{"showContent": true}
+	if err := p.sui.rpcCall("sui_getObject", []interface{}{registryID, options}, &registry); err != nil {
+		return "", fmt.Errorf("failed to fetch worker registry: %v", err)
+	}
+
+	tableID = registry.Data.Content.Fields.Workers.Fields.ID.ID
+	if tableID == "" {
+		return "", fmt.Errorf("worker registry %s has no workers table", registryID)
+	}
+
+	p.mutex.Lock()
+	p.tableIDs[registryID] = tableID
+	p.mutex.Unlock()
+	return tableID, nil
+}
+
+// suiObjectResponse - Table 엔트리(dynamic field) 객체 응답
+type suiObjectResponse struct {
+	Data struct {
+		Content struct {
+			Fields struct {
+				Value struct {
+					Fields chainWorkerFields `json:"fields"`
+				} `json:"value"`
+			} `json:"fields"`
+		} `json:"content"`
+	} `json:"data"`
+}
+
+// worker - 온체인 WorkerNode를 로컬 WorkerNode로 변환
+func (o *suiObjectResponse) worker() (*WorkerNode, error) {
+	fields := o.Data.Content.Fields.Value.Fields
+	if fields.NodeID == "" {
+		return nil, fmt.Errorf("dynamic field object is not a worker entry")
+	}
+
+	stake, err := strconv.ParseUint(fields.StakeAmount, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stake_amount for %s: %v", fields.NodeID, err)
+	}
+
+	worker := &WorkerNode{
+		NodeID:        fields.NodeID,
+		SealToken:     fields.SealToken,
+		Status:        fields.Status,
+		StakeAmount:   stake,
+		JoinToken:     fields.JoinToken,
+		WorkerAddress: fields.Owner,
+		Role:          fields.Role,
+	}
+	if ms, err := strconv.ParseInt(fields.RegisteredAt, 10, 64); err == nil && ms > 0 {
+		worker.RegisteredAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(fields.LastHeartbeat, 10, 64); err == nil && ms > 0 {
+		worker.LastHeartbeat = time.UnixMilli(ms)
+	}
+	return worker, nil
+}
+
+// fetchWorkers - 레지스트리의 workers 테이블 전체 조회 (페이지 단위)
+func (p *PoolSync) fetchWorkers(registryID string) ([]*WorkerNode, error) {
+	tableID, err := p.workersTableID(registryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var workers []*WorkerNode
+	var cursor interface{}
+	for {
+		var page struct {
+			Data []struct {
+				ObjectID string `json:"objectId"`
+			} `json:"data"`
+			NextCursor  *string `json:"nextCursor"`
+			HasNextPage bool    `json:"hasNextPage"`
+		}
+		if err := p.sui.rpcCall("suix_getDynamicFields", []interface{}{tableID, cursor, 50}, &page); err != nil {
+			return nil, fmt.Errorf("failed to list workers: %v", err)
+		}
+
+		if len(page.Data) > 0 {
+			ids := make([]string, len(page.Data))
+			for i, field := range page.Data {
+				ids[i] = field.ObjectID
+			}
+
+			var objects []suiObjectResponse
+			options := map[string]bool{"showContent": true}
+			if err := p.sui.rpcCall("sui_multiGetObjects", []interface{}{ids, options}, &objects); err != nil {
+				return nil, fmt.Errorf("failed to fetch worker objects: %v", err)
+			}
+			for i := range objects {
+				worker, err := objects[i].worker()
+				if err != nil {
+					p.logger.Warnf("⚠️ Skipping worker entry: %v", err)
+					continue
+				}
+				workers = append(workers, worker)
+			}
+		}
+
+		if !page.HasNextPage || page.NextCursor == nil {
+			return workers, nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// writeMetrics - 풀 동기화 메트릭 출력
+func (p *PoolSync) writeMetrics(w io.Writer) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_pool_divergence_total", "counter", "Worker pool fields corrected from on-chain state")
+	for _, field := range []string{"missing", "extra", "status", "stake_amount", "seal_token", "owner", "role"} {
+		writeMetric(w, "nautilus_pool_divergence_total", map[string]string{"field": field}, float64(p.divergence[field]))
+	}
+
+	writeMetricHeader(w, "nautilus_pool_onchain_workers", "gauge", "Workers registered in the on-chain registry at the last reconciliation")
+	writeMetric(w, "nautilus_pool_onchain_workers", nil, float64(p.onChain))
+
+	if !p.lastReconcile.IsZero() {
+		writeMetricHeader(w, "nautilus_pool_last_reconcile_timestamp", "gauge", "Unix time of the last successful reconciliation")
+		writeMetric(w, "nautilus_pool_last_reconcile_timestamp", nil, float64(p.lastReconcile.Unix()))
+	}
+
+	failed := 0.0
+	if p.lastErr != nil {
+		failed = 1
+	}
+	writeMetricHeader(w, "nautilus_pool_reconcile_failed", "gauge", "Whether the last reconciliation attempt failed")
+	writeMetric(w, "nautilus_pool_reconcile_failed", nil, failed)
+}
@@ -0,0 +1,350 @@
+// Priority Guard - PriorityClass 설치, 시스템 구성요소 critical 지정, 테넌트 요청의 선점/축출로부터 보호
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+우선순위 정책
+
+  - 시스템 구성요소(DNS, ingress, 모니터링 DaemonSet 등)는 업스트림 system-node-critical(DaemonSet)/
+    system-cluster-critical(그 외) 클래스로 실행합니다. 대상은 NAUTILUS_CRITICAL_NAMESPACES(기본 kube-system)의
+    워크로드와, 어느 네임스페이스든 k3s-daas.io/critical=true 라벨이 붙은 워크로드입니다.
+    값이 2e9 이상이라 kubelet은 자원 압박 축출 대상에서 제외하고, 스케줄러는 더 낮은 Pod만 선점합니다.
+  - 테넌트용 클래스: daas-tenant-high(NAUTILUS_TENANT_MAX_PRIORITY, 기본 1000), daas-tenant-default(0, 기본값),
+    daas-tenant-batch(-10, 선점 안 함). 테넌트가 만드는 PriorityClass도 이 상한을 넘을 수 없습니다.
+
+컨트랙트 경로 요청(테넌트)에 대한 거부 규칙
+  - critical 네임스페이스에 대한 쓰기/삭제
+  - system-* 클래스 사용, 상한을 넘는 spec.priority, 보호된 PriorityClass(system-*, daas-*) 수정/삭제
+  - critical 워크로드/Pod에 대한 수정/삭제 (축출 경로)
+*/
+
+const (
+	criticalWorkloadLabel = "k3s-daas.io/critical"
+
+	systemNodeCritical    = "system-node-critical"
+	systemClusterCritical = "system-cluster-critical"
+	tenantDefaultClass    = "daas-tenant-default"
+
+	// kubelet이 critical Pod으로 취급하는 최소 우선순위 (scheduling.SystemCriticalPriority)
+	systemCriticalPriority = 2000000000
+
+	priorityResyncInterval = 5 * time.Minute
+)
+
+// protectedResources - 대상 객체의 critical 여부를 확인하는 리소스
+var protectedResources = map[string]bool{
+	"pods": true, "deployments": true, "daemonsets": true, "statefulsets": true, "replicasets": true,
+}
+
+// PriorityGuard - 우선순위 클래스와 critical 워크로드 보호
+type PriorityGuard struct {
+	logger             *logrus.Logger
+	k3sMgr             *K3sManager
+	criticalNamespaces map[string]bool
+	tenantMaxPriority  int64
+
+	mutex      sync.Mutex
+	lastSync   time.Time
+	classesSet bool
+	marked     uint64
+	rejected   map[string]uint64 // 거부 사유별
+}
+
+// NewPriorityGuard - NAUTILUS_CRITICAL_NAMESPACES, NAUTILUS_TENANT_MAX_PRIORITY 기준으로 생성
+func NewPriorityGuard(logger *logrus.Logger, k3sMgr *K3sManager) *PriorityGuard {
+	namespaces := make(map[string]bool)
+	for _, namespace := range splitList(getEnvOrDefault("NAUTILUS_CRITICAL_NAMESPACES", "kube-system")) {
+		namespaces[namespace] = true
+	}
+	maxPriority, err := strconv.ParseInt(os.Getenv("NAUTILUS_TENANT_MAX_PRIORITY"), 10, 32)
+	if err != nil || maxPriority <= 0 || maxPriority >= 1000000000 {
+		maxPriority = 1000
+	}
+	return &PriorityGuard{
+		logger:             logger,
+		k3sMgr:             k3sMgr,
+		criticalNamespaces: namespaces,
+		tenantMaxPriority:  maxPriority,
+		rejected:           make(map[string]uint64),
+	}
+}
+
+// tenantClasses - 설치할 테넌트용 PriorityClass
+func (g *PriorityGuard) tenantClasses() []map[string]interface{} {
+	class := func(name string, value int64, globalDefault bool, policy, description string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion":       "scheduling.k8s.io/v1",
+			"kind":             "PriorityClass",
+			"metadata":         map[string]interface{}{"name": name, "labels": map[string]string{"app.kubernetes.io/managed-by": "nautilus"}},
+			"value":            value,
+			"globalDefault":    globalDefault,
+			"preemptionPolicy": policy,
+			"description":      description,
+		}
+	}
+	return []map[string]interface{}{
+		class("daas-tenant-high", g.tenantMaxPriority, false, "PreemptLowerPriority", "Highest priority available to tenant workloads"),
+		class(tenantDefaultClass, 0, true, "PreemptLowerPriority", "Default priority of tenant workloads"),
+		class("daas-tenant-batch", -10, false, "Never", "Batch workloads that never preempt others"),
+	}
+}
+
+// Reconcile - 테넌트 클래스 설치 및 시스템 워크로드 critical 지정 (priorityResyncInterval마다)
+func (g *PriorityGuard) Reconcile() {
+	g.mutex.Lock()
+	due := time.Since(g.lastSync) >= priorityResyncInterval
+	if due {
+		g.lastSync = time.Now()
+	}
+	g.mutex.Unlock()
+	if !due {
+		return
+	}
+
+	manifest, _ := json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": g.tenantClasses()})
+	if _, err := g.k3sMgr.RunKubectl(manifest, "apply", "-f", "-"); err != nil {
+		g.logger.Errorf("❌ Failed to apply tenant priority classes: %v", err)
+	} else {
+		g.mutex.Lock()
+		if !g.classesSet {
+			g.logger.Infof("🎚️ Tenant priority classes installed (max %d)", g.tenantMaxPriority)
+		}
+		g.classesSet = true
+		g.mutex.Unlock()
+	}
+
+	for _, resource := range []string{"daemonsets", "deployments", "statefulsets"} {
+		if err := g.markCritical(resource); err != nil {
+			g.logger.Warnf("⚠️ Failed to mark critical %s: %v", resource, err)
+		}
+	}
+}
+
+// markCritical - critical 대상 워크로드 중 critical 클래스가 아닌 것의 priorityClassName 지정
+func (g *PriorityGuard) markCritical(resource string) error {
+	output, err := g.k3sMgr.RunKubectl(nil, "get", resource, "--all-namespaces", "-o", "json")
+	if err != nil {
+		return err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string            `json:"name"`
+				Namespace string            `json:"namespace"`
+				Labels    map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Template struct {
+					Spec struct {
+						PriorityClassName string `json:"priorityClassName"`
+					} `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", resource, err)
+	}
+
+	class := systemClusterCritical
+	if resource == "daemonsets" {
+		class = systemNodeCritical
+	}
+	for _, item := range list.Items {
+		meta := item.Metadata
+		if !g.criticalNamespaces[meta.Namespace] && meta.Labels[criticalWorkloadLabel] != "true" {
+			continue
+		}
+		current := item.Spec.Template.Spec.PriorityClassName
+		if current == systemNodeCritical || current == systemClusterCritical {
+			continue
+		}
+		patch := fmt.Sprintf(`{"spec":{"template":{"spec":{"priorityClassName":%q}}}}`, class)
+		if _, err := g.k3sMgr.RunKubectl(nil, "patch", resource, meta.Name, "-n", meta.Namespace, "--type=merge", "-p", patch); err != nil {
+			g.logger.Warnf("⚠️ Failed to mark %s %s/%s critical: %v", resource, meta.Namespace, meta.Name, err)
+			continue
+		}
+		g.mutex.Lock()
+		g.marked++
+		g.mutex.Unlock()
+		g.logger.Infof("🛡️ Marked %s %s/%s as %s", resource, meta.Namespace, meta.Name, class)
+	}
+	return nil
+}
+
+// reject - 거부 사유 집계 후 오류 반환
+func (g *PriorityGuard) reject(reason, format string, args ...interface{}) error {
+	g.mutex.Lock()
+	g.rejected[reason]++
+	g.mutex.Unlock()
+	return fmt.Errorf("Forbidden: "+format, args...)
+}
+
+// Admit - 컨트랙트 경로 요청에 우선순위/critical 보호 규칙 적용
+func (g *PriorityGuard) Admit(request *K8sAPIRequest, payload *admissionPayload) error {
+	method := strings.ToUpper(request.Method)
+	if method == "GET" {
+		return nil
+	}
+
+	if g.criticalNamespaces[request.Namespace] {
+		return g.reject("critical_namespace", "namespace %s is reserved for cluster-critical components", request.Namespace)
+	}
+	if request.Resource == "priorityclasses" {
+		return g.admitPriorityClass(method, request)
+	}
+
+	if request.Payload != "" && (method == "POST" || method == "PUT" || method == "PATCH") {
+		if err := g.admitPodPriority(payload); err != nil {
+			return err
+		}
+	}
+	if request.Name != "" && method != "POST" && protectedResources[request.Resource] {
+		return g.admitTarget(request)
+	}
+	return nil
+}
+
+// admitPriorityClass - 테넌트 PriorityClass는 상한 이하, 기본값 지정 불가, 보호된 이름 사용 불가
+func (g *PriorityGuard) admitPriorityClass(method string, request *K8sAPIRequest) error {
+	name := request.Name
+	var class struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Value            int64  `json:"value"`
+		GlobalDefault    bool   `json:"globalDefault"`
+		PreemptionPolicy string `json:"preemptionPolicy"`
+	}
+	if request.Payload != "" && method != "DELETE" {
+		if err := json.Unmarshal([]byte(request.Payload), &class); err != nil {
+			return g.reject("priority_class", "PriorityClass payload must be JSON: %v", err)
+		}
+		if class.Metadata.Name != "" {
+			name = class.Metadata.Name
+		}
+	}
+	if strings.HasPrefix(name, "system-") || strings.HasPrefix(name, "daas-") {
+		return g.reject("priority_class", "PriorityClass %s is managed by the cluster", name)
+	}
+	if method == "DELETE" {
+		return nil
+	}
+	if class.Value > g.tenantMaxPriority {
+		return g.reject("priority_value", "PriorityClass value %d exceeds the tenant maximum %d", class.Value, g.tenantMaxPriority)
+	}
+	if class.GlobalDefault {
+		return g.reject("priority_class", "tenants cannot set a global default PriorityClass")
+	}
+	if policy := class.PreemptionPolicy; policy != "" && policy != "PreemptLowerPriority" && policy != "Never" {
+		return g.reject("priority_class", "unsupported preemptionPolicy %q", policy)
+	}
+	return nil
+}
+
+// admitPodPriority - Pod 템플릿의 critical 클래스 사용과 상한을 넘는 priority 값 거부
+func (g *PriorityGuard) admitPodPriority(payload *admissionPayload) error {
+	obj := payload.Object()
+	if obj == nil {
+		return nil
+	}
+
+	// kubectl apply는 요청 네임스페이스가 없으면 매니페스트의 네임스페이스를 사용
+	if namespace, _ := objectMeta(obj)["namespace"].(string); g.criticalNamespaces[namespace] {
+		return g.reject("critical_namespace", "namespace %s is reserved for cluster-critical components", namespace)
+	}
+	if labels, _ := objectMeta(obj)["labels"].(map[string]interface{}); labels[criticalWorkloadLabel] == "true" {
+		return g.reject("critical_label", "label %s is reserved for cluster-critical components", criticalWorkloadLabel)
+	}
+	podSpec, podMeta := podTemplateOf(obj)
+	if podSpec == nil {
+		return nil
+	}
+	if labels, _ := podMeta["labels"].(map[string]interface{}); labels[criticalWorkloadLabel] == "true" {
+		return g.reject("critical_label", "label %s is reserved for cluster-critical components", criticalWorkloadLabel)
+	}
+	if class, _ := podSpec["priorityClassName"].(string); strings.HasPrefix(class, "system-") {
+		return g.reject("critical_class", "priorityClassName %s is reserved for cluster-critical components", class)
+	}
+	if priority, ok := podSpec["priority"].(float64); ok && int64(priority) > g.tenantMaxPriority {
+		return g.reject("priority_value", "priority %d exceeds the tenant maximum %d", int64(priority), g.tenantMaxPriority)
+	}
+	return nil
+}
+
+/*
+admitTarget - 기존 객체가 critical이면 수정/삭제 거부 (테넌트 요청이 시스템 Pod을 축출하지 못하도록)
+K3s가 실행 중이 아니면 이후 실행도 실패하므로 조회를 생략하고, 객체가 없으면 통과시킵니다.
+*/
+func (g *PriorityGuard) admitTarget(request *K8sAPIRequest) error {
+	if !g.k3sMgr.IsRunning() {
+		return nil
+	}
+	args := append(append([]string{"get"}, objectRef(request)...), "-o", "json", "--ignore-not-found")
+	output, err := g.k3sMgr.RunKubectl(nil, args...)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s/%s before modifying it: %v", request.Resource, request.Name, err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil
+	}
+
+	var object struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Priority          *int64 `json:"priority"`
+			PriorityClassName string `json:"priorityClassName"`
+			Template          struct {
+				Spec struct {
+					PriorityClassName string `json:"priorityClassName"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &object); err != nil {
+		return fmt.Errorf("failed to parse %s/%s: %v", request.Resource, request.Name, err)
+	}
+
+	critical := object.Metadata.Labels[criticalWorkloadLabel] == "true" ||
+		strings.HasPrefix(object.Spec.PriorityClassName, "system-") ||
+		strings.HasPrefix(object.Spec.Template.Spec.PriorityClassName, "system-") ||
+		(object.Spec.Priority != nil && *object.Spec.Priority >= systemCriticalPriority)
+	if critical {
+		g.logger.Warnf("🛡️ Refused %s of critical %s %s/%s from %s", request.Method, request.Resource, request.Namespace, request.Name, request.Requester)
+		return g.reject("critical_target", "%s %s/%s is a cluster-critical component", request.Resource, request.Namespace, request.Name)
+	}
+	return nil
+}
+
+// writeMetrics - 거부 사유별 요청 수, critical 지정 수
+func (g *PriorityGuard) writeMetrics(w io.Writer) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_priority_rejections_total", "counter", "Tenant requests refused to protect cluster-critical components, by reason")
+	for _, reason := range []string{"critical_namespace", "critical_class", "critical_label", "critical_target", "priority_class", "priority_value"} {
+		writeMetric(w, "nautilus_priority_rejections_total", map[string]string{"reason": reason}, float64(g.rejected[reason]))
+	}
+	writeMetricHeader(w, "nautilus_priority_critical_marked_total", "counter", "System workloads patched to a critical priority class")
+	writeMetric(w, "nautilus_priority_critical_marked_total", nil, float64(g.marked))
+	installed := 0.0
+	if g.classesSet {
+		installed = 1
+	}
+	writeMetricHeader(w, "nautilus_priority_classes_installed", "gauge", "Whether the tenant priority classes are installed")
+	writeMetric(w, "nautilus_priority_classes_installed", nil, installed)
+}
@@ -0,0 +1,63 @@
+// Quantity - K8s 리소스 수량 문자열 파싱 (cpu: "500m", memory: "128Mi")
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// memorySuffixes - 메모리 단위 (긴 접미사부터 검사)
+var memorySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"k", 1e3}, {"K", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// parseCPUMillis - CPU 수량을 millicore로 변환
+func parseCPUMillis(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(value, "m") {
+		millis, err := strconv.ParseFloat(strings.TrimSuffix(value, "m"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu quantity %q: %v", value, err)
+		}
+		return int64(millis), nil
+	}
+
+	cores, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu quantity %q: %v", value, err)
+	}
+	return int64(cores * 1000), nil
+}
+
+// parseMemoryBytes - 메모리 수량을 바이트로 변환
+func parseMemoryBytes(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	for _, unit := range memorySuffixes {
+		if strings.HasSuffix(value, unit.suffix) {
+			number, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid memory quantity %q: %v", value, err)
+			}
+			return int64(number * unit.multiplier), nil
+		}
+	}
+
+	bytes, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q: %v", value, err)
+	}
+	return int64(bytes), nil
+}
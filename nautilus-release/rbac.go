@@ -0,0 +1,267 @@
+// RBAC - 테넌트 지갑 간 위임 접근 권한 (view/edit) 관리 및 검증
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RoleView = "view" // 읽기 전용 (secrets 제외)
+	RoleEdit = "edit" // 네임스페이스 리소스 읽기/쓰기
+)
+
+// clusterScopedResources - 위임 권한으로 접근할 수 없는 클러스터 범위 리소스
+var clusterScopedResources = map[string]bool{
+	"namespaces": true,
+	"nodes":      true,
+}
+
+// AccessGrant - 소유자가 다른 지갑에 부여한 접근 권한
+type AccessGrant struct {
+	ID         string     `json:"id"`
+	Owner      string     `json:"owner"`
+	Grantee    string     `json:"grantee"`
+	Role       string     `json:"role"`
+	Namespaces []string   `json:"namespaces"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// allowsNamespace - 권한 범위에 네임스페이스 포함 여부 ("*"는 전체)
+func (g *AccessGrant) allowsNamespace(namespace string) bool {
+	for _, ns := range g.Namespaces {
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsVerb - 역할이 메서드/리소스를 허용하는지 확인
+func (g *AccessGrant) allowsVerb(method, resource string) bool {
+	if clusterScopedResources[resource] {
+		return false
+	}
+
+	switch g.Role {
+	case RoleView:
+		return strings.ToUpper(method) == "GET" && resource != "secrets"
+	case RoleEdit:
+		return true
+	default:
+		return false
+	}
+}
+
+// RBACAuthorizer - 위임 권한 저장소 및 검증기
+type RBACAuthorizer struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	grants     map[string]*AccessGrant
+	stateFile  string
+	mutex      sync.RWMutex
+}
+
+// NewRBACAuthorizer - 새 RBAC Authorizer 생성 (저장된 권한 로드)
+func NewRBACAuthorizer(logger *logrus.Logger, workerPool *WorkerPool) *RBACAuthorizer {
+	r := &RBACAuthorizer{
+		logger:     logger,
+		workerPool: workerPool,
+		grants:     make(map[string]*AccessGrant),
+		stateFile:  statePath("access-grants.json"),
+	}
+
+	var grants []*AccessGrant
+	if found, err := loadJSONState(r.stateFile, &grants); err != nil {
+		logger.Warnf("⚠️ Failed to load access grants: %v", err)
+	} else if found {
+		for _, grant := range grants {
+			r.grants[grant.ID] = grant
+		}
+		logger.Infof("🔐 Loaded %d access grants", len(grants))
+	}
+
+	return r
+}
+
+// Authorize - requester가 owner의 클러스터에 요청을 실행할 수 있는지 확인
+func (r *RBACAuthorizer) Authorize(owner, requester, method, resource, namespace string) error {
+	if owner == "" || strings.EqualFold(owner, requester) {
+		return nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	now := time.Now()
+	for _, grant := range r.grants {
+		if !strings.EqualFold(grant.Owner, owner) || !strings.EqualFold(grant.Grantee, requester) {
+			continue
+		}
+		if grant.ExpiresAt != nil && now.After(*grant.ExpiresAt) {
+			continue
+		}
+		if grant.allowsNamespace(namespace) && grant.allowsVerb(method, resource) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not allowed to %s %s in namespace %s of %s", requester, method, resource, namespace, owner)
+}
+
+// Grant - 권한 부여
+func (r *RBACAuthorizer) Grant(grant *AccessGrant) error {
+	if grant.Role != RoleView && grant.Role != RoleEdit {
+		return fmt.Errorf("invalid role %q (expected %s or %s)", grant.Role, RoleView, RoleEdit)
+	}
+	if grant.Grantee == "" || len(grant.Namespaces) == 0 {
+		return fmt.Errorf("grantee and namespaces are required")
+	}
+	if strings.EqualFold(grant.Owner, grant.Grantee) {
+		return fmt.Errorf("cannot grant access to yourself")
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("failed to generate grant id: %v", err)
+	}
+	grant.ID = "grant-" + hex.EncodeToString(id)
+	grant.CreatedAt = time.Now()
+
+	r.mutex.Lock()
+	r.grants[grant.ID] = grant
+	err := r.persistLocked()
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof("🔐 Access granted: %s → %s (%s on %s)", grant.Owner, grant.Grantee, grant.Role, strings.Join(grant.Namespaces, ","))
+	return nil
+}
+
+// Revoke - 소유자의 권한 회수
+func (r *RBACAuthorizer) Revoke(owner, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	grant, exists := r.grants[id]
+	if !exists || !strings.EqualFold(grant.Owner, owner) {
+		return fmt.Errorf("grant %s not found", id)
+	}
+
+	delete(r.grants, id)
+	if err := r.persistLocked(); err != nil {
+		return err
+	}
+
+	r.logger.Infof("🔐 Access revoked: %s → %s", grant.Owner, grant.Grantee)
+	return nil
+}
+
+// ListGrants - 지갑이 부여했거나 부여받은 권한 목록
+func (r *RBACAuthorizer) ListGrants(wallet string) []*AccessGrant {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []*AccessGrant
+	for _, grant := range r.grants {
+		if strings.EqualFold(grant.Owner, wallet) || strings.EqualFold(grant.Grantee, wallet) {
+			result = append(result, grant)
+		}
+	}
+	return result
+}
+
+// AllGrants - 전체 권한 목록 (디버그 덤프용)
+func (r *RBACAuthorizer) AllGrants() []*AccessGrant {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	grants := make([]*AccessGrant, 0, len(r.grants))
+	for _, grant := range r.grants {
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// persistLocked - 권한 목록 저장 (mutex 보유 상태에서 호출)
+func (r *RBACAuthorizer) persistLocked() error {
+	grants := make([]*AccessGrant, 0, len(r.grants))
+	for _, grant := range r.grants {
+		grants = append(grants, grant)
+	}
+	return saveJSONState(r.stateFile, grants)
+}
+
+// handleGrants - 위임 권한 API (GET 목록, POST 부여, DELETE 회수)
+func (r *RBACAuthorizer) handleGrants(w http.ResponseWriter, req *http.Request) {
+	// 소유자는 자신의 워커 Seal 토큰으로 인증
+	wallet, ok := r.workerPool.OwnerBySealToken(req.Header.Get("X-Seal-Token"))
+	if !ok {
+		http.Error(w, "Invalid or missing seal token", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch req.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   r.ListGrants(wallet),
+		})
+
+	case http.MethodPost:
+		var body struct {
+			Grantee    string   `json:"grantee"`
+			Role       string   `json:"role"`
+			Namespaces []string `json:"namespaces"`
+			TTLHours   int      `json:"ttl_hours"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid grant payload", http.StatusBadRequest)
+			return
+		}
+
+		grant := &AccessGrant{
+			Owner:      wallet,
+			Grantee:    body.Grantee,
+			Role:       body.Role,
+			Namespaces: body.Namespaces,
+		}
+		if body.TTLHours > 0 {
+			expiresAt := time.Now().Add(time.Duration(body.TTLHours) * time.Hour)
+			grant.ExpiresAt = &expiresAt
+		}
+
+		if err := r.Grant(grant); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   grant,
+		})
+
+	case http.MethodDelete:
+		if err := r.Revoke(wallet, req.URL.Query().Get("id")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,241 @@
+// Read Only - 사고 대응용 클러스터 전체 읽기 전용 모드 (변경 요청 거부, 조회/하트비트/모니터링 유지)
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+ReadOnlyMode - 체인 포크, 키 유출 의심 같은 사고 중 클러스터 변경을 멈추는 스위치
+
+켜져 있는 동안 거부하는 것:
+  - kubectl 경로의 POST/PUT/PATCH/DELETE (서버 측 dryRun과 TokenReview/SubjectAccessReview 같은 조회성 생성은 허용)
+  - 컨트랙트 이벤트 경로의 쓰기 요청 (실행하지 않고 사유를 담은 실패 결과를 컨트랙트에 기록)
+  - kubectl 경로를 거치지 않고 클러스터를 바꾸는 관리 API (휴지통 복원, 테넌트 데이터 삭제, 카나리 가중치 변경, 워커 설정 게시/롤백)
+
+조회, watch, 워커 하트비트, 메트릭/상태 API, 스테이킹/슬래싱 이벤트 처리는 계속 동작합니다.
+NAUTILUS_READ_ONLY=true(사유는 NAUTILUS_READ_ONLY_REASON)면 켠 상태로 시작하고, 관리자 API로 켠 상태는
+디스크에 저장되어 재시작 후에도 유지됩니다 (사고 중 재시작으로 풀리지 않도록).
+*/
+type ReadOnlyMode struct {
+	logger     *logrus.Logger
+	adminToken string
+	stateFile  string
+	stream     *EventStream
+
+	mutex    sync.RWMutex
+	state    ReadOnlyState
+	rejected map[string]uint64 // 경로(kubectl, contract, admin)별 거부 수
+}
+
+// ReadOnlyState - 읽기 전용 모드 상태
+type ReadOnlyState struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	Source  string    `json:"source,omitempty"` // config 또는 api
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// NewReadOnlyMode - 새 Read Only Mode 생성 (NAUTILUS_READ_ONLY, NAUTILUS_READ_ONLY_REASON)
+func NewReadOnlyMode(logger *logrus.Logger) *ReadOnlyMode {
+	m := &ReadOnlyMode{
+		logger:     logger,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		stateFile:  statePath("read-only.json"),
+		rejected:   make(map[string]uint64),
+	}
+	if _, err := loadJSONState(m.stateFile, &m.state); err != nil {
+		logger.Warnf("⚠️ Failed to load read-only state: %v", err)
+	}
+	if getEnvOrDefault("NAUTILUS_READ_ONLY", "false") == "true" && !m.state.Enabled {
+		m.state = ReadOnlyState{
+			Enabled: true,
+			Reason:  getEnvOrDefault("NAUTILUS_READ_ONLY_REASON", "set by NAUTILUS_READ_ONLY"),
+			Source:  "config",
+			Since:   time.Now(),
+		}
+	}
+	if m.state.Enabled {
+		logger.Warnf("🧊 Cluster is in read-only mode since %s: %s", m.state.Since.Format(time.RFC3339), m.state.Reason)
+	}
+	return m
+}
+
+// State - 현재 상태
+func (m *ReadOnlyMode) State() ReadOnlyState {
+	if m == nil {
+		return ReadOnlyState{}
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.state
+}
+
+// Set - 읽기 전용 모드 켜기/끄기 (상태 저장, 이벤트 스트림에 알림)
+func (m *ReadOnlyMode) Set(enabled bool, reason string) (ReadOnlyState, error) {
+	if enabled && strings.TrimSpace(reason) == "" {
+		return ReadOnlyState{}, fmt.Errorf("a reason is required to enable read-only mode")
+	}
+
+	m.mutex.Lock()
+	if m.state.Enabled == enabled && (!enabled || m.state.Reason == reason) {
+		state := m.state
+		m.mutex.Unlock()
+		return state, nil
+	}
+	state := ReadOnlyState{}
+	if enabled {
+		state = ReadOnlyState{Enabled: true, Reason: reason, Source: "api", Since: time.Now()}
+		if m.state.Enabled {
+			state.Since = m.state.Since // 사유만 바꾸면 시작 시각 유지
+		}
+	}
+	if err := saveJSONState(m.stateFile, state); err != nil {
+		m.mutex.Unlock()
+		return ReadOnlyState{}, err
+	}
+	m.state = state
+	m.mutex.Unlock()
+
+	if enabled {
+		m.logger.Warnf("🧊 Cluster switched to read-only mode: %s", reason)
+	} else {
+		m.logger.Infof("🔓 Cluster read-only mode lifted")
+	}
+	m.stream.Publish("cluster.read_only", "", "", state)
+	return state, nil
+}
+
+// message - 거부 응답에 쓰는 안내 문구
+func (m *ReadOnlyMode) message(state ReadOnlyState) string {
+	return fmt.Sprintf("cluster is in emergency read-only mode since %s: %s (reads and watches still work)",
+		state.Since.UTC().Format(time.RFC3339), state.Reason)
+}
+
+// CheckRequest - 컨트랙트 경로 요청이 쓰기면 읽기 전용 모드 중 거부
+func (m *ReadOnlyMode) CheckRequest(request *K8sAPIRequest) error {
+	if m == nil || strings.ToUpper(request.Method) == http.MethodGet {
+		return nil
+	}
+	state := m.State()
+	if !state.Enabled {
+		return nil
+	}
+	m.count("contract")
+	return fmt.Errorf("ServiceUnavailable: %s", m.message(state))
+}
+
+// readOnlyExempt - 쓰기 메서드지만 클러스터를 바꾸지 않는 요청
+func readOnlyExempt(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if isDryRun(r) {
+		return true
+	}
+	// TokenReview, SubjectAccessReview, SelfSubjectRulesReview 등은 생성 요청이지만 저장되지 않음
+	return strings.HasPrefix(r.URL.Path, "/apis/authentication.k8s.io/") ||
+		strings.HasPrefix(r.URL.Path, "/apis/authorization.k8s.io/")
+}
+
+// Middleware - 읽기 전용 모드 중 kubectl 쓰기를 K8s Status 503으로 거부
+func (m *ReadOnlyMode) Middleware(next http.Handler) http.Handler {
+	return m.Guard("kubectl", next)
+}
+
+// Guard - Middleware와 같은 거부를 다른 경로(path는 거부 수 지표의 라벨)에 적용 (m이 nil이면 그대로 통과)
+func (m *ReadOnlyMode) Guard(path string, next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if readOnlyExempt(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		state := m.State()
+		if !state.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		m.count(path)
+		m.logger.Infof("🧊 Rejected %s %s in read-only mode", r.Method, r.URL.Path)
+		httpserver.WriteStatus(w, http.StatusServiceUnavailable, "ServiceUnavailable", m.message(state))
+	})
+}
+
+func (m *ReadOnlyMode) count(path string) {
+	m.mutex.Lock()
+	m.rejected[path]++
+	m.mutex.Unlock()
+}
+
+/*
+handleReadOnly - 읽기 전용 모드 조회/전환 (/api/v1/admin/read-only)
+
+	GET                                   현재 상태 (인증 없음, 테넌트와 Gateway가 사고 여부 확인)
+	PUT {"enabled": true, "reason": ""}   켜기/끄기 (관리자 토큰, 켤 때는 사유 필수)
+*/
+func (m *ReadOnlyMode) handleReadOnly(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, m.State())
+	case http.MethodPut:
+		if m.adminToken == "" {
+			http.Error(w, "Read-only API disabled (NAUTILUS_ADMIN_TOKEN not set), use NAUTILUS_READ_ONLY", http.StatusForbidden)
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Enabled *bool  `json:"enabled"`
+			Reason  string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Enabled == nil {
+			http.Error(w, "Invalid request body, expected {\"enabled\": bool, \"reason\": string}", http.StatusBadRequest)
+			return
+		}
+		state, err := m.Set(*body.Enabled, body.Reason)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, state)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 읽기 전용 모드 여부와 거부한 쓰기 요청 수
+func (m *ReadOnlyMode) writeMetrics(w io.Writer) {
+	m.mutex.RLock()
+	enabled := m.state.Enabled
+	kubectl, contract, admin := m.rejected["kubectl"], m.rejected["contract"], m.rejected["admin"]
+	m.mutex.RUnlock()
+
+	value := 0.0
+	if enabled {
+		value = 1
+	}
+	writeMetricHeader(w, "nautilus_read_only", "gauge", "Whether the cluster is in emergency read-only mode")
+	writeMetric(w, "nautilus_read_only", nil, value)
+	writeMetricHeader(w, "nautilus_read_only_rejections_total", "counter", "Mutating requests rejected in read-only mode by path")
+	writeMetric(w, "nautilus_read_only_rejections_total", map[string]string{"path": "kubectl"}, float64(kubectl))
+	writeMetric(w, "nautilus_read_only_rejections_total", map[string]string{"path": "contract"}, float64(contract))
+	writeMetric(w, "nautilus_read_only_rejections_total", map[string]string{"path": "admin"}, float64(admin))
+}
@@ -0,0 +1,272 @@
+// Readiness Gate - 저장소/체인 이벤트/증명 준비 전에는 kubectl 트래픽을 503으로 거부
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 준비 상태 (starting → ready, 준비 후 조건을 잃으면 not_ready)
+const (
+	readinessStarting = "starting"
+	readinessReady    = "ready"
+	readinessNotReady = "not_ready"
+)
+
+// ReadinessCondition - 개별 준비 조건
+type ReadinessCondition struct {
+	Name    string
+	Ready   bool
+	Message string
+}
+
+/*
+ReadinessGate - 마스터가 kubectl 요청을 처리할 수 있는 상태인지 판단
+
+  - store: K3s API 서버 실행 중이고 워커 풀을 온체인 상태로 재구성함
+  - chain_cursor: 이벤트 조회가 최신 체크포인트와 READINESS_MAX_CHECKPOINT_LAG 이내
+  - attestation: TEE 확인 및 Seal 토큰 왕복 검증 통과 (한 번 통과하면 유지)
+  - event_loop: 이벤트 조회/처리 루프가 진행 중 (EventWatchdog 판정)
+*/
+type ReadinessGate struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	sui        *SuiIntegration
+	poolSync   *PoolSync
+	selfTest   *SelfTest
+	watchdog   *EventWatchdog
+	maxLag     uint64
+	interval   time.Duration
+	state      string
+	conditions []ReadinessCondition
+	attested   bool
+	changedAt  time.Time
+	mutex      sync.RWMutex
+}
+
+// NewReadinessGate - 새 Readiness Gate 생성 (READINESS_CHECK_INTERVAL_SECONDS로 평가 주기 조정)
+func NewReadinessGate(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration, poolSync *PoolSync) *ReadinessGate {
+	maxLag, err := strconv.ParseUint(getEnvOrDefault("READINESS_MAX_CHECKPOINT_LAG", "100"), 10, 64)
+	if err != nil {
+		logger.Warnf("⚠️ Invalid READINESS_MAX_CHECKPOINT_LAG, using 100: %v", err)
+		maxLag = 100
+	}
+
+	return &ReadinessGate{
+		logger:    logger,
+		k3sMgr:    k3sMgr,
+		sui:       sui,
+		poolSync:  poolSync,
+		selfTest:  NewSelfTest(logger, sui),
+		maxLag:    maxLag,
+		interval:  envSeconds("READINESS_CHECK_INTERVAL_SECONDS", 5),
+		state:     readinessStarting,
+		changedAt: time.Now(),
+	}
+}
+
+// Start - 주기적 준비 조건 평가
+func (g *ReadinessGate) Start(ctx context.Context) {
+	g.logger.Info("🚦 Starting Readiness Gate...")
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.evaluate()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate - 조건을 다시 평가하고 상태 전이 기록
+func (g *ReadinessGate) evaluate() {
+	conditions := []ReadinessCondition{g.checkStore(), g.checkChainCursor(), g.checkAttestation(), g.watchdog.Condition()}
+
+	ready := true
+	for _, condition := range conditions {
+		ready = ready && condition.Ready
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.conditions = conditions
+	next := g.state
+	switch {
+	case ready:
+		next = readinessReady
+	case g.state == readinessReady:
+		next = readinessNotReady
+	}
+	if next == g.state {
+		return
+	}
+
+	elapsed := time.Since(g.changedAt).Round(time.Second)
+	g.state = next
+	g.changedAt = time.Now()
+	if ready {
+		g.logger.Infof("✅ Master ready to serve kubectl traffic (after %s)", elapsed)
+	} else {
+		g.logger.Warnf("🚧 Master no longer ready, rejecting kubectl traffic: %s", pendingConditions(conditions))
+	}
+}
+
+// checkStore - K3s 데이터스토어와 워커 풀 재구성 확인 (재구성 실패 시 여기서 재시도)
+func (g *ReadinessGate) checkStore() ReadinessCondition {
+	condition := ReadinessCondition{Name: "store"}
+	if !g.k3sMgr.IsRunning() {
+		condition.Message = "K3s API server is not running"
+		return condition
+	}
+	if !g.poolSync.Loaded() {
+		if err := g.poolSync.Rebuild(); err != nil {
+			condition.Message = err.Error()
+			return condition
+		}
+	}
+	condition.Ready = true
+	condition.Message = "K3s running, worker pool loaded"
+	return condition
+}
+
+// checkChainCursor - 이벤트 조회 지점과 최신 체크포인트 차이
+func (g *ReadinessGate) checkChainCursor() ReadinessCondition {
+	condition := ReadinessCondition{Name: "chain_cursor"}
+	if !g.sui.onSui() {
+		condition.Ready = true
+		condition.Message = g.sui.backend.Name() + " chain backend, events are final immediately"
+		return condition
+	}
+
+	cursor := g.sui.EventCursor()
+	if cursor == 0 {
+		condition.Message = "no successful event poll yet"
+		return condition
+	}
+	latest, err := g.sui.latestCheckpoint()
+	if err != nil {
+		condition.Message = fmt.Sprintf("failed to read latest checkpoint: %v", err)
+		return condition
+	}
+
+	var lag uint64
+	if latest > cursor {
+		lag = latest - cursor
+	}
+	condition.Ready = lag <= g.maxLag
+	condition.Message = fmt.Sprintf("event cursor %d checkpoints behind %d (max %d)", lag, latest, g.maxLag)
+	return condition
+}
+
+// checkAttestation - 시작 검증과 같은 TEE/Seal 토큰 확인 (통과 전까지 매 주기 재시도)
+func (g *ReadinessGate) checkAttestation() ReadinessCondition {
+	condition := ReadinessCondition{Name: "attestation", Ready: true, Message: "attestation verified"}
+
+	g.mutex.RLock()
+	attested := g.attested
+	g.mutex.RUnlock()
+	if attested {
+		return condition
+	}
+
+	if err := g.selfTest.checkAttestation(); err != nil {
+		condition.Ready = false
+		condition.Message = err.Error()
+		return condition
+	}
+
+	g.mutex.Lock()
+	g.attested = true
+	g.mutex.Unlock()
+	return condition
+}
+
+// pendingConditions - 충족되지 않은 조건 요약 ("store (K3s API server is not running), ...")
+func pendingConditions(conditions []ReadinessCondition) string {
+	var pending []string
+	for _, condition := range conditions {
+		if !condition.Ready {
+			pending = append(pending, fmt.Sprintf("%s (%s)", condition.Name, condition.Message))
+		}
+	}
+	return strings.Join(pending, ", ")
+}
+
+// Ready - kubectl 트래픽 처리 가능 여부와 미충족 조건 요약
+func (g *ReadinessGate) Ready() (bool, string) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if g.state == readinessReady {
+		return true, ""
+	}
+	if g.conditions == nil {
+		return false, "readiness not evaluated yet"
+	}
+	return false, pendingConditions(g.conditions)
+}
+
+// Middleware - 준비 전에는 K8s Status 형식 503 + Retry-After로 거부
+func (g *ReadinessGate) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ready, pending := g.Ready()
+		if ready {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter := int(g.interval.Seconds())
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"kind":       "Status",
+			"apiVersion": "v1",
+			"metadata":   map[string]interface{}{},
+			"status":     "Failure",
+			"message":    "Nautilus master is not ready yet, waiting for: " + pending,
+			"reason":     "ServiceUnavailable",
+			"details":    map[string]interface{}{"retryAfterSeconds": retryAfter},
+			"code":       http.StatusServiceUnavailable,
+		})
+	})
+}
+
+// writeMetrics - 준비 상태 메트릭 출력
+func (g *ReadinessGate) writeMetrics(w io.Writer) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	ready := 0.0
+	if g.state == readinessReady {
+		ready = 1
+	}
+	writeMetricHeader(w, "nautilus_ready", "gauge", "Whether the master accepts kubectl traffic")
+	writeMetric(w, "nautilus_ready", nil, ready)
+
+	writeMetricHeader(w, "nautilus_readiness_condition", "gauge", "Whether each readiness condition is met")
+	for _, condition := range g.conditions {
+		met := 0.0
+		if condition.Ready {
+			met = 1
+		}
+		writeMetric(w, "nautilus_readiness_condition", map[string]string{"condition": condition.Name}, met)
+	}
+}
@@ -0,0 +1,670 @@
+// Registry Cache - 워커용 Docker Hub pull-through 캐시 및 레지스트리 미러 안내
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const registryAuthRealm = "nautilus-registry-cache"
+
+/*
+RegistryCache - 레지스트리 v2 읽기 경로(/v2/) pull-through 캐시
+
+워커마다 Docker Hub에서 따로 받으면 익명 pull 한도에 걸리므로 마스터가 한 번 받아 디스크에 보관합니다.
+  - blob과 다이제스트로 조회한 manifest는 내용 주소 기반이라 영구 캐시 (크기 상한 초과 시 오래 안 쓴 blob부터 삭제)
+  - 태그 → 다이제스트 매핑은 TTL 동안 캐시, 업스트림 장애 시에는 만료된 매핑으로 응답
+  - 허용 목록에 없는 저장소는 거부 (임의 이미지 중계 방지)
+  - 워커는 노드 ID / Seal 토큰 Basic 인증으로 접근
+
+NAUTILUS_REGISTRY_MIRRORS로 외부 미러(Harbor 프록시 프로젝트, 캐시 노드)를 함께 안내할 수 있으며,
+containerd는 안내받은 미러를 순서대로 시도한 뒤 Docker Hub로 폴백합니다.
+*/
+type RegistryCache struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	enabled    bool
+	upstream   string
+	username   string
+	password   string
+	dir        string
+	maxBytes   int64
+	allowlist  []string
+	mirrors    []string
+	publicURL  string
+	tagTTL     time.Duration
+	client     *http.Client
+	slo        *SLOTracker // 오류 예산 소진 중 정리 보류 (선택)
+
+	mutex  sync.Mutex
+	tokens map[string]registryToken // 저장소별 업스트림 Bearer 토큰
+	tags   map[string]registryTag   // "저장소:태그" → 다이제스트
+	stats  registryCacheStats
+}
+
+type registryToken struct {
+	value   string
+	expires time.Time
+}
+
+type registryTag struct {
+	digest  string
+	fetched time.Time
+}
+
+type registryCacheStats struct {
+	requests      map[string]uint64 // "kind/result" (manifest|blob, hit|miss|stale)
+	bytesCache    uint64
+	bytesUpstream uint64
+	denied        uint64
+	upstreamErrs  uint64
+	pruned        uint64
+}
+
+// registryMirror - 워커에 안내하는 미러 하나
+type registryMirror struct {
+	Endpoint string `json:"endpoint"`
+	SealAuth bool   `json:"seal_auth"` // 노드 ID / Seal 토큰으로 인증 (마스터 내장 캐시)
+}
+
+// NewRegistryCache - 새 Registry Cache 생성
+func NewRegistryCache(logger *logrus.Logger, workerPool *WorkerPool) *RegistryCache {
+	maxGB, err := strconv.ParseFloat(getEnvOrDefault("NAUTILUS_REGISTRY_CACHE_MAX_GB", "50"), 64)
+	if err != nil || maxGB <= 0 {
+		maxGB = 50
+	}
+	return &RegistryCache{
+		logger:     logger,
+		workerPool: workerPool,
+		enabled:    getEnvOrDefault("NAUTILUS_REGISTRY_CACHE", "false") == "true",
+		upstream:   strings.TrimSuffix(getEnvOrDefault("NAUTILUS_REGISTRY_UPSTREAM", "https://registry-1.docker.io"), "/"),
+		username:   os.Getenv("NAUTILUS_REGISTRY_USERNAME"),
+		password:   os.Getenv("NAUTILUS_REGISTRY_PASSWORD"),
+		dir:        getEnvOrDefault("NAUTILUS_REGISTRY_CACHE_DIR", statePath("registry-cache")),
+		maxBytes:   int64(maxGB * (1 << 30)),
+		allowlist:  splitList(getEnvOrDefault("NAUTILUS_REGISTRY_ALLOWLIST", "library/*,rancher/*")),
+		mirrors:    splitList(os.Getenv("NAUTILUS_REGISTRY_MIRRORS")),
+		publicURL:  strings.TrimSuffix(os.Getenv("NAUTILUS_REGISTRY_CACHE_URL"), "/"),
+		tagTTL:     envSeconds("NAUTILUS_REGISTRY_TAG_TTL_SECONDS", 600),
+		client:     &http.Client{Timeout: 30 * time.Minute},
+		tokens:     make(map[string]registryToken),
+		tags:       make(map[string]registryTag),
+		stats:      registryCacheStats{requests: make(map[string]uint64)},
+	}
+}
+
+// Start - 주기적으로 캐시 크기 상한 적용
+func (c *RegistryCache) Start(ctx context.Context) {
+	if !c.enabled {
+		return
+	}
+	c.logger.Infof("📦 Registry cache enabled: upstream=%s dir=%s allow=%v", c.upstream, c.dir, c.allowlist)
+
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.slo != nil && c.slo.Defer("registry_cache_prune") {
+				continue
+			}
+			c.prune()
+		}
+	}
+}
+
+// handleMirror - 워커가 K3s registries.yaml을 만들 때 사용할 미러 목록
+func (c *RegistryCache) handleMirror(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	worker, exists := c.workerPool.GetWorker(r.URL.Query().Get("node_id"))
+	if !exists {
+		http.Error(w, "Unknown worker", http.StatusNotFound)
+		return
+	}
+	if worker.SealToken != "" && r.Header.Get("X-Seal-Token") != worker.SealToken {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return
+	}
+
+	mirrors := make([]registryMirror, 0, len(c.mirrors)+1)
+	if c.enabled {
+		endpoint := c.publicURL
+		if endpoint == "" {
+			scheme := "http"
+			if r.TLS != nil {
+				scheme = "https"
+			}
+			endpoint = scheme + "://" + r.Host
+		}
+		mirrors = append(mirrors, registryMirror{Endpoint: endpoint, SealAuth: true})
+	}
+	for _, endpoint := range c.mirrors {
+		mirrors = append(mirrors, registryMirror{Endpoint: endpoint})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "success",
+		"registry":  "docker.io",
+		"mirrors":   mirrors,
+		"allowlist": c.allowlist,
+	})
+}
+
+// ServeHTTP - /v2/ 레지스트리 API (GET/HEAD만 지원하는 읽기 전용 미러)
+func (c *RegistryCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !c.enabled {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+	if !c.authorized(r) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm=%q`, registryAuthRealm))
+		registryError(w, http.StatusUnauthorized, "UNAUTHORIZED", "seal token required")
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		registryError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "read-only mirror")
+		return
+	}
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	repo, kind, ref, ok := parseRegistryPath(r.URL.Path)
+	if !ok {
+		registryError(w, http.StatusNotFound, "NAME_UNKNOWN", "unsupported path")
+		return
+	}
+	if !c.allowed(repo) {
+		c.count("", "denied")
+		c.logger.Warnf("🚫 Registry cache denied %s (not in allowlist)", repo)
+		registryError(w, http.StatusForbidden, "DENIED", repo+" is not in the registry cache allowlist")
+		return
+	}
+
+	switch kind {
+	case "manifests":
+		c.serveManifest(w, r, repo, ref)
+	case "blobs":
+		c.serveBlob(w, r, repo, ref)
+	}
+}
+
+// authorized - Basic 인증 사용자명은 노드 ID, 비밀번호는 Seal 토큰
+func (c *RegistryCache) authorized(r *http.Request) bool {
+	nodeID, token, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	worker, exists := c.workerPool.GetWorker(nodeID)
+	return exists && worker.SealToken != "" && token == worker.SealToken
+}
+
+/*
+parseRegistryPath - /v2/<name>/manifests/<ref>, /v2/<name>/blobs/<digest> 분해
+공식 이미지는 containerd가 library/ 접두사를 붙여 요청하지만 직접 호출에 대비해 보정합니다.
+*/
+func parseRegistryPath(p string) (repo, kind, ref string, ok bool) {
+	p = strings.TrimPrefix(p, "/v2/")
+	for _, k := range []string{"manifests", "blobs"} {
+		idx := strings.LastIndex(p, "/"+k+"/")
+		if idx <= 0 {
+			continue
+		}
+		repo, ref = p[:idx], p[idx+len(k)+2:]
+		if ref == "" || strings.Contains(ref, "/") {
+			return "", "", "", false
+		}
+		if !strings.Contains(repo, "/") {
+			repo = "library/" + repo
+		}
+		return repo, k, ref, true
+	}
+	return "", "", "", false
+}
+
+// allowed - 허용 목록 패턴 ("*" 전체, "library/*" 한 단계, "org/**" 하위 전체)
+func (c *RegistryCache) allowed(repo string) bool {
+	for _, pattern := range c.allowlist {
+		if pattern == "*" {
+			return true
+		}
+		if prefix := strings.TrimSuffix(pattern, "/**"); prefix != pattern && strings.HasPrefix(repo, prefix+"/") {
+			return true
+		}
+		if matched, _ := path.Match(pattern, repo); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isDigest - sha256:<64 hex>
+func isDigest(ref string) bool {
+	hexPart := strings.TrimPrefix(ref, "sha256:")
+	if hexPart == ref || len(hexPart) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(hexPart)
+	return err == nil
+}
+
+func (c *RegistryCache) contentPath(kind, digest string) string {
+	return filepath.Join(c.dir, kind, "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+// serveManifest - 태그는 TTL 동안 캐시된 다이제스트로, 다이제스트는 캐시 파일로 응답
+func (c *RegistryCache) serveManifest(w http.ResponseWriter, r *http.Request, repo, ref string) {
+	digest := ref
+	var stale *registryTag
+	if !isDigest(ref) {
+		c.mutex.Lock()
+		tag, ok := c.tags[repo+":"+ref]
+		c.mutex.Unlock()
+		if ok && time.Since(tag.fetched) < c.tagTTL {
+			digest = tag.digest
+		} else if ok {
+			stale = &tag
+		}
+	}
+
+	if isDigest(digest) {
+		if body, contentType, err := c.readManifest(digest); err == nil {
+			c.count("manifest", "hit")
+			writeManifest(w, r, body, contentType, digest)
+			return
+		}
+	}
+
+	body, contentType, fetchedDigest, err := c.fetchManifest(r, repo, ref)
+	if err != nil {
+		// 업스트림 장애나 pull 한도 초과 시 만료된 태그 매핑이라도 사용
+		if stale != nil {
+			if body, contentType, readErr := c.readManifest(stale.digest); readErr == nil {
+				c.count("manifest", "stale")
+				c.logger.Warnf("⚠️ Registry upstream failed for %s:%s, serving cached %s: %v", repo, ref, stale.digest, err)
+				writeManifest(w, r, body, contentType, stale.digest)
+				return
+			}
+		}
+		c.count("", "upstream_error")
+		c.logger.Errorf("❌ Registry manifest fetch failed %s:%s: %v", repo, ref, err)
+		registryError(w, http.StatusBadGateway, "UNKNOWN", err.Error())
+		return
+	}
+
+	c.count("manifest", "miss")
+	if err := c.storeManifest(fetchedDigest, body, contentType); err != nil {
+		c.logger.Warnf("⚠️ Failed to cache manifest %s: %v", fetchedDigest, err)
+	}
+	if !isDigest(ref) {
+		c.mutex.Lock()
+		c.tags[repo+":"+ref] = registryTag{digest: fetchedDigest, fetched: time.Now()}
+		c.mutex.Unlock()
+	}
+	writeManifest(w, r, body, contentType, fetchedDigest)
+}
+
+func writeManifest(w http.ResponseWriter, r *http.Request, body []byte, contentType, digest string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+func (c *RegistryCache) readManifest(digest string) ([]byte, string, error) {
+	p := c.contentPath("manifests", digest)
+	body, err := os.ReadFile(p)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType, err := os.ReadFile(p + ".type")
+	if err != nil {
+		return nil, "", err
+	}
+	return body, string(contentType), nil
+}
+
+func (c *RegistryCache) storeManifest(digest string, body []byte, contentType string) error {
+	p := c.contentPath("manifests", digest)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p+".type", []byte(contentType), 0600); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, body, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// fetchManifest - 업스트림 manifest 조회 (클라이언트 Accept 전달, 다이제스트 검증)
+func (c *RegistryCache) fetchManifest(r *http.Request, repo, ref string) ([]byte, string, string, error) {
+	resp, err := c.upstreamGet(repo, "/v2/"+repo+"/manifests/"+ref, r.Header.Values("Accept"))
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, "", "", err
+	}
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if isDigest(ref) && digest != ref {
+		return nil, "", "", fmt.Errorf("manifest digest mismatch: got %s", digest)
+	}
+	return body, resp.Header.Get("Content-Type"), digest, nil
+}
+
+/*
+serveBlob - 캐시된 blob은 파일에서 (Range 지원), 없으면 업스트림에서 받으며 동시에 저장
+받은 내용의 다이제스트가 요청과 일치할 때만 캐시에 넣습니다.
+*/
+func (c *RegistryCache) serveBlob(w http.ResponseWriter, r *http.Request, repo, digest string) {
+	if !isDigest(digest) {
+		registryError(w, http.StatusBadRequest, "DIGEST_INVALID", "unsupported digest")
+		return
+	}
+
+	p := c.contentPath("blobs", digest)
+	if file, err := os.Open(p); err == nil {
+		defer file.Close()
+		c.count("blob", "hit")
+		now := time.Now()
+		os.Chtimes(p, now, now) // 크기 상한 적용 시 최근 사용 순서 기준
+		if info, err := file.Stat(); err == nil && r.Method != http.MethodHead {
+			c.addBytes(true, uint64(info.Size()))
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Docker-Content-Digest", digest)
+		http.ServeContent(w, r, "", time.Time{}, file)
+		return
+	}
+
+	resp, err := c.upstreamGet(repo, "/v2/"+repo+"/blobs/"+digest, nil)
+	if err != nil {
+		c.count("", "upstream_error")
+		c.logger.Errorf("❌ Registry blob fetch failed %s@%s: %v", repo, digest, err)
+		registryError(w, http.StatusBadGateway, "UNKNOWN", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	c.count("blob", "miss")
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", digest)
+	if resp.ContentLength >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	tmp, err := c.tempBlob()
+	if err != nil {
+		c.logger.Warnf("⚠️ Registry cache not writable, streaming only: %v", err)
+		n, _ := io.Copy(w, resp.Body)
+		c.addBytes(false, uint64(n))
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, tmp, hasher), resp.Body)
+	c.addBytes(false, uint64(n))
+	tmp.Close()
+	if err != nil {
+		return
+	}
+	if got := "sha256:" + hex.EncodeToString(hasher.Sum(nil)); got != digest {
+		c.logger.Errorf("❌ Registry blob digest mismatch for %s: got %s", digest, got)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err == nil {
+		os.Rename(tmp.Name(), p)
+	}
+}
+
+func (c *RegistryCache) tempBlob() (*os.File, error) {
+	dir := filepath.Join(c.dir, "tmp")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, "blob-")
+}
+
+/*
+upstreamGet - 업스트림 GET (Bearer 토큰 challenge 처리)
+Docker Hub는 401과 함께 토큰 발급 주소를 알려주며, 저장소별 토큰을 만료 전까지 재사용합니다.
+NAUTILUS_REGISTRY_USERNAME/PASSWORD가 있으면 인증 사용자 한도로 토큰을 받습니다.
+*/
+func (c *RegistryCache) upstreamGet(repo, p string, accept []string) (*http.Response, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, c.upstream+p, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range accept {
+			req.Header.Add("Accept", value)
+		}
+		c.mutex.Lock()
+		token, ok := c.tokens[repo]
+		c.mutex.Unlock()
+		if ok && time.Now().Before(token.expires) {
+			req.Header.Set("Authorization", "Bearer "+token.value)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, fmt.Errorf("upstream returned HTTP %d", resp.StatusCode)
+		}
+		if err := c.refreshToken(repo, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("upstream authorization failed")
+}
+
+// refreshToken - WWW-Authenticate: Bearer realm="...",service="...",scope="..." 로 토큰 발급
+func (c *RegistryCache) refreshToken(repo, challenge string) error {
+	params, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"service": {params["service"]}, "scope": {"repository:" + repo + ":pull"}}
+	req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token request returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid token response: %v", err)
+	}
+	if result.Token == "" {
+		result.Token = result.AccessToken
+	}
+	if result.ExpiresIn <= 0 {
+		result.ExpiresIn = 60
+	}
+
+	c.mutex.Lock()
+	c.tokens[repo] = registryToken{
+		value:   result.Token,
+		expires: time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - 10*time.Second),
+	}
+	c.mutex.Unlock()
+	return nil
+}
+
+// parseBearerChallenge - WWW-Authenticate Bearer 파라미터 (realm 필수)
+func parseBearerChallenge(challenge string) (map[string]string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return nil, fmt.Errorf("unsupported upstream auth challenge: %q", challenge)
+	}
+	params := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			params[key] = strings.Trim(value, `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return nil, fmt.Errorf("auth challenge without realm")
+	}
+	return params, nil
+}
+
+// prune - 캐시 크기가 상한을 넘으면 오래 안 쓴 blob부터 삭제 (manifest는 작아서 유지)
+func (c *RegistryCache) prune() {
+	type blobFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var blobs []blobFile
+	var total int64
+	filepath.Walk(filepath.Join(c.dir, "blobs"), func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			blobs = append(blobs, blobFile{p, info.Size(), info.ModTime()})
+			total += info.Size()
+		}
+		return nil
+	})
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].modTime.Before(blobs[j].modTime) })
+	removed := 0
+	for _, blob := range blobs {
+		if total <= c.maxBytes {
+			break
+		}
+		if os.Remove(blob.path) == nil {
+			total -= blob.size
+			removed++
+		}
+	}
+
+	c.mutex.Lock()
+	c.stats.pruned += uint64(removed)
+	c.mutex.Unlock()
+	c.logger.Infof("🧹 Registry cache pruned %d blobs (now %d MiB)", removed, total>>20)
+}
+
+func (c *RegistryCache) count(kind, result string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	switch result {
+	case "denied":
+		c.stats.denied++
+	case "upstream_error":
+		c.stats.upstreamErrs++
+	default:
+		c.stats.requests[kind+"/"+result]++
+	}
+}
+
+func (c *RegistryCache) addBytes(fromCache bool, n uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if fromCache {
+		c.stats.bytesCache += n
+	} else {
+		c.stats.bytesUpstream += n
+	}
+}
+
+// registryError - 레지스트리 v2 오류 형식
+func registryError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
+
+// writeMetrics - 캐시 적중률/전송량 메트릭
+func (c *RegistryCache) writeMetrics(w io.Writer) {
+	if !c.enabled {
+		return
+	}
+	c.mutex.Lock()
+	requests := make(map[string]uint64, len(c.stats.requests))
+	for key, value := range c.stats.requests {
+		requests[key] = value
+	}
+	stats := c.stats
+	c.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_registry_cache_requests_total", "counter", "Registry cache lookups by kind and result (hit, miss, stale)")
+	for _, kind := range []string{"manifest", "blob"} {
+		for _, result := range []string{"hit", "miss", "stale"} {
+			writeMetric(w, "nautilus_registry_cache_requests_total", map[string]string{"kind": kind, "result": result}, float64(requests[kind+"/"+result]))
+		}
+	}
+	writeMetricHeader(w, "nautilus_registry_cache_bytes_total", "counter", "Blob bytes served to workers by source")
+	writeMetric(w, "nautilus_registry_cache_bytes_total", map[string]string{"source": "cache"}, float64(stats.bytesCache))
+	writeMetric(w, "nautilus_registry_cache_bytes_total", map[string]string{"source": "upstream"}, float64(stats.bytesUpstream))
+	writeMetricHeader(w, "nautilus_registry_cache_denied_total", "counter", "Pulls rejected because the repository is not in the allowlist")
+	writeMetric(w, "nautilus_registry_cache_denied_total", nil, float64(stats.denied))
+	writeMetricHeader(w, "nautilus_registry_cache_upstream_errors_total", "counter", "Upstream registry failures not covered by the cache")
+	writeMetric(w, "nautilus_registry_cache_upstream_errors_total", nil, float64(stats.upstreamErrs))
+	writeMetricHeader(w, "nautilus_registry_cache_pruned_blobs_total", "counter", "Blobs evicted to stay under NAUTILUS_REGISTRY_CACHE_MAX_GB")
+	writeMetric(w, "nautilus_registry_cache_pruned_blobs_total", nil, float64(stats.pruned))
+}
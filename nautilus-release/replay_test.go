@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	sui "github.com/k3s-io/daas-sui"
+)
+
+// 컨트랙트 이벤트 재생 테스트
+// testdata/replay/<이름>.events.json에 기록된 이벤트 순서를 이벤트 처리 경로에 그대로 넣고,
+// 결과 워커 풀 상태, 실행한 kubectl 명령, 컨트랙트 트랜잭션(sui client call), 요청별 실행 결과(감사 이벤트), 노드 이벤트를
+// <이름>.golden.json과 비교합니다. 핸들러 동작을 의도적으로 바꿨다면 아래로 골든 파일을 갱신하세요.
+//
+//	go test -run TestEventReplay -update .
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/replay golden files")
+
+// replayFixture - 기록된 이벤트 순서와 외부 명령 응답
+type replayFixture struct {
+	Description string `json:"description"`
+	// K3sRunning이 false면 kubectl 준비 확인이 실패한 상태로 재생
+	K3sRunning bool              `json:"k3s_running"`
+	Responses  []replayResponse  `json:"responses,omitempty"`
+	Events     []json.RawMessage `json:"events"`
+}
+
+// replayResponse - 명령 문자열(예: "kubectl get pods -o json -n default")별 응답, 없으면 빈 출력으로 성공
+type replayResponse struct {
+	Command string `json:"command"`
+	Stdout  string `json:"stdout,omitempty"`
+	Stderr  string `json:"stderr,omitempty"`
+	Fail    bool   `json:"fail,omitempty"`
+}
+
+// replayResult - 골든 파일 형식 (시각 등 비결정적 필드 제외)
+type replayResult struct {
+	Workers  []replayWorker               `json:"workers"`
+	Commands []string                     `json:"commands"`
+	Results  []replayRequestResult        `json:"results,omitempty"`
+	Events   map[string][]replayNodeEvent `json:"node_events,omitempty"`
+}
+
+type replayWorker struct {
+	NodeID        string `json:"node_id"`
+	Status        string `json:"status"`
+	Role          string `json:"role"`
+	StakeAmount   uint64 `json:"stake_amount"`
+	WorkerAddress string `json:"worker_address"`
+	JoinToken     string `json:"join_token,omitempty"`
+}
+
+type replayRequestResult struct {
+	RequestID  string `json:"request_id"`
+	Verb       string `json:"verb"`
+	RequestURI string `json:"request_uri"`
+	Code       int    `json:"code"`
+	Error      string `json:"error,omitempty"`
+}
+
+type replayNodeEvent struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+func TestEventReplay(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "replay", "*.events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no replay fixtures in testdata/replay")
+	}
+
+	for _, path := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(path), ".events.json")
+		t.Run(name, func(t *testing.T) {
+			var fixture replayFixture
+			readReplayJSON(t, path, &fixture)
+
+			got := replayEvents(t, &fixture)
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(got); err != nil {
+				t.Fatal(err)
+			}
+			encoded := buf.Bytes()
+
+			goldenPath := strings.TrimSuffix(path, ".events.json") + ".golden.json"
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, encoded, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if string(want) != string(encoded) {
+				t.Errorf("%s: replay result differs from %s\n--- want\n%s\n--- got\n%s",
+					fixture.Description, goldenPath, want, encoded)
+			}
+		})
+	}
+}
+
+// replayEvents - 외부 명령을 기록하는 SuiIntegration으로 이벤트를 순서대로 처리
+func replayEvents(t *testing.T, fixture *replayFixture) *replayResult {
+	// 스테이킹 티어 기준은 환경과 무관하게 고정
+	t.Setenv("MIN_STAKE_AMOUNT", "1000000")
+
+	logger := benchLogger()
+	k3sMgr := NewK3sManager(logger)
+	k3sMgr.configFile = filepath.Join(t.TempDir(), "k3s.yaml")
+	if fixture.K3sRunning {
+		if err := os.WriteFile(k3sMgr.configFile, []byte("apiVersion: v1\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	responses := make(map[string]replayResponse, len(fixture.Responses))
+	for _, response := range fixture.Responses {
+		responses[response.Command] = response
+	}
+
+	result := &replayResult{Commands: []string{}, Events: make(map[string][]replayNodeEvent)}
+	s := &SuiIntegration{
+		logger:        logger,
+		k3sMgr:        k3sMgr,
+		workerPool:    k3sMgr.workerPool,
+		sealTokenMgr:  k3sMgr.sealTokenManager,
+		controllerMgr: NewControllerManager(logger, k3sMgr),
+		history:       NewHeartbeatHistory(logger),
+		audit: &AuditLogger{
+			logger: logger,
+			policy: []AuditPolicyRule{{Level: AuditLevelMetadata}},
+			queue:  make(chan *AuditEvent, len(fixture.Events)),
+		},
+		backend:      sui.NewBackend(sui.NewReadOnlyClient("", "0xreplay"), "", ""),
+		contractAddr: "0xreplay",
+		registryAddr: "0xregistry",
+		eventChan:    make(chan *SuiContractEvent, len(fixture.Events)),
+		inFlight:     make(map[string]*K8sAPIRequest),
+	}
+	s.runner = func(name string, stdin []byte, args ...string) ([]byte, []byte, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		if len(stdin) > 0 {
+			result.Commands = append(result.Commands, command+" <<< "+string(stdin))
+		} else {
+			result.Commands = append(result.Commands, command)
+		}
+		if command == "kubectl get nodes" && !fixture.K3sRunning {
+			return nil, []byte("connection refused"), errors.New("exit status 1")
+		}
+		response := responses[command]
+		if response.Fail {
+			return []byte(response.Stdout), []byte(response.Stderr), errors.New("exit status 1")
+		}
+		return []byte(response.Stdout), []byte(response.Stderr), nil
+	}
+
+	for i, raw := range fixture.Events {
+		var eventJSON suiEventJSON
+		if err := json.Unmarshal(raw, &eventJSON); err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+		// 폴링 경로와 같은 필터(acceptEvent)를 거쳐 처리
+		if event := s.parseEventFromAPI(&eventJSON); event != nil {
+			s.processEvent(context.Background(), event)
+		}
+	}
+
+	// 감사 이벤트는 처리 순서대로 큐에 쌓여 있음
+	for len(s.audit.queue) > 0 {
+		event := <-s.audit.queue
+		result.Results = append(result.Results, replayRequestResult{
+			RequestID:  event.AuditID,
+			Verb:       event.Verb,
+			RequestURI: event.RequestURI,
+			Code:       event.ResponseStatus.Code,
+			Error:      event.ResponseStatus.Message,
+		})
+	}
+
+	workers := s.workerPool.ListWorkers()
+	sort.Slice(workers, func(i, j int) bool { return workers[i].NodeID < workers[j].NodeID })
+	for _, worker := range workers {
+		result.Workers = append(result.Workers, replayWorker{
+			NodeID:        worker.NodeID,
+			Status:        worker.Status,
+			Role:          worker.Role,
+			StakeAmount:   worker.StakeAmount,
+			WorkerAddress: worker.WorkerAddress,
+			JoinToken:     worker.JoinToken,
+		})
+	}
+
+	// 워커 풀에 없는 대상(예: 마스터 attestation 실패)의 이벤트도 포함
+	for nodeID := range s.history.events {
+		_, events := s.history.Timeline(nodeID, time.Time{}, 0)
+		for _, event := range events {
+			result.Events[nodeID] = append(result.Events[nodeID], replayNodeEvent{Kind: event.Kind, Detail: event.Detail})
+		}
+	}
+	return result
+}
+
+func readReplayJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Request Signing - Gateway 서명 검증 및 마스터 응답 서명 (형식과 nonce 검증은 공용 pkg/signing)
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/k3s-io/daas-signing"
+	"github.com/sirupsen/logrus"
+)
+
+// 서명 헤더
+const (
+	signatureTimestampHeader = signing.HeaderTimestamp
+	signatureNonceHeader     = signing.HeaderNonce
+	signatureHeader          = signing.HeaderSignature
+)
+
+// RequestSigner - Gateway 요청 검증 및 응답 서명
+type RequestSigner struct {
+	logger     *logrus.Logger
+	verifier   *signing.Verifier // Gateway 요청 서명/nonce 재사용 검증 (GATEWAY_PUBLIC_KEY가 없으면 nil)
+	signingKey EnclaveSigner
+	payloads   *PayloadDecryptor // 증명 문서로 공지할 본문 암호화 키 (EncryptedPayloads 게이트가 꺼져 있으면 nil)
+}
+
+// NewRequestSigner - 새 Request Signer 생성
+// GATEWAY_PUBLIC_KEY가 없으면 검증 비활성, 서명 키는 키링에서 봉인된 키를 열거나 생성
+func NewRequestSigner(logger *logrus.Logger, keyring *EnclaveKeyring) (*RequestSigner, error) {
+	s := &RequestSigner{logger: logger}
+
+	if hexKey := os.Getenv("GATEWAY_PUBLIC_KEY"); hexKey != "" {
+		raw, err := hex.DecodeString(hexKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid GATEWAY_PUBLIC_KEY")
+		}
+		s.verifier = signing.NewVerifier(ed25519.PublicKey(raw))
+	} else {
+		logger.Warn("⚠️ GATEWAY_PUBLIC_KEY not set, K8s API proxy accepts unsigned requests")
+	}
+
+	signingKey, err := keyring.Load(responseSigningKey())
+	if err != nil {
+		return nil, err
+	}
+	s.signingKey = signingKey
+	logger.Infof("🔏 Response signing public key: %s", hex.EncodeToString(s.publicKey()))
+
+	return s, nil
+}
+
+// responseSigningKey - 응답/증명 서명용 Ed25519 키 (이전 NAUTILUS_SIGNING_KEY, response-signing.key를 가져와 봉인)
+func responseSigningKey() enclaveKeySpec {
+	return enclaveKeySpec{
+		name:       "response-signing",
+		envVar:     "NAUTILUS_SIGNING_KEY",
+		legacyFile: statePath("response-signing.key"),
+		parse:      parseEd25519Seed,
+		generate: func() (crypto.Signer, error) {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			return key, err
+		},
+	}
+}
+
+// publicKey - 응답 서명 공개키
+func (s *RequestSigner) publicKey() ed25519.PublicKey {
+	return s.signingKey.Public().(ed25519.PublicKey)
+}
+
+// signMessage - 봉인 키로 Ed25519 서명 (Ed25519 서명은 실패하지 않음)
+func (s *RequestSigner) signMessage(message []byte) []byte {
+	signature, _ := s.signingKey.Sign(message)
+	return signature
+}
+
+/*
+Wrap - 요청 서명 검증 후 응답 서명
+
+일반 응답은 버퍼링하여 본문 전체에 서명하고,
+watch 응답은 끝나지 않으므로 버퍼링하지 않고 Flush마다 서명한 프레임으로 보냅니다 (signing.ResponseStream).
+*/
+func (s *RequestSigner) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.verifier == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := s.verifier.VerifyRequest(r, body); err != nil {
+			s.logger.Warnf("🚫 Rejected unsigned K8s API request from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "Invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		// 업스트림(K3s)이 압축한 본문에 서명하면 Gateway의 투명 압축 해제 후 검증이 깨지므로
+		// 비압축 본문에 서명하고 압축은 바깥 Compress 미들웨어에 맡김
+		r.Header.Del("Accept-Encoding")
+
+		// watch는 끝나지 않으므로 버퍼링하지 않고 프레임마다 서명
+		if isWatchRequest(r) {
+			stream := signing.NewResponseStream(w, s.signMessage, r.Header.Get(signatureNonceHeader))
+			next.ServeHTTP(stream, r)
+			stream.Close()
+			return
+		}
+
+		recorder := &signedResponseRecorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		s.sign(w.Header(), recorder.status, r.Header.Get(signatureNonceHeader), recorder.body.Bytes())
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+	})
+}
+
+// verifying - Gateway 서명을 검증하는지 (GATEWAY_PUBLIC_KEY 설정)
+func (s *RequestSigner) verifying() bool {
+	return s != nil && s.verifier != nil
+}
+
+// sign - 응답 헤더에 서명 추가 (요청 nonce에 묶음)
+func (s *RequestSigner) sign(header http.Header, status int, nonce string, body []byte) {
+	signing.SignResponseWith(s.signMessage, header, status, nonce, body)
+}
+
+// signedResponseRecorder - 서명을 위해 응답을 버퍼링
+type signedResponseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *signedResponseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *signedResponseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *signedResponseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
@@ -0,0 +1,438 @@
+// Response Store - K8s API 실행 결과의 온체인 기록 (zstd 압축, 크기 초과분 오프체인 저장, 작은 응답 배치 제출)
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+record_api_result의 output 문자열 형식 (K8sAPIResultEvent를 읽는 쪽에서 접두사로 구분)
+
+	<원문>                                     압축이 이득이 없거나 꺼진 작은 응답
+	zstd:<base64(zstd(원문))>                   NAUTILUS_RESPONSE_COMPRESS_MIN_BYTES 이상에서 더 작아질 때
+	blob:sha256=<hex>;size=<n>[;walrus=<id>]   인라인 한도 초과 (GET /api/v1/responses/<hex> 또는 Walrus 집계기에서 조회)
+	truncated:<n>:<원문 앞부분>                 인라인 한도 초과 + NAUTILUS_RESPONSE_OVERFLOW=truncate (또는 blob 저장 실패)
+
+인라인 한도(NAUTILUS_RESPONSE_MAX_INLINE_BYTES)는 Sui의 pure 인자 최대 크기(16KiB) 아래로 둡니다.
+인코딩된 output이 NAUTILUS_RESPONSE_BATCH_ITEM_BYTES 이하인 결과는 모아 두었다가
+NAUTILUS_RESPONSE_BATCH_INTERVAL초마다(또는 NAUTILUS_RESPONSE_BATCH_MAX건이 차면) record_api_results 한 번으로 제출합니다.
+*/
+
+const (
+	responseCompressionZstd = "zstd"
+	responseOverflowBlob    = "blob"
+	responseOverflowTrunc   = "truncate"
+
+	responseMaxErrorBytes  = 2048
+	responseSubmitAttempts = 3
+	responseBlobExpiry     = time.Hour
+)
+
+// pendingResult - 배치 제출 대기 중인 결과 (output은 인코딩 완료)
+type pendingResult struct {
+	result   K8sAPIResult
+	attempts int
+}
+
+/*
+ResponseStore - 실행 결과 인코딩과 온체인 제출
+오프체인 blob은 상태 디렉토리(response-blobs/)에 보관 기간 동안 두고, Walrus 퍼블리셔가 설정되면 함께 업로드합니다.
+온체인 output은 원래 공개 데이터이므로 blob 조회에는 인증이 없습니다 (내용 해시를 알아야 조회 가능).
+*/
+type ResponseStore struct {
+	logger      *logrus.Logger
+	sui         *SuiIntegration
+	compression string
+	compressMin int
+	maxInline   int
+	overflow    string
+	blobDir     string
+	retention   time.Duration
+	walrusURL   string
+	walrusEpoch int
+	interval    time.Duration
+	batchMax    int
+	batchItem   int
+	encoder     *zstd.Encoder
+	client      *http.Client
+
+	mutex        sync.Mutex
+	pending      []pendingResult
+	pendingBytes int
+	results      map[string]uint64 // 인코딩 방식별 결과 수
+	inputBytes   uint64
+	chainBytes   uint64
+	txs          map[string]uint64 // single | batch
+	failures     uint64
+	dropped      uint64
+	blobFailures uint64
+}
+
+// envCount - 양의 정수 환경변수 (잘못된 값은 기본값)
+func envCount(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnvOrDefault(key, strconv.Itoa(defaultValue)))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// NewResponseStore - 환경변수 설정으로 Response Store 생성
+func NewResponseStore(logger *logrus.Logger, sui *SuiIntegration) (*ResponseStore, error) {
+	r := &ResponseStore{
+		logger:      logger,
+		sui:         sui,
+		compression: strings.ToLower(getEnvOrDefault("NAUTILUS_RESPONSE_COMPRESSION", responseCompressionZstd)),
+		compressMin: envCount("NAUTILUS_RESPONSE_COMPRESS_MIN_BYTES", 256),
+		maxInline:   envCount("NAUTILUS_RESPONSE_MAX_INLINE_BYTES", 15000),
+		overflow:    strings.ToLower(getEnvOrDefault("NAUTILUS_RESPONSE_OVERFLOW", responseOverflowBlob)),
+		blobDir:     statePath("response-blobs"),
+		retention:   time.Duration(envCount("NAUTILUS_RESPONSE_BLOB_RETENTION_HOURS", 168)) * time.Hour,
+		walrusURL:   strings.TrimRight(os.Getenv("NAUTILUS_WALRUS_PUBLISHER_URL"), "/"),
+		walrusEpoch: envCount("NAUTILUS_WALRUS_EPOCHS", 5),
+		interval:    envSeconds("NAUTILUS_RESPONSE_BATCH_INTERVAL", 2),
+		batchMax:    envCount("NAUTILUS_RESPONSE_BATCH_MAX", 32),
+		batchItem:   envCount("NAUTILUS_RESPONSE_BATCH_ITEM_BYTES", 1024),
+		client:      &http.Client{Timeout: 60 * time.Second},
+		results:     make(map[string]uint64),
+		txs:         make(map[string]uint64),
+	}
+
+	switch r.compression {
+	case responseCompressionZstd:
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %v", err)
+		}
+		r.encoder = encoder
+	case "none":
+	default:
+		return nil, fmt.Errorf("unsupported NAUTILUS_RESPONSE_COMPRESSION %q (zstd, none)", r.compression)
+	}
+	if r.overflow != responseOverflowBlob && r.overflow != responseOverflowTrunc {
+		return nil, fmt.Errorf("unsupported NAUTILUS_RESPONSE_OVERFLOW %q (blob, truncate)", r.overflow)
+	}
+	if r.batchItem > r.maxInline {
+		r.batchItem = r.maxInline
+	}
+	return r, nil
+}
+
+// Start - 배치 주기 제출과 만료된 blob 정리 (종료 시 남은 배치 제출)
+func (r *ResponseStore) Start(ctx context.Context) {
+	r.logger.Infof("💾 Response store: compression=%s, inline limit %dB, overflow=%s, batches of up to %d every %s",
+		r.compression, r.maxInline, r.overflow, r.batchMax, r.interval)
+
+	flush := time.NewTicker(r.interval)
+	defer flush.Stop()
+	expire := time.NewTicker(responseBlobExpiry)
+	defer expire.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.Flush()
+			return
+		case <-flush.C:
+			r.Flush()
+		case <-expire.C:
+			r.expireBlobs()
+		}
+	}
+}
+
+// Submit - 결과 인코딩 후 작은 응답은 배치에, 나머지는 바로 제출
+func (r *ResponseStore) Submit(result *K8sAPIResult) {
+	if !r.sui.canSubmit() {
+		r.logger.Debugf("💾 Result %s not recorded on-chain (no signing key)", result.RequestID)
+		return
+	}
+
+	encoded := *result
+	var mode string
+	encoded.Output, mode = r.encodeOutput(result.RequestID, result.Output)
+	encoded.Error = truncateUTF8(result.Error, responseMaxErrorBytes)
+
+	r.mutex.Lock()
+	r.results[mode]++
+	r.inputBytes += uint64(len(result.Output))
+	r.chainBytes += uint64(len(encoded.Output))
+
+	if r.batchMax <= 1 || len(encoded.Output) > r.batchItem {
+		r.mutex.Unlock()
+		r.submitSingle(pendingResult{result: encoded})
+		return
+	}
+	r.pending = append(r.pending, pendingResult{result: encoded})
+	r.pendingBytes += len(encoded.Output)
+	due := len(r.pending) >= r.batchMax || r.pendingBytes+r.batchItem > r.maxInline
+	r.mutex.Unlock()
+
+	if due {
+		r.Flush()
+	}
+}
+
+// Flush - 대기 중인 결과 즉시 제출
+func (r *ResponseStore) Flush() {
+	r.mutex.Lock()
+	items := r.pending
+	r.pending, r.pendingBytes = nil, 0
+	r.mutex.Unlock()
+
+	// 배치의 output 벡터도 하나의 pure 인자이므로 합계가 인라인 한도를 넘지 않게 나눔
+	var batch []pendingResult
+	size := 0
+	for _, item := range items {
+		length := len(item.result.Output)
+		if length > r.batchItem {
+			r.submitSingle(item)
+			continue
+		}
+		if len(batch) > 0 && (size+length > r.maxInline || len(batch) >= r.batchMax) {
+			r.submitBatch(batch)
+			batch, size = nil, 0
+		}
+		batch = append(batch, item)
+		size += length
+	}
+	if len(batch) > 0 {
+		r.submitBatch(batch)
+	}
+}
+
+// encodeOutput - 온체인 output 문자열과 인코딩 방식
+func (r *ResponseStore) encodeOutput(requestID, output string) (string, string) {
+	encoded, mode := output, "raw"
+	if r.encoder != nil && len(output) >= r.compressMin {
+		compressed := "zstd:" + base64.StdEncoding.EncodeToString(r.encoder.EncodeAll([]byte(output), nil))
+		if len(compressed) < len(output) {
+			encoded, mode = compressed, "zstd"
+		}
+	}
+	if len(encoded) <= r.maxInline {
+		return encoded, mode
+	}
+
+	if r.overflow == responseOverflowBlob {
+		ref, err := r.storeBlob([]byte(output))
+		if err == nil {
+			r.logger.Infof("📦 Response %s (%d bytes) stored off-chain: %s", requestID, len(output), ref)
+			return ref, "blob"
+		}
+		r.mutex.Lock()
+		r.blobFailures++
+		r.mutex.Unlock()
+		r.logger.Warnf("⚠️ Failed to store response %s off-chain, truncating: %v", requestID, err)
+	}
+
+	prefix := fmt.Sprintf("truncated:%d:", len(output))
+	return prefix + truncateUTF8(output, r.maxInline-len(prefix)), "truncated"
+}
+
+// truncateUTF8 - 문자 경계를 지키며 최대 limit 바이트로 자름
+func truncateUTF8(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
+
+// storeBlob - 원문을 내용 해시로 저장하고 온체인 참조 반환
+func (r *ResponseStore) storeBlob(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	if err := os.MkdirAll(r.blobDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create blob directory: %v", err)
+	}
+	path := filepath.Join(r.blobDir, digest)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0600); err != nil {
+			return "", fmt.Errorf("failed to write blob: %v", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return "", fmt.Errorf("failed to store blob: %v", err)
+		}
+	}
+
+	ref := fmt.Sprintf("blob:sha256=%s;size=%d", digest, len(data))
+	if r.walrusURL != "" {
+		blobID, err := uploadWalrusBlob(r.client, r.walrusURL, r.walrusEpoch, data)
+		if err != nil {
+			// 로컬 사본은 남아 있으므로 마스터 API로는 조회 가능
+			r.logger.Warnf("⚠️ Walrus upload of response blob %s failed: %v", digest[:12], err)
+		} else {
+			ref += ";walrus=" + blobID
+		}
+	}
+	return ref, nil
+}
+
+// expireBlobs - 보관 기간이 지난 로컬 blob 삭제
+func (r *ResponseStore) expireBlobs() {
+	entries, err := os.ReadDir(r.blobDir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-r.retention)
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(r.blobDir, entry.Name())) == nil {
+			removed++
+		}
+	}
+	if removed > 0 {
+		r.logger.Infof("🧹 Expired %d response blobs older than %s", removed, r.retention)
+	}
+}
+
+// submitSingle - record_api_result 한 건 제출
+func (r *ResponseStore) submitSingle(item pendingResult) {
+	refs := r.sui.activeContract()
+	result := item.result
+	err := r.sui.callContract("k8s_scheduler", "record_api_result", refs.Scheduler, refs.Registry,
+		result.RequestID, strconv.FormatBool(result.Success), result.Output, result.Error,
+		strconv.FormatInt(result.ExecutionTime, 10))
+	r.recordSubmission("single", []pendingResult{item}, err)
+}
+
+// submitBatch - record_api_results로 여러 건 제출 (한 건이면 단건 호출)
+func (r *ResponseStore) submitBatch(batch []pendingResult) {
+	if len(batch) == 1 {
+		r.submitSingle(batch[0])
+		return
+	}
+
+	requestIDs := make([]string, len(batch))
+	successes := make([]bool, len(batch))
+	outputs := make([]string, len(batch))
+	errors := make([]string, len(batch))
+	times := make([]int64, len(batch))
+	for i, item := range batch {
+		requestIDs[i] = item.result.RequestID
+		successes[i] = item.result.Success
+		outputs[i] = item.result.Output
+		errors[i] = item.result.Error
+		times[i] = item.result.ExecutionTime
+	}
+
+	refs := r.sui.activeContract()
+	err := r.sui.callContract("k8s_scheduler", "record_api_results", refs.Scheduler, refs.Registry,
+		jsonArg(requestIDs), jsonArg(successes), jsonArg(outputs), jsonArg(errors), jsonArg(times))
+	r.recordSubmission("batch", batch, err)
+}
+
+// jsonArg - 벡터 인자의 sui CLI 표기 (["a","b"])
+func jsonArg(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// recordSubmission - 제출 결과 집계 (실패한 결과는 다음 배치에서 최대 responseSubmitAttempts회까지 재시도)
+func (r *ResponseStore) recordSubmission(kind string, items []pendingResult, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err == nil {
+		r.txs[kind]++
+		r.logger.Debugf("💾 Recorded %d result(s) on-chain (%s)", len(items), kind)
+		return
+	}
+
+	r.failures++
+	r.logger.Errorf("❌ Failed to record %d result(s) on-chain: %v", len(items), err)
+	for _, item := range items {
+		item.attempts++
+		if item.attempts >= responseSubmitAttempts {
+			r.dropped++
+			r.logger.Errorf("❌ Giving up on result %s after %d attempts", item.result.RequestID, item.attempts)
+			continue
+		}
+		r.pending = append(r.pending, item)
+		r.pendingBytes += len(item.result.Output)
+	}
+}
+
+/*
+handleBlob - 오프체인에 저장된 응답 원문 (/api/v1/responses/{sha256})
+온체인 output의 blob:sha256=... 참조를 따라가는 조회용입니다.
+*/
+func (r *ResponseStore) handleBlob(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	digest := strings.TrimPrefix(req.URL.Path, "/api/v1/responses/")
+	if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+		http.Error(w, "Invalid response digest", http.StatusBadRequest)
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(r.blobDir, strings.ToLower(digest)))
+	if os.IsNotExist(err) {
+		http.Error(w, "Response not found (expired or stored on another master)", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Write(data)
+}
+
+// writeMetrics - 인코딩 방식별 결과 수, 바이트 절감, 트랜잭션 수, 실패
+func (r *ResponseStore) writeMetrics(w io.Writer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_response_results_total", "counter", "K8s API results recorded on-chain, by output encoding")
+	for _, mode := range []string{"raw", "zstd", "blob", "truncated"} {
+		writeMetric(w, "nautilus_response_results_total", map[string]string{"encoding": mode}, float64(r.results[mode]))
+	}
+	writeMetricHeader(w, "nautilus_response_bytes_total", "counter", "Result output bytes before encoding and as written on-chain")
+	writeMetric(w, "nautilus_response_bytes_total", map[string]string{"stage": "original"}, float64(r.inputBytes))
+	writeMetric(w, "nautilus_response_bytes_total", map[string]string{"stage": "onchain"}, float64(r.chainBytes))
+	writeMetricHeader(w, "nautilus_response_transactions_total", "counter", "Transactions submitted to record results")
+	for _, kind := range []string{"single", "batch"} {
+		writeMetric(w, "nautilus_response_transactions_total", map[string]string{"kind": kind}, float64(r.txs[kind]))
+	}
+	writeMetricHeader(w, "nautilus_response_pending", "gauge", "Results waiting for the next batch")
+	writeMetric(w, "nautilus_response_pending", nil, float64(len(r.pending)))
+	writeMetricHeader(w, "nautilus_response_submit_failures_total", "counter", "Failed result transactions")
+	writeMetric(w, "nautilus_response_submit_failures_total", nil, float64(r.failures))
+	writeMetricHeader(w, "nautilus_response_dropped_total", "counter", "Results abandoned after repeated submit failures")
+	writeMetric(w, "nautilus_response_dropped_total", nil, float64(r.dropped))
+	writeMetricHeader(w, "nautilus_response_blob_failures_total", "counter", "Oversized results truncated because off-chain storage failed")
+	writeMetric(w, "nautilus_response_blob_failures_total", nil, float64(r.blobFailures))
+}
@@ -0,0 +1,227 @@
+// Restart Checkpoint - 정상 종료 직전 상태를 저장해 다음 시작이 체인 전체 재구성 없이 변경분만 동기화
+//
+// 드레인이 끝난 뒤 워커 풀, 패키지별 마지막 처리 이벤트, 레지스트리 테이블 ID, 객체/스테이킹 캐시를
+// 한 파일(restart-checkpoint.json)에 기록합니다. 다음 시작은 체크포인트가 같은 컨트랙트 패키지에서
+// NAUTILUS_CHECKPOINT_MAX_AGE_SECONDS(기본 900초) 이내에 쓰였을 때만 읽고, 이벤트 구독을 기록된
+// 위치 다음부터 재개해 꺼져 있던 동안의 체인 변경을 반영합니다. 전체 풀 비교는 다음 주기 검사에서 합니다.
+// 체크포인트는 읽는 즉시 지우므로 비정상 종료 뒤에는 항상 온체인 전체 재구성으로 시작합니다.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+	sui "github.com/k3s-io/daas-sui"
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+// restartCheckpointVersion - 체크포인트 형식 (다른 버전은 읽지 않고 전체 재구성)
+const restartCheckpointVersion = 1
+
+// RestartCheckpoint - 종료 시 저장하는 상태
+type RestartCheckpoint struct {
+	Version      int                      `json:"version"`
+	Build        string                   `json:"build"`
+	Package      string                   `json:"package"`
+	WrittenAt    time.Time                `json:"written_at"`
+	Height       uint64                   `json:"height"` // 이벤트 조회가 따라잡은 체크포인트 (참고용)
+	Events       map[string]chain.EventID `json:"events"` // 패키지 → 마지막으로 처리를 마친 이벤트
+	Workers      []*WorkerNode            `json:"workers"`
+	PoolTables   map[string]string        `json:"pool_tables"`   // 레지스트리 → workers 테이블 ID
+	ReconciledAt time.Time                `json:"reconciled_at"` // 마지막 온체인 전체 정합성 검사
+	Cache        sui.CacheSnapshot        `json:"cache"`
+}
+
+// RestartCheckpointer - 체크포인트 저장/복원
+type RestartCheckpointer struct {
+	logger   *logrus.Logger
+	sui      *SuiIntegration
+	poolSync *PoolSync
+	path     string
+	maxAge   time.Duration
+
+	mutex    sync.Mutex
+	restored *RestartCheckpoint // 이번 시작에 복원한 체크포인트 (콜드 스타트면 nil)
+	took     time.Duration      // 복원(또는 콜드 스타트 재구성)에 걸린 시간
+}
+
+// NewRestartCheckpointer - 새 Restart Checkpointer 생성
+func NewRestartCheckpointer(logger *logrus.Logger, sui *SuiIntegration, poolSync *PoolSync) *RestartCheckpointer {
+	return &RestartCheckpointer{
+		logger:   logger,
+		sui:      sui,
+		poolSync: poolSync,
+		path:     statePath("restart-checkpoint.json"),
+		maxAge:   envSeconds("NAUTILUS_CHECKPOINT_MAX_AGE_SECONDS", 900),
+	}
+}
+
+/*
+Restore - 시작 시 체크포인트를 복원하거나, 없으면 온체인 상태로 워커 풀 재구성
+이벤트 구독(SuiIntegration.Start) 전에 호출해야 구독이 기록된 위치부터 재개됩니다.
+*/
+func (c *RestartCheckpointer) Restore() {
+	started := time.Now()
+	checkpoint, reason := c.load()
+	if checkpoint != nil {
+		c.apply(checkpoint)
+	} else {
+		if reason != "" {
+			c.logger.Infof("🧊 Cold start: %s", reason)
+		}
+		if err := c.poolSync.Rebuild(); err != nil {
+			c.logger.Errorf("❌ %v", err)
+		}
+	}
+
+	c.mutex.Lock()
+	c.restored, c.took = checkpoint, time.Since(started)
+	c.mutex.Unlock()
+}
+
+// load - 사용할 수 있는 체크포인트 읽기 (읽은 파일은 바로 삭제, 못 쓰면 이유 반환)
+func (c *RestartCheckpointer) load() (*RestartCheckpoint, string) {
+	if !features.Enabled(featureRestartCheckpoint) {
+		return nil, ""
+	}
+
+	var checkpoint RestartCheckpoint
+	found, err := loadJSONState(c.path, &checkpoint)
+	if found {
+		// 이번 실행이 비정상 종료되면 다음 시작이 오래된 위치에서 재개하지 않도록 한 번만 사용
+		if removeErr := os.Remove(c.path); removeErr != nil {
+			return nil, fmt.Sprintf("failed to consume restart checkpoint: %v", removeErr)
+		}
+	}
+	switch {
+	case err != nil:
+		return nil, fmt.Sprintf("unreadable restart checkpoint: %v", err)
+	case !found:
+		return nil, "no restart checkpoint (first start or unclean shutdown)"
+	case !c.sui.onSui():
+		return nil, fmt.Sprintf("restart checkpoints are not used on the %s chain backend", c.sui.backend.Name())
+	case checkpoint.Version != restartCheckpointVersion:
+		return nil, fmt.Sprintf("restart checkpoint format v%d is not v%d", checkpoint.Version, restartCheckpointVersion)
+	case checkpoint.Package != c.sui.contractAddr:
+		return nil, fmt.Sprintf("restart checkpoint is for package %s, not %s", checkpoint.Package, c.sui.contractAddr)
+	case time.Since(checkpoint.WrittenAt) > c.maxAge:
+		return nil, fmt.Sprintf("restart checkpoint is %s old (max %s)", time.Since(checkpoint.WrittenAt).Round(time.Second), c.maxAge)
+	case checkpoint.ReconciledAt.IsZero():
+		return nil, "restart checkpoint was written before the worker pool was loaded from chain"
+	}
+	return &checkpoint, ""
+}
+
+// apply - 워커 풀, 이벤트 재개 위치, 레지스트리 테이블 ID, 캐시 복원
+func (c *RestartCheckpointer) apply(checkpoint *RestartCheckpoint) {
+	for _, worker := range checkpoint.Workers {
+		if err := c.sui.workerPool.AddWorker(worker); err != nil {
+			c.logger.Debugf("Checkpoint worker %s skipped: %v", worker.NodeID, err)
+		}
+	}
+	c.sui.resumeFrom(checkpoint.Events)
+	c.poolSync.restore(checkpoint.PoolTables, checkpoint.ReconciledAt)
+	cached := c.sui.chain.RestoreCache(checkpoint.Cache)
+
+	c.logger.WithFields(logrus.Fields{
+		"written_at": checkpoint.WrittenAt.Format(time.RFC3339),
+		"build":      checkpoint.Build,
+		"packages":   len(checkpoint.Events),
+	}).Infof("⚡ Restored restart checkpoint: %d workers, %d cache entries, resuming events after checkpoint %d",
+		len(checkpoint.Workers), cached, checkpoint.Height)
+}
+
+// Save - 종료 시 체크포인트 기록 (드레인 후, 새 요청이 더 이상 상태를 바꾸지 않을 때)
+func (c *RestartCheckpointer) Save() error {
+	if !features.Enabled(featureRestartCheckpoint) || !c.sui.onSui() {
+		return nil
+	}
+
+	tables, reconciledAt := c.poolSync.checkpointState()
+	checkpoint := RestartCheckpoint{
+		Version:      restartCheckpointVersion,
+		Build:        version.Get("master").String(),
+		Package:      c.sui.contractAddr,
+		WrittenAt:    time.Now(),
+		Height:       c.sui.EventCursor(),
+		Events:       c.sui.eventPositions(),
+		Workers:      c.sui.workerPool.ListWorkers(),
+		PoolTables:   tables,
+		ReconciledAt: reconciledAt,
+		Cache:        c.sui.chain.CacheSnapshot(),
+	}
+	if err := saveJSONState(c.path, checkpoint); err != nil {
+		return fmt.Errorf("failed to write restart checkpoint: %v", err)
+	}
+	c.logger.Infof("💾 Wrote restart checkpoint: %d workers, %d packages, %d cached objects",
+		len(checkpoint.Workers), len(checkpoint.Events), len(checkpoint.Cache.Objects)+len(checkpoint.Cache.Stakes))
+	return nil
+}
+
+// writeMetrics - 이번 시작의 복원 여부와 소요 시간
+func (c *RestartCheckpointer) writeMetrics(w io.Writer) {
+	c.mutex.Lock()
+	restored, took := c.restored, c.took
+	c.mutex.Unlock()
+
+	value := 0.0
+	if restored != nil {
+		value = 1
+	}
+	writeMetricHeader(w, "nautilus_restart_checkpoint_restored", "gauge", "Whether this start restored a restart checkpoint instead of rebuilding from chain")
+	writeMetric(w, "nautilus_restart_checkpoint_restored", nil, value)
+	writeMetricHeader(w, "nautilus_restart_startup_sync_seconds", "gauge", "Time spent restoring the checkpoint or rebuilding the worker pool at start")
+	writeMetric(w, "nautilus_restart_startup_sync_seconds", nil, took.Seconds())
+}
+
+// resumeFrom - 체크포인트의 패키지별 위치를 구독 재개 위치와 처리 위치로 설정
+func (s *SuiIntegration) resumeFrom(positions map[string]chain.EventID) {
+	s.positionMutex.Lock()
+	defer s.positionMutex.Unlock()
+	s.resumeAfter = make(map[string]chain.EventID, len(positions))
+	s.processed = make(map[string]chain.EventID, len(positions))
+	for packageID, position := range positions {
+		s.resumeAfter[packageID] = position
+		s.processed[packageID] = position
+	}
+}
+
+// resumePosition - 패키지 구독을 재개할 위치 (체크포인트가 없으면 nil, 백엔드가 최신부터 시작)
+func (s *SuiIntegration) resumePosition(packageID string) *chain.EventID {
+	s.positionMutex.Lock()
+	defer s.positionMutex.Unlock()
+	position, ok := s.resumeAfter[packageID]
+	if !ok {
+		return nil
+	}
+	return &position
+}
+
+// markProcessed - 처리를 마친 이벤트 위치 기록 (재시작 시 이 다음부터 다시 받으므로 최소 한 번 처리)
+func (s *SuiIntegration) markProcessed(event *SuiContractEvent) {
+	if event.TxDigest == "" || event.PackageID == "" {
+		return
+	}
+	s.positionMutex.Lock()
+	defer s.positionMutex.Unlock()
+	if s.processed == nil {
+		s.processed = make(map[string]chain.EventID)
+	}
+	s.processed[event.PackageID] = chain.EventID{TxDigest: event.TxDigest, Seq: event.EventSeq}
+}
+
+// eventPositions - 패키지별 마지막 처리 이벤트 복사본
+func (s *SuiIntegration) eventPositions() map[string]chain.EventID {
+	s.positionMutex.Lock()
+	defer s.positionMutex.Unlock()
+	positions := make(map[string]chain.EventID, len(s.processed))
+	for packageID, position := range s.processed {
+		positions[packageID] = position
+	}
+	return positions
+}
@@ -0,0 +1,703 @@
+// Schedule Simulator - 워크로드가 현재 클러스터에 배치될 수 있는지와 부족한 용량/스테이킹 계산 (상태 변경 없음)
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+스테이킹을 늘리기 전에 테넌트가 워크로드를 미리 배치해 볼 수 있도록 kube-scheduler의 필터 단계를 흉내냅니다.
+
+	POST /api/v1/schedule/simulate   {"pods": [Pod/Deployment/StatefulSet/ReplicaSet/Job 매니페스트...]}
+
+  - 필터: cordon/NotReady 노드, 활성 상태가 아닌 워커, nodeSelector, required nodeAffinity,
+    NoSchedule/NoExecute taint, cpu/memory/pods 할당 가능량 (실행 중인 Pod 요청량 차감)
+  - k3s-daas.io/* 위치 어노테이션은 실제 생성 때와 같이 nodeAffinity/분산 제약으로 변환 후 평가
+  - 분산 제약(DoNotSchedule)은 같은 요청 안의 복제본끼리만 계산 (기존 Pod는 고려하지 않음)
+  - 점수: 요청 후 남는 CPU 비율이 가장 큰 노드 (LeastAllocated)
+
+배치되지 못한 Pod는 역할별 기준 노드 크기로 채워 넣어 필요한 노드 수와 stake_tiers 기준 추가 스테이킹을 계산합니다.
+*/
+
+const (
+	scheduleSimStateTTL       = 15 * time.Second // kubectl 조회 결과 재사용 기간 (반복 시뮬레이션 부하 방지)
+	scheduleSimMaxPods        = 500
+	scheduleSimMaxBodyBytes   = 1 << 20
+	scheduleSimDefaultCPU     = 2000    // 기준 노드를 정할 수 없을 때 (millicore)
+	scheduleSimDefaultMemory  = 4 << 30 // 바이트
+	scheduleSimDefaultMaxPods = 110
+)
+
+// simNode - 시뮬레이션용 노드 (남은 할당 가능량)
+type simNode struct {
+	name        string
+	labels      map[string]string
+	taints      []simTaint
+	blocked     string // 배치 불가 사유 (cordon, NotReady, 워커 상태)
+	allocCPU    int64
+	allocMemory int64
+	freeCPU     int64
+	freeMemory  int64
+	freePods    int64
+}
+
+type simTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+type simToleration struct {
+	Key      string `json:"key"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+	Effect   string `json:"effect"`
+}
+
+type simRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+type simContainer struct {
+	Resources struct {
+		Requests map[string]string `json:"requests"`
+	} `json:"resources"`
+}
+
+// simPodSpec - 시뮬레이션에 필요한 Pod spec 필드
+type simPodSpec struct {
+	Containers     []simContainer    `json:"containers"`
+	InitContainers []simContainer    `json:"initContainers"`
+	NodeSelector   map[string]string `json:"nodeSelector"`
+	Tolerations    []simToleration   `json:"tolerations"`
+	Affinity       struct {
+		NodeAffinity struct {
+			Required struct {
+				Terms []struct {
+					MatchExpressions []simRequirement `json:"matchExpressions"`
+				} `json:"nodeSelectorTerms"`
+			} `json:"requiredDuringSchedulingIgnoredDuringExecution"`
+		} `json:"nodeAffinity"`
+	} `json:"affinity"`
+	TopologySpread []struct {
+		MaxSkew           int    `json:"maxSkew"`
+		TopologyKey       string `json:"topologyKey"`
+		WhenUnsatisfiable string `json:"whenUnsatisfiable"`
+	} `json:"topologySpreadConstraints"`
+}
+
+// SimulatedPlacement - 배치 결과 (Node가 비어 있으면 Reason이 스케줄 불가 사유)
+type SimulatedPlacement struct {
+	Workload  string `json:"workload"`
+	Pod       string `json:"pod"`
+	Node      string `json:"node,omitempty"`
+	CPUMillis int64  `json:"cpu_millis"`
+	MemoryMB  int64  `json:"memory_mb"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// AdditionalCapacity - 스케줄 불가 Pod를 받기 위해 추가로 필요한 역할별 노드와 스테이킹
+type AdditionalCapacity struct {
+	Role          string   `json:"role"`
+	Nodes         int      `json:"nodes"`
+	NodeCPUMillis int64    `json:"node_cpu_millis"` // 계산에 쓴 기준 노드 크기
+	NodeMemoryMB  int64    `json:"node_memory_mb"`
+	CPUMillis     int64    `json:"cpu_millis"`
+	MemoryMB      int64    `json:"memory_mb"`
+	StakePerNode  uint64   `json:"stake_per_node_mist"`
+	StakeMist     uint64   `json:"stake_mist"`
+	Oversized     []string `json:"oversized,omitempty"` // 기준 노드보다 큰 노드가 필요한 Pod
+}
+
+// ScheduleSimulation - 시뮬레이션 결과
+type ScheduleSimulation struct {
+	Scheduled       []SimulatedPlacement `json:"scheduled"`
+	Unschedulable   []SimulatedPlacement `json:"unschedulable"`
+	Additional      []AdditionalCapacity `json:"additional_capacity"`
+	AdditionalStake uint64               `json:"additional_stake_mist"`
+	Nodes           int                  `json:"nodes_considered"`
+	StateAge        float64              `json:"state_age_seconds"`
+	SimulatedAt     time.Time            `json:"simulated_at"`
+}
+
+// simPod - 매니페스트에서 펼친 복제본 하나
+type simPod struct {
+	workload string
+	name     string
+	role     string
+	spec     simPodSpec
+	cpu      int64
+	memory   int64
+}
+
+// ScheduleSimulator - 클러스터 상태 스냅샷 위에서 배치 시뮬레이션
+type ScheduleSimulator struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	workerPool *WorkerPool
+
+	mutex     sync.Mutex
+	nodes     []simNode
+	fetchedAt time.Time
+}
+
+// NewScheduleSimulator - 새 스케줄 시뮬레이터 생성
+func NewScheduleSimulator(logger *logrus.Logger, k3sMgr *K3sManager) *ScheduleSimulator {
+	return &ScheduleSimulator{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		workerPool: k3sMgr.workerPool,
+	}
+}
+
+// snapshot - 노드 상태 복사본 (TTL 안에서는 캐시 사용)
+func (s *ScheduleSimulator) snapshot() ([]simNode, time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.nodes == nil || time.Since(s.fetchedAt) > scheduleSimStateTTL {
+		nodes, err := s.collectNodes()
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		s.nodes, s.fetchedAt = nodes, time.Now()
+	}
+
+	nodes := make([]simNode, len(s.nodes))
+	copy(nodes, s.nodes)
+	return nodes, s.fetchedAt, nil
+}
+
+// collectNodes - 노드 할당 가능량에서 종료되지 않은 Pod의 요청량을 뺀 잔여 용량
+func (s *ScheduleSimulator) collectNodes() ([]simNode, error) {
+	output, err := s.k3sMgr.RunKubectl(nil, "get", "nodes", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var nodeList struct {
+		Items []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Unschedulable bool       `json:"unschedulable"`
+				Taints        []simTaint `json:"taints"`
+			} `json:"spec"`
+			Status struct {
+				Allocatable map[string]string `json:"allocatable"`
+				Conditions  []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &nodeList); err != nil {
+		return nil, fmt.Errorf("failed to parse node list: %v", err)
+	}
+
+	output, err = s.k3sMgr.RunKubectl(nil, "get", "pods", "--all-namespaces",
+		"--field-selector=status.phase!=Succeeded,status.phase!=Failed", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var podList struct {
+		Items []struct {
+			Spec struct {
+				NodeName string `json:"nodeName"`
+				simPodSpec
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &podList); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %v", err)
+	}
+	used := make(map[string][3]int64) // 노드 → cpu, memory, pods
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		cpu, memory := podRequests(pod.Spec.simPodSpec)
+		total := used[pod.Spec.NodeName]
+		used[pod.Spec.NodeName] = [3]int64{total[0] + cpu, total[1] + memory, total[2] + 1}
+	}
+
+	nodes := make([]simNode, 0, len(nodeList.Items))
+	for _, item := range nodeList.Items {
+		cpu, _ := parseCPUMillis(item.Status.Allocatable["cpu"])
+		memory, _ := parseMemoryBytes(item.Status.Allocatable["memory"])
+		pods, err := strconv.ParseInt(item.Status.Allocatable["pods"], 10, 64)
+		if err != nil {
+			pods = scheduleSimDefaultMaxPods
+		}
+		node := simNode{
+			name:        item.Metadata.Name,
+			labels:      item.Metadata.Labels,
+			taints:      item.Spec.Taints,
+			allocCPU:    cpu,
+			allocMemory: memory,
+			freeCPU:     cpu - used[item.Metadata.Name][0],
+			freeMemory:  memory - used[item.Metadata.Name][1],
+			freePods:    pods - used[item.Metadata.Name][2],
+		}
+
+		ready := false
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == "Ready" && condition.Status == "True" {
+				ready = true
+			}
+		}
+		switch {
+		case item.Spec.Unschedulable:
+			node.blocked = "node(s) were unschedulable"
+		case !ready:
+			node.blocked = "node(s) were not ready"
+		}
+		if worker, ok := s.workerPool.GetWorker(item.Metadata.Name); ok && worker.Status != "active" && node.blocked == "" {
+			node.blocked = "worker(s) were not active"
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// podRequests - 유효 요청량 (컨테이너 합과 가장 큰 init 컨테이너 중 큰 값)
+func podRequests(spec simPodSpec) (int64, int64) {
+	var cpu, memory int64
+	for _, container := range spec.Containers {
+		c, _ := parseCPUMillis(container.Resources.Requests["cpu"])
+		m, _ := parseMemoryBytes(container.Resources.Requests["memory"])
+		cpu += c
+		memory += m
+	}
+	for _, container := range spec.InitContainers {
+		c, _ := parseCPUMillis(container.Resources.Requests["cpu"])
+		m, _ := parseMemoryBytes(container.Resources.Requests["memory"])
+		if c > cpu {
+			cpu = c
+		}
+		if m > memory {
+			memory = m
+		}
+	}
+	return cpu, memory
+}
+
+// expandPods - 매니페스트를 복제본 단위 Pod로 펼침 (위치 어노테이션은 실제 생성 경로와 같이 변환)
+func expandPods(manifests []map[string]interface{}) ([]simPod, error) {
+	var pods []simPod
+	for i, obj := range manifests {
+		podSpec, podMeta := podTemplateOf(obj)
+		if podSpec == nil {
+			return nil, fmt.Errorf("pods[%d]: kind %v has no pod template", i, obj["kind"])
+		}
+		if podMeta == nil {
+			podMeta = map[string]interface{}{}
+		}
+		annotations := mergedAnnotations(obj, podMeta)
+		if _, err := applyTopologyConstraints(podSpec, podMeta, annotations); err != nil {
+			return nil, fmt.Errorf("pods[%d]: %v", i, err)
+		}
+
+		raw, err := json.Marshal(podSpec)
+		if err != nil {
+			return nil, fmt.Errorf("pods[%d]: %v", i, err)
+		}
+		var spec simPodSpec
+		if err := json.Unmarshal(raw, &spec); err != nil {
+			return nil, fmt.Errorf("pods[%d]: invalid pod spec: %v", i, err)
+		}
+		for _, container := range append(spec.Containers, spec.InitContainers...) {
+			_, cpuErr := parseCPUMillis(container.Resources.Requests["cpu"])
+			_, memoryErr := parseMemoryBytes(container.Resources.Requests["memory"])
+			for _, err := range []error{cpuErr, memoryErr} {
+				if err != nil {
+					return nil, fmt.Errorf("pods[%d]: %v", i, err)
+				}
+			}
+		}
+
+		meta := objectMeta(obj)
+		name, _ := meta["name"].(string)
+		if name == "" {
+			name = fmt.Sprintf("pod-%d", i)
+		}
+		namespace, _ := meta["namespace"].(string)
+		if namespace == "" {
+			namespace = "default"
+		}
+		replicas := 1
+		if kind, _ := obj["kind"].(string); kind != "Pod" {
+			if value, ok := obj["spec"].(map[string]interface{})["replicas"].(float64); ok {
+				replicas = int(value)
+			}
+		}
+		if replicas < 0 || len(pods)+replicas > scheduleSimMaxPods {
+			return nil, fmt.Errorf("at most %d pods can be simulated per request", scheduleSimMaxPods)
+		}
+
+		cpu, memory := podRequests(spec)
+		role := podRole(spec)
+		for r := 0; r < replicas; r++ {
+			podName := name
+			if kind, _ := obj["kind"].(string); kind != "Pod" {
+				podName = fmt.Sprintf("%s-%d", name, r)
+			}
+			pods = append(pods, simPod{workload: namespace + "/" + name, name: podName, role: role, spec: spec, cpu: cpu, memory: memory})
+		}
+	}
+	return pods, nil
+}
+
+// podRole - nodeSelector 또는 nodeAffinity가 요구하는 노드 역할 (없으면 worker)
+func podRole(spec simPodSpec) string {
+	if role := spec.NodeSelector[nodeRoleLabel]; role != "" {
+		return role
+	}
+	for _, term := range spec.Affinity.NodeAffinity.Required.Terms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == nodeRoleLabel && expression.Operator == "In" && len(expression.Values) > 0 {
+				return expression.Values[0]
+			}
+		}
+	}
+	return NodeRoleWorker
+}
+
+// filterReason - 노드가 Pod를 받을 수 없는 사유 (kube-scheduler 메시지 형식, 받을 수 있으면 빈 문자열)
+func filterReason(node *simNode, pod *simPod) string {
+	if node.blocked != "" {
+		return node.blocked
+	}
+	if !selectsNode(node, pod) {
+		return "node(s) didn't match Pod's node affinity/selector"
+	}
+	for _, taint := range node.taints {
+		if (taint.Effect == "NoSchedule" || taint.Effect == "NoExecute") && !tolerates(pod.spec.Tolerations, taint) {
+			return fmt.Sprintf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value)
+		}
+	}
+	switch {
+	case node.freePods < 1:
+		return "Too many pods"
+	case pod.cpu > node.freeCPU:
+		return "Insufficient cpu"
+	case pod.memory > node.freeMemory:
+		return "Insufficient memory"
+	}
+	return ""
+}
+
+// selectsNode - nodeSelector와 required nodeAffinity 충족 여부 (term 중 하나만 맞으면 됨)
+func selectsNode(node *simNode, pod *simPod) bool {
+	for key, value := range pod.spec.NodeSelector {
+		if node.labels[key] != value {
+			return false
+		}
+	}
+	terms := pod.spec.Affinity.NodeAffinity.Required.Terms
+	for _, term := range terms {
+		if matchesRequirements(node.labels, term.MatchExpressions) {
+			return true
+		}
+	}
+	return len(terms) == 0
+}
+
+// matchesRequirements - nodeSelectorTerm의 matchExpressions 평가 (모두 충족해야 함)
+func matchesRequirements(labels map[string]string, requirements []simRequirement) bool {
+	for _, requirement := range requirements {
+		value, exists := labels[requirement.Key]
+		switch requirement.Operator {
+		case "In":
+			if !exists || !containsString(requirement.Values, value) {
+				return false
+			}
+		case "NotIn":
+			if exists && containsString(requirement.Values, value) {
+				return false
+			}
+		case "Exists":
+			if !exists {
+				return false
+			}
+		case "DoesNotExist":
+			if exists {
+				return false
+			}
+		case "Gt", "Lt":
+			actual, err1 := strconv.ParseInt(value, 10, 64)
+			if !exists || len(requirement.Values) != 1 || err1 != nil {
+				return false
+			}
+			limit, err2 := strconv.ParseInt(requirement.Values[0], 10, 64)
+			if err2 != nil || (requirement.Operator == "Gt" && actual <= limit) || (requirement.Operator == "Lt" && actual >= limit) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// tolerates - taint를 허용하는 toleration이 있는지
+func tolerates(tolerations []simToleration, taint simTaint) bool {
+	for _, toleration := range tolerations {
+		if toleration.Effect != "" && toleration.Effect != taint.Effect {
+			continue
+		}
+		if toleration.Key == "" && toleration.Operator == "Exists" {
+			return true
+		}
+		if toleration.Key != taint.Key {
+			continue
+		}
+		if toleration.Operator == "Exists" || toleration.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// Simulate - 노드 스냅샷 복사본 위에 순서대로 배치 (클러스터 상태는 바꾸지 않음)
+func (s *ScheduleSimulator) Simulate(pods []simPod) (*ScheduleSimulation, error) {
+	nodes, fetchedAt, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScheduleSimulation{
+		Scheduled:     []SimulatedPlacement{},
+		Unschedulable: []SimulatedPlacement{},
+		Additional:    []AdditionalCapacity{},
+		Nodes:         len(nodes),
+		StateAge:      time.Since(fetchedAt).Seconds(),
+		SimulatedAt:   time.Now(),
+	}
+	spread := make(map[string]map[string]int) // 워크로드|토폴로지 키 → 도메인 → 배치한 복제본 수
+	var pending []simPod
+
+	for i := range pods {
+		pod := &pods[i]
+		placement := SimulatedPlacement{Workload: pod.workload, Pod: pod.name, CPUMillis: pod.cpu, MemoryMB: pod.memory >> 20}
+
+		reasons := make(map[string]int)
+		var best *simNode
+		var bestScore float64
+		for n := range nodes {
+			node := &nodes[n]
+			reason := filterReason(node, pod)
+			if reason == "" {
+				reason = spreadReason(spread, nodes, node, pod)
+			}
+			if reason != "" {
+				reasons[reason]++
+				continue
+			}
+			score := float64(node.freeCPU-pod.cpu) / float64(maxInt64(node.allocCPU, 1))
+			if best == nil || score > bestScore {
+				best, bestScore = node, score
+			}
+		}
+
+		if best == nil {
+			placement.Reason = unschedulableMessage(len(nodes), reasons)
+			result.Unschedulable = append(result.Unschedulable, placement)
+			pending = append(pending, *pod)
+			continue
+		}
+		best.freeCPU -= pod.cpu
+		best.freeMemory -= pod.memory
+		best.freePods--
+		for _, constraint := range pod.spec.TopologySpread {
+			key := pod.workload + "|" + constraint.TopologyKey
+			if spread[key] == nil {
+				spread[key] = make(map[string]int)
+			}
+			spread[key][best.labels[constraint.TopologyKey]]++
+		}
+		placement.Node = best.name
+		result.Scheduled = append(result.Scheduled, placement)
+	}
+
+	result.Additional = additionalCapacity(nodes, pending)
+	for _, capacity := range result.Additional {
+		result.AdditionalStake += capacity.StakeMist
+	}
+	return result, nil
+}
+
+// spreadReason - DoNotSchedule 분산 제약 위반 여부 (같은 요청의 복제본 기준)
+func spreadReason(spread map[string]map[string]int, nodes []simNode, node *simNode, pod *simPod) string {
+	for _, constraint := range pod.spec.TopologySpread {
+		if constraint.WhenUnsatisfiable != "DoNotSchedule" {
+			continue
+		}
+		domain, ok := node.labels[constraint.TopologyKey]
+		if !ok {
+			return "node(s) didn't match pod topology spread constraints (missing required label)"
+		}
+		counts := spread[pod.workload+"|"+constraint.TopologyKey]
+		// 노드 선택 조건을 만족하는 도메인 중 최소 배치 수 (kube-scheduler와 같이 자원 여유는 보지 않음)
+		lowest := -1
+		for n := range nodes {
+			candidate, ok := nodes[n].labels[constraint.TopologyKey]
+			if !ok || !selectsNode(&nodes[n], pod) {
+				continue
+			}
+			if lowest < 0 || counts[candidate] < lowest {
+				lowest = counts[candidate]
+			}
+		}
+		maxSkew := constraint.MaxSkew
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+		if counts[domain]+1-lowest > maxSkew {
+			return "node(s) didn't match pod topology spread constraints"
+		}
+	}
+	return ""
+}
+
+// unschedulableMessage - "0/3 nodes are available: 2 Insufficient cpu, 1 ..." 형식
+func unschedulableMessage(total int, reasons map[string]int) string {
+	if total == 0 {
+		return "no nodes available to schedule pods"
+	}
+	parts := make([]string, 0, len(reasons))
+	for reason, count := range reasons {
+		parts = append(parts, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("0/%d nodes are available: %s.", total, strings.Join(parts, ", "))
+}
+
+/*
+additionalCapacity - 배치되지 못한 Pod를 역할별 기준 노드에 first-fit decreasing으로 채워 필요한 노드 수 계산
+기준 노드 크기는 같은 역할 노드의 평균 할당 가능량(없으면 전체 노드 평균, 노드가 없으면 기본값)입니다.
+*/
+func additionalCapacity(nodes []simNode, pending []simPod) []AdditionalCapacity {
+	byRole := make(map[string][]simPod)
+	for _, pod := range pending {
+		byRole[pod.role] = append(byRole[pod.role], pod)
+	}
+
+	result := make([]AdditionalCapacity, 0, len(byRole))
+	for role, pods := range byRole {
+		nodeCPU, nodeMemory := referenceNodeSize(nodes, role)
+		capacity := AdditionalCapacity{Role: role, NodeCPUMillis: nodeCPU, NodeMemoryMB: nodeMemory >> 20}
+
+		sort.SliceStable(pods, func(i, j int) bool { return pods[i].cpu > pods[j].cpu })
+		var bins [][2]int64 // 새 노드별 남은 cpu, memory
+		var memory int64
+		for _, pod := range pods {
+			capacity.CPUMillis += pod.cpu
+			memory += pod.memory
+			if pod.cpu > nodeCPU || pod.memory > nodeMemory {
+				// 기준보다 큰 노드 하나를 따로 둔다고 보고 계산 (Oversized로 알림)
+				capacity.Oversized = append(capacity.Oversized, pod.workload+"/"+pod.name)
+				bins = append(bins, [2]int64{0, 0})
+				continue
+			}
+			placed := false
+			for b := range bins {
+				if bins[b][0] >= pod.cpu && bins[b][1] >= pod.memory {
+					bins[b][0] -= pod.cpu
+					bins[b][1] -= pod.memory
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				bins = append(bins, [2]int64{nodeCPU - pod.cpu, nodeMemory - pod.memory})
+			}
+		}
+		capacity.MemoryMB = memory >> 20
+		capacity.Nodes = len(bins)
+		if stake, err := requiredStakeForRole(role); err == nil {
+			capacity.StakePerNode = stake
+			capacity.StakeMist = stake * uint64(capacity.Nodes)
+		}
+		result = append(result, capacity)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Role < result[j].Role })
+	return result
+}
+
+// referenceNodeSize - 역할별 평균 할당 가능량 (cpu millicore, memory 바이트)
+func referenceNodeSize(nodes []simNode, role string) (int64, int64) {
+	for _, matchRole := range []bool{true, false} {
+		var cpu, memory, count int64
+		for _, node := range nodes {
+			if matchRole && node.labels[nodeRoleLabel] != role {
+				continue
+			}
+			cpu += node.allocCPU
+			memory += node.allocMemory
+			count++
+		}
+		if count > 0 && cpu > 0 {
+			return cpu / count, memory / count
+		}
+	}
+	return scheduleSimDefaultCPU, scheduleSimDefaultMemory
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// handleSimulate - POST /api/v1/schedule/simulate
+func (s *ScheduleSimulator) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Pods []map[string]interface{} `json:"pods"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, scheduleSimMaxBodyBytes)).Decode(&body); err != nil {
+		http.Error(w, "Invalid simulation request", http.StatusBadRequest)
+		return
+	}
+	if len(body.Pods) == 0 {
+		http.Error(w, "pods is required", http.StatusBadRequest)
+		return
+	}
+	pods, err := expandPods(body.Pods)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if s.k3sMgr == nil || !s.k3sMgr.IsRunning() {
+		http.Error(w, "K3s control plane is not running", http.StatusServiceUnavailable)
+		return
+	}
+	result, err := s.Simulate(pods)
+	if err != nil {
+		s.logger.Warnf("⚠️ Schedule simulation failed: %v", err)
+		http.Error(w, "Failed to read cluster state: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	s.logger.Debugf("🧮 Simulated %d pods: %d scheduled, %d unschedulable", len(pods), len(result.Scheduled), len(result.Unschedulable))
+	writeClientJSON(w, result)
+}
@@ -0,0 +1,940 @@
+// Scheduler Extender - kube-scheduler 익스텐더로 등록하여 필터/점수/바인드 단계에서 스케줄러 플러그인과 테넌트 웹훅(가격 기반 배치 등) 호출
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SchedulerExtenders 기능 게이트를 켜면 K3s 시작 전에 KubeSchedulerConfiguration을 써서 마스터를
+kube-scheduler 익스텐더(/scheduler/extender/filter, /prioritize, /bind)로 등록하고, 스케줄링마다 플러그인 파이프라인
+(scheduler_plugins.go)을 실행합니다. 등록된 웹훅은 ExtenderWebhooks 플러그인이 병렬로 호출합니다.
+
+웹훅 프로토콜 (POST, JSON):
+
+	<url>/filter      {"pod": <v1.Pod>, "nodes": [ExtenderNode...]}  →  {"failed_nodes": {"<node>": "<사유>"}, "error": ""}
+	<url>/prioritize  {"pod": <v1.Pod>, "nodes": [ExtenderNode...]}  →  {"scores": {"<node>": 0-10}}
+
+  - 대상: namespaces에 속한 Pod (비어 있으면 전체), 시스템 critical 우선순위 Pod는 호출하지 않음
+  - timeout_ms 안에 응답하지 않거나 오류(2xx 아님, error 필드)면 failure_policy 적용
+    ignore(fail-open)  웹훅이 없는 것처럼 진행
+    fail(fail-closed)  필터 단계에서 모든 후보 노드를 탈락시킴 (Pod는 Pending으로 남고 스케줄러가 재시도)
+    점수 단계의 실패는 정책과 관계없이 해당 웹훅 점수만 제외
+  - 점수는 0~10으로 잘라 weight로 가중 평균한 값이 ExtenderWebhooks 플러그인의 점수가 됨
+
+	NAUTILUS_SCHEDULER_EXTENDER_URL          kube-scheduler가 호출할 마스터 주소 (기본 NAUTILUS_LISTEN_ADDR의 127.0.0.1)
+	NAUTILUS_SCHEDULER_EXTENDER_MAX_TIMEOUT  웹훅 timeout_ms 상한 (초, 기본 5, kube-scheduler httpTimeout은 +1초)
+*/
+
+const (
+	extenderPolicyIgnore = "ignore"
+	extenderPolicyFail   = "fail"
+
+	maxExtenderPriority    = 10 // k8s.io/kube-scheduler/extender/v1.MaxExtenderPriority
+	defaultExtenderTimeout = time.Second
+	extenderMaxBodyBytes   = 4 << 20
+	schedulerConfigFile    = "daas-scheduler-config.yaml"
+)
+
+var extenderNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// ExtenderWebhook - 스케줄링에 참여하는 외부 웹훅
+type ExtenderWebhook struct {
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	Namespaces    []string  `json:"namespaces,omitempty"`
+	Filter        bool      `json:"filter"`
+	Prioritize    bool      `json:"prioritize"`
+	Weight        int       `json:"weight"`
+	TimeoutMs     int       `json:"timeout_ms"`
+	FailurePolicy string    `json:"failure_policy"`
+	Token         string    `json:"token,omitempty"` // 웹훅 요청의 Authorization: Bearer
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// appliesTo - 네임스페이스 대상 여부
+func (h *ExtenderWebhook) appliesTo(namespace string) bool {
+	return len(h.Namespaces) == 0 || containsString(h.Namespaces, namespace)
+}
+
+// redacted - 조회 응답용 (토큰 제외)
+func (h ExtenderWebhook) redacted() ExtenderWebhook {
+	if h.Token != "" {
+		h.Token = "redacted"
+	}
+	return h
+}
+
+// ExtenderNode - 웹훅에 넘기는 후보 노드 (워커 노드는 스테이킹/위치/수집기 정보 포함)
+type ExtenderNode struct {
+	Name        string                     `json:"name"`
+	Role        string                     `json:"role,omitempty"`
+	Status      string                     `json:"status,omitempty"`
+	Owner       string                     `json:"owner,omitempty"`
+	Region      string                     `json:"region,omitempty"`
+	Zone        string                     `json:"zone,omitempty"`
+	LatencyMs   int64                      `json:"latency_ms,omitempty"`
+	StakeAmount uint64                     `json:"stake_amount,omitempty"`
+	StakeTier   string                     `json:"stake_tier,omitempty"`
+	Telemetry   map[string]json.RawMessage `json:"telemetry,omitempty"`
+}
+
+// extenderArgs - kube-scheduler ExtenderArgs (nodeCacheCapable이므로 NodeNames만 옴)
+type extenderArgs struct {
+	Pod       json.RawMessage `json:"Pod"`
+	NodeNames *[]string       `json:"NodeNames"`
+}
+
+// extenderFilterResult - kube-scheduler ExtenderFilterResult
+type extenderFilterResult struct {
+	NodeNames   *[]string         `json:"NodeNames"`
+	FailedNodes map[string]string `json:"FailedNodes"`
+	Error       string            `json:"Error"`
+}
+
+// extenderHostPriority - kube-scheduler HostPriority
+type extenderHostPriority struct {
+	Host  string `json:"Host"`
+	Score int64  `json:"Score"`
+}
+
+// extenderBindingArgs - kube-scheduler ExtenderBindingArgs
+type extenderBindingArgs struct {
+	PodName      string `json:"PodName"`
+	PodNamespace string `json:"PodNamespace"`
+	PodUID       string `json:"PodUID"`
+	Node         string `json:"Node"`
+}
+
+// extenderBindingResult - kube-scheduler ExtenderBindingResult
+type extenderBindingResult struct {
+	Error string `json:"Error"`
+}
+
+// extenderPod - 대상 판단에 필요한 Pod 필드
+type extenderPod struct {
+	Metadata struct {
+		Namespace string            `json:"namespace"`
+		Name      string            `json:"name"`
+		Labels    map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		Priority *int64 `json:"priority"`
+	} `json:"spec"`
+}
+
+// SchedulerExtender - 웹훅 저장소, 플러그인 파이프라인과 kube-scheduler 익스텐더 엔드포인트
+type SchedulerExtender struct {
+	logger      *logrus.Logger
+	workerPool  *WorkerPool
+	adminToken  string
+	client      *http.Client
+	selfURL     string
+	maxTimeout  time.Duration
+	stateFile   string
+	pluginsFile string
+	handle      *SchedulerPluginHandle
+	bindVerb    bool // kube-scheduler 설정에 bindVerb를 등록했는지 (시작 시 결정)
+
+	mutex        sync.RWMutex
+	webhooks     map[string]*ExtenderWebhook
+	pipeline     *schedulerPipeline
+	calls        map[string]uint64 // <name>/<verb>/<outcome>
+	rejected     map[string]uint64 // 웹훅이 탈락시킨 노드 수
+	pluginErrors map[string]uint64 // <plugin>/<phase>
+}
+
+// NewSchedulerExtender - 저장된 웹훅과 플러그인 설정을 복원하여 생성
+func NewSchedulerExtender(logger *logrus.Logger, k3sMgr *K3sManager, simulator *ScheduleSimulator) (*SchedulerExtender, error) {
+	maxTimeout := envSeconds("NAUTILUS_SCHEDULER_EXTENDER_MAX_TIMEOUT", 5)
+	if maxTimeout < defaultExtenderTimeout {
+		maxTimeout = defaultExtenderTimeout
+	}
+	selfURL := os.Getenv("NAUTILUS_SCHEDULER_EXTENDER_URL")
+	if selfURL == "" {
+		config := httpserver.ConfigFromEnv("NAUTILUS", ":8080")
+		_, port, err := net.SplitHostPort(config.Addr)
+		if err != nil {
+			port = "8080"
+		}
+		selfURL = config.Scheme() + "://127.0.0.1:" + port
+	}
+
+	e := &SchedulerExtender{
+		logger:       logger,
+		workerPool:   k3sMgr.workerPool,
+		adminToken:   os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		client:       &http.Client{},
+		selfURL:      strings.TrimSuffix(selfURL, "/") + "/scheduler/extender",
+		maxTimeout:   maxTimeout,
+		stateFile:    statePath("scheduler-extenders.json"),
+		pluginsFile:  statePath("scheduler-plugins.json"),
+		webhooks:     make(map[string]*ExtenderWebhook),
+		calls:        make(map[string]uint64),
+		rejected:     make(map[string]uint64),
+		pluginErrors: make(map[string]uint64),
+	}
+	e.handle = &SchedulerPluginHandle{Extender: e, K3s: k3sMgr, WorkerPool: k3sMgr.workerPool, Simulator: simulator}
+
+	var webhooks []*ExtenderWebhook
+	if found, err := loadJSONState(e.stateFile, &webhooks); err != nil {
+		logger.Warnf("⚠️ Failed to load scheduler extender webhooks: %v", err)
+	} else if found {
+		for _, webhook := range webhooks {
+			e.webhooks[webhook.Name] = webhook
+		}
+		logger.Infof("🧭 Loaded %d scheduler extender webhooks", len(webhooks))
+	}
+
+	configs, err := defaultSchedulerPluginConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var saved []SchedulerPluginConfig
+	if found, err := loadJSONState(e.pluginsFile, &saved); err != nil {
+		logger.Warnf("⚠️ Failed to load scheduler plugin config, using defaults: %v", err)
+	} else if found {
+		// 저장 후 없어진 플러그인 등으로 설정이 맞지 않으면 기본값으로 시작
+		if e.pipeline, err = buildSchedulerPipeline(e.handle, withNewPlugins(saved, configs)); err != nil {
+			logger.Warnf("⚠️ Saved scheduler plugin config is invalid, using defaults: %v", err)
+		}
+	}
+	if e.pipeline == nil {
+		if e.pipeline, err = buildSchedulerPipeline(e.handle, configs); err != nil {
+			return nil, err
+		}
+	}
+	e.bindVerb = e.pipeline.customBind()
+	return e, nil
+}
+
+// withNewPlugins - 저장된 설정 뒤에 그 뒤로 등록된 플러그인의 기본 설정을 덧붙임
+func withNewPlugins(saved, defaults []SchedulerPluginConfig) []SchedulerPluginConfig {
+	configs := append([]SchedulerPluginConfig(nil), saved...)
+	for _, config := range defaults {
+		found := false
+		for _, existing := range saved {
+			found = found || existing.Name == config.Name
+		}
+		if !found {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+/*
+InstallForK3s - kube-scheduler 설정 파일 작성 후 경로 반환 (K3s 시작 인자 --kube-scheduler-arg config=)
+--config를 쓰면 kube-scheduler가 --kubeconfig 플래그를 무시하므로 K3s가 만드는 스케줄러 kubeconfig를 직접 지정합니다.
+마스터가 응답하지 않아도 스케줄링이 멈추지 않도록 ignorable로 등록합니다 (fail-closed는 웹훅 단위로 적용).
+*/
+func (e *SchedulerExtender) InstallForK3s(dataDir string) (string, error) {
+	extender := map[string]interface{}{
+		"urlPrefix":        e.selfURL,
+		"filterVerb":       "filter",
+		"prioritizeVerb":   "prioritize",
+		"weight":           1,
+		"nodeCacheCapable": true,
+		"httpTimeout":      (e.maxTimeout + time.Second).String(),
+		"ignorable":        true,
+	}
+	if e.bindVerb {
+		extender["bindVerb"] = "bind"
+	}
+	if strings.HasPrefix(e.selfURL, "https://") {
+		extender["enableHTTPS"] = true
+		extender["tlsConfig"] = map[string]interface{}{"insecure": true} // 루프백의 자체 서명 인증서
+	}
+	config := map[string]interface{}{
+		"apiVersion":       "kubescheduler.config.k8s.io/v1",
+		"kind":             "KubeSchedulerConfiguration",
+		"clientConnection": map[string]interface{}{"kubeconfig": filepath.Join(dataDir, "server", "cred", "scheduler.kubeconfig")},
+		"extenders":        []interface{}{extender},
+	}
+
+	// JSON은 YAML이기도 하므로 그대로 기록
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dataDir, "server", schedulerConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create K3s server directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write scheduler config: %v", err)
+	}
+	e.logger.Infof("🧭 Registered %s as kube-scheduler extender (bind: %t)", e.selfURL, e.bindVerb)
+	return path, nil
+}
+
+// validate - 웹훅 설정 검증 및 기본값
+func (e *SchedulerExtender) validate(webhook *ExtenderWebhook) error {
+	if !extenderNamePattern.MatchString(webhook.Name) {
+		return fmt.Errorf("name must be a DNS label")
+	}
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	webhook.URL = strings.TrimSuffix(webhook.URL, "/")
+	if !webhook.Filter && !webhook.Prioritize {
+		return fmt.Errorf("enable filter, prioritize or both")
+	}
+	switch webhook.FailurePolicy {
+	case "":
+		webhook.FailurePolicy = extenderPolicyIgnore
+	case extenderPolicyIgnore, extenderPolicyFail:
+	default:
+		return fmt.Errorf("failure_policy must be ignore or fail")
+	}
+	if webhook.Weight <= 0 {
+		webhook.Weight = 1
+	}
+	if webhook.TimeoutMs <= 0 {
+		webhook.TimeoutMs = int(defaultExtenderTimeout / time.Millisecond)
+	}
+	if limit := int(e.maxTimeout / time.Millisecond); webhook.TimeoutMs > limit {
+		return fmt.Errorf("timeout_ms must not exceed %d (NAUTILUS_SCHEDULER_EXTENDER_MAX_TIMEOUT)", limit)
+	}
+	return nil
+}
+
+// Put - 웹훅 추가/교체 (이름 기준)
+func (e *SchedulerExtender) Put(webhook ExtenderWebhook) (*ExtenderWebhook, error) {
+	if err := e.validate(&webhook); err != nil {
+		return nil, err
+	}
+	webhook.UpdatedAt = time.Now().UTC()
+
+	e.mutex.Lock()
+	e.webhooks[webhook.Name] = &webhook
+	err := e.persistLocked()
+	e.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Infof("🧭 Scheduler extender webhook %s set (%s, failure policy %s)", webhook.Name, webhook.URL, webhook.FailurePolicy)
+	return &webhook, nil
+}
+
+// Remove - 웹훅 삭제
+func (e *SchedulerExtender) Remove(name string) (bool, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if _, ok := e.webhooks[name]; !ok {
+		return false, nil
+	}
+	delete(e.webhooks, name)
+	return true, e.persistLocked()
+}
+
+// List - 이름 순 웹훅 목록 (토큰 제외)
+func (e *SchedulerExtender) List() []ExtenderWebhook {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	webhooks := make([]ExtenderWebhook, 0, len(e.webhooks))
+	for _, webhook := range e.webhooks {
+		webhooks = append(webhooks, webhook.redacted())
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name < webhooks[j].Name })
+	return webhooks
+}
+
+func (e *SchedulerExtender) persistLocked() error {
+	webhooks := make([]*ExtenderWebhook, 0, len(e.webhooks))
+	for _, webhook := range e.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	return saveJSONState(e.stateFile, webhooks)
+}
+
+// matching - Pod에 적용할 웹훅 (verb별)
+func (e *SchedulerExtender) matching(pod extenderPod, verb string) []ExtenderWebhook {
+	if pod.Spec.Priority != nil && *pod.Spec.Priority >= systemCriticalPriority {
+		return nil
+	}
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	var webhooks []ExtenderWebhook
+	for _, webhook := range e.webhooks {
+		if !webhook.appliesTo(pod.Metadata.Namespace) {
+			continue
+		}
+		if (verb == "filter" && webhook.Filter) || (verb == "prioritize" && webhook.Prioritize) {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name < webhooks[j].Name })
+	return webhooks
+}
+
+// candidates - 노드 이름을 플러그인/웹훅용 노드 정보로 변환
+func (e *SchedulerExtender) candidates(names []string) []ExtenderNode {
+	nodes := make([]ExtenderNode, 0, len(names))
+	for _, name := range names {
+		node := ExtenderNode{Name: name}
+		if worker, ok := e.workerPool.GetWorker(name); ok {
+			node.Role = worker.Role
+			node.Status = worker.Status
+			node.Owner = worker.WorkerAddress
+			node.Region = worker.Region
+			node.Zone = worker.Zone
+			node.LatencyMs = worker.LatencyMs
+			node.StakeAmount = worker.StakeAmount
+			node.StakeTier = stakeTierFor(worker.StakeAmount)
+			node.Telemetry = worker.Telemetry
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// call - 웹훅 호출 (timeout_ms 적용, 2xx가 아니면 오류)
+func (e *SchedulerExtender) call(webhook ExtenderWebhook, verb string, payload []byte, result interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL+"/"+verb, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+webhook.Token)
+	}
+	client := *e.client
+	client.Timeout = time.Duration(webhook.TimeoutMs) * time.Millisecond
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, extenderMaxBodyBytes)).Decode(result)
+}
+
+// fanOut - 웹훅 병렬 호출, 웹훅별 오류 반환
+func (e *SchedulerExtender) fanOut(webhooks []ExtenderWebhook, verb string, payload []byte, result func(i int) interface{}) []error {
+	errs := make([]error, len(webhooks))
+	var wg sync.WaitGroup
+	for i := range webhooks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = e.call(webhooks[i], verb, payload, result(i))
+		}(i)
+	}
+	wg.Wait()
+
+	e.mutex.Lock()
+	for i, webhook := range webhooks {
+		outcome := "ok"
+		if errs[i] != nil {
+			outcome = "error"
+			if netErr, ok := errs[i].(net.Error); ok && netErr.Timeout() {
+				outcome = "timeout"
+			}
+		}
+		e.calls[webhook.Name+"/"+verb+"/"+outcome]++
+	}
+	e.mutex.Unlock()
+	return errs
+}
+
+// extenderWebhookPlugin - 등록된 테넌트 웹훅을 필터/점수 단계 플러그인으로 실행
+type extenderWebhookPlugin struct {
+	e *SchedulerExtender
+}
+
+func (extenderWebhookPlugin) Name() string { return "ExtenderWebhooks" }
+
+// nodeNames - 후보 노드 이름
+func nodeNames(nodes []ExtenderNode) []string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.Name
+	}
+	return names
+}
+
+// Filter - 후보 노드 중 웹훅이 탈락시킨 노드와 사유 (실패 정책은 웹훅별로 적용)
+func (p extenderWebhookPlugin) Filter(state *SchedulingState) (map[string]string, error) {
+	e, pod := p.e, state.Pod
+	failed := make(map[string]string)
+	webhooks := e.matching(pod, "filter")
+	if len(webhooks) == 0 {
+		return failed, nil
+	}
+
+	names := nodeNames(state.Nodes)
+	payload, _ := json.Marshal(map[string]interface{}{"pod": state.RawPod, "nodes": state.Nodes})
+	results := make([]struct {
+		FailedNodes map[string]string `json:"failed_nodes"`
+		Error       string            `json:"error"`
+	}, len(webhooks))
+	errs := e.fanOut(webhooks, "filter", payload, func(i int) interface{} { return &results[i] })
+
+	for i, webhook := range webhooks {
+		err := errs[i]
+		if err == nil && results[i].Error != "" {
+			err = fmt.Errorf("%s", results[i].Error)
+		}
+		if err != nil {
+			if webhook.FailurePolicy != extenderPolicyFail {
+				e.logger.Warnf("⚠️ Scheduler extender %s failed for %s/%s, ignoring: %v", webhook.Name, pod.Metadata.Namespace, pod.Metadata.Name, err)
+				continue
+			}
+			e.logger.Warnf("🚫 Scheduler extender %s failed for %s/%s, rejecting all nodes: %v", webhook.Name, pod.Metadata.Namespace, pod.Metadata.Name, err)
+			for _, name := range names {
+				if _, seen := failed[name]; !seen {
+					failed[name] = fmt.Sprintf("scheduler extender %s unavailable", webhook.Name)
+				}
+			}
+			continue
+		}
+
+		count := 0
+		for name, reason := range results[i].FailedNodes {
+			if !containsString(names, name) {
+				continue
+			}
+			if _, seen := failed[name]; !seen {
+				failed[name] = fmt.Sprintf("%s: %s", webhook.Name, reason)
+			}
+			count++
+		}
+		e.mutex.Lock()
+		e.rejected[webhook.Name] += uint64(count)
+		e.mutex.Unlock()
+	}
+	return failed, nil
+}
+
+// Score - 웹훅 점수(0~10)의 웹훅 weight 가중 평균 (응답한 웹훅이 없으면 기권)
+func (p extenderWebhookPlugin) Score(state *SchedulingState) (map[string]int64, error) {
+	e, pod := p.e, state.Pod
+	webhooks := e.matching(pod, "prioritize")
+	if len(webhooks) == 0 || len(state.Nodes) == 0 {
+		return nil, nil
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{"pod": state.RawPod, "nodes": state.Nodes})
+	results := make([]struct {
+		Scores map[string]int64 `json:"scores"`
+	}, len(webhooks))
+	errs := e.fanOut(webhooks, "prioritize", payload, func(i int) interface{} { return &results[i] })
+
+	totals := make(map[string]int64)
+	weights := int64(0)
+	for i, webhook := range webhooks {
+		if errs[i] != nil {
+			e.logger.Warnf("⚠️ Scheduler extender %s scoring failed for %s/%s: %v", webhook.Name, pod.Metadata.Namespace, pod.Metadata.Name, errs[i])
+			continue
+		}
+		weights += int64(webhook.Weight)
+		for name, score := range results[i].Scores {
+			totals[name] += clampScore(score) * int64(webhook.Weight)
+		}
+	}
+	if weights == 0 {
+		return nil, nil
+	}
+	scores := make(map[string]int64, len(state.Nodes))
+	for _, node := range state.Nodes {
+		scores[node.Name] = totals[node.Name] / weights
+	}
+	return scores, nil
+}
+
+// schedulingState - kube-scheduler 인자를 플러그인 입력으로 변환
+func (e *SchedulerExtender) schedulingState(rawPod json.RawMessage, names []string) (*SchedulingState, *schedulerPipeline, bool) {
+	var pod extenderPod
+	if json.Unmarshal(rawPod, &pod) != nil {
+		return nil, nil, false
+	}
+	e.mutex.RLock()
+	pipeline := e.pipeline
+	e.mutex.RUnlock()
+	return &SchedulingState{Pod: pod, RawPod: rawPod, Nodes: e.candidates(names)}, pipeline, true
+}
+
+// pluginFailed - 플러그인 오류 기록 (해당 플러그인만 건너뜀)
+func (e *SchedulerExtender) pluginFailed(plugin, phase string, state *SchedulingState, err error) {
+	e.logger.Warnf("⚠️ Scheduler plugin %s %s failed for %s/%s, skipping: %v", plugin, phase, state.Pod.Metadata.Namespace, state.Pod.Metadata.Name, err)
+	e.mutex.Lock()
+	e.pluginErrors[plugin+"/"+phase]++
+	e.mutex.Unlock()
+}
+
+// Filter - 필터 플러그인을 순서대로 실행하여 탈락한 노드와 사유 반환
+func (e *SchedulerExtender) Filter(rawPod json.RawMessage, names []string) map[string]string {
+	failed := make(map[string]string)
+	state, pipeline, ok := e.schedulingState(rawPod, names)
+	if !ok || len(names) == 0 {
+		return failed
+	}
+
+	for _, plugin := range pipeline.filters {
+		rejected, err := plugin.Filter(state)
+		if err != nil {
+			e.pluginFailed(plugin.Name(), "filter", state, err)
+			continue
+		}
+		if len(rejected) == 0 {
+			continue
+		}
+		remaining := state.Nodes[:0:0]
+		for _, node := range state.Nodes {
+			if reason, ok := rejected[node.Name]; ok {
+				failed[node.Name] = reason
+			} else {
+				remaining = append(remaining, node)
+			}
+		}
+		state.Nodes = remaining
+		if len(state.Nodes) == 0 {
+			break
+		}
+	}
+	return failed
+}
+
+// Prioritize - 점수 플러그인 점수(0~10)의 플러그인 weight 가중 평균
+func (e *SchedulerExtender) Prioritize(rawPod json.RawMessage, names []string) []extenderHostPriority {
+	priorities := make([]extenderHostPriority, 0, len(names))
+	state, pipeline, ok := e.schedulingState(rawPod, names)
+	if !ok {
+		return priorities
+	}
+
+	totals := make(map[string]int64)
+	weights := int64(0)
+	if len(names) > 0 {
+		for _, scorer := range pipeline.scorers {
+			scores, err := scorer.plugin.Score(state)
+			if err != nil {
+				e.pluginFailed(scorer.plugin.Name(), "score", state, err)
+				continue
+			}
+			if scores == nil {
+				continue
+			}
+			weights += int64(scorer.weight)
+			for name, score := range scores {
+				totals[name] += clampScore(score) * int64(scorer.weight)
+			}
+		}
+	}
+
+	for _, name := range names {
+		score := int64(0)
+		if weights > 0 {
+			score = totals[name] / weights
+		}
+		priorities = append(priorities, extenderHostPriority{Host: name, Score: score})
+	}
+	return priorities
+}
+
+// Bind - 바인드 플러그인을 순서대로 호출 (처음 처리한 플러그인의 결과)
+func (e *SchedulerExtender) Bind(binding extenderBindingArgs) error {
+	e.mutex.RLock()
+	pipeline := e.pipeline
+	e.mutex.RUnlock()
+
+	for _, plugin := range pipeline.binders {
+		handled, err := plugin.Bind(binding)
+		if !handled {
+			continue
+		}
+		if err != nil {
+			e.mutex.Lock()
+			e.pluginErrors[plugin.Name()+"/bind"]++
+			e.mutex.Unlock()
+			return fmt.Errorf("%s: %v", plugin.Name(), err)
+		}
+		e.logger.Infof("📌 Pod %s/%s bound to %s by %s", binding.PodNamespace, binding.PodName, binding.Node, plugin.Name())
+		return nil
+	}
+	return fmt.Errorf("no scheduler bind plugin handled pod %s/%s", binding.PodNamespace, binding.PodName)
+}
+
+// allowKubeScheduler - 익스텐더 엔드포인트는 같은 호스트의 kube-scheduler만 호출
+func allowKubeScheduler(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+		http.Error(w, "Scheduler extender is only available to the local kube-scheduler", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func decodeExtenderArgs(w http.ResponseWriter, r *http.Request) (*extenderArgs, bool) {
+	var args extenderArgs
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, extenderMaxBodyBytes)).Decode(&args); err != nil || args.NodeNames == nil {
+		http.Error(w, "Invalid extender arguments (nodeCacheCapable requires NodeNames)", http.StatusBadRequest)
+		return nil, false
+	}
+	return &args, true
+}
+
+// handleFilter - kube-scheduler 필터 단계 (POST /scheduler/extender/filter)
+func (e *SchedulerExtender) handleFilter(w http.ResponseWriter, r *http.Request) {
+	if !allowKubeScheduler(w, r) {
+		return
+	}
+	args, ok := decodeExtenderArgs(w, r)
+	if !ok {
+		return
+	}
+
+	failed := e.Filter(args.Pod, *args.NodeNames)
+	passed := make([]string, 0, len(*args.NodeNames))
+	for _, name := range *args.NodeNames {
+		if _, rejected := failed[name]; !rejected {
+			passed = append(passed, name)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(extenderFilterResult{NodeNames: &passed, FailedNodes: failed})
+}
+
+// handlePrioritize - kube-scheduler 점수 단계 (POST /scheduler/extender/prioritize)
+func (e *SchedulerExtender) handlePrioritize(w http.ResponseWriter, r *http.Request) {
+	if !allowKubeScheduler(w, r) {
+		return
+	}
+	args, ok := decodeExtenderArgs(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e.Prioritize(args.Pod, *args.NodeNames))
+}
+
+// handleBind - kube-scheduler 바인드 단계 (POST /scheduler/extender/bind, 바인드 플러그인이 설정된 경우만 등록됨)
+func (e *SchedulerExtender) handleBind(w http.ResponseWriter, r *http.Request) {
+	if !allowKubeScheduler(w, r) {
+		return
+	}
+	var binding extenderBindingArgs
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, extenderMaxBodyBytes)).Decode(&binding); err != nil ||
+		binding.PodName == "" || binding.PodNamespace == "" || binding.Node == "" {
+		http.Error(w, "Invalid extender binding arguments", http.StatusBadRequest)
+		return
+	}
+	result := extenderBindingResult{}
+	if err := e.Bind(binding); err != nil {
+		e.logger.Warnf("⚠️ Failed to bind pod %s/%s to %s: %v", binding.PodNamespace, binding.PodName, binding.Node, err)
+		result.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Plugins - 등록된 모든 플러그인의 설정과 구현 단계 (설정 순서, 설정에 없는 플러그인은 꺼진 것으로 뒤에 표시)
+func (e *SchedulerExtender) Plugins() []SchedulerPluginStatus {
+	e.mutex.RLock()
+	configs := withNewPlugins(e.pipeline.configs, disabledSchedulerPlugins())
+	e.mutex.RUnlock()
+
+	schedulerPluginsMu.RLock()
+	defer schedulerPluginsMu.RUnlock()
+	statuses := []SchedulerPluginStatus{}
+	for _, config := range configs {
+		status := SchedulerPluginStatus{SchedulerPluginConfig: config, Phases: []string{}}
+		if plugin, err := schedulerPluginFactories[config.Name](e.handle, config.Args); err == nil {
+			status.Phases = pluginPhases(plugin)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// SetPlugins - 플러그인 설정 교체 (검증 후 바로 적용, 바인드 플러그인 변경은 재시작 후 적용)
+func (e *SchedulerExtender) SetPlugins(configs []SchedulerPluginConfig) error {
+	// 빠진 플러그인은 꺼진 것으로 저장 (재시작 시 기본값으로 다시 켜지지 않도록)
+	configs = withNewPlugins(configs, disabledSchedulerPlugins())
+	pipeline, err := buildSchedulerPipeline(e.handle, configs)
+	if err != nil {
+		return err
+	}
+	if pipeline.customBind() != e.bindVerb {
+		e.logger.Warnf("⚠️ Scheduler bind plugins changed; kube-scheduler picks this up after the master restarts")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if err := saveJSONState(e.pluginsFile, configs); err != nil {
+		return err
+	}
+	e.pipeline = pipeline
+	e.logger.Infof("🧭 Scheduler plugins updated (%d filter, %d score, %d bind)", len(pipeline.filters), len(pipeline.scorers), len(pipeline.binders))
+	return nil
+}
+
+func (e *SchedulerExtender) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if e.adminToken == "" {
+		http.Error(w, "Scheduler extender API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(e.adminToken)) != 1 {
+		e.logger.Warnf("🚫 Unauthorized scheduler extender API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handleWebhooks - 익스텐더 웹훅 관리 (/api/v1/admin/scheduler-extenders, 관리자 토큰 필요)
+
+	GET                  웹훅 목록 (토큰 제외)
+	POST {ExtenderWebhook}  추가/교체 (이름 기준)
+	DELETE ?name=        삭제
+*/
+func (e *SchedulerExtender) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !e.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, e.List())
+
+	case http.MethodPost:
+		var webhook ExtenderWebhook
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&webhook); err != nil {
+			http.Error(w, "Invalid scheduler extender webhook: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		saved, err := e.Put(webhook)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, saved.redacted())
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		removed, err := e.Remove(name)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		case !removed:
+			http.Error(w, "Unknown scheduler extender webhook", http.StatusNotFound)
+		default:
+			e.logger.Infof("🗑️ Scheduler extender webhook %s removed", name)
+			writeClientJSON(w, map[string]interface{}{"removed": name})
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+/*
+handlePlugins - 스케줄러 플러그인 설정 (/api/v1/admin/scheduler-plugins, 관리자 토큰 필요)
+
+	GET                            등록된 플러그인과 단계, 가중치
+	PUT [SchedulerPluginConfig...]  전체 설정 교체 (목록 순서가 실행 순서, 빠진 플러그인은 꺼짐)
+*/
+func (e *SchedulerExtender) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	if !e.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, e.Plugins())
+
+	case http.MethodPut:
+		var configs []SchedulerPluginConfig
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&configs); err != nil {
+			http.Error(w, "Invalid scheduler plugin config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := e.SetPlugins(configs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeClientJSON(w, e.Plugins())
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 웹훅 호출 결과와 탈락시킨 노드 수
+func (e *SchedulerExtender) writeMetrics(w io.Writer) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_scheduler_extender_webhooks", "gauge", "Configured scheduler extender webhooks")
+	writeMetric(w, "nautilus_scheduler_extender_webhooks", nil, float64(len(e.webhooks)))
+
+	keys := make([]string, 0, len(e.calls))
+	for key := range e.calls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeMetricHeader(w, "nautilus_scheduler_extender_calls_total", "counter", "Scheduler extender webhook calls by verb and outcome")
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 3)
+		writeMetric(w, "nautilus_scheduler_extender_calls_total",
+			map[string]string{"webhook": parts[0], "verb": parts[1], "outcome": parts[2]}, float64(e.calls[key]))
+	}
+
+	names := make([]string, 0, len(e.rejected))
+	for name := range e.rejected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeMetricHeader(w, "nautilus_scheduler_extender_rejected_nodes_total", "counter", "Candidate nodes filtered out by scheduler extender webhooks")
+	for _, name := range names {
+		writeMetric(w, "nautilus_scheduler_extender_rejected_nodes_total", map[string]string{"webhook": name}, float64(e.rejected[name]))
+	}
+
+	writeMetricHeader(w, "nautilus_scheduler_plugin_weight", "gauge", "Score weight of enabled scheduler plugins")
+	for _, scorer := range e.pipeline.scorers {
+		writeMetric(w, "nautilus_scheduler_plugin_weight", map[string]string{"plugin": scorer.plugin.Name()}, float64(scorer.weight))
+	}
+	keys = keys[:0]
+	for key := range e.pluginErrors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeMetricHeader(w, "nautilus_scheduler_plugin_errors_total", "counter", "Scheduler plugin failures by phase (the plugin is skipped)")
+	for _, key := range keys {
+		plugin, phase, _ := strings.Cut(key, "/")
+		writeMetric(w, "nautilus_scheduler_plugin_errors_total", map[string]string{"plugin": plugin, "phase": phase}, float64(e.pluginErrors[key]))
+	}
+}
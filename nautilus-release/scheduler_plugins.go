@@ -0,0 +1,481 @@
+// Scheduler Plugins - 익스텐더 스케줄링을 필터/점수/바인드 단계 플러그인 파이프라인으로 구성 (가중치 설정 가능)
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+kube-scheduler가 익스텐더를 호출할 때마다 설정된 플러그인을 순서대로 실행합니다.
+
+	filter     후보 노드를 탈락시킴 (앞 플러그인이 탈락시킨 노드는 다음 플러그인에 전달하지 않음)
+	score      노드별 0~10점, 플러그인 weight로 가중 평균해 kube-scheduler에 반환
+	bind       Pod를 노드에 바인딩 (DefaultBinder 외의 바인드 플러그인이 켜져 있을 때만 bindVerb 등록)
+
+내장 플러그인: ExtenderWebhooks(테넌트 웹훅, 필터+점수), StakeWeight(스테이킹 양 비례),
+LeastAllocated(요청 후 남는 CPU/메모리 비율), TopologySpread(같은 라벨 Pod가 적은 존/리전 우선),
+CapacityReservations(다른 네임스페이스의 미사용 예약 용량 보류, capacity_reservations.go),
+HostCompliance(컴플라이언스 정책 위반 노드 감점, node_compliance.go), DefaultBinder.
+다른 플러그인은 같은 패키지에서 init()으로 RegisterSchedulerPlugin을 호출해 추가하고 설정에서 켭니다.
+
+	NAUTILUS_SCHEDULER_PLUGINS  저장된 설정이 없을 때의 가중치 (예: "StakeWeight=3,LeastAllocated=1,-TopologySpread",
+	                            이름만 쓰면 weight 1로 켬, -이름은 끔)
+
+설정은 /api/v1/admin/scheduler-plugins(PUT)로 바꾸며 재시작 후에도 유지됩니다.
+바인드 플러그인 추가/제거는 kube-scheduler 설정이 바뀌어야 하므로 마스터 재시작 후 적용됩니다.
+*/
+
+const defaultBinderPlugin = "DefaultBinder"
+
+// SchedulerPlugin - 파이프라인 플러그인 (FilterPlugin/ScorePlugin/BindPlugin 중 하나 이상 구현)
+type SchedulerPlugin interface {
+	Name() string
+}
+
+// FilterPlugin - 탈락시킬 노드와 사유 (오류면 이 플러그인만 건너뜀)
+type FilterPlugin interface {
+	SchedulerPlugin
+	Filter(state *SchedulingState) (map[string]string, error)
+}
+
+// ScorePlugin - 노드별 점수 0~10 (nil이면 기권하여 가중 평균에서 제외, 없는 노드는 0점)
+type ScorePlugin interface {
+	SchedulerPlugin
+	Score(state *SchedulingState) (map[string]int64, error)
+}
+
+// BindPlugin - 바인딩 처리 (처리하지 않는 Pod는 false를 반환해 다음 플러그인에 넘김)
+type BindPlugin interface {
+	SchedulerPlugin
+	Bind(binding extenderBindingArgs) (bool, error)
+}
+
+// SchedulingState - 익스텐더 호출 한 번의 Pod와 남은 후보 노드
+type SchedulingState struct {
+	Pod    extenderPod
+	RawPod json.RawMessage // kube-scheduler가 보낸 v1.Pod
+	Nodes  []ExtenderNode
+}
+
+// SchedulerPluginHandle - 플러그인이 사용할 수 있는 마스터 구성요소
+type SchedulerPluginHandle struct {
+	Extender   *SchedulerExtender
+	K3s        *K3sManager
+	WorkerPool *WorkerPool
+	Simulator  *ScheduleSimulator // 노드별 할당 가능량과 요청량 (15초 캐시)
+
+	// Reservations - 활성 용량 예약 (CapacityReservations 게이트가 꺼져 있으면 nil, 파이프라인 생성 후 설정됨)
+	Reservations *CapacityReservations
+	// Compliance - 노드 컴플라이언스 판정 (HostCompliance 게이트가 꺼져 있으면 nil, 파이프라인 생성 후 설정됨)
+	Compliance *NodeComplianceTracker
+}
+
+// SchedulerPluginFactory - 설정의 args로 플러그인 생성
+type SchedulerPluginFactory func(handle *SchedulerPluginHandle, args json.RawMessage) (SchedulerPlugin, error)
+
+// SchedulerPluginConfig - 플러그인별 설정 (목록 순서가 실행 순서)
+type SchedulerPluginConfig struct {
+	Name     string          `json:"name"`
+	Disabled bool            `json:"disabled,omitempty"`
+	Weight   int             `json:"weight"` // 점수 가중치 (0이면 점수 단계 제외)
+	Args     json.RawMessage `json:"args,omitempty"`
+}
+
+// SchedulerPluginStatus - 조회 응답 (등록된 모든 플러그인)
+type SchedulerPluginStatus struct {
+	SchedulerPluginConfig
+	Phases []string `json:"phases"`
+}
+
+type registeredSchedulerPlugin struct {
+	name           string
+	defaultEnabled bool
+}
+
+var (
+	schedulerPluginsMu       sync.RWMutex
+	schedulerPluginRegistry  []registeredSchedulerPlugin
+	schedulerPluginFactories = make(map[string]SchedulerPluginFactory)
+)
+
+// RegisterSchedulerPlugin - 플러그인 등록 (init에서 호출, 이름 중복 시 panic)
+func RegisterSchedulerPlugin(name string, defaultEnabled bool, factory SchedulerPluginFactory) {
+	schedulerPluginsMu.Lock()
+	defer schedulerPluginsMu.Unlock()
+	if _, exists := schedulerPluginFactories[name]; exists {
+		panic("scheduler plugin registered twice: " + name)
+	}
+	schedulerPluginFactories[name] = factory
+	schedulerPluginRegistry = append(schedulerPluginRegistry, registeredSchedulerPlugin{name, defaultEnabled})
+}
+
+func init() {
+	RegisterSchedulerPlugin("ExtenderWebhooks", true, func(handle *SchedulerPluginHandle, _ json.RawMessage) (SchedulerPlugin, error) {
+		return extenderWebhookPlugin{handle.Extender}, nil
+	})
+	RegisterSchedulerPlugin("StakeWeight", true, func(handle *SchedulerPluginHandle, _ json.RawMessage) (SchedulerPlugin, error) {
+		return stakeWeightPlugin{}, nil
+	})
+	RegisterSchedulerPlugin("LeastAllocated", true, newLeastAllocatedPlugin)
+	RegisterSchedulerPlugin("TopologySpread", true, newTopologySpreadPlugin)
+	RegisterSchedulerPlugin(defaultBinderPlugin, true, func(handle *SchedulerPluginHandle, _ json.RawMessage) (SchedulerPlugin, error) {
+		return defaultBinder{handle.K3s}, nil
+	})
+}
+
+// defaultSchedulerPluginConfigs - 기본으로 켜는 플러그인 (weight 1), NAUTILUS_SCHEDULER_PLUGINS 반영
+func defaultSchedulerPluginConfigs() ([]SchedulerPluginConfig, error) {
+	schedulerPluginsMu.RLock()
+	var configs []SchedulerPluginConfig
+	for _, plugin := range schedulerPluginRegistry {
+		configs = append(configs, SchedulerPluginConfig{Name: plugin.name, Disabled: !plugin.defaultEnabled, Weight: 1})
+	}
+	schedulerPluginsMu.RUnlock()
+
+	for _, item := range splitList(os.Getenv("NAUTILUS_SCHEDULER_PLUGINS")) {
+		name, weight, enabled := item, 1, true
+		if strings.HasPrefix(item, "-") {
+			name, enabled = item[1:], false
+		} else if key, value, ok := strings.Cut(item, "="); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("invalid NAUTILUS_SCHEDULER_PLUGINS weight %q", item)
+			}
+			name, weight = key, parsed
+		}
+		found := false
+		for i := range configs {
+			if configs[i].Name == name {
+				configs[i].Disabled = !enabled
+				if enabled {
+					configs[i].Weight = weight
+				}
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown scheduler plugin %q in NAUTILUS_SCHEDULER_PLUGINS", name)
+		}
+	}
+	return configs, nil
+}
+
+// disabledSchedulerPlugins - 등록된 모든 플러그인을 끈 설정
+func disabledSchedulerPlugins() []SchedulerPluginConfig {
+	schedulerPluginsMu.RLock()
+	defer schedulerPluginsMu.RUnlock()
+	configs := make([]SchedulerPluginConfig, 0, len(schedulerPluginRegistry))
+	for _, plugin := range schedulerPluginRegistry {
+		configs = append(configs, SchedulerPluginConfig{Name: plugin.name, Disabled: true})
+	}
+	return configs
+}
+
+// weightedScorer - 점수 플러그인과 가중치
+type weightedScorer struct {
+	plugin ScorePlugin
+	weight int
+}
+
+// schedulerPipeline - 설정으로 만든 단계별 플러그인 목록
+type schedulerPipeline struct {
+	configs []SchedulerPluginConfig
+	filters []FilterPlugin
+	scorers []weightedScorer
+	binders []BindPlugin
+}
+
+// buildSchedulerPipeline - 설정 검증 후 플러그인 생성
+func buildSchedulerPipeline(handle *SchedulerPluginHandle, configs []SchedulerPluginConfig) (*schedulerPipeline, error) {
+	pipeline := &schedulerPipeline{configs: configs}
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		schedulerPluginsMu.RLock()
+		factory, ok := schedulerPluginFactories[config.Name]
+		schedulerPluginsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown scheduler plugin %q", config.Name)
+		}
+		if seen[config.Name] {
+			return nil, fmt.Errorf("scheduler plugin %q configured twice", config.Name)
+		}
+		seen[config.Name] = true
+		if config.Weight < 0 {
+			return nil, fmt.Errorf("scheduler plugin %s: weight must not be negative", config.Name)
+		}
+		if config.Disabled {
+			continue
+		}
+
+		plugin, err := factory(handle, config.Args)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler plugin %s: %v", config.Name, err)
+		}
+		if filter, ok := plugin.(FilterPlugin); ok {
+			pipeline.filters = append(pipeline.filters, filter)
+		}
+		if scorer, ok := plugin.(ScorePlugin); ok && config.Weight > 0 {
+			pipeline.scorers = append(pipeline.scorers, weightedScorer{scorer, config.Weight})
+		}
+		if binder, ok := plugin.(BindPlugin); ok {
+			pipeline.binders = append(pipeline.binders, binder)
+		}
+	}
+	return pipeline, nil
+}
+
+// customBind - DefaultBinder 외의 바인드 플러그인이 있으면 kube-scheduler 바인딩을 마스터가 맡음
+func (p *schedulerPipeline) customBind() bool {
+	for _, binder := range p.binders {
+		if binder.Name() != defaultBinderPlugin {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginPhases - 플러그인이 구현한 단계
+func pluginPhases(plugin SchedulerPlugin) []string {
+	var phases []string
+	if _, ok := plugin.(FilterPlugin); ok {
+		phases = append(phases, "filter")
+	}
+	if _, ok := plugin.(ScorePlugin); ok {
+		phases = append(phases, "score")
+	}
+	if _, ok := plugin.(BindPlugin); ok {
+		phases = append(phases, "bind")
+	}
+	return phases
+}
+
+// clampScore - 플러그인 점수를 0~10으로 자름
+func clampScore(score int64) int64 {
+	if score < 0 {
+		return 0
+	}
+	if score > maxExtenderPriority {
+		return maxExtenderPriority
+	}
+	return score
+}
+
+// stakeWeightPlugin - 후보 중 가장 많이 스테이킹한 워커 대비 비율 (워커가 아닌 노드는 0점)
+type stakeWeightPlugin struct{}
+
+func (stakeWeightPlugin) Name() string { return "StakeWeight" }
+
+func (stakeWeightPlugin) Score(state *SchedulingState) (map[string]int64, error) {
+	var highest uint64
+	for _, node := range state.Nodes {
+		if node.StakeAmount > highest {
+			highest = node.StakeAmount
+		}
+	}
+	if highest == 0 {
+		return nil, nil
+	}
+	scores := make(map[string]int64, len(state.Nodes))
+	for _, node := range state.Nodes {
+		scores[node.Name] = int64(float64(node.StakeAmount)/float64(highest)*maxExtenderPriority + 0.5)
+	}
+	return scores, nil
+}
+
+// leastAllocatedPlugin - Pod 요청량을 뺀 뒤 남는 CPU/메모리 비율이 큰 노드 우선
+type leastAllocatedPlugin struct {
+	simulator *ScheduleSimulator
+	args      struct {
+		CPUWeight    int `json:"cpu_weight"`
+		MemoryWeight int `json:"memory_weight"`
+	}
+}
+
+func newLeastAllocatedPlugin(handle *SchedulerPluginHandle, args json.RawMessage) (SchedulerPlugin, error) {
+	plugin := &leastAllocatedPlugin{simulator: handle.Simulator}
+	plugin.args.CPUWeight, plugin.args.MemoryWeight = 1, 1
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &plugin.args); err != nil {
+			return nil, fmt.Errorf("invalid args: %v", err)
+		}
+	}
+	if plugin.args.CPUWeight < 0 || plugin.args.MemoryWeight < 0 || plugin.args.CPUWeight+plugin.args.MemoryWeight == 0 {
+		return nil, fmt.Errorf("cpu_weight and memory_weight must not be negative and not both zero")
+	}
+	if plugin.simulator == nil {
+		return nil, fmt.Errorf("node capacity snapshot unavailable")
+	}
+	return plugin, nil
+}
+
+func (p *leastAllocatedPlugin) Name() string { return "LeastAllocated" }
+
+func (p *leastAllocatedPlugin) Score(state *SchedulingState) (map[string]int64, error) {
+	var pod struct {
+		Spec simPodSpec `json:"spec"`
+	}
+	if err := json.Unmarshal(state.RawPod, &pod); err != nil {
+		return nil, fmt.Errorf("invalid pod: %v", err)
+	}
+	cpu, memory := podRequests(pod.Spec)
+
+	nodes, _, err := p.simulator.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*simNode, len(nodes))
+	for i := range nodes {
+		byName[nodes[i].name] = &nodes[i]
+	}
+
+	total := float64(p.args.CPUWeight + p.args.MemoryWeight)
+	scores := make(map[string]int64, len(state.Nodes))
+	for _, candidate := range state.Nodes {
+		node, ok := byName[candidate.Name]
+		if !ok {
+			continue
+		}
+		freeCPU := float64(node.freeCPU-cpu) / float64(maxInt64(node.allocCPU, 1))
+		freeMemory := float64(node.freeMemory-memory) / float64(maxInt64(node.allocMemory, 1))
+		fraction := (freeCPU*float64(p.args.CPUWeight) + freeMemory*float64(p.args.MemoryWeight)) / total
+		scores[candidate.Name] = clampScore(int64(fraction*maxExtenderPriority + 0.5))
+	}
+	return scores, nil
+}
+
+// spreadIgnoredLabels - 롤아웃마다 바뀌어 같은 워크로드를 나누는 라벨 (분산 대상 선택에서 제외)
+var spreadIgnoredLabels = map[string]bool{"pod-template-hash": true, "controller-revision-hash": true}
+
+// topologySpreadPlugin - 같은 네임스페이스에서 라벨이 같은 Pod가 적은 존(또는 리전) 우선
+type topologySpreadPlugin struct {
+	k3sMgr     *K3sManager
+	workerPool *WorkerPool
+	region     bool
+}
+
+func newTopologySpreadPlugin(handle *SchedulerPluginHandle, args json.RawMessage) (SchedulerPlugin, error) {
+	var config struct {
+		TopologyKey string `json:"topology_key"` // zone(기본) 또는 region
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &config); err != nil {
+			return nil, fmt.Errorf("invalid args: %v", err)
+		}
+	}
+	if config.TopologyKey != "" {
+		if _, ok := topologyKeys[config.TopologyKey]; !ok {
+			return nil, fmt.Errorf("topology_key must be zone or region")
+		}
+	}
+	return &topologySpreadPlugin{k3sMgr: handle.K3s, workerPool: handle.WorkerPool, region: config.TopologyKey == "region"}, nil
+}
+
+func (p *topologySpreadPlugin) Name() string { return "TopologySpread" }
+
+// domainOf - 워커가 보고한 존/리전 ("" 이면 알 수 없음)
+func (p *topologySpreadPlugin) domainOf(node ExtenderNode) string {
+	if p.region {
+		return node.Region
+	}
+	return node.Zone
+}
+
+func (p *topologySpreadPlugin) Score(state *SchedulingState) (map[string]int64, error) {
+	keys := make([]string, 0, len(state.Pod.Metadata.Labels))
+	for key := range state.Pod.Metadata.Labels {
+		if !spreadIgnoredLabels[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 || p.k3sMgr == nil {
+		return nil, nil
+	}
+	sort.Strings(keys)
+	selector := make([]string, len(keys))
+	for i, key := range keys {
+		selector[i] = key + "=" + state.Pod.Metadata.Labels[key]
+	}
+
+	output, err := p.k3sMgr.RunKubectl(nil, "get", "pods", "-n", state.Pod.Metadata.Namespace,
+		"-l", strings.Join(selector, ","), "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var pods struct {
+		Items []struct {
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("invalid pod list: %v", err)
+	}
+
+	// 후보 노드의 존/리전만 비교 (0개인 도메인도 포함)
+	counts := make(map[string]int)
+	for _, node := range state.Nodes {
+		if domain := p.domainOf(node); domain != "" {
+			counts[domain] = 0
+		}
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == "Succeeded" || pod.Status.Phase == "Failed" {
+			continue
+		}
+		if worker, ok := p.workerPool.GetWorker(pod.Spec.NodeName); ok {
+			if domain := p.domainOf(ExtenderNode{Zone: worker.Zone, Region: worker.Region}); domain != "" {
+				if _, candidate := counts[domain]; candidate {
+					counts[domain]++
+				}
+			}
+		}
+	}
+	most := 0
+	for _, count := range counts {
+		if count > most {
+			most = count
+		}
+	}
+	if most == 0 {
+		return nil, nil
+	}
+
+	scores := make(map[string]int64, len(state.Nodes))
+	for _, node := range state.Nodes {
+		if domain := p.domainOf(node); domain != "" {
+			scores[node.Name] = int64(maxExtenderPriority * (most - counts[domain]) / most)
+		}
+	}
+	return scores, nil
+}
+
+// defaultBinder - pods/binding 하위 리소스로 바인딩 (kube-scheduler 기본 동작과 같음)
+type defaultBinder struct {
+	k3sMgr *K3sManager
+}
+
+func (defaultBinder) Name() string { return defaultBinderPlugin }
+
+func (b defaultBinder) Bind(binding extenderBindingArgs) (bool, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Binding",
+		"metadata":   map[string]interface{}{"name": binding.PodName, "namespace": binding.PodNamespace, "uid": binding.PodUID},
+		"target":     map[string]interface{}{"apiVersion": "v1", "kind": "Node", "name": binding.Node},
+	})
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/binding", binding.PodNamespace, binding.PodName)
+	if _, err := b.k3sMgr.RunKubectl(body, "create", "--raw", path, "-f", "-"); err != nil {
+		return true, err
+	}
+	return true, nil
+}
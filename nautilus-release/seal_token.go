@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -70,6 +71,8 @@ func (stm *SealTokenManager) GenerateRealSealToken(nodeID string, stakeAmount ui
 
 // ValidateSealToken validates a seal token (simplified version)
 func (stm *SealTokenManager) ValidateSealToken(sealToken, nodeID string) bool {
+	// 체인에서 만든 토큰은 SealToken 객체 ID (0x + 64 hex)
+	sealToken = strings.TrimPrefix(sealToken, "0x")
 	if len(sealToken) != 64 {
 		stm.logger.Warnf("❌ Invalid seal token length for %s", nodeID)
 		return false
@@ -0,0 +1,523 @@
+// Sealing Escrow - 봉인 루트 키를 보관자(운영자)들에게 Shamir 분할로 맡기고, 엔클레이브를 잃었을 때 API로 복구
+package main
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	escrow "github.com/k3s-io/daas-escrow"
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+봉인 루트 키(enclave_keys.go)는 KMS 키 정책이나 local 루트 파일에만 묶여 있어서, 엔클레이브 하드웨어 고장이나
+KMS 키 삭제로 루트를 잃으면 <state>/*.sealed 의 마스터 키(체인 키, 응답 서명 키, 서비스 계정 키)를 열 수 없습니다.
+보관자를 설정하면 루트 키를 threshold-of-n Shamir 조각으로 나누고 각 조각을 보관자의 X25519 공개키로 봉인한
+<state>/sealing-escrow.json 을 만듭니다(평문 조각은 엔클레이브 밖으로 나가지 않음). 루트나 보관자 구성이 바뀌면 다시 만듭니다.
+
+	NAUTILUS_ESCROW_GUARDIANS   보관자 목록 JSON 파일 ([{"name": "alice", "public_key": "<daasctl escrow keygen 출력>"}])
+	NAUTILUS_ESCROW_THRESHOLD   복구에 필요한 보관자 수 (기본 과반)
+
+복구 절차 (상태 디렉토리 백업을 새 인스턴스에 복원한 뒤):
+ 1. nitro-kms는 열 수 없는 sealing-root.kms 를 옮겨 두고 시작 (새 루트 생성), local은 그대로 시작
+ 2. 봉인 blob이 번들의 루트에 속하면 마스터는 다른 구성요소를 띄우지 않고 API 주소에서 복구 엔드포인트만 엽니다
+    (로그에 일회용 복구 키 ID 출력, NAUTILUS_ADMIN_TOKEN 필요)
+ 3. 보관자마다 daasctl escrow submit --guardian <이름> --key <개인키 파일>
+    조각을 로컬에서 열어 복구 키로 다시 봉인해 제출 (GET/POST /api/v1/admin/escrow/recovery)
+ 4. threshold개가 모이면 루트를 복원해 루트 ID를 확인하고, 모든 blob을 새 루트로 다시 봉인(한 상태 트랜잭션)한 뒤
+    새 루트로 번들을 다시 만들고 정상 기동을 계속합니다. 보관자 개인키는 그대로 쓰므로 따로 받을 것이 없습니다.
+*/
+
+const (
+	escrowBundleVersion = 1
+	escrowBundleFile    = "sealing-escrow.json"
+)
+
+// EscrowGuardian - NAUTILUS_ESCROW_GUARDIANS 항목
+type EscrowGuardian struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"` // X25519 (base64)
+
+	key *ecdh.PublicKey
+}
+
+// EscrowShare - 보관자 공개키로 봉인된 조각
+type EscrowShare struct {
+	Guardian string           `json:"guardian"`
+	KeyID    string           `json:"key_id"`
+	Index    byte             `json:"index"`
+	Envelope *escrow.Envelope `json:"envelope"`
+}
+
+// EscrowBundle - <state>/sealing-escrow.json (관리자 API로 그대로 공개해도 되는 형태)
+type EscrowBundle struct {
+	Version   int           `json:"version"`
+	Algorithm string        `json:"algorithm"`
+	RootID    string        `json:"root_id"`
+	Provider  string        `json:"provider"`
+	Threshold int           `json:"threshold"`
+	Shares    []EscrowShare `json:"shares"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// EscrowRecoveryStatus - 복구 대기 중 GET/POST /api/v1/admin/escrow/recovery 응답
+type EscrowRecoveryStatus struct {
+	RootID        string        `json:"root_id"` // 복구할 (이전) 루트
+	Threshold     int           `json:"threshold"`
+	RecoveryKey   string        `json:"recovery_key"` // 일회용 X25519 공개키 (base64), 조각을 이 키로 다시 봉인해 제출
+	RecoveryKeyID string        `json:"recovery_key_id"`
+	Shares        []EscrowShare `json:"shares"`
+	Received      []string      `json:"received"`
+	Recovered     bool          `json:"recovered"`
+}
+
+// EscrowSubmission - 보관자가 복구 키로 다시 봉인한 조각 (평문은 escrow.Share JSON)
+type EscrowSubmission struct {
+	Guardian string           `json:"guardian"`
+	Envelope *escrow.Envelope `json:"envelope"`
+}
+
+// escrowRecovery - 진행 중인 복구 (제출된 조각은 엔클레이브 메모리에만)
+type escrowRecovery struct {
+	bundle   *EscrowBundle
+	key      *ecdh.PrivateKey
+	received map[string]escrow.Share
+	done     chan []byte
+	finished bool
+}
+
+// SealingEscrow - 봉인 루트 키 에스크로 번들 관리와 복구
+type SealingEscrow struct {
+	logger     *logrus.Logger
+	keyring    *EnclaveKeyring
+	adminToken string
+	guardians  []EscrowGuardian
+	threshold  int
+	bundlePath string
+
+	mutex     sync.Mutex
+	bundle    *EscrowBundle
+	recovery  *escrowRecovery
+	recovered int // 이번 기동에서 복구한 blob 수
+}
+
+// NewSealingEscrow - 보관자 목록과 저장된 번들 로드 (NAUTILUS_ESCROW_GUARDIANS가 없으면 nil)
+func NewSealingEscrow(logger *logrus.Logger, keyring *EnclaveKeyring) (*SealingEscrow, error) {
+	path := os.Getenv("NAUTILUS_ESCROW_GUARDIANS")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("NAUTILUS_ESCROW_GUARDIANS: %v", err)
+	}
+	var guardians []EscrowGuardian
+	if err := json.Unmarshal(data, &guardians); err != nil {
+		return nil, fmt.Errorf("NAUTILUS_ESCROW_GUARDIANS: %v", err)
+	}
+	if len(guardians) == 0 || len(guardians) > escrow.MaxShares {
+		return nil, fmt.Errorf("NAUTILUS_ESCROW_GUARDIANS must list 1 to %d guardians", escrow.MaxShares)
+	}
+	names := make(map[string]bool, len(guardians))
+	for i := range guardians {
+		guardian := &guardians[i]
+		if guardian.Name == "" || strings.ContainsAny(guardian.Name, "/ ") || names[guardian.Name] {
+			return nil, fmt.Errorf("NAUTILUS_ESCROW_GUARDIANS: invalid or duplicate guardian name %q", guardian.Name)
+		}
+		names[guardian.Name] = true
+		if guardian.key, err = escrow.ParsePublicKey(guardian.PublicKey); err != nil {
+			return nil, fmt.Errorf("NAUTILUS_ESCROW_GUARDIANS: guardian %s: %v", guardian.Name, err)
+		}
+	}
+	sort.Slice(guardians, func(i, j int) bool { return guardians[i].Name < guardians[j].Name })
+
+	threshold := len(guardians)/2 + 1
+	if value := os.Getenv("NAUTILUS_ESCROW_THRESHOLD"); value != "" {
+		if threshold, err = strconv.Atoi(value); err != nil || threshold < 1 || threshold > len(guardians) {
+			return nil, fmt.Errorf("NAUTILUS_ESCROW_THRESHOLD must be between 1 and %d", len(guardians))
+		}
+	}
+	if threshold == 1 && len(guardians) > 1 {
+		logger.Warnf("⚠️ NAUTILUS_ESCROW_THRESHOLD=1: any single guardian can recover the sealing root")
+	}
+
+	e := &SealingEscrow{
+		logger:     logger,
+		keyring:    keyring,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		guardians:  guardians,
+		threshold:  threshold,
+		bundlePath: statePath(escrowBundleFile),
+	}
+	var bundle EscrowBundle
+	found, err := loadJSONState(e.bundlePath, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load escrow bundle: %v", err)
+	}
+	if found {
+		e.bundle = &bundle
+	}
+	return e, nil
+}
+
+// Ensure - 현재 루트와 보관자 구성의 번들이 없으면 새로 분할해 저장
+func (e *SealingEscrow) Ensure() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.bundle != nil && e.bundle.RootID == e.keyring.rootID && e.bundle.Threshold == e.threshold && e.sameGuardians(e.bundle) {
+		return nil
+	}
+
+	shares, err := escrow.Split(e.keyring.root, e.threshold, len(e.guardians))
+	if err != nil {
+		return err
+	}
+	bundle := &EscrowBundle{
+		Version:   escrowBundleVersion,
+		Algorithm: escrow.Algorithm,
+		RootID:    e.keyring.rootID,
+		Provider:  e.keyring.provider,
+		Threshold: e.threshold,
+		CreatedAt: time.Now().UTC(),
+	}
+	for i, guardian := range e.guardians {
+		plaintext, err := json.Marshal(shares[i])
+		if err != nil {
+			return err
+		}
+		envelope, err := escrow.Seal(guardian.key, plaintext, escrow.ShareAAD(bundle.RootID, guardian.Name))
+		if err != nil {
+			return err
+		}
+		bundle.Shares = append(bundle.Shares, EscrowShare{
+			Guardian: guardian.Name,
+			KeyID:    escrow.KeyID(guardian.key),
+			Index:    shares[i].Index,
+			Envelope: envelope,
+		})
+	}
+	if err := saveJSONState(e.bundlePath, bundle); err != nil {
+		return fmt.Errorf("failed to save escrow bundle: %v", err)
+	}
+	e.bundle = bundle
+	e.logger.Infof("🗝️ Sealing root %s escrowed to %d guardians (threshold %d)", bundle.RootID, len(bundle.Shares), bundle.Threshold)
+	return nil
+}
+
+// sameGuardians - 번들 조각이 현재 보관자 이름/키와 같은지
+func (e *SealingEscrow) sameGuardians(bundle *EscrowBundle) bool {
+	if len(bundle.Shares) != len(e.guardians) {
+		return false
+	}
+	for i, share := range bundle.Shares {
+		if share.Guardian != e.guardians[i].Name || share.KeyID != escrow.KeyID(e.guardians[i].key) {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingRecovery - 번들의 (이전) 루트로 봉인되어 현재 루트로 열 수 없는 blob 이름
+func (e *SealingEscrow) pendingRecovery() ([]string, error) {
+	paths, err := filepath.Glob(statePath("*.sealed"))
+	if err != nil {
+		return nil, err
+	}
+	var pending []string
+	for _, path := range paths {
+		var blob sealedKeyBlob
+		if _, err := loadJSONState(path, &blob); err != nil {
+			return nil, err
+		}
+		if blob.RootID == e.keyring.rootID {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".sealed")
+		if e.bundle == nil || blob.RootID != e.bundle.RootID {
+			// 번들이 없는 루트는 복구할 수 없음 (키링 Load가 루트 불일치로 실패)
+			e.logger.Warnf("⚠️ Sealed %s key belongs to root %s, which has no escrow bundle", name, blob.RootID)
+			continue
+		}
+		pending = append(pending, name)
+	}
+	return pending, nil
+}
+
+/*
+Recover - 열 수 없는 blob이 번들의 루트에 속하면 보관자 조각이 threshold개 모일 때까지 복구 엔드포인트만 열고 대기
+복구한 루트로 blob을 열어 현재 루트로 다시 봉인합니다. 복구할 것이 없으면 바로 반환합니다.
+*/
+func (e *SealingEscrow) Recover(ctx context.Context) error {
+	pending, err := e.pendingRecovery()
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	if e.adminToken == "" {
+		return fmt.Errorf("%d sealed keys need escrow recovery but NAUTILUS_ADMIN_TOKEN is not set", len(pending))
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	recovery := &escrowRecovery{
+		bundle:   e.bundle,
+		key:      key,
+		received: make(map[string]escrow.Share),
+		done:     make(chan []byte, 1),
+	}
+	e.mutex.Lock()
+	e.recovery = recovery
+	e.mutex.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/admin/escrow/recovery", e.handleRecovery)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "escrow recovery in progress", http.StatusServiceUnavailable)
+	})
+	server, err := httpserver.New(httpserver.ConfigFromEnv("NAUTILUS", ":8080"), httpserver.Chain(mux, httpserver.Recovery(e.logger.Errorf)))
+	if err != nil {
+		return fmt.Errorf("invalid recovery listener config: %v", err)
+	}
+	serverCtx, stop := context.WithCancel(ctx)
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Run(serverCtx, 10*time.Second) }()
+
+	e.logger.Warnf("🆘 %d sealed keys (%s) belong to lost root %s; waiting for %d of %d guardian shares on %s (recovery key %s)",
+		len(pending), strings.Join(pending, ", "), recovery.bundle.RootID, recovery.bundle.Threshold, len(recovery.bundle.Shares),
+		server.Description(), escrow.KeyID(key.PublicKey()))
+
+	var root []byte
+	select {
+	case root = <-recovery.done:
+	case err := <-stopped:
+		stop()
+		return fmt.Errorf("recovery listener failed: %v", err)
+	case <-ctx.Done():
+		stop()
+		return ctx.Err()
+	}
+	// 마지막 제출에 응답한 뒤 API 서버가 같은 주소를 쓸 수 있도록 리스너 종료를 기다림
+	stop()
+	<-stopped
+
+	if err := e.keyring.rewrap(root, pending); err != nil {
+		return err
+	}
+	e.mutex.Lock()
+	e.recovery = nil
+	e.recovered = len(pending)
+	e.mutex.Unlock()
+	e.logger.Infof("♻️ Recovered %d sealed keys from escrow root %s and re-sealed them under root %s", len(pending), recovery.bundle.RootID, e.keyring.rootID)
+	return nil
+}
+
+// rewrap - 이전 루트로 봉인된 blob을 현재 루트로 다시 봉인 (모두 한 상태 트랜잭션으로 교체)
+func (k *EnclaveKeyring) rewrap(oldRoot []byte, names []string) error {
+	aead, rootID, err := sealingAEAD(oldRoot)
+	if err != nil {
+		return err
+	}
+	previous := &EnclaveKeyring{rootID: rootID, aead: aead}
+	txn := newStateTxn()
+	for _, name := range names {
+		path := statePath(name + ".sealed")
+		var blob sealedKeyBlob
+		if _, err := loadJSONState(path, &blob); err != nil {
+			return err
+		}
+		key, err := previous.unseal(name, &blob)
+		if err != nil {
+			return err
+		}
+		resealed, err := k.seal(name, key)
+		if err != nil {
+			return err
+		}
+		if err := txn.Put(path, resealed); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
+func (e *SealingEscrow) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if e.adminToken == "" {
+		http.Error(w, "Escrow API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(e.adminToken)) != 1 {
+		e.logger.Warnf("🚫 Unauthorized escrow API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleEscrow - 현재 에스크로 번들 (GET /api/v1/admin/escrow, 관리자 토큰 필요)
+func (e *SealingEscrow) handleEscrow(w http.ResponseWriter, r *http.Request) {
+	if !e.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e.mutex.Lock()
+	bundle := e.bundle
+	e.mutex.Unlock()
+	if bundle == nil {
+		http.Error(w, "Sealing root is not escrowed yet", http.StatusServiceUnavailable)
+		return
+	}
+	writeClientJSON(w, bundle)
+}
+
+/*
+handleRecovery - 복구 대기 중에만 열리는 엔드포인트 (관리자 토큰 필요)
+
+	GET                                             복구할 루트, 일회용 복구 키, 보관자별 봉인된 조각, 받은 조각
+	POST {"guardian": "alice", "envelope": {...}}   복구 키로 봉인한 조각 제출 (threshold개가 모이면 복구)
+*/
+func (e *SealingEscrow) handleRecovery(w http.ResponseWriter, r *http.Request) {
+	if !e.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		e.mutex.Lock()
+		status := e.recoveryStatus()
+		e.mutex.Unlock()
+		writeClientJSON(w, status)
+	case http.MethodPost:
+		var submission EscrowSubmission
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&submission); err != nil || submission.Envelope == nil {
+			http.Error(w, "Invalid share submission", http.StatusBadRequest)
+			return
+		}
+		status, code, err := e.submit(submission)
+		if err != nil {
+			http.Error(w, err.Error(), code)
+			return
+		}
+		writeClientJSON(w, status)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// submit - 조각을 열어 검증하고 threshold개가 모이면 루트 복원 (실패 시 HTTP 상태 코드와 사유)
+func (e *SealingEscrow) submit(submission EscrowSubmission) (EscrowRecoveryStatus, int, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	recovery := e.recovery
+	if recovery == nil || recovery.finished {
+		return EscrowRecoveryStatus{}, http.StatusConflict, fmt.Errorf("no escrow recovery in progress")
+	}
+
+	var expected *EscrowShare
+	for i := range recovery.bundle.Shares {
+		if recovery.bundle.Shares[i].Guardian == submission.Guardian {
+			expected = &recovery.bundle.Shares[i]
+		}
+	}
+	if expected == nil {
+		return EscrowRecoveryStatus{}, http.StatusBadRequest, fmt.Errorf("unknown guardian %q", submission.Guardian)
+	}
+	plaintext, err := escrow.Open(recovery.key, submission.Envelope, escrow.RecoveryAAD(recovery.bundle.RootID, submission.Guardian))
+	if err != nil {
+		return EscrowRecoveryStatus{}, http.StatusBadRequest, err
+	}
+	var share escrow.Share
+	if err := json.Unmarshal(plaintext, &share); err != nil || share.Index != expected.Index {
+		return EscrowRecoveryStatus{}, http.StatusBadRequest, fmt.Errorf("submitted share is not %s's share", submission.Guardian)
+	}
+	recovery.received[submission.Guardian] = share
+	e.logger.Infof("🗝️ Escrow share from %s accepted (%d of %d)", submission.Guardian, len(recovery.received), recovery.bundle.Threshold)
+
+	if len(recovery.received) >= recovery.bundle.Threshold {
+		shares := make([]escrow.Share, 0, len(recovery.received))
+		for _, share := range recovery.received {
+			shares = append(shares, share)
+		}
+		root, err := escrow.Combine(shares)
+		if err == nil {
+			var rootID string
+			if _, rootID, err = sealingAEAD(root); err == nil && rootID != recovery.bundle.RootID {
+				err = fmt.Errorf("combined shares do not reproduce root %s", recovery.bundle.RootID)
+			}
+		}
+		if err != nil {
+			// 어느 조각이 잘못됐는지 알 수 없으므로 모두 버리고 다시 받음
+			recovery.received = make(map[string]escrow.Share)
+			e.logger.Errorf("❌ Escrow recovery failed, all shares discarded: %v", err)
+			return EscrowRecoveryStatus{}, http.StatusConflict, fmt.Errorf("%v; all shares were discarded, guardians must resubmit", err)
+		}
+		recovery.finished = true
+		recovery.done <- root
+	}
+	return e.recoveryStatus(), 0, nil
+}
+
+// recoveryStatus - 진행 상황 (mutex 보유 상태에서 호출)
+func (e *SealingEscrow) recoveryStatus() EscrowRecoveryStatus {
+	recovery := e.recovery
+	if recovery == nil {
+		return EscrowRecoveryStatus{Recovered: true}
+	}
+	status := EscrowRecoveryStatus{
+		RootID:        recovery.bundle.RootID,
+		Threshold:     recovery.bundle.Threshold,
+		RecoveryKey:   base64.StdEncoding.EncodeToString(recovery.key.PublicKey().Bytes()),
+		RecoveryKeyID: escrow.KeyID(recovery.key.PublicKey()),
+		Shares:        recovery.bundle.Shares,
+		Received:      make([]string, 0, len(recovery.received)),
+		Recovered:     recovery.finished,
+	}
+	for guardian := range recovery.received {
+		status.Received = append(status.Received, guardian)
+	}
+	sort.Strings(status.Received)
+	return status
+}
+
+// writeMetrics - 보관자 수, threshold, 번들이 현재 루트의 것인지, 복구한 blob 수
+func (e *SealingEscrow) writeMetrics(w io.Writer) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	current, created := 0.0, 0.0
+	if e.bundle != nil {
+		created = float64(e.bundle.CreatedAt.Unix())
+		if e.bundle.RootID == e.keyring.rootID {
+			current = 1
+		}
+	}
+	writeMetricHeader(w, "nautilus_escrow_guardians", "gauge", "Guardians holding a share of the sealing root")
+	writeMetric(w, "nautilus_escrow_guardians", nil, float64(len(e.guardians)))
+	writeMetricHeader(w, "nautilus_escrow_threshold", "gauge", "Guardian shares needed to recover the sealing root")
+	writeMetric(w, "nautilus_escrow_threshold", nil, float64(e.threshold))
+	writeMetricHeader(w, "nautilus_escrow_current", "gauge", "Whether the escrow bundle covers the active sealing root")
+	writeMetric(w, "nautilus_escrow_current", nil, current)
+	writeMetricHeader(w, "nautilus_escrow_bundle_timestamp_seconds", "gauge", "When the escrow bundle was created")
+	writeMetric(w, "nautilus_escrow_bundle_timestamp_seconds", nil, created)
+	writeMetricHeader(w, "nautilus_escrow_recovered_keys", "gauge", "Sealed keys recovered from escrow during this start")
+	writeMetric(w, "nautilus_escrow_recovered_keys", nil, float64(e.recovered))
+}
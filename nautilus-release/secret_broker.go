@@ -0,0 +1,417 @@
+// Secret Broker - 워커가 Pod 마운트 시 외부 Secret(Vault, 온체인 Sealed Secret)을 가져오도록 신원 토큰 발급/복호화
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SecretBroker - 마스터 저장소 밖에 있는 Secret을 워커가 Pod 마운트 시점에 해석하도록 지원
+
+워커의 secrets FlexVolume 드라이버가 kubelet의 mount 호출을 받으면 워커 데몬이 Provider별로 해석합니다.
+마스터는 두 가지를 제공하며, 모두 워커 Seal 토큰으로 인증하고 Pod가 그 워커에 배치된 것(같은 UID)인지 확인합니다.
+
+  - vault: Pod 서비스 계정의 JWT 발급 (POST /api/v1/secrets/token, aud 기본 vault)
+    테넌트 Vault는 /api/v1/serviceaccounts/jwks로 서명을 검증하는 JWT 인증 role을 네임스페이스 클레임에 묶어 사용
+  - seal: 온체인 sealed_secrets::SealedSecret 복호화 (POST /api/v1/secrets/unseal)
+    오브젝트 소유자가 네임스페이스의 k3s-daas.io/tenant 라벨과 같고 오브젝트 namespace가 Pod 네임스페이스와 같을 때만 응답
+
+Sealed Secret 봉투 (base64(JSON), 본문 또는 blob_url 내용):
+
+	{"v":1, "kid":"<key_id>", "epk":"<임시 X25519 공개키 b64>", "nonce":"<12바이트 b64>", "ct":"<AES-256-GCM b64>"}
+
+  - 수신자 키: GET /api/v1/secrets/recipient (TEE 서명 키에서 파생한 X25519, 마스터 재시작/대기 마스터에서도 같음)
+  - 대칭 키: sha256("k3s-daas sealed secret v1" || ECDH 공유값 || epk || 수신자 공개키)
+  - AAD: "<owner>/<namespace>/<name>" - 공개된 암호문을 다른 지갑의 오브젝트에 복사해 복호화를 요청해도 실패
+  - 평문: {"<파일 이름>": "<base64 값>"} (K8s Secret data와 같은 형식)
+*/
+
+const (
+	sealedSecretType       = "::sealed_secrets::SealedSecret"
+	sealedSecretKDFLabel   = "k3s-daas sealed secret v1"
+	sealedSecretMaxBlob    = 1 << 20
+	secretTokenAudience    = "vault"
+	secretBlobFetchTimeout = 15 * time.Second
+)
+
+var (
+	errSecretPodNotOnNode = errors.New("pod is not scheduled on this worker")
+	errSecretForbidden    = errors.New("sealed secret does not belong to the pod's namespace tenant")
+)
+
+// sealedEnvelope - 암호화된 Secret 봉투
+type sealedEnvelope struct {
+	Version   int    `json:"v"`
+	KeyID     string `json:"kid"`
+	Ephemeral []byte `json:"epk"`
+	Nonce     []byte `json:"nonce"`
+	Sealed    []byte `json:"ct"`
+}
+
+// SecretRecipient - 테넌트가 Secret을 암호화할 때 쓰는 마스터 공개키
+type SecretRecipient struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	PublicKey []byte `json:"public_key"` // X25519 (base64)
+}
+
+// secretPodRequest - 워커가 보내는 마운트 대상 Pod (kubelet FlexVolume 옵션 그대로)
+type secretPodRequest struct {
+	NodeID    string `json:"node_id"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	PodUID    string `json:"pod_uid"`
+}
+
+// SecretBroker - Secret 해석 지원
+type SecretBroker struct {
+	logger          *logrus.Logger
+	k3sMgr          *K3sManager
+	workerPool      *WorkerPool
+	sui             *SuiIntegration
+	serviceAccounts *ServiceAccountIssuer
+	key             EnclaveAgreement
+	keyID           string
+	httpClient      *http.Client
+
+	mutex    sync.Mutex
+	requests map[string]map[string]int // kind → outcome → 횟수
+}
+
+// NewSecretBroker - 봉인된 응답 서명 키에서 Sealed Secret 수신자 키를 파생해 생성 (키 합의는 키링 안에서)
+func NewSecretBroker(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration, keyring *EnclaveKeyring, serviceAccounts *ServiceAccountIssuer) (*SecretBroker, error) {
+	key, err := keyring.DeriveX25519(responseSigningKey().name, sealedSecretKDFLabel+" recipient")
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive sealed secret key: %v", err)
+	}
+	digest := sha256.Sum256(key.PublicKey().Bytes())
+	return &SecretBroker{
+		logger:          logger,
+		k3sMgr:          k3sMgr,
+		workerPool:      k3sMgr.workerPool,
+		sui:             sui,
+		serviceAccounts: serviceAccounts,
+		key:             key,
+		keyID:           hex.EncodeToString(digest[:8]),
+		httpClient:      &http.Client{Timeout: secretBlobFetchTimeout},
+		requests:        make(map[string]map[string]int),
+	}, nil
+}
+
+// Recipient - 암호화용 공개키
+func (b *SecretBroker) Recipient() SecretRecipient {
+	return SecretRecipient{Algorithm: "X25519-SHA256-AES256GCM", KeyID: b.keyID, PublicKey: b.key.PublicKey().Bytes()}
+}
+
+// authenticateWorker - node_id의 Seal 토큰 확인
+func (b *SecretBroker) authenticateWorker(r *http.Request, nodeID string) bool {
+	worker, exists := b.workerPool.GetWorker(nodeID)
+	return exists && worker.SealToken != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Seal-Token")), []byte(worker.SealToken)) == 1
+}
+
+// podIdentity - 마운트 대상 Pod의 서비스 계정과 네임스페이스 테넌트
+type podIdentity struct {
+	ServiceAccount string
+	Tenant         string
+}
+
+// verifyPod - Pod가 요청한 워커에 배치되어 있고 UID가 같은지 확인
+func (b *SecretBroker) verifyPod(request secretPodRequest) (*podIdentity, error) {
+	if !podLogNamePattern.MatchString(request.Namespace) || !podLogNamePattern.MatchString(request.Pod) {
+		return nil, fmt.Errorf("namespace and pod must be DNS names")
+	}
+	output, err := b.k3sMgr.RunKubectl(nil, "get", "pod", request.Pod, "-n", request.Namespace, "-o", "json")
+	if err != nil {
+		return nil, errSecretPodNotOnNode
+	}
+	var pod struct {
+		Metadata struct {
+			UID string `json:"uid"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName           string `json:"nodeName"`
+			ServiceAccountName string `json:"serviceAccountName"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &pod); err != nil {
+		return nil, fmt.Errorf("failed to decode pod: %v", err)
+	}
+	if pod.Spec.NodeName != request.NodeID || (request.PodUID != "" && pod.Metadata.UID != request.PodUID) {
+		return nil, errSecretPodNotOnNode
+	}
+
+	identity := &podIdentity{ServiceAccount: pod.Spec.ServiceAccountName}
+	if identity.ServiceAccount == "" {
+		identity.ServiceAccount = "default"
+	}
+	tenant, err := b.k3sMgr.RunKubectl(nil, "get", "namespace", request.Namespace,
+		"-o", `jsonpath={.metadata.labels.k3s-daas\.io/tenant}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace %s: %v", request.Namespace, err)
+	}
+	identity.Tenant = strings.TrimSpace(string(tenant))
+	return identity, nil
+}
+
+// IssueToken - Pod 서비스 계정 JWT (Vault 등 외부 Secret 저장소의 JWT 인증용)
+// K8s API audience가 아니고 계정 저장소에도 없으므로 마스터 K8s API 인증에는 쓸 수 없음
+func (b *SecretBroker) IssueToken(request secretPodRequest, audience string) (*ServiceAccountToken, error) {
+	identity, err := b.verifyPod(request)
+	if err != nil {
+		return nil, err
+	}
+	if audience == "" {
+		audience = secretTokenAudience
+	}
+	if audience == b.serviceAccounts.audience {
+		return nil, fmt.Errorf("audience %s is reserved for the Kubernetes API", audience)
+	}
+	account := &ServiceAccount{Namespace: request.Namespace, Name: identity.ServiceAccount, Owner: identity.Tenant}
+	return b.serviceAccounts.IssueToken(account, []string{audience}, minServiceAccountTTL)
+}
+
+// Unseal - 온체인 Sealed Secret 복호화 (소유자/네임스페이스 확인 후)
+func (b *SecretBroker) Unseal(ctx context.Context, request secretPodRequest, objectID string) (map[string][]byte, uint64, error) {
+	identity, err := b.verifyPod(request)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	object, err := b.sui.backend.QueryObject(ctx, objectID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read sealed secret %s: %v", objectID, err)
+	}
+	if !strings.HasSuffix(object.Type, sealedSecretType) {
+		return nil, 0, fmt.Errorf("object %s is %s, not a sealed secret", objectID, object.Type)
+	}
+	field := func(name string) string {
+		value, _ := object.Fields[name].(string)
+		return value
+	}
+	owner, namespace, name := field("owner"), field("namespace"), field("name")
+	if identity.Tenant == "" || !strings.EqualFold(owner, identity.Tenant) || namespace != request.Namespace {
+		return nil, 0, errSecretForbidden
+	}
+
+	encoded := field("ciphertext")
+	if encoded == "" {
+		encoded, err = b.fetchBlob(ctx, field("blob_url"), field("blob_sha256"))
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	data, err := b.open(encoded, owner+"/"+namespace+"/"+name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt sealed secret %s: %v", objectID, err)
+	}
+	return data, object.Version, nil
+}
+
+// fetchBlob - 외부 blob을 내려받아 온체인 다이제스트와 비교
+func (b *SecretBroker) fetchBlob(ctx context.Context, url, digest string) (string, error) {
+	if url == "" {
+		return "", fmt.Errorf("sealed secret has neither ciphertext nor blob_url")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch sealed secret blob: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sealed secret blob: HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, sealedSecretMaxBlob+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > sealedSecretMaxBlob {
+		return "", fmt.Errorf("sealed secret blob exceeds %d bytes", sealedSecretMaxBlob)
+	}
+	sum := sha256.Sum256(body)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return "", fmt.Errorf("sealed secret blob does not match blob_sha256")
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// open - 봉투 복호화
+func (b *SecretBroker) open(encoded, aad string) (map[string][]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("envelope is not base64")
+	}
+	var envelope sealedEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Version != 1 {
+		return nil, fmt.Errorf("unsupported envelope")
+	}
+	if envelope.KeyID != "" && envelope.KeyID != b.keyID {
+		return nil, fmt.Errorf("sealed for key %s, this master has %s", envelope.KeyID, b.keyID)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope.Ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key")
+	}
+	shared, err := b.key.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := sealedSecretCipher(shared, envelope.Ephemeral, b.key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Sealed, []byte(aad))
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed")
+	}
+	var data map[string][]byte
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, fmt.Errorf("plaintext is not a JSON object of base64 values")
+	}
+	return data, nil
+}
+
+// sealedSecretCipher - ECDH 공유값에서 AES-256-GCM 키 파생
+func sealedSecretCipher(shared, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	hash := sha256.New()
+	hash.Write([]byte(sealedSecretKDFLabel))
+	hash.Write(shared)
+	hash.Write(ephemeral)
+	hash.Write(recipient)
+	block, err := aes.NewCipher(hash.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (b *SecretBroker) count(kind, outcome string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.requests[kind] == nil {
+		b.requests[kind] = make(map[string]int)
+	}
+	b.requests[kind][outcome]++
+}
+
+// secretErrorStatus - 오류별 응답 코드
+func secretErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errSecretPodNotOnNode), errors.Is(err, errSecretForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// handleRecipient - GET /api/v1/secrets/recipient (인증 없음)
+func (b *SecretBroker) handleRecipient(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, b.Recipient())
+}
+
+// decodePodRequest - 요청 본문 해석과 워커 인증 (실패 시 응답까지 작성)
+func (b *SecretBroker) decodePodRequest(w http.ResponseWriter, r *http.Request, body interface{}, pod *secretPodRequest) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(body); err != nil || pod.Namespace == "" || pod.Pod == "" {
+		http.Error(w, "Invalid secret request", http.StatusBadRequest)
+		return false
+	}
+	if !b.authenticateWorker(r, pod.NodeID) {
+		http.Error(w, "Invalid seal token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleToken - POST /api/v1/secrets/token {node_id, namespace, pod, pod_uid, audience}
+func (b *SecretBroker) handleToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		secretPodRequest
+		Audience string `json:"audience"`
+	}
+	if !b.decodePodRequest(w, r, &body, &body.secretPodRequest) {
+		return
+	}
+	token, err := b.IssueToken(body.secretPodRequest, body.Audience)
+	if err != nil {
+		b.count("token", "rejected")
+		b.logger.Warnf("🚫 Secret token refused for %s/%s on %s: %v", body.Namespace, body.Pod, body.NodeID, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	b.count("token", "issued")
+	w.Header().Set("Cache-Control", "no-store")
+	writeClientJSON(w, token)
+}
+
+// handleUnseal - POST /api/v1/secrets/unseal {node_id, namespace, pod, pod_uid, object_id}
+func (b *SecretBroker) handleUnseal(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		secretPodRequest
+		ObjectID string `json:"object_id"`
+	}
+	if !b.decodePodRequest(w, r, &body, &body.secretPodRequest) {
+		return
+	}
+	if body.ObjectID == "" {
+		http.Error(w, "object_id is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	data, version, err := b.Unseal(ctx, body.secretPodRequest, body.ObjectID)
+	if err != nil {
+		b.count("unseal", "rejected")
+		b.logger.Warnf("🚫 Sealed secret %s refused for %s/%s on %s: %v", body.ObjectID, body.Namespace, body.Pod, body.NodeID, err)
+		http.Error(w, err.Error(), secretErrorStatus(err))
+		return
+	}
+	b.count("unseal", "opened")
+	b.logger.Infof("🔓 Sealed secret %s (v%d) released to %s/%s on %s", body.ObjectID, version, body.Namespace, body.Pod, body.NodeID)
+	w.Header().Set("Cache-Control", "no-store")
+	writeClientJSON(w, map[string]interface{}{"object_id": body.ObjectID, "version": version, "data": data})
+}
+
+// writeMetrics - 토큰 발급/복호화 결과
+func (b *SecretBroker) writeMetrics(w io.Writer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	writeMetricHeader(w, "nautilus_secret_requests_total", "counter", "Worker requests for external secret tokens and sealed secret decryption")
+	for _, kind := range []string{"token", "unseal"} {
+		for outcome, count := range b.requests[kind] {
+			writeMetric(w, "nautilus_secret_requests_total", map[string]string{"kind": kind, "outcome": outcome}, float64(count))
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Self Test - 시작 전 Contract/RPC/TEE/시계 검증으로 잘못된 설정을 즉시 알림
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// teeDevices - 지원하는 TEE 디바이스 경로
+var teeDevices = []string{
+	"/dev/nsm",         // AWS Nitro Enclaves
+	"/dev/sgx_enclave", // Intel SGX
+	"/dev/tdx_guest",   // Intel TDX
+	"/dev/sev-guest",   // AMD SEV-SNP
+}
+
+// teeDevice - 감지된 TEE 디바이스 경로 (없으면 빈 문자열)
+func teeDevice() string {
+	for _, path := range teeDevices {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// selfTestCheck - 개별 검증 항목
+type selfTestCheck struct {
+	name     string
+	needsRPC bool
+	run      func() *UserFriendlyError
+}
+
+// SelfTest - 시작 검증 실행기
+type SelfTest struct {
+	logger       *logrus.Logger
+	sui          *SuiIntegration
+	maxClockSkew time.Duration
+}
+
+// NewSelfTest - 새 Self Test 생성
+func NewSelfTest(logger *logrus.Logger, sui *SuiIntegration) *SelfTest {
+	return &SelfTest{
+		logger:       logger,
+		sui:          sui,
+		maxClockSkew: envSeconds("MAX_CLOCK_SKEW_SECONDS", 30),
+	}
+}
+
+// Run - 모든 검증 실행, 실패 항목이 있으면 첫 번째 오류 반환
+func (t *SelfTest) Run() error {
+	t.logger.Info("🧪 Running startup self-test...")
+
+	// Sui 외 백엔드(mock 등)에서는 Sui RPC 검증 생략
+	chainConfigured := t.sui.onSui()
+	if !chainConfigured {
+		t.logger.Warnf("⚠️ Using %s chain backend, skipping Sui on-chain checks", t.sui.backend.Name())
+	}
+
+	checks := []selfTestCheck{
+		{name: "Sui RPC health", needsRPC: true, run: t.checkRPC},
+		{name: "Contract package", needsRPC: true, run: t.checkContractPackage},
+		{name: "Worker registry object", needsRPC: true, run: func() *UserFriendlyError {
+			return t.checkObjectReadable("WORKER_REGISTRY_ID", t.sui.activeContract().Registry)
+		}},
+		{name: "Scheduler object", needsRPC: true, run: func() *UserFriendlyError {
+			return t.checkObjectReadable("K8S_SCHEDULER_ID", t.sui.activeContract().Scheduler)
+		}},
+		{name: "Clock skew vs chain", needsRPC: true, run: t.checkClockSkew},
+		{name: "TEE attestation", run: t.checkAttestation},
+	}
+
+	var firstErr *UserFriendlyError
+	for _, check := range checks {
+		if check.needsRPC && !chainConfigured {
+			continue
+		}
+
+		if err := check.run(); err != nil {
+			t.logger.Errorf("❌ %s: %v", check.name, err)
+			t.logger.Errorf("💡 %s", err.Solution)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		t.logger.Infof("✅ %s", check.name)
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	t.logger.Info("✅ Startup self-test passed")
+	return nil
+}
+
+// checkRPC - RPC 엔드포인트 응답 확인
+func (t *SelfTest) checkRPC() *UserFriendlyError {
+	var sequence string
+	if err := t.sui.rpcCall("sui_getLatestCheckpointSequenceNumber", []interface{}{}, &sequence); err != nil {
+		return NewUserFriendlyError(ErrCodeRPCUnreachable,
+			fmt.Sprintf("Sui RPC %s is not responding", t.sui.suiRPCURL),
+			"SUI_RPC_URL이 올바른 네트워크(testnet/mainnet)의 fullnode를 가리키는지 확인하세요", err)
+	}
+	return nil
+}
+
+// checkContractPackage - 패키지 존재 및 버전 확인
+func (t *SelfTest) checkContractPackage() *UserFriendlyError {
+	var response struct {
+		Data *struct {
+			Version string `json:"version"`
+			Type    string `json:"type"`
+		} `json:"data"`
+	}
+	packageID := t.sui.activeContract().Package
+	err := t.sui.rpcCall("sui_getObject", []interface{}{
+		packageID,
+		map[string]interface{}{"showType": true},
+	}, &response)
+	if err != nil || response.Data == nil || response.Data.Type != "package" {
+		return NewUserFriendlyError(ErrCodeContractNotFound,
+			fmt.Sprintf("Contract package %s not found on chain", packageID),
+			"CONTRACT_PACKAGE_ID가 현재 네트워크에 배포된 패키지 ID인지 확인하세요 (sui client publish 출력의 packageId)", err)
+	}
+
+	// 패키지 전환 중에는 CONTRACT_PACKAGE_VERSION이 이전 패키지 기준이므로 확인하지 않음
+	if expected := os.Getenv("CONTRACT_PACKAGE_VERSION"); expected != "" && packageID == t.sui.contractAddr && expected != response.Data.Version {
+		return NewUserFriendlyError(ErrCodeContractVersion,
+			fmt.Sprintf("Contract package version %s does not match expected %s", response.Data.Version, expected),
+			"업그레이드된 패키지 ID로 CONTRACT_PACKAGE_ID를 갱신하거나 CONTRACT_PACKAGE_VERSION을 수정하세요", nil)
+	}
+	return nil
+}
+
+// checkObjectReadable - 공유 오브젝트 조회 가능 여부
+func (t *SelfTest) checkObjectReadable(envName, objectID string) *UserFriendlyError {
+	var response struct {
+		Data *struct {
+			ObjectID string `json:"objectId"`
+		} `json:"data"`
+	}
+	err := t.sui.rpcCall("sui_getObject", []interface{}{objectID, map[string]interface{}{}}, &response)
+	if err != nil || response.Data == nil {
+		return NewUserFriendlyError(ErrCodeObjectUnreadable,
+			fmt.Sprintf("Object %s (%s) is not readable", objectID, envName),
+			fmt.Sprintf("%s가 패키지 배포 시 생성된 공유 오브젝트 ID인지 확인하세요", envName), err)
+	}
+	return nil
+}
+
+// checkClockSkew - 로컬 시계와 체인 체크포인트 시각 비교
+func (t *SelfTest) checkClockSkew() *UserFriendlyError {
+	chainNow, err := t.sui.chainTime()
+	if err != nil {
+		return NewUserFriendlyError(ErrCodeRPCUnreachable,
+			"Failed to read latest checkpoint timestamp",
+			"Sui RPC 상태를 확인하세요", err)
+	}
+
+	skew := time.Since(chainNow)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > t.maxClockSkew {
+		return NewUserFriendlyError(ErrCodeClockSkew,
+			fmt.Sprintf("Local clock differs from chain by %s (max %s)", skew.Round(time.Second), t.maxClockSkew),
+			"NTP 동기화(timedatectl set-ntp true 또는 chronyd)를 활성화하세요", nil)
+	}
+	return nil
+}
+
+// checkAttestation - TEE 디바이스 확인 및 Seal 토큰 생성/검증 왕복 테스트
+func (t *SelfTest) checkAttestation() *UserFriendlyError {
+	device := teeDevice()
+	if device == "" {
+		if getEnvOrDefault("TEE_REQUIRED", "false") == "true" {
+			return NewUserFriendlyError(ErrCodeAttestationFailed,
+				"No TEE device found but TEE_REQUIRED=true",
+				"Nitro Enclave/SGX/TDX/SEV-SNP가 활성화된 인스턴스에서 실행하거나 TEE_REQUIRED를 해제하세요", nil)
+		}
+		t.logger.Warn("⚠️ No TEE device found, running without hardware attestation")
+	} else {
+		t.logger.Infof("🔒 TEE device detected: %s", device)
+	}
+
+	sealTokenMgr := t.sui.sealTokenMgr
+	token, err := sealTokenMgr.GenerateRealSealToken("self-test", 0, "self-test")
+	if err != nil || !sealTokenMgr.ValidateSealToken(token, "self-test") {
+		return NewUserFriendlyError(ErrCodeAttestationFailed,
+			"Seal token generation/validation round trip failed",
+			"하드웨어 fingerprint 수집 권한과 난수 생성기(/dev/urandom)를 확인하세요", err)
+	}
+	return nil
+}
@@ -0,0 +1,613 @@
+// Service Accounts - 클러스터 안의 컨트롤러/오퍼레이터용 ServiceAccount와 TEE 키로 서명한 프로젝티드 토큰
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+Pod 안의 컨트롤러는 Sui 지갑도 Gateway 서명도 없으므로, 소유자(워커 Seal 토큰으로 인증한 지갑)가
+ServiceAccount를 만들고 마스터가 서명한 JWT를 발급받아 Pod에 넣습니다(Secret 또는 프로젝티드 볼륨).
+토큰으로 K8s API(/api/, /apis/)를 호출하면 마스터가 직접 검증하고 소유자 지갑으로 요청을 실행합니다.
+
+  - 서명 키: TEE 상태 디렉토리의 RSA 키 (JWT alg=RS256), 공개키는 /openid/v1/jwks와 /api/v1/serviceaccounts/jwks
+    (이전에 응답 서명 키로 발급한 EdDSA 토큰은 만료될 때까지 계속 검증)
+  - 발급자: OIDC 디스커버리 문서를 제공하므로 클라우드 워크로드 ID 연동에 그대로 사용 (oidc_discovery.go)
+  - 검증: 서명, 발급자, 만료, audience, 계정 존재/UID (계정을 지우면 발급된 토큰 모두 무효)
+  - 범위: 계정의 네임스페이스만 (cluster_scope 계정은 클러스터 범위 요청도 허용)
+
+	NAUTILUS_SA_ISSUER         토큰 iss (기본 NAUTILUS_PUBLIC_URL 또는 https://nautilus.k3s-daas.local)
+	NAUTILUS_SA_AUDIENCE       K8s API가 요구하는 audience (기본 nautilus)
+	NAUTILUS_SA_TOKEN_MAX_TTL  최대 유효 시간(초, 기본 86400)
+*/
+
+const (
+	serviceAccountUserPrefix     = "system:serviceaccount:"
+	serviceAccountHeader         = "X-Daas-Service-Account" // 프록시가 K3s로 전달하는 인증된 계정 (감사용)
+	defaultServiceAccountTTL     = time.Hour
+	minServiceAccountTTL         = 10 * time.Minute
+	serviceAccountClockLeeway    = 30 * time.Second
+	serviceAccountTokenAlgorithm = "RS256"
+	legacyTokenAlgorithm         = "EdDSA"
+)
+
+// 토큰 검증 실패 사유 (401로 응답)
+var (
+	errServiceAccountToken    = errors.New("invalid service account token")
+	errServiceAccountExpired  = errors.New("service account token expired")
+	errServiceAccountAudience = errors.New("service account token audience not accepted")
+	errServiceAccountRevoked  = errors.New("service account no longer exists")
+)
+
+// ServiceAccount - 소유자 지갑에 묶인 클러스터 내부 계정
+type ServiceAccount struct {
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	UID          string    `json:"uid"`
+	Owner        string    `json:"owner"`
+	ClusterScope bool      `json:"cluster_scope"` // 클러스터 범위/다른 네임스페이스 요청 허용
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// username - K8s와 같은 서비스 계정 사용자 이름
+func (sa *ServiceAccount) username() string {
+	return serviceAccountUserPrefix + sa.Namespace + ":" + sa.Name
+}
+
+// ServiceAccountToken - 발급한 토큰 (K8s TokenRequest status와 같은 필드)
+type ServiceAccountToken struct {
+	Token               string    `json:"token"`
+	ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	Audiences           []string  `json:"audiences"`
+}
+
+// serviceAccountClaims - JWT 본문 (kubernetes.io 클레임은 K8s 프로젝티드 토큰과 같은 형태)
+type serviceAccountClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  []string `json:"aud"`
+	IssuedAt  int64    `json:"iat"`
+	NotBefore int64    `json:"nbf"`
+	Expiry    int64    `json:"exp"`
+	ID        string   `json:"jti"`
+	Kube      struct {
+		Namespace      string `json:"namespace"`
+		ServiceAccount struct {
+			Name string `json:"name"`
+			UID  string `json:"uid"`
+		} `json:"serviceaccount"`
+	} `json:"kubernetes.io"`
+	Owner string `json:"daas.k3s.io/owner"`
+}
+
+// serviceAccountJWK - JWKS 항목 (RSA 또는 Ed25519 공개키)
+type serviceAccountJWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// ServiceAccountIssuer - 계정 저장소, 토큰 발급기, K8s API 인증기
+type ServiceAccountIssuer struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	workerPool *WorkerPool
+	rsaKey     EnclaveSigner
+	rsaPublic  *rsa.PublicKey
+	rsaKeyID   string
+	key        ed25519.PublicKey // 이전 EdDSA 토큰 검증용
+	keyID      string
+	issuer     string
+	audience   string
+	maxTTL     time.Duration
+	stateFile  string
+
+	mutex    sync.RWMutex
+	accounts map[string]*ServiceAccount // <namespace>/<name>
+	issued   int
+	accepted int
+	rejected int
+}
+
+// NewServiceAccountIssuer - 저장된 계정을 복원하고 봉인된 TEE 서명 키로 발급기 생성
+func NewServiceAccountIssuer(logger *logrus.Logger, k3sMgr *K3sManager, signer *RequestSigner, keyring *EnclaveKeyring) (*ServiceAccountIssuer, error) {
+	maxTTL := 24 * time.Hour
+	if seconds, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_SA_TOKEN_MAX_TTL", "86400")); err == nil && seconds > 0 {
+		maxTTL = time.Duration(seconds) * time.Second
+	}
+	if maxTTL < minServiceAccountTTL {
+		maxTTL = minServiceAccountTTL
+	}
+
+	rsaKey, err := keyring.Load(serviceAccountSigningKey())
+	if err != nil {
+		return nil, err
+	}
+	rsaPublic := rsaKey.Public().(*rsa.PublicKey)
+	public := signer.publicKey()
+	digest := sha256.Sum256(public)
+	s := &ServiceAccountIssuer{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		workerPool: k3sMgr.workerPool,
+		rsaKey:     rsaKey,
+		rsaPublic:  rsaPublic,
+		rsaKeyID:   rsaKeyID(rsaPublic),
+		key:        public,
+		keyID:      hex.EncodeToString(digest[:8]),
+		issuer:     serviceAccountIssuerURL(),
+		audience:   getEnvOrDefault("NAUTILUS_SA_AUDIENCE", "nautilus"),
+		maxTTL:     maxTTL,
+		stateFile:  statePath("service-accounts.json"),
+		accounts:   make(map[string]*ServiceAccount),
+	}
+
+	var accounts []*ServiceAccount
+	if found, err := loadJSONState(s.stateFile, &accounts); err != nil {
+		logger.Warnf("⚠️ Failed to load service accounts: %v", err)
+	} else if found {
+		for _, account := range accounts {
+			s.accounts[account.Namespace+"/"+account.Name] = account
+		}
+		logger.Infof("🪪 Loaded %d service accounts", len(accounts))
+	}
+	if err := checkIssuerURL(s.issuer); err != nil {
+		logger.Warnf("⚠️ Service account tokens cannot be federated with cloud providers: %v", err)
+	}
+	logger.Infof("🪪 Service account issuer %s (OIDC discovery at %s)", s.issuer, s.discoveryPath())
+	return s, nil
+}
+
+// Create - 계정 생성 (같은 소유자의 재생성은 기존 계정 반환)
+func (s *ServiceAccountIssuer) Create(owner, namespace, name string, clusterScope bool) (*ServiceAccount, bool, error) {
+	if !podLogNamePattern.MatchString(namespace) || !podLogNamePattern.MatchString(name) {
+		return nil, false, fmt.Errorf("namespace and name must be DNS names")
+	}
+
+	s.mutex.Lock()
+	key := namespace + "/" + name
+	if existing, ok := s.accounts[key]; ok {
+		s.mutex.Unlock()
+		if !strings.EqualFold(existing.Owner, owner) {
+			return nil, false, fmt.Errorf("service account %s is owned by another wallet", key)
+		}
+		return existing, false, nil
+	}
+
+	uid := make([]byte, 16)
+	rand.Read(uid)
+	account := &ServiceAccount{
+		Namespace:    namespace,
+		Name:         name,
+		UID:          fmt.Sprintf("%x-%x-%x-%x-%x", uid[0:4], uid[4:6], uid[6:8], uid[8:10], uid[10:]),
+		Owner:        owner,
+		ClusterScope: clusterScope,
+		CreatedAt:    time.Now(),
+	}
+	s.accounts[key] = account
+	err := s.persistLocked()
+	s.mutex.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.logger.Infof("🪪 Service account %s created for %s", key, owner)
+	s.mirror("create", account)
+	return account, true, nil
+}
+
+// Delete - 계정 삭제 (UID가 사라지므로 발급된 토큰은 즉시 거부)
+func (s *ServiceAccountIssuer) Delete(owner, namespace, name string) error {
+	s.mutex.Lock()
+	key := namespace + "/" + name
+	account, ok := s.accounts[key]
+	if !ok || !strings.EqualFold(account.Owner, owner) {
+		s.mutex.Unlock()
+		return fmt.Errorf("service account %s not found", key)
+	}
+	delete(s.accounts, key)
+	err := s.persistLocked()
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.logger.Infof("🗑️ Service account %s deleted, outstanding tokens revoked", key)
+	s.mirror("delete", account)
+	return nil
+}
+
+// List - 소유자의 계정 목록
+func (s *ServiceAccountIssuer) List(owner string) []*ServiceAccount {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	accounts := []*ServiceAccount{}
+	for _, account := range s.accounts {
+		if strings.EqualFold(account.Owner, owner) {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Namespace+"/"+accounts[i].Name < accounts[j].Namespace+"/"+accounts[j].Name
+	})
+	return accounts
+}
+
+func (s *ServiceAccountIssuer) get(namespace, name string) (*ServiceAccount, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	account, ok := s.accounts[namespace+"/"+name]
+	return account, ok
+}
+
+// persistLocked - 계정 목록 저장 (mutex 보유 상태에서 호출)
+func (s *ServiceAccountIssuer) persistLocked() error {
+	accounts := make([]*ServiceAccount, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		accounts = append(accounts, account)
+	}
+	return saveJSONState(s.stateFile, accounts)
+}
+
+// mirror - K3s에도 같은 이름의 ServiceAccount를 만들거나 지움 (kubectl get sa 표시용, 실패해도 계속)
+func (s *ServiceAccountIssuer) mirror(action string, account *ServiceAccount) {
+	if s.k3sMgr == nil || !s.k3sMgr.IsRunning() {
+		return
+	}
+	args := []string{"create", "serviceaccount", account.Name, "-n", account.Namespace}
+	if action == "delete" {
+		args = []string{"delete", "serviceaccount", account.Name, "-n", account.Namespace, "--ignore-not-found"}
+	}
+	if _, err := s.k3sMgr.RunKubectl(nil, args...); err != nil && !strings.Contains(err.Error(), "AlreadyExists") {
+		s.logger.Warnf("⚠️ Failed to %s service account %s/%s in K3s: %v", action, account.Namespace, account.Name, err)
+	}
+}
+
+// IssueToken - 계정 토큰 발급 (audience 미지정 시 API audience, 유효 시간은 10분~최대값)
+func (s *ServiceAccountIssuer) IssueToken(account *ServiceAccount, audiences []string, ttl time.Duration) (*ServiceAccountToken, error) {
+	if len(audiences) == 0 {
+		audiences = []string{s.audience}
+	}
+	if ttl <= 0 {
+		ttl = defaultServiceAccountTTL
+	}
+	if ttl < minServiceAccountTTL {
+		ttl = minServiceAccountTTL
+	}
+	if ttl > s.maxTTL {
+		ttl = s.maxTTL
+	}
+
+	now := time.Now()
+	jti := make([]byte, 12)
+	rand.Read(jti)
+	claims := serviceAccountClaims{
+		Issuer:    s.issuer,
+		Subject:   account.username(),
+		Audience:  audiences,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		Expiry:    now.Add(ttl).Unix(),
+		ID:        hex.EncodeToString(jti),
+		Owner:     account.Owner,
+	}
+	claims.Kube.Namespace = account.Namespace
+	claims.Kube.ServiceAccount.Name = account.Name
+	claims.Kube.ServiceAccount.UID = account.UID
+
+	header, _ := json.Marshal(map[string]string{"alg": serviceAccountTokenAlgorithm, "typ": "JWT", "kid": s.rsaKeyID})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := s.rsaKey.Sign([]byte(signingInput))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	s.issued++
+	s.mutex.Unlock()
+
+	return &ServiceAccountToken{
+		Token:               signingInput + "." + base64.RawURLEncoding.EncodeToString(signature),
+		ExpirationTimestamp: time.Unix(claims.Expiry, 0).UTC(),
+		Audiences:           audiences,
+	}, nil
+}
+
+// Authenticate - 토큰 검증 후 계정 반환 (audience가 비어 있으면 검사하지 않음)
+func (s *ServiceAccountIssuer) Authenticate(token, audience string) (*ServiceAccount, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errServiceAccountToken
+	}
+	rawHeader, err1 := base64.RawURLEncoding.DecodeString(parts[0])
+	rawPayload, err2 := base64.RawURLEncoding.DecodeString(parts[1])
+	signature, err3 := base64.RawURLEncoding.DecodeString(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, errServiceAccountToken
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if json.Unmarshal(rawHeader, &header) != nil {
+		return nil, errServiceAccountToken
+	}
+	signingInput := []byte(parts[0] + "." + parts[1])
+	switch {
+	case header.Algorithm == serviceAccountTokenAlgorithm && header.KeyID == s.rsaKeyID:
+		hashed := sha256.Sum256(signingInput)
+		if rsa.VerifyPKCS1v15(s.rsaPublic, crypto.SHA256, hashed[:], signature) != nil {
+			return nil, errServiceAccountToken
+		}
+	case header.Algorithm == legacyTokenAlgorithm && header.KeyID == s.keyID:
+		if !ed25519.Verify(s.key, signingInput, signature) {
+			return nil, errServiceAccountToken
+		}
+	default:
+		return nil, errServiceAccountToken
+	}
+
+	var claims serviceAccountClaims
+	if json.Unmarshal(rawPayload, &claims) != nil || claims.Issuer != s.issuer {
+		return nil, errServiceAccountToken
+	}
+	now := time.Now()
+	if now.After(time.Unix(claims.Expiry, 0).Add(serviceAccountClockLeeway)) ||
+		now.Add(serviceAccountClockLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, errServiceAccountExpired
+	}
+	if audience != "" && !containsString(claims.Audience, audience) {
+		return nil, errServiceAccountAudience
+	}
+
+	account, ok := s.get(claims.Kube.Namespace, claims.Kube.ServiceAccount.Name)
+	if !ok || account.UID != claims.Kube.ServiceAccount.UID || claims.Subject != account.username() {
+		return nil, errServiceAccountRevoked
+	}
+	return account, nil
+}
+
+// bearerJWT - Authorization: Bearer 값이 JWT 형태면 반환 (Seal 토큰 등 다른 Bearer는 빈 문자열)
+func bearerJWT(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == r.Header.Get("Authorization") || strings.Count(token, ".") != 2 || !strings.HasPrefix(token, "eyJ") {
+		return ""
+	}
+	return token
+}
+
+/*
+Middleware - 서비스 계정 토큰 요청은 inCluster로, 나머지는 gateway(서명 검증 경로)로 보냄
+검증에 성공하면 Impersonate-User를 소유자 지갑으로 덮어써 테넌트 제한이 소유자 기준으로 적용되고,
+토큰은 K3s로 전달하지 않습니다.
+*/
+func (s *ServiceAccountIssuer) Middleware(inCluster, gateway http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerJWT(r)
+		if token == "" {
+			gateway.ServeHTTP(w, r)
+			return
+		}
+
+		account, err := s.Authenticate(token, s.audience)
+		if err != nil {
+			s.count(false)
+			s.logger.Warnf("🚫 Rejected service account token from %s: %v", r.RemoteAddr, err)
+			writeK8sStatus(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+			return
+		}
+		if namespace := k8sPathNamespace(r.URL.Path); !account.ClusterScope && namespace != account.Namespace {
+			s.count(false)
+			message := fmt.Sprintf("%s may only access namespace %s", account.username(), account.Namespace)
+			s.logger.Warnf("🚫 %s (requested %s %s)", message, r.Method, r.URL.Path)
+			writeK8sStatus(w, http.StatusForbidden, "Forbidden", message)
+			return
+		}
+		s.count(true)
+
+		r.Header.Del("Authorization")
+		r.Header.Del("Impersonate-Group")
+		r.Header.Set("Impersonate-User", account.Owner)
+		r.Header.Set(serviceAccountHeader, account.username())
+		inCluster.ServeHTTP(w, r)
+	})
+}
+
+func (s *ServiceAccountIssuer) count(accepted bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if accepted {
+		s.accepted++
+	} else {
+		s.rejected++
+	}
+}
+
+// k8sPathNamespace - /api/v1/namespaces/<ns>/... 또는 /apis/<g>/<v>/namespaces/<ns>/... 의 네임스페이스
+// (네임스페이스 객체 자체 /api/v1/namespaces/<ns>는 클러스터 범위로 취급)
+func k8sPathNamespace(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "namespaces" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+/*
+handleServiceAccounts - 서비스 계정 관리 (소유자는 워커 Seal 토큰으로 인증)
+
+	GET    /api/v1/serviceaccounts                          소유 계정 목록
+	POST   /api/v1/serviceaccounts                          {namespace, name, cluster_scope}
+	DELETE /api/v1/serviceaccounts?namespace=&name=         삭제 (발급된 토큰 무효)
+*/
+func (s *ServiceAccountIssuer) handleServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	owner, ok := s.workerPool.OwnerBySealToken(r.Header.Get("X-Seal-Token"))
+	if !ok {
+		http.Error(w, "Invalid or missing seal token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeClientJSON(w, s.List(owner))
+
+	case http.MethodPost:
+		var body struct {
+			Namespace    string `json:"namespace"`
+			Name         string `json:"name"`
+			ClusterScope bool   `json:"cluster_scope"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid service account payload", http.StatusBadRequest)
+			return
+		}
+		account, created, err := s.Create(owner, body.Namespace, body.Name, body.ClusterScope)
+		if err != nil {
+			status := http.StatusBadRequest
+			if strings.Contains(err.Error(), "another wallet") {
+				status = http.StatusConflict
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": account})
+
+	case http.MethodDelete:
+		if err := s.Delete(owner, r.URL.Query().Get("namespace"), r.URL.Query().Get("name")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+/*
+handleToken - 토큰 발급 (POST /api/v1/serviceaccounts/token)
+소유자의 Seal 토큰, 또는 같은 계정의 유효한 토큰(만료 전 갱신)으로 호출합니다.
+*/
+func (s *ServiceAccountIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Namespace         string   `json:"namespace"`
+		Name              string   `json:"name"`
+		Audiences         []string `json:"audiences"`
+		ExpirationSeconds int64    `json:"expiration_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid token request", http.StatusBadRequest)
+		return
+	}
+
+	var account *ServiceAccount
+	if token := bearerJWT(r); token != "" {
+		// 자기 갱신: 토큰이 가리키는 계정으로만 발급 (audience 무관)
+		current, err := s.Authenticate(token, "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if body.Namespace != current.Namespace || body.Name != current.Name {
+			http.Error(w, "service account tokens can only renew themselves", http.StatusForbidden)
+			return
+		}
+		account = current
+	} else {
+		owner, ok := s.workerPool.OwnerBySealToken(r.Header.Get("X-Seal-Token"))
+		if !ok {
+			http.Error(w, "Invalid or missing seal token", http.StatusUnauthorized)
+			return
+		}
+		existing, found := s.get(body.Namespace, body.Name)
+		if !found || !strings.EqualFold(existing.Owner, owner) {
+			http.Error(w, "Service account not found", http.StatusNotFound)
+			return
+		}
+		account = existing
+	}
+
+	token, err := s.IssueToken(account, body.Audiences, time.Duration(body.ExpirationSeconds)*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": token})
+}
+
+// handleJWKS - 토큰 검증용 공개키 (GET /api/v1/serviceaccounts/jwks, 인증 없음, 이전 EdDSA 키 포함)
+func (s *ServiceAccountIssuer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer": s.issuer,
+		"keys": []serviceAccountJWK{
+			rsaJWK(s.rsaPublic, s.rsaKeyID),
+			{
+				KeyType: "OKP", Curve: "Ed25519", Use: "sig", Algorithm: legacyTokenAlgorithm, KeyID: s.keyID,
+				X: base64.RawURLEncoding.EncodeToString(s.key),
+			},
+		},
+	})
+}
+
+// writeMetrics - 계정 수, 발급/인증 결과
+func (s *ServiceAccountIssuer) writeMetrics(w io.Writer) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	writeMetricHeader(w, "nautilus_service_accounts", "gauge", "Service accounts registered by tenants")
+	writeMetric(w, "nautilus_service_accounts", nil, float64(len(s.accounts)))
+	writeMetricHeader(w, "nautilus_service_account_tokens_issued_total", "counter", "Service account tokens issued")
+	writeMetric(w, "nautilus_service_account_tokens_issued_total", nil, float64(s.issued))
+	writeMetricHeader(w, "nautilus_service_account_authentications_total", "counter", "K8s API requests authenticated with service account tokens")
+	writeMetric(w, "nautilus_service_account_authentications_total", map[string]string{"outcome": "accepted"}, float64(s.accepted))
+	writeMetric(w, "nautilus_service_account_authentications_total", map[string]string{"outcome": "rejected"}, float64(s.rejected))
+}
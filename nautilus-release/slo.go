@@ -0,0 +1,404 @@
+// SLO Tracker - 컨트롤 플레인 SLI(kubectl 경로, 이벤트 파이프라인) 기록과 오류 예산 계산
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+마스터가 SLO를 지키는지 분 단위 버킷으로 집계합니다 (메모리에만 보관, 재시작하면 초기화).
+
+	SLI      기록 지점                                           나쁜 요청
+	kubectl  /api/, /apis/ 프록시 (watch, exec/attach/logs -f 제외)   5xx 응답 (429는 테넌트 한도라 제외)
+	events   컨트랙트 이벤트 처리                                   처리 실패로 dead-letter 큐에 들어간 이벤트
+
+SLO마다 가용성(나쁜 요청 비율) 또는 지연(임계값을 넘긴 비율)을 목표와 비교합니다.
+
+	NAUTILUS_SLO_WINDOW_DAYS             오류 예산 기간 (기본 30)
+	NAUTILUS_SLO_KUBECTL_AVAILABILITY    kubectl 가용성 목표 % (기본 99.5)
+	NAUTILUS_SLO_KUBECTL_LATENCY         kubectl 지연 목표 % (기본 99)
+	NAUTILUS_SLO_KUBECTL_LATENCY_MS      kubectl 지연 임계값 (기본 1000)
+	NAUTILUS_SLO_EVENTS_AVAILABILITY     이벤트 처리 성공 목표 % (기본 99.9)
+	NAUTILUS_SLO_EVENTS_LATENCY          이벤트 지연 목표 % (기본 99)
+	NAUTILUS_SLO_EVENTS_LATENCY_MS       체인 타임스탬프부터 처리 완료까지 임계값 (기본 30000, 확정 대기 포함)
+
+소진 판단은 다중 윈도 번 레이트를 씁니다: 1시간과 5분이 모두 14.4배 이상(빠른 소진)이거나
+6시간과 30분이 모두 6배 이상(느린 소진)이면 burning. NAUTILUS_SLO_PAUSE_BACKGROUND=true이면
+burning 동안 레지스트리 캐시 정리, Pod 로그 만료, 보관 정책 적용 같은 급하지 않은 작업을 미룹니다.
+*/
+
+const (
+	SLIKubectl = "kubectl"
+	SLIEvents  = "events"
+
+	sloAvailability = "availability"
+	sloLatency      = "latency"
+)
+
+// sloBurnWindows - 번 레이트 계산 윈도 (짧은 윈도는 긴 윈도의 소진이 아직 진행 중인지 확인용)
+var sloBurnWindows = []struct {
+	long      time.Duration
+	short     time.Duration
+	threshold float64
+}{
+	{time.Hour, 5 * time.Minute, 14.4},
+	{6 * time.Hour, 30 * time.Minute, 6},
+}
+
+// SLObjective - SLI 하나에 대한 목표
+type SLObjective struct {
+	Name        string  `json:"name"`
+	SLI         string  `json:"sli"`
+	Kind        string  `json:"kind"`
+	Target      float64 `json:"target"` // 0.995
+	ThresholdMs int64   `json:"threshold_ms,omitempty"`
+}
+
+// SLOStatus - 목표별 현재 상태
+type SLOStatus struct {
+	SLObjective
+	WindowHours     int                `json:"window_hours"`
+	Total           uint64             `json:"total"`
+	Bad             uint64             `json:"bad"`
+	Compliance      float64            `json:"compliance"`       // 기간 내 좋은 요청 비율 (요청이 없으면 1)
+	BudgetRemaining float64            `json:"budget_remaining"` // 남은 오류 예산 비율 (초과하면 음수)
+	BurnRates       map[string]float64 `json:"burn_rates"`       // "1h", "5m", "6h", "30m"
+	Burning         bool               `json:"burning"`
+}
+
+// sloBucket - 1분 동안의 SLI 집계
+type sloBucket struct {
+	minute int64
+	total  uint64
+	failed uint64
+	slow   map[int64]uint64 // 지연 임계값별 초과 수
+}
+
+// SLOTracker - SLI 기록기와 오류 예산 계산기
+type SLOTracker struct {
+	logger          *logrus.Logger
+	objectives      []SLObjective
+	window          time.Duration
+	pauseBackground bool
+
+	mutex    sync.Mutex
+	buckets  map[string][]sloBucket // SLI별 분 단위 링 버퍼
+	deferred map[string]uint64      // 미룬 백그라운드 작업 수
+	burning  bool
+}
+
+// NewSLOTracker - 환경변수로 목표를 설정해 생성
+func NewSLOTracker(logger *logrus.Logger) *SLOTracker {
+	days, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_SLO_WINDOW_DAYS", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	objective := func(sli, kind, targetEnv string, target float64, thresholdEnv string, thresholdMs int64) SLObjective {
+		if value, err := strconv.ParseFloat(os.Getenv(targetEnv), 64); err == nil && value > 0 && value < 100 {
+			target = value
+		}
+		if thresholdEnv != "" {
+			if value, err := strconv.ParseInt(os.Getenv(thresholdEnv), 10, 64); err == nil && value > 0 {
+				thresholdMs = value
+			}
+		}
+		return SLObjective{Name: sli + "_" + kind, SLI: sli, Kind: kind, Target: target / 100, ThresholdMs: thresholdMs}
+	}
+
+	t := &SLOTracker{
+		logger: logger,
+		objectives: []SLObjective{
+			objective(SLIKubectl, sloAvailability, "NAUTILUS_SLO_KUBECTL_AVAILABILITY", 99.5, "", 0),
+			objective(SLIKubectl, sloLatency, "NAUTILUS_SLO_KUBECTL_LATENCY", 99, "NAUTILUS_SLO_KUBECTL_LATENCY_MS", 1000),
+			objective(SLIEvents, sloAvailability, "NAUTILUS_SLO_EVENTS_AVAILABILITY", 99.9, "", 0),
+			objective(SLIEvents, sloLatency, "NAUTILUS_SLO_EVENTS_LATENCY", 99, "NAUTILUS_SLO_EVENTS_LATENCY_MS", 30000),
+		},
+		window:          time.Duration(days) * 24 * time.Hour,
+		pauseBackground: getEnvOrDefault("NAUTILUS_SLO_PAUSE_BACKGROUND", "false") == "true",
+		buckets:         make(map[string][]sloBucket),
+		deferred:        make(map[string]uint64),
+	}
+	for _, sli := range []string{SLIKubectl, SLIEvents} {
+		t.buckets[sli] = make([]sloBucket, int(t.window/time.Minute))
+	}
+	return t
+}
+
+// Record - SLI 하나의 결과 기록 (failed면 가용성, latency가 임계값을 넘으면 지연 SLO의 나쁜 요청)
+func (t *SLOTracker) Record(sli string, latency time.Duration, failed bool) {
+	now := time.Now()
+	minute := now.Unix() / 60
+
+	t.mutex.Lock()
+	ring, ok := t.buckets[sli]
+	if !ok {
+		t.mutex.Unlock()
+		return
+	}
+	bucket := &ring[minute%int64(len(ring))]
+	if bucket.minute != minute {
+		*bucket = sloBucket{minute: minute, slow: make(map[int64]uint64)}
+	}
+	bucket.total++
+	if failed {
+		bucket.failed++
+	}
+	for _, objective := range t.objectives {
+		if objective.SLI == sli && objective.Kind == sloLatency && latency > time.Duration(objective.ThresholdMs)*time.Millisecond {
+			bucket.slow[objective.ThresholdMs]++
+		}
+	}
+	t.mutex.Unlock()
+}
+
+// countLocked - 최근 span 동안 목표의 전체/나쁜 요청 수
+func (t *SLOTracker) countLocked(objective SLObjective, span time.Duration, now time.Time) (uint64, uint64) {
+	ring := t.buckets[objective.SLI]
+	current := now.Unix() / 60
+	minutes := int64(span / time.Minute)
+	if minutes > int64(len(ring)) {
+		minutes = int64(len(ring))
+	}
+	var total, bad uint64
+	for minute := current; minute > current-minutes; minute-- {
+		bucket := &ring[minute%int64(len(ring))]
+		if bucket.minute != minute || bucket.total == 0 {
+			continue
+		}
+		total += bucket.total
+		if objective.Kind == sloAvailability {
+			bad += bucket.failed
+		} else {
+			bad += bucket.slow[objective.ThresholdMs]
+		}
+	}
+	return total, bad
+}
+
+// burnRate - 오류 비율 / 허용 오류 비율 (1이면 기간 끝에 예산을 딱 소진)
+func burnRate(total, bad uint64, target float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - target)
+}
+
+func formatWindow(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
+
+// Status - 목표별 준수율, 남은 예산, 번 레이트
+func (t *SLOTracker) Status() []SLOStatus {
+	now := time.Now()
+
+	t.mutex.Lock()
+	statuses := make([]SLOStatus, 0, len(t.objectives))
+	anyBurning := false
+	for _, objective := range t.objectives {
+		total, bad := t.countLocked(objective, t.window, now)
+		status := SLOStatus{
+			SLObjective:     objective,
+			WindowHours:     int(t.window / time.Hour),
+			Total:           total,
+			Bad:             bad,
+			Compliance:      1,
+			BudgetRemaining: 1,
+			BurnRates:       make(map[string]float64),
+		}
+		if total > 0 {
+			status.Compliance = 1 - float64(bad)/float64(total)
+			status.BudgetRemaining = 1 - burnRate(total, bad, objective.Target)
+		}
+		for _, window := range sloBurnWindows {
+			longTotal, longBad := t.countLocked(objective, window.long, now)
+			shortTotal, shortBad := t.countLocked(objective, window.short, now)
+			longRate := burnRate(longTotal, longBad, objective.Target)
+			shortRate := burnRate(shortTotal, shortBad, objective.Target)
+			status.BurnRates[formatWindow(window.long)] = longRate
+			status.BurnRates[formatWindow(window.short)] = shortRate
+			if longRate >= window.threshold && shortRate >= window.threshold {
+				status.Burning = true
+			}
+		}
+		anyBurning = anyBurning || status.Burning
+		statuses = append(statuses, status)
+	}
+	changed := anyBurning != t.burning
+	t.burning = anyBurning
+	t.mutex.Unlock()
+
+	if changed && anyBurning {
+		t.logger.Warnf("🔥 Error budget burning: %s", strings.Join(burningNames(statuses), ", "))
+	} else if changed {
+		t.logger.Info("✅ Error budget burn back within limits")
+	}
+	return statuses
+}
+
+func burningNames(statuses []SLOStatus) []string {
+	var names []string
+	for _, status := range statuses {
+		if status.Burning {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+/*
+Defer - 급하지 않은 백그라운드 작업을 이번 주기에 건너뛸지
+NAUTILUS_SLO_PAUSE_BACKGROUND=true이고 오류 예산이 빠르게 소진 중일 때만 true입니다.
+*/
+func (t *SLOTracker) Defer(task string) bool {
+	if !t.pauseBackground {
+		return false
+	}
+	burning := false
+	for _, status := range t.Status() {
+		burning = burning || status.Burning
+	}
+	if !burning {
+		return false
+	}
+
+	t.mutex.Lock()
+	t.deferred[task]++
+	t.mutex.Unlock()
+	t.logger.Infof("⏸️ Deferring %s while the error budget is burning", task)
+	return true
+}
+
+// Middleware - K8s API 프록시 요청을 kubectl SLI로 기록
+func (t *SLOTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isLongRunningRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		recorder := &sloRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+		t.Record(SLIKubectl, time.Since(start), recorder.status >= 500)
+	})
+}
+
+// isLongRunningRequest - 응답 시간이 클라이언트에 달린 요청 (watch, 로그 follow, exec/attach/port-forward)
+func isLongRunningRequest(r *http.Request) bool {
+	if isWatchRequest(r) || r.URL.Query().Get("follow") == "true" {
+		return true
+	}
+	if strings.EqualFold(r.Header.Get("Connection"), "upgrade") {
+		return true
+	}
+	for _, suffix := range []string{"/exec", "/attach", "/portforward", "/proxy"} {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sloRecorder - 응답 상태 코드 기록
+type sloRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *sloRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *sloRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *sloRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// RecordEvent - 컨트랙트 이벤트 처리 결과 기록 (지연은 체인 타임스탬프 기준)
+func (t *SLOTracker) RecordEvent(event *SuiContractEvent, failed bool) {
+	var latency time.Duration
+	if event.Timestamp > 0 {
+		latency = time.Since(time.UnixMilli(event.Timestamp))
+		if latency < 0 {
+			latency = 0
+		}
+	}
+	t.Record(SLIEvents, latency, failed)
+}
+
+// handleSLO - 목표별 오류 예산 상태 (/api/v1/slo)
+func (t *SLOTracker) handleSLO(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, t.Status())
+}
+
+// writeMetrics - 목표, 준수율, 남은 예산, 번 레이트, 미룬 작업 수
+func (t *SLOTracker) writeMetrics(w io.Writer) {
+	statuses := t.Status()
+
+	writeMetricHeader(w, "nautilus_slo_target", "gauge", "SLO target ratio")
+	for _, status := range statuses {
+		writeMetric(w, "nautilus_slo_target", map[string]string{"slo": status.Name}, status.Target)
+	}
+	writeMetricHeader(w, "nautilus_slo_requests_total", "gauge", "Requests counted in the SLO window by result")
+	for _, status := range statuses {
+		writeMetric(w, "nautilus_slo_requests_total", map[string]string{"slo": status.Name, "result": "good"}, float64(status.Total-status.Bad))
+		writeMetric(w, "nautilus_slo_requests_total", map[string]string{"slo": status.Name, "result": "bad"}, float64(status.Bad))
+	}
+	writeMetricHeader(w, "nautilus_slo_error_budget_remaining", "gauge", "Fraction of the error budget left in the SLO window (negative when exceeded)")
+	for _, status := range statuses {
+		writeMetric(w, "nautilus_slo_error_budget_remaining", map[string]string{"slo": status.Name}, status.BudgetRemaining)
+	}
+	writeMetricHeader(w, "nautilus_slo_burn_rate", "gauge", "Error budget burn rate per window (1 = budget exhausted exactly at window end)")
+	for _, status := range statuses {
+		windows := make([]string, 0, len(status.BurnRates))
+		for window := range status.BurnRates {
+			windows = append(windows, window)
+		}
+		sort.Strings(windows)
+		for _, window := range windows {
+			writeMetric(w, "nautilus_slo_burn_rate", map[string]string{"slo": status.Name, "window": window}, status.BurnRates[window])
+		}
+	}
+	writeMetricHeader(w, "nautilus_slo_burning", "gauge", "1 while the multi-window burn rate alert condition holds")
+	for _, status := range statuses {
+		burning := 0.0
+		if status.Burning {
+			burning = 1
+		}
+		writeMetric(w, "nautilus_slo_burning", map[string]string{"slo": status.Name}, burning)
+	}
+
+	t.mutex.Lock()
+	tasks := make([]string, 0, len(t.deferred))
+	for task := range t.deferred {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	writeMetricHeader(w, "nautilus_slo_background_deferred_total", "counter", "Background task runs skipped while the error budget was burning")
+	for _, task := range tasks {
+		writeMetric(w, "nautilus_slo_background_deferred_total", map[string]string{"task": task}, float64(t.deferred[task]))
+	}
+	t.mutex.Unlock()
+}
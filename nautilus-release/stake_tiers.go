@@ -0,0 +1,53 @@
+// Stake Tiers - 노드 역할별 최소 스테이킹 (worker_registry.move의 티어와 동일)
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	NodeRoleWorker  = "worker"
+	NodeRoleEdge    = "edge"
+	NodeRoleStorage = "storage"
+
+	nodeRoleLabel = "k3s-daas.io/node-role"
+	nodeRoleAnnot = "k3s-daas.io/node-role"
+
+	// stakeTierLabel - 현재 스테이킹 양이 충족하는 가장 높은 티어 (추가 예치/부분 인출 시 갱신)
+	stakeTierLabel = "k3s-daas.io/stake-tier"
+)
+
+// stakeTierMultipliers - MIN_STAKE_AMOUNT 대비 역할별 배수
+var stakeTierMultipliers = map[string]uint64{
+	NodeRoleWorker:  1,
+	NodeRoleEdge:    2,
+	NodeRoleStorage: 10,
+}
+
+// requiredStakeForRole - 역할에 필요한 최소 스테이킹 (MIST)
+func requiredStakeForRole(role string) (uint64, error) {
+	multiplier, ok := stakeTierMultipliers[role]
+	if !ok {
+		return 0, fmt.Errorf("unknown node role %q", role)
+	}
+
+	base, err := strconv.ParseUint(getEnvOrDefault("MIN_STAKE_AMOUNT", "1000000"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MIN_STAKE_AMOUNT: %v", err)
+	}
+	return base * multiplier, nil
+}
+
+// stakeTierFor - 스테이킹 양이 충족하는 가장 높은 티어 (어느 티어에도 못 미치면 빈 문자열)
+func stakeTierFor(amount uint64) string {
+	tier := ""
+	var best uint64
+	for role := range stakeTierMultipliers {
+		required, err := requiredStakeForRole(role)
+		if err == nil && amount >= required && required >= best {
+			tier, best = role, required
+		}
+	}
+	return tier
+}
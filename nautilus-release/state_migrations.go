@@ -0,0 +1,284 @@
+// State Migrations - 상태 저장소 스키마 버전 및 순차 마이그레이션
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const schemaMarkerFile = "schema-version.json"
+
+/*
+stateMigration - 상태 파일 형식 변경 하나
+
+Apply는 stateDir의 파일을 새 형식으로 바꾸고 변경 내용을 한 줄씩 반환합니다.
+dryRun이면 파일을 쓰지 않고 바꿀 내용만 반환해야 합니다.
+한 번 배포된 마이그레이션은 수정하지 말고 새 버전을 뒤에 추가합니다.
+*/
+type stateMigration struct {
+	Version int
+	Name    string
+	Apply   func(dir string, dryRun bool) ([]string, error)
+}
+
+// stateMigrations - 버전 순서대로 적용 (마지막 버전이 현재 바이너리의 스키마)
+var stateMigrations = []stateMigration{
+	{Version: 1, Name: "baseline: schema marker for existing state files", Apply: func(string, bool) ([]string, error) { return nil, nil }},
+	{Version: 2, Name: "watch bookmarks keyed by canonical collection path", Apply: migrateBookmarkKeys},
+}
+
+// schemaMarker - 상태 디렉토리의 스키마 버전 기록
+type schemaMarker struct {
+	Version   int                `json:"version"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Applied   []appliedMigration `json:"applied,omitempty"`
+}
+
+type appliedMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+	Backup    string    `json:"backup,omitempty"`
+}
+
+func latestSchemaVersion() int {
+	return stateMigrations[len(stateMigrations)-1].Version
+}
+
+/*
+readSchemaVersion - 현재 상태 디렉토리의 스키마 버전
+마커가 없을 때 상태 파일도 없으면 새 설치이므로 최신 버전으로 보고, 파일이 있으면 마커 도입 이전(0)입니다.
+*/
+func readSchemaVersion(dir string) (*schemaMarker, bool, error) {
+	var marker schemaMarker
+	found, err := loadJSONState(filepath.Join(dir, schemaMarkerFile), &marker)
+	if err != nil || found {
+		return &marker, found, err
+	}
+	files, err := stateFiles(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(files) == 0 {
+		marker.Version = latestSchemaVersion()
+	}
+	return &marker, false, nil
+}
+
+// stateFiles - 백업/마이그레이션 대상 상태 파일 (하위 디렉토리와 임시 파일 제외)
+func stateFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state directory: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && !strings.HasSuffix(entry.Name(), ".tmp") && entry.Name() != schemaMarkerFile {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
+
+// backupState - 마이그레이션 전 상태 파일 복사 (backups/schema-v<from>-<시각>/)
+func backupState(dir string, from int) (string, error) {
+	files, err := stateFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	backupDir := filepath.Join(dir, "backups", fmt.Sprintf("schema-v%d-%s", from, time.Now().UTC().Format("20060102T150405Z")))
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	for _, name := range append(files, schemaMarkerFile) {
+		if err := copyFile(filepath.Join(dir, name), filepath.Join(backupDir, name)); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to back up %s: %v", name, err)
+		}
+	}
+	return backupDir, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+/*
+migrateState - 밀린 마이그레이션을 순서대로 적용
+
+  - 실제 적용 전에 상태 파일 전체를 백업하고, 각 단계가 끝날 때마다 마커를 갱신 (중간 실패 시 그 단계부터 재시도)
+  - 마커가 바이너리보다 새 버전이면 이전 버전으로 되돌린 배포로 보고 거부 (새 형식을 잘못 읽지 않도록)
+  - dryRun이면 바꿀 내용만 기록하고 파일은 건드리지 않음
+*/
+func migrateState(logger *logrus.Logger, dir string, dryRun bool) error {
+	marker, found, err := readSchemaVersion(dir)
+	if err != nil {
+		return err
+	}
+	latest := latestSchemaVersion()
+	if marker.Version > latest {
+		return fmt.Errorf("state schema v%d in %s is newer than this binary (v%d); upgrade or restore a backup from %s",
+			marker.Version, dir, latest, filepath.Join(dir, "backups"))
+	}
+
+	var pending []stateMigration
+	for _, migration := range stateMigrations {
+		if migration.Version > marker.Version {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		// 새 설치: 마커만 기록 (기록하지 못해도 다음 시작 때 다시 새 설치로 판단됨)
+		if !found && !dryRun {
+			marker.UpdatedAt = time.Now()
+			if err := saveJSONState(filepath.Join(dir, schemaMarkerFile), marker); err != nil {
+				logger.Warnf("⚠️ Failed to record state schema version: %v", err)
+			}
+		}
+		return nil
+	}
+
+	mode := ""
+	if dryRun {
+		mode = ", dry run"
+	}
+	logger.Infof("🗄️ State schema v%d → v%d (%d migrations%s)", marker.Version, latest, len(pending), mode)
+
+	backup := ""
+	if !dryRun {
+		if backup, err = backupState(dir, marker.Version); err != nil {
+			return err
+		}
+		logger.Infof("💾 State backed up to %s", backup)
+	}
+
+	for _, migration := range pending {
+		changes, err := migration.Apply(dir, dryRun)
+		if err != nil {
+			return fmt.Errorf("migration v%d (%s) failed: %v; state backup: %s", migration.Version, migration.Name, err, backup)
+		}
+		for _, change := range changes {
+			logger.Infof("   v%d: %s", migration.Version, change)
+		}
+		if dryRun {
+			logger.Infof("🔎 v%d %s: %d changes (not applied)", migration.Version, migration.Name, len(changes))
+			continue
+		}
+
+		marker.Version = migration.Version
+		marker.UpdatedAt = time.Now()
+		marker.Applied = append(marker.Applied, appliedMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: marker.UpdatedAt,
+			Backup:    backup,
+		})
+		if err := saveJSONState(filepath.Join(dir, schemaMarkerFile), marker); err != nil {
+			return fmt.Errorf("failed to record schema v%d: %v", migration.Version, err)
+		}
+		logger.Infof("✅ Applied state migration v%d: %s", migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// migrateBookmarkKeys - v2: 요청 경로 그대로였던 watch 북마크 키를 bookmarkKey 형식으로 통일
+func migrateBookmarkKeys(dir string, dryRun bool) ([]string, error) {
+	path := filepath.Join(dir, "watch-bookmarks.json")
+	bookmarks := make(map[string]string)
+	found, err := loadJSONState(path, &bookmarks)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(bookmarks))
+	for key := range bookmarks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	migrated := make(map[string]string, len(bookmarks))
+	for _, key := range keys {
+		canonical := bookmarkKey(key)
+		if canonical != key {
+			changes = append(changes, fmt.Sprintf("watch-bookmarks.json: %s → %s", key, canonical))
+		}
+		// 같은 대상의 북마크가 여럿이면 표준 경로로 기록된 값을 우선
+		if _, exists := migrated[canonical]; !exists || canonical == key {
+			migrated[canonical] = bookmarks[key]
+		}
+	}
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+	return changes, saveJSONState(path, migrated)
+}
+
+/*
+runMigrateCommand - nautilus-control migrate [status] [--dry-run] [--dir 경로]
+서비스를 멈춘 상태에서 수동으로 마이그레이션하거나 적용될 내용을 미리 확인합니다.
+*/
+func runMigrateCommand(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dryRun := flags.Bool("dry-run", false, "show pending changes without modifying state")
+	dir := flags.String("dir", stateDir(), "state directory (NAUTILUS_STATE_DIR)")
+
+	status := len(args) > 0 && args[0] == "status"
+	if status {
+		args = args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if status {
+		marker, found, err := readSchemaVersion(*dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "state dir:      %s\n", *dir)
+		fmt.Fprintf(out, "schema version: v%d (marker present: %t)\n", marker.Version, found)
+		fmt.Fprintf(out, "binary schema:  v%d\n", latestSchemaVersion())
+		for _, migration := range stateMigrations {
+			state := "pending"
+			if migration.Version <= marker.Version {
+				state = "applied"
+			}
+			fmt.Fprintf(out, "  v%d %-8s %s\n", migration.Version, state, migration.Name)
+		}
+		return nil
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(out)
+	if !*dryRun {
+		if _, err := recoverStateTxns(*dir); err != nil {
+			return err
+		}
+	}
+	return migrateState(logger, *dir, *dryRun)
+}
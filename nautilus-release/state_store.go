@@ -0,0 +1,226 @@
+// State Store - 마스터 상태를 JSON 파일로 영속화
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stateDir - 상태 파일 디렉토리
+func stateDir() string {
+	return getEnvOrDefault("NAUTILUS_STATE_DIR", "/var/lib/nautilus")
+}
+
+// statePath - 상태 파일 경로
+func statePath(name string) string {
+	return filepath.Join(stateDir(), name)
+}
+
+// saveJSONState - 임시 파일에 쓴 뒤 rename하여 원자적으로 저장
+func saveJSONState(path string, value interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace state: %v", err)
+	}
+	return nil
+}
+
+// loadJSONState - 상태 파일 로드 (파일이 없으면 false)
+func loadJSONState(path string, value interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode state %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// stateTxnDir - 커밋됐지만 아직 반영이 끝나지 않은 트랜잭션 저널 디렉토리
+func stateTxnDir(dir string) string {
+	return filepath.Join(dir, "txn")
+}
+
+// stateTxnOp - 트랜잭션 저널의 작업 하나 (Data가 nil이면 삭제)
+type stateTxnOp struct {
+	Path string          `json:"path"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+/*
+stateTxn - 여러 상태 파일을 한 번에 바꾸는 트랜잭션 (모두 반영되거나 하나도 반영되지 않음)
+
+Commit은 모든 작업을 저널에 기록하고 fsync한 뒤 rename하는 시점에 커밋되며, 그다음 파일에 반영하고 저널을 지웁니다.
+반영 도중 프로세스가 죽으면 다음 시작 시 recoverStateTxns가 저널을 처음부터 다시 반영합니다 (Put/Delete는 멱등).
+rename 전에 죽으면 아무 파일도 바뀌지 않습니다. 같은 파일을 여러 번 바꾸면 마지막 작업만 남습니다.
+*/
+type stateTxn struct {
+	ops []stateTxnOp
+}
+
+// newStateTxn - 빈 트랜잭션
+func newStateTxn() *stateTxn {
+	return &stateTxn{}
+}
+
+// Put - 파일을 value의 JSON으로 교체
+func (t *stateTxn) Put(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+	t.set(stateTxnOp{Path: path, Data: data})
+	return nil
+}
+
+// Delete - 파일 삭제 (없으면 무시)
+func (t *stateTxn) Delete(path string) {
+	t.set(stateTxnOp{Path: path})
+}
+
+func (t *stateTxn) set(op stateTxnOp) {
+	for i := range t.ops {
+		if t.ops[i].Path == op.Path {
+			t.ops[i] = op
+			return
+		}
+	}
+	t.ops = append(t.ops, op)
+}
+
+// Len - 작업 수
+func (t *stateTxn) Len() int {
+	return len(t.ops)
+}
+
+// Commit - 저널 기록 후 모든 작업 반영 (작업이 하나면 저널 없이 바로 반영)
+func (t *stateTxn) Commit() error {
+	switch len(t.ops) {
+	case 0:
+		return nil
+	case 1:
+		return applyStateTxnOp(t.ops[0])
+	}
+
+	dir := stateTxnDir(stateDir())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create transaction directory: %v", err)
+	}
+	data, err := json.Marshal(t.ops)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %v", err)
+	}
+	journal := filepath.Join(dir, fmt.Sprintf("%d-%s.json", time.Now().UnixNano(), randomToken(6)))
+	if err := writeSynced(journal+".tmp", data); err != nil {
+		return fmt.Errorf("failed to write transaction journal: %v", err)
+	}
+	// 커밋 시점
+	if err := os.Rename(journal+".tmp", journal); err != nil {
+		os.Remove(journal + ".tmp")
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	for _, op := range t.ops {
+		if err := applyStateTxnOp(op); err != nil {
+			// 저널을 남겨 두면 다음 시작 시 나머지가 반영됨
+			return fmt.Errorf("transaction committed but not fully applied (replayed on restart): %v", err)
+		}
+	}
+	return os.Remove(journal)
+}
+
+func applyStateTxnOp(op stateTxnOp) error {
+	if op.Data == nil {
+		if err := os.Remove(op.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete state: %v", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(op.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+	tmp := op.Path + ".tmp"
+	if err := os.WriteFile(tmp, op.Data, 0600); err != nil {
+		return fmt.Errorf("failed to write state: %v", err)
+	}
+	if err := os.Rename(tmp, op.Path); err != nil {
+		return fmt.Errorf("failed to replace state: %v", err)
+	}
+	return nil
+}
+
+// writeSynced - 파일 기록 후 디스크까지 flush
+func writeSynced(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+/*
+recoverStateTxns - 반영 도중 중단된 트랜잭션을 커밋 순서대로 다시 반영하고, 커밋 전에 중단된 저널은 버림
+상태 파일을 읽는 컴포넌트와 마이그레이션보다 먼저 호출해야 합니다. 다시 반영한 트랜잭션 수를 반환합니다.
+*/
+func recoverStateTxns(dir string) (int, error) {
+	txnDir := stateTxnDir(dir)
+	entries, err := os.ReadDir(txnDir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read transaction directory: %v", err)
+	}
+
+	replayed := 0
+	for _, entry := range entries { // 이름이 커밋 시각으로 시작하므로 ReadDir 순서가 커밋 순서
+		path := filepath.Join(txnDir, entry.Name())
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			os.Remove(path)
+			continue
+		}
+		var ops []stateTxnOp
+		if _, err := loadJSONState(path, &ops); err != nil {
+			return replayed, err
+		}
+		for _, op := range ops {
+			if err := applyStateTxnOp(op); err != nil {
+				return replayed, fmt.Errorf("failed to replay transaction %s: %v", entry.Name(), err)
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return replayed, fmt.Errorf("failed to remove transaction journal: %v", err)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
@@ -0,0 +1,135 @@
+// Status Access - 워커 상태 포트 접근 허용 목록(마스터/운영 네트워크, 피어 워커)과 mTLS 신원을 하트비트 응답으로 배포
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+StatusAccess - 스테이커 호스트 상태 서버(기본 :10260)를 인터넷 스캐너로부터 차단하기 위한 정책
+
+워커는 하트비트에 적용 중인 정책 버전(status_access_version)을 보내고, 다르면 응답의 status_access로 새 정책을 받습니다.
+  - cidrs: 모든 경로에 접근할 수 있는 주소 (NAUTILUS_WORKER_STATUS_ALLOWLIST - 마스터 송신 주소, 운영/모니터링 네트워크)
+    워커는 여기에 마스터 엔드포인트의 DNS 주소와 자체 설정(status_firewall.allowed_cidrs)을 더합니다.
+  - peer_cidrs: 다른 활성 워커 주소 (대역폭 측정 경로만)
+  - client_ca, identities: 워커가 mTLS를 요구할 때 신뢰할 CA(TEE kubelet CA)와 허용할 인증서 CN
+    (NAUTILUS_WORKER_STATUS_IDENTITIES, 기본 nautilus-master - kubelet 인증서 CN system:node:*는 포함하지 않음)
+*/
+
+// StatusAccessPolicy - 워커 상태 포트 접근 정책
+type StatusAccessPolicy struct {
+	Version    string   `json:"version"`
+	CIDRs      []string `json:"cidrs"`
+	PeerCIDRs  []string `json:"peer_cidrs"`
+	ClientCA   string   `json:"client_ca,omitempty"` // PEM
+	Identities []string `json:"identities"`
+}
+
+// StatusAccess - 워커별 정책 계산
+type StatusAccess struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	ca         *KubeletCA
+	cidrs      []string
+	identities []string
+}
+
+// NewStatusAccess - 환경변수 허용 목록 검증 후 생성
+func NewStatusAccess(logger *logrus.Logger, workerPool *WorkerPool, ca *KubeletCA) (*StatusAccess, error) {
+	cidrs, err := parseStatusCIDRs(getEnvOrDefault("NAUTILUS_WORKER_STATUS_ALLOWLIST", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAUTILUS_WORKER_STATUS_ALLOWLIST: %v", err)
+	}
+	var identities []string
+	for _, identity := range strings.Split(getEnvOrDefault("NAUTILUS_WORKER_STATUS_IDENTITIES", "nautilus-master"), ",") {
+		if identity = strings.TrimSpace(identity); identity != "" {
+			if strings.HasPrefix(identity, "system:node:") {
+				return nil, fmt.Errorf("NAUTILUS_WORKER_STATUS_IDENTITIES must not include kubelet identities (%s)", identity)
+			}
+			identities = append(identities, identity)
+		}
+	}
+	sort.Strings(identities)
+	return &StatusAccess{logger: logger, workerPool: workerPool, ca: ca, cidrs: cidrs, identities: identities}, nil
+}
+
+// parseStatusCIDRs - 쉼표로 구분한 CIDR/IP 목록 (IP는 단일 주소 CIDR로 정규화)
+func parseStatusCIDRs(value string) ([]string, error) {
+	var cidrs []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			cidrs = append(cidrs, singleAddressCIDR(ip))
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP or CIDR", entry)
+		}
+		cidrs = append(cidrs, network.String())
+	}
+	sort.Strings(cidrs)
+	return cidrs, nil
+}
+
+func singleAddressCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.To4().String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+// Policy - nodeID에 보낼 정책 (워커가 이미 같은 버전을 적용했으면 nil)
+func (s *StatusAccess) Policy(nodeID, appliedVersion string) *StatusAccessPolicy {
+	policy := &StatusAccessPolicy{
+		CIDRs:      s.cidrs,
+		PeerCIDRs:  s.peerCIDRs(nodeID),
+		Identities: s.identities,
+	}
+	if s.ca != nil {
+		policy.ClientCA = string(s.ca.CertificatePEM())
+	}
+	encoded, _ := json.Marshal(policy)
+	digest := sha256.Sum256(encoded)
+	policy.Version = hex.EncodeToString(digest[:8])
+	if policy.Version == appliedVersion {
+		return nil
+	}
+	return policy
+}
+
+// peerCIDRs - 다른 활성 워커의 상태 서버 주소 (측정 주소를 보고한 워커만)
+func (s *StatusAccess) peerCIDRs(nodeID string) []string {
+	seen := make(map[string]bool)
+	var cidrs []string
+	for _, worker := range s.workerPool.ListWorkers() {
+		if worker.NodeID == nodeID || worker.Status != "active" || worker.ProbeURL == "" {
+			continue
+		}
+		u, err := url.Parse(worker.ProbeURL)
+		if err != nil {
+			continue
+		}
+		ip := net.ParseIP(u.Hostname())
+		if ip == nil {
+			continue
+		}
+		if cidr := singleAddressCIDR(ip); !seen[cidr] {
+			seen[cidr] = true
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	sort.Strings(cidrs)
+	return cidrs
+}
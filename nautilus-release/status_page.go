@@ -0,0 +1,262 @@
+// Status Page - 인증 없이 볼 수 있는 클러스터 공개 상태 페이지 (JSON/HTML, IP별 요청 제한)
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	statusUptimeSamples     = 24 * 60 // 최근 24시간 (1분 간격)
+	statusRateLimitPerMin   = 30
+	statusRateLimitInterval = time.Minute
+)
+
+// PublicStatus - 공개 상태 (민감 정보 제외)
+type PublicStatus struct {
+	Healthy         bool      `json:"healthy"`
+	TotalNodes      int       `json:"total_nodes"`
+	ActiveNodes     int       `json:"active_nodes"`
+	AllocatableCPU  int64     `json:"allocatable_cpu_millis"`
+	AllocatableMem  int64     `json:"allocatable_memory_mb"`
+	CapacityWanted  bool      `json:"capacity_wanted"`
+	Uptime24hPct    float64   `json:"uptime_24h_percent"`
+	ContractAddress string    `json:"contract_address"`
+	Measurement     string    `json:"attestation_measurement"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// StatusPage - 공개 상태 페이지
+type StatusPage struct {
+	logger      *logrus.Logger
+	k3sMgr      *K3sManager
+	sui         *SuiIntegration
+	capacity    *CapacityPublisher
+	measurement string
+	samples     []bool
+	next        int
+	filled      bool
+	visitors    map[string]*statusVisitor
+	mutex       sync.RWMutex
+}
+
+// statusVisitor - IP별 고정 윈도우 요청 카운터
+type statusVisitor struct {
+	windowStart time.Time
+	count       int
+}
+
+// NewStatusPage - 새 Status Page 생성
+func NewStatusPage(logger *logrus.Logger, k3sMgr *K3sManager, sui *SuiIntegration) *StatusPage {
+	return &StatusPage{
+		logger:      logger,
+		k3sMgr:      k3sMgr,
+		sui:         sui,
+		measurement: attestationMeasurement(),
+		samples:     make([]bool, statusUptimeSamples),
+		visitors:    make(map[string]*statusVisitor),
+	}
+}
+
+// attestationMeasurement - TEE 측정값 (TEE_MEASUREMENT, 없으면 실행 바이너리 SHA-256)
+func attestationMeasurement() string {
+	if measurement := os.Getenv("TEE_MEASUREMENT"); measurement != "" {
+		return measurement
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Start - 1분마다 가용성 샘플 기록 및 오래된 방문자 정리
+func (p *StatusPage) Start(ctx context.Context) {
+	p.logger.Info("📣 Starting public status page...")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	p.record()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.record()
+		}
+	}
+}
+
+// record - 현재 가용성 샘플 저장
+func (p *StatusPage) record() {
+	running := p.k3sMgr.IsRunning()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.samples[p.next] = running
+	p.next = (p.next + 1) % len(p.samples)
+	if p.next == 0 {
+		p.filled = true
+	}
+
+	now := time.Now()
+	for ip, visitor := range p.visitors {
+		if now.Sub(visitor.windowStart) > statusRateLimitInterval {
+			delete(p.visitors, ip)
+		}
+	}
+}
+
+// uptimePercent - 기록된 샘플 기준 가용률
+func (p *StatusPage) uptimePercent() float64 {
+	count := p.next
+	if p.filled {
+		count = len(p.samples)
+	}
+	if count == 0 {
+		return 0
+	}
+
+	up := 0
+	for i := 0; i < count; i++ {
+		if p.samples[i] {
+			up++
+		}
+	}
+	return float64(up) * 100 / float64(count)
+}
+
+// allow - IP별 요청 제한 확인
+func (p *StatusPage) allow(remoteAddr string) bool {
+	ip, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		ip = remoteAddr
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	visitor, exists := p.visitors[ip]
+	if !exists || now.Sub(visitor.windowStart) > statusRateLimitInterval {
+		p.visitors[ip] = &statusVisitor{windowStart: now, count: 1}
+		return true
+	}
+	visitor.count++
+	return visitor.count <= statusRateLimitPerMin
+}
+
+// Status - 현재 공개 상태
+func (p *StatusPage) Status() PublicStatus {
+	status := PublicStatus{
+		Healthy:         p.k3sMgr.IsRunning(),
+		ContractAddress: p.sui.activeContract().Package,
+		Measurement:     p.measurement,
+		GeneratedAt:     time.Now(),
+	}
+
+	for _, worker := range p.k3sMgr.workerPool.ListWorkers() {
+		status.TotalNodes++
+		if worker.Status == "active" {
+			status.ActiveNodes++
+		}
+	}
+
+	if p.capacity != nil {
+		if snapshot := p.capacity.Snapshot(); snapshot != nil {
+			status.AllocatableCPU = snapshot.Supply.AllocatableCPUMillis
+			status.AllocatableMem = snapshot.Supply.AllocatableMemoryMB
+			status.CapacityWanted = snapshot.CapacityWanted
+		}
+	}
+
+	p.mutex.RLock()
+	status.Uptime24hPct = p.uptimePercent()
+	p.mutex.RUnlock()
+
+	return status
+}
+
+// statusTemplate - 간단한 HTML 상태 페이지
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>K3s-DaaS Cluster Status</title>
+<style>
+body { font-family: sans-serif; max-width: 640px; margin: 40px auto; color: #222; }
+.badge { padding: 4px 10px; border-radius: 4px; color: #fff; }
+.up { background: #2e7d32; } .down { background: #c62828; }
+td { padding: 6px 12px; border-bottom: 1px solid #eee; } code { word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>K3s-DaaS Cluster Status</h1>
+<p>{{if .Healthy}}<span class="badge up">Operational</span>{{else}}<span class="badge down">Degraded</span>{{end}}</p>
+<table>
+<tr><td>Nodes</td><td>{{.ActiveNodes}} active / {{.TotalNodes}} registered</td></tr>
+<tr><td>Allocatable</td><td>{{.AllocatableCPU}}m CPU, {{.AllocatableMem}} MiB memory</td></tr>
+<tr><td>Capacity wanted</td><td>{{.CapacityWanted}}</td></tr>
+<tr><td>Uptime (24h)</td><td>{{printf "%.2f" .Uptime24hPct}}%</td></tr>
+<tr><td>Contract</td><td><code>{{.ContractAddress}}</code></td></tr>
+<tr><td>Attestation</td><td><code>{{.Measurement}}</code></td></tr>
+</table>
+<p><small>Generated {{.GeneratedAt.UTC.Format "2006-01-02 15:04:05 UTC"}}</small></p>
+</body>
+</html>
+`))
+
+// handleStatus - GET /status (Accept 또는 ?format=json이면 JSON)
+func (p *StatusPage) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !p.allow(r.RemoteAddr) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	status := p.Status()
+	w.Header().Set("Cache-Control", "public, max-age=15")
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   status,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusTemplate.Execute(w, status); err != nil {
+		p.logger.Errorf("❌ Failed to render status page: %v", err)
+	}
+}
@@ -4,17 +4,18 @@ package main
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	apirequest "github.com/k3s-io/daas-apirequest"
+	chain "github.com/k3s-io/daas-chain"
+	sui "github.com/k3s-io/daas-sui"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,16 +25,55 @@ type SuiIntegration struct {
 	k3sMgr        *K3sManager
 	workerPool    *WorkerPool
 	sealTokenMgr  *SealTokenManager
+	controllerMgr *ControllerManager
+	rbac          *RBACAuthorizer
+	audit         *AuditLogger
+	clock         *ClockGuard
+	pin           *PackagePin
+	history       *HeartbeatHistory
+	poolSync      *PoolSync
+	quota         *TenantThrottler
+	maintenance   *MaintenanceScheduler
+	appeals       *AppealTracker
+	abandonment   *AbandonmentTracker
+	reservations  *CapacityReservations
+	enrollment    *EnrollmentQueue
+	finality      *FinalityGate
+	deadLetters   *DeadLetterQueue
+	responses     *ResponseStore
+	slo           *SLOTracker
+	migration     *ContractMigration
+	chain         *sui.SuiClient
+	backend       chain.Backend     // 이벤트 구독/컨트랙트 호출 백엔드 (NAUTILUS_CHAIN_BACKEND)
+	mockChain     *chain.MockServer // 마스터가 직접 호스팅하는 mock 체인 (그 외 nil)
 	suiRPCURL     string
 	contractAddr  string
-	privateKey    string
+	chainKey      EnclaveSigner // 봉인된 Sui 마스터 키 (PRIVATE_KEY에서 가져옴, 없으면 nil)
 	wsConn        *websocket.Conn
 	eventChan     chan *SuiContractEvent
+	inFlight      map[string]*K8sAPIRequest
+	inFlightMutex sync.Mutex
 	stopChan      chan bool
 	registryAddr  string
 	schedulerAddr string
+	runner        commandRunner
+	cursorMutex   sync.RWMutex
+	cursor        uint64 // 마지막으로 이벤트 조회에 성공한 시점의 체크포인트
+	positionMutex sync.Mutex
+	resumeAfter   map[string]chain.EventID // 재시작 체크포인트에서 읽은 패키지별 구독 재개 위치
+	processed     map[string]chain.EventID // 패키지별 마지막으로 처리를 마친 이벤트
+	watchdog      *EventWatchdog // 조회/처리 진행 시각 기록 (없으면 nil)
+	trash         *TrashBin      // 삭제 직전 객체 보관 (SoftDelete 게이트가 꺼져 있으면 nil)
+	payloads      *PayloadDecryptor // 암호화된 요청 본문 복호화 (EncryptedPayloads 게이트가 꺼져 있으면 nil)
+	chainHealth   *ChainHealth      // 요청 이벤트 처리 지연 기록 (ChainHealth 게이트가 꺼져 있으면 nil)
+	readOnly      *ReadOnlyMode     // 읽기 전용 모드 중 쓰기 요청 거부
+	scopes        *TokenScopes      // 범위 제한 Seal 토큰 (RBAC 전에 확인)
+	provisioner   *NamespaceProvisioner // 첫 배포 시 네임스페이스 자동 생성 (NamespaceAutoProvisioning 게이트가 꺼져 있으면 nil)
 }
 
+// commandRunner - 외부 명령(kubectl, sui CLI) 실행 (재생 테스트에서 실행 대신 기록하도록 교체)
+type commandRunner func(name string, stdin []byte, args ...string) (stdout, stderr []byte, err error)
+
 // SuiContractEvent - Sui Contract에서 발생하는 이벤트
 type SuiContractEvent struct {
 	Type         string                 `json:"type"`
@@ -42,23 +82,34 @@ type SuiContractEvent struct {
 	Sender       string                 `json:"sender"`
 	EventData    map[string]interface{} `json:"parsedJson"`
 	TxDigest     string                 `json:"transactionDigest"`
+	EventSeq     string                 `json:"eventSeq"` // 트랜잭션 내 이벤트 순번 (id.eventSeq)
 	Timestamp    int64                  `json:"timestampMs"`
 }
 
-// K8sAPIRequest - K8s API 요청 (Contract에서 받음)
-type K8sAPIRequest struct {
-	RequestID    string `json:"request_id"`
-	Method       string `json:"method"`        // GET, POST, PUT, DELETE, PATCH
-	Resource     string `json:"resource"`      // pods, services, deployments, etc.
-	Namespace    string `json:"namespace"`     // default, kube-system, etc.
-	Name         string `json:"name"`          // 리소스 이름 (optional)
-	Payload      string `json:"payload"`       // YAML/JSON 데이터 (POST/PUT용)
-	SealToken    string `json:"seal_token"`    // TEE 인증 토큰
-	Requester    string `json:"requester"`     // 요청자 주소
-	Priority     int    `json:"priority"`      // 1-10 우선순위
-	Timestamp    string `json:"timestamp"`
+// suiEventJSON - Sui 이벤트 JSON (WebSocket 구독 결과, 재생 픽스처)
+// 이벤트마다 map으로 디코딩하지 않도록 바깥 필드는 구조체로 받고 이벤트별로 형태가 다른 parsedJson만 맵으로 둠
+type suiEventJSON struct {
+	Type      string `json:"type"`
+	PackageID string `json:"packageId"`
+	Sender    string `json:"sender"`
+	TxDigest  string `json:"transactionDigest"`
+	ID        struct {
+		EventSeq string `json:"eventSeq"`
+	} `json:"id"`
+	TimestampMs string                 `json:"timestampMs"` // u64는 문자열로 옴
+	ParsedJSON  map[string]interface{} `json:"parsedJson"`
+}
+
+// suiEventMessage - suix_subscribeEvent 알림 (구독 확인 응답은 params가 없음)
+type suiEventMessage struct {
+	Params struct {
+		Result *suiEventJSON `json:"result"`
+	} `json:"params"`
 }
 
+// K8sAPIRequest - K8s API 요청 (Contract에서 받음, Gateway/Move 이벤트와 공용 스키마)
+type K8sAPIRequest = apirequest.Request
+
 // WorkerNodeRequest - 워커 노드 관리 요청
 type WorkerNodeRequest struct {
 	Action       string `json:"action"`        // register, unregister, heartbeat
@@ -80,208 +131,159 @@ type K8sAPIResult struct {
 }
 
 // NewSuiIntegration - 새 Sui Integration 생성
-func NewSuiIntegration(logger *logrus.Logger, k3sMgr *K3sManager) *SuiIntegration {
+func NewSuiIntegration(logger *logrus.Logger, k3sMgr *K3sManager, controllerMgr *ControllerManager, keyring *EnclaveKeyring) *SuiIntegration {
+	suiRPCURL := getEnvOrDefault("SUI_RPC_URL", "https://fullnode.testnet.sui.io")
+	contractAddr := getEnvOrDefault("CONTRACT_PACKAGE_ID", "0x664356de3f1ce1df7d8039fb7f244dba3baec08025d791d15245876c76253bfc")
+
+	httpRPCURL := strings.Replace(suiRPCURL, "wss://", "https://", 1)
+	httpRPCURL = strings.Replace(httpRPCURL, "/websocket", "", 1)
+
+	chainKey, err := keyring.Load(suiMasterKey())
+	if err != nil {
+		logger.Fatalf("❌ Failed to load Sui master key: %v", err)
+	}
+	chainClient := sui.NewReadOnlyClient(httpRPCURL, contractAddr)
+	backend, mockChain := openChainBackend(logger, chainClient, contractAddr, chainKey != nil)
+
 	return &SuiIntegration{
 		logger:        logger,
 		k3sMgr:        k3sMgr,
 		workerPool:    k3sMgr.workerPool,
 		sealTokenMgr:  k3sMgr.sealTokenManager,
-		suiRPCURL:     getEnvOrDefault("SUI_RPC_URL", "https://fullnode.testnet.sui.io"),
-		contractAddr:  getEnvOrDefault("CONTRACT_PACKAGE_ID", "0x664356de3f1ce1df7d8039fb7f244dba3baec08025d791d15245876c76253bfc"),
+		controllerMgr: controllerMgr,
+		suiRPCURL:     suiRPCURL,
+		contractAddr:  contractAddr,
+		chain:         chainClient,
+		backend:       backend,
+		mockChain:     mockChain,
 		registryAddr:  getEnvOrDefault("WORKER_REGISTRY_ID", "0xca7ddf00a634c97b126aac539f0d5e8b8df20ad4e88b5f7b5f18291fbe6f0981"),
 		schedulerAddr: getEnvOrDefault("K8S_SCHEDULER_ID", "0xf0f551c41b4056441a167a72ea14607f83aa6b73eb1383f69516ab0a893842a3"),
-		privateKey:    getEnvOrDefault("PRIVATE_KEY", ""),
+		chainKey:      chainKey,
 		eventChan:     make(chan *SuiContractEvent, 100),
+		inFlight:      make(map[string]*K8sAPIRequest),
 		stopChan:      make(chan bool, 1),
 	}
 }
 
 // Start - Sui Integration 시작
 func (s *SuiIntegration) Start(ctx context.Context) {
-	s.logger.Info("🌊 Starting Sui Integration...")
-
-	if s.contractAddr == "" || s.privateKey == "" {
-		s.logger.Warn("⚠️ Sui contract not configured, running in mock mode")
-		s.startMockMode(ctx)
-		return
-	}
-
-	s.logger.Info("🔗 Starting real Sui contract integration...")
-	s.startRealMode(ctx)
-}
+	s.logger.Infof("🌊 Starting Sui Integration (%s chain backend)...", s.backend.Name())
 
-// startRealMode - 실제 Contract 연동 모드
-func (s *SuiIntegration) startRealMode(ctx context.Context) {
 	// HTTP API 폴링으로 이벤트 수집
 	go s.pollSuiEvents(ctx)
 
 	// 이벤트 처리 고루틴 시작
 	go s.processContractEvents(ctx)
 
+	// 인증된 체크포인트에 포함된 이벤트만 처리
+	if s.finality != nil {
+		go s.finality.Start(ctx)
+	}
+
 	// 주기적 상태 체크
 	go s.periodicHealthCheck(ctx)
 
-	s.logger.Info("✅ Sui Integration started in real mode with HTTP polling")
+	s.logger.Infof("✅ Sui Integration started on %s chain backend", s.backend.Name())
 }
 
-// pollSuiEvents - HTTP API를 통한 이벤트 폴링
+// pollSuiEvents - 체인 백엔드 구독으로 이벤트 수집
 func (s *SuiIntegration) pollSuiEvents(ctx context.Context) {
-	// HTTP RPC URL로 변경
-	httpRPCURL := strings.Replace(s.suiRPCURL, "wss://", "https://", 1)
-	httpRPCURL = strings.Replace(httpRPCURL, "/websocket", "", 1)
-
-	s.logger.Infof("🔍 Starting event polling from: %s", httpRPCURL)
-
-	lastCheckpoint := uint64(0)
-	ticker := time.NewTicker(3 * time.Second) // 3초마다 폴링
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			events, newCheckpoint := s.fetchLatestEvents(httpRPCURL, lastCheckpoint)
-			if len(events) > 0 {
-				s.logger.Infof("📨 Found %d new events", len(events))
-				for _, event := range events {
-					select {
-					case s.eventChan <- event:
-					default:
-						s.logger.Warn("⚠️ Event channel full, dropping event")
-					}
-				}
-				lastCheckpoint = newCheckpoint
-			}
-		}
-	}
+	s.subscribePackage(ctx, s.contractAddr)
 }
 
-// fetchLatestEvents - 최신 이벤트 가져오기
-func (s *SuiIntegration) fetchLatestEvents(rpcURL string, fromCheckpoint uint64) ([]*SuiContractEvent, uint64) {
-	// Sui queryEvents API 호출 - All 필터로 모든 이벤트 가져오기 (최근 이벤트부터)
-	requestBody := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      1,
-		"method":  "suix_queryEvents",
-		"params": []interface{}{
-			map[string]interface{}{
-				"All": []interface{}{},
-			},
-			nil,  // cursor
-			50,   // limit
-			true, // descending_order (최신 이벤트부터)
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		s.logger.Errorf("❌ Failed to marshal request: %v", err)
-		return nil, fromCheckpoint
-	}
+// subscribePackage - 패키지 하나의 이벤트 구독 (패키지 전환 중에는 새 패키지도 따로 구독)
+func (s *SuiIntegration) subscribePackage(ctx context.Context, packageID string) {
+	s.logger.Infof("🔍 Subscribing to %s events for package %s", s.backend.Name(), packageID)
 
-	resp, err := http.Post(rpcURL, "application/json", bytes.NewBuffer(jsonData))
+	batches, err := s.backend.SubscribeEvents(ctx, chain.EventFilter{Package: packageID, After: s.resumePosition(packageID)})
 	if err != nil {
-		s.logger.Errorf("❌ Failed to query events: %v", err)
-		return nil, fromCheckpoint
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		s.logger.Errorf("❌ Failed to read response: %v", err)
-		return nil, fromCheckpoint
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		s.logger.Errorf("❌ Failed to parse response: %v", err)
-		return nil, fromCheckpoint
+		s.logger.Errorf("❌ Failed to subscribe to contract events: %v", err)
+		return
 	}
 
-	// API 에러 확인
-	if errorData, ok := result["error"]; ok {
-		s.logger.Errorf("❌ Sui API error: %v", errorData)
-		return nil, fromCheckpoint
-	}
+	for batch := range batches {
+		if batch.Err != nil {
+			s.logger.Errorf("❌ Failed to query events: %v", batch.Err)
+		} else if batch.Height > 0 {
+			// 조회에 성공한 높이까지는 따라잡은 것
+			s.cursorMutex.Lock()
+			s.cursor = batch.Height
+			s.cursorMutex.Unlock()
+			s.watchdog.CursorAdvanced(batch.Height)
+		}
 
-	// 이벤트 데이터 파싱
-	events := []*SuiContractEvent{}
-	maxCheckpoint := fromCheckpoint
-
-	s.logger.Debugf("📡 API Response: %v", result)
-
-	if data, ok := result["result"].(map[string]interface{}); ok {
-		if eventList, ok := data["data"].([]interface{}); ok {
-			s.logger.Infof("🔍 Found %d events in API response", len(eventList))
-			for _, eventData := range eventList {
-				if eventMap, ok := eventData.(map[string]interface{}); ok {
-					event := s.parseEventFromAPI(eventMap)
-					if event != nil {
-						events = append(events, event)
-						s.logger.Infof("✅ Parsed event: %s", event.Type)
-
-						// 체크포인트 업데이트
-						if timestampMs, ok := eventMap["timestampMs"].(string); ok {
-							if ts, err := strconv.ParseUint(timestampMs, 10, 64); err == nil && ts > maxCheckpoint {
-								maxCheckpoint = ts
-							}
-						}
-					} else {
-						s.logger.Warn("⚠️ Failed to parse event")
-					}
-				}
+		for _, raw := range batch.Events {
+			event := s.acceptEvent(contractEventFromChain(raw))
+			if event == nil {
+				continue
+			}
+			s.logger.Infof("📨 Received event: %s", event.Type)
+			select {
+			case s.eventChan <- event:
+			default:
+				s.logger.Warn("⚠️ Event channel full, dropping event")
 			}
-		} else {
-			s.logger.Warn("⚠️ No 'data' field in API response")
 		}
-	} else {
-		s.logger.Warn("⚠️ No 'result' field in API response")
 	}
-
-	return events, maxCheckpoint
 }
 
-// parseEventFromAPI - API 응답에서 이벤트 파싱
-func (s *SuiIntegration) parseEventFromAPI(eventMap map[string]interface{}) *SuiContractEvent {
-	event := &SuiContractEvent{}
-
-	// 기본 필드 파싱
-	if eventType, ok := eventMap["type"].(string); ok {
-		event.Type = eventType
-	}
-
-	if packageID, ok := eventMap["packageId"].(string); ok {
-		event.PackageID = packageID
-	}
-
-	if sender, ok := eventMap["sender"].(string); ok {
-		event.Sender = sender
+// contractEventFromChain - 백엔드 이벤트를 내부 이벤트 형식으로 변환
+func contractEventFromChain(event chain.Event) *SuiContractEvent {
+	return &SuiContractEvent{
+		Type:      event.Type,
+		PackageID: event.Package,
+		Module:    event.Module,
+		Sender:    event.Sender,
+		EventData: event.Data,
+		TxDigest:  event.TxDigest,
+		EventSeq:  event.Seq,
+		Timestamp: event.TimestampMs,
 	}
+}
 
-	if txDigest, ok := eventMap["transactionDigest"].(string); ok {
-		event.TxDigest = txDigest
+// parseEventFromAPI - API 응답에서 이벤트 파싱
+func (s *SuiIntegration) parseEventFromAPI(raw *suiEventJSON) *SuiContractEvent {
+	event := &SuiContractEvent{
+		Type:      raw.Type,
+		PackageID: raw.PackageID,
+		Sender:    raw.Sender,
+		TxDigest:  raw.TxDigest,
+		EventSeq:  raw.ID.EventSeq,
+		EventData: raw.ParsedJSON,
 	}
-
-	if timestampMs, ok := eventMap["timestampMs"].(string); ok {
-		if ts, err := strconv.ParseInt(timestampMs, 10, 64); err == nil {
-			event.Timestamp = ts
-		}
+	if ts, err := strconv.ParseInt(raw.TimestampMs, 10, 64); err == nil {
+		event.Timestamp = ts
 	}
 
-	// parsedJson 필드 파싱
-	if parsedJson, ok := eventMap["parsedJson"].(map[string]interface{}); ok {
-		event.EventData = parsedJson
-	}
+	return s.acceptEvent(event)
+}
 
+// acceptEvent - 우리 패키지의 처리 대상 이벤트만 통과 (아니면 nil)
+func (s *SuiIntegration) acceptEvent(event *SuiContractEvent) *SuiContractEvent {
 	// 우리가 관심 있는 이벤트인지 확인 - 새 contract 이벤트 타입
-	if event.PackageID == s.contractAddr && (
+	if s.acceptsPackage(event.PackageID) && (
 		strings.Contains(event.Type, "WorkerRegisteredEvent") ||
 		strings.Contains(event.Type, "K8sAPIRequestScheduledEvent") ||
 		strings.Contains(event.Type, "WorkerStatusChangedEvent") ||
 		strings.Contains(event.Type, "StakeDepositedEvent") ||
 		strings.Contains(event.Type, "WorkerAssignedEvent") ||
-		strings.Contains(event.Type, "K8sAPIResultEvent")) {
+		strings.Contains(event.Type, "K8sAPIResultEvent") ||
+		strings.Contains(event.Type, "AttestationVerifiedEvent") ||
+		strings.Contains(event.Type, "MaintenanceScheduledEvent") ||
+		strings.Contains(event.Type, "MaintenanceCancelledEvent") ||
+		strings.Contains(event.Type, "AppealSubmittedEvent") ||
+		strings.Contains(event.Type, "AppealDecidedEvent") ||
+		strings.Contains(event.Type, "EnrollmentDecidedEvent") ||
+		strings.Contains(event.Type, "EmergencyUnstakeEvent") ||
+		strings.Contains(event.Type, "ScopedTokenIssuedEvent") ||
+		strings.Contains(event.Type, "ScopedTokenRevokedEvent") ||
+		strings.Contains(event.Type, "ReservationPurchasedEvent") ||
+		strings.Contains(event.Type, "ReservationConfirmedEvent") ||
+		strings.Contains(event.Type, "ReservationRejectedEvent") ||
+		strings.Contains(event.Type, "ReservationCancelledEvent")) {
+		if s.migration != nil {
+			s.migration.ObserveEvent(event)
+		}
 		return event
 	}
 
@@ -358,7 +360,7 @@ func (s *SuiIntegration) listenForEvents(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			var message map[string]interface{}
+			var message suiEventMessage
 			err := s.wsConn.ReadJSON(&message)
 			if err != nil {
 				s.logger.Errorf("❌ Error reading WebSocket message: %v", err)
@@ -366,30 +368,24 @@ func (s *SuiIntegration) listenForEvents(ctx context.Context) {
 			}
 
 			// 이벤트 메시지 처리
-			s.handleWebSocketMessage(message)
+			s.handleWebSocketMessage(&message)
 		}
 	}
 }
 
 // handleWebSocketMessage - WebSocket 메시지 처리
-func (s *SuiIntegration) handleWebSocketMessage(message map[string]interface{}) {
-	// "params" 필드에서 이벤트 데이터 추출
-	if params, ok := message["params"].(map[string]interface{}); ok {
-		if result, ok := params["result"].(map[string]interface{}); ok {
-			event := &SuiContractEvent{}
-
-			// JSON 데이터를 구조체로 변환
-			if data, err := json.Marshal(result); err == nil {
-				if err := json.Unmarshal(data, event); err == nil {
-					// 이벤트 채널로 전송
-					select {
-					case s.eventChan <- event:
-						s.logger.Debugf("📨 Received contract event: %s", event.Type)
-					default:
-						s.logger.Warn("⚠️ Event channel full, dropping event")
-					}
-				}
-			}
+func (s *SuiIntegration) handleWebSocketMessage(message *suiEventMessage) {
+	// "params.result"에 이벤트가 있는 알림만 처리
+	if message.Params.Result == nil {
+		return
+	}
+	if event := s.parseEventFromAPI(message.Params.Result); event != nil {
+		// 이벤트 채널로 전송
+		select {
+		case s.eventChan <- event:
+			s.logger.Debugf("📨 Received contract event: %s", event.Type)
+		default:
+			s.logger.Warn("⚠️ Event channel full, dropping event")
 		}
 	}
 }
@@ -401,25 +397,148 @@ func (s *SuiIntegration) processContractEvents(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case event := <-s.eventChan:
-			s.processEvent(event)
+			if s.finality != nil {
+				s.finality.Admit(event)
+				continue
+			}
+			s.processEvent(ctx, event)
 		}
 	}
 }
 
-// processEvent - 개별 이벤트 처리
-func (s *SuiIntegration) processEvent(event *SuiContractEvent) {
+// processEvent - 개별 이벤트 처리 (처리할 수 없는 이벤트는 dead-letter 큐로)
+func (s *SuiIntegration) processEvent(ctx context.Context, event *SuiContractEvent) {
 	s.logger.Infof("🔧 Processing event: %s from %s", event.Type, event.Sender)
+	defer s.watchdog.BeginEvent()()
+
+	err := s.dispatchEvent(ctx, event)
+	if err != nil {
+		s.logger.Warnf("⚠️ Event %s (%s) not processed: %v", event.Type, event.TxDigest, err)
+		s.deadLetters.Add(event, err)
+	}
+	if s.slo != nil {
+		s.slo.RecordEvent(event, err != nil)
+	}
+	s.markProcessed(event)
+}
+
+// dispatchEvent - 페이로드 검증 후 타입별 핸들러 호출 (핸들러 panic도 오류로 반환)
+func (s *SuiIntegration) dispatchEvent(ctx context.Context, event *SuiContractEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+
+	if err := validateEventPayload(event); err != nil {
+		return err
+	}
+	// 고정한 다이제스트와 다른 패키지의 이벤트는 처리하지 않음 (검증 후 dead-letter에서 재처리)
+	if err := s.pin.Check(event.PackageID); err != nil {
+		return err
+	}
 
 	switch {
 	case strings.Contains(event.Type, "WorkerRegisteredEvent"):
 		s.handleWorkerRegisteredEvent(event)
 	case strings.Contains(event.Type, "K8sAPIRequestScheduledEvent"):
-		s.handleK8sAPIRequest(event)
+		s.handleK8sAPIRequest(ctx, event)
 	case strings.Contains(event.Type, "WorkerStatusChangedEvent"):
 		s.handleWorkerStatusEvent(event)
+	case strings.Contains(event.Type, "AttestationVerifiedEvent"):
+		s.handleAttestationEvent(event)
+	case strings.Contains(event.Type, "MaintenanceScheduledEvent"),
+		strings.Contains(event.Type, "MaintenanceCancelledEvent"):
+		s.maintenance.handleChainEvent(event)
+	case strings.Contains(event.Type, "AppealSubmittedEvent"),
+		strings.Contains(event.Type, "AppealDecidedEvent"):
+		s.appeals.handleChainEvent(event)
+	case strings.Contains(event.Type, "EnrollmentDecidedEvent"):
+		s.enrollment.handleChainEvent(event)
+	case strings.Contains(event.Type, "StakeAmountChangedEvent"):
+		s.handleStakeAmountChanged(event)
+	case strings.Contains(event.Type, "EmergencyUnstakeEvent"):
+		s.handleEmergencyUnstake(event)
+	case strings.Contains(event.Type, "ScopedTokenIssuedEvent"),
+		strings.Contains(event.Type, "ScopedTokenRevokedEvent"):
+		s.scopes.handleChainEvent(event)
+	case strings.Contains(event.Type, "ReservationPurchasedEvent"),
+		strings.Contains(event.Type, "ReservationConfirmedEvent"),
+		strings.Contains(event.Type, "ReservationRejectedEvent"),
+		strings.Contains(event.Type, "ReservationCancelledEvent"):
+		s.reservations.handleChainEvent(event)
+	case strings.Contains(event.Type, "StakeDepositedEvent"),
+		strings.Contains(event.Type, "WorkerAssignedEvent"),
+		strings.Contains(event.Type, "K8sAPIResultEvent"):
+		// 정보성 이벤트 (등록/결과 처리 경로에서 이미 반영됨)
+		s.logger.Debugf("Event %s requires no action", event.Type)
 	default:
-		s.logger.Warnf("⚠️ Unknown event type: %s", event.Type)
+		return fmt.Errorf("unsupported event type %s", event.Type)
+	}
+	return nil
+}
+
+// requiredEventFields - 이벤트 타입별 핸들러가 요구하는 parsedJson 문자열 필드
+var requiredEventFields = map[string][]string{
+	"WorkerRegisteredEvent":       {"node_id", "owner"},
+	"WorkerStatusChangedEvent":    {"node_id", "new_status"},
+	"AttestationVerifiedEvent":    {"target", "verdict"},
+	"MaintenanceScheduledEvent":   {"node_id", "start_ms", "end_ms"},
+	"MaintenanceCancelledEvent":   {"node_id"},
+	"AppealSubmittedEvent":        {"appeal_id", "node_id", "evidence_digest"},
+	"AppealDecidedEvent":          {"appeal_id"},
+	"EnrollmentDecidedEvent":      {"node_id"},
+	"StakeAmountChangedEvent":     {"node_id"},
+	"EmergencyUnstakeEvent":       {"node_id"},
+	"ScopedTokenIssuedEvent":      {"node_id", "owner", "token_hash"},
+	"ScopedTokenRevokedEvent":     {"node_id", "token_hash"},
+	"ReservationPurchasedEvent":   {"reservation_id", "tenant", "namespace", "start_ms", "end_ms"},
+	"ReservationConfirmedEvent":   {"reservation_id"},
+	"ReservationRejectedEvent":    {"reservation_id"},
+	"ReservationCancelledEvent":   {"reservation_id"},
+}
+
+// validateEventPayload - 필수 필드 누락/타입 오류를 핸들러 실행 전에 확인
+func validateEventPayload(event *SuiContractEvent) error {
+	// K8s API 요청은 공용 스키마(필드 이름과 Move 타입) 기준으로 확인
+	if strings.Contains(event.Type, apirequest.EventType) {
+		if event.EventData == nil {
+			return fmt.Errorf("missing parsedJson")
+		}
+		_, err := apirequest.Decode(event.EventData)
+		return err
+	}
+	for eventType, fields := range requiredEventFields {
+		if !strings.Contains(event.Type, eventType) {
+			continue
+		}
+		if event.EventData == nil {
+			return fmt.Errorf("missing parsedJson")
+		}
+		for _, field := range fields {
+			value, exists := event.EventData[field]
+			if !exists {
+				return fmt.Errorf("missing field %s", field)
+			}
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("field %s has unexpected type %T", field, value)
+			}
+		}
+		if eventType == "WorkerRegisteredEvent" {
+			switch event.EventData["stake_amount"].(type) {
+			case string, float64:
+			default:
+				return fmt.Errorf("missing or invalid field stake_amount")
+			}
+		}
+		if eventType == "StakeAmountChangedEvent" {
+			if _, ok := eventU64(event.EventData["new_amount"]); !ok {
+				return fmt.Errorf("missing or invalid field new_amount")
+			}
+		}
+		return nil
 	}
+	return nil
 }
 
 // handleWorkerRegisteredEvent - 워커 등록 이벤트 처리
@@ -461,6 +580,21 @@ func (s *SuiIntegration) handleWorkerRegisteredEvent(event *SuiContractEvent) {
 		return
 	}
 
+	// 역할별 스테이킹 티어 확인 (컨트랙트 검증과 동일, 역할이 없으면 worker)
+	role, _ := event.EventData["role"].(string)
+	if role == "" {
+		role = NodeRoleWorker
+	}
+	requiredStake, err := requiredStakeForRole(role)
+	if err != nil {
+		s.logger.Errorf("❌ Rejecting worker %s: %v", nodeID, err)
+		return
+	}
+	if stakeAmount < requiredStake {
+		s.logger.Errorf("❌ Rejecting worker %s: stake %d below %s tier minimum %d", nodeID, stakeAmount, role, requiredStake)
+		return
+	}
+
 	// 워커 노드 객체 생성
 	worker := &WorkerNode{
 		NodeID:        nodeID,
@@ -468,7 +602,23 @@ func (s *SuiIntegration) handleWorkerRegisteredEvent(event *SuiContractEvent) {
 		Status:        "pending",
 		StakeAmount:   uint64(stakeAmount),
 		WorkerAddress: owner,
+		Role:          role,
+	}
+	if event.Timestamp > 0 {
+		worker.RegisteredAt = time.UnixMilli(event.Timestamp)
+	}
+
+	// 승인 모드에서는 자동 승인 규칙에 맞지 않으면 운영자 심사 대기열로
+	if !s.enrollment.Admit(worker) {
+		s.logger.Infof("📋 Worker %s is waiting for enrollment approval", nodeID)
+		return
 	}
+	s.addRegisteredWorker(worker)
+}
+
+// addRegisteredWorker - 등록(또는 승인)된 워커를 풀에 추가하고 Seal 토큰 검증 후 활성화
+func (s *SuiIntegration) addRegisteredWorker(worker *WorkerNode) {
+	nodeID, sealToken := worker.NodeID, worker.SealToken
 
 	// 워커 풀에 추가
 	if err := s.workerPool.AddWorker(worker); err != nil {
@@ -482,21 +632,15 @@ func (s *SuiIntegration) handleWorkerRegisteredEvent(event *SuiContractEvent) {
 		s.logger.Infof("👥 Worker %s added to pool successfully", nodeID)
 	}
 
-	// Join token 생성 및 설정
-	if joinToken, err := s.k3sMgr.GetJoinToken(); err == nil {
-		if err := s.workerPool.SetWorkerJoinToken(nodeID, joinToken); err == nil {
-			s.logger.Infof("🎟️ Join token assigned to worker %s", nodeID)
+	// K3s 조인 토큰은 여기서 배포하지 않음: 워커가 Seal 토큰으로 /api/v1/nodes/register를 호출할 때 노드 전용 1회용 토큰 발급 (join_tokens.go)
 
-			// 조인 토큰을 컨트랙트에 저장
-			if err := s.setJoinTokenToContract(nodeID, joinToken); err != nil {
-				s.logger.Errorf("❌ Failed to store join token in contract: %v", err)
-			} else {
-				s.logger.Infof("✅ Join token stored in contract for worker %s", nodeID)
-			}
-		}
-	}
+	s.logger.Infof("💰 Stake amount: %d SUI MIST, Owner: %s", worker.StakeAmount, worker.WorkerAddress)
 
-	s.logger.Infof("💰 Stake amount: %d SUI MIST, Owner: %s", stakeAmount, owner)
+	// 시계 오차가 크면 토큰 만료 검증을 신뢰할 수 없으므로 활성화 보류
+	if err := s.clock.Check(); err != nil {
+		s.logger.Errorf("🛑 Worker %s activation deferred: %v", nodeID, err)
+		return
+	}
 
 	// 워커를 자동으로 활성화 (실제 환경에서는 검증 후)
 	if s.sealTokenMgr.ValidateSealToken(sealToken, nodeID) {
@@ -508,76 +652,125 @@ func (s *SuiIntegration) handleWorkerRegisteredEvent(event *SuiContractEvent) {
 	}
 }
 
-// handleK8sAPIRequest - K8s API 요청 스케줄링 이벤트 처리
-func (s *SuiIntegration) handleK8sAPIRequest(event *SuiContractEvent) {
+// handleK8sAPIRequest - K8s API 요청 스케줄링 이벤트 처리 (클라이언트 마감 시각을 모든 실행 단계의 context로 전달)
+func (s *SuiIntegration) handleK8sAPIRequest(ctx context.Context, event *SuiContractEvent) {
 	s.logger.Infof("📝 Processing K8s API request scheduling event")
+	receivedAt := time.Now()
 
-	// 이벤트 데이터 파싱
-	requestID, ok := event.EventData["request_id"].(string)
-	if !ok {
-		s.logger.Errorf("❌ Failed to parse request_id from event")
+	// 재전송 방지는 정확한 시계를 전제로 함
+	if err := s.clock.Check(); err != nil {
+		s.logger.Errorf("🛑 Refusing K8s API request: %v", err)
 		return
 	}
 
-	method, ok := event.EventData["method"].(string)
-	if !ok {
-		s.logger.Errorf("❌ Failed to parse method from event")
-		return
-	}
-
-	resource, ok := event.EventData["resource"].(string)
-	if !ok {
-		s.logger.Errorf("❌ Failed to parse resource from event")
+	// 이벤트 데이터 파싱 (공용 스키마, 타입은 dispatchEvent에서 검증됨)
+	request, err := apirequest.Decode(event.EventData)
+	if err != nil {
+		s.logger.Errorf("❌ Failed to parse K8s API request event: %v", err)
 		return
 	}
-
-	namespace, ok := event.EventData["namespace"].(string)
-	if !ok {
-		s.logger.Errorf("❌ Failed to parse namespace from event")
-		return
+	if event.Timestamp > 0 {
+		request.Timestamp = uint64(event.Timestamp)
 	}
-
-	name := ""
-	if nameVal, exists := event.EventData["name"]; exists {
-		name, _ = nameVal.(string)
+	if s.chainHealth != nil {
+		s.chainHealth.ObserveEventDelay(event.Timestamp, receivedAt)
 	}
-
-	payload := ""
-	if payloadVal, exists := event.EventData["payload"]; exists {
-		payload, _ = payloadVal.(string)
-	}
-
-	assignedWorker, ok := event.EventData["assigned_worker"].(string)
-	if !ok {
-		s.logger.Errorf("❌ Failed to parse assigned_worker from event")
-		return
+	requestID, assignedWorker := request.RequestID, request.AssignedWorker
+
+	// kubectl이 이미 포기한 요청(지연된 이벤트, 재처리)은 실행하지 않고 만료 응답만 기록
+	if request.DeadlineMs > 0 {
+		deadlineAt := time.UnixMilli(int64(request.DeadlineMs))
+		if !receivedAt.Before(deadlineAt) {
+			s.logger.Warnf("⌛ Request %s expired %s ago, skipping execution", requestID, receivedAt.Sub(deadlineAt).Round(time.Millisecond))
+			result := expiredResult(request, "before execution")
+			if s.audit != nil {
+				s.audit.RecordK8sRequest(request, result, receivedAt)
+			}
+			s.storeResultToContract(result)
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadlineAt)
+		defer cancel()
 	}
 
-	// K8s API 요청 객체 생성
-	request := &K8sAPIRequest{
-		RequestID:    requestID,
-		Method:       method,
-		Resource:     resource,
-		Namespace:    namespace,
-		Name:         name,
-		Payload:      payload,
-		Timestamp:    fmt.Sprintf("%d", event.Timestamp),
-	}
+	s.trackInFlight(request)
+	defer s.untrackInFlight(requestID)
 
 	s.logger.Infof("🚀 NEW K8S API REQUEST RECEIVED FROM CONTRACT!")
 	s.logger.Infof("🎯 Executing K8s API: %s %s in namespace %s (assigned to %s)",
 		request.Method, request.Resource, request.Namespace, assignedWorker)
-	s.logger.Infof("📦 Request ID: %s, Payload: %s", requestID, payload)
+	s.logger.Infof("📦 Request ID: %s, Payload: %s", requestID, request.Payload)
 
 	// K3s가 실행 중인지 확인
-	if !s.isK3sActuallyRunning() {
+	if !s.isK3sActuallyRunning(ctx) {
 		s.logger.Warn("⚠️ K3s is not ready, queuing request")
 		// TODO: 요청을 큐에 저장하고 나중에 처리
 		return
 	}
 
-	// 실제 K8s API 실행
-	result := s.executeK8sAPI(request)
+	// 어드미션을 통과한 요청은 kubectl로 K3s에 적용 (StatefulSet 등은 K3s 내장 컨트롤러가 etcd 상태로 reconcile)
+	// 암호화된 본문은 권한/어드미션 검사 전에 엔클레이브 안에서 복호화
+	var result *K8sAPIResult
+	if err := s.readOnly.CheckRequest(request); err != nil {
+		s.logger.Warnf("🧊 Request %s not executed: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if err := s.openPayload(request); err != nil {
+		s.logger.Warnf("🔒 Request %s not executed: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if err := s.authorizeRequest(request, assignedWorker); err != nil {
+		s.logger.Warnf("🚫 Request %s denied: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if err := s.throttleRequest(request); err != nil {
+		s.logger.Warnf("🚦 Request %s throttled: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if err := s.controllerMgr.Admit(request); err != nil {
+		s.logger.Errorf("❌ Request %s rejected: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if err := s.provisioner.Ensure(request, assignedWorker); err != nil {
+		// 어드미션을 통과한 요청만 네임스페이스를 만듦 (거부될 요청이 빈 네임스페이스를 남기지 않도록)
+		s.logger.Warnf("🏗️ Request %s not executed: %v", requestID, err)
+		result = &K8sAPIResult{
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+		}
+	} else if ctx.Err() != nil {
+		result = expiredResult(request, "while waiting for admission")
+	} else {
+		result = s.executeK8sAPI(ctx, request)
+	}
+
+	// 감사 로그 기록
+	if s.audit != nil {
+		s.audit.RecordK8sRequest(request, result, receivedAt)
+	}
 
 	// 결과를 Contract에 저장
 	s.storeResultToContract(result)
@@ -590,6 +783,129 @@ func (s *SuiIntegration) handleK8sAPIRequest(event *SuiContractEvent) {
 	}
 }
 
+// expiredResult - 마감 시각이 지나 실행하지 않았거나 중단한 요청의 응답 (K8s Timeout 사유)
+func expiredResult(request *K8sAPIRequest, stage string) *K8sAPIResult {
+	return &K8sAPIResult{
+		RequestID: request.RequestID,
+		Success:   false,
+		Error: fmt.Sprintf("Timeout: request deadline %s exceeded %s",
+			time.UnixMilli(int64(request.DeadlineMs)).UTC().Format(time.RFC3339Nano), stage),
+		Timestamp: fmt.Sprintf("%d", time.Now().Unix()),
+	}
+}
+
+// trackInFlight - 실행 중 요청 등록 (디버그 API 노출용)
+func (s *SuiIntegration) trackInFlight(request *K8sAPIRequest) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	s.inFlight[request.RequestID] = request
+}
+
+// untrackInFlight - 실행 완료 요청 제거
+func (s *SuiIntegration) untrackInFlight(requestID string) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+	delete(s.inFlight, requestID)
+}
+
+// InFlightRequests - 실행 중 요청 목록 (payload 제외)
+func (s *SuiIntegration) InFlightRequests() []K8sAPIRequest {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	requests := make([]K8sAPIRequest, 0, len(s.inFlight))
+	for _, request := range s.inFlight {
+		copied := *request
+		copied.Payload = ""
+		copied.SealToken = ""
+		requests = append(requests, copied)
+	}
+	return requests
+}
+
+// EventQueueDepth - 처리 대기 중 이벤트 수와 큐 용량
+func (s *SuiIntegration) EventQueueDepth() (int, int) {
+	return len(s.eventChan), cap(s.eventChan)
+}
+
+// authorizeRequest - 범위 제한 토큰이면 범위 확인 후, 워커 소유자가 아닌 요청자는 위임 권한 확인
+func (s *SuiIntegration) authorizeRequest(request *K8sAPIRequest, assignedWorker string) error {
+	if err := s.scopes.CheckRequest(request); err != nil {
+		return err
+	}
+	if s.rbac == nil || request.Requester == "" {
+		return nil
+	}
+
+	worker, exists := s.workerPool.GetWorker(assignedWorker)
+	if !exists {
+		return nil
+	}
+
+	return s.rbac.Authorize(worker.WorkerAddress, request.Requester, request.Method, request.Resource, request.Namespace)
+}
+
+// throttleRequest - 요청자별 QPS 제한 (요청자가 없으면 제한 없음)
+func (s *SuiIntegration) throttleRequest(request *K8sAPIRequest) error {
+	if s.quota == nil || request.Requester == "" {
+		return nil
+	}
+
+	if allowed, wait := s.quota.Allow(request.Requester); !allowed {
+		return fmt.Errorf("TooManyRequests: tenant %s exceeded its request quota, retry after %s", request.Requester, wait.Round(time.Second))
+	}
+	return nil
+}
+
+// eventU64 - Sui u64 필드 (문자열 또는 숫자로 인코딩됨)
+func eventU64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		return parsed, err == nil
+	case float64:
+		return uint64(v), v >= 0
+	}
+	return 0, false
+}
+
+// handleStakeAmountChanged - 추가 예치/부분 인출 반영 (재등록 없이 스테이킹 양과 티어 라벨 갱신)
+func (s *SuiIntegration) handleStakeAmountChanged(event *SuiContractEvent) {
+	nodeID := event.EventData["node_id"].(string)
+	newAmount, _ := eventU64(event.EventData["new_amount"])
+	oldAmount, _ := eventU64(event.EventData["old_amount"])
+
+	if err := s.workerPool.UpdateWorkerStake(nodeID, newAmount); err != nil {
+		s.logger.Warnf("⚠️ Worker %s not found in local pool, syncing from contract", nodeID)
+		if err := s.poolSync.SyncWorker(nodeID); err != nil {
+			s.logger.Errorf("❌ Failed to sync worker %s from contract: %v", nodeID, err)
+		}
+		return
+	}
+
+	// 컨트랙트가 역할 최소치 미만 인출을 막지만, 티어 설정이 어긋난 경우를 알림
+	if worker, ok := s.workerPool.GetWorker(nodeID); ok && worker.Role != "" {
+		if required, err := requiredStakeForRole(worker.Role); err == nil && newAmount < required {
+			s.logger.Warnf("⚠️ Worker %s stake %d is below the %s tier minimum %d", nodeID, newAmount, worker.Role, required)
+		}
+	}
+	s.history.RecordEvent(nodeID, "stake", fmt.Sprintf("%d → %d", oldAmount, newAmount))
+}
+
+// handleEmergencyUnstake - 포기 증명으로 등록이 해제된 노드를 풀에서 제거
+func (s *SuiIntegration) handleEmergencyUnstake(event *SuiContractEvent) {
+	nodeID := event.EventData["node_id"].(string)
+	owner, _ := event.EventData["owner"].(string)
+	amount, _ := eventU64(event.EventData["amount"])
+
+	if err := s.workerPool.RemoveWorker(nodeID); err != nil {
+		s.logger.Debugf("Emergency-unstaked worker %s was not in the local pool: %v", nodeID, err)
+	}
+	s.abandonment.forget(nodeID)
+	s.logger.Infof("🪦 Worker %s emergency-unstaked by %s (%d returned)", nodeID, owner, amount)
+	s.history.RecordEvent(nodeID, "emergency_unstake", fmt.Sprintf("%d returned to %s", amount, owner))
+}
+
 // handleWorkerStatusEvent - 워커 상태 변경 이벤트 처리
 func (s *SuiIntegration) handleWorkerStatusEvent(event *SuiContractEvent) {
 	s.logger.Infof("🔄 Processing worker status change event")
@@ -615,8 +931,11 @@ func (s *SuiIntegration) handleWorkerStatusEvent(event *SuiContractEvent) {
 	// 로컬 워커 풀 상태 업데이트
 	if err := s.workerPool.UpdateWorkerStatus(nodeID, newStatus); err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			s.logger.Warnf("⚠️ Worker %s not found in local pool, may need to sync from contract", nodeID)
-			// TODO: 워커가 로컬에 없으면 contract에서 워커 정보를 가져와서 추가
+			// 워커가 로컬에 없으면 contract에서 워커 정보를 가져와서 추가
+			s.logger.Warnf("⚠️ Worker %s not found in local pool, syncing from contract", nodeID)
+			if err := s.poolSync.SyncWorker(nodeID); err != nil {
+				s.logger.Errorf("❌ Failed to sync worker %s from contract: %v", nodeID, err)
+			}
 		} else {
 			s.logger.Errorf("❌ Failed to update worker status: %v", err)
 		}
@@ -624,6 +943,7 @@ func (s *SuiIntegration) handleWorkerStatusEvent(event *SuiContractEvent) {
 	}
 
 	s.logger.Infof("✅ Worker %s status updated: %s → %s", nodeID, oldStatus, newStatus)
+	s.history.RecordEvent(nodeID, "status", oldStatus+" → "+newStatus)
 
 	// 상태에 따른 추가 작업
 	switch newStatus {
@@ -637,7 +957,7 @@ func (s *SuiIntegration) handleWorkerStatusEvent(event *SuiContractEvent) {
 }
 
 // executeK8sAPI - 실제 K8s API 실행
-func (s *SuiIntegration) executeK8sAPI(request *K8sAPIRequest) *K8sAPIResult {
+func (s *SuiIntegration) executeK8sAPI(ctx context.Context, request *K8sAPIRequest) *K8sAPIResult {
 	startTime := time.Now()
 
 	result := &K8sAPIResult{
@@ -653,30 +973,53 @@ func (s *SuiIntegration) executeK8sAPI(request *K8sAPIRequest) *K8sAPIResult {
 		return result
 	}
 
+	// DELETE: DeleteOptions 전제 조건 확인
+	var deleteOptions *K8sDeleteOptions
+	var deleted []byte
+	if strings.ToUpper(request.Method) == "DELETE" {
+		deleteOptions, _ = parseDeleteOptions(request.Payload)
+		if err := s.checkDeletePreconditions(ctx, request, deleteOptions); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			return result
+		}
+		// 소프트 삭제: 삭제가 성공하면 휴지통에 넣을 객체를 미리 조회
+		if s.trash.Enabled(request.Resource) && request.Name != "" && len(deleteOptions.DryRun) == 0 {
+			deleted, _, _ = s.runCommand(ctx, "kubectl", nil, append(append([]string{"get"}, objectRef(request)...), "-o", "json")...)
+		}
+	}
+
 	// kubectl 실행
 	s.logger.Infof("🎯 Executing kubectl command: kubectl %v", strings.Join(args, " "))
 	s.logger.Infof("📋 Request details - Method: %s, Resource: %s, Namespace: %s, Name: %s",
 		request.Method, request.Resource, request.Namespace, request.Name)
 
-	cmd := exec.Command("kubectl", args...)
-	cmd.Env = append(os.Environ(), "KUBECONFIG=/etc/rancher/k3s/k3s.yaml")
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	var stdin []byte
+	if args[0] == "apply" {
+		stdin = []byte(request.Payload)
+	}
+	stdout, stderr, err := s.runCommand(ctx, "kubectl", stdin, args...)
 
 	result.ExecutionTime = time.Since(startTime).Milliseconds()
-	result.Output = stdout.String()
-
-	if err != nil {
+	result.Output = string(stdout)
+
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		// 마감 시각에 kubectl을 중단했으므로 부분 적용 여부는 클라이언트가 다시 조회해야 함
+		expired := expiredResult(request, "during execution")
+		expired.ExecutionTime = result.ExecutionTime
+		s.logger.Warnf("⌛ kubectl for request %s cancelled at its deadline", request.RequestID)
+		return expired
+	} else if err != nil {
 		result.Success = false
-		result.Error = fmt.Sprintf("Command failed: %v, stderr: %s", err, stderr.String())
+		result.Error = fmt.Sprintf("Command failed: %v, stderr: %s", err, stderr)
 		s.logger.Errorf("❌ kubectl command failed: %v", err)
-		s.logger.Errorf("❌ stderr: %s", stderr.String())
+		s.logger.Errorf("❌ stderr: %s", stderr)
 	} else {
 		result.Success = true
+		if deleteOptions != nil && request.Name != "" && len(deleteOptions.DryRun) == 0 {
+			result.Output = s.deletionResult(ctx, request)
+			s.trash.Keep(request.Resource, deleted, request.Requester, "chain")
+		}
 		s.logger.Infof("✅ kubectl command succeeded in %dms", result.ExecutionTime)
 		if result.Output != "" {
 			s.logger.Infof("📤 kubectl output: %s", result.Output)
@@ -734,6 +1077,19 @@ func (s *SuiIntegration) buildKubectlCommand(request *K8sAPIRequest) []string {
 		args = append(args, "-n", request.Namespace)
 	}
 
+	// DELETE: DeleteOptions를 kubectl 플래그로 (잘못된 옵션은 거부)
+	if strings.ToUpper(request.Method) == "DELETE" {
+		options, err := parseDeleteOptions(request.Payload)
+		if err != nil {
+			return nil
+		}
+		flags, err := options.kubectlFlags()
+		if err != nil {
+			return nil
+		}
+		args = append(args, flags...)
+	}
+
 	return args
 }
 
@@ -746,29 +1102,55 @@ func (s *SuiIntegration) storeResultToContract(result *K8sAPIResult) {
 		return
 	}
 
-	// TODO: 실제 Sui Contract 호출로 결과 저장
-	// Move 함수 호출: store_k8s_result(request_id, success, output, error)
+	if s.responses == nil {
+		s.logger.Infof("💾 Result not recorded on-chain (no response store): %s (Success: %v)",
+			result.RequestID, result.Success)
+		return
+	}
 
-	s.logger.Infof("💾 Storing result to contract: %s (Success: %v)",
-		result.RequestID, result.Success)
+	// 압축/오프체인 저장/배치는 Response Store가 처리
+	s.responses.Submit(result)
 }
 
 // isK3sActuallyRunning - K3s가 실제로 실행 중인지 확인
-func (s *SuiIntegration) isK3sActuallyRunning() bool {
+func (s *SuiIntegration) isK3sActuallyRunning(ctx context.Context) bool {
 	// kubeconfig 파일 존재 확인
-	if _, err := os.Stat("/etc/rancher/k3s/k3s.yaml"); err != nil {
+	if _, err := os.Stat(s.k3sMgr.GetKubeconfig()); err != nil {
 		return false
 	}
 
 	// kubectl 명령으로 API 서버 상태 확인
-	cmd := exec.Command("kubectl", "get", "nodes", "--kubeconfig", "/etc/rancher/k3s/k3s.yaml")
-	if err := cmd.Run(); err != nil {
+	if _, _, err := s.runCommand(ctx, "kubectl", nil, "get", "nodes"); err != nil {
 		return false
 	}
 
 	return true
 }
 
+// runCommand - 외부 명령 실행 (kubectl은 마스터 kubeconfig 사용, context가 끝나면 프로세스 종료)
+func (s *SuiIntegration) runCommand(ctx context.Context, name string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if s.runner != nil {
+		return s.runner(name, stdin, args...)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if name == "kubectl" {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+s.k3sMgr.GetKubeconfig())
+	}
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
 // periodicHealthCheck - 주기적 상태 체크
 func (s *SuiIntegration) periodicHealthCheck(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second)
@@ -779,7 +1161,7 @@ func (s *SuiIntegration) periodicHealthCheck(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if s.isK3sActuallyRunning() {
+			if s.isK3sActuallyRunning(ctx) {
 				s.logger.Debug("💚 K3s health check passed")
 			} else {
 				s.logger.Warn("💛 K3s health check failed")
@@ -788,83 +1170,78 @@ func (s *SuiIntegration) periodicHealthCheck(ctx context.Context) {
 	}
 }
 
-// startMockMode - Mock 모드로 실행 (Contract 없이 테스트)
-func (s *SuiIntegration) startMockMode(ctx context.Context) {
-	s.logger.Info("🧪 Sui Integration running in mock mode")
-
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			s.logger.Info("🛑 Sui Integration stopping...")
-			return
-		case <-ticker.C:
-			s.processMockEvent()
+// callContract - sui CLI로 Move 함수 호출 (Sui 외 백엔드는 백엔드로 제출)
+func (s *SuiIntegration) callContract(module, function string, args ...string) error {
+	if !s.onSui() {
+		callArgs := make([]interface{}, len(args))
+		for i, arg := range args {
+			callArgs[i] = arg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := s.backend.SubmitTx(ctx, chain.NewTx(module, function, callArgs...)); err != nil {
+			return fmt.Errorf("%s::%s call failed: %v", module, function, err)
 		}
+		return nil
 	}
-}
 
-// processMockEvent - Mock 이벤트 처리
-func (s *SuiIntegration) processMockEvent() {
-	s.logger.Info("🔄 Processing mock Sui event...")
-
-	// Mock K8s API 요청 생성
-	mockRequest := &K8sAPIRequest{
-		RequestID:    fmt.Sprintf("mock_%d", time.Now().Unix()),
-		Method:       "GET",
-		Resource:     "pods",
-		Namespace:    "default",
-		SealToken:    "mock_seal_token",
-		Requester:    "mock_user",
-		Priority:     1,
-		Timestamp:    fmt.Sprintf("%d", time.Now().Unix()),
+	// SUI 클라이언트 명령어 구성
+	cmdArgs := []string{"client", "call",
+		"--package", s.activeContract().Package,
+		"--module", module,
+		"--function", function,
 	}
-
-	s.logger.Infof("🔧 Processing K8s API request: %s %s", mockRequest.Method, mockRequest.Resource)
-
-	if !s.isK3sActuallyRunning() {
-		s.logger.Warn("⚠️ K3s is not running, skipping request")
-		return
+	if len(args) > 0 {
+		cmdArgs = append(cmdArgs, "--args")
+		cmdArgs = append(cmdArgs, args...)
 	}
+	cmdArgs = append(cmdArgs, "--gas-budget", "10000000")
 
-	// 실제 kubectl 명령 실행
-	result := s.executeK8sAPI(mockRequest)
-	s.logger.Infof("✅ Mock K8s API request completed: Success=%v", result.Success)
-
-	if result.Success {
-		s.logger.Debugf("📊 Output: %s", result.Output)
-	} else {
-		s.logger.Errorf("❌ Error: %s", result.Error)
-	}
-}
-
-// setJoinTokenToContract - 조인 토큰을 컨트랙트에 저장
-func (s *SuiIntegration) setJoinTokenToContract(nodeID, joinToken string) error {
-	// SUI 클라이언트 명령어 구성
-	cmd := exec.Command("sui", "client", "call",
-		"--package", s.contractAddr,
-		"--module", "worker_registry",
-		"--function", "set_join_token",
-		"--args", s.registryAddr, nodeID, joinToken,
-		"--gas-budget", "10000000",
-	)
-
-	s.logger.Debugf("🔗 Executing SUI command: %s", strings.Join(cmd.Args, " "))
+	s.logger.Debugf("🔗 Executing SUI command: sui %s", strings.Join(cmdArgs, " "))
 
 	// 명령 실행
-	output, err := cmd.CombinedOutput()
+	stdout, stderr, err := s.runCommand(context.Background(), "sui", nil, cmdArgs...)
+	output := append(stdout, stderr...)
 	if err != nil {
 		s.logger.Errorf("❌ Failed to execute SUI command: %v", err)
 		s.logger.Errorf("❌ Command output: %s", string(output))
-		return fmt.Errorf("failed to set join token in contract: %v", err)
+		return fmt.Errorf("%s::%s call failed: %v", module, function, err)
 	}
 
 	s.logger.Debugf("✅ SUI command output: %s", string(output))
 	return nil
 }
 
+// rpcCall - Sui JSON-RPC 호출 후 result 필드 디코딩 (공용 Sui 클라이언트)
+func (s *SuiIntegration) rpcCall(method string, params []interface{}, result interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.chain.Call(ctx, method, params, result)
+}
+
+// latestCheckpoint - 최신 체크포인트 시퀀스 번호
+func (s *SuiIntegration) latestCheckpoint() (uint64, error) {
+	var sequence string
+	if err := s.rpcCall("sui_getLatestCheckpointSequenceNumber", []interface{}{}, &sequence); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(sequence, 10, 64)
+}
+
+// EventCursor - 이벤트 조회가 따라잡은 체크포인트 (아직 성공한 조회가 없으면 0)
+func (s *SuiIntegration) EventCursor() uint64 {
+	s.cursorMutex.RLock()
+	defer s.cursorMutex.RUnlock()
+	return s.cursor
+}
+
+// chainTime - 최신 체크포인트 타임스탬프 조회 (체인 기준 시각)
+func (s *SuiIntegration) chainTime() (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.chain.ChainTime(ctx)
+}
+
 // getEnvOrDefault - 환경변수 또는 기본값 반환
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
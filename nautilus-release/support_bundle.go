@@ -0,0 +1,304 @@
+// Support Bundle - 문제 보고용 진단 아카이브(설정, 최근 로그, 상태, 버전, 이벤트 큐, 증명)를 비밀 정보를 가린 tar.gz로 생성
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+GET /debug/support-bundle (관리자 토큰) - daasctl support-bundle이 받아 워커/Gateway 정보와 함께 묶습니다.
+
+	manifest.json      생성 시각, 버전, 파일 목록, 수집 실패 항목
+	version.json       빌드 버전
+	environment.json   환경변수 (KEY/TOKEN/SECRET/PASSWORD/CREDENTIAL 이름은 값 숨김)
+	state.json         런타임, 이벤트 큐 깊이, 진행 중 요청, 저장소 객체 수
+	dump.json          워커 풀(Seal/조인 토큰 숨김), 접근 권한, 용량, 노드 상태
+	readiness.json     준비 상태와 미충족 조건
+	events.json        이벤트 큐, 확정 대기, dead letter
+	chain-drift.json   마지막 체인 정합성 검사 보고서
+	attestation.json   번들 생성 시점의 서명된 TEE 증명 문서
+	metrics.txt        /metrics 전체
+	logs.txt           최근 로그 (NAUTILUS_SUPPORT_LOG_LINES줄, 토큰/키 형태 값 숨김)
+	k3s/*.txt          kubectl version, 노드, 최근 이벤트 (K3s 실행 중일 때)
+*/
+
+const defaultSupportLogLines = 2000
+
+var (
+	// 로그 줄의 비밀 값: "token=...", "Bearer ...", Sui 개인키, 0x 없는 64자리 hex (Seal 토큰)
+	sensitiveLogValue = regexp.MustCompile(`(?i)\b([a-z_]*(?:token|secret|password|private_?key|seed)[a-z_]*)(["']?\s*[=:]\s*["']?)[^\s"',}]+`)
+	bearerLogValue    = regexp.MustCompile(`(?i)\bbearer\s+[^\s"',]+`)
+	suiPrivateKey     = regexp.MustCompile(`suiprivkey1[0-9a-z]+`)
+	sealTokenValue    = regexp.MustCompile(`(^|[^0-9a-fA-Fx])[0-9a-f]{64}\b`)
+)
+
+// redactLogLine - 로그 한 줄에서 비밀 값 제거
+func redactLogLine(line string) string {
+	line = sensitiveLogValue.ReplaceAllString(line, "${1}${2}"+redacted)
+	line = bearerLogValue.ReplaceAllString(line, "Bearer "+redacted)
+	line = suiPrivateKey.ReplaceAllString(line, redacted)
+	return sealTokenValue.ReplaceAllString(line, "${1}"+redacted)
+}
+
+// sensitiveName - 값을 숨길 필드/환경변수 이름인지
+func sensitiveName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range sensitiveEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue - JSON으로 변환한 값에서 민감한 이름의 필드 값을 숨김 (이벤트 payload 등 구조를 모르는 값용)
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if sensitiveName(key) {
+				if field != nil && field != "" {
+					typed[key] = redacted
+				}
+				continue
+			}
+			typed[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactValue(item)
+		}
+	case string:
+		return redactLogLine(typed)
+	}
+	return value
+}
+
+// logRing - 최근 로그 줄 보관 (logrus hook)
+type logRing struct {
+	lines []string
+	next  int
+	full  bool
+	mutex sync.Mutex
+
+	allow func(*logrus.Entry) bool // 런타임 로그 레벨/추적 필터 (LogControl.Allow, 없으면 모두 보관)
+}
+
+// newLogRing - NAUTILUS_SUPPORT_LOG_LINES줄(기본 2000)을 보관하는 hook 생성
+func newLogRing() *logRing {
+	size, err := strconv.Atoi(getEnvOrDefault("NAUTILUS_SUPPORT_LOG_LINES", strconv.Itoa(defaultSupportLogLines)))
+	if err != nil || size <= 0 {
+		size = defaultSupportLogLines
+	}
+	return &logRing{lines: make([]string, size)}
+}
+
+func (l *logRing) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (l *logRing) Fire(entry *logrus.Entry) error {
+	if l.allow != nil && !l.allow(entry) {
+		return nil
+	}
+	line := fmt.Sprintf("%s %-7s %s", entry.Time.UTC().Format(time.RFC3339Nano), entry.Level.String(), entry.Message)
+	for key, value := range entry.Data {
+		line += fmt.Sprintf(" %s=%v", key, value)
+	}
+	line = redactLogLine(line)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.lines[l.next] = line
+	l.next = (l.next + 1) % len(l.lines)
+	if l.next == 0 {
+		l.full = true
+	}
+	return nil
+}
+
+// Lines - 오래된 순서의 보관 로그
+func (l *logRing) Lines() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.full {
+		return append([]string(nil), l.lines[:l.next]...)
+	}
+	return append(append([]string(nil), l.lines[l.next:]...), l.lines[:l.next]...)
+}
+
+// bundleFile - 번들 항목 하나 (수집 실패는 manifest에 기록하고 나머지는 계속)
+type bundleFile struct {
+	name    string
+	collect func() ([]byte, error)
+}
+
+func jsonFile(name string, collect func() (interface{}, error)) bundleFile {
+	return bundleFile{name: name, collect: func() ([]byte, error) {
+		value, err := collect()
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(value, "", "  ")
+	}}
+}
+
+// redactedJSON - 구조를 모르는 값을 JSON 왕복 후 민감한 필드를 숨김
+func redactedJSON(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return nil, err
+	}
+	return redactValue(generic), nil
+}
+
+// supportBundleFiles - 번들에 넣을 항목 (구성요소가 없으면 건너뜀)
+func (d *DebugServer) supportBundleFiles() []bundleFile {
+	files := []bundleFile{
+		jsonFile("version.json", func() (interface{}, error) { return version.Get("master"), nil }),
+		jsonFile("environment.json", func() (interface{}, error) { return sanitizedEnvironment(), nil }),
+		jsonFile("state.json", func() (interface{}, error) { return d.runtimeState(), nil }),
+		jsonFile("dump.json", func() (interface{}, error) { return redactedJSON(d.stateDump()) }),
+		jsonFile("events.json", func() (interface{}, error) {
+			depth, capacity := d.sui.EventQueueDepth()
+			events := map[string]interface{}{
+				"queue_depth": depth, "queue_capacity": capacity, "in_flight_requests": d.sui.InFlightRequests(),
+			}
+			if d.sui.finality != nil {
+				events["finality_pending"] = d.sui.finality.PendingCount()
+			}
+			if d.deadLetters != nil {
+				events["dead_letters"] = d.deadLetters.Entries()
+			}
+			return redactedJSON(events)
+		}),
+	}
+	if d.readiness != nil {
+		files = append(files, jsonFile("readiness.json", func() (interface{}, error) {
+			ready, pending := d.readiness.Ready()
+			return map[string]interface{}{"ready": ready, "pending": pending}, nil
+		}))
+	}
+	if d.poolSync != nil {
+		files = append(files, jsonFile("chain-drift.json", func() (interface{}, error) { return d.poolSync.Report(), nil }))
+	}
+	if d.signer != nil {
+		files = append(files, jsonFile("attestation.json", func() (interface{}, error) {
+			nonce := make([]byte, 16)
+			rand.Read(nonce)
+			return d.signer.Attest("support-bundle-" + hex.EncodeToString(nonce)), nil
+		}))
+	}
+	if d.metrics != nil {
+		files = append(files, bundleFile{name: "metrics.txt", collect: func() ([]byte, error) {
+			var buf bytes.Buffer
+			d.metrics.writeAll(&buf)
+			return buf.Bytes(), nil
+		}})
+	}
+	if d.logs != nil {
+		files = append(files, bundleFile{name: "logs.txt", collect: func() ([]byte, error) {
+			return []byte(strings.Join(d.logs.Lines(), "\n") + "\n"), nil
+		}})
+	}
+	if d.k3sMgr.IsRunning() {
+		for name, args := range map[string][]string{
+			"k3s/version.txt": {"version", "-o", "yaml"},
+			"k3s/nodes.txt":   {"get", "nodes", "-o", "wide"},
+			"k3s/events.txt":  {"get", "events", "-A", "--sort-by=.lastTimestamp"},
+		} {
+			args := args
+			files = append(files, bundleFile{name: name, collect: func() ([]byte, error) {
+				output, err := d.k3sMgr.RunKubectl(nil, args...)
+				return []byte(redactLogLine(string(output))), err
+			}})
+		}
+	}
+	return files
+}
+
+// writeSupportBundle - tar.gz 작성 (manifest.json은 마지막에 추가)
+func (d *DebugServer) writeSupportBundle(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	now := time.Now()
+
+	add := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := archive.Write(data)
+		return err
+	}
+
+	var written []string
+	failures := make(map[string]string)
+	for _, file := range d.supportBundleFiles() {
+		data, err := file.collect()
+		if err != nil {
+			failures[file.name] = err.Error()
+			if len(data) == 0 {
+				continue
+			}
+		}
+		if err := add(file.name, data); err != nil {
+			return err
+		}
+		written = append(written, file.name)
+	}
+
+	manifest, err := json.MarshalIndent(map[string]interface{}{
+		"component":    "master",
+		"version":      version.Get("master").String(),
+		"generated_at": now.UTC(),
+		"files":        written,
+		"errors":       failures,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := add("manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// handleSupportBundle - 지원 번들 다운로드
+func (d *DebugServer) handleSupportBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	d.logger.Infof("🧰 Support bundle requested from %s", r.RemoteAddr)
+	filename := fmt.Sprintf("nautilus-support-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := d.writeSupportBundle(w); err != nil {
+		d.logger.Errorf("❌ Failed to write support bundle: %v", err)
+	}
+}
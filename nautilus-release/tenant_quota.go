@@ -0,0 +1,305 @@
+// Tenant Quota - 요청자 지갑별 API 요청 계량 및 스테이킹 비례 QPS 제한
+//
+// 테넌트는 Gateway가 서명해 전달하는 Impersonate-User(HTTP 경로) 또는 컨트랙트 이벤트의
+// requester(이벤트 경로)로 식별합니다. QPS는 기본값에 소유 워커 스테이킹에 비례한 몫을 더한
+// 값이며, 초과 시 K8s와 동일하게 429 + Retry-After로 응답합니다.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// tenantBucket - 테넌트별 토큰 버킷과 누적 사용량
+type tenantBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	allowed   uint64
+	throttled uint64
+	egress    uint64 // 테넌트 Pod 송신 바이트 (워커 하트비트 pod_network 집계)
+	ingress   uint64
+	lastSeen  time.Time
+
+	reportedAllowed   uint64 // 사용량 기록으로 넘긴 누적값
+	reportedThrottled uint64
+	reportedEgress    uint64
+	reportedIngress   uint64
+}
+
+// TenantUsage - 테넌트가 조회하는 사용량
+type TenantUsage struct {
+	Tenant    string    `json:"tenant"`
+	Stake     uint64    `json:"stake"`
+	QPS       float64   `json:"qps"`
+	Burst     float64   `json:"burst"`
+	Available float64   `json:"available"`
+	Allowed   uint64    `json:"allowed"`
+	Throttled uint64    `json:"throttled"`
+	Egress    uint64    `json:"egress_bytes"`
+	Ingress   uint64    `json:"ingress_bytes"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// TenantThrottler - 테넌트별 토큰 버킷 관리
+type TenantThrottler struct {
+	logger       *logrus.Logger
+	workerPool   *WorkerPool
+	baseQPS      float64
+	maxQPS       float64
+	stakePerQPS  float64
+	burstSeconds float64
+	buckets      map[string]*tenantBucket
+	mutex        sync.Mutex
+}
+
+// NewTenantThrottler - 새 Tenant Throttler 생성
+// TENANT_BASE_QPS(기본 1), TENANT_MAX_QPS(기본 50), TENANT_STAKE_PER_QPS(기본 MIN_STAKE_AMOUNT),
+// TENANT_BURST_SECONDS(기본 5초 분량)
+func NewTenantThrottler(logger *logrus.Logger, workerPool *WorkerPool) *TenantThrottler {
+	return &TenantThrottler{
+		logger:       logger,
+		workerPool:   workerPool,
+		baseQPS:      envFloat("TENANT_BASE_QPS", 1),
+		maxQPS:       envFloat("TENANT_MAX_QPS", 50),
+		stakePerQPS:  envFloat("TENANT_STAKE_PER_QPS", envFloat("MIN_STAKE_AMOUNT", 1000000)),
+		burstSeconds: envFloat("TENANT_BURST_SECONDS", 5),
+		buckets:      make(map[string]*tenantBucket),
+	}
+}
+
+// envFloat - 양수 실수 환경 변수 (잘못된 값이면 기본값)
+func envFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnvOrDefault(key, ""), 64)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// limits - 스테이킹에 비례한 QPS와 버스트 크기
+func (t *TenantThrottler) limits(tenant string) (uint64, float64, float64) {
+	stake := t.workerPool.StakeByOwner(tenant)
+	qps := math.Min(t.baseQPS+float64(stake)/t.stakePerQPS, t.maxQPS)
+	return stake, qps, qps * t.burstSeconds
+}
+
+// Allow - 요청 1건 허용 여부 (거부 시 다음 토큰까지 대기 시간)
+func (t *TenantThrottler) Allow(tenant string) (bool, time.Duration) {
+	_, qps, burst := t.limits(tenant)
+	now := time.Now()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	bucket, exists := t.buckets[tenant]
+	if !exists {
+		bucket = &tenantBucket{tokens: burst, updatedAt: now}
+		t.buckets[tenant] = bucket
+	}
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*qps)
+	bucket.updatedAt = now
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		bucket.throttled++
+		wait := time.Duration((1 - bucket.tokens) / qps * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	bucket.allowed++
+	return true, 0
+}
+
+// AddNetwork - 테넌트 Pod의 송수신 바이트 누적 (요청 토큰에는 영향 없음)
+func (t *TenantThrottler) AddNetwork(tenant string, egress, ingress uint64) {
+	_, _, burst := t.limits(tenant)
+	now := time.Now()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	bucket, exists := t.buckets[tenant]
+	if !exists {
+		bucket = &tenantBucket{tokens: burst, updatedAt: now}
+		t.buckets[tenant] = bucket
+	}
+	bucket.egress += egress
+	bucket.ingress += ingress
+	bucket.lastSeen = now
+}
+
+// Usage - 테넌트 사용량 조회
+func (t *TenantThrottler) Usage(tenant string) TenantUsage {
+	stake, qps, burst := t.limits(tenant)
+	usage := TenantUsage{Tenant: tenant, Stake: stake, QPS: qps, Burst: burst, Available: burst}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if bucket, exists := t.buckets[tenant]; exists {
+		usage.Available = math.Min(burst, bucket.tokens+time.Since(bucket.updatedAt).Seconds()*qps)
+		usage.Allowed = bucket.allowed
+		usage.Throttled = bucket.throttled
+		usage.Egress = bucket.egress
+		usage.Ingress = bucket.ingress
+		usage.LastSeen = bucket.lastSeen
+	}
+	return usage
+}
+
+// Middleware - Impersonate-User 기준 제한 (테넌트 미지정 요청은 통과)
+func (t *TenantThrottler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("Impersonate-User")
+		if tenant == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, wait := t.Allow(tenant)
+		if !allowed {
+			t.logger.Warnf("🚦 Throttled tenant %s: %s %s", tenant, r.Method, r.URL.Path)
+			writeTooManyRequests(w, tenant, wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeTooManyRequests - K8s API 서버와 같은 429 Status 응답
+func writeTooManyRequests(w http.ResponseWriter, tenant string, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("X-Kubernetes-PF-FlowSchema-UID", "tenant-stake")
+	w.Header().Set("X-Kubernetes-PF-PriorityLevel-UID", "tenant-"+tenant)
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]interface{}{},
+		"status":     "Failure",
+		"message":    fmt.Sprintf("tenant %s exceeded its request quota, please try again later", tenant),
+		"reason":     "TooManyRequests",
+		"details":    map[string]interface{}{"retryAfterSeconds": retryAfter},
+		"code":       http.StatusTooManyRequests,
+	})
+}
+
+// TenantUsageDelta - 마지막 사용량 기록 이후 늘어난 요청 수와 송수신 바이트
+type TenantUsageDelta struct {
+	Tenant    string
+	Allowed   uint64
+	Throttled uint64
+	Egress    uint64
+	Ingress   uint64
+}
+
+// UsageDeltas - 테넌트별 미기록 사용량을 꺼내고 기록한 것으로 표시 (변화 없는 테넌트 제외)
+func (t *TenantThrottler) UsageDeltas() []TenantUsageDelta {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var deltas []TenantUsageDelta
+	for tenant, bucket := range t.buckets {
+		delta := TenantUsageDelta{
+			Tenant:    tenant,
+			Allowed:   bucket.allowed - bucket.reportedAllowed,
+			Throttled: bucket.throttled - bucket.reportedThrottled,
+			Egress:    bucket.egress - bucket.reportedEgress,
+			Ingress:   bucket.ingress - bucket.reportedIngress,
+		}
+		if delta.Allowed == 0 && delta.Throttled == 0 && delta.Egress == 0 && delta.Ingress == 0 {
+			continue
+		}
+		bucket.reportedAllowed, bucket.reportedThrottled = bucket.allowed, bucket.throttled
+		bucket.reportedEgress, bucket.reportedIngress = bucket.egress, bucket.ingress
+		deltas = append(deltas, delta)
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Tenant < deltas[j].Tenant })
+	return deltas
+}
+
+// Prune - lastSeen이 cutoff 이전인 테넌트 사용량 기록 삭제 (보관 정책)
+func (t *TenantThrottler) Prune(cutoff time.Time) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	removed := 0
+	for tenant, bucket := range t.buckets {
+		if bucket.lastSeen.Before(cutoff) {
+			delete(t.buckets, tenant)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Forget - 테넌트 사용량 기록 삭제 (다음 요청부터 새 버킷)
+func (t *TenantThrottler) Forget(tenant string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	_, ok := t.buckets[tenant]
+	delete(t.buckets, tenant)
+	return ok
+}
+
+// handleUsage - 테넌트 사용량 API (테넌트 인증은 APIServer.tenantAuth)
+func (t *TenantThrottler) handleUsage(w http.ResponseWriter, r *http.Request, tenant string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   t.Usage(tenant),
+	})
+}
+
+// writeMetrics - 테넌트별 허용/거부 요청 수와 송수신 바이트
+func (t *TenantThrottler) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	tenants := make([]string, 0, len(t.buckets))
+	for tenant := range t.buckets {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	allowed := make([]uint64, len(tenants))
+	throttled := make([]uint64, len(tenants))
+	egress := make([]uint64, len(tenants))
+	ingress := make([]uint64, len(tenants))
+	for i, tenant := range tenants {
+		allowed[i] = t.buckets[tenant].allowed
+		throttled[i] = t.buckets[tenant].throttled
+		egress[i] = t.buckets[tenant].egress
+		ingress[i] = t.buckets[tenant].ingress
+	}
+	t.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_tenant_requests_total", "counter", "API requests per tenant by outcome")
+	for i, tenant := range tenants {
+		writeMetric(w, "nautilus_tenant_requests_total", map[string]string{"tenant": tenant, "outcome": "allowed"}, float64(allowed[i]))
+		writeMetric(w, "nautilus_tenant_requests_total", map[string]string{"tenant": tenant, "outcome": "throttled"}, float64(throttled[i]))
+	}
+
+	writeMetricHeader(w, "nautilus_tenant_network_bytes_total", "counter", "Pod network bytes per tenant by direction")
+	for i, tenant := range tenants {
+		writeMetric(w, "nautilus_tenant_network_bytes_total", map[string]string{"tenant": tenant, "direction": "egress"}, float64(egress[i]))
+		writeMetric(w, "nautilus_tenant_network_bytes_total", map[string]string{"tenant": tenant, "direction": "ingress"}, float64(ingress[i]))
+	}
+}
@@ -0,0 +1,453 @@
+// Tenant Usage - 테넌트 네임스페이스의 ResourceQuota 사용량, 7일 추이, 소진 시점 예측
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tenantUsageRetention = 7 * 24 * time.Hour // 추이와 예측에 쓰는 기간
+	tenantUsageFresh     = time.Minute        // 조회 시 이보다 오래된 현재값은 다시 읽음
+	tenantUsageMinSpan   = 6 * time.Hour      // 예측에 필요한 최소 관측 기간
+)
+
+/*
+TenantUsageForecaster - 배포가 quota에 막히기 전에 테넌트가 남은 여유를 볼 수 있도록
+k3s-daas.io/tenant 라벨이 붙은 네임스페이스의 ResourceQuota(status.used / status.hard)를
+TENANT_USAGE_SAMPLE_SECONDS(기본 3600초)마다 기록하고 7일간 보관합니다 (재시작 후에도 유지).
+
+  - 현재값: 네임스페이스/리소스별로 한도 대비 사용률이 가장 높은 ResourceQuota 기준
+  - 추이: 하루 단위 마지막 사용량 (최대 7일)
+  - 예측: 보관 기간의 사용량에 최소제곱 직선을 맞춰 하루 증가량을 구하고, 증가 중이면 한도에 닿는 시각
+    (관측 기간이 6시간 미만이면 예측하지 않음)
+
+수치는 cpu 계열은 millicore, 나머지는 K8s 수량을 정수로 푼 값(메모리/스토리지는 바이트, 개수는 개수)입니다.
+테넌트는 /api/v1/tenant/usage로 자기 네임스페이스만, 관리자는 /api/v1/admin/tenant-usage로 전체를 조회합니다.
+*/
+type TenantUsageForecaster struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	quota      *TenantThrottler
+	interval   time.Duration
+	adminToken string
+	stateFile  string
+
+	mutex     sync.Mutex
+	series    map[string][]QuotaUsagePoint // namespace/resource → 기록
+	current   map[string]quotaObservation  // namespace/resource → 최신 값
+	tenants   map[string]string            // 네임스페이스 → 테넌트 지갑
+	sampledAt time.Time
+	failures  int
+}
+
+// QuotaUsagePoint - 사용량 기록 한 점
+type QuotaUsagePoint struct {
+	Time time.Time `json:"time"`
+	Used float64   `json:"used"`
+}
+
+// quotaObservation - ResourceQuota의 리소스 하나
+type quotaObservation struct {
+	namespace string
+	resource  string
+	quota     string
+	used      string
+	hard      string
+	usedValue float64
+	hardValue float64
+}
+
+// QuotaResourceUsage - 네임스페이스의 리소스별 사용량과 예측
+type QuotaResourceUsage struct {
+	Resource     string            `json:"resource"`
+	Quota        string            `json:"quota"` // 가장 빠듯한 ResourceQuota 이름
+	Used         string            `json:"used"`
+	Hard         string            `json:"hard"`
+	UsedValue    float64           `json:"used_value"`
+	HardValue    float64           `json:"hard_value"`
+	Ratio        float64           `json:"ratio"`
+	Trend        []QuotaUsagePoint `json:"trend"`
+	GrowthPerDay float64           `json:"growth_per_day"`
+	ExhaustsAt   *time.Time        `json:"exhausts_at,omitempty"` // 현재 증가 추세로 한도에 닿는 시각 (이미 닿았으면 지금)
+}
+
+// NamespaceQuotaUsage - 테넌트 네임스페이스 하나
+type NamespaceQuotaUsage struct {
+	Namespace  string               `json:"namespace"`
+	Resources  []QuotaResourceUsage `json:"resources"`
+	ExhaustsAt *time.Time           `json:"exhausts_at,omitempty"` // 리소스 중 가장 빠른 소진 예측
+}
+
+// TenantQuotaReport - /api/v1/tenant/usage 응답
+type TenantQuotaReport struct {
+	Tenant     string                `json:"tenant"`
+	Requests   TenantUsage           `json:"requests"` // API 요청 QPS 한도와 사용량
+	Namespaces []NamespaceQuotaUsage `json:"namespaces"`
+	ExhaustsAt *time.Time            `json:"exhausts_at,omitempty"`
+	SampledAt  time.Time             `json:"sampled_at"`
+}
+
+// NewTenantUsageForecaster - 새 Tenant Usage Forecaster 생성 (TENANT_USAGE_SAMPLE_SECONDS)
+func NewTenantUsageForecaster(logger *logrus.Logger, k3sMgr *K3sManager, quota *TenantThrottler) *TenantUsageForecaster {
+	f := &TenantUsageForecaster{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		quota:      quota,
+		interval:   envSeconds("TENANT_USAGE_SAMPLE_SECONDS", 3600),
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		stateFile:  statePath("tenant-usage.json"),
+		series:     make(map[string][]QuotaUsagePoint),
+		current:    make(map[string]quotaObservation),
+		tenants:    make(map[string]string),
+	}
+	if _, err := loadJSONState(f.stateFile, &f.series); err != nil {
+		logger.Warnf("⚠️ Failed to load tenant usage history: %v", err)
+	}
+	return f
+}
+
+// Start - 주기적 사용량 기록
+func (f *TenantUsageForecaster) Start(ctx context.Context) {
+	f.logger.Infof("📈 Starting Tenant Usage Forecaster (every %s)...", f.interval)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !f.k3sMgr.IsRunning() {
+				continue
+			}
+			if err := f.sample(true); err != nil {
+				f.logger.Warnf("⚠️ Failed to sample tenant quota usage: %v", err)
+			}
+		}
+	}
+}
+
+// sample - 테넌트 네임스페이스의 ResourceQuota를 읽어 현재값 갱신 (record면 기록에도 추가)
+func (f *TenantUsageForecaster) sample(record bool) error {
+	tenants, err := f.namespaceTenants()
+	if err != nil {
+		f.mutex.Lock()
+		f.failures++
+		f.mutex.Unlock()
+		return err
+	}
+	output, err := f.k3sMgr.RunKubectl(nil, "get", "resourcequotas", "--all-namespaces", "-o", "json")
+	if err != nil {
+		f.mutex.Lock()
+		f.failures++
+		f.mutex.Unlock()
+		return err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Status struct {
+				Hard map[string]string `json:"hard"`
+				Used map[string]string `json:"used"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("failed to parse resource quota list: %v", err)
+	}
+
+	current := make(map[string]quotaObservation)
+	for _, item := range list.Items {
+		if tenants[item.Metadata.Namespace] == "" {
+			continue
+		}
+		for resource, hard := range item.Status.Hard {
+			observation := quotaObservation{
+				namespace: item.Metadata.Namespace, resource: resource, quota: item.Metadata.Name,
+				used: item.Status.Used[resource], hard: hard,
+			}
+			if observation.used == "" {
+				observation.used = "0"
+			}
+			if observation.usedValue, err = quotaValue(resource, observation.used); err != nil {
+				continue
+			}
+			if observation.hardValue, err = quotaValue(resource, hard); err != nil {
+				continue
+			}
+			// 같은 리소스를 제한하는 ResourceQuota가 여럿이면 가장 빠듯한 것만 (모두 강제되므로)
+			key := observation.namespace + "/" + resource
+			if previous, exists := current[key]; exists && quotaRatio(previous) >= quotaRatio(observation) {
+				continue
+			}
+			current[key] = observation
+		}
+	}
+
+	now := time.Now()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.tenants = tenants
+	f.current = current
+	f.sampledAt = now
+	if !record {
+		return nil
+	}
+
+	cutoff := now.Add(-tenantUsageRetention)
+	for key, points := range f.series {
+		if _, exists := current[key]; !exists && (len(points) == 0 || points[len(points)-1].Time.Before(cutoff)) {
+			delete(f.series, key)
+		}
+	}
+	for key, observation := range current {
+		points := append(f.series[key], QuotaUsagePoint{Time: now, Used: observation.usedValue})
+		start := 0
+		for start < len(points) && points[start].Time.Before(cutoff) {
+			start++
+		}
+		f.series[key] = points[start:]
+	}
+	if err := saveJSONState(f.stateFile, f.series); err != nil {
+		f.logger.Warnf("⚠️ Failed to save tenant usage history: %v", err)
+	}
+	return nil
+}
+
+// namespaceTenants - k3s-daas.io/tenant 라벨이 붙은 네임스페이스
+func (f *TenantUsageForecaster) namespaceTenants() (map[string]string, error) {
+	output, err := f.k3sMgr.RunKubectl(nil, "get", "namespaces", "-l", tenantLabel,
+		"-o", `jsonpath={range .items[*]}{.metadata.name}{"="}{.metadata.labels.k3s-daas\.io/tenant}{"\n"}{end}`)
+	if err != nil {
+		return nil, err
+	}
+	tenants := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if namespace, tenant, ok := strings.Cut(line, "="); ok && tenant != "" {
+			tenants[namespace] = tenant
+		}
+	}
+	return tenants, nil
+}
+
+// quotaValue - ResourceQuota 수량을 수치로 (cpu 계열은 millicore)
+func quotaValue(resource, value string) (float64, error) {
+	if resource == "cpu" || strings.HasSuffix(resource, ".cpu") {
+		millis, err := parseCPUMillis(value)
+		return float64(millis), err
+	}
+	number, err := parseMemoryBytes(value)
+	return float64(number), err
+}
+
+func quotaRatio(observation quotaObservation) float64 {
+	if observation.hardValue <= 0 {
+		return math.Inf(1)
+	}
+	return observation.usedValue / observation.hardValue
+}
+
+// forecast - 기록에 맞춘 직선의 하루 증가량과 한도 도달 시각
+func forecast(points []QuotaUsagePoint, observation quotaObservation, now time.Time) (float64, *time.Time) {
+	if observation.usedValue >= observation.hardValue {
+		return 0, &now
+	}
+	if len(points) < 2 || points[len(points)-1].Time.Sub(points[0].Time) < tenantUsageMinSpan {
+		return 0, nil
+	}
+
+	// 시간 축은 일 단위 (첫 기록 기준)
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range points {
+		x := point.Time.Sub(points[0].Time).Hours() / 24
+		sumX += x
+		sumY += point.Used
+		sumXY += x * point.Used
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, nil
+	}
+	growth := (n*sumXY - sumX*sumY) / denominator
+	if growth <= 0 {
+		return growth, nil
+	}
+	days := (observation.hardValue - observation.usedValue) / growth
+	exhaustsAt := now.Add(time.Duration(days * 24 * float64(time.Hour)))
+	return growth, &exhaustsAt
+}
+
+// dailyTrend - 하루 단위 마지막 사용량
+func dailyTrend(points []QuotaUsagePoint) []QuotaUsagePoint {
+	trend := []QuotaUsagePoint{}
+	for _, point := range points {
+		if n := len(trend); n > 0 && trend[n-1].Time.Truncate(24*time.Hour).Equal(point.Time.Truncate(24*time.Hour)) {
+			trend[n-1] = point
+			continue
+		}
+		trend = append(trend, point)
+	}
+	return trend
+}
+
+// earliest - 둘 중 빠른 소진 예측
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+// Reports - 테넌트별 보고서 (tenant가 비어 있으면 quota가 있는 모든 테넌트, 빠른 소진 예측 순)
+func (f *TenantUsageForecaster) Reports(tenant string) []TenantQuotaReport {
+	f.mutex.Lock()
+	stale := time.Since(f.sampledAt) > tenantUsageFresh
+	f.mutex.Unlock()
+	if stale && f.k3sMgr.IsRunning() {
+		if err := f.sample(false); err != nil {
+			f.logger.Warnf("⚠️ Failed to read tenant quota usage: %v", err)
+		}
+	}
+
+	now := time.Now()
+	f.mutex.Lock()
+	byTenant := make(map[string]map[string]*NamespaceQuotaUsage)
+	if tenant != "" {
+		byTenant[tenant] = make(map[string]*NamespaceQuotaUsage)
+	}
+	for namespace, owner := range f.tenants {
+		if tenant == "" || owner == tenant {
+			if byTenant[owner] == nil {
+				byTenant[owner] = make(map[string]*NamespaceQuotaUsage)
+			}
+			byTenant[owner][namespace] = &NamespaceQuotaUsage{Namespace: namespace, Resources: []QuotaResourceUsage{}}
+		}
+	}
+	for key, observation := range f.current {
+		namespaces := byTenant[f.tenants[observation.namespace]]
+		if namespaces == nil || namespaces[observation.namespace] == nil {
+			continue
+		}
+		usage := QuotaResourceUsage{
+			Resource: observation.resource, Quota: observation.quota, Used: observation.used, Hard: observation.hard,
+			UsedValue: observation.usedValue, HardValue: observation.hardValue, Ratio: quotaRatio(observation),
+			Trend: dailyTrend(f.series[key]),
+		}
+		if math.IsInf(usage.Ratio, 1) {
+			usage.Ratio = 1
+		}
+		usage.GrowthPerDay, usage.ExhaustsAt = forecast(f.series[key], observation, now)
+		namespace := namespaces[observation.namespace]
+		namespace.Resources = append(namespace.Resources, usage)
+		namespace.ExhaustsAt = earliest(namespace.ExhaustsAt, usage.ExhaustsAt)
+	}
+	sampledAt := f.sampledAt
+	f.mutex.Unlock()
+
+	reports := make([]TenantQuotaReport, 0, len(byTenant))
+	for owner, namespaces := range byTenant {
+		report := TenantQuotaReport{Tenant: owner, Namespaces: []NamespaceQuotaUsage{}, SampledAt: sampledAt}
+		if f.quota != nil {
+			report.Requests = f.quota.Usage(owner)
+		}
+		for _, namespace := range namespaces {
+			sort.Slice(namespace.Resources, func(i, j int) bool { return namespace.Resources[i].Resource < namespace.Resources[j].Resource })
+			report.Namespaces = append(report.Namespaces, *namespace)
+			report.ExhaustsAt = earliest(report.ExhaustsAt, namespace.ExhaustsAt)
+		}
+		sort.Slice(report.Namespaces, func(i, j int) bool { return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace })
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		a, b := reports[i].ExhaustsAt, reports[j].ExhaustsAt
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		if a != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return reports[i].Tenant < reports[j].Tenant
+	})
+	return reports
+}
+
+// handleTenantUsage - 테넌트 본인 사용량 (tenantAuth가 확인한 지갑)
+func (f *TenantUsageForecaster) handleTenantUsage(w http.ResponseWriter, r *http.Request, tenant string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeClientJSON(w, f.Reports(tenant)[0])
+}
+
+// handleAdminUsage - 전체 테넌트 사용량 (관리자 토큰, 대시보드용)
+func (f *TenantUsageForecaster) handleAdminUsage(w http.ResponseWriter, r *http.Request) {
+	if f.adminToken == "" {
+		http.Error(w, "Tenant usage admin API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(f.adminToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeClientJSON(w, f.Reports(r.URL.Query().Get("tenant")))
+}
+
+// writeMetrics - 테넌트 네임스페이스 quota 사용률과 예상 소진까지 남은 시간
+func (f *TenantUsageForecaster) writeMetrics(w io.Writer) {
+	f.mutex.Lock()
+	failures := f.failures
+	f.mutex.Unlock()
+
+	reports := f.Reports("")
+	now := time.Now()
+	writeMetricHeader(w, "nautilus_tenant_quota_usage_ratio", "gauge", "Used share of the tightest ResourceQuota per tenant namespace and resource")
+	for _, report := range reports {
+		for _, namespace := range report.Namespaces {
+			for _, resource := range namespace.Resources {
+				writeMetric(w, "nautilus_tenant_quota_usage_ratio",
+					map[string]string{"tenant": report.Tenant, "namespace": namespace.Namespace, "resource": resource.Resource}, resource.Ratio)
+			}
+		}
+	}
+	writeMetricHeader(w, "nautilus_tenant_quota_exhaustion_seconds", "gauge", "Forecast seconds until the resource reaches its quota at the 7-day growth rate")
+	for _, report := range reports {
+		for _, namespace := range report.Namespaces {
+			for _, resource := range namespace.Resources {
+				if resource.ExhaustsAt != nil {
+					writeMetric(w, "nautilus_tenant_quota_exhaustion_seconds",
+						map[string]string{"tenant": report.Tenant, "namespace": namespace.Namespace, "resource": resource.Resource},
+						math.Max(0, resource.ExhaustsAt.Sub(now).Seconds()))
+				}
+			}
+		}
+	}
+	writeMetricHeader(w, "nautilus_tenant_quota_sample_failures_total", "counter", "Failed namespace or ResourceQuota reads")
+	writeMetric(w, "nautilus_tenant_quota_sample_failures_total", nil, float64(failures))
+}
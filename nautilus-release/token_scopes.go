@@ -0,0 +1,471 @@
+// Token Scopes - 네임스페이스/리소스/동사/만료로 제한된 Seal 토큰 (체인 발급 또는 세션 교환)
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	"github.com/sirupsen/logrus"
+)
+
+// scopeVerbs - 범위에 쓸 수 있는 동사 (K8s RBAC 동사와 같은 이름)
+var scopeVerbs = map[string]bool{
+	"get": true, "list": true, "watch": true,
+	"create": true, "update": true, "patch": true, "delete": true,
+	"*": true,
+}
+
+// scopedTokenPrefix - 세션 교환으로 발급한 토큰 접두사 (워커 Seal 토큰과 구분)
+const scopedTokenPrefix = "scp_"
+
+/*
+TokenScope - 토큰 하나의 범위
+
+비어 있는 목록은 제한 없음, "*"도 전체를 뜻합니다. 리소스는 K8s RBAC처럼 하위 리소스를
+"pods/log" 형태로 따로 지정해야 합니다 (pods만 허용하면 exec/log는 거부).
+네임스페이스가 지정된 토큰으로는 클러스터 범위 리소스(nodes, namespaces 등)에 접근할 수 없습니다.
+*/
+type TokenScope struct {
+	NodeID     string    `json:"node_id"`
+	Owner      string    `json:"owner"`
+	Namespaces []string  `json:"namespaces,omitempty"`
+	Resources  []string  `json:"resources,omitempty"`
+	Verbs      []string  `json:"verbs,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IssuedAt   time.Time `json:"issued_at"`
+	Source     string    `json:"source"` // chain 또는 exchange
+}
+
+// scopeAllows - 목록이 값을 허용하는지 (빈 목록과 "*"는 전체)
+func scopeAllows(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == "*" || item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows - 동사/리소스/네임스페이스가 범위 안인지 (만료는 별도 확인)
+func (s *TokenScope) Allows(verb, resource, namespace string) bool {
+	return scopeAllows(s.Verbs, verb) && scopeAllows(s.Resources, resource) && scopeAllows(s.Namespaces, namespace)
+}
+
+// validate - 알 수 없는 동사와 빈 항목 거부
+func (s *TokenScope) validate() error {
+	for _, verb := range s.Verbs {
+		if !scopeVerbs[verb] {
+			return fmt.Errorf("unknown verb %q (expected get, list, watch, create, update, patch, delete or *)", verb)
+		}
+	}
+	for _, list := range [][]string{s.Namespaces, s.Resources} {
+		for _, item := range list {
+			if strings.TrimSpace(item) == "" {
+				return fmt.Errorf("namespaces and resources must not contain empty entries")
+			}
+		}
+	}
+	return nil
+}
+
+// scopeVerb - HTTP 메서드를 K8s 동사로 (이름 없는 GET은 list, watch 요청은 watch)
+func scopeVerb(method, name string, watch bool) string {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case watch:
+			return "watch"
+		case name == "":
+			return "list"
+		}
+		return "get"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return strings.ToLower(method)
+}
+
+/*
+scopeTarget - kubectl 경로의 네임스페이스, 리소스(하위 리소스 포함), 이름, watch 여부
+
+	/api/v1/namespaces/default/pods/web/log     → default, pods/log, web
+	/apis/apps/v1/watch/namespaces/default/deployments → default, deployments, "", watch
+	/api/v1/nodes                                → "", nodes, ""
+	/api, /apis/apps/v1 (디스커버리)              → false
+*/
+func scopeTarget(r *http.Request) (namespace, resource, name string, watch, ok bool) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "", "", "", false, false
+	}
+	watch = r.URL.Query().Get("watch") == "true" || r.URL.Query().Get("watch") == "1"
+	if segments[0] == "watch" && len(segments) > 1 {
+		watch, segments = true, segments[1:]
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		namespace, segments = segments[1], segments[2:]
+	}
+	resource = segments[0]
+	if len(segments) >= 2 {
+		name = segments[1]
+	}
+	if len(segments) >= 3 {
+		resource += "/" + segments[2]
+	}
+	return namespace, resource, name, watch, resource != ""
+}
+
+/*
+TokenScopes - 범위 제한 토큰 저장소
+
+발급 경로는 두 가지입니다:
+  - 체인: worker_registry::issue_scoped_token이 토큰의 sha256 해시와 범위를 이벤트로 기록 (토큰은 소유자만 앎)
+  - 세션 교환: 워커 Seal 토큰으로 POST /api/v1/tokens/exchange를 호출하면 마스터가 새 토큰을 만들어 한 번만 반환
+
+어느 쪽이든 토큰 원문은 저장하지 않고 해시로만 찾습니다. 범위는 RBAC 위임 권한보다 먼저 확인하며,
+범위 제한 토큰이 아닌 워커 Seal 토큰은 지금처럼 제한 없이 동작합니다.
+*/
+type TokenScopes struct {
+	logger     *logrus.Logger
+	workerPool *WorkerPool
+	stateFile  string
+	maxTTL     time.Duration // 세션 교환 최대 유효 기간 (TOKEN_EXCHANGE_MAX_TTL_SECONDS)
+	defaultTTL time.Duration // ttl_seconds 생략 시 (TOKEN_EXCHANGE_DEFAULT_TTL_SECONDS)
+
+	mutex  sync.RWMutex
+	scopes map[string]*TokenScope // 토큰 sha256 hex → 범위
+	denied uint64
+}
+
+// NewTokenScopes - 새 Token Scopes 생성 (저장된 범위 로드, 만료된 항목 정리)
+func NewTokenScopes(logger *logrus.Logger, workerPool *WorkerPool) *TokenScopes {
+	t := &TokenScopes{
+		logger:     logger,
+		workerPool: workerPool,
+		stateFile:  statePath("token-scopes.json"),
+		maxTTL:     envSeconds("TOKEN_EXCHANGE_MAX_TTL_SECONDS", 86400),
+		defaultTTL: envSeconds("TOKEN_EXCHANGE_DEFAULT_TTL_SECONDS", 3600),
+		scopes:     make(map[string]*TokenScope),
+	}
+	if _, err := loadJSONState(t.stateFile, &t.scopes); err != nil {
+		logger.Warnf("⚠️ Failed to load token scopes: %v", err)
+	}
+	t.mutex.Lock()
+	t.pruneLocked(time.Now())
+	t.mutex.Unlock()
+	return t
+}
+
+// tokenHash - 토큰 조회 키 (체인 이벤트의 token_hash와 같은 형식)
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Lookup - 범위 제한 토큰이면 범위 반환 (만료된 것도 반환, 호출자가 확인)
+func (t *TokenScopes) Lookup(token string) (*TokenScope, bool) {
+	if t == nil || token == "" {
+		return nil, false
+	}
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	scope, ok := t.scopes[tokenHash(token)]
+	if !ok {
+		return nil, false
+	}
+	copied := *scope
+	return &copied, true
+}
+
+// Check - 범위 제한 토큰의 요청이 범위 밖이거나 만료됐으면 오류 (일반 토큰은 nil)
+func (t *TokenScopes) Check(token, verb, resource, namespace string) error {
+	scope, ok := t.Lookup(token)
+	if !ok {
+		// 정리된 교환 토큰이 범위 없는 토큰으로 통과하지 않도록 접두사로 구분
+		if strings.HasPrefix(token, scopedTokenPrefix) {
+			t.count()
+			return fmt.Errorf("Unauthorized: unknown or expired scoped token")
+		}
+		return nil
+	}
+	if time.Now().After(scope.ExpiresAt) {
+		t.count()
+		return fmt.Errorf("Unauthorized: scoped token expired at %s", scope.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if !scope.Allows(verb, resource, namespace) {
+		t.count()
+		where := "cluster scope"
+		if namespace != "" {
+			where = "namespace " + namespace
+		}
+		return fmt.Errorf("Forbidden: token scope does not allow %s %s in %s", verb, resource, where)
+	}
+	return nil
+}
+
+func (t *TokenScopes) count() {
+	t.mutex.Lock()
+	t.denied++
+	t.mutex.Unlock()
+}
+
+// CheckRequest - 컨트랙트 경로 요청의 토큰 범위 확인
+func (t *TokenScopes) CheckRequest(request *K8sAPIRequest) error {
+	if t == nil {
+		return nil
+	}
+	return t.Check(request.SealToken, scopeVerb(request.Method, request.Name, false), request.Resource, request.Namespace)
+}
+
+// Middleware - kubectl 경로에서 Gateway가 전달한 X-Seal-Token의 범위 확인 (디스커버리 경로는 통과)
+func (t *TokenScopes) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace, resource, name, watch, ok := scopeTarget(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		err := t.Check(r.Header.Get("X-Seal-Token"), scopeVerb(r.Method, name, watch), resource, namespace)
+		if err == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		t.logger.Infof("🔏 Rejected %s %s: %v", r.Method, r.URL.Path, err)
+		reason, message, _ := strings.Cut(err.Error(), ": ")
+		code := http.StatusForbidden
+		if reason == "Unauthorized" {
+			code = http.StatusUnauthorized
+		}
+		httpserver.WriteStatus(w, code, reason, message)
+	})
+}
+
+// Issue - 범위 등록 (token_hash 기준, 같은 해시는 덮어씀)
+func (t *TokenScopes) Issue(hash string, scope *TokenScope) error {
+	if err := scope.validate(); err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.pruneLocked(time.Now())
+	t.scopes[hash] = scope
+	if err := saveJSONState(t.stateFile, t.scopes); err != nil {
+		delete(t.scopes, hash)
+		return err
+	}
+	t.logger.Infof("🔏 Scoped token issued for %s via %s (verbs %v, resources %v, namespaces %v, until %s)",
+		scope.NodeID, scope.Source, scope.Verbs, scope.Resources, scope.Namespaces, scope.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// Revoke - 범위 제한 토큰 폐기 (이후 요청은 일반 토큰으로도 인정되지 않음)
+func (t *TokenScopes) Revoke(hash string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	scope, ok := t.scopes[hash]
+	if !ok {
+		return false
+	}
+	// 폐기 후에도 해시가 남아 있어야 범위 없는 토큰으로 통과하지 않음 (만료 시각을 과거로)
+	scope.ExpiresAt = time.Now()
+	if err := saveJSONState(t.stateFile, t.scopes); err != nil {
+		t.logger.Warnf("⚠️ Failed to save token scopes: %v", err)
+	}
+	t.logger.Infof("🔏 Scoped token for %s revoked", scope.NodeID)
+	return true
+}
+
+// pruneLocked - 만료 후 하루 지난 교환 토큰 삭제 (체인 발급 토큰은 형식을 알 수 없어 만료 상태로 유지)
+func (t *TokenScopes) pruneLocked(now time.Time) {
+	for hash, scope := range t.scopes {
+		if scope.Source == "exchange" && now.Sub(scope.ExpiresAt) > 24*time.Hour {
+			delete(t.scopes, hash)
+		}
+	}
+}
+
+// eventStrings - Move vector<String> 필드
+func eventStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// handleChainEvent - worker_registry 범위 토큰 발급/폐기 이벤트 반영
+func (t *TokenScopes) handleChainEvent(event *SuiContractEvent) {
+	if t == nil {
+		return
+	}
+	hash := strings.ToLower(event.EventData["token_hash"].(string))
+	nodeID := event.EventData["node_id"].(string)
+
+	if strings.Contains(event.Type, "ScopedTokenRevokedEvent") {
+		if !t.Revoke(hash) {
+			t.logger.Debugf("Revocation for unknown scoped token of %s ignored", nodeID)
+		}
+		return
+	}
+
+	expiresAt, err := eventUint(event.EventData["expires_at_ms"])
+	if err != nil {
+		t.logger.Warnf("⚠️ Scoped token event for %s without expires_at_ms", nodeID)
+		return
+	}
+	issuedAt := time.Now()
+	if ms, err := eventUint(event.EventData["timestamp"]); err == nil && ms > 0 {
+		issuedAt = time.UnixMilli(int64(ms))
+	}
+	scope := &TokenScope{
+		NodeID:     nodeID,
+		Owner:      event.EventData["owner"].(string),
+		Namespaces: eventStrings(event.EventData["namespaces"]),
+		Resources:  eventStrings(event.EventData["resources"]),
+		Verbs:      eventStrings(event.EventData["verbs"]),
+		ExpiresAt:  time.UnixMilli(int64(expiresAt)),
+		IssuedAt:   issuedAt,
+		Source:     "chain",
+	}
+	if err := t.Issue(hash, scope); err != nil {
+		t.logger.Warnf("⚠️ Invalid scoped token for %s: %v", nodeID, err)
+	}
+}
+
+// ScopedTokenGrant - 세션 교환 응답 (토큰은 이때 한 번만 반환)
+type ScopedTokenGrant struct {
+	Token string      `json:"token"`
+	Scope *TokenScope `json:"scope"`
+}
+
+/*
+handleExchange - 세션 교환 (/api/v1/tokens/exchange)
+
+	POST   {"namespaces": [], "resources": [], "verbs": [], "ttl_seconds": 0}   워커 Seal 토큰으로 범위 제한 토큰 발급
+	DELETE                                                                      제시한 범위 제한 토큰 폐기
+
+범위 제한 토큰으로 다시 교환할 수는 없습니다 (범위를 넓히는 우회 방지).
+*/
+func (t *TokenScopes) handleExchange(w http.ResponseWriter, r *http.Request) {
+	token := requestSealToken(r)
+	switch r.Method {
+	case http.MethodPost:
+		if _, scoped := t.Lookup(token); scoped {
+			http.Error(w, "Scoped tokens cannot be exchanged, use the worker seal token", http.StatusForbidden)
+			return
+		}
+		worker, ok := t.workerPool.WorkerBySealToken(token)
+		if !ok {
+			http.Error(w, "Invalid or missing seal token", http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Namespaces []string `json:"namespaces"`
+			Resources  []string `json:"resources"`
+			Verbs      []string `json:"verbs"`
+			TTLSeconds int      `json:"ttl_seconds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		ttl := t.defaultTTL
+		if body.TTLSeconds > 0 {
+			ttl = time.Duration(body.TTLSeconds) * time.Second
+		}
+		if ttl > t.maxTTL {
+			http.Error(w, fmt.Sprintf("ttl_seconds exceeds the maximum of %d", int(t.maxTTL.Seconds())), http.StatusBadRequest)
+			return
+		}
+
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+			return
+		}
+		now := time.Now()
+		grant := ScopedTokenGrant{
+			Token: scopedTokenPrefix + hex.EncodeToString(secret),
+			Scope: &TokenScope{
+				NodeID:     worker.NodeID,
+				Owner:      worker.WorkerAddress,
+				Namespaces: body.Namespaces,
+				Resources:  body.Resources,
+				Verbs:      body.Verbs,
+				ExpiresAt:  now.Add(ttl),
+				IssuedAt:   now,
+				Source:     "exchange",
+			},
+		}
+		if err := t.Issue(tokenHash(grant.Token), grant.Scope); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusCreated)
+		writeClientJSON(w, grant)
+
+	case http.MethodDelete:
+		if !t.Revoke(tokenHash(token)) {
+			http.Error(w, "Not a scoped token", http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, map[string]bool{"revoked": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeMetrics - 활성 범위 제한 토큰 수와 범위 밖으로 거부한 요청 수
+func (t *TokenScopes) writeMetrics(w io.Writer) {
+	t.mutex.RLock()
+	now := time.Now()
+	active := map[string]int{"chain": 0, "exchange": 0}
+	for _, scope := range t.scopes {
+		if now.Before(scope.ExpiresAt) {
+			active[scope.Source]++
+		}
+	}
+	denied := t.denied
+	t.mutex.RUnlock()
+
+	sources := make([]string, 0, len(active))
+	for source := range active {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	writeMetricHeader(w, "nautilus_scoped_tokens", "gauge", "Unexpired scoped seal tokens by how they were issued")
+	for _, source := range sources {
+		writeMetric(w, "nautilus_scoped_tokens", map[string]string{"source": source}, float64(active[source]))
+	}
+	writeMetricHeader(w, "nautilus_scoped_token_denials_total", "counter", "Requests rejected because they were outside the token scope or the token had expired")
+	writeMetric(w, "nautilus_scoped_token_denials_total", nil, float64(denied))
+}
@@ -0,0 +1,495 @@
+// Topology Routing - 서비스 트래픽을 같은 노드/존의 정상 엔드포인트로 우선 보내는 토폴로지 힌트 관리
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+kube-proxy는 EndpointSlice의 힌트가 자기 존(노드)을 가리키는 엔드포인트만 골라 쓰므로, 힌트를 직접 계산해 기록합니다.
+
+	Service  k3s-daas.io/topology-routing: "zone" | "node" | "node,zone"   선호 범위 (없거나 off면 K8s 기본 동작)
+	Service  k3s-daas.io/topology-min-endpoints: "2"                         존 안 정상 엔드포인트가 이보다 적으면 그 존은 전체 엔드포인트 사용 (기본 1)
+
+컨트롤러가 selector를 넘겨받아(k3s-daas.io/topology-selector에 보관) <service>-topology EndpointSlice를 관리합니다.
+  - zone: 엔드포인트마다 hints.forZones = [자기 존]과 service.kubernetes.io/topology-mode=Auto
+  - node: hints.forNodes = [자기 노드]와 spec.trafficDistribution=PreferSameNode (K8s 1.33+, 이전 버전은 존 힌트만 적용)
+정상 엔드포인트(Ready 노드, offline/slashed/cordon 아님)가 부족한 존과 노드는 모든 엔드포인트의 힌트에 추가해
+그 존/노드의 클라이언트가 전체 엔드포인트로 폴백하게 합니다. 엔드포인트가 아예 없는 존은 kube-proxy가 스스로 폴백합니다.
+canary가 붙은 Service는 canary 컨트롤러가 엔드포인트를 관리하므로 넘겨주고, 어노테이션을 지우면 selector를 되돌립니다.
+*/
+
+const (
+	topologyRoutingAnnot  = "k3s-daas.io/topology-routing"
+	topologyMinEndpoints  = "k3s-daas.io/topology-min-endpoints"
+	topologySelectorAnnot = "k3s-daas.io/topology-selector"
+	topologyManagedBy     = "nautilus.k3s-daas.io/topology"
+	topologyModeAnnot     = "service.kubernetes.io/topology-mode"
+	topologyHintsAnnot    = "service.kubernetes.io/topology-aware-hints" // K8s 1.26 (1.27부터 topology-mode)
+	preferSameNode        = "PreferSameNode"
+)
+
+// TopologyRouteState - Service별 토폴로지 라우팅 상태 (API 응답)
+type TopologyRouteState struct {
+	Namespace     string         `json:"namespace"`
+	Service       string         `json:"service"`
+	Scopes        []string       `json:"scopes"`
+	MinEndpoints  int            `json:"min_endpoints"`
+	Endpoints     int            `json:"endpoints"`
+	HealthyByZone map[string]int `json:"healthy_by_zone"`
+	FallbackZones []string       `json:"fallback_zones"` // 정상 엔드포인트가 부족해 전체 엔드포인트를 쓰는 존
+	FallbackNodes []string       `json:"fallback_nodes"` // 로컬 엔드포인트가 모두 비정상인 노드
+	NodeHints     bool           `json:"node_hints"`     // 노드 힌트가 실제로 기록되는지
+	Note          string         `json:"note,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	nodeUnsupported bool   // trafficDistribution 설정 실패 (K8s 1.33 미만)
+	applied         string // 마지막으로 적용한 EndpointSlice (변경 시에만 apply)
+}
+
+// routingNode - 힌트 계산에 쓰는 노드 정보
+type routingNode struct {
+	zone    string
+	healthy bool
+}
+
+// TopologyRouter - 토폴로지 힌트 EndpointSlice reconcile
+type TopologyRouter struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	canaries   *CanaryController
+	adminToken string
+
+	mutex    sync.Mutex
+	states   map[string]*TopologyRouteState // <namespace>/<service>
+	failures int
+}
+
+// NewTopologyRouter - 새 토폴로지 라우터 생성 (canary가 붙은 Service는 건너뜀)
+func NewTopologyRouter(logger *logrus.Logger, k3sMgr *K3sManager, canaries *CanaryController) *TopologyRouter {
+	return &TopologyRouter{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		canaries:   canaries,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		states:     make(map[string]*TopologyRouteState),
+	}
+}
+
+// ReconcileAll - 어노테이션이 붙은 Service의 힌트를 갱신하고, 어노테이션이 빠진 Service는 원래대로 되돌림
+func (t *TopologyRouter) ReconcileAll() {
+	output, err := t.k3sMgr.RunKubectl(nil, "get", "services", "--all-namespaces", "-o", "json")
+	if err != nil {
+		t.logger.Warnf("⚠️ Topology routing: failed to list services: %v", err)
+		return
+	}
+	var list struct {
+		Items []canaryObject `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		t.logger.Warnf("⚠️ Topology routing: failed to parse services: %v", err)
+		return
+	}
+
+	var nodes map[string]routingNode
+	seen := make(map[string]bool)
+	for i := range list.Items {
+		service := &list.Items[i]
+		namespace, name := service.Metadata.Namespace, service.Metadata.Name
+		annotations := service.Metadata.Annotations
+		owned := annotations[topologySelectorAnnot] != ""
+
+		scopes, scopeErr := parseTopologyScopes(annotations[topologyRoutingAnnot])
+		if (scopeErr == nil && len(scopes) == 0) || t.canaries.Manages(namespace, name) {
+			if owned {
+				t.release(service)
+			}
+			continue
+		}
+
+		if nodes == nil {
+			if nodes, err = t.listNodes(); err != nil {
+				t.logger.Warnf("⚠️ Topology routing: failed to list nodes: %v", err)
+				return
+			}
+		}
+		key := namespace + "/" + name
+		seen[key] = true
+		t.reconcile(service, scopes, scopeErr, nodes)
+	}
+
+	t.mutex.Lock()
+	for key := range t.states {
+		if !seen[key] {
+			delete(t.states, key)
+		}
+	}
+	t.mutex.Unlock()
+}
+
+// listNodes - 노드별 존 라벨과 정상 여부 (Ready, cordon 아님, 워커 풀에서 offline/slashed 아님)
+func (t *TopologyRouter) listNodes() (map[string]routingNode, error) {
+	output, err := t.k3sMgr.RunKubectl(nil, "get", "nodes", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Unschedulable bool `json:"unschedulable"`
+			} `json:"spec"`
+			Status struct {
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse nodes: %v", err)
+	}
+
+	nodes := make(map[string]routingNode, len(list.Items))
+	for _, item := range list.Items {
+		ready := false
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == "Ready" {
+				ready = condition.Status == "True"
+			}
+		}
+		healthy := ready && !item.Spec.Unschedulable
+		if worker, ok := t.k3sMgr.workerPool.GetWorker(item.Metadata.Name); ok && (worker.Status == "offline" || worker.Status == "slashed") {
+			healthy = false
+		}
+		nodes[item.Metadata.Name] = routingNode{zone: item.Metadata.Labels[topologyZoneLabel], healthy: healthy}
+	}
+	return nodes, nil
+}
+
+// parseTopologyScopes - "node", "zone" 목록 (빈 값과 off는 비활성)
+func parseTopologyScopes(value string) ([]string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "off" || value == "false" {
+		return nil, nil
+	}
+	var scopes []string
+	for _, part := range strings.Split(value, ",") {
+		scope := strings.TrimSpace(part)
+		if scope != "node" && scope != "zone" {
+			return nil, fmt.Errorf("invalid %s %q (expected node, zone or node,zone)", topologyRoutingAnnot, value)
+		}
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func (t *TopologyRouter) reconcile(service *canaryObject, scopes []string, scopeErr error, nodes map[string]routingNode) {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+
+	t.mutex.Lock()
+	key := namespace + "/" + name
+	state, ok := t.states[key]
+	if !ok {
+		state = &TopologyRouteState{Namespace: namespace, Service: name}
+		t.states[key] = state
+	}
+	t.mutex.Unlock()
+
+	err := scopeErr
+	if err == nil {
+		err = t.sync(state, service, scopes, nodes)
+	}
+
+	t.mutex.Lock()
+	state.UpdatedAt = time.Now()
+	state.Error = ""
+	if err != nil {
+		state.Error = err.Error()
+		t.failures++
+	}
+	t.mutex.Unlock()
+	if err != nil {
+		t.logger.Warnf("⚠️ Topology routing %s/%s: %v", namespace, name, err)
+	}
+}
+
+func (t *TopologyRouter) sync(state *TopologyRouteState, service *canaryObject, scopes []string, nodes map[string]routingNode) error {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+	wantZone, wantNode := containsString(scopes, "zone"), containsString(scopes, "node")
+
+	minEndpoints := 1
+	if value := strings.TrimSpace(service.Metadata.Annotations[topologyMinEndpoints]); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("invalid %s %q (expected a positive integer)", topologyMinEndpoints, value)
+		}
+		minEndpoints = parsed
+	}
+
+	// 1. Service selector 인수 (원래 selector는 어노테이션에 보관, kube-proxy가 힌트를 쓰도록 topology-mode 설정)
+	selector := map[string]string{}
+	if saved := service.Metadata.Annotations[topologySelectorAnnot]; saved != "" {
+		if err := json.Unmarshal([]byte(saved), &selector); err != nil {
+			return fmt.Errorf("invalid %s annotation: %v", topologySelectorAnnot, err)
+		}
+	} else {
+		if err := json.Unmarshal(service.Spec.Selector, &selector); err != nil || len(selector) == 0 {
+			return fmt.Errorf("service has no selector to route by topology")
+		}
+		saved, _ := json.Marshal(selector)
+		patch, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{
+				topologySelectorAnnot: string(saved),
+				topologyModeAnnot:     "Auto",
+				topologyHintsAnnot:    "auto",
+			}},
+			"spec": map[string]interface{}{"selector": nil},
+		})
+		if _, err := t.k3sMgr.RunKubectl(nil, "patch", "service", name, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+			return fmt.Errorf("failed to take over selector: %v", err)
+		}
+		t.logger.Infof("🗺️ Topology routing %s/%s: endpoints now managed (%s)", namespace, name, strings.Join(scopes, ","))
+	}
+
+	// 노드 힌트는 kube-proxy가 trafficDistribution=PreferSameNode일 때만 사용 (필드가 없는 K8s는 힌트 필드도 거부)
+	t.mutex.Lock()
+	nodeUnsupported := state.nodeUnsupported
+	t.mutex.Unlock()
+	nodeHints := wantNode && !nodeUnsupported
+	if nodeHints && service.Spec.TrafficDistribution != preferSameNode {
+		patch, _ := json.Marshal(map[string]interface{}{"spec": map[string]string{"trafficDistribution": preferSameNode}})
+		if _, err := t.k3sMgr.RunKubectl(nil, "patch", "service", name, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+			t.logger.Warnf("⚠️ Topology routing %s/%s: node preference unsupported by this cluster, using zone hints only: %v", namespace, name, err)
+			nodeHints, nodeUnsupported = false, true
+		}
+	}
+
+	// 2. 준비된 Pod
+	output, err := t.k3sMgr.RunKubectl(nil, "get", "pods", "-n", namespace, "-l", labelSelectorString(selector), "-o", "json")
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	var podList struct {
+		Items []canaryPod `json:"items"`
+	}
+	if err := json.Unmarshal(output, &podList); err != nil {
+		return fmt.Errorf("failed to parse pods: %v", err)
+	}
+	sort.Slice(podList.Items, func(i, j int) bool { return podList.Items[i].Metadata.Name < podList.Items[j].Metadata.Name })
+
+	var pods []*canaryPod
+	for i := range podList.Items {
+		if podList.Items[i].ready() {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+
+	// 3. 존/노드별 정상 엔드포인트 수와 폴백 대상
+	healthyByZone := make(map[string]int)
+	healthyByNode := make(map[string]int)
+	zoneless := false
+	for _, pod := range pods {
+		node := nodes[pod.Spec.NodeName]
+		if node.zone == "" {
+			zoneless = true
+		} else if _, ok := healthyByZone[node.zone]; !ok {
+			healthyByZone[node.zone] = 0
+		}
+		if _, ok := healthyByNode[pod.Spec.NodeName]; !ok {
+			healthyByNode[pod.Spec.NodeName] = 0
+		}
+		if node.healthy {
+			healthyByZone[node.zone]++
+			healthyByNode[pod.Spec.NodeName]++
+		}
+	}
+	delete(healthyByZone, "")
+
+	note := ""
+	zoneHints := wantZone || nodeHints // 노드 힌트가 없는 노드는 존 힌트로 폴백
+	if zoneHints && zoneless {
+		// 힌트가 빠진 엔드포인트가 하나라도 있으면 kube-proxy가 힌트를 모두 무시하므로 명시적으로 끔
+		zoneHints = false
+		note = "some endpoints run on nodes without a " + topologyZoneLabel + " label; zone preference disabled"
+	}
+	if nodeUnsupported && wantNode {
+		note = strings.TrimPrefix(note+"; node preference requires spec.trafficDistribution (Kubernetes 1.33+)", "; ")
+	}
+
+	fallbackZones := []string{}
+	for zone, healthy := range healthyByZone {
+		if healthy < minEndpoints {
+			fallbackZones = append(fallbackZones, zone)
+		}
+	}
+	sort.Strings(fallbackZones)
+	fallbackNodes := []string{}
+	for node, healthy := range healthyByNode {
+		if healthy == 0 && node != "" {
+			fallbackNodes = append(fallbackNodes, node)
+		}
+	}
+	sort.Strings(fallbackNodes)
+
+	slice, err := endpointSliceManifest(service, pods, name+"-topology", topologyManagedBy, func(pod *canaryPod, endpoint map[string]interface{}) {
+		node := nodes[pod.Spec.NodeName]
+		if node.zone != "" {
+			endpoint["zone"] = node.zone
+		}
+		hints := map[string]interface{}{}
+		if zoneHints {
+			hints["forZones"] = topologyHintList(node.zone, fallbackZones)
+		}
+		if nodeHints && pod.Spec.NodeName != "" {
+			hints["forNodes"] = topologyHintList(pod.Spec.NodeName, fallbackNodes)
+		}
+		if len(hints) > 0 {
+			endpoint["hints"] = hints
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	state.Scopes = scopes
+	state.MinEndpoints = minEndpoints
+	state.Endpoints = len(pods)
+	state.HealthyByZone = healthyByZone
+	state.FallbackZones = fallbackZones
+	state.FallbackNodes = fallbackNodes
+	state.NodeHints = nodeHints
+	state.nodeUnsupported = nodeUnsupported
+	state.Note = note
+	applied := state.applied
+	t.mutex.Unlock()
+
+	if slice == applied {
+		return nil
+	}
+	if _, err := t.k3sMgr.RunKubectl([]byte(slice), "apply", "-f", "-"); err != nil {
+		return fmt.Errorf("failed to apply endpoint slice: %v", err)
+	}
+	t.mutex.Lock()
+	state.applied = slice
+	t.mutex.Unlock()
+	t.logger.Infof("🗺️ Topology routing %s/%s: %d endpoints in %d zones, fallback zones %v, fallback nodes %v",
+		namespace, name, len(pods), len(healthyByZone), fallbackZones, fallbackNodes)
+	return nil
+}
+
+// topologyHintList - 자기 존/노드와 폴백 대상을 합친 힌트 목록 ([{"name": ...}])
+func topologyHintList(own string, fallback []string) []map[string]string {
+	names := append([]string{}, fallback...)
+	if own != "" && !containsString(names, own) {
+		names = append(names, own)
+	}
+	sort.Strings(names)
+	hints := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		hints = append(hints, map[string]string{"name": name})
+	}
+	return hints
+}
+
+// release - 토폴로지 라우팅을 끈 Service의 selector와 어노테이션 복원 및 관리 EndpointSlice 삭제
+func (t *TopologyRouter) release(service *canaryObject) {
+	namespace, name := service.Metadata.Namespace, service.Metadata.Name
+	selector := map[string]string{}
+	if err := json.Unmarshal([]byte(service.Metadata.Annotations[topologySelectorAnnot]), &selector); err != nil {
+		t.logger.Warnf("⚠️ Topology routing %s/%s: cannot restore selector: %v", namespace, name, err)
+		return
+	}
+	spec := map[string]interface{}{"selector": selector}
+	if service.Spec.TrafficDistribution == preferSameNode {
+		spec["trafficDistribution"] = nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+			topologySelectorAnnot: nil,
+			topologyModeAnnot:     nil,
+			topologyHintsAnnot:    nil,
+		}},
+		"spec": spec,
+	})
+	if _, err := t.k3sMgr.RunKubectl(nil, "patch", "service", name, "-n", namespace, "--type=merge", "-p", string(patch)); err != nil {
+		t.logger.Warnf("⚠️ Topology routing %s/%s: failed to restore selector: %v", namespace, name, err)
+		return
+	}
+	if _, err := t.k3sMgr.RunKubectl(nil, "delete", "endpointslice", name+"-topology", "-n", namespace, "--ignore-not-found"); err != nil {
+		t.logger.Warnf("⚠️ Topology routing %s/%s: failed to delete endpoint slice: %v", namespace, name, err)
+	}
+	t.logger.Infof("🗺️ Topology routing %s/%s disabled, selector restored", namespace, name)
+}
+
+// States - 토폴로지 라우팅 상태 목록
+func (t *TopologyRouter) States() []TopologyRouteState {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	states := make([]TopologyRouteState, 0, len(t.states))
+	for _, state := range t.states {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Namespace+"/"+states[i].Service < states[j].Namespace+"/"+states[j].Service
+	})
+	return states
+}
+
+// handleTopologyRoutes - Service별 토폴로지 라우팅 상태 조회 (/api/v1/topology-routes, 관리자 토큰 필요)
+func (t *TopologyRouter) handleTopologyRoutes(w http.ResponseWriter, r *http.Request) {
+	if t.adminToken == "" {
+		http.Error(w, "Topology routing API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(t.adminToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeClientJSON(w, t.States())
+}
+
+// writeMetrics - Service별 폴백 존 수와 reconcile 오류 수
+func (t *TopologyRouter) writeMetrics(w io.Writer) {
+	states := t.States()
+	writeMetricHeader(w, "nautilus_topology_routing_endpoints", "gauge", "Endpoints published with topology hints per service")
+	for _, state := range states {
+		writeMetric(w, "nautilus_topology_routing_endpoints", map[string]string{"namespace": state.Namespace, "service": state.Service}, float64(state.Endpoints))
+	}
+	writeMetricHeader(w, "nautilus_topology_routing_fallback_zones", "gauge", "Zones routed to all endpoints for lack of healthy local endpoints")
+	for _, state := range states {
+		writeMetric(w, "nautilus_topology_routing_fallback_zones", map[string]string{"namespace": state.Namespace, "service": state.Service}, float64(len(state.FallbackZones)))
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	writeMetricHeader(w, "nautilus_topology_routing_sync_errors_total", "counter", "Topology routing reconcile errors")
+	writeMetric(w, "nautilus_topology_routing_sync_errors_total", nil, float64(t.failures))
+}
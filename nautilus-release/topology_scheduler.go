@@ -0,0 +1,314 @@
+// Topology Scheduler - 워커 리전/존/역할을 노드 라벨로 관리하고 지역 분산 스케줄링 제약 적용
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	topologyRegionLabel   = "topology.kubernetes.io/region"
+	topologyZoneLabel     = "topology.kubernetes.io/zone"
+	masterLatencyLabel    = "k3s-daas.io/master-latency-ms"
+	zoneAffinityAnnot     = "k3s-daas.io/zone-affinity"
+	regionAffinityAnnot   = "k3s-daas.io/region-affinity"
+	spreadByAnnot         = "k3s-daas.io/spread-by"
+	spreadMaxSkewAnnot    = "k3s-daas.io/spread-max-skew"
+	maxMasterLatencyAnnot = "k3s-daas.io/max-master-latency-ms"
+)
+
+// topologyKeys - spread-by 어노테이션 값과 노드 라벨 매핑
+var topologyKeys = map[string]string{
+	"zone":   topologyZoneLabel,
+	"region": topologyRegionLabel,
+}
+
+// TopologyScheduler - 지역 기반 스케줄링 지원
+type TopologyScheduler struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	workerPool *WorkerPool
+}
+
+// NewTopologyScheduler - 새 Topology Scheduler 생성
+func NewTopologyScheduler(logger *logrus.Logger, k3sMgr *K3sManager) *TopologyScheduler {
+	return &TopologyScheduler{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		workerPool: k3sMgr.workerPool,
+	}
+}
+
+// SyncNodeLabels - 워커가 보고한 위치 정보를 K8s 노드 라벨로 반영
+func (t *TopologyScheduler) SyncNodeLabels(worker *WorkerNode) error {
+	args := []string{"label", "node", worker.NodeID, "--overwrite",
+		fmt.Sprintf("%s=%d", masterLatencyLabel, worker.LatencyMs/10*10),
+	}
+	if worker.Role != "" {
+		args = append(args, fmt.Sprintf("%s=%s", nodeRoleLabel, worker.Role))
+	}
+	if tier := stakeTierFor(worker.StakeAmount); tier != "" {
+		args = append(args, fmt.Sprintf("%s=%s", stakeTierLabel, tier))
+	} else {
+		args = append(args, stakeTierLabel+"-")
+	}
+	if worker.Region != "" {
+		args = append(args, fmt.Sprintf("%s=%s", topologyRegionLabel, worker.Region))
+	}
+	if worker.Zone != "" {
+		args = append(args, fmt.Sprintf("%s=%s", topologyZoneLabel, worker.Zone))
+	}
+	if worker.Arch != "" {
+		args = append(args,
+			fmt.Sprintf("%s=%s", nodeOSLabel, worker.OS),
+			fmt.Sprintf("%s=%s", nodeArchLabel, worker.Arch),
+		)
+	}
+	network := worker.Network
+	if network != nil {
+		args = append(args,
+			fmt.Sprintf("%s=%d", bandwidthLabel, bandwidthBucket(network.DownloadMbps)),
+			fmt.Sprintf("%s=%d", uploadBandwidthLabel, bandwidthBucket(network.UploadMbps)),
+		)
+	}
+
+	if _, err := t.k3sMgr.RunKubectl(nil, args...); err != nil {
+		return fmt.Errorf("failed to label node %s: %v", worker.NodeID, err)
+	}
+
+	// 피어별 지연/대역폭은 라벨 값으로 표현할 수 없으므로 어노테이션에 JSON으로 기록
+	if network != nil {
+		annotation, err := networkAnnotation(network)
+		if err != nil {
+			return err
+		}
+		if _, err := t.k3sMgr.RunKubectl(nil, "annotate", "node", worker.NodeID, "--overwrite",
+			networkProbeAnnot+"="+annotation); err != nil {
+			return fmt.Errorf("failed to annotate node %s: %v", worker.NodeID, err)
+		}
+	}
+
+	worker.LabelsSynced = true
+	t.logger.Infof("🏷️ Node %s labeled with region=%s zone=%s role=%s", worker.NodeID, worker.Region, worker.Zone, worker.Role)
+	return nil
+}
+
+// Admit - 워크로드 생성 요청에 위치 제약 어노테이션을 스케줄링 규칙으로 변환
+func (t *TopologyScheduler) Admit(request *K8sAPIRequest, payload *admissionPayload) error {
+	method := strings.ToUpper(request.Method)
+	if (method != "POST" && method != "PUT") || request.Payload == "" {
+		return nil
+	}
+
+	// YAML 등 JSON이 아닌 payload는 그대로 전달
+	obj := payload.Object()
+	podSpec, podMeta := podTemplateOf(obj)
+	if podSpec == nil {
+		return nil
+	}
+
+	annotations := mergedAnnotations(obj, podMeta)
+	changed, err := applyTopologyConstraints(podSpec, podMeta, annotations)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	payload.MarkChanged()
+
+	t.logger.Infof("🌍 Topology constraints applied to %s %s/%s", request.Resource, request.Namespace, request.Name)
+	return nil
+}
+
+// podTemplateOf - 리소스 종류에 관계없이 Pod spec과 metadata 추출
+func podTemplateOf(obj map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	kind, _ := obj["kind"].(string)
+	spec, _ := obj["spec"].(map[string]interface{})
+	if spec == nil {
+		return nil, nil
+	}
+
+	switch kind {
+	case "Pod":
+		meta, _ := obj["metadata"].(map[string]interface{})
+		return spec, meta
+	case "Deployment", "StatefulSet", "ReplicaSet", "DaemonSet", "Job":
+		template, _ := spec["template"].(map[string]interface{})
+		if template == nil {
+			return nil, nil
+		}
+		podSpec, _ := template["spec"].(map[string]interface{})
+		meta, _ := template["metadata"].(map[string]interface{})
+		return podSpec, meta
+	default:
+		return nil, nil
+	}
+}
+
+// mergedAnnotations - 워크로드와 Pod 템플릿 어노테이션 병합 (템플릿 우선)
+func mergedAnnotations(obj, podMeta map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	for _, meta := range []map[string]interface{}{objectMeta(obj), podMeta} {
+		annotations, _ := meta["annotations"].(map[string]interface{})
+		for key, value := range annotations {
+			if str, ok := value.(string); ok {
+				result[key] = str
+			}
+		}
+	}
+	return result
+}
+
+// objectMeta - 오브젝트 metadata 반환
+func objectMeta(obj map[string]interface{}) map[string]interface{} {
+	meta, _ := obj["metadata"].(map[string]interface{})
+	return meta
+}
+
+// applyTopologyConstraints - 어노테이션을 nodeAffinity / topologySpreadConstraints로 변환
+func applyTopologyConstraints(podSpec, podMeta map[string]interface{}, annotations map[string]string) (bool, error) {
+	changed := false
+
+	for _, affinity := range []struct{ annotation, label string }{
+		{regionAffinityAnnot, topologyRegionLabel},
+		{zoneAffinityAnnot, topologyZoneLabel},
+		{nodeRoleAnnot, nodeRoleLabel},
+	} {
+		values := splitList(annotations[affinity.annotation])
+		if len(values) == 0 {
+			continue
+		}
+		addRequiredNodeExpression(podSpec, map[string]interface{}{
+			"key":      affinity.label,
+			"operator": "In",
+			"values":   toInterfaceSlice(values),
+		})
+		changed = true
+	}
+
+	if value := annotations[maxMasterLatencyAnnot]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return false, fmt.Errorf("invalid %s: %q", maxMasterLatencyAnnot, value)
+		}
+		// 라벨은 10ms 단위로 내림되어 있으므로 limit 이하 버킷만 허용
+		addRequiredNodeExpression(podSpec, map[string]interface{}{
+			"key":      masterLatencyLabel,
+			"operator": "Lt",
+			"values":   []interface{}{strconv.Itoa(limit + 1)},
+		})
+		changed = true
+	}
+
+	for _, bandwidth := range []struct{ annotation, label string }{
+		{minBandwidthAnnot, bandwidthLabel},
+		{minUploadAnnot, uploadBandwidthLabel},
+	} {
+		value := annotations[bandwidth.annotation]
+		if value == "" {
+			continue
+		}
+		minimum, err := strconv.Atoi(value)
+		if err != nil || minimum <= 0 {
+			return false, fmt.Errorf("invalid %s: %q", bandwidth.annotation, value)
+		}
+		// 라벨이 10Mbps 단위로 내림되어 있으므로 minimum 이상인 버킷만 허용 (측정 전 노드는 제외)
+		addRequiredNodeExpression(podSpec, map[string]interface{}{
+			"key":      bandwidth.label,
+			"operator": "Gt",
+			"values":   []interface{}{strconv.Itoa(minimum - 1)},
+		})
+		changed = true
+	}
+
+	if spreadBy := annotations[spreadByAnnot]; spreadBy != "" {
+		topologyKey, ok := topologyKeys[spreadBy]
+		if !ok {
+			return false, fmt.Errorf("invalid %s: %q (expected zone or region)", spreadByAnnot, spreadBy)
+		}
+
+		maxSkew := 1
+		if value := annotations[spreadMaxSkewAnnot]; value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				return false, fmt.Errorf("invalid %s: %q", spreadMaxSkewAnnot, value)
+			}
+			maxSkew = parsed
+		}
+
+		labels, _ := podMeta["labels"].(map[string]interface{})
+		if len(labels) == 0 {
+			return false, fmt.Errorf("%s requires pod labels to select peers", spreadByAnnot)
+		}
+
+		constraints, _ := podSpec["topologySpreadConstraints"].([]interface{})
+		podSpec["topologySpreadConstraints"] = append(constraints, map[string]interface{}{
+			"maxSkew":           maxSkew,
+			"topologyKey":       topologyKey,
+			"whenUnsatisfiable": "DoNotSchedule",
+			"labelSelector": map[string]interface{}{
+				"matchLabels": labels,
+			},
+		})
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// addRequiredNodeExpression - requiredDuringScheduling nodeAffinity의 모든 term에 조건 추가
+func addRequiredNodeExpression(podSpec map[string]interface{}, expression map[string]interface{}) {
+	affinity := childMap(podSpec, "affinity")
+	nodeAffinity := childMap(affinity, "nodeAffinity")
+	required := childMap(nodeAffinity, "requiredDuringSchedulingIgnoredDuringExecution")
+
+	terms, _ := required["nodeSelectorTerms"].([]interface{})
+	if len(terms) == 0 {
+		terms = []interface{}{map[string]interface{}{}}
+	}
+
+	// nodeSelectorTerms는 OR 관계이므로 각 term에 AND 조건으로 추가
+	for _, term := range terms {
+		termMap, ok := term.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		expressions, _ := termMap["matchExpressions"].([]interface{})
+		termMap["matchExpressions"] = append(expressions, expression)
+	}
+	required["nodeSelectorTerms"] = terms
+}
+
+// childMap - 하위 map을 가져오거나 생성
+func childMap(parent map[string]interface{}, key string) map[string]interface{} {
+	child, ok := parent[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		parent[key] = child
+	}
+	return child
+}
+
+// splitList - 쉼표 구분 문자열을 공백 제거 후 분리
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// toInterfaceSlice - JSON 인코딩용 변환
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,510 @@
+// Trash - 소프트 삭제: 삭제된 K8s 객체를 보관 기간 동안 휴지통에 두고 복원/만료 정리 (SoftDelete 기능 게이트)
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	trashGCInterval        = 10 * time.Minute
+	defaultTrashResources  = "deployments,statefulsets,daemonsets,cronjobs,jobs,services,configmaps,secrets,persistentvolumeclaims,ingresses"
+	defaultTrashMaxEntries = 1000
+)
+
+var errTrashNotFound = errors.New("trash entry not found")
+
+/*
+TrashBin - 실수로 지운 객체를 되살리기 위한 휴지통
+
+이름을 지정한 DELETE(kubectl 프록시 경로와 온체인 K8sAPIRequest 모두)는 삭제 직전 객체를 조회해 두고,
+삭제가 성공하면 상태 디렉토리의 trash/<id>.json에 보관합니다. 복원은 uid/resourceVersion/status 같은
+서버 필드를 지운 객체를 kubectl create로 다시 만들므로 같은 이름의 객체가 이미 있으면 409로 실패합니다.
+
+	NAUTILUS_TRASH_RESOURCES        보관할 리소스 (기본 deployments,statefulsets,daemonsets,cronjobs,jobs,services,
+	                                configmaps,secrets,persistentvolumeclaims,ingresses)
+	NAUTILUS_TRASH_RETENTION_HOURS  보관 기간 (기본 72), 지나면 10분 주기 정리에서 삭제
+	NAUTILUS_TRASH_MAX_ENTRIES      최대 보관 수 (기본 1000, 넘으면 오래된 것부터 삭제)
+
+ownerReferences가 있는 객체(ReplicaSet, Job이 만든 Pod 등)는 소유자가 다시 만들므로 보관하지 않습니다.
+네임스페이스는 안의 객체가 함께 지워지므로 기본 목록에 없습니다. PVC는 객체만 복원되며, 회수 정책이 Delete인
+볼륨의 데이터는 되살릴 수 없습니다. Secret도 평문 JSON으로 보관되므로 상태 디렉토리 권한(0700)에 의존합니다.
+*/
+type TrashBin struct {
+	logger     *logrus.Logger
+	k3sMgr     *K3sManager
+	adminToken string
+	dir        string
+	resources  map[string]bool
+	retention  time.Duration
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*TrashEntry
+	counts  map[string]int // trashed, restored, purged, expired, failed
+}
+
+// TrashEntry - 휴지통에 보관한 객체 하나 (목록 조회에서는 object 생략)
+type TrashEntry struct {
+	ID         string          `json:"id"`
+	Resource   string          `json:"resource"`
+	Kind       string          `json:"kind"`
+	APIVersion string          `json:"api_version"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Name       string          `json:"name"`
+	DeletedBy  string          `json:"deleted_by,omitempty"`
+	Source     string          `json:"source"` // kubectl, chain
+	DeletedAt  time.Time       `json:"deleted_at"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	Object     json.RawMessage `json:"object,omitempty"`
+}
+
+// NewTrashBin - 휴지통 생성 (기존 보관 항목 로드)
+func NewTrashBin(logger *logrus.Logger, k3sMgr *K3sManager) *TrashBin {
+	t := &TrashBin{
+		logger:     logger,
+		k3sMgr:     k3sMgr,
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		dir:        statePath("trash"),
+		resources:  toSet(splitList(getEnvOrDefault("NAUTILUS_TRASH_RESOURCES", defaultTrashResources))),
+		retention:  time.Duration(envCount("NAUTILUS_TRASH_RETENTION_HOURS", 72)) * time.Hour,
+		maxEntries: envCount("NAUTILUS_TRASH_MAX_ENTRIES", defaultTrashMaxEntries),
+		entries:    make(map[string]*TrashEntry),
+		counts:     make(map[string]int),
+	}
+
+	files, _ := filepath.Glob(filepath.Join(t.dir, "*.json"))
+	for _, file := range files {
+		var entry TrashEntry
+		if found, err := loadJSONState(file, &entry); err != nil || !found || entry.ID == "" {
+			logger.Warnf("⚠️ Ignoring unreadable trash entry %s: %v", file, err)
+			continue
+		}
+		entry.Object = nil
+		t.entries[entry.ID] = &entry
+	}
+	if len(t.entries) > 0 {
+		logger.Infof("🗑️ Trash holds %d deleted objects", len(t.entries))
+	}
+	return t
+}
+
+// Enabled - 리소스가 휴지통 대상인지 (nil이면 false)
+func (t *TrashBin) Enabled(resource string) bool {
+	return t != nil && t.resources[resource]
+}
+
+// Keep - 삭제된 객체 보관 (object는 삭제 직전 조회한 JSON, 소유자가 있는 객체는 무시)
+func (t *TrashBin) Keep(resource string, object []byte, deletedBy, source string) {
+	if !t.Enabled(resource) || len(object) == 0 {
+		return
+	}
+	var meta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name            string        `json:"name"`
+			Namespace       string        `json:"namespace"`
+			OwnerReferences []interface{} `json:"ownerReferences"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(object, &meta); err != nil || meta.Metadata.Name == "" {
+		t.logger.Warnf("⚠️ Deleted %s not kept in trash: unparseable object", resource)
+		t.count("failed")
+		return
+	}
+	if len(meta.Metadata.OwnerReferences) > 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	entry := &TrashEntry{
+		ID:         "trash-" + randomToken(12),
+		Resource:   resource,
+		Kind:       meta.Kind,
+		APIVersion: meta.APIVersion,
+		Namespace:  meta.Metadata.Namespace,
+		Name:       meta.Metadata.Name,
+		DeletedBy:  deletedBy,
+		Source:     source,
+		DeletedAt:  now,
+		ExpiresAt:  now.Add(t.retention),
+		Object:     json.RawMessage(object),
+	}
+	// 새 항목 저장과 최대 보관 수를 넘은 항목 삭제를 한 트랜잭션으로 (중간에 죽어도 보관 수 유지)
+	t.mutex.Lock()
+	evicted := t.overflowLocked()
+	txn := newStateTxn()
+	err := txn.Put(t.entryPath(entry.ID), entry)
+	for _, id := range evicted {
+		txn.Delete(t.entryPath(id))
+	}
+	if err == nil {
+		err = txn.Commit()
+	}
+	if err != nil {
+		t.counts["failed"]++
+		t.mutex.Unlock()
+		t.logger.Warnf("⚠️ Deleted %s %s not kept in trash: %v", resource, entry.qualifiedName(), err)
+		return
+	}
+	listed := *entry
+	listed.Object = nil
+	t.entries[entry.ID] = &listed
+	t.counts["trashed"]++
+	for _, id := range evicted {
+		delete(t.entries, id)
+		t.counts["expired"]++
+	}
+	t.mutex.Unlock()
+
+	t.logger.Infof("🗑️ %s %s moved to trash as %s (restorable until %s)", resource, entry.qualifiedName(), entry.ID, entry.ExpiresAt.Format(time.RFC3339))
+}
+
+// overflowLocked - 항목 하나를 더하면 최대 보관 수를 넘게 되는 오래된 항목
+func (t *TrashBin) overflowLocked() []string {
+	if len(t.entries) < t.maxEntries {
+		return nil
+	}
+	ordered := t.sortedLocked()
+	var evicted []string
+	for _, entry := range ordered[:len(ordered)+1-t.maxEntries] {
+		evicted = append(evicted, entry.ID)
+	}
+	return evicted
+}
+
+func (t *TrashBin) sortedLocked() []*TrashEntry {
+	entries := make([]*TrashEntry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.Before(entries[j].DeletedAt) })
+	return entries
+}
+
+func (t *TrashBin) entryPath(id string) string {
+	return filepath.Join(t.dir, id+".json")
+}
+
+func (t *TrashBin) remove(id string) {
+	if err := os.Remove(t.entryPath(id)); err != nil && !os.IsNotExist(err) {
+		t.logger.Warnf("⚠️ Failed to remove trash entry %s: %v", id, err)
+	}
+}
+
+// removeAll - 여러 항목 파일을 한 트랜잭션으로 삭제 (중단되면 재시작 시 나머지 삭제)
+func (t *TrashBin) removeAll(ids []string) {
+	txn := newStateTxn()
+	for _, id := range ids {
+		txn.Delete(t.entryPath(id))
+	}
+	if err := txn.Commit(); err != nil {
+		t.logger.Warnf("⚠️ Failed to remove %d trash entries: %v", len(ids), err)
+	}
+}
+
+func (t *TrashBin) count(outcome string) {
+	t.mutex.Lock()
+	t.counts[outcome]++
+	t.mutex.Unlock()
+}
+
+func (e *TrashEntry) qualifiedName() string {
+	if e.Namespace == "" {
+		return e.Name
+	}
+	return e.Namespace + "/" + e.Name
+}
+
+// Middleware - K8s API 프록시의 이름 지정 DELETE를 가로채 삭제 직전 객체 보관 (dryRun 제외)
+func (t *TrashBin) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource, ok := trashTarget(r.URL.Path)
+		if r.Method != http.MethodDelete || !ok || !t.Enabled(resource) || r.URL.Query().Get("dryRun") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// 조회에 실패하면(없는 객체 등) 보관 없이 삭제만 전달
+		object, err := t.k3sMgr.RunKubectl(nil, "get", "--raw", r.URL.Path)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		recorder := &sloRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if recorder.status < 300 {
+			t.Keep(resource, object, r.Header.Get("Impersonate-User"), "kubectl")
+		}
+	})
+}
+
+/*
+trashTarget - 단일 객체 경로의 리소스 이름 (하위 리소스와 컬렉션은 false)
+
+	/api/v1/namespaces/default/configmaps/app  → configmaps
+	/apis/apps/v1/namespaces/default/deployments/web → deployments
+	/api/v1/namespaces/default/pods/web/status → false
+*/
+func trashTarget(path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "", false
+	}
+	if len(segments) == 4 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		return "", false
+	}
+	return segments[0], true
+}
+
+// restorableObject - 다시 만들 수 있도록 서버가 채우는 필드 제거
+func restorableObject(data []byte) ([]byte, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"uid", "resourceVersion", "creationTimestamp", "deletionTimestamp",
+			"deletionGracePeriodSeconds", "generation", "managedFields", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	// 할당된 ClusterIP는 이미 다른 Service가 쓰고 있을 수 있으므로 다시 할당받음 (headless는 유지)
+	if object["kind"] == "Service" {
+		if spec, ok := object["spec"].(map[string]interface{}); ok && spec["clusterIP"] != "None" {
+			delete(spec, "clusterIP")
+			delete(spec, "clusterIPs")
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Restore - 보관한 객체를 다시 생성하고 휴지통에서 제거 (이미 있으면 오류)
+func (t *TrashBin) Restore(id string) (*TrashEntry, []byte, error) {
+	t.mutex.Lock()
+	listed, ok := t.entries[id]
+	t.mutex.Unlock()
+	if !ok {
+		return nil, nil, errTrashNotFound
+	}
+	var entry TrashEntry
+	if _, err := loadJSONState(t.entryPath(id), &entry); err != nil {
+		return nil, nil, err
+	}
+	object, err := restorableObject(entry.Object)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trash entry %s is corrupt: %v", id, err)
+	}
+	output, err := t.k3sMgr.RunKubectl(object, "create", "-f", "-", "-o", "json")
+	if err != nil {
+		t.count("failed")
+		return nil, nil, err
+	}
+
+	t.mutex.Lock()
+	delete(t.entries, id)
+	t.counts["restored"]++
+	t.mutex.Unlock()
+	t.remove(id)
+	t.logger.Infof("♻️ %s %s restored from trash %s", listed.Resource, listed.qualifiedName(), id)
+	return listed, output, nil
+}
+
+// Purge - 보관 항목 즉시 삭제
+func (t *TrashBin) Purge(id string) bool {
+	t.mutex.Lock()
+	_, ok := t.entries[id]
+	if ok {
+		delete(t.entries, id)
+		t.counts["purged"]++
+	}
+	t.mutex.Unlock()
+	if ok {
+		t.remove(id)
+	}
+	return ok
+}
+
+// PurgeNamespaces - 네임스페이스의 보관 항목 삭제 (테넌트 데이터 삭제에서 호출)
+func (t *TrashBin) PurgeNamespaces(namespaces []string) int {
+	inNamespaces := toSet(namespaces)
+	t.mutex.Lock()
+	var purged []string
+	for id, entry := range t.entries {
+		if inNamespaces[entry.Namespace] {
+			delete(t.entries, id)
+			purged = append(purged, id)
+			t.counts["purged"]++
+		}
+	}
+	t.mutex.Unlock()
+	t.removeAll(purged)
+	return len(purged)
+}
+
+// Start - 보관 기간이 지난 항목 주기적 정리
+func (t *TrashBin) Start(ctx context.Context) {
+	t.logger.Infof("🗑️ Soft delete enabled for %s (retention %s)", joinSorted(t.resources, ","), t.retention)
+
+	ticker := time.NewTicker(trashGCInterval)
+	defer ticker.Stop()
+	for {
+		t.collect(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TrashBin) collect(now time.Time) {
+	t.mutex.Lock()
+	var expired []string
+	for id, entry := range t.entries {
+		if now.After(entry.ExpiresAt) {
+			delete(t.entries, id)
+			expired = append(expired, id)
+			t.counts["expired"]++
+		}
+	}
+	t.mutex.Unlock()
+
+	t.removeAll(expired)
+	if len(expired) > 0 {
+		t.logger.Infof("🧹 Purged %d expired trash entries", len(expired))
+	}
+}
+
+// List - 보관 항목 (오래된 순, 비어 있지 않은 조건만 적용)
+func (t *TrashBin) List(namespace, resource string) []TrashEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	entries := []TrashEntry{}
+	for _, entry := range t.sortedLocked() {
+		if (namespace == "" || entry.Namespace == namespace) && (resource == "" || entry.Resource == resource) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+func (t *TrashBin) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if t.adminToken == "" {
+		http.Error(w, "Trash API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(t.adminToken)) != 1 {
+		t.logger.Warnf("🚫 Unauthorized trash API access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handleTrash - 휴지통 조회/삭제 (/api/v1/admin/trash, 관리자 토큰 필요)
+
+	GET    ?namespace=&resource=   보관 항목 목록 (객체 본문 제외)
+	GET    ?id=                    보관 항목과 삭제 직전 객체
+	DELETE ?id=                    보관 항목 즉시 삭제
+*/
+func (t *TrashBin) handleTrash(w http.ResponseWriter, r *http.Request) {
+	if !t.authorize(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	id := query.Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		if id == "" {
+			writeClientJSON(w, t.List(query.Get("namespace"), query.Get("resource")))
+			return
+		}
+		t.mutex.Lock()
+		_, ok := t.entries[id]
+		t.mutex.Unlock()
+		var entry TrashEntry
+		if ok {
+			ok, _ = loadJSONState(t.entryPath(id), &entry)
+		}
+		if !ok {
+			http.Error(w, "Trash entry not found", http.StatusNotFound)
+			return
+		}
+		writeClientJSON(w, entry)
+
+	case http.MethodDelete:
+		if !t.Purge(id) {
+			http.Error(w, "Trash entry not found", http.StatusNotFound)
+			return
+		}
+		t.logger.Infof("🗑️ Trash entry %s purged", id)
+		writeClientJSON(w, map[string]string{"id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRestore - 보관 항목 복원 (POST /api/v1/admin/trash/restore?id=, 관리자 토큰 필요)
+func (t *TrashBin) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if !t.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	entry, output, err := t.Restore(id)
+	switch {
+	case err == errTrashNotFound:
+		http.Error(w, "Trash entry not found", http.StatusNotFound)
+		return
+	case err != nil && strings.Contains(err.Error(), "AlreadyExists"):
+		http.Error(w, "Cannot restore: an object with the same name already exists", http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Restore failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeClientJSON(w, map[string]interface{}{"entry": entry, "object": json.RawMessage(output)})
+}
+
+// writeMetrics - 보관 항목 수와 휴지통 처리 결과
+func (t *TrashBin) writeMetrics(w io.Writer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_trash_entries", "gauge", "Deleted objects held in the soft-delete trash")
+	writeMetric(w, "nautilus_trash_entries", nil, float64(len(t.entries)))
+	writeMetricHeader(w, "nautilus_trash_operations_total", "counter", "Soft-delete trash operations by outcome")
+	for _, outcome := range []string{"trashed", "restored", "purged", "expired", "failed"} {
+		writeMetric(w, "nautilus_trash_operations_total", map[string]string{"outcome": outcome}, float64(t.counts[outcome]))
+	}
+}
@@ -0,0 +1,179 @@
+// Version Skew - 워커/Gateway와 빌드 버전 교환, 지원 범위를 벗어난 버전 경고 또는 거부
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	version "github.com/k3s-io/daas-version"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+VersionSkew - 구성요소 간 버전 핸드셰이크 (서버 쪽)
+
+  - 모든 응답에 X-Daas-Version: master/<버전>을 붙여 워커와 Gateway가 마스터 버전을 확인
+  - 워커 등록과 Gateway 요청이 보낸 X-Daas-Version을 NAUTILUS_VERSION_SKEW_POLICY로 판정
+    warn(기본): 경고 로그와 /healthz?verbose, 메트릭에 표시 / refuse: 426 Upgrade Required / off
+  - 워커와 Gateway는 마스터보다 최대 NAUTILUS_VERSION_MAX_MINOR_SKEW(기본 2) 마이너 버전 낮을 수 있고 높을 수는 없음
+    → 업그레이드는 마스터 먼저
+  - dev 빌드처럼 semver가 아닌 버전은 비교하지 않음
+*/
+
+// peerVersionTTL - 이 시간 동안 요청이 없는 구성요소는 목록에서 제외
+const peerVersionTTL = 24 * time.Hour
+
+// PeerVersion - 마지막으로 확인한 구성요소 버전
+type PeerVersion struct {
+	Component string    `json:"component"`
+	Peer      string    `json:"peer"`
+	Version   string    `json:"version"`
+	Skew      string    `json:"skew,omitempty"`
+	Refused   bool      `json:"refused,omitempty"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// VersionSkew - 버전 정책과 확인한 구성요소 목록
+type VersionSkew struct {
+	logger *logrus.Logger
+	local  version.Info
+	policy version.Policy
+
+	mutex sync.Mutex
+	peers map[string]*PeerVersion
+}
+
+// NewVersionSkew - NAUTILUS_VERSION_SKEW_POLICY / NAUTILUS_VERSION_MAX_MINOR_SKEW로 생성
+func NewVersionSkew(logger *logrus.Logger) (*VersionSkew, error) {
+	policy, err := version.PolicyFromEnv("NAUTILUS")
+	if err != nil {
+		return nil, err
+	}
+	return &VersionSkew{
+		logger: logger,
+		local:  version.Get("master"),
+		policy: policy,
+		peers:  make(map[string]*PeerVersion),
+	}, nil
+}
+
+// Middleware - 응답에 마스터 버전을 붙이고, 요청에 버전이 있으면 정책 적용
+func (v *VersionSkew) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(version.Header, v.local.HeaderValue())
+
+		component, peerVersion, ok := version.ParseHeader(r.Header.Get(version.Header))
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		peer := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(peer); err == nil {
+			peer = host
+		}
+		if err := v.Check(component, peer, peerVersion); err != nil {
+			httpserver.WriteStatus(w, http.StatusUpgradeRequired, "UpgradeRequired", err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Check - 구성요소 버전 기록, refuse 정책에서 지원 범위를 벗어나면 오류
+func (v *VersionSkew) Check(component, peer, peerVersion string) error {
+	skew := v.policy.Check(v.local.Version, peerVersion)
+	refused := v.policy.Refuses(skew)
+
+	key := component + "/" + peer
+	v.mutex.Lock()
+	previous, seen := v.peers[key]
+	changed := !seen || previous.Version != peerVersion
+	entry := &PeerVersion{Component: component, Peer: peer, Version: peerVersion, Refused: refused, LastSeen: time.Now()}
+	if skew != nil {
+		entry.Skew = skew.Error()
+	}
+	v.peers[key] = entry
+	v.mutex.Unlock()
+
+	// 같은 구성요소의 같은 버전은 처음 한 번만 기록 (하트비트마다 로그가 쌓이지 않도록)
+	if changed {
+		fields := logrus.Fields{"component": component, "peer": peer, "version": peerVersion, "master_version": v.local.Version}
+		switch {
+		case refused:
+			v.logger.WithFields(fields).Errorf("⛔ Refusing %s outside the supported version skew: %v", component, skew)
+		case skew != nil:
+			v.logger.WithFields(fields).Warnf("⚠️ %s is outside the supported version skew: %v", component, skew)
+		default:
+			v.logger.WithFields(fields).Infof("🔖 %s connected", component)
+		}
+	}
+	if refused {
+		return skew
+	}
+	return nil
+}
+
+// Peers - 최근 확인한 구성요소 버전 (구성요소, 주소 순)
+func (v *VersionSkew) Peers() []PeerVersion {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	cutoff := time.Now().Add(-peerVersionTTL)
+	peers := make([]PeerVersion, 0, len(v.peers))
+	for key, peer := range v.peers {
+		if peer.LastSeen.Before(cutoff) {
+			delete(v.peers, key)
+			continue
+		}
+		peers = append(peers, *peer)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Component != peers[j].Component {
+			return peers[i].Component < peers[j].Component
+		}
+		return peers[i].Peer < peers[j].Peer
+	})
+	return peers
+}
+
+// writeVerbose - /healthz?verbose 출력
+func (v *VersionSkew) writeVerbose(w io.Writer) {
+	fmt.Fprintf(w, "[+]version %s (skew policy %s, max %d minor)\n", v.local, v.policy.Mode, v.policy.MaxMinorSkew)
+	for _, peer := range v.Peers() {
+		mark := "+"
+		if peer.Skew != "" {
+			mark = "-"
+		}
+		fmt.Fprintf(w, "[%s]%s %s %s", mark, peer.Component, peer.Peer, peer.Version)
+		if peer.Skew != "" {
+			fmt.Fprintf(w, ": %s", peer.Skew)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeMetrics - 빌드 정보와 구성요소 버전 분포
+func (v *VersionSkew) writeMetrics(w io.Writer) {
+	writeMetricHeader(w, "nautilus_build_info", "gauge", "Build version of this master")
+	writeMetric(w, "nautilus_build_info", map[string]string{"version": v.local.Version, "commit": v.local.Commit, "go_version": v.local.GoVersion}, 1)
+
+	type group struct{ component, version, skewed string }
+	counts := make(map[group]int)
+	for _, peer := range v.Peers() {
+		skewed := "false"
+		if peer.Skew != "" {
+			skewed = "true"
+		}
+		counts[group{peer.Component, peer.Version, skewed}]++
+	}
+	writeMetricHeader(w, "nautilus_peer_versions", "gauge", "Workers and gateways seen in the last 24h by version")
+	for g, count := range counts {
+		writeMetric(w, "nautilus_peer_versions", map[string]string{"component": g.component, "version": g.version, "skewed": g.skewed}, float64(count))
+	}
+}
@@ -0,0 +1,276 @@
+// Wallet Monitor - 마스터 지갑 SUI 잔액 감시 (부족 경고, 테스트넷 faucet 자동 요청)
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sui "github.com/k3s-io/daas-sui"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+WalletMonitor - 가스를 지불하는 마스터 지갑의 잔액 확인
+
+마스터는 응답 기록, 감사 앵커, 가스 대납(GAS_SPONSORSHIP)에 지갑의 SUI를 쓰므로 잔액이 바닥나면
+트랜잭션이 조용히 실패합니다. 주기적으로 잔액을 조회해 임계값 아래로 내려가면 로그를 남기고
+nautilus_wallet_below_threshold 메트릭을 켜서 WalletBalanceLow/WalletBalanceCritical 알림 규칙이 발생하게 합니다.
+
+	NAUTILUS_WALLET_ADDRESSES               감시할 주소 (쉼표 구분, 기본 GAS_SPONSOR_ADDRESS 또는 sui CLI 활성 주소)
+	NAUTILUS_WALLET_LOW_BALANCE_MIST        경고 임계값 (기본 1 SUI)
+	NAUTILUS_WALLET_CRITICAL_BALANCE_MIST   심각 임계값 (기본 0.2 SUI)
+	NAUTILUS_WALLET_CHECK_INTERVAL_SECONDS  조회 주기 (기본 300)
+	NAUTILUS_WALLET_AUTO_FAUCET             true면 경고 임계값 아래에서 faucet 요청 (테스트넷/데브넷만, 1시간에 한 번)
+	NAUTILUS_SUI_FAUCET_URL                 faucet 주소 (기본 RPC 주소로 판단한 공용 faucet)
+
+메인넷 RPC에서는 faucet을 설정해도 요청하지 않습니다. mock 체인에는 가스가 없으므로 감시하지 않습니다.
+*/
+type WalletMonitor struct {
+	logger     *logrus.Logger
+	sui        *SuiIntegration
+	addresses  []string
+	low        uint64
+	critical   uint64
+	interval   time.Duration
+	faucetURL  string
+	cooldown   time.Duration
+	client     *http.Client
+	lookupAddr func() (string, error)
+
+	mutex   sync.Mutex
+	wallets map[string]*WalletBalance
+	faucet  map[string]int // succeeded, failed
+}
+
+// WalletBalance - 지갑 하나의 마지막 조회 결과
+type WalletBalance struct {
+	Address       string    `json:"address"`
+	BalanceMist   uint64    `json:"balance_mist"`
+	Level         string    `json:"level"` // ok, low, critical, unknown
+	CheckedAt     time.Time `json:"checked_at"`
+	Error         string    `json:"error,omitempty"`
+	FaucetAt      time.Time `json:"faucet_requested_at,omitempty"`
+	FaucetError   string    `json:"faucet_error,omitempty"`
+	previousLevel string
+}
+
+// NewWalletMonitor - 새 지갑 잔액 감시기 생성 (임계값 형식이 잘못되면 오류)
+func NewWalletMonitor(logger *logrus.Logger, suiIntegration *SuiIntegration) (*WalletMonitor, error) {
+	low, err := strconv.ParseUint(getEnvOrDefault("NAUTILUS_WALLET_LOW_BALANCE_MIST", strconv.Itoa(sui.MistPerSui)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAUTILUS_WALLET_LOW_BALANCE_MIST: %v", err)
+	}
+	critical, err := strconv.ParseUint(getEnvOrDefault("NAUTILUS_WALLET_CRITICAL_BALANCE_MIST", strconv.Itoa(sui.MistPerSui/5)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAUTILUS_WALLET_CRITICAL_BALANCE_MIST: %v", err)
+	}
+	if critical > low {
+		return nil, fmt.Errorf("NAUTILUS_WALLET_CRITICAL_BALANCE_MIST (%d) must not exceed NAUTILUS_WALLET_LOW_BALANCE_MIST (%d)", critical, low)
+	}
+
+	m := &WalletMonitor{
+		logger:    logger,
+		sui:       suiIntegration,
+		addresses: splitList(os.Getenv("NAUTILUS_WALLET_ADDRESSES")),
+		low:       low,
+		critical:  critical,
+		interval:  envSeconds("NAUTILUS_WALLET_CHECK_INTERVAL_SECONDS", 300),
+		cooldown:  time.Hour,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		lookupAddr: func() (string, error) {
+			output, err := exec.Command("sui", "client", "active-address").Output()
+			return strings.TrimSpace(string(output)), err
+		},
+		wallets: make(map[string]*WalletBalance),
+		faucet:  make(map[string]int),
+	}
+	if getEnvOrDefault("NAUTILUS_WALLET_AUTO_FAUCET", "false") == "true" {
+		rpcURL := suiIntegration.chain.Endpoint()
+		m.faucetURL = getEnvOrDefault("NAUTILUS_SUI_FAUCET_URL", sui.FaucetURL(rpcURL))
+		if sui.IsMainnet(rpcURL) || m.faucetURL == "" {
+			logger.Warnf("⚠️ NAUTILUS_WALLET_AUTO_FAUCET ignored: no faucet for %s", rpcURL)
+			m.faucetURL = ""
+		}
+	}
+	return m, nil
+}
+
+// Start - 주기적 잔액 조회 (Sui 체인 백엔드에서만)
+func (m *WalletMonitor) Start(ctx context.Context) {
+	if !m.sui.onSui() {
+		return
+	}
+	if len(m.addresses) == 0 {
+		address := os.Getenv("GAS_SPONSOR_ADDRESS")
+		if address == "" {
+			var err error
+			if address, err = m.lookupAddr(); err != nil || address == "" {
+				m.logger.Warnf("⚠️ Wallet balance monitor disabled: no NAUTILUS_WALLET_ADDRESSES and sui CLI has no active address (%v)", err)
+				return
+			}
+		}
+		m.addresses = []string{address}
+	}
+	m.logger.Infof("👛 Monitoring wallet balance of %s (low %s, critical %s)",
+		strings.Join(m.addresses, ", "), formatSui(m.low), formatSui(m.critical))
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check - 모든 지갑 잔액 조회, 임계값 전이 로그, 필요하면 faucet 요청
+func (m *WalletMonitor) check(ctx context.Context) {
+	for _, address := range m.addresses {
+		queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		balance, err := m.sui.chain.Balance(queryCtx, address)
+		cancel()
+
+		m.mutex.Lock()
+		wallet := m.wallets[address]
+		if wallet == nil {
+			wallet = &WalletBalance{Address: address, previousLevel: "ok"}
+			m.wallets[address] = wallet
+		}
+		wallet.CheckedAt = time.Now().UTC()
+		if err != nil {
+			// 조회 실패는 마지막 잔액과 단계를 유지 (RPC 장애를 잔액 부족으로 오인하지 않음)
+			wallet.Error = err.Error()
+			if wallet.Level == "" {
+				wallet.Level = "unknown"
+			}
+			m.mutex.Unlock()
+			m.logger.Warnf("⚠️ Failed to query balance of %s: %v", address, err)
+			continue
+		}
+		wallet.Error = ""
+		wallet.BalanceMist = balance
+		wallet.Level = m.level(balance)
+		changed := wallet.Level != wallet.previousLevel
+		wallet.previousLevel = wallet.Level
+		requestFaucet := m.faucetURL != "" && wallet.Level != "ok" && time.Since(wallet.FaucetAt) >= m.cooldown
+		if requestFaucet {
+			wallet.FaucetAt = time.Now().UTC()
+		}
+		m.mutex.Unlock()
+
+		switch {
+		case changed && wallet.Level == "critical":
+			m.logger.Errorf("🪫 Wallet %s balance %s is below the critical threshold %s, transactions will start failing",
+				address, formatSui(balance), formatSui(m.critical))
+		case changed && wallet.Level == "low":
+			m.logger.Warnf("🪫 Wallet %s balance %s is below %s, top it up", address, formatSui(balance), formatSui(m.low))
+		case changed:
+			m.logger.Infof("✅ Wallet %s balance recovered to %s", address, formatSui(balance))
+		}
+		if requestFaucet {
+			m.requestFaucet(ctx, address)
+		}
+	}
+}
+
+func (m *WalletMonitor) level(balance uint64) string {
+	switch {
+	case balance < m.critical:
+		return "critical"
+	case balance < m.low:
+		return "low"
+	}
+	return "ok"
+}
+
+// requestFaucet - 테스트넷 faucet에 가스 요청 (결과는 다음 조회에서 잔액으로 확인)
+func (m *WalletMonitor) requestFaucet(ctx context.Context, address string) {
+	err := sui.RequestFaucet(ctx, m.client, m.faucetURL, address)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	wallet := m.wallets[address]
+	if err != nil {
+		m.faucet["failed"]++
+		wallet.FaucetError = err.Error()
+		m.logger.Warnf("⚠️ Faucet request for %s failed: %v", address, err)
+		return
+	}
+	m.faucet["succeeded"]++
+	wallet.FaucetError = ""
+	m.logger.Infof("🚰 Requested testnet gas for %s from %s", address, m.faucetURL)
+}
+
+// Wallets - 지갑별 마지막 조회 결과 (주소 순)
+func (m *WalletMonitor) Wallets() []WalletBalance {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	wallets := make([]WalletBalance, 0, len(m.wallets))
+	for _, wallet := range m.wallets {
+		wallets = append(wallets, *wallet)
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].Address < wallets[j].Address })
+	return wallets
+}
+
+// writeVerbose - /healthz?verbose 지갑 줄 (임계값 아래면 [-])
+func (m *WalletMonitor) writeVerbose(w io.Writer) {
+	for _, wallet := range m.Wallets() {
+		mark := "+"
+		if wallet.Level != "ok" {
+			mark = "-"
+		}
+		fmt.Fprintf(w, "[%s]wallet %s %s", mark, wallet.Address, formatSui(wallet.BalanceMist))
+		switch {
+		case wallet.Error != "":
+			fmt.Fprintf(w, ": balance query failed: %s", wallet.Error)
+		case wallet.Level != "ok":
+			fmt.Fprintf(w, ": %s balance", wallet.Level)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeMetrics - 지갑 잔액과 임계값 상태, faucet 요청 결과
+func (m *WalletMonitor) writeMetrics(w io.Writer) {
+	wallets := m.Wallets()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_wallet_balance_mist", "gauge", "SUI balance of a master wallet in MIST")
+	for _, wallet := range wallets {
+		writeMetric(w, "nautilus_wallet_balance_mist", map[string]string{"address": wallet.Address}, float64(wallet.BalanceMist))
+	}
+	writeMetricHeader(w, "nautilus_wallet_below_threshold", "gauge", "Whether a master wallet balance is below the low or critical threshold")
+	for _, wallet := range wallets {
+		low, critical := 0.0, 0.0
+		switch wallet.Level {
+		case "critical":
+			low, critical = 1, 1
+		case "low":
+			low = 1
+		}
+		writeMetric(w, "nautilus_wallet_below_threshold", map[string]string{"address": wallet.Address, "threshold": "low"}, low)
+		writeMetric(w, "nautilus_wallet_below_threshold", map[string]string{"address": wallet.Address, "threshold": "critical"}, critical)
+	}
+	writeMetricHeader(w, "nautilus_wallet_faucet_requests_total", "counter", "Testnet faucet requests made for low master wallets")
+	writeMetric(w, "nautilus_wallet_faucet_requests_total", map[string]string{"outcome": "succeeded"}, float64(m.faucet["succeeded"]))
+	writeMetric(w, "nautilus_wallet_faucet_requests_total", map[string]string{"outcome": "failed"}, float64(m.faucet["failed"]))
+}
+
+// formatSui - MIST를 SUI 단위 문자열로
+func formatSui(mist uint64) string {
+	return strconv.FormatFloat(float64(mist)/sui.MistPerSui, 'f', -1, 64) + " SUI"
+}
@@ -0,0 +1,178 @@
+// Watch Stream - watch 북마크 및 resourceVersion 기반 재개 지원
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const watchEventBookmark = "BOOKMARK"
+
+// isWatchRequest - watch=true|1 쿼리 여부
+func isWatchRequest(r *http.Request) bool {
+	watch := r.URL.Query().Get("watch")
+	return watch == "true" || watch == "1"
+}
+
+/*
+bookmarkKey - 북마크 저장 키 (watch 대상 경로)
+구식 watch 경로(/api/v1/watch/namespaces/default/pods)와 끝 슬래시를 표준 경로로 통일하여
+같은 대상의 북마크가 여러 키로 나뉘지 않게 합니다.
+*/
+func bookmarkKey(p string) string {
+	segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+	watchAt := -1
+	switch {
+	case len(segments) > 3 && segments[1] == "api":
+		watchAt = 3
+	case len(segments) > 4 && segments[1] == "apis":
+		watchAt = 4
+	}
+	if watchAt > 0 && segments[watchAt] == "watch" {
+		segments = append(segments[:watchAt], segments[watchAt+1:]...)
+	}
+	return strings.Join(segments, "/")
+}
+
+// isJSONWatch - 줄 단위 JSON 이벤트로 응답받는 watch인지 (Protobuf 스트림은 건드리지 않음)
+func isJSONWatch(r *http.Request) bool {
+	return isWatchRequest(r) && !strings.Contains(r.Header.Get("Accept"), "protobuf")
+}
+
+/*
+requestWatchBookmarks - K3s로 보내는 watch 요청에 allowWatchBookmarks=true 추가
+
+변경이 없는 리소스도 K3s가 주기적으로 BOOKMARK 이벤트를 보내므로
+마스터가 기록하는 마지막 resourceVersion이 compaction 범위 밖으로 밀려나지 않습니다.
+클라이언트가 요청하지 않은 북마크는 watchStream이 걸러냅니다.
+*/
+func requestWatchBookmarks(req *http.Request) {
+	if !isJSONWatch(req) {
+		return
+	}
+	query := req.URL.Query()
+	if query.Get("allowWatchBookmarks") == "true" {
+		return
+	}
+	query.Set("allowWatchBookmarks", "true")
+	req.URL.RawQuery = query.Encode()
+}
+
+// watchEvent - watch 이벤트 중 북마크에 필요한 필드
+type watchEvent struct {
+	Type   string `json:"type"`
+	Object struct {
+		Kind       string `json:"kind"`
+		APIVersion string `json:"apiVersion"`
+		Metadata   struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	} `json:"object"`
+}
+
+/*
+watchStream - JSON watch 스트림을 줄 단위로 전달하며 resourceVersion 추적
+
+  - 모든 이벤트(북마크 포함)의 resourceVersion을 Drainer에 경로별로 기록
+  - 클라이언트가 allowWatchBookmarks를 요청하지 않았으면 BOOKMARK 이벤트 제거
+  - 드레인으로 스트림이 끊길 때 마지막 resourceVersion을 BOOKMARK로 보내
+    informer가 전체 재조회 없이 그 지점부터 watch를 재개하도록 함
+*/
+type watchStream struct {
+	http.ResponseWriter
+	drainer         *Drainer
+	key             string
+	clientBookmarks bool
+
+	partial         []byte
+	kind            string
+	apiVersion      string
+	resourceVersion string
+	delivered       string // 클라이언트가 마지막으로 받은 resourceVersion
+}
+
+func newWatchStream(w http.ResponseWriter, r *http.Request, drainer *Drainer) *watchStream {
+	return &watchStream{
+		ResponseWriter:  w,
+		drainer:         drainer,
+		key:             bookmarkKey(r.URL.Path),
+		clientBookmarks: r.URL.Query().Get("allowWatchBookmarks") == "true",
+		resourceVersion: r.URL.Query().Get("resourceVersion"),
+	}
+}
+
+// Write - 완성된 줄만 클라이언트로 전달 (걸러낼 북마크가 중간에 잘리지 않도록)
+func (s *watchStream) Write(p []byte) (int, error) {
+	s.partial = append(s.partial, p...)
+	for {
+		idx := bytes.IndexByte(s.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		line := s.partial[:idx+1]
+		s.partial = s.partial[idx+1:]
+		if !s.observe(line[:idx]) {
+			continue
+		}
+		if _, err := s.ResponseWriter.Write(line); err != nil {
+			return 0, err
+		}
+		s.delivered = s.resourceVersion
+	}
+	// 비정상적으로 긴 줄은 그대로 흘려보냄 (메모리 보호)
+	if len(s.partial) > 1<<20 {
+		if _, err := s.ResponseWriter.Write(s.partial); err != nil {
+			return 0, err
+		}
+		s.partial = nil
+	}
+	return len(p), nil
+}
+
+// observe - 이벤트 한 줄을 파싱하고 클라이언트로 보낼지 여부 반환
+func (s *watchStream) observe(line []byte) bool {
+	var event watchEvent
+	// ERROR 이벤트(410 Expired 등)의 객체는 Status라 재개 위치가 아님
+	if json.Unmarshal(line, &event) != nil || event.Type == "ERROR" {
+		return true
+	}
+	if rv := event.Object.Metadata.ResourceVersion; rv != "" {
+		s.resourceVersion = rv
+		if s.drainer != nil {
+			s.drainer.recordBookmark(s.key, rv)
+		}
+	}
+	if event.Object.Kind != "" {
+		s.kind, s.apiVersion = event.Object.Kind, event.Object.APIVersion
+	}
+	return event.Type != watchEventBookmark || s.clientBookmarks
+}
+
+// finish - 남은 부분 줄을 내보내고, 드레인 중이면 마지막 위치를 BOOKMARK로 전달
+func (s *watchStream) finish() {
+	if len(s.partial) > 0 {
+		s.ResponseWriter.Write(s.partial)
+		s.partial = nil
+	}
+	if s.drainer == nil || !s.drainer.Draining() || !s.clientBookmarks || s.kind == "" ||
+		s.resourceVersion == "" || s.resourceVersion == s.delivered {
+		return
+	}
+
+	fmt.Fprintf(s.ResponseWriter, `{"type":%q,"object":{"kind":%q,"apiVersion":%q,"metadata":{"resourceVersion":%q}}}`+"\n",
+		watchEventBookmark, s.kind, s.apiVersion, s.resourceVersion)
+	s.Flush()
+}
+
+func (s *watchStream) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *watchStream) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
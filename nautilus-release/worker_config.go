@@ -0,0 +1,411 @@
+// Worker Config Sync - 워커 런타임 설정(이미지 미러, 하트비트 주기, 기능 게이트)을 버전 번들로 중앙 관리
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	workerConfigMinHeartbeat = 10  // 초
+	workerConfigMaxHeartbeat = 600 // 초
+	workerConfigHistoryLimit = 20
+)
+
+var featureGateName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+/*
+WorkerSettings - 중앙에서 관리하는 워커 설정
+비어 있는 필드는 워커의 로컬 설정을 그대로 씁니다.
+워커도 같은 JSON으로 체크섬을 다시 계산하므로 필드와 태그를 바꾸면 워커 쪽 정의도 함께 바꿔야 합니다.
+*/
+type WorkerSettings struct {
+	RegistryMirrors          []string        `json:"registry_mirrors,omitempty"`
+	HeartbeatIntervalSeconds int             `json:"heartbeat_interval_seconds,omitempty"`
+	FeatureGates             map[string]bool `json:"feature_gates,omitempty"`
+}
+
+// WorkerConfigBundle - 게시된 설정 번들 (버전은 1부터 단조 증가)
+type WorkerConfigBundle struct {
+	Version     int64          `json:"version"`
+	Settings    WorkerSettings `json:"settings"`
+	Checksum    string         `json:"checksum"` // settings JSON의 sha256
+	Comment     string         `json:"comment,omitempty"`
+	RollbackOf  int64          `json:"rollback_of,omitempty"`
+	PublishedAt time.Time      `json:"published_at"`
+}
+
+// WorkerConfigUpdate - 하트비트 응답으로 보내는 번들 (Changed는 워커가 적용한 버전 대비 바뀐 필드)
+type WorkerConfigUpdate struct {
+	WorkerConfigBundle
+	Changed []string `json:"changed"`
+}
+
+// WorkerConfigFailure - 워커가 검증/적용에 실패해 이전 설정으로 되돌린 번들
+type WorkerConfigFailure struct {
+	Version int64  `json:"version"`
+	Error   string `json:"error"`
+}
+
+// WorkerConfigStatus - 워커별 마지막 보고
+type WorkerConfigStatus struct {
+	NodeID         string    `json:"node_id"`
+	AppliedVersion int64     `json:"applied_version"`
+	FailedVersion  int64     `json:"failed_version,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	ReportedAt     time.Time `json:"reported_at"`
+}
+
+// workerConfigState - 상태 파일 형식
+type workerConfigState struct {
+	Bundles []WorkerConfigBundle           `json:"bundles"`
+	Workers map[string]*WorkerConfigStatus `json:"workers"`
+}
+
+/*
+WorkerConfigSync - 설정 번들 게시와 하트비트 기반 차등 배포
+
+  - 관리자가 번들을 게시하면 버전이 올라가고, 워커가 하트비트로 보고한 적용 버전이 다를 때만 응답에 번들을 실음
+  - 워커는 번들을 검증 후 원자적으로 적용하고, 실패하면 이전 설정을 유지한 채 실패 버전을 보고
+  - 실패가 보고된 버전은 같은 워커에 다시 보내지 않음 (새 버전 게시 또는 이전 버전으로 롤백 필요)
+*/
+type WorkerConfigSync struct {
+	logger     *logrus.Logger
+	history    *HeartbeatHistory
+	stateFile  string
+	adminToken string
+	mutex      sync.Mutex
+	bundles    []WorkerConfigBundle
+	workers    map[string]*WorkerConfigStatus
+	delivered  uint64
+	rejected   uint64
+}
+
+// NewWorkerConfigSync - 새 Worker Config Sync 생성 (게시 이력과 워커 보고 복원)
+func NewWorkerConfigSync(logger *logrus.Logger) *WorkerConfigSync {
+	c := &WorkerConfigSync{
+		logger:     logger,
+		stateFile:  statePath("worker-config.json"),
+		adminToken: os.Getenv("NAUTILUS_ADMIN_TOKEN"),
+		workers:    make(map[string]*WorkerConfigStatus),
+	}
+	var state workerConfigState
+	if _, err := loadJSONState(c.stateFile, &state); err != nil {
+		logger.Warnf("⚠️ Failed to load worker config bundles: %v", err)
+	}
+	c.bundles = state.Bundles
+	if state.Workers != nil {
+		c.workers = state.Workers
+	}
+	return c
+}
+
+// validate - 워커가 거부할 값은 게시 단계에서 먼저 거부
+func (s WorkerSettings) validate() error {
+	for _, mirror := range s.RegistryMirrors {
+		endpoint, err := url.Parse(mirror)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("invalid registry mirror %q", mirror)
+		}
+	}
+	if interval := s.HeartbeatIntervalSeconds; interval != 0 && (interval < workerConfigMinHeartbeat || interval > workerConfigMaxHeartbeat) {
+		return fmt.Errorf("heartbeat_interval_seconds must be between %d and %d", workerConfigMinHeartbeat, workerConfigMaxHeartbeat)
+	}
+	for name := range s.FeatureGates {
+		if !featureGateName.MatchString(name) {
+			return fmt.Errorf("invalid feature gate name %q", name)
+		}
+	}
+	return nil
+}
+
+// checksum - settings JSON의 sha256 (맵 키는 정렬되어 직렬화됨)
+func (s WorkerSettings) checksum() string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// changedFields - 두 설정 사이에 바뀐 JSON 필드 이름
+func (s WorkerSettings) changedFields(previous *WorkerSettings) []string {
+	changed := []string{}
+	current, before := reflect.ValueOf(s), reflect.ValueOf(WorkerSettings{})
+	if previous != nil {
+		before = reflect.ValueOf(*previous)
+	}
+	for i := 0; i < current.NumField(); i++ {
+		if previous != nil && reflect.DeepEqual(current.Field(i).Interface(), before.Field(i).Interface()) {
+			continue
+		}
+		changed = append(changed, strings.Split(current.Type().Field(i).Tag.Get("json"), ",")[0])
+	}
+	return changed
+}
+
+// Publish - 새 버전 게시
+func (c *WorkerConfigSync) Publish(settings WorkerSettings, comment string, rollbackOf int64) (*WorkerConfigBundle, error) {
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bundle := WorkerConfigBundle{
+		Version:     1,
+		Settings:    settings,
+		Checksum:    settings.checksum(),
+		Comment:     comment,
+		RollbackOf:  rollbackOf,
+		PublishedAt: time.Now(),
+	}
+	if current := c.currentLocked(); current != nil {
+		bundle.Version = current.Version + 1
+	}
+	c.bundles = append(c.bundles, bundle)
+	if len(c.bundles) > workerConfigHistoryLimit {
+		c.bundles = c.bundles[len(c.bundles)-workerConfigHistoryLimit:]
+	}
+	c.saveLocked()
+
+	c.logger.Infof("🧩 Worker config v%d published (%s)", bundle.Version, strings.Join(settings.changedFields(c.settingsLocked(bundle.Version-1)), ", "))
+	return &bundle, nil
+}
+
+// Rollback - 이전 버전의 설정을 새 버전으로 다시 게시 (워커는 항상 버전이 증가하는 번들만 받음)
+func (c *WorkerConfigSync) Rollback(version int64, comment string) (*WorkerConfigBundle, error) {
+	c.mutex.Lock()
+	settings := c.settingsLocked(version)
+	c.mutex.Unlock()
+	if settings == nil {
+		return nil, fmt.Errorf("worker config version %d not found", version)
+	}
+	if comment == "" {
+		comment = fmt.Sprintf("rollback to v%d", version)
+	}
+	return c.Publish(*settings, comment, version)
+}
+
+/*
+Observe - 하트비트의 적용 버전/실패 보고를 기록하고, 보내야 할 번들이 있으면 반환
+워커가 현재 버전을 이미 적용했거나 현재 버전을 거부했다면 nil입니다.
+*/
+func (c *WorkerConfigSync) Observe(nodeID string, applied int64, failure *WorkerConfigFailure) *WorkerConfigUpdate {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	status := c.workers[nodeID]
+	if status == nil {
+		status = &WorkerConfigStatus{NodeID: nodeID}
+		c.workers[nodeID] = status
+	}
+	previous := *status
+	status.AppliedVersion = applied
+	status.FailedVersion, status.Error = 0, ""
+	if failure != nil {
+		status.FailedVersion, status.Error = failure.Version, failure.Error
+	}
+	status.ReportedAt = time.Now()
+
+	if status.AppliedVersion != previous.AppliedVersion && applied > 0 {
+		c.logger.Infof("🧩 Worker %s applied config v%d", nodeID, applied)
+		c.history.RecordEvent(nodeID, "config_applied", fmt.Sprintf("v%d", applied))
+	}
+	if status.FailedVersion != 0 && status.FailedVersion != previous.FailedVersion {
+		c.rejected++
+		c.logger.Warnf("⚠️ Worker %s rejected config v%d, kept v%d: %s", nodeID, status.FailedVersion, applied, status.Error)
+		c.history.RecordEvent(nodeID, "config_rejected", fmt.Sprintf("v%d: %s", status.FailedVersion, status.Error))
+	}
+	if status.AppliedVersion != previous.AppliedVersion || status.FailedVersion != previous.FailedVersion {
+		c.saveLocked()
+	}
+
+	current := c.currentLocked()
+	if current == nil || applied == current.Version || status.FailedVersion == current.Version {
+		return nil
+	}
+	c.delivered++
+	return &WorkerConfigUpdate{
+		WorkerConfigBundle: *current,
+		Changed:            current.Settings.changedFields(c.settingsLocked(applied)),
+	}
+}
+
+func (c *WorkerConfigSync) currentLocked() *WorkerConfigBundle {
+	if len(c.bundles) == 0 {
+		return nil
+	}
+	return &c.bundles[len(c.bundles)-1]
+}
+
+// settingsLocked - 이력에 남은 버전의 설정 (없거나 정리된 버전이면 nil)
+func (c *WorkerConfigSync) settingsLocked(version int64) *WorkerSettings {
+	for i := range c.bundles {
+		if c.bundles[i].Version == version {
+			settings := c.bundles[i].Settings
+			return &settings
+		}
+	}
+	return nil
+}
+
+func (c *WorkerConfigSync) saveLocked() {
+	if err := saveJSONState(c.stateFile, workerConfigState{Bundles: c.bundles, Workers: c.workers}); err != nil {
+		c.logger.Warnf("⚠️ Failed to persist worker config bundles: %v", err)
+	}
+}
+
+// snapshot - 현재 번들, 이력(최신순), 워커 보고
+func (c *WorkerConfigSync) snapshot() (*WorkerConfigBundle, []WorkerConfigBundle, []WorkerConfigStatus) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var current *WorkerConfigBundle
+	if bundle := c.currentLocked(); bundle != nil {
+		copied := *bundle
+		current = &copied
+	}
+	history := make([]WorkerConfigBundle, 0, len(c.bundles))
+	for i := len(c.bundles) - 1; i >= 0; i-- {
+		history = append(history, c.bundles[i])
+	}
+	workers := make([]WorkerConfigStatus, 0, len(c.workers))
+	for _, status := range c.workers {
+		workers = append(workers, *status)
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].NodeID < workers[j].NodeID })
+	return current, history, workers
+}
+
+// authorize - NAUTILUS_ADMIN_TOKEN Bearer 인증 (미설정이면 API 비활성)
+func (c *WorkerConfigSync) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if c.adminToken == "" {
+		http.Error(w, "Worker config API disabled (NAUTILUS_ADMIN_TOKEN not set)", http.StatusForbidden)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) != 1 {
+		c.logger.Warnf("🚫 Unauthorized worker config access from %s", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+/*
+handleWorkerConfig - 설정 번들 조회/게시 (/api/v1/worker-config, 관리자 토큰 필요)
+
+	GET                                          현재 번들, 이력, 워커별 적용 버전
+	POST {"settings": {...}, "comment": "..."}   새 버전 게시
+*/
+func (c *WorkerConfigSync) handleWorkerConfig(w http.ResponseWriter, r *http.Request) {
+	if !c.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		current, history, workers := c.snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"current": current,
+			"history": history,
+			"workers": workers,
+		})
+
+	case http.MethodPost:
+		var request struct {
+			Settings WorkerSettings `json:"settings"`
+			Comment  string         `json:"comment"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid worker config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		bundle, err := c.Publish(request.Settings, request.Comment, 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "bundle": bundle})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRollback - 이전 버전 설정을 새 버전으로 재게시 (POST ?version=N)
+func (c *WorkerConfigSync) handleRollback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !c.authorize(w, r) {
+		return
+	}
+
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil || version <= 0 {
+		http.Error(w, "Expected ?version=N", http.StatusBadRequest)
+		return
+	}
+	bundle, err := c.Rollback(version, r.URL.Query().Get("comment"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "bundle": bundle})
+}
+
+// writeMetrics - 설정 동기화 메트릭 출력
+func (c *WorkerConfigSync) writeMetrics(w io.Writer) {
+	c.mutex.Lock()
+	var version int64
+	if current := c.currentLocked(); current != nil {
+		version = current.Version
+	}
+	upToDate, failing := 0, 0
+	for _, status := range c.workers {
+		if status.AppliedVersion == version {
+			upToDate++
+		}
+		if status.FailedVersion != 0 {
+			failing++
+		}
+	}
+	delivered, rejected := c.delivered, c.rejected
+	c.mutex.Unlock()
+
+	writeMetricHeader(w, "nautilus_worker_config_version", "gauge", "Latest published worker config bundle version")
+	writeMetric(w, "nautilus_worker_config_version", nil, float64(version))
+	writeMetricHeader(w, "nautilus_worker_config_workers", "gauge", "Workers by config sync state")
+	writeMetric(w, "nautilus_worker_config_workers", map[string]string{"state": "up_to_date"}, float64(upToDate))
+	writeMetric(w, "nautilus_worker_config_workers", map[string]string{"state": "rejected"}, float64(failing))
+	writeMetricHeader(w, "nautilus_worker_config_total", "counter", "Worker config bundles delivered in heartbeat responses and rejected by workers")
+	writeMetric(w, "nautilus_worker_config_total", map[string]string{"outcome": "delivered"}, float64(delivered))
+	writeMetric(w, "nautilus_worker_config_total", map[string]string{"outcome": "rejected"}, float64(rejected))
+}
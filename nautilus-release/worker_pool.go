@@ -1,7 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -18,6 +22,34 @@ type WorkerNode struct {
 	LastHeartbeat time.Time `json:"last_heartbeat"`
 	WorkerAddress string    `json:"worker_address"`
 	RegisteredAt  time.Time `json:"registered_at"`
+	Region        string    `json:"region,omitempty"`
+	Zone          string    `json:"zone,omitempty"`
+	LatencyMs     int64     `json:"latency_ms,omitempty"`
+	Role          string    `json:"role,omitempty"`
+	OS            string    `json:"os,omitempty"`
+	Arch          string    `json:"arch,omitempty"`
+	LabelsSynced  bool      `json:"-"`
+
+	Conditions []NodeCondition `json:"conditions,omitempty"`
+
+	// 하트비트 수집기 섹션 (워커 설정에 따라 gpu, temperature, 사용자 지표 등)
+	Telemetry       map[string]json.RawMessage `json:"telemetry,omitempty"`
+	TelemetryErrors map[string]string          `json:"telemetry_errors,omitempty"`
+
+	// 마지막 대역폭/지연 측정 결과와 다른 워커가 측정에 사용할 상태 서버 URL
+	Network    *NetworkReport `json:"network,omitempty"`
+	ProbeURL   string         `json:"probe_url,omitempty"`
+	ProbeToken string         `json:"-"`
+
+	// 마지막 하트비트 연결에서 관측한 워커 IP (kubelet serving 인증서의 IP SAN 확인용)
+	ObservedIP string `json:"observed_ip,omitempty"`
+}
+
+// NodeCondition - 워커가 하트비트로 보고하는 노드 조건 (DiskPressure, MemoryPressure)
+type NodeCondition struct {
+	Type    string `json:"type"`
+	Status  bool   `json:"status"`
+	Message string `json:"message,omitempty"`
 }
 
 // WorkerPool manages all worker nodes
@@ -25,6 +57,7 @@ type WorkerPool struct {
 	workers map[string]*WorkerNode
 	mutex   sync.RWMutex
 	logger  *logrus.Logger
+	stream  *EventStream // optional dashboard event stream
 }
 
 // NewWorkerPool creates a new worker pool
@@ -49,6 +82,7 @@ func (wp *WorkerPool) AddWorker(worker *WorkerNode) error {
 	wp.workers[worker.NodeID] = worker
 
 	wp.logger.Infof("👥 Worker added to pool: %s (stake: %d)", worker.NodeID, worker.StakeAmount)
+	wp.stream.PublishWorker(streamNode+".joined", worker, nil)
 	return nil
 }
 
@@ -61,6 +95,54 @@ func (wp *WorkerPool) GetWorker(nodeID string) (*WorkerNode, bool) {
 	return worker, exists
 }
 
+// WorkerBySealToken returns the worker holding the given seal token
+func (wp *WorkerPool) WorkerBySealToken(sealToken string) (*WorkerNode, bool) {
+	if sealToken == "" {
+		return nil, false
+	}
+
+	wp.mutex.RLock()
+	defer wp.mutex.RUnlock()
+
+	for _, worker := range wp.workers {
+		if worker.SealToken == sealToken {
+			return worker, true
+		}
+	}
+	return nil, false
+}
+
+// OwnerBySealToken returns the wallet address owning the worker with the given seal token
+func (wp *WorkerPool) OwnerBySealToken(sealToken string) (string, bool) {
+	if sealToken == "" {
+		return "", false
+	}
+
+	wp.mutex.RLock()
+	defer wp.mutex.RUnlock()
+
+	for _, worker := range wp.workers {
+		if worker.SealToken == sealToken && worker.WorkerAddress != "" {
+			return worker.WorkerAddress, true
+		}
+	}
+	return "", false
+}
+
+// StakeByOwner returns the total stake of workers owned by the given wallet address
+func (wp *WorkerPool) StakeByOwner(owner string) uint64 {
+	wp.mutex.RLock()
+	defer wp.mutex.RUnlock()
+
+	var total uint64
+	for _, worker := range wp.workers {
+		if worker.WorkerAddress == owner {
+			total += worker.StakeAmount
+		}
+	}
+	return total
+}
+
 // GetAvailableWorker returns an available worker for scheduling
 func (wp *WorkerPool) GetAvailableWorker() *WorkerNode {
 	wp.mutex.RLock()
@@ -103,6 +185,7 @@ func (wp *WorkerPool) UpdateWorkerStatus(nodeID, status string) error {
 	worker.LastHeartbeat = time.Now()
 
 	wp.logger.Infof("🔄 Worker %s status: %s → %s", nodeID, oldStatus, status)
+	wp.publishStatus(worker, oldStatus)
 	return nil
 }
 
@@ -121,6 +204,177 @@ func (wp *WorkerPool) SetWorkerJoinToken(nodeID, joinToken string) error {
 	return nil
 }
 
+// UpdateWorkerTopology records a heartbeat with the worker's reported locality.
+// It returns true when region, zone or the 10ms latency bucket changed and
+// node labels need a resync.
+func (wp *WorkerPool) UpdateWorkerTopology(nodeID, region, zone string, latencyMs int64) (bool, error) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if !exists {
+		return false, fmt.Errorf("worker %s not found", nodeID)
+	}
+
+	changed := worker.Region != region || worker.Zone != zone || worker.LatencyMs/10 != latencyMs/10
+	worker.Region = region
+	worker.Zone = zone
+	worker.LatencyMs = latencyMs
+	worker.LastHeartbeat = time.Now()
+
+	if changed {
+		wp.logger.Infof("🌍 Worker %s topology: region=%s zone=%s (latency %dms)", nodeID, region, zone, latencyMs)
+	}
+	return changed, nil
+}
+
+// UpdateWorkerPlatform records the OS and CPU architecture a worker reports.
+// It returns true when either changed and the node's platform labels need a
+// resync. Empty values (older workers) leave the recorded platform untouched.
+func (wp *WorkerPool) UpdateWorkerPlatform(nodeID, osName, arch string) bool {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if !exists || osName == "" || arch == "" {
+		return false
+	}
+	if worker.OS == osName && worker.Arch == arch {
+		return false
+	}
+	worker.OS = osName
+	worker.Arch = arch
+	wp.logger.Infof("🧬 Worker %s platform: %s/%s", nodeID, osName, arch)
+	return true
+}
+
+// WorkerPlatforms returns the distinct os/arch pairs reported by workers
+// that can run pods (slashed workers excluded).
+func (wp *WorkerPool) WorkerPlatforms() []string {
+	wp.mutex.RLock()
+	defer wp.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var platforms []string
+	for _, worker := range wp.workers {
+		if worker.Arch == "" || worker.Status == "slashed" {
+			continue
+		}
+		platform := worker.OS + "/" + worker.Arch
+		if !seen[platform] {
+			seen[platform] = true
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// UpdateWorkerNetwork records the worker's probe endpoint and its latest
+// bandwidth measurement. It returns true when a new measurement arrived and
+// the node's bandwidth labels and probe annotation need a resync.
+func (wp *WorkerPool) UpdateWorkerNetwork(nodeID, probeURL, token string, report *NetworkReport) bool {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if !exists {
+		return false
+	}
+	worker.ProbeURL = probeURL
+	worker.ProbeToken = token
+	if report == nil || (worker.Network != nil && worker.Network.MeasuredAt.Equal(report.MeasuredAt)) {
+		return false
+	}
+
+	previous := worker.Network
+	worker.Network = report
+	if previous == nil || bandwidthBucket(previous.DownloadMbps) != bandwidthBucket(report.DownloadMbps) ||
+		bandwidthBucket(previous.UploadMbps) != bandwidthBucket(report.UploadMbps) {
+		wp.logger.Infof("📶 Worker %s bandwidth: %.1f Mbps down, %.1f Mbps up", nodeID, report.DownloadMbps, report.UploadMbps)
+	}
+	return true
+}
+
+// UpdateWorkerStake records a stake top-up or partial withdrawal; the stake
+// tier label is refreshed with the next heartbeat
+func (wp *WorkerPool) UpdateWorkerStake(nodeID string, amount uint64) error {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if !exists {
+		return fmt.Errorf("worker %s not found", nodeID)
+	}
+	if worker.StakeAmount != amount {
+		wp.logger.Infof("💰 Worker %s stake: %d → %d", nodeID, worker.StakeAmount, amount)
+		worker.StakeAmount = amount
+		worker.LabelsSynced = false
+	}
+	return nil
+}
+
+// UpdateWorkerConditions stores reported node conditions and returns the ones whose status changed
+func (wp *WorkerPool) UpdateWorkerConditions(nodeID string, conditions []NodeCondition) []NodeCondition {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if !exists {
+		return nil
+	}
+
+	previous := make(map[string]bool, len(worker.Conditions))
+	for _, condition := range worker.Conditions {
+		previous[condition.Type] = condition.Status
+	}
+	var transitions []NodeCondition
+	for _, condition := range conditions {
+		if previous[condition.Type] != condition.Status {
+			transitions = append(transitions, condition)
+		}
+	}
+	worker.Conditions = conditions
+	return transitions
+}
+
+// UpdateWorkerTelemetry - 마지막 하트비트의 수집기 섹션으로 교체
+func (wp *WorkerPool) UpdateWorkerTelemetry(nodeID string, sections map[string]json.RawMessage, failures map[string]string) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	if worker, exists := wp.workers[nodeID]; exists {
+		worker.Telemetry = sections
+		worker.TelemetryErrors = failures
+	}
+}
+
+// UpdateWorkerAddress - 하트비트 연결의 원격 IP 기록 (워커가 보고한 값이 아닌 마스터가 관측한 값)
+func (wp *WorkerPool) UpdateWorkerAddress(nodeID, remoteAddr string) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil || net.ParseIP(host) == nil {
+		return
+	}
+
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	if worker, exists := wp.workers[nodeID]; exists {
+		worker.ObservedIP = host
+	}
+}
+
+// ObservedIP - 워커의 관측 IP (아직 하트비트가 없으면 빈 문자열)
+func (wp *WorkerPool) ObservedIP(nodeID string) string {
+	wp.mutex.RLock()
+	defer wp.mutex.RUnlock()
+
+	if worker, exists := wp.workers[nodeID]; exists {
+		return worker.ObservedIP
+	}
+	return ""
+}
+
 // GetWorkerStats returns worker pool statistics
 func (wp *WorkerPool) GetWorkerStats() map[string]int {
 	wp.mutex.RLock()
@@ -159,12 +413,14 @@ func (wp *WorkerPool) RemoveWorker(nodeID string) error {
 	wp.mutex.Lock()
 	defer wp.mutex.Unlock()
 
-	if _, exists := wp.workers[nodeID]; !exists {
+	worker, exists := wp.workers[nodeID]
+	if !exists {
 		return fmt.Errorf("worker %s not found", nodeID)
 	}
 
 	delete(wp.workers, nodeID)
 	wp.logger.Infof("❌ Worker removed from pool: %s", nodeID)
+	wp.stream.PublishWorker(streamNode+".left", worker, nil)
 	return nil
 }
 
@@ -178,8 +434,110 @@ func (wp *WorkerPool) CheckHeartbeats() {
 
 	for nodeID, worker := range wp.workers {
 		if now.Sub(worker.LastHeartbeat) > timeout && worker.Status != "offline" {
+			oldStatus := worker.Status
 			worker.Status = "offline"
 			wp.logger.Warnf("💀 Worker %s marked offline (no heartbeat)", nodeID)
+			wp.publishStatus(worker, oldStatus)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// PoolDrift is one chain-authored field that differed locally when a worker
+// was synced from its on-chain record. Seal tokens are never copied into it.
+type PoolDrift struct {
+	Field string
+	Local string
+	Chain string
+}
+
+// SyncFromChain upserts a worker from its on-chain record and returns the
+// chain-authored fields that differed locally ("missing" for a new worker).
+// Locally observed state (join token, topology, heartbeats) is preserved, and
+// a local "offline" from missed heartbeats is kept while the chain says active.
+func (wp *WorkerPool) SyncFromChain(chain *WorkerNode) []PoolDrift {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[chain.NodeID]
+	if !exists {
+		if chain.LastHeartbeat.IsZero() {
+			chain.LastHeartbeat = time.Now()
+		}
+		wp.workers[chain.NodeID] = chain
+		wp.logger.Infof("👥 Worker restored from chain: %s (status: %s)", chain.NodeID, chain.Status)
+		wp.stream.PublishWorker(streamNode+".joined", chain, map[string]interface{}{"source": "chain"})
+		return []PoolDrift{{Field: "missing", Chain: chain.Status}}
+	}
+
+	var diverged []PoolDrift
+	if worker.SealToken != chain.SealToken {
+		diverged = append(diverged, PoolDrift{Field: "seal_token"})
+		worker.SealToken = chain.SealToken
+	}
+	if worker.StakeAmount != chain.StakeAmount {
+		diverged = append(diverged, PoolDrift{Field: "stake_amount",
+			Local: strconv.FormatUint(worker.StakeAmount, 10), Chain: strconv.FormatUint(chain.StakeAmount, 10)})
+		worker.StakeAmount = chain.StakeAmount
+		worker.LabelsSynced = false
+	}
+	if worker.WorkerAddress != chain.WorkerAddress {
+		diverged = append(diverged, PoolDrift{Field: "owner", Local: worker.WorkerAddress, Chain: chain.WorkerAddress})
+		worker.WorkerAddress = chain.WorkerAddress
+	}
+	if chain.Role != "" && worker.Role != chain.Role {
+		diverged = append(diverged, PoolDrift{Field: "role", Local: worker.Role, Chain: chain.Role})
+		worker.Role = chain.Role
+		worker.LabelsSynced = false
+	}
+	if worker.Status != chain.Status && !(worker.Status == "offline" && chain.Status == "active") {
+		diverged = append(diverged, PoolDrift{Field: "status", Local: worker.Status, Chain: chain.Status})
+		oldStatus := worker.Status
+		worker.Status = chain.Status
+		wp.publishStatus(worker, oldStatus)
+	}
+	return diverged
+}
+
+// MarkLabelsStale makes the next heartbeat of the worker re-apply its node
+// labels. It reports whether the worker exists.
+func (wp *WorkerPool) MarkLabelsStale(nodeID string) bool {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	worker, exists := wp.workers[nodeID]
+	if exists {
+		worker.LabelsSynced = false
+	}
+	return exists
+}
+
+// PruneMissing removes workers that are not in the given on-chain set and
+// returns their IDs.
+func (wp *WorkerPool) PruneMissing(onChain map[string]bool) []string {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	var removed []string
+	for nodeID, worker := range wp.workers {
+		if !onChain[nodeID] {
+			delete(wp.workers, nodeID)
+			removed = append(removed, nodeID)
+			wp.logger.Warnf("❌ Worker %s not found on chain, removed from pool", nodeID)
+			wp.stream.PublishWorker(streamNode+".left", worker, map[string]interface{}{"source": "chain"})
+		}
+	}
+	return removed
+}
+
+// publishStatus streams a status transition; moving into "slashed" is
+// reported as a slashing event. Called with wp.mutex held.
+func (wp *WorkerPool) publishStatus(worker *WorkerNode, oldStatus string) {
+	if oldStatus == worker.Status {
+		return
+	}
+	eventType := streamNode + ".status"
+	if worker.Status == "slashed" {
+		eventType = streamSlashing + ".status"
+	}
+	wp.stream.PublishWorker(eventType, worker, map[string]interface{}{"old_status": oldStatus})
+}
@@ -0,0 +1,288 @@
+// Package apirequest is the single definition of a K8s API request as it
+// travels through K3s-DaaS: the gateway submits it with
+// k8s_scheduler::submit_k8s_request, the contract emits it as
+// K8sAPIRequestScheduledEvent and the master executes it.
+//
+// Request mirrors the Move event field for field (names and types), Fields
+// lists the on-chain schema, Decode converts an event's parsedJson into a
+// Request and Validate applies the same checks the contract asserts, so a
+// request the gateway accepts is one the contract and the master accept too.
+package apirequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Move schema of K8sAPIRequestScheduledEvent.
+const (
+	Module    = "k8s_scheduler"
+	EventType = "K8sAPIRequestScheduledEvent"
+
+	// SubmitFunction and DelegatedSubmitFunction take MoveArgs after the
+	// scheduler and registry objects (the delegated form also takes the owner
+	// address first).
+	SubmitFunction          = "submit_k8s_request"
+	DelegatedSubmitFunction = "submit_delegated_k8s_request"
+
+	// MinPriority and MaxPriority bound Priority (asserted by the contract).
+	MinPriority = 1
+	MaxPriority = 10
+	// MinSealTokenLength is the shortest seal token the contract accepts.
+	MinSealTokenLength = 32
+)
+
+// Request is a K8s API request scheduled on chain. JSON field names are the
+// Move field names; u64 values are encoded as numbers here but arrive as
+// strings in event parsedJson, which Decode handles.
+type Request struct {
+	RequestID      string `json:"request_id"`
+	Method         string `json:"method"`    // GET, POST, PUT, DELETE, PATCH
+	Resource       string `json:"resource"`  // plural resource, e.g. pods
+	Namespace      string `json:"namespace"` // empty for cluster-scoped resources
+	Name           string `json:"name"`      // empty for list/create
+	Payload        string `json:"payload"`   // JSON object for POST/PUT/PATCH
+	SealToken      string `json:"seal_token"`
+	Requester      string `json:"requester"`       // wallet that submitted (or was delegated) the request
+	Priority       uint8  `json:"priority"`        // MinPriority..MaxPriority
+	AssignedWorker string `json:"assigned_worker"` // set by the contract when scheduling
+	Timestamp      uint64 `json:"timestamp"`       // Unix ms when scheduled
+	DeadlineMs     uint64 `json:"deadline_ms"`     // Unix ms the client gives up, 0 for none
+}
+
+// Field describes one field of the on-chain schema.
+type Field struct {
+	Name     string
+	MoveType string
+	// Required fields must be present in every event; the contract always
+	// emits them, so a missing one means a foreign or truncated event.
+	Required bool
+}
+
+// Fields is the on-chain schema in declaration order.
+var Fields = []Field{
+	{Name: "request_id", MoveType: "String", Required: true},
+	{Name: "method", MoveType: "String", Required: true},
+	{Name: "resource", MoveType: "String", Required: true},
+	{Name: "namespace", MoveType: "String", Required: true},
+	{Name: "name", MoveType: "String"},
+	{Name: "payload", MoveType: "String"},
+	{Name: "seal_token", MoveType: "String"},
+	{Name: "requester", MoveType: "address"},
+	{Name: "priority", MoveType: "u8"},
+	{Name: "assigned_worker", MoveType: "String", Required: true},
+	{Name: "timestamp", MoveType: "u64"},
+	{Name: "deadline_ms", MoveType: "u64"},
+}
+
+// legacyFields maps field names used by older gateway and listener builds
+// to the canonical name.
+var legacyFields = map[string]string{
+	"resource_type": "resource",
+}
+
+var methods = map[string]bool{"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true}
+
+var resources = map[string]bool{
+	"pods": true, "services": true, "deployments": true, "statefulsets": true,
+	"persistentvolumeclaims": true, "configmaps": true, "secrets": true,
+	"namespaces": true, "nodes": true,
+}
+
+// Methods returns the HTTP methods the contract accepts.
+func Methods() []string { return sortedKeys(methods) }
+
+// Resources returns the resources the contract accepts.
+func Resources() []string { return sortedKeys(resources) }
+
+// SupportsResource reports whether the contract accepts requests for resource.
+func SupportsResource(resource string) bool { return resources[resource] }
+
+// Decode converts a K8sAPIRequestScheduledEvent parsedJson object into a
+// Request. String fields must be strings, u8/u64 fields may be JSON numbers
+// or decimal strings, and payload may also be a vector<u8> (array of bytes)
+// as emitted by older contract builds.
+func Decode(data map[string]interface{}) (*Request, error) {
+	if data == nil {
+		return nil, fmt.Errorf("missing event data")
+	}
+	normalized := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if canonical, ok := legacyFields[key]; ok {
+			if _, exists := data[canonical]; exists {
+				continue
+			}
+			key = canonical
+		}
+		normalized[key] = value
+	}
+
+	request := &Request{}
+	for _, field := range Fields {
+		value, exists := normalized[field.Name]
+		if !exists || value == nil {
+			if field.Required {
+				return nil, fmt.Errorf("missing field %s", field.Name)
+			}
+			continue
+		}
+		if err := request.set(field, value); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// DecodeJSON decodes a JSON-encoded event payload (see Decode).
+func DecodeJSON(data []byte) (*Request, error) {
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return Decode(fields)
+}
+
+func (r *Request) set(field Field, value interface{}) error {
+	switch field.MoveType {
+	case "u8", "u64":
+		limit := uint64(math.MaxUint64)
+		if field.MoveType == "u8" {
+			limit = math.MaxUint8
+		}
+		number, ok := unsigned(value, limit)
+		if !ok {
+			return fmt.Errorf("field %s has invalid %s value %v", field.Name, field.MoveType, value)
+		}
+		switch field.Name {
+		case "priority":
+			r.Priority = uint8(number)
+		case "timestamp":
+			r.Timestamp = number
+		case "deadline_ms":
+			r.DeadlineMs = number
+		}
+		return nil
+	}
+
+	text, ok := value.(string)
+	if !ok && field.Name == "payload" {
+		text, ok = byteVector(value)
+	}
+	if !ok {
+		return fmt.Errorf("field %s has unexpected type %T", field.Name, value)
+	}
+	switch field.Name {
+	case "request_id":
+		r.RequestID = text
+	case "method":
+		r.Method = text
+	case "resource":
+		r.Resource = text
+	case "namespace":
+		r.Namespace = text
+	case "name":
+		r.Name = text
+	case "payload":
+		r.Payload = text
+	case "seal_token":
+		r.SealToken = text
+	case "requester":
+		r.Requester = text
+	case "assigned_worker":
+		r.AssignedWorker = text
+	}
+	return nil
+}
+
+// Validate applies the contract's submission checks. Events decoded from
+// chain already passed them; the gateway calls Validate before submitting so
+// invalid requests fail fast instead of aborting on chain.
+func (r *Request) Validate() error {
+	switch {
+	case r.RequestID == "":
+		return fmt.Errorf("request_id is required")
+	case !methods[r.Method]:
+		return fmt.Errorf("method %q is not one of %s", r.Method, strings.Join(Methods(), ", "))
+	case !resources[r.Resource]:
+		return fmt.Errorf("resource %q is not supported (supported: %s)", r.Resource, strings.Join(Resources(), ", "))
+	case r.Priority < MinPriority || r.Priority > MaxPriority:
+		return fmt.Errorf("priority %d is outside %d-%d", r.Priority, MinPriority, MaxPriority)
+	case len(r.SealToken) < MinSealTokenLength:
+		return fmt.Errorf("seal_token must be at least %d characters", MinSealTokenLength)
+	}
+	if r.Payload != "" && !json.Valid([]byte(r.Payload)) {
+		return fmt.Errorf("payload is not valid JSON")
+	}
+	return nil
+}
+
+// MoveArgs returns the pure arguments of SubmitFunction in declaration
+// order (request_id, method, resource, namespace, name, payload, seal_token,
+// priority, deadline_ms). u64 values are decimal strings as the Sui JSON-RPC
+// expects.
+func (r *Request) MoveArgs() []interface{} {
+	return []interface{}{
+		r.RequestID, r.Method, r.Resource, r.Namespace, r.Name, r.Payload, r.SealToken,
+		r.Priority, strconv.FormatUint(r.DeadlineMs, 10),
+	}
+}
+
+// unsigned accepts JSON numbers (float64 or json.Number) and decimal strings.
+func unsigned(value interface{}, limit uint64) (uint64, bool) {
+	var number uint64
+	switch v := value.(type) {
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case json.Number:
+		parsed, err := strconv.ParseUint(v.String(), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v > float64(limit) {
+			return 0, false
+		}
+		number = uint64(v)
+	default:
+		return 0, false
+	}
+	return number, number <= limit
+}
+
+// byteVector converts a Move vector<u8> rendered as a JSON array.
+func byteVector(value interface{}) (string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return "", false
+	}
+	bytes := make([]byte, len(items))
+	for i, item := range items {
+		b, ok := unsigned(item, math.MaxUint8)
+		if !ok {
+			return "", false
+		}
+		bytes[i] = byte(b)
+	}
+	return string(bytes), true
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package apirequest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Payload encryption keeps manifests off the public chain: the gateway seals
+// the payload to the executing master's X25519 key (advertised in its signed
+// attestation document) and submits an EncryptedPayload in place of the
+// manifest. The envelope is itself a JSON object, so the contract schema and
+// Validate are unchanged.
+//
+// The symmetric key is sha256(PayloadKDFLabel || shared || epk || recipient)
+// and the AAD binds the ciphertext to request_id, method, resource,
+// namespace and name, so a ciphertext copied from chain into another request
+// does not decrypt.
+const (
+	PayloadEncryptionVersion = 1
+	PayloadAlgorithm         = "X25519-SHA256-AES256GCM"
+	PayloadKDFLabel          = "k3s-daas request payload v1"
+)
+
+// EncryptedPayload is the on-chain form of an encrypted payload.
+type EncryptedPayload struct {
+	Version   int    `json:"daas_encrypted"`
+	KeyID     string `json:"kid"`
+	Ephemeral []byte `json:"epk"`
+	Nonce     []byte `json:"nonce"`
+	Sealed    []byte `json:"ct"`
+	// Digest is the hex SHA-256 of the plaintext, letting the requester
+	// prove which manifest was submitted; the master checks it after
+	// decrypting.
+	Digest string `json:"sha256"`
+}
+
+// KeyAgreement is the recipient side of the key exchange
+// (*ecdh.PrivateKey implements it).
+type KeyAgreement interface {
+	PublicKey() *ecdh.PublicKey
+	ECDH(remote *ecdh.PublicKey) ([]byte, error)
+}
+
+// PayloadKeyID identifies a recipient key (first 8 bytes of its SHA-256).
+func PayloadKeyID(recipient *ecdh.PublicKey) string {
+	digest := sha256.Sum256(recipient.Bytes())
+	return hex.EncodeToString(digest[:8])
+}
+
+// PayloadEncrypted reports whether Payload is an EncryptedPayload envelope.
+func (r *Request) PayloadEncrypted() bool {
+	_, ok := r.envelope()
+	return ok
+}
+
+// EncryptPayload replaces Payload with an envelope sealed to recipient.
+// An empty payload is left as is.
+func (r *Request) EncryptPayload(recipient *ecdh.PublicKey) error {
+	if r.Payload == "" {
+		return nil
+	}
+	if r.PayloadEncrypted() {
+		return fmt.Errorf("payload is already encrypted")
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return err
+	}
+	aead, err := payloadAEAD(shared, ephemeral.PublicKey().Bytes(), recipient.Bytes())
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(r.Payload))
+	envelope, err := json.Marshal(EncryptedPayload{
+		Version:   PayloadEncryptionVersion,
+		KeyID:     PayloadKeyID(recipient),
+		Ephemeral: ephemeral.PublicKey().Bytes(),
+		Nonce:     nonce,
+		Sealed:    aead.Seal(nil, nonce, []byte(r.Payload), r.payloadAAD()),
+		Digest:    hex.EncodeToString(digest[:]),
+	})
+	if err != nil {
+		return err
+	}
+	r.Payload = string(envelope)
+	return nil
+}
+
+// DecryptPayload replaces an encrypted Payload with the plaintext after
+// checking the key ID, the AEAD tag and the plaintext digest. A plaintext
+// payload is left as is.
+func (r *Request) DecryptPayload(key KeyAgreement) error {
+	envelope, ok := r.envelope()
+	if !ok {
+		return nil
+	}
+	if envelope.Version != PayloadEncryptionVersion {
+		return fmt.Errorf("unsupported payload encryption version %d", envelope.Version)
+	}
+	if keyID := PayloadKeyID(key.PublicKey()); envelope.KeyID != keyID {
+		return fmt.Errorf("payload is encrypted to key %s, this master has %s", envelope.KeyID, keyID)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope.Ephemeral)
+	if err != nil {
+		return fmt.Errorf("invalid ephemeral key: %v", err)
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return err
+	}
+	aead, err := payloadAEAD(shared, envelope.Ephemeral, key.PublicKey().Bytes())
+	if err != nil {
+		return err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return fmt.Errorf("invalid nonce length %d", len(envelope.Nonce))
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Sealed, r.payloadAAD())
+	if err != nil {
+		return fmt.Errorf("payload does not decrypt for this request")
+	}
+	digest := sha256.Sum256(plaintext)
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(digest[:])), []byte(strings.ToLower(envelope.Digest))) != 1 {
+		return fmt.Errorf("decrypted payload does not match its sha256")
+	}
+	if !json.Valid(plaintext) {
+		return fmt.Errorf("decrypted payload is not valid JSON")
+	}
+	r.Payload = string(plaintext)
+	return nil
+}
+
+// envelope parses Payload as an EncryptedPayload (false for plaintext).
+func (r *Request) envelope() (*EncryptedPayload, bool) {
+	if !strings.Contains(r.Payload, `"daas_encrypted"`) {
+		return nil, false
+	}
+	var envelope EncryptedPayload
+	if err := json.Unmarshal([]byte(r.Payload), &envelope); err != nil || envelope.Version == 0 {
+		return nil, false
+	}
+	return &envelope, true
+}
+
+func (r *Request) payloadAAD() []byte {
+	return []byte(strings.Join([]string{r.RequestID, r.Method, r.Resource, r.Namespace, r.Name}, "\n"))
+}
+
+func payloadAEAD(shared, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	material := append([]byte(PayloadKDFLabel), shared...)
+	material = append(material, ephemeral...)
+	material = append(material, recipient...)
+	key := sha256.Sum256(material)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,228 @@
+// Package chain defines the blockchain backend used by K3s-DaaS components.
+//
+// The master, the staker host and the API proxy talk to the chain only
+// through Backend: submitting contract calls, reading objects, following
+// contract events and checking stakes. Sui is the production backend
+// (registered by github.com/k3s-io/daas-sui); other chains plug in by calling
+// Register from their package. The in-memory mock ("mock") runs the whole
+// stack offline: the master hosts it with MockServer and the other
+// components reach it through MockClient.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when an object does not exist (or the node
+	// serving the backend has not seen it yet)
+	ErrNotFound = errors.New("object not found")
+	// ErrDeleted is returned for objects that existed but were deleted or
+	// wrapped, e.g. a withdrawn or confiscated stake
+	ErrDeleted = errors.New("object deleted")
+	// ErrInsufficientStake is returned by ValidateStake together with the
+	// stake it found when the amount or status does not qualify
+	ErrInsufficientStake = errors.New("insufficient stake")
+	// ErrReadOnly is returned by SubmitTx when the backend has no signer
+	ErrReadOnly = errors.New("backend is read-only")
+)
+
+// Backend is a blockchain the DaaS contracts are deployed on
+type Backend interface {
+	// Name is the registered backend name, e.g. "sui" or "mock"
+	Name() string
+	// SubmitTx signs and executes the calls atomically
+	SubmitTx(ctx context.Context, tx *Tx) (*TxResult, error)
+	// QueryObject reads an object by ID without caching
+	QueryObject(ctx context.Context, objectID string) (*Object, error)
+	// SubscribeEvents delivers contract events in order until ctx is done
+	SubscribeEvents(ctx context.Context, filter EventFilter) (<-chan EventBatch, error)
+	// ValidateStake looks up a node's stake and checks it against minStake
+	ValidateStake(ctx context.Context, ref StakeRef, minStake uint64) (*Stake, error)
+}
+
+// Call is one contract function call
+type Call struct {
+	Module   string        `json:"module"`
+	Function string        `json:"function"`
+	Args     []interface{} `json:"args"`
+}
+
+// ResultRef refers to the output of an earlier call in the same transaction
+type ResultRef struct {
+	Index int `json:"result"`
+}
+
+// Result returns a reference to the output of the i-th call of a transaction
+func Result(i int) ResultRef {
+	return ResultRef{Index: i}
+}
+
+// Tx is a batch of calls that succeed or fail together
+type Tx struct {
+	Calls     []Call `json:"calls"`
+	GasBudget uint64 `json:"gas_budget,omitempty"` // 0 uses the backend default
+}
+
+// NewTx creates a transaction with a single call
+func NewTx(module, function string, args ...interface{}) *Tx {
+	return (&Tx{}).Add(module, function, args...)
+}
+
+// Add appends a call and returns the transaction for chaining
+func (t *Tx) Add(module, function string, args ...interface{}) *Tx {
+	if args == nil {
+		args = []interface{}{}
+	}
+	t.Calls = append(t.Calls, Call{Module: module, Function: function, Args: args})
+	return t
+}
+
+// TxResult describes an executed transaction
+type TxResult struct {
+	Digest  string   `json:"digest"`
+	Created []Object `json:"created,omitempty"` // only ID and Type are set
+	Events  []Event  `json:"events,omitempty"`
+}
+
+// CreatedOfType returns the ID of the first created object whose type
+// contains typeName, or "" if there is none
+func (r *TxResult) CreatedOfType(typeName string) string {
+	for _, object := range r.Created {
+		if strings.Contains(object.Type, typeName) {
+			return object.ID
+		}
+	}
+	return ""
+}
+
+// Object is an on-chain object
+type Object struct {
+	ID      string                 `json:"id"`
+	Type    string                 `json:"type"`
+	Version uint64                 `json:"version"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Event is a contract event
+type Event struct {
+	TxDigest    string                 `json:"tx_digest"`
+	Seq         string                 `json:"seq"` // position within the transaction
+	Package     string                 `json:"package"`
+	Module      string                 `json:"module"`
+	Type        string                 `json:"type"` // fully qualified, e.g. <package>::<module>::<Name>
+	Sender      string                 `json:"sender"`
+	Data        map[string]interface{} `json:"data"`
+	TimestampMs int64                  `json:"timestamp_ms"`
+}
+
+// EventID is the position of one event, used to resume a subscription
+type EventID struct {
+	TxDigest string `json:"tx_digest"`
+	Seq      string `json:"seq"`
+}
+
+// EventFilter selects the events a subscription delivers
+type EventFilter struct {
+	Package      string        // contract package/address; empty means the backend's own
+	Module       string        // empty means every module of the package
+	PollInterval time.Duration // for polling backends; 0 uses the backend default
+	// After resumes right after this event instead of at the chain's current
+	// end. Backends that keep no history (the mocks) ignore it.
+	After *EventID
+}
+
+// EventBatch is what a subscription delivers after every poll or push, even
+// when no events arrived, so consumers can tell a quiet chain from a stalled
+// subscription. Height is how far the backend had read when the batch was
+// taken (checkpoint, block number); 0 if the backend does not know. A batch
+// can carry both events and Err when a poll failed part way through.
+type EventBatch struct {
+	Events []Event
+	Height uint64
+	Err    error
+}
+
+// StakeRef identifies a stake either by its object or by the node it backs
+type StakeRef struct {
+	NodeID   string `json:"node_id"`
+	ObjectID string `json:"object_id,omitempty"` // preferred when known
+}
+
+// Stake is a node's stake as seen on chain
+type Stake struct {
+	NodeID   string `json:"node_id"`
+	ObjectID string `json:"object_id,omitempty"`
+	Amount   uint64 `json:"amount"`
+	Status   string `json:"status"` // active, slashed, withdrawn
+	Version  uint64 `json:"version,omitempty"`
+}
+
+// CheckStake applies the common ValidateStake rule: the stake must be active
+// and at least minStake. Backends call it after reading the stake.
+func CheckStake(stake *Stake, minStake uint64) (*Stake, error) {
+	if stake.Status != "active" {
+		return stake, fmt.Errorf("%w: stake is %s", ErrInsufficientStake, stake.Status)
+	}
+	if stake.Amount < minStake {
+		return stake, fmt.Errorf("%w: has %d, requires %d", ErrInsufficientStake, stake.Amount, minStake)
+	}
+	return stake, nil
+}
+
+// Config is what a backend factory receives
+type Config struct {
+	Endpoint   string            // RPC endpoint
+	Package    string            // contract package/address
+	Sender     string            // signing address; empty for read-only use
+	PrivateKey string            // signing key; empty for read-only use
+	Options    map[string]string // backend specific settings
+}
+
+// Factory creates a backend from its configuration
+type Factory func(config Config) (Backend, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a backend available to Open. It panics if the name is
+// already taken, like database/sql drivers.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, exists := factories[name]; exists {
+		panic("chain: backend registered twice: " + name)
+	}
+	factories[name] = factory
+}
+
+// Open creates the named backend
+func Open(name string, config Config) (Backend, error) {
+	factoriesMu.RLock()
+	factory, exists := factories[name]
+	factoriesMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown chain backend %q (available: %v)", name, Backends())
+	}
+	return factory(config)
+}
+
+// Backends lists the registered backend names
+func Backends() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,354 @@
+package chain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MockBackendName is the registered name of the in-memory backend
+const MockBackendName = "mock"
+
+// mockSender signs transactions on a mock chain opened without a sender
+const mockSender = "0x000000000000000000000000000000000000000000000000000000000000dead"
+
+func init() {
+	Register(MockBackendName, func(config Config) (Backend, error) {
+		sender := config.Sender
+		if sender == "" {
+			sender = mockSender
+		}
+		// With an endpoint the chain lives in another process (see MockServer)
+		if config.Endpoint != "" {
+			return NewMockClient(config.Endpoint, sender), nil
+		}
+
+		mock := NewMockBackend(config.Package)
+		mock.sender = sender
+		if value := config.Options["default_stake"]; value != "" {
+			stake, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default_stake %q: %v", value, err)
+			}
+			mock.DefaultStake = stake
+		}
+		if value := config.Options["min_stake"]; value != "" {
+			minStake, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid min_stake %q: %v", value, err)
+			}
+			mock.MinStake = minStake
+		}
+		return mock, nil
+	})
+}
+
+// MockBackend is an in-memory chain for offline development and on-prem
+// installs. Transactions are final as soon as SubmitTx returns and their
+// events reach subscribers immediately. Object IDs and digests are derived
+// from the package and block height, so replaying the same calls yields the
+// same IDs. The DaaS contract functions in mockContract are emulated
+// (objects, stakes, events); any other call emits a generic
+// <package>::<module>::<function> event. Nodes without a recorded stake get
+// DefaultStake when it is set, so a cluster can run without any staking.
+type MockBackend struct {
+	DefaultStake uint64
+	MinStake     uint64 // base of the role tiers enforced on partial withdrawals
+
+	pkg     string
+	sender  string
+	mu      sync.RWMutex
+	height  uint64
+	events  []Event
+	objects map[string]*Object
+	deleted map[string]bool
+	stakes  map[string]*Stake // by node ID
+	changed chan struct{}     // closed and replaced whenever events are appended
+}
+
+// NewMockBackend creates an empty in-memory chain for the given package name
+func NewMockBackend(pkg string) *MockBackend {
+	if pkg == "" {
+		pkg = "0x0"
+	}
+	return &MockBackend{
+		pkg:     pkg,
+		sender:  mockSender,
+		objects: make(map[string]*Object),
+		deleted: make(map[string]bool),
+		stakes:  make(map[string]*Stake),
+		changed: make(chan struct{}),
+	}
+}
+
+// Name implements Backend
+func (m *MockBackend) Name() string {
+	return MockBackendName
+}
+
+// SubmitTx executes the calls as the backend's own sender
+func (m *MockBackend) SubmitTx(ctx context.Context, tx *Tx) (*TxResult, error) {
+	return m.SubmitTxAs(m.sender, tx)
+}
+
+// SubmitTxAs executes the calls in a new block on behalf of sender. A failing
+// call aborts the whole transaction without effects, as on a real chain.
+func (m *MockBackend) SubmitTxAs(sender string, tx *Tx) (*TxResult, error) {
+	if tx == nil || len(tx.Calls) == 0 {
+		return nil, fmt.Errorf("transaction has no calls")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	height := m.height + 1
+	exec := &mockExec{
+		mock:    m,
+		sender:  sender,
+		digest:  m.mockID("tx", height, 0),
+		height:  height,
+		results: make([]*Object, len(tx.Calls)),
+		stakes:  make(map[string]*Stake),
+		deletes: make(map[string]bool),
+	}
+	for i, call := range tx.Calls {
+		exec.index = i
+		if err := exec.run(call); err != nil {
+			return nil, fmt.Errorf("%s::%s aborted: %v", call.Module, call.Function, err)
+		}
+	}
+
+	m.height = height
+	result := &TxResult{Digest: exec.digest}
+	for _, object := range exec.results {
+		if object != nil {
+			m.objects[object.ID] = object
+			result.Created = append(result.Created, Object{ID: object.ID, Type: object.Type})
+		}
+	}
+	for nodeID, stake := range exec.stakes {
+		m.stakes[nodeID] = stake
+		// Keep the stake record in step, as the registry does on chain
+		if record := m.objects[stake.ObjectID]; record != nil && record.Version < stake.Version {
+			record.Fields["stake_amount"] = strconv.FormatUint(stake.Amount, 10)
+			record.Version = stake.Version
+		}
+	}
+	for objectID := range exec.deletes {
+		delete(m.objects, objectID)
+		m.deleted[objectID] = true
+	}
+	m.appendEventsLocked(exec.events...)
+	result.Events = exec.events
+	return result, nil
+}
+
+// Publish appends a contract event in a new block, as if a transaction
+// emitted it (package, digest and timestamp are filled in when empty)
+func (m *MockBackend) Publish(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.height++
+	if event.Package == "" {
+		event.Package = m.pkg
+	}
+	if event.TxDigest == "" {
+		event.TxDigest = m.mockID("tx", m.height, 0)
+		event.Seq = "0"
+	}
+	if event.TimestampMs == 0 {
+		event.TimestampMs = time.Now().UnixMilli()
+	}
+	m.appendEventsLocked(event)
+}
+
+// PutObject stores or replaces an object and bumps its version
+func (m *MockBackend) PutObject(object Object) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing := m.objects[object.ID]; existing != nil && object.Version <= existing.Version {
+		object.Version = existing.Version + 1
+	}
+	if object.Version == 0 {
+		object.Version = 1
+	}
+	m.objects[object.ID] = &object
+	delete(m.deleted, object.ID)
+}
+
+// DeleteObject removes an object; later reads return ErrDeleted
+func (m *MockBackend) DeleteObject(objectID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, objectID)
+	m.deleted[objectID] = true
+}
+
+// SetStake records a node's stake
+func (m *MockBackend) SetStake(stake Stake) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stakes[stake.NodeID] = &stake
+}
+
+// Slash marks a node's stake and its stake object as slashed, so the staker
+// host goes through the same shutdown and appeal flow as on a real chain
+func (m *MockBackend) Slash(nodeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stake := m.stakes[nodeID]
+	if stake == nil {
+		return fmt.Errorf("%w: no stake for node %s", ErrNotFound, nodeID)
+	}
+	stake.Status = "slashed"
+	stake.Version++
+	if object := m.objects[stake.ObjectID]; object != nil {
+		object.Fields["status"] = "slashed"
+		object.Version++
+	}
+	return nil
+}
+
+// QueryObject implements Backend
+func (m *MockBackend) QueryObject(ctx context.Context, objectID string) (*Object, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.deleted[objectID] {
+		return nil, fmt.Errorf("%w: %s", ErrDeleted, objectID)
+	}
+	object := m.objects[objectID]
+	if object == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, objectID)
+	}
+	copied := *object
+	copied.Fields = make(map[string]interface{}, len(object.Fields))
+	for key, value := range object.Fields {
+		copied.Fields[key] = value
+	}
+	return &copied, nil
+}
+
+// SubscribeEvents delivers events appended after the subscription started
+// as soon as they are appended. An empty batch is also sent every
+// PollInterval (default 1s) so consumers can tell the subscription is alive.
+func (m *MockBackend) SubscribeEvents(ctx context.Context, filter EventFilter) (<-chan EventBatch, error) {
+	if filter.Package == "" {
+		filter.Package = m.pkg
+	}
+	interval := filter.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	m.mu.RLock()
+	next := len(m.events)
+	m.mu.RUnlock()
+
+	batches := make(chan EventBatch)
+	go func() {
+		defer close(batches)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			events, height, changed := m.eventsSince(next)
+			next += len(events)
+
+			batch := EventBatch{Height: height, Events: filterEvents(events, filter)}
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			case <-ticker.C:
+			}
+		}
+	}()
+	return batches, nil
+}
+
+// eventsSince returns the events after position next, the current height
+// and a channel that is closed when more events arrive
+func (m *MockBackend) eventsSince(next int) ([]Event, uint64, <-chan struct{}) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if next > len(m.events) {
+		next = len(m.events)
+	}
+	events := append([]Event(nil), m.events[next:]...)
+	return events, m.height, m.changed
+}
+
+// ValidateStake implements Backend
+func (m *MockBackend) ValidateStake(ctx context.Context, ref StakeRef, minStake uint64) (*Stake, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if ref.ObjectID != "" && m.deleted[ref.ObjectID] {
+		return nil, fmt.Errorf("%w: stake %s", ErrDeleted, ref.ObjectID)
+	}
+
+	recorded := m.stakes[ref.NodeID]
+	for _, candidate := range m.stakes {
+		if ref.ObjectID != "" && candidate.ObjectID == ref.ObjectID {
+			recorded = candidate
+			break
+		}
+	}
+	if recorded == nil {
+		if m.DefaultStake == 0 {
+			return nil, fmt.Errorf("%w: no stake for node %s", ErrNotFound, ref.NodeID)
+		}
+		return CheckStake(&Stake{NodeID: ref.NodeID, ObjectID: ref.ObjectID, Amount: m.DefaultStake, Status: "active"}, minStake)
+	}
+	stake := *recorded
+	return CheckStake(&stake, minStake)
+}
+
+// end returns the position after the last event and the current height
+func (m *MockBackend) end() (int, uint64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.events), m.height
+}
+
+func (m *MockBackend) appendEventsLocked(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+	m.events = append(m.events, events...)
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// mockID derives a Sui-style 32-byte hex ID, stable for the same package,
+// block height and position
+func (m *MockBackend) mockID(kind string, height uint64, index int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d/%d", m.pkg, kind, height, index)))
+	return "0x" + hex.EncodeToString(sum[:])
+}
+
+func filterEvents(events []Event, filter EventFilter) []Event {
+	var matched []Event
+	for _, event := range events {
+		if (filter.Package == "" || event.Package == filter.Package) && (filter.Module == "" || event.Module == filter.Module) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,367 @@
+package chain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mockCall emulates one contract function. It may create an object (which
+// later calls in the same transaction can pass on as a ResultRef) and emit
+// events through exec.
+type mockCall func(exec *mockExec, args []interface{}) (*Object, error)
+
+// mockContract holds the DaaS contract functions the mock emulates, keyed by
+// module::function. They follow the Move sources closely enough for the
+// staker host and the master to run their real flows against the mock.
+var mockContract = map[string]mockCall{
+	"staking::stake_for_node":               mockStakeForNode,
+	"k8s_gateway::create_worker_seal_token": mockCreateSealToken,
+	"staking::unstake":                      mockUnstake,
+	"staking::top_up_stake":                 mockTopUpStake,
+	"staking::withdraw_stake":               mockWithdrawStake,
+	"slash_appeals::submit_appeal":          mockSubmitAppeal,
+	"sealed_secrets::create_sealed_secret":  mockCreateSealedSecret,
+}
+
+// mockExec is the state of a transaction being executed. Effects are staged
+// here and only applied to the backend when every call succeeded.
+type mockExec struct {
+	mock    *MockBackend
+	sender  string
+	digest  string
+	height  uint64
+	index   int
+	results []*Object
+	stakes  map[string]*Stake
+	deletes map[string]bool
+	events  []Event
+}
+
+func (e *mockExec) run(call Call) error {
+	emulate, ok := mockContract[call.Module+"::"+call.Function]
+	if !ok {
+		e.emit(call.Module, call.Function, map[string]interface{}{"function": call.Function, "args": call.Args})
+		return nil
+	}
+	object, err := emulate(e, call.Args)
+	if err != nil {
+		return err
+	}
+	e.results[e.index] = object
+	return nil
+}
+
+// newObject creates an object of <package>::<typeName> owned by the sender
+func (e *mockExec) newObject(typeName string, fields map[string]interface{}) *Object {
+	fields["owner"] = e.sender
+	return &Object{
+		ID:      e.mock.mockID("object", e.height, e.index),
+		Type:    e.mock.pkg + "::" + typeName,
+		Version: 1,
+		Fields:  fields,
+	}
+}
+
+// emit appends an event of <package>::<module>::<name>
+func (e *mockExec) emit(module, name string, data map[string]interface{}) {
+	e.events = append(e.events, Event{
+		TxDigest:    e.digest,
+		Seq:         strconv.Itoa(len(e.events)),
+		Package:     e.mock.pkg,
+		Module:      module,
+		Type:        fmt.Sprintf("%s::%s::%s", e.mock.pkg, module, name),
+		Sender:      e.sender,
+		Data:        data,
+		TimestampMs: time.Now().UnixMilli(),
+	})
+}
+
+// result resolves a ResultRef argument to the object created by that call.
+// Refs decoded from JSON arrive as {"result": n}.
+func (e *mockExec) result(arg interface{}) (*Object, error) {
+	index := -1
+	switch ref := arg.(type) {
+	case ResultRef:
+		index = ref.Index
+	case map[string]interface{}:
+		if value, ok := ref["result"].(float64); ok {
+			index = int(value)
+		}
+	}
+	if index < 0 || index >= e.index || e.results[index] == nil {
+		return nil, fmt.Errorf("argument %v is not the result of an earlier call", arg)
+	}
+	return e.results[index], nil
+}
+
+// stakeOf returns the node's stake including changes staged in this transaction
+func (e *mockExec) stakeOf(nodeID string) *Stake {
+	if stake := e.stakes[nodeID]; stake != nil {
+		return stake
+	}
+	return e.mock.stakes[nodeID]
+}
+
+// stake_for_node(amount, node_id, role) → StakeRecord
+func mockStakeForNode(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected amount, node_id and role")
+	}
+	amount, ok := argU64(args[0])
+	if !ok || amount == 0 {
+		return nil, fmt.Errorf("invalid stake amount %v", args[0])
+	}
+	nodeID, _ := args[1].(string)
+	if nodeID == "" {
+		return nil, fmt.Errorf("empty node_id")
+	}
+	role := "worker"
+	if len(args) > 2 {
+		if value, _ := args[2].(string); value != "" {
+			role = value
+		}
+	}
+	if existing := exec.stakeOf(nodeID); existing != nil && existing.Status == "active" {
+		return nil, fmt.Errorf("node %s is already staked", nodeID)
+	}
+
+	record := exec.newObject("staking::StakeRecord", map[string]interface{}{
+		"node_id":      nodeID,
+		"stake_amount": strconv.FormatUint(amount, 10),
+		"role":         role,
+		"status":       "active",
+	})
+	exec.stakes[nodeID] = &Stake{NodeID: nodeID, ObjectID: record.ID, Amount: amount, Status: "active", Version: 1}
+	exec.emit("worker_registry", "StakeDepositedEvent", map[string]interface{}{
+		"node_id":   nodeID,
+		"owner":     exec.sender,
+		"amount":    strconv.FormatUint(amount, 10),
+		"timestamp": strconv.FormatInt(time.Now().UnixMilli(), 10),
+	})
+	return record, nil
+}
+
+// create_worker_seal_token(stake_record) → SealToken, registering the worker
+func mockCreateSealToken(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("expected the stake record")
+	}
+	record, err := exec.result(args[0])
+	if err != nil {
+		return nil, err
+	}
+	nodeID, _ := record.Fields["node_id"].(string)
+
+	token := exec.newObject("k8s_gateway::SealToken", map[string]interface{}{
+		"node_id":         nodeID,
+		"stake_object_id": record.ID,
+	})
+	exec.emit("worker_registry", "WorkerRegisteredEvent", map[string]interface{}{
+		"node_id":      nodeID,
+		"owner":        exec.sender,
+		"stake_amount": record.Fields["stake_amount"],
+		"role":         record.Fields["role"],
+		"seal_token":   token.ID,
+		"timestamp":    strconv.FormatInt(time.Now().UnixMilli(), 10),
+	})
+	return token, nil
+}
+
+// unstake(owner, node_id) withdraws the stake; the record object goes away
+func mockUnstake(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected owner and node_id")
+	}
+	nodeID, _ := args[1].(string)
+	stake := exec.stakeOf(nodeID)
+	if stake == nil || stake.Status == "withdrawn" {
+		return nil, fmt.Errorf("node %s has no stake", nodeID)
+	}
+
+	withdrawn := *stake
+	withdrawn.Status = "withdrawn"
+	withdrawn.Version++
+	exec.stakes[nodeID] = &withdrawn
+	exec.deletes[stake.ObjectID] = true
+	exec.emit("worker_registry", "WorkerStatusChangedEvent", map[string]interface{}{
+		"node_id":    nodeID,
+		"old_status": stake.Status,
+		"new_status": "offline",
+	})
+	return nil, nil
+}
+
+// mockTierMultipliers mirrors the role tiers of worker_registry.move
+var mockTierMultipliers = map[string]uint64{"worker": 1, "edge": 2, "storage": 10}
+
+// top_up_stake(node_id, amount, coins) adds to an active stake. On Sui the
+// coin object IDs are merged into the payment; the mock only counts amount.
+func mockTopUpStake(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected node_id, amount and coins")
+	}
+	nodeID, _ := args[0].(string)
+	amount, ok := argU64(args[1])
+	if !ok || amount == 0 {
+		return nil, fmt.Errorf("invalid top-up amount %v", args[1])
+	}
+	stake, err := exec.changeableStake(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := *stake
+	changed.Amount += amount
+	changed.Version++
+	exec.stakes[nodeID] = &changed
+	exec.emit("worker_registry", "StakeDepositedEvent", map[string]interface{}{
+		"node_id":   nodeID,
+		"owner":     exec.sender,
+		"amount":    strconv.FormatUint(amount, 10),
+		"timestamp": strconv.FormatInt(time.Now().UnixMilli(), 10),
+	})
+	exec.emitStakeChanged(stake, &changed)
+	return nil, nil
+}
+
+// withdraw_stake(node_id, amount) takes part of a stake back; what remains
+// must still cover the role's tier (withdrawing everything is unstake)
+func mockWithdrawStake(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected node_id and amount")
+	}
+	nodeID, _ := args[0].(string)
+	amount, ok := argU64(args[1])
+	if !ok || amount == 0 {
+		return nil, fmt.Errorf("invalid withdrawal amount %v", args[1])
+	}
+	stake, err := exec.changeableStake(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	required := exec.mock.MinStake * mockTierMultipliers[exec.roleOf(stake)]
+	if amount > stake.Amount || stake.Amount-amount < required {
+		return nil, fmt.Errorf("%w: withdrawing %d of %d would leave less than the %s tier minimum %d",
+			ErrInsufficientStake, amount, stake.Amount, exec.roleOf(stake), required)
+	}
+
+	changed := *stake
+	changed.Amount -= amount
+	changed.Version++
+	exec.stakes[nodeID] = &changed
+	exec.emitStakeChanged(stake, &changed)
+	return nil, nil
+}
+
+// changeableStake returns a stake that may be topped up or withdrawn from
+func (e *mockExec) changeableStake(nodeID string) (*Stake, error) {
+	stake := e.stakeOf(nodeID)
+	switch {
+	case stake == nil || stake.Status == "withdrawn":
+		return nil, fmt.Errorf("%w: node %s has no stake", ErrNotFound, nodeID)
+	case stake.Status == "slashed":
+		return nil, fmt.Errorf("stake of node %s is slashed", nodeID)
+	}
+	return stake, nil
+}
+
+// roleOf reads the role from the stake record, defaulting to worker
+func (e *mockExec) roleOf(stake *Stake) string {
+	if record := e.mock.objects[stake.ObjectID]; record != nil {
+		if role, _ := record.Fields["role"].(string); role != "" {
+			return role
+		}
+	}
+	return "worker"
+}
+
+func (e *mockExec) emitStakeChanged(before, after *Stake) {
+	e.emit("worker_registry", "StakeAmountChangedEvent", map[string]interface{}{
+		"node_id":    after.NodeID,
+		"owner":      e.sender,
+		"old_amount": strconv.FormatUint(before.Amount, 10),
+		"new_amount": strconv.FormatUint(after.Amount, 10),
+		"role":       e.roleOf(after),
+		"timestamp":  strconv.FormatInt(time.Now().UnixMilli(), 10),
+	})
+}
+
+// submit_appeal(node_id, stake_object_id, evidence_digest, summary) → SlashAppeal
+func mockSubmitAppeal(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 4 {
+		return nil, fmt.Errorf("expected node_id, stake_object_id, evidence_digest and summary")
+	}
+	fields := map[string]interface{}{"status": "pending"}
+	for i, name := range []string{"node_id", "stake_object_id", "evidence_digest", "summary"} {
+		fields[name], _ = args[i].(string)
+	}
+	if fields["node_id"] == "" {
+		return nil, fmt.Errorf("empty node_id")
+	}
+	if digest, _ := fields["evidence_digest"].(string); len(digest) != 71 || !strings.HasPrefix(digest, "sha256:") {
+		return nil, fmt.Errorf("evidence_digest must be sha256:<64 hex>")
+	}
+
+	appeal := exec.newObject("slash_appeals::SlashAppeal", fields)
+	exec.emit("slash_appeals", "AppealSubmittedEvent", map[string]interface{}{
+		"appeal_id":       appeal.ID,
+		"node_id":         fields["node_id"],
+		"appellant":       exec.sender,
+		"stake_object_id": fields["stake_object_id"],
+		"evidence_digest": fields["evidence_digest"],
+		"summary":         fields["summary"],
+		"timestamp":       strconv.FormatInt(time.Now().UnixMilli(), 10),
+	})
+	return appeal, nil
+}
+
+// create_sealed_secret(namespace, name, ciphertext, blob_url, blob_sha256, key_id) → SealedSecret
+func mockCreateSealedSecret(exec *mockExec, args []interface{}) (*Object, error) {
+	if len(args) < 6 {
+		return nil, fmt.Errorf("expected namespace, name, ciphertext, blob_url, blob_sha256 and key_id")
+	}
+	fields := map[string]interface{}{"updated_at": strconv.FormatInt(time.Now().UnixMilli(), 10)}
+	for i, name := range []string{"namespace", "name", "ciphertext", "blob_url", "blob_sha256", "key_id"} {
+		fields[name], _ = args[i].(string)
+	}
+	if fields["namespace"] == "" || fields["name"] == "" {
+		return nil, fmt.Errorf("empty namespace or name")
+	}
+	if fields["ciphertext"] == "" && fields["blob_url"] == "" {
+		return nil, fmt.Errorf("either ciphertext or blob_url is required")
+	}
+	if digest, _ := fields["blob_sha256"].(string); fields["blob_url"] != "" && (len(digest) != 71 || !strings.HasPrefix(digest, "sha256:")) {
+		return nil, fmt.Errorf("blob_sha256 must be sha256:<64 hex>")
+	}
+
+	secret := exec.newObject("sealed_secrets::SealedSecret", fields)
+	exec.emit("sealed_secrets", "SealedSecretUpdatedEvent", map[string]interface{}{
+		"secret_id": secret.ID,
+		"owner":     exec.sender,
+		"namespace": fields["namespace"],
+		"name":      fields["name"],
+		"key_id":    fields["key_id"],
+		"timestamp": fields["updated_at"],
+	})
+	return secret, nil
+}
+
+// argU64 reads an integer argument passed in-process or decoded from JSON
+func argU64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case int:
+		return uint64(v), v >= 0
+	case int64:
+		return uint64(v), v >= 0
+	case float64:
+		return uint64(v), v >= 0
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
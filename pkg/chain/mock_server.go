@@ -0,0 +1,334 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mockLongPoll bounds how long an events request waits for new events
+const mockLongPoll = 20 * time.Second
+
+// MockServer exposes a MockBackend over HTTP so every process of an offline
+// stack (master, staker hosts, API proxy) shares one mock chain. Mount it
+// under a prefix with http.StripPrefix; the routes are
+//
+//	POST /tx                   submit {"sender", "tx"}
+//	GET  /object?id=           read an object
+//	GET  /events?after=&wait=  long-poll events after a position
+//	GET  /stake?node_id=&object_id=&min=
+//	POST /stake                record a stake (admin)
+//	POST /slash?node_id=       slash a node's stake (admin)
+//
+// Admin routes require "Authorization: Bearer <AdminToken>" when AdminToken
+// is set.
+type MockServer struct {
+	AdminToken string
+
+	backend *MockBackend
+}
+
+// NewMockServer serves the given backend
+func NewMockServer(backend *MockBackend) *MockServer {
+	return &MockServer{backend: backend}
+}
+
+// Backend returns the served chain
+func (s *MockServer) Backend() *MockBackend {
+	return s.backend
+}
+
+// mockError carries sentinel errors across the wire
+type mockError struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"` // not_found, deleted, insufficient_stake
+	Stake *Stake `json:"stake,omitempty"`
+}
+
+// remoteError keeps the server's message and still matches its sentinel
+// with errors.Is
+type remoteError struct {
+	message  string
+	sentinel error
+}
+
+func (e *remoteError) Error() string { return e.message }
+func (e *remoteError) Unwrap() error { return e.sentinel }
+
+var mockErrorCodes = map[string]error{
+	"not_found":          ErrNotFound,
+	"deleted":            ErrDeleted,
+	"insufficient_stake": ErrInsufficientStake,
+}
+
+type mockEventsResponse struct {
+	Events []Event `json:"events"`
+	Next   int     `json:"next"`
+	Height uint64  `json:"height"`
+}
+
+type mockTxRequest struct {
+	Sender string `json:"sender"`
+	Tx     *Tx    `json:"tx"`
+}
+
+// ServeHTTP implements http.Handler
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := strings.Trim(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	switch {
+	case route == "tx" && r.Method == http.MethodPost:
+		var request mockTxRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Tx == nil {
+			writeMockError(w, http.StatusBadRequest, fmt.Errorf("invalid transaction: %v", err), nil)
+			return
+		}
+		sender := request.Sender
+		if sender == "" {
+			sender = s.backend.sender
+		}
+		result, err := s.backend.SubmitTxAs(sender, request.Tx)
+		if err != nil {
+			writeMockError(w, http.StatusUnprocessableEntity, err, nil)
+			return
+		}
+		writeMockJSON(w, result)
+
+	case route == "object" && r.Method == http.MethodGet:
+		object, err := s.backend.QueryObject(r.Context(), query.Get("id"))
+		if err != nil {
+			writeMockError(w, http.StatusNotFound, err, nil)
+			return
+		}
+		writeMockJSON(w, object)
+
+	case route == "events" && r.Method == http.MethodGet:
+		after, _ := strconv.Atoi(query.Get("after"))
+		if after < 0 {
+			// A negative position asks for the current end of the chain
+			next, height := s.backend.end()
+			writeMockJSON(w, mockEventsResponse{Next: next, Height: height})
+			return
+		}
+		wait, _ := time.ParseDuration(query.Get("wait") + "s")
+		if wait > mockLongPoll {
+			wait = mockLongPoll
+		}
+		events, height, changed := s.backend.eventsSince(after)
+		if len(events) == 0 && wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-changed:
+				events, height, _ = s.backend.eventsSince(after)
+			case <-timer.C:
+			case <-r.Context().Done():
+			}
+			timer.Stop()
+		}
+		writeMockJSON(w, mockEventsResponse{Events: events, Next: after + len(events), Height: height})
+
+	case route == "stake" && r.Method == http.MethodGet:
+		min, _ := strconv.ParseUint(query.Get("min"), 10, 64)
+		stake, err := s.backend.ValidateStake(r.Context(), StakeRef{NodeID: query.Get("node_id"), ObjectID: query.Get("object_id")}, min)
+		if err != nil {
+			writeMockError(w, http.StatusNotFound, err, stake)
+			return
+		}
+		writeMockJSON(w, stake)
+
+	case route == "stake" && r.Method == http.MethodPost:
+		if !s.authorized(r) {
+			writeMockError(w, http.StatusUnauthorized, errors.New("admin token required"), nil)
+			return
+		}
+		var stake Stake
+		if err := json.NewDecoder(r.Body).Decode(&stake); err != nil || stake.NodeID == "" {
+			writeMockError(w, http.StatusBadRequest, fmt.Errorf("invalid stake: %v", err), nil)
+			return
+		}
+		if stake.Status == "" {
+			stake.Status = "active"
+		}
+		s.backend.SetStake(stake)
+		writeMockJSON(w, stake)
+
+	case route == "slash" && r.Method == http.MethodPost:
+		if !s.authorized(r) {
+			writeMockError(w, http.StatusUnauthorized, errors.New("admin token required"), nil)
+			return
+		}
+		if err := s.backend.Slash(query.Get("node_id")); err != nil {
+			writeMockError(w, http.StatusNotFound, err, nil)
+			return
+		}
+		writeMockJSON(w, map[string]string{"node_id": query.Get("node_id"), "status": "slashed"})
+
+	default:
+		writeMockError(w, http.StatusNotFound, fmt.Errorf("no mock chain route %s %s", r.Method, r.URL.Path), nil)
+	}
+}
+
+func (s *MockServer) authorized(r *http.Request) bool {
+	if s.AdminToken == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.AdminToken)) == 1
+}
+
+func writeMockJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeMockError(w http.ResponseWriter, status int, err error, stake *Stake) {
+	body := mockError{Error: err.Error(), Stake: stake}
+	for code, sentinel := range mockErrorCodes {
+		if errors.Is(err, sentinel) {
+			body.Code = code
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// MockClient is a Backend for a mock chain served by MockServer in another
+// process. Transactions are signed as sender.
+type MockClient struct {
+	endpoint string
+	sender   string
+	http     *http.Client
+}
+
+// NewMockClient connects to a MockServer mounted at endpoint
+func NewMockClient(endpoint, sender string) *MockClient {
+	return &MockClient{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		sender:   sender,
+		http:     &http.Client{Timeout: mockLongPoll + 10*time.Second},
+	}
+}
+
+// Name implements Backend
+func (c *MockClient) Name() string {
+	return MockBackendName
+}
+
+// SubmitTx implements Backend
+func (c *MockClient) SubmitTx(ctx context.Context, tx *Tx) (*TxResult, error) {
+	body, err := json.Marshal(mockTxRequest{Sender: c.sender, Tx: tx})
+	if err != nil {
+		return nil, err
+	}
+	var result TxResult
+	if _, err := c.do(ctx, http.MethodPost, "/tx", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// QueryObject implements Backend
+func (c *MockClient) QueryObject(ctx context.Context, objectID string) (*Object, error) {
+	var object Object
+	if _, err := c.do(ctx, http.MethodGet, "/object?id="+url.QueryEscape(objectID), nil, &object); err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// ValidateStake implements Backend
+func (c *MockClient) ValidateStake(ctx context.Context, ref StakeRef, minStake uint64) (*Stake, error) {
+	path := fmt.Sprintf("/stake?node_id=%s&object_id=%s&min=%d", url.QueryEscape(ref.NodeID), url.QueryEscape(ref.ObjectID), minStake)
+	var stake Stake
+	failed, err := c.do(ctx, http.MethodGet, path, nil, &stake)
+	if err != nil {
+		if failed != nil && failed.Stake != nil {
+			return failed.Stake, err
+		}
+		return nil, err
+	}
+	return &stake, nil
+}
+
+// SubscribeEvents long-polls the server, so events arrive as soon as they are
+// appended. Like MockBackend it starts at the current end of the chain.
+func (c *MockClient) SubscribeEvents(ctx context.Context, filter EventFilter) (<-chan EventBatch, error) {
+	var start mockEventsResponse
+	if _, err := c.do(ctx, http.MethodGet, "/events?after=-1", nil, &start); err != nil {
+		return nil, err
+	}
+	retry := filter.PollInterval
+	if retry <= 0 {
+		retry = time.Second
+	}
+	wait := int(mockLongPoll / time.Second)
+
+	batches := make(chan EventBatch)
+	go func() {
+		defer close(batches)
+		next := start.Next
+		for {
+			var page mockEventsResponse
+			_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/events?after=%d&wait=%d", next, wait), nil, &page)
+			if ctx.Err() != nil {
+				return
+			}
+
+			batch := EventBatch{Err: err}
+			if err == nil {
+				next = page.Next
+				batch.Height = page.Height
+				batch.Events = filterEvents(page.Events, filter)
+			}
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				select {
+				case <-time.After(retry):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return batches, nil
+}
+
+// do performs a request and decodes the result into out; failures from the
+// server are returned with their sentinel error and decoded body
+func (c *MockClient) do(ctx context.Context, method, path string, body []byte, out interface{}) (*mockError, error) {
+	request, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("mock chain unreachable: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var failed mockError
+		if err := json.NewDecoder(response.Body).Decode(&failed); err != nil {
+			return nil, fmt.Errorf("mock chain returned %s", response.Status)
+		}
+		return &failed, &remoteError{message: failed.Error, sentinel: mockErrorCodes[failed.Code]}
+	}
+	return nil, json.NewDecoder(response.Body).Decode(out)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Authenticator attaches credentials to an outgoing request.
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// AuthFunc adapts a function to the Authenticator interface, e.g. to fetch a
+// fresh token from a secret store on every request.
+type AuthFunc func(req *http.Request) error
+
+// Apply calls f(req).
+func (f AuthFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// SealToken authenticates as the owner of a staked worker. It is required for
+// tenant usage, maintenance changes, token introspection and kubeconfig retrieval.
+type SealToken string
+
+// Apply sets the X-Seal-Token header.
+func (t SealToken) Apply(req *http.Request) error {
+	if t == "" {
+		return errors.New("empty seal token")
+	}
+	req.Header.Set("X-Seal-Token", string(t))
+	return nil
+}
+
+// BearerToken authenticates admin endpoints (NAUTILUS_ADMIN_TOKEN on the master).
+type BearerToken string
+
+// Apply sets the Authorization header.
+func (t BearerToken) Apply(req *http.Request) error {
+	if t == "" {
+		return errors.New("empty bearer token")
+	}
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// MultiAuth applies several authenticators in order, e.g. a seal token for
+// tenant APIs together with an admin bearer token.
+func MultiAuth(auths ...Authenticator) Authenticator {
+	return AuthFunc(func(req *http.Request) error {
+		for _, auth := range auths {
+			if err := auth.Apply(req); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SealTokenFromFile reads a seal token saved by the worker or written by an
+// operator. Surrounding whitespace is trimmed.
+func SealTokenFromFile(path string) (SealToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("seal token file is empty: " + path)
+	}
+	return SealToken(token), nil
+}
+
+// SealTokenFromEnv reads the seal token from DAAS_SEAL_TOKEN.
+func SealTokenFromEnv() (SealToken, error) {
+	token := strings.TrimSpace(os.Getenv("DAAS_SEAL_TOKEN"))
+	if token == "" {
+		return "", errors.New("DAAS_SEAL_TOKEN is not set")
+	}
+	return SealToken(token), nil
+}
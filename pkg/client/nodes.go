@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListWorkers returns the workers known to the master, sorted by node ID.
+func (c *Client) ListWorkers(ctx context.Context, filter WorkerFilter) ([]Worker, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Role != "" {
+		query.Set("role", filter.Role)
+	}
+	var workers []Worker
+	if err := c.getData(ctx, "/api/v1/workers", query, &workers); err != nil {
+		return nil, err
+	}
+	return workers, nil
+}
+
+// GetWorker returns a single worker. Unknown IDs yield an error for which IsNotFound is true.
+func (c *Client) GetWorker(ctx context.Context, nodeID string) (*Worker, error) {
+	if nodeID == "" {
+		return nil, fmt.Errorf("client: node ID is required")
+	}
+	var worker Worker
+	if err := c.getData(ctx, "/api/v1/workers/"+url.PathEscape(nodeID), nil, &worker); err != nil {
+		return nil, err
+	}
+	return &worker, nil
+}
+
+// StakingStatus returns the stake amount and tier the master holds for a worker.
+// The master updates it from on-chain stake events, so it follows top-ups and
+// withdrawals within one event poll.
+func (c *Client) StakingStatus(ctx context.Context, nodeID string) (*StakingStatus, error) {
+	worker, err := c.GetWorker(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &StakingStatus{
+		NodeID:      worker.NodeID,
+		Owner:       worker.WorkerAddress,
+		Role:        worker.Role,
+		Amount:      worker.StakeAmount,
+		Tier:        worker.StakeTier,
+		Status:      worker.Status,
+		LastUpdated: worker.LastHeartbeat,
+	}, nil
+}
+
+// NodeHealth returns health scores for all nodes the master has observed.
+func (c *Client) NodeHealth(ctx context.Context) ([]NodeHealth, error) {
+	var health []NodeHealth
+	if err := c.getData(ctx, "/api/v1/nodes/health", nil, &health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// NodeTimeline returns a node's heartbeat samples and events.
+func (c *Client) NodeTimeline(ctx context.Context, nodeID string, opts TimelineOptions) (*Timeline, error) {
+	if nodeID == "" {
+		return nil, fmt.Errorf("client: node ID is required")
+	}
+	query := url.Values{}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	var timeline Timeline
+	if err := c.getData(ctx, "/api/v1/nodes/"+url.PathEscape(nodeID)+"/timeline", query, &timeline); err != nil {
+		return nil, err
+	}
+	return &timeline, nil
+}
+
+// MaintenanceWindows lists scheduled, active and recently finished maintenance windows.
+func (c *Client) MaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error) {
+	body, err := c.do(ctx, http.MethodGet, "/api/v1/nodes/maintenance", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Windows []MaintenanceWindow `json:"windows"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("client: decoding maintenance windows: %w", err)
+	}
+	return response.Windows, nil
+}
+
+// ScheduleMaintenance schedules maintenance for a worker. Requires the worker's SealToken.
+func (c *Client) ScheduleMaintenance(ctx context.Context, request MaintenanceRequest) (*MaintenanceWindow, error) {
+	if request.NodeID == "" || request.Duration <= 0 {
+		return nil, fmt.Errorf("client: node ID and a positive duration are required")
+	}
+	payload := map[string]interface{}{
+		"node_id":          request.NodeID,
+		"duration_seconds": int64(request.Duration / time.Second),
+		"reason":           request.Reason,
+	}
+	if !request.Start.IsZero() {
+		payload["start"] = request.Start
+	}
+
+	body, err := c.do(ctx, http.MethodPost, "/api/v1/nodes/maintenance", nil, payload)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Window MaintenanceWindow `json:"window"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("client: decoding maintenance window: %w", err)
+	}
+	return &response.Window, nil
+}
+
+// CancelMaintenance cancels a worker's pending or active maintenance. Requires the worker's SealToken.
+func (c *Client) CancelMaintenance(ctx context.Context, nodeID string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/nodes/maintenance", url.Values{"node_id": {nodeID}}, nil)
+	return err
+}
+
+// Healthy reports whether the master's liveness endpoint answers 200.
+func (c *Client) Healthy(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/healthz", nil, nil)
+	return err
+}
+
+// Ready reports whether the master accepts kubectl traffic (chain synced, K3s up).
+func (c *Client) Ready(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/readyz", nil, nil)
+	return err
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// IntrospectToken reports which worker the client's SealToken belongs to.
+func (c *Client) IntrospectToken(ctx context.Context) (*TokenInfo, error) {
+	var info TokenInfo
+	if err := c.getData(ctx, "/api/v1/tokens/introspect", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Usage returns the API quota state of the wallet owning the client's SealToken.
+func (c *Client) Usage(ctx context.Context) (*TenantUsage, error) {
+	var usage TenantUsage
+	if err := c.getData(ctx, "/api/v1/tenants/usage", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// ChainHealth returns the chain health indicators sampled by the master.
+// It requires the ChainHealth feature gate on the master.
+func (c *Client) ChainHealth(ctx context.Context) (*ChainHealth, error) {
+	var health ChainHealth
+	if err := c.getData(ctx, "/api/v1/chain/health", nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Kubeconfig returns a kubeconfig (YAML) that points kubectl at the API
+// gateway and authenticates with the client's SealToken.
+func (c *Client) Kubeconfig(ctx context.Context) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, "/kubectl/config", nil, nil)
+}
+
+// JoinToken mints a single-use, short-lived K3s join token for the node the
+// client's SealToken is bound to. Each call revokes the node's previous token.
+func (c *Client) JoinToken(ctx context.Context) (string, error) {
+	body, err := c.do(ctx, http.MethodGet, "/api/v1/nodes/token", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		JoinToken string `json:"join_token"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("client: decoding join token: %w", err)
+	}
+	return response.JoinToken, nil
+}
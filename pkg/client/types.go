@@ -0,0 +1,168 @@
+package client
+
+import "time"
+
+// Worker is the public view of a worker node (seal and join tokens are never exposed).
+type Worker struct {
+	NodeID        string          `json:"node_id"`
+	Status        string          `json:"status"` // pending, active, busy, offline
+	Role          string          `json:"role,omitempty"`
+	StakeAmount   uint64          `json:"stake_amount"` // MIST
+	StakeTier     string          `json:"stake_tier"`
+	WorkerAddress string          `json:"worker_address"`
+	Region        string          `json:"region,omitempty"`
+	Zone          string          `json:"zone,omitempty"`
+	LatencyMs     int64           `json:"latency_ms,omitempty"`
+	RegisteredAt  time.Time       `json:"registered_at"`
+	LastHeartbeat time.Time       `json:"last_heartbeat"`
+	Conditions    []NodeCondition `json:"conditions,omitempty"`
+}
+
+// NodeCondition is a pressure condition reported by the worker (DiskPressure, MemoryPressure).
+type NodeCondition struct {
+	Type    string `json:"type"`
+	Status  bool   `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// WorkerFilter narrows ListWorkers. Empty fields match everything.
+type WorkerFilter struct {
+	Status string
+	Role   string
+}
+
+// StakingStatus summarises a worker's stake as seen by the master.
+type StakingStatus struct {
+	NodeID      string    `json:"node_id"`
+	Owner       string    `json:"owner"`
+	Role        string    `json:"role,omitempty"`
+	Amount      uint64    `json:"amount"` // MIST
+	Tier        string    `json:"tier"`
+	Status      string    `json:"status"`
+	LastUpdated time.Time `json:"last_updated"` // last heartbeat
+}
+
+// NodeHealth is the master's health score for a node.
+type NodeHealth struct {
+	NodeName        string    `json:"node_name"`
+	Score           float64   `json:"score"`
+	PodsObserved    int       `json:"pods_observed"`
+	PodsFailed      int       `json:"pods_failed"`
+	Restarts        int       `json:"restarts"`
+	AvgStartSeconds float64   `json:"avg_start_seconds"`
+	FailureRate     float64   `json:"failure_rate"`
+	Probation       bool      `json:"probation"`
+	ProbationSince  time.Time `json:"probation_since,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// HeartbeatSample is one heartbeat recorded by the master.
+type HeartbeatSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	RunningPods   int       `json:"running_pods"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	MemoryPercent float64   `json:"memory_percent"`
+	DiskPercent   float64   `json:"disk_percent"`
+	LatencyMs     int64     `json:"latency_ms"`
+	StakeStatus   string    `json:"stake_status,omitempty"`
+	StakeAmount   uint64    `json:"stake_amount,omitempty"`
+}
+
+// NodeEvent is a state change on a node (slashing, probation, stake, maintenance, ...).
+type NodeEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Detail    string    `json:"detail"`
+}
+
+// Timeline is a node's heartbeat history and events.
+type Timeline struct {
+	NodeID     string            `json:"node_id"`
+	Since      time.Time         `json:"since"`
+	Heartbeats []HeartbeatSample `json:"heartbeats"`
+	Events     []NodeEvent       `json:"events"`
+}
+
+// TimelineOptions bounds a timeline query. A zero Since means the last 24 hours;
+// a zero Limit returns every sample in range.
+type TimelineOptions struct {
+	Since time.Time
+	Limit int
+}
+
+// MaintenanceWindow is a scheduled maintenance period for a node.
+type MaintenanceWindow struct {
+	NodeID      string    `json:"node_id"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Reason      string    `json:"reason,omitempty"`
+	Source      string    `json:"source"` // chain, api
+	RequestedBy string    `json:"requested_by,omitempty"`
+	State       string    `json:"state"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// MaintenanceRequest schedules maintenance. A zero Start means now.
+type MaintenanceRequest struct {
+	NodeID   string
+	Start    time.Time
+	Duration time.Duration
+	Reason   string
+}
+
+// TenantUsage is the caller's API quota state.
+type TenantUsage struct {
+	Tenant    string    `json:"tenant"`
+	Stake     uint64    `json:"stake"`
+	QPS       float64   `json:"qps"`
+	Burst     float64   `json:"burst"`
+	Available float64   `json:"available"`
+	Allowed   uint64    `json:"allowed"`
+	Throttled uint64    `json:"throttled"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// ChainHealth is the master's view of the Sui chain: RPC round trip,
+// how far event polling trails the chain head, how long request events take
+// to reach the master, and the current reference gas price.
+type ChainHealth struct {
+	Healthy           bool      `json:"healthy"`
+	RPCLatencyMs      int64     `json:"rpc_latency_ms"`
+	LatestCheckpoint  uint64    `json:"latest_checkpoint"`
+	EventCursor       uint64    `json:"event_cursor"`
+	CheckpointLag     uint64    `json:"checkpoint_lag"`
+	EventDelayP50Ms   int64     `json:"event_delay_p50_ms"`
+	EventDelayP95Ms   int64     `json:"event_delay_p95_ms"`
+	EventDelaySamples int       `json:"event_delay_samples"`
+	ReferenceGasPrice uint64    `json:"reference_gas_price"`
+	Error             string    `json:"error,omitempty"`
+	SampledAt         time.Time `json:"sampled_at"`
+}
+
+// TokenInfo describes the worker a seal token belongs to. Unknown tokens
+// come back with Active=false rather than an error.
+type TokenInfo struct {
+	Active      bool        `json:"active"`
+	NodeID      string      `json:"node_id,omitempty"`
+	Owner       string      `json:"owner,omitempty"`
+	Role        string      `json:"role,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	StakeAmount uint64      `json:"stake_amount,omitempty"`
+	StakeTier   string      `json:"stake_tier,omitempty"`
+	Scope       *TokenScope `json:"scope,omitempty"`
+}
+
+// TokenScope limits a scoped token to namespaces, resources and verbs until
+// ExpiresAt. Empty lists are unrestricted. Source is "chain" for tokens
+// issued with worker_registry::issue_scoped_token and "exchange" for tokens
+// minted by the master's session exchange.
+type TokenScope struct {
+	NodeID     string    `json:"node_id"`
+	Owner      string    `json:"owner"`
+	Namespaces []string  `json:"namespaces,omitempty"`
+	Resources  []string  `json:"resources,omitempty"`
+	Verbs      []string  `json:"verbs,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IssuedAt   time.Time `json:"issued_at"`
+	Source     string    `json:"source"`
+}
@@ -0,0 +1,134 @@
+package escrow
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Shares travel sealed to an X25519 key: the guardian's key inside the escrow
+// bundle, and the replacement master's one-time recovery key on the way back.
+// The symmetric key is sha256(kdfLabel || shared || epk || recipient), and the
+// AAD names the root and the guardian, so a share cannot be replayed into
+// another root's recovery or submitted under another guardian's name.
+const (
+	Algorithm = "X25519-SHA256-AES256GCM"
+	kdfLabel  = "k3s-daas escrow share v1"
+)
+
+// Envelope is a share sealed to one recipient key.
+type Envelope struct {
+	KeyID     string `json:"kid"`
+	Ephemeral []byte `json:"epk"`
+	Nonce     []byte `json:"nonce"`
+	Sealed    []byte `json:"ct"`
+}
+
+// KeyAgreement is the recipient side of the key exchange
+// (*ecdh.PrivateKey implements it).
+type KeyAgreement interface {
+	PublicKey() *ecdh.PublicKey
+	ECDH(remote *ecdh.PublicKey) ([]byte, error)
+}
+
+// KeyID identifies a recipient key (first 8 bytes of its SHA-256).
+func KeyID(key *ecdh.PublicKey) string {
+	sum := sha256.Sum256(key.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParsePublicKey reads a base64 X25519 public key as written by
+// daasctl escrow keygen.
+func ParsePublicKey(value string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("escrow: public key is not base64: %v", err)
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: %v", err)
+	}
+	return key, nil
+}
+
+// ShareAAD binds a share in the escrow bundle to its root and guardian.
+func ShareAAD(rootID, guardian string) []byte {
+	return []byte("escrow/" + rootID + "/" + guardian)
+}
+
+// RecoveryAAD binds a share submitted for recovery to the root being
+// recovered and the guardian submitting it.
+func RecoveryAAD(rootID, guardian string) []byte {
+	return []byte("escrow-recovery/" + rootID + "/" + guardian)
+}
+
+// Seal encrypts plaintext to recipient with a fresh ephemeral key.
+func Seal(recipient *ecdh.PublicKey, plaintext, aad []byte) (*Envelope, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: %v", err)
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: %v", err)
+	}
+	aead, err := envelopeAEAD(shared, ephemeral.PublicKey().Bytes(), recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("escrow: %v", err)
+	}
+	return &Envelope{
+		KeyID:     KeyID(recipient),
+		Ephemeral: ephemeral.PublicKey().Bytes(),
+		Nonce:     nonce,
+		Sealed:    aead.Seal(nil, nonce, plaintext, aad),
+	}, nil
+}
+
+// Open decrypts an envelope sealed to key with the same AAD.
+func Open(key KeyAgreement, envelope *Envelope, aad []byte) ([]byte, error) {
+	if keyID := KeyID(key.PublicKey()); envelope.KeyID != keyID {
+		return nil, fmt.Errorf("escrow: envelope is sealed to key %s, not %s", envelope.KeyID, keyID)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope.Ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: invalid ephemeral key: %v", err)
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: %v", err)
+	}
+	aead, err := envelopeAEAD(shared, envelope.Ephemeral, key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("escrow: invalid nonce length %d", len(envelope.Nonce))
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Sealed, aad)
+	if err != nil {
+		return nil, fmt.Errorf("escrow: envelope does not decrypt for this root and guardian")
+	}
+	return plaintext, nil
+}
+
+func envelopeAEAD(shared, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	material := append([]byte(kdfLabel), shared...)
+	material = append(material, ephemeral...)
+	material = append(material, recipient...)
+	key := sha256.Sum256(material)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
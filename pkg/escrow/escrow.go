@@ -0,0 +1,139 @@
+// Package escrow splits the master's sealing root key among operator-held
+// guardians so that state sealed by an enclave survives the loss of that
+// enclave (hardware failure, a deleted KMS key, a lost local root file).
+//
+// The root key is split with Shamir secret sharing over GF(2^8): any
+// threshold of the shares reconstruct it, fewer reveal nothing about it.
+// Each share is sealed to one guardian's X25519 public key (see Seal), so the
+// escrow bundle the master stores and serves holds no plaintext share.
+//
+// Recovery runs on the replacement master: it publishes a one-time recovery
+// key, each guardian opens their share offline and re-seals it to that key,
+// and once threshold shares have arrived the master combines them, checks the
+// result against the escrowed root ID and re-seals its keys under its new
+// root. daasctl escrow implements the guardian side.
+package escrow
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the largest number of shares (the x coordinates 1..255).
+const MaxShares = 255
+
+// Share is one point of the sharing polynomials: Index is the x coordinate
+// and Value holds the y coordinate for each byte of the secret.
+type Share struct {
+	Index byte   `json:"index"`
+	Value []byte `json:"value"`
+}
+
+// Split divides secret into count shares, any threshold of which recover it.
+func Split(secret []byte, threshold, count int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("escrow: empty secret")
+	}
+	if threshold < 1 || count < threshold || count > MaxShares {
+		return nil, fmt.Errorf("escrow: invalid threshold %d of %d shares", threshold, count)
+	}
+
+	shares := make([]Share, count)
+	for i := range shares {
+		shares[i] = Share{Index: byte(i + 1), Value: make([]byte, len(secret))}
+	}
+	// One random polynomial of degree threshold-1 per secret byte, with the
+	// byte as its constant term.
+	coefficients := make([]byte, threshold-1)
+	for position, b := range secret {
+		if _, err := rand.Read(coefficients); err != nil {
+			return nil, fmt.Errorf("escrow: %v", err)
+		}
+		for i := range shares {
+			x := shares[i].Index
+			y := byte(0)
+			for j := len(coefficients) - 1; j >= 0; j-- {
+				y = gfMul(y, x) ^ coefficients[j]
+			}
+			shares[i].Value[position] = gfMul(y, x) ^ b
+		}
+	}
+	return shares, nil
+}
+
+// Combine interpolates the shares at x = 0. It cannot tell whether enough
+// shares were supplied: fewer than the threshold yield a wrong secret, so
+// callers compare the result against a known digest of the secret.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("escrow: no shares")
+	}
+	length := len(shares[0].Value)
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if share.Index == 0 {
+			return nil, errors.New("escrow: share index 0 is not valid")
+		}
+		if seen[share.Index] {
+			return nil, fmt.Errorf("escrow: duplicate share %d", share.Index)
+		}
+		if len(share.Value) != length || length == 0 {
+			return nil, errors.New("escrow: shares have different lengths")
+		}
+		seen[share.Index] = true
+	}
+
+	secret := make([]byte, length)
+	for i, share := range shares {
+		// Lagrange basis polynomial for share i evaluated at 0.
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = gfMul(basis, gfDiv(other.Index, other.Index^share.Index))
+			}
+		}
+		for position, y := range share.Value {
+			secret[position] ^= gfMul(y, basis)
+		}
+	}
+	return secret, nil
+}
+
+// GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1, using log and exp
+// tables for generator 3.
+var gfExp, gfLog = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// x *= 3
+		high := x & 0x80
+		x2 := x << 1
+		if high != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	for i := 255; i < len(exp); i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// gfDiv divides a by a non-zero b.
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
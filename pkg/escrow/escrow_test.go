@@ -0,0 +1,92 @@
+package escrow
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+
+	shares, err := Split(secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var picked []Share
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		got, err := Combine(picked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("shares %v: combined secret differs", subset)
+		}
+	}
+
+	got, err := Combine(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, secret) {
+		t.Error("two of three shares reconstructed the secret")
+	}
+}
+
+func TestSplitSingleShare(t *testing.T) {
+	shares, err := Split([]byte("root"), 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, share := range shares {
+		if got, _ := Combine([]Share{share}); string(got) != "root" {
+			t.Errorf("share %d: got %q", share.Index, got)
+		}
+	}
+}
+
+func TestSplitRejectsInvalidParameters(t *testing.T) {
+	for _, c := range []struct{ threshold, count int }{{0, 3}, {4, 3}, {2, 256}} {
+		if _, err := Split([]byte("x"), c.threshold, c.count); err == nil {
+			t.Errorf("Split(threshold %d, count %d) succeeded", c.threshold, c.count)
+		}
+	}
+}
+
+func TestCombineRejectsDuplicates(t *testing.T) {
+	shares, _ := Split([]byte("root"), 2, 3)
+	if _, err := Combine([]Share{shares[0], shares[0]}); err == nil {
+		t.Error("duplicate shares accepted")
+	}
+	if _, err := Combine([]Share{shares[0], {Index: 2, Value: []byte("x")}}); err == nil {
+		t.Error("shares of different lengths accepted")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	guardian, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+
+	envelope, err := Seal(guardian.PublicKey(), []byte("share"), ShareAAD("root1", "alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := Open(guardian, envelope, ShareAAD("root1", "alice"))
+	if err != nil || string(plaintext) != "share" {
+		t.Fatalf("Open = %q, %v", plaintext, err)
+	}
+	if _, err := Open(guardian, envelope, ShareAAD("root1", "bob")); err == nil {
+		t.Error("share opened under another guardian's name")
+	}
+	if _, err := Open(guardian, envelope, RecoveryAAD("root1", "alice")); err == nil {
+		t.Error("bundle share opened as a recovery submission")
+	}
+	if _, err := Open(other, envelope, ShareAAD("root1", "alice")); err == nil {
+		t.Error("share opened with another key")
+	}
+}
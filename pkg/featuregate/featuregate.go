@@ -0,0 +1,253 @@
+// Package featuregate provides named on/off switches for risky features so
+// they can be rolled out gradually. Each binary registers the gates it knows
+// with a default and a maturity stage; operators override them with a
+// "Name=true,Other=false" list from a config file, <PREFIX>_FEATURE_GATES or
+// the --feature-gates flag, in that order of precedence.
+package featuregate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Stage is the maturity of a gated feature.
+type Stage string
+
+const (
+	Alpha      Stage = "alpha"      // off by default, may change or disappear
+	Beta       Stage = "beta"       // on by default, can still be turned off
+	GA         Stage = "ga"         // always on; the gate is kept for old configs
+	Deprecated Stage = "deprecated" // scheduled for removal
+)
+
+// Override sources reported in State.Source.
+const (
+	SourceDefault = "default"
+	SourceConfig  = "config"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// FlagName is the command-line flag consumed by ParseArgs.
+const FlagName = "feature-gates"
+
+// Logf is the printf-style logger used for the startup summary.
+type Logf func(format string, args ...interface{})
+
+// Spec describes a known gate.
+type Spec struct {
+	Default     bool
+	Stage       Stage
+	Description string
+}
+
+// State is the resolved value of a gate.
+type State struct {
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Default     bool   `json:"default"`
+	Stage       Stage  `json:"stage"`
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
+}
+
+// Gates holds the known gates of one binary and their current values.
+type Gates struct {
+	mu      sync.RWMutex
+	specs   map[string]Spec
+	values  map[string]bool
+	sources map[string]string
+}
+
+// New registers the known gates with their defaults.
+func New(specs map[string]Spec) *Gates {
+	g := &Gates{
+		specs:   make(map[string]Spec, len(specs)),
+		values:  make(map[string]bool, len(specs)),
+		sources: make(map[string]string, len(specs)),
+	}
+	for name, spec := range specs {
+		if spec.Stage == GA {
+			spec.Default = true
+		}
+		g.specs[name] = spec
+		g.values[name] = spec.Default
+		g.sources[name] = SourceDefault
+	}
+	return g
+}
+
+// Enabled reports whether the gate is on. Asking for a gate that was never
+// registered is a programming error and panics, so typos fail loudly in tests.
+func (g *Gates) Enabled(name string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	enabled, ok := g.values[name]
+	if !ok {
+		panic(fmt.Sprintf("featuregate: unknown feature gate %q", name))
+	}
+	return enabled
+}
+
+// Known reports whether the gate is registered.
+func (g *Gates) Known(name string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.specs[name]
+	return ok
+}
+
+// SetFromMap applies overrides. The whole map is rejected if any entry names
+// an unknown gate or tries to turn off a GA gate.
+func (g *Gates) SetFromMap(values map[string]bool, source string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name, enabled := range values {
+		spec, ok := g.specs[name]
+		if !ok {
+			return fmt.Errorf("unknown feature gate %q (known: %s)", name, strings.Join(g.namesLocked(), ", "))
+		}
+		if spec.Stage == GA && !enabled {
+			return fmt.Errorf("feature gate %q is GA and cannot be disabled", name)
+		}
+	}
+	for name, enabled := range values {
+		g.values[name] = enabled
+		g.sources[name] = source
+	}
+	return nil
+}
+
+// Set applies a "Name=true,Other=false" list.
+func (g *Gates) Set(value, source string) error {
+	values, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	return g.SetFromMap(values, source)
+}
+
+// LoadEnv applies the list in the environment variable, if set.
+func (g *Gates) LoadEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	if err := g.Set(value, SourceEnv); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// ParseArgs applies every --feature-gates=<list> (or "--feature-gates <list>")
+// in args and returns the remaining arguments, so binaries that dispatch on
+// subcommands can keep doing so.
+func (g *Gates) ParseArgs(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != FlagName {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--%s requires a value", FlagName)
+			}
+			i++
+			value = args[i]
+		}
+		if err := g.Set(value, SourceFlag); err != nil {
+			return nil, fmt.Errorf("--%s: %w", FlagName, err)
+		}
+	}
+	return rest, nil
+}
+
+// States returns every gate sorted by name.
+func (g *Gates) States() []State {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	states := make([]State, 0, len(g.specs))
+	for _, name := range g.namesLocked() {
+		spec := g.specs[name]
+		states = append(states, State{
+			Name:        name,
+			Enabled:     g.values[name],
+			Default:     spec.Default,
+			Stage:       spec.Stage,
+			Source:      g.sources[name],
+			Description: spec.Description,
+		})
+	}
+	return states
+}
+
+// String renders the resolved values as a "Name=true,Other=false" list.
+func (g *Gates) String() string {
+	states := g.States()
+	parts := make([]string, len(states))
+	for i, state := range states {
+		parts[i] = state.Name + "=" + strconv.FormatBool(state.Enabled)
+	}
+	return strings.Join(parts, ",")
+}
+
+// LogSummary logs one line per gate, flagging overrides and deprecated gates.
+func (g *Gates) LogSummary(logf Logf) {
+	for _, state := range g.States() {
+		note := ""
+		if state.Source != SourceDefault {
+			note = fmt.Sprintf(" (set by %s, default %t)", state.Source, state.Default)
+		}
+		if state.Stage == Deprecated && state.Source != SourceDefault {
+			note += " - deprecated, will be removed"
+		}
+		logf("feature gate %s=%t [%s]%s", state.Name, state.Enabled, state.Stage, note)
+	}
+}
+
+// WriteVerbose writes the gates as plain-text check lines for /healthz?verbose.
+func (g *Gates) WriteVerbose(w io.Writer) {
+	for _, state := range g.States() {
+		fmt.Fprintf(w, "[+]feature-gate %s=%t stage=%s source=%s\n", state.Name, state.Enabled, state.Stage, state.Source)
+	}
+}
+
+func (g *Gates) namesLocked() []string {
+	names := make([]string, 0, len(g.specs))
+	for name := range g.specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse splits a "Name=true,Other=false" list.
+func Parse(value string) (map[string]bool, error) {
+	values := map[string]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("feature gate %q: expected Name=true|false", entry)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("feature gate %q: %q is not a boolean", name, raw)
+		}
+		values[strings.TrimSpace(name)] = enabled
+	}
+	return values, nil
+}
@@ -0,0 +1,113 @@
+package httpserver
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Allowlist is a set of client networks grouped by source (static config,
+// addresses pushed by a control plane, ...). Each source can be replaced
+// independently while requests are being served. Loopback clients are
+// always allowed so local health checks keep working.
+type Allowlist struct {
+	mu      sync.RWMutex
+	sources map[string][]*net.IPNet
+}
+
+// NewAllowlist returns an empty allowlist that only admits loopback clients.
+func NewAllowlist() *Allowlist {
+	return &Allowlist{sources: make(map[string][]*net.IPNet)}
+}
+
+// ParsePrefixes parses CIDRs and bare IPs (treated as /32 or /128).
+func ParsePrefixes(values []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", value)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", value)
+		}
+		nets = append(nets, network)
+	}
+	return nets, nil
+}
+
+// Set replaces the networks of one source. An empty list removes the source.
+func (a *Allowlist) Set(source string, prefixes []string) error {
+	nets, err := ParsePrefixes(prefixes)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(nets) == 0 {
+		delete(a.sources, source)
+	} else {
+		a.sources[source] = nets
+	}
+	return nil
+}
+
+// Allows reports whether the client address (host:port or a bare IP) is
+// loopback or inside any source.
+func (a *Allowlist) Allows(addr string) bool {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, nets := range a.sources {
+		for _, network := range nets {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Prefixes returns every network in CIDR form, sorted and de-duplicated
+// (for status output and host firewall rules).
+func (a *Allowlist) Prefixes() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	seen := make(map[string]bool)
+	var prefixes []string
+	for _, nets := range a.sources {
+		for _, network := range nets {
+			if cidr := network.String(); !seen[cidr] {
+				seen[cidr] = true
+				prefixes = append(prefixes, cidr)
+			}
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
@@ -0,0 +1,188 @@
+package httpserver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressMinSize is the response size below which gzip is not worth
+// the CPU; small Status objects and health checks go out as-is.
+const DefaultCompressMinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// Compress gzips responses for clients that send Accept-Encoding: gzip
+// (kubectl and client-go do by default). Responses smaller than minSize,
+// responses that already carry a Content-Encoding (e.g. from an upstream
+// API server) and protocol upgrades are passed through unchanged.
+// Streaming responses keep working: Flush flushes the gzip stream.
+func Compress(minSize int) Middleware {
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, minSize: minSize}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsGzip parses Accept-Encoding, honouring an explicit q=0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers up to minSize bytes to decide whether to compress,
+// then either streams through gzip or writes the buffered bytes verbatim.
+type compressWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	buf         []byte
+	gz          *gzip.Writer
+	decided     bool
+	wroteHeader bool
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		c.buf = append(c.buf, b...)
+		if len(c.buf) < c.minSize {
+			return len(b), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if c.gz != nil {
+		return c.gz.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// decide commits the response headers and flushes the buffer. large reports
+// whether the body reached minSize (or is being streamed).
+func (c *compressWriter) decide(large bool) error {
+	c.decided = true
+	header := c.Header()
+	compress := large &&
+		header.Get("Content-Encoding") == "" &&
+		c.status != http.StatusNoContent && c.status != http.StatusNotModified &&
+		c.status >= http.StatusOK
+
+	if compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length") // sent chunked
+		c.gz = gzipWriters.Get().(*gzip.Writer)
+		c.gz.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+	c.wroteHeader = true
+
+	buffered := c.buf
+	c.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	var err error
+	if c.gz != nil {
+		_, err = c.gz.Write(buffered)
+	} else {
+		_, err = c.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+// Flush commits the buffered response (a flush means the handler is
+// streaming, e.g. a watch) and flushes the gzip stream to the client.
+func (c *compressWriter) Flush() {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		c.decide(true)
+	}
+	if c.gz != nil {
+		c.gz.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close writes anything still buffered and terminates the gzip stream.
+func (c *compressWriter) Close() error {
+	if !c.decided {
+		if c.status == 0 {
+			// handler wrote nothing; let net/http send its default response
+			return nil
+		}
+		c.decide(false)
+	}
+	if c.gz == nil {
+		return nil
+	}
+	err := c.gz.Close()
+	c.gz.Reset(nil)
+	gzipWriters.Put(c.gz)
+	c.gz = nil
+	return err
+}
+
+// Hijack supports protocol upgrades that reach the handler despite the
+// Upgrade check (e.g. SPDY exec/attach) as long as nothing was written yet.
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok || c.wroteHeader {
+		return nil, nil, fmt.Errorf("connection cannot be hijacked")
+	}
+	c.decided = true
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
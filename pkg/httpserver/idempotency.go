@@ -0,0 +1,333 @@
+package httpserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderIdempotencyKey lets clients mark retries of a mutating request.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses served from the replay cache.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	maxIdempotentRequest    = 4 << 20
+)
+
+// IdempotencyConfig controls the replay cache. Zero values take the defaults.
+type IdempotencyConfig struct {
+	// TTL is how long a response to a request with an explicit
+	// Idempotency-Key is replayed (default 10 minutes).
+	TTL time.Duration
+	// DerivedTTL applies to keys derived from the request content. It only
+	// covers network retries (default 30 seconds): an identical request made
+	// deliberately later, e.g. re-creating a deleted pod, must not be replayed.
+	DerivedTTL time.Duration
+	// MaxEntries bounds the cache; the oldest entries are evicted first (default 10000).
+	MaxEntries int
+	// MaxBodyBytes is the largest response body that is cached (default 1 MiB).
+	MaxBodyBytes int
+	// Scope returns the caller identity keys are namespaced by, so one
+	// caller cannot read another's response by guessing its key (default:
+	// the Authorization header).
+	Scope func(r *http.Request) string
+}
+
+// DeriveKey returns an idempotency key for a request that did not send one,
+// or "" to leave it alone. body is the full request body.
+type DeriveKey func(r *http.Request, body []byte) string
+
+// IdempotencyStats are cumulative counters for metrics.
+type IdempotencyStats struct {
+	Entries    int
+	Stored     uint64
+	Replayed   uint64
+	InProgress uint64 // duplicates rejected while the original was still running
+	Mismatched uint64 // keys reused for a different request
+}
+
+type idempotentEntry struct {
+	fingerprint string
+	pending     bool
+	status      int
+	header      http.Header
+	body        []byte
+	created     time.Time
+	expires     time.Time
+}
+
+// IdempotencyCache replays the original response to mutating requests that
+// repeat an idempotency key, so a retried worker registration or pod create
+// does not run twice. One cache can back several routes.
+type IdempotencyCache struct {
+	config IdempotencyConfig
+
+	mutex   sync.Mutex
+	entries map[string]*idempotentEntry
+	stats   IdempotencyStats
+}
+
+// NewIdempotencyCache creates an in-memory replay cache.
+func NewIdempotencyCache(config IdempotencyConfig) *IdempotencyCache {
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Minute
+	}
+	if config.DerivedTTL <= 0 {
+		config.DerivedTTL = 30 * time.Second
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+	if config.Scope == nil {
+		config.Scope = func(r *http.Request) string { return r.Header.Get("Authorization") }
+	}
+	return &IdempotencyCache{config: config, entries: make(map[string]*idempotentEntry)}
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *IdempotencyCache) Stats() IdempotencyStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
+
+// Middleware applies the cache to POST, PUT, PATCH and DELETE requests that
+// carry an Idempotency-Key, or for which derive returns a key (derive may be
+// nil). The first request runs normally; repeats within the TTL get the
+// stored status, headers and body with Idempotent-Replayed: true. A repeat
+// that arrives while the first is still running gets 409, and a key reused
+// for a different request gets 422. Server errors and responses the client
+// is expected to retry (408, 429) are not stored. Kubernetes dry-run requests
+// (a dryRun query parameter) always pass through: replaying a dry run's
+// response for the real request would report an object that was never stored.
+func (c *IdempotencyCache) Middleware(derive DeriveKey) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if key == "" && derive == nil || r.Header.Get("Upgrade") != "" || r.URL.Query().Has("dryRun") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				WriteStatus(w, http.StatusBadRequest, "BadRequest", "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				original := r.Body
+				var err error
+				body, err = io.ReadAll(io.LimitReader(original, maxIdempotentRequest+1))
+				if err != nil {
+					original.Close()
+					WriteStatus(w, http.StatusBadRequest, "BadRequest", "failed to read request body")
+					return
+				}
+				if len(body) > maxIdempotentRequest {
+					// Too large to fingerprint; pass through with the body intact
+					r.Body = struct {
+						io.Reader
+						io.Closer
+					}{io.MultiReader(bytes.NewReader(body), original), original}
+					next.ServeHTTP(w, r)
+					return
+				}
+				original.Close()
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			ttl := c.config.TTL
+			if key == "" {
+				if key = derive(r, body); key == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+				ttl = c.config.DerivedTTL
+			}
+
+			cacheKey := digest(c.config.Scope(r), key)
+			fingerprint := digest(r.Method, r.URL.Path, r.URL.RawQuery, string(body))
+			entry, replay := c.begin(cacheKey, fingerprint)
+			switch {
+			case replay && entry.fingerprint != fingerprint:
+				WriteStatus(w, http.StatusUnprocessableEntity, "Invalid", "Idempotency-Key was already used for a different request")
+				return
+			case replay && entry.pending:
+				w.Header().Set("Retry-After", "1")
+				WriteStatus(w, http.StatusConflict, "Conflict", "a request with this Idempotency-Key is still in progress")
+				return
+			case replay:
+				for name, values := range entry.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(HeaderIdempotentReplayed, "true")
+				w.WriteHeader(entry.status)
+				w.Write(entry.body)
+				return
+			}
+
+			recorder := &replayRecorder{ResponseWriter: w, limit: c.config.MaxBodyBytes}
+			completed := false
+			defer func() {
+				if !completed {
+					c.abandon(cacheKey)
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+			completed = true
+			c.finish(cacheKey, recorder, ttl)
+		})
+	}
+}
+
+func digest(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// begin returns the live entry for key (replay=true) or reserves a pending one.
+func (c *IdempotencyCache) begin(key, fingerprint string) (*idempotentEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if entry, ok := c.entries[key]; ok {
+		if entry.pending || now.Before(entry.expires) {
+			copied := *entry
+			if entry.fingerprint == fingerprint && !entry.pending {
+				c.stats.Replayed++
+			} else if entry.fingerprint != fingerprint {
+				c.stats.Mismatched++
+			} else {
+				c.stats.InProgress++
+			}
+			return &copied, true
+		}
+		delete(c.entries, key)
+	}
+
+	if len(c.entries) >= c.config.MaxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = &idempotentEntry{fingerprint: fingerprint, pending: true, created: now}
+	return nil, false
+}
+
+// evictLocked drops expired entries, then the oldest completed ones until
+// there is room.
+func (c *IdempotencyCache) evictLocked(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.pending && !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	for len(c.entries) >= c.config.MaxEntries {
+		oldestKey := ""
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if !entry.pending && (oldestKey == "" || entry.created.Before(oldest)) {
+				oldestKey, oldest = key, entry.created
+			}
+		}
+		if oldestKey == "" {
+			return
+		}
+		delete(c.entries, oldestKey)
+	}
+}
+
+func (c *IdempotencyCache) finish(key string, recorder *replayRecorder, ttl time.Duration) {
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= 500 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || recorder.overflow {
+		c.abandon(key)
+		return
+	}
+
+	header := recorder.Header().Clone()
+	header.Del("Date")
+	header.Del("Content-Length")
+	header.Del(HeaderRequestID)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		entry.pending = false
+		entry.status = status
+		entry.header = header
+		entry.body = recorder.body.Bytes()
+		entry.expires = time.Now().Add(ttl)
+		c.stats.Stored++
+	}
+}
+
+// abandon forgets a reservation so the client can retry for real.
+func (c *IdempotencyCache) abandon(key string) {
+	c.mutex.Lock()
+	delete(c.entries, key)
+	c.mutex.Unlock()
+}
+
+// replayRecorder passes the response through while keeping a copy of it.
+type replayRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (r *replayRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *replayRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if !r.overflow {
+		if r.body.Len()+len(b) > r.limit {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *replayRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *replayRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
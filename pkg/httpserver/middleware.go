@@ -0,0 +1,187 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// HeaderRequestID carries the request ID to and from clients.
+const HeaderRequestID = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// panics counts recovered handler panics for the whole process.
+var panics atomic.Uint64
+
+// PanicCount returns the number of handler panics recovered so far.
+func PanicCount() uint64 {
+	return panics.Load()
+}
+
+// Logf is the printf-style logger used by middleware (log.Printf, logrus Infof, ...).
+type Logf func(format string, args ...interface{})
+
+// Middleware wraps a handler.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middleware so that the first one is outermost.
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// RequestID assigns every request an ID (reusing a client-supplied
+// X-Request-ID), echoes it in the response and stores it in the context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestIDFrom returns the ID assigned by RequestID, or "".
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("req_%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Recovery turns handler panics into a Kubernetes Status 500 response
+// instead of killing the connection. The panic is logged with its request
+// ID and stack trace and counted in PanicCount.
+func Recovery(logf Logf) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				panics.Add(1)
+				requestID := RequestIDFrom(r.Context())
+				logf("panic serving %s %s (request %s): %v\n%s", r.Method, r.URL.Path, requestID, err, debug.Stack())
+
+				// Headers already sent; the status can no longer be changed.
+				if recorder.status != 0 {
+					return
+				}
+				WriteStatus(w, http.StatusInternalServerError, "InternalError",
+					fmt.Sprintf("Internal error occurred: request %s failed", requestID))
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// WriteStatus writes a Kubernetes-style Status failure object.
+func WriteStatus(w http.ResponseWriter, code int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]interface{}{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
+
+// statusRecorder captures the response status (0 until anything is written).
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush keeps streaming responses (watch, logs) working through the recorder.
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Logging writes one access log line per request. Paths in skip (e.g.
+// health probes) are not logged.
+func Logging(logf Logf, skip ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, path := range skip {
+				if r.URL.Path == path {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			logf("%s %s %d %s request=%s", r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond), RequestIDFrom(r.Context()))
+		})
+	}
+}
+
+// RequireToken rejects requests whose bearer token (or the given header)
+// does not match token. An empty token disables the check.
+func RequireToken(header, token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			presented := r.Header.Get(header)
+			if header == "Authorization" {
+				presented = strings.TrimPrefix(presented, "Bearer ")
+			}
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
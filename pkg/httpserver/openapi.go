@@ -0,0 +1,392 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Security scheme names referenced by Operation.Auth.
+const (
+	AuthSealToken  = "sealToken"  // X-Seal-Token header of a staked worker
+	AuthAdminToken = "adminToken" // Authorization: Bearer <admin token>
+)
+
+// Param documents a query parameter. Path parameters are taken from the
+// {name} segments of Operation.Path.
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+	Type        string // OpenAPI scalar type, default "string"
+}
+
+// Operation documents one method on a route.
+//
+// Request and Response are example values: their types (and, for
+// map[string]interface{} literals, their keys) become the JSON schema. A
+// string Response is served as ContentType (default text/plain); a nil
+// Response documents a status without a body.
+type Operation struct {
+	Method      string
+	Path        string // OpenAPI path template; defaults to the mux pattern
+	Summary     string
+	Description string
+	Tags        []string
+	Auth        string // AuthSealToken, AuthAdminToken or "" for public
+	Query       []Param
+	Request     interface{}
+	Response    interface{}
+	ContentType string
+	Status      int   // success status, default 200
+	Errors      []int // documented error statuses (plain-text bodies)
+	Deprecated  bool
+}
+
+// Router is a ServeMux that records the operations registered on each
+// pattern, so the OpenAPI document is generated from the same definitions
+// that route requests and cannot silently drift from them.
+type Router struct {
+	mux          *http.ServeMux
+	title        string
+	version      string
+	mutex        sync.Mutex
+	operations   []Operation
+	undocumented []string
+}
+
+// NewRouter creates an empty router for the API described by title and version.
+func NewRouter(title, version string) *Router {
+	return &Router{mux: http.NewServeMux(), title: title, version: version}
+}
+
+// Handle registers handler for pattern and documents its operations. A pattern
+// registered without operations (proxies, profiling, file trees) is routed but
+// left out of the spec; Undocumented lists those patterns.
+func (r *Router) Handle(pattern string, handler http.Handler, ops ...Operation) {
+	r.mux.Handle(pattern, handler)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(ops) == 0 {
+		r.undocumented = append(r.undocumented, pattern)
+		return
+	}
+	for _, op := range ops {
+		if op.Path == "" {
+			op.Path = pattern
+		}
+		if op.Method == "" {
+			op.Method = http.MethodGet
+		}
+		r.operations = append(r.operations, op)
+	}
+}
+
+// HandleFunc is Handle for a handler function.
+func (r *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request), ops ...Operation) {
+	r.Handle(pattern, http.HandlerFunc(handler), ops...)
+}
+
+// ServeHTTP dispatches to the registered handlers.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// Operations returns the documented operations in registration order.
+func (r *Router) Operations() []Operation {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Operation(nil), r.operations...)
+}
+
+// Undocumented returns the patterns registered without operations.
+func (r *Router) Undocumented() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.undocumented...)
+}
+
+// SpecHandler serves the OpenAPI document as JSON. It is generated on every
+// request, so routes added after it was mounted are included.
+func (r *Router) SpecHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(r.OpenAPI())
+	})
+}
+
+var pathParam = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// OpenAPI builds an OpenAPI 3.0 document from the documented operations.
+func (r *Router) OpenAPI() map[string]interface{} {
+	paths := map[string]interface{}{}
+	usedAuth := map[string]bool{}
+
+	for _, op := range r.Operations() {
+		item, _ := paths[op.Path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[op.Path] = item
+		}
+
+		operation := map[string]interface{}{
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"responses":   responsesFor(op),
+		}
+		if op.Description != "" {
+			operation["description"] = op.Description
+		}
+		if len(op.Tags) > 0 {
+			operation["tags"] = op.Tags
+		}
+		if op.Deprecated {
+			operation["deprecated"] = true
+		}
+		if op.Auth != "" {
+			usedAuth[op.Auth] = true
+			operation["security"] = []map[string][]string{{op.Auth: {}}}
+		}
+
+		var params []map[string]interface{}
+		for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": match[1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, param := range op.Query {
+			paramType := param.Type
+			if paramType == "" {
+				paramType = "string"
+			}
+			entry := map[string]interface{}{
+				"name": param.Name, "in": "query", "required": param.Required,
+				"schema": map[string]interface{}{"type": paramType},
+			}
+			if param.Description != "" {
+				entry["description"] = param.Description
+			}
+			params = append(params, entry)
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+
+		if op.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  contentFor(op.Request, ""),
+			}
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": r.title, "version": r.version},
+		"paths":   paths,
+	}
+	schemes := map[string]interface{}{}
+	if usedAuth[AuthSealToken] {
+		schemes[AuthSealToken] = map[string]interface{}{
+			"type": "apiKey", "in": "header", "name": "X-Seal-Token",
+			"description": "Seal token issued to a staked worker",
+		}
+	}
+	if usedAuth[AuthAdminToken] {
+		schemes[AuthAdminToken] = map[string]interface{}{
+			"type": "http", "scheme": "bearer",
+			"description": "Operator admin token",
+		}
+	}
+	if len(schemes) > 0 {
+		doc["components"] = map[string]interface{}{"securitySchemes": schemes}
+	}
+	return doc
+}
+
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	upper := true
+	for _, c := range op.Path {
+		switch {
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			if upper && c >= 'a' && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			b.WriteRune(c)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
+
+func responsesFor(op Operation) map[string]interface{} {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	if op.Response != nil {
+		success["content"] = contentFor(op.Response, op.ContentType)
+	}
+
+	responses := map[string]interface{}{strconv.Itoa(status): success}
+	for _, code := range op.Errors {
+		responses[strconv.Itoa(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content": map[string]interface{}{
+				"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			},
+		}
+	}
+	return responses
+}
+
+func contentFor(example interface{}, contentType string) map[string]interface{} {
+	if text, ok := example.(string); ok {
+		if contentType == "" {
+			contentType = "text/plain"
+		}
+		schema := map[string]interface{}{"type": "string"}
+		if text != "" {
+			schema["example"] = text
+		}
+		return map[string]interface{}{contentType: map[string]interface{}{"schema": schema}}
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return map[string]interface{}{contentType: map[string]interface{}{"schema": SchemaOf(example)}}
+}
+
+// SchemaOf derives a JSON schema from an example value. Struct fields follow
+// their json tags; map[string]interface{} literals contribute their keys.
+func SchemaOf(example interface{}) map[string]interface{} {
+	return schemaForValue(reflect.ValueOf(example), map[reflect.Type]bool{})
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+func schemaForValue(v reflect.Value, seen map[reflect.Type]bool) map[string]interface{} {
+	if !v.IsValid() {
+		return map[string]interface{}{}
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return schemaForType(v.Type(), seen)
+		}
+		return schemaForValue(v.Elem(), seen)
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String && v.Type().Elem().Kind() == reflect.Interface && v.Len() > 0 {
+			keys := v.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			properties := map[string]interface{}{}
+			for _, key := range keys {
+				properties[key.String()] = schemaForValue(v.MapIndex(key), seen)
+			}
+			return map[string]interface{}{"type": "object", "properties": properties}
+		}
+	case reflect.Slice:
+		if kind := v.Type().Elem().Kind(); (kind == reflect.Interface || kind == reflect.Map) && v.Len() > 0 {
+			return map[string]interface{}{"type": "array", "items": schemaForValue(v.Index(0), seen)}
+		}
+	}
+	return schemaForType(v.Type(), seen)
+}
+
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawJSONType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaForType(t.Elem(), seen)
+		schema["nullable"] = true
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		properties := map[string]interface{}{}
+		addStructFields(t, properties, seen)
+		return map[string]interface{}{"type": "object", "properties": properties}
+	}
+	return map[string]interface{}{}
+}
+
+func addStructFields(t reflect.Type, properties map[string]interface{}, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(embedded, properties, seen)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaForType(field.Type, seen)
+	}
+}
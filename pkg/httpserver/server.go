@@ -0,0 +1,281 @@
+// Package httpserver provides the shared HTTP listener setup for K3s-DaaS
+// components: configurable listen address, TLS from files or an in-memory
+// self-signed certificate, an optional HTTP→HTTPS redirect listener, HSTS,
+// HTTP/2 on TLS listeners, graceful shutdown and the common middleware
+// (recovery, access log, token auth, gzip compression) plus a client
+// address allowlist and optional client certificate verification.
+package httpserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Config describes how a component exposes its HTTP server.
+type Config struct {
+	Addr         string // listen address, e.g. ":8080"
+	TLSCertFile  string // PEM certificate; requires TLSKeyFile
+	TLSKeyFile   string
+	SelfSigned   bool   // generate an in-memory certificate when no files are given
+	RedirectAddr string // plain HTTP listener that redirects to HTTPS (TLS only)
+	HSTSMaxAge   int    // Strict-Transport-Security max-age in seconds (TLS only, 0 = off)
+	DisableHTTP2 bool   // serve HTTP/1.1 only on the TLS listener
+}
+
+// ConfigFromEnv reads <PREFIX>_LISTEN_ADDR, <PREFIX>_TLS_CERT_FILE,
+// <PREFIX>_TLS_KEY_FILE, <PREFIX>_TLS_SELF_SIGNED, <PREFIX>_HTTP_REDIRECT_ADDR,
+// <PREFIX>_HSTS_MAX_AGE and <PREFIX>_HTTP2 ("false" disables HTTP/2),
+// falling back to defaultAddr and plaintext.
+func ConfigFromEnv(prefix, defaultAddr string) Config {
+	cfg := Config{
+		Addr:         os.Getenv(prefix + "_LISTEN_ADDR"),
+		TLSCertFile:  os.Getenv(prefix + "_TLS_CERT_FILE"),
+		TLSKeyFile:   os.Getenv(prefix + "_TLS_KEY_FILE"),
+		SelfSigned:   os.Getenv(prefix+"_TLS_SELF_SIGNED") == "true",
+		RedirectAddr: os.Getenv(prefix + "_HTTP_REDIRECT_ADDR"),
+		DisableHTTP2: os.Getenv(prefix+"_HTTP2") == "false",
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = defaultAddr
+	}
+	if maxAge, err := strconv.Atoi(os.Getenv(prefix + "_HSTS_MAX_AGE")); err == nil && maxAge > 0 {
+		cfg.HSTSMaxAge = maxAge
+	}
+	return cfg
+}
+
+// TLSEnabled reports whether the server will serve HTTPS.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.SelfSigned
+}
+
+// Scheme returns "https" or "http" for building self-referencing URLs.
+func (c Config) Scheme() string {
+	if c.TLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// Server is an http.Server plus its optional redirect listener.
+type Server struct {
+	*http.Server
+	config    Config
+	redirect  *http.Server
+	clientCAs atomic.Pointer[x509.CertPool]
+}
+
+// New validates the configuration and prepares the server. The handler is
+// wrapped with HSTS when enabled. Each component passes its own mux;
+// http.DefaultServeMux is never used implicitly.
+func New(cfg Config, handler http.Handler) (*Server, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("a handler is required")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("both TLS certificate and key files must be set")
+	}
+
+	s := &Server{
+		Server: &http.Server{Addr: cfg.Addr, Handler: handler},
+		config: cfg,
+	}
+	if !cfg.TLSEnabled() {
+		return s, nil
+	}
+
+	// The certificate is loaded here rather than by ListenAndServeTLS so that
+	// per-connection configs (SetClientCAs) can be cloned from TLSConfig.
+	var cert tls.Certificate
+	var err error
+	if cfg.TLSCertFile == "" {
+		cert, err = selfSignedCertificate()
+	} else if cert, err = tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil {
+		err = fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	// HTTP/2 is negotiated via ALPN. kubectl multiplexes list/watch requests
+	// over one connection with it; an empty TLSNextProto map turns it off.
+	if cfg.DisableHTTP2 {
+		s.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		s.TLSConfig.NextProtos = []string{"http/1.1"}
+	} else {
+		s.TLSConfig.NextProtos = []string{"h2", "http/1.1"}
+	}
+
+	if cfg.HSTSMaxAge > 0 {
+		header := fmt.Sprintf("max-age=%d; includeSubDomains", cfg.HSTSMaxAge)
+		s.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Strict-Transport-Security", header)
+			handler.ServeHTTP(w, r)
+		})
+	}
+
+	if cfg.RedirectAddr != "" {
+		s.redirect = &http.Server{Addr: cfg.RedirectAddr, Handler: redirectHandler(cfg.Addr)}
+	}
+	return s, nil
+}
+
+// SetClientCAs asks TLS clients for a certificate and verifies any that is
+// presented against pool; callers decide per request whether one is
+// required (r.TLS.VerifiedChains). The first call must happen before the
+// server starts, possibly with a nil pool; later calls replace the pool
+// for new connections, e.g. when the CA arrives from a control plane. It
+// has no effect on a plaintext server.
+func (s *Server) SetClientCAs(pool *x509.CertPool) {
+	if s.TLSConfig == nil {
+		return
+	}
+	s.clientCAs.Store(pool)
+	if s.TLSConfig.GetConfigForClient != nil {
+		return
+	}
+	s.TLSConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool := s.clientCAs.Load()
+		if pool == nil {
+			return nil, nil
+		}
+		config := s.TLSConfig.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		return config, nil
+	}
+}
+
+// ListenAndServe serves HTTP or HTTPS according to the configuration and
+// starts the redirect listener alongside it.
+func (s *Server) ListenAndServe() error {
+	if !s.config.TLSEnabled() {
+		return s.Server.ListenAndServe()
+	}
+
+	if s.redirect != nil {
+		go func() {
+			if err := s.redirect.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logf("HTTP redirect listener on %s failed: %v", s.redirect.Addr, err)
+			}
+		}()
+	}
+	return s.Server.ListenAndServeTLS("", "")
+}
+
+// Run serves until ctx is cancelled, then shuts down gracefully, giving
+// in-flight requests up to timeout to complete.
+func (s *Server) Run(ctx context.Context, timeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return s.Shutdown(shutdownCtx)
+	}
+}
+
+// Shutdown stops the server and the redirect listener.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.redirect != nil {
+		s.redirect.Shutdown(ctx)
+	}
+	return s.Server.Shutdown(ctx)
+}
+
+// Description is a short human-readable summary for startup logs.
+func (s *Server) Description() string {
+	desc := fmt.Sprintf("%s://%s", s.config.Scheme(), s.config.Addr)
+	if s.config.TLSEnabled() && s.config.TLSCertFile == "" {
+		desc += " (self-signed)"
+	}
+	if s.config.TLSEnabled() && !s.config.DisableHTTP2 {
+		desc += " h2"
+	}
+	if s.redirect != nil {
+		desc += fmt.Sprintf(", redirect from http://%s", s.redirect.Addr)
+	}
+	return desc
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+		return
+	}
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// redirectHandler sends every request to the same host and path on the TLS port.
+func redirectHandler(tlsAddr string) http.Handler {
+	_, tlsPort, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if tlsPort != "" && tlsPort != "443" {
+			host = net.JoinHostPort(host, tlsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
+// selfSignedCertificate creates an ECDSA P-256 certificate valid for one
+// year. The key never leaves process memory, which suits TEE deployments.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate TLS key: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate certificate serial: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hostname, Organization: []string{"K3s-DaaS"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	if hostname != "" {
+		template.DNSNames = append(template.DNSNames, hostname)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create self-signed certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,5 @@
+module github.com/k3s-io/daas-service
+
+go 1.21
+
+require golang.org/x/sys v0.10.0
@@ -0,0 +1,165 @@
+// Package service installs and manages K3s-DaaS binaries as operating system
+// services: systemd units on Linux (logging to journald) and Windows services
+// (logging to the EventLog), both with a restart-on-failure policy.
+//
+// Every binary exposes the same subcommand through Command:
+//
+//	<binary> service install [--env KEY=VALUE]... [--user NAME] [-- ARGS...]
+//	<binary> service uninstall|start|stop|restart|status
+package service
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupported is returned on platforms without a supported service manager.
+var ErrUnsupported = errors.New("service management is only supported on Linux (systemd) and Windows")
+
+// Spec describes a service to install.
+type Spec struct {
+	Name        string            // unit / service name, e.g. "k3s-daas-worker"
+	DisplayName string            // human readable name (Windows)
+	Description string            // unit Description= / service description
+	Executable  string            // absolute path; defaults to the running binary
+	Args        []string          // arguments passed to the executable
+	Env         map[string]string // environment for the service process
+	WorkingDir  string            // working directory; defaults to the executable's directory
+	User        string            // run as this user (systemd only)
+	RestartSec  int               // delay before restarting after a failure; defaults to 5
+}
+
+// withDefaults fills in the executable, working directory and restart delay.
+func (s Spec) withDefaults() (Spec, error) {
+	if s.Name == "" {
+		return s, errors.New("service name is required")
+	}
+	if s.Executable == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return s, fmt.Errorf("failed to resolve executable: %v", err)
+		}
+		s.Executable = exe
+	}
+	exe, err := filepath.Abs(s.Executable)
+	if err != nil {
+		return s, fmt.Errorf("failed to resolve executable: %v", err)
+	}
+	s.Executable = exe
+	if s.WorkingDir == "" {
+		s.WorkingDir = filepath.Dir(s.Executable)
+	}
+	if s.DisplayName == "" {
+		s.DisplayName = s.Name
+	}
+	if s.RestartSec <= 0 {
+		s.RestartSec = 5
+	}
+	return s, nil
+}
+
+// envFlag collects repeated --env KEY=VALUE flags.
+type envFlag map[string]string
+
+func (e envFlag) String() string { return "" }
+
+func (e envFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	e[key] = val
+	return nil
+}
+
+// Command runs a "service" subcommand (args excludes the word "service").
+// Flags given to install override the corresponding fields of spec.
+func Command(spec Spec, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: service install|uninstall|start|stop|restart|status")
+	}
+
+	switch action := args[0]; action {
+	case "install":
+		fs := flag.NewFlagSet("service install", flag.ContinueOnError)
+		fs.SetOutput(out)
+		env := envFlag{}
+		for key, value := range spec.Env {
+			env[key] = value
+		}
+		fs.Var(env, "env", "environment variable for the service (KEY=VALUE, repeatable)")
+		fs.StringVar(&spec.Name, "name", spec.Name, "service name")
+		fs.StringVar(&spec.Executable, "binary", spec.Executable, "path to the executable (defaults to this binary)")
+		fs.StringVar(&spec.User, "user", spec.User, "run as this user (systemd only)")
+		fs.StringVar(&spec.WorkingDir, "workdir", spec.WorkingDir, "working directory")
+		fs.IntVar(&spec.RestartSec, "restart-sec", spec.RestartSec, "seconds to wait before restarting after a failure")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		spec.Env = env
+		if extra := fs.Args(); len(extra) > 0 {
+			spec.Args = extra
+		}
+		if err := Install(spec); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "installed service %s\n", spec.Name)
+		return nil
+	case "uninstall", "start", "stop", "restart", "status":
+		name := spec.Name
+		fs := flag.NewFlagSet("service "+action, flag.ContinueOnError)
+		fs.SetOutput(out)
+		fs.StringVar(&name, "name", name, "service name")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return control(action, name, out)
+	default:
+		return fmt.Errorf("unknown service action %q", action)
+	}
+}
+
+// control dispatches the non-install actions.
+func control(action, name string, out io.Writer) error {
+	switch action {
+	case "uninstall":
+		if err := Uninstall(name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "uninstalled service %s\n", name)
+	case "start":
+		return Start(name)
+	case "stop":
+		return Stop(name)
+	case "restart":
+		if err := Stop(name); err != nil {
+			return err
+		}
+		return Start(name)
+	case "status":
+		status, err := Status(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, status)
+	}
+	return nil
+}
+
+// NotifyContext returns a context that is canceled on SIGINT/SIGTERM or, when
+// running under the Windows service manager, on a stop/shutdown request.
+func NotifyContext(parent context.Context, name string) (context.Context, context.CancelFunc) {
+	return notifyContext(parent, name)
+}
+
+// LogOutput returns where a component should write its logs: the EventLog when
+// running as a Windows service, stderr otherwise (journald captures it under systemd).
+func LogOutput(name string) io.Writer {
+	return logOutput(name)
+}
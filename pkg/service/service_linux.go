@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// unitDir is where generated systemd units are written.
+const unitDir = "/etc/systemd/system"
+
+func unitPath(name string) string {
+	return filepath.Join(unitDir, name+".service")
+}
+
+// systemdQuote quotes a value for ExecStart= / Environment= when needed.
+func systemdQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\$%") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// renderUnit generates the systemd unit for spec.
+func renderUnit(spec Spec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", spec.Description)
+	fmt.Fprintf(&b, "Wants=network-online.target\nAfter=network-online.target\n")
+	fmt.Fprintf(&b, "StartLimitIntervalSec=300\nStartLimitBurst=10\n\n")
+
+	fmt.Fprintf(&b, "[Service]\n")
+	fmt.Fprintf(&b, "Type=simple\n")
+	command := []string{systemdQuote(spec.Executable)}
+	for _, arg := range spec.Args {
+		command = append(command, systemdQuote(arg))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(command, " "))
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(spec.WorkingDir))
+	if spec.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", spec.User)
+	}
+
+	keys := make([]string, 0, len(spec.Env))
+	for key := range spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(key+"="+spec.Env[key]))
+	}
+
+	fmt.Fprintf(&b, "Restart=on-failure\nRestartSec=%d\n", spec.RestartSec)
+	fmt.Fprintf(&b, "KillSignal=SIGTERM\nTimeoutStopSec=60\n")
+	fmt.Fprintf(&b, "StandardOutput=journal\nStandardError=journal\nSyslogIdentifier=%s\n", spec.Name)
+	fmt.Fprintf(&b, "LimitNOFILE=1048576\n\n")
+
+	fmt.Fprintf(&b, "[Install]\nWantedBy=multi-user.target\n")
+	return b.String()
+}
+
+func systemctl(args ...string) error {
+	output, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Install writes the unit file, reloads systemd and enables the unit.
+func Install(spec Spec) error {
+	spec, err := spec.withDefaults()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(unitPath(spec.Name), []byte(renderUnit(spec)), 0644); err != nil {
+		return fmt.Errorf("failed to write unit file: %v", err)
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl("enable", spec.Name+".service")
+}
+
+// Uninstall stops and disables the unit and removes its file.
+func Uninstall(name string) error {
+	// a unit that is not running cannot be stopped; ignore that
+	_ = systemctl("stop", name+".service")
+	if err := systemctl("disable", name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(unitPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove unit file: %v", err)
+	}
+	return systemctl("daemon-reload")
+}
+
+// Start starts the unit.
+func Start(name string) error {
+	return systemctl("start", name+".service")
+}
+
+// Stop stops the unit.
+func Stop(name string) error {
+	return systemctl("stop", name+".service")
+}
+
+// Status reports the unit's active state (active, inactive, failed, ...) and its PID.
+func Status(name string) (string, error) {
+	output, err := exec.Command("systemctl", "show", name+".service",
+		"--property=LoadState,ActiveState,SubState,MainPID,NRestarts").Output()
+	if err != nil {
+		return "", fmt.Errorf("systemctl show failed: %v", err)
+	}
+
+	props := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			props[key] = value
+		}
+	}
+	if props["LoadState"] == "not-found" {
+		return "not installed", nil
+	}
+
+	status := props["ActiveState"] + " (" + props["SubState"] + ")"
+	if pid, _ := strconv.Atoi(props["MainPID"]); pid > 0 {
+		status += fmt.Sprintf(", pid %d", pid)
+	}
+	if restarts := props["NRestarts"]; restarts != "" && restarts != "0" {
+		status += ", restarts " + restarts
+	}
+	return status, nil
+}
+
+func notifyContext(parent context.Context, _ string) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+}
+
+func logOutput(string) io.Writer {
+	return os.Stderr
+}
@@ -0,0 +1,34 @@
+//go:build !linux && !windows
+
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Install is not supported on this platform.
+func Install(Spec) error { return ErrUnsupported }
+
+// Uninstall is not supported on this platform.
+func Uninstall(string) error { return ErrUnsupported }
+
+// Start is not supported on this platform.
+func Start(string) error { return ErrUnsupported }
+
+// Stop is not supported on this platform.
+func Stop(string) error { return ErrUnsupported }
+
+// Status is not supported on this platform.
+func Status(string) (string, error) { return "", ErrUnsupported }
+
+func notifyContext(parent context.Context, _ string) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+}
+
+func logOutput(string) io.Writer {
+	return os.Stderr
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// Install creates an auto-start service with restart-on-failure recovery
+// actions and registers it as an EventLog source.
+func Install(spec Spec) error {
+	spec, err := spec.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager: %v", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(spec.Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", spec.Name)
+	}
+
+	s, err := m.CreateService(spec.Name, spec.Executable, mgr.Config{
+		DisplayName: spec.DisplayName,
+		Description: spec.Description,
+		StartType:   mgr.StartAutomatic,
+	}, spec.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %v", err)
+	}
+	defer s.Close()
+
+	delay := time.Duration(spec.RestartSec) * time.Second
+	actions := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: delay},
+		{Type: mgr.ServiceRestart, Delay: delay},
+		{Type: mgr.ServiceRestart, Delay: 4 * delay},
+	}
+	if err := s.SetRecoveryActions(actions, 24*60*60); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %v", err)
+	}
+
+	if err := setEnvironment(spec.Name, spec.Env); err != nil {
+		return err
+	}
+
+	if err := eventlog.InstallAsEventCreate(spec.Name, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		// an existing source from an earlier install is fine
+		if !strings.Contains(err.Error(), "exists") {
+			return fmt.Errorf("failed to register event log source: %v", err)
+		}
+	}
+	return nil
+}
+
+// setEnvironment stores the service environment in the service's registry key.
+func setEnvironment(name string, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+name, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to open service registry key: %v", err)
+	}
+	defer key.Close()
+
+	values := make([]string, 0, len(env))
+	for k, v := range env {
+		values = append(values, k+"="+v)
+	}
+	sort.Strings(values)
+	if err := key.SetStringsValue("Environment", values); err != nil {
+		return fmt.Errorf("failed to set service environment: %v", err)
+	}
+	return nil
+}
+
+func openService(name string) (*mgr.Mgr, *mgr.Service, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to service manager: %v", err)
+	}
+	s, err := m.OpenService(name)
+	if err != nil {
+		m.Disconnect()
+		return nil, nil, fmt.Errorf("service %s is not installed: %v", name, err)
+	}
+	return m, s, nil
+}
+
+// Uninstall stops and deletes the service and its EventLog source.
+func Uninstall(name string) error {
+	_ = Stop(name)
+
+	m, s, err := openService(name)
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	defer s.Close()
+
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("failed to delete service: %v", err)
+	}
+	eventlog.Remove(name)
+	return nil
+}
+
+// Start starts the service.
+func Start(name string) error {
+	m, s, err := openService(name)
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	defer s.Close()
+
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("failed to start service: %v", err)
+	}
+	return nil
+}
+
+// Stop asks the service to stop and waits up to 60 seconds for it to do so.
+func Stop(name string) error {
+	m, s, err := openService(name)
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	defer s.Close()
+
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return fmt.Errorf("failed to stop service: %v", err)
+	}
+	deadline := time.Now().Add(60 * time.Second)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for service %s to stop", name)
+		}
+		time.Sleep(500 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return fmt.Errorf("failed to query service: %v", err)
+		}
+	}
+	return nil
+}
+
+// Status reports the service state and PID.
+func Status(name string) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to service manager: %v", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return "not installed", nil
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	if err != nil {
+		return "", fmt.Errorf("failed to query service: %v", err)
+	}
+
+	states := map[svc.State]string{
+		svc.Stopped:         "stopped",
+		svc.StartPending:    "start pending",
+		svc.StopPending:     "stop pending",
+		svc.Running:         "running",
+		svc.ContinuePending: "continue pending",
+		svc.PausePending:    "pause pending",
+		svc.Paused:          "paused",
+	}
+	result := states[status.State]
+	if status.ProcessId != 0 {
+		result += fmt.Sprintf(", pid %d", status.ProcessId)
+	}
+	return result, nil
+}
+
+// handler reports Running to the service manager and cancels the context on stop.
+type handler struct {
+	cancel context.CancelFunc
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.StartPending}
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for request := range requests {
+		switch request.Cmd {
+		case svc.Interrogate:
+			changes <- request.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending, WaitHint: 60000}
+			h.cancel()
+			return false, 0
+		}
+	}
+	return false, 0
+}
+
+func notifyContext(parent context.Context, name string) (context.Context, context.CancelFunc) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	}
+
+	// services start in System32; resolve relative config paths next to the binary
+	if exe, err := os.Executable(); err == nil {
+		os.Chdir(filepath.Dir(exe))
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		if err := svc.Run(name, &handler{cancel: cancel}); err != nil {
+			cancel()
+		}
+	}()
+	return ctx, cancel
+}
+
+// eventLogWriter maps log lines to EventLog entries by severity.
+type eventLogWriter struct {
+	log *eventlog.Log
+}
+
+func (w *eventLogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSpace(string(p))
+	if line == "" {
+		// Entries filtered out by the formatter arrive as empty writes.
+		return len(p), nil
+	}
+	lower := strings.ToLower(line)
+	var err error
+	switch {
+	case strings.Contains(lower, "level=error"), strings.Contains(lower, "level=fatal"), strings.Contains(line, "❌"):
+		err = w.log.Error(1, line)
+	case strings.Contains(lower, "level=warn"), strings.Contains(line, "⚠️"):
+		err = w.log.Warning(1, line)
+	default:
+		err = w.log.Info(1, line)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func logOutput(name string) io.Writer {
+	if isService, err := svc.IsWindowsService(); err != nil || !isService {
+		return os.Stderr
+	}
+	log, err := eventlog.Open(name)
+	if err != nil {
+		return os.Stderr
+	}
+	return &eventLogWriter{log: log}
+}
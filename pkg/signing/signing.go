@@ -0,0 +1,208 @@
+// Package signing - Gateway ↔ Nautilus 마스터 구간 요청/응답 Ed25519 서명
+//
+// 요청 서명 대상: METHOD \n PATH?QUERY \n TIMESTAMP \n NONCE \n SHA256(BODY)
+// 응답 서명 대상: STATUS \n REQUEST_NONCE \n TIMESTAMP \n SHA256(BODY)
+// 응답은 요청 nonce에 묶여 있어 다른 요청의 응답을 재사용할 수 없습니다.
+// watch 응답은 본문을 버퍼링하지 않고 프레임마다 서명합니다 (stream.go).
+//
+// Gateway(서명/응답 검증)와 Nautilus 마스터(요청 검증/응답 서명)가 같은 구현을 사용합니다.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 서명 헤더
+const (
+	HeaderTimestamp = "X-Daas-Signature-Timestamp"
+	HeaderNonce     = "X-Daas-Signature-Nonce"
+	HeaderSignature = "X-Daas-Signature"
+
+	// MaxAge - 서명 유효 시간 (시계 오차 포함)
+	MaxAge = 5 * time.Minute
+)
+
+// ParsePrivateKey - hex 인코딩된 32바이트 seed 또는 64바이트 개인키 파싱
+func ParsePrivateKey(hexKey string) (ed25519.PrivateKey, error) {
+	raw, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key encoding: %v", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid signing key length %d", len(raw))
+	}
+}
+
+// ParsePublicKey - hex 인코딩된 32바이트 공개키 파싱
+func ParsePublicKey(hexKey string) (ed25519.PublicKey, error) {
+	raw, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %v", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length %d", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func requestPayload(method, uri, timestamp, nonce string, body []byte) []byte {
+	digest := sha256.Sum256(body)
+	return []byte(fmt.Sprintf("%s\n%s\n%s\n%s\n%s", method, uri, timestamp, nonce, hex.EncodeToString(digest[:])))
+}
+
+func responsePayload(status int, requestNonce, timestamp string, body []byte) []byte {
+	digest := sha256.Sum256(body)
+	return []byte(fmt.Sprintf("%d\n%s\n%s\n%s", status, requestNonce, timestamp, hex.EncodeToString(digest[:])))
+}
+
+// SignFunc - 응답 서명 함수 (마스터는 엔클레이브에 봉인된 키로 서명)
+type SignFunc func(message []byte) []byte
+
+// KeySigner - Ed25519 개인키로 서명하는 SignFunc
+func KeySigner(key ed25519.PrivateKey) SignFunc {
+	return func(message []byte) []byte {
+		return ed25519.Sign(key, message)
+	}
+}
+
+// SignRequest - 요청에 서명 헤더 추가, 응답 검증에 필요한 nonce 반환
+func SignRequest(key ed25519.PrivateKey, req *http.Request, body []byte) (string, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	signature := ed25519.Sign(key, requestPayload(req.Method, req.URL.RequestURI(), timestamp, nonce, body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, hex.EncodeToString(signature))
+	return nonce, nil
+}
+
+// VerifyResponse - 응답 서명 검증 (요청 nonce와 일치해야 함)
+func VerifyResponse(key ed25519.PublicKey, resp *http.Response, requestNonce string, body []byte) error {
+	return VerifyResponseHeader(key, resp.Header, resp.StatusCode, requestNonce, body)
+}
+
+// VerifyResponseHeader - 응답 헤더의 서명 검증 (http.Response 없이 상태 코드와 헤더만 있을 때)
+func VerifyResponseHeader(key ed25519.PublicKey, header http.Header, status int, requestNonce string, body []byte) error {
+	timestamp := header.Get(HeaderTimestamp)
+	if header.Get(HeaderNonce) != requestNonce {
+		return fmt.Errorf("response nonce does not match request")
+	}
+	if _, err := checkFreshness(timestamp); err != nil {
+		return err
+	}
+
+	signature, err := hex.DecodeString(header.Get(HeaderSignature))
+	if err != nil || !ed25519.Verify(key, responsePayload(status, requestNonce, timestamp, body), signature) {
+		return fmt.Errorf("invalid response signature")
+	}
+	return nil
+}
+
+/*
+Verifier - 요청 서명 검증기 (nonce 재사용 차단)
+
+본 nonce는 서명 타임스탬프의 분 단위 버킷에 보관하고, 타임스탬프가 MaxAge 창을 벗어난 버킷은 통째로 버립니다.
+재전송된 요청은 같은 타임스탬프여야 서명이 맞으므로 버킷 하나만 확인하면 되고,
+요청마다 전체 nonce를 순회하지 않아 검증 비용이 QPS와 무관합니다.
+*/
+type Verifier struct {
+	key     ed25519.PublicKey
+	buckets map[int64]map[string]struct{} // 타임스탬프(분) → 본 nonce
+	mutex   sync.Mutex
+}
+
+// nonceBucket - nonce 버킷 단위 (초)
+const nonceBucket = 60
+
+// NewVerifier - 새 Verifier 생성
+func NewVerifier(key ed25519.PublicKey) *Verifier {
+	return &Verifier{
+		key:     key,
+		buckets: make(map[int64]map[string]struct{}),
+	}
+}
+
+// VerifyRequest - 요청 서명 검증
+func (v *Verifier) VerifyRequest(req *http.Request, body []byte) error {
+	timestamp := req.Header.Get(HeaderTimestamp)
+	nonce := req.Header.Get(HeaderNonce)
+	if timestamp == "" || nonce == "" || req.Header.Get(HeaderSignature) == "" {
+		return fmt.Errorf("missing request signature")
+	}
+	unix, err := checkFreshness(timestamp)
+	if err != nil {
+		return err
+	}
+
+	signature, err := hex.DecodeString(req.Header.Get(HeaderSignature))
+	if err != nil || !ed25519.Verify(v.key, requestPayload(req.Method, req.URL.RequestURI(), timestamp, nonce, body), signature) {
+		return fmt.Errorf("invalid request signature")
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	// 마지막 타임스탬프까지 창을 벗어난 버킷 제거 (버킷 수는 창 길이로 제한됨)
+	now := time.Now().Unix()
+	for bucket := range v.buckets {
+		if (bucket+1)*nonceBucket+int64(MaxAge/time.Second) <= now {
+			delete(v.buckets, bucket)
+		}
+	}
+
+	bucket := unix / nonceBucket
+	seen := v.buckets[bucket]
+	if seen == nil {
+		seen = make(map[string]struct{})
+		v.buckets[bucket] = seen
+	}
+	if _, replayed := seen[nonce]; replayed {
+		return fmt.Errorf("replayed request nonce")
+	}
+	seen[nonce] = struct{}{}
+	return nil
+}
+
+// SignResponse - 응답 헤더에 서명 추가 (WriteHeader 전에 호출)
+func SignResponse(key ed25519.PrivateKey, header http.Header, status int, requestNonce string, body []byte) {
+	SignResponseWith(KeySigner(key), header, status, requestNonce, body)
+}
+
+// SignResponseWith - SignResponse와 같으나 서명 함수를 받음 (봉인 키처럼 개인키를 꺼낼 수 없을 때)
+func SignResponseWith(sign SignFunc, header http.Header, status int, requestNonce string, body []byte) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderNonce, requestNonce)
+	header.Set(HeaderSignature, hex.EncodeToString(sign(responsePayload(status, requestNonce, timestamp, body))))
+}
+
+// checkFreshness - 타임스탬프가 MaxAge 창 안인지 확인하고 Unix 초 반환
+func checkFreshness(timestamp string) (int64, error) {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid signature timestamp")
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > MaxAge || age < -MaxAge {
+		return 0, fmt.Errorf("signature timestamp outside allowed window")
+	}
+	return unix, nil
+}
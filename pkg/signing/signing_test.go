@@ -0,0 +1,106 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifierRejectsReplay(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	verifier := NewVerifier(public)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pods?watch=true", nil)
+	if _, err := SignRequest(private, req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.VerifyRequest(req, nil); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if err := verifier.VerifyRequest(req, nil); err == nil {
+		t.Fatal("replayed request accepted")
+	}
+	if err := verifier.VerifyRequest(req, []byte("other body")); err == nil {
+		t.Fatal("request with a different body accepted")
+	}
+
+	// 타임스탬프 창을 벗어난 버킷은 다음 검증 때 통째로 제거
+	stale := time.Now().Add(-2*MaxAge).Unix() / nonceBucket
+	verifier.buckets[stale] = map[string]struct{}{"old": {}}
+	other := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+	SignRequest(private, other, nil)
+	if err := verifier.VerifyRequest(other, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := verifier.buckets[stale]; ok {
+		t.Fatal("expired nonce bucket was not dropped")
+	}
+}
+
+func TestResponseStream(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	events := []string{`{"type":"ADDED"}` + "\n", strings.Repeat("x", maxFrameSize+10) + "\n", `{"type":"DELETED"}` + "\n"}
+
+	record := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		stream := NewResponseStream(rec, KeySigner(private), "nonce-1")
+		stream.Header().Set("Content-Type", "application/json")
+		for _, event := range events {
+			stream.Write([]byte(event))
+			stream.Flush()
+		}
+		if err := stream.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	resp := record().Result()
+	if !IsStream(resp) {
+		t.Fatal("stream header missing")
+	}
+	reader, err := VerifyStream(public, resp, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil || string(body) != strings.Join(events, "") {
+		t.Fatalf("stream body mismatch (%d bytes): %v", len(body), err)
+	}
+
+	if _, err := VerifyStream(public, record().Result(), "nonce-2"); err == nil {
+		t.Fatal("stream accepted for a different request nonce")
+	}
+
+	// 프레임 데이터 변조
+	tampered := record()
+	raw := strings.Replace(tampered.Body.String(), "ADDED", "ADDEE", 1)
+	resp = tampered.Result()
+	resp.Body = io.NopCloser(strings.NewReader(raw))
+	reader, _ = VerifyStream(public, resp, "nonce-1")
+	if _, err := io.ReadAll(reader); err == nil {
+		t.Fatal("tampered frame accepted")
+	}
+
+	// 종료 프레임 없이 끊긴 스트림
+	truncated := record()
+	raw = truncated.Body.String()
+	resp = truncated.Result()
+	resp.Body = io.NopCloser(strings.NewReader(raw[:strings.LastIndex(raw, "0 ")]))
+	reader, _ = VerifyStream(public, resp, "nonce-1")
+	if _, err := io.ReadAll(reader); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated stream: got %v", err)
+	}
+
+	// 스트림 헤더를 뗀 응답은 일반 응답으로도 검증되지 않음
+	stripped := record().Result()
+	stripped.Header.Del(HeaderStream)
+	if err := VerifyResponse(public, stripped, "nonce-1", nil); err == nil {
+		t.Fatal("stream signature accepted as a plain response")
+	}
+}
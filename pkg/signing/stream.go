@@ -0,0 +1,194 @@
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+스트리밍(watch) 응답 서명
+
+watch 응답은 끝나지 않으므로 본문 전체에 서명할 수 없습니다. 마스터는 본문을 프레임으로 나눠 보냅니다.
+
+	헤더: X-Daas-Signature-Stream: frames-v1, 응답 서명은 본문 대신 스트림 표시(streamMarker)에 대해 계산
+	프레임: <길이(hex)> <서명(hex)>\n<데이터>
+	프레임 서명 대상: REQUEST_NONCE \n 순번 \n SHA256(데이터)
+	종료: 길이 0 프레임
+
+프레임 순번이 서명에 들어가므로 프레임을 빼거나 순서를 바꾸면 검증에 실패하고,
+종료 프레임 없이 끊긴 스트림은 io.ErrUnexpectedEOF로 드러납니다.
+*/
+const (
+	HeaderStream = "X-Daas-Signature-Stream"
+	StreamFormat = "frames-v1"
+
+	// maxFrameSize - 프레임 하나의 최대 데이터 크기 (넘으면 나눠 보냄)
+	maxFrameSize = 64 * 1024
+)
+
+// streamMarker - 스트리밍 응답 헤더 서명에 본문 대신 넣는 값 (스트림 헤더를 떼면 일반 응답으로도 검증되지 않음)
+var streamMarker = []byte("stream:" + StreamFormat)
+
+func framePayload(requestNonce string, seq uint64, data []byte) []byte {
+	digest := sha256.Sum256(data)
+	return []byte(fmt.Sprintf("%s\n%d\n%s", requestNonce, seq, hex.EncodeToString(digest[:])))
+}
+
+// ResponseStream - 응답을 버퍼링하지 않고 Flush마다 서명한 프레임으로 보내는 http.ResponseWriter
+type ResponseStream struct {
+	w       http.ResponseWriter
+	sign    SignFunc
+	nonce   string
+	status  int
+	seq     uint64
+	pending bytes.Buffer
+}
+
+// NewResponseStream - 요청 nonce에 묶인 스트리밍 응답 작성기 (핸들러가 끝나면 Close 호출)
+func NewResponseStream(w http.ResponseWriter, sign SignFunc, requestNonce string) *ResponseStream {
+	return &ResponseStream{w: w, sign: sign, nonce: requestNonce}
+}
+
+func (s *ResponseStream) Header() http.Header {
+	return s.w.Header()
+}
+
+// WriteHeader - 상태 코드와 스트림 표시에 서명하고 헤더 전송
+func (s *ResponseStream) WriteHeader(status int) {
+	if s.status != 0 {
+		return
+	}
+	s.status = status
+	header := s.w.Header()
+	header.Del("Content-Length")
+	header.Set(HeaderStream, StreamFormat)
+	SignResponseWith(s.sign, header, status, s.nonce, streamMarker)
+	s.w.WriteHeader(status)
+}
+
+// Write - 다음 Flush까지 모음 (최대 프레임 크기를 넘는 부분은 바로 전송)
+func (s *ResponseStream) Write(p []byte) (int, error) {
+	s.WriteHeader(http.StatusOK)
+	s.pending.Write(p)
+	for s.pending.Len() >= maxFrameSize {
+		if err := s.writeFrame(s.pending.Next(maxFrameSize)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush - 모인 데이터를 프레임 하나로 보내고 클라이언트로 flush
+func (s *ResponseStream) Flush() {
+	s.WriteHeader(http.StatusOK)
+	if s.pending.Len() > 0 {
+		s.writeFrame(s.pending.Next(s.pending.Len()))
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close - 남은 데이터와 종료 프레임 전송
+func (s *ResponseStream) Close() error {
+	s.WriteHeader(http.StatusOK)
+	if s.pending.Len() > 0 {
+		if err := s.writeFrame(s.pending.Next(s.pending.Len())); err != nil {
+			return err
+		}
+	}
+	err := s.writeFrame(nil)
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return err
+}
+
+func (s *ResponseStream) writeFrame(data []byte) error {
+	signature := s.sign(framePayload(s.nonce, s.seq, data))
+	s.seq++
+	if _, err := fmt.Fprintf(s.w, "%x %s\n", len(data), hex.EncodeToString(signature)); err != nil {
+		return err
+	}
+	_, err := s.w.Write(data)
+	return err
+}
+
+// IsStream - 프레임 단위로 서명된 스트리밍 응답인지
+func IsStream(resp *http.Response) bool {
+	return resp.Header.Get(HeaderStream) != ""
+}
+
+// VerifyStream - 스트리밍 응답 헤더 서명을 확인하고, 프레임마다 서명을 검증하며 데이터를 읽는 Reader 반환
+func VerifyStream(key ed25519.PublicKey, resp *http.Response, requestNonce string) (io.Reader, error) {
+	if format := resp.Header.Get(HeaderStream); format != StreamFormat {
+		return nil, fmt.Errorf("unsupported response stream format %q", format)
+	}
+	if err := VerifyResponseHeader(key, resp.Header, resp.StatusCode, requestNonce, streamMarker); err != nil {
+		return nil, err
+	}
+	return &streamReader{key: key, nonce: requestNonce, source: bufio.NewReader(resp.Body)}, nil
+}
+
+// streamReader - 검증된 프레임 데이터만 돌려주는 Reader
+type streamReader struct {
+	key    ed25519.PublicKey
+	nonce  string
+	source *bufio.Reader
+	seq    uint64
+	frame  []byte
+	err    error
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	for len(r.frame) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.frame, r.err = r.next()
+	}
+	n := copy(p, r.frame)
+	r.frame = r.frame[n:]
+	return n, nil
+}
+
+// next - 프레임 하나를 읽고 검증 (종료 프레임이면 io.EOF)
+func (r *streamReader) next() ([]byte, error) {
+	line, err := r.source.ReadSlice('\n')
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid response stream frame: %v", err)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid response stream frame header")
+	}
+	size, err := strconv.ParseUint(fields[0], 16, 32)
+	if err != nil || size > maxFrameSize {
+		return nil, fmt.Errorf("invalid response stream frame length %q", fields[0])
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.source, data); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	signature, err := hex.DecodeString(fields[1])
+	if err != nil || !ed25519.Verify(r.key, framePayload(r.nonce, r.seq, data), signature) {
+		return nil, fmt.Errorf("invalid response stream frame signature")
+	}
+	r.seq++
+	if size == 0 {
+		return nil, io.EOF
+	}
+	return data, nil
+}
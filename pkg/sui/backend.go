@@ -0,0 +1,360 @@
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+)
+
+// BackendName is the name the Sui backend is registered under
+const BackendName = "sui"
+
+const (
+	defaultBackendGasBudget = 10000000
+	defaultEventPoll        = 3 * time.Second
+	eventPageSize           = 50
+)
+
+func init() {
+	chain.Register(BackendName, func(config chain.Config) (chain.Backend, error) {
+		return NewBackend(NewReadOnlyClient(config.Endpoint, config.Package), config.Sender, config.PrivateKey), nil
+	})
+}
+
+// Backend implements chain.Backend on top of SuiClient
+type Backend struct {
+	client *SuiClient
+	sender string
+	signer string
+}
+
+// NewBackend wraps an existing client so the backend shares its metrics and
+// cache. Without sender and signer the backend is read-only.
+func NewBackend(client *SuiClient, sender, signer string) *Backend {
+	return &Backend{client: client, sender: sender, signer: signer}
+}
+
+// Client returns the underlying Sui client for Sui-specific RPCs
+func (b *Backend) Client() *SuiClient {
+	return b.client
+}
+
+// Name implements chain.Backend
+func (b *Backend) Name() string {
+	return BackendName
+}
+
+// SubmitTx builds the calls into one programmable transaction block against
+// the client's package and executes it
+func (b *Backend) SubmitTx(ctx context.Context, tx *chain.Tx) (*chain.TxResult, error) {
+	if b.sender == "" || b.signer == "" {
+		return nil, chain.ErrReadOnly
+	}
+	if tx == nil || len(tx.Calls) == 0 {
+		return nil, fmt.Errorf("transaction has no calls")
+	}
+
+	gasBudget := tx.GasBudget
+	if gasBudget == 0 {
+		gasBudget = defaultBackendGasBudget
+	}
+	ptb := NewTransactionBuilder(b.sender, gasBudget)
+	for _, call := range tx.Calls {
+		args := make([]interface{}, len(call.Args))
+		for i, arg := range call.Args {
+			if ref, ok := arg.(chain.ResultRef); ok {
+				arg = Argument{"Result": ref.Index}
+			}
+			args[i] = arg
+		}
+		ptb.MoveCall(b.client.contractPackage, call.Module, call.Function, args...)
+	}
+	txBytes, err := ptb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.client.ExecuteTransactionBlock(ctx, txBytes, []string{b.signer})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &chain.TxResult{Digest: response.Digest}
+	for _, change := range response.ObjectChanges {
+		if change.Type == "created" {
+			result.Created = append(result.Created, chain.Object{ID: change.ObjectID, Type: change.ObjectType})
+		}
+	}
+	if len(response.Events) > 0 {
+		raw, err := json.Marshal(response.Events)
+		if err == nil {
+			var events []Event
+			if json.Unmarshal(raw, &events) == nil {
+				for _, event := range events {
+					result.Events = append(result.Events, event.toChain())
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// u64 accepts Sui u64 values encoded either as strings or as numbers
+type u64 uint64
+
+func (v *u64) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		*v = 0
+		return nil
+	}
+	parsed, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("not a u64 value: %s", data)
+	}
+	*v = u64(parsed)
+	return nil
+}
+
+// objectResponse is the result of sui_getObject. Missing, deleted and
+// wrapped objects carry error.code (notExists, deleted) instead of data.
+type objectResponse struct {
+	Data *struct {
+		ObjectID string          `json:"objectId"`
+		Version  u64             `json:"version"`
+		Type     string          `json:"type"`
+		Content  json.RawMessage `json:"content"`
+	} `json:"data"`
+	Error *struct {
+		Code     string `json:"code"`
+		ObjectID string `json:"object_id"`
+	} `json:"error"`
+}
+
+// objectContent is a moveObject's content; older fullnodes put the fields
+// directly in content instead of under content.fields
+type objectContent struct {
+	DataType string                 `json:"dataType"`
+	Type     string                 `json:"type"`
+	Fields   map[string]interface{} `json:"fields"`
+}
+
+// QueryObject reads an object directly from the fullnode, bypassing the
+// cache, so callers watching for slashing or deletion see the latest state
+func (b *Backend) QueryObject(ctx context.Context, objectID string) (*chain.Object, error) {
+	var response objectResponse
+	err := b.client.Call(ctx, "sui_getObject", []interface{}{
+		objectID,
+		map[string]bool{"showType": true, "showContent": true},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		switch response.Error.Code {
+		case "deleted":
+			return nil, fmt.Errorf("%w: %s", chain.ErrDeleted, objectID)
+		case "notExists":
+			return nil, fmt.Errorf("%w: %s", chain.ErrNotFound, objectID)
+		default:
+			return nil, fmt.Errorf("object %s: %s", objectID, response.Error.Code)
+		}
+	}
+	if response.Data == nil || len(response.Data.Content) == 0 || string(response.Data.Content) == "null" {
+		return nil, fmt.Errorf("object %s has no content", objectID)
+	}
+
+	var content objectContent
+	if err := json.Unmarshal(response.Data.Content, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse object %s: %v", objectID, err)
+	}
+	if content.DataType != "" && content.DataType != "moveObject" {
+		return nil, fmt.Errorf("object %s is not a Move object: %s", objectID, content.DataType)
+	}
+	if content.Fields == nil {
+		if err := json.Unmarshal(response.Data.Content, &content.Fields); err != nil {
+			return nil, fmt.Errorf("failed to parse object %s: %v", objectID, err)
+		}
+		delete(content.Fields, "dataType")
+		delete(content.Fields, "type")
+	}
+
+	object := &chain.Object{
+		ID:      response.Data.ObjectID,
+		Type:    content.Type,
+		Version: uint64(response.Data.Version),
+		Fields:  content.Fields,
+	}
+	if object.Type == "" {
+		object.Type = response.Data.Type
+	}
+	return object, nil
+}
+
+// ValidateStake reads the stake object when its ID is known and otherwise
+// looks the stake up by node
+func (b *Backend) ValidateStake(ctx context.Context, ref chain.StakeRef, minStake uint64) (*chain.Stake, error) {
+	if ref.ObjectID == "" {
+		info, err := b.client.queryStakeInfo(ctx, ref.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		return chain.CheckStake(&chain.Stake{
+			NodeID:   ref.NodeID,
+			ObjectID: info.ObjectID,
+			Amount:   info.StakeAmount,
+			Status:   info.Status,
+		}, minStake)
+	}
+
+	object, err := b.QueryObject(ctx, ref.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := fieldU64(object.Fields["stake_amount"])
+	if !ok {
+		return nil, fmt.Errorf("stake object %s has no stake_amount (type %s)", ref.ObjectID, object.Type)
+	}
+	stake := &chain.Stake{NodeID: ref.NodeID, ObjectID: object.ID, Amount: amount, Version: object.Version}
+	stake.Status, _ = object.Fields["status"].(string)
+	if stake.Status == "" {
+		stake.Status = "active" // a StakeProof is valid for as long as it exists
+	}
+	if nodeID, _ := object.Fields["node_id"].(string); nodeID != "" {
+		stake.NodeID = nodeID
+	}
+	return chain.CheckStake(stake, minStake)
+}
+
+// fieldU64 reads a Move u64 field, which the RPC renders as a string
+func fieldU64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		return parsed, err == nil
+	case float64:
+		return uint64(v), v >= 0
+	}
+	return 0, false
+}
+
+// SubscribeEvents polls suix_queryEvents. The first poll reads the most
+// recent page so a restarted consumer picks up where the chain is rather
+// than replaying the package's whole history; later polls follow the cursor.
+// With filter.After the first poll instead drains everything after that
+// event, so a consumer that checkpointed its position misses nothing.
+func (b *Backend) SubscribeEvents(ctx context.Context, filter chain.EventFilter) (<-chan chain.EventBatch, error) {
+	pkg := filter.Package
+	if pkg == "" {
+		pkg = b.client.contractPackage
+	}
+	query := map[string]interface{}{"Package": pkg}
+	if filter.Module != "" {
+		query = map[string]interface{}{
+			"MoveModule": map[string]string{"package": pkg, "module": filter.Module},
+		}
+	}
+	interval := filter.PollInterval
+	if interval <= 0 {
+		interval = defaultEventPoll
+	}
+
+	batches := make(chan chain.EventBatch)
+	go func() {
+		defer close(batches)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var cursor *EventCursor
+		seeded := false
+		if filter.After != nil {
+			cursor = &EventCursor{TxDigest: filter.After.TxDigest, EventSeq: filter.After.Seq}
+			seeded = true
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var batch chain.EventBatch
+			// Read the height first: once the query succeeds the backend has
+			// caught up to at least that checkpoint
+			height, heightErr := b.client.LatestCheckpoint(ctx)
+			if !seeded {
+				batch.Events, cursor, batch.Err = b.latestEvents(ctx, query)
+				seeded = batch.Err == nil
+			} else {
+				batch.Events, cursor, batch.Err = b.eventsAfter(ctx, query, cursor)
+			}
+			if batch.Err == nil && heightErr == nil {
+				batch.Height = height
+			}
+
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return batches, nil
+}
+
+// latestEvents returns the newest page in ascending order and its cursor
+func (b *Backend) latestEvents(ctx context.Context, query map[string]interface{}) ([]chain.Event, *EventCursor, error) {
+	var page EventPage
+	if err := b.client.Call(ctx, "suix_queryEvents", []interface{}{query, nil, eventPageSize, true}, &page); err != nil {
+		return nil, nil, err
+	}
+	if len(page.Data) == 0 {
+		return nil, nil, nil
+	}
+
+	events := make([]chain.Event, 0, len(page.Data))
+	for i := len(page.Data) - 1; i >= 0; i-- {
+		events = append(events, page.Data[i].toChain())
+	}
+	newest := page.Data[0].ID
+	return events, &EventCursor{TxDigest: newest.TxDigest, EventSeq: newest.EventSeq}, nil
+}
+
+// eventsAfter drains every page after cursor
+func (b *Backend) eventsAfter(ctx context.Context, query map[string]interface{}, cursor *EventCursor) ([]chain.Event, *EventCursor, error) {
+	var events []chain.Event
+	for {
+		page, err := b.client.QueryEvents(ctx, query, cursor, eventPageSize)
+		if err != nil {
+			return events, cursor, err
+		}
+		for _, event := range page.Data {
+			events = append(events, event.toChain())
+		}
+		if page.NextCursor != nil {
+			cursor = page.NextCursor
+		}
+		if !page.HasNextPage || len(page.Data) == 0 {
+			return events, cursor, nil
+		}
+	}
+}
+
+func (e Event) toChain() chain.Event {
+	timestamp, _ := strconv.ParseInt(e.TimestampMs, 10, 64)
+	return chain.Event{
+		TxDigest:    e.ID.TxDigest,
+		Seq:         e.ID.EventSeq,
+		Package:     e.PackageID,
+		Module:      e.TransactionModule,
+		Type:        e.Type,
+		Sender:      e.Sender,
+		Data:        e.ParsedJSON,
+		TimestampMs: timestamp,
+	}
+}
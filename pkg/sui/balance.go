@@ -0,0 +1,89 @@
+package sui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MistPerSui is the number of MIST in one SUI.
+const MistPerSui = 1_000_000_000
+
+// SuiCoinType is the coin type gas is paid in.
+const SuiCoinType = "0x2::sui::SUI"
+
+// Balance returns the total SUI balance of owner in MIST.
+func (c *SuiClient) Balance(ctx context.Context, owner string) (uint64, error) {
+	var balance struct {
+		TotalBalance string `json:"totalBalance"`
+	}
+	if err := c.Call(ctx, "suix_getBalance", []interface{}{owner, SuiCoinType}, &balance); err != nil {
+		return 0, err
+	}
+	total, err := strconv.ParseUint(balance.TotalBalance, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid balance %q: %v", balance.TotalBalance, err)
+	}
+	return total, nil
+}
+
+// FaucetURL returns the public faucet for the network an RPC endpoint belongs
+// to, or "" for mainnet and endpoints whose network is unknown.
+func FaucetURL(rpcEndpoint string) string {
+	switch {
+	case strings.Contains(rpcEndpoint, "testnet"):
+		return "https://faucet.testnet.sui.io/v2/gas"
+	case strings.Contains(rpcEndpoint, "devnet"):
+		return "https://faucet.devnet.sui.io/v2/gas"
+	}
+	return ""
+}
+
+// IsMainnet reports whether an RPC endpoint points at Sui mainnet, where no
+// faucet exists and callers must never request one.
+func IsMainnet(rpcEndpoint string) bool {
+	return strings.Contains(rpcEndpoint, "mainnet")
+}
+
+// RequestFaucet asks a Sui faucet to send gas coins to recipient. Public
+// faucets rate-limit per address and IP, so callers should space requests
+// out; a 429 is returned as an error like any other non-2xx status.
+func RequestFaucet(ctx context.Context, client *http.Client, faucetURL, recipient string) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"FixedAmountRequest": map[string]string{"recipient": recipient},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, faucetURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("faucet request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("faucet returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	// v2 faucets answer {"status": "Success"} or {"status": {"Failure": ...}}; v1 uses "error"
+	var result struct {
+		Status json.RawMessage `json:"status"`
+		Error  string          `json:"error"`
+	}
+	if json.Unmarshal(data, &result) == nil {
+		if result.Error != "" {
+			return fmt.Errorf("faucet error: %s", result.Error)
+		}
+		if len(result.Status) > 0 && string(result.Status) != `"Success"` {
+			return fmt.Errorf("faucet error: %s", result.Status)
+		}
+	}
+	return nil
+}
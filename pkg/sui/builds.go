@@ -0,0 +1,86 @@
+package sui
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AllowedBuild is one entry of the on-chain BuildAllowlist
+// (build_allowlist.move), keyed by the SBOM digest of the binary.
+type AllowedBuild struct {
+	Component  string    `json:"component"`
+	Version    string    `json:"version"`
+	Commit     string    `json:"commit"`
+	Builder    string    `json:"builder"`
+	SBOMDigest string    `json:"sbom_digest"`
+	AllowedAt  time.Time `json:"allowed_at"`
+}
+
+// AllowedBuild looks up a build by SBOM digest in the BuildAllowlist shared
+// object. It returns nil without an error when the build is not allowed
+// (never added, or revoked).
+func (c *SuiClient) AllowedBuild(ctx context.Context, allowlistID, sbomDigest string) (*AllowedBuild, error) {
+	var allowlist struct {
+		Data struct {
+			Content struct {
+				Fields struct {
+					Builds struct {
+						Fields struct {
+							ID struct {
+								ID string `json:"id"`
+							} `json:"id"`
+						} `json:"fields"`
+					} `json:"builds"`
+				} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	options := map[string]bool{"showContent": true}
+	if err := c.Call(ctx, "sui_getObject", []interface{}{allowlistID, options}, &allowlist); err != nil {
+		return nil, fmt.Errorf("failed to fetch build allowlist: %w", err)
+	}
+	tableID := allowlist.Data.Content.Fields.Builds.Fields.ID.ID
+	if tableID == "" {
+		return nil, fmt.Errorf("build allowlist %s has no builds table", allowlistID)
+	}
+
+	var entry struct {
+		Data *struct {
+			Content struct {
+				Fields struct {
+					Value struct {
+						Fields struct {
+							Component  string `json:"component"`
+							Version    string `json:"version"`
+							Commit     string `json:"commit"`
+							Builder    string `json:"builder"`
+							SBOMDigest string `json:"sbom_digest"`
+							AllowedAt  string `json:"allowed_at"`
+						} `json:"fields"`
+					} `json:"value"`
+				} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+		// A missing key is reported in the result, not as an RPC error
+		Error *struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	name := map[string]interface{}{"type": "0x1::string::String", "value": sbomDigest}
+	if err := c.Call(ctx, "suix_getDynamicFieldObject", []interface{}{tableID, name}, &entry); err != nil {
+		return nil, fmt.Errorf("failed to fetch allowed build: %w", err)
+	}
+	if entry.Error != nil || entry.Data == nil {
+		return nil, nil
+	}
+	fields := entry.Data.Content.Fields.Value.Fields
+	return &AllowedBuild{
+		Component:  fields.Component,
+		Version:    fields.Version,
+		Commit:     fields.Commit,
+		Builder:    fields.Builder,
+		SBOMDigest: fields.SBOMDigest,
+		AllowedAt:  unixMillis(fields.AllowedAt),
+	}, nil
+}
@@ -0,0 +1,427 @@
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SuiCache caches on-chain objects and values derived from them. Entries
+// expire after a per-type TTL and are invalidated early when a transaction
+// mutates, wraps or deletes one of the objects they were built from.
+type SuiCache struct {
+	entries  map[string]*cacheEntry
+	byObject map[string]map[string]struct{} // object ID -> cache keys derived from it
+	typeTTL  map[string]time.Duration
+	ttl      time.Duration
+	stats    map[string]*CacheTypeStats
+	mu       sync.RWMutex
+}
+
+type cacheEntry struct {
+	value      interface{}
+	objectType string
+	objectIDs  []string
+	expiresAt  time.Time
+}
+
+// CacheTypeStats tracks cache effectiveness for one object type
+type CacheTypeStats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"`
+	Expirations   int64 `json:"expirations"`
+	Entries       int   `json:"entries"`
+}
+
+// CacheStats summarizes cache effectiveness across all object types
+type CacheStats struct {
+	Hits          int64                      `json:"hits"`
+	Misses        int64                      `json:"misses"`
+	Invalidations int64                      `json:"invalidations"`
+	Entries       int                        `json:"entries"`
+	ByType        map[string]*CacheTypeStats `json:"by_type"`
+}
+
+// NewSuiCache creates a cache with the given default TTL
+func NewSuiCache(ttl time.Duration) *SuiCache {
+	return &SuiCache{
+		entries:  make(map[string]*cacheEntry),
+		byObject: make(map[string]map[string]struct{}),
+		typeTTL:  make(map[string]time.Duration),
+		ttl:      ttl,
+		stats:    make(map[string]*CacheTypeStats),
+	}
+}
+
+// SetTypeTTL overrides the TTL for entries of one object type
+func (sc *SuiCache) SetTypeTTL(objectType string, ttl time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.typeTTL[objectType] = ttl
+}
+
+// Get returns a live entry and records a hit or miss for the object type
+func (sc *SuiCache) Get(key, objectType string) (interface{}, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	stats := sc.statsLocked(objectType)
+	entry, exists := sc.entries[key]
+	if !exists {
+		stats.Misses++
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		sc.removeLocked(key)
+		stats.Expirations++
+		stats.Misses++
+		return nil, false
+	}
+
+	stats.Hits++
+	return entry.value, true
+}
+
+// Set stores a value derived from the given objects
+func (sc *SuiCache) Set(key, objectType string, objectIDs []string, value interface{}) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	ttl := sc.ttl
+	if typeTTL, ok := sc.typeTTL[objectType]; ok {
+		ttl = typeTTL
+	}
+	sc.setLocked(key, objectType, objectIDs, value, time.Now().Add(ttl))
+}
+
+func (sc *SuiCache) setLocked(key, objectType string, objectIDs []string, value interface{}, expiresAt time.Time) {
+	sc.removeLocked(key)
+	sc.entries[key] = &cacheEntry{
+		value:      value,
+		objectType: objectType,
+		objectIDs:  objectIDs,
+		expiresAt:  expiresAt,
+	}
+	for _, objectID := range objectIDs {
+		if sc.byObject[objectID] == nil {
+			sc.byObject[objectID] = make(map[string]struct{})
+		}
+		sc.byObject[objectID][key] = struct{}{}
+	}
+	sc.statsLocked(objectType).Entries++
+}
+
+// InvalidateObject drops every entry derived from the object
+func (sc *SuiCache) InvalidateObject(objectID string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for key := range sc.byObject[objectID] {
+		if entry, exists := sc.entries[key]; exists {
+			sc.statsLocked(entry.objectType).Invalidations++
+		}
+		sc.removeLocked(key)
+	}
+}
+
+// InvalidateType drops every entry of an object type, e.g. when a new object
+// of that type is created and type-wide query results become stale
+func (sc *SuiCache) InvalidateType(objectType string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for key, entry := range sc.entries {
+		if entry.objectType == objectType {
+			sc.statsLocked(objectType).Invalidations++
+			sc.removeLocked(key)
+		}
+	}
+}
+
+// Stats returns a snapshot of cache hit/miss counters
+func (sc *SuiCache) Stats() CacheStats {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	stats := CacheStats{
+		Entries: len(sc.entries),
+		ByType:  make(map[string]*CacheTypeStats, len(sc.stats)),
+	}
+	for objectType, typeStats := range sc.stats {
+		snapshot := *typeStats
+		stats.ByType[objectType] = &snapshot
+		stats.Hits += typeStats.Hits
+		stats.Misses += typeStats.Misses
+		stats.Invalidations += typeStats.Invalidations
+	}
+	return stats
+}
+
+func (sc *SuiCache) statsLocked(objectType string) *CacheTypeStats {
+	stats, exists := sc.stats[objectType]
+	if !exists {
+		stats = &CacheTypeStats{}
+		sc.stats[objectType] = stats
+	}
+	return stats
+}
+
+func (sc *SuiCache) removeLocked(key string) {
+	entry, exists := sc.entries[key]
+	if !exists {
+		return
+	}
+
+	delete(sc.entries, key)
+	sc.statsLocked(entry.objectType).Entries--
+	for _, objectID := range entry.objectIDs {
+		if keys := sc.byObject[objectID]; keys != nil {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(sc.byObject, objectID)
+			}
+		}
+	}
+}
+
+const (
+	stakeCacheType  = "StakeInfo"
+	objectCacheType = "object"
+)
+
+// CachedObject is a GetObject cache entry carried across a restart.
+type CachedObject struct {
+	Object    *SuiObject `json:"object"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// CachedStake is a ValidateStake cache entry carried across a restart.
+type CachedStake struct {
+	Stake     *StakeInfo `json:"stake"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// CacheSnapshot is the part of the cache whose values can be serialized:
+// objects and stake lookups. Derived values of other types are rebuilt.
+type CacheSnapshot struct {
+	Objects []CachedObject `json:"objects,omitempty"`
+	Stakes  []CachedStake  `json:"stakes,omitempty"`
+}
+
+// CacheSnapshot returns the live object and stake entries.
+func (c *SuiClient) CacheSnapshot() CacheSnapshot {
+	sc := c.cache
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	var snapshot CacheSnapshot
+	now := time.Now()
+	for _, entry := range sc.entries {
+		if now.After(entry.expiresAt) {
+			continue
+		}
+		switch value := entry.value.(type) {
+		case *SuiObject:
+			snapshot.Objects = append(snapshot.Objects, CachedObject{Object: value, ExpiresAt: entry.expiresAt})
+		case *StakeInfo:
+			snapshot.Stakes = append(snapshot.Stakes, CachedStake{Stake: value, ExpiresAt: entry.expiresAt})
+		}
+	}
+	return snapshot
+}
+
+// RestoreCache loads a snapshot taken by CacheSnapshot. Entries keep their
+// original expiry, so a restart never extends how long a value is trusted;
+// already expired entries are skipped. It returns how many were restored.
+func (c *SuiClient) RestoreCache(snapshot CacheSnapshot) int {
+	sc := c.cache
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	restored := 0
+	now := time.Now()
+	for _, cached := range snapshot.Objects {
+		if cached.Object == nil || cached.Object.ObjectID == "" || now.After(cached.ExpiresAt) {
+			continue
+		}
+		sc.setLocked("object:"+cached.Object.ObjectID, objectCacheType, []string{cached.Object.ObjectID}, cached.Object, cached.ExpiresAt)
+		restored++
+	}
+	for _, cached := range snapshot.Stakes {
+		if cached.Stake == nil || cached.Stake.NodeID == "" || now.After(cached.ExpiresAt) {
+			continue
+		}
+		var objectIDs []string
+		if cached.Stake.ObjectID != "" {
+			objectIDs = []string{cached.Stake.ObjectID}
+		}
+		sc.setLocked("stake:"+cached.Stake.NodeID, stakeCacheType, objectIDs, cached.Stake, cached.ExpiresAt)
+		restored++
+	}
+	return restored
+}
+
+// ObjectChange is one entry of a transaction's objectChanges
+type ObjectChange struct {
+	Type       string `json:"type"` // created, mutated, deleted, wrapped, transferred, published
+	ObjectID   string `json:"objectId"`
+	ObjectType string `json:"objectType"`
+}
+
+// GetObject returns an object by ID, served from cache until it expires or
+// an observed transaction changes it
+func (c *SuiClient) GetObject(ctx context.Context, objectID string) (*SuiObject, error) {
+	if cached, ok := c.cache.Get("object:"+objectID, objectCacheType); ok {
+		return cached.(*SuiObject), nil
+	}
+
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "sui_getObject",
+		"params": []interface{}{
+			objectID,
+			map[string]bool{"showType": true, "showContent": true},
+		},
+	}
+
+	resp, err := c.makeRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result struct {
+			Data  *SuiObject  `json:"data"`
+			Error interface{} `json:"error"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	if response.Result.Data == nil {
+		return nil, fmt.Errorf("object %s not found: %v", objectID, response.Result.Error)
+	}
+
+	c.cache.Set("object:"+objectID, objectCacheType, []string{objectID}, response.Result.Data)
+	return response.Result.Data, nil
+}
+
+// ApplyObjectChanges invalidates cache entries affected by a transaction
+func (c *SuiClient) ApplyObjectChanges(changes []ObjectChange) {
+	for _, change := range changes {
+		switch change.Type {
+		case "created":
+			c.cache.InvalidateType(change.ObjectType)
+		case "published":
+			// packages are immutable; nothing cached can be stale
+		default:
+			c.cache.InvalidateObject(change.ObjectID)
+		}
+	}
+}
+
+// WatchObjectChanges polls transactions touching the contract package and
+// invalidates cached objects they changed, until ctx is cancelled
+func (c *SuiClient) WatchObjectChanges(ctx context.Context, interval time.Duration) error {
+	// Start from the latest transaction; older changes are covered by TTLs
+	cursor, _, err := c.queryPackageTransactions(ctx, nil, true, 1)
+	if err != nil {
+		return fmt.Errorf("failed to initialize object change watch: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		for {
+			next, changes, err := c.queryPackageTransactions(ctx, cursor, false, 50)
+			if err != nil {
+				// Fall back to TTL-only expiry until the next poll succeeds
+				break
+			}
+			c.ApplyObjectChanges(changes)
+			if next == nil || (cursor != nil && *next == *cursor) {
+				break
+			}
+			cursor = next
+		}
+	}
+}
+
+func (c *SuiClient) queryPackageTransactions(ctx context.Context, cursor *string, descending bool, limit int) (*string, []ObjectChange, error) {
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "suix_queryTransactionBlocks",
+		"params": []interface{}{
+			map[string]interface{}{
+				"filter": map[string]interface{}{
+					"MoveFunction": map[string]interface{}{"package": c.contractPackage},
+				},
+				"options": map[string]bool{"showObjectChanges": true},
+			},
+			cursor,
+			limit,
+			descending,
+		},
+	}
+
+	resp, err := c.makeRequest(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response struct {
+		Result struct {
+			Data []struct {
+				ObjectChanges []ObjectChange `json:"objectChanges"`
+			} `json:"data"`
+			NextCursor *string `json:"nextCursor"`
+		} `json:"result"`
+		Error interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	if response.Error != nil {
+		return nil, nil, fmt.Errorf("query failed: %v", response.Error)
+	}
+
+	var changes []ObjectChange
+	for _, tx := range response.Result.Data {
+		changes = append(changes, tx.ObjectChanges...)
+	}
+
+	next := response.Result.NextCursor
+	if next == nil {
+		next = cursor
+	}
+	return next, changes, nil
+}
+
+// invalidateFromEffects drops cached objects mutated, wrapped or deleted by
+// a transaction this client executed
+func (c *SuiClient) invalidateFromEffects(effects map[string]interface{}) {
+	for _, field := range []string{"mutated", "wrapped", "deleted", "unwrapped"} {
+		refs, _ := effects[field].([]interface{})
+		for _, ref := range refs {
+			refMap, _ := ref.(map[string]interface{})
+			if nested, ok := refMap["reference"].(map[string]interface{}); ok {
+				refMap = nested
+			}
+			if objectID, ok := refMap["objectId"].(string); ok {
+				c.cache.InvalidateObject(objectID)
+			}
+		}
+	}
+}
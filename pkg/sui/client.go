@@ -1,4 +1,8 @@
-// Package sui provides the comprehensive Sui blockchain client for K3s-DaaS
+// Package sui provides the shared Sui blockchain client for K3s-DaaS.
+//
+// It is used by the staker host (worker-release), the Nautilus master
+// (nautilus-release) and the API proxy so that RPC access, transaction
+// building, event queries, metrics and caching behave the same everywhere.
 package sui
 
 import (
@@ -10,47 +14,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"sort"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
-
-	"github.com/k3s-io/k3s/pkg/security"
 )
 
 // SuiClient provides comprehensive Sui blockchain interaction for K3s-DaaS
 type SuiClient struct {
-	endpoint       string
-	httpClient     *http.Client
-	privateKey     ed25519.PrivateKey
-	publicKey      ed25519.PublicKey
-	address        string
+	endpoint        string
+	httpClient      *http.Client
+	privateKey      ed25519.PrivateKey
+	publicKey       ed25519.PublicKey
+	address         string
 	contractPackage string
-	metrics        *SuiMetrics
-	cache          *SuiCache
-	mu             sync.RWMutex
+	metrics         *SuiMetrics
+	cache           *SuiCache
+	mu              sync.RWMutex
 }
 
 // SuiMetrics tracks blockchain interaction performance
 type SuiMetrics struct {
-	RequestCount       int64
-	SuccessCount       int64
-	ErrorCount         int64
-	AvgResponseTime    time.Duration
-	LastRequestTime    time.Time
-	TransactionsFailed int64
+	RequestCount        int64
+	SuccessCount        int64
+	ErrorCount          int64
+	AvgResponseTime     time.Duration
+	LastRequestTime     time.Time
+	TransactionsFailed  int64
 	TransactionsSuccess int64
-	mu                 sync.RWMutex
-}
-
-// SuiCache provides caching for frequently accessed data
-type SuiCache struct {
-	stakes     map[string]*StakeInfo
-	objects    map[string]*SuiObject
-	lastUpdate time.Time
-	ttl        time.Duration
-	mu         sync.RWMutex
+	Cache               CacheStats
+	mu                  sync.RWMutex
 }
 
 // StakeInfo represents staking information for a node
@@ -62,6 +53,7 @@ type StakeInfo struct {
 	CreatedAt     time.Time `json:"created_at"`
 	ExpiresAt     time.Time `json:"expires_at"`
 	LastUpdate    time.Time `json:"last_update"`
+	ObjectID      string    `json:"object_id,omitempty"`
 }
 
 // NodeRegistration represents node registration data
@@ -84,10 +76,25 @@ type SuiObject struct {
 
 // TransactionResponse represents Sui transaction response
 type TransactionResponse struct {
-	Digest  string                 `json:"digest"`
-	Effects map[string]interface{} `json:"effects"`
-	Events  []interface{}          `json:"events"`
-	Status  string                 `json:"status"`
+	Digest        string                 `json:"digest"`
+	Effects       map[string]interface{} `json:"effects"`
+	Events        []interface{}          `json:"events"`
+	ObjectChanges []ObjectChange         `json:"objectChanges,omitempty"`
+	Status        string                 `json:"status"`
+}
+
+// NewReadOnlyClient creates a client for queries and event access only. It has
+// no signing key, so transactions must be signed elsewhere (e.g. the sui CLI).
+func NewReadOnlyClient(endpoint, contractPackage string) *SuiClient {
+	return &SuiClient{
+		endpoint:        endpoint,
+		contractPackage: contractPackage,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		metrics: &SuiMetrics{},
+		cache:   NewSuiCache(5 * time.Minute),
+	}
 }
 
 // NewSuiClient creates a new Sui blockchain client
@@ -119,30 +126,31 @@ func NewSuiClient(endpoint, privateKeyHex, contractPackage string) (*SuiClient,
 			Timeout: 30 * time.Second,
 		},
 		metrics: &SuiMetrics{},
-		cache: &SuiCache{
-			stakes:  make(map[string]*StakeInfo),
-			objects: make(map[string]*SuiObject),
-			ttl:     5 * time.Minute,
-		},
+		cache:   NewSuiCache(5 * time.Minute),
 	}
 
 	return client, nil
 }
 
+// SetTransport replaces the transport used for RPC requests, e.g. to route
+// them through endpoint failover or a caching resolver. Call it before the
+// client is shared.
+func (c *SuiClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // ValidateStake validates if a node has sufficient stake for participation
 func (c *SuiClient) ValidateStake(ctx context.Context, nodeID string, minStake uint64) (*StakeInfo, error) {
 	c.updateMetrics(func() { c.metrics.RequestCount++ })
 
 	// Check cache first
 	if stakeInfo := c.getCachedStake(nodeID); stakeInfo != nil {
-		if time.Since(stakeInfo.LastUpdate) < c.cache.ttl {
-			if stakeInfo.StakeAmount >= minStake && stakeInfo.Status == "active" {
-				c.updateMetrics(func() { c.metrics.SuccessCount++ })
-				return stakeInfo, nil
-			}
-			return nil, fmt.Errorf("insufficient stake: has %d, requires %d",
-				stakeInfo.StakeAmount, minStake)
+		if stakeInfo.StakeAmount >= minStake && stakeInfo.Status == "active" {
+			c.updateMetrics(func() { c.metrics.SuccessCount++ })
+			return stakeInfo, nil
 		}
+		return nil, fmt.Errorf("insufficient stake: has %d, requires %d",
+			stakeInfo.StakeAmount, minStake)
 	}
 
 	// Query blockchain for current stake
@@ -344,6 +352,7 @@ func (c *SuiClient) GetMetrics() *SuiMetrics {
 		LastRequestTime:     c.metrics.LastRequestTime,
 		TransactionsFailed:  c.metrics.TransactionsFailed,
 		TransactionsSuccess: c.metrics.TransactionsSuccess,
+		Cache:               c.cache.Stats(),
 	}
 }
 
@@ -389,6 +398,7 @@ func (c *SuiClient) queryStakeInfo(ctx context.Context, nodeID string) (*StakeIn
 	stakeInfo := &StakeInfo{
 		NodeID:     nodeID,
 		LastUpdate: time.Now(),
+		ObjectID:   obj.ObjectID,
 	}
 
 	if amount, ok := obj.Content["stake_amount"].(float64); ok {
@@ -405,6 +415,18 @@ func (c *SuiClient) queryStakeInfo(ctx context.Context, nodeID string) (*StakeIn
 }
 
 func (c *SuiClient) queryObjects(ctx context.Context, query map[string]interface{}) ([]SuiObject, error) {
+	// Query results are cached per struct type and dropped when any returned
+	// object changes or a new object of the type is created
+	structType, _ := query["StructType"].(string)
+	queryKey, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %v", err)
+	}
+	cacheKey := "query:" + string(queryKey)
+	if cached, ok := c.cache.Get(cacheKey, structType); ok {
+		return cached.([]SuiObject), nil
+	}
+
 	request := map[string]interface{}{
 		"jsonrpc": "2.0",
 		"id":      1,
@@ -427,6 +449,12 @@ func (c *SuiClient) queryObjects(ctx context.Context, query map[string]interface
 		return nil, fmt.Errorf("failed to parse response: %v", err)
 	}
 
+	objectIDs := make([]string, len(response.Result.Data))
+	for i, obj := range response.Result.Data {
+		objectIDs[i] = obj.ObjectID
+	}
+	c.cache.Set(cacheKey, structType, objectIDs, response.Result.Data)
+
 	return response.Result.Data, nil
 }
 
@@ -438,10 +466,10 @@ func (c *SuiClient) executeTransaction(ctx context.Context, moveCall map[string]
 		"method":  "sui_executeTransactionBlock",
 		"params": []interface{}{
 			map[string]interface{}{
-				"sender":      c.address,
-				"moveCall":    moveCall,
-				"gasBudget":   "10000000",
-				"gasPrice":    "1000",
+				"sender":    c.address,
+				"moveCall":  moveCall,
+				"gasBudget": "10000000",
+				"gasPrice":  "1000",
 			},
 		},
 	}
@@ -465,6 +493,9 @@ func (c *SuiClient) executeTransaction(ctx context.Context, moveCall map[string]
 		return nil, fmt.Errorf("transaction failed: %v", response.Error)
 	}
 
+	// Our own writes invalidate cached reads of the objects they touched
+	c.invalidateFromEffects(response.Result.Effects)
+
 	return &response.Result, nil
 }
 
@@ -509,23 +540,18 @@ func (c *SuiClient) makeRequest(ctx context.Context, request map[string]interfac
 }
 
 func (c *SuiClient) getCachedStake(nodeID string) *StakeInfo {
-	c.cache.mu.RLock()
-	defer c.cache.mu.RUnlock()
-
-	if stake, exists := c.cache.stakes[nodeID]; exists {
-		if time.Since(stake.LastUpdate) < c.cache.ttl {
-			return stake
-		}
+	if cached, ok := c.cache.Get("stake:"+nodeID, stakeCacheType); ok {
+		return cached.(*StakeInfo)
 	}
 	return nil
 }
 
 func (c *SuiClient) setCachedStake(nodeID string, stake *StakeInfo) {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	c.cache.stakes[nodeID] = stake
-	c.cache.lastUpdate = time.Now()
+	var objectIDs []string
+	if stake.ObjectID != "" {
+		objectIDs = []string{stake.ObjectID}
+	}
+	c.cache.Set("stake:"+nodeID, stakeCacheType, objectIDs, stake)
 }
 
 func (c *SuiClient) updateMetrics(fn func()) {
@@ -549,16 +575,16 @@ func mapToStruct(data map[string]interface{}, target interface{}) error {
 	return json.Unmarshal(jsonBytes, target)
 }
 
-// Integration with existing security package
-func (c *SuiClient) ValidateSealToken(token *security.SealToken, minStake uint64) error {
-	if token == nil {
-		return fmt.Errorf("nil seal token")
+// ValidateSealToken checks the stake behind a seal token issued to nodeID
+func (c *SuiClient) ValidateSealToken(nodeID string, minStake uint64) error {
+	if nodeID == "" {
+		return fmt.Errorf("seal token has no node ID")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	stakeInfo, err := c.ValidateStake(ctx, token.NodeID, minStake)
+	stakeInfo, err := c.ValidateStake(ctx, nodeID, minStake)
 	if err != nil {
 		return fmt.Errorf("stake validation failed: %v", err)
 	}
@@ -573,4 +599,4 @@ func (c *SuiClient) ValidateSealToken(token *security.SealToken, minStake uint64
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,8 @@
+module github.com/k3s-io/daas-sui
+
+go 1.21
+
+require github.com/k3s-io/daas-chain v0.0.0
+
+// 공용 체인 백엔드 인터페이스 (Sui/mock)
+replace github.com/k3s-io/daas-chain => ../chain
@@ -0,0 +1,133 @@
+package sui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MasterHeartbeatTimeout mirrors MASTER_HEARTBEAT_TIMEOUT_MS in
+// master_registry.move: a master without a heartbeat for this long is inactive.
+const MasterHeartbeatTimeout = 15 * time.Minute
+
+// RegionalMaster is one entry of the on-chain MasterRegistry.
+type RegionalMaster struct {
+	Region        string    `json:"region"`
+	Operator      string    `json:"operator"`
+	Endpoint      string    `json:"endpoint"`
+	PublicKey     string    `json:"public_key"`
+	RegisteredAt  time.Time `json:"registered_at"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// Announced reports whether the master has published an endpoint and key.
+func (m RegionalMaster) Announced() bool {
+	return m.Endpoint != "" && m.PublicKey != ""
+}
+
+// Active reports whether the master announced itself and heartbeated recently.
+func (m RegionalMaster) Active(now time.Time) bool {
+	return m.Announced() && !m.LastHeartbeat.IsZero() && now.Sub(m.LastHeartbeat) <= MasterHeartbeatTimeout
+}
+
+// regionalMasterObject is a masters table entry (dynamic field object).
+type regionalMasterObject struct {
+	Data struct {
+		Content struct {
+			Fields struct {
+				Value struct {
+					Fields struct {
+						Region        string `json:"region"`
+						Operator      string `json:"operator"`
+						Endpoint      string `json:"endpoint"`
+						PublicKey     string `json:"public_key"`
+						RegisteredAt  string `json:"registered_at"`
+						LastHeartbeat string `json:"last_heartbeat"`
+					} `json:"fields"`
+				} `json:"value"`
+			} `json:"fields"`
+		} `json:"content"`
+	} `json:"data"`
+}
+
+// RegionalMasters lists every master in the MasterRegistry shared object.
+func (c *SuiClient) RegionalMasters(ctx context.Context, registryID string) ([]RegionalMaster, error) {
+	var registry struct {
+		Data struct {
+			Content struct {
+				Fields struct {
+					Masters struct {
+						Fields struct {
+							ID struct {
+								ID string `json:"id"`
+							} `json:"id"`
+						} `json:"fields"`
+					} `json:"masters"`
+				} `json:"fields"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	options := map[string]bool{"showContent": true}
+	if err := c.Call(ctx, "sui_getObject", []interface{}{registryID, options}, &registry); err != nil {
+		return nil, fmt.Errorf("failed to fetch master registry: %w", err)
+	}
+	tableID := registry.Data.Content.Fields.Masters.Fields.ID.ID
+	if tableID == "" {
+		return nil, fmt.Errorf("master registry %s has no masters table", registryID)
+	}
+
+	var masters []RegionalMaster
+	var cursor interface{}
+	for {
+		var page struct {
+			Data []struct {
+				ObjectID string `json:"objectId"`
+			} `json:"data"`
+			NextCursor  *string `json:"nextCursor"`
+			HasNextPage bool    `json:"hasNextPage"`
+		}
+		if err := c.Call(ctx, "suix_getDynamicFields", []interface{}{tableID, cursor, 50}, &page); err != nil {
+			return nil, fmt.Errorf("failed to list masters: %w", err)
+		}
+
+		if len(page.Data) > 0 {
+			ids := make([]string, len(page.Data))
+			for i, field := range page.Data {
+				ids[i] = field.ObjectID
+			}
+			var objects []regionalMasterObject
+			if err := c.Call(ctx, "sui_multiGetObjects", []interface{}{ids, options}, &objects); err != nil {
+				return nil, fmt.Errorf("failed to fetch master entries: %w", err)
+			}
+			for _, object := range objects {
+				fields := object.Data.Content.Fields.Value.Fields
+				if fields.Region == "" {
+					continue
+				}
+				masters = append(masters, RegionalMaster{
+					Region:        fields.Region,
+					Operator:      fields.Operator,
+					Endpoint:      fields.Endpoint,
+					PublicKey:     fields.PublicKey,
+					RegisteredAt:  unixMillis(fields.RegisteredAt),
+					LastHeartbeat: unixMillis(fields.LastHeartbeat),
+				})
+			}
+		}
+
+		if !page.HasNextPage || page.NextCursor == nil {
+			return masters, nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// unixMillis parses a Move u64 millisecond timestamp; zero stays the zero time.
+func unixMillis(value string) time.Time {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,186 @@
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RPCError is a JSON-RPC error returned by a Sui fullnode
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Address returns the client's Sui address (empty for read-only clients)
+func (c *SuiClient) Address() string {
+	return c.address
+}
+
+// Endpoint returns the RPC endpoint the client talks to
+func (c *SuiClient) Endpoint() string {
+	return c.endpoint
+}
+
+// RawRequest sends a prebuilt JSON-RPC request and returns the raw response
+// body. Callers that need the full envelope (e.g. to inspect objectChanges
+// alongside errors) use this instead of Call.
+func (c *SuiClient) RawRequest(ctx context.Context, request map[string]interface{}) ([]byte, error) {
+	c.updateMetrics(func() { c.metrics.RequestCount++ })
+
+	body, err := c.makeRequest(ctx, request)
+	if err != nil {
+		c.updateMetrics(func() { c.metrics.ErrorCount++ })
+		return nil, err
+	}
+
+	c.updateMetrics(func() { c.metrics.SuccessCount++ })
+	return body, nil
+}
+
+// Call invokes a JSON-RPC method and decodes its result into result (which
+// may be nil when only success matters)
+func (c *SuiClient) Call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	body, err := c.RawRequest(ctx, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("%s request failed: %v", method, err)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", method, err)
+	}
+	if response.Error != nil {
+		c.updateMetrics(func() { c.metrics.ErrorCount++ })
+		return fmt.Errorf("%s: %v", method, response.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// LatestCheckpoint returns the sequence number of the latest checkpoint
+func (c *SuiClient) LatestCheckpoint(ctx context.Context) (uint64, error) {
+	var sequence string
+	if err := c.Call(ctx, "sui_getLatestCheckpointSequenceNumber", nil, &sequence); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(sequence, 10, 64)
+}
+
+// ChainTime returns the timestamp of the latest checkpoint, which is agreed
+// by validators and therefore a trustworthy reference for local clocks
+func (c *SuiClient) ChainTime(ctx context.Context) (time.Time, error) {
+	var sequence string
+	if err := c.Call(ctx, "sui_getLatestCheckpointSequenceNumber", nil, &sequence); err != nil {
+		return time.Time{}, err
+	}
+
+	var checkpoint struct {
+		TimestampMs string `json:"timestampMs"`
+	}
+	if err := c.Call(ctx, "sui_getCheckpoint", []interface{}{sequence}, &checkpoint); err != nil {
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(checkpoint.TimestampMs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid checkpoint timestamp %q: %v", checkpoint.TimestampMs, err)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// Event is a Move event as returned by suix_queryEvents
+type Event struct {
+	ID struct {
+		TxDigest string `json:"txDigest"`
+		EventSeq string `json:"eventSeq"`
+	} `json:"id"`
+	PackageID         string                 `json:"packageId"`
+	TransactionModule string                 `json:"transactionModule"`
+	Sender            string                 `json:"sender"`
+	Type              string                 `json:"type"`
+	ParsedJSON        map[string]interface{} `json:"parsedJson"`
+	TimestampMs       string                 `json:"timestampMs"`
+}
+
+// EventCursor identifies a position in the event stream
+type EventCursor struct {
+	TxDigest string `json:"txDigest"`
+	EventSeq string `json:"eventSeq"`
+}
+
+// EventPage is one page of suix_queryEvents results
+type EventPage struct {
+	Data        []Event      `json:"data"`
+	NextCursor  *EventCursor `json:"nextCursor"`
+	HasNextPage bool         `json:"hasNextPage"`
+}
+
+// ModuleEventFilter matches events emitted by a module of the client's package
+func (c *SuiClient) ModuleEventFilter(module string) map[string]interface{} {
+	return map[string]interface{}{
+		"MoveModule": map[string]string{"package": c.contractPackage, "module": module},
+	}
+}
+
+// QueryEvents returns events matching filter in ascending order after cursor
+func (c *SuiClient) QueryEvents(ctx context.Context, filter map[string]interface{}, cursor *EventCursor, limit int) (*EventPage, error) {
+	var page EventPage
+	if err := c.Call(ctx, "suix_queryEvents", []interface{}{filter, cursor, limit, false}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ExecuteTransactionBlock submits signed transaction bytes and invalidates
+// cached objects touched by the transaction
+func (c *SuiClient) ExecuteTransactionBlock(ctx context.Context, txBytes string, signatures []string) (*TransactionResponse, error) {
+	var result TransactionResponse
+	err := c.Call(ctx, "sui_executeTransactionBlock", []interface{}{
+		txBytes,
+		signatures,
+		map[string]bool{"showEffects": true, "showEvents": true, "showObjectChanges": true},
+		"WaitForLocalExecution",
+	}, &result)
+	if err != nil {
+		c.updateMetrics(func() { c.metrics.TransactionsFailed++ })
+		return nil, err
+	}
+
+	c.ApplyObjectChanges(result.ObjectChanges)
+	c.invalidateFromEffects(result.Effects)
+
+	if status, _ := result.Effects["status"].(map[string]interface{}); status != nil && status["status"] != "success" {
+		c.updateMetrics(func() { c.metrics.TransactionsFailed++ })
+		return &result, fmt.Errorf("transaction %s failed: %v", result.Digest, status["error"])
+	}
+
+	c.updateMetrics(func() { c.metrics.TransactionsSuccess++ })
+	return &result, nil
+}
+
+// DryRunTransactionBlock simulates transaction bytes without executing them
+// and returns the decoded response (input, effects, events)
+func (c *SuiClient) DryRunTransactionBlock(ctx context.Context, txBytes string, result interface{}) error {
+	return c.Call(ctx, "sui_dryRunTransactionBlock", []interface{}{txBytes}, result)
+}
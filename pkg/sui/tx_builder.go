@@ -0,0 +1,87 @@
+package sui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// TransactionBuilder batches Move calls into one programmable transaction
+// block. Commands run atomically: if any aborts the whole block is rolled
+// back, so multi-step flows never end half-applied.
+type TransactionBuilder struct {
+	sender       string
+	gasBudget    uint64
+	gasPrice     uint64
+	sponsor      string
+	transactions []interface{}
+}
+
+// Argument refers to the result of an earlier command in the same block
+type Argument map[string]int
+
+// NewTransactionBuilder creates a builder for transactions sent by sender
+func NewTransactionBuilder(sender string, gasBudget uint64) *TransactionBuilder {
+	return &TransactionBuilder{sender: sender, gasBudget: gasBudget, gasPrice: 1000}
+}
+
+// WithGasPrice overrides the reference gas price
+func (b *TransactionBuilder) WithGasPrice(gasPrice uint64) *TransactionBuilder {
+	b.gasPrice = gasPrice
+	return b
+}
+
+// WithGasSponsor makes sponsor the gas owner; both sender and sponsor must sign
+func (b *TransactionBuilder) WithGasSponsor(sponsor string) *TransactionBuilder {
+	b.sponsor = sponsor
+	return b
+}
+
+// MoveCall appends a Move call and returns a reference to its result that
+// can be passed as an argument to later calls
+func (b *TransactionBuilder) MoveCall(packageID, module, function string, args ...interface{}) Argument {
+	if args == nil {
+		args = []interface{}{}
+	}
+	b.transactions = append(b.transactions, map[string]interface{}{
+		"MoveCall": map[string]interface{}{
+			"packageObjectId": packageID,
+			"module":          module,
+			"function":        function,
+			"typeArguments":   []string{},
+			"arguments":       args,
+		},
+	})
+	return Argument{"Result": len(b.transactions) - 1}
+}
+
+// Len returns the number of commands in the block
+func (b *TransactionBuilder) Len() int {
+	return len(b.transactions)
+}
+
+// Build serializes the block to base64 transaction bytes
+func (b *TransactionBuilder) Build() (string, error) {
+	if len(b.transactions) == 0 {
+		return "", fmt.Errorf("transaction has no commands")
+	}
+
+	txBlock := map[string]interface{}{
+		"version":      1,
+		"sender":       b.sender,
+		"gasPayment":   nil, // selected by the node
+		"gasBudget":    fmt.Sprintf("%d", b.gasBudget),
+		"gasPrice":     fmt.Sprintf("%d", b.gasPrice),
+		"kind":         "ProgrammableTransaction",
+		"transactions": b.transactions,
+	}
+	if b.sponsor != "" {
+		txBlock["gasOwner"] = b.sponsor
+	}
+
+	txJSON, err := json.Marshal(txBlock)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize transaction: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(txJSON), nil
+}
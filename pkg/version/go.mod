@@ -0,0 +1,3 @@
+module github.com/k3s-io/daas-version
+
+go 1.21
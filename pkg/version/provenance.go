@@ -0,0 +1,53 @@
+package version
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ProvenanceDomain separates provenance signatures from the other messages
+// the same enclave key signs (responses, attestation documents).
+const ProvenanceDomain = "daas-build-provenance-v1"
+
+// Provenance is a master's build info bound to a caller's nonce and signed
+// with its enclave key, so a worker can tell which build it is talking to.
+type Provenance struct {
+	Info
+	Nonce     string `json:"nonce,omitempty"`
+	IssuedAt  int64  `json:"issued_at"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// Digest is the SHA-256 the signature covers. Every provenance field except
+// the key and the signature is included, one per line.
+func (p *Provenance) Digest() []byte {
+	payload := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%d",
+		ProvenanceDomain, p.Component, p.Version, p.Commit, p.Builder, p.SBOMDigest,
+		p.BuildDate, p.GoVersion, p.Nonce, p.IssuedAt)
+	digest := sha256.Sum256([]byte(payload))
+	return digest[:]
+}
+
+// Sign fills in the key and signature using sign, which must produce an
+// ed25519 signature over its argument.
+func (p *Provenance) Sign(publicKey ed25519.PublicKey, sign func([]byte) []byte) {
+	p.PublicKey = hex.EncodeToString(publicKey)
+	p.Signature = hex.EncodeToString(sign(p.Digest()))
+}
+
+// Verify checks the signature against a pinned key. The key carried in the
+// document is deliberately ignored: anyone can sign with a key of their own.
+func (p *Provenance) Verify(publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid pinned public key")
+	}
+	signature, err := hex.DecodeString(p.Signature)
+	if err != nil || !ed25519.Verify(publicKey, p.Digest(), signature) {
+		return errors.New("provenance signature does not verify")
+	}
+	return nil
+}
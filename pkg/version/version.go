@@ -0,0 +1,222 @@
+// Package version carries the build version of each K3s-DaaS binary and the
+// skew policy applied when components talk to each other. Release builds
+// stamp the version with
+//
+//	-ldflags "-X github.com/k3s-io/daas-version.Version=v1.4.0 -X github.com/k3s-io/daas-version.Commit=$(git rev-parse --short HEAD)"
+//
+// The Dockerfiles additionally stamp Builder (who produced the binary) and
+// SBOMDigest (sha256 of the module list the binary was linked from, shipped
+// next to it in the image), which together with Commit form the provenance a
+// master signs for its workers; see Provenance.
+//
+// Components exchange "<component>/<version>" in the X-Daas-Version header on
+// worker registration and on every gateway request to a master. The master
+// is the server side of both: a worker or gateway may be up to MaxMinorSkew
+// minor versions older than the master but never newer, so masters are
+// upgraded first. Development builds ("dev" or any non-semver version) are
+// never refused.
+package version
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Set at link time; see the package doc.
+var (
+	Version    = "dev"
+	Commit     = ""
+	BuildDate  = ""
+	Builder    = ""
+	SBOMDigest = ""
+)
+
+// Header carries "<component>/<version>" in both directions.
+const Header = "X-Daas-Version"
+
+// LabelKey is the node label workers add with their version.
+const LabelKey = "k3s-daas.io/version"
+
+// DefaultMaxMinorSkew is how many minor versions a client may lag the master.
+const DefaultMaxMinorSkew = 2
+
+// Info describes one binary.
+type Info struct {
+	Component  string `json:"component"`
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	BuildDate  string `json:"build_date,omitempty"`
+	Builder    string `json:"builder,omitempty"`
+	SBOMDigest string `json:"sbom_digest,omitempty"`
+	GoVersion  string `json:"go_version"`
+}
+
+// Get returns the build info of the running binary. Without a stamped commit
+// the VCS revision recorded by the Go toolchain is used.
+func Get(component string) Info {
+	info := Info{
+		Component: component, Version: Version, Commit: Commit, BuildDate: BuildDate,
+		Builder: Builder, SBOMDigest: SBOMDigest, GoVersion: runtime.Version(),
+	}
+	if info.Commit == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+					info.Commit = setting.Value[:12]
+				}
+			}
+		}
+	}
+	return info
+}
+
+// String formats the info for logs and --version output.
+func (i Info) String() string {
+	if i.Commit == "" {
+		return fmt.Sprintf("%s %s (%s)", i.Component, i.Version, i.GoVersion)
+	}
+	return fmt.Sprintf("%s %s (commit %s, %s)", i.Component, i.Version, i.Commit, i.GoVersion)
+}
+
+// HeaderValue is the value sent in Header.
+func (i Info) HeaderValue() string {
+	return i.Component + "/" + i.Version
+}
+
+// ParseHeader splits a Header value into component and version.
+func ParseHeader(value string) (component, version string, ok bool) {
+	component, version, ok = strings.Cut(strings.TrimSpace(value), "/")
+	if !ok || component == "" || version == "" {
+		return "", "", false
+	}
+	return component, version, true
+}
+
+// LabelValue makes a version usable as a Kubernetes label value: at most 63
+// characters from [A-Za-z0-9._-], starting and ending alphanumeric.
+func LabelValue(version string) string {
+	value := []byte(version)
+	for i, c := range value {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			value[i] = '_'
+		}
+	}
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(string(value), "._-")
+}
+
+// Semver is the numeric part of a version.
+type Semver struct {
+	Major, Minor, Patch int
+}
+
+// Parse reads "v1.2.3", "1.2" or "v1.2.3-rc.1+build". Anything else, such as
+// "dev", is not a release version.
+func Parse(version string) (Semver, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Semver{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, false
+		}
+		numbers[i] = n
+	}
+	return Semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, true
+}
+
+// Mode says what to do with a skewed peer.
+type Mode string
+
+const (
+	ModeWarn   Mode = "warn"   // log and report, keep talking (default)
+	ModeRefuse Mode = "refuse" // reject the registration or request
+	ModeOff    Mode = "off"    // do not compare versions
+)
+
+// Policy is the supported skew between a server and its clients.
+type Policy struct {
+	Mode         Mode
+	MaxMinorSkew int
+}
+
+// PolicyFromEnv reads <PREFIX>_VERSION_SKEW_POLICY (warn, refuse, off) and
+// <PREFIX>_VERSION_MAX_MINOR_SKEW.
+func PolicyFromEnv(prefix string) (Policy, error) {
+	policy := Policy{Mode: ModeWarn, MaxMinorSkew: DefaultMaxMinorSkew}
+	if value := os.Getenv(prefix + "_VERSION_SKEW_POLICY"); value != "" {
+		switch mode := Mode(strings.ToLower(value)); mode {
+		case ModeWarn, ModeRefuse, ModeOff:
+			policy.Mode = mode
+		default:
+			return policy, fmt.Errorf("%s_VERSION_SKEW_POLICY: unknown mode %q (warn, refuse, off)", prefix, value)
+		}
+	}
+	if value := os.Getenv(prefix + "_VERSION_MAX_MINOR_SKEW"); value != "" {
+		skew, err := strconv.Atoi(value)
+		if err != nil || skew < 0 {
+			return policy, fmt.Errorf("%s_VERSION_MAX_MINOR_SKEW: invalid value %q", prefix, value)
+		}
+		policy.MaxMinorSkew = skew
+	}
+	return policy, nil
+}
+
+// SkewError reports a client outside the supported skew.
+type SkewError struct {
+	Server string
+	Client string
+	Reason string
+}
+
+func (e *SkewError) Error() string {
+	return fmt.Sprintf("version skew: client %s, server %s: %s", e.Client, e.Server, e.Reason)
+}
+
+// Check compares a client version against the server it talks to. It returns
+// a *SkewError when the pair is outside the policy, or nil when it is within
+// it, when either side is not a release version, or when the mode is off.
+func (p Policy) Check(server, client string) error {
+	if p.Mode == ModeOff {
+		return nil
+	}
+	serverVersion, ok := Parse(server)
+	if !ok {
+		return nil
+	}
+	clientVersion, ok := Parse(client)
+	if !ok {
+		return nil
+	}
+
+	skew := func(reason string) error {
+		return &SkewError{Server: server, Client: client, Reason: reason}
+	}
+	switch {
+	case clientVersion.Major != serverVersion.Major:
+		return skew("major versions differ")
+	case clientVersion.Minor > serverVersion.Minor:
+		return skew("client is newer than the server (upgrade the master first)")
+	case serverVersion.Minor-clientVersion.Minor > p.MaxMinorSkew:
+		return skew(fmt.Sprintf("client is %d minor versions behind (at most %d supported)", serverVersion.Minor-clientVersion.Minor, p.MaxMinorSkew))
+	}
+	return nil
+}
+
+// Refuses reports whether a skew found by Check must reject the peer.
+func (p Policy) Refuses(err error) bool {
+	return err != nil && p.Mode == ModeRefuse
+}
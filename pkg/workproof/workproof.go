@@ -0,0 +1,87 @@
+// Package workproof implements the heartbeat challenge a master sends to
+// its workers to make one machine posing as many worker identities costly.
+//
+// A challenge carries a nonce and a memory size. The worker answers with
+// two values:
+//
+//   - Signature, an HMAC-SHA256 of the challenge under the identity's seal
+//     token, showing that a process holding that identity's secret is live.
+//   - Solution, the result of a memory-hard computation over the nonce (see
+//     Solve). Every identity challenged in the same round has to produce
+//     one before the deadline, so identities sharing a host compete for its
+//     memory and CPU.
+//
+// Checking a Solution costs the verifier as much as producing it, so a
+// verifier usually checks a random sample of the answers.
+package workproof
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// wordSize is the size of one memory cell, a SHA-256 digest.
+const wordSize = sha256.Size
+
+// MaxMemoryMiB bounds the memory a challenge may ask for.
+const MaxMemoryMiB = 1024
+
+// Sign returns the hex HMAC-SHA256 of "id:nonce" keyed by the seal token.
+func Sign(sealToken, id, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(sealToken))
+	mac.Write([]byte(id + ":" + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is Sign(sealToken, id, nonce).
+func VerifySignature(sealToken, id, nonce, signature string) bool {
+	want, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(sealToken))
+	mac.Write([]byte(id + ":" + nonce))
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+// Solve fills memoryMiB MiB with a SHA-256 chain seeded by the nonce, then
+// performs one data-dependent read per cell, each hashing the running value
+// with the cell its low bits select (the ROMix construction of scrypt).
+// Because the next cell is only known after the previous read, computing
+// the result without the memory means recomputing the chain up to each
+// cell. It returns the final value in hex.
+func Solve(nonce string, memoryMiB int) (string, error) {
+	if memoryMiB < 1 || memoryMiB > MaxMemoryMiB {
+		return "", fmt.Errorf("memory %d MiB is outside 1-%d", memoryMiB, MaxMemoryMiB)
+	}
+	cells := memoryMiB << 20 / wordSize
+	memory := make([]byte, cells*wordSize)
+
+	word := sha256.Sum256([]byte(nonce))
+	copy(memory, word[:])
+	for i := 1; i < cells; i++ {
+		word = sha256.Sum256(word[:])
+		copy(memory[i*wordSize:], word[:])
+	}
+
+	var input [2 * wordSize]byte
+	for i := 0; i < cells; i++ {
+		j := int(binary.BigEndian.Uint64(word[:8]) % uint64(cells))
+		copy(input[:wordSize], word[:])
+		copy(input[wordSize:], memory[j*wordSize:(j+1)*wordSize])
+		word = sha256.Sum256(input[:])
+	}
+	return hex.EncodeToString(word[:]), nil
+}
+
+// Verify reports whether solution is Solve(nonce, memoryMiB).
+func Verify(nonce string, memoryMiB int, solution string) (bool, error) {
+	want, err := Solve(nonce, memoryMiB)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal([]byte(want), []byte(solution)), nil
+}
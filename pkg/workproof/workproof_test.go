@@ -0,0 +1,46 @@
+package workproof
+
+import "testing"
+
+func TestSolveVerify(t *testing.T) {
+	solution, err := Solve("nonce-1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := Verify("nonce-1", 1, solution); err != nil || !ok {
+		t.Fatalf("Verify(own solution) = %v, %v", ok, err)
+	}
+	if ok, _ := Verify("nonce-2", 1, solution); ok {
+		t.Fatal("solution accepted for a different nonce")
+	}
+	if ok, _ := Verify("nonce-1", 2, solution); ok {
+		t.Fatal("solution accepted for a different memory size")
+	}
+	if _, err := Solve("nonce-1", 0); err == nil {
+		t.Fatal("Solve accepted 0 MiB")
+	}
+}
+
+func TestSignature(t *testing.T) {
+	signature := Sign("seal-token", "challenge-1", "nonce-1")
+	if !VerifySignature("seal-token", "challenge-1", "nonce-1", signature) {
+		t.Fatal("own signature rejected")
+	}
+	for _, c := range []struct{ token, id, nonce, signature string }{
+		{"other-token", "challenge-1", "nonce-1", signature},
+		{"seal-token", "challenge-2", "nonce-1", signature},
+		{"seal-token", "challenge-1", "nonce-1", "zz"},
+	} {
+		if VerifySignature(c.token, c.id, c.nonce, c.signature) {
+			t.Fatalf("signature accepted for %+v", c)
+		}
+	}
+}
+
+func BenchmarkSolve32MiB(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := Solve("bench", 32); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
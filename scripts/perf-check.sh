@@ -0,0 +1,68 @@
+#!/bin/bash
+
+# API 요청 경로 벤치마크 실행 후 예산(api-proxy/perf/bench-budget.txt) 확인
+#
+#   scripts/perf-check.sh                     # 예산 확인
+#   BASELINE=perf-base scripts/perf-check.sh  # 이전 결과 대비 회귀 확인 (TOLERANCE 기본 20%)
+#   OUT=perf-new scripts/perf-check.sh        # 결과를 디렉토리에 저장 (다음 실행의 BASELINE)
+#
+# 예산 초과나 회귀가 있으면 종료 코드 1
+
+set -o pipefail
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+BUDGET="$ROOT/api-proxy/perf/bench-budget.txt"
+BENCHTIME="${BENCHTIME:-1s}"
+TOLERANCE="${TOLERANCE:-20}"
+OUT="${OUT:-$(mktemp -d)}"
+mkdir -p "$OUT"
+
+failed=0
+
+for dir in $(grep -v '^#' "$BUDGET" | awk 'NF { print $1 }' | sort -u); do
+    name=$(echo "$dir" | tr '/' '-')
+    echo "🏃 $dir"
+    if ! (cd "$ROOT/$dir" && go test -run '^$' -bench . -benchmem -benchtime "$BENCHTIME" . ) > "$OUT/$name.txt"; then
+        cat "$OUT/$name.txt"
+        echo "❌ benchmarks failed in $dir"
+        failed=1
+        continue
+    fi
+    grep '^Benchmark' "$OUT/$name.txt"
+done
+
+echo ""
+while read -r dir bench limit; do
+    [[ -z "$dir" || "$dir" == \#* ]] && continue
+    name=$(echo "$dir" | tr '/' '-')
+    nsop=$(awk -v b="$bench" '$1 ~ "^"b"(-[0-9]+)?$" { print $3 }' "$OUT/$name.txt" 2>/dev/null)
+    if [ -z "$nsop" ]; then
+        echo "❌ $bench: no result"
+        failed=1
+        continue
+    fi
+
+    status="✅"
+    if awk -v n="$nsop" -v l="$limit" 'BEGIN { exit !(n > l) }'; then
+        status="❌"
+        failed=1
+    fi
+    line="$status $bench ${nsop} ns/op (budget ${limit})"
+
+    if [ -n "$BASELINE" ] && [ -f "$BASELINE/$name.txt" ]; then
+        base=$(awk -v b="$bench" '$1 ~ "^"b"(-[0-9]+)?$" { print $3 }' "$BASELINE/$name.txt")
+        if [ -n "$base" ]; then
+            change=$(awk -v n="$nsop" -v b="$base" 'BEGIN { printf "%+.0f", 100 * (n / b - 1) }')
+            line="$line, ${change}% vs baseline"
+            if [ "${change#+}" -gt "$TOLERANCE" ] 2>/dev/null; then
+                line="${line/✅/❌} (regression)"
+                failed=1
+            fi
+        fi
+    fi
+    echo "$line"
+done < "$BUDGET"
+
+echo ""
+echo "📁 results: $OUT"
+exit $failed
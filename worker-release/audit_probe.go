@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+/*
+auditProbe - 마스터가 하트비트 응답에 실어 보내는 Pod 상태 질의
+마스터는 보고된 running_pods를 자신의 배정 기록과 비교하고, 가끔 임의 Pod의 실행 여부를
+물어 자가 보고의 신뢰도를 확인합니다. 답은 다음 하트비트의 probe_result로 전송합니다.
+*/
+type auditProbe struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+}
+
+// probeResult - 프로브 응답
+type probeResult struct {
+	ID      string `json:"id"`
+	Running bool   `json:"running"`
+}
+
+/*
+handleHeartbeatResponse - 하트비트 응답에서 감사 프로브를 꺼내 다음 하트비트용 응답 준비
+(마스터가 새 설정 번들을 실어 보냈으면 먼저 적용, 하트비트 챌린지는 백그라운드 계산 시작)
+응답 확인은 지금 수행하여 다음 하트비트 시점의 상태 변화와 섞이지 않게 합니다.
+*/
+func (s *StakerHost) handleHeartbeatResponse(body []byte) {
+	var response struct {
+		Probe      *auditProbe         `json:"probe"`
+		Config     *workerConfigBundle `json:"config"`
+		ProbePeers []probePeer         `json:"probe_peers"`
+		Access     *statusAccessPolicy `json:"status_access"`
+		Challenge  *heartbeatChallenge `json:"challenge"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return
+	}
+	if s.netProbe != nil {
+		s.netProbe.setPeers(response.ProbePeers)
+	}
+	if response.Access != nil && s.firewall != nil {
+		s.firewall.apply(*response.Access)
+	}
+	if response.Config != nil && features.Enabled(featureConfigSync) {
+		s.applyConfigBundle(*response.Config)
+	}
+	if response.Challenge != nil {
+		s.challenges.start(response.Challenge, s.stakingStatus.SealToken())
+	}
+	if response.Probe == nil {
+		return
+	}
+
+	running, err := s.podRunning(response.Probe.Namespace, response.Probe.Pod)
+	if err != nil {
+		// 조회 실패 시 응답하지 않음 (마스터는 만료된 프로브를 실패로 기록)
+		log.Printf("⚠️ 감사 프로브 조회 실패 (%s/%s): %v", response.Probe.Namespace, response.Probe.Pod, err)
+		return
+	}
+	s.probeAnswer = &probeResult{ID: response.Probe.ID, Running: running}
+}
+
+// podRunning - 로컬 CRI 또는 (staking 모드) 런타임 에이전트에 Pod 실행 여부 확인
+func (s *StakerHost) podRunning(namespace, name string) (bool, error) {
+	if s.runtimeClient != nil {
+		return s.runtimeClient.PodRunning(namespace, name)
+	}
+	return isPodRunning(namespace, name)
+}
+
+/*
+isPodRunning - CRI에서 Pod 샌드박스가 Ready 상태인지 확인
+K3s에 포함된 crictl을 사용합니다 (k3s crictl).
+*/
+func isPodRunning(namespace, name string) (bool, error) {
+	output, err := exec.Command("k3s", "crictl", "pods",
+		"--namespace", namespace, "--name", name, "--state", "ready", "--quiet").Output()
+	if err != nil {
+		return false, fmt.Errorf("crictl pods 실패: %v", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// countReadyPods - CRI의 Ready Pod 샌드박스 수
+func countReadyPods() (int, error) {
+	output, err := exec.Command("k3s", "crictl", "pods", "--state", "ready", "--quiet").Output()
+	if err != nil {
+		return 0, fmt.Errorf("crictl pods 실패: %v", err)
+	}
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return 0, nil
+	}
+	return len(strings.Split(trimmed, "\n")), nil
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+)
+
+// 네트워크 측정 기본값
+const (
+	defaultNetworkProbeInterval = 10 * 60 // 초
+	defaultNetworkProbeBytes    = 2 << 20 // 마스터와 주고받는 양 (2MiB)
+	defaultPeerProbeBytes       = 1 << 20 // 피어 워커에서 내려받는 양 (1MiB)
+	maxPeerProbeBytes           = 8 << 20 // 다른 워커가 요청할 수 있는 최대 양
+	networkProbeTimeout         = 60 * time.Second
+	networkProbeChunk           = 64 << 10
+	probeTokenHeader            = "X-Probe-Token"
+)
+
+/*
+NetworkProbeConfig - 대역폭/지연 측정 설정
+
+마스터와의 다운로드/업로드 대역폭, 하트비트 응답으로 받은 피어 워커와의 지연/대역폭을 주기적으로 측정해
+다음 하트비트로 보고합니다. 마스터는 결과를 노드 라벨(k3s-daas.io/bandwidth-mbps)과
+어노테이션(k3s-daas.io/network-probe)으로 반영하고, Pod의 k3s-daas.io/min-bandwidth-mbps 요청에 사용합니다.
+*/
+type NetworkProbeConfig struct {
+	Disabled        bool   `json:"disabled"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	Bytes           int64  `json:"bytes"`         // 마스터 측정 1회 전송량
+	PeerBytes       int64  `json:"peer_bytes"`    // 피어 측정 1회 전송량
+	AdvertiseURL    string `json:"advertise_url"` // 다른 워커가 측정에 사용할 상태 서버 주소 (기본: listen_addr, 호스트는 마스터가 채움)
+}
+
+/*
+applyNetworkProbeDefaults - 네트워크 측정 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_NETWORK_PROBE_INTERVAL: 측정 주기 (초, 0이면 측정 안 함)
+- K3S_DAAS_PROBE_ADVERTISE_URL: 피어 측정용 상태 서버 주소
+*/
+func applyNetworkProbeDefaults(config *StakerHostConfig) error {
+	p := &config.NetworkProbe
+	if value := os.Getenv("K3S_DAAS_NETWORK_PROBE_INTERVAL"); value != "" {
+		interval, err := strconv.Atoi(value)
+		if err != nil || interval < 0 {
+			return fmt.Errorf("잘못된 K3S_DAAS_NETWORK_PROBE_INTERVAL: %s", value)
+		}
+		p.IntervalSeconds = interval
+		p.Disabled = interval == 0
+	}
+	if value := os.Getenv("K3S_DAAS_PROBE_ADVERTISE_URL"); value != "" {
+		p.AdvertiseURL = value
+	}
+
+	if p.IntervalSeconds <= 0 {
+		p.IntervalSeconds = defaultNetworkProbeInterval
+	}
+	if p.Bytes <= 0 {
+		p.Bytes = defaultNetworkProbeBytes
+	}
+	if p.PeerBytes <= 0 {
+		p.PeerBytes = defaultPeerProbeBytes
+	}
+	if p.PeerBytes > maxPeerProbeBytes {
+		p.PeerBytes = maxPeerProbeBytes
+	}
+	return nil
+}
+
+// peerProbeResult - 피어 워커 하나의 측정 결과 (마스터 PeerProbe와 같은 형식)
+type peerProbeResult struct {
+	NodeID string  `json:"node_id"`
+	RTTMs  int64   `json:"rtt_ms"`
+	Mbps   float64 `json:"mbps"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// networkReport - 하트비트 network 섹션
+type networkReport struct {
+	DownloadMbps float64           `json:"download_mbps"`
+	UploadMbps   float64           `json:"upload_mbps"`
+	Peers        []peerProbeResult `json:"peers,omitempty"`
+	MeasuredAt   time.Time         `json:"measured_at"`
+}
+
+// probePeer - 마스터가 하트비트 응답으로 고른 측정 대상
+type probePeer struct {
+	NodeID string `json:"node_id"`
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+}
+
+/*
+networkProber - 측정 결과, 측정 대상, 피어 측정 요청 처리 상태
+token은 프로세스마다 새로 만들며 하트비트로 마스터에 알립니다.
+마스터는 이 워커를 측정 대상으로 고른 워커에게만 토큰을 전달합니다.
+*/
+type networkProber struct {
+	token   string
+	payload []byte // 압축되지 않는 무작위 데이터 (반복 전송)
+	serving int32  // 처리 중인 피어 측정 요청 (한 번에 하나)
+
+	mutex  sync.Mutex
+	report *networkReport
+	peers  []probePeer
+}
+
+func newNetworkProber() *networkProber {
+	token := make([]byte, 16)
+	rand.Read(token)
+	payload := make([]byte, networkProbeChunk)
+	rand.Read(payload)
+	return &networkProber{token: hex.EncodeToString(token), payload: payload}
+}
+
+func (p *networkProber) setPeers(peers []probePeer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.peers = peers
+}
+
+func (p *networkProber) snapshot() (*networkReport, []probePeer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.report, append([]probePeer(nil), p.peers...)
+}
+
+// runNetworkProbe - 주기적 대역폭 측정 (첫 측정은 등록 후 첫 하트비트가 피어 목록을 받을 시간 뒤)
+func (s *StakerHost) runNetworkProbe(ctx context.Context) {
+	if s.netProbe == nil || s.config.NetworkProbe.Disabled {
+		return
+	}
+	interval := time.Duration(s.config.NetworkProbe.IntervalSeconds) * time.Second
+	log.Printf("📶 네트워크 측정 활성화 (%s 간격, 마스터 %dKiB / 피어 %dKiB)",
+		interval, s.config.NetworkProbe.Bytes>>10, s.config.NetworkProbe.PeerBytes>>10)
+
+	timer := time.NewTimer(s.heartbeatInterval() + 5*time.Second)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if s.stakingStatus.SealToken() != "" {
+			s.measureNetwork()
+		}
+		timer.Reset(interval)
+	}
+}
+
+// measureNetwork - 마스터 다운로드/업로드와 피어 측정 1회
+func (s *StakerHost) measureNetwork() {
+	report := &networkReport{MeasuredAt: time.Now().UTC()}
+
+	download, err := s.measureMasterDownload()
+	if err != nil {
+		log.Printf("⚠️ 마스터 다운로드 대역폭 측정 실패: %v", err)
+		return
+	}
+	upload, err := s.measureMasterUpload()
+	if err != nil {
+		log.Printf("⚠️ 마스터 업로드 대역폭 측정 실패: %v", err)
+		return
+	}
+	report.DownloadMbps, report.UploadMbps = download, upload
+
+	_, peers := s.netProbe.snapshot()
+	for _, peer := range peers {
+		result := peerProbeResult{NodeID: peer.NodeID}
+		if rtt, mbps, err := s.measurePeer(peer); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.RTTMs, result.Mbps = rtt.Milliseconds(), mbps
+		}
+		report.Peers = append(report.Peers, result)
+	}
+
+	s.netProbe.mutex.Lock()
+	s.netProbe.report = report
+	s.netProbe.mutex.Unlock()
+	log.Printf("📶 네트워크 측정: 다운로드 %.1f Mbps, 업로드 %.1f Mbps, 피어 %d개", download, upload, len(report.Peers))
+}
+
+// masterProbeClient - 마스터 측정용 클라이언트 (예비 주소 장애 조치 적용)
+func (s *StakerHost) masterProbeClient() *http.Client {
+	client := &http.Client{Timeout: networkProbeTimeout}
+	if s.network != nil {
+		client.Transport = s.network.transport
+	}
+	return client
+}
+
+func (s *StakerHost) masterProbeURL() string {
+	return fmt.Sprintf("%s/api/v1/nodes/bandwidth-probe?node_id=%s&bytes=%d",
+		s.masterURL(), s.config.NodeID, s.config.NetworkProbe.Bytes)
+}
+
+// measureMasterDownload - 마스터가 보내는 데이터 수신 속도 (응답 헤더 이후 본문 수신 시간 기준)
+func (s *StakerHost) measureMasterDownload() (float64, error) {
+	req, err := http.NewRequest(http.MethodGet, s.masterProbeURL(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Seal-Token", s.stakingStatus.SealToken())
+	return downloadMbps(s.masterProbeClient(), req)
+}
+
+// measureMasterUpload - 마스터로 보내는 속도 (요청 전체 왕복 시간 기준이라 실제보다 약간 낮게 측정)
+func (s *StakerHost) measureMasterUpload() (float64, error) {
+	size := s.config.NetworkProbe.Bytes
+	req, err := http.NewRequest(http.MethodPost, s.masterProbeURL(), &repeatReader{chunk: s.netProbe.payload, remaining: size})
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Seal-Token", s.stakingStatus.SealToken())
+
+	start := time.Now()
+	resp, err := s.masterProbeClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return mbps(size, time.Since(start)), nil
+}
+
+/*
+measurePeer - 피어 워커 상태 서버와의 지연(빈 요청 3회 중앙값)과 다운로드 대역폭
+워커 상태 서버는 보통 자체 서명 인증서를 쓰므로 인증서는 검증하지 않습니다 (측정 토큰 외에 민감한 데이터 없음).
+*/
+func (s *StakerHost) measurePeer(peer probePeer) (time.Duration, float64, error) {
+	client := &http.Client{
+		Timeout:   networkProbeTimeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	defer client.CloseIdleConnections()
+	request := func(size int64) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/bandwidth-probe?bytes=%d", peer.URL, size), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(probeTokenHeader, peer.Token)
+		return req, nil
+	}
+
+	var samples []time.Duration
+	var lastErr error
+	for i := 0; i < latencyProbeCount; i++ {
+		req, err := request(0)
+		if err != nil {
+			return 0, 0, err
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
+			continue
+		}
+		samples = append(samples, time.Since(start))
+	}
+	if len(samples) == 0 {
+		return 0, 0, fmt.Errorf("피어 응답 없음 (%s): %v", peer.URL, lastErr)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	req, err := request(s.config.NetworkProbe.PeerBytes)
+	if err != nil {
+		return 0, 0, err
+	}
+	bandwidth, err := downloadMbps(client, req)
+	if err != nil {
+		return 0, 0, err
+	}
+	return samples[len(samples)/2], bandwidth, nil
+}
+
+// downloadMbps - 응답 본문 수신 속도 (gzip은 측정값을 왜곡하므로 identity 요청)
+func downloadMbps(client *http.Client, req *http.Request) (float64, error) {
+	req.Header.Set("Accept-Encoding", "identity")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	start := time.Now()
+	received, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	return mbps(received, time.Since(start)), nil
+}
+
+func mbps(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		elapsed = time.Millisecond
+	}
+	return float64(bytes) * 8 / elapsed.Seconds() / 1e6
+}
+
+// repeatReader - payload를 반복해 remaining 바이트를 읽히는 업로드 본문
+type repeatReader struct {
+	chunk     []byte
+	offset    int
+	remaining int64
+}
+
+func (r *repeatReader) Read(b []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.chunk[r.offset:])
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+	}
+	r.offset = (r.offset + n) % len(r.chunk)
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+// handlePeerProbe - 다른 워커의 측정 요청 (측정 토큰 확인, 동시에 하나만 처리)
+func (s *StakerHost) handlePeerProbe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.netProbe == nil || subtle.ConstantTimeCompare([]byte(r.Header.Get(probeTokenHeader)), []byte(s.netProbe.token)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	size, err := strconv.ParseInt(r.URL.Query().Get("bytes"), 10, 64)
+	if err != nil || size < 0 {
+		size = 0
+	}
+	if size > maxPeerProbeBytes {
+		size = maxPeerProbeBytes
+	}
+	if size > 0 {
+		if !atomic.CompareAndSwapInt32(&s.netProbe.serving, 0, 1) {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "Another probe is in progress", http.StatusTooManyRequests)
+			return
+		}
+		defer atomic.StoreInt32(&s.netProbe.serving, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	io.Copy(w, &repeatReader{chunk: s.netProbe.payload, remaining: size})
+}
+
+// probeURL - 하트비트로 알리는 피어 측정 주소 (루프백에서만 리슨하면 다른 워커가 접근할 수 없으므로 생략)
+func (s *StakerHost) probeURL() string {
+	if s.config.NetworkProbe.AdvertiseURL != "" {
+		return s.config.NetworkProbe.AdvertiseURL
+	}
+	server := httpserver.ConfigFromEnv("K3S_DAAS", s.config.ListenAddr)
+	host, port, err := net.SplitHostPort(server.Addr)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return ""
+	}
+	scheme := "http"
+	if server.TLSEnabled() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// addNetworkProbe - 하트비트 payload에 마지막 측정 결과와 피어 측정 주소/토큰 추가
+func (s *StakerHost) addNetworkProbe(payload map[string]interface{}) {
+	if s.netProbe == nil || s.config.NetworkProbe.Disabled {
+		return
+	}
+	if report, _ := s.netProbe.snapshot(); report != nil {
+		payload["network"] = report
+	}
+	if url := s.probeURL(); url != "" {
+		payload["probe_url"] = url
+		payload["probe_token"] = s.netProbe.token
+	}
+}
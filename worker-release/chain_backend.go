@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	chain "github.com/k3s-io/daas-chain"
+	sui "github.com/k3s-io/daas-sui"
+)
+
+/*
+openChainBackend - 설정된 체인 백엔드 생성
+chain_backend(또는 K3S_DAAS_CHAIN_BACKEND)가 비어 있으면 Sui를 사용하며,
+Sui 백엔드는 공용 Sui 클라이언트를 감싸 메트릭과 캐시를 공유합니다.
+
+mock은 마스터가 호스팅하는 인메모리 체인(<nautilus_endpoint>/api/v1/mockchain)에
+연결하므로, 스테이킹 → Seal 토큰 → 워커 등록 이벤트 흐름이 실제와 같이 동작합니다.
+다른 주소의 mock 체인은 K3S_DAAS_MOCK_CHAIN_URL로 지정합니다.
+예전 MOCK_MODE=true는 chain_backend=mock과 같게 취급합니다.
+*/
+func openChainBackend(config *StakerHostConfig, client *sui.SuiClient) (chain.Backend, error) {
+	if os.Getenv("MOCK_MODE") == "true" {
+		log.Printf("⚠️ MOCK_MODE는 더 이상 사용되지 않습니다 - K3S_DAAS_CHAIN_BACKEND=mock을 사용하세요")
+		config.ChainBackend = chain.MockBackendName
+	}
+	if name := os.Getenv("K3S_DAAS_CHAIN_BACKEND"); name != "" {
+		config.ChainBackend = name
+	}
+	if config.ChainBackend == "" {
+		config.ChainBackend = sui.BackendName
+	}
+
+	if config.ChainBackend == sui.BackendName {
+		return sui.NewBackend(client, config.SuiWalletAddress, config.SuiPrivateKey), nil
+	}
+
+	endpoint := config.SuiRPCEndpoint
+	if config.ChainBackend == chain.MockBackendName {
+		endpoint = os.Getenv("K3S_DAAS_MOCK_CHAIN_URL")
+		if endpoint == "" && config.NautilusEndpoint != "" {
+			endpoint = strings.TrimRight(config.NautilusEndpoint, "/") + "/api/v1/mockchain"
+		}
+		log.Printf("🧪 mock 체인 사용: %s", endpoint)
+	}
+
+	defaultStake := os.Getenv("K3S_DAAS_CHAIN_DEFAULT_STAKE")
+	if defaultStake == "" {
+		defaultStake = fmt.Sprintf("%d", config.StakeAmount)
+	}
+	return chain.Open(config.ChainBackend, chain.Config{
+		Endpoint:   endpoint,
+		Package:    config.ContractAddress,
+		Sender:     config.SuiWalletAddress,
+		PrivateKey: config.SuiPrivateKey,
+		Options: map[string]string{
+			"default_stake": defaultStake,
+			"min_stake":     fmt.Sprintf("%d", config.MinStakeAmount),
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	sui "github.com/k3s-io/daas-sui"
+)
+
+// 시계 오차 기준 (Seal 토큰 만료 / 재전송 방지 타임스탬프 보호)
+const (
+	clockSkewWarnThreshold = 5 * time.Second
+	clockSkewRecheckPeriod = 5 * time.Minute
+	defaultMaxClockSkew    = 30 // 초
+)
+
+/*
+fetchChainTime - Sui 최신 체크포인트의 타임스탬프 조회
+체크포인트 시각은 검증자 합의로 정해지므로 로컬 시계 검증 기준으로 사용합니다.
+mock 체인은 로컬 시계를 그대로 쓰므로 오차가 없습니다.
+*/
+func (s *StakerHost) fetchChainTime() (time.Time, error) {
+	if s.suiClient.backend != nil && s.suiClient.backend.Name() != sui.BackendName {
+		return time.Now(), nil
+	}
+	return s.suiClient.chain.ChainTime(context.Background())
+}
+
+/*
+suiRPC - 단순 Sui JSON-RPC 조회 호출 (공용 Sui 클라이언트 사용)
+*/
+func (s *StakerHost) suiRPC(method string, params []interface{}, result interface{}) error {
+	return s.suiClient.chain.Call(context.Background(), method, params, result)
+}
+
+/*
+ensureClockSync - 보안 민감 작업(스테이킹, 등록, 하트비트) 전 시계 오차 확인
+
+최근 측정값이 오래됐으면 다시 측정하고, 오차가 임계값을 넘으면 경고,
+최대 허용치를 넘으면 오류를 반환하여 작업을 거부합니다.
+RPC 장애로 측정에 실패한 경우에는 마지막 측정값을 기준으로 판단합니다.
+*/
+func (s *StakerHost) ensureClockSync() error {
+	if time.Since(s.clockCheckedAt) > clockSkewRecheckPeriod {
+		chainNow, err := s.fetchChainTime()
+		if err != nil {
+			log.Printf("⚠️ 체인 시각 조회 실패, 마지막 측정값 사용: %v", err)
+		} else {
+			s.clockSkew = time.Since(chainNow)
+		}
+		s.clockCheckedAt = time.Now()
+	}
+
+	skew := s.clockSkew
+	if skew < 0 {
+		skew = -skew
+	}
+
+	maxSkew := time.Duration(s.config.MaxClockSkewSeconds) * time.Second
+	if skew > maxSkew {
+		return fmt.Errorf("시계 오차 %s가 허용치 %s를 초과합니다 (NTP 동기화 필요)", s.clockSkew.Round(time.Millisecond), maxSkew)
+	}
+	if skew > clockSkewWarnThreshold {
+		log.Printf("⚠️ 시계 오차 경고: %s (허용치 %s)", s.clockSkew.Round(time.Millisecond), maxSkew)
+	}
+	return nil
+}
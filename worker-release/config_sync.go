@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWorkerConfigPath  = "/var/lib/k3s-daas-agent/worker-config.json"
+	defaultHeartbeatInterval = 30 * time.Second
+	minSyncedHeartbeat       = 10  // 초 (마스터 검증 범위와 동일)
+	maxSyncedHeartbeat       = 600 // 초
+)
+
+/*
+workerSettings - 마스터가 중앙 관리하는 워커 설정 (nautilus-release WorkerSettings와 같은 JSON)
+체크섬을 같은 직렬화로 다시 계산하므로 필드 순서와 태그를 마스터와 맞춰야 합니다.
+비어 있는 필드는 로컬 설정(staker-config.json)을 그대로 씁니다.
+*/
+type workerSettings struct {
+	RegistryMirrors          []string        `json:"registry_mirrors,omitempty"`
+	HeartbeatIntervalSeconds int             `json:"heartbeat_interval_seconds,omitempty"`
+	FeatureGates             map[string]bool `json:"feature_gates,omitempty"`
+}
+
+// workerConfigBundle - 하트비트 응답으로 받은 설정 번들 (changed: 적용 중인 버전 대비 바뀐 필드)
+type workerConfigBundle struct {
+	Version  int64          `json:"version"`
+	Settings workerSettings `json:"settings"`
+	Checksum string         `json:"checksum"`
+	Changed  []string       `json:"changed,omitempty"`
+}
+
+// configFailure - 거부한 번들 (다음 하트비트로 마스터에 보고)
+type configFailure struct {
+	Version int64  `json:"version"`
+	Error   string `json:"error"`
+}
+
+/*
+configSync - 마스터 설정 번들의 검증, 원자적 적용, 실패 시 롤백
+적용된 번들은 파일에 저장되어 재시작 후에도 유지되며, runtime 모드 프로세스도 같은 파일을 읽습니다.
+*/
+type configSync struct {
+	mu      sync.Mutex
+	path    string
+	applied workerConfigBundle // 마지막으로 적용에 성공한 번들 (Version 0 = 없음)
+	failure *configFailure
+}
+
+// applyConfigSyncDefaults - 번들 저장 경로 (K3S_DAAS_WORKER_CONFIG_PATH)
+func applyConfigSyncDefaults(config *StakerHostConfig) {
+	if path := os.Getenv("K3S_DAAS_WORKER_CONFIG_PATH"); path != "" {
+		config.WorkerConfigPath = path
+	}
+	if config.WorkerConfigPath == "" {
+		config.WorkerConfigPath = defaultWorkerConfigPath
+	}
+}
+
+// newConfigSync - 저장된 번들 복원 (손상되었거나 검증에 실패하면, ConfigSync 게이트가 꺼져 있으면 로컬 설정으로 시작)
+func newConfigSync(path string) *configSync {
+	c := &configSync{path: path}
+	if !features.Enabled(featureConfigSync) {
+		return c
+	}
+	var bundle workerConfigBundle
+	if err := loadStateFile(path, &bundle); err != nil {
+		log.Printf("⚠️ 저장된 워커 설정 번들 읽기 실패, 로컬 설정 사용: %v", err)
+		return c
+	}
+	if bundle.Version == 0 {
+		return c
+	}
+	if err := bundle.validate(); err != nil {
+		log.Printf("⚠️ 저장된 워커 설정 v%d 무시: %v", bundle.Version, err)
+		return c
+	}
+	c.applied = bundle
+	log.Printf("🧩 워커 설정 v%d 복원", bundle.Version)
+	return c
+}
+
+// validate - 체크섬과 값 범위 확인 (실패한 번들은 일부도 적용하지 않음)
+func (b workerConfigBundle) validate() error {
+	data, err := json.Marshal(b.Settings)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != b.Checksum {
+		return fmt.Errorf("체크섬 불일치")
+	}
+
+	for _, mirror := range b.Settings.RegistryMirrors {
+		endpoint, err := url.Parse(mirror)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("잘못된 미러 주소: %s", mirror)
+		}
+	}
+	if interval := b.Settings.HeartbeatIntervalSeconds; interval != 0 && (interval < minSyncedHeartbeat || interval > maxSyncedHeartbeat) {
+		return fmt.Errorf("하트비트 주기 %d초는 %d~%d초 범위를 벗어남", interval, minSyncedHeartbeat, maxSyncedHeartbeat)
+	}
+	return nil
+}
+
+// current - 적용 중인 번들과 마지막 실패 (하트비트 보고용)
+func (c *configSync) current() (workerConfigBundle, *configFailure) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.applied, c.failure
+}
+
+// settings - 적용 중인 설정
+func (c *configSync) settings() workerSettings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.applied.Settings
+}
+
+/*
+apply - 번들 검증 → 파일 저장(임시 파일 + rename) → 메모리 교체
+어느 단계든 실패하면 이전 번들을 유지하고 실패 버전을 기록합니다. 반환값은 이전 설정입니다.
+*/
+func (c *configSync) apply(bundle workerConfigBundle) (workerSettings, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.applied.Settings
+	if bundle.Version <= c.applied.Version {
+		return previous, fmt.Errorf("v%d는 적용 중인 v%d보다 새 버전이 아님", bundle.Version, c.applied.Version)
+	}
+
+	err := bundle.validate()
+	if err == nil {
+		stored := bundle
+		stored.Changed = nil
+		err = writeStateFile(c.path, stored)
+	}
+	if err != nil {
+		c.failure = &configFailure{Version: bundle.Version, Error: err.Error()}
+		return previous, err
+	}
+
+	c.applied = bundle
+	c.applied.Changed = nil
+	c.failure = nil
+	return previous, nil
+}
+
+/*
+applyConfigBundle - 하트비트 응답의 설정 번들 적용
+바뀐 필드만 반영합니다. 이미지 미러는 registries.yaml을 다시 쓰는 다음 K3s Agent 시작부터 사용됩니다.
+*/
+func (s *StakerHost) applyConfigBundle(bundle workerConfigBundle) {
+	previous, err := s.configSync.apply(bundle)
+	if err != nil {
+		log.Printf("❌ 워커 설정 v%d 거부, 이전 설정 유지: %v", bundle.Version, err)
+		return
+	}
+
+	changed := bundle.Changed
+	if changed == nil {
+		changed = []string{"registry_mirrors", "heartbeat_interval_seconds", "feature_gates"}
+	}
+	for _, field := range changed {
+		switch field {
+		case "heartbeat_interval_seconds":
+			if interval := s.heartbeatInterval(); s.heartbeatTicker != nil {
+				s.heartbeatTicker.Reset(interval)
+				log.Printf("💓 하트비트 주기 변경: %s", interval)
+			}
+		case "registry_mirrors":
+			if strings.Join(previous.RegistryMirrors, ",") != strings.Join(bundle.Settings.RegistryMirrors, ",") {
+				log.Printf("📦 이미지 미러 변경 (다음 K3s Agent 시작 시 적용): %v", bundle.Settings.RegistryMirrors)
+			}
+		case "feature_gates":
+			log.Printf("🚩 기능 게이트: %v", bundle.Settings.FeatureGates)
+		}
+	}
+	log.Printf("🧩 워커 설정 v%d 적용 완료 (변경: %s)", bundle.Version, strings.Join(changed, ", "))
+}
+
+// heartbeatInterval - 중앙 설정의 하트비트 주기 (없으면 30초)
+func (s *StakerHost) heartbeatInterval() time.Duration {
+	if seconds := s.configSync.settings().HeartbeatIntervalSeconds; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultHeartbeatInterval
+}
+
+// registryMirrorEndpoints - 중앙 설정 미러가 있으면 우선, 없으면 로컬 registry_mirrors
+func (s *StakerHost) registryMirrorEndpoints() []string {
+	if mirrors := s.configSync.settings().RegistryMirrors; len(mirrors) > 0 {
+		return mirrors
+	}
+	return s.config.RegistryMirrors
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// 엔드포인트 장애 조치 기본값
+const (
+	defaultDNSCachePath = "/var/lib/k3s-daas-agent/dns-cache.json"
+
+	dnsCacheTTL         = 60 * time.Second // 이 시간 안의 조회 결과는 DNS를 다시 묻지 않음
+	dnsLookupTimeout    = 3 * time.Second
+	endpointDialTimeout = 5 * time.Second // 죽은 주소에서 오래 기다리지 않고 다음 주소로 넘어감
+	blacklistBase       = 5 * time.Second // 첫 실패 후 차단 시간 (연속 실패마다 두 배)
+	blacklistMax        = 5 * time.Minute
+)
+
+/*
+applyEndpointDefaults - 예비 엔드포인트와 DNS 캐시 설정
+- K3S_DAAS_NAUTILUS_FALLBACK_ENDPOINTS: 쉼표로 구분한 예비 마스터 주소
+- K3S_DAAS_SUI_RPC_FALLBACK_ENDPOINTS: 쉼표로 구분한 예비 Sui RPC 주소
+- K3S_DAAS_DNS_CACHE: DNS 캐시 파일 경로
+*/
+func applyEndpointDefaults(config *StakerHostConfig) error {
+	if value := os.Getenv("K3S_DAAS_NAUTILUS_FALLBACK_ENDPOINTS"); value != "" {
+		config.NautilusFallbackEndpoints = splitEndpointList(value)
+	}
+	if value := os.Getenv("K3S_DAAS_SUI_RPC_FALLBACK_ENDPOINTS"); value != "" {
+		config.SuiRPCFallbackEndpoints = splitEndpointList(value)
+	}
+	if path := os.Getenv("K3S_DAAS_DNS_CACHE"); path != "" {
+		config.DNSCachePath = path
+	}
+	if config.DNSCachePath == "" {
+		config.DNSCachePath = defaultDNSCachePath
+	}
+
+	for _, endpoint := range append(append([]string{}, config.NautilusFallbackEndpoints...), config.SuiRPCFallbackEndpoints...) {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("잘못된 예비 엔드포인트: %q (scheme://host 형식 필요)", endpoint)
+		}
+	}
+	return nil
+}
+
+func splitEndpointList(value string) []string {
+	var endpoints []string
+	for _, endpoint := range strings.Split(value, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+/*
+workerNetwork - 마스터/Sui RPC 호출이 공유하는 네트워크 계층
+
+DNS나 마스터 주소 하나에 문제가 생겨도 워커가 멈추지 않도록
+  - 조회한 주소를 파일에 캐시해 DNS 장애 시 마지막으로 알던 주소로 접속하고
+  - 설정된 주소 목록(기본 + 예비) 중 건강한 주소로 요청을 보내며
+  - 실패한 주소는 연속 실패 횟수에 따라 점점 길게 차단합니다.
+
+요청 URL은 기본 주소(nautilus_endpoint, sui_rpc_endpoint) 그대로 두면
+전송 계층이 선택된 주소로 바꿔 보냅니다.
+*/
+type workerNetwork struct {
+	dns       *dnsCache
+	master    *endpointPool
+	suiRPC    *endpointPool
+	transport http.RoundTripper
+}
+
+func newWorkerNetwork(config *StakerHostConfig) *workerNetwork {
+	cache := newDNSCache(config.DNSCachePath)
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.DialContext = cache.DialContext
+
+	network := &workerNetwork{
+		dns:    cache,
+		master: newEndpointPool("마스터", append([]string{config.NautilusEndpoint}, config.NautilusFallbackEndpoints...)),
+		suiRPC: newEndpointPool("Sui RPC", append([]string{config.SuiRPCEndpoint}, config.SuiRPCFallbackEndpoints...)),
+	}
+	network.transport = &failoverTransport{
+		pools: []*endpointPool{network.master, network.suiRPC},
+		next:  base,
+	}
+	return network
+}
+
+// restyClient - 장애 조치 전송 계층을 쓰는 마스터 호출용 클라이언트
+func (s *StakerHost) restyClient() *resty.Client {
+	if s.network == nil {
+		return resty.New()
+	}
+	return resty.NewWithClient(&http.Client{Transport: s.network.transport})
+}
+
+// masterURL - 지금 사용할 마스터 주소 (K3s agent --server 등 URL을 직접 넘기는 곳용)
+func (s *StakerHost) masterURL() string {
+	if s.network == nil {
+		return s.config.NautilusEndpoint
+	}
+	return s.network.master.current()
+}
+
+// endpointHealth - 주소 하나의 상태 (상태 API에 그대로 노출)
+type endpointHealth struct {
+	URL              string    `json:"url"`
+	Failures         int       `json:"consecutive_failures"`
+	BlacklistedUntil time.Time `json:"blacklisted_until,omitempty"`
+	LastError        string    `json:"last_error,omitempty"`
+	LastSuccess      time.Time `json:"last_success,omitempty"`
+}
+
+/*
+endpointPool - 같은 서비스를 제공하는 주소 목록
+
+설정 순서가 우선순위입니다. 차단되지 않은 첫 주소를 쓰고,
+모두 차단되었으면 차단이 가장 먼저 풀리는 주소부터 다시 시도합니다.
+*/
+type endpointPool struct {
+	name      string
+	mu        sync.Mutex
+	endpoints []*endpointHealth
+}
+
+func newEndpointPool(name string, urls []string) *endpointPool {
+	pool := &endpointPool{name: name}
+	seen := make(map[string]bool)
+	for _, raw := range urls {
+		raw = strings.TrimRight(raw, "/")
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		pool.endpoints = append(pool.endpoints, &endpointHealth{URL: raw})
+	}
+	return pool
+}
+
+// candidates - 시도할 주소 순서 (건강한 주소를 설정 순서대로, 그다음 차단 해제가 빠른 순)
+func (p *endpointPool) candidates() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var healthy []string
+	var blocked []*endpointHealth
+	for _, endpoint := range p.endpoints {
+		if now.Before(endpoint.BlacklistedUntil) {
+			blocked = append(blocked, endpoint)
+		} else {
+			healthy = append(healthy, endpoint.URL)
+		}
+	}
+	sort.SliceStable(blocked, func(i, j int) bool {
+		return blocked[i].BlacklistedUntil.Before(blocked[j].BlacklistedUntil)
+	})
+	for _, endpoint := range blocked {
+		healthy = append(healthy, endpoint.URL)
+	}
+	return healthy
+}
+
+func (p *endpointPool) current() string {
+	if candidates := p.candidates(); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// report - 요청 결과 기록 (실패 시 5초부터 두 배씩 최대 5분 차단, 성공 시 초기화)
+func (p *endpointPool) report(endpointURL string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL != endpointURL {
+			continue
+		}
+		if err == nil {
+			if endpoint.Failures > 0 {
+				log.Printf("✅ %s 엔드포인트 복구: %s (연속 실패 %d회 후)", p.name, endpoint.URL, endpoint.Failures)
+			}
+			endpoint.Failures = 0
+			endpoint.BlacklistedUntil = time.Time{}
+			endpoint.LastSuccess = time.Now()
+			return
+		}
+
+		endpoint.Failures++
+		backoff := blacklistMax
+		if endpoint.Failures <= 7 {
+			backoff = blacklistBase << (endpoint.Failures - 1)
+			if backoff > blacklistMax {
+				backoff = blacklistMax
+			}
+		}
+		endpoint.BlacklistedUntil = time.Now().Add(backoff)
+		endpoint.LastError = err.Error()
+		if len(p.endpoints) > 1 {
+			log.Printf("🚫 %s 엔드포인트 %s %s 동안 제외 (연속 실패 %d회): %v", p.name, endpoint.URL, backoff, endpoint.Failures, err)
+		}
+		return
+	}
+}
+
+// status - 주소별 상태 복사본
+func (p *endpointPool) status() []endpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := make([]endpointHealth, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		status = append(status, *endpoint)
+	}
+	return status
+}
+
+// match - 요청 URL이 이 풀의 주소로 시작하면 나머지 경로/쿼리 반환
+func (p *endpointPool) match(target *url.URL) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	raw := target.String()
+	for _, endpoint := range p.endpoints {
+		if !strings.HasPrefix(raw, endpoint.URL) {
+			continue
+		}
+		rest := raw[len(endpoint.URL):]
+		if rest == "" || rest[0] == '/' || rest[0] == '?' {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+/*
+failoverTransport - 풀에 속한 주소로 가는 요청을 건강한 주소로 보내는 RoundTripper
+
+연결 실패나 게이트웨이 오류(502/503/504)면 해당 주소를 차단하고 다음 주소로 재시도합니다.
+본문을 다시 만들 수 없는 요청(GetBody 없음)은 한 번만 시도합니다.
+*/
+type failoverTransport struct {
+	pools []*endpointPool
+	next  http.RoundTripper
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, pool := range t.pools {
+		if rest, ok := pool.match(req.URL); ok {
+			return pool.roundTrip(t.next, req, rest)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (p *endpointPool) roundTrip(next http.RoundTripper, req *http.Request, rest string) (*http.Response, error) {
+	candidates := p.candidates()
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		candidates = candidates[:1]
+	}
+
+	var lastErr error
+	for i, endpointURL := range candidates {
+		target, err := url.Parse(endpointURL + rest)
+		if err != nil {
+			return nil, err
+		}
+		attempt := req.Clone(req.Context())
+		attempt.URL = target
+		attempt.Host = ""
+		if i > 0 && req.GetBody != nil {
+			if attempt.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := next.RoundTrip(attempt)
+		if err != nil {
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			p.report(endpointURL, err)
+			lastErr = err
+			continue
+		}
+
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			p.report(endpointURL, fmt.Errorf("HTTP %d", resp.StatusCode))
+			if i < len(candidates)-1 {
+				resp.Body.Close()
+				continue
+			}
+		default:
+			p.report(endpointURL, nil)
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// dnsEntry - 호스트 이름 하나의 조회 결과
+type dnsEntry struct {
+	Addrs      []string  `json:"addrs"`
+	ResolvedAt time.Time `json:"resolved_at"`
+
+	staleLogged bool
+}
+
+/*
+dnsCache - 노드 로컬 DNS 캐시
+
+TTL 안에서는 DNS를 다시 묻지 않고, 조회가 실패하면 오래된 결과라도 사용합니다.
+결과는 파일에 저장되므로 DNS가 내려간 상태에서 재시작해도 마스터에 접속할 수 있습니다.
+*/
+type dnsCache struct {
+	path     string
+	resolver *net.Resolver
+	dialer   *net.Dialer
+	mu       sync.Mutex
+	entries  map[string]*dnsEntry
+}
+
+func newDNSCache(path string) *dnsCache {
+	cache := &dnsCache{
+		path:     path,
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{Timeout: endpointDialTimeout, KeepAlive: 30 * time.Second},
+		entries:  make(map[string]*dnsEntry),
+	}
+	if err := loadStateFile(path, &cache.entries); err != nil {
+		log.Printf("⚠️ DNS 캐시 로드 실패, 빈 캐시로 시작: %v", err)
+		cache.entries = make(map[string]*dnsEntry)
+	}
+	return cache
+}
+
+// DialContext - 캐시된 주소로 연결 (주소가 여러 개면 순서대로 시도)
+func (c *dnsCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil || host == "localhost" {
+		return c.dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, ip := range addrs {
+		conn, err := c.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry := c.entries[host]
+	c.mu.Unlock()
+	if entry != nil && time.Since(entry.ResolvedAt) < dnsCacheTTL {
+		return entry.Addrs, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	addrs, err := c.resolver.LookupHost(lookupCtx, host)
+	cancel()
+	if err != nil || len(addrs) == 0 {
+		if entry == nil {
+			return nil, fmt.Errorf("DNS 조회 실패 (캐시 없음): %s: %v", host, err)
+		}
+		c.mu.Lock()
+		if !entry.staleLogged {
+			entry.staleLogged = true
+			log.Printf("⚠️ DNS 조회 실패, 캐시된 주소 사용: %s → %s (%s 전 조회): %v",
+				host, strings.Join(entry.Addrs, ","), time.Since(entry.ResolvedAt).Round(time.Second), err)
+		}
+		c.mu.Unlock()
+		return entry.Addrs, nil
+	}
+
+	changed := entry == nil || strings.Join(entry.Addrs, ",") != strings.Join(addrs, ",")
+	c.mu.Lock()
+	c.entries[host] = &dnsEntry{Addrs: addrs, ResolvedAt: time.Now()}
+	c.mu.Unlock()
+	if changed {
+		c.save()
+	}
+	return addrs, nil
+}
+
+// save - 캐시 파일 갱신 (주소가 바뀐 경우에만 호출)
+func (c *dnsCache) save() {
+	c.mu.Lock()
+	snapshot := make(map[string]*dnsEntry, len(c.entries))
+	for host, entry := range c.entries {
+		copied := *entry
+		snapshot[host] = &copied
+	}
+	c.mu.Unlock()
+
+	if err := writeStateFile(c.path, snapshot); err != nil {
+		log.Printf("⚠️ DNS 캐시 저장 실패: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	featuregate "github.com/k3s-io/daas-featuregate"
+)
+
+// 워커 기능 게이트 이름 (staker-config.json feature_gates < K3S_DAAS_FEATURE_GATES < --feature-gates)
+const (
+	featureAutoAppeal = "AutoAppeal"
+	featureImageGC    = "ImageGC"
+	featureConfigSync = "ConfigSync"
+)
+
+var features = featuregate.New(map[string]featuregate.Spec{
+	featureAutoAppeal: {
+		Default: false, Stage: featuregate.Alpha,
+		Description: "Submit a slash appeal on-chain with the worker wallet when slashing is confirmed",
+	},
+	featureImageGC: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Remove unused images in LRU order above the disk high watermark",
+	},
+	featureConfigSync: {
+		Default: true, Stage: featuregate.Beta,
+		Description: "Apply config bundles pushed by the master in heartbeat responses",
+	},
+})
+
+/*
+applyFeatureGateDefaults - 설정 파일의 feature_gates 반영
+환경 변수와 플래그는 main에서 먼저 적용되므로, 이미 덮어쓴 게이트는 설정 파일 값으로 되돌리지 않습니다.
+기존 auto_appeal(K3S_DAAS_AUTO_APPEAL)은 AutoAppeal 게이트로 취급합니다.
+*/
+func applyFeatureGateDefaults(config *StakerHostConfig) error {
+	values := make(map[string]bool, len(config.FeatureGates)+1)
+	for name, enabled := range config.FeatureGates {
+		values[name] = enabled
+	}
+	if _, ok := values[featureAutoAppeal]; !ok && config.AutoAppeal {
+		values[featureAutoAppeal] = true
+	}
+
+	for _, state := range features.States() {
+		if state.Source == featuregate.SourceEnv || state.Source == featuregate.SourceFlag {
+			delete(values, state.Name)
+		}
+	}
+	if err := features.SetFromMap(values, featuregate.SourceConfig); err != nil {
+		return fmt.Errorf("feature_gates: %v", err)
+	}
+	return nil
+}
+
+// featureGate - 마스터 설정 번들의 게이트가 있으면 우선, 없으면 로컬 게이트 값
+func (s *StakerHost) featureGate(name string) bool {
+	if enabled, ok := s.configSync.settings().FeatureGates[name]; ok {
+		return enabled
+	}
+	return features.Enabled(name)
+}
+
+// logFeatureGates - 시작 로그에 게이트 상태 출력
+func logFeatureGates() {
+	features.LogSummary(func(format string, args ...interface{}) {
+		log.Printf("🚩 "+format, args...)
+	})
+}
@@ -4,6 +4,13 @@ go 1.21
 
 require (
 	github.com/go-resty/resty/v2 v2.7.0
+	github.com/k3s-io/daas-chain v0.0.0
+	github.com/k3s-io/daas-featuregate v0.0.0
+	github.com/k3s-io/daas-httpserver v0.0.0
+	github.com/k3s-io/daas-service v0.0.0
+	github.com/k3s-io/daas-sui v0.0.0
+	github.com/k3s-io/daas-version v0.0.0
+	github.com/k3s-io/daas-workproof v0.0.0
 	github.com/k3s-io/k3s v1.28.3-0.20230919131847-6330a5b49cfe
 	k8s.io/client-go v0.28.2
 )
@@ -52,3 +59,24 @@ require (
 
 // 로컬 K3s 패키지 참조
 replace github.com/k3s-io/k3s => ../k3s-daas/pkg-reference
+
+// 공용 Sui 클라이언트
+replace github.com/k3s-io/daas-sui => ../pkg/sui
+
+// 공용 체인 백엔드 인터페이스 (Sui/mock)
+replace github.com/k3s-io/daas-chain => ../pkg/chain
+
+// 공용 기능 게이트 (--feature-gates)
+replace github.com/k3s-io/daas-featuregate => ../pkg/featuregate
+
+// 공용 HTTP 리스너 (TLS/리다이렉트/HSTS)
+replace github.com/k3s-io/daas-httpserver => ../pkg/httpserver
+
+// 공용 서비스 관리 (systemd 유닛 / Windows 서비스)
+replace github.com/k3s-io/daas-service => ../pkg/service
+
+// 공용 빌드 버전 / 구성요소 간 버전 차이 정책
+replace github.com/k3s-io/daas-version => ../pkg/version
+
+// 하트비트 챌린지 (nonce 서명, 메모리 의존 작업 증명)
+replace github.com/k3s-io/daas-workproof => ../pkg/workproof
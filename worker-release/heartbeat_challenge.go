@@ -0,0 +1,119 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	workproof "github.com/k3s-io/daas-workproof"
+)
+
+// 기본 챌린지 메모리 상한 (마스터 요청이 이보다 크면 답하지 않음)
+const defaultChallengeMaxMemoryMiB = 256
+
+/*
+heartbeatChallenge - 마스터가 하트비트 응답으로 보내는 챌린지 (마스터 HeartbeatChallenge와 동일)
+
+마스터가 HeartbeatChallenges 기능을 켜면 라운드마다 nonce와 메모리 크기를 보냅니다.
+워커는 Seal 토큰으로 nonce에 서명하고 memory_mib 크기의 작업 증명을 계산해 다음 하트비트에 첨부합니다.
+마감까지 답하지 못하면 마스터는 실패로 기록하고 노드 건강 점수를 깎습니다.
+*/
+type heartbeatChallenge struct {
+	ID        string    `json:"id"`
+	Nonce     string    `json:"nonce"`
+	MemoryMiB int       `json:"memory_mib"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// challengeAnswer - 다음 하트비트의 challenge_answer
+type challengeAnswer struct {
+	ID        string `json:"id"`
+	Signature string `json:"signature"`
+	Solution  string `json:"solution"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+}
+
+// challengeSolver - 챌린지 계산 (한 번에 하나, 하트비트를 막지 않도록 별도 고루틴)
+type challengeSolver struct {
+	maxMemoryMiB int
+	mutex        sync.Mutex
+	solving      string // 계산 중이거나 답을 준비한 챌린지 ID
+	answer       *challengeAnswer
+}
+
+/*
+newChallengeSolver - 챌린지 계산기 생성
+- K3S_DAAS_CHALLENGE_MAX_MEMORY_MB: 작업 증명에 허용할 최대 메모리 (MiB, 기본 256)
+*/
+func newChallengeSolver() *challengeSolver {
+	solver := &challengeSolver{maxMemoryMiB: defaultChallengeMaxMemoryMiB}
+	if value := os.Getenv("K3S_DAAS_CHALLENGE_MAX_MEMORY_MB"); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
+			solver.maxMemoryMiB = limit
+		} else {
+			log.Printf("⚠️ 잘못된 K3S_DAAS_CHALLENGE_MAX_MEMORY_MB 무시: %s", value)
+		}
+	}
+	return solver
+}
+
+// start - 새 챌린지면 서명과 작업 증명 계산 시작 (같은 챌린지는 한 번만)
+func (c *challengeSolver) start(challenge *heartbeatChallenge, sealToken string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if challenge.ID == c.solving {
+		return
+	}
+	if challenge.MemoryMiB > c.maxMemoryMiB {
+		log.Printf("⚠️ 하트비트 챌린지 %s가 메모리 상한을 넘습니다 (%d MiB > %d MiB), 응답하지 않음",
+			challenge.ID, challenge.MemoryMiB, c.maxMemoryMiB)
+		return
+	}
+	c.solving = challenge.ID
+	c.answer = nil
+
+	go func() {
+		started := time.Now()
+		solution, err := workproof.Solve(challenge.Nonce, challenge.MemoryMiB)
+		if err != nil {
+			log.Printf("❌ 하트비트 챌린지 계산 실패 (%s): %v", challenge.ID, err)
+			return
+		}
+		elapsed := time.Since(started)
+		if time.Now().After(challenge.Deadline) {
+			log.Printf("⚠️ 하트비트 챌린지 %s를 마감 후에 완료했습니다 (%s)", challenge.ID, elapsed.Round(time.Millisecond))
+		}
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if c.solving != challenge.ID {
+			return
+		}
+		c.answer = &challengeAnswer{
+			ID:        challenge.ID,
+			Signature: workproof.Sign(sealToken, challenge.ID, challenge.Nonce),
+			Solution:  solution,
+			ElapsedMs: elapsed.Milliseconds(),
+		}
+		log.Printf("🧩 하트비트 챌린지 %s 완료 (%d MiB, %s)", challenge.ID, challenge.MemoryMiB, elapsed.Round(time.Millisecond))
+	}()
+}
+
+// pending - 아직 보내지 않은 챌린지 응답
+func (c *challengeSolver) pending() *challengeAnswer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.answer
+}
+
+// delivered - 마스터가 응답을 받은 뒤 제거 (전송 실패 시 다음 하트비트에 다시 첨부)
+func (c *challengeSolver) delivered(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.answer != nil && c.answer.ID == id {
+		c.answer = nil
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 하트비트 수집기 기본값
+const (
+	defaultCollectorMaxBytes   = 4 * 1024  // 수집기 하나의 섹션 크기 한도
+	defaultCollectorTotalBytes = 32 * 1024 // 모든 섹션 합계 한도
+	defaultCollectorTimeout    = 5         // 초
+)
+
+/*
+HeartbeatCollector - 하트비트에 이름 있는 섹션을 추가하는 수집기
+
+배포 환경마다 필요한 텔레메트리(GPU 상태, 온도, 업무 지표 등)가 다르므로
+기본 필드(스테이킹, Pod 수, 자원 사용량) 외의 내용은 수집기로 붙입니다.
+Collect 결과는 JSON으로 직렬화되어 하트비트의 collectors.<이름>에 들어갑니다.
+*/
+type HeartbeatCollector interface {
+	Name() string
+	// DefaultEnabled - 설정에 enabled가 없을 때 사용 여부
+	DefaultEnabled() bool
+	Collect(ctx context.Context) (interface{}, error)
+}
+
+/*
+HeartbeatCollectorConfig - 수집기별 설정 (heartbeat_collectors 항목)
+
+내장 수집기(gpu, temperature)는 name으로 켜고 끄며,
+command가 있으면 그 명령의 stdout(JSON)을 섹션으로 보내는 사용자 수집기가 됩니다.
+*/
+type HeartbeatCollectorConfig struct {
+	Name           string   `json:"name"`
+	Enabled        *bool    `json:"enabled,omitempty"`         // 생략 시 수집기 기본값 (사용자 수집기는 사용)
+	MaxBytes       int      `json:"max_bytes,omitempty"`       // 섹션 크기 한도 (기본 4KiB)
+	Command        []string `json:"command,omitempty"`         // 사용자 수집기 명령 (stdout JSON)
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"` // 수집 시간 한도 (기본 5초)
+}
+
+var (
+	collectorsMu      sync.RWMutex
+	builtinCollectors = make(map[string]HeartbeatCollector)
+)
+
+// registerHeartbeatCollector - 내장 수집기 등록 (init에서 호출, 이름 중복 시 panic)
+func registerHeartbeatCollector(collector HeartbeatCollector) {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	if _, exists := builtinCollectors[collector.Name()]; exists {
+		panic("heartbeat collector registered twice: " + collector.Name())
+	}
+	builtinCollectors[collector.Name()] = collector
+}
+
+func init() {
+	registerHeartbeatCollector(gpuCollector{})
+	registerHeartbeatCollector(temperatureCollector{})
+}
+
+// activeCollector - 설정이 반영된 수집기
+type activeCollector struct {
+	collector HeartbeatCollector
+	maxBytes  int
+	timeout   time.Duration
+}
+
+/*
+heartbeatCollectors - 하트비트마다 활성 수집기를 실행하고 크기 한도를 적용
+
+섹션이 자신의 max_bytes를 넘거나 전체 한도(heartbeat_collectors_max_bytes)를
+넘기게 되면 전송 전에 빼고, 이유를 collector_errors에 남겨 마스터가 알 수 있게 합니다.
+*/
+type heartbeatCollectors struct {
+	active     []activeCollector
+	totalBytes int
+}
+
+/*
+newHeartbeatCollectors - 설정으로 활성 수집기 목록 구성
+- K3S_DAAS_HEARTBEAT_COLLECTORS: 쉼표로 구분한 내장 수집기 이름 (설정과 별도로 켬)
+*/
+func newHeartbeatCollectors(configs []HeartbeatCollectorConfig, totalBytes int) (*heartbeatCollectors, error) {
+	if totalBytes <= 0 {
+		totalBytes = defaultCollectorTotalBytes
+	}
+	hc := &heartbeatCollectors{totalBytes: totalBytes}
+
+	settings := make(map[string]HeartbeatCollectorConfig)
+	for _, config := range configs {
+		if config.Name == "" {
+			return nil, fmt.Errorf("heartbeat_collectors 항목에 name이 없습니다")
+		}
+		if _, exists := settings[config.Name]; exists {
+			return nil, fmt.Errorf("heartbeat_collectors에 %s가 중복되었습니다", config.Name)
+		}
+		settings[config.Name] = config
+	}
+	for _, name := range strings.Split(os.Getenv("K3S_DAAS_HEARTBEAT_COLLECTORS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config := settings[name]
+			config.Name = name
+			enabled := true
+			config.Enabled = &enabled
+			settings[name] = config
+		}
+	}
+
+	collectorsMu.RLock()
+	defer collectorsMu.RUnlock()
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	for name := range builtinCollectors {
+		if _, configured := settings[name]; !configured {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		config := settings[name]
+		collector, builtin := builtinCollectors[name]
+		switch {
+		case len(config.Command) > 0:
+			if builtin {
+				return nil, fmt.Errorf("수집기 %s는 내장 수집기라 command를 지정할 수 없습니다", name)
+			}
+			collector = commandCollector{name: name, command: config.Command}
+		case !builtin:
+			return nil, fmt.Errorf("알 수 없는 하트비트 수집기: %s (command 필요)", name)
+		}
+
+		enabled := collector.DefaultEnabled()
+		if config.Enabled != nil {
+			enabled = *config.Enabled
+		}
+		if !enabled {
+			continue
+		}
+
+		active := activeCollector{
+			collector: collector,
+			maxBytes:  config.MaxBytes,
+			timeout:   time.Duration(config.TimeoutSeconds) * time.Second,
+		}
+		if active.maxBytes <= 0 {
+			active.maxBytes = defaultCollectorMaxBytes
+		}
+		if active.timeout <= 0 {
+			active.timeout = defaultCollectorTimeout * time.Second
+		}
+		hc.active = append(hc.active, active)
+	}
+	return hc, nil
+}
+
+// names - 활성 수집기 이름 (상태 API/로그용)
+func (hc *heartbeatCollectors) names() []string {
+	names := make([]string, 0, len(hc.active))
+	for _, active := range hc.active {
+		names = append(names, active.collector.Name())
+	}
+	return names
+}
+
+/*
+collect - 활성 수집기를 병렬로 실행하고 크기 한도를 통과한 섹션만 반환
+전체 한도는 이름 순서대로 채우므로 어떤 섹션이 빠질지 예측할 수 있습니다.
+*/
+func (hc *heartbeatCollectors) collect() (map[string]json.RawMessage, map[string]string) {
+	if hc == nil || len(hc.active) == 0 {
+		return nil, nil
+	}
+
+	type outcome struct {
+		data json.RawMessage
+		err  error
+	}
+	outcomes := make([]outcome, len(hc.active))
+	var wg sync.WaitGroup
+	for i, active := range hc.active {
+		wg.Add(1)
+		go func(i int, active activeCollector) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), active.timeout)
+			defer cancel()
+
+			value, err := active.collector.Collect(ctx)
+			if err != nil {
+				outcomes[i].err = err
+				return
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				outcomes[i].err = fmt.Errorf("직렬화 실패: %v", err)
+				return
+			}
+			outcomes[i].data = data
+		}(i, active)
+	}
+	wg.Wait()
+
+	sections := make(map[string]json.RawMessage)
+	failures := make(map[string]string)
+	used := 0
+	for i, active := range hc.active {
+		name := active.collector.Name()
+		result := outcomes[i]
+		switch {
+		case result.err != nil:
+			failures[name] = result.err.Error()
+		case len(result.data) > active.maxBytes:
+			failures[name] = fmt.Sprintf("섹션 크기 %dB가 한도 %dB를 초과", len(result.data), active.maxBytes)
+		case used+len(result.data) > hc.totalBytes:
+			failures[name] = fmt.Sprintf("전체 수집기 한도 %dB 초과", hc.totalBytes)
+		default:
+			sections[name] = result.data
+			used += len(result.data)
+		}
+	}
+	for name, reason := range failures {
+		log.Printf("⚠️ 하트비트 수집기 %s 제외: %s", name, reason)
+	}
+	return sections, failures
+}
+
+/*
+commandCollector - 사용자 정의 수집기
+명령의 stdout을 JSON으로 그대로 보냅니다 (업무 지표 스크립트 등).
+*/
+type commandCollector struct {
+	name    string
+	command []string
+}
+
+func (c commandCollector) Name() string         { return c.name }
+func (c commandCollector) DefaultEnabled() bool { return true }
+
+func (c commandCollector) Collect(ctx context.Context) (interface{}, error) {
+	output, err := exec.CommandContext(ctx, c.command[0], c.command[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("명령 실행 실패: %v", err)
+	}
+	if !json.Valid(output) {
+		return nil, fmt.Errorf("명령 출력이 JSON이 아닙니다")
+	}
+	return json.RawMessage(output), nil
+}
+
+// gpuCollector - nvidia-smi로 GPU 사용률/메모리/온도 수집 (기본 꺼짐)
+type gpuCollector struct{}
+
+func (gpuCollector) Name() string         { return "gpu" }
+func (gpuCollector) DefaultEnabled() bool { return false }
+
+func (gpuCollector) Collect(ctx context.Context) (interface{}, error) {
+	output, err := exec.CommandContext(ctx, "nvidia-smi",
+		"--query-gpu=index,name,utilization.gpu,memory.used,memory.total,temperature.gpu",
+		"--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi 실행 실패: %v", err)
+	}
+
+	var gpus []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 6 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		gpu := map[string]interface{}{"index": fields[0], "name": fields[1]}
+		for i, key := range []string{"utilization_percent", "memory_used_mib", "memory_total_mib", "temperature_c"} {
+			if value, err := strconv.ParseFloat(fields[i+2], 64); err == nil {
+				gpu[key] = value
+			}
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
+
+// temperatureCollector - /sys/class/thermal의 온도 센서 (리눅스 전용, 기본 꺼짐)
+type temperatureCollector struct{}
+
+func (temperatureCollector) Name() string         { return "temperature" }
+func (temperatureCollector) DefaultEnabled() bool { return false }
+
+func (temperatureCollector) Collect(ctx context.Context) (interface{}, error) {
+	zones, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("온도 센서가 없습니다")
+	}
+
+	readings := make(map[string]float64)
+	for _, zone := range zones {
+		raw, err := os.ReadFile(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+		milli, err := strconv.ParseFloat(strings.TrimSpace(string(raw)), 64)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(zone)
+		if kind, err := os.ReadFile(filepath.Join(zone, "type")); err == nil {
+			name = name + "/" + strings.TrimSpace(string(kind))
+		}
+		readings[name] = milli / 1000
+	}
+	return readings, nil
+}
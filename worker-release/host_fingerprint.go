@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultFingerprintRefreshMinutes = 60
+
+// defaultFingerprintSysctls - 항상 보고하는 커널 설정 (마스터 컴플라이언스 정책이 요구 값을 정함)
+var defaultFingerprintSysctls = []string{
+	"net.ipv4.ip_forward",
+	"net.bridge.bridge-nf-call-iptables",
+	"kernel.kptr_restrict",
+	"kernel.dmesg_restrict",
+	"kernel.unprivileged_bpf_disabled",
+	"kernel.yama.ptrace_scope",
+	"kernel.panic",
+	"kernel.panic_on_oops",
+	"vm.overcommit_memory",
+	"fs.protected_symlinks",
+	"fs.protected_hardlinks",
+}
+
+// HostFingerprintConfig - 호스트 지문 수집 설정
+type HostFingerprintConfig struct {
+	Sysctls        []string `json:"sysctls"`         // 기본 목록에 더해 보고할 sysctl (예: net.core.somaxconn)
+	RefreshMinutes int      `json:"refresh_minutes"` // 지문을 다시 수집하는 주기 (기본 60분, OS/커널은 재부팅 전까지 거의 바뀌지 않음)
+}
+
+/*
+applyHostFingerprintDefaults - 호스트 지문 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_FINGERPRINT_SYSCTLS: 추가로 보고할 sysctl (쉼표 구분)
+*/
+func applyHostFingerprintDefaults(config *StakerHostConfig) error {
+	f := &config.HostFingerprint
+	if value := os.Getenv("K3S_DAAS_FINGERPRINT_SYSCTLS"); value != "" {
+		f.Sysctls = nil
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				f.Sysctls = append(f.Sysctls, key)
+			}
+		}
+	}
+	for _, key := range f.Sysctls {
+		if strings.Contains(key, "/") || strings.Contains(key, "..") {
+			return fmt.Errorf("잘못된 host_fingerprint.sysctls 항목: %s", key)
+		}
+	}
+	if f.RefreshMinutes < 0 {
+		return fmt.Errorf("잘못된 host_fingerprint.refresh_minutes: %d", f.RefreshMinutes)
+	}
+	if f.RefreshMinutes == 0 {
+		f.RefreshMinutes = defaultFingerprintRefreshMinutes
+	}
+	return nil
+}
+
+/*
+HostFingerprint - 호스트 OS/커널/런타임 지문 (하트비트 host_fingerprint)
+
+마스터는 이를 컴플라이언스 정책(허용 OS, 최소 커널, 런타임 버전, 필수 sysctl)과 비교해 위반 노드에
+HostNonCompliant 조건을 걸고 스케줄링 점수를 낮춥니다. Digest가 바뀌면 마스터가 드리프트로 기록합니다.
+*/
+type HostFingerprint struct {
+	OSID           string            `json:"os_id"`                     // /etc/os-release ID (ubuntu, debian, ...)
+	OSVersion      string            `json:"os_version"`                // VERSION_ID
+	OSName         string            `json:"os_name,omitempty"`         // PRETTY_NAME
+	KernelRelease  string            `json:"kernel_release"`            // uname -r
+	CgroupVersion  int               `json:"cgroup_version,omitempty"`  // 1 또는 2
+	K3sVersion     string            `json:"k3s_version,omitempty"`     // k3s --version
+	RuntimeName    string            `json:"runtime_name,omitempty"`    // crictl version RuntimeName
+	RuntimeVersion string            `json:"runtime_version,omitempty"` // crictl version RuntimeVersion
+	Sysctls        map[string]string `json:"sysctls,omitempty"`         // 읽을 수 없는 키는 생략
+	Digest         string            `json:"digest"`
+	CollectedAt    time.Time         `json:"collected_at"`
+}
+
+// computeDigest - 수집 시각을 뺀 지문의 sha256 (json.Marshal은 map 키를 정렬하므로 같은 호스트는 같은 값)
+func (f *HostFingerprint) computeDigest() string {
+	canonical := *f
+	canonical.Digest, canonical.CollectedAt = "", time.Time{}
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// collectHostFingerprint - OS/커널은 플랫폼별 수집, k3s/컨테이너 런타임 버전은 명령으로 확인 (실패한 항목은 비워 둠)
+func collectHostFingerprint(extraSysctls []string) (*HostFingerprint, error) {
+	fingerprint := &HostFingerprint{CollectedAt: time.Now()}
+	keys := append(append([]string(nil), defaultFingerprintSysctls...), extraSysctls...)
+	sort.Strings(keys)
+	if err := collectHostOS(fingerprint, keys); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if path, err := findK3sBinary(); err == nil {
+		if output, err := exec.CommandContext(ctx, path, "--version").Output(); err == nil {
+			// "k3s version v1.28.5+k3s1 (5b2d1271)"
+			if fields := strings.Fields(string(output)); len(fields) >= 3 {
+				fingerprint.K3sVersion = fields[2]
+			}
+		}
+		if output, err := exec.CommandContext(ctx, path, "crictl", "version").Output(); err == nil {
+			fingerprint.RuntimeName, fingerprint.RuntimeVersion = parseCrictlVersion(output)
+		}
+	}
+
+	fingerprint.Digest = fingerprint.computeDigest()
+	return fingerprint, nil
+}
+
+// parseCrictlVersion - "RuntimeName:  containerd" / "RuntimeVersion:  v1.7.11-k3s2" 줄
+func parseCrictlVersion(output []byte) (string, string) {
+	var name, version string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "RuntimeName":
+			name = strings.TrimSpace(value)
+		case "RuntimeVersion":
+			version = strings.TrimSpace(value)
+		}
+	}
+	return name, version
+}
+
+// parseOSRelease - /etc/os-release의 KEY=value (따옴표 제거)
+func parseOSRelease(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// hostFingerprinter - 마지막 지문 캐시 (refresh 주기마다 다시 수집, 실패하면 이전 지문 유지)
+type hostFingerprinter struct {
+	refresh time.Duration
+
+	mu          sync.Mutex
+	fingerprint *HostFingerprint
+	fetchedAt   time.Time
+}
+
+func newHostFingerprinter(config HostFingerprintConfig) *hostFingerprinter {
+	return &hostFingerprinter{refresh: time.Duration(config.RefreshMinutes) * time.Minute}
+}
+
+func (h *hostFingerprinter) get(fetch func() (*HostFingerprint, error)) (*HostFingerprint, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.fingerprint == nil || time.Since(h.fetchedAt) >= h.refresh {
+		fingerprint, err := fetch()
+		if err != nil {
+			log.Printf("⚠️ 호스트 지문 수집 실패: %v", err)
+		} else {
+			if h.fingerprint != nil && h.fingerprint.Digest != fingerprint.Digest {
+				log.Printf("🧬 호스트 지문 변경: %s %s, 커널 %s", fingerprint.OSID, fingerprint.OSVersion, fingerprint.KernelRelease)
+			}
+			h.fingerprint = fingerprint
+		}
+		// 실패해도 매 하트비트마다 명령을 실행하지 않도록 시각은 갱신
+		h.fetchedAt = time.Now()
+	}
+	return h.fingerprint, h.fingerprint != nil
+}
+
+// hostFingerprint - 하트비트용 호스트 지문 (staking 모드는 런타임 호스트에서 수집)
+func (s *StakerHost) hostFingerprint() (*HostFingerprint, bool) {
+	return s.fingerprint.get(func() (*HostFingerprint, error) {
+		if s.runtimeClient != nil {
+			return s.runtimeClient.HostFingerprint()
+		}
+		return collectHostFingerprint(s.config.HostFingerprint.Sysctls)
+	})
+}
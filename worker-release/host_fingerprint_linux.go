@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// collectHostOS - /etc/os-release, 커널 릴리스, cgroup 계층, sysctl 값
+func collectHostOS(fingerprint *HostFingerprint, sysctls []string) error {
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		data, err = os.ReadFile("/usr/lib/os-release")
+	}
+	if err != nil {
+		return fmt.Errorf("os-release 읽기 실패: %v", err)
+	}
+	release := parseOSRelease(data)
+	fingerprint.OSID = release["ID"]
+	fingerprint.OSVersion = release["VERSION_ID"]
+	fingerprint.OSName = release["PRETTY_NAME"]
+
+	kernel, err := readSysctl("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return fmt.Errorf("커널 릴리스 읽기 실패: %v", err)
+	}
+	fingerprint.KernelRelease = kernel
+
+	fingerprint.CgroupVersion = 1
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err == nil {
+		fingerprint.CgroupVersion = 2
+	}
+
+	fingerprint.Sysctls = make(map[string]string, len(sysctls))
+	for _, key := range sysctls {
+		// 모듈이 로드되지 않은 키(bridge-nf-call-iptables 등)는 생략해 마스터가 누락으로 판단
+		if value, err := readSysctl("/proc/sys/" + strings.ReplaceAll(key, ".", "/")); err == nil {
+			fingerprint.Sysctls[key] = value
+		}
+	}
+	return nil
+}
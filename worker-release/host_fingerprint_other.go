@@ -0,0 +1,11 @@
+//go:build !linux
+
+package main
+
+import "runtime"
+
+// collectHostOS - os-release와 /proc/sys는 Linux에만 있으므로 OS 이름만 보고 (마스터 정책에서 미지원 OS로 판정)
+func collectHostOS(fingerprint *HostFingerprint, sysctls []string) error {
+	fingerprint.OSID = runtime.GOOS
+	return nil
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 이미지 GC 기본값
+const (
+	defaultImageGCInterval  = 5 * 60 // 초
+	defaultImageMinAge      = 2 * 60 // 초 (kubelet --image-minimum-gc-age 기본값과 같음)
+	defaultImageUsageState  = "/var/lib/k3s-daas-agent/image-usage.json"
+	containerdPinnedLabel   = "io.cri-containerd.pinned=pinned"
+	defaultPinnedPauseImage = "rancher/mirrored-pause"
+)
+
+/*
+ImageGCPolicy - 컨테이너 이미지 GC 설정
+
+이미지 파일시스템 사용률이 high 워터마크(resource_reservation.image_gc_high_percent)를 넘으면
+사용하지 않는 이미지를 마지막 사용 시각이 오래된 순서(LRU)로 지워 low 워터마크까지 낮춥니다.
+실행 중이거나 멈춘 컨테이너가 참조하는 이미지, 고정 이미지, min_age보다 최근에 쓰인 이미지는 지우지 않습니다.
+*/
+type ImageGCPolicy struct {
+	IntervalSeconds int      `json:"interval_seconds"`
+	MinAgeSeconds   int      `json:"min_age_seconds"`
+	PinnedImages    []string `json:"pinned_images"`    // 참조 접두사 (예: rancher/mirrored-pause)
+	UsageStatePath  string   `json:"usage_state_path"` // 이미지별 마지막 사용 시각 기록
+}
+
+/*
+applyImageGCDefaults - 이미지 GC 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_IMAGE_GC_HIGH, K3S_DAAS_IMAGE_GC_LOW: 디스크 사용률 워터마크 (%)
+- K3S_DAAS_IMAGE_GC_INTERVAL: 점검 주기 (초)
+*/
+func applyImageGCDefaults(config *StakerHostConfig) error {
+	p := &config.ImageGC
+	for env, field := range map[string]*int{
+		"K3S_DAAS_IMAGE_GC_HIGH":     &config.Resources.ImageGCHighPercent,
+		"K3S_DAAS_IMAGE_GC_LOW":      &config.Resources.ImageGCLowPercent,
+		"K3S_DAAS_IMAGE_GC_INTERVAL": &p.IntervalSeconds,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("잘못된 %s: %s", env, value)
+		}
+		*field = parsed
+	}
+
+	if p.IntervalSeconds <= 0 {
+		p.IntervalSeconds = defaultImageGCInterval
+	}
+	if p.MinAgeSeconds <= 0 {
+		p.MinAgeSeconds = defaultImageMinAge
+	}
+	if p.UsageStatePath == "" {
+		p.UsageStatePath = defaultImageUsageState
+	}
+	if len(p.PinnedImages) == 0 {
+		p.PinnedImages = []string{defaultPinnedPauseImage}
+	}
+	return nil
+}
+
+/*
+ContainerImage - 런타임의 이미지 하나
+같은 이미지를 가리키는 태그/다이제스트 참조는 하나로 묶습니다.
+*/
+type ContainerImage struct {
+	ID        string   `json:"id"`
+	Refs      []string `json:"refs"`
+	SizeBytes uint64   `json:"size_bytes"`
+	InUse     bool     `json:"in_use"` // 컨테이너(종료된 것 포함)가 참조 중
+	Pinned    bool     `json:"pinned"` // 런타임이 고정한 이미지 (pause 등)
+}
+
+// ImageGCStats - 노드 메트릭으로 노출하는 이미지 GC 상태
+type ImageGCStats struct {
+	Images         int     `json:"images"`
+	ImageBytes     uint64  `json:"image_bytes"`
+	UnusedImages   int     `json:"unused_images"`
+	DiskPercent    float64 `json:"disk_percent"`
+	HighPercent    int     `json:"high_percent"`
+	LowPercent     int     `json:"low_percent"`
+	LastRun        int64   `json:"last_run,omitempty"`
+	LastCollection int64   `json:"last_collection,omitempty"`
+	RemovedTotal   int     `json:"removed_total"`
+	ReclaimedBytes uint64  `json:"reclaimed_bytes"`
+	LastError      string  `json:"last_error,omitempty"`
+}
+
+/*
+imageGC - 이미지 사용 기록과 GC 실행
+런타임은 이미지의 마지막 사용 시각을 알려주지 않으므로 점검할 때마다 사용 중인 이미지를 기록하고,
+재시작 후에도 LRU 순서가 유지되도록 파일에 저장합니다.
+*/
+type imageGC struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time // 이미지 ID 기준
+	loaded   bool
+	stats    ImageGCStats
+}
+
+// snapshot - 현재 통계 (nil이면 빈 값)
+func (g *imageGC) snapshot() ImageGCStats {
+	if g == nil {
+		return ImageGCStats{}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stats
+}
+
+// imageFSPath - 런타임의 이미지 저장 경로 (사용률 측정 기준)
+func (s *StakerHost) imageFSPath() string {
+	if s.config.ContainerRuntime == "docker" {
+		return "/var/lib/docker"
+	}
+	return "/var/lib/containerd"
+}
+
+// runImageGC - 주기적 이미지 GC (컨테이너 런타임을 직접 가진 combined/runtime 모드에서만)
+func (s *StakerHost) runImageGC(ctx context.Context) {
+	if s.images == nil || s.k3sAgent == nil || s.k3sAgent.runtime == nil {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(s.config.ImageGC.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		s.collectImages(false)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+collectImages - 이미지 사용 기록 갱신 후 필요하면 LRU 순서로 미사용 이미지 제거
+force면 high 워터마크와 관계없이 low 워터마크까지 정리합니다 (디스크 압박 감지 시).
+*/
+func (s *StakerHost) collectImages(force bool) {
+	if s.images == nil || s.k3sAgent == nil || s.k3sAgent.runtime == nil || !s.featureGate(featureImageGC) {
+		return
+	}
+	g := s.images
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	policy := s.config.ImageGC
+	high, low := s.config.Resources.ImageGCHighPercent, s.config.Resources.ImageGCLowPercent
+	now := time.Now()
+	g.stats.LastRun = now.Unix()
+	g.stats.HighPercent, g.stats.LowPercent = high, low
+
+	images, err := s.k3sAgent.runtime.ListImages()
+	if err != nil {
+		g.stats.LastError = err.Error()
+		log.Printf("⚠️ 이미지 목록 조회 실패: %v", err)
+		return
+	}
+	stats, err := readNodeStats(s.imageFSPath())
+	if err != nil {
+		g.stats.LastError = err.Error()
+		log.Printf("⚠️ 이미지 파일시스템 측정 실패: %v", err)
+		return
+	}
+	g.stats.LastError = ""
+
+	g.observe(policy.UsageStatePath, images, now)
+
+	var candidates []ContainerImage
+	g.stats.Images, g.stats.ImageBytes = len(images), 0
+	for _, image := range images {
+		g.stats.ImageBytes += image.SizeBytes
+		if image.InUse || image.Pinned || isPinnedImage(image, policy.PinnedImages) {
+			continue
+		}
+		if now.Sub(g.lastUsed[image.ID]) < time.Duration(policy.MinAgeSeconds)*time.Second {
+			continue
+		}
+		candidates = append(candidates, image)
+	}
+	g.stats.UnusedImages = len(candidates)
+
+	usage := stats.diskPercent()
+	g.stats.DiskPercent = usage
+	if usage < float64(high) && !force {
+		return
+	}
+	if usage <= float64(low) {
+		return
+	}
+
+	// 오래 안 쓴 이미지부터, 같으면 큰 이미지부터
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := g.lastUsed[candidates[i].ID], g.lastUsed[candidates[j].ID]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return candidates[i].SizeBytes > candidates[j].SizeBytes
+	})
+
+	target := uint64((usage - float64(low)) / 100 * float64(stats.DiskTotal))
+	log.Printf("🧹 이미지 GC: 디스크 %.1f%% (high %d%%, low %d%%), 목표 %d MiB 확보", usage, high, low, target>>20)
+
+	var freed uint64
+	removed := 0
+	for _, image := range candidates {
+		if freed >= target {
+			break
+		}
+		if err := s.k3sAgent.runtime.RemoveImage(image); err != nil {
+			log.Printf("⚠️ 이미지 제거 실패 %s: %v", image.displayName(), err)
+			continue
+		}
+		log.Printf("🗑️ 이미지 제거: %s (%d MiB, 마지막 사용 %s)", image.displayName(), image.SizeBytes>>20, g.lastUsed[image.ID].Format(time.RFC3339))
+		delete(g.lastUsed, image.ID)
+		freed += image.SizeBytes
+		removed++
+	}
+
+	g.stats.LastCollection = now.Unix()
+	g.stats.RemovedTotal += removed
+	g.stats.ReclaimedBytes += freed
+	g.stats.UnusedImages -= removed
+	g.stats.Images -= removed
+	g.stats.ImageBytes -= freed
+	if freed < target {
+		log.Printf("⚠️ 이미지 GC: %d MiB만 확보 (지울 수 있는 이미지 부족)", freed>>20)
+	}
+	g.save(policy.UsageStatePath)
+}
+
+// observe - 사용 중 이미지의 마지막 사용 시각 갱신 (처음 본 이미지는 지금을 기준으로 함)
+func (g *imageGC) observe(statePath string, images []ContainerImage, now time.Time) {
+	if !g.loaded {
+		g.loaded = true
+		g.lastUsed = make(map[string]time.Time)
+		var recorded map[string]int64
+		if err := loadStateFile(statePath, &recorded); err != nil {
+			log.Printf("⚠️ 이미지 사용 기록 로드 실패: %v", err)
+		}
+		for id, unix := range recorded {
+			g.lastUsed[id] = time.Unix(unix, 0)
+		}
+	}
+
+	present := make(map[string]bool, len(images))
+	for _, image := range images {
+		present[image.ID] = true
+		if _, seen := g.lastUsed[image.ID]; image.InUse || !seen {
+			g.lastUsed[image.ID] = now
+		}
+	}
+	// 다른 경로(kubelet 축출, 수동 삭제)로 사라진 이미지 기록 제거
+	for id := range g.lastUsed {
+		if !present[id] {
+			delete(g.lastUsed, id)
+		}
+	}
+	g.save(statePath)
+}
+
+func (g *imageGC) save(statePath string) {
+	recorded := make(map[string]int64, len(g.lastUsed))
+	for id, t := range g.lastUsed {
+		recorded[id] = t.Unix()
+	}
+	if err := writeStateFile(statePath, recorded); err != nil {
+		log.Printf("⚠️ 이미지 사용 기록 저장 실패: %v", err)
+	}
+}
+
+// isPinnedImage - 설정된 접두사와 일치하는 참조가 있는지 (docker.io/ 접두사 무시)
+func isPinnedImage(image ContainerImage, pinned []string) bool {
+	for _, ref := range image.Refs {
+		ref = strings.TrimPrefix(ref, "docker.io/")
+		for _, prefix := range pinned {
+			if strings.HasPrefix(ref, strings.TrimPrefix(prefix, "docker.io/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c ContainerImage) displayName() string {
+	for _, ref := range c.Refs {
+		if !strings.Contains(ref, "@sha256:") && ref != "<none>:<none>" {
+			return ref
+		}
+	}
+	return c.ID
+}
+
+/*
+parseImageSize - 런타임 CLI의 크기 표기를 바이트로 변환
+ctr: "2.9 MiB" (이진 단위), docker: "142MB" (십진 단위)
+*/
+func parseImageSize(value string) uint64 {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split <= 0 {
+		return 0
+	}
+	number, err := strconv.ParseFloat(value[:split], 64)
+	if err != nil {
+		return 0
+	}
+	units := map[string]float64{
+		"B": 1, "kB": 1e3, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12,
+		"KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40,
+	}
+	return uint64(number * units[value[split:]])
+}
+
+// imageGCStats - 노드 메트릭용 이미지 GC 상태 (staking 모드는 런타임 에이전트에서 조회)
+func (s *StakerHost) imageGCStats() ImageGCStats {
+	if s.runtimeClient != nil {
+		stats, err := s.runtimeClient.ImageGCStats()
+		if err != nil {
+			return ImageGCStats{LastError: err.Error()}
+		}
+		return stats
+	}
+	return s.images.snapshot()
+}
@@ -17,9 +17,6 @@ import (
 	"github.com/k3s-io/k3s/pkg/daemons/config"
 	"github.com/k3s-io/k3s/pkg/agent/proxy"
 
-	// HTTP 클라이언트
-	"github.com/go-resty/resty/v2"
-
 	// Kubernetes 클라이언트
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -42,6 +39,11 @@ type K3sAgentManager struct {
 func (s *StakerHost) startRealK3sAgent() error {
 	log.Printf("🚀 Starting real K3s Agent with Seal token integration...")
 
+	// Seal 토큰은 마스터 등록에만 사용하고, K3s 조인은 Nautilus가 발급한 노드 전용 토큰으로 수행
+	if s.joinToken == "" {
+		return fmt.Errorf("조인 토큰 없음: Nautilus 등록(registerWithNautilus)이 먼저 필요합니다")
+	}
+
 	// Context 생성
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -73,6 +75,9 @@ func (s *StakerHost) startRealK3sAgent() error {
 	}
 
 	log.Printf("✅ K3s Agent가 Seal 토큰으로 성공적으로 시작됨")
+
+	// 5. 이전 실행에서 남은 컨테이너 정리 및 배정 기록 시작
+	s.startOrphanReconcile()
 	return nil
 }
 
@@ -89,23 +94,24 @@ func (manager *K3sAgentManager) setupK3sAgentConfig() error {
 
 	// K3s Agent 설정 생성
 	config := &K3sAgentWorkerConfig{
-		ServerURL:                manager.stakerHost.config.NautilusEndpoint,
-		Token:                    manager.stakerHost.stakingStatus.SealToken,
+		ServerURL:                manager.stakerHost.masterURL(),
+		Token:                    manager.stakerHost.joinToken,
 		DataDir:                  dataDir,
 		NodeName:                 manager.stakerHost.config.NodeID,
 		NodeIP:                   "0.0.0.0",
 		ContainerRuntimeEndpoint: manager.getContainerRuntimeEndpoint(),
-		NodeLabels: []string{
+		NodeLabels: append([]string{
 			"k3s-daas.io/worker=true",
 			"k3s-daas.io/seal-auth=enabled",
-			fmt.Sprintf("k3s-daas.io/stake-amount=%d", manager.stakerHost.stakingStatus.StakeAmount),
-		},
-		KubeletArgs: []string{
+			fmt.Sprintf("k3s-daas.io/stake-amount=%d", manager.stakerHost.stakingStatus.StakeAmount()),
+			versionLabel(),
+		}, append(manager.stakerHost.topologyLabels(), resourceLimitLabels()...)...),
+		KubeletArgs: append([]string{
 			"--container-runtime=remote",
 			"--container-runtime-endpoint=" + manager.getContainerRuntimeEndpoint(),
 			"--fail-swap-on=false",
 			"--cgroup-driver=systemd",
-		},
+		}, append(append(manager.stakerHost.config.Resources.kubeletArgs(), resourceEnforcementKubeletArgs()...), manager.stakerHost.config.Secrets.kubeletArgs()...)...),
 		LogLevel: "info",
 	}
 
@@ -160,7 +166,7 @@ func (manager *K3sAgentManager) startAgent() error {
 	log.Printf("🚀 Starting K3s Agent process...")
 
 	// K3s 바이너리 경로 확인
-	k3sBinary, err := manager.findK3sBinary()
+	k3sBinary, err := findK3sBinary()
 	if err != nil {
 		log.Printf("❌ K3s 바이너리를 찾을 수 없습니다")
 		log.Printf("💡 해결 방법: K3s를 설치하거나 K3S_BINARY_PATH 환경변수로 바이너리 경로를 지정해주세요")
@@ -171,8 +177,8 @@ func (manager *K3sAgentManager) startAgent() error {
 	// K3s agent 명령어 구성
 	args := []string{
 		"agent",
-		"--server", manager.stakerHost.config.NautilusEndpoint,
-		"--token", manager.stakerHost.stakingStatus.SealToken,
+		"--server", manager.stakerHost.masterURL(),
+		"--token", manager.stakerHost.joinToken,
 		"--data-dir", "/var/lib/k3s-daas-agent",
 		"--node-name", manager.stakerHost.config.NodeID,
 		"--node-ip", "0.0.0.0",
@@ -180,22 +186,31 @@ func (manager *K3sAgentManager) startAgent() error {
 		"--log", "info",
 	}
 
+	// Docker Hub pull 한도를 피하도록 마스터가 안내한 이미지 미러 사용 (실패해도 직접 pull로 계속)
+	if registryConfig, err := manager.stakerHost.writeRegistryConfig("/var/lib/k3s-daas-agent"); err != nil {
+		log.Printf("⚠️ 이미지 미러 설정 실패, Docker Hub에서 직접 pull: %v", err)
+	} else if registryConfig != "" {
+		args = append(args, "--private-registry", registryConfig)
+	}
+
 	// 노드 라벨 추가
-	for _, label := range []string{
+	for _, label := range append([]string{
 		"k3s-daas.io/worker=true",
 		"k3s-daas.io/seal-auth=enabled",
-		fmt.Sprintf("k3s-daas.io/stake-amount=%d", manager.stakerHost.stakingStatus.StakeAmount),
-	} {
+		fmt.Sprintf("k3s-daas.io/stake-amount=%d", manager.stakerHost.stakingStatus.StakeAmount()),
+		versionLabel(),
+	}, append(manager.stakerHost.topologyLabels(), resourceLimitLabels()...)...) {
 		args = append(args, "--node-label", label)
 	}
 
 	// kubelet args 추가
-	kubeletArgs := []string{
+	// 시스템 예약, 축출 임계값, 이미지 GC로 테넌트 Pod이 노드 자원을 모두 쓰지 못하게 함
+	kubeletArgs := append([]string{
 		"--container-runtime=remote",
 		"--container-runtime-endpoint=" + manager.getContainerRuntimeEndpoint(),
 		"--fail-swap-on=false",
 		"--cgroup-driver=systemd",
-	}
+	}, append(append(manager.stakerHost.config.Resources.kubeletArgs(), resourceEnforcementKubeletArgs()...), manager.stakerHost.config.Secrets.kubeletArgs()...)...)
 	for _, arg := range kubeletArgs {
 		args = append(args, "--kubelet-arg", arg)
 	}
@@ -239,37 +254,6 @@ func (manager *K3sAgentManager) startAgent() error {
 	return nil
 }
 
-// K3s 바이너리 찾기
-func (manager *K3sAgentManager) findK3sBinary() (string, error) {
-	// 1. 환경변수에서 확인
-	if k3sPath := os.Getenv("K3S_BINARY_PATH"); k3sPath != "" {
-		if _, err := os.Stat(k3sPath); err == nil {
-			return k3sPath, nil
-		}
-	}
-
-	// 2. PATH에서 k3s 검색
-	if k3sPath, err := exec.LookPath("k3s"); err == nil {
-		return k3sPath, nil
-	}
-
-	// 3. 일반적인 위치에서 검색
-	commonPaths := []string{
-		"/usr/local/bin/k3s",
-		"/usr/bin/k3s",
-		"/opt/k3s/bin/k3s",
-		"./k3s",
-	}
-
-	for _, path := range commonPaths {
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
-	}
-
-	return "", fmt.Errorf("k3s binary not found in PATH or common locations")
-}
-
 // Agent 로그 출력용 Writer
 type AgentLogWriter struct {
 	prefix string
@@ -430,8 +414,8 @@ func (s *StakerHost) requestKubeconfigFromTEE() ([]byte, error) {
 	log.Printf("📄 Requesting kubeconfig from Nautilus TEE...")
 
 	// Nautilus TEE에 kubeconfig 요청
-	resp, err := resty.New().R().
-		SetHeader("X-Seal-Token", s.stakingStatus.SealToken).
+	resp, err := s.restyClient().R().
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
 		Get(s.config.NautilusEndpoint + "/kubectl/config")
 
 	if err != nil {
@@ -449,7 +433,7 @@ func (s *StakerHost) requestKubeconfigFromTEE() ([]byte, error) {
 
 // Agent 헬스체크 (기존 함수 확장)
 func (s *StakerHost) makeHealthCheck(url string) (string, error) {
-	client := resty.New().SetTimeout(5 * time.Second)
+	client := s.restyClient().SetTimeout(5 * time.Second)
 
 	resp, err := client.R().Get(url)
 	if err != nil {
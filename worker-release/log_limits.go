@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 로그 폭주 방지 기본값 (컨테이너별)
+const (
+	defaultPodLogRateLimitKBps  = 1024      // 초당 수집 바이트 (1MiB/s)
+	defaultPodLogBurstKB        = 16 * 1024 // 순간 허용량
+	defaultPodLogMaxContainerMB = 256       // 컨테이너 하나가 차지할 수 있는 보관 용량
+
+	podLogThrottleHold = 5 * time.Minute // 마지막 제한 후 이 시간 동안 제한 상태 유지 (조건이 깜빡이지 않게)
+
+	logThrottleRateLimited = "RateLimited"
+	logThrottleSizeCapped  = "SizeCapped"
+)
+
+// logBucket - 컨테이너별 토큰 버킷 (바이트 단위, 초당 rate_limit_kbps씩 채워짐)
+type logBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// LogThrottle - 로그 제한이 걸린 컨테이너 (하트비트 log_throttling, 마스터가 Pod 조건으로 반영)
+type LogThrottle struct {
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container"`
+	Reason       string    `json:"reason"` // RateLimited: 수집 속도 초과로 버림, SizeCapped: 보관 상한으로 오래된 로그 삭제
+	DroppedLines int64     `json:"dropped_lines"`
+	DroppedBytes int64     `json:"dropped_bytes"`
+	Since        time.Time `json:"since"`
+	LastAt       time.Time `json:"last_at"`
+}
+
+/*
+limitRate - 토큰 버킷으로 이번에 보관할 줄만 남김 (호출자가 p.mu 보유)
+허용량을 넘는 줄은 버리고 읽기 위치는 그대로 넘어가므로, 로그를 폭주시키는 컨테이너가
+디스크나 수집 주기를 독점하지 못합니다. 버린 분량은 표식 줄로 남겨 조회 시 공백을 알 수 있게 합니다.
+*/
+func (p *podLogStore) limitRate(dir string, data []byte) []byte {
+	now := time.Now()
+	rate := float64(p.policy.RateLimitKBps * 1024)
+	burst := float64(p.policy.BurstKB * 1024)
+
+	bucket, ok := p.buckets[dir]
+	if !ok {
+		bucket = &logBucket{tokens: burst, last: now}
+		p.buckets[dir] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if float64(len(data)) <= bucket.tokens {
+		bucket.tokens -= float64(len(data))
+		return data
+	}
+
+	// 허용량 안의 마지막 완전한 줄까지만 보관
+	cut := 0
+	if bucket.tokens >= 1 {
+		cut = bytes.LastIndexByte(data[:int(bucket.tokens)], '\n') + 1
+	}
+	bucket.tokens -= float64(cut)
+	dropped := data[cut:]
+	lines := int64(bytes.Count(dropped, []byte{'\n'}))
+
+	p.throttleLocked(dir, logThrottleRateLimited, lines, int64(len(dropped)))
+	marker := fmt.Sprintf("%s stderr F [k3s-daas] log rate limit %d KB/s exceeded, dropped %d lines (%d bytes)\n",
+		now.UTC().Format(time.RFC3339Nano), p.policy.RateLimitKBps, lines, len(dropped))
+	return append(data[:cut:cut], marker...)
+}
+
+// throttleLocked - 제한 기록 (호출자가 p.mu 보유, dir은 <보관 경로>/<ns>/<pod>/<container>)
+func (p *podLogStore) throttleLocked(dir, reason string, lines, size int64) {
+	rel, err := filepath.Rel(p.policy.Dir, dir)
+	if err != nil {
+		return
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 {
+		return
+	}
+
+	now := time.Now()
+	key := rel + "|" + reason
+	throttle, ok := p.throttles[key]
+	if !ok || now.Sub(throttle.LastAt) > podLogThrottleHold {
+		throttle = &LogThrottle{Namespace: parts[0], Pod: parts[1], Container: parts[2], Reason: reason, Since: now}
+		p.throttles[key] = throttle
+		log.Printf("🚱 로그 제한 시작: %s/%s (%s) - %s", parts[0], parts[1], parts[2], reason)
+	}
+	throttle.DroppedLines += lines
+	throttle.DroppedBytes += size
+	throttle.LastAt = now
+	p.stats.DroppedBytes += size
+}
+
+// throttling - 현재 제한 중인 컨테이너 (유지 시간이 지난 항목은 해제)
+func (p *podLogStore) throttling() []LogThrottle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	active := make([]LogThrottle, 0, len(p.throttles))
+	for key, throttle := range p.throttles {
+		if time.Since(throttle.LastAt) > podLogThrottleHold {
+			log.Printf("✅ 로그 제한 해제: %s/%s (%s) - %s", throttle.Namespace, throttle.Pod, throttle.Container, throttle.Reason)
+			delete(p.throttles, key)
+			continue
+		}
+		active = append(active, *throttle)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i], active[j]
+		if a.Namespace+"/"+a.Pod != b.Namespace+"/"+b.Pod {
+			return a.Namespace+"/"+a.Pod < b.Namespace+"/"+b.Pod
+		}
+		return a.Container+a.Reason < b.Container+b.Reason
+	})
+	p.stats.ThrottledContainers = len(active)
+	return active
+}
+
+// logThrottling - 제한 중인 컨테이너 (staking 모드는 런타임 에이전트에 조회, 알 수 없으면 false)
+func (s *StakerHost) logThrottling() ([]LogThrottle, bool) {
+	if s.runtimeClient != nil {
+		throttles, err := s.runtimeClient.LogThrottling()
+		if err != nil {
+			log.Printf("⚠️ 런타임 에이전트 로그 제한 상태 조회 실패: %v", err)
+			return nil, false
+		}
+		return throttles, true
+	}
+	if s.podLogs == nil {
+		return nil, false
+	}
+	return s.podLogs.throttling(), true
+}
+
+// logThrottleCondition - 제한 중인 컨테이너가 있으면 LogThrottling 조건 (kubelet 조건 옆에 함께 보고)
+func logThrottleCondition(throttles []LogThrottle) NodeCondition {
+	condition := NodeCondition{Type: "LogThrottling"}
+	if len(throttles) == 0 {
+		return condition
+	}
+	pods := make(map[string]bool)
+	for _, throttle := range throttles {
+		pods[throttle.Namespace+"/"+throttle.Pod] = true
+	}
+	condition.Status = true
+	condition.Message = fmt.Sprintf("%d containers in %d pods exceed log limits", len(throttles), len(pods))
+	return condition
+}
@@ -17,19 +17,26 @@ package main
 
 import (
 	"context"
-	"encoding/base64"  // Base64 인코딩/디코딩
 	"encoding/json"    // JSON 직렬화/역직렬화를 위한 패키지
+	"errors"
 	"fmt"              // 포맷 문자열 처리
 	"log"              // 로깅
 	"net/http"         // HTTP 서버/클라이언트
 	"os"               // 운영체제 인터페이스 (환경변수, 파일 등)
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"             // 시간 관련 함수들
 
-	"github.com/go-resty/resty/v2" // HTTP 클라이언트 라이브러리 (Sui RPC 통신용)
+	chain "github.com/k3s-io/daas-chain"           // 공용 체인 백엔드 인터페이스 (Sui, mock)
+	featuregate "github.com/k3s-io/daas-featuregate" // 공용 기능 게이트 (--feature-gates)
+	httpserver "github.com/k3s-io/daas-httpserver" // 공용 HTTP 리스너 (TLS, 리다이렉트, HSTS)
+	service "github.com/k3s-io/daas-service"       // 공용 서비스 관리 (systemd 유닛 / Windows 서비스)
+	sui "github.com/k3s-io/daas-sui"               // 공용 Sui 클라이언트 (RPC, 트랜잭션 빌더, 캐시)
+	version "github.com/k3s-io/daas-version"       // 공용 빌드 버전 / 마스터와의 버전 차이 정책
 )
 
 /*
@@ -46,6 +53,49 @@ type StakerHostConfig struct {
 	NautilusEndpoint string `json:"nautilus_endpoint"`  // Nautilus TEE 엔드포인트 (마스터 노드)
 	ContainerRuntime string `json:"container_runtime"`  // 컨테이너 런타임 (containerd 또는 docker)
 	MinStakeAmount   uint64 `json:"min_stake_amount"`   // 최소 스테이킹 요구량
+	Region           string `json:"region"`             // 워커 위치 리전 (예: "ap-northeast-2")
+	Zone             string `json:"zone"`               // 워커 위치 존 (예: "ap-northeast-2a")
+	MaxClockSkewSeconds int `json:"max_clock_skew_seconds"` // 체인 시각 대비 허용 오차 (초)
+	NodeRole         string `json:"node_role"`          // 노드 역할: worker, edge, storage (스테이킹 티어 결정)
+	StakeTiers       map[string]uint64 `json:"stake_tiers"` // 역할별 최소 스테이킹 (MIST, 미설정 시 컨트랙트 기본 배수)
+	GasSponsorship   bool   `json:"gas_sponsorship"`    // 마스터가 가스를 대납하는 스폰서 트랜잭션 사용 여부
+	OnChainHeartbeatEvery int `json:"onchain_heartbeat_every"` // 온체인 하트비트 기록 주기 (하트비트 N회마다)
+	SlashConfirmations int `json:"slash_confirmations"` // 노드 종료 전 필요한 연속 슬래싱 확인 횟수 (기본 3)
+	AutoAppeal       bool   `json:"auto_appeal"`        // 슬래싱 확정 시 워커 지갑으로 온체인 이의 신청 제출
+	AppealEvidenceDir string `json:"appeal_evidence_dir"` // 이의 신청 증거 번들 저장 경로 (기본 /var/lib/k3s-daas-agent/appeals)
+	ListenAddr       string `json:"listen_addr"`        // 상태 서버 주소 (기본 :10260, 실제 kubelet 10250과 충돌 방지)
+	PeerAttestation  bool   `json:"peer_attestation"`   // 에폭마다 마스터 TEE 증명 교차 검증 참여 여부
+	AttestationSampleRate float64 `json:"attestation_sample_rate"` // 에폭당 검증자로 선택될 확률 (기본 0.25)
+	MasterPublicKey  string `json:"master_public_key"`  // 마스터 응답 서명 공개키 (hex, 증명 검증용으로 고정)
+	TrustedMeasurements []string `json:"trusted_measurements"` // 허용되는 마스터 측정값 목록 (비어 있으면 서명만 검증)
+	AttestationPeers []AttestationPeer `json:"attestation_peers"` // 추가로 검증할 증명 제공자 (선택)
+	OrphanPolicy     string `json:"orphan_policy"`      // 재시작 시 기록에 없는 이전 Pod 처리: stop(기본) 또는 keep
+	AssignmentStatePath string `json:"assignment_state_path"` // 실행 중 Pod 기록 파일 (기본 /var/lib/k3s-daas-agent/assignments.json)
+	Resources        ResourceReservation `json:"resource_reservation"` // kubelet 시스템 예약, 축출 임계값, 이미지 GC 설정
+	ImageGC          ImageGCPolicy `json:"image_gc"`           // 미사용 이미지 LRU 정리 주기, 고정 이미지
+	RegistryMirrors  []string `json:"registry_mirrors"`          // docker.io 미러 직접 지정 (비어 있으면 마스터 안내 사용)
+	ChainBackend     string `json:"chain_backend"`      // 체인 백엔드: sui(기본) 또는 mock (온프레미스, 스테이킹 체인 없음)
+	HeartbeatCollectors []HeartbeatCollectorConfig `json:"heartbeat_collectors"` // 하트비트에 붙일 수집기 (gpu, temperature, 사용자 명령)
+	HeartbeatCollectorsMaxBytes int `json:"heartbeat_collectors_max_bytes"` // 수집기 섹션 합계 한도 (기본 32KiB)
+	NautilusFallbackEndpoints []string `json:"nautilus_fallback_endpoints"` // 마스터 장애 시 사용할 예비 주소 (설정 순서가 우선순위)
+	SuiRPCFallbackEndpoints []string `json:"sui_rpc_fallback_endpoints"` // Sui RPC 예비 주소
+	DNSCachePath     string `json:"dns_cache_path"`     // DNS 캐시 파일 (기본 /var/lib/k3s-daas-agent/dns-cache.json)
+	PreflightIgnore  []string `json:"preflight_ignore"`  // 실패해도 등록을 막지 않을 프리플라이트 검사 이름 (all이면 전체)
+	MinK3sVersion    string `json:"min_k3s_version"`    // 허용하는 최소 k3s 버전 (기본 v1.26.0)
+	WorkerConfigPath string `json:"worker_config_path"` // 마스터 설정 번들 저장 파일 (기본 /var/lib/k3s-daas-agent/worker-config.json)
+	PodLogs          PodLogPolicy `json:"pod_logs"`            // Pod 로그 수집/보관/배송 (kubectl logs 재시작 후 조회용)
+	FeatureGates     map[string]bool `json:"feature_gates"` // 기능 게이트 (예: {"AutoAppeal": true}, 환경 변수/플래그가 우선)
+	StakingStatePath string `json:"staking_state_path"` // 암호화된 스테이킹 상태 파일 (기본 /var/lib/k3s-daas-agent/staking-state.enc)
+	StakingStateKeyPath string `json:"staking_state_key_path"` // 상태 파일 암호화 키 (기본 /var/lib/k3s-daas-agent/staking-state.key)
+	NetworkProbe     NetworkProbeConfig `json:"network_probe"` // 마스터/피어 대역폭 측정 주기와 전송량 (노드 대역폭 라벨)
+	Secrets          SecretsConfig `json:"secrets"`            // Pod 마운트 시 Vault/온체인 Sealed Secret을 해석하는 secrets 볼륨
+	StatusFirewall   StatusFirewallConfig `json:"status_firewall"` // 상태 서버 포트 허용 목록, mTLS, 호스트 방화벽 규칙
+	Termination      TerminationConfig `json:"termination"`  // 워커가 직접 Pod을 내릴 때의 유예 시간 (노드 종료, 고아 Pod 정리)
+	NodeProblems     NodeProblemConfig `json:"node_problems"` // 커널 로그 기반 노드 문제 감지 (KernelDeadlock, OOMKilling, 디스크 오류)
+	WalletMonitor    WalletMonitorConfig `json:"wallet_monitor"` // 지갑 SUI 잔액 감시, 가스 부족 경고, testnet faucet 자동 충전
+	BuildAllowlistID string `json:"build_allowlist_id"` // 온체인 빌드 허용 목록 객체 ID (마스터 빌드 출처 대조)
+	MasterBuildPolicy string `json:"master_build_policy"` // 허용되지 않은 마스터 빌드 처리: off, warn(허용 목록이 있으면 기본), refuse
+	HostFingerprint  HostFingerprintConfig `json:"host_fingerprint"` // 하트비트로 보내는 OS/커널/런타임 지문 (마스터 컴플라이언스 검사)
 }
 
 /*
@@ -56,12 +106,36 @@ type StakerHost struct {
 	config           *StakerHostConfig // 설정 정보
 	suiClient        *SuiClient        // Sui 블록체인 클라이언트
 	k3sAgent         *K3sAgent         // K3s 워커 노드 에이전트
-	stakingStatus    *StakingStatus    // 현재 스테이킹 상태
+	stakingStatus    *StakingState     // 현재 스테이킹 상태 (전이는 상태 머신으로만)
 	heartbeatTicker  *time.Ticker      // 하트비트 타이머 (30초마다 실행)
 	isRunning        bool              // 실행 상태
 	sealToken        string            // Current seal token (cached from stakingStatus)
+	joinToken        string            // Nautilus가 Seal 검증 후 발급한 노드 전용 1회용 K3s 조인 토큰
 	lastHeartbeat    int64             // Last heartbeat timestamp
 	startTime        time.Time         // Node start time
+	clockSkew        time.Duration     // 체인 체크포인트 대비 로컬 시계 오차
+	clockCheckedAt   time.Time         // 마지막 시계 오차 측정 시각
+	heartbeatCount   int               // 온체인 하트비트 주기 계산용 카운터
+	probeAnswer      *probeResult      // 다음 하트비트에 보낼 감사 프로브 응답
+	runtimeClient    *RuntimeClient    // staking 모드: 런타임 에이전트 소켓 클라이언트 (combined 모드는 nil)
+	stakeMonitor     *stakeMonitor     // 하트비트와 분리된 스테이킹 상태 조회 (자체 재시도 일정)
+	reconcileOnce    sync.Once         // 이전 실행의 고아 컨테이너 정리 (프로세스당 한 번)
+	pressure         *pressureMonitor  // 디스크/메모리 압박 감지 결과 (하트비트 node_conditions)
+	problems         *nodeProblemDetector // 커널 로그 기반 노드 문제 (하트비트 node_conditions, 비활성화 시 nil)
+	wallet           *walletMonitor       // 지갑 잔액 감시 (하트비트 node_conditions, 비활성화 시 nil)
+	fingerprint      *hostFingerprinter   // 호스트 OS/커널/런타임 지문 캐시 (하트비트 host_fingerprint)
+	masterBuild      *masterBuildCheck    // 마스터 빌드 출처 확인 결과 (master_build_policy=off면 nil)
+	images           *imageGC          // 이미지 사용 기록 및 GC 통계
+	podLogs          *podLogStore      // Pod 로그 보관소 (staking 모드에서는 nil, 런타임 에이전트가 보관)
+	heartbeats       *heartbeatLog     // 최근 하트비트 결과 (슬래싱 이의 신청 증거)
+	collectors       *heartbeatCollectors // 하트비트 추가 섹션 수집기 (설정으로 선택, 크기 한도 적용)
+	network          *workerNetwork       // DNS 캐시와 마스터/Sui RPC 예비 주소 장애 조치
+	preflight        *PreflightReport     // 콜드 스타트 프리플라이트 결과 (staking 모드는 런타임 에이전트가 보유)
+	configSync       *configSync          // 마스터가 배포한 설정 번들 (미러, 하트비트 주기, 기능 게이트)
+	stakingStore     *stakingStore        // 스테이킹 객체 ID/Seal 토큰 암호화 저장 (재시작 후 재사용)
+	netProbe         *networkProber       // 대역폭/지연 측정 결과와 측정 대상 피어
+	firewall         *statusFirewall      // 상태 서버 접근 제한 (마스터 허용 목록, 클라이언트 인증서)
+	challenges       *challengeSolver     // 마스터 하트비트 챌린지 계산과 다음 하트비트용 응답
 }
 
 /*
@@ -69,10 +143,11 @@ Sui 클라이언트 - Sui 블록체인과의 모든 통신을 담당
 스테이킹, Seal 토큰 생성, 상태 조회 등의 작업을 처리합니다.
 */
 type SuiClient struct {
-	rpcEndpoint string        // Sui 테스트넷 RPC URL
-	privateKey  string        // 트랜잭션 서명용 개인키 (hex 형식)
-	client      *resty.Client // HTTP 클라이언트 (재사용 가능)
-	address     string        // 지갑 주소
+	rpcEndpoint string          // Sui 테스트넷 RPC URL
+	privateKey  string          // 트랜잭션 서명용 개인키 (hex 형식)
+	chain       *sui.SuiClient  // 공용 Sui 클라이언트 (RPC, 메트릭, 캐시)
+	backend     chain.Backend   // 체인 백엔드 (스테이킹 조회, 이의 신청 제출 - sui 또는 mock)
+	address     string          // 지갑 주소
 }
 
 /*
@@ -93,6 +168,7 @@ type StakingStatus struct {
 	IsStaked       bool   `json:"is_staked"`        // 스테이킹 완료 여부
 	StakeAmount    uint64 `json:"stake_amount"`     // 스테이킹한 SUI 양 (MIST 단위)
 	StakeObjectID  string `json:"stake_object_id"`  // Sui 블록체인의 스테이킹 오브젝트 ID
+	StakeObjectVersion uint64 `json:"stake_object_version"` // 마지막으로 확인한 스테이킹 오브젝트 버전
 	SealToken      string `json:"seal_token"`       // Nautilus TEE 인증용 Seal 토큰
 	LastValidation int64  `json:"last_validation"`  // 마지막 검증 시각 (Unix timestamp)
 	Status         string `json:"status"`           // 상태: active(정상), slashed(슬래시됨), pending(대기중)
@@ -104,8 +180,10 @@ type StakingStatus struct {
 */
 type ContainerRuntime interface {
 	RunContainer(image, name string, env map[string]string) error // 컨테이너 실행
-	StopContainer(name string) error                              // 컨테이너 중단
+	StopContainer(name string, grace time.Duration) error         // 컨테이너 중단 (SIGTERM 후 grace가 지나면 SIGKILL)
 	ListContainers() ([]Container, error)                         // 실행 중인 컨테이너 목록 조회
+	ListImages() ([]ContainerImage, error)                        // 이미지 목록 (컨테이너 참조 여부 포함)
+	RemoveImage(image ContainerImage) error                       // 이미지의 모든 참조 제거
 }
 
 /*
@@ -135,6 +213,9 @@ type Kubelet struct {
 	mu          sync.RWMutex    // 뮤텍스
 }
 
+// 상태 서버 기본 주소 (K3s Agent가 띄우는 kubelet의 10250 포트와 겹치지 않도록 분리)
+const defaultListenAddr = ":10260"
+
 /*
 🚀 메인 함수 - K3s-DaaS 스테이커 호스트의 진입점
 
@@ -143,13 +224,23 @@ type Kubelet struct {
 2️⃣ Sui 블록체인에 스테이킹 + Seal 토큰 생성
 3️⃣ Kubelet 시작 + Nautilus TEE 등록
 4️⃣ 하트비트 서비스 시작 (백그라운드)
-5️⃣ HTTP 상태 서버 실행 (포트 10250)
+5️⃣ HTTP 상태 서버 실행 (기본 포트 10260)
 
 이는 기존 K3s worker node 시작 과정과 완전히 다른 블록체인 기반 접근법입니다.
 전통적인 K3s join token 대신 Seal 토큰을 사용하여 보안성을 크게 향상시켰습니다.
 
 환경변수:
 - STAKER_CONFIG_PATH: 설정 파일 경로 (기본값: ./staker-config.json)
+- K3S_DAAS_LISTEN_ADDR, K3S_DAAS_TLS_CERT_FILE/KEY_FILE, K3S_DAAS_TLS_SELF_SIGNED,
+  K3S_DAAS_HTTP_REDIRECT_ADDR, K3S_DAAS_HSTS_MAX_AGE: 상태 서버 리슨/TLS 설정
+- K3S_DAAS_ADMIN_TOKEN: /api/v1/register, /api/v1/unstake 호출에 필요한 Bearer 토큰
+- K3S_DAAS_MODE: combined(기본) | staking | runtime - 스테이킹 데몬과 런타임 에이전트 분리 실행
+- K3S_DAAS_RUNTIME_SOCKET: 두 프로세스 간 unix 소켓 경로 (기본 /run/k3s-daas/runtime.sock)
+- K3S_DAAS_PREFLIGHT_IGNORE: 실패해도 등록을 막지 않을 프리플라이트 검사 (쉼표 구분, all이면 전체)
+
+서비스 관리: worker-release service install|uninstall|start|stop|restart|status
+(K3S_DAAS_MODE에 따라 k3s-daas-worker / k3s-daas-staking / k3s-daas-runtime 유닛으로 설치)
+프리플라이트만 실행: worker-release preflight (필수 검사 실패 시 종료 코드 1)
 */
 func main() {
 	// 📁 설정 파일 경로 결정 (환경변수 또는 기본값)
@@ -158,7 +249,55 @@ func main() {
 		configPath = "./staker-config.json"
 	}
 
-	log.Printf("🚀 K3s-DaaS 스테이커 호스트 시작...")
+	mode := workerMode()
+	name := workerServiceName(mode)
+
+	// 🚩 기능 게이트: K3S_DAAS_FEATURE_GATES, --feature-gates (설정 파일 feature_gates보다 우선)
+	if err := features.LoadEnv("K3S_DAAS_FEATURE_GATES"); err != nil {
+		log.Fatalf("❌ 기능 게이트 설정 오류: %v", err)
+	}
+	args, err := features.ParseArgs(os.Args[1:])
+	if err != nil {
+		log.Fatalf("❌ 기능 게이트 설정 오류: %v", err)
+	}
+
+	// 🧰 서비스 관리 서브커맨드 (systemd 유닛 / Windows 서비스 설치 및 제어)
+	if len(args) > 0 && args[0] == "service" {
+		if err := service.Command(workerServiceSpec(mode, configPath), args[1:], os.Stdout); err != nil {
+			log.Fatalf("❌ 서비스 관리 실패: %v", err)
+		}
+		return
+	}
+
+	// 🔖 빌드 버전 출력
+	if len(args) > 0 && args[0] == "version" {
+		fmt.Println(version.Get("worker"))
+		return
+	}
+
+	// 🔑 kubelet이 실행하는 secrets FlexVolume 드라이버 (stdout은 kubelet이 읽는 JSON 결과 전용)
+	if len(args) > 0 && args[0] == "flexvolume" {
+		os.Exit(runFlexVolume(args[1:], os.Stdout))
+	}
+
+	// 🧪 프리플라이트만 실행하고 결과 표 출력 (설치 직후 노드 점검용)
+	if len(args) > 0 && args[0] == "preflight" {
+		config, err := loadConfig(configPath)
+		if err != nil {
+			log.Fatalf("❌ 설정 파일 로드 실패: %v", err)
+		}
+		report := runPreflight(config, mode)
+		fmt.Print(report.matrix())
+		if !report.Passed {
+			fmt.Printf("\n필수 검사 실패: %s\n", strings.Join(report.Blocking, ", "))
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 📝 Windows 서비스로 실행 시 EventLog, 그 외 stderr (systemd에서는 journald로 수집)
+	log.SetOutput(service.LogOutput(name))
+	log.Printf("🚀 K3s-DaaS 스테이커 호스트 시작... (모드: %s, %s)", mode, version.Get("worker"))
 	log.Printf("📁 설정 파일: %s", configPath)
 
 	// 1️⃣ 스테이커 호스트 초기화 (설정 로드, 클라이언트 초기화)
@@ -166,29 +305,56 @@ func main() {
 	if err != nil {
 		log.Fatalf("❌ 스테이커 호스트 초기화 실패: %v", err)
 	}
+	logFeatureGates()
+
+	// 🧪 콜드 스타트 프리플라이트 (k3s를 실행하는 프로세스에서만 - staking 모드는 런타임 에이전트 결과 사용)
+	if mode != modeStaking {
+		stakerHost.preflight = runPreflight(stakerHost.config, mode)
+		stakerHost.preflight.log()
+
+		// 🔑 kubelet 플러그인 디렉토리에 secrets 볼륨 드라이버 설치 (kubelet 시작 전)
+		if !stakerHost.config.Secrets.Disabled {
+			if err := installSecretsDriver(stakerHost.config.Secrets); err != nil {
+				log.Printf("⚠️ secrets 볼륨 드라이버 설치 실패 (secrets 볼륨을 쓰는 Pod는 시작되지 않음): %v", err)
+			}
+		}
+	}
+
+	// 🔌 runtime 모드: K3s Agent/컨테이너 런타임만 실행하고 소켓 API로 스테이킹 데몬의 요청 처리
+	if mode == modeRuntime {
+		ctx, stop := service.NotifyContext(context.Background(), name)
+		defer stop()
+		go stakerHost.runPressureMonitor(ctx)
+		go stakerHost.runImageGC(ctx)
+		go stakerHost.runPodLogCollector(ctx)
+		if err := serveRuntimeAgent(ctx, stakerHost, runtimeSocketPath()); err != nil {
+			log.Fatalf("❌ 런타임 에이전트 오류: %v", err)
+		}
+		stakerHost.stopContainers()
+		log.Printf("✅ 런타임 에이전트 종료 완료")
+		return
+	}
+
+	// 🔗 staking 모드: 런타임 작업(에이전트 시작, Pod 조회, 감사 프로브)은 소켓으로 위임
+	if mode == modeStaking {
+		stakerHost.runtimeClient = NewRuntimeClient(runtimeSocketPath())
+		log.Printf("🔗 런타임 에이전트 소켓: %s", runtimeSocketPath())
+	}
 
 	// 2️⃣ Sui 블록체인에 스테이킹 등록 및 Seal 토큰 생성
 	// 이 단계가 성공해야만 클러스터에 참여할 수 있습니다.
+	// 저장된 스테이킹이 체인에서 유효하면 새 트랜잭션 없이 재사용
 	log.Printf("🌊 Sui 블록체인 스테이킹 시작...")
-	if err := stakerHost.RegisterStake(); err != nil {
-		// 개발/테스트 환경에서는 Mock 데이터로 계속 진행
-		if os.Getenv("MOCK_MODE") == "true" {
-			log.Printf("⚠️ 스테이킹 실패하지만 Mock 모드로 계속 진행: %v", err)
-			stakerHost.stakingStatus.IsStaked = true
-			stakerHost.stakingStatus.Status = "mock"
-			stakerHost.stakingStatus.SealToken = "seal_mock_token_for_testing_12345678"
-			stakerHost.sealToken = "seal_mock_token_for_testing_12345678"
-		} else {
-			log.Fatalf("❌ 스테이킹 등록 실패: %v", err)
-		}
+	if err := stakerHost.ensureStake(); err != nil {
+		log.Fatalf("❌ 스테이킹 등록 실패: %v", err)
 	}
 
 	// 3️⃣ K3s Agent (kubelet + 컨테이너 런타임) 시작 및 Nautilus TEE 등록
 	log.Printf("🔧 K3s Agent 및 Nautilus TEE 연결 시작...")
 	if err := stakerHost.StartK3sAgent(); err != nil {
-		// 개발/테스트 환경에서는 Mock 모드로 계속 진행
-		if os.Getenv("MOCK_MODE") == "true" {
-			log.Printf("⚠️ K3s Agent 시작 실패하지만 Mock 모드로 계속 진행: %v", err)
+		// mock 체인(오프라인 개발)에서는 K3s 바이너리 없이도 스테이킹/하트비트 흐름을 계속 확인
+		if stakerHost.suiClient.backend.Name() == chain.MockBackendName {
+			log.Printf("⚠️ K3s Agent 시작 실패하지만 mock 체인이므로 계속 진행: %v", err)
 		} else {
 			log.Fatalf("❌ K3s Agent 시작 실패: %v", err)
 		}
@@ -198,32 +364,74 @@ func main() {
 	log.Printf("💓 하트비트 서비스 시작...")
 	stakerHost.StartHeartbeat()
 
-	// 5️⃣ HTTP API 서버 시작 (포트 10250 - kubelet 포트와 동일)
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+	// 5️⃣ HTTP API 서버 시작 (기본 포트 10260 - kubelet 10250과 분리)
+	// 전용 라우터 사용 - 다른 컴포넌트가 DefaultServeMux에 등록한 핸들러와 섞이지 않음
+	// 등록한 Operation으로 관리 API 명세(/openapi-daas.json)를 생성
+	mux := httpserver.NewRouter("K3s-DaaS worker node API", "v1")
+	mux.Handle("/openapi-daas.json", mux.SpecHandler(), httpserver.Operation{
+		Summary: "OpenAPI document for the node API", Tags: []string{"meta"}, Response: map[string]interface{}{},
+	})
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		// 📊 노드 상태 정보를 JSON으로 반환
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		health := map[string]interface{}{
 			"status":         "healthy",                        // 노드 상태
 			"node_id":        stakerHost.config.NodeID,         // 노드 식별자
 			"staking_status": stakerHost.stakingStatus,         // 스테이킹 상태 (Seal 토큰 포함)
 			"running_pods":   stakerHost.getRunningPodsCount(), // 실행 중인 Pod 수
 			"timestamp":      time.Now().Unix(),                // 응답 시각
-		})
+			"version":        version.Get("worker"),            // 빌드 버전
+		}
+		// ⛽ 지갑 잔액 감시 결과 (비활성화면 생략)
+		if wallet := stakerHost.wallet.snapshot(); wallet != nil {
+			health["wallet"] = wallet
+		}
+		// 🧾 마스터 빌드 출처 확인 결과 (비활성화면 생략)
+		if build := stakerHost.masterBuild.snapshot(); build != nil {
+			health["master_build"] = build
+		}
+		// 🚩 ?verbose면 기능 게이트 상태 포함
+		if _, verbose := r.URL.Query()["verbose"]; verbose {
+			health["feature_gates"] = features.States()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(health)
+	}, httpserver.Operation{
+		Summary: "Node health and staking status", Tags: []string{"node"},
+		Query: []httpserver.Param{{Name: "verbose", Type: "boolean", Description: "include feature gate states"}},
+		Response: map[string]interface{}{
+			"status": "", "node_id": "", "staking_status": &StakingStatus{}, "running_pods": 0, "timestamp": int64(0),
+			"version": version.Info{},
+			"wallet": map[string]interface{}{},
+			"master_build": map[string]interface{}{},
+			"feature_gates": []featuregate.State{},
+		},
 	})
 
 	// 📊 스테이킹 상태 상세 정보 엔드포인트
-	http.HandleFunc("/api/v1/staking", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/api/v1/staking", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 
 		stakingInfo := map[string]interface{}{
 			"node_id":       stakerHost.config.NodeID,
 			"wallet_address": stakerHost.config.SuiWalletAddress,
-			"stake_amount":  stakerHost.config.StakeAmount,
+			"stake_amount":  stakerHost.currentStakeAmount(),
+			"stake_tier":    stakerHost.stakeTier(stakerHost.currentStakeAmount()),
 			"min_stake":     stakerHost.config.MinStakeAmount,
 			"status":        stakerHost.stakingStatus,
 			"seal_token":    stakerHost.sealToken,
 			"contract_address": stakerHost.config.ContractAddress,
 			"last_heartbeat": stakerHost.lastHeartbeat,
+			"stake_check":    stakerHost.stakeMonitor.snapshot(),
 		}
 
 		if stakerHost.sealToken != "" {
@@ -231,10 +439,22 @@ func main() {
 		}
 
 		json.NewEncoder(w).Encode(stakingInfo)
+	}, httpserver.Operation{
+		Summary: "Stake amount, tier, seal token and last on-chain stake check", Tags: []string{"staking"},
+		Response: map[string]interface{}{
+			"node_id": "", "wallet_address": "", "stake_amount": uint64(0), "stake_tier": "", "min_stake": uint64(0),
+			"status": &StakingStatus{}, "seal_token": "", "seal_token_short": "", "contract_address": "",
+			"last_heartbeat": int64(0), "stake_check": map[string]interface{}{},
+		},
 	})
 
 	// 📈 노드 메트릭스 엔드포인트
-	http.HandleFunc("/api/v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/api/v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 
 		metrics := map[string]interface{}{
@@ -243,19 +463,40 @@ func main() {
 			"memory_usage":   stakerHost.getMemoryUsage(),
 			"cpu_usage":      stakerHost.getCPUUsage(),
 			"disk_usage":     stakerHost.getDiskUsage(),
+			"image_gc":       stakerHost.imageGCStats(),
 			"network_stats":  stakerHost.getNetworkStats(),
 			"uptime_seconds": time.Since(stakerHost.startTime).Seconds(),
+			"clock_skew_ms":  stakerHost.clockSkew.Milliseconds(),
+			"staking":        stakerHost.stakingStatus.metrics(), // 상태 전이 수와 거부한 전이 수
+			"http_panics":    httpserver.PanicCount(), // 복구된 핸들러 panic 횟수
 			"timestamp":      time.Now().Unix(),
 		}
+		if balance, ok := stakerHost.wallet.balanceMist(); ok {
+			metrics["wallet_balance_mist"] = balance
+		}
 
 		json.NewEncoder(w).Encode(metrics)
+	}, httpserver.Operation{
+		Summary: "Node resource usage, image GC and network statistics", Tags: []string{"node"},
+		Response: map[string]interface{}{
+			"node_id": "", "running_pods": 0, "memory_usage": map[string]interface{}{}, "cpu_usage": map[string]interface{}{},
+			"disk_usage": map[string]interface{}{}, "image_gc": ImageGCStats{}, "network_stats": map[string]interface{}{},
+			"uptime_seconds": 0.0, "clock_skew_ms": int64(0), "http_panics": uint64(0), "timestamp": int64(0),
+			"wallet_balance_mist": uint64(0),
+		},
 	})
 
 	// 🔧 노드 설정 정보 엔드포인트
-	http.HandleFunc("/api/v1/config", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/api/v1/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 
 		// 민감한 정보는 마스킹
+		configBundle, _ := stakerHost.configSync.current()
 		configInfo := map[string]interface{}{
 			"node_id":           stakerHost.config.NodeID,
 			"sui_rpc_endpoint":  stakerHost.config.SuiRPCEndpoint,
@@ -264,13 +505,58 @@ func main() {
 			"container_runtime": stakerHost.config.ContainerRuntime,
 			"min_stake_amount":  stakerHost.config.MinStakeAmount,
 			"wallet_masked":     stakerHost.config.SuiWalletAddress[:8] + "...",
+			"heartbeat_collectors": stakerHost.collectors.names(),
+			"master_endpoints":  stakerHost.network.master.status(),
+			"sui_rpc_endpoints": stakerHost.network.suiRPC.status(),
+			"config_version":    configBundle.Version,
+			"registry_mirrors":  stakerHost.registryMirrorEndpoints(),
+			"heartbeat_interval_seconds": int(stakerHost.heartbeatInterval().Seconds()),
+			"feature_gates":     configBundle.Settings.FeatureGates,
 		}
 
 		json.NewEncoder(w).Encode(configInfo)
+	}, httpserver.Operation{
+		Summary: "Node configuration with the wallet address masked", Tags: []string{"node"},
+		Response: map[string]interface{}{
+			"node_id": "", "sui_rpc_endpoint": "", "contract_address": "", "nautilus_endpoint": "",
+			"container_runtime": "", "min_stake_amount": uint64(0), "wallet_masked": "",
+			"heartbeat_collectors": []string{}, "master_endpoints": []endpointHealth{}, "sui_rpc_endpoints": []endpointHealth{},
+			"config_version": int64(0), "registry_mirrors": []string{}, "heartbeat_interval_seconds": 0, "feature_gates": map[string]bool{},
+		},
 	})
 
+	// 🧪 콜드 스타트 프리플라이트 결과 (통과/실패 매트릭스)
+	mux.HandleFunc("/api/v1/preflight", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		report, err := stakerHost.preflightReport()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}, httpserver.Operation{
+		Summary: "Cold-start preflight checks (k3s binary, cgroup v2, br_netfilter, swap, ports)", Tags: []string{"node"},
+		Response: &PreflightReport{},
+	})
+
+	// 📶 다른 워커의 대역폭/지연 측정 대상 (마스터가 전달한 X-Probe-Token 필요)
+	mux.HandleFunc("/api/v1/bandwidth-probe", stakerHost.handlePeerProbe, httpserver.Operation{
+		Summary: "Probe payload for peer bandwidth and latency measurement (X-Probe-Token from the master)", Tags: []string{"node"},
+		Query:    []httpserver.Param{{Name: "bytes", Type: "integer", Description: "payload size (0 for a latency probe, at most 8MiB)"}},
+		Response: "", ContentType: "application/octet-stream",
+		Errors:   []int{http.StatusUnauthorized, http.StatusTooManyRequests},
+	})
+
+	// 🔑 관리용 엔드포인트 인증 (K3S_DAAS_ADMIN_TOKEN 설정 시 Bearer 토큰 필요)
+	requireAdmin := httpserver.RequireToken("Authorization", os.Getenv("K3S_DAAS_ADMIN_TOKEN"))
+
 	// 🔄 Nautilus 마스터 노드 등록 엔드포인트
-	http.HandleFunc("/api/v1/register", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/api/v1/register", requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -278,6 +564,12 @@ func main() {
 
 		// 현재 Seal 토큰으로 Nautilus에 등록 시도
 		err := stakerHost.registerWithNautilus()
+		var pending *enrollmentPendingError
+		if errors.As(err, &pending) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(pending.retryAfter.Seconds())))
+			http.Error(w, err.Error(), http.StatusAccepted)
+			return
+		}
 		if err != nil {
 			log.Printf("❌ Nautilus 등록 실패: %v", err)
 			http.Error(w, fmt.Sprintf("Registration failed: %v", err), http.StatusInternalServerError)
@@ -291,10 +583,44 @@ func main() {
 			"message":  "Successfully registered with Nautilus master",
 			"timestamp": time.Now().Unix(),
 		})
+	})), httpserver.Operation{
+		Method: http.MethodPost, Summary: "Register this node with the Nautilus master", Tags: []string{"staking"},
+		Auth:     httpserver.AuthAdminToken,
+		Response: map[string]interface{}{"status": "", "node_id": "", "message": "", "timestamp": int64(0)},
+		Errors:   []int{http.StatusAccepted, http.StatusUnauthorized, http.StatusInternalServerError},
+	})
+
+	// 📜 Pod 로그 조회 (재시작/삭제된 컨테이너 포함, 로그 내용이 민감하므로 관리자 토큰 필요)
+	mux.Handle("/api/v1/logs", requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		result, err := stakerHost.podLogQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})), httpserver.Operation{
+		Summary: "Captured pod logs by pod, container and time range", Tags: []string{"node"},
+		Auth: httpserver.AuthAdminToken,
+		Query: []httpserver.Param{
+			{Name: "namespace", Description: "default if omitted"},
+			{Name: "pod", Required: true},
+			{Name: "container", Description: "all containers of the pod if omitted"},
+			{Name: "since", Description: "RFC3339 time or a duration ago such as 1h"},
+			{Name: "until", Description: "RFC3339 time or a duration ago"},
+			{Name: "limit", Type: "integer", Description: "most recent entries to return (default 1000, max 10000)"},
+		},
+		Response: &PodLogResult{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
 	})
 
 	// 💔 강제 스테이킹 해제 엔드포인트 (관리용)
-	http.HandleFunc("/api/v1/unstake", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/api/v1/unstake", requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -308,10 +634,15 @@ func main() {
 			return
 		}
 
-		stakerHost.stakingStatus.Status = "unstaked"
-		stakerHost.stakingStatus.IsStaked = false
-		stakerHost.stakingStatus.SealToken = ""
+		if err := stakerHost.stakingStatus.Transition(stakingUnstaked, func(status *StakingStatus) {
+			status.SealToken = ""
+		}); err != nil {
+			log.Printf("❌ 스테이킹 해제 상태 반영 실패: %v", err)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		stakerHost.sealToken = ""
+		stakerHost.saveStakingState()
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -320,14 +651,127 @@ func main() {
 			"message":  "Successfully unstaked from Sui",
 			"timestamp": time.Now().Unix(),
 		})
+	})), httpserver.Operation{
+		Method: http.MethodPost, Summary: "Withdraw the whole stake and leave the cluster", Tags: []string{"staking"},
+		Auth:     httpserver.AuthAdminToken,
+		Response: map[string]interface{}{"status": "", "node_id": "", "message": "", "timestamp": int64(0)},
+		Errors:   []int{http.StatusUnauthorized, http.StatusInternalServerError},
+	})
+
+	// 💰 스테이킹 추가 예치 / 부분 인출 (노드 재등록 없이 스테이킹 양과 티어 변경)
+	mux.Handle("/api/v1/stake/", requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request struct {
+			Amount uint64   `json:"amount"` // MIST 단위
+			Coins  []string `json:"coins"`  // 추가 예치에 합칠 코인 Object ID (선택)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		var amount uint64
+		var err error
+		switch strings.TrimPrefix(r.URL.Path, "/api/v1/stake/") {
+		case "top-up":
+			amount, err = stakerHost.topUpStake(request.Amount, request.Coins)
+		case "withdraw":
+			amount, err = stakerHost.withdrawStake(request.Amount)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"node_id":      stakerHost.config.NodeID,
+			"stake_amount": amount,
+			"stake_tier":   stakerHost.stakeTier(amount),
+			"timestamp":    time.Now().Unix(),
+		})
+	})), httpserver.Operation{
+		Method: http.MethodPost, Path: "/api/v1/stake/top-up", Summary: "Add coins to the existing stake", Tags: []string{"staking"},
+		Auth:     httpserver.AuthAdminToken,
+		Request:  map[string]interface{}{"amount": uint64(0), "coins": []string{}},
+		Response: map[string]interface{}{"node_id": "", "stake_amount": uint64(0), "stake_tier": "", "timestamp": int64(0)},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	}, httpserver.Operation{
+		Method: http.MethodPost, Path: "/api/v1/stake/withdraw", Summary: "Withdraw part of the stake, keeping the role minimum", Tags: []string{"staking"},
+		Auth:     httpserver.AuthAdminToken,
+		Request:  map[string]interface{}{"amount": uint64(0)},
+		Response: map[string]interface{}{"node_id": "", "stake_amount": uint64(0), "stake_tier": "", "timestamp": int64(0)},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
 	})
 
+	// 🔒 리슨 주소/TLS 설정 (K3S_DAAS_LISTEN_ADDR, K3S_DAAS_TLS_CERT_FILE 등 환경변수 우선)
+	// 미들웨어 체인: 접근 제한 → 요청 ID → panic 복구 (Status 500) → 접근 로그 (헬스체크 제외)
+	handler := httpserver.Chain(mux, stakerHost.firewall.middleware, httpserver.RequestID, httpserver.Recovery(log.Printf), httpserver.Logging(log.Printf, "/health"))
+	serverConfig := httpserver.ConfigFromEnv("K3S_DAAS", stakerHost.config.ListenAddr)
+	server, err := httpserver.New(serverConfig, handler)
+	if err != nil {
+		log.Fatalf("❌ 상태 서버 설정 오류: %v", err)
+	}
+	if err := stakerHost.firewall.attach(server, serverConfig.TLSEnabled()); err != nil {
+		log.Fatalf("❌ 상태 서버 설정 오류: %v", err)
+	}
+
 	log.Printf("✅ K3s-DaaS 스테이커 호스트 '%s' 준비 완료!", stakerHost.config.NodeID)
-	log.Printf("🌐 상태 확인 서버 실행 중: http://localhost:10250/health")
+	log.Printf("🌐 상태 확인 서버 실행 중: %s/health", server.Description())
 	log.Printf("💡 Ctrl+C로 종료")
 
-	// 🌐 HTTP 서버 시작 (블로킹 - 이 지점에서 프로그램이 계속 실행됨)
-	log.Fatal(http.ListenAndServe(":10250", nil))
+	// 🌐 HTTP 서버 시작 (블로킹 - SIGINT/SIGTERM 시 진행 중 요청 완료 후 종료)
+	ctx, stop := service.NotifyContext(context.Background(), name)
+	defer stop()
+
+	// 💽 디스크/메모리 압박 감지 (하트비트로 마스터에 보고)
+	go stakerHost.runPressureMonitor(ctx)
+	go stakerHost.runImageGC(ctx)
+	go stakerHost.runPodLogCollector(ctx)
+
+	// 🩺 커널 교착/OOM 폭주/디스크 오류 감지 (하트비트로 보고, 마스터가 taint)
+	go stakerHost.runNodeProblemDetector(ctx)
+
+	// ⛽ 지갑 가스 잔액 감시 (부족하면 하트비트로 보고, testnet은 faucet 충전)
+	go stakerHost.runWalletMonitor(ctx)
+
+	// 🧾 마스터 빌드 출처 재확인 (업그레이드나 온체인 허용 취소 감지)
+	go stakerHost.runMasterBuildCheck(ctx)
+
+	// 📶 마스터/피어 대역폭 측정 (하트비트로 보고, 마스터가 노드 라벨로 반영)
+	go stakerHost.runNetworkProbe(ctx)
+
+	// 🧱 상태 포트 호스트 방화벽 규칙 (host_firewall, 종료 시 제거)
+	go stakerHost.firewall.run(ctx)
+
+	// 🔑 secrets 볼륨 드라이버의 Secret 해석 요청 처리 (Seal 토큰으로 마스터에 JWT 발급/복호화 요청)
+	if !stakerHost.config.Secrets.Disabled {
+		go func() {
+			if err := serveSecretsSocket(ctx, stakerHost); err != nil {
+				log.Printf("❌ secrets 소켓 오류: %v", err)
+			}
+		}()
+	}
+
+	// 🛡️ 에폭마다 무작위 선택 시 마스터 TEE 증명 교차 검증
+	if stakerHost.config.PeerAttestation {
+		go stakerHost.runPeerAttestation(ctx)
+	}
+
+	if err := server.Run(ctx, 10*time.Second); err != nil {
+		log.Fatalf("❌ 상태 서버 오류: %v", err)
+	}
+
+	// 🛑 노드 정리 (하트비트 중단, 컨테이너 정리, 온체인 offline)
+	stakerHost.Shutdown()
 }
 
 /*
@@ -348,14 +792,37 @@ func NewStakerHost(configPath string) (*StakerHost, error) {
 		return nil, fmt.Errorf("설정 파일 로드 실패: %v", err)
 	}
 
+	// 🌐 마스터/Sui RPC 호출이 공유하는 네트워크 계층 (DNS 캐시, 예비 주소)
+	network := newWorkerNetwork(config)
+
 	// 2️⃣ Sui 블록체인 클라이언트 초기화
 	// 스테이킹, Seal 토큰 생성, 상태 조회에 사용됩니다.
 	suiClient := &SuiClient{
 		rpcEndpoint: config.SuiRPCEndpoint, // Sui 테스트넷 RPC 엔드포인트
 		privateKey:  config.SuiPrivateKey,  // 트랜잭션 서명용 개인키 (hex)
-		client:      resty.New(),           // 재사용 가능한 HTTP 클라이언트
+		chain:       sui.NewReadOnlyClient(config.SuiRPCEndpoint, config.ContractAddress), // 공용 Sui 클라이언트
 		address:     config.SuiWalletAddress, // 지갑 주소
 	}
+	suiClient.chain.SetTransport(network.transport)
+	suiClient.backend, err = openChainBackend(config, suiClient.chain)
+	if err != nil {
+		return nil, fmt.Errorf("체인 백엔드 초기화 실패: %v", err)
+	}
+
+	// 📡 하트비트 수집기 (설정 오류는 시작 시 바로 알림)
+	collectors, err := newHeartbeatCollectors(config.HeartbeatCollectors, config.HeartbeatCollectorsMaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("하트비트 수집기 설정 오류: %v", err)
+	}
+	if names := collectors.names(); len(names) > 0 {
+		log.Printf("📡 하트비트 수집기: %s", strings.Join(names, ", "))
+	}
+
+	// 📜 Pod 로그 보관소 (kubelet 로그가 있는 프로세스에서만 - staking 모드는 런타임 에이전트에 조회 위임)
+	var podLogs *podLogStore
+	if workerMode() != modeStaking {
+		podLogs = newPodLogStore(config.PodLogs)
+	}
 
 	// 3️⃣ K3s 워커 노드 에이전트 초기화
 	// 실제 K3s 바이너리를 프로세스로 실행하여 완전한 워커 노드 기능을 제공합니다.
@@ -365,8 +832,8 @@ func NewStakerHost(configPath string) (*StakerHost, error) {
 		nodeID: config.NodeID,
 		kubelet: &Kubelet{
 			nodeID:    config.NodeID,
-			masterURL: config.NautilusEndpoint,
-			token:     "", // 초기에는 빈 값, RegisterStake 후에 Seal token으로 설정됨
+			masterURL: network.master.current(),
+			token:     "", // 초기에는 빈 값, Nautilus 등록 후 발급받은 조인 토큰으로 설정됨
 			dataDir:   filepath.Join(".", "k3s-data"),
 			ctx:       ctx,
 			cancel:    cancel,
@@ -376,35 +843,58 @@ func NewStakerHost(configPath string) (*StakerHost, error) {
 
 	// 4️⃣ 컨테이너 런타임 설정 (containerd 또는 docker)
 	// 설정에 따라 적절한 런타임 구현체를 선택합니다.
+	// staking 모드에서는 런타임 에이전트가 별도 프로세스이므로 런타임 바이너리를 요구하지 않습니다.
 	switch config.ContainerRuntime {
 	case "containerd":
-		runtime, err := NewContainerdRuntime() // containerd 사용
-		if err != nil {
-			log.Fatalf("❌ Containerd 런타임 초기화 실패: %v", err)
+		if workerMode() != modeStaking {
+			runtime, err := NewContainerdRuntime() // containerd 사용
+			if err != nil {
+				log.Fatalf("❌ Containerd 런타임 초기화 실패: %v", err)
+			}
+			k3sAgent.runtime = runtime
 		}
-		k3sAgent.runtime = runtime
 	case "docker":
-		runtime, err := NewDockerRuntime()     // docker 사용
-		if err != nil {
-			log.Fatalf("❌ Docker 런타임 초기화 실패: %v", err)
+		if workerMode() != modeStaking {
+			runtime, err := NewDockerRuntime()     // docker 사용
+			if err != nil {
+				log.Fatalf("❌ Docker 런타임 초기화 실패: %v", err)
+			}
+			k3sAgent.runtime = runtime
 		}
-		k3sAgent.runtime = runtime
 	default:
 		return nil, fmt.Errorf("지원하지 않는 컨테이너 런타임: %s", config.ContainerRuntime)
 	}
 
+	// 초기 상태는 대기중 (전이마다 로그)
+	stakingStatus := newStakingState()
+	stakingStatus.OnTransition(logStakingTransition)
+
 	// 5️⃣ 스테이커 호스트 인스턴스 생성 및 반환
 	return &StakerHost{
-		config:    config,
-		suiClient: suiClient,
-		k3sAgent:  k3sAgent,
-		stakingStatus: &StakingStatus{
-			Status: "pending", // 초기 상태는 대기중
-		},
+		config:        config,
+		suiClient:     suiClient,
+		k3sAgent:      k3sAgent,
+		stakingStatus: stakingStatus,
 		isRunning:     false,
 		sealToken:     "",
 		lastHeartbeat: 0,
 		startTime:     time.Now(),
+		stakeMonitor:  newStakeMonitor(),
+		pressure:      &pressureMonitor{},
+		problems:      newNodeProblemDetector(config.NodeProblems),
+		wallet:        newWalletMonitor(config.WalletMonitor),
+		fingerprint:   newHostFingerprinter(config.HostFingerprint),
+		masterBuild:   newMasterBuildCheck(config),
+		images:        &imageGC{},
+		heartbeats:    &heartbeatLog{},
+		collectors:    collectors,
+		network:       network,
+		configSync:    newConfigSync(config.WorkerConfigPath),
+		podLogs:       podLogs,
+		stakingStore:  newStakingStore(config),
+		netProbe:      newNetworkProber(),
+		firewall:      newStatusFirewall(config),
+		challenges:    newChallengeSolver(),
 	}, nil
 }
 
@@ -416,15 +906,15 @@ func NewK3sStakerHost_LEGACY(cfg *StakerHostConfig) (*StakerHost, error) {
 /*
 🌊 Seal 토큰 기반 스테이킹 등록 - K3s-DaaS의 핵심 기능
 
-이 함수는 다음 두 단계를 순차적으로 수행합니다:
+이 함수는 다음 두 단계를 하나의 PTB(Programmable Transaction Block)로 수행합니다:
 1️⃣ Sui 블록체인에 SUI 토큰을 스테이킹하여 노드 참여 권한 획득
 2️⃣ 스테이킹 증명으로 Seal 토큰 생성 (Nautilus TEE 인증용)
 
 Seal 토큰은 기존 K3s의 join token을 대체하여 블록체인 기반 인증을 제공합니다.
 
 플로우:
-스테이킹 트랜잭션 생성 → 블록체인 실행 → Object ID 추출 →
-Seal 토큰 트랜잭션 생성 → 블록체인 실행 → Seal 토큰 추출 → 상태 업데이트
+트랜잭션 생성 (스테이킹 → Seal 토큰) → 체인 백엔드로 실행 (원자적) →
+Object ID / Seal 토큰 추출 → 상태 업데이트
 
 반환값:
 - error: 스테이킹 또는 Seal 토큰 생성 과정에서 발생한 오류
@@ -432,128 +922,53 @@ Seal 토큰 트랜잭션 생성 → 블록체인 실행 → Seal 토큰 추출 
 func (s *StakerHost) RegisterStake() error {
 	log.Printf("🌊 Sui 블록체인에 스테이킹 등록 중... Node ID: %s", s.config.NodeID)
 
-	// 1️⃣ 스테이킹 트랜잭션 생성
-	// Sui RPC 2.0 표준 형식으로 트랜잭션 실행 요청을 구성합니다.
-	stakePayload := map[string]interface{}{
-		"jsonrpc": "2.0",                        // JSON-RPC 버전
-		"id":      1,                            // 요청 ID
-		"method":  "sui_executeTransactionBlock", // Sui 트랜잭션 실행 메소드
-		"params": []interface{}{
-			map[string]interface{}{
-				"txBytes": func() string {
-					txBytes, err := s.buildStakingTransaction()
-					if err != nil {
-						log.Printf("⚠️ 스테이킹 트랜잭션 빌드 실패: %v", err)
-						return ""
-					}
-					return txBytes
-				}(), // 스테이킹 트랜잭션 바이트 (Move 컨트랙트 호출)
-			},
-			[]string{s.config.SuiPrivateKey},        // 트랜잭션 서명용 개인키 배열
-			map[string]interface{}{
-				"requestType": "WaitForLocalExecution", // 로컬 실행 완료까지 대기
-				"options": map[string]bool{
-					"showObjectChanges": true, // 객체 변경사항 포함 (스테이킹 Object ID 추출용)
-					"showEffects":       true, // 트랜잭션 효과 포함
-				},
-			},
-		},
-	}
-
-	// HTTP POST 요청으로 Sui 테스트넷에 스테이킹 트랜잭션 전송
-	resp, err := s.suiClient.client.R().
-		SetHeader("Content-Type", "application/json"). // JSON 형식 지정
-		SetBody(stakePayload).                          // 위에서 구성한 스테이킹 payload
-		Post(s.config.SuiRPCEndpoint)                   // Sui 테스트넷 RPC 엔드포인트로 전송
-
-	if err != nil {
-		return fmt.Errorf("스테이킹 트랜잭션 전송 실패: %v", err)
-	}
-
-	// 🔍 Sui 블록체인 응답 파싱
-	var stakeResult map[string]interface{}
-	if err := json.Unmarshal(resp.Body(), &stakeResult); err != nil {
-		return fmt.Errorf("스테이킹 응답 파싱 실패: %v", err)
+	// ⏱️ Seal 토큰 만료 검증은 정확한 시계를 전제로 함
+	if err := s.ensureClockSync(); err != nil {
+		return err
 	}
 
-	// 📝 스테이킹 Object ID 추출 (블록체인에서 생성된 스테이킹 증명)
-	// 이 Object ID는 나중에 Seal 토큰 생성에 사용됩니다.
-	stakeObjectID, err := s.extractStakeObjectID(stakeResult)
-	if err != nil {
-		return fmt.Errorf("스테이킹 Object ID 추출 실패: %v", err)
-	}
-
-	log.Printf("✅ 스테이킹 성공! Stake Object ID: %s", stakeObjectID)
-
-	// 2️⃣ Seal 토큰 생성 (워커 노드용)
-	// 스테이킹 증명(Object ID)을 바탕으로 Seal 토큰을 생성합니다.
-	// 이 토큰은 기존 K3s join token을 대체하여 Nautilus TEE 인증에 사용됩니다.
-	sealPayload := map[string]interface{}{
-		"jsonrpc": "2.0",                        // JSON-RPC 버전
-		"id":      2,                            // 두 번째 요청 (스테이킹은 1번)
-		"method":  "sui_executeTransactionBlock", // 같은 Sui 트랜잭션 실행 메소드
-		"params": []interface{}{
-			map[string]interface{}{
-				"txBytes": func() string {
-					txBytes, err := s.buildSealTokenTransaction(stakeObjectID)
-					if err != nil {
-						log.Printf("⚠️ Seal 토큰 트랜잭션 빌드 실패: %v", err)
-						return ""
-					}
-					return txBytes
-				}(), // Seal 토큰 생성 트랜잭션 (스테이킹 Object ID 포함)
-			},
-			[]string{s.config.SuiPrivateKey},        // 동일한 개인키로 서명
-			map[string]interface{}{
-				"requestType": "WaitForLocalExecution", // 로컬 실행 완료까지 대기
-				"options": map[string]bool{
-					"showObjectChanges": true, // 객체 변경사항 포함 (Seal 토큰 추출용)
-					"showEffects":       true, // 트랜잭션 효과 포함
-				},
-			},
-		},
+	// 🏷️ 역할별 스테이킹 티어 사전 확인 (컨트랙트에서도 동일하게 검증)
+	if err := s.validateStakeTier(); err != nil {
+		return err
 	}
 
-	// HTTP POST 요청으로 Sui 테스트넷에 Seal 토큰 생성 트랜잭션 전송
-	sealResp, err := s.suiClient.client.R().
-		SetHeader("Content-Type", "application/json"). // JSON 형식 지정
-		SetBody(sealPayload).                           // 위에서 구성한 Seal 토큰 payload
-		Post(s.config.SuiRPCEndpoint)                   // 동일한 Sui 테스트넷 엔드포인트 사용
-
+	// 1️⃣ 스테이킹 + Seal 토큰 생성을 하나의 트랜잭션으로 구성
+	// 두 호출이 원자적으로 실행되어 가스/지연이 줄고 부분 완료가 발생하지 않습니다.
+	// 🚨 하나라도 실패하면 전체가 롤백되므로 재시도해도 안전
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	result, err := s.suiClient.backend.SubmitTx(ctx, s.buildRegistrationTransaction())
 	if err != nil {
-		return fmt.Errorf("Seal 토큰 생성 요청 실패: %v", err)
+		return fmt.Errorf("스테이킹 및 Seal 토큰 생성 실패: %v", err)
 	}
 
-	// 🔍 Sui 블록체인 응답 파싱 (Seal 토큰)
-	var sealResult map[string]interface{}
-	if err := json.Unmarshal(sealResp.Body(), &sealResult); err != nil {
-		return fmt.Errorf("Seal 토큰 응답 파싱 실패: %v", err)
+	// 2️⃣ 같은 트랜잭션 결과에서 스테이킹 증명과 Seal 토큰 추출
+	stakeObjectID := result.CreatedOfType("StakeRecord")
+	if stakeObjectID == "" {
+		return fmt.Errorf("스테이킹 Object ID 추출 실패: 트랜잭션 %s에 StakeRecord가 없습니다", result.Digest)
 	}
+	log.Printf("✅ 스테이킹 성공! Stake Object ID: %s", stakeObjectID)
 
-	// 🔑 Seal 토큰 추출 (블록체인에서 생성된 인증 토큰)
-	// 이 토큰이 기존 K3s join token을 완전히 대체합니다.
-	sealToken, err := s.extractSealToken(sealResult)
-	if err != nil {
-		return fmt.Errorf("Seal 토큰 추출 실패: %v", err)
+	// 🔑 Seal 토큰은 기존 K3s join token을 대체하여 Nautilus TEE 인증에 사용됩니다.
+	sealToken := result.CreatedOfType("SealToken")
+	if sealToken == "" {
+		return fmt.Errorf("Seal 토큰 추출 실패: 트랜잭션 %s에 SealToken이 없습니다", result.Digest)
 	}
 
 	// 📊 스테이킹 상태 업데이트 - 모든 정보를 로컬에 저장
-	s.stakingStatus.IsStaked = true                    // 스테이킹 완료 플래그
-	s.stakingStatus.StakeAmount = s.config.StakeAmount // 스테이킹한 SUI 양 (MIST 단위)
-	s.stakingStatus.StakeObjectID = stakeObjectID      // 블록체인의 스테이킹 증명 ID
-	s.stakingStatus.SealToken = sealToken              // 생성된 Seal 토큰
-	s.stakingStatus.Status = "active"                  // 활성 상태로 설정
-	s.stakingStatus.LastValidation = time.Now().Unix() // 현재 시간으로 검증 시각 설정
+	// pending → active 전이와 함께 기록 (IsStaked는 상태 머신이 설정)
+	if err := s.stakingStatus.Transition(stakingActive, func(status *StakingStatus) {
+		status.StakeAmount = s.config.StakeAmount // 스테이킹한 SUI 양 (MIST 단위)
+		status.StakeObjectID = stakeObjectID      // 블록체인의 스테이킹 증명 ID
+		status.SealToken = sealToken              // 생성된 Seal 토큰
+		status.LastValidation = time.Now().Unix() // 현재 시간으로 검증 시각 설정
+	}); err != nil {
+		return err
+	}
 
 	// 🔄 캐시된 sealToken 필드도 동기화
 	s.sealToken = sealToken
 
-	// 🔑 K3s Agent에서 Seal 토큰을 사용하도록 설정 업데이트
-	if s.k3sAgent != nil && s.k3sAgent.kubelet != nil {
-		s.k3sAgent.kubelet.token = sealToken
-		log.Printf("🔧 K3s Agent에 Seal 토큰 설정 완료")
-	}
-
 	log.Printf("✅ Seal 토큰 생성 성공! Token ID: %s", sealToken)
 	log.Printf("🎉 스테이킹 및 Seal 토큰 준비 완료!")
 
@@ -568,8 +983,8 @@ K3s Agent 시작 함수 - 실제 워커 노드 기능 활성화
 
 플로우:
 1. 스테이킹 완료 여부 검증
-2. Kubelet 시작 (Pod 실행 준비)
-3. Nautilus TEE에 Seal 토큰으로 워커 노드 등록
+2. Nautilus TEE에 Seal 토큰으로 워커 노드 등록 (노드 전용 1회용 조인 토큰 발급)
+3. 발급받은 조인 토큰으로 Kubelet 시작 (토큰 만료 전에 조인해야 함)
 
 반환값:
 - error: kubelet 시작 또는 Nautilus 등록 과정에서 발생한 오류
@@ -578,30 +993,61 @@ func (s *StakerHost) StartK3sAgent() error {
 	log.Printf("🚀 K3s Agent 시작 중... Node ID: %s", s.config.NodeID)
 
 	// ✅ 전제조건 검증: 스테이킹과 Seal 토큰이 준비되었는지 확인
-	if !s.stakingStatus.IsStaked {
+	if !s.stakingStatus.IsStaked() {
 		return fmt.Errorf("K3s Agent 시작 불가: 스테이킹이 완료되지 않음")
 	}
 
-	if s.stakingStatus.SealToken == "" {
+	if s.stakingStatus.SealToken() == "" {
 		return fmt.Errorf("K3s Agent 시작 불가: Seal 토큰이 생성되지 않음")
 	}
 
-	// 🚀 실제 K3s Agent 시작 (기존 시뮬레이션 kubelet 대체)
-	if err := s.startRealK3sAgent(); err != nil {
-		return fmt.Errorf("실제 K3s Agent 시작 실패: %v", err)
+	// 🧪 필수 프리플라이트 검사가 실패한 노드는 Nautilus에 등록하지 않음 (스케줄 대상이 되지 않도록)
+	report, err := s.preflightReport()
+	if err != nil {
+		return fmt.Errorf("프리플라이트 결과 조회 실패: %v", err)
+	}
+	if err := report.err(); err != nil {
+		return err
 	}
 
 	// 🔒 Nautilus TEE에 Seal 토큰으로 등록
-	// 이 단계에서 워커 노드가 클러스터에 공식적으로 참여합니다.
-	if err := s.registerWithNautilus(); err != nil {
+	// 마스터가 Seal 토큰을 다시 검증한 뒤 이 노드만 쓸 수 있는 조인 토큰을 발급합니다.
+	// 📋 승인 모드 마스터는 운영자가 심사할 때까지 202를 돌려주므로 Retry-After 간격으로 재시도
+	err = s.registerWithNautilus()
+	var pending *enrollmentPendingError
+	for errors.As(err, &pending) {
+		log.Printf("📋 %v - %s 후 다시 등록합니다", err, pending.retryAfter)
+		time.Sleep(pending.retryAfter)
+		err = s.registerWithNautilus()
+	}
+	if err != nil {
 		return fmt.Errorf("Nautilus TEE 등록 실패: %v", err)
 	}
 
+	// 🚀 실제 K3s Agent 시작 (staking 모드는 런타임 에이전트 프로세스에 위임)
+	if s.runtimeClient != nil {
+		if err := s.runtimeClient.StartAgent(s.stakingStatus.SealToken(), s.joinToken, s.stakingStatus.StakeAmount()); err != nil {
+			return fmt.Errorf("런타임 에이전트의 K3s Agent 시작 실패: %v", err)
+		}
+	} else if err := s.startRealK3sAgent(); err != nil {
+		return fmt.Errorf("실제 K3s Agent 시작 실패: %v", err)
+	}
+
 	log.Printf("✅ K3s Agent 시작 완료!")
 	return nil
 }
 
 
+// enrollmentPendingError - 승인 모드 마스터가 등록을 심사 중 (HTTP 202, retryAfter 후 재시도)
+type enrollmentPendingError struct {
+	retryAfter time.Duration
+	message    string
+}
+
+func (e *enrollmentPendingError) Error() string {
+	return fmt.Sprintf("Nautilus 등록 승인 대기 중: %s", e.message)
+}
+
 /*
 🔒 Nautilus TEE 워커 노드 등록 함수 - K3s-DaaS의 혁신적인 부분
 
@@ -613,7 +1059,7 @@ K3s-DaaS는 Seal 토큰을 사용하여 블록체인 기반 인증을 수행합
 2️⃣ Nautilus TEE에 직접 연결하여 Seal 토큰으로 워커 노드 등록
 
 이 방식의 장점:
-- 중앙화된 join token 관리 불필요
+- 클러스터 전체용 join token을 워커에 배포하지 않음 (응답의 조인 토큰은 이 노드 전용, 짧은 TTL, 1회용)
 - 블록체인 기반 스테이킹으로 보안성 확보
 - TEE에서 토큰 검증으로 위변조 방지
 
@@ -621,6 +1067,15 @@ K3s-DaaS는 Seal 토큰을 사용하여 블록체인 기반 인증을 수행합
 - error: Nautilus 정보 조회 또는 등록 과정에서 발생한 오류
 */
 func (s *StakerHost) registerWithNautilus() error {
+	if err := s.ensureClockSync(); err != nil {
+		return err
+	}
+
+	// 🧾 허용 목록에 없는 마스터 빌드면 refuse 정책에서 등록 중단
+	if err := s.checkMasterBuild(); err != nil {
+		return err
+	}
+
 	log.Printf("🔑 Nautilus TEE 정보 조회 중...")
 
 	// 1️⃣ Sui 컨트랙트에서 Nautilus TEE 엔드포인트 정보 조회
@@ -636,15 +1091,22 @@ func (s *StakerHost) registerWithNautilus() error {
 	// 기존 K3s join token 대신 Seal 토큰을 사용합니다.
 	registrationPayload := map[string]interface{}{
 		"node_id":    s.config.NodeID,         // 워커 노드 식별자
-		"seal_token": s.stakingStatus.SealToken, // 블록체인 기반 인증 토큰
+		"seal_token": s.stakingStatus.SealToken(), // 블록체인 기반 인증 토큰
 		"timestamp":  time.Now().Unix(),       // 요청 시각 (replay 공격 방지)
+		"region":     s.config.Region,         // 워커 위치 리전
+		"zone":       s.config.Zone,           // 워커 위치 존
 	}
 
+	// 🔁 엔드포인트 페일오버/재시도가 같은 키를 보내 마스터가 첫 응답(같은 조인 토큰)을 재생하도록 함
+	idempotencyKey := fmt.Sprintf("register-%s-%d", s.config.NodeID, time.Now().UnixNano())
+
 	// 🌐 Nautilus TEE에 HTTP 등록 요청 전송
 	// X-Seal-Token 헤더로 추가 인증을 수행합니다.
-	resp, err := resty.New().R().
+	resp, err := s.restyClient().R().
 		SetHeader("Content-Type", "application/json").           // JSON 형식 지정
-		SetHeader("X-Seal-Token", s.stakingStatus.SealToken).    // Seal 토큰 헤더 추가 (이중 인증)
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).  // Seal 토큰 헤더 추가 (이중 인증)
+		SetHeader(httpserver.HeaderIdempotencyKey, idempotencyKey). // 재시도 중복 등록 방지
+		SetHeader(version.Header, version.Get("worker").HeaderValue()). // 버전 핸드셰이크 (마스터가 차이 정책 적용)
 		SetBody(registrationPayload).                            // 등록 정보 전송
 		Post(nautilusInfo.Endpoint + "/api/v1/register-worker")  // Nautilus TEE 워커 등록 엔드포인트
 
@@ -652,12 +1114,45 @@ func (s *StakerHost) registerWithNautilus() error {
 		return fmt.Errorf("Nautilus TEE 연결 실패: %v", err)
 	}
 
+	// 🔖 마스터가 지원 범위를 벗어난 버전이라며 거부 (NAUTILUS_VERSION_SKEW_POLICY=refuse)
+	if resp.StatusCode() == http.StatusUpgradeRequired {
+		return fmt.Errorf("마스터가 워커 버전 %s을(를) 지원하지 않습니다: %s", version.Version, resp.String())
+	}
+
+	// 🔖 워커 쪽에서도 마스터 버전 확인 (K3S_DAAS_VERSION_SKEW_POLICY, 이전 마스터는 헤더 없음)
+	if err := checkMasterVersion(resp.Header().Get(version.Header)); err != nil {
+		return err
+	}
+
+	// 📋 승인 모드 마스터가 아직 심사 중 (거절되면 403으로 아래에서 오류 처리)
+	if resp.StatusCode() == http.StatusAccepted {
+		retryAfter := 30 * time.Second
+		if seconds, err := strconv.Atoi(resp.Header().Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return &enrollmentPendingError{retryAfter: retryAfter, message: strings.TrimSpace(resp.String())}
+	}
+
 	// 📋 등록 결과 검증
 	if resp.StatusCode() != 200 {
 		return fmt.Errorf("Nautilus TEE가 등록을 거부했습니다 (HTTP %d): %s",
 			resp.StatusCode(), resp.String())
 	}
 
+	// 🎟️ 발급받은 조인 토큰 저장 (expires_at 전에 K3s Agent가 조인해야 함)
+	var registration struct {
+		JoinToken string `json:"join_token"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	if err := json.Unmarshal(resp.Body(), &registration); err != nil || registration.JoinToken == "" {
+		return fmt.Errorf("Nautilus TEE 등록 응답에 조인 토큰이 없습니다: %s", resp.String())
+	}
+	s.joinToken = registration.JoinToken
+	if s.k3sAgent != nil && s.k3sAgent.kubelet != nil {
+		s.k3sAgent.kubelet.token = registration.JoinToken
+	}
+	log.Printf("🎟️ 조인 토큰 발급 완료 (만료: %s)", registration.ExpiresAt)
+
 	log.Printf("🔒 TEE connection established with Seal authentication")
 	log.Printf("✅ K3s Staker Host '%s' ready and running", s.config.NodeID)
 
@@ -667,38 +1162,39 @@ func (s *StakerHost) registerWithNautilus() error {
 /*
 💓 스테이킹 검증 및 하트비트 서비스 시작
 
-K3s-DaaS의 핵심 기능으로, 30초마다 다음을 수행합니다:
-1. Sui 블록체인에서 스테이킹 상태 검증 (슬래싱 여부 확인)
-2. Nautilus TEE에 하트비트 전송 (노드 생존 신호)
+K3s-DaaS의 핵심 기능으로, 두 작업을 독립적으로 수행합니다:
+1. Sui 블록체인에서 스테이킹 상태 검증 (기본 60초, 실패 시 지수 백오프 재시도)
+2. Nautilus TEE에 하트비트 전송 (30초마다, 노드 생존 신호)
 
 이는 전통적인 K3s와 다른 부분으로, 블록체인 기반 검증을 통해
 악의적인 노드를 자동으로 제거할 수 있습니다.
 
-스테이킹이 슬래시된 경우 노드를 자동으로 종료합니다.
+슬래싱이 연속 slash_confirmations회 확인된 경우에만 노드를 자동으로 종료합니다.
 */
 func (s *StakerHost) StartHeartbeat() {
-	log.Printf("💓 하트비트 서비스 시작 (30초 간격)")
+	// ⏰ 하트비트 타이머 생성 (기본 30초, 마스터 설정 번들로 변경 가능)
+	interval := s.heartbeatInterval()
+	log.Printf("💓 하트비트 서비스 시작 (%s 간격)", interval)
+	s.heartbeatTicker = time.NewTicker(interval)
 
-	// ⏰ 30초마다 실행되는 타이머 생성
-	s.heartbeatTicker = time.NewTicker(30 * time.Second)
+	// 🔗 스테이킹 상태 조회는 별도 고루틴에서 자체 재시도 일정으로 수행
+	// Sui RPC 장애가 마스터 하트비트(생존 신호)를 막지 않습니다.
+	go s.runStakeMonitor()
 
 	// 🔄 별도 고루틴에서 하트비트 처리 (메인 스레드 블록킹 방지)
 	go func() {
 		failureCount := 0
 		maxFailures := 3
+		disconnected := false // 마스터와 연결이 끊겼다가 복구되면 전체 동기화
 
 		for range s.heartbeatTicker.C { // 타이머가 틱할 때마다 실행
-			if err := s.validateStakeAndSendHeartbeat(); err != nil {
+			err := s.sendHeartbeat()
+			s.heartbeats.record(err)
+			if err != nil {
 				failureCount++
+				disconnected = true
 				log.Printf("⚠️ 하트비트 오류 (%d/%d): %v", failureCount, maxFailures, err)
 
-				// 🚨 치명적 오류: 스테이킹이 슬래시된 경우
-				if err.Error() == "stake_slashed" {
-					log.Printf("🛑 스테이킹이 슬래시되었습니다! 노드를 종료합니다...")
-					s.Shutdown() // 즉시 노드 종료
-					return       // 고루틴 종료
-				}
-
 				// 연속 실패가 임계값을 초과한 경우 K3s Agent 재시작 시도
 				if failureCount >= maxFailures {
 					log.Printf("🔄 연속 실패 %d회, K3s Agent 재시작 시도...", failureCount)
@@ -712,298 +1208,216 @@ func (s *StakerHost) StartHeartbeat() {
 					log.Printf("✅ 하트비트 복구됨, 실패 카운터 리셋")
 					failureCount = 0
 				}
+				// 🔁 오프라인 동안 놓친 drain/Pod 삭제를 반영한 뒤 다음 하트비트 진행
+				if disconnected {
+					if err := s.resyncWithMaster("재연결"); err != nil {
+						log.Printf("⚠️ 재연결 후 전체 동기화 실패 (다음 하트비트에 재시도): %v", err)
+						continue
+					}
+					disconnected = false
+				}
 			}
 		}
 	}()
 }
 
 /*
-📊 스테이킹 상태 검증 및 하트비트 전송 함수
+📊 하트비트 전송 함수
 
 하트비트 서비스의 핵심 로직으로 다음을 순차적으로 수행합니다:
-1️⃣ Sui 블록체인에서 현재 스테이킹 상태 조회 및 슬래싱 여부 확인
+1️⃣ 스테이킹 감시 고루틴이 마지막으로 확인한 스테이킹 상태 조회 (체인 직접 조회 없음)
 2️⃣ 노드 상태 정보 수집 (실행 중인 Pod 수, 리소스 사용량 등)
 3️⃣ Nautilus TEE에 하트비트 전송 (Seal 토큰으로 인증)
 
-이 과정을 통해 블록체인 기반의 노드 검증과 TEE 기반의 보안 통신을 동시에 수행합니다.
+스테이킹 검증은 runStakeMonitor가 담당하므로 Sui RPC 장애가 있어도 하트비트는 계속 전송됩니다.
 
 반환값:
-- error: 스테이킹 검증 또는 하트비트 전송 과정에서 발생한 오류
-        "stake_slashed" 오류는 노드 즉시 종료를 의미함
+- error: 하트비트 전송 과정에서 발생한 오류
 */
-func (s *StakerHost) validateStakeAndSendHeartbeat() error {
-	// 1️⃣ Sui 블록체인에서 스테이킹 상태 확인
-	// 다른 검증자들이 이 노드를 슬래싱했는지 확인합니다.
-	stakeInfo, err := s.checkStakeOnSui()
-	if err != nil {
-		return fmt.Errorf("스테이킹 상태 확인 실패: %v", err)
+func (s *StakerHost) sendHeartbeat() error {
+	// 1️⃣ 마지막으로 확인된 스테이킹 상태 (아직 조회 전이면 등록 시점 값)
+	stakeStatus, stakeAmount := s.stakingStatus.Status(), s.stakingStatus.StakeAmount()
+	stakeInfo, checkedAt := s.stakeMonitor.last()
+	if stakeInfo != nil {
+		stakeStatus, stakeAmount = stakeInfo.Status, stakeInfo.Amount
 	}
 
-	// 🚨 치명적 상황: 스테이킹이 슬래시된 경우
-	if stakeInfo.Status == "slashed" {
-		s.stakingStatus.Status = "slashed" // 로컬 상태도 업데이트
-		return fmt.Errorf("stake_slashed") // 특별한 오류 코드 반환
+	// ⏱️ 하트비트 타임스탬프는 재전송 방지에 사용되므로 시계 오차 확인
+	if err := s.ensureClockSync(); err != nil {
+		return err
 	}
 
 	// 2️⃣ 노드 상태 정보 수집 및 하트비트 payload 구성
 	heartbeatPayload := map[string]interface{}{
 		"node_id":         s.config.NodeID,       // 노드 식별자
 		"timestamp":       time.Now().Unix(),     // 현재 시각 (최신성 증명)
-		"stake_status":    stakeInfo.Status,      // 블록체인 스테이킹 상태
-		"stake_amount":    stakeInfo.Amount,      // 현재 스테이킹 양
+		"stake_status":    stakeStatus,           // 블록체인 스테이킹 상태 (마지막 확인 값)
+		"stake_amount":    stakeAmount,           // 현재 스테이킹 양
+		"stake_tier":      s.stakeTier(stakeAmount), // 스테이킹 양이 충족하는 티어 (추가 예치/인출 시 변경)
 		"running_pods":    s.getRunningPodsCount(), // 실행 중인 Pod 개수
 		"resource_usage":  s.getResourceUsage(),  // CPU/메모리/디스크 사용량
+		"region":          s.config.Region,       // 워커 위치 리전
+		"zone":            s.config.Zone,         // 워커 위치 존
+		"os":              runtime.GOOS,          // 노드 OS (kubernetes.io/os 라벨)
+		"arch":            runtime.GOARCH,        // 노드 아키텍처 (멀티 아키텍처 이미지 플랫폼 선택)
 	}
 
-	// 3️⃣ Nautilus TEE에 Seal 토큰 인증 하트비트 전송
-	_, err = resty.New().R().
-		SetHeader("Content-Type", "application/json").           // JSON 형식
-		SetHeader("X-Seal-Token", s.stakingStatus.SealToken).    // Seal 토큰 인증 헤더
-		SetBody(heartbeatPayload).                               // 노드 상태 정보
-		Post(s.config.NautilusEndpoint + "/api/v1/nodes/heartbeat") // Nautilus 하트비트 엔드포인트
-
-	if err != nil {
-		return fmt.Errorf("하트비트 전송 실패: %v", err)
+	// 💽 디스크/메모리 압박 조건 (측정 전이면 생략)
+	_, conditions := s.pressure.snapshot()
+	// 🩺 커널 로그로 감지한 노드 문제 (용량 기반 DiskPressure와 합침)
+	conditions = mergeNodeConditions(conditions, s.problems.conditions(time.Now()))
+	// ⛽ 지갑 가스 부족 (마스터가 워커 조건으로 표시)
+	conditions = append(conditions, s.wallet.condition()...)
+	// 🚱 로그 제한 중인 컨테이너 (마스터가 Pod 조건으로 반영, 빈 목록은 해제)
+	if throttles, ok := s.logThrottling(); ok {
+		conditions = append(conditions, logThrottleCondition(throttles))
+		heartbeatPayload["log_throttling"] = throttles
 	}
-
-	// ✅ 성공: 마지막 검증 시각 업데이트
-	currentTime := time.Now().Unix()
-	s.stakingStatus.LastValidation = currentTime
-	s.lastHeartbeat = currentTime
-	return nil
-}
-
-
-// Legacy 노드 종료 함수 - 대체된 함수 사용
-func (s *StakerHost) Shutdown_LEGACY() {
-	log.Printf("🛑 Shutting down staker host: %s", s.config.NodeID)
-
-	s.isRunning = false
-
-	// 실행 중인 컨테이너들 정리
-	if s.k3sAgent != nil && s.k3sAgent.runtime != nil {
-		// 컨테이너 정리 로직
-		log.Printf("🧹 Cleaning up containers...")
-		containers, _ := s.k3sAgent.runtime.ListContainers()
-		for _, container := range containers {
-			log.Printf("🛑 컨테이너 중단: %s", container.Name)
-			s.k3sAgent.runtime.StopContainer(container.Name)
-		}
+	if len(conditions) > 0 {
+		heartbeatPayload["node_conditions"] = conditions
 	}
 
-	log.Printf("✅ Staker host shutdown complete")
-	os.Exit(0)
-}
-
-// 설정 파일 로드
-func loadStakerConfig() (*StakerHostConfig, error) {
-	configPath := os.Getenv("STAKER_CONFIG_PATH")
-	if configPath == "" {
-		configPath = "./staker-config.json"
+	// 📊 Pod별 누적 네트워크 사용량 (마스터가 테넌트별 송수신량으로 집계)
+	if usage, ok := s.podNetwork(); ok {
+		heartbeatPayload["pod_network"] = usage
 	}
 
-	file, err := os.Open(configPath)
-	if err != nil {
-		return nil, err
+	// 🧮 Pod 자원 제한 강제 여부와 CPU 스로틀링/OOM kill (마스터가 Pod 조건으로 반영)
+	if usage, ok := s.podResources(); ok {
+		heartbeatPayload["pod_resources"] = usage
 	}
-	defer file.Close()
 
-	var config StakerHostConfig
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
-		return nil, err
+	// 🧬 호스트 OS/커널/런타임 지문 (마스터가 컴플라이언스 정책과 비교)
+	if fingerprint, ok := s.hostFingerprint(); ok {
+		heartbeatPayload["host_fingerprint"] = fingerprint
 	}
 
-	// 기본값 설정
-	if config.MinStakeAmount == 0 {
-		config.MinStakeAmount = 1000 // 1000 MIST
+	// 🌍 마스터까지의 지연시간 측정 (실패해도 하트비트는 전송)
+	if latency, err := s.measureMasterLatency(); err == nil {
+		heartbeatPayload["latency_ms"] = latency.Milliseconds()
+	} else {
+		log.Printf("⚠️ 마스터 지연시간 측정 실패: %v", err)
 	}
 
-	return &config, nil
-}
-
-/*
-스테이킹 트랜잭션 빌드 함수
-Sui Move 컨트랙트의 stake_for_node 함수를 호출하는 트랜잭션을 생성합니다.
+	// 📶 마지막 대역폭 측정 결과와 피어 측정 주소 (측정 전이면 주소만)
+	s.addNetworkProbe(heartbeatPayload)
 
-실제 구현에서는 Sui SDK를 사용하여:
-1. Move 컨트랙트 패키지 ID와 모듈명 지정
-2. stake_for_node(amount, node_id, staker_address) 함수 호출
-3. 트랜잭션을 바이트 형태로 직렬화
+	// 🧱 적용 중인 상태 포트 접근 정책 버전 (다르면 마스터가 새 정책 전달)
+	heartbeatPayload["status_access_version"] = s.firewall.appliedVersion()
 
-반환값:
-- string: 직렬화된 트랜잭션 바이트 (Base64 인코딩)
-*/
-func (s *StakerHost) buildStakingTransaction() (string, error) {
-	// 🎯 스테이킹 컨트랙트 호출을 위한 트랜잭션 구성
-	// MoveCall 트랜잭션 데이터 구조화
-
-	// 📋 Move 함수 호출 데이터
-	moveCall := map[string]interface{}{
-		"packageObjectId": s.config.ContractAddress, // 스마트 컨트랙트 주소
-		"module":          "staking",                 // 모듈명
-		"function":        "stake_for_node",          // 함수명
-		"typeArguments":   []string{},                // 타입 인자 없음
-		"arguments": []interface{}{
-			s.config.StakeAmount, // 스테이킹 양 (MIST 단위)
-			s.config.NodeID,      // 노드 ID
-		},
+	// 🕒 스테이킹 상태 확인 시각 (마스터가 오래된 값인지 판단)
+	if !checkedAt.IsZero() {
+		heartbeatPayload["stake_checked_at"] = checkedAt.Unix()
 	}
 
-	// 🏗️ 트랜잭션 블록 구성
-	txBlock := map[string]interface{}{
-		"version":    1,
-		"sender":     s.suiClient.address,
-		"gasPayment": nil,    // 자동으로 가스 코인 선택
-		"gasBudget":  "10000000", // 10M MIST 가스 한도
-		"gasPrice":   "1000", // 가스 가격
-		"transactions": []interface{}{
-			map[string]interface{}{
-				"MoveCall": moveCall,
-			},
-		},
+	// 📡 수집기 섹션 (크기 한도를 넘거나 실패한 수집기는 이유만 전송)
+	sections, failures := s.collectors.collect()
+	if len(sections) > 0 {
+		heartbeatPayload["collectors"] = sections
 	}
-
-	// 📤 JSON으로 직렬화 후 Base64 인코딩
-	txJSON, err := json.Marshal(txBlock)
-	if err != nil {
-		return "", fmt.Errorf("트랜잭션 직렬화 실패: %v", err)
+	if len(failures) > 0 {
+		heartbeatPayload["collector_errors"] = failures
 	}
 
-	return base64.StdEncoding.EncodeToString(txJSON), nil
-}
+	// 🧩 적용 중인 설정 번들 버전 (마스터가 최신 번들 전달 여부 판단), 거부한 번들
+	bundle, failure := s.configSync.current()
+	heartbeatPayload["config_version"] = bundle.Version
+	if failure != nil {
+		heartbeatPayload["config_error"] = failure
+	}
 
-/*
-Seal 토큰 트랜잭션 빌드 함수
-스테이킹 증명을 바탕으로 Seal 토큰을 생성하는 트랜잭션을 만듭니다.
+	// 🔍 이전 하트비트에서 받은 감사 프로브 응답 첨부
+	if s.probeAnswer != nil {
+		heartbeatPayload["probe_result"] = s.probeAnswer
+	}
 
-매개변수:
-- stakeObjectID: 앞서 생성된 스테이킹 오브젝트의 ID
+	// 🧩 계산을 마친 하트비트 챌린지 응답 첨부
+	challengeAnswer := s.challenges.pending()
+	if challengeAnswer != nil {
+		heartbeatPayload["challenge_answer"] = challengeAnswer
+	}
 
-실제 구현에서는 k8s_gateway 컨트랙트의 create_worker_seal_token 함수를 호출합니다.
+	// 3️⃣ Nautilus TEE에 Seal 토큰 인증 하트비트 전송
+	resp, err := s.restyClient().R().
+		SetHeader("Content-Type", "application/json").           // JSON 형식
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).  // Seal 토큰 인증 헤더
+		SetBody(heartbeatPayload).                               // 노드 상태 정보
+		Post(s.config.NautilusEndpoint + "/api/v1/nodes/heartbeat") // Nautilus 하트비트 엔드포인트
 
-반환값:
-- string: 직렬화된 트랜잭션 바이트 (Base64 인코딩)
-*/
-func (s *StakerHost) buildSealTokenTransaction(stakeObjectID string) (string, error) {
-	// 🎯 k8s_gateway::create_worker_seal_token 호출
-	// 스테이킹 검증 후 워커 노드용 Seal 토큰 생성
-
-	// 📝 Move 함수 호출 데이터
-	moveCall := map[string]interface{}{
-		"packageObjectId": s.config.ContractAddress, // k8s_gateway 컨트랙트 주소
-		"module":          "k8s_gateway",             // 모듈명
-		"function":        "create_worker_seal_token", // Seal 토큰 생성 함수
-		"typeArguments":   []string{},                 // 타입 인수 없음
-		"arguments": []interface{}{
-			stakeObjectID, // 스테이킹 객체 ID 전달
-		},
+	if err != nil {
+		return fmt.Errorf("하트비트 전송 실패: %v", err)
 	}
 
-	// 🏗️ 트랜잭션 블록 구성
-	txBlock := map[string]interface{}{
-		"version":    1,
-		"sender":     s.suiClient.address,
-		"gasPayment": nil,       // 자동으로 가스 코인 선택
-		"gasBudget":  "5000000", // 5M MIST 가스 한도
-		"gasPrice":   "1000",    // 가스 가격
-		"transactions": []interface{}{
-			map[string]interface{}{
-				"MoveCall": moveCall,
-			},
-		},
+	// 🔐 마스터가 Seal 토큰을 거부하면 체인 상태가 바뀌었을 수 있으므로 즉시 스테이킹 재확인
+	if resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden {
+		s.stakeMonitor.request()
+		return fmt.Errorf("마스터가 하트비트를 거부했습니다 (HTTP %d)", resp.StatusCode())
 	}
 
-	// 📤 JSON 직렬화 후 Base64 인코딩
-	txJSON, err := json.Marshal(txBlock)
-	if err != nil {
-		return "", fmt.Errorf("Seal 토큰 트랜잭션 직렬화 실패: %v", err)
+	// 🔍 응답에 새 감사 프로브가 있으면 다음 하트비트용 응답 준비
+	s.probeAnswer = nil
+	if challengeAnswer != nil && resp.IsSuccess() {
+		s.challenges.delivered(challengeAnswer.ID)
 	}
+	s.handleHeartbeatResponse(resp.Body())
+
+	// ✅ 성공: 마지막 검증 시각 업데이트
+	currentTime := time.Now().Unix()
+	s.stakingStatus.Update(func(status *StakingStatus) { status.LastValidation = currentTime })
+	s.lastHeartbeat = currentTime
 
-	return base64.StdEncoding.EncodeToString(txJSON), nil
+	// ⛽ 주기적으로 온체인 하트비트 기록 (노드 지갑 대신 마스터가 가스 지불)
+	s.recordHeartbeatOnChain()
+	return nil
 }
 
-/*
-스테이킹 Object ID 추출 함수
-Sui 트랜잭션 실행 결과에서 새로 생성된 스테이킹 오브젝트의 ID를 찾습니다.
 
-매개변수:
-- result: Sui RPC sui_executeTransactionBlock의 응답
+// Legacy 노드 종료 함수 - 대체된 함수 사용
+func (s *StakerHost) Shutdown_LEGACY() {
+	log.Printf("🛑 Shutting down staker host: %s", s.config.NodeID)
 
-실제로는 result["result"]["objectChanges"]에서 "created" 타입의 오브젝트를 찾아
-StakeRecord 타입의 오브젝트 ID를 추출합니다.
+	s.isRunning = false
 
-반환값:
-- string: 스테이킹 오브젝트 ID (0x로 시작하는 64자리 hex)
-*/
-func (s *StakerHost) extractStakeObjectID(result map[string]interface{}) (string, error) {
-	// 🔍 Sui 응답에서 새로 생성된 StakeRecord 객체 찾기
-	if resultData, exists := result["result"]; exists {
-		if resultMap, ok := resultData.(map[string]interface{}); ok {
-			if objectChanges, exists := resultMap["objectChanges"]; exists {
-				if changes, ok := objectChanges.([]interface{}); ok {
-					// 🔎 "created" 타입의 오브젝트 중 StakeRecord 찾기
-					for _, change := range changes {
-						if changeMap, ok := change.(map[string]interface{}); ok {
-							if changeType, exists := changeMap["type"]; exists && changeType == "created" {
-								if objectType, exists := changeMap["objectType"]; exists {
-									// 📎 StakeRecord 타입 확인
-									if strings.Contains(objectType.(string), "StakeRecord") {
-										if objectId, exists := changeMap["objectId"]; exists {
-											return objectId.(string), nil
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+	// 실행 중인 컨테이너들 정리
+	if s.k3sAgent != nil && s.k3sAgent.runtime != nil {
+		// 컨테이너 정리 로직
+		log.Printf("🧹 Cleaning up containers...")
+		containers, _ := s.k3sAgent.runtime.ListContainers()
+		for _, container := range containers {
+			log.Printf("🛑 컨테이너 중단: %s", container.Name)
+			s.k3sAgent.runtime.StopContainer(container.Name, s.config.Termination.defaultGrace())
 		}
 	}
 
-	return "", fmt.Errorf("스테이킹 객체 ID를 찾을 수 없습니다")
+	log.Printf("✅ Staker host shutdown complete")
+	os.Exit(0)
 }
 
 /*
-Seal 토큰 추출 함수
-Sui 트랜잭션 실행 결과에서 새로 생성된 Seal 토큰을 찾습니다.
+등록 트랜잭션 빌드 함수
+스테이킹과 Seal 토큰 생성을 하나의 트랜잭션(Sui에서는 Programmable Transaction Block)으로 구성합니다.
 
-매개변수:
-- result: Sui RPC sui_executeTransactionBlock의 응답
-
-실제로는 result["result"]["objectChanges"]에서 SealToken 타입의 오브젝트를 찾아
-토큰 해시 또는 오브젝트 ID를 추출합니다.
+구성:
+1. staking::stake_for_node(amount, node_id, role) → StakeRecord
+2. k8s_gateway::create_worker_seal_token(Result 0) → SealToken
 
-반환값:
-- string: Seal 토큰 (Nautilus TEE 인증에 사용)
+이후 등록 단계(예: capability 등록)도 같은 트랜잭션에 Add로 추가하면
+모든 단계가 한 번에 성공하거나 함께 롤백됩니다.
 */
-func (s *StakerHost) extractSealToken(result map[string]interface{}) (string, error) {
-	// 🔍 Sui 응답에서 새로 생성된 SealToken 객체 찾기
-	if resultData, exists := result["result"]; exists {
-		if resultMap, ok := resultData.(map[string]interface{}); ok {
-			if objectChanges, exists := resultMap["objectChanges"]; exists {
-				if changes, ok := objectChanges.([]interface{}); ok {
-					// 🔎 "created" 타입의 오브젝트 중 SealToken 찾기
-					for _, change := range changes {
-						if changeMap, ok := change.(map[string]interface{}); ok {
-							if changeType, exists := changeMap["type"]; exists && changeType == "created" {
-								if objectType, exists := changeMap["objectType"]; exists {
-									// 📎 SealToken 타입 확인
-									if strings.Contains(objectType.(string), "SealToken") {
-										if objectId, exists := changeMap["objectId"]; exists {
-											return objectId.(string), nil
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return "", fmt.Errorf("Seal 토큰을 찾을 수 없습니다")
+func (s *StakerHost) buildRegistrationTransaction() *chain.Tx {
+	// 📋 스테이킹 - 생성된 StakeRecord를 다음 호출에서 사용
+	tx := chain.NewTx("staking", "stake_for_node",
+		s.config.StakeAmount, // 스테이킹 양 (MIST 단위)
+		s.config.NodeID,      // 노드 ID
+		s.config.NodeRole,    // 노드 역할 (스테이킹 티어)
+	)
+
+	// 🔑 스테이킹 결과로 워커 노드용 Seal 토큰 생성
+	tx.Add("k8s_gateway", "create_worker_seal_token", chain.Result(0))
+
+	// 🏗️ 두 호출의 가스를 합친 예산 (10M + 5M MIST)
+	tx.GasBudget = 15000000
+	return tx
 }
 
 /*
@@ -1029,99 +1443,24 @@ Nautilus TEE의 실제 엔드포인트를 알 수 있게 합니다.
 - error: 조회 과정에서 발생한 오류
 */
 func (s *StakerHost) getNautilusInfoWithSeal() (*NautilusInfo, error) {
-	// 🔍 Sui 컨트랙트에서 Nautilus TEE 정보 조회 트랜잭션 구성
-	queryPayload := map[string]interface{}{
-		"jsonrpc": "2.0",                        // JSON-RPC 버전
-		"id":      1,                            // 요청 ID
-		"method":  "sui_executeTransactionBlock", // Sui 트랜잭션 실행
-		"params": []interface{}{
-			map[string]interface{}{
-				"txBytes": func() string {
-					txBytes, err := s.buildNautilusQueryTransaction()
-					if err != nil {
-						log.Printf("⚠️ Nautilus 조회 트랜잭션 빌드 실패: %v", err)
-						return ""
-					}
-					return txBytes
-				}(), // Nautilus 정보 조회 트랜잭션
-			},
-			[]string{s.config.SuiPrivateKey}, // 트랜잭션 서명용 개인키
-			map[string]interface{}{
-				"requestType": "WaitForLocalExecution", // 로컬 실행 대기
-				"options": map[string]bool{
-					"showEffects": true, // 실행 효과 표시
-					"showEvents":  true, // 이벤트 표시 (Nautilus 정보 포함)
-				},
-			},
-		},
-	}
+	// 🔍 k8s_gateway::get_nautilus_info_for_worker 호출 (컨트랙트가 Seal 토큰 검증)
+	tx := chain.NewTx("k8s_gateway", "get_nautilus_info_for_worker", s.stakingStatus.SealToken())
+	tx.GasBudget = 3000000 // 3M MIST 가스 한도
 
-	// 🌐 Sui 테스트넷에 조회 요청 전송
-	_, err := s.suiClient.client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(queryPayload).
-		Post(s.config.SuiRPCEndpoint)
-
-	if err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := s.suiClient.backend.SubmitTx(ctx, tx); err != nil {
 		return nil, fmt.Errorf("Nautilus 정보 조회 요청 실패: %v", err)
 	}
 
 	// 📄 응답에서 Nautilus TEE 정보 추출
-	// 🚧 실제 구현에서는 result["result"]["events"]에서 Nautilus 정보 파싱
+	// 🚧 실제 구현에서는 트랜잭션 이벤트에서 Nautilus 정보 파싱
 	return &NautilusInfo{
 		Endpoint: s.config.NautilusEndpoint, // 설정에서 가져온 엔드포인트 (테스트용)
 		PubKey:   "nautilus_pub_key",       // TEE 공개키 (테스트용)
 	}, nil
 }
 
-/*
-Nautilus TEE 정보 조회 트랜잭션 빌드 함수
-
-k8s_gateway 컨트랙트의 get_nautilus_info_for_worker 함수를 호출하는
-트랜잭션을 생성합니다. 이 함수는 Seal 토큰을 검증한 후
-Nautilus TEE의 실제 엔드포인트 정보를 반환합니다.
-
-반환값:
-- string: 직렬화된 트랜잭션 바이트 (Base64 인코딩)
-*/
-func (s *StakerHost) buildNautilusQueryTransaction() (string, error) {
-	// 🎯 k8s_gateway::get_nautilus_info_for_worker 호출
-	// Seal 토큰 검증 후 Nautilus 연결 정보 반환
-
-	// 📝 Move 함수 호출 데이터
-	moveCall := map[string]interface{}{
-		"packageObjectId": s.config.ContractAddress,        // k8s_gateway 컨트랙트 주소
-		"module":          "k8s_gateway",                   // 모듈명
-		"function":        "get_nautilus_info_for_worker", // Nautilus 정보 조회 함수
-		"typeArguments":   []string{},                       // 타입 인수 없음
-		"arguments": []interface{}{
-			s.stakingStatus.SealToken, // Seal 토큰 ID 전달
-		},
-	}
-
-	// 🏗️ 트랜잭션 블록 구성
-	txBlock := map[string]interface{}{
-		"version":    1,
-		"sender":     s.suiClient.address,
-		"gasPayment": nil,       // 자동으로 가스 코인 선택
-		"gasBudget":  "3000000", // 3M MIST 가스 한도
-		"gasPrice":   "1000",    // 가스 가격
-		"transactions": []interface{}{
-			map[string]interface{}{
-				"MoveCall": moveCall,
-			},
-		},
-	}
-
-	// 📤 JSON 직렬화 후 Base64 인코딩
-	txJSON, err := json.Marshal(txBlock)
-	if err != nil {
-		return "", fmt.Errorf("Nautilus 조회 트랜잭션 직렬화 실패: %v", err)
-	}
-
-	return base64.StdEncoding.EncodeToString(txJSON), nil
-}
-
 /*
 스테이킹 정보 구조체
 Sui 블록체인에서 조회한 스테이킹 객체의 핵심 정보를 담습니다.
@@ -1156,7 +1495,7 @@ func (k *Kubelet) Start() error {
 
 	// 기본 검증
 	if k.token == "" {
-		return fmt.Errorf("Seal 토큰이 설정되지 않았습니다")
+		return fmt.Errorf("조인 토큰이 설정되지 않았습니다 (Nautilus 등록 필요)")
 	}
 
 	// 데이터 디렉토리 생성
@@ -1164,16 +1503,10 @@ func (k *Kubelet) Start() error {
 		return fmt.Errorf("데이터 디렉토리 생성 실패: %v", err)
 	}
 
-	// K3s 바이너리 확인
-	k3sBinary := "k3s"
-	if _, err := exec.LookPath(k3sBinary); err != nil {
-		// Windows에서 k3s.exe 확인
-		k3sBinary = "k3s.exe"
-		if _, err := exec.LookPath(k3sBinary); err != nil {
-			log.Printf("⚠️ k3s 바이너리를 찾을 수 없습니다. 시뮬레이션 모드로 실행합니다.")
-			k.running = true
-			return nil
-		}
+	// K3s 바이너리 확인 (없으면 시뮬레이션으로 넘어가지 않고 실패 - 프리플라이트 k3s-binary 검사 참고)
+	k3sBinary, err := findK3sBinary()
+	if err != nil {
+		return fmt.Errorf("K3s Agent 시작 불가: %v", err)
 	}
 
 	// K3s agent 명령 구성
@@ -1184,6 +1517,9 @@ func (k *Kubelet) Start() error {
 		"--data-dir", k.dataDir,
 		"--node-name", k.nodeID,
 		"--kubelet-arg", "fail-swap-on=false",
+		/* 서빙 인증서도 CSR로 요청해 마스터 TEE CA에서 발급받음 (마스터 CSR API는 JSON만 지원) */
+		"--kubelet-arg", "rotate-server-certificates=true",
+		"--kubelet-arg", "kube-api-content-type=application/json",
 	}
 
 	log.Printf("🚀 K3s Agent 명령 실행: %s %s", k3sBinary, strings.Join(args, " "))
@@ -1283,15 +1619,23 @@ func (c *ContainerdRuntime) RunContainer(image, name string, env map[string]stri
 
 /*
 컨테이너 중단 함수 (containerd)
-실행 중인 컨테이너를 정상적으로 중단시킵니다.
+SIGTERM을 보내고 grace 동안 종료를 기다린 뒤, 남아 있으면 SIGKILL로 강제 종료합니다.
 */
-func (c *ContainerdRuntime) StopContainer(name string) error {
-	log.Printf("🛑 Containerd: 컨테이너 중단 중... %s", name)
+func (c *ContainerdRuntime) StopContainer(name string, grace time.Duration) error {
+	log.Printf("🛑 Containerd: 컨테이너 중단 중... %s (유예 %s)", name, grace)
 
-	// Kill task first
-	killCmd := exec.Command("ctr", "-n", c.namespace, "tasks", "kill", name)
+	// SIGTERM 후 종료 대기
+	killCmd := exec.Command("ctr", "-n", c.namespace, "tasks", "kill", "--signal", "SIGTERM", name)
 	if err := killCmd.Run(); err != nil {
-		log.Printf("Warning: failed to kill task: %v", err)
+		log.Printf("Warning: failed to signal task: %v", err)
+	}
+	if !c.waitTaskStopped(name, grace) {
+		log.Printf("⏱️ Containerd: 유예 시간 초과, SIGKILL %s", name)
+		forceCmd := exec.Command("ctr", "-n", c.namespace, "tasks", "kill", "--all", "--signal", "SIGKILL", name)
+		if err := forceCmd.Run(); err != nil {
+			log.Printf("Warning: failed to kill task: %v", err)
+		}
+		c.waitTaskStopped(name, 5*time.Second)
 	}
 
 	// Delete task
@@ -1310,6 +1654,31 @@ func (c *ContainerdRuntime) StopContainer(name string) error {
 	return nil
 }
 
+// waitTaskStopped - 태스크가 STOPPED(또는 사라짐)가 될 때까지 대기
+func (c *ContainerdRuntime) waitTaskStopped(name string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		output, err := exec.Command("ctr", "-n", c.namespace, "tasks", "list").Output()
+		if err != nil {
+			return false
+		}
+		running := false
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && fields[0] == name && fields[2] != "STOPPED" {
+				running = true
+			}
+		}
+		if !running {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
 /*
 컨테이너 목록 조회 함수 (containerd)
 현재 실행 중인 모든 컨테이너의 정보를 반환합니다.
@@ -1350,6 +1719,68 @@ func (c *ContainerdRuntime) ListContainers() ([]Container, error) {
 	return result, nil
 }
 
+/*
+이미지 목록 조회 함수 (containerd)
+같은 다이제스트를 가리키는 참조(태그, repo@sha256)를 하나로 묶고,
+컨테이너(종료된 것 포함)가 참조하는 이미지는 사용 중으로 표시합니다.
+*/
+func (c *ContainerdRuntime) ListImages() ([]ContainerImage, error) {
+	// REF TYPE DIGEST SIZE(숫자 단위) PLATFORMS LABELS
+	imagesOutput, err := exec.Command("ctr", "-n", c.namespace, "images", "ls").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	// CONTAINER IMAGE RUNTIME
+	containersOutput, err := exec.Command("ctr", "-n", c.namespace, "containers", "ls").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	usedRefs := make(map[string]bool)
+	for _, line := range strings.Split(string(containersOutput), "\n")[1:] {
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			usedRefs[fields[1]] = true
+		}
+	}
+
+	byDigest := make(map[string]*ContainerImage)
+	var order []string
+	for _, line := range strings.Split(string(imagesOutput), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		ref, digest := fields[0], fields[2]
+		image, ok := byDigest[digest]
+		if !ok {
+			image = &ContainerImage{ID: digest, SizeBytes: parseImageSize(fields[3] + fields[4])}
+			byDigest[digest] = image
+			order = append(order, digest)
+		}
+		image.Refs = append(image.Refs, ref)
+		image.InUse = image.InUse || usedRefs[ref]
+		image.Pinned = image.Pinned || strings.Contains(line, containerdPinnedLabel)
+	}
+
+	result := make([]ContainerImage, 0, len(order))
+	for _, digest := range order {
+		result = append(result, *byDigest[digest])
+	}
+	return result, nil
+}
+
+/*
+이미지 제거 함수 (containerd)
+참조가 모두 지워져야 containerd가 콘텐츠를 회수합니다.
+*/
+func (c *ContainerdRuntime) RemoveImage(image ContainerImage) error {
+	args := append([]string{"-n", c.namespace, "images", "rm"}, image.Refs...)
+	if output, err := exec.Command("ctr", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove image: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
 /*
 🐋 Docker 런타임 구현
 Docker는 가장 널리 사용되는 컨테이너 런타임입니다.
@@ -1407,13 +1838,13 @@ func (d *DockerRuntime) RunContainer(image, name string, env map[string]string)
 
 /*
 컨테이너 중단 함수 (Docker)
-Docker 컨테이너를 정상적으로 중단시킵니다.
+docker stop -t로 SIGTERM 후 grace가 지나면 Docker가 SIGKILL을 보냅니다.
 */
-func (d *DockerRuntime) StopContainer(name string) error {
-	log.Printf("🛑 Docker: 컨테이너 중단 중... %s", name)
+func (d *DockerRuntime) StopContainer(name string, grace time.Duration) error {
+	log.Printf("🛑 Docker: 컨테이너 중단 중... %s (유예 %s)", name, grace)
 
 	// Stop container
-	stopCmd := exec.Command("docker", "stop", name)
+	stopCmd := exec.Command("docker", "stop", "-t", strconv.Itoa(int(grace/time.Second)), name)
 	if err := stopCmd.Run(); err != nil {
 		log.Printf("Warning: failed to stop container: %v", err)
 	}
@@ -1462,6 +1893,73 @@ func (d *DockerRuntime) ListContainers() ([]Container, error) {
 	return result, nil
 }
 
+/*
+이미지 목록 조회 함수 (Docker)
+멈춘 컨테이너가 참조하는 이미지도 사용 중으로 표시합니다.
+*/
+func (d *DockerRuntime) ListImages() ([]ContainerImage, error) {
+	imagesOutput, err := exec.Command("docker", "images", "--no-trunc", "--format", "{{.ID}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	// 컨테이너의 이미지 ID (태그가 바뀌어도 정확히 일치)
+	containersOutput, err := exec.Command("docker", "ps", "-a", "--no-trunc", "--format", "{{.ID}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	usedIDs := make(map[string]bool)
+	if ids := strings.Fields(string(containersOutput)); len(ids) > 0 {
+		args := append([]string{"inspect", "--format", "{{.Image}}"}, ids...)
+		output, err := exec.Command("docker", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect containers: %w", err)
+		}
+		for _, id := range strings.Fields(string(output)) {
+			usedIDs[id] = true
+		}
+	}
+
+	byID := make(map[string]*ContainerImage)
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(string(imagesOutput)), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 3 {
+			continue
+		}
+		image, ok := byID[parts[0]]
+		if !ok {
+			image = &ContainerImage{ID: parts[0], SizeBytes: parseImageSize(parts[2]), InUse: usedIDs[parts[0]]}
+			byID[parts[0]] = image
+			order = append(order, parts[0])
+		}
+		if parts[1] != "<none>:<none>" {
+			image.Refs = append(image.Refs, parts[1])
+		}
+	}
+
+	result := make([]ContainerImage, 0, len(order))
+	for _, id := range order {
+		result = append(result, *byID[id])
+	}
+	return result, nil
+}
+
+/*
+이미지 제거 함수 (Docker)
+태그가 여럿이면 태그를 모두 지정해야 하고, 태그가 없는 이미지는 ID로 지웁니다.
+*/
+func (d *DockerRuntime) RemoveImage(image ContainerImage) error {
+	targets := image.Refs
+	if len(targets) == 0 {
+		targets = []string{image.ID}
+	}
+	args := append([]string{"rmi"}, targets...)
+	if output, err := exec.Command("docker", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove image: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
 // ==================== 누락된 함수들 추가 ====================
 
 /*
@@ -1470,6 +1968,18 @@ func (d *DockerRuntime) ListContainers() ([]Container, error) {
 하트비트 정보에 포함되어 Nautilus TEE가 노드의 작업 부하를 파악하는 데 사용됩니다.
 */
 func (s *StakerHost) getRunningPodsCount() int {
+	// staking 모드에서는 런타임 에이전트가 CRI를 조회
+	if s.runtimeClient != nil {
+		count, err := s.runtimeClient.ReadyPods()
+		if err != nil {
+			log.Printf("⚠️ 런타임 에이전트 Pod 수 조회 실패: %v", err)
+		}
+		return count
+	}
+	// 마스터가 배정 기록(Running Pod 수)과 비교하므로 컨테이너가 아닌 Ready Pod 샌드박스 수를 보고
+	if count, err := countReadyPods(); err == nil {
+		return count
+	}
 	if s.k3sAgent == nil || s.k3sAgent.runtime == nil {
 		return 0
 	}
@@ -1484,11 +1994,17 @@ func (s *StakerHost) getRunningPodsCount() int {
 func (s *StakerHost) getResourceUsage() map[string]interface{} {
 	// 🚧 TODO: 실제 구현에서는 시스템 메트릭 수집 라이브러리 사용
 	// 예시: gopsutil 패키지로 CPU/메모리/디스크 사용량 실시간 조회
-	return map[string]interface{}{
+	usage := map[string]interface{}{
 		"cpu_percent":    45.2, // CPU 사용률 (%)
 		"memory_percent": 67.8, // 메모리 사용률 (%)
 		"disk_percent":   23.1, // 디스크 사용률 (%)
 	}
+	// 메모리/디스크는 압박 감지에서 측정한 실제 값 사용
+	if stats, _ := s.pressure.snapshot(); stats != nil {
+		usage["memory_percent"] = stats.memoryPercent()
+		usage["disk_percent"] = stats.diskPercent()
+	}
+	return usage
 }
 
 // getMemoryUsage returns current memory usage metrics
@@ -1539,57 +2055,13 @@ func (s *StakerHost) getNetworkStats() map[string]interface{} {
 func (s *StakerHost) unstakeFromSui() error {
 	log.Printf("🔄 Sui 블록체인에서 스테이킹 해제 중...")
 
-	// Sui 트랜잭션 구성 (실제 구현에서는 Sui SDK 사용)
-	unstakePayload := map[string]interface{}{
-		"function": "unstake",
-		"arguments": []interface{}{
-			s.config.SuiWalletAddress, // 스테이커 주소
-			s.config.NodeID,           // 노드 ID
-		},
-		"type_arguments": []string{},
-	}
-
-	// Sui RPC를 통한 트랜잭션 실행
-	resp, err := resty.New().R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"id":      1,
-			"method":  "sui_executeTransactionBlock",
-			"params": []interface{}{
-				unstakePayload,
-				[]string{s.config.SuiPrivateKey}, // 서명을 위한 개인키 (실제로는 안전하게 관리)
-				map[string]interface{}{
-					"showInput":          true,
-					"showRawInput":       false,
-					"showEffects":        true,
-					"showEvents":         true,
-					"showObjectChanges":  true,
-					"showBalanceChanges": true,
-				},
-			},
-		}).
-		Post(s.config.SuiRPCEndpoint)
-
-	if err != nil {
+	// 📤 staking::unstake(스테이커 주소, 노드 ID) - 스테이킹 객체가 삭제됨
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if _, err := s.suiClient.backend.SubmitTx(ctx, chain.NewTx("staking", "unstake", s.config.SuiWalletAddress, s.config.NodeID)); err != nil {
 		return fmt.Errorf("unstaking transaction failed: %v", err)
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("Sui RPC error: %d %s", resp.StatusCode(), resp.String())
-	}
-
-	// 트랜잭션 결과 파싱
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
-		return fmt.Errorf("failed to parse unstaking response: %v", err)
-	}
-
-	// 오류 확인
-	if errorInfo, exists := result["error"]; exists {
-		return fmt.Errorf("unstaking failed: %v", errorInfo)
-	}
-
 	log.Printf("✅ 스테이킹 해제 완료")
 	return nil
 }
@@ -1597,57 +2069,43 @@ func (s *StakerHost) unstakeFromSui() error {
 /*
 🔍 Sui 블록체인에서 스테이킹 상태 조회 함수 - 실제 구현
 하트비트 과정에서 호출되는 함수로, 현재 노드의 스테이킹 상태를
-Sui 블록체인에서 직접 조회합니다.
+체인 백엔드(기본 Sui 블록체인)에서 직접 조회합니다.
 */
 func (s *StakerHost) checkStakeOnSui() (*StakeInfo, error) {
-	// 📡 Sui RPC sui_getObject 호출로 스테이킹 객체 조회
-	queryPayload := map[string]interface{}{
-		"jsonrpc": "2.0",         // JSON-RPC 버전
-		"id":      1,             // 요청 ID
-		"method":  "sui_getObject", // Sui 객체 조회 메소드
-		"params": []interface{}{
-			s.stakingStatus.StakeObjectID, // 조회할 스테이킹 객체 ID
-			map[string]interface{}{
-				"showContent": true, // 객체 내용 포함 (상태와 양 확인용)
-			},
-		},
-	}
-
-	// 🌐 Sui 테스트넷에 조회 요청 전송
-	resp, err := s.suiClient.client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(queryPayload).
-		Post(s.suiClient.rpcEndpoint)
-
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// 📡 체인 백엔드로 스테이킹 객체 조회 (슬래싱 감지를 위해 캐시 없이 직접 조회)
+	// 최소량 검사는 티어 검증에서 하므로 여기서는 0으로 조회해 상태만 판단
+	stake, err := s.suiClient.backend.ValidateStake(ctx, chain.StakeRef{
+		NodeID:   s.config.NodeID,
+		ObjectID: s.stakingStatus.StakeObjectID(),
+	}, 0)
+	switch {
+	case errors.Is(err, chain.ErrDeleted):
+		return nil, errStakeObjectGone
+	case errors.Is(err, chain.ErrInsufficientStake):
+		// 슬래싱/인출 상태도 그대로 반환 - 종료 여부는 스테이킹 감시가 판단
+	case err != nil:
+		return nil, fmt.Errorf("스테이킹 상태 조회 실패: %v", err)
+	}
+	stakeInfo := &StakeInfo{Amount: stake.Amount, Status: stake.Status}
+	version := stake.Version
+
+	// 🔢 객체 버전은 단조 증가해야 함 - 역행은 뒤처진/악의적 RPC 노드 응답으로 간주
+	// (버전 0은 구형 응답에 버전이 없는 경우로, 비교하지 않음)
+	if version == 0 {
+		return stakeInfo, nil
+	}
+	changed, err := s.stakingStatus.advanceObjectVersion(version)
 	if err != nil {
-		return nil, fmt.Errorf("Sui 스테이킹 상태 조회 요청 실패: %v", err)
-	}
-
-	// 📄 JSON 응답 파싱
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
-		return nil, fmt.Errorf("Sui 응답 파싱 실패: %v", err)
-	}
-
-	// 🔍 StakeRecord 객체의 content 필드에서 실제 데이터 추출
-	if resultData, exists := result["result"]; exists {
-		if resultMap, ok := resultData.(map[string]interface{}); ok {
-			if data, exists := resultMap["data"]; exists {
-				if dataMap, ok := data.(map[string]interface{}); ok {
-					if content, exists := dataMap["content"]; exists {
-						if contentMap, ok := content.(map[string]interface{}); ok {
-							return &StakeInfo{
-								Amount: uint64(contentMap["stake_amount"].(float64)), // 스테이킹된 SUI 양
-								Status: contentMap["status"].(string),               // 스테이킹 상태
-							}, nil
-						}
-					}
-				}
-			}
-		}
+		return nil, err
+	}
+	if changed {
+		s.saveStakingState()
 	}
 
-	return nil, fmt.Errorf("스테이킹 상태 파싱 실패")
+	return stakeInfo, nil
 }
 
 /*
@@ -1670,13 +2128,19 @@ func (s *StakerHost) Shutdown() {
 		log.Printf("✅ K3s Agent 종료 완료")
 	}
 
-	// 3️⃣ 실행 중인 모든 컨테이너 정리
-	if s.k3sAgent != nil && s.k3sAgent.runtime != nil {
-		log.Printf("🐳 실행 중인 컨테이너들 정리 중...")
-		containers, _ := s.k3sAgent.runtime.ListContainers()
-		for _, container := range containers {
-			log.Printf("🛑 컨테이너 중단: %s", container.Name)
-			s.k3sAgent.runtime.StopContainer(container.Name)
+	// 3️⃣ 실행 중인 모든 컨테이너 정리 (staking 모드는 런타임 에이전트에 요청)
+	if s.runtimeClient != nil {
+		if err := s.runtimeClient.StopAgent(); err != nil {
+			log.Printf("⚠️ 런타임 에이전트 정리 요청 실패: %v", err)
+		}
+	} else {
+		s.stopContainers()
+	}
+
+	// 4️⃣ 온체인 상태를 offline으로 변경 (스폰서 가스 사용 시)
+	if s.config.GasSponsorship && s.stakingStatus.SealToken() != "" {
+		if _, err := s.executeSponsoredCall("worker_registry", "change_worker_status", "offline"); err != nil {
+			log.Printf("⚠️ 온체인 상태 변경 실패: %v", err)
 		}
 	}
 
@@ -1685,6 +2149,33 @@ func (s *StakerHost) Shutdown() {
 	os.Exit(0)
 }
 
+// stopContainers - 로컬 컨테이너 런타임의 실행 중인 컨테이너 정리
+func (s *StakerHost) stopContainers() {
+	if s.k3sAgent == nil || s.k3sAgent.runtime == nil {
+		return
+	}
+	log.Printf("🐳 실행 중인 컨테이너들 정리 중...")
+
+	// Pod 단위로 preStop 훅과 유예 시간을 지켜 종료 (CRI를 쓸 수 없으면 런타임에서 직접 중단)
+	if err := terminateAllPods(s.config.Termination); err == nil {
+		return
+	} else {
+		log.Printf("⚠️ CRI Pod 종료 불가, 런타임에서 직접 중단: %v", err)
+	}
+
+	containers, _ := s.k3sAgent.runtime.ListContainers()
+	var wg sync.WaitGroup
+	for _, container := range containers {
+		log.Printf("🛑 컨테이너 중단: %s", container.Name)
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			s.k3sAgent.runtime.StopContainer(name, s.config.Termination.defaultGrace())
+		}(container.Name)
+	}
+	wg.Wait()
+}
+
 /*
 ⚙️ 설정 파일 로드 함수 - 실제 구현
 staker-config.json 파일을 읽어서 StakerHostConfig 구조체로 파싱합니다.
@@ -1708,5 +2199,131 @@ func loadConfig(path string) (*StakerHostConfig, error) {
 		config.MinStakeAmount = 1000 // 1000 MIST
 	}
 
+	// 🌍 위치 정보는 환경변수로 덮어쓸 수 있음
+	if region := os.Getenv("K3S_DAAS_REGION"); region != "" {
+		config.Region = region
+	}
+	if zone := os.Getenv("K3S_DAAS_ZONE"); zone != "" {
+		config.Zone = zone
+	}
+
+	// ⏱️ 시계 오차 허용치 (기본 30초)
+	if config.MaxClockSkewSeconds <= 0 {
+		config.MaxClockSkewSeconds = defaultMaxClockSkew
+	}
+
+	// 🏷️ 노드 역할 및 스테이킹 티어
+	if role := os.Getenv("K3S_DAAS_NODE_ROLE"); role != "" {
+		config.NodeRole = role
+	}
+	if err := applyStakeTierDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🛡️ TEE 증명 교차 검증 설정
+	if err := applyPeerAttestationDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// ♻️ 재시작 시 이전 실행 컨테이너 정리 설정
+	if err := applyOrphanDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🧹 이미지 GC (워터마크 환경변수는 자원 예약 검증 전에 반영)
+	if err := applyImageGCDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🌐 예비 엔드포인트와 DNS 캐시
+	if err := applyEndpointDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 📶 대역폭/지연 측정
+	if err := applyNetworkProbeDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🔑 secrets 볼륨 (Vault, 온체인 Sealed Secret)
+	if err := applySecretsDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🧱 상태 포트 접근 제한
+	if err := applyStatusFirewallDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🛑 Pod 종료 유예 시간
+	if err := applyTerminationDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 💽 kubelet 자원 예약 및 축출 임계값
+	if err := applyResourceReservationDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🩺 커널 로그 기반 노드 문제 감지
+	if err := applyNodeProblemDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// ⛽ 지갑 잔액 감시 및 faucet
+	if err := applyWalletMonitorDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🧬 호스트 지문 (컴플라이언스 보고용 sysctl 목록)
+	if err := applyHostFingerprintDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🧾 마스터 빌드 출처 확인 (마스터 공개키 설정 이후)
+	if err := applyMasterBuildDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// ⛽ 스폰서 가스 설정
+	if os.Getenv("K3S_DAAS_GAS_SPONSORSHIP") == "true" {
+		config.GasSponsorship = true
+	}
+	if config.OnChainHeartbeatEvery <= 0 {
+		config.OnChainHeartbeatEvery = defaultOnChainHeartbeatEvery
+	}
+
+	// 🚨 슬래싱 확정에 필요한 연속 조회 횟수 (일시적 RPC 오응답으로 종료 방지)
+	if config.SlashConfirmations <= 0 {
+		config.SlashConfirmations = defaultSlashConfirmations
+	}
+
+	// ⚖️ 슬래싱 이의 신청 (증거 저장 경로, 온체인 자동 제출 여부)
+	applyAppealDefaults(&config)
+
+	// 🚩 기능 게이트 (auto_appeal은 AutoAppeal 게이트로 반영)
+	if err := applyFeatureGateDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 🧪 프리플라이트 (무시할 검사, 최소 k3s 버전)
+	if err := applyPreflightDefaults(&config); err != nil {
+		return nil, err
+	}
+
+	// 📜 Pod 로그 보관 (보관 기간, 배송 대상)
+	if err := applyPodLogDefaults(&config); err != nil {
+		return nil, err
+	}
+	applyStakingStateDefaults(&config)
+
+	// 🧩 마스터 설정 번들 저장 경로
+	applyConfigSyncDefaults(&config)
+
+	// 🌐 상태 서버 주소 (TLS 등은 K3S_DAAS_TLS_* 환경변수)
+	if config.ListenAddr == "" {
+		config.ListenAddr = defaultListenAddr
+	}
+
 	return &config, nil
 }
\ No newline at end of file
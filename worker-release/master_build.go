@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+	"github.com/k3s-io/daas-sui"
+	version "github.com/k3s-io/daas-version"
+)
+
+// 마스터 빌드 출처 재확인 주기 (마스터 업그레이드나 온체인 허용 취소 반영)
+const masterBuildCheckInterval = time.Hour
+
+/*
+applyMasterBuildDefaults - 마스터 빌드 출처 확인 설정 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_BUILD_ALLOWLIST_ID: 온체인 build_allowlist::BuildAllowlist 객체 ID
+- K3S_DAAS_MASTER_BUILD_POLICY: off, warn(허용 목록이 있으면 기본), refuse(허용되지 않은 마스터에 등록하지 않음)
+applyPeerAttestationDefaults 다음에 호출해야 환경변수로 지정한 master_public_key가 반영됩니다.
+*/
+func applyMasterBuildDefaults(config *StakerHostConfig) error {
+	if id := os.Getenv("K3S_DAAS_BUILD_ALLOWLIST_ID"); id != "" {
+		config.BuildAllowlistID = id
+	}
+	if policy := os.Getenv("K3S_DAAS_MASTER_BUILD_POLICY"); policy != "" {
+		config.MasterBuildPolicy = strings.ToLower(policy)
+	}
+	if config.MasterBuildPolicy == "" {
+		config.MasterBuildPolicy = "off"
+		if config.BuildAllowlistID != "" {
+			config.MasterBuildPolicy = "warn"
+		}
+	}
+
+	switch config.MasterBuildPolicy {
+	case "off":
+		return nil
+	case "warn", "refuse":
+	default:
+		return fmt.Errorf("잘못된 master_build_policy: %s (off, warn, refuse)", config.MasterBuildPolicy)
+	}
+	if config.BuildAllowlistID == "" {
+		return fmt.Errorf("master_build_policy=%s에는 build_allowlist_id가 필요합니다", config.MasterBuildPolicy)
+	}
+	if config.MasterPublicKey == "" {
+		return fmt.Errorf("master_build_policy=%s에는 master_public_key가 필요합니다", config.MasterBuildPolicy)
+	}
+	return nil
+}
+
+// masterBuildCheck - 마지막 마스터 빌드 확인 결과
+type masterBuildCheck struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	build     *version.Provenance // 서명이 확인된 마스터 빌드 (조회/서명 실패면 nil)
+	allowed   *sui.AllowedBuild   // 온체인 허용 기록 (허용되지 않았으면 nil)
+	err       error
+}
+
+func newMasterBuildCheck(config *StakerHostConfig) *masterBuildCheck {
+	if config.MasterBuildPolicy == "off" {
+		return nil
+	}
+	return &masterBuildCheck{}
+}
+
+// snapshot - /health 응답용 요약 (비활성화면 nil)
+func (c *masterBuildCheck) snapshot() map[string]interface{} {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checkedAt.IsZero() {
+		return map[string]interface{}{"trusted": false}
+	}
+
+	snapshot := map[string]interface{}{
+		"trusted":    c.err == nil,
+		"checked_at": c.checkedAt.Unix(),
+	}
+	if c.build != nil {
+		snapshot["version"] = c.build.Version
+		snapshot["commit"] = c.build.Commit
+		snapshot["builder"] = c.build.Builder
+		snapshot["sbom_digest"] = c.build.SBOMDigest
+	}
+	if c.allowed != nil {
+		snapshot["allowed_at"] = c.allowed.AllowedAt.Unix()
+	}
+	if c.err != nil {
+		snapshot["error"] = c.err.Error()
+	}
+	return snapshot
+}
+
+/*
+checkMasterBuild - 마스터 등록 전 빌드 출처 확인
+refuse 정책에서는 확인에 실패하면 오류를 반환해 등록을 중단하고, warn 정책에서는 로그만 남깁니다.
+*/
+func (s *StakerHost) checkMasterBuild() error {
+	if s.masterBuild == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := s.recordMasterBuild(ctx)
+	if err != nil && s.config.MasterBuildPolicy == "refuse" {
+		return fmt.Errorf("허용되지 않은 마스터 빌드: %v", err)
+	}
+	return nil
+}
+
+// runMasterBuildCheck - 마스터 빌드 주기적 재확인 (등록 후에는 거부할 수 없으므로 결과 변화만 로그)
+func (s *StakerHost) runMasterBuildCheck(ctx context.Context) {
+	if s.masterBuild == nil {
+		return
+	}
+	ticker := time.NewTicker(masterBuildCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		s.recordMasterBuild(checkCtx)
+		cancel()
+	}
+}
+
+// recordMasterBuild - 확인 결과 저장 및 결과가 바뀌었을 때 로그
+func (s *StakerHost) recordMasterBuild(ctx context.Context) error {
+	build, allowed, err := s.verifyMasterBuild(ctx)
+
+	c := s.masterBuild
+	c.mu.Lock()
+	changed := c.checkedAt.IsZero() || (c.err == nil) != (err == nil) ||
+		(build != nil && (c.build == nil || c.build.SBOMDigest != build.SBOMDigest))
+	c.checkedAt, c.build, c.allowed, c.err = time.Now(), build, allowed, err
+	c.mu.Unlock()
+
+	if changed {
+		if err != nil {
+			log.Printf("🚨 마스터 빌드 확인 실패: %v", err)
+		} else {
+			log.Printf("🧾 마스터 빌드 확인: %s (commit %s, builder %s, %s)",
+				build.Version, build.Commit, build.Builder, build.SBOMDigest)
+		}
+	}
+	return err
+}
+
+/*
+verifyMasterBuild - 마스터의 서명된 빌드 출처를 조회해 온체인 허용 목록과 대조
+nonce 일치, 발급 시각, 고정 공개키 서명을 확인한 뒤 SBOM 다이제스트로 허용 기록을 찾아
+커밋과 빌더가 같은지 확인합니다. 서명이 확인되면 실패한 경우에도 빌드 정보를 함께 반환합니다.
+*/
+func (s *StakerHost) verifyMasterBuild(ctx context.Context) (*version.Provenance, *sui.AllowedBuild, error) {
+	publicKey, err := hex.DecodeString(s.config.MasterPublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, nil, fmt.Errorf("설정된 master_public_key가 올바르지 않습니다")
+	}
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, nil, fmt.Errorf("nonce 생성 실패: %v", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+
+	endpoint := strings.TrimSuffix(s.config.NautilusEndpoint, "/") + "/api/v1/version?nonce=" + url.QueryEscape(nonce)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("빌드 출처 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("빌드 출처 조회 실패 (HTTP %d)", resp.StatusCode)
+	}
+
+	var body struct {
+		Data version.Provenance `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("빌드 출처 파싱 실패: %v", err)
+	}
+	build := &body.Data
+
+	if build.Nonce != nonce {
+		return nil, nil, fmt.Errorf("nonce 불일치 (재전송 의심)")
+	}
+	if age := time.Since(time.Unix(build.IssuedAt, 0)); age > attestationMaxAge || age < -attestationMaxAge {
+		return nil, nil, fmt.Errorf("발급 시각 오차 %s", age.Round(time.Second))
+	}
+	// 문서에 포함된 공개키가 아닌 고정된 공개키로 검증
+	if err := build.Verify(ed25519.PublicKey(publicKey)); err != nil {
+		return nil, nil, err
+	}
+
+	if build.SBOMDigest == "" {
+		return build, nil, fmt.Errorf("출처가 기록되지 않은 빌드 (%s)", build.Version)
+	}
+	if s.suiClient.backend.Name() == chain.MockBackendName {
+		return build, nil, fmt.Errorf("mock 체인에는 빌드 허용 목록이 없습니다")
+	}
+	allowed, err := s.suiClient.chain.AllowedBuild(ctx, s.config.BuildAllowlistID, build.SBOMDigest)
+	if err != nil {
+		return build, nil, err
+	}
+	switch {
+	case allowed == nil:
+		return build, nil, fmt.Errorf("허용 목록에 없는 빌드 %s", build.SBOMDigest)
+	case allowed.Component != "" && allowed.Component != build.Component:
+		return build, allowed, fmt.Errorf("%s 빌드로 허용된 SBOM입니다 (보고: %s)", allowed.Component, build.Component)
+	case allowed.Commit != build.Commit:
+		return build, allowed, fmt.Errorf("커밋 불일치 (허용 %s, 보고 %s)", allowed.Commit, build.Commit)
+	case allowed.Builder != "" && allowed.Builder != build.Builder:
+		return build, allowed, fmt.Errorf("빌더 불일치 (허용 %s, 보고 %s)", allowed.Builder, build.Builder)
+	}
+	return build, allowed, nil
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 노드 자원 예약 및 압박 감지 기본값
+const (
+	defaultSystemReserved    = "cpu=500m,memory=512Mi,ephemeral-storage=2Gi"
+	defaultKubeReserved      = "cpu=250m,memory=256Mi,ephemeral-storage=1Gi"
+	defaultEvictionHard      = "memory.available<300Mi,nodefs.available<10%,imagefs.available<15%,nodefs.inodesFree<5%"
+	defaultEvictionSoft      = "memory.available<500Mi,nodefs.available<15%"
+	defaultEvictionSoftGrace = "memory.available=1m30s,nodefs.available=1m30s"
+	defaultImageGCHigh       = 85
+	defaultImageGCLow        = 70
+
+	pressureCheckInterval = 30 * time.Second
+	pressureStatsPath     = "/var/lib/k3s-daas-agent"
+)
+
+/*
+ResourceReservation - kubelet 자원 예약 및 축출 설정
+
+system/kube 예약분은 테넌트 Pod이 할당받을 수 없어 노드 프로세스(K3s, 스테이커 호스트)가 굶지 않습니다.
+축출 임계값을 넘으면 kubelet이 DiskPressure/MemoryPressure 조건을 마스터에 보고하고
+QoS 순서대로 (BestEffort 먼저) Pod을 축출합니다. 이미지 GC 임계값은 워커의 LRU 이미지 GC(image_gc.go)가 사용합니다.
+값 형식은 kubelet 플래그와 같습니다.
+*/
+type ResourceReservation struct {
+	SystemReserved     string `json:"system_reserved"`
+	KubeReserved       string `json:"kube_reserved"`
+	EvictionHard       string `json:"eviction_hard"`
+	EvictionSoft       string `json:"eviction_soft"`
+	EvictionSoftGrace  string `json:"eviction_soft_grace_period"`
+	ImageGCHighPercent int    `json:"image_gc_high_percent"`
+	ImageGCLowPercent  int    `json:"image_gc_low_percent"`
+}
+
+/*
+applyResourceReservationDefaults - 자원 예약 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_SYSTEM_RESERVED, K3S_DAAS_KUBE_RESERVED: 예: cpu=500m,memory=512Mi,ephemeral-storage=2Gi
+- K3S_DAAS_EVICTION_HARD, K3S_DAAS_EVICTION_SOFT: 예: memory.available<300Mi,nodefs.available<10%
+- K3S_DAAS_EVICTION_GRACE: soft 임계값 유예 시간, 예: memory.available=1m30s,nodefs.available=1m30s
+*/
+func applyResourceReservationDefaults(config *StakerHostConfig) error {
+	r := &config.Resources
+	for env, field := range map[string]*string{
+		"K3S_DAAS_SYSTEM_RESERVED": &r.SystemReserved,
+		"K3S_DAAS_KUBE_RESERVED":   &r.KubeReserved,
+		"K3S_DAAS_EVICTION_HARD":   &r.EvictionHard,
+		"K3S_DAAS_EVICTION_SOFT":   &r.EvictionSoft,
+		"K3S_DAAS_EVICTION_GRACE":  &r.EvictionSoftGrace,
+	} {
+		if value := os.Getenv(env); value != "" {
+			*field = value
+		}
+	}
+
+	if r.SystemReserved == "" {
+		r.SystemReserved = defaultSystemReserved
+	}
+	if r.KubeReserved == "" {
+		r.KubeReserved = defaultKubeReserved
+	}
+	if r.EvictionHard == "" {
+		r.EvictionHard = defaultEvictionHard
+	}
+	if r.EvictionSoft == "" {
+		r.EvictionSoft = defaultEvictionSoft
+		r.EvictionSoftGrace = defaultEvictionSoftGrace
+	}
+	if r.ImageGCHighPercent <= 0 {
+		r.ImageGCHighPercent = defaultImageGCHigh
+	}
+	if r.ImageGCLowPercent <= 0 {
+		r.ImageGCLowPercent = defaultImageGCLow
+	}
+
+	if r.ImageGCLowPercent >= r.ImageGCHighPercent || r.ImageGCHighPercent > 100 {
+		return fmt.Errorf("잘못된 이미지 GC 임계값: low %d%%, high %d%%", r.ImageGCLowPercent, r.ImageGCHighPercent)
+	}
+	if _, err := parseEvictionThresholds(r.EvictionHard); err != nil {
+		return fmt.Errorf("잘못된 eviction_hard: %v", err)
+	}
+	if _, err := parseEvictionThresholds(r.EvictionSoft); err != nil {
+		return fmt.Errorf("잘못된 eviction_soft: %v", err)
+	}
+	if r.EvictionSoftGrace == "" {
+		return fmt.Errorf("eviction_soft에는 eviction_soft_grace_period가 필요합니다")
+	}
+	return nil
+}
+
+// kubeletArgs - 예약/축출/이미지 GC kubelet 플래그
+func (r ResourceReservation) kubeletArgs() []string {
+	return []string{
+		"--system-reserved=" + r.SystemReserved,
+		"--kube-reserved=" + r.KubeReserved,
+		"--enforce-node-allocatable=pods",
+		"--eviction-hard=" + r.EvictionHard,
+		"--eviction-soft=" + r.EvictionSoft,
+		"--eviction-soft-grace-period=" + r.EvictionSoftGrace,
+		"--eviction-minimum-reclaim=nodefs.available=1Gi,imagefs.available=1Gi",
+		// 이미지 GC는 워커가 고정 이미지 목록과 함께 수행하므로 kubelet 자체 GC는 끔 (100%)
+		"--image-gc-high-threshold=100",
+		fmt.Sprintf("--image-gc-low-threshold=%d", r.ImageGCLowPercent),
+	}
+}
+
+// evictionThreshold - "signal<quantity" 하나 (quantity는 바이트 또는 비율)
+type evictionThreshold struct {
+	signal  string
+	bytes   uint64
+	percent float64
+}
+
+// parseEvictionThresholds - kubelet 축출 임계값 문자열 파싱
+func parseEvictionThresholds(value string) ([]evictionThreshold, error) {
+	var thresholds []evictionThreshold
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "<", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q: signal<quantity 형식이어야 합니다", entry)
+		}
+
+		threshold := evictionThreshold{signal: parts[0]}
+		if strings.HasSuffix(parts[1], "%") {
+			percent, err := strconv.ParseFloat(strings.TrimSuffix(parts[1], "%"), 64)
+			if err != nil || percent <= 0 || percent >= 100 {
+				return nil, fmt.Errorf("%q: 잘못된 비율", entry)
+			}
+			threshold.percent = percent
+		} else {
+			bytes, err := parseByteQuantity(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", entry, err)
+			}
+			threshold.bytes = bytes
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// parseByteQuantity - K8s 이진 단위 수량 (예: 300Mi, 2Gi, 1048576)
+func parseByteQuantity(value string) (uint64, error) {
+	units := []struct {
+		suffix string
+		scale  uint64
+	}{{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}}
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			n, err := strconv.ParseUint(strings.TrimSuffix(value, unit.suffix), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("잘못된 수량 %q", value)
+			}
+			return n * unit.scale, nil
+		}
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 수량 %q", value)
+	}
+	return n, nil
+}
+
+// nodeStats - 노드 디스크/메모리 측정값
+type nodeStats struct {
+	DiskTotal       uint64
+	DiskAvailable   uint64
+	InodesTotal     uint64
+	InodesFree      uint64
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+}
+
+func (n nodeStats) diskPercent() float64 {
+	if n.DiskTotal == 0 {
+		return 0
+	}
+	return 100 * float64(n.DiskTotal-n.DiskAvailable) / float64(n.DiskTotal)
+}
+
+func (n nodeStats) memoryPercent() float64 {
+	if n.MemoryTotal == 0 {
+		return 0
+	}
+	return 100 * float64(n.MemoryTotal-n.MemoryAvailable) / float64(n.MemoryTotal)
+}
+
+// below - 임계값 신호에 해당하는 가용량이 기준보다 낮은지
+func (n nodeStats) below(t evictionThreshold) bool {
+	var available, total uint64
+	switch t.signal {
+	case "memory.available":
+		available, total = n.MemoryAvailable, n.MemoryTotal
+	case "nodefs.available", "imagefs.available":
+		available, total = n.DiskAvailable, n.DiskTotal
+	case "nodefs.inodesFree", "imagefs.inodesFree":
+		available, total = n.InodesFree, n.InodesTotal
+	default:
+		return false
+	}
+	if total == 0 {
+		return false
+	}
+	if t.percent > 0 {
+		return 100*float64(available)/float64(total) < t.percent
+	}
+	return available < t.bytes
+}
+
+// NodeCondition - 마스터에 보고하는 노드 조건 (kubelet 조건과 같은 이름)
+type NodeCondition struct {
+	Type    string `json:"type"` // DiskPressure, MemoryPressure, KernelDeadlock, OOMKilling
+	Status  bool   `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+/*
+pressureMonitor - 디스크/메모리 압박 감지
+
+kubelet 축출은 soft/hard 임계값에서 동작하므로, 그보다 먼저 (이미지 GC 상한 도달 시)
+사용하지 않는 이미지와 종료된 컨테이너를 정리해 축출까지 가지 않도록 합니다.
+감지한 조건은 하트비트의 node_conditions로 마스터에 보고합니다.
+*/
+type pressureMonitor struct {
+	mu         sync.Mutex
+	stats      *nodeStats
+	conditions []NodeCondition
+	lastGC     time.Time
+}
+
+// snapshot - 마지막 측정값과 조건 (측정 전이면 nil)
+func (p *pressureMonitor) snapshot() (*nodeStats, []NodeCondition) {
+	if p == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats, append([]NodeCondition(nil), p.conditions...)
+}
+
+// runPressureMonitor - 주기적 측정 및 압박 시 이미지/컨테이너 GC
+func (s *StakerHost) runPressureMonitor(ctx context.Context) {
+	if s.pressure == nil {
+		return
+	}
+	ticker := time.NewTicker(pressureCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkPressure()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *StakerHost) checkPressure() {
+	stats, err := readNodeStats(pressureStatsPath)
+	if err != nil {
+		log.Printf("⚠️ 노드 자원 측정 실패: %v", err)
+		return
+	}
+
+	r := s.config.Resources
+	soft, _ := parseEvictionThresholds(r.EvictionSoft)
+	hard, _ := parseEvictionThresholds(r.EvictionHard)
+
+	disk := NodeCondition{Type: "DiskPressure"}
+	memory := NodeCondition{Type: "MemoryPressure"}
+	for _, t := range append(soft, hard...) {
+		if !stats.below(t) {
+			continue
+		}
+		condition := &disk
+		if t.signal == "memory.available" {
+			condition = &memory
+		}
+		condition.Status = true
+		condition.Message = t.signal + " below threshold"
+	}
+
+	p := s.pressure
+	p.mu.Lock()
+	previous := p.conditions
+	p.stats, p.conditions = stats, []NodeCondition{disk, memory}
+	// staking 모드는 K3s를 직접 실행하지 않으므로 측정만 하고 정리는 런타임 에이전트에 맡김
+	// 워터마크 기반 정리는 runImageGC가 주기적으로 하므로 여기서는 압박 시에만 즉시 정리
+	needGC := s.runtimeClient == nil && time.Since(p.lastGC) > 5*time.Minute && disk.Status
+	if needGC {
+		p.lastGC = time.Now()
+	}
+	p.mu.Unlock()
+
+	for i, condition := range []NodeCondition{disk, memory} {
+		if len(previous) > i && previous[i].Status == condition.Status {
+			continue
+		}
+		if condition.Status {
+			log.Printf("🚨 %s 감지: %s (디스크 %.1f%%, 메모리 %.1f%%)", condition.Type, condition.Message, stats.diskPercent(), stats.memoryPercent())
+		} else if len(previous) > i {
+			log.Printf("✅ %s 해소", condition.Type)
+		}
+	}
+
+	if needGC {
+		s.collectGarbage()
+	}
+}
+
+// collectGarbage - 종료된 컨테이너 제거 후 LRU 이미지 GC를 low 워터마크까지 실행
+func (s *StakerHost) collectGarbage() {
+	log.Printf("🧹 디스크 압박: 종료된 컨테이너와 미사용 이미지 정리 중...")
+
+	output, err := exec.Command("k3s", "crictl", "ps", "-a", "--state", "exited", "--quiet").Output()
+	if err != nil {
+		log.Printf("⚠️ 종료된 컨테이너 조회 실패: %v", err)
+	} else if ids := strings.Fields(string(output)); len(ids) > 0 {
+		if err := exec.Command("k3s", append([]string{"crictl", "rm"}, ids...)...).Run(); err != nil {
+			log.Printf("⚠️ 종료된 컨테이너 제거 실패: %v", err)
+		} else {
+			log.Printf("🗑️ 종료된 컨테이너 %d개 제거", len(ids))
+		}
+	}
+
+	s.collectImages(true)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// readNodeStats - statfs(데이터 디렉토리, 없으면 /)와 /proc/meminfo로 측정
+func readNodeStats(path string) (*nodeStats, error) {
+	if _, err := os.Stat(path); err != nil {
+		path = "/"
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return nil, fmt.Errorf("statfs %s 실패: %v", path, err)
+	}
+	stats := &nodeStats{
+		DiskTotal:     fs.Blocks * uint64(fs.Bsize),
+		DiskAvailable: fs.Bavail * uint64(fs.Bsize),
+		InodesTotal:   fs.Files,
+		InodesFree:    fs.Ffree,
+	}
+
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, fmt.Errorf("/proc/meminfo 읽기 실패: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			stats.MemoryTotal = kb * 1024
+		case "MemAvailable:":
+			stats.MemoryAvailable = kb * 1024
+		}
+	}
+	return stats, scanner.Err()
+}
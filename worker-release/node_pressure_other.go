@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "fmt"
+
+// readNodeStats - 자원 측정은 Linux 워커에서만 지원
+func readNodeStats(path string) (*nodeStats, error) {
+	return nil, fmt.Errorf("node stats are only available on linux")
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 노드 문제 감지 기본값
+const (
+	defaultOOMStormThreshold  = 5
+	defaultDiskErrorThreshold = 3
+	defaultProblemWindowSecs  = 600
+	defaultKernelLogLookback  = 300
+)
+
+/*
+NodeProblemConfig - 커널 로그 기반 노드 문제 감지 설정 (staker-config.json node_problems)
+
+node-problem-detector처럼 커널 로그(/dev/kmsg)를 따라 읽어 다음 조건을 하트비트 node_conditions로 보고합니다.
+  - KernelDeadlock: hung task, soft lockup, RCU stall (커널 상태가 복구되지 않으므로 재부팅 전까지 유지)
+  - OOMKilling: window_seconds 안의 OOM kill이 oom_threshold번 이상 (OOM 폭주)
+  - DiskPressure: window_seconds 안의 디스크 I/O·파일시스템 오류가 disk_error_threshold번 이상이거나
+    데이터 디렉토리가 읽기 전용으로 다시 마운트됨 (용량 기반 DiskPressure와 합쳐서 보고)
+
+마스터는 이 조건이 켜진 노드에 taint를 걸어 새 Pod이 배치되지 않게 합니다 (NAUTILUS_NODE_PROBLEM_TAINT).
+*/
+type NodeProblemConfig struct {
+	Disabled           bool `json:"disabled"`
+	OOMThreshold       int  `json:"oom_threshold"`        // OOMKilling 판정 횟수 (기본 5)
+	DiskErrorThreshold int  `json:"disk_error_threshold"` // 디스크 오류 판정 횟수 (기본 3)
+	WindowSeconds      int  `json:"window_seconds"`       // OOM/디스크 오류를 세는 구간 (기본 600초)
+	LookbackSeconds    int  `json:"lookback_seconds"`     // 시작 시 다시 읽는 과거 커널 로그 (기본 300초)
+}
+
+/*
+applyNodeProblemDefaults - 노드 문제 감지 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_NODE_PROBLEMS: off이면 감지하지 않음
+- K3S_DAAS_OOM_STORM_THRESHOLD: OOMKilling 판정 횟수
+*/
+func applyNodeProblemDefaults(config *StakerHostConfig) error {
+	p := &config.NodeProblems
+	if os.Getenv("K3S_DAAS_NODE_PROBLEMS") == "off" {
+		p.Disabled = true
+	}
+	if value := os.Getenv("K3S_DAAS_OOM_STORM_THRESHOLD"); value != "" {
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold <= 0 {
+			return fmt.Errorf("잘못된 K3S_DAAS_OOM_STORM_THRESHOLD: %s", value)
+		}
+		p.OOMThreshold = threshold
+	}
+
+	if p.OOMThreshold <= 0 {
+		p.OOMThreshold = defaultOOMStormThreshold
+	}
+	if p.DiskErrorThreshold <= 0 {
+		p.DiskErrorThreshold = defaultDiskErrorThreshold
+	}
+	if p.WindowSeconds <= 0 {
+		p.WindowSeconds = defaultProblemWindowSecs
+	}
+	if p.LookbackSeconds < 0 {
+		return fmt.Errorf("잘못된 node_problems.lookback_seconds: %d", p.LookbackSeconds)
+	}
+	if p.LookbackSeconds == 0 {
+		p.LookbackSeconds = defaultKernelLogLookback
+	}
+	return nil
+}
+
+// kernelProblem - 커널 로그 한 줄이 가리키는 문제 종류
+type kernelProblem int
+
+const (
+	problemNone kernelProblem = iota
+	problemDeadlock
+	problemOOM
+	problemDisk
+)
+
+// kernelLogRules - node-problem-detector kernel-monitor 규칙에 해당하는 패턴
+var kernelLogRules = []struct {
+	problem kernelProblem
+	pattern *regexp.Regexp
+}{
+	{problemDeadlock, regexp.MustCompile(`task \S+:\d+ blocked for more than \d+ seconds`)},
+	{problemDeadlock, regexp.MustCompile(`BUG: soft lockup`)},
+	{problemDeadlock, regexp.MustCompile(`rcu_(sched|preempt|bh) (self-)?detected stall`)},
+	{problemOOM, regexp.MustCompile(`(Killed process \d+|Memory cgroup out of memory: Kill(ed)? process \d+)`)},
+	{problemDisk, regexp.MustCompile(`(I/O error, dev \S+|Buffer I/O error on dev(ice)? \S+)`)},
+	{problemDisk, regexp.MustCompile(`(EXT4-fs error|XFS \(\S+\): (Corruption|metadata I/O error)|BTRFS error)`)},
+	{problemDisk, regexp.MustCompile(`Remounting filesystem read-only`)},
+}
+
+// classifyKernelLog - 커널 메시지 분류 (해당 없으면 problemNone)
+func classifyKernelLog(message string) kernelProblem {
+	for _, rule := range kernelLogRules {
+		if rule.pattern.MatchString(message) {
+			return rule.problem
+		}
+	}
+	return problemNone
+}
+
+/*
+nodeProblemDetector - 커널 로그와 데이터 디렉토리 상태로 판정한 노드 문제
+OOM/디스크 오류는 최근 window 안의 발생 시각만 남겨 세고, 교착은 처음 본 메시지를 유지합니다.
+*/
+type nodeProblemDetector struct {
+	config NodeProblemConfig
+
+	mu         sync.Mutex
+	deadlock   string      // 처음 감지한 교착 메시지 (재부팅 전까지 유지)
+	ooms       []time.Time // window 안의 OOM kill 시각
+	oomLast    string
+	diskErrors []time.Time // window 안의 디스크 오류 시각
+	diskLast   string
+	readOnly   bool // 데이터 디렉토리가 읽기 전용으로 마운트됨
+	reported   map[string]bool
+}
+
+func newNodeProblemDetector(config NodeProblemConfig) *nodeProblemDetector {
+	if config.Disabled {
+		return nil
+	}
+	return &nodeProblemDetector{config: config, reported: make(map[string]bool)}
+}
+
+// observe - 커널 메시지 하나 반영 (at은 메시지가 기록된 시각)
+func (d *nodeProblemDetector) observe(message string, at time.Time) {
+	problem := classifyKernelLog(message)
+	if problem == problemNone {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch problem {
+	case problemDeadlock:
+		if d.deadlock == "" {
+			d.deadlock = message
+		}
+	case problemOOM:
+		d.ooms = append(d.ooms, at)
+		d.oomLast = message
+	case problemDisk:
+		d.diskErrors = append(d.diskErrors, at)
+		d.diskLast = message
+	}
+}
+
+// setReadOnly - 데이터 디렉토리 읽기 전용 여부 반영
+func (d *nodeProblemDetector) setReadOnly(readOnly bool) {
+	d.mu.Lock()
+	d.readOnly = readOnly
+	d.mu.Unlock()
+}
+
+// conditions - 현재 판정 (KernelDeadlock, OOMKilling, DiskPressure 순서)
+func (d *nodeProblemDetector) conditions(now time.Time) []NodeCondition {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := now.Add(-time.Duration(d.config.WindowSeconds) * time.Second)
+	d.ooms = withinWindow(d.ooms, cutoff)
+	d.diskErrors = withinWindow(d.diskErrors, cutoff)
+
+	deadlock := NodeCondition{Type: "KernelDeadlock"}
+	if d.deadlock != "" {
+		deadlock.Status = true
+		deadlock.Message = d.deadlock
+	}
+	oom := NodeCondition{Type: "OOMKilling"}
+	if len(d.ooms) >= d.config.OOMThreshold {
+		oom.Status = true
+		oom.Message = fmt.Sprintf("%d OOM kills in %ds, last: %s", len(d.ooms), d.config.WindowSeconds, d.oomLast)
+	}
+	disk := NodeCondition{Type: "DiskPressure"}
+	switch {
+	case d.readOnly:
+		disk.Status = true
+		disk.Message = pressureStatsPath + " is mounted read-only"
+	case len(d.diskErrors) >= d.config.DiskErrorThreshold:
+		disk.Status = true
+		disk.Message = fmt.Sprintf("%d disk errors in %ds, last: %s", len(d.diskErrors), d.config.WindowSeconds, d.diskLast)
+	}
+	return []NodeCondition{deadlock, oom, disk}
+}
+
+func withinWindow(times []time.Time, cutoff time.Time) []time.Time {
+	kept := times[:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// mergeNodeConditions - 같은 종류의 조건은 하나로 합침 (하나라도 켜져 있으면 켜짐, 메시지는 켜진 쪽)
+func mergeNodeConditions(conditions []NodeCondition, extra []NodeCondition) []NodeCondition {
+	merged := append([]NodeCondition(nil), conditions...)
+	for _, condition := range extra {
+		found := false
+		for i := range merged {
+			if merged[i].Type != condition.Type {
+				continue
+			}
+			found = true
+			if condition.Status && !merged[i].Status {
+				merged[i] = condition
+			} else if condition.Status && merged[i].Status {
+				merged[i].Message += "; " + condition.Message
+			}
+		}
+		if !found {
+			merged = append(merged, condition)
+		}
+	}
+	return merged
+}
+
+// runNodeProblemDetector - 커널 로그 감시와 주기적 판정 (판정이 바뀔 때 로그)
+func (s *StakerHost) runNodeProblemDetector(ctx context.Context) {
+	d := s.problems
+	if d == nil {
+		return
+	}
+	if err := watchKernelLog(ctx, d); err != nil {
+		log.Printf("⚠️ 커널 로그 감시 불가 (KernelDeadlock/OOMKilling 감지 안 함): %v", err)
+	} else {
+		log.Printf("🩺 노드 문제 감지 시작 (커널 로그, OOM 폭주 기준 %d회/%ds)", d.config.OOMThreshold, d.config.WindowSeconds)
+	}
+
+	ticker := time.NewTicker(pressureCheckInterval)
+	defer ticker.Stop()
+	for {
+		d.setReadOnly(isReadOnlyMount(pressureStatsPath))
+		for _, condition := range d.conditions(time.Now()) {
+			d.mu.Lock()
+			changed := d.reported[condition.Type] != condition.Status
+			d.reported[condition.Type] = condition.Status
+			d.mu.Unlock()
+			if !changed {
+				continue
+			}
+			if condition.Status {
+				log.Printf("🚨 노드 문제 %s 감지: %s", condition.Type, condition.Message)
+			} else {
+				log.Printf("✅ 노드 문제 %s 해소", condition.Type)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// watchKernelLog - /dev/kmsg를 따라 읽어 감지기에 전달 (열 수 없으면 오류, 읽기는 백그라운드)
+func watchKernelLog(ctx context.Context, d *nodeProblemDetector) error {
+	kmsg, err := os.Open("/dev/kmsg")
+	if err != nil {
+		return err
+	}
+	boot := bootTime()
+	since := time.Now().Add(-time.Duration(d.config.LookbackSeconds) * time.Second)
+
+	go func() {
+		defer kmsg.Close()
+		buf := make([]byte, 8192) // /dev/kmsg는 read 한 번에 레코드 하나
+		for ctx.Err() == nil {
+			n, err := kmsg.Read(buf)
+			if errors.Is(err, syscall.EPIPE) {
+				continue // 읽기 전에 링 버퍼에서 밀려난 레코드
+			}
+			if err != nil {
+				return
+			}
+			at, message, ok := parseKmsgRecord(string(buf[:n]), boot)
+			if ok && at.After(since) {
+				d.observe(message, at)
+			}
+		}
+	}()
+	return nil
+}
+
+// parseKmsgRecord - "우선순위,순번,부팅 후 마이크로초,플래그;메시지" 레코드 (이어지는 KEY=VALUE 줄은 무시)
+func parseKmsgRecord(record string, boot time.Time) (time.Time, string, bool) {
+	header, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	message, _, _ = strings.Cut(message, "\n")
+	fields := strings.Split(header, ",")
+	if len(fields) < 3 {
+		return time.Time{}, "", false
+	}
+	usec, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return boot.Add(time.Duration(usec) * time.Microsecond), message, true
+}
+
+// bootTime - /proc/uptime으로 계산한 부팅 시각 (커널 로그 타임스탬프 기준)
+func bootTime() time.Time {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return time.Now()
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return time.Now()
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Now().Add(-time.Duration(uptime * float64(time.Second)))
+}
+
+// isReadOnlyMount - 경로(없으면 /)가 있는 파일시스템이 읽기 전용인지
+func isReadOnlyMount(path string) bool {
+	if _, err := os.Stat(path); err != nil {
+		path = "/"
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return false
+	}
+	return fs.Flags&syscall.MS_RDONLY != 0
+}
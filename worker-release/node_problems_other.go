@@ -0,0 +1,17 @@
+//go:build !linux
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// watchKernelLog - 커널 로그 감시는 Linux 워커에서만 지원
+func watchKernelLog(ctx context.Context, d *nodeProblemDetector) error {
+	return fmt.Errorf("kernel log monitoring is only available on linux")
+}
+
+func isReadOnlyMount(path string) bool {
+	return false
+}
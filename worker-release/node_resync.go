@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// desiredPod - 마스터 기준으로 이 노드에서 실행 중이어야 하는 Pod (terminating이면 삭제 요청됨)
+type desiredPod struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	UID         string `json:"uid"`
+	Phase       string `json:"phase"`
+	Terminating bool   `json:"terminating,omitempty"`
+}
+
+// nodeDesiredState - /api/v1/nodes/desired-state 응답 (nautilus-release NodeDesiredState와 같은 JSON)
+type nodeDesiredState struct {
+	NodeID        string              `json:"node_id"`
+	Pods          []desiredPod        `json:"pods"`
+	ConfigVersion int64               `json:"config_version"`
+	Config        *workerConfigBundle `json:"config,omitempty"`
+	Cordoned      bool                `json:"cordoned"`
+	Maintenance   bool                `json:"maintenance"`
+	WorkerStatus  string              `json:"worker_status"`
+}
+
+// podReconcileRequest - 런타임 에이전트에 넘기는 목표 Pod 목록 (since 이후 만들어진 샌드박스는 대상 아님)
+type podReconcileRequest struct {
+	Pods  []desiredPod `json:"pods"`
+	Since time.Time    `json:"since"`
+}
+
+// podReconcileResult - 목표 상태와 대조한 결과
+type podReconcileResult struct {
+	Adopted []podAssignment `json:"adopted"`
+	Stopped []podAssignment `json:"stopped"`
+	Kept    []podAssignment `json:"kept"`
+}
+
+// fetchDesiredState - 마스터에서 노드 전체 목표 상태 조회 (Seal 토큰 인증)
+func (s *StakerHost) fetchDesiredState() (*nodeDesiredState, error) {
+	resp, err := s.restyClient().SetTimeout(10*time.Second).R().
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetQueryParam("node_id", s.config.NodeID).
+		Get(s.config.NautilusEndpoint + "/api/v1/nodes/desired-state")
+	if err != nil {
+		return nil, fmt.Errorf("목표 상태 조회 실패: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("마스터가 목표 상태 조회를 거부했습니다 (HTTP %d)", resp.StatusCode())
+	}
+
+	var body struct {
+		Data *nodeDesiredState `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body(), &body); err != nil || body.Data == nil {
+		return nil, fmt.Errorf("목표 상태 응답 파싱 실패: %v", err)
+	}
+	return body.Data, nil
+}
+
+/*
+resyncWithMaster - 마스터의 목표 상태와 로컬 실제 상태를 대조 (재시작 또는 재연결 직후)
+
+오프라인 동안 놓친 drain과 Pod 삭제를 따라잡기 위해 하트비트의 차등 정보 대신 전체 상태를 받습니다.
+ 1. 적용 중인 설정 번들보다 새 버전이면 적용 (runtime 모드는 스테이킹 데몬이 적용)
+ 2. 목표에 없거나 삭제 중인 Pod 샌드박스 중단, 목표에 있는 Pod은 채택
+ 3. 결과를 /api/v1/nodes/reconcile로 보고해 노드 타임라인에 남김
+*/
+func (s *StakerHost) resyncWithMaster(reason string) error {
+	since := time.Now()
+	desired, err := s.fetchDesiredState()
+	if err != nil {
+		return err
+	}
+	log.Printf("🔁 마스터 전체 동기화 (%s): Pod %d개, 설정 v%d, cordon=%t, 정비=%t",
+		reason, len(desired.Pods), desired.ConfigVersion, desired.Cordoned, desired.Maintenance)
+
+	if desired.Config != nil && workerMode() != modeRuntime && features.Enabled(featureConfigSync) {
+		if bundle, _ := s.configSync.current(); desired.Config.Version > bundle.Version {
+			s.applyConfigBundle(*desired.Config)
+		}
+	}
+	if desired.Cordoned {
+		// 새 Pod은 마스터가 배정하지 않으므로 기존 Pod 대조만 수행
+		log.Printf("🚧 마스터에서 cordon된 노드입니다 (정비=%t), 새 Pod 배정 없음", desired.Maintenance)
+	}
+
+	result, err := s.reconcileDesired(podReconcileRequest{Pods: desired.Pods, Since: since})
+	if err != nil {
+		return err
+	}
+	log.Printf("🔁 전체 동기화 완료: 채택 %d, 중단 %d, 유지 %d", len(result.Adopted), len(result.Stopped), len(result.Kept))
+	if len(result.Adopted)+len(result.Stopped)+len(result.Kept) == 0 {
+		return nil
+	}
+	return s.reportReconcile(result.Adopted, result.Stopped, result.Kept)
+}
+
+// reconcileDesired - 로컬 CRI 또는 (staking 모드) 런타임 에이전트에서 목표 Pod과 대조
+func (s *StakerHost) reconcileDesired(req podReconcileRequest) (podReconcileResult, error) {
+	if s.runtimeClient != nil {
+		return s.runtimeClient.ReconcilePods(req)
+	}
+	return s.reconcileDesiredPods(req)
+}
+
+/*
+reconcileDesiredPods - since 이전에 만들어진 Ready 샌드박스를 목표 Pod과 대조
+  - 목표에 있는 Pod: 채택
+  - 마스터가 삭제를 요청한 Pod: orphan_policy와 관계없이 유예 시간을 지켜 중단
+  - 목표에 없는 Pod: orphan_policy가 stop이면 중단, keep이면 그대로 둠
+*/
+func (s *StakerHost) reconcileDesiredPods(req podReconcileRequest) (podReconcileResult, error) {
+	result := podReconcileResult{Adopted: []podAssignment{}, Stopped: []podAssignment{}, Kept: []podAssignment{}}
+	sandboxes, err := listSandboxes()
+	if err != nil {
+		return result, err
+	}
+
+	desired := make(map[string]desiredPod, len(req.Pods))
+	for _, pod := range req.Pods {
+		desired[pod.UID] = pod
+	}
+
+	for _, sandbox := range sandboxes {
+		if sandbox.State != "SANDBOX_READY" || !sandbox.createdAt().Before(req.Since) {
+			continue
+		}
+		pod := sandbox.assignment()
+
+		target, ok := desired[pod.UID]
+		if ok && !target.Terminating {
+			result.Adopted = append(result.Adopted, pod)
+			continue
+		}
+		if !ok && s.config.OrphanPolicy == orphanPolicyKeep {
+			log.Printf("👻 목표 상태에 없는 Pod 유지: %s/%s", pod.Namespace, pod.Name)
+			result.Kept = append(result.Kept, pod)
+			continue
+		}
+		if err := terminateSandbox(pod.SandboxID, s.config.Termination.defaultGrace(), 0); err != nil {
+			log.Printf("⚠️ Pod 중단 실패 %s/%s: %v", pod.Namespace, pod.Name, err)
+			result.Kept = append(result.Kept, pod)
+			continue
+		}
+		log.Printf("🧹 오프라인 동안 삭제된 Pod 중단: %s/%s", pod.Namespace, pod.Name)
+		result.Stopped = append(result.Stopped, pod)
+	}
+	return result, nil
+}
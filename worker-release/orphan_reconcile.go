@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// 고아 컨테이너 정리 정책
+const (
+	orphanPolicyStop = "stop" // 기록에 없는 Pod 샌드박스 중단 (기본)
+	orphanPolicyKeep = "keep" // 기록만 하고 그대로 둠
+
+	defaultAssignmentStatePath = "/var/lib/k3s-daas-agent/assignments.json"
+	assignmentRecordInterval   = 30 * time.Second
+	criReadyTimeout            = 2 * time.Minute
+)
+
+/*
+podAssignment - 이 노드에서 실행 중이던 Pod 기록
+스테이커 호스트가 비정상 종료된 뒤 다시 시작할 때 이전 실행의 Pod을 식별하는 기준입니다.
+*/
+type podAssignment struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	SandboxID string `json:"sandbox_id"`
+}
+
+// assignmentState - 배정 기록 파일 형식
+type assignmentState struct {
+	NodeID     string                   `json:"node_id"`
+	Pods       map[string]podAssignment `json:"pods"` // Pod UID 기준
+	RecordedAt int64                    `json:"recorded_at"`
+}
+
+// criSandbox - crictl pods -o json 항목 중 필요한 필드
+type criSandbox struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Created  string `json:"createdAt"` // Unix 나노초 (문자열)
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		UID       string `json:"uid"`
+	} `json:"metadata"`
+}
+
+func (c criSandbox) createdAt() time.Time {
+	nanos, err := strconv.ParseInt(c.Created, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+func (c criSandbox) assignment() podAssignment {
+	return podAssignment{Namespace: c.Metadata.Namespace, Name: c.Metadata.Name, UID: c.Metadata.UID, SandboxID: c.ID}
+}
+
+/*
+applyOrphanDefaults - 고아 컨테이너 정리 설정 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_ORPHAN_POLICY: stop | keep
+- K3S_DAAS_ASSIGNMENT_STATE: 배정 기록 파일 경로
+*/
+func applyOrphanDefaults(config *StakerHostConfig) error {
+	if policy := os.Getenv("K3S_DAAS_ORPHAN_POLICY"); policy != "" {
+		config.OrphanPolicy = policy
+	}
+	if path := os.Getenv("K3S_DAAS_ASSIGNMENT_STATE"); path != "" {
+		config.AssignmentStatePath = path
+	}
+	if config.OrphanPolicy == "" {
+		config.OrphanPolicy = orphanPolicyStop
+	}
+	if config.AssignmentStatePath == "" {
+		config.AssignmentStatePath = defaultAssignmentStatePath
+	}
+
+	if config.OrphanPolicy != orphanPolicyStop && config.OrphanPolicy != orphanPolicyKeep {
+		return fmt.Errorf("잘못된 orphan_policy: %s (stop 또는 keep)", config.OrphanPolicy)
+	}
+	return nil
+}
+
+// listSandboxes - CRI의 Pod 샌드박스 목록 (K3s 내장 crictl)
+func listSandboxes() ([]criSandbox, error) {
+	output, err := exec.Command("k3s", "crictl", "pods", "-o", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("crictl pods 실패: %v", err)
+	}
+	var response struct {
+		Items []criSandbox `json:"items"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("crictl 출력 파싱 실패: %v", err)
+	}
+	return response.Items, nil
+}
+
+func loadAssignmentState(path string) (*assignmentState, error) {
+	var state *assignmentState
+	if err := loadStateFile(path, &state); err != nil {
+		return nil, fmt.Errorf("배정 기록 파싱 실패: %v", err)
+	}
+	return state, nil
+}
+
+func saveAssignmentState(path string, state *assignmentState) error {
+	return writeStateFile(path, state)
+}
+
+// loadStateFile - JSON 상태 파일 읽기 (파일이 없으면 v를 그대로 두고 nil 반환)
+func loadStateFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeStateFile - 임시 파일에 쓴 뒤 rename (기록 도중 크래시해도 이전 기록 유지)
+func writeStateFile(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/*
+startOrphanReconcile - K3s Agent 시작 후 한 번만 이전 실행의 컨테이너를 정리하고 배정 기록 시작
+(런타임 에이전트가 Agent를 재시작해도 정리는 프로세스당 한 번)
+마스터의 목표 상태로 먼저 대조하고, 마스터에 닿지 않으면 로컬 배정 기록으로 정리합니다.
+*/
+func (s *StakerHost) startOrphanReconcile() {
+	s.reconcileOnce.Do(func() {
+		go func() {
+			if _, err := s.waitForSandboxes(); err != nil {
+				log.Printf("⚠️ 컨테이너 런타임 응답 없음: %v", err)
+			}
+			if err := s.resyncWithMaster("시작"); err != nil {
+				log.Printf("⚠️ 마스터 전체 동기화 실패, 로컬 배정 기록으로 정리: %v", err)
+				if err := s.reconcileOrphans(); err != nil {
+					log.Printf("⚠️ 고아 컨테이너 정리 실패: %v", err)
+				}
+			}
+			s.recordAssignments()
+		}()
+	})
+}
+
+/*
+reconcileOrphans - 런타임의 실제 Pod 샌드박스를 배정 기록과 대조
+
+이 프로세스 시작 전에 만들어진 샌드박스만 대상으로 합니다 (재시작한 kubelet이 새로 만든 Pod 제외).
+  - 기록에 있는 Pod: 다시 추적 대상으로 채택
+  - 기록에 없는 Pod: orphan_policy가 stop이면 중단, keep이면 그대로 둠
+
+배정 기록이 아예 없으면 (첫 실행) 이전 Pod을 구분할 수 없으므로 정리하지 않습니다.
+결과는 마스터에 보고하여 노드 타임라인에 남깁니다.
+*/
+func (s *StakerHost) reconcileOrphans() error {
+	recorded, err := loadAssignmentState(s.config.AssignmentStatePath)
+	if err != nil {
+		return err
+	}
+	if recorded == nil {
+		log.Printf("📋 배정 기록 없음, 고아 컨테이너 정리 생략 (%s)", s.config.AssignmentStatePath)
+		return nil
+	}
+
+	sandboxes, err := s.waitForSandboxes()
+	if err != nil {
+		return err
+	}
+
+	var adopted, stopped, kept []podAssignment
+	for _, sandbox := range sandboxes {
+		if sandbox.State != "SANDBOX_READY" || !sandbox.createdAt().Before(s.startTime) {
+			continue
+		}
+		pod := sandbox.assignment()
+
+		if _, ok := recorded.Pods[pod.UID]; ok {
+			adopted = append(adopted, pod)
+			continue
+		}
+		if s.config.OrphanPolicy == orphanPolicyKeep {
+			log.Printf("👻 기록에 없는 Pod 유지: %s/%s", pod.Namespace, pod.Name)
+			kept = append(kept, pod)
+			continue
+		}
+		// 고아 Pod도 preStop 훅과 유예 시간을 지켜 종료
+		if err := terminateSandbox(pod.SandboxID, s.config.Termination.defaultGrace(), 0); err != nil {
+			log.Printf("⚠️ 고아 Pod 중단 실패 %s/%s: %v", pod.Namespace, pod.Name, err)
+			kept = append(kept, pod)
+			continue
+		}
+		log.Printf("🧹 고아 Pod 중단: %s/%s", pod.Namespace, pod.Name)
+		stopped = append(stopped, pod)
+	}
+
+	log.Printf("♻️ 이전 실행 컨테이너 정리 완료: 채택 %d, 중단 %d, 유지 %d", len(adopted), len(stopped), len(kept))
+	if len(adopted)+len(stopped)+len(kept) == 0 {
+		return nil
+	}
+	return s.reportReconcile(adopted, stopped, kept)
+}
+
+// waitForSandboxes - Agent가 띄운 containerd가 응답할 때까지 재시도
+func (s *StakerHost) waitForSandboxes() ([]criSandbox, error) {
+	deadline := time.Now().Add(criReadyTimeout)
+	for {
+		sandboxes, err := listSandboxes()
+		if err == nil {
+			return sandboxes, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// reportReconcile - 채택/중단한 Pod을 마스터에 보고
+func (s *StakerHost) reportReconcile(adopted, stopped, kept []podAssignment) error {
+	resp, err := s.restyClient().SetTimeout(10*time.Second).R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetBody(map[string]interface{}{
+			"node_id": s.config.NodeID,
+			"adopted": adopted,
+			"stopped": stopped,
+			"kept":    kept,
+		}).
+		Post(s.config.NautilusEndpoint + "/api/v1/nodes/reconcile")
+	if err != nil {
+		return fmt.Errorf("정리 결과 보고 실패: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("마스터가 정리 결과를 거부했습니다 (HTTP %d)", resp.StatusCode())
+	}
+	return nil
+}
+
+// recordAssignments - 실행 중인 Pod을 주기적으로 기록 (다음 시작 시 정리 기준)
+func (s *StakerHost) recordAssignments() {
+	ticker := time.NewTicker(assignmentRecordInterval)
+	defer ticker.Stop()
+
+	for {
+		if sandboxes, err := listSandboxes(); err == nil {
+			state := &assignmentState{
+				NodeID:     s.config.NodeID,
+				Pods:       make(map[string]podAssignment),
+				RecordedAt: time.Now().Unix(),
+			}
+			for _, sandbox := range sandboxes {
+				if sandbox.State == "SANDBOX_READY" {
+					state.Pods[sandbox.Metadata.UID] = sandbox.assignment()
+				}
+			}
+			if err := saveAssignmentState(s.config.AssignmentStatePath, state); err != nil {
+				log.Printf("⚠️ 배정 기록 저장 실패: %v", err)
+			}
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+교차 검증(peer review) 증명 - 워커가 마스터(및 선택적으로 피어)의 TEE 주장을 검증
+지금까지 신뢰는 마스터→워커 방향으로만 흘렀습니다. 매 에폭마다 무작위로 선택된 워커가
+마스터의 증명 문서를 받아 고정된 공개키와 허용 측정값 목록으로 검증하고,
+결과를 worker_registry::report_attestation으로 온체인에 기록합니다.
+문서 형식과 서명 다이제스트는 nautilus-release/attestation.go와 동일해야 합니다.
+*/
+const (
+	attestationDomain        = "daas-attestation-v1"
+	defaultAttestationSample = 0.25             // 에폭당 검증자로 선택될 확률
+	attestationEpochPoll     = 10 * time.Minute // 에폭 변경 확인 주기
+	attestationMaxAge        = 2 * time.Minute  // 문서 발급 시각 허용 오차
+)
+
+// AttestationPeer - 추가로 검증할 증명 제공자 (스탠바이 마스터 등)
+type AttestationPeer struct {
+	NodeID    string `json:"node_id"`
+	Endpoint  string `json:"endpoint"`
+	PublicKey string `json:"public_key"` // hex ed25519 공개키 (고정)
+}
+
+// attestationDocument - 서명된 TEE 증명 문서
+type attestationDocument struct {
+	Subject     string `json:"subject"`
+	Measurement string `json:"measurement"`
+	TEEDevice   string `json:"tee_device"`
+	Nonce       string `json:"nonce"`
+	IssuedAt    int64  `json:"issued_at"`
+	PublicKey   string `json:"public_key"`
+	// EncryptionKey - 마스터가 요청 본문 E2E 암호화를 켠 경우의 수신자 키 (서명에 포함)
+	EncryptionKey string `json:"encryption_key,omitempty"`
+	Signature     string `json:"signature"`
+}
+
+// attestationDigest - 서명 대상 다이제스트 (마스터와 동일한 형식)
+func attestationDigest(doc *attestationDocument) []byte {
+	payload := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%d",
+		attestationDomain, doc.Subject, doc.Measurement, doc.TEEDevice, doc.Nonce, doc.IssuedAt)
+	if doc.EncryptionKey != "" {
+		payload += "\n" + doc.EncryptionKey
+	}
+	digest := sha256.Sum256([]byte(payload))
+	return digest[:]
+}
+
+/*
+applyPeerAttestationDefaults - 교차 검증 설정 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_PEER_ATTESTATION=true: 교차 검증 활성화
+- K3S_DAAS_MASTER_PUBLIC_KEY: 마스터 응답 서명 공개키 (hex)
+- K3S_DAAS_TRUSTED_MEASUREMENTS: 허용 측정값 (쉼표 구분)
+- K3S_DAAS_ATTESTATION_SAMPLE_RATE: 에폭당 선택 확률 (0~1)
+*/
+func applyPeerAttestationDefaults(config *StakerHostConfig) error {
+	if os.Getenv("K3S_DAAS_PEER_ATTESTATION") == "true" {
+		config.PeerAttestation = true
+	}
+	if key := os.Getenv("K3S_DAAS_MASTER_PUBLIC_KEY"); key != "" {
+		config.MasterPublicKey = key
+	}
+	if list := os.Getenv("K3S_DAAS_TRUSTED_MEASUREMENTS"); list != "" {
+		config.TrustedMeasurements = strings.Split(list, ",")
+	}
+	if rate := os.Getenv("K3S_DAAS_ATTESTATION_SAMPLE_RATE"); rate != "" {
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return fmt.Errorf("잘못된 K3S_DAAS_ATTESTATION_SAMPLE_RATE: %v", err)
+		}
+		config.AttestationSampleRate = value
+	}
+	if config.AttestationSampleRate <= 0 {
+		config.AttestationSampleRate = defaultAttestationSample
+	}
+
+	if config.PeerAttestation && config.MasterPublicKey == "" {
+		return fmt.Errorf("peer_attestation에는 master_public_key가 필요합니다")
+	}
+	return nil
+}
+
+/*
+selectedForEpoch - 이번 에폭에 검증자로 선택되었는지 결정
+sha256(epoch || node_id)의 앞 8바이트를 [0,1)로 변환해 선택 확률과 비교하므로,
+누구나 같은 계산으로 선택 결과를 재현할 수 있고 워커가 선택을 조작할 수 없습니다.
+*/
+func selectedForEpoch(epoch, nodeID string, rate float64) bool {
+	digest := sha256.Sum256([]byte(epoch + nodeID))
+	draw := float64(binary.BigEndian.Uint64(digest[:8])) / math.Pow(2, 64)
+	return draw < rate
+}
+
+// currentEpoch - Sui 최신 시스템 상태의 에폭
+func (s *StakerHost) currentEpoch() (string, error) {
+	var state struct {
+		Epoch string `json:"epoch"`
+	}
+	if err := s.suiRPC("suix_getLatestSuiSystemState", []interface{}{}, &state); err != nil {
+		return "", err
+	}
+	return state.Epoch, nil
+}
+
+/*
+runPeerAttestation - 에폭 변경마다 선택 여부를 확인하고 마스터/피어 증명 검증
+ctx 종료 시까지 블로킹합니다.
+*/
+func (s *StakerHost) runPeerAttestation(ctx context.Context) {
+	log.Printf("🛡️ 교차 검증 활성화 (선택 확률 %.0f%%, 허용 측정값 %d개)",
+		s.config.AttestationSampleRate*100, len(s.config.TrustedMeasurements))
+
+	ticker := time.NewTicker(attestationEpochPoll)
+	defer ticker.Stop()
+
+	lastEpoch := ""
+	for {
+		epoch, err := s.currentEpoch()
+		if err != nil {
+			log.Printf("⚠️ 에폭 조회 실패: %v", err)
+		} else if epoch != lastEpoch {
+			lastEpoch = epoch
+			if selectedForEpoch(epoch, s.config.NodeID, s.config.AttestationSampleRate) {
+				log.Printf("🎲 에폭 %s 교차 검증자로 선택됨", epoch)
+				s.attestTargets()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attestTargets - 마스터와 설정된 피어의 증명 검증 및 결과 보고
+func (s *StakerHost) attestTargets() {
+	targets := append([]AttestationPeer{{
+		NodeID:    "master",
+		Endpoint:  s.config.NautilusEndpoint,
+		PublicKey: s.config.MasterPublicKey,
+	}}, s.config.AttestationPeers...)
+
+	for _, target := range targets {
+		measurement, err := s.verifyAttestation(target)
+		verdict := "valid"
+		if err != nil {
+			verdict = "invalid"
+			log.Printf("🚨 %s 증명 검증 실패: %v", target.NodeID, err)
+		} else {
+			log.Printf("✅ %s 증명 검증 성공 (측정값 %s)", target.NodeID, measurement)
+		}
+		s.reportAttestation(target.NodeID, measurement, verdict)
+	}
+}
+
+/*
+verifyAttestation - 증명 문서 조회 및 검증
+nonce 일치, 발급 시각, 고정 공개키 서명, 허용 측정값을 차례로 확인합니다.
+실패한 경우에도 확인된 측정값이 있으면 함께 반환합니다.
+*/
+func (s *StakerHost) verifyAttestation(target AttestationPeer) (string, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", fmt.Errorf("nonce 생성 실패: %v", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(target.Endpoint, "/") + "/api/v1/attestation?nonce=" + url.QueryEscape(nonce))
+	if err != nil {
+		return "", fmt.Errorf("증명 문서 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("증명 문서 조회 실패 (HTTP %d)", resp.StatusCode)
+	}
+
+	var body struct {
+		Data attestationDocument `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("증명 문서 파싱 실패: %v", err)
+	}
+	doc := &body.Data
+
+	if doc.Nonce != nonce {
+		return doc.Measurement, fmt.Errorf("nonce 불일치 (재전송 의심)")
+	}
+	if age := time.Since(time.Unix(doc.IssuedAt, 0)); age > attestationMaxAge || age < -attestationMaxAge {
+		return doc.Measurement, fmt.Errorf("발급 시각 오차 %s", age.Round(time.Second))
+	}
+
+	// 문서에 포함된 공개키가 아닌 고정된 공개키로 검증
+	publicKey, err := hex.DecodeString(target.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return doc.Measurement, fmt.Errorf("설정된 공개키가 올바르지 않습니다")
+	}
+	signature, err := hex.DecodeString(doc.Signature)
+	if err != nil || !ed25519.Verify(publicKey, attestationDigest(doc), signature) {
+		return doc.Measurement, fmt.Errorf("서명 검증 실패")
+	}
+
+	if len(s.config.TrustedMeasurements) > 0 && !containsString(s.config.TrustedMeasurements, doc.Measurement) {
+		return doc.Measurement, fmt.Errorf("허용되지 않은 측정값 %s", doc.Measurement)
+	}
+	return doc.Measurement, nil
+}
+
+/*
+reportAttestation - 검증 결과를 온체인에 기록
+스폰서 가스가 설정되지 않은 경우 로그만 남깁니다 (워커 지갑 가스 소모 방지).
+*/
+func (s *StakerHost) reportAttestation(target, measurement, verdict string) {
+	if !s.config.GasSponsorship {
+		log.Printf("ℹ️ 스폰서 가스 미사용 - %s 검증 결과(%s)는 온체인에 기록하지 않음", target, verdict)
+		return
+	}
+	if measurement == "" {
+		measurement = "unknown"
+	}
+
+	digest, err := s.executeSponsoredCall("worker_registry", "report_attestation", target, measurement, verdict)
+	if err != nil {
+		log.Printf("⚠️ 교차 검증 결과 기록 실패: %v", err)
+		return
+	}
+	log.Printf("⛽ 교차 검증 결과 온체인 기록 (%s: %s): %s", target, verdict, digest)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if strings.TrimSpace(item) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+)
+
+// resourceLimitsLabel - 노드가 Pod CPU/메모리 제한을 cgroup으로 강제할 수 있는지 (enforced 또는 advisory)
+const resourceLimitsLabel = "k3s-daas.io/resource-limits"
+
+/*
+PodResourceUsage - Pod 자원 제한 강제 상태와 누적 카운터 (하트비트 pod_resources, 마스터가 Pod 조건으로 반영)
+
+제한 값은 kubelet이 CRI로 넘겨 containerd가 만든 OCI spec(linux.resources)에서 읽고,
+Enforced는 제한이 있는 모든 컨테이너의 cgroup(cpu.max, memory.max)에 그 값이 실제로 걸려 있을 때만 true입니다.
+스로틀링은 컨테이너 cgroup의 cpu.stat 합, OOM kill은 Pod cgroup의 memory.events(하위 포함)에서 읽습니다.
+*/
+type PodResourceUsage struct {
+	Namespace           string  `json:"namespace"`
+	Pod                 string  `json:"pod"`
+	UID                 string  `json:"uid"`
+	CPULimitMillis      int64   `json:"cpu_limit_millis,omitempty"`
+	MemoryLimitBytes    int64   `json:"memory_limit_bytes,omitempty"`
+	Enforced            bool    `json:"enforced"`
+	CPUPeriods          uint64  `json:"cpu_periods"`
+	CPUThrottledPeriods uint64  `json:"cpu_throttled_periods"`
+	CPUThrottledSeconds float64 `json:"cpu_throttled_seconds"`
+	OOMKills            uint64  `json:"oom_kills"`
+}
+
+// cgroupCounters - 컨테이너 cgroup에서 읽은 값
+type cgroupCounters struct {
+	cpuQuota         int64 // cpu.max 할당량 (마이크로초, 제한 없으면 0)
+	cpuPeriod        int64
+	memoryMax        int64 // memory.max (제한 없으면 0)
+	periods          uint64
+	throttledPeriods uint64
+	throttledUsec    uint64
+	podOOMKills      uint64 // Pod cgroup memory.events oom_kill
+}
+
+// criListedContainer - crictl ps -o json 항목
+type criListedContainer struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels"`
+}
+
+// resourceEnforcementKubeletArgs - Pod 제한을 cgroup으로 강제하는 kubelet 플래그 (기본값이지만 배포 설정이 끄지 못하도록 명시)
+func resourceEnforcementKubeletArgs() []string {
+	return []string{
+		"--cgroups-per-qos=true",
+		"--cpu-cfs-quota=true",
+		"--cpu-cfs-quota-period=100ms",
+	}
+}
+
+// resourceLimitLabels - 노드 기능 라벨 (cgroup v2 컨트롤러가 없으면 제한은 스케줄링에만 쓰이는 advisory)
+func resourceLimitLabels() []string {
+	if supported, reason := cgroupLimitsSupported(); !supported {
+		log.Printf("⚠️ Pod 자원 제한을 강제할 수 없음: %s", reason)
+		return []string{resourceLimitsLabel + "=advisory"}
+	}
+	return []string{resourceLimitsLabel + "=enforced"}
+}
+
+// collectPodResources - 실행 중인 컨테이너를 Pod별로 묶어 제한과 cgroup 카운터 수집
+func collectPodResources() ([]PodResourceUsage, error) {
+	output, err := exec.Command("k3s", "crictl", "ps", "--state", "running", "-o", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("crictl ps 실패: %v", err)
+	}
+	var response struct {
+		Containers []criListedContainer `json:"containers"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("crictl 출력 파싱 실패: %v", err)
+	}
+	supported, _ := cgroupLimitsSupported()
+
+	pods := make(map[string]*PodResourceUsage)
+	for _, container := range response.Containers {
+		uid := container.Labels["io.kubernetes.pod.uid"]
+		if uid == "" {
+			continue
+		}
+		pod, ok := pods[uid]
+		if !ok {
+			pod = &PodResourceUsage{
+				Namespace: container.Labels["io.kubernetes.pod.namespace"],
+				Pod:       container.Labels["io.kubernetes.pod.name"],
+				UID:       uid,
+				Enforced:  supported,
+			}
+			pods[uid] = pod
+		}
+
+		pid, spec, err := inspectContainerResources(container.ID)
+		if err != nil {
+			log.Printf("⚠️ 컨테이너 %s 자원 spec 조회 실패: %v", container.ID, err)
+			pod.Enforced = false
+			continue
+		}
+		if spec.cpuQuota > 0 && spec.cpuPeriod > 0 {
+			pod.CPULimitMillis += spec.cpuQuota * 1000 / spec.cpuPeriod
+		}
+		pod.MemoryLimitBytes += spec.memoryMax
+
+		counters, err := readContainerCgroup(pid)
+		if err != nil {
+			if spec.cpuQuota > 0 || spec.memoryMax > 0 {
+				pod.Enforced = false
+			}
+			continue
+		}
+		// OCI spec의 제한이 cgroup에 걸리지 않았으면 (cgroupfs 드라이버 불일치, 위임 누락 등) 강제되지 않는 것
+		if (spec.cpuQuota > 0 && counters.cpuQuota != spec.cpuQuota) || (spec.memoryMax > 0 && counters.memoryMax != spec.memoryMax) {
+			pod.Enforced = false
+		}
+		pod.CPUPeriods += counters.periods
+		pod.CPUThrottledPeriods += counters.throttledPeriods
+		pod.CPUThrottledSeconds += float64(counters.throttledUsec) / 1e6
+		if counters.podOOMKills > pod.OOMKills {
+			pod.OOMKills = counters.podOOMKills
+		}
+	}
+
+	usage := make([]PodResourceUsage, 0, len(pods))
+	for _, pod := range pods {
+		if pod.CPULimitMillis == 0 && pod.MemoryLimitBytes == 0 {
+			pod.Enforced = false // 제한이 없는 Pod (BestEffort 등)
+		}
+		usage = append(usage, *pod)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].UID < usage[j].UID })
+	return usage, nil
+}
+
+// inspectContainerResources - crictl inspect의 PID와 OCI spec linux.resources 제한
+func inspectContainerResources(containerID string) (int, cgroupCounters, error) {
+	output, err := exec.Command("k3s", "crictl", "inspect", containerID).Output()
+	if err != nil {
+		return 0, cgroupCounters{}, err
+	}
+	var inspect struct {
+		Info struct {
+			Pid         int `json:"pid"`
+			RuntimeSpec struct {
+				Linux struct {
+					Resources struct {
+						CPU *struct {
+							Quota  int64 `json:"quota"`
+							Period int64 `json:"period"`
+						} `json:"cpu"`
+						Memory *struct {
+							Limit int64 `json:"limit"`
+						} `json:"memory"`
+					} `json:"resources"`
+				} `json:"linux"`
+			} `json:"runtimeSpec"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(output, &inspect); err != nil {
+		return 0, cgroupCounters{}, err
+	}
+
+	var spec cgroupCounters
+	resources := inspect.Info.RuntimeSpec.Linux.Resources
+	if resources.CPU != nil && resources.CPU.Quota > 0 {
+		spec.cpuQuota, spec.cpuPeriod = resources.CPU.Quota, resources.CPU.Period
+	}
+	if resources.Memory != nil && resources.Memory.Limit > 0 {
+		spec.memoryMax = resources.Memory.Limit
+	}
+	return inspect.Info.Pid, spec, nil
+}
+
+// podResources - 하트비트에 넣을 Pod 자원 제한 상태 (staking 모드는 런타임 에이전트가 수집)
+func (s *StakerHost) podResources() ([]PodResourceUsage, bool) {
+	var usage []PodResourceUsage
+	var err error
+	if s.runtimeClient != nil {
+		usage, err = s.runtimeClient.PodResources()
+	} else {
+		usage, err = collectPodResources()
+	}
+	if err != nil {
+		log.Printf("⚠️ Pod 자원 제한 상태 수집 실패: %v", err)
+		return nil, false
+	}
+	return usage, true
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupLimitsSupported - cgroup v2 통합 계층에서 cpu/memory 컨트롤러를 하위 cgroup에 위임하는지
+func cgroupLimitsSupported() (bool, string) {
+	if check := checkCgroupV2(); check.Status != preflightPass {
+		return false, check.Detail
+	}
+	data, err := os.ReadFile(filepath.Join(cgroupRoot, "cgroup.subtree_control"))
+	if err != nil {
+		return false, fmt.Sprintf("cgroup.subtree_control 읽기 실패: %v", err)
+	}
+	delegated := strings.Fields(string(data))
+	for _, controller := range []string{"cpu", "memory"} {
+		found := false
+		for _, name := range delegated {
+			found = found || name == controller
+		}
+		if !found {
+			return false, controller + " 컨트롤러가 하위 cgroup에 위임되지 않음"
+		}
+	}
+	return true, ""
+}
+
+// readContainerCgroup - 컨테이너 프로세스의 cgroup(/proc/<pid>/cgroup) 제한과 카운터, 상위 Pod cgroup의 OOM kill
+func readContainerCgroup(pid int) (cgroupCounters, error) {
+	var counters cgroupCounters
+	if pid <= 0 {
+		return counters, fmt.Errorf("컨테이너 PID 없음")
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return counters, err
+	}
+	var path string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			path = filepath.Join(cgroupRoot, strings.TrimPrefix(line, "0::"))
+		}
+	}
+	if path == "" {
+		return counters, fmt.Errorf("cgroup v2 경로 없음 (PID %d)", pid)
+	}
+
+	if fields := strings.Fields(readCgroupFile(path, "cpu.max")); len(fields) == 2 && fields[0] != "max" {
+		counters.cpuQuota, _ = strconv.ParseInt(fields[0], 10, 64)
+		counters.cpuPeriod, _ = strconv.ParseInt(fields[1], 10, 64)
+	}
+	if value := readCgroupFile(path, "memory.max"); value != "max" {
+		counters.memoryMax, _ = strconv.ParseInt(value, 10, 64)
+	}
+	stat, err := readCgroupKeyValues(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return counters, err
+	}
+	counters.periods, counters.throttledPeriods, counters.throttledUsec = stat["nr_periods"], stat["nr_throttled"], stat["throttled_usec"]
+
+	// 컨테이너가 OOM으로 재시작되면 cgroup도 새로 만들어지므로 누적값은 Pod cgroup에서 읽음
+	if events, err := readCgroupKeyValues(filepath.Join(filepath.Dir(path), "memory.events")); err == nil {
+		counters.podOOMKills = events["oom_kill"]
+	}
+	return counters, nil
+}
+
+func readCgroupFile(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readCgroupKeyValues - "key value" 줄 형식 파일 (cpu.stat, memory.events)
+func readCgroupKeyValues(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values, scanner.Err()
+}
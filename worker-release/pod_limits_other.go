@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import "fmt"
+
+// cgroupLimitsSupported - cgroup 제한은 Linux 워커에서만 강제
+func cgroupLimitsSupported() (bool, string) {
+	return false, "cgroup limits are only enforced on linux"
+}
+
+// readContainerCgroup - cgroup 카운터는 Linux 워커에서만 지원
+func readContainerCgroup(pid int) (cgroupCounters, error) {
+	return cgroupCounters{}, fmt.Errorf("cgroup counters are only available on linux")
+}
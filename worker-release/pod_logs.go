@@ -0,0 +1,754 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pod 로그 보관 기본값
+const (
+	defaultPodLogDir            = "/var/lib/k3s-daas-agent/pod-logs"
+	defaultPodLogSourceDir      = "/var/log/pods" // kubelet이 CRI 형식으로 쓰는 컨테이너 로그
+	defaultPodLogSegmentKB      = 8 * 1024
+	defaultPodLogRetentionHours = 72
+	defaultPodLogMaxTotalMB     = 1024
+	defaultPodLogQueryLimit     = 1000
+	maxPodLogQueryLimit         = 10000
+
+	podLogScanInterval = 2 * time.Second
+	podLogMaxSegment   = 10 * time.Minute // 활성 세그먼트 최대 수명 (배송 지연 상한)
+	podLogReadChunk    = 4 << 20          // 파일당 한 번에 읽는 최대 바이트
+	podLogShipBatch    = 10               // 주기당 배송하는 최대 세그먼트 수
+
+	podLogActiveFile = "current.log"
+	podLogSealedExt  = ".log.gz"
+	podLogIndexFile  = "index.json"
+
+	podLogShipMaster = "master"
+)
+
+/*
+PodLogPolicy - Pod 로그 수집/보관 설정 (staker-config.json pod_logs)
+
+kubelet은 컨테이너가 재시작되면 이전 로그를 하나만 남기고 Pod가 삭제되면 모두 지우므로,
+/var/log/pods를 따라 읽어 Pod/컨테이너별 세그먼트로 따로 보관합니다.
+세그먼트는 segment_kb를 넘거나 10분이 지나면 gzip으로 봉인되고, 파일 이름이 곧 시간 범위 색인입니다.
+ship이 master면 봉인된 세그먼트를 마스터(/api/v1/logs/segments)로, http(s) 주소면 외부 수집기로 보냅니다.
+컨테이너마다 수집 속도(rate_limit_kbps, burst_kb)와 보관 용량(max_container_mb)을 제한해
+로그를 폭주시키는 Pod 하나가 디스크를 채우거나 다른 Pod의 로그를 밀어내지 못하게 합니다.
+*/
+type PodLogPolicy struct {
+	Dir            string `json:"dir"`              // 보관 경로 (기본 /var/lib/k3s-daas-agent/pod-logs)
+	SourceDir      string `json:"source_dir"`       // kubelet 로그 경로 (기본 /var/log/pods)
+	SegmentKB      int64  `json:"segment_kb"`       // 활성 세그먼트 봉인 크기 (기본 8MiB)
+	RetentionHours int    `json:"retention_hours"`  // 봉인된 세그먼트 보관 기간 (기본 72시간)
+	MaxTotalMB     int64  `json:"max_total_mb"`     // 보관 용량 상한, 넘으면 오래된 세그먼트부터 삭제 (기본 1GiB)
+	Ship           string `json:"ship"`             // "" (보내지 않음), master, 또는 외부 수집기 URL
+	RateLimitKBps  int64  `json:"rate_limit_kbps"`  // 컨테이너별 수집 속도 상한, 넘는 줄은 버림 (기본 1024)
+	BurstKB        int64  `json:"burst_kb"`         // 컨테이너별 순간 허용량 (기본 16MiB)
+	MaxContainerMB int64  `json:"max_container_mb"` // 컨테이너별 보관 상한, 넘으면 그 컨테이너의 오래된 세그먼트부터 삭제 (기본 256MiB)
+}
+
+/*
+applyPodLogDefaults - Pod 로그 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_POD_LOG_DIR: 보관 경로
+- K3S_DAAS_POD_LOG_RETENTION_HOURS: 보관 기간 (시간)
+- K3S_DAAS_POD_LOG_SHIP: master, off, 또는 외부 수집기 URL
+*/
+func applyPodLogDefaults(config *StakerHostConfig) error {
+	p := &config.PodLogs
+	if dir := os.Getenv("K3S_DAAS_POD_LOG_DIR"); dir != "" {
+		p.Dir = dir
+	}
+	if value := os.Getenv("K3S_DAAS_POD_LOG_RETENTION_HOURS"); value != "" {
+		hours, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("잘못된 K3S_DAAS_POD_LOG_RETENTION_HOURS: %s", value)
+		}
+		p.RetentionHours = hours
+	}
+	if ship := os.Getenv("K3S_DAAS_POD_LOG_SHIP"); ship != "" {
+		p.Ship = ship
+	}
+	if p.Ship == "off" {
+		p.Ship = ""
+	}
+
+	if p.Dir == "" {
+		p.Dir = defaultPodLogDir
+	}
+	if p.SourceDir == "" {
+		p.SourceDir = defaultPodLogSourceDir
+	}
+	if p.SegmentKB <= 0 {
+		p.SegmentKB = defaultPodLogSegmentKB
+	}
+	if p.RetentionHours <= 0 {
+		p.RetentionHours = defaultPodLogRetentionHours
+	}
+	if p.MaxTotalMB <= 0 {
+		p.MaxTotalMB = defaultPodLogMaxTotalMB
+	}
+	if p.RateLimitKBps <= 0 {
+		p.RateLimitKBps = defaultPodLogRateLimitKBps
+	}
+	if p.BurstKB <= 0 {
+		p.BurstKB = defaultPodLogBurstKB
+	}
+	if p.MaxContainerMB <= 0 {
+		p.MaxContainerMB = defaultPodLogMaxContainerMB
+	}
+	if p.Ship != "" && p.Ship != podLogShipMaster {
+		endpoint, err := url.Parse(p.Ship)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("잘못된 pod_logs.ship: %s (master 또는 http(s) URL)", p.Ship)
+		}
+	}
+	return nil
+}
+
+// PodLogEntry - 조회 결과 한 줄 (CRI 로그 형식: <시각> <stdout|stderr> <P|F> <메시지>)
+type PodLogEntry struct {
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Stream    string    `json:"stream"`
+	Partial   bool      `json:"partial,omitempty"` // 런타임이 긴 줄을 나눈 조각 (다음 항목과 이어짐)
+	Message   string    `json:"message"`
+}
+
+// PodLogQuery - 조회 조건 (Container가 비어 있으면 Pod의 모든 컨테이너)
+type PodLogQuery struct {
+	Namespace string
+	Pod       string
+	Container string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// PodLogResult - 조회 결과 (Limit을 넘으면 가장 최근 항목만 남기고 Truncated)
+type PodLogResult struct {
+	Entries   []PodLogEntry `json:"entries"`
+	Truncated bool          `json:"truncated"`
+	Stats     PodLogStats   `json:"stats"` // 조회한 노드의 수집/보관 상태
+}
+
+// PodLogStats - 수집/보관/배송 통계
+type PodLogStats struct {
+	Containers          int    `json:"containers"`
+	StoredBytes         int64  `json:"stored_bytes"`
+	Segments            int    `json:"segments"`
+	CapturedLines       int64  `json:"captured_lines"`
+	SealedTotal         int    `json:"sealed_total"`
+	ShippedTotal        int    `json:"shipped_total"`
+	PendingShip         int    `json:"pending_ship"`
+	ExpiredTotal        int    `json:"expired_total"`
+	ThrottledContainers int    `json:"throttled_containers"`
+	DroppedBytes        int64  `json:"dropped_bytes"` // 속도 제한으로 버리거나 컨테이너 상한으로 삭제한 바이트
+	LastShipError       string `json:"last_ship_error,omitempty"`
+	LastCaptureErr      string `json:"last_capture_error,omitempty"`
+}
+
+// podLogIndex - 재시작 후 중복 수집/재배송을 막는 기록 (index.json)
+type podLogIndex struct {
+	Offsets map[string]int64 `json:"offsets"` // kubelet 로그 파일 → 읽은 바이트
+	Shipped map[string]int64 `json:"shipped"` // 봉인 세그먼트 상대 경로 → 배송 시각
+}
+
+/*
+podLogStore - kubelet 로그를 따라 읽어 Pod/컨테이너별로 보관
+디렉터리 구조: <dir>/<namespace>/<pod>/<container>/{current.log, <첫 시각>-<마지막 시각>.log.gz}
+(시각은 유닉스 나노초, 조회 시 이름만 보고 범위 밖 세그먼트를 건너뜀)
+*/
+type podLogStore struct {
+	mu        sync.Mutex
+	policy    PodLogPolicy
+	index     podLogIndex
+	openedAt  map[string]time.Time    // 활성 세그먼트 디렉터리 → 첫 기록 시각
+	buckets   map[string]*logBucket   // 컨테이너 디렉터리 → 수집 속도 토큰 버킷
+	throttles map[string]*LogThrottle // <ns>/<pod>/<container>|사유 → 제한 기록
+	stats     PodLogStats
+}
+
+// newPodLogStore - 보관 디렉터리와 색인 복원
+func newPodLogStore(policy PodLogPolicy) *podLogStore {
+	store := &podLogStore{
+		policy:    policy,
+		openedAt:  make(map[string]time.Time),
+		buckets:   make(map[string]*logBucket),
+		throttles: make(map[string]*LogThrottle),
+	}
+	if err := loadStateFile(filepath.Join(policy.Dir, podLogIndexFile), &store.index); err != nil {
+		log.Printf("⚠️ Pod 로그 색인 읽기 실패, 처음부터 수집: %v", err)
+	}
+	if store.index.Offsets == nil {
+		store.index.Offsets = make(map[string]int64)
+	}
+	if store.index.Shipped == nil {
+		store.index.Shipped = make(map[string]int64)
+	}
+	return store
+}
+
+// snapshot - 현재 통계 (nil이면 빈 값)
+func (p *podLogStore) snapshot() PodLogStats {
+	if p == nil {
+		return PodLogStats{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// runPodLogCollector - 주기적 수집 → 봉인 → 보관 정리 → 배송 (K3s를 직접 실행하는 combined/runtime 모드)
+func (s *StakerHost) runPodLogCollector(ctx context.Context) {
+	if s.podLogs == nil {
+		return
+	}
+	log.Printf("📜 Pod 로그 수집 시작: %s → %s (보관 %d시간)", s.podLogs.policy.SourceDir, s.podLogs.policy.Dir, s.podLogs.policy.RetentionHours)
+
+	ticker := time.NewTicker(podLogScanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// 종료 전 남은 로그를 봉인해 다음 시작 전에도 조회/배송 가능하게 함
+			s.podLogs.capture()
+			s.podLogs.seal(true)
+			return
+		case <-ticker.C:
+		}
+
+		s.podLogs.capture()
+		s.podLogs.seal(false)
+		s.podLogs.expire()
+		if s.podLogs.policy.Ship != "" {
+			s.shipPodLogs()
+		}
+	}
+}
+
+/*
+capture - kubelet 로그 파일에서 새로 추가된 완전한 줄만 활성 세그먼트에 덧붙임
+kubelet이 파일을 회전시켜 크기가 기록된 위치보다 작아지면 처음부터 다시 읽습니다.
+*/
+func (p *podLogStore) capture() {
+	files, _ := filepath.Glob(filepath.Join(p.policy.SourceDir, "*", "*", "*.log"))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before := len(p.index.Offsets)
+	changed := false
+	seen := make(map[string]bool, len(files))
+	for _, source := range files {
+		seen[source] = true
+		namespace, pod, container, ok := podLogSource(p.policy.SourceDir, source)
+		if !ok {
+			continue
+		}
+		read, err := p.captureFile(source, filepath.Join(p.policy.Dir, namespace, pod, container))
+		if err != nil {
+			p.stats.LastCaptureErr = err.Error()
+		}
+		changed = changed || read
+	}
+	// 사라진 파일(Pod 삭제, 회전)의 읽기 위치 정리
+	for source := range p.index.Offsets {
+		if !seen[source] {
+			delete(p.index.Offsets, source)
+		}
+	}
+	if changed || len(p.index.Offsets) != before {
+		p.saveIndexLocked()
+	}
+}
+
+// podLogSource - <source>/<ns>_<pod>_<uid>/<container>/<재시작 횟수>.log 경로 해석
+func podLogSource(root, path string) (namespace, pod, container string, ok bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", "", "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	// 네임스페이스와 Pod 이름에는 '_'가 올 수 없으므로 UID 앞에서 나눔
+	fields := strings.SplitN(parts[0], "_", 3)
+	if len(fields) != 3 || fields[0] == "" || fields[1] == "" || parts[1] == "" {
+		return "", "", "", false
+	}
+	return fields[0], fields[1], parts[1], true
+}
+
+// captureFile - 새 줄을 덧붙였으면 true
+func (p *podLogStore) captureFile(source, dir string) (bool, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	offset := p.index.Offsets[source]
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return false, nil
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return false, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, podLogReadChunk))
+	if err != nil {
+		return false, err
+	}
+	// 쓰는 중인 마지막 줄은 다음 주기에 읽음
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return false, nil
+	}
+	data = data[:end+1]
+	read := int64(len(data))
+	data = p.limitRate(dir, data)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return false, err
+	}
+	out, err := os.OpenFile(filepath.Join(dir, podLogActiveFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return false, err
+	}
+	_, err = out.Write(data)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := p.openedAt[dir]; !ok {
+		p.openedAt[dir] = time.Now()
+	}
+	p.index.Offsets[source] = offset + read
+	p.stats.CapturedLines += int64(bytes.Count(data, []byte{'\n'}))
+	return true, nil
+}
+
+// seal - 크기/수명을 넘은 활성 세그먼트를 gzip으로 봉인 (force면 모두)
+func (p *podLogStore) seal(force bool) {
+	actives, _ := filepath.Glob(filepath.Join(p.policy.Dir, "*", "*", "*", podLogActiveFile))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, active := range actives {
+		dir := filepath.Dir(active)
+		info, err := os.Stat(active)
+		if err != nil {
+			continue
+		}
+		opened, known := p.openedAt[dir]
+		if !known {
+			// 재시작 전에 만들어진 세그먼트는 지금부터 수명 계산
+			p.openedAt[dir] = time.Now()
+			opened = p.openedAt[dir]
+		}
+		if !force && info.Size() < p.policy.SegmentKB*1024 && time.Since(opened) < podLogMaxSegment {
+			continue
+		}
+		if err := sealSegment(active); err != nil {
+			log.Printf("⚠️ Pod 로그 세그먼트 봉인 실패 (%s): %v", dir, err)
+			continue
+		}
+		delete(p.openedAt, dir)
+		p.stats.SealedTotal++
+	}
+}
+
+// sealSegment - current.log를 <첫 시각>-<마지막 시각>.log.gz로 압축 후 삭제
+func sealSegment(active string) error {
+	data, err := os.ReadFile(active)
+	if err != nil {
+		return err
+	}
+	var first, last time.Time
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		entry, ok := parseCRILine(string(line))
+		if !ok {
+			continue
+		}
+		if first.IsZero() {
+			first = entry.Time
+		}
+		last = entry.Time
+	}
+	if first.IsZero() {
+		return os.Remove(active)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%d%s", first.UnixNano(), last.UnixNano(), podLogSealedExt)
+	sealed := filepath.Join(filepath.Dir(active), name)
+	tmp := sealed + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, sealed); err != nil {
+		return err
+	}
+	return os.Remove(active)
+}
+
+// podLogSegment - 봉인된 세그먼트 (이름에서 해석한 시간 범위)
+type podLogSegment struct {
+	path      string
+	rel       string // <ns>/<pod>/<container>/<이름> (배송 기록 키)
+	namespace string
+	pod       string
+	container string
+	from      time.Time
+	to        time.Time
+	size      int64
+}
+
+// segments - 보관 중인 봉인 세그먼트 (마지막 시각 오름차순)
+func (p *podLogStore) segments(namespace, pod, container string) []podLogSegment {
+	pattern := filepath.Join(p.policy.Dir, globOrAll(namespace), globOrAll(pod), globOrAll(container), "*"+podLogSealedExt)
+	paths, _ := filepath.Glob(pattern)
+
+	var segments []podLogSegment
+	for _, path := range paths {
+		rel, err := filepath.Rel(p.policy.Dir, path)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 4 {
+			continue
+		}
+		bounds := strings.SplitN(strings.TrimSuffix(parts[3], podLogSealedExt), "-", 2)
+		if len(bounds) != 2 {
+			continue
+		}
+		from, err1 := strconv.ParseInt(bounds[0], 10, 64)
+		to, err2 := strconv.ParseInt(bounds[1], 10, 64)
+		info, err3 := os.Stat(path)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		segments = append(segments, podLogSegment{
+			path: path, rel: filepath.ToSlash(rel),
+			namespace: parts[0], pod: parts[1], container: parts[2],
+			from: time.Unix(0, from), to: time.Unix(0, to), size: info.Size(),
+		})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].to.Before(segments[j].to) })
+	return segments
+}
+
+func globOrAll(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return value
+}
+
+// expire - 보관 기간이 지났거나 용량 상한을 넘긴 봉인 세그먼트를 오래된 순서로 삭제
+func (p *podLogStore) expire() {
+	segments := p.segments("", "", "")
+	cutoff := time.Now().Add(-time.Duration(p.policy.RetentionHours) * time.Hour)
+	limit := p.policy.MaxTotalMB << 20
+	containerLimit := p.policy.MaxContainerMB << 20
+
+	var total int64
+	perContainer := make(map[string]int64)
+	for _, segment := range segments {
+		total += segment.size
+		perContainer[filepath.Dir(segment.path)] += segment.size
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	kept, pending := 0, 0
+	for _, segment := range segments {
+		dir := filepath.Dir(segment.path)
+		capped := perContainer[dir] > containerLimit
+		if segment.to.Before(cutoff) || total > limit || capped {
+			if err := os.Remove(segment.path); err == nil {
+				total -= segment.size
+				perContainer[dir] -= segment.size
+				if capped && !segment.to.Before(cutoff) {
+					p.throttleLocked(dir, logThrottleSizeCapped, 0, segment.size)
+				}
+				delete(p.index.Shipped, segment.rel)
+				p.stats.ExpiredTotal++
+				// 빈 컨테이너/Pod 디렉터리 정리 (비어 있지 않으면 Remove가 실패하므로 무시)
+				os.Remove(filepath.Dir(segment.path))
+				os.Remove(filepath.Dir(filepath.Dir(segment.path)))
+				continue
+			}
+		}
+		kept++
+		if _, shipped := p.index.Shipped[segment.rel]; !shipped && p.policy.Ship != "" {
+			pending++
+		}
+	}
+
+	actives, _ := filepath.Glob(filepath.Join(p.policy.Dir, "*", "*", "*", podLogActiveFile))
+	p.stats.Segments = kept
+	p.stats.StoredBytes = total
+	p.stats.Containers = len(actives)
+	p.stats.PendingShip = pending
+}
+
+// query - 조건에 맞는 로그 (봉인 세그먼트는 이름의 시간 범위로 먼저 거름)
+func (p *podLogStore) query(q PodLogQuery) (PodLogResult, error) {
+	if q.Limit <= 0 {
+		q.Limit = defaultPodLogQueryLimit
+	}
+	if q.Limit > maxPodLogQueryLimit {
+		q.Limit = maxPodLogQueryLimit
+	}
+
+	var entries []PodLogEntry
+	for _, segment := range p.segments(q.Namespace, q.Pod, q.Container) {
+		if (!q.Since.IsZero() && segment.to.Before(q.Since)) || (!q.Until.IsZero() && segment.from.After(q.Until)) {
+			continue
+		}
+		file, err := os.Open(segment.path)
+		if err != nil {
+			continue
+		}
+		zr, err := gzip.NewReader(file)
+		if err == nil {
+			entries = appendPodLogEntries(entries, zr, segment.namespace, segment.pod, segment.container, q)
+		}
+		file.Close()
+	}
+
+	actives, _ := filepath.Glob(filepath.Join(p.policy.Dir, globOrAll(q.Namespace), globOrAll(q.Pod), globOrAll(q.Container), podLogActiveFile))
+	for _, active := range actives {
+		file, err := os.Open(active)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(active)
+		entries = appendPodLogEntries(entries, file,
+			filepath.Base(filepath.Dir(filepath.Dir(dir))), filepath.Base(filepath.Dir(dir)), filepath.Base(dir), q)
+		file.Close()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	result := PodLogResult{Entries: entries, Stats: p.snapshot()}
+	if len(entries) > q.Limit {
+		result.Entries = entries[len(entries)-q.Limit:]
+		result.Truncated = true
+	}
+	if result.Entries == nil {
+		result.Entries = []PodLogEntry{}
+	}
+	return result, nil
+}
+
+func appendPodLogEntries(entries []PodLogEntry, r io.Reader, namespace, pod, container string, q PodLogQuery) []PodLogEntry {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		entry, ok := parseCRILine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if (!q.Since.IsZero() && entry.Time.Before(q.Since)) || (!q.Until.IsZero() && entry.Time.After(q.Until)) {
+			continue
+		}
+		entry.Namespace, entry.Pod, entry.Container = namespace, pod, container
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseCRILine - "2024-01-02T03:04:05.123456789Z stdout F message"
+func parseCRILine(line string) (PodLogEntry, bool) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 {
+		return PodLogEntry{}, false
+	}
+	ts, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return PodLogEntry{}, false
+	}
+	entry := PodLogEntry{Time: ts, Stream: fields[1], Partial: fields[2] == "P"}
+	if len(fields) == 4 {
+		entry.Message = fields[3]
+	}
+	return entry, true
+}
+
+// parsePodLogQuery - ?namespace=&pod=&container=&since=&until=&limit= (since/until은 RFC3339 또는 1h 같은 상대 시간)
+func parsePodLogQuery(values url.Values) (PodLogQuery, error) {
+	q := PodLogQuery{
+		Namespace: values.Get("namespace"),
+		Pod:       values.Get("pod"),
+		Container: values.Get("container"),
+	}
+	if q.Namespace == "" {
+		q.Namespace = "default"
+	}
+	if q.Pod == "" {
+		return q, fmt.Errorf("pod is required")
+	}
+	for _, name := range []string{q.Namespace, q.Pod, q.Container} {
+		if strings.ContainsAny(name, `/\*?[`) || name == "." || name == ".." {
+			return q, fmt.Errorf("invalid name %q", name)
+		}
+	}
+
+	var err error
+	if q.Since, err = parseLogTime(values.Get("since")); err != nil {
+		return q, fmt.Errorf("since: %v", err)
+	}
+	if q.Until, err = parseLogTime(values.Get("until")); err != nil {
+		return q, fmt.Errorf("until: %v", err)
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit <= 0 {
+			return q, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+	return q, nil
+}
+
+func parseLogTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-ago), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// podLogQuery - 로컬 보관소 조회, staking 모드는 런타임 에이전트에 위임
+func (s *StakerHost) podLogQuery(values url.Values) (PodLogResult, error) {
+	if s.runtimeClient != nil {
+		return s.runtimeClient.PodLogs(values)
+	}
+	q, err := parsePodLogQuery(values)
+	if err != nil {
+		return PodLogResult{}, err
+	}
+	if s.podLogs == nil {
+		return PodLogResult{}, fmt.Errorf("Pod 로그 수집이 꺼져 있음")
+	}
+	return s.podLogs.query(q)
+}
+
+/*
+shipPodLogs - 배송하지 않은 봉인 세그먼트를 마스터 또는 외부 수집기로 전송
+마스터는 Seal 토큰으로 노드를 확인하므로 등록 전(토큰 없음)에는 기다립니다.
+실패한 세그먼트는 보관 기간 안에서 다음 주기에 다시 보냅니다.
+*/
+func (s *StakerHost) shipPodLogs() {
+	store := s.podLogs
+	target := store.policy.Ship
+	if target == podLogShipMaster {
+		if s.stakingStatus.SealToken() == "" {
+			return
+		}
+		target = s.masterURL() + "/api/v1/logs/segments"
+	}
+
+	store.mu.Lock()
+	var pending []podLogSegment
+	for _, segment := range store.segments("", "", "") {
+		if _, shipped := store.index.Shipped[segment.rel]; !shipped {
+			pending = append(pending, segment)
+		}
+	}
+	store.mu.Unlock()
+
+	for i, segment := range pending {
+		if i == podLogShipBatch {
+			break
+		}
+		err := s.shipPodLogSegment(target, segment)
+
+		store.mu.Lock()
+		if err != nil {
+			store.stats.LastShipError = err.Error()
+			store.mu.Unlock()
+			log.Printf("⚠️ Pod 로그 배송 실패 (%s): %v", segment.rel, err)
+			return
+		}
+		store.index.Shipped[segment.rel] = time.Now().Unix()
+		store.stats.ShippedTotal++
+		store.stats.LastShipError = ""
+		store.saveIndexLocked()
+		store.mu.Unlock()
+	}
+}
+
+func (s *StakerHost) shipPodLogSegment(target string, segment podLogSegment) error {
+	data, err := os.ReadFile(segment.path)
+	if err != nil {
+		return err
+	}
+	resp, err := s.restyClient().SetTimeout(60*time.Second).R().
+		SetHeader("Content-Type", "application/gzip").
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetQueryParams(map[string]string{
+			"node_id":   s.config.NodeID,
+			"namespace": segment.namespace,
+			"pod":       segment.pod,
+			"container": segment.container,
+			"from":      strconv.FormatInt(segment.from.UnixNano(), 10),
+			"to":        strconv.FormatInt(segment.to.UnixNano(), 10),
+		}).
+		SetBody(data).
+		Post(target)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusCreated && resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+	return nil
+}
+
+func (p *podLogStore) saveIndexLocked() {
+	if err := writeStateFile(filepath.Join(p.policy.Dir, podLogIndexFile), p.index); err != nil {
+		p.stats.LastCaptureErr = err.Error()
+	}
+}
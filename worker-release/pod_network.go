@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// PodNetworkUsage - Pod 네트워크 네임스페이스의 누적 송수신 바이트 (하트비트 pod_network, 마스터가 테넌트별 과금 집계)
+type PodNetworkUsage struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	UID       string `json:"uid"`
+	RxBytes   uint64 `json:"rx_bytes"` // Pod 입장에서 수신 (ingress)
+	TxBytes   uint64 `json:"tx_bytes"` // Pod 입장에서 송신 (egress)
+}
+
+/*
+collectPodNetwork - 실행 중인 Pod 샌드박스별 네트워크 카운터 수집
+샌드박스 프로세스의 /proc/<pid>/net/dev는 Pod 네트워크 네임스페이스 기준이므로 lo를 뺀 인터페이스 합이
+Pod의 송수신량입니다. hostNetwork Pod는 노드 전체 트래픽이 잡히므로 제외합니다.
+카운터는 누적값이며, 샌드박스가 다시 만들어져 값이 줄어드는 경우는 마스터가 리셋으로 처리합니다.
+*/
+func collectPodNetwork() ([]PodNetworkUsage, error) {
+	sandboxes, err := listSandboxes()
+	if err != nil {
+		return nil, err
+	}
+	hostNetns, _ := os.Readlink("/proc/self/ns/net")
+
+	usage := []PodNetworkUsage{}
+	for _, sandbox := range sandboxes {
+		if sandbox.State != "SANDBOX_READY" {
+			continue
+		}
+		pid := sandboxPid(sandbox.ID)
+		if pid <= 0 {
+			continue
+		}
+		if netns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid)); err != nil || netns == hostNetns {
+			continue
+		}
+		rx, tx, err := readNetDev(fmt.Sprintf("/proc/%d/net/dev", pid))
+		if err != nil {
+			log.Printf("⚠️ Pod 네트워크 카운터 읽기 실패 (%s/%s): %v", sandbox.Metadata.Namespace, sandbox.Metadata.Name, err)
+			continue
+		}
+		usage = append(usage, PodNetworkUsage{
+			Namespace: sandbox.Metadata.Namespace,
+			Pod:       sandbox.Metadata.Name,
+			UID:       sandbox.Metadata.UID,
+			RxBytes:   rx,
+			TxBytes:   tx,
+		})
+	}
+	return usage, nil
+}
+
+// sandboxPid - 샌드박스(pause) 프로세스 PID (crictl inspectp의 info.pid)
+func sandboxPid(sandboxID string) int {
+	output, err := exec.Command("k3s", "crictl", "inspectp", sandboxID).Output()
+	if err != nil {
+		return 0
+	}
+	var sandbox struct {
+		Info struct {
+			Pid int `json:"pid"`
+		} `json:"info"`
+	}
+	json.Unmarshal(output, &sandbox)
+	return sandbox.Info.Pid
+}
+
+// readNetDev - /proc/net/dev 형식에서 lo를 제외한 인터페이스의 수신/송신 바이트 합
+func readNetDev(path string) (uint64, uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var rx, tx uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(name) == "lo" {
+			continue // 헤더 두 줄과 루프백
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		received, _ := strconv.ParseUint(fields[0], 10, 64)
+		transmitted, _ := strconv.ParseUint(fields[8], 10, 64)
+		rx += received
+		tx += transmitted
+	}
+	return rx, tx, scanner.Err()
+}
+
+// podNetwork - 하트비트에 넣을 Pod 네트워크 사용량 (staking 모드는 런타임 에이전트가 수집)
+func (s *StakerHost) podNetwork() ([]PodNetworkUsage, bool) {
+	var usage []PodNetworkUsage
+	var err error
+	if s.runtimeClient != nil {
+		usage, err = s.runtimeClient.PodNetwork()
+	} else {
+		usage, err = collectPodNetwork()
+	}
+	if err != nil {
+		log.Printf("⚠️ Pod 네트워크 사용량 수집 실패: %v", err)
+		return nil, false
+	}
+	return usage, true
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Pod 정상 종료 (워커가 직접 Pod을 내리는 경로: 노드 종료, 슬래싱, 고아 Pod 정리)
+
+kubectl delete pod는 kubelet이 업스트림과 같이 처리하지만, 워커가 CRI로 직접 샌드박스를 내리면
+preStop 훅과 유예 시간이 무시되었습니다. kubelet이 컨테이너에 남기는 어노테이션으로 같은 순서를 재현합니다.
+ 1. preStop 훅 (exec / httpGet / sleep) - 유예 시간 안에서 실행
+ 2. CRI StopContainer(timeout=남은 유예 시간) - 이미지 STOPSIGNAL(기본 SIGTERM) 후 시간이 지나면 SIGKILL
+ 3. 샌드박스 중단 및 제거
+*/
+const (
+	defaultTerminationGraceSeconds = 30 // 업스트림 terminationGracePeriodSeconds 기본값
+	defaultShutdownTimeoutSeconds  = 90 // 런타임 에이전트 요청 타임아웃(2분)보다 짧게
+	minStopAfterPreStop            = 2 * time.Second
+
+	annotationPreStopHandler = "io.kubernetes.container.preStopHandler"
+	annotationGracePeriod    = "io.kubernetes.pod.terminationGracePeriod"
+	annotationContainerPorts = "io.kubernetes.container.ports"
+	preStopHTTPResponseLimit = 10 << 10
+)
+
+// TerminationConfig - Pod 종료 유예 시간 설정
+type TerminationConfig struct {
+	DefaultGraceSeconds    int `json:"default_grace_seconds"`    // Pod이 유예 시간을 알리지 않을 때 (기본 30)
+	ShutdownTimeoutSeconds int `json:"shutdown_timeout_seconds"` // 노드 종료 시 모든 Pod 종료를 기다리는 상한 (기본 90)
+}
+
+/*
+applyTerminationDefaults - Pod 종료 설정 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_TERMINATION_GRACE: 기본 유예 시간 (초)
+- K3S_DAAS_SHUTDOWN_TIMEOUT: 노드 종료 시 전체 대기 상한 (초)
+*/
+func applyTerminationDefaults(config *StakerHostConfig) error {
+	t := &config.Termination
+	for env, target := range map[string]*int{
+		"K3S_DAAS_TERMINATION_GRACE": &t.DefaultGraceSeconds,
+		"K3S_DAAS_SHUTDOWN_TIMEOUT":  &t.ShutdownTimeoutSeconds,
+	} {
+		if value := os.Getenv(env); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 0 {
+				return fmt.Errorf("잘못된 %s: %s", env, value)
+			}
+			*target = seconds
+		}
+	}
+
+	if t.DefaultGraceSeconds <= 0 {
+		t.DefaultGraceSeconds = defaultTerminationGraceSeconds
+	}
+	if t.ShutdownTimeoutSeconds <= 0 {
+		t.ShutdownTimeoutSeconds = defaultShutdownTimeoutSeconds
+	}
+	return nil
+}
+
+func (t TerminationConfig) defaultGrace() time.Duration {
+	return time.Duration(t.DefaultGraceSeconds) * time.Second
+}
+
+func (t TerminationConfig) shutdownTimeout() time.Duration {
+	return time.Duration(t.ShutdownTimeoutSeconds) * time.Second
+}
+
+// lifecycleHandler - core/v1 LifecycleHandler (kubelet이 preStopHandler 어노테이션에 JSON으로 기록)
+type lifecycleHandler struct {
+	Exec *struct {
+		Command []string `json:"command"`
+	} `json:"exec"`
+	HTTPGet *struct {
+		Path        string          `json:"path"`
+		Port        json.RawMessage `json:"port"` // 숫자 또는 포트 이름
+		Host        string          `json:"host"`
+		Scheme      string          `json:"scheme"`
+		HTTPHeaders []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"httpHeaders"`
+	} `json:"httpGet"`
+	Sleep *struct {
+		Seconds int64 `json:"seconds"`
+	} `json:"sleep"`
+}
+
+// criContainer - crictl inspect 중 종료에 필요한 필드
+type criContainer struct {
+	Status struct {
+		ID       string `json:"id"`
+		State    string `json:"state"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"status"`
+}
+
+func (c criContainer) gracePeriod() (time.Duration, bool) {
+	seconds, err := strconv.ParseInt(c.Status.Annotations[annotationGracePeriod], 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func (c criContainer) preStop() *lifecycleHandler {
+	raw := c.Status.Annotations[annotationPreStopHandler]
+	if raw == "" {
+		return nil
+	}
+	var handler lifecycleHandler
+	if err := json.Unmarshal([]byte(raw), &handler); err != nil {
+		log.Printf("⚠️ preStop 훅 해석 실패 (%s): %v", c.Status.Metadata.Name, err)
+		return nil
+	}
+	return &handler
+}
+
+// resolvePort - httpGet 포트 (이름이면 컨테이너 포트 어노테이션에서 찾음)
+func (c criContainer) resolvePort(raw json.RawMessage) (int, error) {
+	var number int
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number, nil
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return 0, fmt.Errorf("잘못된 포트: %s", raw)
+	}
+	if number, err := strconv.Atoi(name); err == nil {
+		return number, nil
+	}
+	var ports []struct {
+		Name          string `json:"name"`
+		ContainerPort int    `json:"containerPort"`
+	}
+	json.Unmarshal([]byte(c.Status.Annotations[annotationContainerPorts]), &ports)
+	for _, port := range ports {
+		if port.Name == name {
+			return port.ContainerPort, nil
+		}
+	}
+	return 0, fmt.Errorf("포트 이름 %q를 찾을 수 없습니다", name)
+}
+
+// sandboxContainers - 샌드박스의 실행 중인 컨테이너
+func sandboxContainers(sandboxID string) ([]criContainer, error) {
+	output, err := exec.Command("k3s", "crictl", "ps", "-q", "--pod", sandboxID, "--state", "running").Output()
+	if err != nil {
+		return nil, fmt.Errorf("crictl ps 실패: %v", err)
+	}
+	var containers []criContainer
+	for _, id := range strings.Fields(string(output)) {
+		data, err := exec.Command("k3s", "crictl", "inspect", id).Output()
+		if err != nil {
+			continue // 그 사이 종료됨
+		}
+		var container criContainer
+		if err := json.Unmarshal(data, &container); err != nil {
+			return nil, fmt.Errorf("crictl inspect 파싱 실패: %v", err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// sandboxIP - httpGet 훅의 기본 호스트 (Pod IP)
+func sandboxIP(sandboxID string) string {
+	output, err := exec.Command("k3s", "crictl", "inspectp", sandboxID).Output()
+	if err != nil {
+		return ""
+	}
+	var sandbox struct {
+		Status struct {
+			Network struct {
+				IP string `json:"ip"`
+			} `json:"network"`
+		} `json:"status"`
+	}
+	json.Unmarshal(output, &sandbox)
+	return sandbox.Status.Network.IP
+}
+
+/*
+terminateSandbox - Pod 샌드박스를 업스트림 순서로 종료 후 제거
+유예 시간은 Pod의 terminationGracePeriodSeconds(없으면 defaultGrace)이며 limit을 넘지 않습니다.
+*/
+func terminateSandbox(sandboxID string, defaultGrace, limit time.Duration) error {
+	containers, err := sandboxContainers(sandboxID)
+	if err != nil {
+		return err
+	}
+
+	grace := defaultGrace
+	for _, container := range containers {
+		if podGrace, ok := container.gracePeriod(); ok {
+			grace = podGrace
+			break
+		}
+	}
+	if limit > 0 && grace > limit {
+		grace = limit
+	}
+	deadline := time.Now().Add(grace)
+
+	podIP := ""
+	for _, container := range containers {
+		if container.Status.Annotations[annotationPreStopHandler] != "" {
+			podIP = sandboxIP(sandboxID)
+			break
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, container := range containers {
+		wg.Add(1)
+		go func(container criContainer) {
+			defer wg.Done()
+			stopPodContainer(container, podIP, deadline)
+		}(container)
+	}
+	wg.Wait()
+
+	if output, err := exec.Command("k3s", "crictl", "stopp", sandboxID).CombinedOutput(); err != nil {
+		return fmt.Errorf("crictl stopp 실패: %v (%s)", err, output)
+	}
+	if output, err := exec.Command("k3s", "crictl", "rmp", sandboxID).CombinedOutput(); err != nil {
+		return fmt.Errorf("crictl rmp 실패: %v (%s)", err, output)
+	}
+	return nil
+}
+
+// stopPodContainer - preStop 훅 실행 후 남은 유예 시간으로 CRI 중단 (SIGTERM → SIGKILL)
+func stopPodContainer(container criContainer, podIP string, deadline time.Time) {
+	name := container.Status.Metadata.Name
+	if handler := container.preStop(); handler != nil {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		if err := runPreStop(ctx, container, handler, podIP); err != nil {
+			log.Printf("⚠️ preStop 훅 실패 (%s): %v", name, err)
+		}
+		cancel()
+	}
+
+	// kubelet과 같이 preStop이 유예 시간을 다 써도 최소 2초는 SIGTERM 처리 시간을 줌
+	remaining := time.Until(deadline)
+	if remaining < minStopAfterPreStop {
+		remaining = minStopAfterPreStop
+	}
+	timeout := strconv.Itoa(int(remaining.Round(time.Second) / time.Second))
+	if output, err := exec.Command("k3s", "crictl", "stop", "--timeout", timeout, container.Status.ID).CombinedOutput(); err != nil {
+		log.Printf("⚠️ 컨테이너 중단 실패 (%s): %v (%s)", name, err, strings.TrimSpace(string(output)))
+	}
+}
+
+// runPreStop - exec / httpGet / sleep 훅 (ctx 기한 = 유예 시간 만료)
+func runPreStop(ctx context.Context, container criContainer, handler *lifecycleHandler, podIP string) error {
+	switch {
+	case handler.Exec != nil:
+		if len(handler.Exec.Command) == 0 {
+			return fmt.Errorf("exec 훅에 명령이 없습니다")
+		}
+		timeout := "0"
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = strconv.Itoa(int(time.Until(deadline).Round(time.Second) / time.Second))
+		}
+		args := append([]string{"crictl", "exec", "--sync", "--timeout", timeout, container.Status.ID}, handler.Exec.Command...)
+		if output, err := exec.CommandContext(ctx, "k3s", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("exec 훅 실패: %v (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+
+	case handler.HTTPGet != nil:
+		get := handler.HTTPGet
+		port, err := container.resolvePort(get.Port)
+		if err != nil {
+			return err
+		}
+		host := get.Host
+		if host == "" {
+			host = podIP
+		}
+		if host == "" {
+			return fmt.Errorf("Pod IP를 알 수 없습니다")
+		}
+		scheme := strings.ToLower(get.Scheme)
+		if scheme == "" {
+			scheme = "http"
+		}
+		path := get.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		target := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		for _, header := range get.HTTPHeaders {
+			req.Header.Add(header.Name, header.Value)
+		}
+		// kubelet과 같이 HTTPS 훅은 인증서를 검증하지 않음
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("httpGet 훅 실패: %v", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, io.LimitReader(resp.Body, preStopHTTPResponseLimit))
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("httpGet 훅 응답 HTTP %d", resp.StatusCode)
+		}
+		return nil
+
+	case handler.Sleep != nil:
+		select {
+		case <-time.After(time.Duration(handler.Sleep.Seconds) * time.Second):
+		case <-ctx.Done():
+		}
+		return nil
+	}
+	return fmt.Errorf("지원하지 않는 preStop 훅")
+}
+
+/*
+terminateAllPods - 노드 종료 시 실행 중인 모든 Pod을 병렬로 정상 종료
+모든 Pod은 config의 shutdown 상한 안에서 각자의 유예 시간을 받습니다.
+*/
+func terminateAllPods(config TerminationConfig) error {
+	sandboxes, err := listSandboxes()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, sandbox := range sandboxes {
+		if sandbox.State != "SANDBOX_READY" {
+			continue
+		}
+		wg.Add(1)
+		go func(sandbox criSandbox) {
+			defer wg.Done()
+			pod := sandbox.assignment()
+			if err := terminateSandbox(pod.SandboxID, config.defaultGrace(), config.shutdownTimeout()); err != nil {
+				log.Printf("⚠️ Pod 종료 실패 %s/%s: %v", pod.Namespace, pod.Name, err)
+				return
+			}
+			log.Printf("🛑 Pod 종료: %s/%s", pod.Namespace, pod.Name)
+		}(sandbox)
+	}
+	wg.Wait()
+	return nil
+}
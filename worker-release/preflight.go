@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 프리플라이트 검사 기본값
+const (
+	defaultMinK3sVersion = "v1.26.0"
+	kubeletPort          = 10250
+	flannelVXLANPort     = 8472
+
+	preflightCritical = "critical" // 실패 시 노드 등록 거부
+	preflightWarning  = "warning"  // 결과에만 표시
+
+	preflightPass    = "pass"
+	preflightFail    = "fail"
+	preflightIgnored = "ignored" // 실패했지만 preflight_ignore로 무시
+	preflightSkip    = "skip"    // 이 플랫폼에서는 확인 불가
+)
+
+// PreflightCheck - 프리플라이트 검사 하나의 결과
+type PreflightCheck struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail"`
+}
+
+/*
+PreflightReport - 콜드 스타트 프리플라이트 결과 (통과/실패 매트릭스)
+Passed가 false면 무시되지 않은 필수(critical) 검사가 실패한 것이며,
+워커는 Nautilus에 등록하지 않아 스케줄 가능한 노드로 클러스터에 합류하지 않습니다.
+*/
+type PreflightReport struct {
+	Checks    []PreflightCheck `json:"checks"`
+	Passed    bool             `json:"passed"`
+	Blocking  []string         `json:"blocking,omitempty"`
+	CheckedAt time.Time        `json:"checked_at"`
+}
+
+/*
+applyPreflightDefaults - 프리플라이트 설정 환경변수 덮어쓰기
+- K3S_DAAS_PREFLIGHT_IGNORE: 실패해도 등록을 막지 않을 검사 이름 (쉼표 구분, all이면 전체)
+- K3S_DAAS_MIN_K3S_VERSION: 허용하는 최소 k3s 버전 (기본 v1.26.0)
+*/
+func applyPreflightDefaults(config *StakerHostConfig) error {
+	if value := os.Getenv("K3S_DAAS_PREFLIGHT_IGNORE"); value != "" {
+		config.PreflightIgnore = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				config.PreflightIgnore = append(config.PreflightIgnore, name)
+			}
+		}
+	}
+	if value := os.Getenv("K3S_DAAS_MIN_K3S_VERSION"); value != "" {
+		config.MinK3sVersion = value
+	}
+	if config.MinK3sVersion == "" {
+		config.MinK3sVersion = defaultMinK3sVersion
+	}
+	if _, ok := parseK3sVersion(config.MinK3sVersion); !ok {
+		return fmt.Errorf("잘못된 min_k3s_version: %q", config.MinK3sVersion)
+	}
+	return nil
+}
+
+/*
+runPreflight - k3s 바이너리, 커널 기능, cgroup, swap, 포트 검사
+시뮬레이션 모드로 조용히 넘어가는 대신 무엇이 빠졌는지 운영자에게 표로 보여줍니다.
+*/
+func runPreflight(config *StakerHostConfig, mode string) *PreflightReport {
+	checks := []PreflightCheck{checkK3sBinary(config.MinK3sVersion)}
+	checks = append(checks, kernelPreflightChecks()...)
+	checks = append(checks, checkPorts(config, mode)...)
+
+	ignored := map[string]bool{}
+	for _, name := range config.PreflightIgnore {
+		ignored[name] = true
+	}
+
+	report := &PreflightReport{Checks: checks, Passed: true, CheckedAt: time.Now()}
+	for i := range report.Checks {
+		check := &report.Checks[i]
+		if check.Status != preflightFail {
+			continue
+		}
+		if ignored[check.Name] || ignored["all"] {
+			check.Status = preflightIgnored
+			continue
+		}
+		if check.Severity == preflightCritical {
+			report.Passed = false
+			report.Blocking = append(report.Blocking, check.Name)
+		}
+	}
+	return report
+}
+
+// matrix - 검사 결과 표 (로그와 preflight 서브커맨드 출력용)
+func (r *PreflightReport) matrix() string {
+	var out strings.Builder
+	table := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CHECK\tSEVERITY\tRESULT\tDETAIL")
+	for _, check := range r.Checks {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", check.Name, check.Severity, strings.ToUpper(check.Status), check.Detail)
+	}
+	table.Flush()
+	return out.String()
+}
+
+// log - 결과 표와 판정을 로그로 출력
+func (r *PreflightReport) log() {
+	log.Printf("🧪 프리플라이트 검사 결과:")
+	for _, line := range strings.Split(strings.TrimRight(r.matrix(), "\n"), "\n") {
+		log.Printf("   %s", line)
+	}
+	if r.Passed {
+		log.Printf("✅ 프리플라이트 통과")
+	} else {
+		log.Printf("❌ 필수 검사 실패: %s (K3S_DAAS_PREFLIGHT_IGNORE로 무시 가능)", strings.Join(r.Blocking, ", "))
+	}
+}
+
+// err - 등록을 막는 실패가 있으면 오류
+func (r *PreflightReport) err() error {
+	if r.Passed {
+		return nil
+	}
+	return fmt.Errorf("프리플라이트 필수 검사 실패 (%s): 스케줄 가능한 노드로 등록하지 않습니다 (K3S_DAAS_PREFLIGHT_IGNORE로 무시 가능)",
+		strings.Join(r.Blocking, ", "))
+}
+
+/*
+preflightReport - 현재 노드의 프리플라이트 결과
+staking 모드에서는 k3s를 실행하는 런타임 에이전트의 결과를 조회합니다.
+*/
+func (s *StakerHost) preflightReport() (*PreflightReport, error) {
+	if s.runtimeClient != nil {
+		return s.runtimeClient.Preflight()
+	}
+	if s.preflight == nil {
+		s.preflight = runPreflight(s.config, workerMode())
+		s.preflight.log()
+	}
+	return s.preflight, nil
+}
+
+// findK3sBinary - K3S_BINARY_PATH, PATH(k3s, k3s.exe), 일반적인 설치 위치 순으로 검색
+func findK3sBinary() (string, error) {
+	if k3sPath := os.Getenv("K3S_BINARY_PATH"); k3sPath != "" {
+		if _, err := os.Stat(k3sPath); err == nil {
+			return k3sPath, nil
+		}
+	}
+
+	for _, name := range []string{"k3s", "k3s.exe"} {
+		if k3sPath, err := exec.LookPath(name); err == nil {
+			return k3sPath, nil
+		}
+	}
+
+	commonPaths := []string{
+		"/usr/local/bin/k3s",
+		"/usr/bin/k3s",
+		"/opt/k3s/bin/k3s",
+		"./k3s",
+	}
+	for _, path := range commonPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("k3s binary not found in PATH or common locations")
+}
+
+var k3sVersionPattern = regexp.MustCompile(`v(\d+)\.(\d+)\.(\d+)`)
+
+// parseK3sVersion - "k3s version v1.28.5+k3s1 (...)"에서 [major, minor, patch] 추출
+func parseK3sVersion(output string) ([3]int, bool) {
+	var version [3]int
+	match := k3sVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return version, false
+	}
+	for i := range version {
+		version[i], _ = strconv.Atoi(match[i+1])
+	}
+	return version, true
+}
+
+// checkK3sBinary - k3s 바이너리 존재 및 최소 버전 확인
+func checkK3sBinary(minVersion string) PreflightCheck {
+	check := PreflightCheck{Name: "k3s-binary", Severity: preflightCritical, Status: preflightFail}
+
+	path, err := findK3sBinary()
+	if err != nil {
+		check.Detail = "k3s 바이너리 없음 (curl -sfL https://get.k3s.io | sh - 또는 K3S_BINARY_PATH)"
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		check.Detail = fmt.Sprintf("%s --version 실패: %v", path, err)
+		return check
+	}
+	version, ok := parseK3sVersion(string(output))
+	if !ok {
+		check.Detail = fmt.Sprintf("%s: 버전을 확인할 수 없음", path)
+		return check
+	}
+
+	minimum, _ := parseK3sVersion(minVersion)
+	current := fmt.Sprintf("v%d.%d.%d", version[0], version[1], version[2])
+	for i := range version {
+		if version[i] != minimum[i] {
+			if version[i] < minimum[i] {
+				check.Detail = fmt.Sprintf("%s %s (최소 %s 필요)", path, current, minVersion)
+				return check
+			}
+			break
+		}
+	}
+
+	check.Status = preflightPass
+	check.Detail = fmt.Sprintf("%s %s", path, current)
+	return check
+}
+
+/*
+checkPorts - kubelet(10250/tcp), flannel VXLAN(8472/udp), 상태 서버 포트가 비어 있는지 확인
+상태 서버는 combined 모드에서만 같은 프로세스가 열기 때문에 그때만 검사합니다.
+*/
+func checkPorts(config *StakerHostConfig, mode string) []PreflightCheck {
+	checks := []PreflightCheck{
+		checkPort("port-kubelet", preflightCritical, "tcp", fmt.Sprintf(":%d", kubeletPort)),
+		checkPort("port-flannel-vxlan", preflightWarning, "udp", fmt.Sprintf(":%d", flannelVXLANPort)),
+	}
+	if mode == modeCombined {
+		checks = append(checks, checkPort("port-status-server", preflightCritical, "tcp", config.ListenAddr))
+	}
+	return checks
+}
+
+// checkPort - 잠깐 바인드해 보고 바로 닫음
+func checkPort(name, severity, network, addr string) PreflightCheck {
+	check := PreflightCheck{Name: name, Severity: severity, Status: preflightPass, Detail: addr + "/" + network + " 사용 가능"}
+
+	var err error
+	if network == "udp" {
+		var conn net.PacketConn
+		if conn, err = net.ListenPacket(network, addr); err == nil {
+			conn.Close()
+		}
+	} else {
+		var listener net.Listener
+		if listener, err = net.Listen(network, addr); err == nil {
+			listener.Close()
+		}
+	}
+	if err != nil {
+		check.Status = preflightFail
+		check.Detail = fmt.Sprintf("%s/%s 사용 중: %v", addr, network, err)
+	}
+	return check
+}
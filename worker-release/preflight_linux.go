@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// kubelet이 Pod 자원 제한에 사용하는 cgroup v2 컨트롤러
+var requiredCgroupControllers = []string{"cpu", "memory", "pids"}
+
+// kernelPreflightChecks - cgroup v2, br_netfilter, ip_forward, swap
+func kernelPreflightChecks() []PreflightCheck {
+	return []PreflightCheck{
+		checkCgroupV2(),
+		checkBridgeNetfilter(),
+		checkIPForward(),
+		checkSwap(),
+	}
+}
+
+// checkCgroupV2 - 통합 계층(/sys/fs/cgroup/cgroup.controllers)과 필수 컨트롤러 확인
+func checkCgroupV2() PreflightCheck {
+	check := PreflightCheck{Name: "cgroup-v2", Severity: preflightCritical, Status: preflightFail}
+
+	data, err := os.ReadFile("/sys/fs/cgroup/cgroup.controllers")
+	if err != nil {
+		check.Detail = "cgroup v2 통합 계층이 마운트되지 않음 (systemd.unified_cgroup_hierarchy=1)"
+		return check
+	}
+
+	available := map[string]bool{}
+	for _, controller := range strings.Fields(string(data)) {
+		available[controller] = true
+	}
+	var missing []string
+	for _, controller := range requiredCgroupControllers {
+		if !available[controller] {
+			missing = append(missing, controller)
+		}
+	}
+	if len(missing) > 0 {
+		check.Detail = "컨트롤러 없음: " + strings.Join(missing, ", ")
+		return check
+	}
+
+	check.Status = preflightPass
+	check.Detail = strings.TrimSpace(string(data))
+	return check
+}
+
+// checkBridgeNetfilter - br_netfilter 모듈 로드 및 bridge-nf-call-iptables=1 (Service 트래픽이 iptables를 거치도록)
+func checkBridgeNetfilter() PreflightCheck {
+	check := PreflightCheck{Name: "br-netfilter", Severity: preflightCritical, Status: preflightFail}
+
+	value, err := readSysctl("/proc/sys/net/bridge/bridge-nf-call-iptables")
+	if err != nil {
+		check.Detail = "br_netfilter 모듈이 로드되지 않음 (modprobe br_netfilter)"
+		return check
+	}
+	if value != "1" {
+		check.Detail = "net.bridge.bridge-nf-call-iptables=" + value + " (1 필요)"
+		return check
+	}
+
+	check.Status = preflightPass
+	check.Detail = "net.bridge.bridge-nf-call-iptables=1"
+	return check
+}
+
+// checkIPForward - k3s가 시작 시 켜지만 sysctl 잠금 환경에서는 실패하므로 미리 알림
+func checkIPForward() PreflightCheck {
+	check := PreflightCheck{Name: "ip-forward", Severity: preflightWarning, Status: preflightPass}
+
+	value, err := readSysctl("/proc/sys/net/ipv4/ip_forward")
+	switch {
+	case err != nil:
+		check.Status = preflightFail
+		check.Detail = fmt.Sprintf("net.ipv4.ip_forward 확인 실패: %v", err)
+	case value != "1":
+		check.Status = preflightFail
+		check.Detail = "net.ipv4.ip_forward=" + value
+	default:
+		check.Detail = "net.ipv4.ip_forward=1"
+	}
+	return check
+}
+
+// checkSwap - kubelet은 fail-swap-on=false로 실행되지만 swap은 메모리 축출 판단을 늦춤
+func checkSwap() PreflightCheck {
+	check := PreflightCheck{Name: "swap", Severity: preflightWarning, Status: preflightPass, Detail: "swap 꺼짐"}
+
+	data, err := os.ReadFile("/proc/swaps")
+	if err != nil {
+		check.Status = preflightSkip
+		check.Detail = fmt.Sprintf("/proc/swaps 읽기 실패: %v", err)
+		return check
+	}
+	// 첫 줄은 헤더
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) > 1 {
+		check.Status = preflightFail
+		check.Detail = fmt.Sprintf("swap 장치 %d개 사용 중 (swapoff -a 권장)", len(lines)-1)
+	}
+	return check
+}
+
+func readSysctl(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
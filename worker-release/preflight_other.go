@@ -0,0 +1,19 @@
+//go:build !linux
+
+package main
+
+// kernelPreflightChecks - 커널 기능 검사는 Linux 워커에서만 가능 (건너뜀으로 표시)
+func kernelPreflightChecks() []PreflightCheck {
+	var checks []PreflightCheck
+	for _, check := range []struct{ name, severity string }{
+		{"cgroup-v2", preflightCritical},
+		{"br-netfilter", preflightCritical},
+		{"ip-forward", preflightWarning},
+		{"swap", preflightWarning},
+	} {
+		checks = append(checks, PreflightCheck{
+			Name: check.name, Severity: check.severity, Status: preflightSkip, Detail: "Linux에서만 확인 가능",
+		})
+	}
+	return checks
+}
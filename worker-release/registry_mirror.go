@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+registryMirror - 마스터가 안내하는 이미지 미러
+seal_auth가 true면 마스터 내장 pull-through 캐시로, 노드 ID / Seal 토큰으로 인증합니다.
+*/
+type registryMirror struct {
+	Endpoint string `json:"endpoint"`
+	SealAuth bool   `json:"seal_auth"`
+}
+
+/*
+fetchRegistryMirrors - 미러 목록 결정
+마스터 설정 번들 또는 로컬 설정의 registry_mirrors(예: 사내 Harbor 프록시 프로젝트)가 있으면 그대로 쓰고,
+없으면 마스터에 조회합니다. 미러가 없으면 K3s 기본 동작(Docker Hub 직접 pull)을 유지합니다.
+*/
+func (s *StakerHost) fetchRegistryMirrors() ([]registryMirror, error) {
+	if endpoints := s.registryMirrorEndpoints(); len(endpoints) > 0 {
+		mirrors := make([]registryMirror, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			mirrors = append(mirrors, registryMirror{Endpoint: endpoint})
+		}
+		return mirrors, nil
+	}
+
+	var result struct {
+		Mirrors []registryMirror `json:"mirrors"`
+	}
+	resp, err := s.restyClient().SetTimeout(10*time.Second).R().
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetQueryParam("node_id", s.config.NodeID).
+		SetResult(&result).
+		Get(s.config.NautilusEndpoint + "/api/v1/registry/mirror")
+	if err != nil {
+		return nil, fmt.Errorf("레지스트리 미러 조회 실패: %v", err)
+	}
+	// 미러 안내를 지원하지 않는 이전 버전 마스터
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("마스터가 레지스트리 미러 조회를 거부했습니다 (HTTP %d)", resp.StatusCode())
+	}
+	return result.Mirrors, nil
+}
+
+/*
+writeRegistryConfig - K3s registries.yaml 생성 (--private-registry로 전달)
+docker.io 이미지 pull을 미러로 보내고, 미러가 모두 실패하면 containerd가 Docker Hub로 폴백합니다.
+미러가 없으면 빈 경로를 반환합니다.
+*/
+func (s *StakerHost) writeRegistryConfig(dataDir string) (string, error) {
+	mirrors, err := s.fetchRegistryMirrors()
+	if err != nil || len(mirrors) == 0 {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("mirrors:\n  docker.io:\n    endpoint:\n")
+	for _, mirror := range mirrors {
+		fmt.Fprintf(&b, "      - %q\n", mirror.Endpoint)
+	}
+
+	var configs []string
+	for _, mirror := range mirrors {
+		if !mirror.SealAuth {
+			continue
+		}
+		endpoint, err := url.Parse(mirror.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return "", fmt.Errorf("잘못된 미러 주소: %s", mirror.Endpoint)
+		}
+		configs = append(configs, fmt.Sprintf("  %q:\n    auth:\n      username: %q\n      password: %q\n",
+			endpoint.Host, s.config.NodeID, s.stakingStatus.SealToken()))
+	}
+	if len(configs) > 0 {
+		b.WriteString("configs:\n")
+		b.WriteString(strings.Join(configs, ""))
+	}
+
+	// Seal 토큰이 들어 있으므로 소유자만 읽기 가능
+	path := filepath.Join(dataDir, "registries.yaml")
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return "", fmt.Errorf("registries.yaml 저장 실패: %v", err)
+	}
+	for _, mirror := range mirrors {
+		log.Printf("📦 이미지 미러 사용: %s", mirror.Endpoint)
+	}
+	return path, nil
+}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+	service "github.com/k3s-io/daas-service"
+)
+
+/*
+실행 모드 - 스테이킹 데몬과 런타임 에이전트 분리
+- combined: 한 프로세스에서 스테이킹/하트비트와 K3s Agent를 모두 실행 (기본값, 단순 설치용)
+- staking:  스테이킹, Nautilus 등록, 하트비트, 상태 서버만 실행. 런타임 작업은 소켓으로 위임
+- runtime:  K3s Agent와 컨테이너 런타임만 실행하고 로컬 unix 소켓 API를 제공
+스테이킹 데몬이 죽어도 워크로드가 유지되고, 런타임이 침해되어도 지갑 키에 접근할 수 없도록
+두 프로세스를 분리해 운영할 수 있습니다.
+*/
+const (
+	modeCombined = "combined"
+	modeStaking  = "staking"
+	modeRuntime  = "runtime"
+
+	defaultRuntimeSocket = "/run/k3s-daas/runtime.sock"
+)
+
+// workerMode - K3S_DAAS_MODE 환경변수 (미설정 또는 알 수 없는 값이면 combined)
+func workerMode() string {
+	switch mode := os.Getenv("K3S_DAAS_MODE"); mode {
+	case modeStaking, modeRuntime:
+		return mode
+	default:
+		if mode != "" && mode != modeCombined {
+			log.Printf("⚠️ 알 수 없는 K3S_DAAS_MODE=%q, combined 모드로 실행", mode)
+		}
+		return modeCombined
+	}
+}
+
+// runtimeSocketPath - 런타임 에이전트 소켓 경로 (K3S_DAAS_RUNTIME_SOCKET)
+func runtimeSocketPath() string {
+	if path := os.Getenv("K3S_DAAS_RUNTIME_SOCKET"); path != "" {
+		return path
+	}
+	return defaultRuntimeSocket
+}
+
+// agentStartRequest - 스테이킹 데몬이 런타임 에이전트에 전달하는 조인 정보
+type agentStartRequest struct {
+	SealToken   string `json:"seal_token"`
+	JoinToken   string `json:"join_token"` // Nautilus가 발급한 노드 전용 1회용 토큰
+	StakeAmount uint64 `json:"stake_amount"`
+}
+
+/*
+runtimeAgent - runtime 모드의 소켓 API 서버
+Seal 토큰은 스테이킹 데몬에서 받아 K3s Agent 조인에만 사용하며, 지갑 개인키는 갖지 않습니다.
+*/
+type runtimeAgent struct {
+	host    *StakerHost
+	mu      sync.Mutex
+	started bool
+}
+
+/*
+serveRuntimeAgent - unix 소켓으로 런타임 API 제공 (ctx 종료 시까지 블로킹)
+소켓은 0660 권한으로 생성되어 같은 그룹의 스테이킹 데몬만 접근할 수 있습니다.
+*/
+func serveRuntimeAgent(ctx context.Context, host *StakerHost, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0750); err != nil {
+		return fmt.Errorf("소켓 디렉토리 생성 실패: %v", err)
+	}
+	// 이전 실행에서 남은 소켓 파일 제거
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("기존 소켓 제거 실패: %v", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("런타임 소켓 리슨 실패: %v", err)
+	}
+	if err := os.Chmod(socketPath, 0660); err != nil {
+		listener.Close()
+		return fmt.Errorf("런타임 소켓 권한 설정 실패: %v", err)
+	}
+
+	agent := &runtimeAgent{host: host}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/health", agent.handleHealth)
+	mux.HandleFunc("/v1/agent/start", agent.handleStart)
+	mux.HandleFunc("/v1/agent/stop", agent.handleStop)
+	mux.HandleFunc("/v1/pods", agent.handlePods)
+	mux.HandleFunc("/v1/pods/probe", agent.handleProbe)
+	mux.HandleFunc("/v1/pods/reconcile", agent.handleReconcile)
+	mux.HandleFunc("/v1/pods/resources", agent.handlePodResources)
+	mux.HandleFunc("/v1/images", agent.handleImages)
+	mux.HandleFunc("/v1/preflight", agent.handlePreflight)
+	mux.HandleFunc("/v1/logs", agent.handleLogs)
+	mux.HandleFunc("/v1/logs/throttling", agent.handleLogThrottling)
+	mux.HandleFunc("/v1/network", agent.handleNetwork)
+	mux.HandleFunc("/v1/host/fingerprint", agent.handleHostFingerprint)
+
+	server := &http.Server{
+		Handler:           httpserver.Chain(mux, httpserver.RequestID, httpserver.Recovery(log.Printf)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("🔌 런타임 에이전트 소켓 API 실행 중: %s", socketPath)
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	os.Remove(socketPath)
+	return nil
+}
+
+func (a *runtimeAgent) handleHealth(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	started := a.started
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":        "healthy",
+		"node_id":       a.host.config.NodeID,
+		"agent_started": started,
+	})
+}
+
+// handleStart - Seal 토큰으로 K3s Agent 시작 (이미 실행 중이면 무시)
+func (a *runtimeAgent) handleStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req agentStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SealToken == "" || req.JoinToken == "" {
+		http.Error(w, "seal_token and join_token are required", http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 스테이킹 데몬이 등록 전에 확인하지만, 오래된 데몬 버전을 대비해 여기서도 거부
+	if report, _ := a.host.preflightReport(); report.err() != nil {
+		http.Error(w, report.err().Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	// 스테이킹 데몬이 체인에서 확인한 상태를 그대로 넘겨받음 (pending → active)
+	if err := a.host.stakingStatus.Transition(stakingActive, func(status *StakingStatus) {
+		status.SealToken = req.SealToken
+		status.StakeAmount = req.StakeAmount
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	a.host.sealToken = req.SealToken
+	a.host.joinToken = req.JoinToken
+
+	if err := a.host.startRealK3sAgent(); err != nil {
+		log.Printf("❌ K3s Agent 시작 실패: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.started = true
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleStop - 실행 중인 컨테이너 정리 (슬래싱 또는 스테이킹 데몬 종료 시)
+func (a *runtimeAgent) handleStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	a.host.stopContainers()
+	a.mu.Lock()
+	a.started = false
+	a.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *runtimeAgent) handlePods(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ready": a.host.getRunningPodsCount(),
+	})
+}
+
+func (a *runtimeAgent) handleProbe(w http.ResponseWriter, r *http.Request) {
+	running, err := isPodRunning(r.URL.Query().Get("namespace"), r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"running": running,
+	})
+}
+
+// handleReconcile - 스테이킹 데몬이 마스터에서 받은 목표 Pod과 로컬 샌드박스 대조
+func (a *runtimeAgent) handleReconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req podReconcileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Since.IsZero() {
+		http.Error(w, "pods and since are required", http.StatusBadRequest)
+		return
+	}
+	result, err := a.host.reconcileDesiredPods(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleImages - 이미지 GC 상태 (스테이킹 데몬의 노드 메트릭에 포함)
+func (a *runtimeAgent) handleImages(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a.host.images.snapshot())
+}
+
+// handlePreflight - 런타임 에이전트 시작 시 실행한 프리플라이트 결과
+func (a *runtimeAgent) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	report, _ := a.host.preflightReport()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleLogs - 보관 중인 Pod 로그 조회 (쿼리는 /api/v1/logs와 같음)
+func (a *runtimeAgent) handleLogs(w http.ResponseWriter, r *http.Request) {
+	result, err := a.host.podLogQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleLogThrottling - 로그 제한 중인 컨테이너 (스테이킹 데몬의 하트비트에 포함)
+func (a *runtimeAgent) handleLogThrottling(w http.ResponseWriter, r *http.Request) {
+	throttles := []LogThrottle{}
+	if a.host.podLogs != nil {
+		throttles = a.host.podLogs.throttling()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(throttles)
+}
+
+// handleNetwork - Pod별 누적 네트워크 사용량 (스테이킹 데몬의 하트비트에 포함)
+func (a *runtimeAgent) handleNetwork(w http.ResponseWriter, r *http.Request) {
+	usage, err := collectPodNetwork()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// handlePodResources - Pod별 자원 제한 강제 상태와 스로틀링/OOM 카운터 (스테이킹 데몬의 하트비트에 포함)
+func (a *runtimeAgent) handlePodResources(w http.ResponseWriter, r *http.Request) {
+	usage, err := collectPodResources()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// handleHostFingerprint - 런타임 호스트의 OS/커널/런타임 지문 (스테이킹 데몬의 하트비트에 포함)
+func (a *runtimeAgent) handleHostFingerprint(w http.ResponseWriter, r *http.Request) {
+	fingerprint, err := collectHostFingerprint(a.host.config.HostFingerprint.Sysctls)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fingerprint)
+}
+
+/*
+RuntimeClient - staking 모드에서 런타임 에이전트 소켓 API 호출
+*/
+type RuntimeClient struct {
+	socketPath string
+	httpClient *http.Client
+}
+
+// NewRuntimeClient - unix 소켓으로 연결하는 클라이언트 생성
+func NewRuntimeClient(socketPath string) *RuntimeClient {
+	return &RuntimeClient{
+		socketPath: socketPath,
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute, // K3s Agent 시작은 준비 대기를 포함
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// do - 소켓 API 호출 (호스트명은 무시됨)
+func (c *RuntimeClient) do(method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, "http://runtime"+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("런타임 에이전트(%s) 연결 실패: %v", c.socketPath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("런타임 에이전트 오류 (HTTP %d): %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// StartAgent - Nautilus 조인 토큰으로 K3s Agent 시작 요청
+func (c *RuntimeClient) StartAgent(sealToken, joinToken string, stakeAmount uint64) error {
+	return c.do(http.MethodPost, "/v1/agent/start", agentStartRequest{SealToken: sealToken, JoinToken: joinToken, StakeAmount: stakeAmount}, nil)
+}
+
+// StopAgent - 컨테이너 정리 요청
+func (c *RuntimeClient) StopAgent() error {
+	return c.do(http.MethodPost, "/v1/agent/stop", nil, nil)
+}
+
+// ReadyPods - Ready Pod 샌드박스 수
+func (c *RuntimeClient) ReadyPods() (int, error) {
+	var result struct {
+		Ready int `json:"ready"`
+	}
+	if err := c.do(http.MethodGet, "/v1/pods", nil, &result); err != nil {
+		return 0, err
+	}
+	return result.Ready, nil
+}
+
+// PodRunning - 감사 프로브용 Pod 실행 여부
+func (c *RuntimeClient) PodRunning(namespace, name string) (bool, error) {
+	var result struct {
+		Running bool `json:"running"`
+	}
+	query := url.Values{"namespace": {namespace}, "name": {name}}
+	if err := c.do(http.MethodGet, "/v1/pods/probe?"+query.Encode(), nil, &result); err != nil {
+		return false, err
+	}
+	return result.Running, nil
+}
+
+// ReconcilePods - 런타임 에이전트에서 목표 Pod과 대조 (중단은 에이전트가 수행)
+func (c *RuntimeClient) ReconcilePods(req podReconcileRequest) (podReconcileResult, error) {
+	var result podReconcileResult
+	err := c.do(http.MethodPost, "/v1/pods/reconcile", req, &result)
+	return result, err
+}
+
+// ImageGCStats - 런타임 에이전트의 이미지 GC 상태
+func (c *RuntimeClient) ImageGCStats() (ImageGCStats, error) {
+	var stats ImageGCStats
+	err := c.do(http.MethodGet, "/v1/images", nil, &stats)
+	return stats, err
+}
+
+// Preflight - 런타임 에이전트의 프리플라이트 결과 (k3s 바이너리와 커널 기능은 런타임 호스트 기준)
+func (c *RuntimeClient) Preflight() (*PreflightReport, error) {
+	var report PreflightReport
+	if err := c.do(http.MethodGet, "/v1/preflight", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// PodLogs - 런타임 에이전트가 보관 중인 Pod 로그
+func (c *RuntimeClient) PodLogs(query url.Values) (PodLogResult, error) {
+	var result PodLogResult
+	err := c.do(http.MethodGet, "/v1/logs?"+query.Encode(), nil, &result)
+	return result, err
+}
+
+// LogThrottling - 런타임 에이전트가 로그 제한 중인 컨테이너
+func (c *RuntimeClient) LogThrottling() ([]LogThrottle, error) {
+	var throttles []LogThrottle
+	err := c.do(http.MethodGet, "/v1/logs/throttling", nil, &throttles)
+	return throttles, err
+}
+
+// PodNetwork - 런타임 호스트의 Pod별 누적 네트워크 사용량
+func (c *RuntimeClient) PodNetwork() ([]PodNetworkUsage, error) {
+	var usage []PodNetworkUsage
+	err := c.do(http.MethodGet, "/v1/network", nil, &usage)
+	return usage, err
+}
+
+// PodResources - 런타임 호스트의 Pod별 자원 제한 상태
+func (c *RuntimeClient) PodResources() ([]PodResourceUsage, error) {
+	var usage []PodResourceUsage
+	err := c.do(http.MethodGet, "/v1/pods/resources", nil, &usage)
+	return usage, err
+}
+
+// HostFingerprint - 런타임 호스트의 OS/커널/런타임 지문
+func (c *RuntimeClient) HostFingerprint() (*HostFingerprint, error) {
+	var fingerprint HostFingerprint
+	if err := c.do(http.MethodGet, "/v1/host/fingerprint", nil, &fingerprint); err != nil {
+		return nil, err
+	}
+	return &fingerprint, nil
+}
+
+// workerServiceName - 실행 모드별 서비스 이름 (분리 설치 시 두 유닛이 공존)
+func workerServiceName(mode string) string {
+	switch mode {
+	case modeStaking:
+		return "k3s-daas-staking"
+	case modeRuntime:
+		return "k3s-daas-runtime"
+	default:
+		return "k3s-daas-worker"
+	}
+}
+
+/*
+workerServiceSpec - 현재 모드와 설정 파일 경로를 유지하는 서비스 정의
+설치 시점의 K3S_DAAS_MODE, 설정 파일 경로(절대 경로), 소켓 경로를 서비스 환경에 기록합니다.
+*/
+func workerServiceSpec(mode, configPath string) service.Spec {
+	env := map[string]string{}
+	if mode != modeCombined {
+		env["K3S_DAAS_MODE"] = mode
+		env["K3S_DAAS_RUNTIME_SOCKET"] = runtimeSocketPath()
+	}
+	if abs, err := filepath.Abs(configPath); err == nil {
+		env["STAKER_CONFIG_PATH"] = abs
+	}
+
+	descriptions := map[string]string{
+		modeCombined: "K3s-DaaS worker (staking, heartbeat and K3s agent)",
+		modeStaking:  "K3s-DaaS staking daemon (staking, Nautilus registration, heartbeat)",
+		modeRuntime:  "K3s-DaaS runtime agent (K3s agent and container runtime)",
+	}
+	return service.Spec{
+		Name:        workerServiceName(mode),
+		DisplayName: "K3s-DaaS " + mode,
+		Description: descriptions[mode],
+		Env:         env,
+	}
+}
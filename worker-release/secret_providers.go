@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+/*
+SecretsConfig - Pod 마운트 시점에 외부 Secret을 해석하는 secrets 볼륨 설정
+
+Pod는 FlexVolume 드라이버 k3s-daas.io/secrets를 볼륨으로 쓰고, options.secrets에 파일별 참조를 적습니다.
+
+	volumes:
+	- name: creds
+	  flexVolume:
+	    driver: k3s-daas.io/secrets
+	    options:
+	      secrets: "db-password=vault:secret/data/db#password,tls.key=seal:0x1234#tls.key"
+	      vaultAddr: https://vault.tenant.example   # 선택 (기본: vault_addr 설정)
+	      vaultRole: payments                       # 선택 (기본: Pod 네임스페이스)
+
+값은 워커 디스크에 남지 않도록 tmpfs에만 쓰며, 마스터 Secret 저장소를 거치지 않습니다.
+  - vault: 마스터가 발급한 Pod 서비스 계정 JWT로 Vault JWT 인증 후 KV(v1/v2) 읽기
+  - seal:  온체인 sealed_secrets::SealedSecret 오브젝트를 마스터 TEE가 소유자/네임스페이스 확인 후 복호화
+*/
+type SecretsConfig struct {
+	Disabled      bool   `json:"disabled"`
+	SocketPath    string `json:"socket_path"`     // 드라이버가 Secret 해석을 요청하는 소켓 (기본 /run/k3s-daas/secrets.sock)
+	PluginDir     string `json:"plugin_dir"`      // kubelet FlexVolume 플러그인 디렉토리
+	VaultAddr     string `json:"vault_addr"`      // 볼륨 옵션 vaultAddr가 없을 때 사용할 Vault 주소
+	VaultAuthPath string `json:"vault_auth_path"` // Vault JWT 인증 경로 (기본 auth/jwt)
+}
+
+const (
+	defaultSecretsSocket   = "/run/k3s-daas/secrets.sock"
+	defaultVolumePluginDir = "/usr/libexec/kubernetes/kubelet-plugins/volume/exec"
+	defaultVaultAuthPath   = "auth/jwt"
+	secretResolveTimeout   = 30 * time.Second
+)
+
+/*
+applySecretsDefaults - secrets 볼륨 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_SECRETS_SOCKET: 드라이버-워커 소켓 경로
+- K3S_DAAS_VAULT_ADDR: 기본 Vault 주소
+*/
+func applySecretsDefaults(config *StakerHostConfig) error {
+	c := &config.Secrets
+	if value := os.Getenv("K3S_DAAS_SECRETS_SOCKET"); value != "" {
+		c.SocketPath = value
+	}
+	if value := os.Getenv("K3S_DAAS_VAULT_ADDR"); value != "" {
+		c.VaultAddr = value
+	}
+
+	if c.SocketPath == "" {
+		c.SocketPath = defaultSecretsSocket
+	}
+	if c.PluginDir == "" {
+		c.PluginDir = defaultVolumePluginDir
+	}
+	if c.VaultAuthPath == "" {
+		c.VaultAuthPath = defaultVaultAuthPath
+	}
+	c.VaultAuthPath = strings.Trim(c.VaultAuthPath, "/")
+	if c.VaultAddr != "" && !strings.HasPrefix(c.VaultAddr, "https://") && !strings.HasPrefix(c.VaultAddr, "http://") {
+		return fmt.Errorf("vault_addr는 http(s) 주소여야 합니다: %s", c.VaultAddr)
+	}
+	return nil
+}
+
+// kubeletArgs - kubelet이 secrets 드라이버를 찾을 플러그인 디렉토리
+func (c SecretsConfig) kubeletArgs() []string {
+	if c.Disabled {
+		return nil
+	}
+	return []string{"--volume-plugin-dir=" + c.PluginDir}
+}
+
+// podIdentity - kubelet이 FlexVolume 옵션으로 넘기는 Pod 정보
+type podIdentity struct {
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	UID            string `json:"uid"`
+	ServiceAccount string `json:"service_account"`
+}
+
+// SecretRef - 파일 하나의 참조 ("file=provider:path#key")
+type SecretRef struct {
+	File     string `json:"file"`
+	Provider string `json:"provider"`
+	Path     string `json:"path"` // vault: KV 경로, seal: 오브젝트 ID
+	Key      string `json:"key"`  // 값 안의 키 (기본: 파일 이름)
+}
+
+// parseSecretRefs - 볼륨 옵션 secrets 파싱 (쉼표 구분)
+func parseSecretRefs(spec string) ([]SecretRef, error) {
+	var refs []SecretRef
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		file, source, ok := strings.Cut(entry, "=")
+		provider, path, ok2 := strings.Cut(source, ":")
+		if !ok || !ok2 || path == "" {
+			return nil, fmt.Errorf("잘못된 secrets 항목 (file=provider:path#key): %s", entry)
+		}
+		if file == "" || file == "." || file == ".." || strings.ContainsAny(file, "/\\") {
+			return nil, fmt.Errorf("잘못된 파일 이름: %q", file)
+		}
+		if seen[file] {
+			return nil, fmt.Errorf("중복된 파일 이름: %s", file)
+		}
+		seen[file] = true
+
+		ref := SecretRef{File: file, Provider: provider, Path: path, Key: file}
+		if path, key, found := strings.Cut(path, "#"); found {
+			ref.Path, ref.Key = path, key
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("secrets 옵션이 비어 있습니다")
+	}
+	return refs, nil
+}
+
+/*
+SecretProvider - 외부 Secret 저장소 하나
+Resolve는 path가 가리키는 값 전체(키 → 값)를 돌려주고, 파일별 키 선택은 호출자가 합니다.
+*/
+type SecretProvider interface {
+	Name() string
+	Resolve(ctx context.Context, pod podIdentity, path string, options map[string]string) (map[string][]byte, error)
+}
+
+// secretResolver - Provider 선택과 파일별 값 조립 (같은 path는 한 번만 조회)
+type secretResolver struct {
+	providers map[string]SecretProvider
+}
+
+func newSecretResolver(host *StakerHost) *secretResolver {
+	resolver := &secretResolver{providers: make(map[string]SecretProvider)}
+	for _, provider := range []SecretProvider{newVaultProvider(host), &sealProvider{host: host}} {
+		resolver.providers[provider.Name()] = provider
+	}
+	return resolver
+}
+
+// resolve - 볼륨에 쓸 파일 이름 → 값
+func (r *secretResolver) resolve(ctx context.Context, pod podIdentity, refs []SecretRef, options map[string]string) (map[string][]byte, error) {
+	type source struct{ provider, path string }
+	fetched := make(map[source]map[string][]byte)
+	files := make(map[string][]byte, len(refs))
+	for _, ref := range refs {
+		provider, ok := r.providers[ref.Provider]
+		if !ok {
+			return nil, fmt.Errorf("알 수 없는 Secret provider: %s", ref.Provider)
+		}
+		key := source{ref.Provider, ref.Path}
+		values, ok := fetched[key]
+		if !ok {
+			var err error
+			if values, err = provider.Resolve(ctx, pod, ref.Path, options); err != nil {
+				return nil, fmt.Errorf("%s:%s 조회 실패: %v", ref.Provider, ref.Path, err)
+			}
+			fetched[key] = values
+		}
+		value, ok := values[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("%s:%s에 키 %s가 없습니다", ref.Provider, ref.Path, ref.Key)
+		}
+		files[ref.File] = value
+	}
+	return files, nil
+}
+
+// masterSecretRequest - 마스터 /api/v1/secrets/* 호출 (Seal 토큰 인증, 본문에 Pod 정보)
+func (s *StakerHost) masterSecretRequest(ctx context.Context, path string, pod podIdentity, extra map[string]string, result interface{}) error {
+	if s.stakingStatus.SealToken() == "" {
+		return fmt.Errorf("아직 Seal 토큰이 없습니다 (스테이킹 등록 전)")
+	}
+	body := map[string]string{
+		"node_id":   s.config.NodeID,
+		"namespace": pod.Namespace,
+		"pod":       pod.Name,
+		"pod_uid":   pod.UID,
+	}
+	for key, value := range extra {
+		body[key] = value
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	resp, err := s.restyClient().R().
+		SetContext(ctx).
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetBody(body).
+		SetResult(&envelope).
+		Post(s.config.NautilusEndpoint + path)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("마스터가 거부했습니다 (HTTP %d): %s", resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+	return json.Unmarshal(envelope.Data, result)
+}
+
+// sealProvider - 온체인 Sealed Secret (path = 오브젝트 ID, 복호화는 마스터 TEE)
+type sealProvider struct {
+	host *StakerHost
+}
+
+func (p *sealProvider) Name() string { return "seal" }
+
+func (p *sealProvider) Resolve(ctx context.Context, pod podIdentity, objectID string, _ map[string]string) (map[string][]byte, error) {
+	var result struct {
+		Version uint64            `json:"version"`
+		Data    map[string][]byte `json:"data"`
+	}
+	if err := p.host.masterSecretRequest(ctx, "/api/v1/secrets/unseal", pod, map[string]string{"object_id": objectID}, &result); err != nil {
+		return nil, err
+	}
+	log.Printf("🔓 Sealed Secret %s (v%d) → %s/%s", objectID, result.Version, pod.Namespace, pod.Name)
+	return result.Data, nil
+}
+
+/*
+vaultProvider - HashiCorp Vault (path = KV 경로, 예: secret/data/app)
+Pod 서비스 계정 JWT(aud=vault)로 JWT 인증 role에 로그인하고, 받은 클라이언트 토큰은 임대 기간 동안 재사용합니다.
+role 기본값은 Pod 네임스페이스이며 Vault 쪽에서 bound_claims로 kubernetes.io.namespace를 묶어 둡니다.
+*/
+type vaultProvider struct {
+	host   *StakerHost
+	client *resty.Client
+
+	mutex  sync.Mutex
+	tokens map[string]vaultToken // addr|authPath|role|namespace|serviceAccount → 클라이언트 토큰
+}
+
+type vaultToken struct {
+	token   string
+	expires time.Time
+}
+
+func newVaultProvider(host *StakerHost) *vaultProvider {
+	return &vaultProvider{
+		host:   host,
+		client: resty.New().SetTimeout(secretResolveTimeout),
+		tokens: make(map[string]vaultToken),
+	}
+}
+
+func (p *vaultProvider) Name() string { return "vault" }
+
+func (p *vaultProvider) Resolve(ctx context.Context, pod podIdentity, path string, options map[string]string) (map[string][]byte, error) {
+	config := p.host.config.Secrets
+	addr := strings.TrimRight(firstNonEmpty(options["vaultAddr"], config.VaultAddr), "/")
+	if addr == "" {
+		return nil, fmt.Errorf("Vault 주소가 없습니다 (볼륨 옵션 vaultAddr 또는 vault_addr 설정)")
+	}
+	authPath := strings.Trim(firstNonEmpty(options["vaultAuthPath"], config.VaultAuthPath), "/")
+	role := firstNonEmpty(options["vaultRole"], pod.Namespace)
+
+	token, err := p.login(ctx, addr, authPath, role, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetHeader("X-Vault-Token", token).
+		Get(addr + "/v1/" + strings.TrimLeft(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		if resp.StatusCode() == http.StatusForbidden {
+			p.forget(addr, authPath, role, pod)
+		}
+		return nil, fmt.Errorf("Vault 읽기 실패 (HTTP %d)", resp.StatusCode())
+	}
+	if err := json.Unmarshal(resp.Body(), &secret); err != nil {
+		return nil, fmt.Errorf("Vault 응답 해석 실패: %v", err)
+	}
+
+	// KV v2는 data.data에 값, data.metadata에 버전 정보
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+	values := make(map[string][]byte, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			values[key] = []byte(v)
+		default:
+			encoded, _ := json.Marshal(v)
+			values[key] = encoded
+		}
+	}
+	return values, nil
+}
+
+func vaultTokenKey(addr, authPath, role string, pod podIdentity) string {
+	return strings.Join([]string{addr, authPath, role, pod.Namespace, pod.ServiceAccount}, "|")
+}
+
+// login - 캐시된 토큰이 없거나 곧 만료되면 마스터 JWT로 로그인
+func (p *vaultProvider) login(ctx context.Context, addr, authPath, role string, pod podIdentity) (string, error) {
+	key := vaultTokenKey(addr, authPath, role, pod)
+	p.mutex.Lock()
+	cached, ok := p.tokens[key]
+	p.mutex.Unlock()
+	if ok && time.Until(cached.expires) > time.Minute {
+		return cached.token, nil
+	}
+
+	var jwt struct {
+		Token string `json:"token"`
+	}
+	if err := p.host.masterSecretRequest(ctx, "/api/v1/secrets/token", pod, map[string]string{"audience": "vault"}, &jwt); err != nil {
+		return "", fmt.Errorf("서비스 계정 토큰 발급 실패: %v", err)
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetBody(map[string]string{"role": role, "jwt": jwt.Token}).
+		Post(addr + "/v1/" + authPath + "/login")
+	if err != nil {
+		return "", err
+	}
+	json.Unmarshal(resp.Body(), &login)
+	if resp.StatusCode() != http.StatusOK || login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("Vault 로그인 실패 (role %s, HTTP %d)", role, resp.StatusCode())
+	}
+
+	p.mutex.Lock()
+	p.tokens[key] = vaultToken{
+		token:   login.Auth.ClientToken,
+		expires: time.Now().Add(time.Duration(login.Auth.LeaseDuration) * time.Second),
+	}
+	p.mutex.Unlock()
+	return login.Auth.ClientToken, nil
+}
+
+func (p *vaultProvider) forget(addr, authPath, role string, pod podIdentity) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.tokens, vaultTokenKey(addr, authPath, role, pod))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+)
+
+/*
+secrets FlexVolume 드라이버 (k3s-daas.io/secrets)
+
+kubelet은 플러그인 디렉토리의 k3s-daas.io~secrets/secrets 스크립트를 Pod 마운트마다 실행하고,
+스크립트는 이 바이너리의 flexvolume 서브커맨드를 호출합니다.
+드라이버는 Seal 토큰이 없으므로 스테이킹 프로세스(staking/combined 모드)의 secrets 소켓에 해석을 요청하고,
+받은 값을 tmpfs에 파일로 씁니다. 값은 Pod가 시작될 때마다 새로 가져오므로 원본 변경은 재시작 시 반영됩니다.
+*/
+const (
+	secretsDriverName  = "k3s-daas.io~secrets"
+	secretsTmpfsSize   = "1m"
+	maxSecretsResponse = 4 << 20
+)
+
+// flexResult - FlexVolume 드라이버 출력 (kubelet이 stdout JSON으로 해석)
+type flexResult struct {
+	Status       string          `json:"status"` // Success, Failure, Not supported
+	Message      string          `json:"message,omitempty"`
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+}
+
+// secretsResolveRequest - 드라이버 → 스테이킹 프로세스 요청
+type secretsResolveRequest struct {
+	Pod     podIdentity       `json:"pod"`
+	Secrets string            `json:"secrets"`
+	Options map[string]string `json:"options"`
+}
+
+// runFlexVolume - flexvolume 서브커맨드 (init | mount <dir> <json> | unmount <dir>)
+func runFlexVolume(args []string, out io.Writer) int {
+	result := flexVolumeCommand(args)
+	json.NewEncoder(out).Encode(result)
+	if result.Status == "Failure" {
+		return 1
+	}
+	return 0
+}
+
+func flexVolumeCommand(args []string) flexResult {
+	if len(args) == 0 {
+		return flexResult{Status: "Failure", Message: "usage: flexvolume init|mount|unmount"}
+	}
+	switch args[0] {
+	case "init":
+		return flexResult{Status: "Success", Capabilities: map[string]bool{"attach": false}}
+	case "mount":
+		if len(args) < 3 {
+			return flexResult{Status: "Failure", Message: "usage: flexvolume mount <dir> <options>"}
+		}
+		if err := mountSecretsVolume(args[1], args[2]); err != nil {
+			return flexResult{Status: "Failure", Message: err.Error()}
+		}
+		return flexResult{Status: "Success"}
+	case "unmount":
+		if len(args) < 2 {
+			return flexResult{Status: "Failure", Message: "usage: flexvolume unmount <dir>"}
+		}
+		if err := unmountSecretsVolume(args[1]); err != nil {
+			return flexResult{Status: "Failure", Message: err.Error()}
+		}
+		return flexResult{Status: "Success"}
+	default:
+		return flexResult{Status: "Not supported"}
+	}
+}
+
+// mountSecretsVolume - 스테이킹 프로세스에서 값을 받아 tmpfs에 파일로 기록
+func mountSecretsVolume(dir, rawOptions string) error {
+	var options map[string]string
+	if err := json.Unmarshal([]byte(rawOptions), &options); err != nil {
+		return fmt.Errorf("잘못된 볼륨 옵션: %v", err)
+	}
+	request := secretsResolveRequest{
+		Pod: podIdentity{
+			Namespace:      options["kubernetes.io/pod.namespace"],
+			Name:           options["kubernetes.io/pod.name"],
+			UID:            options["kubernetes.io/pod.uid"],
+			ServiceAccount: options["kubernetes.io/serviceAccount.name"],
+		},
+		Secrets: options["secrets"],
+		Options: options,
+	}
+	if request.Pod.Namespace == "" || request.Pod.Name == "" {
+		return fmt.Errorf("kubelet이 Pod 정보를 전달하지 않았습니다")
+	}
+
+	files, err := requestSecrets(secretsSocketPath(), request)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	mount := exec.Command("mount", "-t", "tmpfs", "-o", "size="+secretsTmpfsSize+",mode=0755", "tmpfs", dir)
+	if output, err := mount.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmpfs 마운트 실패: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), value, 0644); err != nil {
+			unmountSecretsVolume(dir)
+			return fmt.Errorf("%s 기록 실패: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// unmountSecretsVolume - tmpfs 해제 후 디렉토리 제거 (이미 해제된 경우 무시)
+func unmountSecretsVolume(dir string) error {
+	if output, err := exec.Command("umount", dir).CombinedOutput(); err != nil &&
+		!strings.Contains(string(output), "not mounted") && !strings.Contains(string(output), "no mount point") {
+		if _, statErr := os.Stat(dir); !os.IsNotExist(statErr) {
+			return fmt.Errorf("tmpfs 해제 실패: %v: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+	return os.RemoveAll(dir)
+}
+
+// secretsSocketPath - 드라이버 스크립트가 넘긴 K3S_DAAS_SECRETS_SOCKET (없으면 기본값)
+func secretsSocketPath() string {
+	if path := os.Getenv("K3S_DAAS_SECRETS_SOCKET"); path != "" {
+		return path
+	}
+	return defaultSecretsSocket
+}
+
+// requestSecrets - secrets 소켓에 해석 요청
+func requestSecrets(socketPath string, request secretsResolveRequest) (map[string][]byte, error) {
+	client := &http.Client{
+		Timeout: 2 * secretResolveTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Post("http://secrets/v1/resolve", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("secrets 소켓 연결 실패 (%s): %v", socketPath, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSecretsResponse))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(data)))
+	}
+	var result struct {
+		Files map[string][]byte `json:"files"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result.Files, nil
+}
+
+/*
+serveSecretsSocket - 드라이버의 해석 요청 처리 (staking/combined 모드, ctx 종료 시까지 블로킹)
+소켓은 0660 권한으로 만들어 kubelet(root)과 같은 그룹만 접근할 수 있습니다.
+*/
+func serveSecretsSocket(ctx context.Context, host *StakerHost) error {
+	socketPath := host.config.Secrets.SocketPath
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0750); err != nil {
+		return fmt.Errorf("소켓 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("기존 소켓 제거 실패: %v", err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("secrets 소켓 리슨 실패: %v", err)
+	}
+	if err := os.Chmod(socketPath, 0660); err != nil {
+		listener.Close()
+		return fmt.Errorf("secrets 소켓 권한 설정 실패: %v", err)
+	}
+
+	resolver := newSecretResolver(host)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/resolve", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var request secretsResolveRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&request); err != nil {
+			http.Error(w, "잘못된 요청", http.StatusBadRequest)
+			return
+		}
+		refs, err := parseSecretRefs(request.Secrets)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resolveCtx, cancel := context.WithTimeout(r.Context(), secretResolveTimeout)
+		defer cancel()
+		files, err := resolver.resolve(resolveCtx, request.Pod, refs, request.Options)
+		if err != nil {
+			log.Printf("⚠️ Secret 볼륨 해석 실패 (%s/%s): %v", request.Pod.Namespace, request.Pod.Name, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		log.Printf("🔑 Secret 볼륨 준비: %s/%s (파일 %d개)", request.Pod.Namespace, request.Pod.Name, len(files))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"files": files})
+	})
+
+	server := &http.Server{
+		Handler:           httpserver.Chain(mux, httpserver.RequestID, httpserver.Recovery(log.Printf)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("🔑 Secret 볼륨 소켓 실행 중: %s", socketPath)
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	os.Remove(socketPath)
+	return nil
+}
+
+// installSecretsDriver - kubelet 플러그인 디렉토리에 드라이버 스크립트 설치 (k3s를 실행하는 프로세스에서)
+func installSecretsDriver(config SecretsConfig) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(config.PluginDir, secretsDriverName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("플러그인 디렉토리 생성 실패: %v", err)
+	}
+	script := fmt.Sprintf("#!/bin/sh\nK3S_DAAS_SECRETS_SOCKET=%q exec %q flexvolume \"$@\"\n", config.SocketPath, executable)
+	path := filepath.Join(dir, "secrets")
+	// kubelet이 실행 중에 바뀐 파일을 읽지 않도록 임시 파일에 쓴 뒤 교체
+	if err := os.WriteFile(path+".tmp", []byte(script), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	log.Printf("🔑 secrets FlexVolume 드라이버 설치: %s", path)
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+)
+
+/*
+슬래싱 이의 신청 - 슬래싱이 확정되어 노드를 종료하기 전에 증거를 보존하고 이의를 제출
+증거 번들(최근 하트비트 기록, 마스터 증명 문서, Pod 배정 기록, 스테이킹 조회 상태)을
+로컬에 저장하고 sha256 다이제스트를 slash_appeals::submit_appeal로 온체인에 기록합니다.
+번들 원본은 거버넌스가 마스터 API에서 내려받을 수 있도록 마스터에도 업로드합니다.
+*/
+const (
+	defaultAppealEvidenceDir = "/var/lib/k3s-daas-agent/appeals"
+	heartbeatLogSize         = 240 // 30초 간격 기준 2시간
+	appealGasBudget          = 10000000
+)
+
+// heartbeatRecord - 하트비트 한 번의 결과
+type heartbeatRecord struct {
+	At    int64  `json:"at"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// heartbeatLog - 최근 하트비트 결과 (이의 신청 증거용 링 버퍼)
+type heartbeatLog struct {
+	mu      sync.Mutex
+	records []heartbeatRecord
+}
+
+func (l *heartbeatLog) record(err error) {
+	entry := heartbeatRecord{At: time.Now().Unix(), OK: err == nil}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, entry)
+	if len(l.records) > heartbeatLogSize {
+		l.records = l.records[len(l.records)-heartbeatLogSize:]
+	}
+}
+
+func (l *heartbeatLog) list() []heartbeatRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]heartbeatRecord(nil), l.records...)
+}
+
+// slashEvidence - 이의 신청 증거 번들
+type slashEvidence struct {
+	NodeID            string                 `json:"node_id"`
+	WalletAddress     string                 `json:"wallet_address"`
+	StakeObjectID     string                 `json:"stake_object_id"`
+	DetectedAt        int64                  `json:"detected_at"`
+	LastStake         *StakeInfo             `json:"last_stake,omitempty"` // 슬래싱 전 마지막 정상 조회
+	StakeCheck        map[string]interface{} `json:"stake_check"`
+	ClockSkewMs       int64                  `json:"clock_skew_ms"`
+	LastHeartbeat     int64                  `json:"last_heartbeat"`
+	Heartbeats        []heartbeatRecord      `json:"heartbeats"`
+	MasterTimeline    json.RawMessage        `json:"master_timeline,omitempty"`
+	MasterAttestation json.RawMessage        `json:"master_attestation,omitempty"`
+	Assignments       *assignmentState       `json:"assignments,omitempty"`
+	CollectionErrors  []string               `json:"collection_errors,omitempty"`
+}
+
+func applyAppealDefaults(config *StakerHostConfig) {
+	if os.Getenv("K3S_DAAS_AUTO_APPEAL") == "true" {
+		config.AutoAppeal = true
+	}
+	if dir := os.Getenv("K3S_DAAS_APPEAL_EVIDENCE_DIR"); dir != "" {
+		config.AppealEvidenceDir = dir
+	}
+	if config.AppealEvidenceDir == "" {
+		config.AppealEvidenceDir = defaultAppealEvidenceDir
+	}
+}
+
+/*
+fileSlashAppeal - 슬래싱 확정 시 증거 스냅샷 → 마스터 업로드 → 온체인 이의 제출
+각 단계는 실패해도 다음 단계로 진행하며, 로컬 증거 파일은 항상 남깁니다.
+온체인 제출은 AutoAppeal 게이트가 켜진 경우에만 워커 지갑 가스로 수행합니다 (슬래싱된 노드는 스폰서 불가).
+*/
+func (s *StakerHost) fileSlashAppeal() {
+	evidence := s.collectSlashEvidence()
+
+	data, err := json.MarshalIndent(evidence, "", "  ")
+	if err != nil {
+		log.Printf("❌ 이의 신청 증거 직렬화 실패: %v", err)
+		return
+	}
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	path := filepath.Join(s.config.AppealEvidenceDir,
+		fmt.Sprintf("%s-%d.json", s.config.NodeID, evidence.DetectedAt))
+	if err := os.MkdirAll(s.config.AppealEvidenceDir, 0700); err != nil {
+		log.Printf("⚠️ 증거 디렉터리 생성 실패: %v", err)
+	} else if err := os.WriteFile(path, data, 0600); err != nil {
+		log.Printf("⚠️ 증거 파일 저장 실패: %v", err)
+	} else {
+		log.Printf("🗂️ 슬래싱 증거 저장: %s (%s)", path, digest)
+	}
+
+	if err := s.uploadAppealEvidence(data); err != nil {
+		log.Printf("⚠️ 마스터에 증거 업로드 실패 (로컬 파일로 제출 가능): %v", err)
+	}
+
+	if !s.featureGate(featureAutoAppeal) {
+		log.Printf("ℹ️ AutoAppeal 게이트 꺼짐 - slash_appeals::submit_appeal을 직접 호출하세요 (evidence_digest %s)", digest)
+		return
+	}
+
+	txDigest, err := s.submitAppealOnChain(digest)
+	if err != nil {
+		log.Printf("❌ 온체인 이의 신청 실패: %v", err)
+		return
+	}
+	log.Printf("⚖️ 슬래싱 이의 신청 제출 완료: %s", txDigest)
+}
+
+// collectSlashEvidence - 로컬 기록과 마스터 조회 결과로 증거 번들 구성 (조회 실패는 번들에 기록)
+func (s *StakerHost) collectSlashEvidence() *slashEvidence {
+	lastStake, _ := s.stakeMonitor.last()
+	evidence := &slashEvidence{
+		NodeID:        s.config.NodeID,
+		WalletAddress: s.config.SuiWalletAddress,
+		StakeObjectID: s.stakingStatus.StakeObjectID(),
+		DetectedAt:    time.Now().Unix(),
+		LastStake:     lastStake,
+		StakeCheck:    s.stakeMonitor.snapshot(),
+		ClockSkewMs:   s.clockSkew.Milliseconds(),
+		LastHeartbeat: s.lastHeartbeat,
+		Heartbeats:    s.heartbeats.list(),
+	}
+
+	timeline, err := s.fetchMaster("/api/v1/nodes/" + url.PathEscape(s.config.NodeID) + "/timeline?since=24h")
+	if err != nil {
+		evidence.CollectionErrors = append(evidence.CollectionErrors, "master_timeline: "+err.Error())
+	}
+	evidence.MasterTimeline = timeline
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	attestation, err := s.fetchMaster("/api/v1/attestation?nonce=" + hex.EncodeToString(nonce))
+	if err != nil {
+		evidence.CollectionErrors = append(evidence.CollectionErrors, "master_attestation: "+err.Error())
+	}
+	evidence.MasterAttestation = attestation
+
+	assignments, err := loadAssignmentState(s.config.AssignmentStatePath)
+	if err != nil {
+		evidence.CollectionErrors = append(evidence.CollectionErrors, "assignments: "+err.Error())
+	}
+	evidence.Assignments = assignments
+
+	return evidence
+}
+
+// fetchMaster - 마스터 GET 응답 본문 (JSON이 아니면 오류)
+func (s *StakerHost) fetchMaster(path string) (json.RawMessage, error) {
+	client := s.restyClient().SetTimeout(10 * time.Second).GetClient()
+	resp, err := client.Get(strings.TrimSuffix(s.config.NautilusEndpoint, "/") + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("응답이 JSON이 아닙니다")
+	}
+	return body, nil
+}
+
+// uploadAppealEvidence - 거버넌스 심사용으로 마스터에 증거 원본 업로드 (마스터가 다이제스트 재계산)
+func (s *StakerHost) uploadAppealEvidence(data []byte) error {
+	resp, err := s.restyClient().SetTimeout(30*time.Second).R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetQueryParam("node_id", s.config.NodeID).
+		SetBody(data).
+		Post(s.config.NautilusEndpoint + "/api/v1/appeals/evidence")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusCreated && resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+	return nil
+}
+
+// submitAppealOnChain - slash_appeals::submit_appeal 실행 (체인 백엔드, 워커 지갑 서명)
+func (s *StakerHost) submitAppealOnChain(evidenceDigest string) (string, error) {
+	summary := fmt.Sprintf("stake %s slashed while node %s was running; last heartbeat %s",
+		s.stakingStatus.StakeObjectID(), s.config.NodeID, time.Unix(s.lastHeartbeat, 0).UTC().Format(time.RFC3339))
+
+	tx := chain.NewTx("slash_appeals", "submit_appeal",
+		s.config.NodeID,
+		s.stakingStatus.StakeObjectID(),
+		evidenceDigest,
+		summary,
+	)
+	tx.GasBudget = appealGasBudget
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	result, err := s.suiClient.backend.SubmitTx(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("이의 신청 트랜잭션 실패: %v", err)
+	}
+	return result.Digest, nil
+}
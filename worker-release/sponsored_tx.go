@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+)
+
+// 기본값: 10번째 하트비트마다 온체인 하트비트 기록 (30초 간격 기준 5분)
+const defaultOnChainHeartbeatEvery = 10
+
+/*
+sponsoredTransaction - 마스터가 가스를 지불하고 서명한 트랜잭션
+워커는 내용을 확인한 뒤 자신의 서명만 더해 실행합니다.
+*/
+type sponsoredTransaction struct {
+	TxBytes          string `json:"tx_bytes"`
+	Sponsor          string `json:"sponsor"`
+	SponsorSignature string `json:"sponsor_signature"`
+	GasBudget        uint64 `json:"gas_budget"`
+}
+
+/*
+executeSponsoredCall - 가스 스폰서십 핸드셰이크로 Move 호출 실행
+
+1️⃣ 마스터에 Seal 토큰으로 스폰서 트랜잭션 요청 (node_id 이후 인자만 전달)
+2️⃣ 드라이런으로 sender/가스 소유자/호출 함수가 요청과 같은지 확인
+3️⃣ 워커 지갑으로 서명하고 [워커 서명, 스폰서 서명]으로 실행
+
+노드 지갑에 SUI가 없어도 하트비트/상태 변경을 체인에 기록할 수 있습니다.
+가스가 없는 mock 체인에서는 스폰서 없이 백엔드로 바로 제출합니다.
+*/
+func (s *StakerHost) executeSponsoredCall(module, function string, args ...string) (string, error) {
+	if s.suiClient.backend.Name() == chain.MockBackendName {
+		callArgs := []interface{}{s.config.NodeID}
+		for _, arg := range args {
+			callArgs = append(callArgs, arg)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		result, err := s.suiClient.backend.SubmitTx(ctx, chain.NewTx(module, function, callArgs...))
+		if err != nil {
+			return "", fmt.Errorf("%s::%s 실행 실패: %v", module, function, err)
+		}
+		return result.Digest, nil
+	}
+
+	// 1️⃣ 스폰서 트랜잭션 요청
+	resp, err := s.restyClient().R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Seal-Token", s.stakingStatus.SealToken()).
+		SetBody(map[string]interface{}{
+			"node_id":  s.config.NodeID,
+			"module":   module,
+			"function": function,
+			"args":     args,
+		}).
+		Post(s.config.NautilusEndpoint + "/api/v1/gas/sponsor")
+	if err != nil {
+		return "", fmt.Errorf("스폰서 요청 실패: %v", err)
+	}
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("스폰서 거부 (HTTP %d): %s", resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+
+	var envelope struct {
+		Data sponsoredTransaction `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body(), &envelope); err != nil {
+		return "", fmt.Errorf("스폰서 응답 파싱 실패: %v", err)
+	}
+	tx := envelope.Data
+
+	// 2️⃣ 서명 전에 트랜잭션 내용 확인
+	if err := s.verifySponsoredTransaction(&tx, module, function); err != nil {
+		return "", err
+	}
+
+	// 3️⃣ 워커 서명 후 두 서명으로 실행
+	signature, err := signTransactionBytes(s.config.SuiWalletAddress, tx.TxBytes)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Digest  string `json:"digest"`
+		Effects struct {
+			Status struct {
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			} `json:"status"`
+		} `json:"effects"`
+	}
+	err = s.suiRPC("sui_executeTransactionBlock", []interface{}{
+		tx.TxBytes,
+		[]string{signature, tx.SponsorSignature},
+		map[string]bool{"showEffects": true},
+		"WaitForLocalExecution",
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.Effects.Status.Status != "success" {
+		return "", fmt.Errorf("스폰서 트랜잭션 실패: %s", result.Effects.Status.Error)
+	}
+
+	return result.Digest, nil
+}
+
+/*
+verifySponsoredTransaction - 스폰서가 만든 트랜잭션이 요청한 호출인지 확인
+서명은 트랜잭션 전체에 대한 승인이므로 마스터를 맹목적으로 신뢰하지 않습니다.
+*/
+func (s *StakerHost) verifySponsoredTransaction(tx *sponsoredTransaction, module, function string) error {
+	var dryRun struct {
+		Input struct {
+			Sender  string `json:"sender"`
+			GasData struct {
+				Owner string `json:"owner"`
+			} `json:"gasData"`
+			Transaction struct {
+				Transactions []map[string]json.RawMessage `json:"transactions"`
+			} `json:"transaction"`
+		} `json:"input"`
+	}
+	if err := s.suiRPC("sui_dryRunTransactionBlock", []interface{}{tx.TxBytes}, &dryRun); err != nil {
+		return fmt.Errorf("스폰서 트랜잭션 드라이런 실패: %v", err)
+	}
+
+	if !strings.EqualFold(dryRun.Input.Sender, s.config.SuiWalletAddress) {
+		return fmt.Errorf("스폰서 트랜잭션 sender 불일치: %s", dryRun.Input.Sender)
+	}
+	if !strings.EqualFold(dryRun.Input.GasData.Owner, tx.Sponsor) {
+		return fmt.Errorf("가스 소유자가 스폰서가 아닙니다: %s", dryRun.Input.GasData.Owner)
+	}
+
+	// 단일 MoveCall이며 요청한 모듈/함수여야 함 (코인 전송 등 다른 명령 차단)
+	commands := dryRun.Input.Transaction.Transactions
+	if len(commands) != 1 || commands[0]["MoveCall"] == nil {
+		return fmt.Errorf("스폰서 트랜잭션에 예상치 못한 명령이 포함되어 있습니다")
+	}
+	var call struct {
+		Package  string `json:"package"`
+		Module   string `json:"module"`
+		Function string `json:"function"`
+	}
+	if err := json.Unmarshal(commands[0]["MoveCall"], &call); err != nil {
+		return fmt.Errorf("MoveCall 파싱 실패: %v", err)
+	}
+	if !strings.EqualFold(call.Package, s.config.ContractAddress) || call.Module != module || call.Function != function {
+		return fmt.Errorf("스폰서 트랜잭션 호출 불일치: %s::%s::%s", call.Package, call.Module, call.Function)
+	}
+
+	return nil
+}
+
+/*
+signTransactionBytes - sui keytool로 트랜잭션 서명
+워커 지갑 키는 sui 키스토어에 등록되어 있어야 합니다.
+*/
+func signTransactionBytes(address, txBytes string) (string, error) {
+	output, err := exec.Command("sui", "keytool", "sign", "--address", address, "--data", txBytes, "--json").Output()
+	if err != nil {
+		return "", fmt.Errorf("트랜잭션 서명 실패: %v", err)
+	}
+
+	var signed struct {
+		SuiSignature string `json:"suiSignature"`
+	}
+	if err := json.Unmarshal(output, &signed); err != nil || signed.SuiSignature == "" {
+		return "", fmt.Errorf("keytool 서명 결과 파싱 실패")
+	}
+	return signed.SuiSignature, nil
+}
+
+/*
+recordHeartbeatOnChain - N번째 하트비트마다 스폰서 가스로 온체인 하트비트 기록
+실패해도 마스터 하트비트에는 영향을 주지 않습니다.
+*/
+func (s *StakerHost) recordHeartbeatOnChain() {
+	if !s.config.GasSponsorship {
+		return
+	}
+
+	s.heartbeatCount++
+	if s.heartbeatCount%s.config.OnChainHeartbeatEvery != 0 {
+		return
+	}
+
+	digest, err := s.executeSponsoredCall("worker_registry", "update_heartbeat")
+	if err != nil {
+		log.Printf("⚠️ 온체인 하트비트 기록 실패: %v", err)
+		return
+	}
+	log.Printf("⛽ 스폰서 가스로 온체인 하트비트 기록: %s", digest)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+)
+
+/*
+스테이킹 양 조정 - 노드를 다시 등록하지 않고 기존 스테이킹 객체에 추가 예치/부분 인출
+
+컨트랙트가 StakeAmountChangedEvent를 내면 마스터가 워커의 스테이킹 양과
+stake-tier 노드 라벨을 갱신하고, 워커는 하트비트의 stake_amount/stake_tier로 같은 값을 알립니다.
+전부 인출은 /api/v1/unstake(등록 해제)를 사용합니다.
+*/
+
+// currentStakeAmount - 스테이킹 감시가 마지막으로 확인한 양 (조회 전이면 등록 시점 값)
+func (s *StakerHost) currentStakeAmount() uint64 {
+	if info, _ := s.stakeMonitor.last(); info != nil {
+		return info.Amount
+	}
+	return s.stakingStatus.StakeAmount()
+}
+
+/*
+topUpStake - 스테이킹 추가 예치
+coins는 합쳐서 낼 코인 Object ID 목록입니다 (비어 있으면 가스 코인에서 amount만큼 분할).
+*/
+func (s *StakerHost) topUpStake(amount uint64, coins []string) (uint64, error) {
+	if !s.stakingStatus.IsStaked() {
+		return 0, fmt.Errorf("스테이킹되지 않은 노드입니다")
+	}
+	if amount == 0 {
+		return 0, fmt.Errorf("추가 예치 양은 0보다 커야 합니다")
+	}
+	if coins == nil {
+		coins = []string{}
+	}
+
+	before := s.currentStakeAmount()
+	after, err := s.submitStakeChange(chain.NewTx("staking", "top_up_stake", s.config.NodeID, amount, coins), before+amount)
+	if err != nil {
+		return 0, fmt.Errorf("추가 예치 실패: %v", err)
+	}
+	log.Printf("💰 스테이킹 추가 예치: %d → %d MIST (티어 %s)", before, after, s.stakeTier(after))
+	return after, nil
+}
+
+/*
+withdrawStake - 스테이킹 부분 인출
+남는 양이 노드 역할의 티어 최소치 이상이어야 합니다 (컨트랙트에서도 동일하게 검증).
+*/
+func (s *StakerHost) withdrawStake(amount uint64) (uint64, error) {
+	if !s.stakingStatus.IsStaked() {
+		return 0, fmt.Errorf("스테이킹되지 않은 노드입니다")
+	}
+	before := s.currentStakeAmount()
+	required := s.config.StakeTiers[s.config.NodeRole]
+	if amount == 0 || amount > before || before-amount < required {
+		return 0, fmt.Errorf("%d MIST 인출 불가: 현재 %d MIST, %s 역할 최소 %d MIST 유지 필요 (전부 인출은 unstake)",
+			amount, before, s.config.NodeRole, required)
+	}
+
+	after, err := s.submitStakeChange(chain.NewTx("staking", "withdraw_stake", s.config.NodeID, amount), before-amount)
+	if err != nil {
+		return 0, fmt.Errorf("부분 인출 실패: %v", err)
+	}
+	log.Printf("💸 스테이킹 부분 인출: %d → %d MIST (티어 %s)", before, after, s.stakeTier(after))
+	return after, nil
+}
+
+// submitStakeChange - 트랜잭션 실행 후 이벤트의 new_amount(없으면 expected)로 로컬 상태 갱신
+func (s *StakerHost) submitStakeChange(tx *chain.Tx, expected uint64) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	result, err := s.suiClient.backend.SubmitTx(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	amount := expected
+	for _, event := range result.Events {
+		if !strings.HasSuffix(event.Type, "::StakeAmountChangedEvent") {
+			continue
+		}
+		if value, err := strconv.ParseUint(fmt.Sprint(event.Data["new_amount"]), 10, 64); err == nil {
+			amount = value
+		}
+	}
+
+	s.stakingStatus.Update(func(status *StakingStatus) {
+		status.StakeAmount = amount
+		status.LastValidation = time.Now().Unix()
+	})
+	s.saveStakingState()
+	// 다음 하트비트부터 새 양을 알리고, 올라간 객체 버전은 감시 루프가 바로 재조회
+	s.stakeMonitor.setAmount(amount)
+	s.stakeMonitor.request()
+	return amount, nil
+}
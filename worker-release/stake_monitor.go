@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+/*
+스테이킹 감시 - 마스터 하트비트와 분리된 Sui 스테이킹 상태 확인
+Sui RPC 장애가 하트비트(생존 신호)를 막지 않도록 별도 고루틴에서 자체 재시도 일정으로
+스테이킹 객체를 조회합니다. 하트비트는 마지막으로 확인된 값을 보고합니다.
+슬래싱/객체 삭제는 연속 N회의 유효한 조회(RPC 오류 제외)로 확인된 경우에만 노드를 종료합니다.
+*/
+const (
+	stakeCheckInterval        = 60 * time.Second // 정상 상태 조회 주기
+	stakeCheckRetryBase       = 5 * time.Second  // 조회 실패 시 첫 재시도 간격 (실패마다 2배)
+	stakeCheckRetryMax        = 2 * time.Minute  // 재시도 간격 상한
+	stakeConfirmInterval      = 10 * time.Second // 슬래싱 감지 후 재확인 간격
+	defaultSlashConfirmations = 3
+)
+
+// errStakeObjectGone - 스테이킹 객체가 삭제/래핑됨 (인출 또는 몰수)
+var errStakeObjectGone = errors.New("stake_object_deleted")
+
+// stakeMonitor - 마지막 스테이킹 조회 결과와 재시도/확인 상태
+type stakeMonitor struct {
+	mu           sync.Mutex
+	info         *StakeInfo    // 마지막 유효 조회 결과 (nil이면 아직 없음)
+	checkedAt    time.Time     // 마지막 유효 조회 시각
+	lastErr      error         // 마지막 조회 오류
+	failures     int           // 연속 조회 실패 횟수
+	slashedReads int           // 연속 슬래싱/삭제 확인 횟수
+	requests     chan struct{} // 즉시 조회 요청 큐 (중복 요청은 하나로 합쳐짐)
+}
+
+func newStakeMonitor() *stakeMonitor {
+	return &stakeMonitor{requests: make(chan struct{}, 1)}
+}
+
+// request - 다음 주기를 기다리지 않고 조회 요청 (이미 대기 중이면 무시)
+func (m *stakeMonitor) request() {
+	select {
+	case m.requests <- struct{}{}:
+	default:
+	}
+}
+
+// last - 하트비트에 실을 마지막 확인 결과
+func (m *stakeMonitor) last() (*StakeInfo, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.info, m.checkedAt
+}
+
+// setAmount - 추가 예치/부분 인출 직후 마지막 확인 결과의 양 갱신
+func (m *stakeMonitor) setAmount(amount uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.info != nil {
+		updated := *m.info
+		updated.Amount = amount
+		m.info = &updated
+	}
+}
+
+// snapshot - 상태 API용 요약
+func (m *stakeMonitor) snapshot() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := map[string]interface{}{
+		"consecutive_failures": m.failures,
+		"slashed_reads":        m.slashedReads,
+	}
+	if !m.checkedAt.IsZero() {
+		snapshot["checked_at"] = m.checkedAt.Unix()
+	}
+	if m.lastErr != nil {
+		snapshot["last_error"] = m.lastErr.Error()
+	}
+	return snapshot
+}
+
+// retryDelay - 연속 실패 횟수에 따른 지수 백오프
+func retryDelay(failures int) time.Duration {
+	delay := stakeCheckRetryBase
+	for i := 1; i < failures && delay < stakeCheckRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > stakeCheckRetryMax {
+		delay = stakeCheckRetryMax
+	}
+	return delay
+}
+
+/*
+runStakeMonitor - 스테이킹 상태 조회 루프 (블로킹)
+슬래싱이 SlashConfirmations회 연속 확인되면 Shutdown을 호출합니다.
+*/
+func (s *StakerHost) runStakeMonitor() {
+	m := s.stakeMonitor
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-m.requests:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		next := s.checkStakeOnce()
+		if next == 0 {
+			return
+		}
+		timer.Reset(next)
+	}
+}
+
+// checkStakeOnce - 한 번 조회하고 다음 조회까지의 간격 반환 (종료 시 0)
+func (s *StakerHost) checkStakeOnce() time.Duration {
+	m := s.stakeMonitor
+	info, err := s.checkStakeOnSui()
+
+	m.mu.Lock()
+	gone := errors.Is(err, errStakeObjectGone)
+	switch {
+	case err != nil && !gone:
+		// RPC 장애/응답 이상 - 판단 보류, 슬래싱 확인 카운터는 유지
+		m.failures++
+		m.lastErr = err
+		failures := m.failures
+		m.mu.Unlock()
+
+		delay := retryDelay(failures)
+		log.Printf("⚠️ 스테이킹 상태 조회 실패 (%d회 연속, %s 후 재시도): %v", failures, delay, err)
+		return delay
+
+	case gone || info.Status == "slashed":
+		m.failures = 0
+		m.lastErr = nil
+		m.slashedReads++
+		reads := m.slashedReads
+		m.mu.Unlock()
+
+		status := stakingSlashed
+		if gone {
+			status = stakingWithdrawn // 객체가 소비됨 (인출 또는 몰수)
+		}
+		if reads < s.config.SlashConfirmations {
+			log.Printf("🚨 스테이킹 %s 감지 (%d/%d), %s 후 재확인", status, reads, s.config.SlashConfirmations, stakeConfirmInterval)
+			return stakeConfirmInterval
+		}
+
+		if err := s.stakingStatus.Transition(status, nil); err != nil {
+			// 이미 해제/인출된 상태여도 종료는 계속 (상태는 그대로 둠)
+			log.Printf("⚠️ %v", err)
+		}
+		s.saveStakingState()
+		log.Printf("🛑 스테이킹 %s가 %d회 연속 확인되었습니다! 노드를 종료합니다...", status, reads)
+		if !gone {
+			// 종료 전에 증거를 남기고 이의 신청 (인출로 소비된 경우는 제외)
+			s.fileSlashAppeal()
+		}
+		s.Shutdown()
+		return 0
+
+	default:
+		if m.slashedReads > 0 {
+			log.Printf("✅ 슬래싱 감지가 확인되지 않아 카운터 리셋 (%d회 감지)", m.slashedReads)
+		}
+		if m.failures > 0 {
+			log.Printf("✅ 스테이킹 상태 조회 복구됨 (%d회 실패 후)", m.failures)
+		}
+		m.info = info
+		m.checkedAt = time.Now()
+		m.failures = 0
+		m.slashedReads = 0
+		m.lastErr = nil
+		m.mu.Unlock()
+		return stakeCheckInterval
+	}
+}
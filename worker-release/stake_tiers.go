@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// 노드 역할 (worker_registry.move의 역할과 동일)
+const (
+	nodeRoleWorker  = "worker"
+	nodeRoleEdge    = "edge"
+	nodeRoleStorage = "storage"
+)
+
+// defaultStakeTierMultipliers - MinStakeAmount 대비 역할별 기본 배수
+var defaultStakeTierMultipliers = map[string]uint64{
+	nodeRoleWorker:  1,
+	nodeRoleEdge:    2,
+	nodeRoleStorage: 10,
+}
+
+/*
+applyStakeTierDefaults - 역할 기본값과 티어 테이블 채우기
+stake_tiers에 없는 역할은 MinStakeAmount × 기본 배수로 설정합니다.
+*/
+func applyStakeTierDefaults(config *StakerHostConfig) error {
+	if config.NodeRole == "" {
+		config.NodeRole = nodeRoleWorker
+	}
+	if _, ok := defaultStakeTierMultipliers[config.NodeRole]; !ok {
+		return fmt.Errorf("지원하지 않는 노드 역할: %s (worker, edge, storage 중 선택)", config.NodeRole)
+	}
+
+	if config.StakeTiers == nil {
+		config.StakeTiers = make(map[string]uint64)
+	}
+	for role, multiplier := range defaultStakeTierMultipliers {
+		if _, ok := config.StakeTiers[role]; !ok {
+			config.StakeTiers[role] = config.MinStakeAmount * multiplier
+		}
+	}
+	return nil
+}
+
+// stakeTier - 스테이킹 양이 충족하는 가장 높은 역할 티어 (하트비트/상태 API로 알림)
+func (s *StakerHost) stakeTier(amount uint64) string {
+	tier := ""
+	var best uint64
+	for role, required := range s.config.StakeTiers {
+		if amount >= required && (tier == "" || required > best) {
+			tier, best = role, required
+		}
+	}
+	return tier
+}
+
+/*
+validateStakeTier - 설정된 스테이킹 양이 역할 티어를 만족하는지 확인
+부족하면 가스를 소모하는 트랜잭션을 보내기 전에 실패시킵니다.
+*/
+func (s *StakerHost) validateStakeTier() error {
+	required := s.config.StakeTiers[s.config.NodeRole]
+	if s.config.StakeAmount < required {
+		return fmt.Errorf("%s 역할은 최소 %d MIST 스테이킹이 필요합니다 (설정: %d)", s.config.NodeRole, required, s.config.StakeAmount)
+	}
+
+	log.Printf("🏷️ 노드 역할: %s (티어 최소 %d MIST, 스테이킹 %d MIST)", s.config.NodeRole, required, s.config.StakeAmount)
+	return nil
+}
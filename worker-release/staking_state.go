@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+)
+
+const (
+	defaultStakingStatePath    = "/var/lib/k3s-daas-agent/staking-state.enc"
+	defaultStakingStateKeyPath = "/var/lib/k3s-daas-agent/staking-state.key"
+	stakingStateFormat         = 1
+)
+
+/*
+applyStakingStateDefaults - 스테이킹 상태 파일과 암호화 키 경로
+- K3S_DAAS_STAKING_STATE_PATH: 암호화된 상태 파일
+- K3S_DAAS_STAKING_STATE_KEY: 키 (hex 32바이트, 설정 시 키 파일 대신 사용 - 시크릿 저장소에서 주입)
+- K3S_DAAS_STAKING_STATE_KEY_PATH: 키 파일 (없으면 첫 실행 시 0600으로 생성)
+*/
+func applyStakingStateDefaults(config *StakerHostConfig) {
+	if path := os.Getenv("K3S_DAAS_STAKING_STATE_PATH"); path != "" {
+		config.StakingStatePath = path
+	}
+	if path := os.Getenv("K3S_DAAS_STAKING_STATE_KEY_PATH"); path != "" {
+		config.StakingStateKeyPath = path
+	}
+	if config.StakingStatePath == "" {
+		config.StakingStatePath = defaultStakingStatePath
+	}
+	if config.StakingStateKeyPath == "" {
+		config.StakingStateKeyPath = defaultStakingStateKeyPath
+	}
+}
+
+// persistedStaking - 암호화 전 평문 (다른 노드/지갑의 파일을 잘못 읽지 않도록 식별자 포함)
+type persistedStaking struct {
+	NodeID        string        `json:"node_id"`
+	WalletAddress string        `json:"wallet_address"`
+	Staking       StakingStatus `json:"staking"`
+	SavedAt       int64         `json:"saved_at"`
+}
+
+// stakingStateEnvelope - 디스크 형식 (AES-256-GCM, 노드 ID를 추가 인증 데이터로 사용)
+type stakingStateEnvelope struct {
+	Format     int    `json:"format"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+/*
+stakingStore - Seal 토큰과 스테이킹 객체 ID를 암호화해 저장
+Seal 토큰은 마스터 인증 자격 증명이므로 평문으로 디스크에 남기지 않습니다.
+쓰기는 임시 파일 기록 → fsync → rename → 디렉터리 fsync 순서로, 어느 시점에 전원이 꺼져도
+이전 상태나 새 상태 중 하나만 남습니다.
+*/
+type stakingStore struct {
+	mu      sync.Mutex
+	path    string
+	keyPath string
+	nodeID  string
+	wallet  string
+}
+
+func newStakingStore(config *StakerHostConfig) *stakingStore {
+	return &stakingStore{
+		path:    config.StakingStatePath,
+		keyPath: config.StakingStateKeyPath,
+		nodeID:  config.NodeID,
+		wallet:  config.SuiWalletAddress,
+	}
+}
+
+// key - 환경변수 키, 없으면 키 파일 (create면 없을 때 생성)
+func (st *stakingStore) key(create bool) ([]byte, error) {
+	if value := os.Getenv("K3S_DAAS_STAKING_STATE_KEY"); value != "" {
+		key, err := hex.DecodeString(strings.TrimSpace(value))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("K3S_DAAS_STAKING_STATE_KEY는 hex 32바이트여야 함")
+		}
+		return key, nil
+	}
+
+	data, err := os.ReadFile(st.keyPath)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("잘못된 상태 키 파일: %s", st.keyPath)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := writeFileSynced(st.keyPath, []byte(hex.EncodeToString(key)+"\n")); err != nil {
+		return nil, fmt.Errorf("상태 키 파일 생성 실패: %v", err)
+	}
+	log.Printf("🔑 스테이킹 상태 키 생성: %s", st.keyPath)
+	return key, nil
+}
+
+func (st *stakingStore) gcm(create bool) (cipher.AEAD, error) {
+	key, err := st.key(create)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// save - 현재 스테이킹 상태를 암호화해 원자적으로 저장
+func (st *stakingStore) save(status StakingStatus) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	aead, err := st.gcm(true)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(persistedStaking{
+		NodeID: st.nodeID, WalletAddress: st.wallet, Staking: status, SavedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stakingStateEnvelope{
+		Format:     stakingStateFormat,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, []byte(st.nodeID))),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileSynced(st.path, data)
+}
+
+// load - 저장된 상태 (파일이 없으면 nil, 복호화/식별자 불일치는 오류)
+func (st *stakingStore) load() (*persistedStaking, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	data, err := os.ReadFile(st.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var envelope stakingStateEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("상태 파일 형식 오류: %v", err)
+	}
+	if envelope.Format != stakingStateFormat {
+		return nil, fmt.Errorf("지원하지 않는 상태 파일 형식 %d", envelope.Format)
+	}
+
+	aead, err := st.gcm(false)
+	if err != nil {
+		return nil, fmt.Errorf("상태 키 읽기 실패: %v", err)
+	}
+	nonce, err1 := hex.DecodeString(envelope.Nonce)
+	ciphertext, err2 := hex.DecodeString(envelope.Ciphertext)
+	if err1 != nil || err2 != nil || len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("상태 파일 형식 오류")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(st.nodeID))
+	if err != nil {
+		return nil, fmt.Errorf("복호화 실패 (다른 노드의 파일이거나 키가 바뀜)")
+	}
+
+	var state persistedStaking
+	if err := json.Unmarshal(plaintext, &state); err != nil {
+		return nil, err
+	}
+	if state.WalletAddress != st.wallet {
+		return nil, fmt.Errorf("지갑 주소 불일치: 저장 %s, 설정 %s", state.WalletAddress, st.wallet)
+	}
+	return &state, nil
+}
+
+// quarantine - 읽을 수 없는 상태 파일을 옆으로 옮김 (삭제하지 않고 운영자가 확인할 수 있게)
+func (st *stakingStore) quarantine() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	aside := fmt.Sprintf("%s.invalid-%d", st.path, time.Now().Unix())
+	if err := os.Rename(st.path, aside); err == nil {
+		log.Printf("🗃️ 읽을 수 없는 스테이킹 상태 파일 보관: %s", aside)
+	}
+}
+
+// writeFileSynced - 임시 파일 fsync 후 rename, 디렉터리까지 fsync (전원 손실에도 반쯤 쓴 파일이 남지 않음)
+func writeFileSynced(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// 디렉터리 fsync는 Windows에서 지원되지 않으므로 실패해도 무시
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// saveStakingState - 스테이킹 상태가 바뀔 때마다 호출 (실패해도 실행은 계속, 다음 변경 때 다시 저장)
+func (s *StakerHost) saveStakingState() {
+	if s.stakingStore == nil {
+		return
+	}
+	if err := s.stakingStore.save(s.stakingStatus.Snapshot()); err != nil {
+		log.Printf("⚠️ 스테이킹 상태 저장 실패: %v", err)
+	}
+}
+
+/*
+ensureStake - 저장된 스테이킹 상태를 체인과 대조한 뒤 필요할 때만 새로 등록
+- 저장된 객체가 체인에서 active: 그대로 재사용 (재시작마다 중복 스테이킹하지 않음)
+- 객체가 삭제/인출/슬래싱됨: 저장 상태를 버리고 새로 등록
+- 체인 조회 실패: 판단할 수 없으므로 등록하지 않고 오류 반환 (중복 스테이킹 방지)
+*/
+func (s *StakerHost) ensureStake() error {
+	state, err := s.stakingStore.load()
+	if err != nil {
+		log.Printf("⚠️ 저장된 스테이킹 상태 무시: %v", err)
+		s.stakingStore.quarantine()
+		state = nil
+	}
+	if state == nil || state.Staking.StakeObjectID == "" || state.Staking.SealToken == "" {
+		return s.registerFreshStake()
+	}
+
+	restored := state.Staking
+	log.Printf("💾 저장된 스테이킹 상태 발견: %s (저장 %s)", restored.StakeObjectID, time.Unix(state.SavedAt, 0).Format(time.RFC3339))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	stake, err := s.suiClient.backend.ValidateStake(ctx, chain.StakeRef{
+		NodeID: s.config.NodeID, ObjectID: restored.StakeObjectID,
+	}, 0)
+	switch {
+	case errors.Is(err, chain.ErrDeleted):
+		log.Printf("🗑️ 저장된 스테이킹 객체가 체인에 없음 (인출/몰수), 새로 등록")
+		return s.registerFreshStake()
+	case errors.Is(err, chain.ErrInsufficientStake) && stake != nil:
+		log.Printf("🚨 저장된 스테이킹 객체 상태: %s, 새로 등록", stake.Status)
+		return s.registerFreshStake()
+	case err != nil:
+		return fmt.Errorf("저장된 스테이킹 상태를 체인과 대조할 수 없음 (중복 등록 방지를 위해 중단): %v", err)
+	}
+	if stake.ObjectID != "" && stake.ObjectID != restored.StakeObjectID {
+		return fmt.Errorf("체인의 스테이킹 객체 %s가 저장된 객체 %s와 다름 - 다른 호스트가 같은 노드 ID로 등록했는지 확인하세요",
+			stake.ObjectID, restored.StakeObjectID)
+	}
+	if stake.Version != 0 && stake.Version < restored.StakeObjectVersion {
+		return fmt.Errorf("스테이킹 객체 버전 역행: 저장 %d, 체인 %d (RPC 노드 응답 신뢰 불가)", restored.StakeObjectVersion, stake.Version)
+	}
+
+	// 체인에서 확인했으므로 저장된 상태 값과 관계없이 pending → active
+	if err := s.stakingStatus.Transition(stakingActive, func(status *StakingStatus) {
+		*status = restored
+		status.StakeAmount = stake.Amount
+		status.StakeObjectVersion = stake.Version
+		status.LastValidation = time.Now().Unix()
+	}); err != nil {
+		return err
+	}
+	s.sealToken = restored.SealToken
+	s.stakeMonitor.setAmount(stake.Amount)
+	s.saveStakingState()
+
+	log.Printf("✅ 기존 스테이킹 재사용: %s (%d MIST), 새 트랜잭션 없이 재등록", restored.StakeObjectID, stake.Amount)
+	return nil
+}
+
+// registerFreshStake - 새 스테이킹 등록 후 즉시 저장 (등록 직후 크래시해도 다음 시작에서 재사용)
+func (s *StakerHost) registerFreshStake() error {
+	if err := s.RegisterStake(); err != nil {
+		// 이전 실행의 상태 파일을 잃은 채 이미 스테이킹된 노드는 컨트랙트가 재등록을 거부함
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if stake, lookupErr := s.suiClient.backend.ValidateStake(ctx, chain.StakeRef{NodeID: s.config.NodeID}, 0); lookupErr == nil && stake.Status == "active" {
+			return fmt.Errorf("%v (노드 %s에 활성 스테이킹 %s가 이미 있음 - 상태 파일 %s가 없다면 unstake 후 다시 등록하세요)",
+				err, s.config.NodeID, stake.ObjectID, s.config.StakingStatePath)
+		}
+		return err
+	}
+	s.saveStakingState()
+	return nil
+}
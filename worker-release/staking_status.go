@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// 스테이킹 상태 값 (StakingStatus.Status)
+const (
+	stakingPending   = "pending"   // 등록 전
+	stakingActive    = "active"    // 스테이킹 확인됨
+	stakingSlashed   = "slashed"   // 체인에서 슬래싱 확인 (스테이킹은 잠긴 채 남음)
+	stakingWithdrawn = "withdrawn" // 스테이킹 객체가 소비됨 (인출 또는 몰수)
+	stakingUnstaked  = "unstaked"  // 관리 API로 스테이킹 해제
+)
+
+/*
+stakingTransitions - 허용하는 상태 전이
+
+	pending → active → slashed → withdrawn
+	                 ↘ withdrawn
+	                 ↘ unstaked
+
+withdrawn과 unstaked는 종료 상태입니다 (다시 스테이킹하려면 워커를 재시작해 pending부터 등록).
+*/
+var stakingTransitions = map[string][]string{
+	stakingPending:   {stakingActive},
+	stakingActive:    {stakingSlashed, stakingWithdrawn, stakingUnstaked},
+	stakingSlashed:   {stakingWithdrawn},
+	stakingWithdrawn: {},
+	stakingUnstaked:  {},
+}
+
+// stakingTransitionError - 허용되지 않은 전이 (상태는 바뀌지 않음)
+type stakingTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *stakingTransitionError) Error() string {
+	return fmt.Sprintf("허용되지 않은 스테이킹 상태 전이: %s → %s", e.From, e.To)
+}
+
+// stakingTransitionHook - 전이 후 호출 (잠금 밖에서 등록 순서대로)
+type stakingTransitionHook func(from, to string, status StakingStatus)
+
+/*
+StakingState - 스테이킹 상태 머신
+
+HTTP 핸들러, 하트비트/스테이킹 감시 고루틴, 등록 흐름이 같은 상태를 읽고 쓰므로 모든 접근을 잠금으로 보호합니다.
+Status와 IsStaked는 Transition으로만 바꿀 수 있고, 그 외 필드(스테이킹 양, 객체 버전, 검증 시각 등)는 Update로 바꿉니다.
+*/
+type StakingState struct {
+	mu          sync.RWMutex
+	status      StakingStatus
+	hooks       []stakingTransitionHook
+	transitions map[string]uint64 // "from→to"별 전이 수
+	illegal     uint64            // 거부한 전이 수
+}
+
+// newStakingState - pending 상태로 시작
+func newStakingState() *StakingState {
+	return &StakingState{
+		status:      StakingStatus{Status: stakingPending},
+		transitions: make(map[string]uint64),
+	}
+}
+
+// OnTransition - 전이 훅 등록 (로그, 상태 저장 등)
+func (s *StakingState) OnTransition(hook stakingTransitionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+/*
+Transition - 상태 전이 후 apply로 나머지 필드를 같은 잠금 안에서 갱신
+허용되지 않은 전이면 아무것도 바꾸지 않고 *stakingTransitionError를 반환합니다.
+IsStaked는 전이 대상에 따라 정해집니다 (active/slashed면 true).
+*/
+func (s *StakingState) Transition(to string, apply func(status *StakingStatus)) error {
+	s.mu.Lock()
+	from := s.status.Status
+	if !stakingTransitionAllowed(from, to) {
+		s.illegal++
+		s.mu.Unlock()
+		return &stakingTransitionError{From: from, To: to}
+	}
+	if apply != nil {
+		apply(&s.status)
+	}
+	s.status.Status = to
+	s.status.IsStaked = to == stakingActive || to == stakingSlashed
+	s.transitions[from+"→"+to]++
+	snapshot := s.status
+	hooks := append([]stakingTransitionHook(nil), s.hooks...)
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(from, to, snapshot)
+	}
+	return nil
+}
+
+// stakingTransitionAllowed - from에서 to로 갈 수 있는지
+func stakingTransitionAllowed(from, to string) bool {
+	for _, next := range stakingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Update - 상태 값 외 필드 갱신 (apply가 Status/IsStaked를 바꿔도 되돌림)
+func (s *StakingState) Update(apply func(status *StakingStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, staked := s.status.Status, s.status.IsStaked
+	apply(&s.status)
+	s.status.Status, s.status.IsStaked = state, staked
+}
+
+/*
+advanceObjectVersion - 체인에서 읽은 스테이킹 객체 버전 반영 (비교와 갱신을 한 번에)
+버전은 단조 증가해야 하므로 역행하면 오류, 바뀌었으면 changed=true (호출자가 저장)
+*/
+func (s *StakingState) advanceObjectVersion(version uint64) (changed bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.status.StakeObjectVersion
+	if version < current {
+		return false, fmt.Errorf("스테이킹 객체 버전 역행: %d → %d (RPC 노드 응답 신뢰 불가)", current, version)
+	}
+	s.status.StakeObjectVersion = version
+	return version != current, nil
+}
+
+// Snapshot - 현재 상태 복사본 (저장, 응답용)
+func (s *StakingState) Snapshot() StakingStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Status - 현재 상태 값
+func (s *StakingState) Status() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.Status
+}
+
+// IsStaked - 스테이킹이 유지되는 상태인지 (active, slashed)
+func (s *StakingState) IsStaked() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.IsStaked
+}
+
+// SealToken - 마스터 인증용 Seal 토큰
+func (s *StakingState) SealToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.SealToken
+}
+
+// StakeAmount - 로컬에 기록된 스테이킹 양 (MIST)
+func (s *StakingState) StakeAmount() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.StakeAmount
+}
+
+// StakeObjectID - 체인의 스테이킹 객체 ID
+func (s *StakingState) StakeObjectID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.StakeObjectID
+}
+
+// MarshalJSON - 기존 응답 형식 유지 (StakingStatus와 같은 JSON)
+func (s *StakingState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Snapshot())
+}
+
+// metrics - /api/v1/metrics의 전이 수와 거부한 전이 수
+func (s *StakingState) metrics() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	transitions := make(map[string]uint64, len(s.transitions))
+	for key, count := range s.transitions {
+		transitions[key] = count
+	}
+	return map[string]interface{}{
+		"status":      s.status.Status,
+		"transitions": transitions,
+		"illegal":     s.illegal,
+	}
+}
+
+// logStakingTransition - 기본 전이 훅 (상태 변경 로그)
+func logStakingTransition(from, to string, status StakingStatus) {
+	log.Printf("🔁 스테이킹 상태 전이: %s → %s (객체 %s, %d MIST)", from, to, status.StakeObjectID, status.StakeAmount)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestStakingStateLifecycle(t *testing.T) {
+	state := newStakingState()
+	if state.Status() != stakingPending || state.IsStaked() {
+		t.Fatalf("new state = %s staked=%v, want pending and not staked", state.Status(), state.IsStaked())
+	}
+
+	var seen []string
+	state.OnTransition(func(from, to string, status StakingStatus) {
+		seen = append(seen, from+"→"+to)
+		if status.Status != to {
+			t.Errorf("hook snapshot status = %s, want %s", status.Status, to)
+		}
+	})
+
+	if err := state.Transition(stakingActive, func(status *StakingStatus) {
+		status.SealToken = "seal-token"
+		status.StakeAmount = 1000
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsStaked() || state.SealToken() != "seal-token" || state.StakeAmount() != 1000 {
+		t.Fatalf("after activation: %+v", state.Snapshot())
+	}
+	if err := state.Transition(stakingSlashed, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsStaked() {
+		t.Fatal("slashed stake should still count as staked")
+	}
+	if err := state.Transition(stakingWithdrawn, nil); err != nil {
+		t.Fatal(err)
+	}
+	if state.IsStaked() {
+		t.Fatal("withdrawn stake should not count as staked")
+	}
+
+	want := []string{"pending→active", "active→slashed", "slashed→withdrawn"}
+	if len(seen) != len(want) {
+		t.Fatalf("hooks saw %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("hooks saw %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestStakingStateIllegalTransitions(t *testing.T) {
+	cases := []struct {
+		name string
+		path []string // 앞선 전이 (모두 허용되어야 함)
+		to   string
+	}{
+		{"pending to slashed", nil, stakingSlashed},
+		{"pending to unstaked", nil, stakingUnstaked},
+		{"active to active", []string{stakingActive}, stakingActive},
+		{"active to pending", []string{stakingActive}, stakingPending},
+		{"slashed to active", []string{stakingActive, stakingSlashed}, stakingActive},
+		{"slashed to unstaked", []string{stakingActive, stakingSlashed}, stakingUnstaked},
+		{"unstaked to active", []string{stakingActive, stakingUnstaked}, stakingActive},
+		{"withdrawn to slashed", []string{stakingActive, stakingWithdrawn}, stakingSlashed},
+		{"unknown state", []string{stakingActive}, "busy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := newStakingState()
+			for _, step := range tc.path {
+				if err := state.Transition(step, nil); err != nil {
+					t.Fatalf("setup transition to %s: %v", step, err)
+				}
+			}
+			before := state.Snapshot()
+			hookCalled := false
+			state.OnTransition(func(string, string, StakingStatus) { hookCalled = true })
+
+			applied := false
+			err := state.Transition(tc.to, func(status *StakingStatus) {
+				applied = true
+				status.SealToken = "changed"
+			})
+			var transitionErr *stakingTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("err = %v, want *stakingTransitionError", err)
+			}
+			if transitionErr.From != before.Status || transitionErr.To != tc.to {
+				t.Fatalf("error = %+v, want %s → %s", transitionErr, before.Status, tc.to)
+			}
+			if applied || hookCalled {
+				t.Fatalf("rejected transition ran apply=%v hook=%v", applied, hookCalled)
+			}
+			if after := state.Snapshot(); after != before {
+				t.Fatalf("state changed by rejected transition: %+v → %+v", before, after)
+			}
+			if illegal := state.metrics()["illegal"].(uint64); illegal != 1 {
+				t.Fatalf("illegal count = %d, want 1", illegal)
+			}
+		})
+	}
+}
+
+func TestStakingStateUpdateKeepsStatus(t *testing.T) {
+	state := newStakingState()
+	state.Update(func(status *StakingStatus) {
+		status.Status = stakingActive
+		status.IsStaked = true
+		status.LastValidation = 42
+	})
+	snapshot := state.Snapshot()
+	if snapshot.Status != stakingPending || snapshot.IsStaked {
+		t.Fatalf("Update changed the state: %+v", snapshot)
+	}
+	if snapshot.LastValidation != 42 {
+		t.Fatalf("LastValidation = %d, want 42", snapshot.LastValidation)
+	}
+}
+
+func TestStakingStateObjectVersion(t *testing.T) {
+	state := newStakingState()
+	if changed, err := state.advanceObjectVersion(5); err != nil || !changed {
+		t.Fatalf("advance to 5: changed=%v err=%v", changed, err)
+	}
+	if changed, err := state.advanceObjectVersion(5); err != nil || changed {
+		t.Fatalf("same version: changed=%v err=%v", changed, err)
+	}
+	if _, err := state.advanceObjectVersion(4); err == nil {
+		t.Fatal("version regression was accepted")
+	}
+	if version := state.Snapshot().StakeObjectVersion; version != 5 {
+		t.Fatalf("version = %d after regression, want 5", version)
+	}
+}
+
+func TestStakingStateConcurrentTransitions(t *testing.T) {
+	state := newStakingState()
+	if err := state.Transition(stakingActive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 여러 고루틴이 동시에 종료 상태로 보내도 하나만 성공해야 함
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			to := stakingUnstaked
+			if i%2 == 0 {
+				to = stakingWithdrawn
+			}
+			if state.Transition(to, nil) == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+			state.Update(func(status *StakingStatus) { status.LastValidation++ })
+			_ = state.SealToken()
+			if _, err := json.Marshal(state); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d terminal transitions succeeded, want 1", succeeded)
+	}
+	if illegal := state.metrics()["illegal"].(uint64); illegal != 31 {
+		t.Fatalf("illegal count = %d, want 31", illegal)
+	}
+	if validations := state.Snapshot().LastValidation; validations != 32 {
+		t.Fatalf("LastValidation = %d, want 32 updates", validations)
+	}
+}
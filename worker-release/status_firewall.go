@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	httpserver "github.com/k3s-io/daas-httpserver"
+)
+
+/*
+StatusFirewallConfig - 상태 서버 포트(기본 :10260) 접근 제한
+
+기본적으로 loopback, 마스터 엔드포인트 주소, 마스터가 하트비트 응답으로 보낸 허용 목록(status_access),
+allowed_cidrs만 접근할 수 있습니다. 다른 워커는 대역폭 측정 경로(/api/v1/bandwidth-probe)만 호출할 수 있습니다.
+원격에서 관리자 API(/api/v1/stake/* 등)를 쓰려면 운영 네트워크를 allowed_cidrs에 넣어야 합니다.
+*/
+type StatusFirewallConfig struct {
+	Disabled          bool     `json:"disabled"`
+	AllowedCIDRs      []string `json:"allowed_cidrs"`       // 항상 허용할 운영/모니터링 주소 (CIDR 또는 IP)
+	RequireClientCert bool     `json:"require_client_cert"` // 측정 경로 외 원격 요청에 마스터 CA 클라이언트 인증서 요구 (TLS 필요)
+	HostFirewall      bool     `json:"host_firewall"`       // iptables/ip6tables로 상태 포트 차단 규칙 자동 설정 (Linux, root)
+}
+
+const (
+	statusFirewallChain = "K3S-DAAS-STATUS"
+	peerProbePath       = "/api/v1/bandwidth-probe"
+)
+
+/*
+applyStatusFirewallDefaults - 상태 포트 접근 제한 환경변수 덮어쓰기
+- K3S_DAAS_STATUS_FIREWALL: off이면 제한 없음
+- K3S_DAAS_STATUS_ALLOWLIST: 쉼표로 구분한 CIDR/IP (allowed_cidrs 대체)
+- K3S_DAAS_STATUS_MTLS: true이면 클라이언트 인증서 요구
+- K3S_DAAS_STATUS_HOST_FIREWALL: true이면 iptables 규칙 설정
+*/
+func applyStatusFirewallDefaults(config *StakerHostConfig) error {
+	f := &config.StatusFirewall
+	if os.Getenv("K3S_DAAS_STATUS_FIREWALL") == "off" {
+		f.Disabled = true
+	}
+	if value := os.Getenv("K3S_DAAS_STATUS_ALLOWLIST"); value != "" {
+		f.AllowedCIDRs = strings.Split(value, ",")
+	}
+	if value := os.Getenv("K3S_DAAS_STATUS_MTLS"); value != "" {
+		f.RequireClientCert = value == "true"
+	}
+	if value := os.Getenv("K3S_DAAS_STATUS_HOST_FIREWALL"); value != "" {
+		f.HostFirewall = value == "true"
+	}
+	if _, err := httpserver.ParsePrefixes(f.AllowedCIDRs); err != nil {
+		return fmt.Errorf("잘못된 status_firewall.allowed_cidrs: %v", err)
+	}
+	return nil
+}
+
+// statusAccessPolicy - 마스터가 하트비트 응답으로 보내는 허용 목록과 mTLS 신원
+type statusAccessPolicy struct {
+	Version    string   `json:"version"`
+	CIDRs      []string `json:"cidrs"`
+	PeerCIDRs  []string `json:"peer_cidrs"`
+	ClientCA   string   `json:"client_ca"`
+	Identities []string `json:"identities"`
+}
+
+/*
+statusFirewall - 상태 서버 미들웨어와 호스트 방화벽 규칙
+허용 목록은 출처별로 관리합니다: config(allowed_cidrs), master-dns(마스터 엔드포인트 주소), master(마스터 정책).
+*/
+type statusFirewall struct {
+	config    StatusFirewallConfig
+	endpoints []string
+	allowed   *httpserver.Allowlist
+	peers     *httpserver.Allowlist
+	denied    atomic.Uint64
+
+	mutex      sync.Mutex
+	server     *httpserver.Server
+	port       string
+	clientCAs  *x509.CertPool // 상태 서버 연결 전에 정책을 받으면 연결 시 적용
+	version    string
+	identities map[string]bool
+	programmed bool
+}
+
+func newStatusFirewall(config *StakerHostConfig) *statusFirewall {
+	f := &statusFirewall{
+		config:     config.StatusFirewall,
+		endpoints:  append([]string{config.NautilusEndpoint}, config.NautilusFallbackEndpoints...),
+		allowed:    httpserver.NewAllowlist(),
+		peers:      httpserver.NewAllowlist(),
+		identities: make(map[string]bool),
+	}
+	f.allowed.Set("config", f.config.AllowedCIDRs) // applyStatusFirewallDefaults에서 검증됨
+	f.resolveMasters()
+	return f
+}
+
+// resolveMasters - 마스터 엔드포인트(예비 포함) 호스트의 현재 주소
+func (f *statusFirewall) resolveMasters() {
+	var addresses []string
+	for _, endpoint := range f.endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			addresses = append(addresses, ip.String())
+			continue
+		}
+		ips, err := net.LookupIP(u.Hostname())
+		if err != nil {
+			log.Printf("⚠️ 마스터 주소 조회 실패 (상태 포트 허용 목록): %s: %v", u.Hostname(), err)
+			continue
+		}
+		for _, ip := range ips {
+			addresses = append(addresses, ip.String())
+		}
+	}
+	f.allowed.Set("master-dns", addresses)
+}
+
+// attach - 상태 서버 연결 (포트는 호스트 방화벽 규칙, 서버는 클라이언트 CA 교체에 사용)
+func (f *statusFirewall) attach(server *httpserver.Server, tlsEnabled bool) error {
+	if f.config.RequireClientCert && !tlsEnabled {
+		return fmt.Errorf("status_firewall.require_client_cert에는 TLS가 필요합니다 (K3S_DAAS_TLS_CERT_FILE 또는 K3S_DAAS_TLS_SELF_SIGNED)")
+	}
+	_, port, err := net.SplitHostPort(server.Addr)
+	if err != nil {
+		return fmt.Errorf("상태 서버 주소 해석 실패: %v", err)
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.server, f.port = server, port
+	if f.config.RequireClientCert {
+		server.SetClientCAs(f.clientCAs) // CA는 마스터 정책을 받은 뒤 채워짐
+	}
+	return nil
+}
+
+// middleware - 허용 목록 밖의 요청 차단 (접근 로그보다 먼저 실행해 스캐너 요청으로 로그가 넘치지 않게 함)
+func (f *statusFirewall) middleware(next http.Handler) http.Handler {
+	if f.config.Disabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case f.allowed.Allows(r.RemoteAddr):
+			if f.config.RequireClientCert && !isLoopbackAddr(r.RemoteAddr) && r.URL.Path != peerProbePath && !f.trustedClient(r) {
+				f.denied.Add(1)
+				httpserver.WriteStatus(w, http.StatusForbidden, "Forbidden", "a client certificate from the master CA is required")
+				return
+			}
+		case r.URL.Path == peerProbePath && f.peers.Allows(r.RemoteAddr):
+			// 피어 워커 측정 요청 (측정 토큰은 핸들러에서 확인)
+		default:
+			f.denied.Add(1)
+			httpserver.WriteStatus(w, http.StatusForbidden, "Forbidden", "client address is not allowed")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// trustedClient - 마스터 CA로 검증된 인증서의 CN이 허용 신원인지 확인
+func (f *statusFirewall) trustedClient(r *http.Request) bool {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.identities[r.TLS.VerifiedChains[0][0].Subject.CommonName]
+}
+
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// appliedVersion - 하트비트로 보내는 적용 중인 정책 버전
+func (f *statusFirewall) appliedVersion() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.version
+}
+
+// apply - 마스터 정책 적용 (허용 목록, 클라이언트 CA, 호스트 방화벽 규칙)
+func (f *statusFirewall) apply(policy statusAccessPolicy) {
+	if err := f.allowed.Set("master", policy.CIDRs); err != nil {
+		log.Printf("⚠️ 마스터 상태 포트 허용 목록 무시: %v", err)
+		return
+	}
+	if err := f.peers.Set("peers", policy.PeerCIDRs); err != nil {
+		log.Printf("⚠️ 피어 워커 허용 목록 무시: %v", err)
+		return
+	}
+	f.resolveMasters()
+
+	var pool *x509.CertPool
+	if policy.ClientCA != "" {
+		if pool = x509.NewCertPool(); !pool.AppendCertsFromPEM([]byte(policy.ClientCA)) {
+			log.Printf("⚠️ 마스터 클라이언트 CA 해석 실패")
+			pool = nil
+		}
+	}
+
+	f.mutex.Lock()
+	if pool != nil {
+		f.clientCAs = pool
+		if f.server != nil && f.config.RequireClientCert {
+			f.server.SetClientCAs(pool)
+		}
+	}
+	f.version = policy.Version
+	f.identities = make(map[string]bool, len(policy.Identities))
+	for _, identity := range policy.Identities {
+		f.identities[identity] = true
+	}
+	programmed := f.programmed
+	f.mutex.Unlock()
+
+	log.Printf("🧱 상태 포트 접근 정책 적용 (%s): 허용 %d개, 피어 %d개, 차단된 요청 %d건",
+		policy.Version, len(f.allowed.Prefixes()), len(policy.PeerCIDRs), f.denied.Load())
+	if programmed {
+		f.programHostFirewall()
+	}
+}
+
+// run - 호스트 방화벽 규칙 설정 후 종료 시 제거 (host_firewall일 때만, attach 이후 호출)
+func (f *statusFirewall) run(ctx context.Context) {
+	if f.config.Disabled || !f.config.HostFirewall {
+		return
+	}
+	f.mutex.Lock()
+	f.programmed = true
+	f.mutex.Unlock()
+	f.programHostFirewall()
+
+	<-ctx.Done()
+	for _, tool := range []string{"iptables", "ip6tables"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		exec.Command(tool, "-D", "INPUT", "-p", "tcp", "--dport", f.port, "-j", statusFirewallChain).Run()
+		exec.Command(tool, "-F", statusFirewallChain).Run()
+		exec.Command(tool, "-X", statusFirewallChain).Run()
+	}
+	log.Printf("🧱 상태 포트 방화벽 규칙 제거")
+}
+
+/*
+programHostFirewall - 상태 포트로 들어오는 TCP를 전용 체인으로 보내 허용 목록 외 주소를 DROP
+피어 워커 주소는 포트 수준에서만 허용하고 경로 제한은 미들웨어가 담당합니다.
+*/
+func (f *statusFirewall) programHostFirewall() {
+	prefixes := append(f.allowed.Prefixes(), f.peers.Prefixes()...)
+	for _, family := range []struct {
+		tool string
+		ipv6 bool
+	}{{"iptables", false}, {"ip6tables", true}} {
+		if _, err := exec.LookPath(family.tool); err != nil {
+			if !family.ipv6 {
+				log.Printf("⚠️ iptables가 없어 상태 포트 방화벽 규칙을 설정하지 못했습니다 (미들웨어 차단만 적용)")
+			}
+			continue
+		}
+		rules := [][]string{{"-F", statusFirewallChain}, {"-A", statusFirewallChain, "-i", "lo", "-j", "ACCEPT"}}
+		for _, prefix := range prefixes {
+			if strings.Contains(prefix, ":") == family.ipv6 {
+				rules = append(rules, []string{"-A", statusFirewallChain, "-s", prefix, "-j", "ACCEPT"})
+			}
+		}
+		rules = append(rules, []string{"-A", statusFirewallChain, "-j", "DROP"})
+
+		exec.Command(family.tool, "-N", statusFirewallChain).Run() // 이미 있으면 실패 (무시)
+		for _, rule := range rules {
+			if output, err := exec.Command(family.tool, rule...).CombinedOutput(); err != nil {
+				log.Printf("⚠️ %s %s 실패: %v: %s", family.tool, strings.Join(rule, " "), err, strings.TrimSpace(string(output)))
+				return
+			}
+		}
+		jump := []string{"INPUT", "-p", "tcp", "--dport", f.port, "-j", statusFirewallChain}
+		if exec.Command(family.tool, append([]string{"-C"}, jump...)...).Run() != nil {
+			if output, err := exec.Command(family.tool, append([]string{"-I"}, jump...)...).CombinedOutput(); err != nil {
+				log.Printf("⚠️ %s INPUT 규칙 추가 실패: %v: %s", family.tool, err, strings.TrimSpace(string(output)))
+				return
+			}
+		}
+	}
+	log.Printf("🧱 상태 포트 %s 방화벽 규칙 설정 (허용 %d개)", f.port, len(prefixes))
+}
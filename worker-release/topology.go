@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 표준 K8s 토폴로지 라벨 (스케줄러의 topologySpreadConstraints / zone affinity에서 사용)
+const (
+	topologyRegionLabel = "topology.kubernetes.io/region"
+	topologyZoneLabel   = "topology.kubernetes.io/zone"
+	latencyProbeCount   = 3
+)
+
+/*
+topologyLabels - 설정된 리전/존을 K3s 노드 라벨로 변환
+설정되지 않은 값은 라벨을 붙이지 않습니다.
+*/
+func (s *StakerHost) topologyLabels() []string {
+	var labels []string
+	if s.config.Region != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", topologyRegionLabel, s.config.Region))
+	}
+	if s.config.Zone != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", topologyZoneLabel, s.config.Zone))
+	}
+	return labels
+}
+
+/*
+measureMasterLatency - Nautilus 마스터까지의 왕복 지연시간 측정
+/healthz를 여러 번 호출하여 중앙값을 반환합니다 (일시적인 지연 튐 완화).
+*/
+func (s *StakerHost) measureMasterLatency() (time.Duration, error) {
+	client := s.restyClient().SetTimeout(5 * time.Second)
+
+	var samples []time.Duration
+	for i := 0; i < latencyProbeCount; i++ {
+		start := time.Now()
+		resp, err := client.R().Get(s.config.NautilusEndpoint + "/healthz")
+		if err != nil {
+			continue
+		}
+		if resp.StatusCode() == 200 {
+			samples = append(samples, time.Since(start))
+		}
+	}
+
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("마스터 헬스체크 응답 없음: %s", s.config.NautilusEndpoint)
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples[len(samples)/2], nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	version "github.com/k3s-io/daas-version"
+)
+
+/*
+checkMasterVersion - 등록 응답의 마스터 버전(X-Daas-Version)을 차이 정책으로 확인
+K3S_DAAS_VERSION_SKEW_POLICY: warn(기본) 경고만 / refuse 등록 중단 / off
+워커는 마스터보다 K3S_DAAS_VERSION_MAX_MINOR_SKEW(기본 2) 마이너 버전까지 낮을 수 있고 높을 수는 없습니다.
+헤더가 없는 이전 마스터나 dev 빌드는 비교하지 않습니다.
+*/
+func checkMasterVersion(header string) error {
+	policy, err := version.PolicyFromEnv("K3S_DAAS")
+	if err != nil {
+		return err
+	}
+	_, masterVersion, ok := version.ParseHeader(header)
+	if !ok {
+		return nil
+	}
+
+	skew := policy.Check(masterVersion, version.Version)
+	if policy.Refuses(skew) {
+		return fmt.Errorf("마스터 버전 %s와 호환되지 않아 등록을 중단합니다: %v", masterVersion, skew)
+	}
+	if skew != nil {
+		log.Printf("⚠️ 마스터 버전 %s이(가) 지원 범위를 벗어났습니다 (워커 %s): %v", masterVersion, version.Version, skew)
+	}
+	return nil
+}
+
+// versionLabel - kubectl get nodes -L k3s-daas.io/version 로 워커 버전 확인
+func versionLabel() string {
+	return version.LabelKey + "=" + version.LabelValue(version.Version)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	chain "github.com/k3s-io/daas-chain"
+	"github.com/k3s-io/daas-sui"
+)
+
+// 지갑 잔액 감시 기본값
+const (
+	defaultWalletLowBalance      = sui.MistPerSui / 2  // 0.5 SUI
+	defaultWalletCriticalBalance = sui.MistPerSui / 10 // 0.1 SUI
+	defaultWalletCheckInterval   = 300
+	walletFaucetCooldown         = time.Hour // 공용 faucet은 주소/IP별로 요청 빈도를 제한
+)
+
+/*
+WalletMonitorConfig - 워커 지갑 SUI 잔액 감시 설정 (staker-config.json wallet_monitor)
+
+하트비트와 함께 보내는 온체인 트랜잭션(하트비트 기록, 이의 신청, 스테이킹 변경)은 지갑의 가스로 지불됩니다.
+잔액이 low_balance_mist 아래로 내려가면 하트비트 node_conditions에 WalletLowBalance를 보고하고,
+critical_balance_mist 아래면 메시지에 critical을 붙입니다. auto_faucet이면 testnet/devnet에서만
+faucet에 충전을 요청합니다 (mainnet RPC에서는 설정과 관계없이 요청하지 않음).
+*/
+type WalletMonitorConfig struct {
+	Disabled            bool   `json:"disabled"`
+	LowBalanceMist      uint64 `json:"low_balance_mist"`      // 경고 기준 (기본 0.5 SUI)
+	CriticalBalanceMist uint64 `json:"critical_balance_mist"` // 위험 기준 (기본 0.1 SUI, low 이하)
+	IntervalSeconds     int    `json:"interval_seconds"`      // 잔액 조회 주기 (기본 300초)
+	AutoFaucet          bool   `json:"auto_faucet"`           // 기준 미만이면 testnet/devnet faucet 요청
+	FaucetURL           string `json:"faucet_url"`            // faucet 주소 (기본: RPC 엔드포인트의 네트워크 공용 faucet)
+}
+
+/*
+applyWalletMonitorDefaults - 지갑 잔액 감시 기본값 및 환경변수 덮어쓰기
+- K3S_DAAS_WALLET_MONITOR: off이면 감시하지 않음
+- K3S_DAAS_WALLET_LOW_BALANCE_MIST: 경고 기준 (MIST)
+- K3S_DAAS_AUTO_FAUCET: true이면 testnet/devnet faucet 자동 요청
+*/
+func applyWalletMonitorDefaults(config *StakerHostConfig) error {
+	w := &config.WalletMonitor
+	if os.Getenv("K3S_DAAS_WALLET_MONITOR") == "off" {
+		w.Disabled = true
+	}
+	if value := os.Getenv("K3S_DAAS_WALLET_LOW_BALANCE_MIST"); value != "" {
+		threshold, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || threshold == 0 {
+			return fmt.Errorf("잘못된 K3S_DAAS_WALLET_LOW_BALANCE_MIST: %s", value)
+		}
+		w.LowBalanceMist = threshold
+	}
+	if os.Getenv("K3S_DAAS_AUTO_FAUCET") == "true" {
+		w.AutoFaucet = true
+	}
+
+	if w.LowBalanceMist == 0 {
+		w.LowBalanceMist = defaultWalletLowBalance
+	}
+	if w.CriticalBalanceMist == 0 {
+		w.CriticalBalanceMist = defaultWalletCriticalBalance
+		if w.CriticalBalanceMist > w.LowBalanceMist {
+			w.CriticalBalanceMist = w.LowBalanceMist
+		}
+	}
+	if w.CriticalBalanceMist > w.LowBalanceMist {
+		return fmt.Errorf("wallet_monitor.critical_balance_mist(%d)가 low_balance_mist(%d)보다 큽니다", w.CriticalBalanceMist, w.LowBalanceMist)
+	}
+	if w.IntervalSeconds <= 0 {
+		w.IntervalSeconds = defaultWalletCheckInterval
+	}
+	return nil
+}
+
+// walletMonitor - 마지막 잔액 조회 결과와 faucet 요청 기록
+type walletMonitor struct {
+	config     WalletMonitorConfig
+	mu         sync.Mutex
+	balance    uint64
+	checkedAt  time.Time // 마지막 유효 조회 시각 (zero면 아직 없음)
+	lastErr    error
+	level      string    // ok, low, critical (조회 전에는 "")
+	faucetAt   time.Time // 마지막 faucet 요청 시각
+	faucetErr  error
+	faucetSent int // 성공한 faucet 요청 수
+}
+
+func newWalletMonitor(config WalletMonitorConfig) *walletMonitor {
+	if config.Disabled {
+		return nil
+	}
+	return &walletMonitor{config: config}
+}
+
+// walletLevel - 잔액이 속한 구간
+func (m *walletMonitor) walletLevel(balance uint64) string {
+	switch {
+	case balance < m.config.CriticalBalanceMist:
+		return "critical"
+	case balance < m.config.LowBalanceMist:
+		return "low"
+	}
+	return "ok"
+}
+
+// condition - 하트비트 node_conditions용 WalletLowBalance (조회 전이거나 비활성화면 nil)
+func (m *walletMonitor) condition() []NodeCondition {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.checkedAt.IsZero() {
+		return nil
+	}
+	condition := NodeCondition{Type: "WalletLowBalance"}
+	if m.level != "ok" {
+		condition.Status = true
+		condition.Message = fmt.Sprintf("%s: wallet balance %s SUI below %s SUI", m.level,
+			formatSui(m.balance), formatSui(m.config.LowBalanceMist))
+	}
+	return []NodeCondition{condition}
+}
+
+// snapshot - /health 응답용 요약 (비활성화면 nil)
+func (m *walletMonitor) snapshot() map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := map[string]interface{}{
+		"low_balance_mist":      m.config.LowBalanceMist,
+		"critical_balance_mist": m.config.CriticalBalanceMist,
+		"auto_faucet":           m.config.AutoFaucet,
+	}
+	if !m.checkedAt.IsZero() {
+		snapshot["balance_mist"] = m.balance
+		snapshot["level"] = m.level
+		snapshot["checked_at"] = m.checkedAt.Unix()
+	}
+	if m.lastErr != nil {
+		snapshot["last_error"] = m.lastErr.Error()
+	}
+	if !m.faucetAt.IsZero() {
+		snapshot["faucet_requested_at"] = m.faucetAt.Unix()
+		snapshot["faucet_requests"] = m.faucetSent
+		if m.faucetErr != nil {
+			snapshot["faucet_error"] = m.faucetErr.Error()
+		}
+	}
+	return snapshot
+}
+
+// balanceMist - /api/v1/metrics용 마지막 잔액 (조회 전이면 false)
+func (m *walletMonitor) balanceMist() (uint64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balance, !m.checkedAt.IsZero()
+}
+
+// runWalletMonitor - 지갑 잔액 조회 루프 (구간이 바뀔 때 로그, 기준 미만이면 faucet)
+func (s *StakerHost) runWalletMonitor(ctx context.Context) {
+	m := s.wallet
+	if m == nil {
+		return
+	}
+	if s.suiClient.backend.Name() == chain.MockBackendName {
+		log.Printf("⛽ mock 체인에서는 지갑 잔액을 감시하지 않습니다")
+		return
+	}
+	if s.config.SuiWalletAddress == "" {
+		log.Printf("⚠️ sui_wallet_address가 없어 지갑 잔액을 감시하지 않습니다")
+		return
+	}
+
+	faucetURL := ""
+	if m.config.AutoFaucet {
+		faucetURL = m.config.FaucetURL
+		if faucetURL == "" {
+			faucetURL = sui.FaucetURL(s.config.SuiRPCEndpoint)
+		}
+		switch {
+		case sui.IsMainnet(s.config.SuiRPCEndpoint):
+			log.Printf("⚠️ mainnet RPC에서는 auto_faucet을 무시합니다")
+			faucetURL = ""
+		case faucetURL == "":
+			log.Printf("⚠️ %s 네트워크의 faucet을 알 수 없어 auto_faucet을 무시합니다 (wallet_monitor.faucet_url 지정)", s.config.SuiRPCEndpoint)
+		}
+	}
+	log.Printf("⛽ 지갑 잔액 감시 시작 (경고 %s SUI, 위험 %s SUI, %ds마다)",
+		formatSui(m.config.LowBalanceMist), formatSui(m.config.CriticalBalanceMist), m.config.IntervalSeconds)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	ticker := time.NewTicker(time.Duration(m.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		s.checkWalletOnce(ctx, client, faucetURL)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkWalletOnce - 잔액 한 번 조회 (RPC 오류는 마지막 값을 유지)
+func (s *StakerHost) checkWalletOnce(ctx context.Context, client *http.Client, faucetURL string) {
+	m := s.wallet
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	balance, err := s.suiClient.chain.Balance(queryCtx, s.config.SuiWalletAddress)
+	cancel()
+
+	m.mu.Lock()
+	if err != nil {
+		m.lastErr = err
+		m.mu.Unlock()
+		log.Printf("⚠️ 지갑 잔액 조회 실패: %v", err)
+		return
+	}
+	previous := m.level
+	m.balance, m.checkedAt, m.lastErr = balance, time.Now(), nil
+	m.level = m.walletLevel(balance)
+	level := m.level
+	requestFaucet := faucetURL != "" && level != "ok" && time.Since(m.faucetAt) >= walletFaucetCooldown
+	if requestFaucet {
+		m.faucetAt = time.Now()
+	}
+	m.mu.Unlock()
+
+	if level != previous {
+		switch level {
+		case "critical":
+			log.Printf("🚨 지갑 잔액 위험: %s SUI (기준 %s SUI) - 가스 부족으로 온체인 트랜잭션이 실패할 수 있습니다",
+				formatSui(balance), formatSui(m.config.CriticalBalanceMist))
+		case "low":
+			log.Printf("⚠️ 지갑 잔액 부족: %s SUI (기준 %s SUI)", formatSui(balance), formatSui(m.config.LowBalanceMist))
+		default:
+			if previous != "" {
+				log.Printf("✅ 지갑 잔액 회복: %s SUI", formatSui(balance))
+			}
+		}
+	}
+	if !requestFaucet {
+		return
+	}
+
+	log.Printf("🚰 faucet 충전 요청: %s", faucetURL)
+	err = sui.RequestFaucet(ctx, client, faucetURL, s.config.SuiWalletAddress)
+	m.mu.Lock()
+	m.faucetErr = err
+	if err == nil {
+		m.faucetSent++
+	}
+	m.mu.Unlock()
+	if err != nil {
+		log.Printf("⚠️ faucet 요청 실패: %v", err)
+		return
+	}
+	log.Printf("✅ faucet 요청 완료 (잔액은 다음 조회에 반영)")
+}
+
+// formatSui - MIST를 소수점 SUI 문자열로 (예: 1500000000 → 1.5)
+func formatSui(mist uint64) string {
+	return strconv.FormatFloat(float64(mist)/sui.MistPerSui, 'f', -1, 64)
+}